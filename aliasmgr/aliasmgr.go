@@ -0,0 +1,404 @@
+package aliasmgr
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/brronsuite/broln/kvdb"
+	"github.com/brronsuite/broln/lnwire"
+)
+
+var (
+	// aliasBucket stores aliases as keys and their base SCIDs as values.
+	// This is used to populate the maps that the Manager uses. The keys
+	// are alias SCIDs and the values are their respective base SCIDs. This
+	// is used instead of the other way around (base -> alias...) because
+	// updating an alias would require fetching all the existing aliases,
+	// adding another one, and then flushing the write to disk. This is
+	// inefficient compared to N 1:1 mappings at the cost of marginally
+	// more disk space.
+	aliasBucket = []byte("alias-bucket")
+
+	// peerAliasBucket stores the peer's alias SCIDs keyed by the channel
+	// ID. These are the aliases the peer sent us in its FundingLocked
+	// message and that we must use when handing out route hints for the
+	// channel.
+	peerAliasBucket = []byte("peer-alias-bucket")
+
+	// aliasAllocBucket is a root-level bucket that stores the last alias
+	// that was allocated. It is used to allocate a new alias when
+	// requested.
+	aliasAllocBucket = []byte("alias-alloc-bucket")
+
+	// lastAliasKey is a key in the aliasAllocBucket whose value is the
+	// last allocated alias ShortChannelID. This will be updated upon calls
+	// to RequestAlias.
+	lastAliasKey = []byte("last-alias-key")
+
+	// ErrAliasNotFound is returned when the base SCID of an alias cannot
+	// be found.
+	ErrAliasNotFound = errors.New("alias not found")
+
+	// ErrNoPeerAlias is returned when the peer's alias for a channel
+	// cannot be found.
+	ErrNoPeerAlias = errors.New("no peer alias found")
+
+	// byteOrder denotes the byte order of database (de)-serialization
+	// operations.
+	byteOrder = binary.BigEndian
+)
+
+var (
+	// StartingAlias is the first alias ShortChannelID that will get
+	// assigned by RequestAlias. The starting BlockHeight is chosen so that
+	// legitimate SCIDs won't be mistaken for an alias for the foreseeable
+	// future.
+	StartingAlias = lnwire.ShortChannelID{
+		BlockHeight: startingBlockHeight,
+		TxIndex:     0,
+		TxPosition:  0,
+	}
+)
+
+const (
+	// startingBlockHeight is the block height of the first alias that
+	// will be allocated.
+	startingBlockHeight = 16_000_000
+
+	// endBlockHeight is the (exclusive) upper bound of the block height
+	// range that is reserved for aliases.
+	endBlockHeight = 16_250_000
+)
+
+// Manager is a struct that handles aliases for broln. It has an underlying
+// database that can allocate aliases for channels, stores the peer's last
+// alias for use in our hop hints, and contains mappings that both the Switch
+// and the funding manager use.
+type Manager struct {
+	backend kvdb.Backend
+
+	// baseToSet is a mapping from the "base" SCID to the set of aliases
+	// for this channel. This mapping includes all channels that negotiated
+	// the option-scid-alias feature bit.
+	baseToSet map[lnwire.ShortChannelID][]lnwire.ShortChannelID
+
+	// aliasToBase is a mapping that maps all aliases for a given channel
+	// to its base SCID.
+	aliasToBase map[lnwire.ShortChannelID]lnwire.ShortChannelID
+
+	// peerAlias is a cache for the alias SCIDs that our peers send us in
+	// the FundingLocked message. These are used in our hop hints.
+	peerAlias map[lnwire.ChannelID]lnwire.ShortChannelID
+
+	sync.RWMutex
+}
+
+// NewManager initializes an alias Manager from the passed database backend.
+func NewManager(db kvdb.Backend) (*Manager, error) {
+	m := &Manager{backend: db}
+	m.baseToSet = make(
+		map[lnwire.ShortChannelID][]lnwire.ShortChannelID,
+	)
+	m.aliasToBase = make(map[lnwire.ShortChannelID]lnwire.ShortChannelID)
+	m.peerAlias = make(map[lnwire.ChannelID]lnwire.ShortChannelID)
+
+	err := m.populateMaps()
+	return m, err
+}
+
+// populateMaps reads the database state and populates the maps.
+func (m *Manager) populateMaps() error {
+	// This map caches what is found in the database and is used to
+	// populate the Manager's actual maps.
+	aliasMap := make(map[lnwire.ShortChannelID]lnwire.ShortChannelID)
+
+	// This map caches the ChannelID/alias SCIDs stored in the database
+	// and is used to populate the Manager's cache.
+	peerAliasMap := make(map[lnwire.ChannelID]lnwire.ShortChannelID)
+
+	err := kvdb.Update(m.backend, func(tx kvdb.RwTx) error {
+		bucket, err := tx.CreateTopLevelBucket(aliasBucket)
+		if err != nil {
+			return err
+		}
+
+		err = bucket.ForEach(func(k, v []byte) error {
+			// The key will be the alias SCID and the value will be
+			// the base SCID.
+			aliasScid := lnwire.NewShortChanIDFromInt(
+				byteOrder.Uint64(k),
+			)
+			baseScid := lnwire.NewShortChanIDFromInt(
+				byteOrder.Uint64(v),
+			)
+			aliasMap[aliasScid] = baseScid
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		peerBucket, err := tx.CreateTopLevelBucket(peerAliasBucket)
+		if err != nil {
+			return err
+		}
+
+		return peerBucket.ForEach(func(k, v []byte) error {
+			var chanID lnwire.ChannelID
+			copy(chanID[:], k)
+			alias := lnwire.NewShortChanIDFromInt(
+				byteOrder.Uint64(v),
+			)
+
+			peerAliasMap[chanID] = alias
+
+			return nil
+		})
+	}, func() {
+		aliasMap = make(map[lnwire.ShortChannelID]lnwire.ShortChannelID)
+		peerAliasMap = make(map[lnwire.ChannelID]lnwire.ShortChannelID)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Populate the baseToSet and aliasToBase maps.
+	for aliasSCID, baseSCID := range aliasMap {
+		m.baseToSet[baseSCID] = append(m.baseToSet[baseSCID], aliasSCID)
+		m.aliasToBase[aliasSCID] = baseSCID
+	}
+
+	// Populate the peer alias cache.
+	m.peerAlias = peerAliasMap
+
+	return nil
+}
+
+// AddLocalAlias adds a database mapping from the passed alias to the passed
+// base SCID.
+func (m *Manager) AddLocalAlias(alias, baseScid lnwire.ShortChannelID) error {
+	m.Lock()
+	defer m.Unlock()
+
+	err := kvdb.Update(m.backend, func(tx kvdb.RwTx) error {
+		aliasToBaseBucket, err := tx.CreateTopLevelBucket(aliasBucket)
+		if err != nil {
+			return err
+		}
+
+		var (
+			aliasBytes [8]byte
+			baseBytes  [8]byte
+		)
+
+		byteOrder.PutUint64(aliasBytes[:], alias.ToUint64())
+		byteOrder.PutUint64(baseBytes[:], baseScid.ToUint64())
+		return aliasToBaseBucket.Put(aliasBytes[:], baseBytes[:])
+	}, func() {})
+	if err != nil {
+		return err
+	}
+
+	// Update the aliasToBase and baseToSet maps.
+	m.baseToSet[baseScid] = append(m.baseToSet[baseScid], alias)
+	m.aliasToBase[alias] = baseScid
+
+	return nil
+}
+
+// GetAliases fetches the set of aliases stored under a given base SCID from
+// write-through caches.
+func (m *Manager) GetAliases(
+	base lnwire.ShortChannelID) []lnwire.ShortChannelID {
+
+	m.RLock()
+	defer m.RUnlock()
+
+	aliasSet, ok := m.baseToSet[base]
+	if ok {
+		// Copy the found alias slice.
+		setCopy := make([]lnwire.ShortChannelID, len(aliasSet))
+		copy(setCopy, aliasSet)
+		return setCopy
+	}
+
+	return nil
+}
+
+// FindBaseSCID finds the base SCID for a given alias. This is used in the
+// Switch to find the link that is responsible for an alias.
+func (m *Manager) FindBaseSCID(
+	alias lnwire.ShortChannelID) (lnwire.ShortChannelID, error) {
+
+	m.RLock()
+	defer m.RUnlock()
+
+	base, ok := m.aliasToBase[alias]
+	if ok {
+		return base, nil
+	}
+
+	return lnwire.ShortChannelID{}, ErrAliasNotFound
+}
+
+// PutPeerAlias stores the peer's alias SCID once we learn of it in the
+// FundingLocked message.
+func (m *Manager) PutPeerAlias(chanID lnwire.ChannelID,
+	alias lnwire.ShortChannelID) error {
+
+	m.Lock()
+	defer m.Unlock()
+
+	err := kvdb.Update(m.backend, func(tx kvdb.RwTx) error {
+		bucket, err := tx.CreateTopLevelBucket(peerAliasBucket)
+		if err != nil {
+			return err
+		}
+
+		var scratch [8]byte
+		byteOrder.PutUint64(scratch[:], alias.ToUint64())
+		return bucket.Put(chanID[:], scratch[:])
+	}, func() {})
+	if err != nil {
+		return err
+	}
+
+	// Now that the database state has been updated, we'll update the
+	// cache.
+	m.peerAlias[chanID] = alias
+
+	return nil
+}
+
+// GetPeerAlias retrieves a peer's alias SCID by the channel's ChanID.
+func (m *Manager) GetPeerAlias(
+	chanID lnwire.ChannelID) (lnwire.ShortChannelID, error) {
+
+	m.RLock()
+	defer m.RUnlock()
+
+	alias, ok := m.peerAlias[chanID]
+	if !ok {
+		return lnwire.ShortChannelID{}, ErrNoPeerAlias
+	}
+
+	return alias, nil
+}
+
+// RequestAlias returns a new ALIAS ShortChannelID to the caller by allocating
+// the next un-allocated ShortChannelID. The starting ShortChannelID is
+// 16000000:0:0 and the ending ShortChannelID is 16250000:16777215:65535. This
+// gives roughly 2^58 possible ALIAS ShortChannelIDs which ensures this space
+// won't get exhausted.
+func (m *Manager) RequestAlias() (lnwire.ShortChannelID, error) {
+	var nextAlias lnwire.ShortChannelID
+
+	err := kvdb.Update(m.backend, func(tx kvdb.RwTx) error {
+		bucket, err := tx.CreateTopLevelBucket(aliasAllocBucket)
+		if err != nil {
+			return err
+		}
+
+		lastBytes := bucket.Get(lastAliasKey)
+		if lastBytes == nil {
+			// If the key does not exist, then we can write the
+			// StartingAlias to it.
+			nextAlias = StartingAlias
+
+			var scratch [8]byte
+			byteOrder.PutUint64(scratch[:], nextAlias.ToUint64())
+			return bucket.Put(lastAliasKey, scratch[:])
+		}
+
+		// Otherwise the key does exist so we can convert the retrieved
+		// lastAlias to a ShortChannelID and use it to assign the next
+		// ShortChannelID. This next ShortChannelID will then be
+		// persisted in the database.
+		lastScid := lnwire.NewShortChanIDFromInt(
+			byteOrder.Uint64(lastBytes),
+		)
+		nextAlias, err = getNextScid(lastScid)
+		if err != nil {
+			return err
+		}
+
+		var scratch [8]byte
+		byteOrder.PutUint64(scratch[:], nextAlias.ToUint64())
+		return bucket.Put(lastAliasKey, scratch[:])
+	}, func() {
+		nextAlias = lnwire.ShortChannelID{}
+	})
+	if err != nil {
+		return nextAlias, err
+	}
+
+	return nextAlias, nil
+}
+
+// ListAliases returns a carbon copy of baseToSet. This is used by the rpc
+// layer.
+func (m *Manager) ListAliases() map[lnwire.ShortChannelID][]lnwire.ShortChannelID {
+	m.RLock()
+	defer m.RUnlock()
+
+	baseCopy := make(map[lnwire.ShortChannelID][]lnwire.ShortChannelID)
+
+	for k, v := range m.baseToSet {
+		setCopy := make([]lnwire.ShortChannelID, len(v))
+		copy(setCopy, v)
+		baseCopy[k] = setCopy
+	}
+
+	return baseCopy
+}
+
+// getNextScid is a utility function that returns the next SCID for a given
+// alias SCID. The BlockHeight ranges from [16000000, 16250000), the TxIndex
+// ranges from [0, 16777215), and the TxPosition ranges from [0, 65535).
+func getNextScid(last lnwire.ShortChannelID) (lnwire.ShortChannelID, error) {
+	var (
+		next    lnwire.ShortChannelID
+		maxTxIx uint32 = 0xFFFFFF
+		maxPos  uint16 = 0xFFFF
+	)
+
+	switch {
+	// If the TxPosition can still be incremented, we'll do so.
+	case last.TxPosition < maxPos:
+		next = last
+		next.TxPosition++
+
+	// Otherwise, if the TxIndex can still be incremented, we'll reset the
+	// TxPosition and increment the TxIndex.
+	case last.TxIndex < maxTxIx:
+		next = lnwire.ShortChannelID{
+			BlockHeight: last.BlockHeight,
+			TxIndex:     last.TxIndex + 1,
+		}
+
+	// Otherwise we'll move on to the next block height.
+	default:
+		next = lnwire.ShortChannelID{
+			BlockHeight: last.BlockHeight + 1,
+		}
+	}
+
+	if !IsAlias(next) {
+		return lnwire.ShortChannelID{}, fmt.Errorf("alias space "+
+			"exhausted, last alias %v", last)
+	}
+
+	return next, nil
+}
+
+// IsAlias returns true if the passed SCID is an alias. The function determines
+// this by looking at the BlockHeight. If the BlockHeight is greater than
+// startingBlockHeight and less than endBlockHeight, then it is an alias
+// assigned by RequestAlias. These bounds only apply to aliases we generate.
+// Our peers are free to use any range they choose.
+func IsAlias(scid lnwire.ShortChannelID) bool {
+	return scid.BlockHeight >= startingBlockHeight &&
+		scid.BlockHeight < endBlockHeight
+}
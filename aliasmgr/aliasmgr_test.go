@@ -0,0 +1,204 @@
+package aliasmgr
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/brronsuite/broln/kvdb"
+	"github.com/brronsuite/broln/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAliasStorePeerAlias tests that putting and retrieving a peer's alias
+// works properly and survives a restart.
+func TestAliasStorePeerAlias(t *testing.T) {
+	t.Parallel()
+
+	db, cleanup := makeTestDB(t)
+	defer cleanup()
+
+	aliasStore, err := NewManager(db)
+	require.NoError(t, err)
+
+	var chanID1 [32]byte
+	copy(chanID1[:], []byte("channel id 1"))
+	alias := lnwire.ShortChannelID{
+		BlockHeight: startingBlockHeight + 100,
+		TxIndex:     1,
+		TxPosition:  2,
+	}
+
+	_, err = aliasStore.GetPeerAlias(chanID1)
+	require.ErrorIs(t, err, ErrNoPeerAlias)
+
+	err = aliasStore.PutPeerAlias(chanID1, alias)
+	require.NoError(t, err)
+
+	storedAlias, err := aliasStore.GetPeerAlias(chanID1)
+	require.NoError(t, err)
+	require.Equal(t, alias, storedAlias)
+
+	// A new manager created from the same database must load the alias.
+	aliasStore, err = NewManager(db)
+	require.NoError(t, err)
+
+	storedAlias, err = aliasStore.GetPeerAlias(chanID1)
+	require.NoError(t, err)
+	require.Equal(t, alias, storedAlias)
+}
+
+// TestAliasStoreRequest tests that the aliasStore delivers the expected SCID
+// and that local aliases can be mapped back to their base SCID.
+func TestAliasStoreRequest(t *testing.T) {
+	t.Parallel()
+
+	db, cleanup := makeTestDB(t)
+	defer cleanup()
+
+	aliasStore, err := NewManager(db)
+	require.NoError(t, err)
+
+	// We'll assert that the very first alias we receive is StartingAlias.
+	alias1, err := aliasStore.RequestAlias()
+	require.NoError(t, err)
+	require.Equal(t, StartingAlias, alias1)
+	require.True(t, IsAlias(alias1))
+
+	// The next alias should be the result of passing in StartingAlias to
+	// getNextScid.
+	nextAlias, err := getNextScid(alias1)
+	require.NoError(t, err)
+
+	alias2, err := aliasStore.RequestAlias()
+	require.NoError(t, err)
+	require.Equal(t, nextAlias, alias2)
+
+	// Both aliases are mapped to the same base SCID.
+	base := lnwire.NewShortChanIDFromInt(123)
+	require.False(t, IsAlias(base))
+	require.NoError(t, aliasStore.AddLocalAlias(alias1, base))
+	require.NoError(t, aliasStore.AddLocalAlias(alias2, base))
+
+	foundBase, err := aliasStore.FindBaseSCID(alias2)
+	require.NoError(t, err)
+	require.Equal(t, base, foundBase)
+	require.ElementsMatch(
+		t, []lnwire.ShortChannelID{alias1, alias2},
+		aliasStore.GetAliases(base),
+	)
+
+	_, err = aliasStore.FindBaseSCID(base)
+	require.ErrorIs(t, err, ErrAliasNotFound)
+
+	// The mappings and the allocation counter must survive a restart.
+	aliasStore, err = NewManager(db)
+	require.NoError(t, err)
+
+	foundBase, err = aliasStore.FindBaseSCID(alias1)
+	require.NoError(t, err)
+	require.Equal(t, base, foundBase)
+
+	alias3, err := aliasStore.RequestAlias()
+	require.NoError(t, err)
+	nextAlias, err = getNextScid(alias2)
+	require.NoError(t, err)
+	require.Equal(t, nextAlias, alias3)
+}
+
+// TestGetNextScid tests that given a current lnwire.ShortChannelID,
+// getNextScid returns the expected alias to use next.
+func TestGetNextScid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		current  lnwire.ShortChannelID
+		expected lnwire.ShortChannelID
+	}{
+		{
+			name:    "starting alias",
+			current: StartingAlias,
+			expected: lnwire.ShortChannelID{
+				BlockHeight: startingBlockHeight,
+				TxIndex:     0,
+				TxPosition:  1,
+			},
+		},
+		{
+			name: "txposition rollover",
+			current: lnwire.ShortChannelID{
+				BlockHeight: 16_100_000,
+				TxIndex:     15,
+				TxPosition:  65535,
+			},
+			expected: lnwire.ShortChannelID{
+				BlockHeight: 16_100_000,
+				TxIndex:     16,
+				TxPosition:  0,
+			},
+		},
+		{
+			name: "txindex max no rollover",
+			current: lnwire.ShortChannelID{
+				BlockHeight: 16_100_000,
+				TxIndex:     16777215,
+				TxPosition:  15,
+			},
+			expected: lnwire.ShortChannelID{
+				BlockHeight: 16_100_000,
+				TxIndex:     16777215,
+				TxPosition:  16,
+			},
+		},
+		{
+			name: "txindex rollover",
+			current: lnwire.ShortChannelID{
+				BlockHeight: 16_100_000,
+				TxIndex:     16777215,
+				TxPosition:  65535,
+			},
+			expected: lnwire.ShortChannelID{
+				BlockHeight: 16_100_001,
+				TxIndex:     0,
+				TxPosition:  0,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			nextScid, err := getNextScid(test.current)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, nextScid)
+		})
+	}
+
+	// Once the alias range is exhausted, an error is returned.
+	_, err := getNextScid(lnwire.ShortChannelID{
+		BlockHeight: endBlockHeight - 1,
+		TxIndex:     16777215,
+		TxPosition:  65535,
+	})
+	require.Error(t, err)
+}
+
+// makeTestDB creates a new bolt backed database in a temporary file.
+func makeTestDB(t *testing.T) (kvdb.Backend, func()) {
+	file, err := ioutil.TempFile("", "*.db")
+	require.NoError(t, err)
+
+	dbPath := file.Name()
+	require.NoError(t, file.Close())
+
+	db, err := kvdb.Create(
+		kvdb.BoltBackendName, dbPath, true, kvdb.DefaultDBTimeout,
+	)
+	require.NoError(t, err)
+
+	return db, func() {
+		db.Close()
+		os.Remove(dbPath)
+	}
+}
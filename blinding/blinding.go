@@ -0,0 +1,400 @@
+package blinding
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+
+	"github.com/brronsuite/broln/keychain"
+	"github.com/brronsuite/brond/bronec"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+var (
+	// ErrNoHops is returned when a blinded path is requested for an empty
+	// set of hops.
+	ErrNoHops = errors.New("blinded path must have at least one hop")
+
+	// ErrDecryptionFailed is returned when the encrypted data of a blinded
+	// hop could not be decrypted with the derived key.
+	ErrDecryptionFailed = errors.New("unable to decrypt blinded hop data")
+
+	// blindedNodeIDKey is the HMAC key used to derive the factor that
+	// blinds a node's public key.
+	blindedNodeIDKey = []byte("blinded_node_id")
+
+	// rhoKey is the HMAC key used to derive the key that encrypts the
+	// data for a blinded hop.
+	rhoKey = []byte("rho")
+)
+
+// HopInfo describes a single hop of a blinded path before it is blinded. The
+// plaintext is the serialized encrypted_data TLV stream the hop will receive,
+// typically created with EncodeRouteData.
+type HopInfo struct {
+	// NodePub is the real public key of the node.
+	NodePub *bronec.PublicKey
+
+	// PlainText is the data that will be encrypted to the node.
+	PlainText []byte
+}
+
+// BlindedHop is a single hop of a blinded path.
+type BlindedHop struct {
+	// BlindedNodePub is the blinded public key of the node.
+	BlindedNodePub *bronec.PublicKey
+
+	// CipherText is the data encrypted to the node, which only the node
+	// can decrypt using the blinding point it receives.
+	CipherText []byte
+}
+
+// BlindedPath is a path to a destination whose hops after the introduction
+// node are hidden from the sender.
+type BlindedPath struct {
+	// IntroductionPoint is the real public key of the first node of the
+	// path.
+	IntroductionPoint *bronec.PublicKey
+
+	// BlindingPoint is the ephemeral public key that must be handed to the
+	// introduction node so it can decrypt its data.
+	BlindingPoint *bronec.PublicKey
+
+	// BlindedHops are the blinded hops of the path, including the
+	// introduction node itself as the first entry.
+	BlindedHops []*BlindedHop
+}
+
+// BuildBlindedPath creates a new blinded path from the given hops using the
+// session key as the first ephemeral blinding key. The first hop is used as
+// the introduction point of the path.
+func BuildBlindedPath(sessionKey *bronec.PrivateKey,
+	hops []*HopInfo) (*BlindedPath, error) {
+
+	if len(hops) == 0 {
+		return nil, ErrNoHops
+	}
+
+	path := &BlindedPath{
+		IntroductionPoint: hops[0].NodePub,
+		BlindingPoint:     sessionKey.PubKey(),
+		BlindedHops:       make([]*BlindedHop, len(hops)),
+	}
+
+	ephemeral := sessionKey
+	for i, hop := range hops {
+		ecdh := &keychain.PrivKeyECDH{PrivKey: ephemeral}
+		ss, err := ecdh.ECDH(hop.NodePub)
+		if err != nil {
+			return nil, err
+		}
+
+		cipherText, err := encryptData(ss, hop.PlainText)
+		if err != nil {
+			return nil, err
+		}
+
+		path.BlindedHops[i] = &BlindedHop{
+			BlindedNodePub: scalarMultPub(
+				hop.NodePub, blindingFactor(ss),
+			),
+			CipherText: cipherText,
+		}
+
+		ephemeral = NextEphemeralPriv(ephemeral, ss)
+	}
+
+	return path, nil
+}
+
+// DecryptHopData decrypts the data encrypted to our node using the blinding
+// point we received along with it.
+func DecryptHopData(nodeKey keychain.SingleKeyECDH,
+	blindingPoint *bronec.PublicKey, cipherText []byte) ([]byte, error) {
+
+	ss, err := nodeKey.ECDH(blindingPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return decryptData(ss, cipherText)
+}
+
+// NextBlindingPoint computes the blinding point that must be handed to the
+// next node of a blinded path, given the blinding point we received.
+func NextBlindingPoint(nodeKey keychain.SingleKeyECDH,
+	blindingPoint *bronec.PublicKey) (*bronec.PublicKey, error) {
+
+	ss, err := nodeKey.ECDH(blindingPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return NextEphemeralPub(blindingPoint, ss), nil
+}
+
+// NextEphemeralPriv derives the ephemeral private key for the next hop of a
+// route from the current ephemeral key and the shared secret with the current
+// hop: e_{i+1} = SHA256(E_i || ss_i) * e_i. Blinded paths and onion message
+// packets derive their ephemeral keys the same way.
+func NextEphemeralPriv(ephemeral *bronec.PrivateKey,
+	ss [32]byte) *bronec.PrivateKey {
+
+	return scalarMultPriv(
+		ephemeral, nextBlindingFactor(ephemeral.PubKey(), ss),
+	)
+}
+
+// NextEphemeralPub derives the ephemeral public key for the next hop of a
+// route from the current ephemeral public key and the shared secret with the
+// current hop: E_{i+1} = SHA256(E_i || ss_i) * E_i.
+func NextEphemeralPub(ephemeral *bronec.PublicKey,
+	ss [32]byte) *bronec.PublicKey {
+
+	return scalarMultPub(ephemeral, nextBlindingFactor(ephemeral, ss))
+}
+
+// BlindedECDH is an implementation of the SingleKeyECDH interface for the
+// blinded private key of a node. The blinded key is never computed directly,
+// instead the remote public key is multiplied with the blinding factor before
+// performing the ECDH operation with the node key.
+type BlindedECDH struct {
+	nodeKey keychain.SingleKeyECDH
+	factor  *big.Int
+}
+
+// NewBlindedECDH creates a new ECDH implementation for our blinded node key
+// given the blinding point we received.
+func NewBlindedECDH(nodeKey keychain.SingleKeyECDH,
+	blindingPoint *bronec.PublicKey) (*BlindedECDH, error) {
+
+	ss, err := nodeKey.ECDH(blindingPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BlindedECDH{
+		nodeKey: nodeKey,
+		factor:  blindingFactor(ss),
+	}, nil
+}
+
+// PubKey returns the blinded public key of our node.
+//
+// NOTE: This is part of the SingleKeyECDH interface.
+func (b *BlindedECDH) PubKey() *bronec.PublicKey {
+	return scalarMultPub(b.nodeKey.PubKey(), b.factor)
+}
+
+// ECDH performs an ECDH operation between our blinded private key and the
+// given public key. If k is our node key and f the blinding factor, we
+// compute:
+//
+//	sx := k*(f*P)
+//	s := sha256(sx.SerializeCompressed())
+//
+// NOTE: This is part of the SingleKeyECDH interface.
+func (b *BlindedECDH) ECDH(pub *bronec.PublicKey) ([32]byte, error) {
+	return b.nodeKey.ECDH(scalarMultPub(pub, b.factor))
+}
+
+// A compile time check to ensure BlindedECDH implements the SingleKeyECDH
+// interface.
+var _ keychain.SingleKeyECDH = (*BlindedECDH)(nil)
+
+// blindingFactor derives the factor used to blind a node's public key from
+// the shared secret.
+func blindingFactor(ss [32]byte) *big.Int {
+	return hmacScalar(blindedNodeIDKey, ss[:])
+}
+
+// nextBlindingFactor derives the factor that is applied to the current
+// blinding point to obtain the next one.
+func nextBlindingFactor(blindingPoint *bronec.PublicKey,
+	ss [32]byte) *big.Int {
+
+	var b bytes.Buffer
+	b.Write(blindingPoint.SerializeCompressed())
+	b.Write(ss[:])
+	h := sha256.Sum256(b.Bytes())
+
+	return new(big.Int).SetBytes(h[:])
+}
+
+// encryptData encrypts the plaintext with the rho key derived from the shared
+// secret.
+func encryptData(ss [32]byte, plainText []byte) ([]byte, error) {
+	aead, err := newAEAD(ss)
+	if err != nil {
+		return nil, err
+	}
+
+	var nonce [chacha20poly1305.NonceSize]byte
+	return aead.Seal(nil, nonce[:], plainText, nil), nil
+}
+
+// decryptData decrypts the ciphertext with the rho key derived from the shared
+// secret.
+func decryptData(ss [32]byte, cipherText []byte) ([]byte, error) {
+	aead, err := newAEAD(ss)
+	if err != nil {
+		return nil, err
+	}
+
+	var nonce [chacha20poly1305.NonceSize]byte
+	plainText, err := aead.Open(nil, nonce[:], cipherText, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDecryptionFailed, err)
+	}
+
+	return plainText, nil
+}
+
+// newAEAD creates a new ChaCha20-Poly1305 instance keyed with the rho key
+// derived from the shared secret.
+func newAEAD(ss [32]byte) (cipher.AEAD, error) {
+	mac := hmac.New(sha256.New, rhoKey)
+	mac.Write(ss[:])
+
+	return chacha20poly1305.New(mac.Sum(nil))
+}
+
+// hmacScalar computes HMAC-SHA256(key, data) and interprets the result as a
+// scalar.
+func hmacScalar(key, data []byte) *big.Int {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+
+	return new(big.Int).SetBytes(mac.Sum(nil))
+}
+
+// scalarMultPub multiplies the given public key with a scalar.
+func scalarMultPub(pub *bronec.PublicKey, k *big.Int) *bronec.PublicKey {
+	res := &bronec.PublicKey{Curve: bronec.S256()}
+	res.X, res.Y = bronec.S256().ScalarMult(pub.X, pub.Y, k.Bytes())
+
+	return res
+}
+
+// scalarMultPriv multiplies the given private key with a scalar modulo the
+// curve order.
+func scalarMultPriv(priv *bronec.PrivateKey,
+	k *big.Int) *bronec.PrivateKey {
+
+	d := new(big.Int).Mul(priv.D, k)
+	d.Mod(d, bronec.S256().N)
+
+	res, _ := bronec.PrivKeyFromBytes(bronec.S256(), d.Bytes())
+	return res
+}
+
+// Encode serializes the blinded path to the passed writer using the
+// blinded_path encoding of the specification.
+func (p *BlindedPath) Encode(w io.Writer) error {
+	if len(p.BlindedHops) > math.MaxUint8 {
+		return fmt.Errorf("blinded path has too many hops: %v",
+			len(p.BlindedHops))
+	}
+
+	_, err := w.Write(p.IntroductionPoint.SerializeCompressed())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(p.BlindingPoint.SerializeCompressed())
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write([]byte{uint8(len(p.BlindedHops))}); err != nil {
+		return err
+	}
+
+	for _, hop := range p.BlindedHops {
+		if len(hop.CipherText) > math.MaxUint16 {
+			return fmt.Errorf("blinded hop data too large: %v",
+				len(hop.CipherText))
+		}
+
+		_, err := w.Write(hop.BlindedNodePub.SerializeCompressed())
+		if err != nil {
+			return err
+		}
+
+		var l [2]byte
+		binary.BigEndian.PutUint16(l[:], uint16(len(hop.CipherText)))
+		if _, err := w.Write(l[:]); err != nil {
+			return err
+		}
+		if _, err := w.Write(hop.CipherText); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DecodeBlindedPath parses a blinded path from the passed reader.
+func DecodeBlindedPath(r io.Reader) (*BlindedPath, error) {
+	var (
+		path BlindedPath
+		err  error
+	)
+
+	path.IntroductionPoint, err = readPubKey(r)
+	if err != nil {
+		return nil, err
+	}
+	path.BlindingPoint, err = readPubKey(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var numHops [1]byte
+	if _, err := io.ReadFull(r, numHops[:]); err != nil {
+		return nil, err
+	}
+	if numHops[0] == 0 {
+		return nil, ErrNoHops
+	}
+
+	path.BlindedHops = make([]*BlindedHop, numHops[0])
+	for i := range path.BlindedHops {
+		nodePub, err := readPubKey(r)
+		if err != nil {
+			return nil, err
+		}
+
+		var l [2]byte
+		if _, err := io.ReadFull(r, l[:]); err != nil {
+			return nil, err
+		}
+
+		cipherText := make([]byte, binary.BigEndian.Uint16(l[:]))
+		if _, err := io.ReadFull(r, cipherText); err != nil {
+			return nil, err
+		}
+
+		path.BlindedHops[i] = &BlindedHop{
+			BlindedNodePub: nodePub,
+			CipherText:     cipherText,
+		}
+	}
+
+	return &path, nil
+}
+
+// readPubKey reads a compressed public key from the passed reader.
+func readPubKey(r io.Reader) (*bronec.PublicKey, error) {
+	var b [33]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return nil, err
+	}
+
+	return bronec.ParsePubKey(b[:], bronec.S256())
+}
@@ -0,0 +1,136 @@
+package blinding
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/brronsuite/broln/keychain"
+	"github.com/brronsuite/broln/lnwire"
+	"github.com/brronsuite/brond/bronec"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBlindedPath tests that every hop of a blinded path can decrypt its data
+// and derive the blinding point of the next hop.
+func TestBlindedPath(t *testing.T) {
+	t.Parallel()
+
+	const numHops = 3
+
+	var (
+		nodeKeys = make([]keychain.SingleKeyECDH, numHops)
+		hops     = make([]*HopInfo, numHops)
+		payloads = make([]*RouteData, numHops)
+	)
+	for i := 0; i < numHops; i++ {
+		priv, err := bronec.NewPrivateKey(bronec.S256())
+		require.NoError(t, err)
+		nodeKeys[i] = &keychain.PrivKeyECDH{PrivKey: priv}
+
+		payloads[i] = &RouteData{
+			ShortChannelID: &lnwire.ShortChannelID{
+				BlockHeight: uint32(i + 1),
+			},
+		}
+	}
+
+	// Every hop except the last one points to the next node, the last one
+	// gets a path id.
+	for i := 0; i < numHops; i++ {
+		if i < numHops-1 {
+			payloads[i].NextNodeID = nodeKeys[i+1].PubKey()
+		} else {
+			payloads[i].PathID = []byte("path id")
+		}
+
+		plainText, err := EncodeRouteData(payloads[i])
+		require.NoError(t, err)
+
+		hops[i] = &HopInfo{
+			NodePub:   nodeKeys[i].PubKey(),
+			PlainText: plainText,
+		}
+	}
+
+	sessionKey, err := bronec.NewPrivateKey(bronec.S256())
+	require.NoError(t, err)
+
+	path, err := BuildBlindedPath(sessionKey, hops)
+	require.NoError(t, err)
+	require.Equal(t, nodeKeys[0].PubKey(), path.IntroductionPoint)
+	require.Len(t, path.BlindedHops, numHops)
+
+	// The path must survive a serialization round trip.
+	var b bytes.Buffer
+	require.NoError(t, path.Encode(&b))
+	decodedPath, err := DecodeBlindedPath(&b)
+	require.NoError(t, err)
+	require.Equal(t, path, decodedPath)
+
+	blindingPoint := path.BlindingPoint
+	for i, hop := range path.BlindedHops {
+		// The hop must know its own blinded public key.
+		blindedKey, err := NewBlindedECDH(nodeKeys[i], blindingPoint)
+		require.NoError(t, err)
+		require.True(t, blindedKey.PubKey().IsEqual(hop.BlindedNodePub))
+
+		plainText, err := DecryptHopData(
+			nodeKeys[i], blindingPoint, hop.CipherText,
+		)
+		require.NoError(t, err)
+
+		data, err := DecodeRouteData(plainText)
+		require.NoError(t, err)
+		require.Equal(
+			t, payloads[i].ShortChannelID, data.ShortChannelID,
+		)
+		require.Equal(t, payloads[i].PathID, data.PathID)
+		if payloads[i].NextNodeID != nil {
+			nextNodeID := payloads[i].NextNodeID
+			require.True(t, nextNodeID.IsEqual(data.NextNodeID))
+		} else {
+			require.Nil(t, data.NextNodeID)
+		}
+
+		// Another node can't decrypt the data.
+		_, err = DecryptHopData(
+			nodeKeys[(i+1)%numHops], blindingPoint, hop.CipherText,
+		)
+		require.ErrorIs(t, err, ErrDecryptionFailed)
+
+		blindingPoint, err = NextBlindingPoint(
+			nodeKeys[i], blindingPoint,
+		)
+		require.NoError(t, err)
+	}
+}
+
+// TestBlindedECDH tests that the ECDH operation with a blinded node key
+// matches the operation with the blinded private key.
+func TestBlindedECDH(t *testing.T) {
+	t.Parallel()
+
+	nodePriv, err := bronec.NewPrivateKey(bronec.S256())
+	require.NoError(t, err)
+	blindingPriv, err := bronec.NewPrivateKey(bronec.S256())
+	require.NoError(t, err)
+	remotePriv, err := bronec.NewPrivateKey(bronec.S256())
+	require.NoError(t, err)
+
+	nodeKey := &keychain.PrivKeyECDH{PrivKey: nodePriv}
+	blindedKey, err := NewBlindedECDH(nodeKey, blindingPriv.PubKey())
+	require.NoError(t, err)
+
+	ss, err := nodeKey.ECDH(blindingPriv.PubKey())
+	require.NoError(t, err)
+	blindedPriv := &keychain.PrivKeyECDH{
+		PrivKey: scalarMultPriv(nodePriv, blindingFactor(ss)),
+	}
+	require.True(t, blindedPriv.PubKey().IsEqual(blindedKey.PubKey()))
+
+	expected, err := blindedPriv.ECDH(remotePriv.PubKey())
+	require.NoError(t, err)
+	actual, err := blindedKey.ECDH(remotePriv.PubKey())
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+}
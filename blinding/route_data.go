@@ -0,0 +1,331 @@
+package blinding
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/brronsuite/broln/lnwire"
+	"github.com/brronsuite/broln/tlv"
+	"github.com/brronsuite/brond/bronec"
+)
+
+const (
+	// PaddingType is the TLV type of the padding that is used to bring
+	// all encrypted data payloads of a path to the same length.
+	PaddingType tlv.Type = 1
+
+	// ShortChannelIDType is the TLV type of the short channel id of the
+	// outgoing channel.
+	ShortChannelIDType tlv.Type = 2
+
+	// NextNodeIDType is the TLV type of the public key of the next node.
+	NextNodeIDType tlv.Type = 4
+
+	// PathIDType is the TLV type of the path id that the final node of a
+	// path uses to authenticate that it created the path.
+	PathIDType tlv.Type = 6
+
+	// NextBlindingOverrideType is the TLV type of the blinding point that
+	// replaces the next blinding point when joining two blinded paths.
+	NextBlindingOverrideType tlv.Type = 8
+
+	// PaymentRelayType is the TLV type of the fees and cltv delta that an
+	// intermediate node of a blinded payment route applies.
+	PaymentRelayType tlv.Type = 10
+
+	// PaymentConstraintsType is the TLV type of the constraints that an
+	// HTLC routed through a blinded payment route must satisfy.
+	PaymentConstraintsType tlv.Type = 12
+
+	// AllowedFeaturesType is the TLV type of the features that the HTLC
+	// may use within a blinded payment route.
+	AllowedFeaturesType tlv.Type = 14
+)
+
+// PaymentRelay describes how an intermediate node of a blinded payment route
+// derives the outgoing HTLC from the incoming one.
+type PaymentRelay struct {
+	// CltvExpiryDelta is the difference between the incoming and the
+	// outgoing expiry.
+	CltvExpiryDelta uint16
+
+	// FeeProportionalMillionths is the proportional fee charged by the
+	// node.
+	FeeProportionalMillionths uint32
+
+	// FeeBaseMsat is the base fee charged by the node.
+	FeeBaseMsat uint32
+}
+
+// ForwardAmount returns the amount the node forwards for the given incoming
+// amount. False is returned if the incoming amount doesn't cover the base fee.
+func (p *PaymentRelay) ForwardAmount(
+	incoming lnwire.MilliBronees) (lnwire.MilliBronees, bool) {
+
+	base := lnwire.MilliBronees(p.FeeBaseMsat)
+	if incoming < base {
+		return 0, false
+	}
+
+	prop := lnwire.MilliBronees(p.FeeProportionalMillionths)
+	amt := ((incoming-base)*1e6 + 1e6 + prop - 1) / (1e6 + prop)
+
+	return amt, true
+}
+
+// PaymentConstraints are the limits set by the recipient of a blinded payment
+// route that every HTLC routed through it must satisfy.
+type PaymentConstraints struct {
+	// MaxCltvExpiry is the maximum expiry the HTLC may have at the node.
+	MaxCltvExpiry uint32
+
+	// HtlcMinimumMsat is the minimum amount the HTLC must carry at the
+	// node.
+	HtlcMinimumMsat lnwire.MilliBronees
+}
+
+// RouteData is the plaintext content of the encrypted_data of a blinded hop.
+// All fields are optional.
+type RouteData struct {
+	// Padding is ignored by the receiver.
+	Padding []byte
+
+	// ShortChannelID is the channel to forward through. Onion messages
+	// use NextNodeID instead.
+	ShortChannelID *lnwire.ShortChannelID
+
+	// NextNodeID is the real public key of the next node of the path.
+	NextNodeID *bronec.PublicKey
+
+	// PathID is only set for the final node of a path.
+	PathID []byte
+
+	// NextBlindingOverride replaces the blinding point that is sent to
+	// the next node.
+	NextBlindingOverride *bronec.PublicKey
+
+	// PaymentRelay is set for the intermediate nodes of a payment path.
+	PaymentRelay *PaymentRelay
+
+	// PaymentConstraints are the constraints of a payment path.
+	PaymentConstraints *PaymentConstraints
+
+	// AllowedFeatures is the serialized feature vector of the features
+	// the HTLC may use within a payment path.
+	AllowedFeatures []byte
+}
+
+// EncodeRouteData serializes the route data as a TLV stream.
+func EncodeRouteData(data *RouteData) ([]byte, error) {
+	var records []tlv.Record
+	if data.Padding != nil {
+		records = append(records, tlv.MakePrimitiveRecord(
+			PaddingType, &data.Padding,
+		))
+	}
+	if data.ShortChannelID != nil {
+		records = append(records, tlv.MakeStaticRecord(
+			ShortChannelIDType, data.ShortChannelID, 8,
+			lnwire.EShortChannelID, lnwire.DShortChannelID,
+		))
+	}
+	if data.NextNodeID != nil {
+		records = append(records, tlv.MakePrimitiveRecord(
+			NextNodeIDType, &data.NextNodeID,
+		))
+	}
+	if data.PathID != nil {
+		records = append(records, tlv.MakePrimitiveRecord(
+			PathIDType, &data.PathID,
+		))
+	}
+	if data.NextBlindingOverride != nil {
+		records = append(records, tlv.MakePrimitiveRecord(
+			NextBlindingOverrideType, &data.NextBlindingOverride,
+		))
+	}
+	if data.PaymentRelay != nil {
+		records = append(records, newPaymentRelayRecord(
+			data.PaymentRelay,
+		))
+	}
+	if data.PaymentConstraints != nil {
+		records = append(records, newPaymentConstraintsRecord(
+			data.PaymentConstraints,
+		))
+	}
+	if data.AllowedFeatures != nil {
+		records = append(records, tlv.MakePrimitiveRecord(
+			AllowedFeaturesType, &data.AllowedFeatures,
+		))
+	}
+
+	stream, err := tlv.NewStream(records...)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if err := stream.Encode(&b); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// DecodeRouteData parses the route data from a decrypted TLV stream.
+func DecodeRouteData(b []byte) (*RouteData, error) {
+	var (
+		data                 RouteData
+		scid                 lnwire.ShortChannelID
+		nextNodeID           *bronec.PublicKey
+		nextBlindingOverride *bronec.PublicKey
+		paymentRelay         PaymentRelay
+		paymentConstraints   PaymentConstraints
+	)
+
+	stream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(PaddingType, &data.Padding),
+		tlv.MakeStaticRecord(
+			ShortChannelIDType, &scid, 8, lnwire.EShortChannelID,
+			lnwire.DShortChannelID,
+		),
+		tlv.MakePrimitiveRecord(NextNodeIDType, &nextNodeID),
+		tlv.MakePrimitiveRecord(PathIDType, &data.PathID),
+		tlv.MakePrimitiveRecord(
+			NextBlindingOverrideType, &nextBlindingOverride,
+		),
+		newPaymentRelayRecord(&paymentRelay),
+		newPaymentConstraintsRecord(&paymentConstraints),
+		tlv.MakePrimitiveRecord(
+			AllowedFeaturesType, &data.AllowedFeatures,
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedTypes, err := stream.DecodeWithParsedTypes(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := parsedTypes[ShortChannelIDType]; ok {
+		data.ShortChannelID = &scid
+	}
+	if _, ok := parsedTypes[NextNodeIDType]; ok {
+		data.NextNodeID = nextNodeID
+	}
+	if _, ok := parsedTypes[NextBlindingOverrideType]; ok {
+		data.NextBlindingOverride = nextBlindingOverride
+	}
+	if _, ok := parsedTypes[PaymentRelayType]; ok {
+		data.PaymentRelay = &paymentRelay
+	}
+	if _, ok := parsedTypes[PaymentConstraintsType]; ok {
+		data.PaymentConstraints = &paymentConstraints
+	}
+
+	return &data, nil
+}
+
+// newPaymentRelayRecord creates a TLV record for the payment relay, which is
+// encoded as [u16 cltv_delta][u32 fee_prop][tu32 fee_base].
+func newPaymentRelayRecord(relay *PaymentRelay) tlv.Record {
+	return tlv.MakeDynamicRecord(
+		PaymentRelayType, relay, func() uint64 {
+			return 6 + tlv.SizeTUint32(relay.FeeBaseMsat)
+		},
+		encodePaymentRelay, decodePaymentRelay,
+	)
+}
+
+// encodePaymentRelay is an encoder for PaymentRelay.
+func encodePaymentRelay(w io.Writer, val interface{}, buf *[8]byte) error {
+	if v, ok := val.(*PaymentRelay); ok {
+		err := tlv.EUint16T(w, v.CltvExpiryDelta, buf)
+		if err != nil {
+			return err
+		}
+
+		err = tlv.EUint32T(w, v.FeeProportionalMillionths, buf)
+		if err != nil {
+			return err
+		}
+
+		return tlv.ETUint32T(w, v.FeeBaseMsat, buf)
+	}
+
+	return tlv.NewTypeForEncodingErr(val, "*PaymentRelay")
+}
+
+// decodePaymentRelay is a decoder for PaymentRelay.
+func decodePaymentRelay(r io.Reader, val interface{}, buf *[8]byte,
+	l uint64) error {
+
+	if v, ok := val.(*PaymentRelay); ok && l >= 6 && l <= 10 {
+		err := tlv.DUint16(r, &v.CltvExpiryDelta, buf, 2)
+		if err != nil {
+			return err
+		}
+
+		err = tlv.DUint32(r, &v.FeeProportionalMillionths, buf, 4)
+		if err != nil {
+			return err
+		}
+
+		return tlv.DTUint32(r, &v.FeeBaseMsat, buf, l-6)
+	}
+
+	return tlv.NewTypeForDecodingErr(val, "*PaymentRelay", l, 10)
+}
+
+// newPaymentConstraintsRecord creates a TLV record for the payment
+// constraints, which are encoded as [u32 max_cltv][tu64 htlc_min].
+func newPaymentConstraintsRecord(constraints *PaymentConstraints) tlv.Record {
+	return tlv.MakeDynamicRecord(
+		PaymentConstraintsType, constraints, func() uint64 {
+			return 4 + tlv.SizeTUint64(
+				uint64(constraints.HtlcMinimumMsat),
+			)
+		},
+		encodePaymentConstraints, decodePaymentConstraints,
+	)
+}
+
+// encodePaymentConstraints is an encoder for PaymentConstraints.
+func encodePaymentConstraints(w io.Writer, val interface{},
+	buf *[8]byte) error {
+
+	if v, ok := val.(*PaymentConstraints); ok {
+		if err := tlv.EUint32T(w, v.MaxCltvExpiry, buf); err != nil {
+			return err
+		}
+
+		return tlv.ETUint64T(w, uint64(v.HtlcMinimumMsat), buf)
+	}
+
+	return tlv.NewTypeForEncodingErr(val, "*PaymentConstraints")
+}
+
+// decodePaymentConstraints is a decoder for PaymentConstraints.
+func decodePaymentConstraints(r io.Reader, val interface{}, buf *[8]byte,
+	l uint64) error {
+
+	if v, ok := val.(*PaymentConstraints); ok && l >= 4 && l <= 12 {
+		err := tlv.DUint32(r, &v.MaxCltvExpiry, buf, 4)
+		if err != nil {
+			return err
+		}
+
+		var htlcMin uint64
+		if err := tlv.DTUint64(r, &htlcMin, buf, l-4); err != nil {
+			return err
+		}
+		v.HtlcMinimumMsat = lnwire.MilliBronees(htlcMin)
+
+		return nil
+	}
+
+	return tlv.NewTypeForDecodingErr(val, "*PaymentConstraints", l, 12)
+}
@@ -219,6 +219,10 @@ type ChainControl struct {
 	// KeyRing represents a set of keys that we have the private keys to.
 	KeyRing keychain.SecretKeyRing
 
+	// MuSig2Signer is used to create and keep track of MuSig2 signing
+	// sessions.
+	MuSig2Signer input.MuSig2Signer
+
 	// Wc is an abstraction over some basic wallet commands. This base set
 	// of commands will be provided to the Wallet *LightningWallet raw
 	// pointer below.
@@ -698,6 +702,18 @@ func NewChainControl(walletConfig lnwallet.Config,
 		KeyRing:             walletConfig.SecretKeyRing,
 	}
 
+	// A signer that doesn't have access to the private keys itself (for
+	// example a remote signer) manages the MuSig2 sessions on its own.
+	// Otherwise we keep track of them locally, using the key ring to
+	// derive the signing keys.
+	if muSig2Signer, ok := walletConfig.Signer.(input.MuSig2Signer); ok {
+		cc.MuSig2Signer = muSig2Signer
+	} else {
+		cc.MuSig2Signer = input.NewMusigSessionManager(
+			walletConfig.SecretKeyRing,
+		)
+	}
+
 	ccCleanup := func() {
 		if cc.Wallet != nil {
 			if err := cc.Wallet.Shutdown(); err != nil {
@@ -24,8 +24,14 @@ type ChannelAcceptRequest struct {
 	Node *bronec.PublicKey
 
 	// OpenChanMsg is the actual OpenChannel protocol message that the peer
-	// sent to us.
+	// sent to us. If the peer proposed a dual funded channel, it holds the
+	// fields of the OpenChannel2 message that both messages have in
+	// common.
 	OpenChanMsg *lnwire.OpenChannel
+
+	// OpenChan2Msg is the OpenChannel2 protocol message that the peer sent
+	// to us if it proposed a dual funded channel, otherwise it's nil.
+	OpenChan2Msg *lnwire.OpenChannel2
 }
 
 // ChannelAcceptResponse is a struct containing the response to a request to
@@ -62,6 +68,14 @@ type ChannelAcceptResponse struct {
 	// MinAcceptDepth is the minimum depth that the initiator of the
 	// channel should wait before considering the channel open.
 	MinAcceptDepth uint16
+
+	// ZeroConf indicates that the fundee wishes to send min_depth = 0 and
+	// request a zero-conf channel with the counter-party.
+	ZeroConf bool
+
+	// LocalFundingAmt is the amount we contribute to a dual funded
+	// channel. It's only used if the peer proposed a dual funded channel.
+	LocalFundingAmt bronutil.Amount
 }
 
 // NewChannelAcceptResponse is a constructor for a channel accept response,
@@ -72,7 +86,8 @@ type ChannelAcceptResponse struct {
 func NewChannelAcceptResponse(accept bool, acceptErr error,
 	upfrontShutdown lnwire.DeliveryAddress, csvDelay, htlcLimit,
 	minDepth uint16, reserve bronutil.Amount, inFlight,
-	minHtlcIn lnwire.MilliBronees) *ChannelAcceptResponse {
+	minHtlcIn lnwire.MilliBronees,
+	zeroConf bool) *ChannelAcceptResponse {
 
 	resp := &ChannelAcceptResponse{
 		UpfrontShutdown: upfrontShutdown,
@@ -82,6 +97,7 @@ func NewChannelAcceptResponse(accept bool, acceptErr error,
 		HtlcLimit:       htlcLimit,
 		MinHtlcIn:       minHtlcIn,
 		MinAcceptDepth:  minDepth,
+		ZeroConf:        zeroConf,
 	}
 
 	// If we want to accept the channel, we return a response with a nil
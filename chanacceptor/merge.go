@@ -18,6 +18,7 @@ const (
 	fieldMinIn           = "min htlc in"
 	fieldInFlightTotal   = "in flight total"
 	fieldUpfrontShutdown = "upfront shutdown"
+	fieldLocalFundingAmt = "local funding amt"
 )
 
 // fieldMismatchError returns a merge error for a named field when we get two
@@ -117,6 +118,10 @@ func mergeResponse(current, new ChannelAcceptResponse) (ChannelAcceptResponse,
 	}
 	current.MinAcceptDepth = uint16(minDepth)
 
+	// A single acceptor asking for a zero-conf channel is enough, as the
+	// funding manager rejects the channel if it wasn't requested.
+	current.ZeroConf = current.ZeroConf || new.ZeroConf
+
 	reserve, err := mergeInt64(
 		fieldReserve, int64(current.Reserve), int64(new.Reserve),
 	)
@@ -140,6 +145,15 @@ func mergeResponse(current, new ChannelAcceptResponse) (ChannelAcceptResponse,
 		return current, err
 	}
 
+	localFundingAmt, err := mergeInt64(
+		fieldLocalFundingAmt, int64(current.LocalFundingAmt),
+		int64(new.LocalFundingAmt),
+	)
+	if err != nil {
+		return current, err
+	}
+	current.LocalFundingAmt = bronutil.Amount(localFundingAmt)
+
 	current.UpfrontShutdown, err = mergeDeliveryAddress(
 		fieldUpfrontShutdown, current.UpfrontShutdown,
 		new.UpfrontShutdown,
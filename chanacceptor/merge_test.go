@@ -21,6 +21,7 @@ func TestMergeResponse(t *testing.T) {
 			HtlcLimit:       5,
 			MinHtlcIn:       6,
 			MinAcceptDepth:  7,
+			LocalFundingAmt: 8,
 		}
 	)
 
@@ -114,6 +115,29 @@ func TestMergeResponse(t *testing.T) {
 			},
 			err: fieldMismatchError(fieldMinDep, 1, 2),
 		},
+		{
+			name: "different local funding amt",
+			current: ChannelAcceptResponse{
+				LocalFundingAmt: 1,
+			},
+			new: ChannelAcceptResponse{
+				LocalFundingAmt: 2,
+			},
+			err: fieldMismatchError(fieldLocalFundingAmt, 1, 2),
+		},
+		{
+			name: "zero conf",
+			current: ChannelAcceptResponse{
+				ZeroConf: false,
+			},
+			new: ChannelAcceptResponse{
+				ZeroConf: true,
+			},
+			merged: ChannelAcceptResponse{
+				ZeroConf: true,
+			},
+			err: nil,
+		},
 		{
 			name: "merge all values",
 			current: ChannelAcceptResponse{
@@ -43,6 +43,16 @@ var (
 	errMaxHtlcTooHigh = fmt.Errorf("htlc limit exceeds spec limit of: %v",
 		input.MaxHTLCNumber/2)
 
+	// errNotDualFunded is returned if a local funding amount is set for a
+	// channel that wasn't proposed as dual funded channel.
+	errNotDualFunded = errors.New("local funding amount requires a " +
+		"dual funded channel")
+
+	// errZeroConfMinDepth is returned if a zero-conf channel is accepted
+	// with a non-zero min accept depth.
+	errZeroConfMinDepth = errors.New("zero-conf channel requires min " +
+		"accept depth of zero")
+
 	// maxErrorLength is the maximum error length we allow the error we
 	// send to our peer to be.
 	maxErrorLength = 500
@@ -107,7 +117,7 @@ func (r *RPCAcceptor) Accept(req *ChannelAcceptRequest) *ChannelAcceptResponse {
 	// Create a rejection response which we can use for the cases where we
 	// reject the channel.
 	rejectChannel := NewChannelAcceptResponse(
-		false, errChannelRejected, nil, 0, 0, 0, 0, 0, 0,
+		false, errChannelRejected, nil, 0, 0, 0, 0, 0, 0, false,
 	)
 
 	// Send the request to the newRequests channel.
@@ -216,6 +226,8 @@ func (r *RPCAcceptor) receiveResponses(errChan chan error,
 			MaxHtlcCount:    resp.MaxHtlcCount,
 			MinHtlcIn:       resp.MinHtlcIn,
 			MinAcceptDepth:  resp.MinAcceptDepth,
+			ZeroConf:        resp.ZeroConf,
+			LocalFundingAmt: resp.LocalFundingAmt,
 		}
 
 		// We have received a decision for one of our channel
@@ -258,11 +270,29 @@ func (r *RPCAcceptor) sendAcceptRequests(errChan chan error,
 
 			// Map the channel commitment type to its RPC
 			// counterpart.
-			var commitmentType lnrpc.CommitmentType
+			var (
+				commitmentType lnrpc.CommitmentType
+				wantsZeroConf  bool
+				wantsScidAlias bool
+			)
 			if req.OpenChanMsg.ChannelType != nil {
 				channelFeatures := lnwire.RawFeatureVector(
 					*req.OpenChanMsg.ChannelType,
 				)
+
+				// The zero-conf and scid-alias bits are
+				// reported separately, so we strip them before
+				// mapping the base commitment type.
+				wantsZeroConf = channelFeatures.IsSet(
+					lnwire.ZeroConfRequired,
+				)
+				wantsScidAlias = channelFeatures.IsSet(
+					lnwire.ScidAliasRequired,
+				)
+				channelFeatures = *channelFeatures.Clone()
+				channelFeatures.Unset(lnwire.ZeroConfRequired)
+				channelFeatures.Unset(lnwire.ScidAliasRequired)
+
 				switch {
 				case channelFeatures.OnlyContains(
 					lnwire.ScriptEnforcedLeaseRequired,
@@ -310,6 +340,9 @@ func (r *RPCAcceptor) sendAcceptRequests(errChan chan error,
 				MaxAcceptedHtlcs: uint32(req.OpenChanMsg.MaxAcceptedHTLCs),
 				ChannelFlags:     uint32(req.OpenChanMsg.ChannelFlags),
 				CommitmentType:   commitmentType,
+				WantsZeroConf:    wantsZeroConf,
+				WantsScidAlias:   wantsScidAlias,
+				WantsDualFund:    req.OpenChan2Msg != nil,
 			}
 
 			if err := r.send(chanAcceptReq); err != nil {
@@ -334,13 +367,14 @@ func (r *RPCAcceptor) sendAcceptRequests(errChan chan error,
 			// valid, we log our error and proceed to deliver the
 			// rejection.
 			accept, acceptErr, shutdown, err := r.validateAcceptorResponse(
-				requestInfo.request.OpenChanMsg.DustLimit, resp,
+				requestInfo.request.OpenChanMsg.DustLimit,
+				requestInfo.request.OpenChan2Msg != nil, resp,
 			)
 			if err != nil {
 				log.Errorf("Invalid acceptor response: %v", err)
 			}
 
-			requestInfo.response <- NewChannelAcceptResponse(
+			acceptResp := NewChannelAcceptResponse(
 				accept, acceptErr, shutdown,
 				uint16(resp.CsvDelay),
 				uint16(resp.MaxHtlcCount),
@@ -348,7 +382,14 @@ func (r *RPCAcceptor) sendAcceptRequests(errChan chan error,
 				bronutil.Amount(resp.ReserveSat),
 				lnwire.MilliBronees(resp.InFlightMaxMsat),
 				lnwire.MilliBronees(resp.MinHtlcIn),
+				resp.ZeroConf,
 			)
+			if accept {
+				acceptResp.LocalFundingAmt = bronutil.Amount(
+					resp.LocalFundingAmt,
+				)
+			}
+			requestInfo.response <- acceptResp
 
 			// Delete the channel from the acceptRequests map.
 			delete(acceptRequests, pendingID)
@@ -369,8 +410,8 @@ func (r *RPCAcceptor) sendAcceptRequests(errChan chan error,
 // acceptor, returning a boolean indicating whether to accept the channel, an
 // error to send to the peer, and any validation errors that occurred.
 func (r *RPCAcceptor) validateAcceptorResponse(dustLimit bronutil.Amount,
-	req *lnrpc.ChannelAcceptResponse) (bool, error, lnwire.DeliveryAddress,
-	error) {
+	dualFund bool, req *lnrpc.ChannelAcceptResponse) (bool, error,
+	lnwire.DeliveryAddress, error) {
 
 	channelStr := hex.EncodeToString(req.PendingChanId)
 
@@ -397,6 +438,25 @@ func (r *RPCAcceptor) validateAcceptorResponse(dustLimit bronutil.Amount,
 		return false, errChannelRejected, nil, errInsufficientReserve
 	}
 
+	// A zero-conf channel is usable right away, so requiring
+	// confirmations for it is contradictory.
+	if req.ZeroConf && req.MinAcceptDepth != 0 {
+		log.Errorf("Zero-conf channel: %v has non-zero min accept "+
+			"depth: %v", channelStr, req.MinAcceptDepth)
+
+		return false, errChannelRejected, nil, errZeroConfMinDepth
+	}
+
+	// We can only contribute funds if the initiator proposed a dual
+	// funded channel.
+	if req.LocalFundingAmt != 0 && !dualFund {
+		log.Errorf("Local funding amount: %v sat for channel: %v "+
+			"that isn't dual funded", req.LocalFundingAmt,
+			channelStr)
+
+		return false, errChannelRejected, nil, errNotDualFunded
+	}
+
 	// Attempt to parse the upfront shutdown address provided.
 	upfront, err := chancloser.ParseUpfrontShutdownAddress(
 		req.UpfrontShutdown, r.params,
@@ -27,6 +27,7 @@ func TestValidateAcceptorResponse(t *testing.T) {
 	tests := []struct {
 		name        string
 		dustLimit   bronutil.Amount
+		dualFund    bool
 		response    *lnrpc.ChannelAcceptResponse
 		accept      bool
 		acceptorErr error
@@ -115,6 +116,38 @@ func TestValidateAcceptorResponse(t *testing.T) {
 			acceptorErr: errChannelRejected,
 			error:       errMaxHtlcTooHigh,
 		},
+		{
+			name: "zero-conf with min depth",
+			response: &lnrpc.ChannelAcceptResponse{
+				Accept:         true,
+				ZeroConf:       true,
+				MinAcceptDepth: 1,
+			},
+			accept:      false,
+			acceptorErr: errChannelRejected,
+			error:       errZeroConfMinDepth,
+		},
+		{
+			name: "local funding for single funded channel",
+			response: &lnrpc.ChannelAcceptResponse{
+				Accept:          true,
+				LocalFundingAmt: 100_000,
+			},
+			accept:      false,
+			acceptorErr: errChannelRejected,
+			error:       errNotDualFunded,
+		},
+		{
+			name:     "local funding for dual funded channel",
+			dualFund: true,
+			response: &lnrpc.ChannelAcceptResponse{
+				Accept:          true,
+				LocalFundingAmt: 100_000,
+			},
+			accept:      true,
+			acceptorErr: nil,
+			error:       nil,
+		},
 	}
 
 	for _, test := range tests {
@@ -128,7 +161,7 @@ func TestValidateAcceptorResponse(t *testing.T) {
 			)
 
 			accept, acceptErr, shutdown, err := acceptor.validateAcceptorResponse(
-				test.dustLimit, test.response,
+				test.dustLimit, test.dualFund, test.response,
 			)
 			require.Equal(t, test.accept, accept)
 			require.Equal(t, test.acceptorErr, acceptErr)
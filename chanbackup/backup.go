@@ -36,7 +36,8 @@ type AddressSource interface {
 // the channel, as well as addressing information so we can find the peer and
 // reconnect to them to initiate the protocol.
 func assembleChanBackup(addrSource AddressSource,
-	openChan *channeldb.OpenChannel) (*Single, error) {
+	openChan *channeldb.OpenChannel, opts ...SingleOption) (*Single,
+	error) {
 
 	log.Debugf("Crafting backup for ChannelPoint(%v)",
 		openChan.FundingOutpoint)
@@ -48,7 +49,7 @@ func assembleChanBackup(addrSource AddressSource,
 		return nil, err
 	}
 
-	single := NewSingle(openChan, nodeAddrs)
+	single := NewSingle(openChan, nodeAddrs, opts...)
 
 	return &single, nil
 }
@@ -57,7 +58,7 @@ func assembleChanBackup(addrSource AddressSource,
 // the target channel identified by its channel point. If we're unable to find
 // the target channel, then an error will be returned.
 func FetchBackupForChan(chanPoint wire.OutPoint, chanSource LiveChannelSource,
-	addrSource AddressSource) (*Single, error) {
+	addrSource AddressSource, opts ...SingleOption) (*Single, error) {
 
 	// First, we'll query the channel source to see if the channel is known
 	// and open within the database.
@@ -70,7 +71,9 @@ func FetchBackupForChan(chanPoint wire.OutPoint, chanSource LiveChannelSource,
 
 	// Once we have the target channel, we can assemble the backup using
 	// the source to obtain any extra information that we may need.
-	staticChanBackup, err := assembleChanBackup(addrSource, targetChan)
+	staticChanBackup, err := assembleChanBackup(
+		addrSource, targetChan, opts...,
+	)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create chan backup: %v", err)
 	}
@@ -81,7 +84,7 @@ func FetchBackupForChan(chanPoint wire.OutPoint, chanSource LiveChannelSource,
 // FetchStaticChanBackups will return a plaintext static channel back up for
 // all known active/open channels within the passed channel source.
 func FetchStaticChanBackups(chanSource LiveChannelSource,
-	addrSource AddressSource) ([]Single, error) {
+	addrSource AddressSource, opts ...SingleOption) ([]Single, error) {
 
 	// First, we'll query the backup source for information concerning all
 	// currently open and available channels.
@@ -95,7 +98,9 @@ func FetchStaticChanBackups(chanSource LiveChannelSource,
 	// channel.
 	staticChanBackups := make([]Single, 0, len(openChans))
 	for _, openChan := range openChans {
-		chanBackup, err := assembleChanBackup(addrSource, openChan)
+		chanBackup, err := assembleChanBackup(
+			addrSource, openChan, opts...,
+		)
 		if err != nil {
 			return nil, err
 		}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"sort"
 
 	"github.com/brronsuite/broln/keychain"
 	"github.com/brronsuite/broln/lnwire"
@@ -49,6 +50,19 @@ type Multi struct {
 // concatenated. To pack this payload, we then apply our chacha20 AEAD to the
 // entire payload, using the 24-byte nonce as associated data.
 func (m Multi) PackToWriter(w io.Writer, keyRing keychain.KeyRing) error {
+	var multiBackupBuffer bytes.Buffer
+	if err := m.Serialize(&multiBackupBuffer); err != nil {
+		return err
+	}
+
+	// With the plaintext multi backup assembled, we'll now encrypt it
+	// directly to the passed writer.
+	return encryptPayloadToWriter(multiBackupBuffer, w, keyRing)
+}
+
+// Serialize writes out the plaintext version of the multi backup into the
+// passed io.Writer.
+func (m Multi) Serialize(w io.Writer) error {
 	// The only version that we know how to pack atm is version 0. Attempts
 	// to pack any other version will result in an error.
 	switch m.Version {
@@ -60,10 +74,8 @@ func (m Multi) PackToWriter(w io.Writer, keyRing keychain.KeyRing) error {
 			"of %v", m.Version)
 	}
 
-	var multiBackupBuffer bytes.Buffer
-
 	// First, we'll write out the version of this multi channel baackup.
-	err := lnwire.WriteElements(&multiBackupBuffer, byte(m.Version))
+	err := lnwire.WriteElements(w, byte(m.Version))
 	if err != nil {
 		return err
 	}
@@ -72,24 +84,46 @@ func (m Multi) PackToWriter(w io.Writer, keyRing keychain.KeyRing) error {
 	// we'll now write the total number of backups to expect after this
 	// point.
 	numBackups := uint32(len(m.StaticBackups))
-	err = lnwire.WriteElements(&multiBackupBuffer, numBackups)
+	err = lnwire.WriteElements(w, numBackups)
 	if err != nil {
 		return err
 	}
 
 	// Next, we'll serialize the raw plaintext version of each of the
-	// backup into the intermediate buffer.
+	// backup into the writer.
 	for _, chanBackup := range m.StaticBackups {
-		err := chanBackup.Serialize(&multiBackupBuffer)
+		err := chanBackup.Serialize(w)
 		if err != nil {
 			return fmt.Errorf("unable to serialize backup "+
 				"for %v: %v", chanBackup.FundingOutpoint, err)
 		}
 	}
 
-	// With the plaintext multi backup assembled, we'll now encrypt it
-	// directly to the passed writer.
-	return encryptPayloadToWriter(multiBackupBuffer, w, keyRing)
+	return nil
+}
+
+// WithoutCommitState returns a copy of the multi backup in which none of the
+// backups carry the commitment state of their channel. Such a copy can be
+// handed to remote backup locations, as unlike the commitment state it
+// doesn't become dangerous to use once the channels advance. The backups of
+// the copy are sorted by their funding outpoint, so that copies of the same
+// set of channels serialize to the same plaintext.
+func (m Multi) WithoutCommitState() Multi {
+	backups := make([]Single, 0, len(m.StaticBackups))
+	for _, backup := range m.StaticBackups {
+		backup.CommitState = nil
+		backups = append(backups, backup)
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].FundingOutpoint.String() <
+			backups[j].FundingOutpoint.String()
+	})
+
+	return Multi{
+		Version:       m.Version,
+		StaticBackups: backups,
+	}
 }
 
 // UnpackFromReader attempts to unpack (decrypt+deserialize) a packed
@@ -2,6 +2,7 @@ package chanbackup
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"net"
 	"os"
@@ -12,6 +13,10 @@ import (
 	"github.com/brronsuite/broln/keychain"
 )
 
+// ErrSubSwapperExiting is returned if the SubSwapper is shutting down before
+// a requested backup update has been written.
+var ErrSubSwapperExiting = errors.New("sub-swapper exiting")
+
 // Swapper is an interface that allows the chanbackup.SubSwapper to update the
 // main multi backup location once it learns of new channels or that prior
 // channels have been closed.
@@ -93,8 +98,26 @@ type SubSwapper struct {
 	// multi backup.
 	keyRing keychain.KeyRing
 
+	// singleOpts are the options used to create the backups of new
+	// channels.
+	singleOpts []SingleOption
+
 	Swapper
 
+	// sinks are the uploaders of the remote sinks every updated backup is
+	// pushed to in addition to the Swapper.
+	sinks []*sinkUploader
+
+	// remoteBackup is the plaintext of the backup last pushed to the
+	// sinks. As the sinks never receive the commitment state of the
+	// channels, we use it to skip pushing backups that only differ in the
+	// commitment state.
+	remoteBackup []byte
+
+	// commitUpdates is used to hand the backups carrying the latest
+	// commitment state of a channel to the backupUpdater goroutine.
+	commitUpdates chan *commitUpdate
+
 	quit chan struct{}
 	wg   sync.WaitGroup
 }
@@ -102,9 +125,11 @@ type SubSwapper struct {
 // NewSubSwapper creates a new instance of the SubSwapper given the starting
 // set of channels, and the required interfaces to be notified of new channel
 // updates, pack a multi backup, and swap the current best backup from its
-// storage location.
+// storage location. The backups of new channels are created using the given
+// options. Every updated backup is also pushed to the given sinks.
 func NewSubSwapper(startingChans []Single, chanNotifier ChannelNotifier,
-	keyRing keychain.KeyRing, backupSwapper Swapper) (*SubSwapper, error) {
+	keyRing keychain.KeyRing, backupSwapper Swapper,
+	singleOpts []SingleOption, sinks ...Sink) (*SubSwapper, error) {
 
 	// First, we'll subscribe to the latest set of channel updates given
 	// the set of channels we already know of.
@@ -124,21 +149,75 @@ func NewSubSwapper(startingChans []Single, chanNotifier ChannelNotifier,
 		backupState[chanBackup.FundingOutpoint] = chanBackup
 	}
 
+	uploaders := make([]*sinkUploader, 0, len(sinks))
+	for _, sink := range sinks {
+		uploaders = append(uploaders, newSinkUploader(sink))
+	}
+
 	return &SubSwapper{
-		backupState: backupState,
-		chanEvents:  chanEvents,
-		keyRing:     keyRing,
-		Swapper:     backupSwapper,
-		quit:        make(chan struct{}),
+		backupState:   backupState,
+		chanEvents:    chanEvents,
+		keyRing:       keyRing,
+		singleOpts:    singleOpts,
+		Swapper:       backupSwapper,
+		sinks:         uploaders,
+		commitUpdates: make(chan *commitUpdate),
+		quit:          make(chan struct{}),
 	}, nil
 }
 
+// commitUpdate is a request to replace the backup of a channel with one that
+// carries the latest commitment state of the channel.
+type commitUpdate struct {
+	// backup is the new backup of the channel.
+	backup Single
+
+	// errChan is sent the result of writing the new backup to disk.
+	errChan chan error
+}
+
+// UpdateCommitState replaces the backup of an open channel with the given one,
+// which carries the latest local commitment state of the channel, and only
+// returns once the new backup has been written by the Swapper. It must be
+// called before the prior commitment is revoked, as restoring a backup that
+// carries a revoked commitment allows the remote party to sweep all funds of
+// the channel. Updates that are requested concurrently are written at once.
+func (s *SubSwapper) UpdateCommitState(backup Single) error {
+	update := &commitUpdate{
+		backup:  backup,
+		errChan: make(chan error, 1),
+	}
+
+	select {
+	case s.commitUpdates <- update:
+	case <-s.quit:
+		return ErrSubSwapperExiting
+	}
+
+	select {
+	case err := <-update.errChan:
+		return err
+	case <-s.quit:
+		return ErrSubSwapperExiting
+	}
+}
+
 // Start starts the chanbackup.SubSwapper.
 func (s *SubSwapper) Start() error {
 	var startErr error
 	s.started.Do(func() {
 		log.Infof("Starting chanbackup.SubSwapper")
 
+		// Launch the uploaders of our sinks first, so that the backup
+		// we create below is pushed to them as well.
+		for _, sink := range s.sinks {
+			s.wg.Add(1)
+			go func(sink *sinkUploader) {
+				defer s.wg.Done()
+				sink.uploader(s.quit)
+			}(sink)
+		}
+
 		// Before we enter our main loop, we'll update the on-disk
 		// state with the latest Single state, as nodes may have new
 		// advertised addresses.
@@ -228,6 +307,12 @@ func (s *SubSwapper) updateBackupFile(closedChans ...wire.OutPoint) error {
 		return fmt.Errorf("unable to pack multi backup: %v", err)
 	}
 
+	// Push the new backup to our sinks before we touch the on-disk
+	// backup, so they are updated even if writing to disk fails.
+	if err := s.pushToSinks(newMulti); err != nil {
+		return err
+	}
+
 	// Finally, we'll swap out the old backup for this new one in a single
 	// atomic step, combining the file already on-disk with this set of new
 	// channels.
@@ -239,6 +324,51 @@ func (s *SubSwapper) updateBackupFile(closedChans ...wire.OutPoint) error {
 	return nil
 }
 
+// pushToSinks queues the given multi backup for upload to our sinks. The
+// commitment state of the channels is stripped from the uploaded backup, as
+// we can't make sure the sinks always hold the latest one. If this leaves the
+// backup unchanged from the one last pushed, it isn't pushed again.
+func (s *SubSwapper) pushToSinks(multi Multi) error {
+	if len(s.sinks) == 0 {
+		return nil
+	}
+
+	remoteMulti := multi.WithoutCommitState()
+
+	var plaintext bytes.Buffer
+	if err := remoteMulti.Serialize(&plaintext); err != nil {
+		return fmt.Errorf("unable to serialize multi backup: %v", err)
+	}
+	if bytes.Equal(plaintext.Bytes(), s.remoteBackup) {
+		return nil
+	}
+
+	var b bytes.Buffer
+	if err := remoteMulti.PackToWriter(&b, s.keyRing); err != nil {
+		return fmt.Errorf("unable to pack multi backup: %v", err)
+	}
+
+	newBackup := PackedMulti(b.Bytes())
+	for _, sink := range s.sinks {
+		sink.queue(newBackup)
+	}
+
+	s.remoteBackup = plaintext.Bytes()
+
+	return nil
+}
+
+// SinkStatus returns the upload status of each of the sinks the backups are
+// pushed to.
+func (s *SubSwapper) SinkStatus() []SinkStatus {
+	statuses := make([]SinkStatus, 0, len(s.sinks))
+	for _, sink := range s.sinks {
+		statuses = append(statuses, sink.Status())
+	}
+
+	return statuses
+}
+
 // backupFileUpdater is the primary goroutine of the SubSwapper which is
 // responsible for listening for changes to the channel, and updating the
 // persistent multi backup state with a new packed multi of the latest channel
@@ -265,9 +395,22 @@ func (s *SubSwapper) backupUpdater() {
 				log.Debugf("Adding channel %v to backup state",
 					newChan.FundingOutpoint)
 
-				s.backupState[newChan.FundingOutpoint] = NewSingle(
+				chanPoint := newChan.FundingOutpoint
+				backup := NewSingle(
 					newChan.OpenChannel, newChan.Addrs,
+					s.singleOpts...,
 				)
+
+				// The channel may have advanced to a new
+				// commitment since the event was created, in
+				// which case we must not replace the backup of
+				// that commitment with a revoked one.
+				old, ok := s.backupState[chanPoint]
+				if ok && newerCommitState(old, backup) {
+					backup.CommitState = old.CommitState
+				}
+
+				s.backupState[chanPoint] = backup
 			}
 
 			// For all closed channels, we'll remove the prior
@@ -299,6 +442,49 @@ func (s *SubSwapper) backupUpdater() {
 					err)
 			}
 
+		// The local commitment of a channel has advanced. We'll
+		// replace the backup of the channel, and write all commitment
+		// updates that queued up in the meantime to disk at once.
+		case update := <-s.commitUpdates:
+			updates := []*commitUpdate{update}
+			for more := true; more; {
+				select {
+				case update := <-s.commitUpdates:
+					updates = append(updates, update)
+				default:
+					more = false
+				}
+			}
+
+			for _, update := range updates {
+				chanPoint := update.backup.FundingOutpoint
+
+				// A channel that has been closed in the
+				// meantime must not be added back.
+				oldBackup, ok := s.backupState[chanPoint]
+				if !ok || newerCommitState(
+					oldBackup, update.backup,
+				) {
+
+					continue
+				}
+
+				s.backupState[chanPoint] = update.backup
+			}
+
+			log.Debugf("Updating on-disk multi SCB backup with "+
+				"%v commitment updates", len(updates))
+
+			err := s.updateBackupFile()
+			if err != nil {
+				log.Errorf("unable to update backup file: %v",
+					err)
+			}
+
+			for _, update := range updates {
+				update.errChan <- err
+			}
+
 		// TODO(roasbeef): refresh periodically on a time basis due to
 		// possible addr changes from node
 
@@ -308,3 +494,14 @@ func (s *SubSwapper) backupUpdater() {
 		}
 	}
 }
+
+// newerCommitState returns true if both backups carry a commitment state, and
+// the one carried by the first backup is newer than the one carried by the
+// second backup.
+func newerCommitState(a, b Single) bool {
+	if a.CommitState == nil || b.CommitState == nil {
+		return false
+	}
+
+	return a.CommitState.CommitHeight > b.CommitState.CommitHeight
+}
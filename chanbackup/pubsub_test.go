@@ -86,7 +86,7 @@ func TestNewSubSwapperSubscribeFail(t *testing.T) {
 		fail: true,
 	}
 
-	_, err := NewSubSwapper(nil, &chanNotifier, keyRing, &swapper)
+	_, err := NewSubSwapper(nil, &chanNotifier, keyRing, &swapper, nil)
 	if err == nil {
 		t.Fatalf("expected fail due to lack of subscription")
 	}
@@ -156,7 +156,9 @@ func TestSubSwapperIdempotentStartStop(t *testing.T) {
 	var chanNotifier mockChannelNotifier
 
 	swapper := newMockSwapper(keyRing)
-	subSwapper, err := NewSubSwapper(nil, &chanNotifier, keyRing, swapper)
+	subSwapper, err := NewSubSwapper(
+		nil, &chanNotifier, keyRing, swapper, nil,
+	)
 	if err != nil {
 		t.Fatalf("unable to init subSwapper: %v", err)
 	}
@@ -224,7 +226,7 @@ func TestSubSwapperUpdater(t *testing.T) {
 	// With our channel set created, we'll make a fresh sub swapper
 	// instance to begin our test.
 	subSwapper, err := NewSubSwapper(
-		initialChanSet, chanNotifier, keyRing, swapper,
+		initialChanSet, chanNotifier, keyRing, swapper, nil,
 	)
 	if err != nil {
 		t.Fatalf("unable to make swapper: %v", err)
@@ -282,3 +284,95 @@ func TestSubSwapperUpdater(t *testing.T) {
 	// sub-swapper switches the new set with the old.
 	assertExpectedBackupSwap(t, swapper, subSwapper, keyRing, backupSet)
 }
+
+// TestSubSwapperCommitState tests that the SubSwapper synchronously writes
+// the commitment updates of a channel to disk, while never pushing the
+// commitment state to its sinks.
+func TestSubSwapperCommitState(t *testing.T) {
+	t.Parallel()
+
+	keyRing := &mockKeyRing{}
+	chanNotifier := newMockChannelNotifier()
+	swapper := newMockSwapper(keyRing)
+	sink := newMockSink(0)
+
+	channel, err := genRandomOpenChannelShell()
+	if err != nil {
+		t.Fatalf("unable to make test chan: %v", err)
+	}
+	genCommitment(channel, 1)
+	channel.LocalCommitment.CommitHeight = 1
+
+	opts := []SingleOption{WithCommitState()}
+	oldBackup := NewSingle(channel, nil, opts...)
+
+	subSwapper, err := NewSubSwapper(
+		[]Single{oldBackup}, chanNotifier, keyRing, swapper, opts, sink,
+	)
+	if err != nil {
+		t.Fatalf("unable to make swapper: %v", err)
+	}
+	if err := subSwapper.Start(); err != nil {
+		t.Fatalf("unable to start sub swapper: %v", err)
+	}
+	defer subSwapper.Stop()
+
+	assertCommitHeight := func(backup PackedMulti, height uint64) {
+		t.Helper()
+
+		multi, err := backup.Unpack(keyRing)
+		if err != nil {
+			t.Fatalf("unable to unpack multi: %v", err)
+		}
+		if len(multi.StaticBackups) != 1 {
+			t.Fatalf("expected 1 backup, got %v",
+				len(multi.StaticBackups))
+		}
+
+		commitState := multi.StaticBackups[0].CommitState
+		switch {
+		case height == 0 && commitState != nil:
+			t.Fatalf("expected no commitment state")
+
+		case height != 0 && commitState == nil:
+			t.Fatalf("expected commitment state")
+
+		case height != 0 && commitState.CommitHeight != height:
+			t.Fatalf("expected commitment height %v, got %v",
+				height, commitState.CommitHeight)
+		}
+	}
+
+	// The initial backup written to disk should carry the commitment
+	// state, while the one pushed to the sink shouldn't.
+	assertCommitHeight(<-swapper.swaps, 1)
+	assertCommitHeight(assertSinkUpload(t, sink), 0)
+
+	// Once the channel advances, the new backup must be written to disk
+	// by the time the update returns.
+	channel.LocalCommitment.CommitHeight = 2
+	newBackup := NewSingle(channel, nil, opts...)
+	if err := subSwapper.UpdateCommitState(newBackup); err != nil {
+		t.Fatalf("unable to update commit state: %v", err)
+	}
+	select {
+	case backup := <-swapper.swaps:
+		assertCommitHeight(backup, 2)
+	default:
+		t.Fatalf("backup wasn't swapped")
+	}
+
+	// An outdated commitment state must never replace a newer one.
+	if err := subSwapper.UpdateCommitState(oldBackup); err != nil {
+		t.Fatalf("unable to update commit state: %v", err)
+	}
+	assertCommitHeight(<-swapper.swaps, 2)
+
+	// As nothing but the commitment state changed, the sink shouldn't
+	// have received another backup.
+	select {
+	case <-sink.uploads:
+		t.Fatalf("commitment update was pushed to sink")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"math"
 	"net"
 
 	"github.com/brronsuite/broln/channeldb"
@@ -47,8 +48,132 @@ const (
 	// commitment and HTLC outputs that pay directly to the channel
 	// initiator.
 	ScriptEnforcedLeaseVersion = 4
+
+	// CommitStateVersion is a version that explicitly carries the type of
+	// the channel, and optionally the latest local commitment state of the
+	// channel. The commitment state allows a recovering node to force close
+	// the channel on its own, rather than relying on the remote party to
+	// do so through the data loss protection protocol.
+	CommitStateVersion = 5
 )
 
+// maxCommitStateHtlcs is the maximum number of HTLCs on the local commitment
+// for which we still include the commitment state in a backup. A single
+// backup must fit within 64 KiB, so the commitment state of channels with more
+// HTLCs is left out.
+const maxCommitStateHtlcs = 300
+
+// HTLC is a static description of an HTLC that is present on the local
+// commitment transaction stored within a backup.
+type HTLC struct {
+	// Incoming is true if the HTLC was offered to us, and false if we
+	// offered it to the remote party.
+	Incoming bool
+
+	// Amt is the amount of the HTLC.
+	Amt lnwire.MilliBronees
+
+	// RHash is the payment hash of the HTLC.
+	RHash [32]byte
+
+	// RefundTimeout is the absolute timeout of the HTLC.
+	RefundTimeout uint32
+
+	// OutputIndex is the index of the output of the HTLC on the commitment
+	// transaction, or -1 if the HTLC is dust.
+	OutputIndex int32
+
+	// HtlcIndex is the index of the HTLC within the update log of the
+	// party that offered it.
+	HtlcIndex uint64
+
+	// Signature is the remote party's signature for the second-level
+	// transaction that spends the output of the HTLC.
+	Signature []byte
+}
+
+// CommitState is the latest local commitment state of a channel at the time a
+// backup was created. It allows a recovering node to broadcast its own
+// commitment transaction.
+//
+// NOTE: Once the channel advances to a new state, the commitment transaction
+// is revoked, and broadcasting it allows the remote party to sweep all funds
+// of the channel. It is therefore only safe to use the commitment state of
+// the most recent backup.
+type CommitState struct {
+	// CommitHeight is the height of the local commitment.
+	CommitHeight uint64
+
+	// CommitTx is the unsigned local commitment transaction.
+	CommitTx *wire.MsgTx
+
+	// CommitSig is the remote party's signature for the commitment
+	// transaction.
+	CommitSig []byte
+
+	// Htlcs are the HTLCs present on the commitment transaction.
+	Htlcs []HTLC
+}
+
+// newCommitState creates the commitment state of a backup from the latest
+// local commitment of the given channel. It returns nil if the commitment
+// isn't known or carries too many HTLCs to be included in a backup.
+func newCommitState(channel *channeldb.OpenChannel) *CommitState {
+	localCommit := channel.LocalCommitment
+	if localCommit.CommitTx == nil {
+		return nil
+	}
+
+	if len(localCommit.Htlcs) > maxCommitStateHtlcs {
+		log.Warnf("Not including commitment state of ChannelPoint(%v) "+
+			"in backup, %v HTLCs exceed limit of %v",
+			channel.FundingOutpoint, len(localCommit.Htlcs),
+			maxCommitStateHtlcs)
+
+		return nil
+	}
+
+	htlcs := make([]HTLC, 0, len(localCommit.Htlcs))
+	for _, htlc := range localCommit.Htlcs {
+		htlcs = append(htlcs, HTLC{
+			Incoming:      htlc.Incoming,
+			Amt:           htlc.Amt,
+			RHash:         htlc.RHash,
+			RefundTimeout: htlc.RefundTimeout,
+			OutputIndex:   htlc.OutputIndex,
+			HtlcIndex:     htlc.HtlcIndex,
+			Signature:     htlc.Signature,
+		})
+	}
+
+	return &CommitState{
+		CommitHeight: localCommit.CommitHeight,
+		CommitTx:     localCommit.CommitTx.Copy(),
+		CommitSig:    localCommit.CommitSig,
+		Htlcs:        htlcs,
+	}
+}
+
+// SingleOption is a functional option that modifies the static channel backup
+// created by NewSingle.
+type SingleOption func(*singleOptions)
+
+// singleOptions holds the options applied when creating a static channel
+// backup.
+type singleOptions struct {
+	// commitState indicates whether the latest local commitment state is
+	// included in the backup.
+	commitState bool
+}
+
+// WithCommitState includes the latest local commitment state of the channel
+// in the backup, which uses the CommitStateVersion.
+func WithCommitState() SingleOption {
+	return func(o *singleOptions) {
+		o.commitState = true
+	}
+}
+
 // Single is a static description of an existing channel that can be used for
 // the purposes of backing up. The fields in this struct allow a node to
 // recover the settled funds within a channel in the case of partial or
@@ -132,14 +257,36 @@ type Single struct {
 	// NOTE: This field will only be present for the following versions:
 	//
 	// - ScriptEnforcedLeaseVersion
+	// - CommitStateVersion
 	LeaseExpiry uint32
+
+	// ChanType is the type of the channel. All prior versions imply the
+	// type of the channel instead.
+	//
+	// NOTE: This field will only be present for the following versions:
+	//
+	// - CommitStateVersion
+	ChanType channeldb.ChannelType
+
+	// CommitState is the latest local commitment state of the channel. It
+	// is nil if the commitment state wasn't included in the backup.
+	//
+	// NOTE: This field will only be present for the following versions:
+	//
+	// - CommitStateVersion
+	CommitState *CommitState
 }
 
 // NewSingle creates a new static channel backup based on an existing open
 // channel. We also pass in the set of addresses that we used in the past to
 // connect to the channel peer.
-func NewSingle(channel *channeldb.OpenChannel,
-	nodeAddrs []net.Addr) Single {
+func NewSingle(channel *channeldb.OpenChannel, nodeAddrs []net.Addr,
+	opts ...SingleOption) Single {
+
+	var options singleOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
 
 	var shaChainRootDesc keychain.KeyDescriptor
 
@@ -211,6 +358,15 @@ func NewSingle(channel *channeldb.OpenChannel,
 		single.Version = DefaultSingleVersion
 	}
 
+	// If the commitment state should be included, then we'll use the
+	// version that carries it along with the channel type, as the type
+	// can't be implied from the version anymore.
+	if options.commitState {
+		single.Version = CommitStateVersion
+		single.ChanType = channel.ChanType
+		single.CommitState = newCommitState(channel)
+	}
+
 	return single
 }
 
@@ -225,6 +381,7 @@ func (s *Single) Serialize(w io.Writer) error {
 	case AnchorsCommitVersion:
 	case AnchorsZeroFeeHtlcTxCommitVersion:
 	case ScriptEnforcedLeaseVersion:
+	case CommitStateVersion:
 	default:
 		return fmt.Errorf("unable to serialize w/ unknown "+
 			"version: %v", s.Version)
@@ -292,6 +449,25 @@ func (s *Single) Serialize(w io.Writer) error {
 			return err
 		}
 	}
+	if s.Version == CommitStateVersion {
+		err := lnwire.WriteElements(
+			&singleBytes, uint64(s.ChanType), s.LeaseExpiry,
+		)
+		if err != nil {
+			return err
+		}
+
+		err = serializeCommitState(&singleBytes, s.CommitState)
+		if err != nil {
+			return err
+		}
+	}
+
+	// The length of the serialized SCB must fit into its length prefix.
+	if singleBytes.Len() > math.MaxUint16 {
+		return fmt.Errorf("serialized backup of %v bytes exceeds "+
+			"maximum size", singleBytes.Len())
+	}
 
 	// TODO(yy): remove the type assertion when we finished refactoring db
 	// into using write buffer.
@@ -399,6 +575,7 @@ func (s *Single) Deserialize(r io.Reader) error {
 	case AnchorsCommitVersion:
 	case AnchorsZeroFeeHtlcTxCommitVersion:
 	case ScriptEnforcedLeaseVersion:
+	case CommitStateVersion:
 	default:
 		return fmt.Errorf("unable to de-serialize w/ unknown "+
 			"version: %v", s.Version)
@@ -502,10 +679,125 @@ func (s *Single) Deserialize(r io.Reader) error {
 			return err
 		}
 	}
+	if s.Version == CommitStateVersion {
+		var chanType uint64
+		err := lnwire.ReadElements(r, &chanType, &s.LeaseExpiry)
+		if err != nil {
+			return err
+		}
+		s.ChanType = channeldb.ChannelType(chanType)
+
+		s.CommitState, err = deserializeCommitState(r)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// serializeCommitState writes the optional commitment state of a backup to the
+// passed buffer, prefixed by a flag that signals whether it is present.
+func serializeCommitState(w *bytes.Buffer, c *CommitState) error {
+	if err := lnwire.WriteElement(w, c != nil); err != nil {
+		return err
+	}
+	if c == nil {
+		return nil
+	}
+
+	if err := lnwire.WriteElement(w, c.CommitHeight); err != nil {
+		return err
+	}
+	if err := c.CommitTx.Serialize(w); err != nil {
+		return err
+	}
+	if err := wire.WriteVarBytes(w, 0, c.CommitSig); err != nil {
+		return err
+	}
+
+	numHtlcs := uint16(len(c.Htlcs))
+	if err := lnwire.WriteElement(w, numHtlcs); err != nil {
+		return err
+	}
+	for _, htlc := range c.Htlcs {
+		err := lnwire.WriteElements(
+			w, htlc.Incoming, htlc.Amt, htlc.RHash[:],
+			htlc.RefundTimeout, uint32(htlc.OutputIndex),
+			htlc.HtlcIndex,
+		)
+		if err != nil {
+			return err
+		}
+
+		if err := wire.WriteVarBytes(w, 0, htlc.Signature); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
 
+// deserializeCommitState reads the optional commitment state of a backup from
+// the passed io.Reader. It returns nil if the commitment state isn't present.
+func deserializeCommitState(r io.Reader) (*CommitState, error) {
+	var hasCommitState bool
+	if err := lnwire.ReadElement(r, &hasCommitState); err != nil {
+		return nil, err
+	}
+	if !hasCommitState {
+		return nil, nil
+	}
+
+	var c CommitState
+	if err := lnwire.ReadElement(r, &c.CommitHeight); err != nil {
+		return nil, err
+	}
+
+	c.CommitTx = &wire.MsgTx{}
+	if err := c.CommitTx.Deserialize(r); err != nil {
+		return nil, err
+	}
+
+	var err error
+	c.CommitSig, err = wire.ReadVarBytes(
+		r, 0, math.MaxUint16, "commit sig",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var numHtlcs uint16
+	if err := lnwire.ReadElement(r, &numHtlcs); err != nil {
+		return nil, err
+	}
+	for i := uint16(0); i < numHtlcs; i++ {
+		var (
+			htlc        HTLC
+			outputIndex uint32
+		)
+		err := lnwire.ReadElements(
+			r, &htlc.Incoming, &htlc.Amt, htlc.RHash[:],
+			&htlc.RefundTimeout, &outputIndex, &htlc.HtlcIndex,
+		)
+		if err != nil {
+			return nil, err
+		}
+		htlc.OutputIndex = int32(outputIndex)
+
+		htlc.Signature, err = wire.ReadVarBytes(
+			r, 0, math.MaxUint16, "htlc sig",
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		c.Htlcs = append(c.Htlcs, htlc)
+	}
+
+	return &c, nil
+}
+
 // UnpackFromReader is similar to Deserialize method, but it expects the passed
 // io.Reader to contain an encrypt SCB. Refer to the SerializeAndEncrypt method
 // for details w.r.t the encryption scheme used. If we're unable to decrypt the
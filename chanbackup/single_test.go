@@ -83,6 +83,12 @@ func assertSingleEqual(t *testing.T, a, b Single) {
 			spew.Sdump(b.ShaChainRootDesc))
 	}
 
+	if a.ChanType != b.ChanType {
+		t.Fatalf("chan type doesn't match: %v vs %v", a.ChanType,
+			b.ChanType)
+	}
+	assertCommitStateEqual(t, a.CommitState, b.CommitState)
+
 	if len(a.Addresses) != len(b.Addresses) {
 		t.Fatalf("expected %v addrs got %v", len(a.Addresses),
 			len(b.Addresses))
@@ -95,6 +101,35 @@ func assertSingleEqual(t *testing.T, a, b Single) {
 	}
 }
 
+func assertCommitStateEqual(t *testing.T, a, b *CommitState) {
+	t.Helper()
+
+	if a == nil || b == nil {
+		if a != b {
+			t.Fatalf("commit state doesn't match: %v vs %v",
+				spew.Sdump(a), spew.Sdump(b))
+		}
+		return
+	}
+
+	if a.CommitHeight != b.CommitHeight {
+		t.Fatalf("commit height doesn't match: %v vs %v",
+			a.CommitHeight, b.CommitHeight)
+	}
+	if a.CommitTx.TxHash() != b.CommitTx.TxHash() {
+		t.Fatalf("commit tx doesn't match: %v vs %v",
+			spew.Sdump(a.CommitTx), spew.Sdump(b.CommitTx))
+	}
+	if !bytes.Equal(a.CommitSig, b.CommitSig) {
+		t.Fatalf("commit sig doesn't match: %x vs %x", a.CommitSig,
+			b.CommitSig)
+	}
+	if !reflect.DeepEqual(a.Htlcs, b.Htlcs) {
+		t.Fatalf("htlcs don't match: %v vs %v", spew.Sdump(a.Htlcs),
+			spew.Sdump(b.Htlcs))
+	}
+}
+
 func genRandomOpenChannelShell() (*channeldb.OpenChannel, error) {
 	var testPriv [32]byte
 	if _, err := rand.Read(testPriv[:]); err != nil {
@@ -248,6 +283,13 @@ func TestSinglePackUnpack(t *testing.T) {
 			valid:   true,
 		},
 
+		// The commit state version without a commitment state should
+		// pack/unpack with no problem.
+		{
+			version: CommitStateVersion,
+			valid:   true,
+		},
+
 		// A non-default version, atm this should result in a failure.
 		{
 			version: 99,
@@ -464,4 +506,115 @@ func TestSingleUnconfirmedChannel(t *testing.T) {
 	}
 }
 
+// genCommitment populates the local commitment of the given channel with a
+// commitment transaction carrying the given number of HTLCs.
+func genCommitment(channel *channeldb.OpenChannel, numHtlcs int) {
+	commitTx := wire.NewMsgTx(2)
+	commitTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: channel.FundingOutpoint,
+		Sequence:         rand.Uint32(),
+	})
+	commitTx.AddTxOut(&wire.TxOut{
+		Value:    rand.Int63(),
+		PkScript: bytes.Repeat([]byte{0x01}, 34),
+	})
+
+	htlcs := make([]channeldb.HTLC, 0, numHtlcs)
+	for i := 0; i < numHtlcs; i++ {
+		htlc := channeldb.HTLC{
+			Signature:     bytes.Repeat([]byte{byte(i)}, 71),
+			Incoming:      i%2 == 0,
+			Amt:           lnwire.MilliBronees(rand.Int63()),
+			RefundTimeout: rand.Uint32(),
+			OutputIndex:   int32(i + 1),
+			HtlcIndex:     uint64(i),
+		}
+		_, _ = rand.Read(htlc.RHash[:])
+		htlcs = append(htlcs, htlc)
+
+		commitTx.AddTxOut(&wire.TxOut{
+			Value:    int64(htlc.Amt.ToBroneess()),
+			PkScript: bytes.Repeat([]byte{0x02}, 34),
+		})
+	}
+
+	channel.LocalCommitment = channeldb.ChannelCommitment{
+		CommitHeight: rand.Uint64(),
+		CommitTx:     commitTx,
+		CommitSig:    bytes.Repeat([]byte{0x03}, 72),
+		Htlcs:        htlcs,
+	}
+}
+
+// TestSingleCommitState tests that the latest local commitment state of a
+// channel is carried within a backup created with the commit state option.
+func TestSingleCommitState(t *testing.T) {
+	t.Parallel()
+
+	keyRing := &mockKeyRing{}
+
+	channel, err := genRandomOpenChannelShell()
+	if err != nil {
+		t.Fatalf("unable to gen open channel: %v", err)
+	}
+	genCommitment(channel, 3)
+
+	// Without the option, the backup should use the version implied by
+	// the channel type and not carry the commitment state.
+	single := NewSingle(channel, []net.Addr{addr1})
+	if single.Version == CommitStateVersion || single.CommitState != nil {
+		t.Fatalf("commit state included without option")
+	}
+
+	// With the option, the backup should carry the channel type and the
+	// commitment state.
+	single = NewSingle(channel, []net.Addr{addr1}, WithCommitState())
+	single.RemoteNodePub.Curve = nil
+	if single.Version != CommitStateVersion {
+		t.Fatalf("expected version %v, got %v", CommitStateVersion,
+			single.Version)
+	}
+	if single.ChanType != channel.ChanType {
+		t.Fatalf("expected chan type %v, got %v", channel.ChanType,
+			single.ChanType)
+	}
+	if single.CommitState == nil || len(single.CommitState.Htlcs) != 3 {
+		t.Fatalf("expected commit state with 3 htlcs, got %v",
+			spew.Sdump(single.CommitState))
+	}
+
+	// The commitment state should survive a pack/unpack round trip.
+	var b bytes.Buffer
+	if err := single.PackToWriter(&b, keyRing); err != nil {
+		t.Fatalf("unable to pack single: %v", err)
+	}
+	var unpackedSingle Single
+	if err := unpackedSingle.UnpackFromReader(&b, keyRing); err != nil {
+		t.Fatalf("unable to unpack single: %v", err)
+	}
+	unpackedSingle.RemoteNodePub.Curve = nil
+
+	assertSingleEqual(t, single, unpackedSingle)
+
+	// A commitment with too many HTLCs to fit into a backup should be
+	// left out, while the backup still carries the channel type.
+	genCommitment(channel, maxCommitStateHtlcs+1)
+	single = NewSingle(channel, nil, WithCommitState())
+	if single.Version != CommitStateVersion || single.CommitState != nil {
+		t.Fatalf("expected backup without commit state, got %v",
+			spew.Sdump(single.CommitState))
+	}
+
+	// The largest commitment state we include must still fit into a
+	// backup.
+	genCommitment(channel, maxCommitStateHtlcs)
+	single = NewSingle(
+		channel, []net.Addr{addr1, addr2}, WithCommitState(),
+	)
+	b.Reset()
+	if err := single.PackToWriter(&b, keyRing); err != nil {
+		t.Fatalf("unable to pack single: %v", err)
+	}
+}
+
 // TODO(roasbsef): fuzz parsing
@@ -0,0 +1,208 @@
+package chanbackup
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// sinkUploadTimeout is the time we allow a single upload of a backup
+	// to a sink to take.
+	sinkUploadTimeout = time.Minute
+
+	// defaultSinkMinBackoff is the time we wait before retrying a failed
+	// upload for the first time.
+	defaultSinkMinBackoff = 5 * time.Second
+
+	// defaultSinkMaxBackoff is the maximum time we wait between retries of
+	// a failed upload.
+	defaultSinkMaxBackoff = 10 * time.Minute
+)
+
+// Sink is a remote destination that every updated multi-channel backup is
+// pushed to, in addition to the main backup location of the SubSwapper. As
+// the backups are encrypted, a sink doesn't need to be trusted with anything
+// but availability.
+type Sink interface {
+	// Name returns a human readable name that identifies the sink.
+	Name() string
+
+	// Upload pushes the packed multi-channel backup to the sink, replacing
+	// the backup uploaded before.
+	Upload(ctx context.Context, backup PackedMulti) error
+}
+
+// SinkStatus describes the state of the uploads to a sink.
+type SinkStatus struct {
+	// Name is the name of the sink.
+	Name string
+
+	// LastSuccess is the time of the last successful upload. It is the
+	// zero time if no upload succeeded yet.
+	LastSuccess time.Time
+
+	// LastAttempt is the time of the last upload attempt. It is the zero
+	// time if no upload was attempted yet.
+	LastAttempt time.Time
+
+	// LastErr is the error of the last upload attempt, or nil if it
+	// succeeded.
+	LastErr error
+
+	// Pending is true if the latest backup hasn't been uploaded to the sink
+	// yet.
+	Pending bool
+}
+
+// sinkUploader pushes backups to a sink in the background. Only the latest
+// backup is ever uploaded: a backup that is queued while an older one is
+// still being uploaded or retried replaces the older one. Failed uploads are
+// retried with an exponential backoff until they succeed.
+type sinkUploader struct {
+	sink Sink
+
+	// timeout is the time we allow a single upload to take.
+	timeout time.Duration
+
+	// minBackoff and maxBackoff bound the time we wait between retries of
+	// a failed upload.
+	minBackoff time.Duration
+	maxBackoff time.Duration
+
+	// newBackup is signalled whenever a new backup is queued.
+	newBackup chan struct{}
+
+	mu sync.Mutex
+
+	// backup is the latest backup queued for upload.
+	backup PackedMulti
+
+	// seq is increased every time a new backup is queued, which allows us
+	// to tell whether the backup we uploaded is still the latest one.
+	seq uint64
+
+	status SinkStatus
+}
+
+// newSinkUploader creates a new uploader for the given sink.
+func newSinkUploader(sink Sink) *sinkUploader {
+	return &sinkUploader{
+		sink:       sink,
+		timeout:    sinkUploadTimeout,
+		minBackoff: defaultSinkMinBackoff,
+		maxBackoff: defaultSinkMaxBackoff,
+		newBackup:  make(chan struct{}, 1),
+		status: SinkStatus{
+			Name: sink.Name(),
+		},
+	}
+}
+
+// queue queues the given backup for upload, replacing any backup that wasn't
+// uploaded yet.
+func (u *sinkUploader) queue(backup PackedMulti) {
+	u.mu.Lock()
+	u.backup = backup
+	u.seq++
+	u.status.Pending = true
+	u.mu.Unlock()
+
+	select {
+	case u.newBackup <- struct{}{}:
+	default:
+	}
+}
+
+// Status returns the current upload status of the sink.
+func (u *sinkUploader) Status() SinkStatus {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	return u.status
+}
+
+// uploader is the goroutine that uploads queued backups to the sink until the
+// quit channel is closed.
+//
+// NOTE: This MUST be run as a goroutine.
+func (u *sinkUploader) uploader(quit <-chan struct{}) {
+	backoff := u.minBackoff
+	for {
+		select {
+		case <-u.newBackup:
+		case <-quit:
+			return
+		}
+
+		if u.upload(quit) {
+			backoff = u.minBackoff
+			continue
+		}
+
+		log.Debugf("Retrying upload of channel backup to sink %v in %v",
+			u.sink.Name(), backoff)
+
+		// Retry the upload once the backoff expired, unless a newer
+		// backup arrives in the meantime, which is uploaded right away
+		// instead.
+		select {
+		case <-time.After(backoff):
+		case <-u.newBackup:
+		case <-quit:
+			return
+		}
+
+		select {
+		case u.newBackup <- struct{}{}:
+		default:
+		}
+
+		backoff *= 2
+		if backoff > u.maxBackoff {
+			backoff = u.maxBackoff
+		}
+	}
+}
+
+// upload uploads the latest queued backup to the sink and records the result.
+// It returns true if the upload succeeded.
+func (u *sinkUploader) upload(quit <-chan struct{}) bool {
+	u.mu.Lock()
+	backup, seq := u.backup, u.seq
+	u.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), u.timeout)
+	defer cancel()
+
+	// Abort the upload if we're shutting down.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-quit:
+			cancel()
+		case <-done:
+		}
+	}()
+
+	err := u.sink.Upload(ctx, backup)
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.status.LastAttempt = time.Now()
+	u.status.LastErr = err
+	if err != nil {
+		log.Errorf("Unable to upload channel backup to sink %v: %v",
+			u.sink.Name(), err)
+		return false
+	}
+
+	log.Infof("Uploaded channel backup to sink %v", u.sink.Name())
+
+	u.status.LastSuccess = u.status.LastAttempt
+	u.status.Pending = u.seq != seq
+
+	return true
+}
@@ -0,0 +1,51 @@
+package chanbackup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+)
+
+// HookSink is a Sink that hands the backup to an executable, which can then
+// store it wherever it likes. The backup is written to the standard input of
+// the executable, and a non-zero exit code signals a failed upload.
+type HookSink struct {
+	// path is the path of the executable.
+	path string
+}
+
+// A compile-time constraint to ensure HookSink implements Sink.
+var _ Sink = (*HookSink)(nil)
+
+// NewHookSink creates a new sink that hands the backup to the executable at
+// the given path.
+func NewHookSink(path string) *HookSink {
+	return &HookSink{
+		path: path,
+	}
+}
+
+// Name returns a human readable name that identifies the sink.
+//
+// NOTE: Part of the Sink interface.
+func (h *HookSink) Name() string {
+	return "hook:" + h.path
+}
+
+// Upload runs the executable with the backup as its standard input. The
+// executable is killed if the context is canceled before it exits.
+//
+// NOTE: Part of the Sink interface.
+func (h *HookSink) Upload(ctx context.Context, backup PackedMulti) error {
+	cmd := exec.CommandContext(ctx, h.path)
+	cmd.Stdin = bytes.NewReader(backup)
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("backup hook failed: %v: %s", err,
+			bytes.TrimSpace(output))
+	}
+
+	return nil
+}
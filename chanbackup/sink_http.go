@@ -0,0 +1,279 @@
+package chanbackup
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxErrorBodySize is the maximum number of bytes of an error response body
+// that we include in the returned error.
+const maxErrorBodySize = 512
+
+// putBackup sends the given PUT request and checks that the server accepted
+// the upload.
+func putBackup(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	return fmt.Errorf("upload rejected with status %v: %s", resp.Status,
+		bytes.TrimSpace(body))
+}
+
+// WebDAVSink is a Sink that uploads the backup to a WebDAV server.
+type WebDAVSink struct {
+	// url is the URL of the file the backup is stored in.
+	url string
+
+	// user and password are used for basic authentication if user is
+	// set.
+	user     string
+	password string
+
+	client *http.Client
+}
+
+// A compile-time constraint to ensure WebDAVSink implements Sink.
+var _ Sink = (*WebDAVSink)(nil)
+
+// NewWebDAVSink creates a new sink that uploads the backup to the file at the
+// given WebDAV URL, authenticating with the given user and password if the
+// user is set.
+func NewWebDAVSink(fileURL, user, password string) *WebDAVSink {
+	return &WebDAVSink{
+		url:      fileURL,
+		user:     user,
+		password: password,
+		client:   &http.Client{},
+	}
+}
+
+// Name returns a human readable name that identifies the sink.
+//
+// NOTE: Part of the Sink interface.
+func (w *WebDAVSink) Name() string {
+	return "webdav:" + w.url
+}
+
+// Upload stores the backup in the file at the sink's URL, replacing the
+// previous backup.
+//
+// NOTE: Part of the Sink interface.
+func (w *WebDAVSink) Upload(ctx context.Context, backup PackedMulti) error {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPut, w.url, bytes.NewReader(backup),
+	)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	if w.user != "" {
+		req.SetBasicAuth(w.user, w.password)
+	}
+
+	return putBackup(w.client, req)
+}
+
+// S3SinkConfig holds the parameters of an S3-compatible sink.
+type S3SinkConfig struct {
+	// Endpoint is the URL of the S3-compatible service, for example
+	// https://s3.us-east-1.amazonaws.com.
+	Endpoint string
+
+	// Region is the region of the bucket that is used to sign requests.
+	Region string
+
+	// Bucket is the bucket the backup is stored in.
+	Bucket string
+
+	// Key is the object key the backup is stored under.
+	Key string
+
+	// AccessKeyID and SecretAccessKey are the credentials used to sign
+	// requests.
+	AccessKeyID     string
+	SecretAccessKey string
+}
+
+// S3Sink is a Sink that uploads the backup as an object to an S3-compatible
+// service. Requests are path-style and signed with AWS signature version 4,
+// which is understood by all common S3-compatible services.
+type S3Sink struct {
+	cfg S3SinkConfig
+
+	client *http.Client
+
+	// now returns the current time, used to sign requests.
+	now func() time.Time
+}
+
+// A compile-time constraint to ensure S3Sink implements Sink.
+var _ Sink = (*S3Sink)(nil)
+
+// NewS3Sink creates a new sink that uploads the backup to an S3-compatible
+// service.
+func NewS3Sink(cfg S3SinkConfig) *S3Sink {
+	return &S3Sink{
+		cfg:    cfg,
+		client: &http.Client{},
+		now:    time.Now,
+	}
+}
+
+// Name returns a human readable name that identifies the sink.
+//
+// NOTE: Part of the Sink interface.
+func (s *S3Sink) Name() string {
+	return "s3:" + s.cfg.Bucket + "/" + s.cfg.Key
+}
+
+// Upload stores the backup as the sink's object, replacing the previous
+// backup.
+//
+// NOTE: Part of the Sink interface.
+func (s *S3Sink) Upload(ctx context.Context, backup PackedMulti) error {
+	objectURL := strings.TrimSuffix(s.cfg.Endpoint, "/") + "/" +
+		s3EscapePath(s.cfg.Bucket) + "/" + s3EscapePath(s.cfg.Key)
+
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPut, objectURL, bytes.NewReader(backup),
+	)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	payloadHash := sha256.Sum256(backup)
+	signS3Request(
+		req, hex.EncodeToString(payloadHash[:]), s.now(),
+		s.cfg.Region, s.cfg.AccessKeyID, s.cfg.SecretAccessKey,
+	)
+
+	return putBackup(s.client, req)
+}
+
+// s3EscapePath escapes the given path the way the canonical request of AWS
+// signature version 4 expects it: all bytes but unreserved characters and
+// slashes are percent encoded.
+func s3EscapePath(path string) string {
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z',
+			'0' <= c && c <= '9', c == '-', c == '.', c == '_',
+			c == '~', c == '/':
+
+			b.WriteByte(c)
+
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+
+	return b.String()
+}
+
+// signS3Request signs the given request with AWS signature version 4 for the
+// S3 service. All headers set on the request are signed, in addition to the
+// host, the date and the given hex encoded SHA256 hash of the payload.
+func signS3Request(req *http.Request, payloadHash string, now time.Time,
+	region, accessKeyID, secretAccessKey string) {
+
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+
+	// Assemble the canonical headers, which are the lower cased header
+	// names sorted alphabetically along with their trimmed values.
+	headers := map[string]string{
+		"host": host,
+	}
+	for name, values := range req.Header {
+		for i := range values {
+			values[i] = strings.TrimSpace(values[i])
+		}
+		headers[strings.ToLower(name)] = strings.Join(values, ",")
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalQuery := strings.ReplaceAll(
+		req.URL.Query().Encode(), "+", "%20",
+	)
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	canonicalHash := sha256.Sum256([]byte(canonicalRequest))
+
+	scope := date + "/" + region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hex.EncodeToString(canonicalHash[:]),
+	}, "\n")
+
+	// Derive the signing key from the secret access key, scoped to the
+	// date, region and service.
+	key := hmacSHA256([]byte("AWS4"+secretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 "+
+		"Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKeyID, scope, signedHeaders, signature))
+}
+
+// hmacSHA256 returns the HMAC-SHA256 of the given data under the given key.
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
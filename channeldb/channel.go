@@ -108,6 +108,10 @@ var (
 	// broadcasted when moving the channel to state CoopBroadcasted.
 	coopCloseTxKey = []byte("coop-closing-tx-key")
 
+	// coopCloseDeliveryScriptKey points to the script our funds are paid
+	// out to in the cooperative close of the channel.
+	coopCloseDeliveryScriptKey = []byte("coop-close-delivery-script-key")
+
 	// commitDiffKey stores the current pending commitment state we've
 	// extended to the remote party (if any). Each time we propose a new
 	// state, we store the information necessary to reconstruct this state
@@ -167,6 +171,14 @@ var (
 	// in the state CommitBroadcasted.
 	ErrNoCloseTx = fmt.Errorf("no closing tx found")
 
+	// ErrNoDeliveryScript is returned when no cooperative close delivery
+	// script is found for a channel.
+	ErrNoDeliveryScript = fmt.Errorf("no delivery script found")
+
+	// ErrOnionBlobLength is returned if an HTLC carrying a tlv stream
+	// doesn't have an onion blob of the expected size.
+	ErrOnionBlobLength = fmt.Errorf("invalid onion blob length")
+
 	// ErrNoRestoredChannelMutation is returned when a caller attempts to
 	// mutate a channel that's been recovered.
 	ErrNoRestoredChannelMutation = fmt.Errorf("cannot mutate restored " +
@@ -198,6 +210,14 @@ const (
 	// A tlv type definition used to serialize and deserialize a KeyLocator
 	// from the database.
 	keyLocType tlv.Type = 1
+
+	// A tlv type definition used to serialize and deserialize the
+	// confirmed ShortChannelID for a zero-conf channel.
+	realScidType tlv.Type = 2
+
+	// A tlv type definition used to serialize and deserialize the
+	// blinding point of an HTLC within its tlv stream.
+	htlcBlindingPointType tlv.Type = 0
 )
 
 // indexStatus is an enum-like type that describes what state the
@@ -219,7 +239,7 @@ const (
 // fee negotiation, channel closing, the format of HTLCs, etc. Structure-wise,
 // a ChannelType is a bit field, with each bit denoting a modification from the
 // base channel type of single funder.
-type ChannelType uint8
+type ChannelType uint64
 
 const (
 	// NOTE: iota isn't used here for this enum needs to be stable
@@ -262,6 +282,16 @@ const (
 	// period of time, constraining every output that pays to the channel
 	// initiator with an additional CLTV of the lease maturity.
 	LeaseExpirationBit ChannelType = 1 << 6
+
+	// ZeroConfBit indicates that the channel is a zero-conf channel, which
+	// means that it can be used before its funding transaction confirms.
+	ZeroConfBit ChannelType = 1 << 7
+
+	// ScidAliasChanBit indicates that the channel has negotiated the
+	// option-scid-alias channel type, which means that it is only ever
+	// referred to by an alias ShortChannelID towards the peer and in
+	// invoice route hints.
+	ScidAliasChanBit ChannelType = 1 << 8
 )
 
 // IsSingleFunder returns true if the channel type if one of the known single
@@ -311,6 +341,17 @@ func (c ChannelType) HasLeaseExpiration() bool {
 	return c&LeaseExpirationBit == LeaseExpirationBit
 }
 
+// HasZeroConf returns true if the channel is a zero-conf channel.
+func (c ChannelType) HasZeroConf() bool {
+	return c&ZeroConfBit == ZeroConfBit
+}
+
+// HasScidAliasChan returns true if the scid-alias channel type was
+// negotiated.
+func (c ChannelType) HasScidAliasChan() bool {
+	return c&ScidAliasChanBit == ScidAliasChanBit
+}
+
 // ChannelConstraints represents a set of constraints meant to allow a node to
 // limit their exposure, enact flow control and ensure that all HTLCs are
 // economically relevant. This struct will be mirrored for both sides of the
@@ -612,6 +653,11 @@ type OpenChannel struct {
 	// ShortChannelID encodes the exact location in the chain in which the
 	// channel was initially confirmed. This includes: the block height,
 	// transaction index, and the output within the target transaction.
+	//
+	// NOTE: For zero-conf and scid-alias channels this is the alias that
+	// was assigned to the channel upon creation. The confirmed location
+	// is then stored separately and can be retrieved with
+	// ZeroConfRealScid.
 	ShortChannelID lnwire.ShortChannelID
 
 	// IsPending indicates whether a channel's funding transaction has been
@@ -738,6 +784,11 @@ type OpenChannel struct {
 	// have private key isolation from broln.
 	RevocationKeyLocator keychain.KeyLocator
 
+	// confirmedScid is the confirmed ShortChannelID for a zero-conf or
+	// scid-alias channel. It is only set once the funding transaction has
+	// confirmed.
+	confirmedScid lnwire.ShortChannelID
+
 	// TODO(roasbeef): eww
 	Db *ChannelStateDB
 
@@ -754,6 +805,42 @@ func (c *OpenChannel) ShortChanID() lnwire.ShortChannelID {
 	return c.ShortChannelID
 }
 
+// ZeroConfRealScid returns the real, confirmed ShortChannelID of a zero-conf
+// or scid-alias channel. The zero value is returned if the funding transaction
+// hasn't confirmed yet.
+func (c *OpenChannel) ZeroConfRealScid() lnwire.ShortChannelID {
+	c.RLock()
+	defer c.RUnlock()
+
+	return c.confirmedScid
+}
+
+// ZeroConfConfirmed returns whether the funding transaction of a zero-conf or
+// scid-alias channel has confirmed.
+func (c *OpenChannel) ZeroConfConfirmed() bool {
+	c.RLock()
+	defer c.RUnlock()
+
+	return c.confirmedScid != lnwire.ShortChannelID{}
+}
+
+// IsZeroConf returns whether the option_zeroconf channel type was negotiated.
+func (c *OpenChannel) IsZeroConf() bool {
+	c.RLock()
+	defer c.RUnlock()
+
+	return c.ChanType.HasZeroConf()
+}
+
+// HasScidAlias returns whether the option_scid_alias channel type was
+// negotiated.
+func (c *OpenChannel) HasScidAlias() bool {
+	c.RLock()
+	defer c.RUnlock()
+
+	return c.ChanType.HasScidAliasChan()
+}
+
 // ChanStatus returns the current ChannelStatus of this channel.
 func (c *OpenChannel) ChanStatus() ChannelStatus {
 	c.RLock()
@@ -1033,6 +1120,38 @@ func (c *OpenChannel) MarkAsOpen(openLoc lnwire.ShortChannelID) error {
 	return nil
 }
 
+// MarkRealScid marks the zero-conf or scid-alias channel's confirmed
+// ShortChannelID. The alias stored in ShortChannelID is left untouched, as it
+// remains the identifier of the channel towards the rest of the daemon.
+func (c *OpenChannel) MarkRealScid(realScid lnwire.ShortChannelID) error {
+	c.Lock()
+	defer c.Unlock()
+
+	if err := kvdb.Update(c.Db.backend, func(tx kvdb.RwTx) error {
+		chanBucket, err := fetchChanBucketRw(
+			tx, c.IdentityPub, &c.FundingOutpoint, c.ChainHash,
+		)
+		if err != nil {
+			return err
+		}
+
+		channel, err := fetchOpenChannel(chanBucket, &c.FundingOutpoint)
+		if err != nil {
+			return err
+		}
+
+		channel.confirmedScid = realScid
+
+		return putOpenChannel(chanBucket, channel)
+	}, func() {}); err != nil {
+		return err
+	}
+
+	c.confirmedScid = realScid
+
+	return nil
+}
+
 // MarkDataLoss marks sets the channel status to LocalDataLoss and stores the
 // passed commitPoint for use to retrieve funds in case the remote force closes
 // the channel.
@@ -1276,6 +1395,67 @@ func (c *OpenChannel) BroadcastedCooperative() (*wire.MsgTx, error) {
 	return c.getClosingTx(coopCloseTxKey)
 }
 
+// PutCoopCloseDeliveryScript stores the script our funds are paid out to in
+// the cooperative close of the channel, which allows an RBF cooperative close
+// to be resumed after reconnecting to the remote party.
+func (c *OpenChannel) PutCoopCloseDeliveryScript(
+	script lnwire.DeliveryAddress) error {
+
+	c.Lock()
+	defer c.Unlock()
+
+	var b bytes.Buffer
+	if err := WriteElement(&b, []byte(script)); err != nil {
+		return err
+	}
+
+	return kvdb.Update(c.Db.backend, func(tx kvdb.RwTx) error {
+		chanBucket, err := fetchChanBucketRw(
+			tx, c.IdentityPub, &c.FundingOutpoint, c.ChainHash,
+		)
+		if err != nil {
+			return err
+		}
+
+		return chanBucket.Put(coopCloseDeliveryScriptKey, b.Bytes())
+	}, func() {})
+}
+
+// CoopCloseDeliveryScript returns the delivery script stored with
+// PutCoopCloseDeliveryScript. If not found ErrNoDeliveryScript is returned.
+func (c *OpenChannel) CoopCloseDeliveryScript() (lnwire.DeliveryAddress,
+	error) {
+
+	var script []byte
+
+	err := kvdb.View(c.Db.backend, func(tx kvdb.RTx) error {
+		chanBucket, err := fetchChanBucket(
+			tx, c.IdentityPub, &c.FundingOutpoint, c.ChainHash,
+		)
+		switch err {
+		case nil:
+		case ErrNoChanDBExists, ErrNoActiveChannels, ErrChannelNotFound:
+			return ErrNoDeliveryScript
+		default:
+			return err
+		}
+
+		bs := chanBucket.Get(coopCloseDeliveryScriptKey)
+		if bs == nil {
+			return ErrNoDeliveryScript
+		}
+		r := bytes.NewReader(bs)
+		return ReadElement(r, &script)
+	}, func() {
+		script = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return script, nil
+}
+
 // getClosingTx is a helper method which returns the stored closing transaction
 // for key. The caller should use either the force or coop closing keys.
 func (c *OpenChannel) getClosingTx(key []byte) (*wire.MsgTx, error) {
@@ -1528,9 +1708,12 @@ func syncNewChannel(tx kvdb.RwTx, c *OpenChannel, addrs []net.Addr) error {
 // have acked, but not signed a remote commitment for yet. These need to be
 // persisted to be able to produce a valid commit signature if a restart would
 // occur. This method its to be called when we revoke our prior commitment
-// state.
+// state. The spliceCommitments are our commitments for the same state that
+// spend the funding outputs of the pending splices of the channel, keyed by
+// the txid of their splice transaction.
 func (c *OpenChannel) UpdateCommitment(newCommitment *ChannelCommitment,
-	unsignedAckedUpdates []LogUpdate) error {
+	unsignedAckedUpdates []LogUpdate,
+	spliceCommitments map[chainhash.Hash]*ChannelCommitment) error {
 
 	c.Lock()
 	defer c.Unlock()
@@ -1574,6 +1757,16 @@ func (c *OpenChannel) UpdateCommitment(newCommitment *ChannelCommitment,
 				"revocations: %v", err)
 		}
 
+		// Along with it, we'll write our commitments for the funding
+		// outputs of all pending splices.
+		err = putSpliceCommitments(
+			chanBucket, spliceLocalCommitKey, spliceCommitments,
+		)
+		if err != nil {
+			return fmt.Errorf("unable to store splice "+
+				"commitments: %v", err)
+		}
+
 		// Persist unsigned but acked remote updates that need to be
 		// restored after a restart.
 		var b bytes.Buffer
@@ -1757,10 +1950,68 @@ type HTLC struct {
 	// from the HtlcIndex as this will be incremented for each new log
 	// update added.
 	LogIndex uint64
+
+	// BlindingPoint is the route blinding point that was sent along with
+	// the HTLC if it is routed through a blinded route.
+	BlindingPoint *bronec.PublicKey
+}
+
+// encodeTlvStream encodes the optional fields of the HTLC as a tlv stream. It
+// returns nil if none of the optional fields are set.
+func (h *HTLC) encodeTlvStream() ([]byte, error) {
+	var records []tlv.Record
+	if h.BlindingPoint != nil {
+		records = append(records, tlv.MakePrimitiveRecord(
+			htlcBlindingPointType, &h.BlindingPoint,
+		))
+	}
+
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	tlvStream, err := tlv.NewStream(records...)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if err := tlvStream.Encode(&b); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// decodeTlvStream decodes the optional fields of the HTLC from the given tlv
+// stream.
+func (h *HTLC) decodeTlvStream(tlvData []byte) error {
+	var blindingPoint *bronec.PublicKey
+	tlvStream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(htlcBlindingPointType, &blindingPoint),
+	)
+	if err != nil {
+		return err
+	}
+
+	parsedTypes, err := tlvStream.DecodeWithParsedTypes(
+		bytes.NewReader(tlvData),
+	)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := parsedTypes[htlcBlindingPointType]; ok {
+		h.BlindingPoint = blindingPoint
+	}
+
+	return nil
 }
 
 // SerializeHtlcs writes out the passed set of HTLC's into the passed writer
-// using the current default on-disk serialization format.
+// using the current default on-disk serialization format. The tlv stream of an
+// HTLC is appended to its fixed size onion blob, so that HTLCs without any
+// optional fields remain readable by prior versions.
 //
 // NOTE: This API is NOT stable, the on-disk format will likely change in the
 // future.
@@ -1771,9 +2022,25 @@ func SerializeHtlcs(b io.Writer, htlcs ...HTLC) error {
 	}
 
 	for _, htlc := range htlcs {
+		tlvData, err := htlc.encodeTlvStream()
+		if err != nil {
+			return err
+		}
+
+		onionBlob := htlc.OnionBlob
+		if len(tlvData) > 0 {
+			if len(onionBlob) != lnwire.OnionPacketSize {
+				return ErrOnionBlobLength
+			}
+
+			onionBlob = make([]byte, 0, len(onionBlob)+len(tlvData))
+			onionBlob = append(onionBlob, htlc.OnionBlob...)
+			onionBlob = append(onionBlob, tlvData...)
+		}
+
 		if err := WriteElements(b,
 			htlc.Signature, htlc.RHash, htlc.Amt, htlc.RefundTimeout,
-			htlc.OutputIndex, htlc.Incoming, htlc.OnionBlob[:],
+			htlc.OutputIndex, htlc.Incoming, onionBlob[:],
 			htlc.HtlcIndex, htlc.LogIndex,
 		); err != nil {
 			return err
@@ -1810,6 +2077,21 @@ func DeserializeHtlcs(r io.Reader) ([]HTLC, error) {
 		); err != nil {
 			return htlcs, err
 		}
+
+		// Any bytes following the fixed size onion blob are the tlv
+		// stream of the HTLC.
+		onionBlob := htlcs[i].OnionBlob
+		if len(onionBlob) <= lnwire.OnionPacketSize {
+			continue
+		}
+
+		htlcs[i].OnionBlob = onionBlob[:lnwire.OnionPacketSize]
+		err := htlcs[i].decodeTlvStream(
+			onionBlob[lnwire.OnionPacketSize:],
+		)
+		if err != nil {
+			return htlcs, err
+		}
 	}
 
 	return htlcs, nil
@@ -1822,6 +2104,7 @@ func (h *HTLC) Copy() HTLC {
 		Amt:           h.Amt,
 		RefundTimeout: h.RefundTimeout,
 		OutputIndex:   h.OutputIndex,
+		BlindingPoint: h.BlindingPoint,
 	}
 	copy(clone.Signature[:], h.Signature)
 	copy(clone.RHash[:], h.RHash[:])
@@ -1979,6 +2262,16 @@ type CommitDiff struct {
 	// settles and fails from the forwarding packages of other channels,
 	// such that they will not be reforwarded internally after a restart.
 	SettleFailAcks []SettleFailRef
+
+	// SpliceCommitments are the commitments for the same state that spend
+	// the funding outputs of the pending splices of the channel, keyed by
+	// the txid of their splice transaction. Their signatures are part of
+	// CommitSig.
+	//
+	// NOTE: This value is not serialized, it is stored along with the
+	// pending splices, such that it is promoted to their current remote
+	// commitment along with the commitment of this diff.
+	SpliceCommitments map[chainhash.Hash]*ChannelCommitment
 }
 
 // serializeLogUpdates serializes provided list of updates to a stream.
@@ -2184,6 +2477,16 @@ func (c *OpenChannel) AppendRemoteCommitChain(diff *CommitDiff) error {
 			return err
 		}
 
+		// The pending commitments for the funding outputs of the
+		// pending splices are written along with the commit diff.
+		err = putSpliceCommitments(
+			chanBucket, splicePendingCommitKey,
+			diff.SpliceCommitments,
+		)
+		if err != nil {
+			return err
+		}
+
 		// TODO(roasbeef): use seqno to derive key for later LCP
 
 		// With the bucket retrieved, we'll now serialize the commit
@@ -2428,6 +2731,12 @@ func (c *OpenChannel) AdvanceCommitChainTail(fwdPkg *FwdPkg,
 			return err
 		}
 
+		// The commitments for the funding outputs of the pending
+		// splices are advanced in the same way.
+		if err := advanceSpliceCommitChainTails(chanBucket); err != nil {
+			return err
+		}
+
 		// Lastly, we write the forwarding package to disk so that we
 		// can properly recover from failures and reforward HTLCs that
 		// have not received a corresponding settle/fail.
@@ -3347,12 +3656,17 @@ func putChanInfo(chanBucket kvdb.RwBucket, channel *OpenChannel) error {
 		return err
 	}
 
-	// Write the RevocationKeyLocator as the first entry in a tlv stream.
+	// Write the RevocationKeyLocator as the first entry in a tlv stream,
+	// followed by the confirmed ShortChannelID of zero-conf and scid-alias
+	// channels.
 	keyLocRecord := MakeKeyLocRecord(
 		keyLocType, &channel.RevocationKeyLocator,
 	)
+	realScid := channel.confirmedScid.ToUint64()
 
-	tlvStream, err := tlv.NewStream(keyLocRecord)
+	tlvStream, err := tlv.NewStream(
+		keyLocRecord, tlv.MakePrimitiveRecord(realScidType, &realScid),
+	)
 	if err != nil {
 		return err
 	}
@@ -3550,8 +3864,11 @@ func fetchChanInfo(chanBucket kvdb.RBucket, channel *OpenChannel) error {
 		}
 	}
 
+	var realScid uint64
 	keyLocRecord := MakeKeyLocRecord(keyLocType, &channel.RevocationKeyLocator)
-	tlvStream, err := tlv.NewStream(keyLocRecord)
+	tlvStream, err := tlv.NewStream(
+		keyLocRecord, tlv.MakePrimitiveRecord(realScidType, &realScid),
+	)
 	if err != nil {
 		return err
 	}
@@ -3560,6 +3877,8 @@ func fetchChanInfo(chanBucket kvdb.RBucket, channel *OpenChannel) error {
 		return err
 	}
 
+	channel.confirmedScid = lnwire.NewShortChanIDFromInt(realScid)
+
 	channel.Packager = NewChannelPackager(channel.ShortChannelID)
 
 	// Finally, read the optional shutdown scripts.
@@ -114,6 +114,14 @@ func openChannelOption() testChannelOption {
 	}
 }
 
+// channelTypeOption is an option which can be used to add the given bits to
+// the channel type of the test channel.
+func channelTypeOption(chanType ChannelType) testChannelOption {
+	return func(params *testChannelParams) {
+		params.channel.ChanType |= chanType
+	}
+}
+
 // localHtlcsOption is an option which allows setting of htlcs on the local
 // commitment.
 func localHtlcsOption(htlcs []HTLC) testChannelOption {
@@ -643,7 +651,9 @@ func TestChannelStateTransition(t *testing.T) {
 		},
 	}
 
-	err = channel.UpdateCommitment(&commitment, unsignedAckedUpdates)
+	err = channel.UpdateCommitment(
+		&commitment, unsignedAckedUpdates, nil,
+	)
 	if err != nil {
 		t.Fatalf("unable to update commitment: %v", err)
 	}
@@ -1175,6 +1185,29 @@ func TestFetchWaitingCloseChannels(t *testing.T) {
 	}
 }
 
+// TestCoopCloseDeliveryScript asserts that the delivery script of a
+// cooperative close can be stored and retrieved.
+func TestCoopCloseDeliveryScript(t *testing.T) {
+	t.Parallel()
+
+	fullDB, cleanUp, err := MakeTestDB()
+	require.NoError(t, err)
+	defer cleanUp()
+
+	cdb := fullDB.ChannelStateDB()
+	channel := createTestChannel(t, cdb, openChannelOption())
+
+	_, err = channel.CoopCloseDeliveryScript()
+	require.Equal(t, ErrNoDeliveryScript, err)
+
+	script := lnwire.DeliveryAddress{0x00, 0x14, 0x01, 0x02, 0x03}
+	require.NoError(t, channel.PutCoopCloseDeliveryScript(script))
+
+	storedScript, err := channel.CoopCloseDeliveryScript()
+	require.NoError(t, err)
+	require.Equal(t, script, storedScript)
+}
+
 // TestRefreshShortChanID asserts that RefreshShortChanID updates the in-memory
 // state of another OpenChannel to reflect a preceding call to MarkOpen on a
 // different OpenChannel.
@@ -1271,6 +1304,48 @@ func TestRefreshShortChanID(t *testing.T) {
 	}
 }
 
+// TestMarkRealScid tests that the confirmed short channel ID of a zero-conf
+// channel is persisted without touching the alias it is referred to by.
+func TestMarkRealScid(t *testing.T) {
+	t.Parallel()
+
+	fullDB, cleanUp, err := MakeTestDB()
+	require.NoError(t, err)
+	defer cleanUp()
+
+	cdb := fullDB.ChannelStateDB()
+
+	// Create a zero-conf channel and mark it as open using an alias.
+	state := createTestChannel(
+		t, cdb, channelTypeOption(ZeroConfBit|ScidAliasChanBit),
+	)
+
+	alias := lnwire.ShortChannelID{BlockHeight: 16_000_000}
+	require.NoError(t, state.MarkAsOpen(alias))
+	require.False(t, state.ZeroConfConfirmed())
+
+	// Now mark the real, confirmed location of the channel.
+	realScid := lnwire.ShortChannelID{
+		BlockHeight: 105,
+		TxIndex:     10,
+		TxPosition:  15,
+	}
+	require.NoError(t, state.MarkRealScid(realScid))
+
+	// Both the alias and the real SCID must be read back from disk, as
+	// well as the channel type bits that don't fit in a single byte.
+	openChans, err := cdb.FetchOpenChannels(state.IdentityPub)
+	require.NoError(t, err)
+	require.Len(t, openChans, 1)
+
+	dbChan := openChans[0]
+	require.True(t, dbChan.IsZeroConf())
+	require.True(t, dbChan.HasScidAlias())
+	require.Equal(t, alias, dbChan.ShortChanID())
+	require.True(t, dbChan.ZeroConfConfirmed())
+	require.Equal(t, realScid, dbChan.ZeroConfRealScid())
+}
+
 // TestCloseInitiator tests the setting of close initiator statuses for
 // cooperative closes and local force closes.
 func TestCloseInitiator(t *testing.T) {
@@ -1669,3 +1744,46 @@ func TestKeyLocatorEncoding(t *testing.T) {
 	// version are equal.
 	require.Equal(t, keyLoc, decodedKeyLoc)
 }
+
+// TestHtlcBlindingPointSerialization tests that the blinding point of an HTLC
+// is persisted within the tlv stream that follows its onion blob, while HTLCs
+// without a blinding point are serialized as before.
+func TestHtlcBlindingPointSerialization(t *testing.T) {
+	t.Parallel()
+
+	onionBlob := bytes.Repeat([]byte{1}, lnwire.OnionPacketSize)
+	htlcs := []HTLC{
+		{
+			Signature: bytes.Repeat([]byte{2}, 71),
+			RHash:     key,
+			Amt:       1000,
+			OnionBlob: []byte("onionblob"),
+		},
+		{
+			Signature:     bytes.Repeat([]byte{3}, 71),
+			RHash:         rev,
+			Amt:           2000,
+			Incoming:      true,
+			OnionBlob:     onionBlob,
+			BlindingPoint: pubKey,
+		},
+	}
+
+	var b bytes.Buffer
+	require.NoError(t, SerializeHtlcs(&b, htlcs...))
+
+	decodedHtlcs, err := DeserializeHtlcs(&b)
+	require.NoError(t, err)
+	require.Len(t, decodedHtlcs, 2)
+	require.Nil(t, decodedHtlcs[0].BlindingPoint)
+	require.NotNil(t, decodedHtlcs[1].BlindingPoint)
+	require.True(t, decodedHtlcs[1].BlindingPoint.IsEqual(pubKey))
+
+	decodedHtlcs[1].BlindingPoint = pubKey
+	require.Equal(t, htlcs, decodedHtlcs)
+
+	// The tlv stream can only be told apart from the onion blob if the
+	// onion blob has the expected size.
+	htlcs[1].OnionBlob = []byte("onionblob")
+	require.ErrorIs(t, SerializeHtlcs(&b, htlcs...), ErrOnionBlobLength)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/brronsuite/broln/keychain"
 	"github.com/brronsuite/broln/lnwire"
 	"github.com/brronsuite/broln/shachain"
+	"github.com/brronsuite/broln/tlv"
 	"github.com/brronsuite/brond/bronec"
 	"github.com/brronsuite/brond/chaincfg/chainhash"
 	"github.com/brronsuite/brond/wire"
@@ -85,7 +86,12 @@ func WriteElement(w io.Writer, element interface{}) error {
 
 		return binary.Write(w, byteOrder, false)
 	case ChannelType:
-		if err := binary.Write(w, byteOrder, e); err != nil {
+		// The channel type is encoded as a var int, which is backwards
+		// compatible with the single byte that was used before, as all
+		// previously known channel types fit into a single byte var
+		// int.
+		var buf [8]byte
+		if err := tlv.WriteVarInt(w, uint64(e), &buf); err != nil {
 			return err
 		}
 
@@ -270,10 +276,14 @@ func ReadElement(r io.Reader, element interface{}) error {
 		}
 
 	case *ChannelType:
-		if err := binary.Read(r, byteOrder, e); err != nil {
+		var buf [8]byte
+		chanType, err := tlv.ReadVarInt(r, &buf)
+		if err != nil {
 			return err
 		}
 
+		*e = ChannelType(chanType)
+
 	case *chainhash.Hash:
 		if _, err := io.ReadFull(r, e[:]); err != nil {
 			return err
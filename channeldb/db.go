@@ -303,6 +303,14 @@ func CreateWithBackend(backend kvdb.Backend, modifiers ...OptionModifier) (*DB,
 		return nil, err
 	}
 
+	// Backends with SQL support maintain a relational index of the
+	// invoices next to the invoice buckets, which we create and populate
+	// now if needed.
+	if err := initInvoiceSQLIndex(backend); err != nil {
+		backend.Close()
+		return nil, err
+	}
+
 	return chanDB, nil
 }
 
@@ -339,7 +347,8 @@ func (d *DB) Wipe() error {
 				return err
 			}
 		}
-		return nil
+
+		return wipeInvoiceSQLIndex(tx)
 	}, func() {})
 	if err != nil {
 		return err
@@ -496,6 +496,9 @@ func (c *ChannelGraph) ForEachNodeChannel(tx kvdb.RTx, node route.Vertex,
 			OutPolicySet: p1 != nil,
 			InPolicy:     cachedInPolicy,
 		}
+		if p1 != nil {
+			directedChannel.InboundFee = p1.InboundFee()
+		}
 
 		if node == e.NodeKey2Bytes {
 			directedChannel.OtherNode = e.NodeKey1Bytes
@@ -582,6 +585,9 @@ func (c *ChannelGraph) ForEachNodeCached(cb func(node route.Vertex,
 				OutPolicySet: p1 != nil,
 				InPolicy:     cachedInPolicy,
 			}
+			if p1 != nil {
+				directedChannel.InboundFee = p1.InboundFee()
+			}
 
 			if node.PubKeyBytes == e.NodeKey2Bytes {
 				directedChannel.OtherNode = e.NodeKey1Bytes
@@ -3469,6 +3475,19 @@ func (c *ChannelEdgePolicy) IsDisabled() bool {
 	return c.ChannelFlags.IsDisabled()
 }
 
+// InboundFee returns the fee the advertising node charges for HTLCs that
+// arrive over the channel, which is carried in the extra opaque data of the
+// policy. A zero fee is returned if the policy doesn't carry a valid inbound
+// fee.
+func (c *ChannelEdgePolicy) InboundFee() lnwire.InboundFee {
+	fee, err := lnwire.ParseInboundFee(c.ExtraOpaqueData)
+	if err != nil {
+		return lnwire.InboundFee{}
+	}
+
+	return fee
+}
+
 // ComputeFee computes the fee to forward an HTLC of `amt` milli-broneess over
 // the passed active payment channel. This value is currently computed as
 // specified in BOLT07, but will likely change in the near future.
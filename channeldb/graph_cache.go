@@ -82,6 +82,11 @@ type CachedEdgePolicy struct {
 	// the edge is in the cache, only on the copy that is returned in
 	// ForEachChannel().
 	ToNodeFeatures *lnwire.FeatureVector
+
+	// ToNodeInboundFee is the fee the to node charges for HTLCs that
+	// arrive over this channel. It is part of the to node's own policy,
+	// and is only set by path finding on the copy of the policy it uses.
+	ToNodeInboundFee lnwire.InboundFee
 }
 
 // ComputeFee computes the fee to forward an HTLC of `amt` milli-broneess over
@@ -144,6 +149,10 @@ type DirectedChannel struct {
 	// source, so we're always interested in the edge that arrives to us
 	// from the other node.
 	InPolicy *CachedEdgePolicy
+
+	// InboundFee is the fee this node charges for HTLCs that arrive over
+	// the channel, as advertised in its outgoing policy.
+	InboundFee lnwire.InboundFee
 }
 
 // DeepCopy creates a deep copy of the channel, including the incoming policy.
@@ -325,11 +334,13 @@ func (c *GraphCache) UpdatePolicy(policy *ChannelEdgePolicy, fromNode,
 		// policy for node 1.
 		case channel.IsNode1 && edge1:
 			channel.OutPolicySet = true
+			channel.InboundFee = policy.InboundFee()
 
 		// This is node 2, and it is edge 2, so this is the outgoing
 		// policy for node 2.
 		case !channel.IsNode1 && !edge1:
 			channel.OutPolicySet = true
+			channel.InboundFee = policy.InboundFee()
 
 		// The other two cases left mean it's the inbound policy for the
 		// node.
@@ -0,0 +1,784 @@
+package channeldb
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/brronsuite/broln/kvdb"
+	"github.com/brronsuite/broln/lntypes"
+)
+
+// The invoice SQL index consists of relational tables that are maintained next
+// to the invoice buckets when the database backend supports direct SQL access
+// (see kvdb.SQLTx). Every invoice has exactly one row in the invoice table,
+// keyed by its add index, that holds the attributes invoices are usually
+// looked up and filtered by. The settle events and AMP set ids, of which an
+// invoice can have several, are kept in tables of their own. This allows
+// invoice lookups and queries to use the database's indexes instead of walking
+// the invoice indexes with a cursor and deserializing every single invoice.
+// The serialized invoices themselves remain in the invoice bucket which stays
+// the source of truth. A single row sync marker records the add and settle
+// index of the last invoice that was added to and settled in the index.
+const (
+	// invoiceSQLTableName is the name of the relational invoice index
+	// table. The backend prefixes it with its namespace.
+	invoiceSQLTableName = "invoice_index"
+
+	// invoiceSettleSQLTableName is the name of the table that maps the
+	// settle index of every settle event to the invoice that was settled.
+	invoiceSettleSQLTableName = "invoice_settle_index"
+
+	// invoiceSetIDSQLTableName is the name of the table that maps the set
+	// ids of AMP payments to the invoice they paid.
+	invoiceSetIDSQLTableName = "invoice_set_id_index"
+
+	// invoiceSyncSQLTableName is the name of the table that holds the sync
+	// marker of the invoice index.
+	invoiceSyncSQLTableName = "invoice_index_sync"
+)
+
+// invoiceSQLTable returns the namespaced name of the invoice index table.
+func invoiceSQLTable(tx kvdb.SQLTx) string {
+	return tx.PrefixedTableName(invoiceSQLTableName)
+}
+
+// invoiceSettleSQLTable returns the namespaced name of the settle index table.
+func invoiceSettleSQLTable(tx kvdb.SQLTx) string {
+	return tx.PrefixedTableName(invoiceSettleSQLTableName)
+}
+
+// invoiceSetIDSQLTable returns the namespaced name of the set id index table.
+func invoiceSetIDSQLTable(tx kvdb.SQLTx) string {
+	return tx.PrefixedTableName(invoiceSetIDSQLTableName)
+}
+
+// invoiceSyncSQLTable returns the namespaced name of the sync marker table.
+func invoiceSyncSQLTable(tx kvdb.SQLTx) string {
+	return tx.PrefixedTableName(invoiceSyncSQLTableName)
+}
+
+// initInvoiceSQLIndex creates the invoice index tables if the backend supports
+// direct SQL access. As the index isn't maintained while the node runs on an
+// older version, its sync marker is compared to the sequence numbers of the
+// add and settle index buckets. If invoices were added or settled in the
+// meantime, or there is no sync marker yet, the index is rebuilt from the
+// invoice buckets.
+func initInvoiceSQLIndex(db kvdb.Backend) error {
+	return kvdb.Update(db, func(tx kvdb.RwTx) error {
+		sqlTx, ok := kvdb.AsSQLTx(tx)
+		if !ok {
+			return nil
+		}
+
+		if err := createInvoiceSQLTables(sqlTx); err != nil {
+			return fmt.Errorf("unable to create invoice index "+
+				"tables: %v", err)
+		}
+
+		inSync, err := invoiceSQLIndexInSync(tx, sqlTx)
+		if err != nil {
+			return err
+		}
+
+		if inSync {
+			return nil
+		}
+
+		return rebuildInvoiceSQLIndex(tx, sqlTx)
+	}, func() {})
+}
+
+// createInvoiceSQLTables creates the invoice index tables if they don't exist
+// yet. The payment hash and payment address columns were added to the invoice
+// table later on, so an existing table is extended with them. Such a table
+// predates the sync marker, which causes the index to be rebuilt.
+func createInvoiceSQLTables(sqlTx kvdb.SQLTx) error {
+	table := invoiceSQLTable(sqlTx)
+	settleTable := invoiceSettleSQLTable(sqlTx)
+	setIDTable := invoiceSetIDSQLTable(sqlTx)
+	syncTable := invoiceSyncSQLTable(sqlTx)
+
+	_, err := sqlTx.Exec(`
+CREATE TABLE IF NOT EXISTS ` + table + `
+(
+    add_index BIGINT PRIMARY KEY,
+    invoice_key BYTEA NOT NULL,
+    state SMALLINT NOT NULL,
+    creation_date BIGINT NOT NULL,
+    memo BYTEA
+);
+
+ALTER TABLE ` + table + ` ADD COLUMN IF NOT EXISTS payment_hash BYTEA;
+ALTER TABLE ` + table + ` ADD COLUMN IF NOT EXISTS pay_addr BYTEA;
+
+CREATE INDEX IF NOT EXISTS ` + table + `_state
+    ON ` + table + ` (state, add_index);
+
+CREATE INDEX IF NOT EXISTS ` + table + `_creation_date
+    ON ` + table + ` (creation_date, add_index);
+
+CREATE INDEX IF NOT EXISTS ` + table + `_payment_hash
+    ON ` + table + ` (payment_hash);
+
+CREATE INDEX IF NOT EXISTS ` + table + `_pay_addr
+    ON ` + table + ` (pay_addr);
+
+CREATE TABLE IF NOT EXISTS ` + settleTable + `
+(
+    settle_index BIGINT PRIMARY KEY,
+    add_index BIGINT NOT NULL,
+    set_id BYTEA
+);
+
+CREATE INDEX IF NOT EXISTS ` + settleTable + `_add_index
+    ON ` + settleTable + ` (add_index);
+
+CREATE TABLE IF NOT EXISTS ` + setIDTable + `
+(
+    set_id BYTEA PRIMARY KEY,
+    add_index BIGINT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS ` + setIDTable + `_add_index
+    ON ` + setIDTable + ` (add_index);
+
+CREATE TABLE IF NOT EXISTS ` + syncTable + `
+(
+    last_add_index BIGINT NOT NULL,
+    last_settle_index BIGINT NOT NULL
+);
+`)
+
+	return err
+}
+
+// invoiceSQLIndexInSync returns true if the invoice index tables reflect the
+// invoice buckets. This is the case if the add and settle index recorded in
+// the sync marker match the sequence numbers of the add and settle index
+// buckets, which are the add and settle index of the last invoice that was
+// added and settled.
+func invoiceSQLIndexInSync(tx kvdb.RwTx, sqlTx kvdb.SQLTx) (bool, error) {
+	var sqlLastAdded, sqlLastSettled int64
+	row, cancel := sqlTx.QueryRow(
+		"SELECT last_add_index, last_settle_index FROM " +
+			invoiceSyncSQLTable(sqlTx),
+	)
+	err := row.Scan(&sqlLastAdded, &sqlLastSettled)
+	cancel()
+	switch {
+	case err == sql.ErrNoRows:
+		log.Infof("Invoice index has no sync marker")
+		return false, nil
+
+	case err != nil:
+		return false, err
+	}
+
+	lastAdded, lastSettled := invoiceIndexSequences(tx)
+	inSync := uint64(sqlLastAdded) == lastAdded &&
+		uint64(sqlLastSettled) == lastSettled
+
+	if !inSync {
+		log.Infof("Invoice index is out of sync: invoices added up "+
+			"to index %d/%d, settled up to index %d/%d",
+			sqlLastAdded, lastAdded, sqlLastSettled, lastSettled)
+	}
+
+	return inSync, nil
+}
+
+// invoiceIndexSequences returns the sequence numbers of the add and settle
+// index buckets. Both are zero if the buckets don't exist yet.
+func invoiceIndexSequences(tx kvdb.RwTx) (uint64, uint64) {
+	invoices := tx.ReadWriteBucket(invoiceBucket)
+	if invoices == nil {
+		return 0, 0
+	}
+
+	var lastAdded, lastSettled uint64
+	addIndex := invoices.NestedReadWriteBucket(addIndexBucket)
+	if addIndex != nil {
+		lastAdded = addIndex.Sequence()
+	}
+	settleIndex := invoices.NestedReadWriteBucket(settleIndexBucket)
+	if settleIndex != nil {
+		lastSettled = settleIndex.Sequence()
+	}
+
+	return lastAdded, lastSettled
+}
+
+// rebuildInvoiceSQLIndex removes all rows from the invoice index tables, adds
+// the rows of every invoice in the invoice buckets and updates the sync marker
+// accordingly.
+func rebuildInvoiceSQLIndex(tx kvdb.RwTx, sqlTx kvdb.SQLTx) error {
+	if err := wipeInvoiceSQLTables(sqlTx); err != nil {
+		return err
+	}
+
+	lastAdded, lastSettled := invoiceIndexSequences(tx)
+	err := putInvoiceSQLSyncMarker(sqlTx, lastAdded, lastSettled)
+	if err != nil {
+		return err
+	}
+
+	invoices := tx.ReadBucket(invoiceBucket)
+	if invoices == nil {
+		return nil
+	}
+
+	invoiceIndex := invoices.NestedReadBucket(invoiceIndexBucket)
+	if invoiceIndex == nil {
+		return nil
+	}
+
+	var numInvoices int
+	err = invoiceIndex.ForEach(func(k, invoiceKey []byte) error {
+		// Skip the special numInvoicesKey as that does not point to a
+		// valid invoice.
+		if bytes.Equal(k, numInvoicesKey) {
+			return nil
+		}
+
+		invoice, err := fetchInvoice(invoiceKey, invoices)
+		if err != nil {
+			return err
+		}
+
+		var paymentHash lntypes.Hash
+		copy(paymentHash[:], k)
+
+		numInvoices++
+
+		return putInvoiceSQLRows(
+			sqlTx, invoiceKey, paymentHash, &invoice,
+		)
+	})
+	if err != nil {
+		return fmt.Errorf("unable to populate invoice index tables: "+
+			"%v", err)
+	}
+
+	log.Infof("Populated invoice index tables with %d invoices",
+		numInvoices)
+
+	return nil
+}
+
+// putInvoiceSQLSyncMarker replaces the sync marker of the invoice index with
+// the given add and settle index.
+func putInvoiceSQLSyncMarker(sqlTx kvdb.SQLTx, lastAdded,
+	lastSettled uint64) error {
+
+	table := invoiceSyncSQLTable(sqlTx)
+	if _, err := sqlTx.Exec("DELETE FROM " + table); err != nil {
+		return err
+	}
+
+	_, err := sqlTx.Exec(
+		"INSERT INTO "+table+" (last_add_index, last_settle_index) "+
+			"VALUES ($1, $2)",
+		int64(lastAdded), int64(lastSettled),
+	)
+
+	return err
+}
+
+// advanceInvoiceSQLSyncMarker raises the add or settle index, depending on
+// the given column, recorded in the sync marker to the given index.
+func advanceInvoiceSQLSyncMarker(sqlTx kvdb.SQLTx, column string,
+	index uint64) error {
+
+	_, err := sqlTx.Exec(
+		"UPDATE "+invoiceSyncSQLTable(sqlTx)+" SET "+column+
+			"=GREATEST("+column+", $1)",
+		int64(index),
+	)
+
+	return err
+}
+
+// putInvoiceSQLIndex inserts the invoice index rows of the given newly added
+// invoice, and records its add index in the sync marker.
+func putInvoiceSQLIndex(sqlTx kvdb.SQLTx, invoiceKey []byte,
+	paymentHash lntypes.Hash, invoice *Invoice) error {
+
+	err := putInvoiceSQLRows(sqlTx, invoiceKey, paymentHash, invoice)
+	if err != nil {
+		return err
+	}
+
+	return advanceInvoiceSQLSyncMarker(
+		sqlTx, "last_add_index", invoice.AddIndex,
+	)
+}
+
+// putInvoiceSQLRows inserts or updates the invoice index rows of the given
+// invoice.
+func putInvoiceSQLRows(sqlTx kvdb.SQLTx, invoiceKey []byte,
+	paymentHash lntypes.Hash, invoice *Invoice) error {
+
+	// The blank payment address of legacy keysend invoices isn't
+	// indexed, just like in the payment address index bucket.
+	var payAddr []byte
+	if invoice.Terms.PaymentAddr != BlankPayAddr {
+		payAddr = invoice.Terms.PaymentAddr[:]
+	}
+
+	table := invoiceSQLTable(sqlTx)
+	_, err := sqlTx.Exec(
+		"INSERT INTO "+table+" (add_index, invoice_key, "+
+			"payment_hash, pay_addr, state, creation_date, memo) "+
+			"VALUES ($1, $2, $3, $4, $5, $6, $7) "+
+			"ON CONFLICT (add_index) DO UPDATE SET state=$5",
+		int64(invoice.AddIndex), invoiceKey, paymentHash[:], payAddr,
+		int16(invoice.State), invoice.CreationDate.Unix(),
+		invoice.Memo,
+	)
+	if err != nil {
+		return err
+	}
+
+	return putInvoiceSQLRefs(sqlTx, invoice)
+}
+
+// updateInvoiceSQLIndex updates the state of the given invoice in the invoice
+// index tables, adds its new settle events and AMP set ids, and records its
+// latest settle index in the sync marker.
+func updateInvoiceSQLIndex(sqlTx kvdb.SQLTx, invoice *Invoice) error {
+	_, err := sqlTx.Exec(
+		"UPDATE "+invoiceSQLTable(sqlTx)+" SET state=$1 "+
+			"WHERE add_index=$2",
+		int16(invoice.State), int64(invoice.AddIndex),
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := putInvoiceSQLRefs(sqlTx, invoice); err != nil {
+		return err
+	}
+
+	lastSettled := invoice.SettleIndex
+	for _, ampState := range invoice.AMPState {
+		if ampState.SettleIndex > lastSettled {
+			lastSettled = ampState.SettleIndex
+		}
+	}
+	if lastSettled == 0 {
+		return nil
+	}
+
+	return advanceInvoiceSQLSyncMarker(
+		sqlTx, "last_settle_index", lastSettled,
+	)
+}
+
+// putInvoiceSQLRefs inserts the rows of the settle events and AMP set ids of
+// the given invoice that aren't indexed yet.
+func putInvoiceSQLRefs(sqlTx kvdb.SQLTx, invoice *Invoice) error {
+	addIndex := int64(invoice.AddIndex)
+
+	putSettle := func(settleIndex uint64, setID []byte) error {
+		_, err := sqlTx.Exec(
+			"INSERT INTO "+invoiceSettleSQLTable(sqlTx)+
+				" (settle_index, add_index, set_id) "+
+				"VALUES ($1, $2, $3) "+
+				"ON CONFLICT (settle_index) DO NOTHING",
+			int64(settleIndex), addIndex, setID,
+		)
+
+		return err
+	}
+
+	// Non-AMP invoices are settled at most once, and their settle index
+	// is stored on the invoice itself.
+	if invoice.SettleIndex != 0 {
+		if err := putSettle(invoice.SettleIndex, nil); err != nil {
+			return err
+		}
+	}
+
+	for setID, ampState := range invoice.AMPState {
+		setID := setID
+
+		_, err := sqlTx.Exec(
+			"INSERT INTO "+invoiceSetIDSQLTable(sqlTx)+
+				" (set_id, add_index) VALUES ($1, $2) "+
+				"ON CONFLICT (set_id) DO NOTHING",
+			setID[:], addIndex,
+		)
+		if err != nil {
+			return err
+		}
+
+		if ampState.SettleIndex == 0 {
+			continue
+		}
+
+		if err := putSettle(ampState.SettleIndex, setID[:]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteInvoiceSQLIndex removes the invoice index rows of the invoice with the
+// given add index.
+func deleteInvoiceSQLIndex(sqlTx kvdb.SQLTx, addIndex uint64) error {
+	for _, table := range []string{
+		invoiceSQLTable(sqlTx), invoiceSettleSQLTable(sqlTx),
+		invoiceSetIDSQLTable(sqlTx),
+	} {
+		_, err := sqlTx.Exec(
+			"DELETE FROM "+table+" WHERE add_index=$1",
+			int64(addIndex),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// wipeInvoiceSQLIndex removes all rows from the invoice index tables if the
+// backend supports direct SQL access. The sync marker is reset to match the
+// wiped invoice buckets.
+func wipeInvoiceSQLIndex(tx kvdb.RwTx) error {
+	sqlTx, ok := kvdb.AsSQLTx(tx)
+	if !ok {
+		return nil
+	}
+
+	if err := wipeInvoiceSQLTables(sqlTx); err != nil {
+		return err
+	}
+
+	return putInvoiceSQLSyncMarker(sqlTx, 0, 0)
+}
+
+// wipeInvoiceSQLTables removes all rows from the invoice index tables, except
+// for the sync marker.
+func wipeInvoiceSQLTables(sqlTx kvdb.SQLTx) error {
+	for _, table := range []string{
+		invoiceSQLTable(sqlTx), invoiceSettleSQLTable(sqlTx),
+		invoiceSetIDSQLTable(sqlTx),
+	} {
+		if _, err := sqlTx.Exec("DELETE FROM " + table); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fetchInvoiceNumByRefSQL retrieves the invoice number for the provided
+// invoice reference from the invoice index tables. It resolves the reference
+// the same way fetchInvoiceNumByRef does with the invoice index buckets.
+func fetchInvoiceNumByRefSQL(sqlTx kvdb.SQLTx, ref InvoiceRef) ([]byte,
+	error) {
+
+	table := invoiceSQLTable(sqlTx)
+
+	// lookup returns the invoice key of the single invoice matched by the
+	// given query, or nil if there is none.
+	lookup := func(query string, arg []byte) ([]byte, error) {
+		invoiceKeys, err := queryInvoiceKeys(sqlTx, query, arg)
+		if err != nil || len(invoiceKeys) == 0 {
+			return nil, err
+		}
+
+		return invoiceKeys[0], nil
+	}
+
+	// If the set id is present, we only consult the set id index for this
+	// invoice.
+	if setID := ref.SetID(); setID != nil {
+		invoiceNum, err := lookup(
+			"SELECT i.invoice_key FROM "+table+" i JOIN "+
+				invoiceSetIDSQLTable(sqlTx)+" s ON "+
+				"s.add_index = i.add_index WHERE s.set_id=$1",
+			setID[:],
+		)
+		switch {
+		case err != nil:
+			return nil, err
+
+		case invoiceNum == nil:
+			return nil, ErrInvoiceNotFound
+		}
+
+		return invoiceNum, nil
+	}
+
+	var (
+		invoiceNumByHash, invoiceNumByAddr []byte
+		err                                error
+	)
+
+	payHash := ref.PayHash()
+	if payHash != nil {
+		invoiceNumByHash, err = lookup(
+			"SELECT invoice_key FROM "+table+
+				" WHERE payment_hash=$1",
+			payHash[:],
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Only allow lookups for payment address if it is not a blank
+	// payment address, which is a special-cased value for legacy keysend
+	// invoices.
+	payAddr := ref.PayAddr()
+	if payAddr != nil && *payAddr != BlankPayAddr {
+		invoiceNumByAddr, err = lookup(
+			"SELECT invoice_key FROM "+table+" WHERE pay_addr=$1",
+			payAddr[:],
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return selectInvoiceNum(
+		invoiceNumByAddr, invoiceNumByHash, payHash != nil,
+	)
+}
+
+// invoicesAddedSinceSQL returns all invoices with an add index greater than
+// the given one in add index order.
+func invoicesAddedSinceSQL(sqlTx kvdb.SQLTx, invoices kvdb.RBucket,
+	sinceAddIndex uint64) ([]Invoice, error) {
+
+	invoiceKeys, err := queryInvoiceKeys(
+		sqlTx, "SELECT invoice_key FROM "+invoiceSQLTable(sqlTx)+
+			" WHERE add_index > $1 ORDER BY add_index ASC",
+		int64(sinceAddIndex),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return fetchInvoicesByKey(invoices, invoiceKeys)
+}
+
+// invoicesSettledSinceSQL returns all invoices that were settled with a settle
+// index greater than the given one in settle index order. AMP invoices are
+// returned once per settled set, with only the htlcs of that set.
+func invoicesSettledSinceSQL(sqlTx kvdb.SQLTx, invoices kvdb.RBucket,
+	sinceSettleIndex uint64) ([]Invoice, error) {
+
+	rows, cancel, err := sqlTx.Query(
+		"SELECT i.invoice_key, s.set_id FROM "+
+			invoiceSettleSQLTable(sqlTx)+" s JOIN "+
+			invoiceSQLTable(sqlTx)+" i ON "+
+			"i.add_index = s.add_index WHERE s.settle_index > $1 "+
+			"ORDER BY s.settle_index ASC",
+		int64(sinceSettleIndex),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	// We can't look up the invoices in the bucket while iterating over the
+	// rows, so we collect the keys first.
+	type settleEvent struct {
+		invoiceKey []byte
+		setID      *SetID
+	}
+
+	var events []settleEvent
+	for rows.Next() {
+		var invoiceKey, setIDBytes []byte
+		if err := rows.Scan(&invoiceKey, &setIDBytes); err != nil {
+			_ = rows.Close()
+			return nil, err
+		}
+
+		event := settleEvent{
+			invoiceKey: invoiceKey,
+		}
+		if setIDBytes != nil {
+			event.setID = new(SetID)
+			copy(event.setID[:], setIDBytes)
+		}
+
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return nil, err
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+
+	var settledInvoices []Invoice
+	for _, event := range events {
+		invoice, err := fetchInvoice(
+			event.invoiceKey, invoices, event.setID,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		settledInvoices = append(settledInvoices, invoice)
+	}
+
+	return settledInvoices, nil
+}
+
+// queryInvoicesSQL executes the given invoice query against the invoice index
+// table and returns the matching invoices in add index order.
+func queryInvoicesSQL(sqlTx kvdb.SQLTx, invoices kvdb.RBucket,
+	q InvoiceQuery) ([]Invoice, error) {
+
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	addArg := func(arg interface{}) string {
+		args = append(args, arg)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	// The index offset is exclusive in both directions. An offset of zero
+	// in reverse direction means we start with the latest invoice.
+	switch {
+	case !q.Reversed:
+		conditions = append(conditions, "add_index > "+
+			addArg(int64(q.IndexOffset)))
+
+	case q.IndexOffset != 0:
+		conditions = append(conditions, "add_index < "+
+			addArg(int64(q.IndexOffset)))
+	}
+
+	states := q.States
+	if q.PendingOnly {
+		states = filterPendingStates(states)
+
+		// None of the requested states is a pending state, so there
+		// can't be any matches.
+		if len(states) == 0 {
+			return nil, nil
+		}
+	}
+	if len(states) > 0 {
+		stateArgs := make([]string, len(states))
+		for i, state := range states {
+			stateArgs[i] = addArg(int16(state))
+		}
+		conditions = append(conditions, "state IN ("+
+			strings.Join(stateArgs, ", ")+")")
+	}
+
+	if !q.CreationDateStart.IsZero() {
+		conditions = append(conditions, "creation_date >= "+
+			addArg(q.CreationDateStart.Unix()))
+	}
+	if !q.CreationDateEnd.IsZero() {
+		conditions = append(conditions, "creation_date <= "+
+			addArg(q.CreationDateEnd.Unix()))
+	}
+
+	if q.MemoContains != "" {
+		conditions = append(conditions, "position("+
+			addArg([]byte(q.MemoContains))+" in memo) > 0")
+	}
+
+	order := "ASC"
+	if q.Reversed {
+		order = "DESC"
+	}
+
+	query := "SELECT invoice_key FROM " + invoiceSQLTable(sqlTx)
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY add_index " + order + " LIMIT " +
+		addArg(int64(q.NumMaxInvoices))
+
+	invoiceKeys, err := queryInvoiceKeys(sqlTx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return fetchInvoicesByKey(invoices, invoiceKeys)
+}
+
+// queryInvoiceKeys executes the given query which must select the invoice
+// keys of the invoices it matches, and returns them in order. We can't look up
+// the invoices in the bucket while iterating over the rows, so we collect the
+// keys first.
+func queryInvoiceKeys(sqlTx kvdb.SQLTx, query string,
+	args ...interface{}) ([][]byte, error) {
+
+	rows, cancel, err := sqlTx.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	var invoiceKeys [][]byte
+	for rows.Next() {
+		var invoiceKey []byte
+		if err := rows.Scan(&invoiceKey); err != nil {
+			_ = rows.Close()
+			return nil, err
+		}
+
+		invoiceKeys = append(invoiceKeys, invoiceKey)
+	}
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return nil, err
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+
+	return invoiceKeys, nil
+}
+
+// fetchInvoicesByKey looks up the invoices with the given keys in the invoice
+// bucket.
+func fetchInvoicesByKey(invoices kvdb.RBucket,
+	invoiceKeys [][]byte) ([]Invoice, error) {
+
+	var result []Invoice
+	for _, invoiceKey := range invoiceKeys {
+		invoice, err := fetchInvoice(invoiceKey, invoices)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, invoice)
+	}
+
+	return result, nil
+}
+
+// filterPendingStates returns the pending states out of the given set of
+// states. If the set is empty, all pending states are returned.
+func filterPendingStates(states []ContractState) []ContractState {
+	if len(states) == 0 {
+		return []ContractState{ContractOpen, ContractAccepted}
+	}
+
+	var pending []ContractState
+	for _, state := range states {
+		if state == ContractOpen || state == ContractAccepted {
+			pending = append(pending, state)
+		}
+	}
+
+	return pending
+}
@@ -0,0 +1,214 @@
+//go:build kvdb_postgres
+// +build kvdb_postgres
+
+package channeldb
+
+import (
+	"testing"
+
+	"github.com/brronsuite/broln/kvdb"
+	"github.com/brronsuite/broln/lntypes"
+	"github.com/brronsuite/broln/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// addSQLTestInvoices adds the given number of invoices to the database, and
+// settles every second one. It returns the payment hashes of the invoices in
+// add index order.
+func addSQLTestInvoices(t *testing.T, db *DB,
+	numInvoices int) []lntypes.Hash {
+
+	var hashes []lntypes.Hash
+	for i := 1; i <= numInvoices; i++ {
+		amt := lnwire.MilliBronees(i)
+		invoice, err := randInvoice(amt)
+		require.NoError(t, err)
+
+		paymentHash := invoice.Terms.PaymentPreimage.Hash()
+		_, err = db.AddInvoice(invoice, paymentHash)
+		require.NoError(t, err)
+
+		hashes = append(hashes, paymentHash)
+
+		if i%2 != 0 {
+			continue
+		}
+
+		_, err = db.UpdateInvoice(
+			InvoiceRefByHash(paymentHash), nil,
+			getUpdateInvoice(amt),
+		)
+		require.NoError(t, err)
+	}
+
+	return hashes
+}
+
+// execInvoiceSQL executes the given statement against the invoice index
+// tables.
+func execInvoiceSQL(t *testing.T, db *DB, query func(kvdb.SQLTx) string,
+	args ...interface{}) {
+
+	err := kvdb.Update(db, func(tx kvdb.RwTx) error {
+		sqlTx, ok := kvdb.AsSQLTx(tx)
+		require.True(t, ok)
+
+		_, err := sqlTx.Exec(query(sqlTx), args...)
+		return err
+	}, func() {})
+	require.NoError(t, err)
+}
+
+// addIndexes returns the add indexes of the given invoices.
+func addIndexes(invoices []Invoice) []uint64 {
+	var indexes []uint64
+	for _, invoice := range invoices {
+		indexes = append(indexes, invoice.AddIndex)
+	}
+
+	return indexes
+}
+
+// TestInvoiceSQLIndexLookups tests that invoices are looked up by reference,
+// and that added and settled invoices are found through the invoice index
+// tables.
+func TestInvoiceSQLIndexLookups(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp, err := MakeTestDB()
+	defer cleanUp()
+	require.NoError(t, err, "unable to make test db")
+
+	hashes := addSQLTestInvoices(t, db, 6)
+
+	// Every invoice must be found by its payment hash and by its payment
+	// address.
+	for i, hash := range hashes {
+		invoice, err := db.LookupInvoice(InvoiceRefByHash(hash))
+		require.NoError(t, err)
+		require.EqualValues(t, i+1, invoice.AddIndex)
+
+		payAddr := invoice.Terms.PaymentAddr
+		invoice, err = db.LookupInvoice(InvoiceRefByAddr(payAddr))
+		require.NoError(t, err)
+		require.EqualValues(t, i+1, invoice.AddIndex)
+
+		invoice, err = db.LookupInvoice(
+			InvoiceRefByHashAndAddr(hash, payAddr),
+		)
+		require.NoError(t, err)
+		require.EqualValues(t, i+1, invoice.AddIndex)
+	}
+
+	// A payment hash and payment address of different invoices must be
+	// rejected, and unknown references must not be found.
+	otherInvoice, err := db.LookupInvoice(InvoiceRefByHash(hashes[1]))
+	require.NoError(t, err)
+
+	_, err = db.LookupInvoice(InvoiceRefByHashAndAddr(
+		hashes[0], otherInvoice.Terms.PaymentAddr,
+	))
+	require.ErrorIs(t, err, ErrInvRefEquivocation)
+
+	_, err = db.LookupInvoice(InvoiceRefByHash(lntypes.Hash{1}))
+	require.ErrorIs(t, err, ErrInvoiceNotFound)
+
+	_, err = db.LookupInvoice(InvoiceRefBySetID(SetID{1}))
+	require.ErrorIs(t, err, ErrInvoiceNotFound)
+
+	added, err := db.InvoicesAddedSince(3)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{4, 5, 6}, addIndexes(added))
+
+	settled, err := db.InvoicesSettledSince(1)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{4, 6}, addIndexes(settled))
+	for i, invoice := range settled {
+		require.Equal(t, ContractSettled, invoice.State)
+		require.EqualValues(t, i+2, invoice.SettleIndex)
+	}
+
+	// Deleted invoices must not be found anymore.
+	toDelete := []InvoiceDeleteRef{{
+		PayHash:     hashes[5],
+		PayAddr:     &settled[1].Terms.PaymentAddr,
+		AddIndex:    settled[1].AddIndex,
+		SettleIndex: settled[1].SettleIndex,
+	}}
+	require.NoError(t, db.DeleteInvoice(toDelete))
+
+	_, err = db.LookupInvoice(InvoiceRefByHash(hashes[5]))
+	require.ErrorIs(t, err, ErrInvoiceNotFound)
+
+	settled, err = db.InvoicesSettledSince(1)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{4}, addIndexes(settled))
+}
+
+// TestInvoiceSQLIndexRepair tests that invoice index tables that lag behind
+// the invoice buckets are rebuilt on startup, based on their sync marker.
+func TestInvoiceSQLIndexRepair(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp, err := MakeTestDB()
+	defer cleanUp()
+	require.NoError(t, err, "unable to make test db")
+
+	hashes := addSQLTestInvoices(t, db, 4)
+
+	// Simulate that the last invoice was added and settled while the
+	// index wasn't maintained.
+	execInvoiceSQL(t, db, func(sqlTx kvdb.SQLTx) string {
+		return "DELETE FROM " + invoiceSQLTable(sqlTx) +
+			" WHERE add_index=$1"
+	}, int64(4))
+	execInvoiceSQL(t, db, func(sqlTx kvdb.SQLTx) string {
+		return "DELETE FROM " + invoiceSettleSQLTable(sqlTx) +
+			" WHERE add_index=$1"
+	}, int64(4))
+	execInvoiceSQL(t, db, func(sqlTx kvdb.SQLTx) string {
+		return "UPDATE " + invoiceSyncSQLTable(sqlTx) +
+			" SET last_add_index=$1, last_settle_index=$2"
+	}, int64(3), int64(1))
+
+	_, err = db.LookupInvoice(InvoiceRefByHash(hashes[3]))
+	require.ErrorIs(t, err, ErrInvoiceNotFound)
+
+	// The index must be rebuilt on startup.
+	require.NoError(t, initInvoiceSQLIndex(db.Backend))
+
+	invoice, err := db.LookupInvoice(InvoiceRefByHash(hashes[3]))
+	require.NoError(t, err)
+	require.EqualValues(t, 4, invoice.AddIndex)
+
+	settled, err := db.InvoicesSettledSince(1)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{2, 4}, addIndexes(settled))
+
+	// Change the state of a pending invoice in the index only. As the
+	// sync marker still matches, the index must be left alone on startup.
+	execInvoiceSQL(t, db, func(sqlTx kvdb.SQLTx) string {
+		return "UPDATE " + invoiceSQLTable(sqlTx) +
+			" SET state=$1 WHERE add_index=$2"
+	}, int16(ContractAccepted), int64(1))
+
+	query := InvoiceQuery{
+		States:         []ContractState{ContractOpen},
+		NumMaxInvoices: 10,
+	}
+	require.NoError(t, initInvoiceSQLIndex(db.Backend))
+
+	resp, err := db.QueryInvoices(query)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{3}, addIndexes(resp.Invoices))
+
+	// Removing the sync marker forces the index to be rebuilt.
+	execInvoiceSQL(t, db, func(sqlTx kvdb.SQLTx) string {
+		return "DELETE FROM " + invoiceSyncSQLTable(sqlTx)
+	})
+	require.NoError(t, initInvoiceSQLIndex(db.Backend))
+
+	resp, err = db.QueryInvoices(query)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{1, 3}, addIndexes(resp.Invoices))
+}
@@ -1403,6 +1403,131 @@ func TestQueryInvoices(t *testing.T) {
 	}
 }
 
+// TestQueryInvoicesFilters tests that the creation date, state and memo
+// filters of an invoice query are applied correctly, also in combination with
+// pagination.
+func TestQueryInvoicesFilters(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp, err := MakeTestDB()
+	defer cleanUp()
+	require.NoError(t, err, "unable to make test db")
+
+	// We'll add 20 invoices, each created 100 seconds after the previous
+	// one. Every third invoice is settled and the memo tells whether the
+	// add index is even or odd.
+	const numInvoices = 20
+	for i := 1; i <= numInvoices; i++ {
+		amt := lnwire.MilliBronees(i)
+		invoice, err := randInvoice(amt)
+		require.NoError(t, err)
+
+		invoice.CreationDate = time.Unix(int64(i*100), 0)
+		if i%2 == 0 {
+			invoice.Memo = []byte("memo-even")
+		} else {
+			invoice.Memo = []byte("memo-odd")
+		}
+
+		paymentHash := invoice.Terms.PaymentPreimage.Hash()
+		_, err = db.AddInvoice(invoice, paymentHash)
+		require.NoError(t, err)
+
+		if i%3 == 0 {
+			ref := InvoiceRefByHash(paymentHash)
+			_, err := db.UpdateInvoice(
+				ref, nil, getUpdateInvoice(amt),
+			)
+			require.NoError(t, err)
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		query    InvoiceQuery
+		expected []uint64
+	}{
+		{
+			name: "settled only",
+			query: InvoiceQuery{
+				States:         []ContractState{ContractSettled},
+				NumMaxInvoices: numInvoices,
+			},
+			expected: []uint64{3, 6, 9, 12, 15, 18},
+		},
+		{
+			name: "pending only with settled state",
+			query: InvoiceQuery{
+				States:         []ContractState{ContractSettled},
+				PendingOnly:    true,
+				NumMaxInvoices: numInvoices,
+			},
+			expected: nil,
+		},
+		{
+			name: "creation date range",
+			query: InvoiceQuery{
+				CreationDateStart: time.Unix(500, 0),
+				CreationDateEnd:   time.Unix(1000, 0),
+				NumMaxInvoices:    numInvoices,
+			},
+			expected: []uint64{5, 6, 7, 8, 9, 10},
+		},
+		{
+			name: "memo filter",
+			query: InvoiceQuery{
+				MemoContains:   "even",
+				NumMaxInvoices: 4,
+			},
+			expected: []uint64{2, 4, 6, 8},
+		},
+		{
+			name: "combined filters paginated forwards",
+			query: InvoiceQuery{
+				IndexOffset:       6,
+				States:            []ContractState{ContractOpen},
+				CreationDateStart: time.Unix(300, 0),
+				MemoContains:      "odd",
+				NumMaxInvoices:    3,
+			},
+			expected: []uint64{7, 11, 13},
+		},
+		{
+			name: "combined filters paginated backwards",
+			query: InvoiceQuery{
+				States:         []ContractState{ContractOpen},
+				MemoContains:   "even",
+				Reversed:       true,
+				NumMaxInvoices: 3,
+			},
+			expected: []uint64{14, 16, 20},
+		},
+		{
+			name: "no matches",
+			query: InvoiceQuery{
+				MemoContains:   "unknown",
+				NumMaxInvoices: numInvoices,
+			},
+			expected: nil,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			response, err := db.QueryInvoices(testCase.query)
+			require.NoError(t, err)
+
+			var addIndexes []uint64
+			for _, invoice := range response.Invoices {
+				addIndexes = append(addIndexes, invoice.AddIndex)
+			}
+			require.Equal(t, testCase.expected, addIndexes)
+		})
+	}
+}
+
 // getUpdateInvoice returns an invoice update callback that, when called,
 // settles the invoice with the given amount.
 func getUpdateInvoice(amt lnwire.MilliBronees) InvoiceUpdateCallback {
@@ -973,6 +973,20 @@ func (d *DB) AddInvoice(newInvoice *Invoice, paymentHash lntypes.Hash) (
 			return err
 		}
 
+		// Keep the relational invoice index in sync if the backend
+		// supports it.
+		if sqlTx, ok := kvdb.AsSQLTx(tx); ok {
+			var invoiceKey [4]byte
+			byteOrder.PutUint32(invoiceKey[:], invoiceNum)
+
+			err := putInvoiceSQLIndex(
+				sqlTx, invoiceKey[:], paymentHash, newInvoice,
+			)
+			if err != nil {
+				return err
+			}
+		}
+
 		invoiceAddIndex = newIndex
 		return nil
 	}, func() {
@@ -1011,6 +1025,17 @@ func (d *DB) InvoicesAddedSince(sinceAddIndex uint64) ([]Invoice, error) {
 			return nil
 		}
 
+		// If the backend maintains a relational invoice index, we use
+		// it to find the invoices instead of walking the add index.
+		if sqlTx, ok := kvdb.AsSQLTx(tx); ok {
+			var err error
+			newInvoices, err = invoicesAddedSinceSQL(
+				sqlTx, invoices, sinceAddIndex,
+			)
+
+			return err
+		}
+
 		addIndex := invoices.NestedReadBucket(addIndexBucket)
 		if addIndex == nil {
 			return nil
@@ -1070,10 +1095,19 @@ func (d *DB) LookupInvoice(ref InvoiceRef) (Invoice, error) {
 		setIDIndex := tx.ReadBucket(setIDIndexBucket)
 
 		// Retrieve the invoice number for this invoice using
-		// the provided invoice reference.
-		invoiceNum, err := fetchInvoiceNumByRef(
-			invoiceIndex, payAddrIndex, setIDIndex, ref,
+		// the provided invoice reference. If the backend maintains a
+		// relational invoice index, we resolve the reference there.
+		var (
+			invoiceNum []byte
+			err        error
 		)
+		if sqlTx, ok := kvdb.AsSQLTx(tx); ok {
+			invoiceNum, err = fetchInvoiceNumByRefSQL(sqlTx, ref)
+		} else {
+			invoiceNum, err = fetchInvoiceNumByRef(
+				invoiceIndex, payAddrIndex, setIDIndex, ref,
+			)
+		}
 		if err != nil {
 			return err
 		}
@@ -1153,8 +1187,19 @@ func fetchInvoiceNumByRef(invoiceIndex, payAddrIndex, setIDIndex kvdb.RBucket,
 		return nil
 	}
 
-	invoiceNumByHash := getInvoiceNumByHash()
-	invoiceNumByAddr := getInvoiceNumByAddr()
+	return selectInvoiceNum(
+		getInvoiceNumByAddr(), getInvoiceNumByHash(), payHash != nil,
+	)
+}
+
+// selectInvoiceNum returns the invoice number that an invoice reference
+// resolves to, given the invoice numbers that its payment address and payment
+// hash were found under, if any. The payment address will be treated as the
+// primary key, falling back to the payment hash if nothing is found for the
+// payment address.
+func selectInvoiceNum(invoiceNumByAddr, invoiceNumByHash []byte,
+	hasPayHash bool) ([]byte, error) {
+
 	switch {
 
 	// If payment address and payment hash both reference an existing
@@ -1174,7 +1219,7 @@ func fetchInvoiceNumByRef(invoiceIndex, payAddrIndex, setIDIndex kvdb.RBucket,
 	// payment hash for the invoice, without this check we would
 	// inadvertently assume the invoice contains the correct preimage for
 	// the HTLC, which we only enforce via the lookup by the invoice index.
-	case invoiceNumByAddr != nil && payHash == nil:
+	case invoiceNumByAddr != nil && !hasPayHash:
 		return invoiceNumByAddr, nil
 
 	// If we were only able to reference the invoice by hash, return the
@@ -1255,6 +1300,68 @@ type InvoiceQuery struct {
 	// Reversed, if set, indicates that the invoices returned should start
 	// from the IndexOffset and go backwards.
 	Reversed bool
+
+	// CreationDateStart, if set, filters out all invoices that were
+	// created before this time.
+	CreationDateStart time.Time
+
+	// CreationDateEnd, if set, filters out all invoices that were created
+	// after this time.
+	CreationDateEnd time.Time
+
+	// States, if non-empty, only returns invoices that are in one of the
+	// given states.
+	States []ContractState
+
+	// MemoContains, if set, only returns invoices whose memo contains the
+	// given string.
+	MemoContains string
+}
+
+// matches returns true if the given invoice passes all filters of the query.
+// Only the add index range is not checked by this method.
+func (q *InvoiceQuery) matches(invoice *Invoice) bool {
+	// Skip any settled or canceled invoices if the caller is only
+	// interested in pending ones.
+	if q.PendingOnly && !invoice.IsPending() {
+		return false
+	}
+
+	if len(q.States) > 0 {
+		var found bool
+		for _, state := range q.States {
+			if invoice.State == state {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	// The relational index stores the creation date with a resolution of
+	// seconds, so we compare with the same resolution here to return the
+	// same results for all backends.
+	creationDate := invoice.CreationDate.Unix()
+	if !q.CreationDateStart.IsZero() &&
+		creationDate < q.CreationDateStart.Unix() {
+
+		return false
+	}
+	if !q.CreationDateEnd.IsZero() &&
+		creationDate > q.CreationDateEnd.Unix() {
+
+		return false
+	}
+
+	if q.MemoContains != "" &&
+		!bytes.Contains(invoice.Memo, []byte(q.MemoContains)) {
+
+		return false
+	}
+
+	return true
 }
 
 // InvoiceSlice is the response to a invoice query. It includes the original
@@ -1302,6 +1409,27 @@ func (d *DB) QueryInvoices(q InvoiceQuery) (InvoiceSlice, error) {
 			return ErrNoInvoicesCreated
 		}
 
+		// If the backend maintains a relational invoice index, we let
+		// the database do the filtering and pagination for us.
+		if sqlTx, ok := kvdb.AsSQLTx(tx); ok {
+			var err error
+			resp.Invoices, err = queryInvoicesSQL(
+				sqlTx, invoices, q,
+			)
+			if err != nil {
+				return err
+			}
+
+			// The invoices are returned in add index order, so we
+			// still need to reverse them if we were paginating
+			// backwards.
+			if q.Reversed {
+				reverseInvoices(resp.Invoices)
+			}
+
+			return nil
+		}
+
 		// Create a paginator which reads from our add index bucket with
 		// the parameters provided by the invoice query.
 		paginator := newPaginator(
@@ -1319,9 +1447,9 @@ func (d *DB) QueryInvoices(q InvoiceQuery) (InvoiceSlice, error) {
 				return false, err
 			}
 
-			// Skip any settled or canceled invoices if the caller
-			// is only interested in pending ones.
-			if q.PendingOnly && !invoice.IsPending() {
+			// Skip any invoices that don't match the filters of
+			// the query.
+			if !q.matches(&invoice) {
 				return false, nil
 			}
 
@@ -1341,12 +1469,7 @@ func (d *DB) QueryInvoices(q InvoiceQuery) (InvoiceSlice, error) {
 		// we'll need to reverse the slice of invoices to return them in
 		// forward order.
 		if q.Reversed {
-			numInvoices := len(resp.Invoices)
-			for i := 0; i < numInvoices/2; i++ {
-				opposite := numInvoices - i - 1
-				resp.Invoices[i], resp.Invoices[opposite] =
-					resp.Invoices[opposite], resp.Invoices[i]
-			}
+			reverseInvoices(resp.Invoices)
 		}
 
 		return nil
@@ -1369,6 +1492,15 @@ func (d *DB) QueryInvoices(q InvoiceQuery) (InvoiceSlice, error) {
 	return resp, nil
 }
 
+// reverseInvoices reverses the given slice of invoices in place.
+func reverseInvoices(invoices []Invoice) {
+	numInvoices := len(invoices)
+	for i := 0; i < numInvoices/2; i++ {
+		opposite := numInvoices - i - 1
+		invoices[i], invoices[opposite] = invoices[opposite], invoices[i]
+	}
+}
+
 // UpdateInvoice attempts to update an invoice corresponding to the passed
 // payment hash. If an invoice matching the passed payment hash doesn't exist
 // within the database, then the action will fail with a "not found" error.
@@ -1414,8 +1546,17 @@ func (d *DB) UpdateInvoice(ref InvoiceRef, setIDHint *SetID,
 			payHash, setIDHint, invoices, settleIndex, setIDIndex,
 			invoiceNum, callback,
 		)
+		if err != nil {
+			return err
+		}
 
-		return err
+		// The state of the invoice might have changed, so we update
+		// the relational invoice index as well if there is one.
+		if sqlTx, ok := kvdb.AsSQLTx(tx); ok {
+			return updateInvoiceSQLIndex(sqlTx, updatedInvoice)
+		}
+
+		return nil
 	}, func() {
 		updatedInvoice = nil
 	})
@@ -1448,6 +1589,18 @@ func (d *DB) InvoicesSettledSince(sinceSettleIndex uint64) ([]Invoice, error) {
 			return nil
 		}
 
+		// If the backend maintains a relational invoice index, we use
+		// it to find the settled invoices instead of walking the
+		// settle index.
+		if sqlTx, ok := kvdb.AsSQLTx(tx); ok {
+			var err error
+			settledInvoices, err = invoicesSettledSinceSQL(
+				sqlTx, invoices, sinceSettleIndex,
+			)
+
+			return err
+		}
+
 		settleIndex := invoices.NestedReadBucket(settleIndexBucket)
 		if settleIndex == nil {
 			return nil
@@ -3348,6 +3501,15 @@ func (d *DB) DeleteInvoice(invoicesToDelete []InvoiceDeleteRef) error {
 				return err
 			}
 
+			// Remove from the relational invoice index if the
+			// backend maintains one.
+			if sqlTx, ok := kvdb.AsSQLTx(tx); ok {
+				err := deleteInvoiceSQLIndex(sqlTx, ref.AddIndex)
+				if err != nil {
+					return err
+				}
+			}
+
 			// Remove from the settle index if available and
 			// if the invoice is settled.
 			if settleIndex != nil && ref.SettleIndex > 0 {
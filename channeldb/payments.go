@@ -1142,6 +1142,33 @@ func serializeHop(w io.Writer, h *route.Hop) error {
 		records = append(records, h.MPP.Record())
 	}
 
+	// The fields of hops in a blinded route are stored with the types
+	// they have in the onion payload.
+	if h.EncryptedData != nil {
+		records = append(records, record.NewEncryptedDataRecord(
+			&h.EncryptedData,
+		))
+	}
+
+	if h.BlindingPoint != nil {
+		records = append(records, record.NewBlindingPointRecord(
+			&h.BlindingPoint,
+		))
+	}
+
+	if h.TotalAmtMsat != 0 {
+		totalAmt := uint64(h.TotalAmtMsat)
+		records = append(records, record.NewTotalAmtMsatBlindedRecord(
+			&totalAmt,
+		))
+	}
+
+	if h.TrampolineOnion != nil {
+		records = append(records, record.NewTrampolineOnionRecord(
+			&h.TrampolineOnion,
+		))
+	}
+
 	// Final sanity check to absolutely rule out custom records that are not
 	// custom and write into the standard range.
 	if err := h.CustomRecords.Validate(); err != nil {
@@ -1256,6 +1283,46 @@ func deserializeHop(r io.Reader) (*route.Hop, error) {
 		h.MPP = mpp
 	}
 
+	encryptedDataType := uint64(record.EncryptedDataOnionType)
+	if data, ok := tlvMap[encryptedDataType]; ok {
+		delete(tlvMap, encryptedDataType)
+		h.EncryptedData = data
+	}
+
+	blindingPointType := uint64(record.BlindingPointOnionType)
+	if pointBytes, ok := tlvMap[blindingPointType]; ok {
+		delete(tlvMap, blindingPointType)
+
+		var (
+			rec = record.NewBlindingPointRecord(&h.BlindingPoint)
+			r   = bytes.NewReader(pointBytes)
+		)
+		err := rec.Decode(r, uint64(len(pointBytes)))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	totalAmtType := uint64(record.TotalAmtMsatBlindedType)
+	if totalAmtBytes, ok := tlvMap[totalAmtType]; ok {
+		delete(tlvMap, totalAmtType)
+
+		var totalAmt uint64
+		rec := record.NewTotalAmtMsatBlindedRecord(&totalAmt)
+		r := bytes.NewReader(totalAmtBytes)
+		err := rec.Decode(r, uint64(len(totalAmtBytes)))
+		if err != nil {
+			return nil, err
+		}
+		h.TotalAmtMsat = lnwire.MilliBronees(totalAmt)
+	}
+
+	trampolineType := uint64(record.TrampolineOnionType)
+	if onion, ok := tlvMap[trampolineType]; ok {
+		delete(tlvMap, trampolineType)
+		h.TrampolineOnion = onion
+	}
+
 	h.CustomRecords = tlvMap
 
 	return h, nil
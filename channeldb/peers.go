@@ -20,21 +20,32 @@ var (
 	//      |
 	//      |-- <peer-pubkey>
 	//      |        |--flap-count-key: <ts><flap count>
+	//      |        |--peer-storage-key: <blob>
 	//      |
 	//      |-- <peer-pubkey>
 	//      |        |--flap-count-key: <ts><flap count>
+	//      |        |--peer-storage-key: <blob>
 	peersBucket = []byte("peers-bucket")
 
 	// flapCountKey is a key used in the peer pubkey sub-bucket that stores
 	// the timestamp of a peer's last flap count and its all time flap
 	// count.
 	flapCountKey = []byte("flap-count")
+
+	// peerStorageKey is a key used in the peer pubkey sub-bucket that
+	// stores the latest backup blob the peer asked us to store on its
+	// behalf.
+	peerStorageKey = []byte("peer-storage")
 )
 
 var (
 	// ErrNoPeerBucket is returned when we try to read entries for a peer
 	// that is not tracked.
 	ErrNoPeerBucket = errors.New("peer bucket not found")
+
+	// ErrNoPeerStorage is returned when we try to read the backup blob of
+	// a peer that never asked us to store one.
+	ErrNoPeerStorage = errors.New("no peer storage blob found")
 )
 
 // FlapCount contains information about a peer's flap count.
@@ -121,3 +132,94 @@ func (d *DB) ReadFlapCount(pubkey route.Vertex) (*FlapCount, error) {
 
 	return &flapCount, nil
 }
+
+// StorePeerStorage stores the backup blob a peer asked us to hold on its
+// behalf, creating a bucket for the peer's pubkey if necessary. Any blob
+// previously stored for the peer is replaced. An empty blob removes the stored
+// blob.
+func (d *DB) StorePeerStorage(pubkey route.Vertex, blob []byte) error {
+	return kvdb.Update(d, func(tx kvdb.RwTx) error {
+		peers := tx.ReadWriteBucket(peersBucket)
+
+		peerBucket, err := peers.CreateBucketIfNotExists(pubkey[:])
+		if err != nil {
+			return err
+		}
+
+		if len(blob) == 0 {
+			return peerBucket.Delete(peerStorageKey)
+		}
+
+		return peerBucket.Put(peerStorageKey, blob)
+	}, func() {})
+}
+
+// FetchPeerStorage returns the latest backup blob a peer asked us to hold on
+// its behalf, failing with ErrNoPeerStorage if we don't hold a blob for the
+// peer.
+func (d *DB) FetchPeerStorage(pubkey route.Vertex) ([]byte, error) {
+	var blob []byte
+
+	if err := kvdb.View(d, func(tx kvdb.RTx) error {
+		peers := tx.ReadBucket(peersBucket)
+
+		peerBucket := peers.NestedReadBucket(pubkey[:])
+		if peerBucket == nil {
+			return ErrNoPeerStorage
+		}
+
+		blobBytes := peerBucket.Get(peerStorageKey)
+		if blobBytes == nil {
+			return ErrNoPeerStorage
+		}
+
+		// The returned bytes are only valid for the duration of the
+		// transaction, so we copy them.
+		blob = make([]byte, len(blobBytes))
+		copy(blob, blobBytes)
+
+		return nil
+	}, func() {
+		blob = nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return blob, nil
+}
+
+// FetchPeerStoragePeers returns the public keys of all peers we hold a backup
+// blob for.
+func (d *DB) FetchPeerStoragePeers() ([]route.Vertex, error) {
+	var peers []route.Vertex
+
+	if err := kvdb.View(d, func(tx kvdb.RTx) error {
+		peersBkt := tx.ReadBucket(peersBucket)
+
+		return peersBkt.ForEach(func(k, v []byte) error {
+			// All peers are stored in nested buckets.
+			if v != nil {
+				return nil
+			}
+
+			peerBucket := peersBkt.NestedReadBucket(k)
+			if peerBucket.Get(peerStorageKey) == nil {
+				return nil
+			}
+
+			peer, err := route.NewVertexFromBytes(k)
+			if err != nil {
+				return err
+			}
+			peers = append(peers, peer)
+
+			return nil
+		})
+	}, func() {
+		peers = nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return peers, nil
+}
@@ -48,3 +48,67 @@ func TestFlapCount(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, peer2FlapCount, count)
 }
+
+// TestPeerStorage tests storing, replacing and removing the backup blob of a
+// peer.
+func TestPeerStorage(t *testing.T) {
+	db, cleanup, err := MakeTestDB()
+	require.NoError(t, err)
+	defer cleanup()
+
+	// Try to read the blob of a peer that we have no records for.
+	_, err = db.FetchPeerStorage(testPub)
+	require.Equal(t, ErrNoPeerStorage, err)
+
+	// Store a blob for the peer and read it back.
+	blob := []byte{1, 2, 3}
+	require.NoError(t, db.StorePeerStorage(testPub, blob))
+
+	stored, err := db.FetchPeerStorage(testPub)
+	require.NoError(t, err)
+	require.Equal(t, blob, stored)
+
+	// A new blob replaces the one we stored before.
+	blob = []byte{4, 5, 6, 7}
+	require.NoError(t, db.StorePeerStorage(testPub, blob))
+
+	stored, err = db.FetchPeerStorage(testPub)
+	require.NoError(t, err)
+	require.Equal(t, blob, stored)
+
+	// Storing the blob must not interfere with the flap count stored in
+	// the same peer bucket.
+	flapCount := &FlapCount{
+		Count:    3,
+		LastFlap: time.Unix(100, 23),
+	}
+	err = db.WriteFlapCounts(map[route.Vertex]*FlapCount{
+		testPub:         flapCount,
+		route.Vertex{2}: flapCount,
+	})
+	require.NoError(t, err)
+
+	count, err := db.ReadFlapCount(testPub)
+	require.NoError(t, err)
+	require.Equal(t, flapCount, count)
+
+	stored, err = db.FetchPeerStorage(testPub)
+	require.NoError(t, err)
+	require.Equal(t, blob, stored)
+
+	// Only the peer we hold a blob for should be listed, not the one we
+	// merely track the flap count of.
+	peers, err := db.FetchPeerStoragePeers()
+	require.NoError(t, err)
+	require.Equal(t, []route.Vertex{testPub}, peers)
+
+	// Finally, an empty blob removes the stored blob.
+	require.NoError(t, db.StorePeerStorage(testPub, nil))
+
+	_, err = db.FetchPeerStorage(testPub)
+	require.Equal(t, ErrNoPeerStorage, err)
+
+	peers, err = db.FetchPeerStoragePeers()
+	require.NoError(t, err)
+	require.Empty(t, peers)
+}
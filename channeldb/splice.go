@@ -0,0 +1,713 @@
+package channeldb
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/brronsuite/broln/kvdb"
+	"github.com/brronsuite/broln/lnwire"
+	"github.com/brronsuite/broln/tlv"
+	"github.com/brronsuite/brond/bronec"
+	"github.com/brronsuite/brond/chaincfg/chainhash"
+	"github.com/brronsuite/brond/wire"
+	"github.com/brronsuite/bronutil"
+)
+
+var (
+	// pendingSpliceBucket is a sub-bucket of the channel bucket that
+	// stores the splices of the channel that have been signed by both
+	// parties, but aren't locked yet. Each splice is stored in a nested
+	// bucket keyed by the txid of its splice transaction, which also
+	// holds the revocation log of the commitments spending its funding
+	// output.
+	pendingSpliceBucket = []byte("pending-splice-bucket")
+
+	// spliceInfoKey stores the splice transaction and the parameters of
+	// a pending splice.
+	spliceInfoKey = []byte("splice-info-key")
+
+	// spliceLocalCommitKey stores our current commitment spending the
+	// funding output of a pending splice.
+	spliceLocalCommitKey = []byte("splice-local-commit-key")
+
+	// spliceRemoteCommitKey stores the current commitment of the remote
+	// party spending the funding output of a pending splice.
+	spliceRemoteCommitKey = []byte("splice-remote-commit-key")
+
+	// splicePendingCommitKey stores the commitment of the remote party
+	// spending the funding output of a pending splice that we signed,
+	// but that hasn't been revoked yet. It mirrors the commitment of the
+	// commit diff stored under commitDiffKey.
+	splicePendingCommitKey = []byte("splice-pending-commit-key")
+
+	// ErrNoPendingSplice is returned when a channel doesn't have a
+	// pending splice with the requested splice transaction.
+	ErrNoPendingSplice = fmt.Errorf("no pending splice found")
+)
+
+// PendingSplice is a splice of a channel that has been negotiated and signed
+// by both parties, but that isn't locked yet. Until one of the pending
+// splices of a channel is locked, every state of the channel is signed for
+// the current funding output and the funding outputs of all pending splices,
+// so whichever transaction confirms, the latest state can be published.
+type PendingSplice struct {
+	// SpliceTx is the fully signed splice transaction, which spends the
+	// current funding output of the channel.
+	SpliceTx *wire.MsgTx
+
+	// FundingOutpoint is the outpoint of the new funding output created
+	// by the splice transaction.
+	FundingOutpoint wire.OutPoint
+
+	// Capacity is the capacity of the channel after the splice.
+	Capacity bronutil.Amount
+
+	// LocalContribution is the amount we added to (or removed from if
+	// negative) the channel with the splice.
+	LocalContribution bronutil.Amount
+
+	// RemoteContribution is the amount the remote party added to (or
+	// removed from if negative) the channel with the splice.
+	RemoteContribution bronutil.Amount
+
+	// FeePerKw is the fee rate of the splice transaction, which any
+	// replacement of it must exceed.
+	FeePerKw bronutil.Amount
+
+	// IsInitiator is true if we initiated the splice.
+	IsInitiator bool
+
+	// PrevOutScripts are the pkScripts of the outputs spent by the
+	// splice transaction, in the order of its inputs. They're needed to
+	// watch the inputs of the splice transaction for double spends.
+	PrevOutScripts [][]byte
+
+	// LocalCommitment is our current commitment spending the new funding
+	// output.
+	LocalCommitment ChannelCommitment
+
+	// RemoteCommitment is the current commitment of the remote party
+	// spending the new funding output.
+	RemoteCommitment ChannelCommitment
+}
+
+// serializeSpliceInfo writes the transaction and the parameters of the
+// pending splice to the passed writer.
+func serializeSpliceInfo(w io.Writer, s *PendingSplice) error {
+	err := WriteElements(
+		w, s.SpliceTx, s.FundingOutpoint, s.Capacity,
+		s.LocalContribution, s.RemoteContribution, s.FeePerKw,
+		s.IsInitiator, uint16(len(s.PrevOutScripts)),
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, pkScript := range s.PrevOutScripts {
+		if err := WriteElement(w, pkScript); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deserializeSpliceInfo reads the transaction and the parameters of a
+// pending splice from the passed reader.
+func deserializeSpliceInfo(r io.Reader, s *PendingSplice) error {
+	var numScripts uint16
+	err := ReadElements(
+		r, &s.SpliceTx, &s.FundingOutpoint, &s.Capacity,
+		&s.LocalContribution, &s.RemoteContribution, &s.FeePerKw,
+		&s.IsInitiator, &numScripts,
+	)
+	if err != nil {
+		return err
+	}
+
+	if numScripts == 0 {
+		return nil
+	}
+
+	s.PrevOutScripts = make([][]byte, numScripts)
+	for i := range s.PrevOutScripts {
+		if err := ReadElement(r, &s.PrevOutScripts[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// putSpliceCommitment writes a commitment of a pending splice to its bucket
+// under the given key.
+func putSpliceCommitment(spliceBucket kvdb.RwBucket, key []byte,
+	c *ChannelCommitment) error {
+
+	var b bytes.Buffer
+	if err := serializeChanCommit(&b, c); err != nil {
+		return err
+	}
+
+	return spliceBucket.Put(key, b.Bytes())
+}
+
+// fetchSpliceCommitment reads the commitment stored under the given key from
+// the bucket of a pending splice.
+func fetchSpliceCommitment(spliceBucket kvdb.RBucket,
+	key []byte) (ChannelCommitment, error) {
+
+	commitBytes := spliceBucket.Get(key)
+	if commitBytes == nil {
+		return ChannelCommitment{}, ErrNoCommitmentsFound
+	}
+
+	return deserializeChanCommit(bytes.NewReader(commitBytes))
+}
+
+// fetchPendingSplice reads a pending splice from its bucket.
+func fetchPendingSplice(spliceBucket kvdb.RBucket) (*PendingSplice, error) {
+	infoBytes := spliceBucket.Get(spliceInfoKey)
+	if infoBytes == nil {
+		return nil, ErrNoPendingSplice
+	}
+
+	var (
+		s   PendingSplice
+		err error
+	)
+	err = deserializeSpliceInfo(bytes.NewReader(infoBytes), &s)
+	if err != nil {
+		return nil, err
+	}
+
+	s.LocalCommitment, err = fetchSpliceCommitment(
+		spliceBucket, spliceLocalCommitKey,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	s.RemoteCommitment, err = fetchSpliceCommitment(
+		spliceBucket, spliceRemoteCommitKey,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// fetchPendingSplices reads all pending splices from the channel's bucket.
+func fetchPendingSplices(chanBucket kvdb.RBucket) ([]*PendingSplice, error) {
+	splicesBucket := chanBucket.NestedReadBucket(pendingSpliceBucket)
+	if splicesBucket == nil {
+		return nil, nil
+	}
+
+	var splices []*PendingSplice
+	err := splicesBucket.ForEach(func(txid, v []byte) error {
+		// Only nested buckets are stored within the bucket.
+		if v != nil {
+			return nil
+		}
+
+		splice, err := fetchPendingSplice(
+			splicesBucket.NestedReadBucket(txid),
+		)
+		if err != nil {
+			return err
+		}
+		splices = append(splices, splice)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return splices, nil
+}
+
+// putSpliceCommitments writes the passed commitments of the pending splices
+// of the channel, keyed by the txid of their splice transaction, under the
+// given key. All pending splices must have a commitment.
+func putSpliceCommitments(chanBucket kvdb.RwBucket, key []byte,
+	commits map[chainhash.Hash]*ChannelCommitment) error {
+
+	splicesBucket := chanBucket.NestedReadWriteBucket(pendingSpliceBucket)
+	if splicesBucket == nil {
+		if len(commits) != 0 {
+			return ErrNoPendingSplice
+		}
+
+		return nil
+	}
+
+	var numSplices int
+	err := splicesBucket.ForEach(func(txid, v []byte) error {
+		if v != nil {
+			return nil
+		}
+		numSplices++
+
+		spliceTxid, err := chainhash.NewHash(txid)
+		if err != nil {
+			return err
+		}
+		commit, ok := commits[*spliceTxid]
+		if !ok {
+			return fmt.Errorf("missing commitment for pending "+
+				"splice %v", spliceTxid)
+		}
+
+		return putSpliceCommitment(
+			splicesBucket.NestedReadWriteBucket(txid), key, commit,
+		)
+	})
+	if err != nil {
+		return err
+	}
+
+	if numSplices != len(commits) {
+		return ErrNoPendingSplice
+	}
+
+	return nil
+}
+
+// advanceSpliceCommitChainTails promotes the pending remote commitments of all
+// pending splices of the channel to their current remote commitments, adding
+// the revoked ones to the revocation logs of the splices.
+func advanceSpliceCommitChainTails(chanBucket kvdb.RwBucket) error {
+	splicesBucket := chanBucket.NestedReadWriteBucket(pendingSpliceBucket)
+	if splicesBucket == nil {
+		return nil
+	}
+
+	return splicesBucket.ForEach(func(txid, v []byte) error {
+		if v != nil {
+			return nil
+		}
+		spliceBucket := splicesBucket.NestedReadWriteBucket(txid)
+
+		// Splices that were added after we signed the pending remote
+		// commitment don't have one, and are already up to date.
+		pendingBytes := spliceBucket.Get(splicePendingCommitKey)
+		if pendingBytes == nil {
+			return nil
+		}
+
+		revokedCommit, err := fetchSpliceCommitment(
+			spliceBucket, spliceRemoteCommitKey,
+		)
+		if err != nil {
+			return err
+		}
+
+		logBucket, err := spliceBucket.CreateBucketIfNotExists(
+			revocationLogBucket,
+		)
+		if err != nil {
+			return err
+		}
+		err = appendChannelLogEntry(logBucket, &revokedCommit)
+		if err != nil {
+			return err
+		}
+
+		err = spliceBucket.Put(spliceRemoteCommitKey, pendingBytes)
+		if err != nil {
+			return err
+		}
+
+		return spliceBucket.Delete(splicePendingCommitKey)
+	})
+}
+
+// AddPendingSplice stores a splice that has been signed by both parties. From
+// then on, all commitment updates need to include the commitments spending
+// its funding output, until one of the pending splices of the channel is
+// locked with ApplySplice or they are removed with RemovePendingSplices.
+func (c *OpenChannel) AddPendingSplice(splice *PendingSplice) error {
+	c.Lock()
+	defer c.Unlock()
+
+	var b bytes.Buffer
+	if err := serializeSpliceInfo(&b, splice); err != nil {
+		return err
+	}
+
+	return kvdb.Update(c.Db.backend, func(tx kvdb.RwTx) error {
+		chanBucket, err := fetchChanBucketRw(
+			tx, c.IdentityPub, &c.FundingOutpoint, c.ChainHash,
+		)
+		if err != nil {
+			return err
+		}
+
+		// A splice can only be added while there is no pending
+		// commitment for the remote party, as it wouldn't have a
+		// commitment for it.
+		if chanBucket.Get(commitDiffKey) != nil {
+			return fmt.Errorf("unable to add splice with pending " +
+				"remote commitment")
+		}
+
+		splicesBucket, err := chanBucket.CreateBucketIfNotExists(
+			pendingSpliceBucket,
+		)
+		if err != nil {
+			return err
+		}
+
+		txid := splice.SpliceTx.TxHash()
+		spliceBucket, err := splicesBucket.CreateBucket(txid[:])
+		if err != nil {
+			return err
+		}
+
+		err = spliceBucket.Put(spliceInfoKey, b.Bytes())
+		if err != nil {
+			return err
+		}
+
+		err = putSpliceCommitment(
+			spliceBucket, spliceLocalCommitKey,
+			&splice.LocalCommitment,
+		)
+		if err != nil {
+			return err
+		}
+
+		return putSpliceCommitment(
+			spliceBucket, spliceRemoteCommitKey,
+			&splice.RemoteCommitment,
+		)
+	}, func() {})
+}
+
+// PendingSplices returns the splices of the channel that have been signed by
+// both parties, but aren't locked yet. If there are none, a nil slice is
+// returned.
+func (c *OpenChannel) PendingSplices() ([]*PendingSplice, error) {
+	c.RLock()
+	defer c.RUnlock()
+
+	var splices []*PendingSplice
+	err := kvdb.View(c.Db.backend, func(tx kvdb.RTx) error {
+		chanBucket, err := fetchChanBucket(
+			tx, c.IdentityPub, &c.FundingOutpoint, c.ChainHash,
+		)
+		switch err {
+		case nil:
+		case ErrNoChanDBExists, ErrNoActiveChannels, ErrChannelNotFound:
+			return nil
+		default:
+			return err
+		}
+
+		splices, err = fetchPendingSplices(chanBucket)
+		return err
+	}, func() {
+		splices = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return splices, nil
+}
+
+// RemovePendingSplices removes all pending splices of the channel, which is
+// done once the transactions of all of them have been double spent.
+func (c *OpenChannel) RemovePendingSplices() error {
+	c.Lock()
+	defer c.Unlock()
+
+	return kvdb.Update(c.Db.backend, func(tx kvdb.RwTx) error {
+		chanBucket, err := fetchChanBucketRw(
+			tx, c.IdentityPub, &c.FundingOutpoint, c.ChainHash,
+		)
+		if err != nil {
+			return err
+		}
+
+		if chanBucket.NestedReadBucket(pendingSpliceBucket) == nil {
+			return nil
+		}
+
+		return chanBucket.DeleteNestedBucket(pendingSpliceBucket)
+	}, func() {})
+}
+
+// ApplySplice locks the pending splice with the given splice transaction,
+// which confirmed at the given location, moving the channel onto its funding
+// output. The channel is stored under its new outpoint, the commitments and
+// revocation log of the splice replace the current ones, and all other
+// pending splices are removed.
+func (c *OpenChannel) ApplySplice(spliceTxid chainhash.Hash,
+	confLoc lnwire.ShortChannelID) error {
+
+	c.Lock()
+	defer c.Unlock()
+
+	var channel *OpenChannel
+	err := kvdb.Update(c.Db.backend, func(tx kvdb.RwTx) error {
+		chainBucket, err := fetchChainBucketRw(
+			tx, c.IdentityPub, c.ChainHash,
+		)
+		if err != nil {
+			return err
+		}
+
+		var oldKey bytes.Buffer
+		if err := writeOutpoint(&oldKey, &c.FundingOutpoint); err != nil {
+			return err
+		}
+		oldBucket := chainBucket.NestedReadWriteBucket(oldKey.Bytes())
+		if oldBucket == nil {
+			return ErrChannelNotFound
+		}
+
+		splicesBucket := oldBucket.NestedReadBucket(pendingSpliceBucket)
+		if splicesBucket == nil {
+			return ErrNoPendingSplice
+		}
+		spliceBucket := splicesBucket.NestedReadBucket(spliceTxid[:])
+		if spliceBucket == nil {
+			return ErrNoPendingSplice
+		}
+		splice, err := fetchPendingSplice(spliceBucket)
+		if err != nil {
+			return err
+		}
+
+		channel, err = fetchOpenChannel(oldBucket, &c.FundingOutpoint)
+		if err != nil {
+			return err
+		}
+
+		// The forwarding packages are keyed by the short channel ID,
+		// which may change below. A splice is only locked while the
+		// channel is quiescent without any HTLCs, unless the channel
+		// is being closed on the splice's funding output, so they
+		// don't contain any unresolved HTLCs and can be removed.
+		if err := channel.Packager.Wipe(tx); err != nil {
+			return err
+		}
+
+		channel.FundingOutpoint = splice.FundingOutpoint
+		channel.Capacity = splice.Capacity
+		channel.LocalCommitment = splice.LocalCommitment
+		channel.RemoteCommitment = splice.RemoteCommitment
+
+		// Channels with an alias keep using it as their short channel
+		// ID, so only their confirmed short channel ID changes.
+		if channel.ChanType.HasScidAliasChan() {
+			channel.confirmedScid = confLoc
+		} else {
+			channel.ShortChannelID = confLoc
+		}
+		channel.Packager = NewChannelPackager(channel.ShortChannelID)
+
+		// Move the channel data to the bucket of the new outpoint.
+		var newKey bytes.Buffer
+		err = writeOutpoint(&newKey, &channel.FundingOutpoint)
+		if err != nil {
+			return err
+		}
+		newBucket, err := chainBucket.CreateBucket(newKey.Bytes())
+		if err != nil {
+			return err
+		}
+		if err := copyBucket(newBucket, oldBucket); err != nil {
+			return err
+		}
+		err = newBucket.DeleteNestedBucket(pendingSpliceBucket)
+		if err != nil {
+			return err
+		}
+
+		// The revoked states of the old funding output can't be
+		// published anymore, so the revocation log is replaced with
+		// the one of the splice.
+		if newBucket.NestedReadBucket(revocationLogBucket) != nil {
+			err := newBucket.DeleteNestedBucket(revocationLogBucket)
+			if err != nil {
+				return err
+			}
+		}
+		logBucket, err := newBucket.CreateBucket(revocationLogBucket)
+		if err != nil {
+			return err
+		}
+		spliceLog := spliceBucket.NestedReadBucket(revocationLogBucket)
+		if spliceLog != nil {
+			if err := copyBucket(logBucket, spliceLog); err != nil {
+				return err
+			}
+		}
+
+		// If we signed a commitment the remote party hasn't revoked
+		// their prior one for yet, the pending commitment spending the
+		// new funding output replaces it, so it can be retransmitted.
+		if diffBytes := newBucket.Get(commitDiffKey); diffBytes != nil {
+			err := applySpliceCommitDiff(
+				newBucket, spliceBucket, diffBytes,
+				splice.FundingOutpoint,
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := putOpenChannel(newBucket, channel); err != nil {
+			return err
+		}
+
+		err = chainBucket.DeleteNestedBucket(oldKey.Bytes())
+		if err != nil {
+			return err
+		}
+
+		// Finally, the outpoint index needs to reflect that the old
+		// funding output has been spent while the new one is open.
+		opBucket := tx.ReadWriteBucket(outpointBucket)
+		if opBucket.Get(oldKey.Bytes()) == nil {
+			return ErrMissingIndexEntry
+		}
+		err = putOutpointStatus(opBucket, oldKey.Bytes(), outpointClosed)
+		if err != nil {
+			return err
+		}
+
+		return putOutpointStatus(opBucket, newKey.Bytes(), outpointOpen)
+	}, func() {
+		channel = nil
+	})
+	if err != nil {
+		return err
+	}
+
+	c.FundingOutpoint = channel.FundingOutpoint
+	c.Capacity = channel.Capacity
+	c.LocalCommitment = channel.LocalCommitment
+	c.RemoteCommitment = channel.RemoteCommitment
+	c.ShortChannelID = channel.ShortChannelID
+	c.confirmedScid = channel.confirmedScid
+	c.Packager = channel.Packager
+
+	return nil
+}
+
+// applySpliceCommitDiff replaces the commitment and signatures of the commit
+// diff stored in the channel's bucket with the pending commitment spending
+// the funding output of the given splice.
+func applySpliceCommitDiff(chanBucket kvdb.RwBucket,
+	spliceBucket kvdb.RBucket, diffBytes []byte,
+	fundingOutpoint wire.OutPoint) error {
+
+	diff, err := deserializeCommitDiff(bytes.NewReader(diffBytes))
+	if err != nil {
+		return err
+	}
+
+	diff.Commitment, err = fetchSpliceCommitment(
+		spliceBucket, splicePendingCommitKey,
+	)
+	if err != nil {
+		return err
+	}
+
+	spliceTxid := fundingOutpoint.Hash
+	var spliceSig *lnwire.SpliceSig
+	for i := range diff.CommitSig.SpliceSigs {
+		if diff.CommitSig.SpliceSigs[i].FundingTxID == spliceTxid {
+			spliceSig = &diff.CommitSig.SpliceSigs[i]
+			break
+		}
+	}
+	if spliceSig == nil {
+		return fmt.Errorf("missing signature for pending splice %v",
+			spliceTxid)
+	}
+
+	diff.CommitSig = &lnwire.CommitSig{
+		ChanID:    lnwire.NewChanIDFromOutPoint(&fundingOutpoint),
+		CommitSig: spliceSig.CommitSig,
+		HtlcSigs:  spliceSig.HtlcSigs,
+		ExtraData: make([]byte, 0),
+	}
+
+	var b bytes.Buffer
+	if err := serializeCommitDiff(&b, diff); err != nil {
+		return err
+	}
+
+	return chanBucket.Put(commitDiffKey, b.Bytes())
+}
+
+// fetchChainBucketRw returns the writeable bucket that stores all channels
+// with the given node on the given chain.
+func fetchChainBucketRw(tx kvdb.RwTx, nodeKey *bronec.PublicKey,
+	chainHash chainhash.Hash) (kvdb.RwBucket, error) {
+
+	openChanBucket := tx.ReadWriteBucket(openChannelBucket)
+	if openChanBucket == nil {
+		return nil, ErrNoChanDBExists
+	}
+
+	nodePub := nodeKey.SerializeCompressed()
+	nodeChanBucket := openChanBucket.NestedReadWriteBucket(nodePub)
+	if nodeChanBucket == nil {
+		return nil, ErrNoActiveChannels
+	}
+
+	chainBucket := nodeChanBucket.NestedReadWriteBucket(chainHash[:])
+	if chainBucket == nil {
+		return nil, ErrNoActiveChannels
+	}
+
+	return chainBucket, nil
+}
+
+// putOutpointStatus writes the status of an outpoint to the outpoint index.
+func putOutpointStatus(opBucket kvdb.RwBucket, chanPoint []byte,
+	s indexStatus) error {
+
+	status := uint8(s)
+
+	// Write the status of this outpoint as the first entry in a tlv
+	// stream.
+	statusRecord := tlv.MakePrimitiveRecord(indexStatusType, &status)
+	opStream, err := tlv.NewStream(statusRecord)
+	if err != nil {
+		return err
+	}
+
+	var b bytes.Buffer
+	if err := opStream.Encode(&b); err != nil {
+		return err
+	}
+
+	return opBucket.Put(chanPoint, b.Bytes())
+}
+
+// copyBucket recursively copies all keys and nested buckets of src into dst.
+func copyBucket(dst kvdb.RwBucket, src kvdb.RBucket) error {
+	return src.ForEach(func(k, v []byte) error {
+		// A nil value indicates a nested bucket.
+		if v != nil {
+			return dst.Put(k, v)
+		}
+
+		nestedDst, err := dst.CreateBucket(k)
+		if err != nil {
+			return err
+		}
+
+		return copyBucket(nestedDst, src.NestedReadBucket(k))
+	})
+}
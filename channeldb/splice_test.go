@@ -0,0 +1,240 @@
+package channeldb
+
+import (
+	"testing"
+
+	"github.com/brronsuite/broln/lnwire"
+	"github.com/brronsuite/brond/chaincfg/chainhash"
+	"github.com/brronsuite/brond/wire"
+	"github.com/brronsuite/bronutil"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestSplice creates a pending splice of the channel that adds the given
+// amount to our side, spending the current funding output.
+func newTestSplice(state *OpenChannel, amt bronutil.Amount,
+	feePerKw bronutil.Amount) *PendingSplice {
+
+	spliceTx := wire.NewMsgTx(2)
+	spliceTx.AddTxIn(&wire.TxIn{PreviousOutPoint: state.FundingOutpoint})
+	spliceTx.AddTxOut(&wire.TxOut{
+		Value:    int64(state.Capacity + amt),
+		PkScript: []byte{0x00, 0x20},
+	})
+
+	// Use the fee rate as lock time, so each splice transaction has a
+	// distinct txid.
+	spliceTx.LockTime = uint32(feePerKw)
+
+	localCommit := state.LocalCommitment
+	localCommit.LocalBalance += lnwire.NewMSatFromBroneess(amt)
+	remoteCommit := state.RemoteCommitment
+	remoteCommit.LocalBalance += lnwire.NewMSatFromBroneess(amt)
+
+	return &PendingSplice{
+		SpliceTx: spliceTx,
+		FundingOutpoint: wire.OutPoint{
+			Hash: spliceTx.TxHash(),
+		},
+		Capacity:          state.Capacity + amt,
+		LocalContribution: amt,
+		FeePerKw:          feePerKw,
+		IsInitiator:       true,
+		PrevOutScripts:    [][]byte{{0x00, 0x20}},
+		LocalCommitment:   localCommit,
+		RemoteCommitment:  remoteCommit,
+	}
+}
+
+// TestApplySplice tests that pending splices can be stored and updated along
+// with the channel state, and that locking one of them moves the channel onto
+// its funding output.
+func TestApplySplice(t *testing.T) {
+	t.Parallel()
+
+	fullDB, cleanUp, err := MakeTestDB()
+	require.NoError(t, err)
+	defer cleanUp()
+
+	cdb := fullDB.ChannelStateDB()
+	state := createTestChannel(t, cdb, openChannelOption())
+	oldChanPoint := state.FundingOutpoint
+
+	splices, err := state.PendingSplices()
+	require.NoError(t, err)
+	require.Empty(t, splices)
+
+	// Splice in funds on our side, then replace the splice transaction
+	// with one paying a higher fee rate, which results in two pending
+	// splices.
+	const spliceAmt = 100_000
+	splice := newTestSplice(state, spliceAmt, 1000)
+	rbfSplice := newTestSplice(state, spliceAmt, 2000)
+	require.NoError(t, state.AddPendingSplice(splice))
+	require.NoError(t, state.AddPendingSplice(rbfSplice))
+
+	splices, err = state.PendingSplices()
+	require.NoError(t, err)
+	require.Len(t, splices, 2)
+
+	spliceTxid := splice.SpliceTx.TxHash()
+	dbSplices := make(map[chainhash.Hash]*PendingSplice)
+	for _, s := range splices {
+		dbSplices[s.SpliceTx.TxHash()] = s
+	}
+	dbSplice := dbSplices[spliceTxid]
+	require.NotNil(t, dbSplice)
+	require.Equal(t, splice.FundingOutpoint, dbSplice.FundingOutpoint)
+	require.Equal(t, splice.Capacity, dbSplice.Capacity)
+	require.Equal(t, splice.LocalContribution, dbSplice.LocalContribution)
+	require.Equal(t, splice.FeePerKw, dbSplice.FeePerKw)
+	require.True(t, dbSplice.IsInitiator)
+	require.Equal(t, splice.PrevOutScripts, dbSplice.PrevOutScripts)
+	require.Equal(
+		t, splice.LocalCommitment.LocalBalance,
+		dbSplice.LocalCommitment.LocalBalance,
+	)
+
+	// Updating our commitment requires a commitment for every pending
+	// splice.
+	localCommit := state.LocalCommitment
+	localCommit.CommitHeight++
+	spliceLocalCommit := splice.LocalCommitment
+	spliceLocalCommit.CommitHeight++
+	rbfLocalCommit := rbfSplice.LocalCommitment
+	rbfLocalCommit.CommitHeight++
+
+	err = state.UpdateCommitment(
+		&localCommit, nil, map[chainhash.Hash]*ChannelCommitment{
+			spliceTxid: &spliceLocalCommit,
+		},
+	)
+	require.Error(t, err)
+
+	err = state.UpdateCommitment(
+		&localCommit, nil, map[chainhash.Hash]*ChannelCommitment{
+			spliceTxid:                  &spliceLocalCommit,
+			rbfSplice.SpliceTx.TxHash(): &rbfLocalCommit,
+		},
+	)
+	require.NoError(t, err)
+
+	// Extend the commitment chain of the remote party, then advance it,
+	// which revokes the remote commitments of the splices as well.
+	remoteCommit := state.RemoteCommitment
+	remoteCommit.CommitHeight++
+	spliceRemoteCommit := splice.RemoteCommitment
+	spliceRemoteCommit.CommitHeight++
+	rbfRemoteCommit := rbfSplice.RemoteCommitment
+	rbfRemoteCommit.CommitHeight++
+
+	commitDiff := &CommitDiff{
+		Commitment: remoteCommit,
+		CommitSig: &lnwire.CommitSig{
+			ChanID:    lnwire.NewChanIDFromOutPoint(&oldChanPoint),
+			ExtraData: make([]byte, 0),
+			SpliceSigs: lnwire.SpliceSigs{
+				{FundingTxID: spliceTxid},
+				{FundingTxID: rbfSplice.SpliceTx.TxHash()},
+			},
+		},
+		LogUpdates: []LogUpdate{},
+		SpliceCommitments: map[chainhash.Hash]*ChannelCommitment{
+			spliceTxid:                  &spliceRemoteCommit,
+			rbfSplice.SpliceTx.TxHash(): &rbfRemoteCommit,
+		},
+	}
+	require.NoError(t, state.AppendRemoteCommitChain(commitDiff))
+
+	fwdPkg := NewFwdPkg(
+		state.ShortChanID(), remoteCommit.CommitHeight, nil, nil,
+	)
+	require.NoError(t, state.AdvanceCommitChainTail(fwdPkg, nil))
+
+	splices, err = state.PendingSplices()
+	require.NoError(t, err)
+	for _, s := range splices {
+		require.Equal(
+			t, localCommit.CommitHeight,
+			s.LocalCommitment.CommitHeight,
+		)
+		require.Equal(
+			t, remoteCommit.CommitHeight,
+			s.RemoteCommitment.CommitHeight,
+		)
+	}
+
+	// Once the first splice is locked, the channel is moved onto its
+	// funding output.
+	confLoc := lnwire.ShortChannelID{
+		BlockHeight: 200,
+		TxIndex:     3,
+	}
+	require.NoError(t, state.ApplySplice(spliceTxid, confLoc))
+	require.Equal(t, splice.FundingOutpoint, state.FundingOutpoint)
+	require.Equal(t, confLoc, state.ShortChanID())
+
+	_, err = cdb.FetchChannel(nil, oldChanPoint)
+	require.ErrorIs(t, err, ErrChannelNotFound)
+
+	dbChan, err := cdb.FetchChannel(nil, splice.FundingOutpoint)
+	require.NoError(t, err)
+	require.Equal(t, splice.Capacity, dbChan.Capacity)
+	require.Equal(t, confLoc, dbChan.ShortChanID())
+	require.Equal(
+		t, spliceLocalCommit.LocalBalance,
+		dbChan.LocalCommitment.LocalBalance,
+	)
+	require.Equal(
+		t, spliceRemoteCommit.LocalBalance,
+		dbChan.RemoteCommitment.LocalBalance,
+	)
+
+	// The revocation log now holds the revoked commitment spending the
+	// funding output of the splice.
+	revokedCommit, err := dbChan.FindPreviousState(
+		splice.RemoteCommitment.CommitHeight,
+	)
+	require.NoError(t, err)
+	require.Equal(
+		t, splice.RemoteCommitment.LocalBalance,
+		revokedCommit.LocalBalance,
+	)
+
+	// All pending splices have been removed along with the old bucket.
+	splices, err = dbChan.PendingSplices()
+	require.NoError(t, err)
+	require.Empty(t, splices)
+}
+
+// TestRemovePendingSplices tests that the pending splices of a channel can be
+// removed without affecting the channel.
+func TestRemovePendingSplices(t *testing.T) {
+	t.Parallel()
+
+	fullDB, cleanUp, err := MakeTestDB()
+	require.NoError(t, err)
+	defer cleanUp()
+
+	cdb := fullDB.ChannelStateDB()
+	state := createTestChannel(t, cdb, openChannelOption())
+
+	splice := newTestSplice(state, 100_000, 1000)
+	require.NoError(t, state.AddPendingSplice(splice))
+	require.NoError(t, state.RemovePendingSplices())
+
+	splices, err := state.PendingSplices()
+	require.NoError(t, err)
+	require.Empty(t, splices)
+
+	// The channel can be updated again without any splice commitments.
+	localCommit := state.LocalCommitment
+	localCommit.CommitHeight++
+	require.NoError(t, state.UpdateCommitment(&localCommit, nil, nil))
+
+	// Locking a removed splice fails.
+	err = state.ApplySplice(
+		splice.SpliceTx.TxHash(), lnwire.ShortChannelID{},
+	)
+	require.ErrorIs(t, err, ErrNoPendingSplice)
+}
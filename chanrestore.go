@@ -1,6 +1,7 @@
 package broln
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"net"
@@ -8,14 +9,25 @@ import (
 	"github.com/brronsuite/broln/chanbackup"
 	"github.com/brronsuite/broln/channeldb"
 	"github.com/brronsuite/broln/contractcourt"
+	"github.com/brronsuite/broln/input"
 	"github.com/brronsuite/broln/keychain"
+	"github.com/brronsuite/broln/labels"
 	"github.com/brronsuite/broln/lnwire"
 	"github.com/brronsuite/broln/shachain"
 	"github.com/brronsuite/brond/bronec"
 	"github.com/brronsuite/brond/chaincfg"
 	"github.com/brronsuite/brond/chaincfg/chainhash"
+	"github.com/brronsuite/brond/txscript"
+	"github.com/brronsuite/brond/wire"
 )
 
+// errBackupCommitHtlcs is returned if the commitment transaction carried within
+// a backup has HTLC outputs. A restored channel lacks the state needed to
+// resolve those outputs, so the funds in them would be lost if we broadcast
+// the commitment.
+var errBackupCommitHtlcs = errors.New("commitment transaction has HTLC " +
+	"outputs")
+
 const (
 	// mainnetSCBLaunchBlock is the approximate block height of the brocoin
 	// mainnet chain of the date when SCBs first were released in broln
@@ -39,6 +51,20 @@ type chanDBRestorer struct {
 	secretKeys keychain.SecretKeyRing
 
 	chainArb *contractcourt.ChainArbitrator
+
+	// forceClose indicates whether the commitment transactions carried
+	// within the backups should be broadcast once the channels are
+	// restored, rather than waiting for the remote party to close the
+	// channels through the data loss protection protocol.
+	forceClose bool
+
+	// signer is used to sign the commitment transactions carried within
+	// the backups. It is only needed if forceClose is set.
+	signer input.Signer
+
+	// publishTx broadcasts a transaction to the network. It is only needed
+	// if forceClose is set.
+	publishTx func(*wire.MsgTx, string) error
 }
 
 // openChannelShell maps the static channel back up into an open channel
@@ -155,6 +181,10 @@ func (c *chanDBRestorer) openChannelShell(backup chanbackup.Single) (
 		chanType |= channeldb.AnchorOutputsBit
 		chanType |= channeldb.SingleFunderTweaklessBit
 
+	// This version carries the channel type explicitly.
+	case chanbackup.CommitStateVersion:
+		chanType = backup.ChanType
+
 	default:
 		return nil, fmt.Errorf("unknown Single version: %v", err)
 	}
@@ -282,9 +312,126 @@ func (c *chanDBRestorer) RestoreChansFromSingles(backups ...chanbackup.Single) e
 		}
 	}
 
+	if !c.forceClose {
+		return nil
+	}
+
+	// If we were asked to force close the restored channels, we'll now
+	// broadcast the commitment transactions carried within the backups.
+	// The chain watchers we just started will then pick up the spends of
+	// the funding outputs and sweep our outputs once they mature.
+	for i, backup := range backups {
+		chanPoint := backup.FundingOutpoint
+		if backup.CommitState == nil {
+			ltndLog.Warnf("Backup of ChannelPoint(%v) carries no "+
+				"commitment transaction, waiting for remote "+
+				"party to close channel", chanPoint)
+			continue
+		}
+
+		err := c.publishBackupCommitment(backup, channelShells[i].Chan)
+		switch {
+		case errors.Is(err, errBackupCommitHtlcs):
+			ltndLog.Warnf("Commitment transaction of "+
+				"ChannelPoint(%v) carries HTLCs, waiting for "+
+				"remote party to close channel", chanPoint)
+
+		case err != nil:
+			ltndLog.Errorf("Unable to force close "+
+				"ChannelPoint(%v) from backup: %v", chanPoint,
+				err)
+		}
+	}
+
 	return nil
 }
 
+// publishBackupCommitment signs the commitment transaction carried within the
+// given backup and broadcasts it, which force closes the restored channel.
+//
+// NOTE: If the backup isn't the most recent one, the commitment transaction
+// has been revoked, and broadcasting it allows the remote party to sweep all
+// funds of the channel.
+func (c *chanDBRestorer) publishBackupCommitment(backup chanbackup.Single,
+	channel *channeldb.OpenChannel) error {
+
+	commitState := backup.CommitState
+
+	// The outputs of in-flight HTLCs can only be swept with the
+	// second-level transactions and preimages of the channel state, which
+	// a restored channel doesn't have. As the remote party could claim
+	// them, we leave the channel to the data loss protection protocol
+	// instead.
+	for _, htlc := range commitState.Htlcs {
+		if htlc.OutputIndex >= 0 {
+			return errBackupCommitHtlcs
+		}
+	}
+
+	commitTx := commitState.CommitTx.Copy()
+
+	// A commitment transaction spends the funding output as its only
+	// input.
+	if len(commitTx.TxIn) != 1 ||
+		commitTx.TxIn[0].PreviousOutPoint != channel.FundingOutpoint {
+
+		return fmt.Errorf("commitment transaction doesn't spend "+
+			"funding output %v", channel.FundingOutpoint)
+	}
+
+	theirSig, err := bronec.ParseDERSignature(
+		commitState.CommitSig, bronec.S256(),
+	)
+	if err != nil {
+		return err
+	}
+
+	// Our multi-sig key was re-derived when creating the channel shell, so
+	// we can now reconstruct the funding output and sign the commitment
+	// transaction.
+	ourKey := channel.LocalChanCfg.MultiSigKey.PubKey.SerializeCompressed()
+	theirKey := channel.RemoteChanCfg.MultiSigKey.PubKey.
+		SerializeCompressed()
+	multiSigScript, err := input.GenMultiSigScript(ourKey, theirKey)
+	if err != nil {
+		return err
+	}
+	fundingPkScript, err := input.WitnessScriptHash(multiSigScript)
+	if err != nil {
+		return err
+	}
+
+	signDesc := &input.SignDescriptor{
+		KeyDesc:       channel.LocalChanCfg.MultiSigKey,
+		WitnessScript: multiSigScript,
+		Output: &wire.TxOut{
+			PkScript: fundingPkScript,
+			Value:    int64(channel.Capacity),
+		},
+		HashType:   txscript.SigHashAll,
+		SigHashes:  input.NewTxSigHashesV0Only(commitTx),
+		InputIndex: 0,
+	}
+	ourSig, err := c.signer.SignOutputRaw(commitTx, signDesc)
+	if err != nil {
+		return err
+	}
+
+	commitTx.TxIn[0].Witness = input.SpendMultiSig(
+		multiSigScript, ourKey, ourSig, theirKey, theirSig,
+	)
+
+	ltndLog.Warnf("SCB Recovery: broadcasting commitment transaction %v "+
+		"at height %v for ChannelPoint(%v)", commitTx.TxHash(),
+		commitState.CommitHeight, channel.FundingOutpoint)
+
+	label := labels.MakeLabel(
+		labels.LabelTypeChannelClose, &channel.ShortChannelID,
+	)
+
+	return c.publishTx(commitTx, label)
+}
+
 // A compile-time constraint to ensure chanDBRestorer implements
 // chanbackup.ChannelRestorer.
 var _ chanbackup.ChannelRestorer = (*chanDBRestorer)(nil)
@@ -0,0 +1,114 @@
+package broln
+
+import (
+	"testing"
+
+	"github.com/brronsuite/broln/chanbackup"
+	"github.com/brronsuite/broln/channeldb"
+	"github.com/brronsuite/broln/input"
+	"github.com/brronsuite/broln/keychain"
+	"github.com/brronsuite/brond/bronec"
+	"github.com/brronsuite/brond/chaincfg/chainhash"
+	"github.com/brronsuite/brond/wire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPublishBackupCommitment tests that the commitment transaction carried
+// within a backup is only broadcast if it has no HTLC outputs, as a restored
+// channel is unable to resolve them.
+func TestPublishBackupCommitment(t *testing.T) {
+	t.Parallel()
+
+	ourKey, err := bronec.NewPrivateKey(bronec.S256())
+	require.NoError(t, err)
+	theirKey, err := bronec.NewPrivateKey(bronec.S256())
+	require.NoError(t, err)
+
+	theirSig, err := theirKey.Sign(chainhash.DoubleHashB([]byte("sig")))
+	require.NoError(t, err)
+
+	channel := &channeldb.OpenChannel{
+		FundingOutpoint: wire.OutPoint{Index: 1},
+		Capacity:        1_000_000,
+	}
+	channel.LocalChanCfg.MultiSigKey = keychain.KeyDescriptor{
+		PubKey: ourKey.PubKey(),
+	}
+	channel.RemoteChanCfg.MultiSigKey = keychain.KeyDescriptor{
+		PubKey: theirKey.PubKey(),
+	}
+
+	tests := []struct {
+		name      string
+		htlcs     []chanbackup.HTLC
+		published bool
+	}{
+		{
+			name:      "no htlcs",
+			published: true,
+		},
+		{
+			name: "dust htlc",
+			htlcs: []chanbackup.HTLC{
+				{Amt: 1_000, OutputIndex: -1},
+			},
+			published: true,
+		},
+		{
+			name: "in-flight htlc",
+			htlcs: []chanbackup.HTLC{
+				{Amt: 1_000, OutputIndex: -1},
+				{Amt: 100_000_000, OutputIndex: 1},
+			},
+			published: false,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var published *wire.MsgTx
+			restorer := &chanDBRestorer{
+				forceClose: true,
+				signer: &input.MockSigner{
+					Privkeys: []*bronec.PrivateKey{ourKey},
+				},
+				publishTx: func(tx *wire.MsgTx, _ string) error {
+					published = tx
+					return nil
+				},
+			}
+
+			commitTx := wire.NewMsgTx(2)
+			commitTx.AddTxIn(&wire.TxIn{
+				PreviousOutPoint: channel.FundingOutpoint,
+			})
+			commitTx.AddTxOut(&wire.TxOut{Value: 900_000})
+
+			backup := chanbackup.Single{
+				FundingOutpoint: channel.FundingOutpoint,
+				CommitState: &chanbackup.CommitState{
+					CommitHeight: 10,
+					CommitTx:     commitTx,
+					CommitSig:    theirSig.Serialize(),
+					Htlcs:        test.htlcs,
+				},
+			}
+
+			err := restorer.publishBackupCommitment(backup, channel)
+			if !test.published {
+				require.ErrorIs(t, err, errBackupCommitHtlcs)
+				require.Nil(t, published)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, published)
+			require.Equal(t, commitTx.TxHash(), published.TxHash())
+			require.Len(t, published.TxIn[0].Witness, 4)
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"github.com/brronsuite/broln/lnrpc"
+	"github.com/urfave/cli"
+)
+
+var backupSinkStatusCommand = cli.Command{
+	Name:     "backupsinkstatus",
+	Category: "Channels",
+	Usage:    "Show the upload status of the remote channel backup sinks.",
+	Description: `
+	Show the status of every remote sink that updated multi-channel backups
+	are pushed to, such as an S3-compatible bucket, a WebDAV server or a
+	hook executable. For each sink, the time of the last successful upload
+	and of the last upload attempt are shown as unix timestamps, along with
+	the error of the last attempt if it failed. A sink is pending if the
+	latest backup hasn't been uploaded to it yet.`,
+	Action: actionDecorator(backupSinkStatus),
+}
+
+func backupSinkStatus(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.GetBackupSinkStatus(
+		ctxc, &lnrpc.GetBackupSinkStatusRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
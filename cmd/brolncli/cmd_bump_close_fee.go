@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/brronsuite/broln/lnrpc"
+	"github.com/brronsuite/brond/chaincfg/chainhash"
+	"github.com/urfave/cli"
+)
+
+var bumpCloseFeeCommand = cli.Command{
+	Name:     "bumpclosefee",
+	Category: "Channels",
+	Usage:    "Bump the fee of a cooperative channel close.",
+	Description: `
+	Propose a new closing transaction for a channel that is being
+	cooperatively closed, paying a higher fee from our own channel output.
+	Once signed by the remote peer, the new closing transaction replaces
+	the current one in the mempool.
+
+	The fee rate is set via either the --conf_target or the --sat_per_vbyte
+	argument, and the resulting fee must exceed the fee of the current
+	closing transaction. Both we and the remote peer must support the RBF
+	cooperative close protocol, and the remote peer must be online.`,
+	ArgsUsage: "funding_txid [output_index]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "funding_txid",
+			Usage: "the txid of the channel's funding transaction",
+		},
+		cli.IntFlag{
+			Name: "output_index",
+			Usage: "the output index for the funding output of the funding " +
+				"transaction",
+		},
+		cli.Int64Flag{
+			Name: "conf_target",
+			Usage: "(optional) the number of blocks that the " +
+				"transaction *should* confirm in, will be " +
+				"used for fee estimation",
+		},
+		cli.Uint64Flag{
+			Name: "sat_per_vbyte",
+			Usage: "(optional) a manual fee expressed in " +
+				"sat/vbyte that should be used when crafting " +
+				"the transaction",
+		},
+	},
+	Action: actionDecorator(bumpCloseFee),
+}
+
+func bumpCloseFee(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	// Show command help if no arguments and flags were provided.
+	if ctx.NArg() == 0 && ctx.NumFlags() == 0 {
+		cli.ShowCommandHelp(ctx, "bumpclosefee")
+		return nil
+	}
+
+	channelPoint, err := parseChannelPoint(ctx)
+	if err != nil {
+		return err
+	}
+
+	if ctx.IsSet("conf_target") && ctx.IsSet("sat_per_vbyte") {
+		return fmt.Errorf("either conf_target or sat_per_vbyte should " +
+			"be set, but not both")
+	}
+
+	resp, err := client.BumpCloseFee(ctxc, &lnrpc.BumpCloseFeeRequest{
+		ChannelPoint: channelPoint,
+		TargetConf:   int32(ctx.Int64("conf_target")),
+		SatPerVbyte:  ctx.Uint64("sat_per_vbyte"),
+	})
+	if err != nil {
+		return err
+	}
+
+	txid, err := chainhash.NewHash(resp.ClosingTxid)
+	if err != nil {
+		return err
+	}
+
+	printJSON(struct {
+		ClosingTxid string `json:"closing_txid"`
+	}{
+		ClosingTxid: txid.String(),
+	})
+
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/brronsuite/broln/lnrpc"
 	"github.com/urfave/cli"
@@ -71,6 +72,12 @@ var addInvoiceCommand = cli.Command{
 			Usage: "creates an AMP invoice. If true, preimage " +
 				"should not be set.",
 		},
+		cli.BoolFlag{
+			Name: "blind",
+			Usage: "reach you through blinded paths over your " +
+				"private channels instead of routing hints, " +
+				"to not reveal the channels to the payer",
+		},
 	},
 	Action: actionDecorator(addInvoice),
 }
@@ -125,6 +132,7 @@ func addInvoice(ctx *cli.Context) error {
 		Expiry:          ctx.Int64("expiry"),
 		Private:         ctx.Bool("private"),
 		IsAmp:           ctx.Bool("amp"),
+		IsBlinded:       ctx.Bool("blind"),
 	}
 
 	resp, err := client.AddInvoice(ctxc, invoice)
@@ -231,6 +239,27 @@ var listInvoicesCommand = cli.Command{
 			Usage: "if set, invoices succeeding the " +
 				"index_offset will be returned",
 		},
+		cli.Uint64Flag{
+			Name: "creation_date_start",
+			Usage: "if set, only invoices created at or after " +
+				"this unix timestamp will be returned",
+		},
+		cli.Uint64Flag{
+			Name: "creation_date_end",
+			Usage: "if set, only invoices created at or before " +
+				"this unix timestamp will be returned",
+		},
+		cli.StringSliceFlag{
+			Name: "state",
+			Usage: "if set, only invoices in the given state " +
+				"(open, settled, canceled, accepted) will be " +
+				"returned, can be specified multiple times",
+		},
+		cli.StringFlag{
+			Name: "memo_filter",
+			Usage: "if set, only invoices whose memo contains " +
+				"the given string will be returned",
+		},
 	},
 	Action: actionDecorator(listInvoices),
 }
@@ -245,6 +274,21 @@ func listInvoices(ctx *cli.Context) error {
 		IndexOffset:    ctx.Uint64("index_offset"),
 		NumMaxInvoices: ctx.Uint64("max_invoices"),
 		Reversed:       !ctx.Bool("paginate-forwards"),
+
+		CreationDateStart: ctx.Uint64("creation_date_start"),
+		CreationDateEnd:   ctx.Uint64("creation_date_end"),
+		MemoFilter:        ctx.String("memo_filter"),
+	}
+
+	for _, stateStr := range ctx.StringSlice("state") {
+		state, ok := lnrpc.Invoice_InvoiceState_value[strings.ToUpper(
+			stateStr,
+		)]
+		if !ok {
+			return fmt.Errorf("unknown invoice state %v", stateStr)
+		}
+
+		req.States = append(req.States, lnrpc.Invoice_InvoiceState(state))
 	}
 
 	invoices, err := client.ListInvoices(ctxc, req)
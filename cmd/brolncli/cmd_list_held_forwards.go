@@ -0,0 +1,37 @@
+package main
+
+import (
+	"github.com/brronsuite/broln/lnrpc/routerrpc"
+	"github.com/urfave/cli"
+)
+
+var listHeldForwardsCommand = cli.Command{
+	Name:     "listheldforwards",
+	Category: "Payments",
+	Usage:    "List the htlcs held by an htlc interceptor.",
+	Description: `
+	List all htlcs that are held by an htlc interceptor, together with the
+	time since which they are held. Htlcs that were held before a restart
+	are listed as well, even if they weren't replayed to the interceptor
+	yet.
+	`,
+	Action: actionDecorator(listHeldForwards),
+}
+
+func listHeldForwards(ctx *cli.Context) error {
+	ctxc := getContext()
+	conn := getClientConn(ctx, false)
+	defer conn.Close()
+
+	client := routerrpc.NewRouterClient(conn)
+	resp, err := client.ListHeldForwards(
+		ctxc, &routerrpc.ListHeldForwardsRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/brronsuite/broln/lnrpc"
+	"github.com/brronsuite/broln/lnrpc/routerrpc"
+	"github.com/urfave/cli"
+)
+
+var offersCommand = cli.Command{
+	Name:     "offers",
+	Category: "Payments",
+	Usage:    "Create, inspect and pay BOLT 12 offers.",
+	Description: `
+	Offers are reusable payment codes. Payers request an invoice for an
+	offer from the issuer over onion messages, so onion messages must be
+	enabled to use offers.`,
+	Subcommands: []cli.Command{
+		createOfferCommand,
+		listOffersCommand,
+		disableOfferCommand,
+		decodeOfferCommand,
+		payOfferCommand,
+	},
+}
+
+var createOfferCommand = cli.Command{
+	Name:      "create",
+	Usage:     "Create a new offer.",
+	ArgsUsage: "description",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "description",
+			Usage: "a description of what the offer is for, " +
+				"required if an amount is set",
+		},
+		cli.Int64Flag{
+			Name: "amt",
+			Usage: "the amount per item in broneess, if not set " +
+				"the payer chooses the amount",
+		},
+		cli.Int64Flag{
+			Name: "amt_msat",
+			Usage: "the amount per item in millibroneess, if not " +
+				"set the payer chooses the amount",
+		},
+		cli.StringFlag{
+			Name:  "issuer",
+			Usage: "(optional) a human-readable name of the issuer",
+		},
+		cli.Int64Flag{
+			Name: "absolute_expiry",
+			Usage: "(optional) the unix timestamp in seconds " +
+				"after which the offer can't be paid anymore",
+		},
+		cli.BoolFlag{
+			Name:  "allow_quantity",
+			Usage: "allow payers to request several items at once",
+		},
+		cli.Uint64Flag{
+			Name: "quantity_max",
+			Usage: "the maximum number of items that can be " +
+				"requested at once, zero means no limit",
+		},
+	},
+	Action: actionDecorator(createOffer),
+}
+
+func createOffer(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	description := ctx.String("description")
+	if !ctx.IsSet("description") && ctx.Args().Present() {
+		description = ctx.Args().First()
+	}
+
+	if ctx.IsSet("amt") && ctx.IsSet("amt_msat") {
+		return fmt.Errorf("amt and amt_msat cannot both be set")
+	}
+
+	amtMsat := ctx.Int64("amt_msat")
+	if ctx.IsSet("amt") {
+		amtMsat = ctx.Int64("amt") * 1000
+	}
+
+	if ctx.IsSet("quantity_max") && !ctx.Bool("allow_quantity") {
+		return fmt.Errorf("quantity_max requires allow_quantity")
+	}
+
+	resp, err := client.AddOffer(ctxc, &lnrpc.AddOfferRequest{
+		AmtMsat:        amtMsat,
+		Description:    description,
+		Issuer:         ctx.String("issuer"),
+		AbsoluteExpiry: ctx.Int64("absolute_expiry"),
+		AllowQuantity:  ctx.Bool("allow_quantity"),
+		QuantityMax:    ctx.Uint64("quantity_max"),
+	})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var listOffersCommand = cli.Command{
+	Name:   "list",
+	Usage:  "List the offers created by this node.",
+	Action: actionDecorator(listOffers),
+}
+
+func listOffers(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ListOffers(ctxc, &lnrpc.ListOffersRequest{})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var disableOfferCommand = cli.Command{
+	Name:  "disable",
+	Usage: "Stop answering invoice requests for an offer.",
+	Description: `
+	Disable an offer created by this node. Invoice requests for the offer
+	are rejected afterwards, invoices that were already issued can still
+	be paid.`,
+	ArgsUsage: "offer_id",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "offer_id",
+			Usage: "the hex encoded id of the offer to disable",
+		},
+	},
+	Action: actionDecorator(disableOffer),
+}
+
+func disableOffer(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	var offerIDStr string
+	switch {
+	case ctx.IsSet("offer_id"):
+		offerIDStr = ctx.String("offer_id")
+	case ctx.Args().Present():
+		offerIDStr = ctx.Args().First()
+	default:
+		return fmt.Errorf("offer_id argument missing")
+	}
+
+	offerID, err := hex.DecodeString(offerIDStr)
+	if err != nil {
+		return fmt.Errorf("invalid offer id: %v", err)
+	}
+
+	resp, err := client.DisableOffer(ctxc, &lnrpc.DisableOfferRequest{
+		OfferId: offerID,
+	})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var decodeOfferCommand = cli.Command{
+	Name:      "decode",
+	Usage:     "Decode an offer.",
+	ArgsUsage: "offer",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "offer",
+			Usage: "the lno encoded offer",
+		},
+	},
+	Action: actionDecorator(decodeOffer),
+}
+
+func decodeOffer(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	offer, err := offerArg(ctx)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.DecodeOffer(ctxc, &lnrpc.DecodeOfferRequest{
+		Offer: offer,
+	})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var payOfferCommand = cli.Command{
+	Name:  "pay",
+	Usage: "Pay an offer over lightning.",
+	Description: `
+	Request an invoice for the offer from its issuer and pay it. If the
+	offer doesn't specify an amount, the amount must be set.`,
+	ArgsUsage: "offer",
+	Flags: append(paymentFlags(),
+		cli.StringFlag{
+			Name:  "offer",
+			Usage: "the lno encoded offer to pay",
+		},
+		cli.Int64Flag{
+			Name: "amt",
+			Usage: "(optional) number of broneess to pay, " +
+				"required if the offer has no amount",
+		},
+		cli.StringFlag{
+			Name: "payer_note",
+			Usage: "(optional) a note for the issuer that is " +
+				"included in the invoice request",
+		},
+	),
+	Action: actionDecorator(payOffer),
+}
+
+func payOffer(ctx *cli.Context) error {
+	offer, err := offerArg(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := &routerrpc.SendPaymentRequest{
+		Offer:             offer,
+		PayerNote:         ctx.String("payer_note"),
+		Amt:               ctx.Int64("amt"),
+		DestCustomRecords: make(map[uint64][]byte),
+	}
+
+	return sendPaymentRequest(ctx, req)
+}
+
+// offerArg returns the offer passed either as flag or as first argument.
+func offerArg(ctx *cli.Context) (string, error) {
+	switch {
+	case ctx.IsSet("offer"):
+		return ctx.String("offer"), nil
+	case ctx.Args().Present():
+		return ctx.Args().First(), nil
+	default:
+		return "", fmt.Errorf("offer argument missing")
+	}
+}
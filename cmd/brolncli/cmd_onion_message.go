@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/brronsuite/broln/lnrpc"
+	"github.com/urfave/cli"
+)
+
+var sendOnionCommand = cli.Command{
+	Name:     "sendonion",
+	Category: "Peers",
+	Usage:    "Send an onion message to a node.",
+	Description: `
+	Send an onion message through the given nodes. The first node must be a
+	connected peer, the last node is the destination of the message. The
+	message can't be sent to our own node.
+
+	The records delivered to the application of the destination are passed
+	as type=hexvalue pairs, the types must be at least 64:
+
+	    brolncli sendonion --node <peer> --node <dest> --record 101=cafe`,
+	Flags: []cli.Flag{
+		cli.StringSliceFlag{
+			Name: "node",
+			Usage: "the public key of a node on the path to the " +
+				"destination, can be specified multiple times",
+		},
+		cli.StringSliceFlag{
+			Name: "record",
+			Usage: "a record for the destination as " +
+				"type=hexvalue, can be specified multiple " +
+				"times",
+		},
+		cli.StringSliceFlag{
+			Name: "reply_node",
+			Usage: "the public key of a node on the reply path " +
+				"back to us, can be specified multiple times",
+		},
+		cli.StringFlag{
+			Name:  "reply_path_id",
+			Usage: "the hex encoded path id of the reply path",
+		},
+	},
+	Action: actionDecorator(sendOnion),
+}
+
+func sendOnion(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	nodePath, err := parseHexSlice(ctx.StringSlice("node"))
+	if err != nil {
+		return err
+	}
+	if len(nodePath) == 0 {
+		return fmt.Errorf("at least one node must be specified")
+	}
+
+	replyPath, err := parseHexSlice(ctx.StringSlice("reply_node"))
+	if err != nil {
+		return err
+	}
+
+	replyPathID, err := hex.DecodeString(ctx.String("reply_path_id"))
+	if err != nil {
+		return fmt.Errorf("invalid reply path id: %v", err)
+	}
+
+	records := make(map[uint64][]byte)
+	for _, record := range ctx.StringSlice("record") {
+		parts := strings.SplitN(record, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid record format: %v", record)
+		}
+
+		recordType, err := strconv.ParseUint(parts[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid record type: %v", err)
+		}
+
+		value, err := hex.DecodeString(parts[1])
+		if err != nil {
+			return fmt.Errorf("invalid record value: %v", err)
+		}
+
+		records[recordType] = value
+	}
+
+	_, err = client.SendOnionMessage(
+		ctxc,
+		&lnrpc.SendOnionMessageRequest{
+			NodePath:      nodePath,
+			CustomRecords: records,
+			ReplyPath:     replyPath,
+			ReplyPathId:   replyPathID,
+		},
+	)
+
+	return err
+}
+
+var subscribeOnionCommand = cli.Command{
+	Name:     "subscribeonion",
+	Category: "Peers",
+	Usage:    "Subscribe to onion messages addressed to our node.",
+	Action:   actionDecorator(subscribeOnion),
+}
+
+func subscribeOnion(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	stream, err := client.SubscribeOnionMessages(
+		ctxc,
+		&lnrpc.SubscribeOnionMessagesRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+
+		printRespJSON(msg)
+	}
+}
+
+// parseHexSlice decodes a slice of hex encoded strings.
+func parseHexSlice(values []string) ([][]byte, error) {
+	result := make([][]byte, len(values))
+	for i, value := range values {
+		b, err := hex.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hex value %v: %v",
+				value, err)
+		}
+		result[i] = b
+	}
+
+	return result, nil
+}
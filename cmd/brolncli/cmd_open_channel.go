@@ -210,6 +210,25 @@ var openChannelCommand = cli.Command{
 				"propose to the remote peer (%q, %q)",
 				channelTypeTweakless, channelTypeAnchors),
 		},
+		cli.BoolFlag{
+			Name: "zero_conf",
+			Usage: "(optional) whether a zero-conf channel open " +
+				"should be attempted. The channel must be " +
+				"private and will use the scid-alias " +
+				"channel type",
+		},
+		cli.BoolFlag{
+			Name: "scid_alias",
+			Usage: "(optional) whether an scid-alias channel " +
+				"type should be attempted. The channel must " +
+				"be private",
+		},
+		cli.BoolFlag{
+			Name: "dual_fund",
+			Usage: "(optional) whether a dual funded channel " +
+				"should be attempted, allowing the remote " +
+				"peer to contribute funds as well",
+		},
 	},
 	Action: actionDecorator(openChannel),
 }
@@ -249,6 +268,9 @@ func openChannel(ctx *cli.Context) error {
 		CloseAddress:               ctx.String("close_address"),
 		RemoteMaxValueInFlightMsat: ctx.Uint64("remote_max_value_in_flight_msat"),
 		MaxLocalCsv:                uint32(ctx.Uint64("max_local_csv")),
+		ZeroConf:                   ctx.Bool("zero_conf"),
+		ScidAlias:                  ctx.Bool("scid_alias"),
+		DualFund:                   ctx.Bool("dual_fund"),
 	}
 
 	switch {
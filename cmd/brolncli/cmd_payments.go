@@ -42,10 +42,11 @@ var (
 			"this payment",
 	}
 
-	lastHopFlag = cli.StringFlag{
+	lastHopFlag = cli.StringSliceFlag{
 		Name: "last_hop",
 		Usage: "pubkey of the last hop (penultimate node in the path) " +
-			"to route through for this payment",
+			"to route through for this payment; can be specified " +
+			"multiple times to allow any of the given nodes",
 	}
 
 	dataFlag = cli.StringFlag{
@@ -124,11 +125,35 @@ func paymentFlags() []cli.Flag {
 		},
 		cltvLimitFlag,
 		lastHopFlag,
-		cli.Uint64Flag{
+		cli.Int64SliceFlag{
 			Name: "outgoing_chan_id",
 			Usage: "short channel id of the outgoing channel to " +
-				"use for the first hop of the payment",
-			Value: 0,
+				"use for the first hop of the payment; can " +
+				"be specified multiple times to allow any " +
+				"of the given channels",
+		},
+		cli.Int64SliceFlag{
+			Name: "incoming_chan_id",
+			Usage: "short channel id of the channel to use for " +
+				"the last hop into the destination; can be " +
+				"specified multiple times to allow any " +
+				"of the given channels",
+		},
+		cli.StringSliceFlag{
+			Name: "exclude_node",
+			Usage: "pubkey of a node that must not be used by " +
+				"the payment; can be specified multiple times",
+		},
+		cli.Int64SliceFlag{
+			Name: "exclude_chan",
+			Usage: "short channel id of a channel that must not " +
+				"be used by the payment; can be specified " +
+				"multiple times",
+		},
+		cli.StringFlag{
+			Name: "trampoline_node",
+			Usage: "pubkey of a trampoline node that finds the " +
+				"route to the destination on our behalf",
 		},
 		cli.BoolFlag{
 			Name:  "force, f",
@@ -239,6 +264,21 @@ func confirmPayReq(resp *lnrpc.PayReq, amt, feeLimit int64) error {
 	return nil
 }
 
+func confirmOffer(resp *lnrpc.Offer, amt, feeLimit int64) error {
+	fmt.Printf("Offer ID: %v\n", resp.GetOfferId())
+	fmt.Printf("Description: %v\n", resp.GetDescription())
+	fmt.Printf("Issuer: %v\n", resp.GetIssuer())
+	fmt.Printf("Amount (in broneess): %v\n", amt)
+	fmt.Printf("Fee limit (in broneess): %v\n", feeLimit)
+
+	confirm := promptForConfirmation("Confirm payment (yes/no): ")
+	if !confirm {
+		return fmt.Errorf("payment not confirmed")
+	}
+
+	return nil
+}
+
 func parsePayAddr(ctx *cli.Context) ([]byte, error) {
 	var (
 		payAddr []byte
@@ -413,18 +453,43 @@ func sendPaymentRequest(ctx *cli.Context,
 	client := lnrpc.NewLightningClient(conn)
 	routerClient := routerrpc.NewRouterClient(conn)
 
-	outChan := ctx.Uint64("outgoing_chan_id")
-	if outChan != 0 {
-		req.OutgoingChanIds = []uint64{outChan}
+	for _, outChan := range ctx.Int64Slice("outgoing_chan_id") {
+		req.OutgoingChanIds = append(
+			req.OutgoingChanIds, uint64(outChan),
+		)
+	}
+	for _, inChan := range ctx.Int64Slice("incoming_chan_id") {
+		req.IncomingChanIds = append(
+			req.IncomingChanIds, uint64(inChan),
+		)
+	}
+	for _, excludedChan := range ctx.Int64Slice("exclude_chan") {
+		req.ExcludeChannels = append(
+			req.ExcludeChannels, uint64(excludedChan),
+		)
 	}
-	if ctx.IsSet(lastHopFlag.Name) {
-		lastHop, err := route.NewVertexFromStr(
-			ctx.String(lastHopFlag.Name),
+	for _, pubKey := range ctx.StringSlice(lastHopFlag.Name) {
+		lastHop, err := route.NewVertexFromStr(pubKey)
+		if err != nil {
+			return err
+		}
+		req.LastHopPubkeys = append(req.LastHopPubkeys, lastHop[:])
+	}
+	for _, pubKey := range ctx.StringSlice("exclude_node") {
+		excludedNode, err := route.NewVertexFromStr(pubKey)
+		if err != nil {
+			return err
+		}
+		req.ExcludeNodes = append(req.ExcludeNodes, excludedNode[:])
+	}
+	if ctx.IsSet("trampoline_node") {
+		trampolineNode, err := route.NewVertexFromStr(
+			ctx.String("trampoline_node"),
 		)
 		if err != nil {
 			return err
 		}
-		req.LastHopPubkey = lastHop[:]
+		req.TrampolineNode = trampolineNode[:]
 	}
 
 	req.CltvLimit = int32(ctx.Int(cltvLimitFlag.Name))
@@ -514,6 +579,32 @@ func sendPaymentRequest(ctx *cli.Context,
 				return err
 			}
 		}
+	} else if req.Offer != "" {
+		// Decode the offer to find out the amount.
+		decodeReq := &lnrpc.DecodeOfferRequest{Offer: req.Offer}
+		decodeResp, err := client.DecodeOffer(ctxc, decodeReq)
+		if err != nil {
+			return err
+		}
+
+		// If no amount is specified, the amount of the offer is
+		// paid.
+		amt := req.Amt
+		if amt == 0 {
+			amt = decodeResp.AmtMsat / 1000
+		}
+
+		feeLimit, err = retrieveFeeLimit(ctx, amt)
+		if err != nil {
+			return err
+		}
+
+		if !ctx.Bool("force") {
+			err := confirmOffer(decodeResp, amt, feeLimit)
+			if err != nil {
+				return err
+			}
+		}
 	} else {
 		var err error
 		feeLimit, err = retrieveFeeLimit(ctx, req.Amt)
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/brronsuite/broln/lnrpc"
+	"github.com/brronsuite/broln/lnrpc/routerrpc"
+	"github.com/urfave/cli"
+)
+
+var rebalanceChannelCommand = cli.Command{
+	Name:     "rebalancechannel",
+	Category: "Channels",
+	Usage:    "Move liquidity between two of our channels.",
+	Description: `
+	Move liquidity between two channels of our node by paying an invoice
+	of our own node. The payment leaves through the outgoing channel and
+	returns through the incoming channel, while the route in between is
+	found through path finding. The local balance of the outgoing channel
+	decreases by the amount plus fees, and the local balance of the
+	incoming channel increases by the amount.`,
+	ArgsUsage: "outgoing_chan_id incoming_chan_id amt",
+	Flags: []cli.Flag{
+		cli.Uint64Flag{
+			Name: "outgoing_chan_id",
+			Usage: "the short channel id of the channel through " +
+				"which the payment leaves our node",
+		},
+		cli.Uint64Flag{
+			Name: "incoming_chan_id",
+			Usage: "the short channel id of the channel through " +
+				"which the payment returns to our node",
+		},
+		cli.Int64Flag{
+			Name:  "amt",
+			Usage: "the number of broneess to rebalance",
+		},
+		cli.Int64Flag{
+			Name: "fee_limit",
+			Usage: "the maximum number of broneess that may be " +
+				"paid in fees for the rebalance",
+		},
+		cli.DurationFlag{
+			Name: "timeout",
+			Usage: "the maximum amount of time we should spend " +
+				"trying to rebalance, e.g. --timeout=1m",
+			Value: paymentTimeout,
+		},
+		cltvLimitFlag,
+		cli.UintFlag{
+			Name: "max_parts",
+			Usage: "the maximum number of partial payments that " +
+				"may be used",
+			Value: 1,
+		},
+		jsonFlag,
+	},
+	Action: actionDecorator(rebalanceChannel),
+}
+
+func rebalanceChannel(ctx *cli.Context) error {
+	ctxc := getContext()
+	args := ctx.Args()
+
+	if ctx.NArg() == 0 && ctx.NumFlags() == 0 {
+		_ = cli.ShowCommandHelp(ctx, "rebalancechannel")
+		return nil
+	}
+
+	req := &routerrpc.RebalanceChannelRequest{
+		FeeLimitSat: ctx.Int64("fee_limit"),
+		CltvLimit:   int32(ctx.Uint(cltvLimitFlag.Name)),
+		MaxParts:    uint32(ctx.Uint("max_parts")),
+	}
+
+	var err error
+	switch {
+	case ctx.IsSet("outgoing_chan_id"):
+		req.OutgoingChanId = ctx.Uint64("outgoing_chan_id")
+	case args.Present():
+		req.OutgoingChanId, err = strconv.ParseUint(
+			args.First(), 10, 64,
+		)
+		if err != nil {
+			return fmt.Errorf("unable to decode outgoing_chan_id: "+
+				"%v", err)
+		}
+		args = args.Tail()
+	default:
+		return errors.New("outgoing_chan_id argument missing")
+	}
+
+	switch {
+	case ctx.IsSet("incoming_chan_id"):
+		req.IncomingChanId = ctx.Uint64("incoming_chan_id")
+	case args.Present():
+		req.IncomingChanId, err = strconv.ParseUint(
+			args.First(), 10, 64,
+		)
+		if err != nil {
+			return fmt.Errorf("unable to decode incoming_chan_id: "+
+				"%v", err)
+		}
+		args = args.Tail()
+	default:
+		return errors.New("incoming_chan_id argument missing")
+	}
+
+	switch {
+	case ctx.IsSet("amt"):
+		req.Amt = ctx.Int64("amt")
+	case args.Present():
+		req.Amt, err = strconv.ParseInt(args.First(), 10, 64)
+		if err != nil {
+			return fmt.Errorf("unable to decode amt: %v", err)
+		}
+	default:
+		return errors.New("amt argument missing")
+	}
+
+	timeout := ctx.Duration("timeout")
+	if timeout <= 0 {
+		return errors.New("timeout must be greater than zero")
+	}
+	req.TimeoutSeconds = int32(timeout.Seconds())
+
+	printJSON := ctx.Bool(jsonFlag.Name)
+	req.NoInflightUpdates = printJSON
+
+	conn := getClientConn(ctx, false)
+	defer conn.Close()
+
+	client := lnrpc.NewLightningClient(conn)
+	routerClient := routerrpc.NewRouterClient(conn)
+
+	stream, err := routerClient.RebalanceChannel(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	finalState, err := printLivePayment(ctxc, stream, client, printJSON)
+	if err != nil {
+		return err
+	}
+
+	// A failed rebalance is passed up to main, so that we exit with a
+	// non-zero exit code.
+	if finalState.Status != lnrpc.Payment_SUCCEEDED {
+		return errors.New(finalState.Status.String())
+	}
+
+	return nil
+}
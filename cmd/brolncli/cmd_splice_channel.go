@@ -0,0 +1,202 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/brronsuite/broln/lnrpc"
+	"github.com/brronsuite/brond/chaincfg/chainhash"
+	"github.com/urfave/cli"
+)
+
+const userMsgSpliceSign = `The splice transaction was negotiated with the peer.
+Please sign the inputs of the funded PSBT in the following PSBT:
+%s
+
+!!! WARNING !!!
+DO NOT PUBLISH the finished transaction by yourself or with another tool.
+broln MUST publish it in the proper splice flow order OR THE FUNDS CAN BE LOST!
+
+Signed base64 encoded PSBT (or path to text file): `
+
+var spliceChannelCommand = cli.Command{
+	Name:     "splicechannel",
+	Category: "Channels",
+	Usage:    "Resize an existing channel on-chain without closing it.",
+	Description: `
+	Add funds to or remove funds from our side of an existing channel by
+	spending its funding output in a splice transaction that creates a new
+	funding output. The channel keeps its identity in the network and can't
+	be used to forward payments until the splice transaction confirms.
+
+	A positive --amt is funded from the wallet, or from the inputs of the
+	PSBT passed with --funded_psbt. In the latter case, the splice
+	transaction is printed once it's negotiated, and the inputs of the PSBT
+	must be signed and the signed PSBT pasted back to complete the splice.
+	A negative --amt sends the given amount from the channel to a new
+	wallet address.
+
+	The channel must not have any HTLCs in flight while being spliced.`,
+	ArgsUsage: "funding_txid [output_index]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "funding_txid",
+			Usage: "the txid of the channel's funding transaction",
+		},
+		cli.IntFlag{
+			Name: "output_index",
+			Usage: "the output index for the funding output of the funding " +
+				"transaction",
+		},
+		cli.Int64Flag{
+			Name: "amt",
+			Usage: "the amount in broneess to add to our side of " +
+				"the channel if positive, or to remove from it if " +
+				"negative",
+		},
+		cli.Int64Flag{
+			Name: "conf_target",
+			Usage: "(optional) the number of blocks that the " +
+				"transaction *should* confirm in, will be " +
+				"used for fee estimation",
+		},
+		cli.Uint64Flag{
+			Name: "sat_per_vbyte",
+			Usage: "(optional) a manual fee expressed in " +
+				"sat/vbyte that should be used when crafting " +
+				"the transaction",
+		},
+		cli.IntFlag{
+			Name: "min_confs",
+			Usage: "(optional) the minimum number of confirmations " +
+				"each one of your outputs used for the splice " +
+				"transaction must satisfy",
+			Value: defaultUtxoMinConf,
+		},
+		cli.StringFlag{
+			Name: "funded_psbt",
+			Usage: "(optional) a base64 encoded funded PSBT whose " +
+				"inputs and outputs are added to the splice " +
+				"transaction instead of using the wallet",
+		},
+		cli.BoolFlag{
+			Name:  "block",
+			Usage: "block until the splice transaction confirmed",
+		},
+	},
+	Action: actionDecorator(spliceChannel),
+}
+
+func spliceChannel(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	// Show command help if no arguments and flags were provided.
+	if ctx.NArg() == 0 && ctx.NumFlags() == 0 {
+		cli.ShowCommandHelp(ctx, "splicechannel")
+		return nil
+	}
+
+	channelPoint, err := parseChannelPoint(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !ctx.IsSet("amt") {
+		return fmt.Errorf("amt argument missing")
+	}
+
+	req := &lnrpc.SpliceChannelRequest{
+		ChannelPoint:   channelPoint,
+		RelativeAmount: ctx.Int64("amt"),
+		TargetConf:     int32(ctx.Int64("conf_target")),
+		SatPerVbyte:    ctx.Uint64("sat_per_vbyte"),
+		MinConfs:       int32(ctx.Int("min_confs")),
+	}
+
+	if ctx.IsSet("funded_psbt") {
+		req.FundedPsbt, err = base64.StdEncoding.DecodeString(
+			strings.TrimSpace(ctx.String("funded_psbt")),
+		)
+		if err != nil {
+			return fmt.Errorf("base64 decode failed: %v", err)
+		}
+	}
+
+	stream, err := client.SpliceChannel(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	quit := make(chan struct{})
+	defer close(quit)
+
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		switch update := resp.Update.(type) {
+		case *lnrpc.SpliceStatusUpdate_PsbtSign:
+			psbtBase64 := base64.StdEncoding.EncodeToString(
+				update.PsbtSign.Psbt,
+			)
+			fmt.Printf(userMsgSpliceSign, psbtBase64)
+
+			signedStr, err := readTerminalOrFile(quit)
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("reading from terminal or "+
+					"file failed: %v", err)
+			}
+			signedPsbt, err := base64.StdEncoding.DecodeString(
+				strings.TrimSpace(signedStr),
+			)
+			if err != nil {
+				return fmt.Errorf("base64 decode failed: %v",
+					err)
+			}
+
+			_, err = client.SpliceFinalizePsbt(
+				ctxc, &lnrpc.SpliceFinalizePsbtRequest{
+					ChannelPoint: channelPoint,
+					SignedPsbt:   signedPsbt,
+				},
+			)
+			if err != nil {
+				return fmt.Errorf("finalizing PSBT splice "+
+					"flow failed: %v", err)
+			}
+
+		case *lnrpc.SpliceStatusUpdate_SplicePending:
+			txid, err := chainhash.NewHash(
+				update.SplicePending.Txid,
+			)
+			if err != nil {
+				return err
+			}
+
+			printJSON(struct {
+				SpliceTxid string `json:"splice_txid"`
+			}{
+				SpliceTxid: txid.String(),
+			})
+
+			if !ctx.Bool("block") {
+				return nil
+			}
+
+		case *lnrpc.SpliceStatusUpdate_SpliceLocked:
+			printRespJSON(update.SpliceLocked)
+			return nil
+		}
+	}
+}
@@ -136,7 +136,8 @@ var newAddressCommand = cli.Command{
 	Description: `
 	Generate a wallet new address. Address-types has to be one of:
 	    - p2wkh:  Pay to witness key hash
-	    - np2wkh: Pay to nested witness key hash`,
+	    - np2wkh: Pay to nested witness key hash
+	    - p2tr:   Pay to taproot pubkey`,
 	Action: actionDecorator(newAddress),
 }
 
@@ -158,9 +159,11 @@ func newAddress(ctx *cli.Context) error {
 		addrType = lnrpc.AddressType_WITNESS_PUBKEY_HASH
 	case "np2wkh":
 		addrType = lnrpc.AddressType_NESTED_PUBKEY_HASH
+	case "p2tr":
+		addrType = lnrpc.AddressType_TAPROOT_PUBKEY
 	default:
 		return fmt.Errorf("invalid address type %v, support address type "+
-			"are: p2wkh and np2wkh", stringAddrType)
+			"are: p2wkh, np2wkh and p2tr", stringAddrType)
 	}
 
 	client, cleanUp := getClient(ctx)
@@ -851,7 +854,13 @@ func executeChannelClose(ctxc context.Context, client lnrpc.LightningClient,
 				return err
 			}
 
-			txidChan <- txid.String()
+			// The closing transaction of an RBF cooperative close
+			// may be replaced while we block, in which case the
+			// txid of the replacement isn't reported.
+			select {
+			case txidChan <- txid.String():
+			default:
+			}
 
 			if !block {
 				return nil
@@ -1981,6 +1990,23 @@ var updateChannelPolicyCommand = cli.Command{
 				"to all forwarded HTLCs. If unset, the max HTLC " +
 				"is left unchanged.",
 		},
+		cli.Int64Flag{
+			Name: "inbound_base_fee_msat",
+			Usage: "if set, the inbound base fee in " +
+				"milli-broneess that will be charged for " +
+				"each HTLC that arrives over the channel. " +
+				"Must not be positive, as the inbound fee " +
+				"is a discount on the outbound fee.",
+		},
+		cli.Int64Flag{
+			Name: "inbound_fee_rate_ppm",
+			Usage: "if set, the inbound fee rate ppm (parts " +
+				"per million) that will be charged " +
+				"proportionally based on the value of each " +
+				"HTLC that arrives over the channel. Must " +
+				"not be positive, as the inbound fee is a " +
+				"discount on the outbound fee.",
+		},
 		cli.StringFlag{
 			Name: "chan_point",
 			Usage: "The channel whose fee policy should be " +
@@ -2106,6 +2132,26 @@ func updateChannelPolicy(ctx *cli.Context) error {
 		req.MinHtlcMsatSpecified = true
 	}
 
+	// The inbound fee is only updated if one of its components is set.
+	if ctx.IsSet("inbound_base_fee_msat") ||
+		ctx.IsSet("inbound_fee_rate_ppm") {
+
+		inboundBaseFee := ctx.Int64("inbound_base_fee_msat")
+		if inboundBaseFee < math.MinInt32 || inboundBaseFee > 0 {
+			return fmt.Errorf("inbound_base_fee_msat out of range")
+		}
+
+		inboundFeeRate := ctx.Int64("inbound_fee_rate_ppm")
+		if inboundFeeRate < math.MinInt32 || inboundFeeRate > 0 {
+			return fmt.Errorf("inbound_fee_rate_ppm out of range")
+		}
+
+		req.InboundFee = &lnrpc.InboundFee{
+			BaseFeeMsat: int32(inboundBaseFee),
+			FeeRatePpm:  int32(inboundFeeRate),
+		}
+	}
+
 	if chanPoint != nil {
 		req.Scope = &lnrpc.PolicyUpdateRequest_ChanPoint{
 			ChanPoint: chanPoint,
@@ -2406,6 +2452,15 @@ var restoreChanBackupCommand = cli.Command{
 	   * A file path which points to a packed multi-channel backup within a
 	     file, using the same format that broln does in its channel.backup
 	     file.
+
+	If the --force_close flag is set, the commitment transactions carried
+	within backups created with the backupcommitstate option are broadcast
+	once the channels are restored, which recovers the funds without the
+	remote party. Commitment transactions carrying in-flight HTLCs aren't
+	broadcast, as their HTLCs can't be resolved by a restored channel. Only
+	use this flag with the most recent backup, as broadcasting a revoked
+	commitment transaction allows the remote party to sweep all funds of
+	the channel.
 	`,
 	Flags: []cli.Flag{
 		cli.StringFlag{
@@ -2422,6 +2477,12 @@ var restoreChanBackupCommand = cli.Command{
 			Name:  "multi_file",
 			Usage: "the path to a multi-channel back up file",
 		},
+		cli.BoolFlag{
+			Name: "force_close",
+			Usage: "broadcast the commitment transactions " +
+				"carried within the backups to force close " +
+				"the restored channels",
+		},
 	},
 	Action: actionDecorator(restoreChanBackup),
 }
@@ -2505,6 +2566,7 @@ func restoreChanBackup(ctx *cli.Context) error {
 	}
 
 	req.Backup = backups.Backup
+	req.ForceClose = ctx.Bool("force_close")
 
 	_, err = client.RestoreChannelBackups(ctxc, &req)
 	if err != nil {
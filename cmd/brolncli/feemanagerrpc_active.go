@@ -0,0 +1,97 @@
+//go:build feemanagerrpc
+// +build feemanagerrpc
+
+package main
+
+import (
+	"github.com/brronsuite/broln/lnrpc/feemanagerrpc"
+	"github.com/urfave/cli"
+)
+
+func getFeeManagerClient(ctx *cli.Context) (feemanagerrpc.FeeManagerClient,
+	func()) {
+
+	conn := getClientConn(ctx, false)
+
+	cleanUp := func() {
+		conn.Close()
+	}
+
+	return feemanagerrpc.NewFeeManagerClient(conn), cleanUp
+}
+
+var feeManagerStatusCommand = cli.Command{
+	Name:        "status",
+	Usage:       "Get the status of the fee manager.",
+	Description: "",
+	Action:      actionDecorator(feeManagerStatus),
+}
+
+func feeManagerStatus(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getFeeManagerClient(ctx)
+	defer cleanUp()
+
+	req := &feemanagerrpc.StatusRequest{}
+
+	resp, err := client.Status(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var adjustFeesCommand = cli.Command{
+	Name:  "adjust",
+	Usage: "Adjust the fee rates of all channels.",
+	Description: `
+	Determines the target fee rate of each channel from its local balance
+	and forwarding volume, and updates the policies of the channels for
+	which a new channel update is warranted. Use --dry_run to only report
+	the adjustments.`,
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name: "dry_run",
+			Usage: "only report the adjustments without " +
+				"updating any channel policies",
+		},
+	},
+	Action: actionDecorator(adjustFees),
+}
+
+func adjustFees(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getFeeManagerClient(ctx)
+	defer cleanUp()
+
+	req := &feemanagerrpc.AdjustFeesRequest{
+		DryRun: ctx.Bool("dry_run"),
+	}
+
+	resp, err := client.AdjustFees(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+// feeManagerCommands will return the set of commands to enable for
+// feemanagerrpc builds.
+func feeManagerCommands() []cli.Command {
+	return []cli.Command{
+		{
+			Name:        "feemanager",
+			Category:    "Channels",
+			Usage:       "Interact with the automatic fee manager.",
+			Description: "",
+			Subcommands: []cli.Command{
+				feeManagerStatusCommand,
+				adjustFeesCommand,
+			},
+		},
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !feemanagerrpc
+// +build !feemanagerrpc
+
+package main
+
+import "github.com/urfave/cli"
+
+// feeManagerCommands will return nil for non-feemanagerrpc builds.
+func feeManagerCommands() []cli.Command {
+	return nil
+}
@@ -191,6 +191,12 @@ var addHoldInvoiceCommand = cli.Command{
 				"private channels in order to assist the " +
 				"payer in reaching you",
 		},
+		cli.BoolFlag{
+			Name: "blind",
+			Usage: "reach you through blinded paths over your " +
+				"private channels instead of routing hints, " +
+				"to not reveal the channels to the payer",
+		},
 	},
 	Action: actionDecorator(addHoldInvoice),
 }
@@ -242,6 +248,7 @@ func addHoldInvoice(ctx *cli.Context) error {
 		FallbackAddr:    ctx.String("fallback_addr"),
 		Expiry:          ctx.Int64("expiry"),
 		Private:         ctx.Bool("private"),
+		IsBlinded:       ctx.Bool("blind"),
 	}
 
 	resp, err := client.AddHoldInvoice(ctxc, invoice)
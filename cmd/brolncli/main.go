@@ -340,6 +340,8 @@ func main() {
 		openChannelCommand,
 		batchOpenChannelCommand,
 		closeChannelCommand,
+		bumpCloseFeeCommand,
+		spliceChannelCommand,
 		closeAllChannelsCommand,
 		abandonChannelCommand,
 		listPeersCommand,
@@ -375,6 +377,7 @@ func main() {
 		exportChanBackupCommand,
 		verifyChanBackupCommand,
 		restoreChanBackupCommand,
+		backupSinkStatusCommand,
 		bakeMacaroonCommand,
 		listMacaroonIDsCommand,
 		deleteMacaroonIDCommand,
@@ -387,10 +390,14 @@ func main() {
 		deletePaymentsCommand,
 		sendCustomCommand,
 		subscribeCustomCommand,
+		sendOnionCommand,
+		subscribeOnionCommand,
+		offersCommand,
 	}
 
 	// Add any extra commands determined by build flags.
 	app.Commands = append(app.Commands, autopilotCommands()...)
+	app.Commands = append(app.Commands, feeManagerCommands()...)
 	app.Commands = append(app.Commands, invoicesCommands()...)
 	app.Commands = append(app.Commands, routerCommands()...)
 	app.Commands = append(app.Commands, walletCommands()...)
@@ -80,9 +80,11 @@ func parseAddrType(addrTypeStr string) (walletrpc.AddressType, error) {
 		return walletrpc.AddressType_NESTED_WITNESS_PUBKEY_HASH, nil
 	case "np2wkh-p2wkh":
 		return walletrpc.AddressType_HYBRID_NESTED_WITNESS_PUBKEY_HASH, nil
+	case "p2tr":
+		return walletrpc.AddressType_TAPROOT_PUBKEY, nil
 	default:
 		return 0, errors.New("invalid address type, supported address " +
-			"types are: p2wkh, np2wkh, and np2wkh-p2wkh")
+			"types are: p2wkh, np2wkh, np2wkh-p2wkh and p2tr")
 	}
 }
 
@@ -1088,7 +1090,7 @@ var importPubKeyCommand = cli.Command{
 	ArgsUsage: "public_key address_type",
 	Description: `
 	Imports a public key represented in hex as watch-only into the wallet.
-	The address type must be one of the following: np2wkh, p2wkh.
+	The address type must be one of the following: np2wkh, p2wkh, p2tr.
 
 	NOTE: Events (deposits/spends) for a key will only be detected by broln if
 	they happen after the import. Rescans to detect past events will be
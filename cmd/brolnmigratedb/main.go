@@ -0,0 +1,293 @@
+// brolnmigratedb is a standalone tool that copies all data of a stopped broln
+// node from one database backend into another, for example from the local
+// bbolt files into postgres.
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/brronsuite/broln/kvdb"
+	"github.com/brronsuite/broln/lncfg"
+	"github.com/brronsuite/bronutil"
+	flags "github.com/jessevdk/go-flags"
+)
+
+const (
+	// The bbolt file names broln uses for the different databases.
+	boltChannelDBName     = "channel.db"
+	boltMacaroonDBName    = "macaroons.db"
+	boltDecayedLogDBName  = "sphinxreplay.db"
+	boltTowerClientDBName = "wtclient.db"
+	boltTowerServerDBName = "watchtower.db"
+	boltWalletDBName      = "wallet.db"
+
+	defaultChain   = "brocoin"
+	defaultNetwork = "mainnet"
+)
+
+var (
+	defaultBrolnDir = bronutil.AppDataDir("broln", false)
+)
+
+// config holds the command line options of the migration tool.
+type config struct {
+	BrolnDir string `long:"brolndir" description:"The base directory of the broln node. Used to locate the local bbolt or sqlite database files."`
+	DataDir  string `long:"datadir" description:"The data directory of the broln node, defaults to <brolndir>/data."`
+	Chain    string `long:"chain" description:"The chain the node is running on."`
+	Network  string `long:"network" description:"The network the node is running on." choice:"mainnet" choice:"testnet" choice:"regtest" choice:"simnet" choice:"signet"`
+
+	BatchSize int `long:"batchsize" description:"The maximum number of write operations per destination transaction."`
+
+	Source *lncfg.DB `group:"source" namespace:"source" description:"The database to copy the data from."`
+	Dest   *lncfg.DB `group:"dest" namespace:"dest" description:"The database to copy the data into. It must be empty."`
+}
+
+// dbNamespace describes where the data of a single broln database is stored
+// for each of the backends.
+type dbNamespace struct {
+	// name is the namespace used by the remote backends and as table
+	// prefix by sqlite.
+	name string
+
+	// boltDir and boltFile locate the bbolt file of the namespace.
+	boltDir  string
+	boltFile string
+
+	// sqliteDir and sqliteFile locate the sqlite file of the namespace.
+	sqliteDir  string
+	sqliteFile string
+
+	// singleWriter is set for the wallet namespace which requires etcd to
+	// run in single writer mode.
+	singleWriter bool
+}
+
+// namespaces returns all database namespaces broln uses together with the
+// location of their local database files.
+func (c *config) namespaces() []dbNamespace {
+	network := lncfg.NormalizeNetwork(c.Network)
+	graphDir := filepath.Join(c.DataDir, "graph", network)
+	networkDir := filepath.Join(c.DataDir, "chain", c.Chain, network)
+	towerDir := filepath.Join(c.DataDir, "watchtower", c.Chain, network)
+
+	return []dbNamespace{{
+		name:       lncfg.NSChannelDB,
+		boltDir:    graphDir,
+		boltFile:   boltChannelDBName,
+		sqliteDir:  graphDir,
+		sqliteFile: lncfg.SqliteChannelDBName,
+	}, {
+		name:       lncfg.NSMacaroonDB,
+		boltDir:    networkDir,
+		boltFile:   boltMacaroonDBName,
+		sqliteDir:  networkDir,
+		sqliteFile: lncfg.SqliteChainDBName,
+	}, {
+		name:       lncfg.NSDecayedLogDB,
+		boltDir:    graphDir,
+		boltFile:   boltDecayedLogDBName,
+		sqliteDir:  graphDir,
+		sqliteFile: lncfg.SqliteChannelDBName,
+	}, {
+		name:       lncfg.NSTowerClientDB,
+		boltDir:    graphDir,
+		boltFile:   boltTowerClientDBName,
+		sqliteDir:  graphDir,
+		sqliteFile: lncfg.SqliteChannelDBName,
+	}, {
+		name:       lncfg.NSTowerServerDB,
+		boltDir:    towerDir,
+		boltFile:   boltTowerServerDBName,
+		sqliteDir:  towerDir,
+		sqliteFile: lncfg.SqliteTowerDBName,
+	}, {
+		name:         lncfg.NSWalletDB,
+		boltDir:      networkDir,
+		boltFile:     boltWalletDBName,
+		sqliteDir:    networkDir,
+		sqliteFile:   lncfg.SqliteChainDBName,
+		singleWriter: true,
+	}}
+}
+
+// localFileExists returns true if the namespace is stored in a local file for
+// the given backend config and that file exists. For remote backends it
+// always returns true.
+func localFileExists(cfg *lncfg.DB, ns dbNamespace) bool {
+	var path string
+	switch cfg.Backend {
+	case lncfg.BoltBackend:
+		path = filepath.Join(ns.boltDir, ns.boltFile)
+
+	case lncfg.SqliteBackend:
+		path = filepath.Join(ns.sqliteDir, ns.sqliteFile)
+
+	default:
+		return true
+	}
+
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// openBackend opens the database backend of the given namespace. Local
+// database files are created if they don't exist yet.
+func openBackend(ctx context.Context, cfg *lncfg.DB,
+	ns dbNamespace) (kvdb.Backend, error) {
+
+	switch cfg.Backend {
+	case lncfg.BoltBackend:
+		return kvdb.GetBoltBackend(&kvdb.BoltBackendConfig{
+			DBPath:         ns.boltDir,
+			DBFileName:     ns.boltFile,
+			DBTimeout:      cfg.Bolt.DBTimeout,
+			NoFreelistSync: cfg.Bolt.NoFreelistSync,
+		})
+
+	case lncfg.EtcdBackend:
+		etcdCfg := cfg.Etcd.CloneWithSubNamespace(ns.name)
+		if ns.singleWriter {
+			etcdCfg = etcdCfg.CloneWithSingleWriter()
+		}
+
+		return kvdb.Open(kvdb.EtcdBackendName, ctx, etcdCfg)
+
+	case lncfg.PostgresBackend:
+		return kvdb.Open(
+			kvdb.PostgresBackendName, ctx, cfg.Postgres, ns.name,
+		)
+
+	case lncfg.SqliteBackend:
+		return kvdb.Open(
+			kvdb.SqliteBackendName, ctx, cfg.Sqlite, ns.sqliteDir,
+			ns.sqliteFile, ns.name,
+		)
+
+	default:
+		return nil, fmt.Errorf("unknown backend %v", cfg.Backend)
+	}
+}
+
+// migrateNamespace copies the content of a single namespace from the source
+// to the destination backend and verifies the copy afterwards.
+func migrateNamespace(ctx context.Context, cfg *config,
+	ns dbNamespace) error {
+
+	// The tower databases only exist if the tower client or server was
+	// ever enabled, there's nothing to migrate otherwise.
+	if !localFileExists(cfg.Source, ns) {
+		fmt.Printf("Skipping %v, no source database found\n", ns.name)
+		return nil
+	}
+
+	src, err := openBackend(ctx, cfg.Source, ns)
+	if err != nil {
+		return fmt.Errorf("unable to open source DB: %v", err)
+	}
+	defer src.Close()
+
+	dst, err := openBackend(ctx, cfg.Dest, ns)
+	if err != nil {
+		return fmt.Errorf("unable to open destination DB: %v", err)
+	}
+	defer dst.Close()
+
+	fmt.Printf("Copying %v\n", ns.name)
+	if err := kvdb.CopyBackend(src, dst, cfg.BatchSize); err != nil {
+		return fmt.Errorf("unable to copy: %v", err)
+	}
+
+	fmt.Printf("Verifying %v\n", ns.name)
+	if err := kvdb.VerifyCopy(src, dst); err != nil {
+		return fmt.Errorf("verification failed: %v", err)
+	}
+
+	return nil
+}
+
+// validate makes sure the configuration is consistent.
+func (c *config) validate() error {
+	if c.DataDir == "" {
+		c.DataDir = filepath.Join(c.BrolnDir, "data")
+	}
+
+	if err := c.Source.Validate(); err != nil {
+		return fmt.Errorf("invalid source: %v", err)
+	}
+	if err := c.Dest.Validate(); err != nil {
+		return fmt.Errorf("invalid destination: %v", err)
+	}
+
+	if c.Source.Backend == c.Dest.Backend &&
+		(c.Source.Backend == lncfg.BoltBackend ||
+			c.Source.Backend == lncfg.SqliteBackend) {
+
+		return fmt.Errorf("source and destination must not both use "+
+			"the local %v backend", c.Source.Backend)
+	}
+
+	// An embedded etcd only lives as long as the broln process, so there
+	// is nothing we could copy from or to.
+	if (c.Source.Backend == lncfg.EtcdBackend && c.Source.Etcd.Embedded) ||
+		(c.Dest.Backend == lncfg.EtcdBackend && c.Dest.Etcd.Embedded) {
+
+		return fmt.Errorf("embedded etcd is not supported")
+	}
+
+	return nil
+}
+
+func run() error {
+	cfg := &config{
+		BrolnDir:  defaultBrolnDir,
+		Chain:     defaultChain,
+		Network:   defaultNetwork,
+		BatchSize: kvdb.DefaultCopyBatchSize,
+		Source:    lncfg.DefaultDB(),
+		Dest:      lncfg.DefaultDB(),
+	}
+
+	if _, err := flags.Parse(cfg); err != nil {
+		return err
+	}
+
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := cfg.Source.Init(ctx, cfg.DataDir); err != nil {
+		return err
+	}
+	if err := cfg.Dest.Init(ctx, cfg.DataDir); err != nil {
+		return err
+	}
+
+	fmt.Printf("Migrating broln databases from %v to %v. Make sure the "+
+		"node is stopped!\n", cfg.Source.Backend, cfg.Dest.Backend)
+
+	for _, ns := range cfg.namespaces() {
+		if err := migrateNamespace(ctx, cfg, ns); err != nil {
+			return fmt.Errorf("error migrating %v: %v", ns.name,
+				err)
+		}
+	}
+
+	fmt.Println("Migration completed successfully")
+
+	return nil
+}
+
+func main() {
+	if err := run(); err != nil {
+		if e, ok := err.(*flags.Error); ok && e.Type == flags.ErrHelp {
+			os.Exit(0)
+		}
+
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
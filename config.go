@@ -34,6 +34,7 @@ import (
 	"github.com/brronsuite/broln/lnrpc/routerrpc"
 	"github.com/brronsuite/broln/lnrpc/signrpc"
 	"github.com/brronsuite/broln/lnwallet"
+	"github.com/brronsuite/broln/lnwire"
 	"github.com/brronsuite/broln/routing"
 	"github.com/brronsuite/broln/signal"
 	"github.com/brronsuite/broln/tor"
@@ -163,6 +164,10 @@ const (
 	// This value can be overridden with --default-remote-max-htlcs.
 	defaultRemoteMaxHtlcs = 483
 
+	// defaultPeerStorageQuota is the default maximum size of the backup
+	// blob we store on behalf of each of our channel peers.
+	defaultPeerStorageQuota = lnwire.MaxPeerStorageBlobSize
+
 	// defaultMaxLocalCSVDelay is the maximum delay we accept on our
 	// commitment output.
 	// TODO(halseth): find a more scientific choice of value.
@@ -292,6 +297,7 @@ type Config struct {
 	UnsafeReplay       bool   `long:"unsafe-replay" description:"Causes a link to replay the adds on its commitment txn after starting up, this enables testing of the sphinx replay logic."`
 	MaxPendingChannels int    `long:"maxpendingchannels" description:"The maximum number of incoming pending channels permitted per peer."`
 	BackupFilePath     string `long:"backupfilepath" description:"The target location of the channel backup file"`
+	BackupCommitState  bool   `long:"backupcommitstate" description:"If true, channel backups carry the latest local commitment transaction and HTLCs of each channel, which allows a node restoring them to force close its channels without the remote party. The backup file is then updated on every new commitment, before the prior one is revoked. Remote backups and backups handed to peers never carry the commitment state. Broadcasting the commitment of an outdated backup allows the remote party to sweep all funds of a channel."`
 
 	FeeURL string `long:"feeurl" description:"Optional URL for external fee estimation. If no URL is specified, the method for fee estimation will depend on the chosen backend and network. Must be set for neutrino on mainnet."`
 
@@ -341,6 +347,10 @@ type Config struct {
 
 	DefaultRemoteMaxHtlcs uint16 `long:"default-remote-max-htlcs" description:"The default max_htlc applied when opening or accepting channels. This value limits the number of concurrent HTLCs that the remote party can add to the commitment. The maximum possible value is 483."`
 
+	PeerStorageQuota int `long:"peer-storage-quota" description:"The maximum size in bytes of the backup blob we store on behalf of each of our channel peers if protocol.peer-storage is set. Larger blobs are ignored."`
+
+	RestoreFromPeerStorage bool `long:"restore-from-peer-storage" description:"Restore the channels we don't know of from the channel backups our peers hand back to us if protocol.peer-storage is set. This should only be set after losing the channel state, as restored channels are force closed by the peer."`
+
 	NumGraphSyncPeers      int           `long:"numgraphsyncpeers" description:"The number of peers that we should receive new graph updates from. This option can be tuned to save bandwidth for light clients or routing nodes."`
 	HistoricalSyncInterval time.Duration `long:"historicalsyncinterval" description:"The polling interval between historical graph sync attempts. Each historical graph sync attempt ensures we reconcile with the remote peer's graph from the genesis block."`
 
@@ -350,6 +360,8 @@ type Config struct {
 
 	RejectHTLC bool `long:"rejecthtlc" description:"If true, broln will not forward any HTLCs that are meant as onward payments. This option will still allow broln to send HTLCs and receive HTLCs but broln won't be used as a hop."`
 
+	RequireInterceptor bool `long:"requireinterceptor" description:"Whether to hold forwarded HTLCs and HTLCs for our own invoices while no HTLC interceptor is connected, instead of resuming them. Held HTLCs are failed back before their incoming HTLC expires."`
+
 	StaggerInitialReconnect bool `long:"stagger-initial-reconnect" description:"If true, will apply a randomized staggering between 0s and 30s when reconnecting to persistent peers on startup. The first 10 reconnections will be attempted instantly, regardless of the flag's value"`
 
 	MaxOutgoingCltvExpiry uint32 `long:"max-cltv-expiry" description:"The maximum number of blocks funds could be locked up for when forwarding payments."`
@@ -406,6 +418,14 @@ type Config struct {
 
 	RemoteSigner *lncfg.RemoteSigner `group:"remotesigner" namespace:"remotesigner"`
 
+	FeeManager *lncfg.FeeManager `group:"feemanager" namespace:"feemanager"`
+
+	Trampoline *lncfg.Trampoline `group:"trampoline" namespace:"trampoline"`
+
+	RemoteBackup *lncfg.RemoteBackup `group:"remotebackup" namespace:"remotebackup"`
+
+	Sweeper *lncfg.Sweeper `group:"sweeper" namespace:"sweeper"`
+
 	// LogWriter is the root logger that all of the daemon's subloggers are
 	// hooked up to.
 	LogWriter *build.RotatingLogWriter
@@ -519,6 +539,7 @@ func DefaultConfig() Config {
 		MaxChanSize:                   int64(0),
 		CoopCloseTargetConfs:          defaultCoopCloseTargetConfs,
 		DefaultRemoteMaxHtlcs:         defaultRemoteMaxHtlcs,
+		PeerStorageQuota:              defaultPeerStorageQuota,
 		NumGraphSyncPeers:             defaultMinPeers,
 		HistoricalSyncInterval:        discovery.DefaultHistoricalSyncInterval,
 		Tor: &lncfg.Tor{
@@ -590,6 +611,10 @@ func DefaultConfig() Config {
 		DB:                      lncfg.DefaultDB(),
 		Cluster:                 lncfg.DefaultCluster(),
 		RPCMiddleware:           lncfg.DefaultRPCMiddleware(),
+		FeeManager:              lncfg.DefaultFeeManager(),
+		Trampoline:              lncfg.DefaultTrampoline(),
+		RemoteBackup:            lncfg.DefaultRemoteBackup(),
+		Sweeper:                 lncfg.DefaultSweeper(),
 		registeredChains:        chainreg.NewChainRegistry(),
 		ActiveNetParams:         chainreg.BrocoinTestNetParams,
 		ChannelCommitInterval:   defaultChannelCommitInterval,
@@ -901,6 +926,13 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 		)
 	}
 
+	// Zero-conf channels are always referred to by an alias, so they
+	// cannot be enabled without option-scid-alias.
+	if cfg.ProtocolOptions.ZeroConf() && !cfg.ProtocolOptions.ScidAlias() {
+		return nil, mkErr("'protocol.zero-conf' requires " +
+			"'protocol.option-scid-alias' to be set")
+	}
+
 	// Ensure a valid max channel fee allocation was set.
 	if cfg.MaxChannelFeeAllocation <= 0 || cfg.MaxChannelFeeAllocation > 1 {
 		return nil, mkErr("invalid max channel fee allocation: %v, "+
@@ -1551,6 +1583,23 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 			maxRemoteHtlcs)
 	}
 
+	// Ensure that the peer storage quota doesn't exceed the largest blob a
+	// peer is able to send us.
+	if cfg.PeerStorageQuota < 0 ||
+		cfg.PeerStorageQuota > lnwire.MaxPeerStorageBlobSize {
+
+		return nil, mkErr("peer-storage-quota (%v) must be between "+
+			"0 and %v", cfg.PeerStorageQuota,
+			lnwire.MaxPeerStorageBlobSize)
+	}
+
+	// Channels can only be restored from the backups our peers hold if we
+	// hand them our backups in the first place.
+	if cfg.RestoreFromPeerStorage && !cfg.ProtocolOptions.PeerStorage() {
+		return nil, mkErr("restore-from-peer-storage requires " +
+			"protocol.peer-storage to be set")
+	}
+
 	if err := cfg.Gossip.Parse(); err != nil {
 		return nil, mkErr("error parsing gossip syncer: %v", err)
 	}
@@ -1577,6 +1626,10 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 		cfg.HealthChecks,
 		cfg.RPCMiddleware,
 		cfg.RemoteSigner,
+		cfg.FeeManager,
+		cfg.Trampoline,
+		cfg.RemoteBackup,
+		cfg.Sweeper,
 	)
 	if err != nil {
 		return nil, err
@@ -1100,11 +1100,15 @@ func (bo *breachedOutput) SignDesc() *input.SignDescriptor {
 // sign descriptor. The method then returns the witness computed by invoking
 // this function on the first and subsequent calls.
 func (bo *breachedOutput) CraftInputScript(signer input.Signer, txn *wire.MsgTx,
-	hashCache *txscript.TxSigHashes, txinIdx int) (*input.Script, error) {
+	hashCache *txscript.TxSigHashes,
+	prevOutputFetcher txscript.PrevOutputFetcher,
+	txinIdx int) (*input.Script, error) {
 
 	// First, we ensure that the witness generation function has been
 	// initialized for this breached output.
-	bo.witnessFunc = bo.witnessType.WitnessGenerator(signer, bo.SignDesc())
+	signDesc := *bo.SignDesc()
+	signDesc.PrevOutputFetcher = prevOutputFetcher
+	bo.witnessFunc = bo.witnessType.WitnessGenerator(signer, &signDesc)
 
 	// Now that we have ensured that the witness generation function has
 	// been initialized, we can proceed to execute it and generate the
@@ -1443,7 +1447,13 @@ func (b *BreachArbiter) sweepSpendableOutputsTxn(txWeight int64,
 
 	// Create a sighash cache to improve the performance of hashing and
 	// signing SigHashAll inputs.
-	hashCache := txscript.NewTxSigHashes(txn)
+	prevOutFetcher := txscript.NewMultiPrevOutFetcher(nil)
+	for _, input := range inputs {
+		prevOutFetcher.AddPrevOut(
+			*input.OutPoint(), input.SignDesc().Output,
+		)
+	}
+	hashCache := txscript.NewTxSigHashes(txn, prevOutFetcher)
 
 	// Create a closure that encapsulates the process of initializing a
 	// particular output's witness generation function, computing the
@@ -1455,7 +1465,7 @@ func (b *BreachArbiter) sweepSpendableOutputsTxn(txWeight int64,
 		// transaction using the SpendableOutput's witness generation
 		// function.
 		inputScript, err := so.CraftInputScript(
-			b.cfg.Signer, txn, hashCache, idx,
+			b.cfg.Signer, txn, hashCache, prevOutFetcher, idx,
 		)
 		if err != nil {
 			return err
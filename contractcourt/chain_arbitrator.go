@@ -7,6 +7,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/brronsuite/brond/bronec"
 	"github.com/brronsuite/brond/chaincfg/chainhash"
 	"github.com/brronsuite/brond/wire"
 	"github.com/brronsuite/bronutil"
@@ -165,6 +166,13 @@ type ChainArbitratorConfig struct {
 	// resolved (which includes sweeping any time locked funds).
 	NotifyFullyResolvedChannel func(point wire.OutPoint)
 
+	// NotifySpliceConfirmed is a function closure that the
+	// ChainArbitrator will use to notify the peer of a channel once a
+	// pending splice of the channel reached the depth at which it can be
+	// locked.
+	NotifySpliceConfirmed func(chanPoint wire.OutPoint,
+		remotePub *bronec.PublicKey, spliceTxid chainhash.Hash)
+
 	// OnionProcessor is used to decode onion payloads for on-chain
 	// resolution.
 	OnionProcessor OnionProcessor
@@ -178,6 +186,12 @@ type ChainArbitratorConfig struct {
 	// htlcIndex, if it is a forwarded one.
 	IsForwardedHTLC func(chanID lnwire.ShortChannelID, htlcIndex uint64) bool
 
+	// QueryIncomingCircuit returns the circuit key of the incoming HTLC of
+	// the forward that the outgoing HTLC with the given circuit key
+	// belongs to. Nil is returned if the outgoing HTLC isn't forwarded.
+	QueryIncomingCircuit func(
+		outgoing channeldb.CircuitKey) *channeldb.CircuitKey
+
 	// Clock is the clock implementation that ChannelArbitrator uses.
 	// It is useful for testing.
 	Clock clock.Clock
@@ -371,6 +385,13 @@ func newActiveChannelArbitrator(channel *channeldb.OpenChannel,
 			chanStateDB := c.chanSource.ChannelStateDB()
 			return chanStateDB.FetchHistoricalChannel(&chanPoint)
 		},
+		FindOutgoingHTLCDeadline: func(
+			htlc channeldb.HTLC) (int32, bool) {
+
+			return c.findOutgoingHTLCDeadline(
+				channel.ShortChanID(), htlc,
+			)
+		},
 	}
 
 	// The final component needed is an arbitrator log that the arbitrator
@@ -425,6 +446,83 @@ func (c *ChainArbitrator) getArbChannel(
 	}
 }
 
+// findOutgoingHTLCDeadline returns the expiry height of the incoming HTLC of
+// the forward that the given outgoing HTLC on the channel with the given short
+// channel ID belongs to. False is returned if the outgoing HTLC isn't
+// forwarded, or if its incoming HTLC can't be found.
+func (c *ChainArbitrator) findOutgoingHTLCDeadline(
+	scid lnwire.ShortChannelID, htlc channeldb.HTLC) (int32, bool) {
+
+	incoming := c.cfg.QueryIncomingCircuit(channeldb.CircuitKey{
+		ChanID: scid,
+		HtlcID: htlc.HtlcIndex,
+	})
+	if incoming == nil {
+		log.Debugf("No incoming HTLC found for outgoing HTLC %v of "+
+			"channel %v", htlc.HtlcIndex, scid)
+
+		return 0, false
+	}
+
+	// Find the channel the incoming HTLC was received on.
+	var (
+		chanPoint wire.OutPoint
+		found     bool
+	)
+	c.Lock()
+	for cp, arbitrator := range c.activeChannels {
+		if arbitrator.cfg.ShortChanID == incoming.ChanID {
+			chanPoint = cp
+			found = true
+			break
+		}
+	}
+	c.Unlock()
+
+	if !found {
+		log.Warnf("Unable to find incoming channel %v of outgoing "+
+			"HTLC %v of channel %v", incoming.ChanID,
+			htlc.HtlcIndex, scid)
+
+		return 0, false
+	}
+
+	// The incoming channel may have been closed in the meantime, in which
+	// case we look up the HTLC in its final state.
+	chanStateDB := c.chanSource.ChannelStateDB()
+	channel, err := chanStateDB.FetchChannel(nil, chanPoint)
+	if err == channeldb.ErrChannelNotFound {
+		channel, err = chanStateDB.FetchHistoricalChannel(&chanPoint)
+	}
+	if err != nil {
+		log.Errorf("Unable to fetch incoming channel %v of outgoing "+
+			"HTLC %v of channel %v: %v", chanPoint,
+			htlc.HtlcIndex, scid, err)
+
+		return 0, false
+	}
+
+	commits := []channeldb.ChannelCommitment{
+		channel.LocalCommitment, channel.RemoteCommitment,
+	}
+	for _, commit := range commits {
+		for _, incomingHtlc := range commit.Htlcs {
+			if !incomingHtlc.Incoming ||
+				incomingHtlc.HtlcIndex != incoming.HtlcID {
+
+				continue
+			}
+
+			return int32(incomingHtlc.RefundTimeout), true
+		}
+	}
+
+	log.Warnf("Unable to find incoming HTLC %v of outgoing HTLC %v of "+
+		"channel %v", incoming, htlc.HtlcIndex, scid)
+
+	return 0, false
+}
+
 // ResolveContract marks a contract as fully resolved within the database.
 // This is only to be done once all contracts which were live on the channel
 // before hitting the chain have been resolved.
@@ -510,8 +608,16 @@ func (c *ChainArbitrator) Start() error {
 
 		// First, we'll create an active chainWatcher for this channel
 		// to ensure that we detect any relevant on chain events.
+		// The funding outpoint changes if the channel is spliced, so
+		// it's read when the breach is detected.
 		breachClosure := func(ret *lnwallet.BreachRetribution) error {
-			return c.cfg.ContractBreach(chanPoint, ret)
+			return c.cfg.ContractBreach(channel.FundingOutpoint, ret)
+		}
+		spliceConfClosure := func(spliceTxid chainhash.Hash) {
+			c.notifySpliceConfirmed(channel, spliceTxid)
+		}
+		spliceLockClosure := func(oldChanPoint wire.OutPoint) error {
+			return c.handleSplicedChannel(oldChanPoint, channel)
 		}
 
 		chainWatcher, err := newChainWatcher(
@@ -522,6 +628,8 @@ func (c *ChainArbitrator) Start() error {
 				isOurAddr:           c.cfg.IsOurAddress,
 				contractBreach:      breachClosure,
 				extractStateNumHint: lnwallet.GetStateNumHint,
+				spliceConfirmed:     spliceConfClosure,
+				spliceLocked:        spliceLockClosure,
 			},
 		)
 		if err != nil {
@@ -588,6 +696,13 @@ func (c *ChainArbitrator) Start() error {
 				return chanStateDB.FetchHistoricalChannel(&chanPoint)
 			},
 		}
+		arbCfg.FindOutgoingHTLCDeadline = func(
+			htlc channeldb.HTLC) (int32, bool) {
+
+			return c.findOutgoingHTLCDeadline(
+				arbCfg.ShortChanID, htlc,
+			)
+		}
 		chanLog, err := newBoltArbitratorLog(
 			c.chanSource.Backend, arbCfg, c.cfg.ChainHash, chanPoint,
 		)
@@ -1122,10 +1237,18 @@ func (c *ChainArbitrator) WatchNewChannel(newChan *channeldb.OpenChannel) error
 				retInfo *lnwallet.BreachRetribution) error {
 
 				return c.cfg.ContractBreach(
-					chanPoint, retInfo,
+					newChan.FundingOutpoint, retInfo,
 				)
 			},
 			extractStateNumHint: lnwallet.GetStateNumHint,
+			spliceConfirmed: func(spliceTxid chainhash.Hash) {
+				c.notifySpliceConfirmed(newChan, spliceTxid)
+			},
+			spliceLocked: func(oldChanPoint wire.OutPoint) error {
+				return c.handleSplicedChannel(
+					oldChanPoint, newChan,
+				)
+			},
 		},
 	)
 	if err != nil {
@@ -1154,6 +1277,111 @@ func (c *ChainArbitrator) WatchNewChannel(newChan *channeldb.OpenChannel) error
 	return chainWatcher.Start()
 }
 
+// handleSplicedChannel re-keys the chain watcher and the channel arbitrator
+// of a channel once its splice is locked, as both are indexed by the
+// funding outpoint of the channel. The chain watcher keeps watching the
+// channel, while the arbitrator is replaced by one for the new outpoint.
+func (c *ChainArbitrator) handleSplicedChannel(oldChanPoint wire.OutPoint,
+	channel *channeldb.OpenChannel) error {
+
+	newChanPoint := channel.FundingOutpoint
+
+	log.Infof("Moving ChannelArbitrator for ChannelPoint(%v) to "+
+		"spliced ChannelPoint(%v)", oldChanPoint, newChanPoint)
+
+	c.Lock()
+	chainWatcher, ok := c.activeWatchers[oldChanPoint]
+	if !ok {
+		c.Unlock()
+		return fmt.Errorf("unable to find watcher for: %v",
+			oldChanPoint)
+	}
+	oldArb := c.activeChannels[oldChanPoint]
+	delete(c.activeWatchers, oldChanPoint)
+	delete(c.activeChannels, oldChanPoint)
+	c.activeWatchers[newChanPoint] = chainWatcher
+	c.Unlock()
+
+	// The old funding output has been spent by the splice transaction,
+	// so the arbitrator of the old funding outpoint has nothing at stake
+	// anymore and its log can be wiped.
+	if oldArb != nil {
+		if err := oldArb.Stop(); err != nil {
+			log.Warnf("unable to stop ChannelArbitrator(%v): %v",
+				oldChanPoint, err)
+		}
+		if err := oldArb.log.WipeHistory(); err != nil {
+			return err
+		}
+	}
+
+	channelArb, err := newActiveChannelArbitrator(
+		channel, c, chainWatcher.SubscribeChannelEvents(),
+	)
+	if err != nil {
+		return err
+	}
+
+	c.Lock()
+	c.activeChannels[newChanPoint] = channelArb
+	c.Unlock()
+
+	return channelArb.Start(nil)
+}
+
+// notifySpliceConfirmed notifies the peer of the channel that a pending
+// splice of the channel reached the depth at which it can be locked. The
+// peer may be busy locking the splice of another channel through the
+// ChainArbitrator, so the chain watcher isn't blocked by the notification.
+// The notification returns once the peer received it or exited.
+func (c *ChainArbitrator) notifySpliceConfirmed(channel *channeldb.OpenChannel,
+	spliceTxid chainhash.Hash) {
+
+	if c.cfg.NotifySpliceConfirmed == nil {
+		return
+	}
+
+	chanPoint := channel.FundingOutpoint
+	go c.cfg.NotifySpliceConfirmed(
+		chanPoint, channel.IdentityPub, spliceTxid,
+	)
+}
+
+// ConfirmedSplice returns the txid of the pending splice of the channel that
+// spent its funding output, if the splice transaction reached the depth at
+// which it can be locked.
+func (c *ChainArbitrator) ConfirmedSplice(
+	chanPoint wire.OutPoint) (chainhash.Hash, bool) {
+
+	c.Lock()
+	chainWatcher, ok := c.activeWatchers[chanPoint]
+	c.Unlock()
+	if !ok {
+		return chainhash.Hash{}, false
+	}
+
+	return chainWatcher.confirmedSplice()
+}
+
+// LockSplice moves a channel onto the funding output of its pending splice
+// once the splice transaction reached a safe depth and both parties sent
+// splice_locked. The chain watcher and the channel arbitrator of the channel
+// are re-keyed to the new funding outpoint.
+//
+// NOTE: The link of the channel must be stopped while the splice is locked.
+func (c *ChainArbitrator) LockSplice(chanPoint wire.OutPoint,
+	spliceTxid chainhash.Hash) error {
+
+	c.Lock()
+	chainWatcher, ok := c.activeWatchers[chanPoint]
+	c.Unlock()
+	if !ok {
+		return fmt.Errorf("unable to find watcher for: %v", chanPoint)
+	}
+
+	return chainWatcher.lockSplice(spliceTxid)
+}
+
 // SubscribeChannelEvents returns a new active subscription for the set of
 // possible on-chain events for a particular channel. The struct can be used by
 // callers to be notified whenever an event that changes the state of the
@@ -11,6 +11,7 @@ import (
 	"github.com/brronsuite/broln/channeldb"
 	"github.com/brronsuite/broln/input"
 	"github.com/brronsuite/broln/lnwallet"
+	"github.com/brronsuite/broln/lnwire"
 	"github.com/brronsuite/brond/bronec"
 	"github.com/brronsuite/brond/chaincfg"
 	"github.com/brronsuite/brond/chaincfg/chainhash"
@@ -28,6 +29,12 @@ const (
 	// maxCommitPointPollTimeout is the maximum time we'll wait before
 	// polling the database for a channel's commitpoint.
 	maxCommitPointPollTimeout = 10 * time.Minute
+
+	// minSpliceLockConfs is the minimum number of confirmations of a
+	// splice transaction before the channel moves onto its funding
+	// output. Locking a splice that is reorged out afterwards would leave
+	// the channel with a funding output that doesn't exist.
+	minSpliceLockConfs = 3
 )
 
 // LocalUnilateralCloseInfo encapsulates all the information we need to act on
@@ -187,6 +194,40 @@ type chainWatcherConfig struct {
 	// obfuscater. This is used by the chain watcher to identify which
 	// state was broadcast and confirmed on-chain.
 	extractStateNumHint func(*wire.MsgTx, [lnwallet.StateHintSize]byte) uint64
+
+	// spliceConfirmed is called once a pending splice of the channel
+	// reached the depth at which it can be locked.
+	spliceConfirmed func(spliceTxid chainhash.Hash)
+
+	// spliceLocked is called once the channel moved onto the funding
+	// output of a pending splice. The passed outpoint is the funding
+	// outpoint prior to the splice.
+	spliceLocked func(oldChanPoint wire.OutPoint) error
+}
+
+// spliceLockReq is a request to move the channel onto the funding output of
+// a confirmed splice.
+type spliceLockReq struct {
+	spliceTxid chainhash.Hash
+	err        chan error
+}
+
+// spliceWatch tracks the splice transaction that spent the funding output of
+// the channel until the splice is locked.
+type spliceWatch struct {
+	// spliceTxid is the txid of the splice transaction.
+	spliceTxid chainhash.Hash
+
+	// confirmed is true once the splice transaction reached the depth at
+	// which it can be locked.
+	confirmed bool
+
+	// lockReqs receives the requests to lock the splice.
+	lockReqs chan *spliceLockReq
+
+	// done is closed once the splice is locked, or the splice
+	// transaction has been reorged out.
+	done chan struct{}
 }
 
 // chainWatcher is a system that's assigned to every active channel. The duty
@@ -217,6 +258,10 @@ type chainWatcher struct {
 	// clientSubscriptions is a map that keeps track of all the active
 	// client subscriptions for events related to this channel.
 	clientSubscriptions map[uint64]*ChainEventSubscription
+
+	// spliceWatch is set while a splice transaction that spent the
+	// funding output isn't locked yet.
+	spliceWatch *spliceWatch
 }
 
 // newChainWatcher returns a new instance of a chainWatcher for a channel given
@@ -260,10 +305,6 @@ func (c *chainWatcher) Start() error {
 	log.Debugf("Starting chain watcher for ChannelPoint(%v)",
 		chanState.FundingOutpoint)
 
-	// First, we'll register for a notification to be dispatched if the
-	// funding output is spent.
-	fundingOut := &chanState.FundingOutpoint
-
 	// As a height hint, we'll try to use the opening height, but if the
 	// channel isn't yet open, then we'll use the height it was broadcast
 	// at.
@@ -272,6 +313,16 @@ func (c *chainWatcher) Start() error {
 		heightHint = chanState.FundingBroadcastHeight
 	}
 
+	return c.watchFundingOutput(heightHint)
+}
+
+// watchFundingOutput registers for a notification to be dispatched if the
+// current funding output of the channel is spent, and dispatches the
+// closeObserver to act on it.
+func (c *chainWatcher) watchFundingOutput(heightHint uint32) error {
+	chanState := c.cfg.chanState
+	fundingOut := &chanState.FundingOutpoint
+
 	localKey := chanState.LocalChanCfg.MultiSigKey.PubKey.SerializeCompressed()
 	remoteKey := chanState.RemoteChanCfg.MultiSigKey.PubKey.SerializeCompressed()
 	multiSigScript, err := input.GenMultiSigScript(
@@ -571,6 +622,29 @@ func (c *chainWatcher) closeObserver(spendNtfn *chainntnfs.SpendEvent) {
 		// revoked state...!!!
 		commitTxBroadcast := commitSpend.SpendingTx
 
+		// If the funding output was spent by a pending splice of the
+		// channel, then the channel isn't closed, but continues with
+		// the new funding output once the splice is locked.
+		splices, err := c.cfg.chanState.PendingSplices()
+		if err != nil {
+			log.Errorf("Unable to fetch pending splices: %v", err)
+			return
+		}
+		spendingTxid := commitTxBroadcast.TxHash()
+		for _, splice := range splices {
+			if splice.SpliceTx.TxHash() != spendingTxid {
+				continue
+			}
+
+			err := c.watchSplice(commitSpend, splice)
+			if err != nil {
+				log.Errorf("Unable to handle splice of "+
+					"ChannelPoint(%v): %v",
+					c.cfg.chanState.FundingOutpoint, err)
+			}
+			return
+		}
+
 		// First, we'll construct the chainset which includes all the
 		// data we need to dispatch an event to our subscribers about
 		// this possible channel close event.
@@ -620,10 +694,14 @@ func (c *chainWatcher) closeObserver(spendNtfn *chainntnfs.SpendEvent) {
 
 		// Next, we'll check to see if this is a cooperative channel
 		// closure or not. This is characterized by having an input
-		// sequence number that's finalized. This won't happen with
-		// regular commitment transactions due to the state hint
+		// sequence number that's finalized, or that signals
+		// replaceability for RBF cooperative closes. This won't happen
+		// with regular commitment transactions due to the state hint
 		// encoding scheme.
-		if commitTxBroadcast.TxIn[0].Sequence == wire.MaxTxInSequenceNum {
+		closeSequence := commitTxBroadcast.TxIn[0].Sequence
+		if closeSequence == wire.MaxTxInSequenceNum ||
+			closeSequence == lnwallet.RbfCloseSequence {
+
 			// TODO(roasbeef): rare but possible, need itest case
 			// for
 			err := c.dispatchCooperativeClose(commitSpend)
@@ -1262,3 +1340,261 @@ func (c *chainWatcher) waitForCommitmentPoint() *bronec.PublicKey {
 		}
 	}
 }
+
+// spliceLockConfs returns the number of confirmations of a splice
+// transaction before the channel moves onto its funding output.
+func spliceLockConfs(chanState *channeldb.OpenChannel) uint32 {
+	numConfs := uint32(chanState.NumConfsRequired)
+	if numConfs < minSpliceLockConfs {
+		numConfs = minSpliceLockConfs
+	}
+
+	return numConfs
+}
+
+// watchSplice is called once a pending splice of the channel spent the
+// funding output. It waits until the splice transaction reached a safe depth
+// and notifies the peer of the channel, which locks the splice through
+// lockSplice once both parties sent splice_locked. If the splice transaction
+// is reorged out, the old funding output is watched again. If the new
+// funding output is spent before the splice is locked, the splice is applied
+// right away, so the spend is handled as a close of the channel.
+func (c *chainWatcher) watchSplice(spliceSpend *chainntnfs.SpendDetail,
+	splice *channeldb.PendingSplice) error {
+
+	chanState := c.cfg.chanState
+	oldChanPoint := chanState.FundingOutpoint
+	newChanPoint := splice.FundingOutpoint
+	pkScript := splice.SpliceTx.TxOut[newChanPoint.Index].PkScript
+	spendHeight := uint32(spliceSpend.SpendingHeight)
+
+	numConfs := spliceLockConfs(chanState)
+	log.Infof("Splice tx %v of ChannelPoint(%v) confirmed, waiting for "+
+		"%v confirmations to lock it", newChanPoint.Hash, oldChanPoint,
+		numConfs)
+
+	// We need the position of the splice transaction within its block to
+	// determine the new short channel ID, which is known after the first
+	// confirmation already.
+	locNtfn, err := c.cfg.notifier.RegisterConfirmationsNtfn(
+		&newChanPoint.Hash, pkScript, 1, spendHeight,
+	)
+	if err != nil {
+		return err
+	}
+	defer locNtfn.Cancel()
+
+	confNtfn, err := c.cfg.notifier.RegisterConfirmationsNtfn(
+		&newChanPoint.Hash, pkScript, numConfs, spendHeight,
+	)
+	if err != nil {
+		return err
+	}
+	defer confNtfn.Cancel()
+
+	newSpendNtfn, err := c.cfg.notifier.RegisterSpendNtfn(
+		&newChanPoint, pkScript, spendHeight,
+	)
+	if err != nil {
+		return err
+	}
+
+	watch := &spliceWatch{
+		spliceTxid: newChanPoint.Hash,
+		lockReqs:   make(chan *spliceLockReq),
+		done:       make(chan struct{}),
+	}
+	c.Lock()
+	c.spliceWatch = watch
+	c.Unlock()
+
+	defer func() {
+		c.Lock()
+		c.spliceWatch = nil
+		c.Unlock()
+
+		close(watch.done)
+	}()
+
+	var confLoc *lnwire.ShortChannelID
+	for {
+		select {
+		case conf, ok := <-locNtfn.Confirmed:
+			if !ok {
+				newSpendNtfn.Cancel()
+				return fmt.Errorf("notifier exited")
+			}
+
+			confLoc = &lnwire.ShortChannelID{
+				BlockHeight: conf.BlockHeight,
+				TxIndex:     conf.TxIndex,
+				TxPosition:  uint16(newChanPoint.Index),
+			}
+
+		case _, ok := <-confNtfn.Confirmed:
+			if !ok {
+				newSpendNtfn.Cancel()
+				return fmt.Errorf("notifier exited")
+			}
+
+			log.Infof("Splice tx %v of ChannelPoint(%v) reached %v "+
+				"confirmations", newChanPoint.Hash, oldChanPoint,
+				numConfs)
+
+			c.Lock()
+			watch.confirmed = true
+			c.Unlock()
+
+			if c.cfg.spliceConfirmed != nil {
+				c.cfg.spliceConfirmed(newChanPoint.Hash)
+			}
+
+		// If the splice transaction has been reorged out, the old
+		// funding output is unspent again. We watch it for the next
+		// spend, which may be by another pending splice.
+		case _, ok := <-confNtfn.NegativeConf:
+			newSpendNtfn.Cancel()
+			if !ok {
+				return fmt.Errorf("notifier exited")
+			}
+
+			log.Warnf("Splice tx %v of ChannelPoint(%v) reorged out",
+				newChanPoint.Hash, oldChanPoint)
+
+			return c.watchFundingOutput(spendHeight)
+
+		case req := <-watch.lockReqs:
+			if !watch.confirmed || confLoc == nil {
+				req.err <- fmt.Errorf("splice tx %v not "+
+					"confirmed", req.spliceTxid)
+				continue
+			}
+
+			err := c.applySplice(splice, *confLoc)
+			req.err <- err
+			if err != nil {
+				newSpendNtfn.Cancel()
+				return err
+			}
+
+			// From now on, spends of the new funding output
+			// close the channel.
+			c.wg.Add(1)
+			go c.closeObserver(newSpendNtfn)
+
+			return nil
+
+		// The new funding output can only be spent once the splice
+		// transaction confirmed, so the channel is closed before the
+		// splice was locked. We apply the splice and register for the
+		// spend again, which is then handled as a close of the
+		// channel.
+		case _, ok := <-newSpendNtfn.Spend:
+			if !ok {
+				return fmt.Errorf("notifier exited")
+			}
+
+			log.Infof("Spliced ChannelPoint(%v) spent before the "+
+				"splice was locked", newChanPoint)
+
+			if confLoc == nil {
+				select {
+				case conf, ok := <-locNtfn.Confirmed:
+					if !ok {
+						return fmt.Errorf("notifier " +
+							"exited")
+					}
+					confLoc = &lnwire.ShortChannelID{
+						BlockHeight: conf.BlockHeight,
+						TxIndex:     conf.TxIndex,
+						TxPosition: uint16(
+							newChanPoint.Index,
+						),
+					}
+
+				case <-c.quit:
+					return nil
+				}
+			}
+
+			if err := c.applySplice(splice, *confLoc); err != nil {
+				return err
+			}
+
+			return c.watchFundingOutput(confLoc.BlockHeight)
+
+		case <-c.quit:
+			newSpendNtfn.Cancel()
+			return nil
+		}
+	}
+}
+
+// applySplice moves the channel onto the funding output of the given splice
+// and notifies the ChainArbitrator, which re-keys the arbitrator of the
+// channel.
+func (c *chainWatcher) applySplice(splice *channeldb.PendingSplice,
+	confLoc lnwire.ShortChannelID) error {
+
+	chanState := c.cfg.chanState
+	oldChanPoint := chanState.FundingOutpoint
+
+	err := chanState.ApplySplice(splice.SpliceTx.TxHash(), confLoc)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("ChannelPoint(%v) moved onto spliced ChannelPoint(%v)",
+		oldChanPoint, chanState.FundingOutpoint)
+
+	if c.cfg.spliceLocked == nil {
+		return nil
+	}
+
+	return c.cfg.spliceLocked(oldChanPoint)
+}
+
+// confirmedSplice returns the txid of the splice transaction that spent the
+// funding output, if it reached the depth at which it can be locked.
+func (c *chainWatcher) confirmedSplice() (chainhash.Hash, bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.spliceWatch == nil || !c.spliceWatch.confirmed {
+		return chainhash.Hash{}, false
+	}
+
+	return c.spliceWatch.spliceTxid, true
+}
+
+// lockSplice moves the channel onto the funding output of the given splice,
+// which must have reached the depth at which it can be locked.
+func (c *chainWatcher) lockSplice(spliceTxid chainhash.Hash) error {
+	c.Lock()
+	watch := c.spliceWatch
+	c.Unlock()
+
+	if watch == nil || watch.spliceTxid != spliceTxid {
+		return fmt.Errorf("splice tx %v hasn't spent the funding "+
+			"output", spliceTxid)
+	}
+
+	req := &spliceLockReq{
+		spliceTxid: spliceTxid,
+		err:        make(chan error, 1),
+	}
+	select {
+	case watch.lockReqs <- req:
+	case <-watch.done:
+		return fmt.Errorf("splice tx %v reorged out", spliceTxid)
+	case <-c.quit:
+		return ErrChainArbExiting
+	}
+
+	select {
+	case err := <-req.err:
+		return err
+	case <-c.quit:
+		return ErrChainArbExiting
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"crypto/sha256"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -13,7 +14,9 @@ import (
 	"github.com/brronsuite/broln/lntest/mock"
 	"github.com/brronsuite/broln/lnwallet"
 	"github.com/brronsuite/broln/lnwire"
+	"github.com/brronsuite/brond/chaincfg/chainhash"
 	"github.com/brronsuite/brond/wire"
+	"github.com/stretchr/testify/require"
 )
 
 // TestChainWatcherRemoteUnilateralClose tests that the chain watcher is able
@@ -625,3 +628,228 @@ func TestChainWatcherLocalForceCloseDetect(t *testing.T) {
 		})
 	}
 }
+
+// spliceNotifier is a chain notifier that hands out separate notification
+// channels per outpoint and confirmation depth, so the notifications the
+// chain watcher registers for a splice can be told apart.
+type spliceNotifier struct {
+	*mock.ChainNotifier
+
+	sync.Mutex
+	spends map[wire.OutPoint]chan *chainntnfs.SpendDetail
+	confs  map[uint32]*chainntnfs.ConfirmationEvent
+}
+
+func newSpliceNotifier() *spliceNotifier {
+	spends := make(map[wire.OutPoint]chan *chainntnfs.SpendDetail)
+
+	return &spliceNotifier{
+		ChainNotifier: &mock.ChainNotifier{},
+		spends:        spends,
+		confs:         make(map[uint32]*chainntnfs.ConfirmationEvent),
+	}
+}
+
+func (s *spliceNotifier) spendChan(
+	op wire.OutPoint) chan *chainntnfs.SpendDetail {
+
+	s.Lock()
+	defer s.Unlock()
+
+	if _, ok := s.spends[op]; !ok {
+		s.spends[op] = make(chan *chainntnfs.SpendDetail)
+	}
+
+	return s.spends[op]
+}
+
+func (s *spliceNotifier) confEvent(
+	numConfs uint32) *chainntnfs.ConfirmationEvent {
+
+	s.Lock()
+	defer s.Unlock()
+
+	if _, ok := s.confs[numConfs]; !ok {
+		s.confs[numConfs] = &chainntnfs.ConfirmationEvent{
+			Confirmed:    make(chan *chainntnfs.TxConfirmation),
+			NegativeConf: make(chan int32),
+			Cancel:       func() {},
+		}
+	}
+
+	return s.confs[numConfs]
+}
+
+func (s *spliceNotifier) RegisterConfirmationsNtfn(_ *chainhash.Hash,
+	_ []byte, numConfs, _ uint32) (*chainntnfs.ConfirmationEvent, error) {
+
+	return s.confEvent(numConfs), nil
+}
+
+func (s *spliceNotifier) RegisterSpendNtfn(op *wire.OutPoint, _ []byte,
+	_ uint32) (*chainntnfs.SpendEvent, error) {
+
+	return &chainntnfs.SpendEvent{
+		Spend:  s.spendChan(*op),
+		Cancel: func() {},
+	}, nil
+}
+
+// newSpliceWatcher creates a chain watcher for a channel with a pending
+// splice. The txids of confirmed splices are sent over the returned channel.
+func newSpliceWatcher(t *testing.T, notifier *spliceNotifier) (
+	*chainWatcher, *channeldb.PendingSplice, chan chainhash.Hash,
+	chan wire.OutPoint) {
+
+	aliceChannel, _, cleanUp, err := lnwallet.CreateTestChannels(
+		channeldb.SingleFunderTweaklessBit,
+	)
+	require.NoError(t, err)
+	t.Cleanup(cleanUp)
+
+	chanState := aliceChannel.State()
+	spliceTx := wire.NewMsgTx(2)
+	spliceTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: chanState.FundingOutpoint,
+	})
+	spliceTx.AddTxOut(&wire.TxOut{
+		Value:    int64(chanState.Capacity),
+		PkScript: []byte{0x00, 0x20},
+	})
+	splice := &channeldb.PendingSplice{
+		SpliceTx: spliceTx,
+		FundingOutpoint: wire.OutPoint{
+			Hash: spliceTx.TxHash(),
+		},
+		Capacity:         chanState.Capacity,
+		IsInitiator:      true,
+		LocalCommitment:  chanState.LocalCommitment,
+		RemoteCommitment: chanState.RemoteCommitment,
+	}
+	require.NoError(t, chanState.AddPendingSplice(splice))
+
+	confirmed := make(chan chainhash.Hash, 1)
+	locked := make(chan wire.OutPoint, 1)
+	watcher, err := newChainWatcher(chainWatcherConfig{
+		chanState:           chanState,
+		notifier:            notifier,
+		signer:              aliceChannel.Signer,
+		extractStateNumHint: lnwallet.GetStateNumHint,
+		spliceConfirmed: func(spliceTxid chainhash.Hash) {
+			confirmed <- spliceTxid
+		},
+		spliceLocked: func(oldChanPoint wire.OutPoint) error {
+			locked <- oldChanPoint
+			return nil
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, watcher.Start())
+	t.Cleanup(func() {
+		require.NoError(t, watcher.Stop())
+	})
+
+	return watcher, splice, confirmed, locked
+}
+
+// sendSpliceSpend notifies the chain watcher that the funding output has
+// been spent by the splice transaction.
+func sendSpliceSpend(t *testing.T, notifier *spliceNotifier,
+	fundingOutpoint wire.OutPoint, splice *channeldb.PendingSplice) {
+
+	spliceTxid := splice.SpliceTx.TxHash()
+	select {
+	case notifier.spendChan(fundingOutpoint) <- &chainntnfs.SpendDetail{
+		SpentOutPoint:  &fundingOutpoint,
+		SpenderTxHash:  &spliceTxid,
+		SpendingTx:     splice.SpliceTx,
+		SpendingHeight: 100,
+	}:
+	case <-time.After(time.Second * 5):
+		t.Fatalf("spend of funding output not received")
+	}
+}
+
+// sendSpliceConf sends a confirmation of the splice transaction over the
+// notification registered for the given depth.
+func sendSpliceConf(t *testing.T, notifier *spliceNotifier,
+	numConfs uint32) {
+
+	conf := &chainntnfs.TxConfirmation{
+		BlockHeight: 100,
+		TxIndex:     2,
+	}
+	select {
+	case notifier.confEvent(numConfs).Confirmed <- conf:
+	case <-time.After(time.Second * 5):
+		t.Fatalf("confirmation at depth %v not received", numConfs)
+	}
+}
+
+// TestChainWatcherSpliceLock tests that a splice spending the funding output
+// is only locked once it reached a safe depth, after which the channel moves
+// onto the new funding output.
+func TestChainWatcherSpliceLock(t *testing.T) {
+	t.Parallel()
+
+	notifier := newSpliceNotifier()
+	watcher, splice, confirmed, locked := newSpliceWatcher(t, notifier)
+	chanState := watcher.cfg.chanState
+	oldChanPoint := chanState.FundingOutpoint
+	spliceTxid := splice.SpliceTx.TxHash()
+
+	sendSpliceSpend(t, notifier, oldChanPoint, splice)
+	sendSpliceConf(t, notifier, 1)
+
+	// The splice can't be locked before it reached the lock depth.
+	_, ok := watcher.confirmedSplice()
+	require.False(t, ok)
+	require.Error(t, watcher.lockSplice(spliceTxid))
+
+	sendSpliceConf(t, notifier, spliceLockConfs(chanState))
+	select {
+	case txid := <-confirmed:
+		require.Equal(t, spliceTxid, txid)
+	case <-time.After(time.Second * 5):
+		t.Fatalf("splice confirmation not notified")
+	}
+
+	txid, ok := watcher.confirmedSplice()
+	require.True(t, ok)
+	require.Equal(t, spliceTxid, txid)
+
+	// Locking the splice moves the channel onto the new funding output.
+	require.NoError(t, watcher.lockSplice(spliceTxid))
+	select {
+	case chanPoint := <-locked:
+		require.Equal(t, oldChanPoint, chanPoint)
+	case <-time.After(time.Second * 5):
+		t.Fatalf("splice lock not notified")
+	}
+	require.Equal(t, splice.FundingOutpoint, chanState.FundingOutpoint)
+	require.Equal(t, uint32(100), chanState.ShortChanID().BlockHeight)
+}
+
+// TestChainWatcherSpliceReorg tests that the old funding output is watched
+// again once the splice transaction spending it is reorged out.
+func TestChainWatcherSpliceReorg(t *testing.T) {
+	t.Parallel()
+
+	notifier := newSpliceNotifier()
+	watcher, splice, _, _ := newSpliceWatcher(t, notifier)
+	oldChanPoint := watcher.cfg.chanState.FundingOutpoint
+	numConfs := spliceLockConfs(watcher.cfg.chanState)
+
+	sendSpliceSpend(t, notifier, oldChanPoint, splice)
+	select {
+	case notifier.confEvent(numConfs).NegativeConf <- 1:
+	case <-time.After(time.Second * 5):
+		t.Fatalf("reorg of splice tx not received")
+	}
+
+	// The splice can't be locked anymore, and the next spend of the old
+	// funding output is handled again.
+	require.Error(t, watcher.lockSplice(splice.SpliceTx.TxHash()))
+	sendSpliceSpend(t, notifier, oldChanPoint, splice)
+	require.Equal(t, oldChanPoint, watcher.cfg.chanState.FundingOutpoint)
+}
@@ -163,6 +163,14 @@ type ChannelArbitratorConfig struct {
 	// additional information required for proper contract resolution.
 	FetchHistoricalChannel func() (*channeldb.OpenChannel, error)
 
+	// FindOutgoingHTLCDeadline returns the expiry height of the incoming
+	// HTLC of the forward the given outgoing HTLC of this channel belongs
+	// to. It is the deadline by which the outgoing HTLC must be resolved
+	// on chain, as we can't claim the incoming HTLC anymore afterwards.
+	// False is returned if the HTLC isn't forwarded, or if its incoming
+	// HTLC can't be found.
+	FindOutgoingHTLCDeadline func(htlc channeldb.HTLC) (int32, bool)
+
 	ChainArbitratorConfig
 }
 
@@ -365,6 +373,11 @@ type ChannelArbitrator struct {
 	// upon start up to decide which actions to take.
 	state ArbitratorState
 
+	// anchorSweeps holds the sweep parameters we last offered each anchor
+	// to the sweeper with, which allows us to only re-offer an anchor if
+	// its parameters changed.
+	anchorSweeps map[wire.OutPoint]sweep.Params
+
 	wg   sync.WaitGroup
 	quit chan struct{}
 }
@@ -382,6 +395,7 @@ func NewChannelArbitrator(cfg ChannelArbitratorConfig,
 		resolutionSignal: make(chan struct{}),
 		forceCloseReqs:   make(chan *forceCloseReq),
 		activeHTLCs:      htlcSets,
+		anchorSweeps:     make(map[wire.OutPoint]sweep.Params),
 		cfg:              cfg,
 		quit:             make(chan struct{}),
 	}
@@ -1146,9 +1160,14 @@ func (c *ChannelArbitrator) stateStep(
 	return nextState, closeTx, nil
 }
 
-// sweepAnchors offers all given anchor resolutions to the sweeper. It requests
-// sweeping at the minimum fee rate. This fee rate can be upped manually by the
-// user via the BumpFee rpc.
+// sweepAnchors offers all given anchor resolutions to the sweeper. If HTLCs on
+// a commitment are at stake, its anchor is given their deadline and a budget of
+// a share of their value. The sweeper then bumps the fee rate of the package of
+// the commitment and the anchor sweep every block until the commitment
+// confirms, reaching the highest fee rate the budget allows at the deadline.
+// Otherwise the anchor is swept with a relaxed confirmation target, which only
+// happens if the commitment pays less than that. An anchor that was already
+// offered is only re-offered if its deadline or budget changed.
 func (c *ChannelArbitrator) sweepAnchors(anchors *lnwallet.AnchorResolutions,
 	heightHint uint32) error {
 
@@ -1167,6 +1186,13 @@ func (c *ChannelArbitrator) sweepAnchors(anchors *lnwallet.AnchorResolutions,
 			return err
 		}
 
+		// Find the HTLCs we'd lose if the commitment doesn't confirm
+		// before the deadline.
+		atRisk, err := c.timeSensitiveHTLCs(htlcs)
+		if err != nil {
+			return err
+		}
+
 		log.Debugf("ChannelArbitrator(%v): pre-confirmation sweep of "+
 			"anchor of %s commit tx %v", c.cfg.ChanPoint,
 			anchorPath, anchor.CommitAnchor)
@@ -1191,19 +1217,55 @@ func (c *ChannelArbitrator) sweepAnchors(anchors *lnwallet.AnchorResolutions,
 		// Also signal that this is a force sweep, so that the anchor
 		// will be swept even if it isn't economical purely based on the
 		// anchor value.
-		_, err = c.cfg.Sweeper.SweepInput(
-			&anchorInput,
-			sweep.Params{
-				Fee: sweep.FeePreference{
-					ConfTarget: deadline,
-				},
-				Force:          true,
-				ExclusiveGroup: &exclusiveGroup,
+		params := sweep.Params{
+			Fee: sweep.FeePreference{
+				ConfTarget: deadline,
 			},
-		)
+			Force:          true,
+			ExclusiveGroup: &exclusiveGroup,
+		}
+
+		// If HTLCs are at stake, we're willing to spend a share of
+		// their value to get the commitment confirmed before their
+		// deadline. The sweeper raises the fee rate every block until
+		// the commitment confirms.
+		if len(atRisk) > 0 {
+			var atRiskAmt lnwire.MilliBronees
+			deadlineHeight := int32(math.MaxInt32)
+			for _, htlc := range atRisk {
+				atRiskAmt += htlc.Amt
+				if int32(htlc.RefundTimeout) < deadlineHeight {
+					deadlineHeight = int32(
+						htlc.RefundTimeout,
+					)
+				}
+			}
+
+			params.Budget = htlcSweepBudget(atRiskAmt)
+			params.DeadlineHeight = &deadlineHeight
+
+			log.Debugf("ChannelArbitrator(%v): bumping %s "+
+				"commit tx by sweeping anchor %v with budget "+
+				"%v before deadline height %v to protect %v "+
+				"htlcs",
+				c.cfg.ChanPoint, anchorPath,
+				anchor.CommitAnchor, params.Budget,
+				deadlineHeight, len(atRisk))
+		}
+
+		// The sweeper already bumps the anchor every block, so we
+		// don't re-offer it unless its parameters changed, as each
+		// offer adds another result listener to the pending input.
+		prevParams, ok := c.anchorSweeps[anchor.CommitAnchor]
+		if ok && sameAnchorSweep(prevParams, params) {
+			return nil
+		}
+
+		_, err = c.cfg.Sweeper.SweepInput(&anchorInput, params)
 		if err != nil {
 			return err
 		}
+		c.anchorSweeps[anchor.CommitAnchor] = params
 
 		return nil
 	}
@@ -1241,6 +1303,27 @@ func (c *ChannelArbitrator) sweepAnchors(anchors *lnwallet.AnchorResolutions,
 	return nil
 }
 
+// sameAnchorSweep returns true if offering an anchor with the new sweep
+// parameters wouldn't change how the sweeper sweeps it. The confirmation target
+// of an anchor with a deadline height only determines the fee rate its sweep
+// starts at, so it's ignored as it shrinks every block.
+func sameAnchorSweep(prev, next sweep.Params) bool {
+	if prev.Budget != next.Budget || prev.Force != next.Force {
+		return false
+	}
+
+	switch {
+	case prev.DeadlineHeight == nil && next.DeadlineHeight == nil:
+		return prev.Fee == next.Fee
+
+	case prev.DeadlineHeight == nil || next.DeadlineHeight == nil:
+		return false
+
+	default:
+		return *prev.DeadlineHeight == *next.DeadlineHeight
+	}
+}
+
 // findCommitmentDeadline finds the deadline (relative block height) for a
 // commitment transaction by extracting the minimum CLTV from its HTLCs. From
 // our PoV, the deadline is defined to be the smaller of,
@@ -1254,10 +1337,60 @@ func (c *ChannelArbitrator) sweepAnchors(anchors *lnwallet.AnchorResolutions,
 func (c *ChannelArbitrator) findCommitmentDeadline(heightHint uint32,
 	htlcs htlcSet) (uint32, error) {
 
+	atRisk, err := c.timeSensitiveHTLCs(htlcs)
+	if err != nil {
+		return 0, err
+	}
+
 	deadlineMinHeight := uint32(math.MaxUint32)
+	for _, htlc := range atRisk {
+		if htlc.RefundTimeout < deadlineMinHeight {
+			deadlineMinHeight = htlc.RefundTimeout
+		}
+	}
+
+	// Calculate the deadline. There are two cases to be handled here,
+	//   - when the deadlineMinHeight never gets updated, which could
+	//     happen when we have no outgoing HTLCs, and, for incoming HTLCs,
+	//       * either we have none, or,
+	//       * none of the HTLCs are preimageAvailable.
+	//   - when our deadlineMinHeight is no greater than the heightHint,
+	//     which means we are behind our schedule.
+	deadline := deadlineMinHeight - heightHint
+	switch {
+	// When we couldn't find a deadline height from our HTLCs, we will fall
+	// back to the default value.
+	case deadlineMinHeight == math.MaxUint32:
+		deadline = anchorSweepConfTarget
+
+	// When the deadline is passed, we will fall back to the smallest conf
+	// target (1 block).
+	case deadlineMinHeight <= heightHint:
+		log.Warnf("ChannelArbitrator(%v): deadline is passed with "+
+			"deadlineMinHeight=%d, heightHint=%d",
+			c.cfg.ChanPoint, deadlineMinHeight, heightHint)
+		deadline = 1
+	}
+
+	log.Debugf("ChannelArbitrator(%v): calculated deadline: %d, "+
+		"using deadlineMinHeight=%d, heightHint=%d",
+		c.cfg.ChanPoint, deadline, deadlineMinHeight, heightHint)
 
-	// First, iterate through the outgoingHTLCs to find the lowest CLTV
-	// value.
+	return deadline, nil
+}
+
+// timeSensitiveHTLCs returns the HTLCs of the given set that we may lose if the
+// commitment they are on doesn't confirm before their CLTV expires. These are,
+//   - the outgoing HTLCs, and,
+//   - the incoming HTLCs for which the preimage is available.
+//
+// Dust HTLCs are skipped, as they don't have an output on the commitment.
+func (c *ChannelArbitrator) timeSensitiveHTLCs(
+	htlcs htlcSet) ([]channeldb.HTLC, error) {
+
+	var atRisk []channeldb.HTLC
+
+	// First, iterate through the outgoingHTLCs.
 	for _, htlc := range htlcs.outgoingHTLCs {
 		// Skip if the HTLC is dust.
 		if htlc.OutputIndex < 0 {
@@ -1268,13 +1401,11 @@ func (c *ChannelArbitrator) findCommitmentDeadline(heightHint uint32,
 			continue
 		}
 
-		if htlc.RefundTimeout < deadlineMinHeight {
-			deadlineMinHeight = htlc.RefundTimeout
-		}
+		atRisk = append(atRisk, htlc)
 	}
 
-	// Then going through the incomingHTLCs, and update the minHeight when
-	// conditions met.
+	// Then going through the incomingHTLCs, and add those whose preimage
+	// is available.
 	for _, htlc := range htlcs.incomingHTLCs {
 		// Skip if the HTLC is dust.
 		if htlc.OutputIndex < 0 {
@@ -1289,46 +1420,17 @@ func (c *ChannelArbitrator) findCommitmentDeadline(heightHint uint32,
 		// this HTLC.
 		preimageAvailable, err := c.isPreimageAvailable(htlc.RHash)
 		if err != nil {
-			return 0, err
+			return nil, err
 		}
 
 		if !preimageAvailable {
 			continue
 		}
 
-		if htlc.RefundTimeout < deadlineMinHeight {
-			deadlineMinHeight = htlc.RefundTimeout
-		}
+		atRisk = append(atRisk, htlc)
 	}
 
-	// Calculate the deadline. There are two cases to be handled here,
-	//   - when the deadlineMinHeight never gets updated, which could
-	//     happen when we have no outgoing HTLCs, and, for incoming HTLCs,
-	//       * either we have none, or,
-	//       * none of the HTLCs are preimageAvailable.
-	//   - when our deadlineMinHeight is no greater than the heightHint,
-	//     which means we are behind our schedule.
-	deadline := deadlineMinHeight - heightHint
-	switch {
-	// When we couldn't find a deadline height from our HTLCs, we will fall
-	// back to the default value.
-	case deadlineMinHeight == math.MaxUint32:
-		deadline = anchorSweepConfTarget
-
-	// When the deadline is passed, we will fall back to the smallest conf
-	// target (1 block).
-	case deadlineMinHeight <= heightHint:
-		log.Warnf("ChannelArbitrator(%v): deadline is passed with "+
-			"deadlineMinHeight=%d, heightHint=%d",
-			c.cfg.ChanPoint, deadlineMinHeight, heightHint)
-		deadline = 1
-	}
-
-	log.Debugf("ChannelArbitrator(%v): calculated deadline: %d, "+
-		"using deadlineMinHeight=%d, heightHint=%d",
-		c.cfg.ChanPoint, deadline, deadlineMinHeight, heightHint)
-
-	return deadline, nil
+	return atRisk, nil
 }
 
 // launchResolvers updates the activeResolvers list and starts the resolvers.
@@ -2420,8 +2522,15 @@ func (c *ChannelArbitrator) channelAttendant(bestHeight int32) {
 
 			// If we're not in the default state, then we can
 			// ignore this signal as we're waiting for contract
-			// resolution.
-			if c.state != StateDefault {
+			// resolution. The exception is when we're waiting for
+			// our commitment to confirm. We then check its anchors
+			// every block, and re-offer them to the sweeper if
+			// their deadline or budget changed because HTLCs have
+			// become time-sensitive in the meantime, for example
+			// because we learned the preimage of an incoming HTLC.
+			if c.state != StateDefault &&
+				c.state != StateCommitmentBroadcasted {
+
 				continue
 			}
 
@@ -396,6 +396,9 @@ func createTestChannelArbitrator(t *testing.T, log ArbitratorLog,
 		FetchHistoricalChannel: func() (*channeldb.OpenChannel, error) {
 			return &channeldb.OpenChannel{}, nil
 		},
+		FindOutgoingHTLCDeadline: func(channeldb.HTLC) (int32, bool) {
+			return 0, false
+		},
 	}
 
 	// Apply all custom options to the config struct.
@@ -2510,11 +2513,13 @@ func TestChannelArbitratorAnchors(t *testing.T) {
 	chanArb.cfg.Channel.(*mockChannel).anchorResolutions =
 		&lnwallet.AnchorResolutions{
 			Local: &lnwallet.AnchorResolution{
+				CommitAnchor: wire.OutPoint{Index: 1},
 				AnchorSignDescriptor: input.SignDescriptor{
 					Output: &wire.TxOut{Value: 1},
 				},
 			},
 			Remote: &lnwallet.AnchorResolution{
+				CommitAnchor: wire.OutPoint{Index: 2},
 				AnchorSignDescriptor: input.SignDescriptor{
 					Output: &wire.TxOut{Value: 1},
 				},
@@ -2550,10 +2555,12 @@ func TestChannelArbitratorAnchors(t *testing.T) {
 		RefundTimeout: htlcExpiryBase + 2,
 		RHash:         rHash,
 		Incoming:      true,
+		Amt:           200_000_000,
 	}
 	htlc := channeldb.HTLC{
 		HtlcIndex:     100,
 		RefundTimeout: htlcExpiryBase + 3,
+		Amt:           100_000_000,
 	}
 
 	// We now send two HTLC updates, one for local HTLC set and the other
@@ -2703,6 +2710,172 @@ func TestChannelArbitratorAnchors(t *testing.T) {
 		chanArbCtx.sweeper.deadlines[1],
 	)
 
+	// Both anchors protect the two HTLCs on their commitment, so they
+	// should be bumped towards the same deadline height with a budget
+	// taken from the value of both HTLCs.
+	expectedBudget := htlcSweepBudget(htlc.Amt + htlcWithPreimage.Amt)
+	require.Equal(t, []int32{
+		int32(htlcWithPreimage.RefundTimeout),
+		int32(htlcWithPreimage.RefundTimeout),
+	}, chanArbCtx.sweeper.deadlineHeights)
+	require.Equal(t, []bronutil.Amount{
+		expectedBudget, expectedBudget,
+	}, chanArbCtx.sweeper.budgets)
+}
+
+// TestChannelArbitratorAnchorsRebump asserts that the anchors of a broadcast
+// commitment are checked every block, and only re-offered to the sweeper once
+// their deadline or budget changes.
+func TestChannelArbitratorAnchorsRebump(t *testing.T) {
+	log := &mockArbitratorLog{
+		state:     StateDefault,
+		newStates: make(chan ArbitratorState, 5),
+	}
+
+	chanArbCtx, err := createTestChannelArbitrator(t, log)
+	require.NoError(t, err)
+
+	// The preimage of the incoming HTLC isn't known at first.
+	rHash := [lntypes.PreimageSize]byte{1, 2, 3}
+	mockPreimageDB := newMockWitnessBeacon()
+
+	chanArb := chanArbCtx.chanArb
+	chanArb.cfg.PreimageDB = mockPreimageDB
+	chanArb.cfg.Registry = &mockRegistry{}
+
+	localAnchor := wire.OutPoint{Index: 1}
+	remoteAnchor := wire.OutPoint{Index: 2}
+	chanArb.cfg.Channel.(*mockChannel).anchorResolutions =
+		&lnwallet.AnchorResolutions{
+			Local: &lnwallet.AnchorResolution{
+				CommitAnchor: localAnchor,
+				AnchorSignDescriptor: input.SignDescriptor{
+					Output: &wire.TxOut{Value: 1},
+				},
+			},
+			Remote: &lnwallet.AnchorResolution{
+				CommitAnchor: remoteAnchor,
+				AnchorSignDescriptor: input.SignDescriptor{
+					Output: &wire.TxOut{Value: 1},
+				},
+			},
+		}
+
+	require.NoError(t, chanArb.Start(nil))
+	defer func() {
+		require.NoError(t, chanArb.Stop())
+	}()
+
+	htlcUpdates := make(chan *ContractUpdate)
+	chanArb.UpdateContractSignals(&ContractSignals{
+		HtlcUpdates: htlcUpdates,
+		ShortChanID: lnwire.ShortChannelID{},
+	})
+
+	heightHint := uint32(1000)
+	chanArbCtx.chanArb.blocks <- int32(heightHint)
+
+	htlcExpiryBase := heightHint + uint32(10)
+	incomingHtlc := channeldb.HTLC{
+		HtlcIndex:     99,
+		RefundTimeout: htlcExpiryBase + 2,
+		RHash:         rHash,
+		Incoming:      true,
+		Amt:           200_000_000,
+	}
+	outgoingHtlc := channeldb.HTLC{
+		HtlcIndex:     100,
+		RefundTimeout: htlcExpiryBase + 3,
+		Amt:           100_000_000,
+	}
+	htlcs := []channeldb.HTLC{incomingHtlc, outgoingHtlc}
+	htlcUpdates <- &ContractUpdate{
+		HtlcKey: LocalHtlcSet,
+		Htlcs:   htlcs,
+	}
+	htlcUpdates <- &ContractUpdate{
+		HtlcKey: RemoteHtlcSet,
+		Htlcs:   htlcs,
+	}
+
+	errChan := make(chan error, 1)
+	respChan := make(chan *wire.MsgTx, 1)
+	chanArb.forceCloseReqs <- &forceCloseReq{
+		errResp: errChan,
+		closeTx: respChan,
+	}
+
+	chanArbCtx.AssertStateTransitions(
+		StateBroadcastCommit,
+		StateCommitmentBroadcasted,
+	)
+
+	assertAnchorsOffered := func(deadlineHeight int32,
+		budget bronutil.Amount) {
+
+		t.Helper()
+
+		for i := 0; i < 2; i++ {
+			select {
+			case <-chanArbCtx.sweeper.sweptInputs:
+			case <-time.After(5 * time.Second):
+				t.Fatalf("anchor not offered to sweeper")
+			}
+		}
+
+		numOffers := len(chanArbCtx.sweeper.budgets)
+		require.GreaterOrEqual(t, numOffers, 2)
+		require.Equal(t, []int32{deadlineHeight, deadlineHeight},
+			chanArbCtx.sweeper.deadlineHeights[numOffers-2:])
+		require.Equal(t, []bronutil.Amount{budget, budget},
+			chanArbCtx.sweeper.budgets[numOffers-2:])
+	}
+
+	assertNoAnchorsOffered := func() {
+		t.Helper()
+
+		select {
+		case <-chanArbCtx.sweeper.sweptInputs:
+			t.Fatalf("anchor unexpectedly re-offered to sweeper")
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+
+	// Only the outgoing HTLC is at stake, so the anchors are offered with
+	// its deadline and a budget taken from its value.
+	assertAnchorsOffered(
+		int32(outgoingHtlc.RefundTimeout),
+		htlcSweepBudget(outgoingHtlc.Amt),
+	)
+
+	select {
+	case err := <-errChan:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatalf("no response received")
+	}
+
+	// A new block doesn't change the deadline or budget of the anchors,
+	// so they aren't re-offered, as the sweeper bumps them by itself.
+	chanArbCtx.chanArb.blocks <- int32(heightHint + 1)
+	assertNoAnchorsOffered()
+
+	// Once we learn the preimage of the incoming HTLC, it's at stake as
+	// well. The next block should re-offer the anchors with its earlier
+	// deadline and a budget covering both HTLCs.
+	mockPreimageDB.mu.Lock()
+	mockPreimageDB.lookupPreimage[rHash] = rHash
+	mockPreimageDB.mu.Unlock()
+
+	chanArbCtx.chanArb.blocks <- int32(heightHint + 2)
+	assertAnchorsOffered(
+		int32(incomingHtlc.RefundTimeout),
+		htlcSweepBudget(incomingHtlc.Amt+outgoingHtlc.Amt),
+	)
+
+	// Without any further changes, the anchors aren't re-offered again.
+	chanArbCtx.chanArb.blocks <- int32(heightHint + 3)
+	assertNoAnchorsOffered()
 }
 
 // putResolverReportInChannel returns a put report function which will pipe
@@ -18,12 +18,6 @@ import (
 	"github.com/brronsuite/broln/sweep"
 )
 
-const (
-	// commitOutputConfTarget is the default confirmation target we'll use
-	// for sweeps of commit outputs that belong to us.
-	commitOutputConfTarget = 6
-)
-
 // commitSweepResolver is a resolver that will attempt to sweep the commitment
 // output paying to us, in the case that the remote party broadcasts their
 // version of the commitment transaction. We can sweep this output immediately,
@@ -317,8 +311,13 @@ func (c *commitSweepResolver) Resolve() (ContractResolver, error) {
 	// sweeper.
 	c.log.Infof("sweeping commit output")
 
-	feePref := sweep.FeePreference{ConfTarget: commitOutputConfTarget}
-	resultChan, err := c.Sweeper.SweepInput(inp, sweep.Params{Fee: feePref})
+	// The output isn't time-sensitive, so the sweeper gives it its
+	// default deadline and raises its fee rate towards it within its
+	// budget.
+	value := c.commitResolution.SelfOutputSignDesc.Output.Value
+	resultChan, err := c.Sweeper.SweepInput(
+		inp, sweep.Params{Budget: sweepBudget(bronutil.Amount(value))},
+	)
 	if err != nil {
 		c.log.Errorf("unable to sweep input: %v", err)
 
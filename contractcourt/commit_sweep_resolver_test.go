@@ -110,6 +110,11 @@ type mockSweeper struct {
 	createSweepTxChan chan *wire.MsgTx
 
 	deadlines []int
+
+	// deadlineHeights and budgets record the deadline heights and budgets
+	// of the inputs that were offered with a deadline height.
+	deadlineHeights []int32
+	budgets         []bronutil.Amount
 }
 
 func newMockSweeper() *mockSweeper {
@@ -125,13 +130,20 @@ func newMockSweeper() *mockSweeper {
 func (s *mockSweeper) SweepInput(input input.Input, params sweep.Params) (
 	chan sweep.Result, error) {
 
-	s.sweptInputs <- input
-
 	// Update the deadlines used if it's set.
 	if params.Fee.ConfTarget != 0 {
 		s.deadlines = append(s.deadlines, int(params.Fee.ConfTarget))
 	}
 
+	if params.DeadlineHeight != nil {
+		s.deadlineHeights = append(
+			s.deadlineHeights, *params.DeadlineHeight,
+		)
+		s.budgets = append(s.budgets, params.Budget)
+	}
+
+	s.sweptInputs <- input
+
 	result := make(chan sweep.Result, 1)
 	result <- sweep.Result{
 		Tx:  s.sweepTx,
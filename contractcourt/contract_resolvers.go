@@ -8,8 +8,10 @@ import (
 
 	"github.com/brronsuite/broln/build"
 	"github.com/brronsuite/broln/channeldb"
+	"github.com/brronsuite/broln/lnwire"
 	"github.com/brronsuite/brond/wire"
 	"github.com/brronsuite/bronlog"
+	"github.com/brronsuite/bronutil"
 )
 
 var (
@@ -17,15 +19,24 @@ var (
 )
 
 const (
-	// sweepConfTarget is the default number of blocks that we'll use as a
-	// confirmation target when sweeping.
-	sweepConfTarget = 6
-
-	// secondLevelConfTarget is the confirmation target we'll use when
-	// adding fees to our second-level HTLC transactions.
-	secondLevelConfTarget = 6
+	// sweepBudgetRatio is the share of the value of an output that we're
+	// willing to pay in fees to get it swept before its deadline.
+	sweepBudgetRatio = 0.5
 )
 
+// sweepBudget returns the budget for sweeping an output of the given value.
+// Outputs without a deadline are given the default deadline of the sweeper.
+func sweepBudget(amt bronutil.Amount) bronutil.Amount {
+	return bronutil.Amount(float64(amt) * sweepBudgetRatio)
+}
+
+// htlcSweepBudget returns the budget for getting HTLCs of the given total
+// amount confirmed on chain, either through their second-level transactions or
+// by anchoring down the commitment they are on.
+func htlcSweepBudget(amt lnwire.MilliBronees) bronutil.Amount {
+	return sweepBudget(amt.ToBroneess())
+}
+
 // ContractResolver is an interface which packages a state machine which is
 // able to carry out the necessary steps required to fully resolve a Brocoin
 // contract on-chain. Resolvers are fully encodable to ensure callers are able
@@ -217,11 +217,17 @@ func (h *htlcIncomingContestResolver) Resolve() (ContractResolver, error) {
 		}
 	}
 
+	// Htlcs that carry a trampoline onion reach us as the exit hop, but
+	// don't pay one of our invoices. We forwarded them by paying the next
+	// trampoline node instead, so like for regular forwards, we learn the
+	// preimage from the outgoing payment through the preimage db.
+	isTrampoline := len(payload.TrampolineOnion) > 0
+
 	var (
 		hodlChan       chan interface{}
 		witnessUpdates <-chan lntypes.Preimage
 	)
-	if payload.FwdInfo.NextHop == hop.Exit {
+	if payload.FwdInfo.NextHop == hop.Exit && !isTrampoline {
 		// Create a buffered hodl chan to prevent deadlock.
 		hodlChan = make(chan interface{}, 1)
 
@@ -443,9 +449,17 @@ func (h *htlcIncomingContestResolver) SupplementState(_ *channeldb.OpenChannel)
 // decodePayload (re)decodes the hop payload of a received htlc.
 func (h *htlcIncomingContestResolver) decodePayload() (*hop.Payload, error) {
 
+	// The blinding point, amount and expiry of the HTLC are needed to
+	// process the payload of a blinded route.
+	blindingInfo := hop.ReconstructBlindingInfo{
+		BlindingPoint:  h.htlc.BlindingPoint,
+		IncomingAmount: h.htlc.Amt,
+		IncomingCltv:   h.htlcExpiry,
+	}
+
 	onionReader := bytes.NewReader(h.htlc.OnionBlob)
 	iterator, err := h.OnionProcessor.ReconstructHopIterator(
-		onionReader, h.htlc.RHash[:],
+		onionReader, h.htlc.RHash[:], blindingInfo,
 	)
 	if err != nil {
 		return nil, err
@@ -15,6 +15,7 @@ import (
 	"github.com/brronsuite/broln/lntypes"
 	"github.com/brronsuite/broln/lnwallet"
 	"github.com/brronsuite/broln/lnwire"
+	"github.com/brronsuite/brond/bronec"
 	sphinx "github.com/brronsuite/lightning-onion"
 )
 
@@ -144,6 +145,42 @@ func TestHtlcIncomingResolverExitSettle(t *testing.T) {
 	}
 }
 
+// TestHtlcIncomingResolverExitBlinded tests that the blinding point, amount
+// and expiry of an htlc are passed on when reconstructing its hop iterator, as
+// they're needed to process the payload of a blinded route.
+func TestHtlcIncomingResolverExitBlinded(t *testing.T) {
+	t.Parallel()
+	defer timeout(t)()
+
+	_, blindingPoint := bronec.PrivKeyFromBytes(
+		bronec.S256(), testResPreimage[:],
+	)
+
+	ctx := newIncomingResolverTestContext(t, true)
+	ctx.resolver.htlc.BlindingPoint = blindingPoint
+	ctx.registry.notifyResolution = invoices.NewSettleResolution(
+		testResPreimage, testResCircuitKey, testAcceptHeight,
+		invoices.ResultReplayToSettled,
+	)
+
+	ctx.resolve()
+	<-ctx.registry.notifyChan
+	ctx.waitForResult(true)
+
+	blindingInfo := ctx.onionProcessor.blindingInfo
+	if blindingInfo.BlindingPoint != blindingPoint {
+		t.Fatal("blinding point not passed on")
+	}
+	if blindingInfo.IncomingAmount != testHtlcAmount {
+		t.Fatalf("expected amount %v, got %v", testHtlcAmount,
+			blindingInfo.IncomingAmount)
+	}
+	if blindingInfo.IncomingCltv != testHtlcExpiry {
+		t.Fatalf("expected expiry %v, got %v", testHtlcExpiry,
+			blindingInfo.IncomingCltv)
+	}
+}
+
 // TestHtlcIncomingResolverExitCancel tests resolution of an exit hop htlc for
 // an invoice that is already canceled when the resolver starts.
 func TestHtlcIncomingResolverExitCancel(t *testing.T) {
@@ -255,8 +292,35 @@ func TestHtlcIncomingResolverExitCancelHodl(t *testing.T) {
 	ctx.waitForResult(false)
 }
 
+// TestHtlcIncomingResolverTrampolineContestedSuccess tests resolution of an
+// htlc that carries a trampoline onion, for which the preimage is learned from
+// the outgoing trampoline payment after the resolver has been started.
+func TestHtlcIncomingResolverTrampolineContestedSuccess(t *testing.T) {
+	t.Parallel()
+	defer timeout(t)()
+
+	ctx := newIncomingResolverTestContext(t, true)
+	ctx.onionProcessor.trampolineOnion = []byte{7, 8, 9}
+	ctx.resolve()
+
+	// Simulate a new block coming in. HTLC is not yet expired.
+	ctx.notifyEpoch(testInitialBlockHeight + 1)
+
+	ctx.witnessBeacon.preImageUpdates <- testResPreimage
+	ctx.waitForResult(true)
+
+	// The htlc doesn't pay one of our invoices, so the registry should
+	// not have been asked to resolve it.
+	select {
+	case <-ctx.registry.notifyChan:
+		t.Fatal("registry notified of trampoline htlc")
+	default:
+	}
+}
+
 type mockHopIterator struct {
-	isExit bool
+	isExit          bool
+	trampolineOnion []byte
 	hop.Iterator
 }
 
@@ -266,30 +330,39 @@ func (h *mockHopIterator) HopPayload() (*hop.Payload, error) {
 		nextAddress = [8]byte{0x01}
 	}
 
-	return hop.NewLegacyPayload(&sphinx.HopData{
+	payload := hop.NewLegacyPayload(&sphinx.HopData{
 		Realm:         [1]byte{},
 		NextAddress:   nextAddress,
 		ForwardAmount: 100,
 		OutgoingCltv:  40,
 		ExtraBytes:    [12]byte{},
-	}), nil
+	})
+	payload.TrampolineOnion = h.trampolineOnion
+
+	return payload, nil
 }
 
 type mockOnionProcessor struct {
 	isExit           bool
+	trampolineOnion  []byte
 	offeredOnionBlob []byte
+	blindingInfo     hop.ReconstructBlindingInfo
 }
 
-func (o *mockOnionProcessor) ReconstructHopIterator(r io.Reader, rHash []byte) (
-	hop.Iterator, error) {
+func (o *mockOnionProcessor) ReconstructHopIterator(r io.Reader, rHash []byte,
+	blindingInfo hop.ReconstructBlindingInfo) (hop.Iterator, error) {
 
 	data, err := ioutil.ReadAll(r)
 	if err != nil {
 		return nil, err
 	}
 	o.offeredOnionBlob = data
+	o.blindingInfo = blindingInfo
 
-	return &mockHopIterator{isExit: o.isExit}, nil
+	return &mockHopIterator{
+		isExit:          o.isExit,
+		trampolineOnion: o.trampolineOnion,
+	}, nil
 }
 
 type incomingResolverTestContext struct {
@@ -47,13 +47,6 @@ type htlcSuccessResolver struct {
 	// historical queries to the chain for spends/confirmations.
 	broadcastHeight uint32
 
-	// sweepTx will be non-nil if we've already crafted a transaction to
-	// sweep a direct HTLC output. This is only a concern if we're sweeping
-	// from the commitment transaction of the remote party.
-	//
-	// TODO(roasbeef): send off to utxobundler
-	sweepTx *wire.MsgTx
-
 	// htlc contains information on the htlc that we are resolving on-chain.
 	htlc channeldb.HTLC
 
@@ -240,12 +233,15 @@ func (h *htlcSuccessResolver) broadcastReSignedSuccessTx() (
 			h.htlcResolution.SignDetails, h.htlcResolution.Preimage,
 			h.broadcastHeight,
 		)
+
+		// The success transaction must confirm before the HTLC
+		// expires, as the remote party can time it out from then on.
+		deadline := int32(h.htlc.RefundTimeout)
 		_, err := h.Sweeper.SweepInput(
 			&secondLevelInput,
 			sweep.Params{
-				Fee: sweep.FeePreference{
-					ConfTarget: secondLevelConfTarget,
-				},
+				Budget:         htlcSweepBudget(h.htlc.Amt),
+				DeadlineHeight: &deadline,
 			},
 		)
 		if err != nil {
@@ -332,14 +328,13 @@ func (h *htlcSuccessResolver) broadcastReSignedSuccessTx() (
 		&h.htlcResolution.SweepSignDesc, h.broadcastHeight,
 		h.htlcResolution.CsvDelay,
 	)
-	_, err = h.Sweeper.SweepInput(
-		inp,
-		sweep.Params{
-			Fee: sweep.FeePreference{
-				ConfTarget: sweepConfTarget,
-			},
-		},
+
+	// The second-level output only pays to us, so it isn't time-sensitive
+	// anymore and the sweeper gives it its default deadline.
+	budget := sweepBudget(
+		bronutil.Amount(h.htlcResolution.SweepSignDesc.Output.Value),
 	)
+	_, err = h.Sweeper.SweepInput(inp, sweep.Params{Budget: budget})
 	if err != nil {
 		return nil, err
 	}
@@ -355,97 +350,71 @@ func (h *htlcSuccessResolver) broadcastReSignedSuccessTx() (
 func (h *htlcSuccessResolver) resolveRemoteCommitOutput() (
 	ContractResolver, error) {
 
-	// If we don't already have the sweep transaction constructed, we'll do
-	// so and broadcast it.
-	if h.sweepTx == nil {
-		log.Infof("%T(%x): crafting sweep tx for incoming+remote "+
-			"htlc confirmed", h, h.htlc.RHash[:])
-
-		// Before we can craft out sweeping transaction, we need to
-		// create an input which contains all the items required to add
-		// this input to a sweeping transaction, and generate a
-		// witness.
-		inp := input.MakeHtlcSucceedInput(
-			&h.htlcResolution.ClaimOutpoint,
-			&h.htlcResolution.SweepSignDesc,
-			h.htlcResolution.Preimage[:],
-			h.broadcastHeight,
-			h.htlcResolution.CsvDelay,
-		)
-
-		// With the input created, we can now generate the full sweep
-		// transaction, that we'll use to move these coins back into
-		// the backing wallet.
-		//
-		// TODO: Set tx lock time to current block height instead of
-		// zero. Will be taken care of once sweeper implementation is
-		// complete.
-		//
-		// TODO: Use time-based sweeper and result chan.
-		var err error
-		h.sweepTx, err = h.Sweeper.CreateSweepTx(
-			[]input.Input{&inp},
-			sweep.FeePreference{
-				ConfTarget: sweepConfTarget,
-			}, 0,
-		)
-		if err != nil {
-			return nil, err
-		}
-
-		log.Infof("%T(%x): crafted sweep tx=%v", h,
-			h.htlc.RHash[:], spew.Sdump(h.sweepTx))
-
-		// TODO(halseth): should checkpoint sweep tx to DB? Since after
-		// a restart we might create a different tx, that will conflict
-		// with the published one.
-	}
-
-	// Regardless of whether an existing transaction was found or newly
-	// constructed, we'll broadcast the sweep transaction to the network.
-	label := labels.MakeLabel(
-		labels.LabelTypeChannelClose, &h.ShortChanID,
+	log.Infof("%T(%x): offering direct preimage HTLC output to sweeper: "+
+		"%v", h, h.htlc.RHash[:], h.htlcResolution.ClaimOutpoint)
+
+	// Before we can offer the output to the sweeper, we need to create an
+	// input which contains all the items required to add this input to a
+	// sweeping transaction, and generate a witness.
+	inp := input.MakeHtlcSucceedInput(
+		&h.htlcResolution.ClaimOutpoint,
+		&h.htlcResolution.SweepSignDesc,
+		h.htlcResolution.Preimage[:],
+		h.broadcastHeight,
+		h.htlcResolution.CsvDelay,
 	)
-	err := h.PublishTx(h.sweepTx, label)
-	if err != nil {
-		log.Infof("%T(%x): unable to publish tx: %v",
-			h, h.htlc.RHash[:], err)
-		return nil, err
-	}
 
-	// With the sweep transaction broadcast, we'll wait for its
-	// confirmation.
-	sweepTXID := h.sweepTx.TxHash()
-	sweepScript := h.sweepTx.TxOut[0].PkScript
-	confNtfn, err := h.Notifier.RegisterConfirmationsNtfn(
-		&sweepTXID, sweepScript, 1, h.broadcastHeight,
+	// The output must be swept before the HTLC expires, as the remote
+	// party can time it out from then on. The sweeper raises the fee rate
+	// of the sweep every block until it confirms.
+	deadline := int32(h.htlc.RefundTimeout)
+	resultChan, err := h.Sweeper.SweepInput(
+		&inp,
+		sweep.Params{
+			Budget:         htlcSweepBudget(h.htlc.Amt),
+			DeadlineHeight: &deadline,
+		},
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	log.Infof("%T(%x): waiting for sweep tx (txid=%v) to be "+
-		"confirmed", h, h.htlc.RHash[:], sweepTXID)
-
+	// The sweeper signals us through the result channel once the output
+	// has been spent, either by our sweep or by the remote party.
+	var sweepResult sweep.Result
 	select {
-	case _, ok := <-confNtfn.Confirmed:
-		if !ok {
-			return nil, errResolverShuttingDown
-		}
-
+	case sweepResult = <-resultChan:
 	case <-h.quit:
 		return nil, errResolverShuttingDown
 	}
 
-	// Once the transaction has received a sufficient number of
-	// confirmations, we'll mark ourselves as fully resolved and exit.
+	outcome := channeldb.ResolverOutcomeClaimed
+	switch sweepResult.Err {
+	case nil:
+		log.Infof("%T(%x): HTLC output swept by tx %v", h,
+			h.htlc.RHash[:], sweepResult.Tx.TxHash())
+
+	// The remote party timed out the HTLC before our sweep confirmed.
+	case sweep.ErrRemoteSpend:
+		log.Warnf("%T(%x): HTLC output timed out by remote party via "+
+			"%v", h, h.htlc.RHash[:], sweepResult.Tx.TxHash())
+
+		outcome = channeldb.ResolverOutcomeTimeout
+
+	default:
+		log.Errorf("%T(%x): unable to sweep HTLC output: %v", h,
+			h.htlc.RHash[:], sweepResult.Err)
+
+		return nil, sweepResult.Err
+	}
+
+	// Once the transaction has confirmed, we'll mark ourselves as fully
+	// resolved and exit.
 	h.resolved = true
 
 	// Checkpoint the resolver, and write the outcome to disk.
-	return nil, h.checkpointClaim(
-		&sweepTXID,
-		channeldb.ResolverOutcomeClaimed,
-	)
+	sweepTxID := sweepResult.Tx.TxHash()
+	return nil, h.checkpointClaim(&sweepTxID, outcome)
 }
 
 // checkpointClaim checkpoints the success resolver with the reports it needs.
@@ -21,6 +21,10 @@ import (
 
 var testHtlcAmt = lnwire.MilliBronees(200000)
 
+// testIncomingHtlcExpiry is the expiry height of the incoming HTLC of the
+// forward that outgoing HTLCs under test belong to.
+const testIncomingHtlcExpiry = 500
+
 type htlcResolverTestContext struct {
 	resolver ContractResolver
 
@@ -80,6 +84,9 @@ func newHtlcResolverTestContext(t *testing.T,
 
 			return nil
 		},
+		FindOutgoingHTLCDeadline: func(channeldb.HTLC) (int32, bool) {
+			return testIncomingHtlcExpiry, true
+		},
 	}
 	// Since we want to replace this checkpoint method later in the test,
 	// we wrap the call to it in a closure. The linter will complain about
@@ -135,10 +142,9 @@ func (i *htlcResolverTestContext) waitForResult() {
 func TestHtlcSuccessSingleStage(t *testing.T) {
 	htlcOutpoint := wire.OutPoint{Index: 3}
 
-	sweepTx := &wire.MsgTx{
-		TxIn:  []*wire.TxIn{{}},
-		TxOut: []*wire.TxOut{{}},
-	}
+	// The mock sweeper reports the output to be swept by an empty
+	// transaction.
+	sweepTx := &wire.MsgTx{}
 
 	// singleStageResolution is a resolution for a htlc on the remote
 	// party's commitment.
@@ -158,20 +164,33 @@ func TestHtlcSuccessSingleStage(t *testing.T) {
 
 	checkpoints := []checkpoint{
 		{
-			// We send a confirmation for our sweep tx to indicate
-			// that our sweep succeeded.
+			// The resolver offers the output to the sweeper,
+			// which signals that our sweep succeeded.
 			preCheckpoint: func(ctx *htlcResolverTestContext,
 				_ bool) error {
-				// The resolver will create and publish a sweep
-				// tx.
+
 				resolver := ctx.resolver.(*htlcSuccessResolver)
-				resolver.Sweeper.(*mockSweeper).
-					createSweepTxChan <- sweepTx
+				sweeper := resolver.Sweeper.(*mockSweeper)
+				inp := <-sweeper.sweptInputs
+				if *inp.OutPoint() != htlcOutpoint {
+					return fmt.Errorf("outpoint %v swept, "+
+						"expected %v", inp.OutPoint(),
+						htlcOutpoint)
+				}
 
-				// Confirm the sweep, which should resolve it.
-				ctx.notifier.ConfChan <- &chainntnfs.TxConfirmation{
-					Tx:          sweepTx,
-					BlockHeight: testInitialBlockHeight - 1,
+				// The output must be swept before the HTLC
+				// expires.
+				expected := []int32{
+					int32(resolver.htlc.RefundTimeout),
+				}
+				if !reflect.DeepEqual(
+					sweeper.deadlineHeights, expected,
+				) {
+
+					return fmt.Errorf("deadlines %v, "+
+						"expected %v",
+						sweeper.deadlineHeights,
+						expected)
 				}
 
 				return nil
@@ -344,14 +344,19 @@ func (h *htlcTimeoutResolver) spendHtlcOutput() (*chainntnfs.SpendDetail, error)
 			h.htlcResolution.SignDetails,
 			h.broadcastHeight,
 		)
-		_, err := h.Sweeper.SweepInput(
-			&inp,
-			sweep.Params{
-				Fee: sweep.FeePreference{
-					ConfTarget: secondLevelConfTarget,
-				},
-			},
-		)
+
+		// The timeout transaction must confirm before the incoming
+		// HTLC of a forward expires, as our peer on the incoming side
+		// can time it out from then on while the remote party can still
+		// claim the outgoing HTLC with the preimage. HTLCs of our own
+		// payments have no such deadline, so the sweeper gives them its
+		// default one.
+		params := sweep.Params{Budget: htlcSweepBudget(h.htlc.Amt)}
+		deadline, ok := h.FindOutgoingHTLCDeadline(h.htlc)
+		if ok {
+			params.DeadlineHeight = &deadline
+		}
+		_, err := h.Sweeper.SweepInput(&inp, params)
 		if err != nil {
 			return nil, err
 		}
@@ -506,12 +511,15 @@ func (h *htlcTimeoutResolver) handleCommitSpend(
 				h.broadcastHeight, h.htlcResolution.CsvDelay,
 			)
 		}
+
+		// The second-level output only pays to us, so it isn't
+		// time-sensitive anymore and the sweeper gives it its default
+		// deadline.
+		value := h.htlcResolution.SweepSignDesc.Output.Value
 		_, err = h.Sweeper.SweepInput(
 			inp,
 			sweep.Params{
-				Fee: sweep.FeePreference{
-					ConfTarget: sweepConfTarget,
-				},
+				Budget: sweepBudget(bronutil.Amount(value)),
 			},
 		)
 		if err != nil {
@@ -25,7 +25,11 @@ import (
 type mockWitnessBeacon struct {
 	preImageUpdates chan lntypes.Preimage
 	newPreimages    chan []lntypes.Preimage
-	lookupPreimage  map[lntypes.Hash]lntypes.Preimage
+
+	// mu guards lookupPreimage against tests that add preimages while
+	// the preimage is being looked up.
+	mu             sync.Mutex
+	lookupPreimage map[lntypes.Hash]lntypes.Preimage
 }
 
 func newMockWitnessBeacon() *mockWitnessBeacon {
@@ -44,6 +48,9 @@ func (m *mockWitnessBeacon) SubscribeUpdates() *WitnessSubscription {
 }
 
 func (m *mockWitnessBeacon) LookupPreimage(payhash lntypes.Hash) (lntypes.Preimage, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	preimage, ok := m.lookupPreimage[payhash]
 	if !ok {
 		return lntypes.Preimage{}, false
@@ -292,6 +299,11 @@ func TestHtlcTimeoutResolver(t *testing.T) {
 
 				return nil
 			},
+			FindOutgoingHTLCDeadline: func(
+				channeldb.HTLC) (int32, bool) {
+
+				return 0, false
+			},
 		}
 
 		cfg := ResolverConfig{
@@ -1012,7 +1024,8 @@ func TestHtlcTimeoutSecondStageSweeper(t *testing.T) {
 				_ bool) error {
 
 				resolver := ctx.resolver.(*htlcTimeoutResolver)
-				inp := <-resolver.Sweeper.(*mockSweeper).sweptInputs
+				sweeper := resolver.Sweeper.(*mockSweeper)
+				inp := <-sweeper.sweptInputs
 				op := inp.OutPoint()
 				if *op != commitOutpoint {
 					return fmt.Errorf("outpoint %v swept, "+
@@ -1020,6 +1033,19 @@ func TestHtlcTimeoutSecondStageSweeper(t *testing.T) {
 						commitOutpoint)
 				}
 
+				// The timeout tx must confirm before the
+				// incoming HTLC of the forward expires.
+				expected := []int32{testIncomingHtlcExpiry}
+				if !reflect.DeepEqual(
+					sweeper.deadlineHeights, expected,
+				) {
+
+					return fmt.Errorf("deadlines %v, "+
+						"expected %v",
+						sweeper.deadlineHeights,
+						expected)
+				}
+
 				// Emulat the sweeper spending using the
 				// re-signed timeout tx.
 				ctx.notifier.SpendChan <- &chainntnfs.SpendDetail{
@@ -154,12 +154,6 @@ import (
 
 var byteOrder = binary.BigEndian
 
-const (
-	// kgtnOutputConfTarget is the default confirmation target we'll use for
-	// sweeps of CSV delayed outputs.
-	kgtnOutputConfTarget = 6
-)
-
 var (
 	// ErrContractNotFound is returned when the nursery is unable to
 	// retrieve information about a queried contract.
@@ -775,14 +769,18 @@ func (u *UtxoNursery) sweepMatureOutputs(classHeight uint32,
 	utxnLog.Infof("Sweeping %v CSV-delayed outputs with sweep tx for "+
 		"height %v", len(kgtnOutputs), classHeight)
 
-	feePref := sweep.FeePreference{ConfTarget: kgtnOutputConfTarget}
 	for _, output := range kgtnOutputs {
 		// Create local copy to prevent pointer to loop variable to be
 		// passed in with disastrous consequences.
 		local := output
 
+		// The outputs aren't time-sensitive anymore, so the sweeper
+		// gives them its default deadline and raises their fee rate
+		// towards it within their budget.
 		resultChan, err := u.cfg.SweepInput(
-			&local, sweep.Params{Fee: feePref},
+			&local, sweep.Params{
+				Budget: sweepBudget(local.Amount()),
+			},
 		)
 		if err != nil {
 			return err
@@ -7,6 +7,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/brronsuite/broln/aliasmgr"
 	"github.com/brronsuite/broln/batch"
 	"github.com/brronsuite/broln/chainntnfs"
 	"github.com/brronsuite/broln/channeldb"
@@ -1845,6 +1846,24 @@ func (d *AuthenticatedGossiper) processNetworkAnnouncement(
 			return nil, false
 		}
 
+		// Alias SCIDs are only assigned locally to our own channels and
+		// don't point to a funding output, so an announcement of one
+		// by a remote peer can't be validated and is rejected.
+		if nMsg.isRemote && aliasmgr.IsAlias(msg.ShortChannelID) {
+			err := fmt.Errorf("ignoring ChannelAnnouncement for "+
+				"alias chan_id=%v", msg.ShortChannelID)
+			log.Errorf(err.Error())
+
+			key := newRejectCacheKey(
+				msg.ShortChannelID.ToUint64(),
+				sourceToPub(nMsg.source),
+			)
+			_, _ = d.recentRejects.Put(key, &cachedReject{})
+
+			nMsg.err <- err
+			return nil, false
+		}
+
 		// If the advertised inclusionary block is beyond our knowledge
 		// of the chain tip, then we'll ignore for it now.
 		d.Lock()
@@ -1942,8 +1961,18 @@ func (d *AuthenticatedGossiper) processNetworkAnnouncement(
 		// no other goroutine has read the database and is now
 		// making decisions based on this DB state, before it
 		// writes to the DB.
+		//
+		// Our own channels with an alias SCID are added without being
+		// validated against the chain, as the alias doesn't point to a
+		// funding output. Remote announcements of aliases were
+		// rejected above.
 		d.channelMtx.Lock(msg.ShortChannelID.ToUint64())
-		err := d.cfg.Router.AddEdge(edge, schedulerOp...)
+		var err error
+		if aliasmgr.IsAlias(msg.ShortChannelID) {
+			err = d.cfg.Router.AddAliasEdge(edge, schedulerOp...)
+		} else {
+			err = d.cfg.Router.AddEdge(edge, schedulerOp...)
+		}
 		if err != nil {
 			defer d.channelMtx.Unlock(msg.ShortChannelID.ToUint64())
 
@@ -16,6 +16,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/brronsuite/broln/aliasmgr"
 	"github.com/brronsuite/broln/batch"
 	"github.com/brronsuite/broln/chainntnfs"
 	"github.com/brronsuite/broln/channeldb"
@@ -154,6 +155,12 @@ func (r *mockGraphSource) AddEdge(info *channeldb.ChannelEdgeInfo,
 	return nil
 }
 
+func (r *mockGraphSource) AddAliasEdge(info *channeldb.ChannelEdgeInfo,
+	op ...batch.SchedulerOption) error {
+
+	return r.AddEdge(info, op...)
+}
+
 func (r *mockGraphSource) queueValidationFail(chanID uint64) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -956,6 +963,35 @@ func TestPrematureAnnouncement(t *testing.T) {
 	}
 }
 
+// TestRemoteAliasAnnouncement checks that channel announcements of remote peers
+// for an alias SCID are rejected, as they can't be validated against the
+// chain.
+func TestRemoteAliasAnnouncement(t *testing.T) {
+	t.Parallel()
+
+	ctx, cleanup, err := createTestCtx(
+		aliasmgr.StartingAlias.BlockHeight,
+	)
+	require.NoError(t, err, "can't create context")
+	defer cleanup()
+
+	nodePeer := &mockPeer{remoteKeyPriv1.PubKey(), nil, nil}
+
+	ca, err := createRemoteChannelAnnouncement(
+		aliasmgr.StartingAlias.BlockHeight,
+	)
+	require.NoError(t, err, "can't create channel announcement")
+	require.True(t, aliasmgr.IsAlias(ca.ShortChannelID))
+
+	select {
+	case err = <-ctx.gossiper.ProcessRemoteAnnouncement(ca, nodePeer):
+	case <-time.After(time.Second):
+		t.Fatal("announcement was not processed")
+	}
+	require.Error(t, err)
+	require.Empty(t, ctx.router.infos, "edge was added to router")
+}
+
 // TestSignatureAnnouncementLocalFirst ensures that the AuthenticatedGossiper
 // properly processes partial and fully announcement signatures message.
 func TestSignatureAnnouncementLocalFirst(t *testing.T) {
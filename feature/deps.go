@@ -69,6 +69,21 @@ var deps = depDesc{
 		lnwire.ExplicitChannelTypeOptional:  {},
 		lnwire.AnchorsZeroFeeHtlcTxOptional: {},
 	},
+	lnwire.ScidAliasOptional: {
+		lnwire.ExplicitChannelTypeOptional: {},
+	},
+	lnwire.ZeroConfOptional: {
+		lnwire.ScidAliasOptional: {},
+	},
+	lnwire.RouteBlindingOptional: {
+		lnwire.TLVOnionPayloadOptional: {},
+	},
+	lnwire.SpliceOptional: {
+		lnwire.QuiescenceOptional: {},
+	},
+	lnwire.TrampolineRoutingOptional: {
+		lnwire.PaymentAddrOptional: {},
+	},
 }
 
 // ValidateDeps asserts that a feature vector sets all features and their
@@ -27,6 +27,43 @@ type Config struct {
 	// NoScriptEnforcementLease unsets any bits signaling support for script
 	// enforced leases.
 	NoScriptEnforcementLease bool
+
+	// NoOptionScidAlias unsets any bits signalling support for
+	// option_scid_alias. This also implicitly disables zero-conf channels.
+	NoOptionScidAlias bool
+
+	// NoZeroConf unsets any bits signalling support for zero-conf
+	// channels. This should be used instead of NoOptionScidAlias to still
+	// keep option-scid-alias support.
+	NoZeroConf bool
+
+	// NoOnionMessages unsets any bits signalling support for relaying
+	// onion messages.
+	NoOnionMessages bool
+
+	// NoRouteBlinding unsets any bits signalling support for forwarding
+	// and receiving payments through blinded routes.
+	NoRouteBlinding bool
+
+	// NoDualFund unsets any bits signalling support for dual funded
+	// channels.
+	NoDualFund bool
+
+	// NoSplice unsets any bits signalling support for splicing channels
+	// and the quiescence protocol it depends on.
+	NoSplice bool
+
+	// NoRbfCoopClose unsets any bits signalling support for RBF based
+	// cooperative closes.
+	NoRbfCoopClose bool
+
+	// NoTrampolineRouting unsets any bits signalling support for
+	// forwarding trampoline payments.
+	NoTrampolineRouting bool
+
+	// NoPeerStorage unsets any bits signalling support for storing backup
+	// blobs on behalf of our peers.
+	NoPeerStorage bool
 }
 
 // Manager is responsible for generating feature vectors for different requested
@@ -83,6 +120,24 @@ func newManager(cfg Config, desc setDesc) (*Manager, error) {
 			raw.Unset(lnwire.MPPRequired)
 			raw.Unset(lnwire.AMPOptional)
 			raw.Unset(lnwire.AMPRequired)
+			raw.Unset(lnwire.RouteBlindingOptional)
+			raw.Unset(lnwire.RouteBlindingRequired)
+			raw.Unset(lnwire.TrampolineRoutingOptional)
+			raw.Unset(lnwire.TrampolineRoutingRequired)
+		}
+		if cfg.NoDualFund {
+			raw.Unset(lnwire.DualFundOptional)
+			raw.Unset(lnwire.DualFundRequired)
+		}
+		if cfg.NoSplice {
+			raw.Unset(lnwire.SpliceOptional)
+			raw.Unset(lnwire.SpliceRequired)
+			raw.Unset(lnwire.QuiescenceOptional)
+			raw.Unset(lnwire.QuiescenceRequired)
+		}
+		if cfg.NoRbfCoopClose {
+			raw.Unset(lnwire.RbfCoopCloseOptional)
+			raw.Unset(lnwire.RbfCoopCloseRequired)
 		}
 		if cfg.NoStaticRemoteKey {
 			raw.Unset(lnwire.StaticRemoteKeyOptional)
@@ -115,6 +170,30 @@ func newManager(cfg Config, desc setDesc) (*Manager, error) {
 			raw.Unset(lnwire.ScriptEnforcedLeaseOptional)
 			raw.Unset(lnwire.ScriptEnforcedLeaseRequired)
 		}
+		if cfg.NoOptionScidAlias {
+			raw.Unset(lnwire.ScidAliasOptional)
+			raw.Unset(lnwire.ScidAliasRequired)
+		}
+		if cfg.NoZeroConf || cfg.NoOptionScidAlias {
+			raw.Unset(lnwire.ZeroConfOptional)
+			raw.Unset(lnwire.ZeroConfRequired)
+		}
+		if cfg.NoOnionMessages {
+			raw.Unset(lnwire.OnionMessagesOptional)
+			raw.Unset(lnwire.OnionMessagesRequired)
+		}
+		if cfg.NoRouteBlinding {
+			raw.Unset(lnwire.RouteBlindingOptional)
+			raw.Unset(lnwire.RouteBlindingRequired)
+		}
+		if cfg.NoTrampolineRouting {
+			raw.Unset(lnwire.TrampolineRoutingOptional)
+			raw.Unset(lnwire.TrampolineRoutingRequired)
+		}
+		if cfg.NoPeerStorage {
+			raw.Unset(lnwire.ProvideStorageOptional)
+			raw.Unset(lnwire.ProvideStorageRequired)
+		}
 
 		// Ensure that all of our feature sets properly set any
 		// dependent features.
@@ -62,6 +62,23 @@ func explicitNegotiateCommitmentType(channelType lnwire.ChannelType,
 
 	channelFeatures := lnwire.RawFeatureVector(channelType)
 
+	// The zero-conf and scid-alias bits are modifiers that can be applied
+	// on top of the base commitment types, so we'll check for support of
+	// them first and then strip them before matching the base type.
+	if channelFeatures.IsSet(lnwire.ZeroConfRequired) {
+		if !hasFeatures(local, remote, lnwire.ZeroConfOptional) {
+			return 0, errUnsupportedChannelType
+		}
+	}
+	if channelFeatures.IsSet(lnwire.ScidAliasRequired) {
+		if !hasFeatures(local, remote, lnwire.ScidAliasOptional) {
+			return 0, errUnsupportedChannelType
+		}
+	}
+	channelFeatures = *channelFeatures.Clone()
+	channelFeatures.Unset(lnwire.ZeroConfRequired)
+	channelFeatures.Unset(lnwire.ScidAliasRequired)
+
 	switch {
 	// Lease script enforcement + anchors zero fee + static remote key
 	// features only.
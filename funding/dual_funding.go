@@ -0,0 +1,961 @@
+package funding
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/brronsuite/broln/chainntnfs"
+	"github.com/brronsuite/broln/chanacceptor"
+	"github.com/brronsuite/broln/channeldb"
+	"github.com/brronsuite/broln/keychain"
+	"github.com/brronsuite/broln/labels"
+	"github.com/brronsuite/broln/lnpeer"
+	"github.com/brronsuite/broln/lnwallet"
+	"github.com/brronsuite/broln/lnwallet/chainfee"
+	"github.com/brronsuite/broln/lnwallet/chanfunding"
+	"github.com/brronsuite/broln/lnwire"
+	"github.com/brronsuite/brond/txscript"
+	"github.com/brronsuite/brond/wire"
+	"github.com/brronsuite/bronutil"
+	"github.com/davecgh/go-spew/spew"
+)
+
+var (
+	// ErrDualFundNotSupported is returned when a dual funded channel is
+	// requested, but either we or the remote peer don't support the
+	// dual funding protocol.
+	ErrDualFundNotSupported = errors.New("dual funded channels not " +
+		"supported")
+)
+
+// interactiveFundingCtx tracks the interactive construction of the funding
+// transaction of a dual funded channel, and the exchange of the signatures
+// for its inputs afterwards.
+type interactiveFundingCtx struct {
+	// tx is the funding transaction under construction.
+	tx *chanfunding.InteractiveTx
+
+	// initiator is true if we initiated the opening of the channel.
+	initiator bool
+
+	// queue holds the messages adding our inputs and outputs that
+	// haven't been sent to the remote party yet.
+	queue []lnwire.Message
+
+	// sentComplete is true if our last message was a TxComplete, meaning
+	// we have nothing more to add as long as the remote party doesn't
+	// change the transaction.
+	sentComplete bool
+
+	// feeRate is the fee rate of the funding transaction, which each
+	// party pays for its own inputs and outputs.
+	feeRate chainfee.SatPerKWeight
+
+	// lockTime is the lock time of the funding transaction.
+	lockTime uint32
+
+	// remoteAmt is the amount the remote party contributes to the
+	// funding output.
+	remoteAmt bronutil.Amount
+
+	// fundingTx is the final funding transaction once the construction
+	// has finished.
+	fundingTx *wire.MsgTx
+
+	// sentSigs is true once we sent the witnesses of our inputs to the
+	// remote party.
+	sentSigs bool
+}
+
+// newInteractiveFundingCtx returns a new context for the interactive
+// construction of a funding transaction.
+func newInteractiveFundingCtx(initiator bool, feeRate chainfee.SatPerKWeight,
+	lockTime uint32, remoteAmt bronutil.Amount) *interactiveFundingCtx {
+
+	return &interactiveFundingCtx{
+		tx:        chanfunding.NewInteractiveTx(initiator),
+		initiator: initiator,
+		feeRate:   feeRate,
+		lockTime:  lockTime,
+		remoteAmt: remoteAmt,
+	}
+}
+
+// dualFundSupported returns true if both we and the peer signal support for
+// dual funded channels.
+func dualFundSupported(peer lnpeer.Peer) bool {
+	return peer.LocalFeatures().HasFeature(lnwire.DualFundOptional) &&
+		peer.RemoteFeatures().HasFeature(lnwire.DualFundOptional)
+}
+
+// sendOpenChannel2 sends the OpenChannel2 message kicking off the funding
+// workflow of a dual funded channel. The funding transaction will be
+// constructed interactively once the remote party has responded.
+func (f *Manager) sendOpenChannel2(resCtx *reservationWithCtx,
+	chanID [32]byte, msg *InitFundingMsg, fundingOpen *lnwire.OpenChannel,
+	commitFeePerKw chainfee.SatPerKWeight) error {
+
+	// The funding transaction is locked to the current height, which
+	// discourages fee sniping.
+	_, bestHeight, err := f.cfg.Wallet.Cfg.ChainIO.GetBestBlock()
+	if err != nil {
+		return fmt.Errorf("unable to fetch best block: %v", err)
+	}
+
+	fundingFeePerKw := msg.FundingFeePerKw
+	if fundingFeePerKw == 0 {
+		fundingFeePerKw = commitFeePerKw
+	}
+
+	resCtx.interactive = newInteractiveFundingCtx(
+		true, fundingFeePerKw, uint32(bestHeight), 0,
+	)
+
+	ourContribution := resCtx.reservation.OurContribution()
+	openChannel2 := &lnwire.OpenChannel2{
+		ChainHash:               fundingOpen.ChainHash,
+		PendingChannelID:        chanID,
+		FundingFeePerKiloWeight: uint32(fundingFeePerKw),
+		CommitFeePerKiloWeight:  uint32(commitFeePerKw),
+		FundingAmount:           fundingOpen.FundingAmount,
+		DustLimit:               fundingOpen.DustLimit,
+		MaxValueInFlight:        fundingOpen.MaxValueInFlight,
+		HtlcMinimum:             fundingOpen.HtlcMinimum,
+		CsvDelay:                fundingOpen.CsvDelay,
+		MaxAcceptedHTLCs:        fundingOpen.MaxAcceptedHTLCs,
+		LockTime:                uint32(bestHeight),
+		FundingKey:              fundingOpen.FundingKey,
+		RevocationPoint:         fundingOpen.RevocationPoint,
+		PaymentPoint:            fundingOpen.PaymentPoint,
+		DelayedPaymentPoint:     fundingOpen.DelayedPaymentPoint,
+		HtlcPoint:               fundingOpen.HtlcPoint,
+		FirstCommitmentPoint:    fundingOpen.FirstCommitmentPoint,
+		SecondCommitmentPoint:   ourContribution.SecondCommitmentPoint,
+		ChannelFlags:            fundingOpen.ChannelFlags,
+		UpfrontShutdownScript:   fundingOpen.UpfrontShutdownScript,
+		ChannelType:             fundingOpen.ChannelType,
+	}
+
+	return msg.Peer.SendMessage(true, openChannel2)
+}
+
+// handleFundingOpen2 creates a channel reservation for a dual funded channel
+// proposed by the remote peer. The amount we contribute to the channel is
+// determined by our channel acceptor. Once the reservation is created, we
+// respond with an AcceptChannel2 message and add our inputs and outputs to
+// the funding transaction, once the initiator has started its construction.
+func (f *Manager) handleFundingOpen2(peer lnpeer.Peer,
+	msg *lnwire.OpenChannel2) {
+
+	peerIDKey := newSerializedKey(peer.IdentityKey())
+	remoteAmt := msg.FundingAmount
+
+	if !peer.LocalFeatures().HasFeature(lnwire.DualFundOptional) {
+		f.failFundingFlow(
+			peer, msg.PendingChannelID, ErrDualFundNotSupported,
+		)
+		return
+	}
+
+	// Check that we're able to accept another inbound channel from this
+	// peer at all.
+	if err := f.checkInboundChannel(peer); err != nil {
+		f.failFundingFlow(peer, msg.PendingChannelID, err)
+		return
+	}
+
+	// The channel acceptor decides whether we accept the channel, and how
+	// much we contribute to it. Channel acceptors that aren't aware of
+	// dual funded channels can still inspect the fields both open
+	// messages have in common.
+	chanReq := &chanacceptor.ChannelAcceptRequest{
+		Node: peer.IdentityKey(),
+		OpenChanMsg: &lnwire.OpenChannel{
+			ChainHash:             msg.ChainHash,
+			PendingChannelID:      msg.PendingChannelID,
+			FundingAmount:         msg.FundingAmount,
+			DustLimit:             msg.DustLimit,
+			MaxValueInFlight:      msg.MaxValueInFlight,
+			HtlcMinimum:           msg.HtlcMinimum,
+			FeePerKiloWeight:      msg.CommitFeePerKiloWeight,
+			CsvDelay:              msg.CsvDelay,
+			MaxAcceptedHTLCs:      msg.MaxAcceptedHTLCs,
+			FundingKey:            msg.FundingKey,
+			RevocationPoint:       msg.RevocationPoint,
+			PaymentPoint:          msg.PaymentPoint,
+			DelayedPaymentPoint:   msg.DelayedPaymentPoint,
+			HtlcPoint:             msg.HtlcPoint,
+			FirstCommitmentPoint:  msg.FirstCommitmentPoint,
+			ChannelFlags:          msg.ChannelFlags,
+			UpfrontShutdownScript: msg.UpfrontShutdownScript,
+			ChannelType:           msg.ChannelType,
+		},
+		OpenChan2Msg: msg,
+	}
+	acceptorResp := f.cfg.OpenChannelPredicate.Accept(chanReq)
+	if acceptorResp.RejectChannel() {
+		f.failFundingFlow(
+			peer, msg.PendingChannelID,
+			acceptorResp.ChanAcceptError,
+		)
+		return
+	}
+
+	localAmt := acceptorResp.LocalFundingAmt
+	capacity := localAmt + remoteAmt
+
+	// Ensure that the channel, including our own contribution, respects
+	// our channel size limits.
+	if capacity > f.cfg.MaxChanSize {
+		f.failFundingFlow(
+			peer, msg.PendingChannelID,
+			lnwallet.ErrChanTooLarge(capacity, f.cfg.MaxChanSize),
+		)
+		return
+	}
+	if capacity < f.cfg.MinChanSize {
+		f.failFundingFlow(
+			peer, msg.PendingChannelID,
+			lnwallet.ErrChanTooSmall(capacity, f.cfg.MinChanSize),
+		)
+		return
+	}
+
+	log.Infof("Recv'd dual fundingRequest(remote_amt=%v, local_amt=%v, "+
+		"delay=%v, pendingId=%x) from peer(%x)", remoteAmt, localAmt,
+		msg.CsvDelay, msg.PendingChannelID,
+		peer.IdentityKey().SerializeCompressed())
+
+	wasExplicit, _, commitType, err := negotiateCommitmentType(
+		msg.ChannelType, peer.LocalFeatures(), peer.RemoteFeatures(),
+		false,
+	)
+	if err != nil {
+		log.Errorf("channel type negotiation failed: %v", err)
+		f.failFundingFlow(peer, msg.PendingChannelID, err)
+		return
+	}
+
+	// Only echo back a channel type if we actually used explicit
+	// negotiation above. Both parties have funds at stake in a dual
+	// funded channel, so neither of them can trust the other not to
+	// double spend the funding transaction. We therefore don't support
+	// zero-conf, and with it alias channels.
+	var chanTypeFeatureBits *lnwire.ChannelType
+	if wasExplicit {
+		chanTypeFeatureBits = msg.ChannelType
+
+		chanTypeVector := lnwire.RawFeatureVector(*msg.ChannelType)
+		if chanTypeVector.IsSet(lnwire.ZeroConfRequired) ||
+			chanTypeVector.IsSet(lnwire.ScidAliasRequired) {
+
+			err := errors.New("zero-conf and scid-alias channels " +
+				"can't be dual funded")
+			f.failFundingFlow(peer, msg.PendingChannelID, err)
+			return
+		}
+	}
+	if acceptorResp.ZeroConf {
+		err := errors.New("channel acceptor requires zero-conf, but " +
+			"it was not requested")
+		f.failFundingFlow(peer, msg.PendingChannelID, err)
+		return
+	}
+
+	req := &lnwallet.InitFundingReserveMsg{
+		ChainHash:        &msg.ChainHash,
+		PendingChanID:    msg.PendingChannelID,
+		NodeID:           peer.IdentityKey(),
+		NodeAddr:         peer.Address(),
+		LocalFundingAmt:  localAmt,
+		RemoteFundingAmt: remoteAmt,
+		CommitFeePerKw: chainfee.SatPerKWeight(
+			msg.CommitFeePerKiloWeight,
+		),
+		FundingFeePerKw: chainfee.SatPerKWeight(
+			msg.FundingFeePerKiloWeight,
+		),
+		Flags:      msg.ChannelFlags,
+		MinConfs:   1,
+		CommitType: commitType,
+		DualFund:   true,
+	}
+
+	reservation, err := f.cfg.Wallet.InitChannelReservation(req)
+	if err != nil {
+		log.Errorf("Unable to initialize reservation: %v", err)
+		f.failFundingFlow(peer, msg.PendingChannelID, err)
+		return
+	}
+
+	// As we're the responder, we get to specify the number of
+	// confirmations that we require before both of us consider the
+	// channel open, unless our channel acceptor set a value.
+	numConfsReq := f.cfg.NumRequiredConfs(capacity, 0)
+	if acceptorResp.MinAcceptDepth != 0 {
+		numConfsReq = acceptorResp.MinAcceptDepth
+	}
+	reservation.SetNumConfsRequired(numConfsReq)
+
+	// Dual funded channels don't negotiate a channel reserve. Instead,
+	// both parties are required to keep one percent of the capacity,
+	// but at least the larger of both dust limits.
+	maxDustLimit := reservation.OurContribution().DustLimit
+	if msg.DustLimit > maxDustLimit {
+		maxDustLimit = msg.DustLimit
+	}
+	chanReserve := f.cfg.RequiredRemoteChanReserve(capacity, maxDustLimit)
+
+	channelConstraints := &channeldb.ChannelConstraints{
+		DustLimit:        msg.DustLimit,
+		ChanReserve:      chanReserve,
+		MaxPendingAmount: msg.MaxValueInFlight,
+		MinHTLC:          msg.HtlcMinimum,
+		MaxAcceptedHtlcs: msg.MaxAcceptedHTLCs,
+		CsvDelay:         msg.CsvDelay,
+	}
+	err = reservation.CommitConstraints(
+		channelConstraints, f.cfg.MaxLocalCSVDelay, true,
+	)
+	if err != nil {
+		log.Errorf("Unacceptable channel constraints: %v", err)
+		f.failFundingFlow(peer, msg.PendingChannelID, err)
+		return
+	}
+
+	shutdown, err := getUpfrontShutdownScript(
+		f.cfg.EnableUpfrontShutdown, peer, acceptorResp.UpfrontShutdown,
+		func() (lnwire.DeliveryAddress, error) {
+			addr, err := f.cfg.Wallet.NewAddress(
+				lnwallet.WitnessPubKey, false,
+				lnwallet.DefaultAccountName,
+			)
+			if err != nil {
+				return nil, err
+			}
+			return txscript.PayToAddrScript(addr)
+		},
+	)
+	if err != nil {
+		f.failFundingFlow(
+			peer, msg.PendingChannelID,
+			fmt.Errorf("getUpfrontShutdownScript error: %v", err),
+		)
+		return
+	}
+	reservation.SetOurUpfrontShutdown(shutdown)
+
+	// Generate our required constraints for the remote party, using the
+	// values provided by the channel acceptor if they are non-zero.
+	remoteCsvDelay := f.cfg.RequiredRemoteDelay(capacity)
+	if acceptorResp.CSVDelay != 0 {
+		remoteCsvDelay = acceptorResp.CSVDelay
+	}
+
+	remoteMaxValue := f.cfg.RequiredRemoteMaxValue(capacity)
+	if acceptorResp.InFlightTotal != 0 {
+		remoteMaxValue = acceptorResp.InFlightTotal
+	}
+
+	maxHtlcs := f.cfg.RequiredRemoteMaxHTLCs(capacity)
+	if acceptorResp.HtlcLimit != 0 {
+		maxHtlcs = acceptorResp.HtlcLimit
+	}
+
+	minHtlc := f.cfg.DefaultMinHtlcIn
+	if acceptorResp.MinHtlcIn != 0 {
+		minHtlc = acceptorResp.MinHtlcIn
+	}
+
+	f.resMtx.Lock()
+	if _, ok := f.activeReservations[peerIDKey]; !ok {
+		f.activeReservations[peerIDKey] = make(pendingChannels)
+	}
+	resCtx := &reservationWithCtx{
+		reservation:    reservation,
+		chanAmt:        capacity,
+		remoteCsvDelay: remoteCsvDelay,
+		remoteMinHtlc:  minHtlc,
+		remoteMaxValue: remoteMaxValue,
+		remoteMaxHtlcs: maxHtlcs,
+		maxLocalCsv:    f.cfg.MaxLocalCSVDelay,
+		channelType:    msg.ChannelType,
+		interactive: newInteractiveFundingCtx(
+			false, req.FundingFeePerKw, msg.LockTime, remoteAmt,
+		),
+		err:  make(chan error, 1),
+		peer: peer,
+	}
+	f.activeReservations[peerIDKey][msg.PendingChannelID] = resCtx
+	f.resMtx.Unlock()
+
+	// Update the timestamp once the OpenChannel2 message has been
+	// handled.
+	defer resCtx.updateTimestamp()
+
+	remoteContribution := &lnwallet.ChannelContribution{
+		FundingAmount:         remoteAmt,
+		FirstCommitmentPoint:  msg.FirstCommitmentPoint,
+		SecondCommitmentPoint: msg.SecondCommitmentPoint,
+		ChannelConfig: &channeldb.ChannelConfig{
+			ChannelConstraints: channeldb.ChannelConstraints{
+				DustLimit:        msg.DustLimit,
+				MaxPendingAmount: remoteMaxValue,
+				ChanReserve:      chanReserve,
+				MinHTLC:          minHtlc,
+				MaxAcceptedHtlcs: maxHtlcs,
+				CsvDelay:         remoteCsvDelay,
+			},
+			MultiSigKey: keychain.KeyDescriptor{
+				PubKey: copyPubKey(msg.FundingKey),
+			},
+			RevocationBasePoint: keychain.KeyDescriptor{
+				PubKey: copyPubKey(msg.RevocationPoint),
+			},
+			PaymentBasePoint: keychain.KeyDescriptor{
+				PubKey: copyPubKey(msg.PaymentPoint),
+			},
+			DelayBasePoint: keychain.KeyDescriptor{
+				PubKey: copyPubKey(msg.DelayedPaymentPoint),
+			},
+			HtlcBasePoint: keychain.KeyDescriptor{
+				PubKey: copyPubKey(msg.HtlcPoint),
+			},
+		},
+		UpfrontShutdown: msg.UpfrontShutdownScript,
+	}
+	err = reservation.ProcessSingleContribution(remoteContribution)
+	if err != nil {
+		log.Errorf("unable to add contribution reservation: %v", err)
+		f.failFundingFlow(peer, msg.PendingChannelID, err)
+		return
+	}
+
+	// Our inputs and outputs are sent once the initiator has started the
+	// construction of the funding transaction, so we queue them now.
+	err = f.queueContribution(resCtx, msg.PendingChannelID)
+	if err != nil {
+		log.Errorf("unable to add contribution to funding tx: %v", err)
+		f.failFundingFlow(peer, msg.PendingChannelID, err)
+		return
+	}
+
+	log.Infof("Sending dual fundingResp for pending_id(%x)",
+		msg.PendingChannelID)
+	log.Debugf("Remote party accepted commitment constraints: %v",
+		spew.Sdump(remoteContribution.ChannelConfig.ChannelConstraints))
+
+	ourContribution := reservation.OurContribution()
+	fundingAccept := &lnwire.AcceptChannel2{
+		PendingChannelID:      msg.PendingChannelID,
+		FundingAmount:         localAmt,
+		DustLimit:             ourContribution.DustLimit,
+		MaxValueInFlight:      remoteMaxValue,
+		HtlcMinimum:           minHtlc,
+		MinAcceptDepth:        uint32(numConfsReq),
+		CsvDelay:              remoteCsvDelay,
+		MaxAcceptedHTLCs:      maxHtlcs,
+		FundingKey:            ourContribution.MultiSigKey.PubKey,
+		RevocationPoint:       ourContribution.RevocationBasePoint.PubKey,
+		PaymentPoint:          ourContribution.PaymentBasePoint.PubKey,
+		DelayedPaymentPoint:   ourContribution.DelayBasePoint.PubKey,
+		HtlcPoint:             ourContribution.HtlcBasePoint.PubKey,
+		FirstCommitmentPoint:  ourContribution.FirstCommitmentPoint,
+		SecondCommitmentPoint: ourContribution.SecondCommitmentPoint,
+		UpfrontShutdownScript: ourContribution.UpfrontShutdown,
+		ChannelType:           chanTypeFeatureBits,
+	}
+	if err := peer.SendMessage(true, fundingAccept); err != nil {
+		log.Errorf("unable to send funding response to peer: %v", err)
+		f.failFundingFlow(peer, msg.PendingChannelID, err)
+		return
+	}
+}
+
+// handleFundingAccept2 processes the response of the remote peer to our
+// proposal of a dual funded channel. Once the remote contribution is
+// processed, we start the construction of the funding transaction by sending
+// our first input or output.
+func (f *Manager) handleFundingAccept2(peer lnpeer.Peer,
+	msg *lnwire.AcceptChannel2) {
+
+	pendingChanID := msg.PendingChannelID
+	peerKey := peer.IdentityKey()
+
+	resCtx, err := f.getReservationCtx(peerKey, pendingChanID)
+	if err != nil || resCtx.interactive == nil {
+		log.Warnf("Can't find dual funded reservation (peerKey:%v, "+
+			"chan_id:%v)", peerKey, pendingChanID)
+		return
+	}
+
+	// Update the timestamp once the AcceptChannel2 message has been
+	// handled.
+	defer resCtx.updateTimestamp()
+
+	log.Infof("Recv'd dual fundingResponse for pending_id(%x), "+
+		"remote_amt=%v", pendingChanID[:], msg.FundingAmount)
+
+	// The channel type echoed by the responder must match what we
+	// proposed.
+	if resCtx.channelType != nil {
+		if msg.ChannelType == nil {
+			err := errors.New("explicit channel type not " +
+				"echoed back")
+			f.failFundingFlow(peer, pendingChanID, err)
+			return
+		}
+		proposedFeatures := lnwire.RawFeatureVector(*resCtx.channelType)
+		ackedFeatures := lnwire.RawFeatureVector(*msg.ChannelType)
+		if !proposedFeatures.Equals(&ackedFeatures) {
+			err := errors.New("channel type mismatch")
+			f.failFundingFlow(peer, pendingChanID, err)
+			return
+		}
+	}
+
+	if msg.MinAcceptDepth > chainntnfs.MaxNumConfs {
+		err := lnwallet.ErrNumConfsTooLarge(
+			msg.MinAcceptDepth, chainntnfs.MaxNumConfs,
+		)
+		log.Warnf("Unacceptable channel constraints: %v", err)
+		f.failFundingFlow(peer, pendingChanID, err)
+		return
+	}
+	resCtx.reservation.SetNumConfsRequired(uint16(msg.MinAcceptDepth))
+
+	// Now that we know the contribution of the responder, we can update
+	// the capacity of the channel and the initial balances.
+	err = resCtx.reservation.SetRemoteFundingAmt(msg.FundingAmount)
+	if err != nil {
+		log.Errorf("Unable to set remote funding amount: %v", err)
+		f.failFundingFlow(peer, pendingChanID, err)
+		return
+	}
+	capacity := resCtx.reservation.Capacity()
+	resCtx.chanAmt = capacity
+	resCtx.interactive.remoteAmt = msg.FundingAmount
+
+	if capacity > f.cfg.MaxChanSize {
+		err := lnwallet.ErrChanTooLarge(capacity, f.cfg.MaxChanSize)
+		f.failFundingFlow(peer, pendingChanID, err)
+		return
+	}
+
+	// Both parties are required to keep the same reserve, which is
+	// derived from the final capacity and the larger dust limit.
+	maxDustLimit := resCtx.reservation.OurContribution().DustLimit
+	if msg.DustLimit > maxDustLimit {
+		maxDustLimit = msg.DustLimit
+	}
+	chanReserve := f.cfg.RequiredRemoteChanReserve(capacity, maxDustLimit)
+
+	channelConstraints := &channeldb.ChannelConstraints{
+		DustLimit:        msg.DustLimit,
+		ChanReserve:      chanReserve,
+		MaxPendingAmount: msg.MaxValueInFlight,
+		MinHTLC:          msg.HtlcMinimum,
+		MaxAcceptedHtlcs: msg.MaxAcceptedHTLCs,
+		CsvDelay:         msg.CsvDelay,
+	}
+	err = resCtx.reservation.CommitConstraints(
+		channelConstraints, resCtx.maxLocalCsv, false,
+	)
+	if err != nil {
+		log.Warnf("Unacceptable channel constraints: %v", err)
+		f.failFundingFlow(peer, pendingChanID, err)
+		return
+	}
+
+	remoteContribution := &lnwallet.ChannelContribution{
+		FundingAmount:         msg.FundingAmount,
+		FirstCommitmentPoint:  msg.FirstCommitmentPoint,
+		SecondCommitmentPoint: msg.SecondCommitmentPoint,
+		ChannelConfig: &channeldb.ChannelConfig{
+			ChannelConstraints: channeldb.ChannelConstraints{
+				DustLimit:        msg.DustLimit,
+				MaxPendingAmount: resCtx.remoteMaxValue,
+				ChanReserve:      chanReserve,
+				MinHTLC:          resCtx.remoteMinHtlc,
+				MaxAcceptedHtlcs: resCtx.remoteMaxHtlcs,
+				CsvDelay:         resCtx.remoteCsvDelay,
+			},
+			MultiSigKey: keychain.KeyDescriptor{
+				PubKey: copyPubKey(msg.FundingKey),
+			},
+			RevocationBasePoint: keychain.KeyDescriptor{
+				PubKey: copyPubKey(msg.RevocationPoint),
+			},
+			PaymentBasePoint: keychain.KeyDescriptor{
+				PubKey: copyPubKey(msg.PaymentPoint),
+			},
+			DelayBasePoint: keychain.KeyDescriptor{
+				PubKey: copyPubKey(msg.DelayedPaymentPoint),
+			},
+			HtlcBasePoint: keychain.KeyDescriptor{
+				PubKey: copyPubKey(msg.HtlcPoint),
+			},
+		},
+		UpfrontShutdown: msg.UpfrontShutdownScript,
+	}
+	err = resCtx.reservation.ProcessSingleContribution(remoteContribution)
+	if err != nil {
+		log.Errorf("Unable to process contribution from %v: %v",
+			peerKey, err)
+		f.failFundingFlow(peer, pendingChanID, err)
+		return
+	}
+
+	log.Infof("pendingChan(%x): remote party proposes num_confs=%v, "+
+		"csv_delay=%v", pendingChanID[:], msg.MinAcceptDepth,
+		msg.CsvDelay)
+	log.Debugf("Remote party accepted commitment constraints: %v",
+		spew.Sdump(remoteContribution.ChannelConfig.ChannelConstraints))
+
+	// With both multi-sig keys known, we can add our inputs, our change
+	// and the funding output to the transaction and start its
+	// construction.
+	if err := f.queueContribution(resCtx, pendingChanID); err != nil {
+		log.Errorf("Unable to add contribution to funding tx: %v", err)
+		f.failFundingFlow(peer, pendingChanID, err)
+		return
+	}
+
+	if _, err := f.sendNextTxMsg(resCtx, pendingChanID); err != nil {
+		log.Errorf("Unable to send interactive tx message: %v", err)
+		f.failFundingFlow(peer, pendingChanID, err)
+		return
+	}
+}
+
+// queueContribution adds our inputs and outputs to the funding transaction
+// under construction, and queues the messages that announce them to the
+// remote party. The funding output itself is always added by the initiator.
+func (f *Manager) queueContribution(resCtx *reservationWithCtx,
+	chanID lnwire.ChannelID) error {
+
+	ctx := resCtx.interactive
+
+	outPoints, changeOutputs, err :=
+		resCtx.reservation.InteractiveContribution()
+	if err != nil {
+		return err
+	}
+
+	for _, outPoint := range outPoints {
+		outPoint := outPoint
+
+		// The remote party must be able to verify the value and type
+		// of our inputs, so we send the full previous transaction.
+		utxo, err := f.cfg.Wallet.FetchInputInfo(&outPoint)
+		if err != nil {
+			return err
+		}
+		if utxo.PrevTx == nil {
+			return fmt.Errorf("previous transaction of input %v "+
+				"unknown", outPoint)
+		}
+
+		in, err := ctx.tx.AddLocalInput(utxo.PrevTx, outPoint.Index)
+		if err != nil {
+			return err
+		}
+
+		var prevTx bytes.Buffer
+		if err := utxo.PrevTx.Serialize(&prevTx); err != nil {
+			return err
+		}
+
+		ctx.queue = append(ctx.queue, &lnwire.TxAddInput{
+			ChannelID:      chanID,
+			SerialID:       in.SerialID,
+			PrevTx:         prevTx.Bytes(),
+			PrevTxOutIndex: in.PrevOutIndex,
+			Sequence:       in.Sequence,
+		})
+	}
+
+	outputs := changeOutputs
+	if ctx.initiator {
+		fundingOutput, err := resCtx.reservation.FundingOutput()
+		if err != nil {
+			return err
+		}
+		outputs = append(outputs, fundingOutput)
+	}
+
+	for _, txOut := range outputs {
+		out, err := ctx.tx.AddLocalOutput(txOut)
+		if err != nil {
+			return err
+		}
+
+		ctx.queue = append(ctx.queue, &lnwire.TxAddOutput{
+			ChannelID: chanID,
+			SerialID:  out.SerialID,
+			Amount:    bronutil.Amount(txOut.Value),
+			PkScript:  txOut.PkScript,
+		})
+	}
+
+	return nil
+}
+
+// sendNextTxMsg sends the next queued message of the interactive transaction
+// construction to the remote party, or a TxComplete message if we don't have
+// anything left to add. It returns true if a TxComplete message was sent.
+func (f *Manager) sendNextTxMsg(resCtx *reservationWithCtx,
+	chanID lnwire.ChannelID) (bool, error) {
+
+	ctx := resCtx.interactive
+
+	var msg lnwire.Message
+	if len(ctx.queue) > 0 {
+		msg, ctx.queue = ctx.queue[0], ctx.queue[1:]
+		ctx.sentComplete = false
+	} else {
+		msg = &lnwire.TxComplete{ChannelID: chanID}
+		ctx.sentComplete = true
+	}
+
+	if err := resCtx.peer.SendMessage(true, msg); err != nil {
+		return false, err
+	}
+
+	return ctx.sentComplete, nil
+}
+
+// handleInteractiveTxMsg processes a message of the remote party that is part
+// of the interactive construction of a funding transaction. The parties take
+// turns, each message is answered with the next one of our own. The
+// construction is finished once both parties have sent consecutive
+// TxComplete messages.
+func (f *Manager) handleInteractiveTxMsg(peer lnpeer.Peer,
+	chanID lnwire.ChannelID, msg lnwire.Message) {
+
+	peerKey := peer.IdentityKey()
+
+	resCtx, err := f.getReservationCtx(peerKey, chanID)
+	if err != nil || resCtx.interactive == nil {
+		log.Warnf("Can't find dual funded reservation (peerKey:%v, "+
+			"chan_id:%v)", peerKey, chanID)
+		return
+	}
+
+	// Update the timestamp once the message has been handled.
+	defer resCtx.updateTimestamp()
+
+	ctx := resCtx.interactive
+	if ctx.fundingTx != nil {
+		err := errors.New("funding transaction already constructed")
+		f.failFundingFlow(peer, chanID, err)
+		return
+	}
+
+	switch msg := msg.(type) {
+	case *lnwire.TxAddInput:
+		prevTx := &wire.MsgTx{}
+		err = prevTx.Deserialize(bytes.NewReader(msg.PrevTx))
+		if err != nil {
+			break
+		}
+
+		err = ctx.tx.AddRemoteInput(&chanfunding.InteractiveTxInput{
+			SerialID:     msg.SerialID,
+			PrevTx:       prevTx,
+			PrevOutIndex: msg.PrevTxOutIndex,
+			Sequence:     msg.Sequence,
+		})
+
+	case *lnwire.TxAddOutput:
+		err = ctx.tx.AddRemoteOutput(&chanfunding.InteractiveTxOutput{
+			SerialID: msg.SerialID,
+			TxOut: &wire.TxOut{
+				Value:    int64(msg.Amount),
+				PkScript: msg.PkScript,
+			},
+		})
+
+	case *lnwire.TxRemoveInput:
+		err = ctx.tx.RemoveRemoteInput(msg.SerialID)
+
+	case *lnwire.TxRemoveOutput:
+		err = ctx.tx.RemoveRemoteOutput(msg.SerialID)
+
+	case *lnwire.TxComplete:
+		// If we already sent a TxComplete ourselves, both parties
+		// agree on the transaction.
+		if ctx.sentComplete {
+			f.finishInteractiveTx(resCtx, chanID)
+			return
+		}
+	}
+	if err != nil {
+		log.Errorf("Invalid interactive tx message from %x: %v",
+			peerKey.SerializeCompressed(), err)
+		f.failFundingFlow(peer, chanID, err)
+		return
+	}
+
+	// It's our turn now. If we reply to a TxComplete with a TxComplete of
+	// our own, the construction is finished as well.
+	_, remoteComplete := msg.(*lnwire.TxComplete)
+	sentComplete, err := f.sendNextTxMsg(resCtx, chanID)
+	if err != nil {
+		log.Errorf("Unable to send interactive tx message: %v", err)
+		f.failFundingFlow(peer, chanID, err)
+		return
+	}
+
+	if remoteComplete && sentComplete {
+		f.finishInteractiveTx(resCtx, chanID)
+	}
+}
+
+// finishInteractiveTx verifies the constructed funding transaction and passes
+// it to the wallet, which signs our inputs and the remote commitment
+// transaction. The initiator then continues the funding flow by sending the
+// FundingCreated message.
+func (f *Manager) finishInteractiveTx(resCtx *reservationWithCtx,
+	chanID lnwire.ChannelID) {
+
+	ctx := resCtx.interactive
+	peer := resCtx.peer
+
+	fundingOutput, err := resCtx.reservation.FundingOutput()
+	if err != nil {
+		f.failFundingFlow(peer, chanID, err)
+		return
+	}
+
+	err = ctx.tx.Verify(fundingOutput, ctx.remoteAmt, ctx.feeRate)
+	if err != nil {
+		log.Errorf("Invalid funding tx for pending_id(%x): %v",
+			chanID[:], err)
+		f.failFundingFlow(peer, chanID, err)
+		return
+	}
+
+	fundingTx := ctx.tx.Tx(ctx.lockTime)
+	err = resCtx.reservation.ProcessInteractiveTx(
+		fundingTx, ctx.tx.PrevOutFetcher(),
+	)
+	if err != nil {
+		log.Errorf("Unable to process funding tx for "+
+			"pending_id(%x): %v", chanID[:], err)
+		f.failFundingFlow(peer, chanID, err)
+		return
+	}
+	ctx.fundingTx = fundingTx
+
+	log.Infof("Constructed funding tx %v for pending_id(%x)",
+		fundingTx.TxHash(), chanID[:])
+
+	if ctx.initiator {
+		f.continueFundingAccept(resCtx, chanID)
+	}
+}
+
+// startTxSigsExchange starts the exchange of the witnesses of the funding
+// transaction once both commitment transactions are signed. If we're the
+// party that has to sign first, our witnesses are sent right away, otherwise
+// they're sent in response to the ones of the remote party.
+func (f *Manager) startTxSigsExchange(peer lnpeer.Peer,
+	chanID lnwire.ChannelID, ctx *interactiveFundingCtx) error {
+
+	f.resMtx.Lock()
+	f.pendingTxSigs[chanID] = ctx
+	f.resMtx.Unlock()
+
+	if !ctx.tx.LocalSignsFirst(f.cfg.IDKey, peer.IdentityKey()) {
+		return nil
+	}
+
+	return f.sendTxSignatures(peer, chanID, ctx)
+}
+
+// sendTxSignatures sends the witnesses of our inputs to the funding
+// transaction to the remote party.
+func (f *Manager) sendTxSignatures(peer lnpeer.Peer, chanID lnwire.ChannelID,
+	ctx *interactiveFundingCtx) error {
+
+	ctx.sentSigs = true
+
+	return peer.SendMessage(true, &lnwire.TxSignatures{
+		ChannelID: chanID,
+		TxID:      ctx.fundingTx.TxHash(),
+		Witnesses: ctx.tx.LocalWitnesses(ctx.fundingTx),
+	})
+}
+
+// handleTxSignatures processes the witnesses of the remote party's inputs to
+// the funding transaction of a dual funded channel. The party that
+// contributed the lower input amount sends its witnesses first, right after
+// the commitment signatures were exchanged, and the other party replies with
+// its own. With all inputs signed, both parties broadcast the funding
+// transaction.
+func (f *Manager) handleTxSignatures(peer lnpeer.Peer,
+	msg *lnwire.TxSignatures) {
+
+	f.resMtx.RLock()
+	ctx, ok := f.pendingTxSigs[msg.ChannelID]
+	f.resMtx.RUnlock()
+	if !ok {
+		err := fmt.Errorf("unable to find funding tx for chan_id=%v",
+			msg.ChannelID)
+		log.Warnf(err.Error())
+		f.failFundingFlow(peer, msg.ChannelID, err)
+		return
+	}
+
+	fundingTx := ctx.fundingTx
+	if msg.TxID != fundingTx.TxHash() {
+		err := fmt.Errorf("signatures for unknown funding tx %v",
+			msg.TxID)
+		f.failFundingFlow(peer, msg.ChannelID, err)
+		return
+	}
+
+	// If we're supposed to sign first, the remote party must wait for
+	// our witnesses before sending its own.
+	if !ctx.sentSigs &&
+		ctx.tx.LocalSignsFirst(f.cfg.IDKey, peer.IdentityKey()) {
+
+		err := errors.New("received TxSignatures before sending ours")
+		f.failFundingFlow(peer, msg.ChannelID, err)
+		return
+	}
+
+	err := ctx.tx.AddRemoteWitnesses(fundingTx, msg.Witnesses)
+	if err != nil {
+		log.Errorf("Invalid witnesses for funding tx %v: %v",
+			msg.TxID, err)
+		f.failFundingFlow(peer, msg.ChannelID, err)
+		return
+	}
+
+	if !ctx.sentSigs {
+		err = f.sendTxSignatures(peer, msg.ChannelID, ctx)
+		if err != nil {
+			log.Errorf("Unable to send TxSignatures: %v", err)
+			f.failFundingFlow(peer, msg.ChannelID, err)
+			return
+		}
+	}
+
+	f.resMtx.Lock()
+	delete(f.pendingTxSigs, msg.ChannelID)
+	f.resMtx.Unlock()
+
+	// Both parties broadcast the fully signed funding transaction, as
+	// both have funds at stake.
+	log.Infof("Broadcasting dual funded funding tx %v for chan_id=%v",
+		msg.TxID, msg.ChannelID)
+
+	label := labels.MakeLabel(labels.LabelTypeChannelOpen, nil)
+	if err := f.cfg.PublishTransaction(fundingTx, label); err != nil {
+		log.Errorf("Unable to broadcast funding tx %v for "+
+			"chan_id=%v: %v", msg.TxID, msg.ChannelID, err)
+	}
+}
@@ -1,6 +1,7 @@
 package funding
 
 import (
+	"github.com/brronsuite/broln/channeldb"
 	"github.com/brronsuite/broln/lnpeer"
 	"github.com/brronsuite/broln/lnwire"
 )
@@ -18,4 +19,23 @@ type Controller interface {
 	// IsPendingChannel returns whether a particular 32-byte identifier
 	// represents a pending channel in the Controller implementation.
 	IsPendingChannel([32]byte, lnpeer.Peer) bool
+
+	// AnnounceSplicedChannel adds a channel to the graph again once its
+	// splice has confirmed, and announces it to the network if it's
+	// public.
+	AnnounceSplicedChannel(*channeldb.OpenChannel) error
+}
+
+// aliasHandler is an interface that abstracts the managing of aliases.
+type aliasHandler interface {
+	// RequestAlias lets the funding manager request a unique SCID alias to
+	// use in the funding_locked message.
+	RequestAlias() (lnwire.ShortChannelID, error)
+
+	// AddLocalAlias persists an alias to an underlying alias store.
+	AddLocalAlias(alias, base lnwire.ShortChannelID) error
+
+	// PutPeerAlias persists an alias that the peer sent us in their
+	// funding_locked message.
+	PutPeerAlias(chanID lnwire.ChannelID, alias lnwire.ShortChannelID) error
 }
@@ -150,6 +150,10 @@ type reservationWithCtx struct {
 	// the channel.
 	channelType *lnwire.ChannelType
 
+	// interactive tracks the construction of the funding transaction of a
+	// dual funded channel. It's nil for single funded channels.
+	interactive *interactiveFundingCtx
+
 	updateMtx   sync.RWMutex
 	lastUpdated time.Time
 
@@ -248,6 +252,20 @@ type InitFundingMsg struct {
 	// support explicit channel type negotiation.
 	ChannelType *lnwire.ChannelType
 
+	// ZeroConf denotes whether the channel should be opened as a zero-conf
+	// channel. Zero-conf channels always use the scid-alias channel type
+	// and must be private.
+	ZeroConf bool
+
+	// ScidAlias denotes whether the channel should use the scid-alias
+	// channel type. Such channels must be private.
+	ScidAlias bool
+
+	// DualFund denotes whether the channel should be opened as a dual
+	// funded channel, allowing the remote peer to contribute funds as
+	// well. The funding transaction is then constructed interactively.
+	DualFund bool
+
 	// Updates is a channel which updates to the opening status of the channel
 	// are sent on.
 	Updates chan *lnrpc.OpenStatusUpdate
@@ -462,6 +480,15 @@ type Config struct {
 	// MaxAnchorsCommitFeeRate is the max commitment fee rate we'll use as
 	// the initiator for channels of the anchor type.
 	MaxAnchorsCommitFeeRate chainfee.SatPerKWeight
+
+	// AliasManager is an implementation of the aliasHandler interface that
+	// abstracts away the handling of many alias functions.
+	AliasManager aliasHandler
+
+	// DeleteAliasEdge removes the edge of an alias channel from the graph.
+	// It's used once a zero-conf channel is closed because its funding
+	// transaction won't confirm.
+	DeleteAliasEdge func(alias lnwire.ShortChannelID) error
 }
 
 // Manager acts as an orchestrator/bridge between the wallet's
@@ -501,8 +528,13 @@ type Manager struct {
 	// signed by both parties.
 	signedReservations map[lnwire.ChannelID][32]byte
 
-	// resMtx guards both of the maps above to ensure that all access is
-	// goroutine safe.
+	// pendingTxSigs maps the permanent channel ID of a dual funded channel
+	// to the context of its funding transaction, while we're waiting for
+	// the remote party's signatures for its inputs.
+	pendingTxSigs map[lnwire.ChannelID]*interactiveFundingCtx
+
+	// resMtx guards the maps above to ensure that all access is goroutine
+	// safe.
 	resMtx sync.RWMutex
 
 	// fundingMsgs is a channel that relays fundingMsg structs from
@@ -574,6 +606,7 @@ func NewFundingManager(cfg Config) (*Manager, error) {
 		chanIDKey:                   cfg.TempChanIDSeed,
 		activeReservations:          make(map[serializedPubKey]pendingChannels),
 		signedReservations:          make(map[lnwire.ChannelID][32]byte),
+		pendingTxSigs:               make(map[lnwire.ChannelID]*interactiveFundingCtx),
 		newChanBarriers:             make(map[lnwire.ChannelID]chan struct{}),
 		fundingMsgs:                 make(chan *fundingMsg, msgBufferSize),
 		fundingRequests:             make(chan *InitFundingMsg, msgBufferSize),
@@ -624,10 +657,19 @@ func (f *Manager) start() error {
 			f.barrierMtx.Unlock()
 
 			f.localDiscoverySignals[chanID] = make(chan struct{})
+		}
 
+		// Zero-conf channels are marked open right away, so their
+		// funding transaction may not be confirmed either.
+		unconfirmed := channel.IsPending ||
+			channel.IsZeroConf() && !channel.ZeroConfConfirmed()
+		if unconfirmed {
 			// Rebroadcast the funding transaction for any pending
 			// channel that we initiated. No error will be returned
-			// if the transaction already has been broadcast.
+			// if the transaction already has been broadcast. The
+			// funding transaction of a dual funded channel isn't
+			// stored with the witnesses of the remote inputs, so
+			// it can't be rebroadcast.
 			chanType := channel.ChanType
 			if chanType.IsSingleFunder() && chanType.HasFundingTx() &&
 				channel.IsInitiator {
@@ -829,6 +871,37 @@ func (f *Manager) reservationCoordinator() {
 				f.handleFundingOpen(fmsg.peer, msg)
 			case *lnwire.AcceptChannel:
 				f.handleFundingAccept(fmsg.peer, msg)
+			case *lnwire.OpenChannel2:
+				f.handleFundingOpen2(fmsg.peer, msg)
+			case *lnwire.AcceptChannel2:
+				f.handleFundingAccept2(fmsg.peer, msg)
+			case *lnwire.TxAddInput:
+				f.handleInteractiveTxMsg(
+					fmsg.peer, msg.ChannelID, msg,
+				)
+			case *lnwire.TxAddOutput:
+				f.handleInteractiveTxMsg(
+					fmsg.peer, msg.ChannelID, msg,
+				)
+			case *lnwire.TxRemoveInput:
+				f.handleInteractiveTxMsg(
+					fmsg.peer, msg.ChannelID, msg,
+				)
+			case *lnwire.TxRemoveOutput:
+				f.handleInteractiveTxMsg(
+					fmsg.peer, msg.ChannelID, msg,
+				)
+			case *lnwire.TxComplete:
+				f.handleInteractiveTxMsg(
+					fmsg.peer, msg.ChannelID, msg,
+				)
+			case *lnwire.TxSignatures:
+				f.handleTxSignatures(fmsg.peer, msg)
+			case *lnwire.TxAbort:
+				f.handleErrorMsg(fmsg.peer, &lnwire.Error{
+					ChanID: msg.ChannelID,
+					Data:   msg.Data,
+				})
 			case *lnwire.FundingCreated:
 				f.handleFundingCreated(fmsg.peer, msg)
 			case *lnwire.FundingSigned:
@@ -865,9 +938,17 @@ func (f *Manager) advanceFundingState(channel *channeldb.OpenChannel,
 	defer f.wg.Done()
 
 	// If the channel is still pending we must wait for the funding
-	// transaction to confirm.
+	// transaction to confirm. Zero-conf channels are the exception, as
+	// they can be used right away.
 	if channel.IsPending {
-		err := f.advancePendingChannelState(channel, pendingChanID)
+		var err error
+		if channel.IsZeroConf() {
+			err = f.handleZeroConfOpen(channel)
+		} else {
+			err = f.advancePendingChannelState(
+				channel, pendingChanID,
+			)
+		}
 		if err != nil {
 			log.Errorf("Unable to advance pending state of "+
 				"ChannelPoint(%v): %v",
@@ -876,6 +957,16 @@ func (f *Manager) advanceFundingState(channel *channeldb.OpenChannel,
 		}
 	}
 
+	// A zero-conf channel is used before its funding transaction
+	// confirms, so we'll wait for the confirmation in the background to
+	// learn the channel's real SCID. As the funding state machine is
+	// advanced for all channels on startup, the wait is resumed after a
+	// restart as well.
+	if channel.IsZeroConf() && !channel.ZeroConfConfirmed() {
+		f.wg.Add(1)
+		go f.waitForZeroConfChannel(channel)
+	}
+
 	// We create the state-machine object which wraps the database state.
 	lnChannel, err := lnwallet.NewLightningChannel(
 		nil, channel, nil,
@@ -1062,54 +1153,13 @@ func (f *Manager) advancePendingChannelState(
 		// since the channel was initiated reaches
 		// maxWaitNumBlocksFundingConf and we are not the
 		// channel initiator.
-		ch := channel
-		localBalance := ch.LocalCommitment.LocalBalance.ToBroneess()
-		closeInfo := &channeldb.ChannelCloseSummary{
-			ChainHash:               ch.ChainHash,
-			ChanPoint:               ch.FundingOutpoint,
-			RemotePub:               ch.IdentityPub,
-			Capacity:                ch.Capacity,
-			SettledBalance:          localBalance,
-			CloseType:               channeldb.FundingCanceled,
-			RemoteCurrentRevocation: ch.RemoteCurrentRevocation,
-			RemoteNextRevocation:    ch.RemoteNextRevocation,
-			LocalChanConfig:         ch.LocalChanCfg,
-		}
-
-		// Close the channel with us as the initiator because we are
-		// timing the channel out.
-		if err := ch.CloseChannel(
-			closeInfo, channeldb.ChanStatusLocalCloseInitiator,
-		); err != nil {
-			return fmt.Errorf("failed closing channel "+
-				"%v: %v", ch.FundingOutpoint, err)
-		}
-
 		timeoutErr := fmt.Errorf("timeout waiting for funding tx "+
 			"(%v) to confirm", channel.FundingOutpoint)
 
-		// When the peer comes online, we'll notify it that we
-		// are now considering the channel flow canceled.
-		f.wg.Add(1)
-		go func() {
-			defer f.wg.Done()
-
-			peerChan := make(chan lnpeer.Peer, 1)
-			var peerKey [33]byte
-			copy(peerKey[:], ch.IdentityPub.SerializeCompressed())
-
-			f.cfg.NotifyWhenOnline(peerKey, peerChan)
-
-			var peer lnpeer.Peer
-			select {
-			case peer = <-peerChan:
-			case <-f.quit:
-				return
-			}
-			// TODO(halseth): should this send be made
-			// reliable?
-			f.failFundingFlow(peer, pendingChanID, timeoutErr)
-		}()
+		err := f.cancelChannelFunding(channel, pendingChanID, timeoutErr)
+		if err != nil {
+			return err
+		}
 
 		return timeoutErr
 
@@ -1144,23 +1194,15 @@ func (f *Manager) ProcessFundingMsg(msg lnwire.Message, peer lnpeer.Peer) {
 	}
 }
 
-// handleFundingOpen creates an initial 'ChannelReservation' within the wallet,
-// then responds to the source peer with an accept channel message progressing
-// the funding workflow.
-//
-// TODO(roasbeef): add error chan to all, let channelManager handle
-// error+propagate
-func (f *Manager) handleFundingOpen(peer lnpeer.Peer,
-	msg *lnwire.OpenChannel) {
-
-	// Check number of pending channels to be smaller than maximum allowed
-	// number and send ErrorGeneric to remote peer if condition is
-	// violated.
+// checkInboundChannel checks whether we can accept a new inbound channel from
+// the peer. The number of pending channels with the peer must be below the
+// maximum allowed, and we must be fully synced to the network, as we wouldn't
+// be able to properly validate the confirmation of the funding transaction
+// otherwise.
+func (f *Manager) checkInboundChannel(peer lnpeer.Peer) error {
 	peerPubKey := peer.IdentityKey()
 	peerIDKey := newSerializedKey(peerPubKey)
 
-	amt := msg.FundingAmount
-
 	// We get all pending channels for this peer. This is the list of the
 	// active reservations and the channels pending open in the database.
 	f.resMtx.RLock()
@@ -1181,10 +1223,7 @@ func (f *Manager) handleFundingOpen(peer lnpeer.Peer,
 	// the underlying intent anymore, unfortunately.
 	channels, err := f.cfg.Wallet.Cfg.Database.FetchOpenChannels(peerPubKey)
 	if err != nil {
-		f.failFundingFlow(
-			peer, msg.PendingChannelID, err,
-		)
-		return
+		return err
 	}
 
 	for _, c := range channels {
@@ -1202,25 +1241,37 @@ func (f *Manager) handleFundingOpen(peer lnpeer.Peer,
 	// TODO(roasbeef): modify to only accept a _single_ pending channel per
 	// block unless white listed
 	if numPending >= f.cfg.MaxPendingChannels {
-		f.failFundingFlow(
-			peer, msg.PendingChannelID,
-			lnwire.ErrMaxPendingChannels,
-		)
-		return
+		return lnwire.ErrMaxPendingChannels
 	}
 
-	// We'll also reject any requests to create channels until we're fully
-	// synced to the network as we won't be able to properly validate the
-	// confirmation of the funding transaction.
 	isSynced, _, err := f.cfg.Wallet.IsSynced()
 	if err != nil || !isSynced {
 		if err != nil {
 			log.Errorf("unable to query wallet: %v", err)
 		}
-		f.failFundingFlow(
-			peer, msg.PendingChannelID,
-			lnwire.ErrSynchronizingChain,
-		)
+		return lnwire.ErrSynchronizingChain
+	}
+
+	return nil
+}
+
+// handleFundingOpen creates an initial 'ChannelReservation' within the wallet,
+// then responds to the source peer with an accept channel message progressing
+// the funding workflow.
+//
+// TODO(roasbeef): add error chan to all, let channelManager handle
+// error+propagate
+func (f *Manager) handleFundingOpen(peer lnpeer.Peer,
+	msg *lnwire.OpenChannel) {
+
+	peerIDKey := newSerializedKey(peer.IdentityKey())
+
+	amt := msg.FundingAmount
+
+	// Check that we're able to accept another inbound channel from this
+	// peer at all.
+	if err := f.checkInboundChannel(peer); err != nil {
+		f.failFundingFlow(peer, msg.PendingChannelID, err)
 		return
 	}
 
@@ -1300,9 +1351,60 @@ func (f *Manager) handleFundingOpen(peer lnpeer.Peer,
 
 	// Only echo back a channel type in AcceptChannel if we actually used
 	// explicit negotiation above.
-	var chanTypeFeatureBits *lnwire.ChannelType
+	var (
+		chanTypeFeatureBits *lnwire.ChannelType
+		zeroConf            bool
+		scidAlias           bool
+	)
 	if wasExplicit {
 		chanTypeFeatureBits = msg.ChannelType
+
+		// The zero-conf and scid-alias bits are only valid as part of
+		// an explicitly negotiated channel type.
+		chanTypeVector := lnwire.RawFeatureVector(*msg.ChannelType)
+		zeroConf = chanTypeVector.IsSet(lnwire.ZeroConfRequired)
+		scidAlias = chanTypeVector.IsSet(lnwire.ScidAliasRequired)
+	}
+
+	// Alias channels can't be announced, as the alias doesn't point to a
+	// funding output that other nodes could validate.
+	public := msg.ChannelFlags&lnwire.FFAnnounceChannel != 0
+	if (zeroConf || scidAlias) && public {
+		err = fmt.Errorf("cannot open public zero-conf or " +
+			"scid-alias channel")
+		log.Error(err)
+		f.failFundingFlow(peer, msg.PendingChannelID, err)
+		return
+	}
+
+	// A zero-conf channel has no confirmed SCID to use until the funding
+	// transaction confirms, so it must always use an alias.
+	if zeroConf && !scidAlias {
+		err = fmt.Errorf("zero-conf channel must use scid-alias " +
+			"channel type")
+		log.Error(err)
+		f.failFundingFlow(peer, msg.PendingChannelID, err)
+		return
+	}
+
+	// Zero-conf channels trust the initiator not to double spend the
+	// funding transaction, so they are only accepted if our channel
+	// acceptor explicitly agrees. Likewise, if the acceptor wants a
+	// zero-conf channel, the initiator must have asked for one.
+	switch {
+	case zeroConf && !acceptorResp.ZeroConf:
+		err = fmt.Errorf("zero-conf channel not accepted by " +
+			"channel acceptor")
+		log.Error(err)
+		f.failFundingFlow(peer, msg.PendingChannelID, err)
+		return
+
+	case !zeroConf && acceptorResp.ZeroConf:
+		err = fmt.Errorf("channel acceptor requires zero-conf, " +
+			"but it was not requested")
+		log.Error(err)
+		f.failFundingFlow(peer, msg.PendingChannelID, err)
+		return
 	}
 
 	chainHash := chainhash.Hash(msg.ChainHash)
@@ -1319,6 +1421,8 @@ func (f *Manager) handleFundingOpen(peer lnpeer.Peer,
 		Flags:            msg.ChannelFlags,
 		MinConfs:         1,
 		CommitType:       commitType,
+		ZeroConf:         zeroConf,
+		OptionScidAlias:  scidAlias,
 	}
 
 	reservation, err := f.cfg.Wallet.InitChannelReservation(req)
@@ -1328,6 +1432,29 @@ func (f *Manager) handleFundingOpen(peer lnpeer.Peer,
 		return
 	}
 
+	// If this is an alias channel, we'll request an alias now. It is used
+	// as the channel's SCID until the funding transaction confirms, and
+	// is sent to the peer in our funding_locked message.
+	if scidAlias {
+		alias, err := f.cfg.AliasManager.RequestAlias()
+		if err != nil {
+			log.Errorf("Unable to request alias: %v", err)
+			f.failFundingFlow(peer, msg.PendingChannelID, err)
+			return
+		}
+
+		// The alias also serves as the channel's base SCID, so it
+		// maps to itself.
+		err = f.cfg.AliasManager.AddLocalAlias(alias, alias)
+		if err != nil {
+			log.Errorf("Unable to add local alias: %v", err)
+			f.failFundingFlow(peer, msg.PendingChannelID, err)
+			return
+		}
+
+		reservation.AddAlias(alias)
+	}
+
 	// As we're the responder, we get to specify the number of confirmations
 	// that we require before both of us consider the channel open. We'll
 	// use our mapping to derive the proper number of confirmations based on
@@ -1338,6 +1465,12 @@ func (f *Manager) handleFundingOpen(peer lnpeer.Peer,
 	if acceptorResp.MinAcceptDepth != 0 {
 		numConfsReq = acceptorResp.MinAcceptDepth
 	}
+
+	// A zero-conf channel is usable right away, so we don't require any
+	// confirmations.
+	if zeroConf {
+		numConfsReq = 0
+	}
 	reservation.SetNumConfsRequired(numConfsReq)
 
 	// We'll also validate and apply all the constraints the initiating
@@ -1652,6 +1785,16 @@ func (f *Manager) handleFundingAccept(peer lnpeer.Peer,
 		return
 	}
 
+	// A zero-conf channel can only be used right away if the responder
+	// doesn't require any confirmations.
+	if resCtx.reservation.IsZeroConf() && msg.MinAcceptDepth != 0 {
+		err := fmt.Errorf("non-zero min_accept_depth %v for zero-conf "+
+			"channel", msg.MinAcceptDepth)
+		log.Warnf("Unacceptable channel constraints: %v", err)
+		f.failFundingFlow(peer, msg.PendingChannelID, err)
+		return
+	}
+
 	// We'll also specify the responder's preference for the number of
 	// required confirmations, and also the set of channel constraints
 	// they've specified for commitment states we can create.
@@ -1944,9 +2087,30 @@ func (f *Manager) handleFundingCreated(peer lnpeer.Peer,
 	// funding transaction will broadcast after our next message.
 	// CompleteReservationSingle will also mark the channel as 'IsPending'
 	// in the database.
-	completeChan, err := resCtx.reservation.CompleteReservationSingle(
-		&fundingOut, commitSig,
+	//
+	// If the funding transaction was constructed interactively, we
+	// already know it, so we only need to make sure the initiator
+	// references the same outpoint.
+	var (
+		reservation  = resCtx.reservation
+		completeChan *channeldb.OpenChannel
 	)
+	if resCtx.interactive != nil {
+		if fundingOut != *reservation.FundingOutpoint() {
+			err := fmt.Errorf("funding outpoint %v doesn't match "+
+				"constructed funding tx", fundingOut)
+			f.failFundingFlow(peer, pendingChanID, err)
+			return
+		}
+
+		completeChan, err = reservation.CompleteReservation(
+			nil, commitSig,
+		)
+	} else {
+		completeChan, err = reservation.CompleteReservationSingle(
+			&fundingOut, commitSig,
+		)
+	}
 	if err != nil {
 		// TODO(roasbeef): better error logging: peerID, channelID, etc.
 		log.Errorf("unable to complete single reservation: %v", err)
@@ -2020,6 +2184,16 @@ func (f *Manager) handleFundingCreated(peer lnpeer.Peer,
 		return
 	}
 
+	// Now that both commitment transactions of a dual funded channel are
+	// signed, the witnesses of the funding transaction can be exchanged.
+	if resCtx.interactive != nil {
+		err := f.startTxSigsExchange(peer, channelID, resCtx.interactive)
+		if err != nil {
+			log.Errorf("unable to send TxSignatures message: %v",
+				err)
+		}
+	}
+
 	// Now that we've sent over our final signature for this channel, we'll
 	// send it to the ChainArbitrator so it can watch for any on-chain
 	// actions during this final confirmation stage.
@@ -2126,8 +2300,20 @@ func (f *Manager) handleFundingSigned(peer lnpeer.Peer,
 	f.deleteReservationCtx(peerKey, pendingChanID)
 
 	// Broadcast the finalized funding transaction to the network, but only
-	// if we actually have the funding transaction.
-	if completeChan.ChanType.HasFundingTx() {
+	// if we actually have the funding transaction. The funding transaction
+	// of a dual funded channel can only be broadcast once the remote party
+	// sent the witnesses of its inputs.
+	switch {
+	case resCtx.interactive != nil:
+		err := f.startTxSigsExchange(
+			peer, permChanID, resCtx.interactive,
+		)
+		if err != nil {
+			log.Errorf("Unable to send TxSignatures message: %v",
+				err)
+		}
+
+	case completeChan.ChanType.HasFundingTx():
 		fundingTx := completeChan.FundingTxn
 		var fundingTxBuf bytes.Buffer
 		if err := fundingTx.Serialize(&fundingTxBuf); err != nil {
@@ -2221,6 +2407,80 @@ type confirmedChannel struct {
 	fundingTx *wire.MsgTx
 }
 
+// cancelChannelFunding closes a channel whose funding transaction won't
+// confirm. Once the peer is online, we'll notify it that we consider the
+// channel flow canceled. A zero-conf channel is additionally removed from the
+// peer and the graph, as it has been in use already.
+func (f *Manager) cancelChannelFunding(ch *channeldb.OpenChannel,
+	pendingChanID [32]byte, reason error) error {
+
+	localBalance := ch.LocalCommitment.LocalBalance.ToBroneess()
+	closeInfo := &channeldb.ChannelCloseSummary{
+		ChainHash:               ch.ChainHash,
+		ChanPoint:               ch.FundingOutpoint,
+		RemotePub:               ch.IdentityPub,
+		Capacity:                ch.Capacity,
+		SettledBalance:          localBalance,
+		CloseType:               channeldb.FundingCanceled,
+		RemoteCurrentRevocation: ch.RemoteCurrentRevocation,
+		RemoteNextRevocation:    ch.RemoteNextRevocation,
+		LocalChanConfig:         ch.LocalChanCfg,
+	}
+
+	// Close the channel with us as the initiator because we are
+	// canceling the channel.
+	if err := ch.CloseChannel(
+		closeInfo, channeldb.ChanStatusLocalCloseInitiator,
+	); err != nil {
+		return fmt.Errorf("failed closing channel "+
+			"%v: %v", ch.FundingOutpoint, err)
+	}
+
+	// The edge of a zero-conf channel was added to the graph under its
+	// alias, which won't ever be pruned as it doesn't point to an
+	// on-chain output.
+	if ch.IsZeroConf() {
+		err := f.cfg.DeleteAliasEdge(ch.ShortChanID())
+		if err != nil {
+			log.Errorf("Unable to delete alias edge of "+
+				"ChannelPoint(%v): %v", ch.FundingOutpoint,
+				err)
+		}
+	}
+
+	// When the peer comes online, we'll notify it that we
+	// are now considering the channel flow canceled.
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+
+		peerChan := make(chan lnpeer.Peer, 1)
+		var peerKey [33]byte
+		copy(peerKey[:], ch.IdentityPub.SerializeCompressed())
+
+		f.cfg.NotifyWhenOnline(peerKey, peerChan)
+
+		var peer lnpeer.Peer
+		select {
+		case peer = <-peerChan:
+		case <-f.quit:
+			return
+		}
+
+		// The link of a zero-conf channel is active, so we remove
+		// it from the peer.
+		if ch.IsZeroConf() {
+			peer.WipeChannel(&ch.FundingOutpoint)
+		}
+
+		// TODO(halseth): should this send be made
+		// reliable?
+		f.failFundingFlow(peer, pendingChanID, reason)
+	}()
+
+	return nil
+}
+
 // waitForFundingWithTimeout is a wrapper around waitForFundingConfirmation and
 // waitForTimeout that will return ErrConfirmationTimeout if we are not the
 // channel initiator and the maxWaitNumBlocksFundingConf has passed from the
@@ -2238,8 +2498,9 @@ func (f *Manager) waitForFundingWithTimeout(
 
 	// If we are not the initiator, we have no money at stake and will
 	// timeout waiting for the funding transaction to confirm after a
-	// while.
-	if !ch.IsInitiator {
+	// while. The responder of a dual funded channel may have contributed
+	// funds, so it keeps waiting as well.
+	if !ch.IsInitiator && !ch.ChanType.IsDualFunder() {
 		f.wg.Add(1)
 		go f.waitForTimeout(ch, cancelChan, timeoutChan)
 	}
@@ -2307,6 +2568,13 @@ func (f *Manager) waitForFundingConfirmation(
 		return
 	}
 	numConfs := uint32(completeChan.NumConfsRequired)
+
+	// Zero-conf channels don't require any confirmations, but we still
+	// need the first one to learn the real SCID of the channel.
+	if numConfs == 0 {
+		numConfs = 1
+	}
+
 	confNtfn, err := f.cfg.Notifier.RegisterConfirmationsNtfn(
 		&txid, fundingScript, numConfs,
 		completeChan.FundingBroadcastHeight,
@@ -2373,6 +2641,90 @@ func (f *Manager) waitForFundingConfirmation(
 	}
 }
 
+// waitForFundingDoubleSpend sends an error on doubleSpendChan once an input of
+// the funding transaction of the given channel is spent by another
+// transaction, after which the funding transaction can't confirm anymore.
+// Only the inputs that belong to our wallet are watched, as we don't know the
+// scripts of the others. The wait can be canceled by closing the cancelChan.
+//
+// NOTE: doubleSpendChan MUST be buffered.
+// NOTE: This MUST be run as a goroutine.
+func (f *Manager) waitForFundingDoubleSpend(completeChan *channeldb.OpenChannel,
+	cancelChan <-chan struct{}, doubleSpendChan chan<- error) {
+
+	defer f.wg.Done()
+
+	fundingTx := completeChan.FundingTxn
+	fundingTxid := fundingTx.TxHash()
+
+	// The spend notifications of all inputs are forwarded to a single
+	// channel, which is drained until we return.
+	spends := make(chan *chainntnfs.SpendDetail)
+	done := make(chan struct{})
+	defer close(done)
+
+	for _, txIn := range fundingTx.TxIn {
+		prevOut := txIn.PreviousOutPoint
+		utxo, err := f.cfg.Wallet.FetchInputInfo(&prevOut)
+		if err != nil {
+			continue
+		}
+
+		spendNtfn, err := f.cfg.Notifier.RegisterSpendNtfn(
+			&prevOut, utxo.PkScript,
+			completeChan.FundingBroadcastHeight,
+		)
+		if err != nil {
+			log.Errorf("Unable to register for spend of funding "+
+				"input %v: %v", prevOut, err)
+			return
+		}
+
+		f.wg.Add(1)
+		go func() {
+			defer f.wg.Done()
+			defer spendNtfn.Cancel()
+
+			select {
+			case spend, ok := <-spendNtfn.Spend:
+				if !ok {
+					return
+				}
+
+				select {
+				case spends <- spend:
+				case <-done:
+				}
+
+			case <-done:
+			case <-f.quit:
+			}
+		}()
+	}
+
+	for {
+		select {
+		case spend := <-spends:
+			// The funding transaction itself spends all of its
+			// inputs.
+			if *spend.SpenderTxHash == fundingTxid {
+				continue
+			}
+
+			doubleSpendChan <- fmt.Errorf("funding tx input %v "+
+				"double spent by %v", spend.SpentOutPoint,
+				spend.SpenderTxHash)
+			return
+
+		case <-cancelChan:
+			return
+
+		case <-f.quit:
+			return
+		}
+	}
+}
+
 // waitForTimeout will close the timeout channel if maxWaitNumBlocksFundingConf
 // has passed from the broadcast height of the given channel. In case of error,
 // the error is sent on timeoutChan. The wait can be canceled by closing the
@@ -2455,6 +2807,19 @@ func (f *Manager) handleFundingConfirmation(
 		return fmt.Errorf("unable to validate channel: %v", err)
 	}
 
+	// Alias channels keep using their alias as the short channel ID
+	// towards the rest of the daemon, so we'll only record the confirmed
+	// one next to it.
+	shortChanID := confChannel.shortChanID
+	if completeChan.HasScidAlias() {
+		err = completeChan.MarkRealScid(confChannel.shortChanID)
+		if err != nil {
+			return fmt.Errorf("unable to mark real scid: %v", err)
+		}
+
+		shortChanID = completeChan.ShortChanID()
+	}
+
 	// The funding transaction now being confirmed, we add this channel to
 	// the fundingManager's internal persistent state machine that we use
 	// to track the remaining process of the channel opening. This is
@@ -2462,7 +2827,7 @@ func (f *Manager) handleFundingConfirmation(
 	// opening state before we mark the channel opened in the database,
 	// such that we can receover from one of the db writes failing.
 	err = f.saveChannelOpeningState(
-		&fundingPoint, markedOpen, &confChannel.shortChanID,
+		&fundingPoint, markedOpen, &shortChanID,
 	)
 	if err != nil {
 		return fmt.Errorf("error setting channel state to markedOpen: %v",
@@ -2471,7 +2836,7 @@ func (f *Manager) handleFundingConfirmation(
 
 	// Now that the channel has been fully confirmed and we successfully
 	// saved the opening state, we'll mark it as open within the database.
-	err = completeChan.MarkAsOpen(confChannel.shortChanID)
+	err = completeChan.MarkAsOpen(shortChanID)
 	if err != nil {
 		return fmt.Errorf("error setting channel pending flag to false: "+
 			"%v", err)
@@ -2495,9 +2860,8 @@ func (f *Manager) handleFundingConfirmation(
 	// our funding transaction has confirmed. We do not label transactions
 	// we did not publish, because our wallet has no knowledge of them.
 	if completeChan.IsInitiator && completeChan.ChanType.HasFundingTx() {
-		shortChanID := completeChan.ShortChanID()
 		label := labels.MakeLabel(
-			labels.LabelTypeChannelOpen, &shortChanID,
+			labels.LabelTypeChannelOpen, &confChannel.shortChanID,
 		)
 
 		err = f.cfg.UpdateLabel(
@@ -2521,6 +2885,170 @@ func (f *Manager) handleFundingConfirmation(
 	return nil
 }
 
+// handleZeroConfOpen marks a zero-conf channel as open without waiting for its
+// funding transaction to confirm. The channel's alias is used as its short
+// channel ID, and the opening state machine continues with sending the
+// funding locked message.
+func (f *Manager) handleZeroConfOpen(
+	completeChan *channeldb.OpenChannel) error {
+
+	fundingPoint := completeChan.FundingOutpoint
+	chanID := lnwire.NewChanIDFromOutPoint(&fundingPoint)
+	alias := completeChan.ShortChanID()
+
+	log.Infof("ChannelPoint(%v) is a zero-conf channel, marking open "+
+		"with alias %v", fundingPoint, alias)
+
+	err := f.saveChannelOpeningState(&fundingPoint, markedOpen, &alias)
+	if err != nil {
+		return fmt.Errorf("error setting channel state to "+
+			"markedOpen: %v", err)
+	}
+
+	err = completeChan.MarkAsOpen(alias)
+	if err != nil {
+		return fmt.Errorf("error setting channel pending flag to "+
+			"false: %v", err)
+	}
+
+	f.cfg.NotifyOpenChannelEvent(fundingPoint)
+
+	err = f.cfg.ReportShortChanID(fundingPoint)
+	if err != nil {
+		log.Errorf("unable to report short chan id: %v", err)
+	}
+
+	// As with a confirmed channel, we can now process the funding locked
+	// message of the peer.
+	f.localDiscoveryMtx.Lock()
+	if discoverySignal, ok := f.localDiscoverySignals[chanID]; ok {
+		close(discoverySignal)
+	}
+	f.localDiscoveryMtx.Unlock()
+
+	return nil
+}
+
+// waitForZeroConfChannel waits for the funding transaction of an open
+// zero-conf channel to confirm. Once it does, the channel is validated and its
+// confirmed short channel ID is stored next to its alias. If the funding
+// transaction is double spent, or doesn't confirm in time while we're not the
+// initiator, the channel is closed.
+//
+// NOTE: This MUST be run as a goroutine.
+func (f *Manager) waitForZeroConfChannel(completeChan *channeldb.OpenChannel) {
+	defer f.wg.Done()
+
+	confChan := make(chan *confirmedChannel)
+	timeoutChan := make(chan error, 1)
+	doubleSpendChan := make(chan error, 1)
+	cancelChan := make(chan struct{})
+	defer close(cancelChan)
+
+	f.wg.Add(1)
+	go f.waitForFundingConfirmation(completeChan, cancelChan, confChan)
+
+	// As with pending channels, we'll give up on the funding transaction
+	// after a while if we're not the initiator.
+	chanType := completeChan.ChanType
+	if !completeChan.IsInitiator && !chanType.IsDualFunder() {
+		f.wg.Add(1)
+		go f.waitForTimeout(completeChan, cancelChan, timeoutChan)
+	}
+
+	// If we know the funding transaction, we can also detect that it
+	// won't ever confirm as one of its inputs has been double spent.
+	if completeChan.FundingTxn != nil {
+		f.wg.Add(1)
+		go f.waitForFundingDoubleSpend(
+			completeChan, cancelChan, doubleSpendChan,
+		)
+	}
+
+	var (
+		confChannel *confirmedChannel
+		cancelErr   error
+	)
+	select {
+	case c, ok := <-confChan:
+		if !ok {
+			log.Errorf("Waiting for funding confirmation of "+
+				"zero-conf ChannelPoint(%v) failed",
+				completeChan.FundingOutpoint)
+			return
+		}
+		confChannel = c
+
+	case err := <-timeoutChan:
+		if err != nil {
+			log.Errorf("Unable to wait for funding timeout of "+
+				"zero-conf ChannelPoint(%v): %v",
+				completeChan.FundingOutpoint, err)
+			return
+		}
+		cancelErr = fmt.Errorf("timeout waiting for funding tx "+
+			"(%v) to confirm", completeChan.FundingOutpoint)
+
+	case err := <-doubleSpendChan:
+		cancelErr = err
+
+	case <-f.quit:
+		return
+	}
+
+	if cancelErr != nil {
+		log.Warnf("Closing zero-conf ChannelPoint(%v): %v",
+			completeChan.FundingOutpoint, cancelErr)
+
+		// The channel is already known to the peer by its permanent
+		// channel ID.
+		chanID := lnwire.NewChanIDFromOutPoint(
+			&completeChan.FundingOutpoint,
+		)
+		err := f.cancelChannelFunding(completeChan, chanID, cancelErr)
+		if err != nil {
+			log.Errorf("Unable to close zero-conf "+
+				"ChannelPoint(%v): %v",
+				completeChan.FundingOutpoint, err)
+		}
+		return
+	}
+
+	err := f.cfg.Wallet.ValidateChannel(completeChan, confChannel.fundingTx)
+	if err != nil {
+		log.Errorf("Unable to validate zero-conf ChannelPoint(%v): %v",
+			completeChan.FundingOutpoint, err)
+		return
+	}
+
+	err = completeChan.MarkRealScid(confChannel.shortChanID)
+	if err != nil {
+		log.Errorf("Unable to mark real scid of zero-conf "+
+			"ChannelPoint(%v): %v", completeChan.FundingOutpoint,
+			err)
+		return
+	}
+
+	log.Infof("Zero-conf ChannelPoint(%v) confirmed with "+
+		"short_chan_id=%v", completeChan.FundingOutpoint,
+		confChannel.shortChanID)
+
+	// Now that the real SCID is known, we can update the label of the
+	// funding transaction if we published it.
+	if completeChan.IsInitiator && completeChan.ChanType.HasFundingTx() {
+		label := labels.MakeLabel(
+			labels.LabelTypeChannelOpen, &confChannel.shortChanID,
+		)
+
+		err = f.cfg.UpdateLabel(
+			completeChan.FundingOutpoint.Hash, label,
+		)
+		if err != nil {
+			log.Errorf("unable to update label: %v", err)
+		}
+	}
+}
+
 // sendFundingLocked creates and sends the fundingLocked message.
 // This should be called after the funding transaction has been confirmed,
 // and the channelState is 'markedOpen'.
@@ -2543,6 +3071,13 @@ func (f *Manager) sendFundingLocked(
 	}
 	fundingLockedMsg := lnwire.NewFundingLocked(chanID, nextRevocation)
 
+	// For alias channels, we'll include our alias so the peer can use it
+	// in route hints instead of the real SCID.
+	if completeChan.HasScidAlias() {
+		alias := completeChan.ShortChanID()
+		fundingLockedMsg.AliasScid = &alias
+	}
+
 	// If the peer has disconnected before we reach this point, we will need
 	// to wait for him to come back online before sending the fundingLocked
 	// message. This is special for fundingLocked, since failing to send any
@@ -2859,6 +3394,17 @@ func (f *Manager) handleFundingLocked(peer lnpeer.Peer,
 		return
 	}
 
+	// If the peer sent us an alias for an alias channel, we'll store it
+	// so that we can use it in our route hints.
+	if msg.AliasScid != nil && channel.HasScidAlias() {
+		err = f.cfg.AliasManager.PutPeerAlias(chanID, *msg.AliasScid)
+		if err != nil {
+			log.Errorf("Unable to store peer alias for "+
+				"ChannelID(%v): %v", chanID, err)
+			return
+		}
+	}
+
 	// If the RemoteNextRevocation is non-nil, it means that we have
 	// already processed fundingLocked for this channel, so ignore.
 	if channel.RemoteNextRevocation != nil {
@@ -3264,6 +3810,66 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 		return
 	}
 
+	// The funding transaction of a dual funded channel is constructed
+	// interactively from the wallets of both parties. Both parties have
+	// funds at stake, so neither can trust the other with a zero-conf
+	// channel.
+	if msg.DualFund {
+		switch {
+		case !dualFundSupported(msg.Peer):
+			msg.Err <- ErrDualFundNotSupported
+			return
+
+		case msg.PushAmt != 0:
+			msg.Err <- fmt.Errorf("dual funded channels can't " +
+				"push funds")
+			return
+
+		case msg.ChanFunder != nil:
+			msg.Err <- fmt.Errorf("dual funded channels must be " +
+				"funded by the wallet")
+			return
+
+		case msg.ZeroConf || msg.ScidAlias:
+			msg.Err <- fmt.Errorf("dual funded channels can't " +
+				"be zero-conf or scid-alias channels")
+			return
+		}
+	}
+
+	// Zero-conf channels always use an alias, and alias channels can't be
+	// announced as the alias doesn't point to an on-chain output.
+	scidAlias := msg.ScidAlias || msg.ZeroConf
+	if scidAlias && !msg.Private {
+		msg.Err <- fmt.Errorf("zero-conf and scid-alias channels " +
+			"must be private")
+		return
+	}
+
+	// Both zero-conf and scid-alias can only be negotiated through an
+	// explicit channel type. We'll add the required bits either to the
+	// channel type the caller asked for, or to the one we'd arrive at
+	// through implicit negotiation.
+	if scidAlias {
+		chanType := msg.ChannelType
+		if chanType == nil {
+			chanType, _ = implicitNegotiateCommitmentType(
+				msg.Peer.LocalFeatures(),
+				msg.Peer.RemoteFeatures(),
+			)
+		}
+
+		rawChanType := lnwire.RawFeatureVector(*chanType)
+		featureVec := rawChanType.Clone()
+		featureVec.Set(lnwire.ScidAliasRequired)
+		if msg.ZeroConf {
+			featureVec.Set(lnwire.ZeroConfRequired)
+		}
+
+		aliasChanType := lnwire.ChannelType(*featureVec)
+		msg.ChannelType = &aliasChanType
+	}
+
 	// Initialize a funding reservation with the local wallet. If the
 	// wallet doesn't have enough funds to commit to this channel, then the
 	// request will fail, and be aborted.
@@ -3313,6 +3919,10 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 		MinConfs:         msg.MinConfs,
 		CommitType:       commitType,
 		ChanFunder:       msg.ChanFunder,
+		ZeroConf:         msg.ZeroConf,
+		OptionScidAlias:  scidAlias,
+		DualFund:         msg.DualFund,
+		Initiator:        true,
 	}
 
 	reservation, err := f.cfg.Wallet.InitChannelReservation(req)
@@ -3321,6 +3931,27 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 		return
 	}
 
+	// If this is an alias channel, we'll request an alias that is used as
+	// the channel's SCID until the funding transaction confirms. The
+	// alias also serves as the channel's base SCID, so it maps to itself.
+	if scidAlias {
+		alias, err := f.cfg.AliasManager.RequestAlias()
+		if err != nil {
+			reservation.Cancel()
+			msg.Err <- err
+			return
+		}
+
+		err = f.cfg.AliasManager.AddLocalAlias(alias, alias)
+		if err != nil {
+			reservation.Cancel()
+			msg.Err <- err
+			return
+		}
+
+		reservation.AddAlias(alias)
+	}
+
 	// Set our upfront shutdown address in the existing reservation.
 	reservation.SetOurUpfrontShutdown(shutdown)
 
@@ -3431,7 +4062,21 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 		ChannelType:           chanType,
 		LeaseExpiry:           leaseExpiry,
 	}
-	if err := msg.Peer.SendMessage(true, &fundingOpen); err != nil {
+
+	// A dual funded channel is proposed with the OpenChannel2 message
+	// instead, which carries the same parameters.
+	sendOpen := func() error {
+		return msg.Peer.SendMessage(true, &fundingOpen)
+	}
+	if msg.DualFund {
+		sendOpen = func() error {
+			return f.sendOpenChannel2(
+				resCtx, chanID, msg, &fundingOpen,
+				commitFeePerKw,
+			)
+		}
+	}
+	if err := sendOpen(); err != nil {
 		e := fmt.Errorf("unable to send funding request message: %v",
 			err)
 		log.Errorf(e.Error())
@@ -3457,6 +4102,13 @@ func (f *Manager) handleErrorMsg(peer lnpeer.Peer,
 	chanID := msg.ChanID
 	peerKey := peer.IdentityKey()
 
+	// If we were waiting for the signatures of a dual funded channel's
+	// funding transaction, we'll stop doing so. The channel itself is
+	// already pending and times out if the transaction never confirms.
+	f.resMtx.Lock()
+	delete(f.pendingTxSigs, chanID)
+	f.resMtx.Unlock()
+
 	// First, we'll attempt to retrieve and cancel the funding workflow
 	// that this error was tied to. If we're unable to do so, then we'll
 	// exit early as this was an unwarranted error.
@@ -3628,6 +4280,35 @@ func (f *Manager) IsPendingChannel(pendingChanID [32]byte,
 	return ok
 }
 
+// AnnounceSplicedChannel adds a channel to the graph again once its splice
+// has confirmed, as the edge of the old funding outpoint is pruned once it's
+// spent. Public channels are announced to the network after six
+// confirmations of the splice transaction, like newly opened ones.
+//
+// NOTE: This is part of the Controller interface.
+func (f *Manager) AnnounceSplicedChannel(
+	channel *channeldb.OpenChannel) error {
+
+	shortChanID := channel.ShortChanID()
+	if err := f.addToRouterGraph(channel, &shortChanID); err != nil {
+		return err
+	}
+
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+
+		err := f.annAfterSixConfs(channel, &shortChanID)
+		if err != nil {
+			log.Errorf("Unable to announce spliced "+
+				"ChannelPoint(%v): %v",
+				channel.FundingOutpoint, err)
+		}
+	}()
+
+	return nil
+}
+
 func copyPubKey(pub *bronec.PublicKey) *bronec.PublicKey {
 	return &bronec.PublicKey{
 		Curve: bronec.S256(),
@@ -14,6 +14,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -119,6 +120,7 @@ type mockNotifier struct {
 	oneConfChannel chan *chainntnfs.TxConfirmation
 	sixConfChannel chan *chainntnfs.TxConfirmation
 	epochChan      chan *chainntnfs.BlockEpoch
+	spendChan      chan *chainntnfs.SpendDetail
 }
 
 func (m *mockNotifier) RegisterConfirmationsNtfn(txid *chainhash.Hash,
@@ -157,11 +159,76 @@ func (m *mockNotifier) Stop() error {
 func (m *mockNotifier) RegisterSpendNtfn(outpoint *wire.OutPoint, _ []byte,
 	heightHint uint32) (*chainntnfs.SpendEvent, error) {
 	return &chainntnfs.SpendEvent{
-		Spend:  make(chan *chainntnfs.SpendDetail),
+		Spend:  m.spendChan,
 		Cancel: func() {},
 	}, nil
 }
 
+// mockAliasMgr is a mock implementation of the aliasHandler interface, which
+// hands out aliases in order and records the aliases of the peer.
+type mockAliasMgr struct {
+	sync.Mutex
+
+	nextAlias   lnwire.ShortChannelID
+	peerAliases map[lnwire.ChannelID]lnwire.ShortChannelID
+}
+
+func newMockAliasMgr() *mockAliasMgr {
+	return &mockAliasMgr{
+		nextAlias: lnwire.ShortChannelID{
+			BlockHeight: 16_000_000,
+		},
+		peerAliases: make(map[lnwire.ChannelID]lnwire.ShortChannelID),
+	}
+}
+
+func (m *mockAliasMgr) RequestAlias() (lnwire.ShortChannelID, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	alias := m.nextAlias
+	m.nextAlias.TxIndex++
+
+	return alias, nil
+}
+
+func (m *mockAliasMgr) AddLocalAlias(_, _ lnwire.ShortChannelID) error {
+	return nil
+}
+
+func (m *mockAliasMgr) PutPeerAlias(chanID lnwire.ChannelID,
+	alias lnwire.ShortChannelID) error {
+
+	m.Lock()
+	defer m.Unlock()
+
+	m.peerAliases[chanID] = alias
+
+	return nil
+}
+
+func (m *mockAliasMgr) peerAlias(
+	chanID lnwire.ChannelID) (lnwire.ShortChannelID, bool) {
+
+	m.Lock()
+	defer m.Unlock()
+
+	alias, ok := m.peerAliases[chanID]
+	return alias, ok
+}
+
+// mockZeroConfAcceptor is a channel acceptor that accepts all channels as
+// zero-conf channels.
+type mockZeroConfAcceptor struct{}
+
+func (m *mockZeroConfAcceptor) Accept(
+	*chanacceptor.ChannelAcceptRequest) *chanacceptor.ChannelAcceptResponse {
+
+	return &chanacceptor.ChannelAcceptResponse{
+		ZeroConf: true,
+	}
+}
+
 type mockChanEvent struct {
 	openEvent        chan wire.OutPoint
 	pendingOpenEvent chan channelnotifier.PendingOpenChannelEvent
@@ -197,7 +264,10 @@ type testNode struct {
 	mockChanEvent   *mockChanEvent
 	testDir         string
 	shutdownChannel chan struct{}
+	localFeatures   []lnwire.FeatureBit
 	remoteFeatures  []lnwire.FeatureBit
+	aliasMgr        *mockAliasMgr
+	deletedAliases  chan lnwire.ShortChannelID
 
 	remotePeer  *testNode
 	sendMessage func(lnwire.Message) error
@@ -232,7 +302,9 @@ func (n *testNode) QuitSignal() <-chan struct{} {
 }
 
 func (n *testNode) LocalFeatures() *lnwire.FeatureVector {
-	return lnwire.NewFeatureVector(nil, nil)
+	return lnwire.NewFeatureVector(
+		lnwire.NewRawFeatureVector(n.localFeatures...), nil,
+	)
 }
 
 func (n *testNode) RemoteFeatures() *lnwire.FeatureVector {
@@ -303,12 +375,15 @@ func createTestFundingManager(t *testing.T, privKey *bronec.PrivateKey,
 		oneConfChannel: make(chan *chainntnfs.TxConfirmation, 1),
 		sixConfChannel: make(chan *chainntnfs.TxConfirmation, 1),
 		epochChan:      make(chan *chainntnfs.BlockEpoch, 2),
+		spendChan:      make(chan *chainntnfs.SpendDetail, 1),
 	}
 
 	sentMessages := make(chan lnwire.Message)
 	sentAnnouncements := make(chan lnwire.Message)
 	publTxChan := make(chan *wire.MsgTx, 1)
 	shutdownChan := make(chan struct{})
+	aliasMgr := newMockAliasMgr()
+	deletedAliases := make(chan lnwire.ShortChannelID, 1)
 
 	wc := &mock.WalletController{
 		RootKey: alicePrivKey,
@@ -450,6 +525,11 @@ func createTestFundingManager(t *testing.T, privKey *bronec.PrivateKey,
 		OpenChannelPredicate:          chainedAcceptor,
 		NotifyPendingOpenChannelEvent: evt.NotifyPendingOpenChannelEvent,
 		RegisteredChains:              chainreg.NewChainRegistry(),
+		AliasManager:                  aliasMgr,
+		DeleteAliasEdge: func(alias lnwire.ShortChannelID) error {
+			deletedAliases <- alias
+			return nil
+		},
 	}
 
 	for _, op := range options {
@@ -476,6 +556,8 @@ func createTestFundingManager(t *testing.T, privKey *bronec.PrivateKey,
 		testDir:         tempTestDir,
 		shutdownChannel: shutdownChan,
 		addr:            addr,
+		aliasMgr:        aliasMgr,
+		deletedAliases:  deletedAliases,
 	}
 
 	f.cfg.NotifyWhenOnline = func(peer [33]byte,
@@ -554,6 +636,8 @@ func recreateAliceFundingManager(t *testing.T, alice *testNode) {
 		ZombieSweeperInterval: oldCfg.ZombieSweeperInterval,
 		ReservationTimeout:    oldCfg.ReservationTimeout,
 		OpenChannelPredicate:  chainedAcceptor,
+		AliasManager:          oldCfg.AliasManager,
+		DeleteAliasEdge:       oldCfg.DeleteAliasEdge,
 	})
 	if err != nil {
 		t.Fatalf("failed recreating aliceFundingManager: %v", err)
@@ -661,10 +745,12 @@ func openChannel(t *testing.T, alice, bob *testNode, localFundingAmt,
 }
 
 // fundChannel takes the funding process to the point where the funding
-// transaction is confirmed on-chain. Returns the funding tx.
+// transaction is confirmed on-chain. Returns the funding tx. The options can
+// modify the funding request before it is sent.
 func fundChannel(t *testing.T, alice, bob *testNode, localFundingAmt,
 	pushAmt bronutil.Amount, subtractFees bool, numConfs uint32,
-	updateChan chan *lnrpc.OpenStatusUpdate, announceChan bool) *wire.MsgTx {
+	updateChan chan *lnrpc.OpenStatusUpdate, announceChan bool,
+	options ...func(*InitFundingMsg)) *wire.MsgTx {
 
 	// Create a funding request and start the workflow.
 	errChan := make(chan error, 1)
@@ -680,6 +766,9 @@ func fundChannel(t *testing.T, alice, bob *testNode, localFundingAmt,
 		Updates:         updateChan,
 		Err:             errChan,
 	}
+	for _, option := range options {
+		option(initReq)
+	}
 
 	alice.fundingMgr.InitFundingWorkflow(initReq)
 
@@ -3682,3 +3771,347 @@ func testUpfrontFailure(t *testing.T, pkscript []byte, expectErr bool) {
 		require.True(t, ok, "did not receive AcceptChannel")
 	}
 }
+
+// aliasFeatures are the features both nodes need to signal to negotiate a
+// zero-conf or scid-alias channel type.
+var aliasFeatures = []lnwire.FeatureBit{
+	lnwire.ExplicitChannelTypeOptional,
+	lnwire.ZeroConfOptional,
+	lnwire.ScidAliasOptional,
+}
+
+// setupAliasFundingManagers creates funding managers for Alice and Bob that
+// can negotiate alias channels. If zeroConf is set, Bob's channel acceptor
+// accepts all channels as zero-conf channels.
+func setupAliasFundingManagers(t *testing.T,
+	zeroConf bool) (*testNode, *testNode) {
+
+	var options []cfgOption
+	if zeroConf {
+		options = append(options, func(cfg *Config) {
+			cfg.OpenChannelPredicate = &mockZeroConfAcceptor{}
+		})
+	}
+
+	alice, bob := setupFundingManagers(t, options...)
+	for _, node := range []*testNode{alice, bob} {
+		node.localFeatures = aliasFeatures
+		node.remoteFeatures = aliasFeatures
+	}
+
+	return alice, bob
+}
+
+// openZeroConfChannel opens a private zero-conf channel between Alice and
+// Bob, and takes it through the opening state machine without confirming the
+// funding transaction. Returns the funding outpoint and transaction.
+func openZeroConfChannel(t *testing.T, alice, bob *testNode) (*wire.OutPoint,
+	*wire.MsgTx) {
+
+	updateChan := make(chan *lnrpc.OpenStatusUpdate)
+
+	localAmt := bronutil.Amount(500000)
+	fundingTx := fundChannel(
+		t, alice, bob, localAmt, 0, false, 1, updateChan, false,
+		func(msg *InitFundingMsg) {
+			msg.ZeroConf = true
+		},
+	)
+	fundingOutPoint := &wire.OutPoint{
+		Hash:  fundingTx.TxHash(),
+		Index: 0,
+	}
+
+	// Without any confirmation, both sides should consider the channel
+	// open.
+	assertMarkedOpen(t, alice, bob, fundingOutPoint)
+
+	// They'll send their funding locked messages, which carry their alias.
+	fundingLockedAlice := assertFundingMsgSent(
+		t, alice.msgChan, "FundingLocked",
+	).(*lnwire.FundingLocked)
+	require.NotNil(t, fundingLockedAlice.AliasScid)
+
+	fundingLockedBob := assertFundingMsgSent(
+		t, bob.msgChan, "FundingLocked",
+	).(*lnwire.FundingLocked)
+	require.NotNil(t, fundingLockedBob.AliasScid)
+
+	assertFundingLockedSent(t, alice, bob, fundingOutPoint)
+	assertChannelAnnouncements(t, alice, bob, localAmt, nil, nil)
+	assertAddedToRouterGraph(t, alice, bob, fundingOutPoint)
+	waitForOpenUpdate(t, updateChan)
+
+	// Exchange the funding locked messages, after which both sides should
+	// know the alias of their peer.
+	alice.fundingMgr.ProcessFundingMsg(fundingLockedBob, bob)
+	bob.fundingMgr.ProcessFundingMsg(fundingLockedAlice, alice)
+	assertHandleFundingLocked(t, alice, bob)
+
+	chanID := lnwire.NewChanIDFromOutPoint(fundingOutPoint)
+	aliceAlias, ok := bob.aliasMgr.peerAlias(chanID)
+	require.True(t, ok, "bob did not store alice's alias")
+	require.Equal(t, *fundingLockedAlice.AliasScid, aliceAlias)
+
+	bobAlias, ok := alice.aliasMgr.peerAlias(chanID)
+	require.True(t, ok, "alice did not store bob's alias")
+	require.Equal(t, *fundingLockedBob.AliasScid, bobAlias)
+
+	return fundingOutPoint, fundingTx
+}
+
+// fetchOpenChannel fetches the channel with the given funding outpoint that
+// the node has open with its peer.
+func fetchOpenChannel(t *testing.T, node *testNode,
+	fundingOutPoint *wire.OutPoint) *channeldb.OpenChannel {
+
+	t.Helper()
+
+	channels, err := node.fundingMgr.cfg.Wallet.Cfg.Database.
+		FetchOpenChannels(node.remotePeer.privKey.PubKey())
+	require.NoError(t, err)
+
+	for _, channel := range channels {
+		if channel.FundingOutpoint == *fundingOutPoint {
+			return channel
+		}
+	}
+
+	t.Fatalf("channel %v not found", fundingOutPoint)
+	return nil
+}
+
+// assertZeroConfConfirmed asserts that the node eventually stores the given
+// confirmed short channel ID next to the channel's alias.
+func assertZeroConfConfirmed(t *testing.T, node *testNode,
+	fundingOutPoint *wire.OutPoint, confirmedScid lnwire.ShortChannelID) {
+
+	t.Helper()
+
+	var channel *channeldb.OpenChannel
+	for i := 0; i < testPollNumTries; i++ {
+		// If this is not the first try, sleep before retrying.
+		if i > 0 {
+			time.Sleep(testPollSleepMs * time.Millisecond)
+		}
+
+		channel = fetchOpenChannel(t, node, fundingOutPoint)
+		if channel.ZeroConfConfirmed() {
+			break
+		}
+	}
+
+	require.Equal(t, confirmedScid, channel.ZeroConfRealScid())
+
+	// The alias is still used as the channel's short channel ID.
+	require.NotEqual(t, confirmedScid, channel.ShortChanID())
+}
+
+// assertZeroConfCanceled asserts that the node closed the zero-conf channel,
+// deleted its alias edge and sent an error to its peer.
+func assertZeroConfCanceled(t *testing.T, node *testNode,
+	fundingOutPoint *wire.OutPoint) {
+
+	t.Helper()
+
+	select {
+	case alias := <-node.deletedAliases:
+		require.Equal(t, uint32(16_000_000), alias.BlockHeight)
+	case <-time.After(time.Second * 5):
+		t.Fatalf("alias edge not deleted")
+	}
+
+	assertErrorSent(t, node.msgChan)
+
+	closedChans, err := node.fundingMgr.cfg.Wallet.Cfg.Database.
+		FetchClosedChannels(false)
+	require.NoError(t, err)
+	require.Len(t, closedChans, 1)
+	require.Equal(t, *fundingOutPoint, closedChans[0].ChanPoint)
+	require.Equal(
+		t, channeldb.FundingCanceled, closedChans[0].CloseType,
+	)
+
+	openChans, err := node.fundingMgr.cfg.Wallet.Cfg.Database.
+		FetchOpenChannels(node.remotePeer.privKey.PubKey())
+	require.NoError(t, err)
+	require.Empty(t, openChans)
+}
+
+// TestFundingManagerZeroConf checks that a zero-conf channel is opened
+// without waiting for confirmations, and that the confirmed short channel ID
+// is recorded next to the alias once the funding transaction confirms.
+func TestFundingManagerZeroConf(t *testing.T) {
+	t.Parallel()
+
+	alice, bob := setupAliasFundingManagers(t, true)
+	defer tearDownFundingManagers(t, alice, bob)
+
+	fundingOutPoint, fundingTx := openZeroConfChannel(t, alice, bob)
+
+	// Now we'll confirm the funding transaction, which should make both
+	// sides store the confirmed short channel ID.
+	confirmedScid := lnwire.ShortChannelID{
+		BlockHeight: fundingBroadcastHeight + 1,
+		TxIndex:     2,
+	}
+	for _, node := range []*testNode{alice, bob} {
+		node.mockNotifier.oneConfChannel <- &chainntnfs.TxConfirmation{
+			Tx:          fundingTx,
+			BlockHeight: confirmedScid.BlockHeight,
+			TxIndex:     confirmedScid.TxIndex,
+		}
+	}
+
+	assertZeroConfConfirmed(t, alice, fundingOutPoint, confirmedScid)
+	assertZeroConfConfirmed(t, bob, fundingOutPoint, confirmedScid)
+}
+
+// TestFundingManagerZeroConfRestart checks that the initiator of a zero-conf
+// channel rebroadcasts the funding transaction and resumes waiting for it to
+// confirm after a restart.
+func TestFundingManagerZeroConfRestart(t *testing.T) {
+	t.Parallel()
+
+	alice, bob := setupAliasFundingManagers(t, true)
+	defer tearDownFundingManagers(t, alice, bob)
+
+	fundingOutPoint, fundingTx := openZeroConfChannel(t, alice, bob)
+
+	recreateAliceFundingManager(t, alice)
+
+	// As the funding transaction hasn't confirmed yet, Alice should
+	// rebroadcast it.
+	select {
+	case tx := <-alice.publTxChan:
+		require.Equal(t, fundingTx.TxHash(), tx.TxHash())
+	case <-time.After(time.Second * 5):
+		t.Fatalf("alice did not rebroadcast funding tx")
+	}
+
+	confirmedScid := lnwire.ShortChannelID{
+		BlockHeight: fundingBroadcastHeight + 1,
+		TxIndex:     2,
+	}
+	alice.mockNotifier.oneConfChannel <- &chainntnfs.TxConfirmation{
+		Tx:          fundingTx,
+		BlockHeight: confirmedScid.BlockHeight,
+		TxIndex:     confirmedScid.TxIndex,
+	}
+
+	assertZeroConfConfirmed(t, alice, fundingOutPoint, confirmedScid)
+}
+
+// TestFundingManagerZeroConfDoubleSpend checks that a zero-conf channel is
+// closed if its funding transaction is double spent.
+func TestFundingManagerZeroConfDoubleSpend(t *testing.T) {
+	t.Parallel()
+
+	alice, bob := setupAliasFundingManagers(t, true)
+	defer tearDownFundingManagers(t, alice, bob)
+
+	fundingOutPoint, fundingTx := openZeroConfChannel(t, alice, bob)
+
+	// Another transaction spending one of the funding inputs means the
+	// funding transaction can't ever confirm, so Alice should close the
+	// channel.
+	doubleSpendHash := chainhash.Hash{1}
+	alice.mockNotifier.spendChan <- &chainntnfs.SpendDetail{
+		SpentOutPoint: &fundingTx.TxIn[0].PreviousOutPoint,
+		SpenderTxHash: &doubleSpendHash,
+	}
+
+	assertZeroConfCanceled(t, alice, fundingOutPoint)
+}
+
+// TestFundingManagerZeroConfTimeout checks that the responder of a zero-conf
+// channel closes it if the funding transaction doesn't confirm in time.
+func TestFundingManagerZeroConfTimeout(t *testing.T) {
+	t.Parallel()
+
+	alice, bob := setupAliasFundingManagers(t, true)
+	defer tearDownFundingManagers(t, alice, bob)
+
+	fundingOutPoint, _ := openZeroConfChannel(t, alice, bob)
+
+	bob.mockNotifier.epochChan <- &chainntnfs.BlockEpoch{
+		Height: fundingBroadcastHeight + maxWaitNumBlocksFundingConf,
+	}
+
+	assertZeroConfCanceled(t, bob, fundingOutPoint)
+}
+
+// TestFundingManagerScidAlias checks that a non zero-conf channel using the
+// scid-alias channel type keeps using its alias after the funding transaction
+// confirms, and that the aliases are exchanged in the funding locked messages.
+func TestFundingManagerScidAlias(t *testing.T) {
+	t.Parallel()
+
+	alice, bob := setupAliasFundingManagers(t, false)
+	defer tearDownFundingManagers(t, alice, bob)
+
+	updateChan := make(chan *lnrpc.OpenStatusUpdate)
+
+	localAmt := bronutil.Amount(500000)
+	fundingTx := fundChannel(
+		t, alice, bob, localAmt, 0, false, 1, updateChan, false,
+		func(msg *InitFundingMsg) {
+			msg.ScidAlias = true
+		},
+	)
+	fundingOutPoint := &wire.OutPoint{
+		Hash:  fundingTx.TxHash(),
+		Index: 0,
+	}
+
+	confirmedScid := lnwire.ShortChannelID{
+		BlockHeight: fundingBroadcastHeight + 1,
+		TxIndex:     2,
+	}
+	for _, node := range []*testNode{alice, bob} {
+		node.mockNotifier.oneConfChannel <- &chainntnfs.TxConfirmation{
+			Tx:          fundingTx,
+			BlockHeight: confirmedScid.BlockHeight,
+			TxIndex:     confirmedScid.TxIndex,
+		}
+	}
+
+	assertMarkedOpen(t, alice, bob, fundingOutPoint)
+
+	// The funding locked messages should carry the aliases rather than
+	// the confirmed short channel ID.
+	fundingLockedAlice := assertFundingMsgSent(
+		t, alice.msgChan, "FundingLocked",
+	).(*lnwire.FundingLocked)
+	require.NotNil(t, fundingLockedAlice.AliasScid)
+	require.NotEqual(t, confirmedScid, *fundingLockedAlice.AliasScid)
+
+	fundingLockedBob := assertFundingMsgSent(
+		t, bob.msgChan, "FundingLocked",
+	).(*lnwire.FundingLocked)
+	require.NotNil(t, fundingLockedBob.AliasScid)
+	require.NotEqual(t, confirmedScid, *fundingLockedBob.AliasScid)
+
+	assertFundingLockedSent(t, alice, bob, fundingOutPoint)
+	assertChannelAnnouncements(t, alice, bob, localAmt, nil, nil)
+	assertAddedToRouterGraph(t, alice, bob, fundingOutPoint)
+	waitForOpenUpdate(t, updateChan)
+
+	alice.fundingMgr.ProcessFundingMsg(fundingLockedBob, bob)
+	bob.fundingMgr.ProcessFundingMsg(fundingLockedAlice, alice)
+	assertHandleFundingLocked(t, alice, bob)
+
+	chanID := lnwire.NewChanIDFromOutPoint(fundingOutPoint)
+	bobAlias, ok := alice.aliasMgr.peerAlias(chanID)
+	require.True(t, ok, "alice did not store bob's alias")
+	require.Equal(t, *fundingLockedBob.AliasScid, bobAlias)
+
+	aliceAlias, ok := bob.aliasMgr.peerAlias(chanID)
+	require.True(t, ok, "bob did not store alice's alias")
+	require.Equal(t, *fundingLockedAlice.AliasScid, aliceAlias)
+
+	// Both sides should have recorded the confirmed short channel ID next
+	// to their alias.
+	assertZeroConfConfirmed(t, alice, fundingOutPoint, confirmedScid)
+	assertZeroConfConfirmed(t, bob, fundingOutPoint, confirmedScid)
+}
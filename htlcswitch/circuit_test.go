@@ -93,7 +93,15 @@ func newOnionProcessor(t *testing.T) *hop.OnionProcessor {
 		t.Fatalf("unable to start sphinx router: %v", err)
 	}
 
-	return hop.NewOnionProcessor(sphinxRouter)
+	return hop.NewOnionProcessor(
+		sphinxRouter, &keychain.PrivKeyECDH{PrivKey: sphinxPrivKey},
+		func(blindedKey keychain.SingleKeyECDH) *sphinx.Router {
+			return sphinx.NewRouter(
+				blindedKey, &brocoinCfg.SimNetParams,
+				sphinx.NewMemoryReplayLog(),
+			)
+		},
+	)
 }
 
 // newCircuitMap creates a new htlcswitch.CircuitMap using a temp db and a
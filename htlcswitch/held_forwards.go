@@ -0,0 +1,392 @@
+package htlcswitch
+
+import (
+	"bytes"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/brronsuite/broln/channeldb"
+	"github.com/brronsuite/broln/clock"
+	"github.com/brronsuite/broln/kvdb"
+	"github.com/brronsuite/broln/lntypes"
+	"github.com/brronsuite/broln/lnwire"
+)
+
+var (
+	// heldForwardsBucketKey is used for the root level bucket that stores
+	// the htlcs held by an interceptor, keyed by their incoming circuit
+	// key.
+	heldForwardsBucketKey = []byte("held-forwards-bucket")
+)
+
+// HeldForward describes an htlc that is held by an interceptor until it is
+// resolved. Held htlcs are persisted, such that they can be held again when
+// they are replayed after a restart.
+type HeldForward struct {
+	// IncomingCircuit contains the incoming channel and htlc id of the
+	// htlc.
+	IncomingCircuit channeldb.CircuitKey
+
+	// OutgoingChanID is the requested outgoing channel of the htlc. It is
+	// hop.Exit for htlcs destined for our own invoices.
+	OutgoingChanID lnwire.ShortChannelID
+
+	// Hash is the payment hash of the htlc.
+	Hash lntypes.Hash
+
+	// OutgoingExpiry is the absolute block height at which the outgoing
+	// htlc expires.
+	OutgoingExpiry uint32
+
+	// OutgoingAmount is the amount to forward.
+	OutgoingAmount lnwire.MilliBronees
+
+	// IncomingExpiry is the absolute block height at which the incoming
+	// htlc expires.
+	IncomingExpiry uint32
+
+	// IncomingAmount is the amount of the accepted htlc.
+	IncomingAmount lnwire.MilliBronees
+
+	// HeldSince is the time at which the htlc was first held. It is
+	// preserved when the htlc is held again after a restart.
+	HeldSince time.Time
+}
+
+// serializeHeldForward serializes the held forward, without its incoming
+// circuit which is used as key.
+func serializeHeldForward(w io.Writer, f *HeldForward) error {
+	return channeldb.WriteElements(w,
+		f.OutgoingChanID, [32]byte(f.Hash), f.OutgoingExpiry,
+		f.OutgoingAmount, f.IncomingExpiry, f.IncomingAmount,
+		uint64(f.HeldSince.UnixNano()),
+	)
+}
+
+// deserializeHeldForward deserializes a held forward stored under the given
+// incoming circuit.
+func deserializeHeldForward(r io.Reader,
+	key channeldb.CircuitKey) (*HeldForward, error) {
+
+	f := &HeldForward{
+		IncomingCircuit: key,
+	}
+
+	var (
+		hash      [32]byte
+		heldSince uint64
+	)
+	if err := channeldb.ReadElements(r,
+		&f.OutgoingChanID, &hash, &f.OutgoingExpiry,
+		&f.OutgoingAmount, &f.IncomingExpiry, &f.IncomingAmount,
+		&heldSince,
+	); err != nil {
+		return nil, err
+	}
+
+	f.Hash = hash
+	f.HeldSince = time.Unix(0, int64(heldSince))
+
+	return f, nil
+}
+
+// heldForwardStore is a persistent store of the htlcs held by an interceptor.
+// All held forwards are cached in memory, such that the interceptable switch
+// can check whether an htlc was held before a restart without hitting the
+// database for every forward.
+type heldForwardStore struct {
+	backend kvdb.Backend
+	clock   clock.Clock
+
+	mu       sync.Mutex
+	forwards map[channeldb.CircuitKey]*HeldForward
+}
+
+// newHeldForwardStore creates a held forward store, loading all held forwards
+// from the database. Held forwards whose incoming htlc isn't part of an open
+// channel anymore are removed, as they were resolved while we were down.
+func newHeldForwardStore(db kvdb.Backend, clock clock.Clock,
+	fetchAllOpenChannels func() ([]*channeldb.OpenChannel, error)) (
+	*heldForwardStore, error) {
+
+	store := &heldForwardStore{
+		backend:  db,
+		clock:    clock,
+		forwards: make(map[channeldb.CircuitKey]*HeldForward),
+	}
+
+	var forwards []*HeldForward
+	err := kvdb.Update(db, func(tx kvdb.RwTx) error {
+		bucket, err := tx.CreateTopLevelBucket(heldForwardsBucketKey)
+		if err != nil {
+			return err
+		}
+
+		return bucket.ForEach(func(k, v []byte) error {
+			var key channeldb.CircuitKey
+			if err := key.SetBytes(k); err != nil {
+				return err
+			}
+
+			f, err := deserializeHeldForward(
+				bytes.NewReader(v), key,
+			)
+			if err != nil {
+				return err
+			}
+
+			forwards = append(forwards, f)
+
+			return nil
+		})
+	}, func() {
+		forwards = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(forwards) == 0 {
+		return store, nil
+	}
+
+	// Collect the incoming htlcs of all open channels, such that we can
+	// remove the held forwards that were resolved in the meantime.
+	openChannels, err := fetchAllOpenChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	incoming := make(map[channeldb.CircuitKey]struct{})
+	for _, openChannel := range openChannels {
+		chanID := openChannel.ShortChanID()
+		for _, htlc := range openChannel.LocalCommitment.Htlcs {
+			if !htlc.Incoming {
+				continue
+			}
+
+			incoming[channeldb.CircuitKey{
+				ChanID: chanID,
+				HtlcID: htlc.HtlcIndex,
+			}] = struct{}{}
+		}
+	}
+
+	for _, f := range forwards {
+		if _, ok := incoming[f.IncomingCircuit]; ok {
+			store.forwards[f.IncomingCircuit] = f
+			continue
+		}
+
+		log.Debugf("Removing resolved held forward %v",
+			f.IncomingCircuit)
+
+		if err := store.remove(f.IncomingCircuit); err != nil {
+			return nil, err
+		}
+	}
+
+	log.Infof("Loaded %v held forwards", len(store.forwards))
+
+	return store, nil
+}
+
+// isHeld returns true if the htlc with the given incoming circuit is held.
+func (s *heldForwardStore) isHeld(key channeldb.CircuitKey) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.forwards[key]
+	return ok
+}
+
+// empty returns true if no htlcs are held.
+func (s *heldForwardStore) empty() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.forwards) == 0
+}
+
+// hold persists the intercepted htlc as held. If the htlc is held already,
+// the time at which it was first held is kept.
+func (s *heldForwardStore) hold(packet InterceptedPacket) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.forwards[packet.IncomingCircuit]; ok {
+		return nil
+	}
+
+	f := &HeldForward{
+		IncomingCircuit: packet.IncomingCircuit,
+		OutgoingChanID:  packet.OutgoingChanID,
+		Hash:            packet.Hash,
+		OutgoingExpiry:  packet.OutgoingExpiry,
+		OutgoingAmount:  packet.OutgoingAmount,
+		IncomingExpiry:  packet.IncomingExpiry,
+		IncomingAmount:  packet.IncomingAmount,
+		HeldSince:       s.clock.Now(),
+	}
+
+	var b bytes.Buffer
+	if err := serializeHeldForward(&b, f); err != nil {
+		return err
+	}
+
+	err := kvdb.Batch(s.backend, func(tx kvdb.RwTx) error {
+		bucket, err := tx.CreateTopLevelBucket(heldForwardsBucketKey)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(f.IncomingCircuit.Bytes(), b.Bytes())
+	})
+	if err != nil {
+		return err
+	}
+
+	s.forwards[f.IncomingCircuit] = f
+
+	return nil
+}
+
+// release removes the held htlc with the given incoming circuit, as it was
+// resolved.
+func (s *heldForwardStore) release(key channeldb.CircuitKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.forwards[key]; !ok {
+		return nil
+	}
+
+	if err := s.remove(key); err != nil {
+		return err
+	}
+
+	delete(s.forwards, key)
+
+	return nil
+}
+
+// remove deletes the held htlc with the given incoming circuit from the
+// database.
+func (s *heldForwardStore) remove(key channeldb.CircuitKey) error {
+	return kvdb.Batch(s.backend, func(tx kvdb.RwTx) error {
+		bucket, err := tx.CreateTopLevelBucket(heldForwardsBucketKey)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Delete(key.Bytes())
+	})
+}
+
+// list returns all held htlcs, ordered by the time at which they were first
+// held.
+func (s *heldForwardStore) list() []HeldForward {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	forwards := make([]HeldForward, 0, len(s.forwards))
+	for _, f := range s.forwards {
+		forwards = append(forwards, *f)
+	}
+
+	sort.Slice(forwards, func(i, j int) bool {
+		return forwards[i].HeldSince.Before(forwards[j].HeldSince)
+	})
+
+	return forwards
+}
+
+// persistedForward wraps an intercepted htlc that is persisted as held once it
+// ends up held, and releases it from the held forward store once it is
+// resolved.
+type persistedForward struct {
+	InterceptedForward
+
+	store *heldForwardStore
+
+	// mu guards persisted and done, such that an htlc that is resolved
+	// while it is being persisted is released from the store.
+	mu sync.Mutex
+
+	// persisted is true if the htlc is persisted as held.
+	persisted bool
+
+	// done is true if the htlc was resolved.
+	done bool
+}
+
+// persist persists the htlc as held, unless it is persisted already or was
+// resolved in the meantime.
+func (f *persistedForward) persist() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.persisted || f.done {
+		return nil
+	}
+
+	if err := f.store.hold(f.Packet()); err != nil {
+		return err
+	}
+	f.persisted = true
+
+	return nil
+}
+
+// Resume resumes the default behavior of the htlc.
+func (f *persistedForward) Resume() error {
+	return f.resolved(f.InterceptedForward.Resume())
+}
+
+// Settle settles the htlc with the given preimage.
+func (f *persistedForward) Settle(preimage lntypes.Preimage) error {
+	return f.resolved(f.InterceptedForward.Settle(preimage))
+}
+
+// Fail fails the htlc with the default failure.
+func (f *persistedForward) Fail() error {
+	return f.resolved(f.InterceptedForward.Fail())
+}
+
+// FailWithMessage fails the htlc with the given failure message.
+func (f *persistedForward) FailWithMessage(
+	failure lnwire.FailureMessage) error {
+
+	return f.resolved(f.InterceptedForward.FailWithMessage(failure))
+}
+
+// FailWithReason fails the htlc with the given encrypted failure reason.
+func (f *persistedForward) FailWithReason(reason lnwire.OpaqueReason) error {
+	return f.resolved(f.InterceptedForward.FailWithReason(reason))
+}
+
+// resolved releases the htlc from the held forward store if it was resolved
+// without an error. If the resolution failed, the htlc remains held, such
+// that it is held again once the link replays it.
+func (f *persistedForward) resolved(err error) error {
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.done = true
+	if !f.persisted {
+		return nil
+	}
+
+	key := f.Packet().IncomingCircuit
+	if err := f.store.release(key); err != nil {
+		log.Errorf("Unable to release held forward %v: %v", key, err)
+	}
+
+	return nil
+}
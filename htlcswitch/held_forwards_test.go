@@ -0,0 +1,188 @@
+package htlcswitch
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/brronsuite/broln/channeldb"
+	"github.com/brronsuite/broln/clock"
+	"github.com/brronsuite/broln/lntypes"
+	"github.com/brronsuite/broln/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestHeldForwardStore tests that held forwards are persisted until they are
+// released, that the time at which they were first held is kept, and that
+// held forwards that were resolved while we were down are removed on startup.
+func TestHeldForwardStore(t *testing.T) {
+	t.Parallel()
+
+	tempPath, err := ioutil.TempDir("", "heldforwards")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempPath)
+
+	cdb, err := channeldb.Open(tempPath)
+	require.NoError(t, err)
+	defer cdb.Close()
+
+	startTime := time.Unix(1000, 0)
+	testClock := clock.NewTestClock(startTime)
+
+	chanID := lnwire.NewShortChanIDFromInt(1)
+	newPacket := func(htlcID uint64) InterceptedPacket {
+		return InterceptedPacket{
+			IncomingCircuit: channeldb.CircuitKey{
+				ChanID: chanID,
+				HtlcID: htlcID,
+			},
+			OutgoingChanID: lnwire.NewShortChanIDFromInt(2),
+			Hash:           [32]byte{byte(htlcID)},
+			OutgoingExpiry: 100,
+			OutgoingAmount: 1000,
+			IncomingExpiry: 140,
+			IncomingAmount: 1100,
+		}
+	}
+
+	// Only the first htlc is still part of the channel when we restart.
+	openChannel := &channeldb.OpenChannel{
+		ShortChannelID: chanID,
+		LocalCommitment: channeldb.ChannelCommitment{
+			Htlcs: []channeldb.HTLC{{
+				Incoming:  true,
+				HtlcIndex: 0,
+			}},
+		},
+	}
+	fetchAllOpenChannels := func() ([]*channeldb.OpenChannel, error) {
+		return []*channeldb.OpenChannel{openChannel}, nil
+	}
+
+	store, err := newHeldForwardStore(cdb, testClock, fetchAllOpenChannels)
+	require.NoError(t, err)
+	require.True(t, store.empty())
+
+	for i := uint64(0); i < 3; i++ {
+		require.NoError(t, store.hold(newPacket(i)))
+	}
+
+	// Holding an htlc again must keep the time at which it was first held.
+	testClock.SetTime(startTime.Add(time.Minute))
+	require.NoError(t, store.hold(newPacket(0)))
+
+	require.NoError(t, store.release(newPacket(2).IncomingCircuit))
+	require.False(t, store.isHeld(newPacket(2).IncomingCircuit))
+	require.Len(t, store.list(), 2)
+
+	// After a restart, the second htlc isn't part of the channel anymore,
+	// so only the first htlc must be held.
+	store, err = newHeldForwardStore(cdb, testClock, fetchAllOpenChannels)
+	require.NoError(t, err)
+
+	packet := newPacket(0)
+	require.Equal(t, []HeldForward{{
+		IncomingCircuit: packet.IncomingCircuit,
+		OutgoingChanID:  packet.OutgoingChanID,
+		Hash:            packet.Hash,
+		OutgoingExpiry:  packet.OutgoingExpiry,
+		OutgoingAmount:  packet.OutgoingAmount,
+		IncomingExpiry:  packet.IncomingExpiry,
+		IncomingAmount:  packet.IncomingAmount,
+		HeldSince:       startTime,
+	}}, store.list())
+
+	// Releasing the htlc must remove it from the database as well.
+	require.NoError(t, store.release(packet.IncomingCircuit))
+
+	store, err = newHeldForwardStore(cdb, testClock, fetchAllOpenChannels)
+	require.NoError(t, err)
+	require.True(t, store.empty())
+}
+
+// stubForward is an intercepted htlc whose resolutions always succeed.
+type stubForward struct {
+	packet InterceptedPacket
+}
+
+func (f *stubForward) Packet() InterceptedPacket {
+	return f.packet
+}
+
+func (f *stubForward) Resume() error {
+	return nil
+}
+
+func (f *stubForward) Settle(lntypes.Preimage) error {
+	return nil
+}
+
+func (f *stubForward) Fail() error {
+	return nil
+}
+
+func (f *stubForward) FailWithMessage(lnwire.FailureMessage) error {
+	return nil
+}
+
+func (f *stubForward) FailWithReason(lnwire.OpaqueReason) error {
+	return nil
+}
+
+// TestInterceptPersistsHeldForwards tests that intercepted htlcs are only
+// persisted as held if they end up held, and that they are released once they
+// are resolved.
+func TestInterceptPersistsHeldForwards(t *testing.T) {
+	t.Parallel()
+
+	s, err := initSwitchWithDB(testStartingHeight, nil)
+	require.NoError(t, err)
+
+	interceptableSwitch := NewInterceptableSwitch(
+		&InterceptableSwitchConfig{
+			Switch: s,
+		},
+	)
+
+	newForward := func(htlcID uint64) *stubForward {
+		return &stubForward{
+			packet: InterceptedPacket{
+				IncomingCircuit: channeldb.CircuitKey{
+					ChanID: lnwire.NewShortChanIDFromInt(1),
+					HtlcID: htlcID,
+				},
+			},
+		}
+	}
+
+	// An htlc that the interceptor declines is resumed without being
+	// persisted.
+	interceptableSwitch.SetInterceptor(func(InterceptedForward) bool {
+		return false
+	})
+	require.False(t, interceptableSwitch.InterceptExitHop(newForward(0)))
+	require.Empty(t, interceptableSwitch.HeldForwards())
+
+	// An htlc that the interceptor resolves right away isn't persisted
+	// either.
+	interceptableSwitch.SetInterceptor(func(fwd InterceptedForward) bool {
+		require.NoError(t, fwd.Resume())
+		return true
+	})
+	require.True(t, interceptableSwitch.InterceptExitHop(newForward(1)))
+	require.Empty(t, interceptableSwitch.HeldForwards())
+
+	// An htlc that the interceptor holds is persisted until it is
+	// resolved.
+	var held InterceptedForward
+	interceptableSwitch.SetInterceptor(func(fwd InterceptedForward) bool {
+		held = fwd
+		return true
+	})
+	require.True(t, interceptableSwitch.InterceptExitHop(newForward(2)))
+	require.Len(t, interceptableSwitch.HeldForwards(), 1)
+
+	require.NoError(t, held.Resume())
+	require.Empty(t, interceptableSwitch.HeldForwards())
+}
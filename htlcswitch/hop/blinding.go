@@ -0,0 +1,223 @@
+package hop
+
+import (
+	"fmt"
+
+	"github.com/brronsuite/broln/blinding"
+	"github.com/brronsuite/broln/keychain"
+	"github.com/brronsuite/broln/lnwire"
+	"github.com/brronsuite/broln/record"
+	"github.com/brronsuite/brond/bronec"
+)
+
+// ErrInvalidBlinding is returned when the encrypted data of a blinded hop
+// can't be processed, or the HTLC doesn't satisfy the constraints of the
+// blinded route.
+type ErrInvalidBlinding struct {
+	// Reason describes why the blinded hop is invalid.
+	Reason string
+}
+
+// newInvalidBlinding creates an ErrInvalidBlinding with a formatted reason.
+func newInvalidBlinding(format string, a ...interface{}) ErrInvalidBlinding {
+	return ErrInvalidBlinding{Reason: fmt.Sprintf(format, a...)}
+}
+
+// Error returns a human-readable description of the invalid blinding error.
+func (e ErrInvalidBlinding) Error() string {
+	return fmt.Sprintf("invalid blinded hop: %v", e.Reason)
+}
+
+// BlindingKit contains the information that is needed to process the
+// encrypted recipient data of an HTLC that is routed through a blinded route.
+type BlindingKit struct {
+	// NodeKey is the identity key of our node that the encrypted data is
+	// encrypted to.
+	NodeKey keychain.SingleKeyECDH
+
+	// UpdateAddBlinding is the blinding point that was received in the
+	// update_add_htlc message. It is only set if we aren't the
+	// introduction node of the blinded route.
+	UpdateAddBlinding *bronec.PublicKey
+
+	// IncomingAmount is the amount of the incoming HTLC.
+	IncomingAmount lnwire.MilliBronees
+
+	// IncomingCltv is the expiry of the incoming HTLC.
+	IncomingCltv uint32
+}
+
+// DecryptAndValidateFwdInfo decrypts the encrypted recipient data of the
+// payload and populates the forwarding info of the payload from it. As the
+// sender doesn't know the parameters of the blinded hops, the amount and
+// expiry of the outgoing HTLC are derived from the incoming HTLC.
+func (b *BlindingKit) DecryptAndValidateFwdInfo(payload *Payload) error {
+	// Exactly one of the introduction node's payload and the
+	// update_add_htlc message must carry the blinding point.
+	var blindingPoint *bronec.PublicKey
+	switch {
+	case payload.BlindingPoint != nil && b.UpdateAddBlinding != nil:
+		return newInvalidBlinding("blinding point in payload and " +
+			"update_add_htlc")
+
+	case payload.BlindingPoint != nil:
+		blindingPoint = payload.BlindingPoint
+
+	case b.UpdateAddBlinding != nil:
+		blindingPoint = b.UpdateAddBlinding
+
+	default:
+		return newInvalidBlinding("no blinding point")
+	}
+
+	plainText, err := blinding.DecryptHopData(
+		b.NodeKey, blindingPoint, payload.EncryptedData,
+	)
+	if err != nil {
+		return newInvalidBlinding("%v", err)
+	}
+
+	data, err := blinding.DecodeRouteData(plainText)
+	if err != nil {
+		return newInvalidBlinding("%v", err)
+	}
+
+	// We don't support any features within blinded routes yet, so the
+	// recipient must not allow the HTLC to use any.
+	for _, features := range data.AllowedFeatures {
+		if features != 0 {
+			return newInvalidBlinding("unsupported allowed " +
+				"features")
+		}
+	}
+
+	if c := data.PaymentConstraints; c != nil {
+		if b.IncomingCltv > c.MaxCltvExpiry {
+			return newInvalidBlinding("expiry %v exceeds "+
+				"maximum %v", b.IncomingCltv, c.MaxCltvExpiry)
+		}
+
+		if b.IncomingAmount < c.HtlcMinimumMsat {
+			return newInvalidBlinding("amount %v below "+
+				"minimum %v", b.IncomingAmount,
+				c.HtlcMinimumMsat)
+		}
+	}
+
+	// Only the final node of the route doesn't have an outgoing channel.
+	if data.ShortChannelID == nil {
+		return b.validateFinalHop(payload, data)
+	}
+
+	return b.validateForwardingHop(payload, data, blindingPoint)
+}
+
+// validateFinalHop populates the payload of the final node of a blinded route.
+// The path id we put in the route is the payment address of the invoice,
+// which is passed on as an MPP record together with the total amount.
+func (b *BlindingKit) validateFinalHop(payload *Payload,
+	data *blinding.RouteData) error {
+
+	var paymentAddr [32]byte
+	if len(data.PathID) != len(paymentAddr) {
+		return newInvalidBlinding("invalid path id length %v",
+			len(data.PathID))
+	}
+	copy(paymentAddr[:], data.PathID)
+
+	// The sender must tell us the amount and expiry it intended to pay,
+	// and the total amount of the payment.
+	switch {
+	case payload.FwdInfo.AmountToForward == 0:
+		return ErrInvalidPayload{
+			Type:      record.AmtOnionType,
+			Violation: OmittedViolation,
+			FinalHop:  true,
+		}
+
+	case payload.FwdInfo.OutgoingCTLV == 0:
+		return ErrInvalidPayload{
+			Type:      record.LockTimeOnionType,
+			Violation: OmittedViolation,
+			FinalHop:  true,
+		}
+
+	case payload.TotalAmtMsat == 0:
+		return ErrInvalidPayload{
+			Type:      record.TotalAmtMsatBlindedType,
+			Violation: OmittedViolation,
+			FinalHop:  true,
+		}
+	}
+
+	payload.FwdInfo.NextHop = Exit
+	payload.MPP = record.NewMPP(payload.TotalAmtMsat, paymentAddr)
+
+	return nil
+}
+
+// validateForwardingHop populates the forwarding info of an intermediate node
+// of a blinded route from the payment relay parameters that the recipient
+// put in the route.
+func (b *BlindingKit) validateForwardingHop(payload *Payload,
+	data *blinding.RouteData, blindingPoint *bronec.PublicKey) error {
+
+	// The sender doesn't know the amount and expiry of the intermediate
+	// hops, so it must not include them.
+	switch {
+	case payload.FwdInfo.AmountToForward != 0:
+		return ErrInvalidPayload{
+			Type:      record.AmtOnionType,
+			Violation: IncludedViolation,
+		}
+
+	case payload.FwdInfo.OutgoingCTLV != 0:
+		return ErrInvalidPayload{
+			Type:      record.LockTimeOnionType,
+			Violation: IncludedViolation,
+		}
+
+	case payload.TotalAmtMsat != 0:
+		return ErrInvalidPayload{
+			Type:      record.TotalAmtMsatBlindedType,
+			Violation: IncludedViolation,
+		}
+	}
+
+	relay := data.PaymentRelay
+	if relay == nil {
+		return newInvalidBlinding("no payment relay")
+	}
+
+	amt, ok := relay.ForwardAmount(b.IncomingAmount)
+	if !ok {
+		return newInvalidBlinding("amount %v below base fee %v",
+			b.IncomingAmount, relay.FeeBaseMsat)
+	}
+
+	if b.IncomingCltv < uint32(relay.CltvExpiryDelta) {
+		return newInvalidBlinding("expiry %v below cltv delta %v",
+			b.IncomingCltv, relay.CltvExpiryDelta)
+	}
+
+	nextBlinding := data.NextBlindingOverride
+	if nextBlinding == nil {
+		var err error
+		nextBlinding, err = blinding.NextBlindingPoint(
+			b.NodeKey, blindingPoint,
+		)
+		if err != nil {
+			return newInvalidBlinding("%v", err)
+		}
+	}
+
+	payload.FwdInfo = ForwardingInfo{
+		Network:         BrocoinNetwork,
+		NextHop:         *data.ShortChannelID,
+		AmountToForward: amt,
+		OutgoingCTLV:    b.IncomingCltv - uint32(relay.CltvExpiryDelta),
+		NextBlinding:    nextBlinding,
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package hop
+
+import (
+	"testing"
+
+	"github.com/brronsuite/broln/blinding"
+	"github.com/brronsuite/broln/keychain"
+	"github.com/brronsuite/broln/lnwire"
+	"github.com/brronsuite/brond/bronec"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBlindingKitRoute asserts that an HTLC can be forwarded through a blinded
+// route by the introduction node and received by the final node, using the
+// relay parameters and constraints that the recipient put in the route.
+func TestBlindingKitRoute(t *testing.T) {
+	t.Parallel()
+
+	introKey, err := bronec.NewPrivateKey(bronec.S256())
+	require.NoError(t, err)
+
+	finalKey, err := bronec.NewPrivateKey(bronec.S256())
+	require.NoError(t, err)
+
+	sessionKey, err := bronec.NewPrivateKey(bronec.S256())
+	require.NoError(t, err)
+
+	var paymentAddr [32]byte
+	paymentAddr[0] = 1
+
+	scid := lnwire.NewShortChanIDFromInt(1234)
+	introPlainText, err := blinding.EncodeRouteData(&blinding.RouteData{
+		ShortChannelID: &scid,
+		PaymentRelay: &blinding.PaymentRelay{
+			CltvExpiryDelta:           40,
+			FeeProportionalMillionths: 1000,
+			FeeBaseMsat:               1000,
+		},
+		PaymentConstraints: &blinding.PaymentConstraints{
+			MaxCltvExpiry:   1000,
+			HtlcMinimumMsat: 1000,
+		},
+	})
+	require.NoError(t, err)
+
+	finalPlainText, err := blinding.EncodeRouteData(&blinding.RouteData{
+		PathID: paymentAddr[:],
+		PaymentConstraints: &blinding.PaymentConstraints{
+			MaxCltvExpiry:   960,
+			HtlcMinimumMsat: 1000,
+		},
+	})
+	require.NoError(t, err)
+
+	path, err := blinding.BuildBlindedPath(sessionKey, []*blinding.HopInfo{
+		{
+			NodePub:   introKey.PubKey(),
+			PlainText: introPlainText,
+		},
+		{
+			NodePub:   finalKey.PubKey(),
+			PlainText: finalPlainText,
+		},
+	})
+	require.NoError(t, err)
+
+	// The introduction node receives the blinding point in its payload and
+	// derives the outgoing HTLC from the incoming one.
+	introKit := &BlindingKit{
+		NodeKey:        &keychain.PrivKeyECDH{PrivKey: introKey},
+		IncomingAmount: 101_100,
+		IncomingCltv:   900,
+	}
+	introPayload := &Payload{
+		EncryptedData: path.BlindedHops[0].CipherText,
+		BlindingPoint: path.BlindingPoint,
+	}
+	require.NoError(t, introKit.DecryptAndValidateFwdInfo(introPayload))
+
+	fwdInfo := introPayload.FwdInfo
+	require.Equal(t, scid, fwdInfo.NextHop)
+	require.Equal(t, lnwire.MilliBronees(100_000), fwdInfo.AmountToForward)
+	require.Equal(t, uint32(860), fwdInfo.OutgoingCTLV)
+	require.NotNil(t, fwdInfo.NextBlinding)
+
+	// The final node receives the blinding point in update_add_htlc and
+	// the amounts from the sender.
+	finalKit := &BlindingKit{
+		NodeKey:           &keychain.PrivKeyECDH{PrivKey: finalKey},
+		UpdateAddBlinding: fwdInfo.NextBlinding,
+		IncomingAmount:    fwdInfo.AmountToForward,
+		IncomingCltv:      fwdInfo.OutgoingCTLV,
+	}
+	finalPayload := &Payload{
+		FwdInfo: ForwardingInfo{
+			AmountToForward: fwdInfo.AmountToForward,
+			OutgoingCTLV:    fwdInfo.OutgoingCTLV,
+		},
+		EncryptedData: path.BlindedHops[1].CipherText,
+		TotalAmtMsat:  fwdInfo.AmountToForward,
+	}
+	require.NoError(t, finalKit.DecryptAndValidateFwdInfo(finalPayload))
+
+	require.Equal(t, Exit, finalPayload.FwdInfo.NextHop)
+	require.NotNil(t, finalPayload.MPP)
+	require.Equal(t, paymentAddr, finalPayload.MPP.PaymentAddr())
+	require.Equal(t, fwdInfo.AmountToForward, finalPayload.MPP.TotalMsat())
+
+	// An HTLC that expires after the constraint of the final node must be
+	// rejected.
+	finalKit.IncomingCltv = 961
+	finalPayload.FwdInfo.OutgoingCTLV = 961
+	err = finalKit.DecryptAndValidateFwdInfo(finalPayload)
+	require.IsType(t, ErrInvalidBlinding{}, err)
+
+	// The final node must reject the blinding point in both the payload and
+	// update_add_htlc.
+	finalKit.IncomingCltv = 860
+	finalPayload.BlindingPoint = fwdInfo.NextBlinding
+	err = finalKit.DecryptAndValidateFwdInfo(finalPayload)
+	require.IsType(t, ErrInvalidBlinding{}, err)
+}
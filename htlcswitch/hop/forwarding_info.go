@@ -2,6 +2,7 @@ package hop
 
 import (
 	"github.com/brronsuite/broln/lnwire"
+	"github.com/brronsuite/brond/bronec"
 )
 
 // ForwardingInfo contains all the information that is necessary to forward and
@@ -26,4 +27,8 @@ type ForwardingInfo struct {
 	// OutgoingCTLV is the specified value of the CTLV timelock to be used
 	// in the outgoing HTLC.
 	OutgoingCTLV uint32
+
+	// NextBlinding is the blinding point that must be passed to the next
+	// hop if the HTLC is forwarded within a blinded route.
+	NextBlinding *bronec.PublicKey
 }
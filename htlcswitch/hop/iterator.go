@@ -2,10 +2,14 @@ package hop
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
 	"fmt"
 	"io"
 	"sync"
 
+	"github.com/brronsuite/broln/blinding"
+	"github.com/brronsuite/broln/keychain"
 	"github.com/brronsuite/broln/lnwire"
 	"github.com/brronsuite/brond/bronec"
 	sphinx "github.com/brronsuite/lightning-onion"
@@ -47,16 +51,28 @@ type sphinxHopIterator struct {
 	// includes the information required to properly forward the packet to
 	// the next hop.
 	processedPacket *sphinx.ProcessedPacket
+
+	// blindingKit is used to process the encrypted recipient data if the
+	// HTLC is routed through a blinded route.
+	blindingKit *BlindingKit
+
+	// router is the sphinx router that processed the packet, which is a
+	// router using our blinded node key if the HTLC was received with a
+	// blinding point.
+	router *sphinx.Router
 }
 
 // makeSphinxHopIterator converts a processed packet returned from a sphinx
 // router and converts it into an hop iterator for usage in the link.
 func makeSphinxHopIterator(ogPacket *sphinx.OnionPacket,
-	packet *sphinx.ProcessedPacket) *sphinxHopIterator {
+	packet *sphinx.ProcessedPacket, blindingKit *BlindingKit,
+	router *sphinx.Router) *sphinxHopIterator {
 
 	return &sphinxHopIterator{
 		ogPacket:        ogPacket,
 		processedPacket: packet,
+		blindingKit:     blindingKit,
+		router:          router,
 	}
 }
 
@@ -90,9 +106,30 @@ func (r *sphinxHopIterator) HopPayload() (*Payload, error) {
 	// Otherwise, if this is the TLV payload, then we'll make a new stream
 	// to decode only what we need to make routing decisions.
 	case sphinx.PayloadTLV:
-		return NewPayloadFromReader(bytes.NewReader(
+		payload, err := NewPayloadFromReader(bytes.NewReader(
 			r.processedPacket.Payload.Payload,
 		))
+		if err != nil {
+			return nil, err
+		}
+
+		// The forwarding info of a blinded hop is part of its
+		// encrypted data.
+		if payload.EncryptedData == nil {
+			return payload, nil
+		}
+
+		if r.blindingKit == nil {
+			return nil, newInvalidBlinding("unable to process " +
+				"encrypted data")
+		}
+
+		err = r.blindingKit.DecryptAndValidateFwdInfo(payload)
+		if err != nil {
+			return nil, err
+		}
+
+		return payload, nil
 
 	default:
 		return nil, fmt.Errorf("unknown sphinx payload type: %v",
@@ -109,6 +146,14 @@ func (r *sphinxHopIterator) HopPayload() (*Payload, error) {
 func (r *sphinxHopIterator) ExtractErrorEncrypter(
 	extracter ErrorEncrypterExtracter) (ErrorEncrypter, lnwire.FailCode) {
 
+	// The shared secret of a packet that was processed with our blinded
+	// node key can only be derived by the same router.
+	if r.blindingKit != nil && r.blindingKit.UpdateAddBlinding != nil {
+		return newSphinxErrorEncrypter(
+			r.router, r.ogPacket.EphemeralKey,
+		)
+	}
+
 	return extracter(r.ogPacket.EphemeralKey)
 }
 
@@ -123,11 +168,30 @@ func (r *sphinxHopIterator) ExtractErrorEncrypter(
 // tests dependent from the sphinx internal parts.
 type OnionProcessor struct {
 	router *sphinx.Router
+
+	// nodeKey is the identity key of our node, which is used to process
+	// the encrypted data of blinded routes.
+	nodeKey keychain.SingleKeyECDH
+
+	// newBlindedRouter creates a sphinx router that processes packets
+	// with the given blinded node key.
+	newBlindedRouter BlindedRouterFactory
 }
 
+// BlindedRouterFactory creates a sphinx router that processes onion packets
+// with the given blinded node key. The returned router must share the replay
+// log of the main router, and doesn't need to be started.
+type BlindedRouterFactory func(blindedKey keychain.SingleKeyECDH) *sphinx.Router
+
 // NewOnionProcessor creates new instance of decoder.
-func NewOnionProcessor(router *sphinx.Router) *OnionProcessor {
-	return &OnionProcessor{router}
+func NewOnionProcessor(router *sphinx.Router, nodeKey keychain.SingleKeyECDH,
+	newBlindedRouter BlindedRouterFactory) *OnionProcessor {
+
+	return &OnionProcessor{
+		router:           router,
+		nodeKey:          nodeKey,
+		newBlindedRouter: newBlindedRouter,
+	}
 }
 
 // Start spins up the onion processor's sphinx router.
@@ -146,12 +210,29 @@ func (p *OnionProcessor) Stop() error {
 
 // DecodeHopIterator attempts to decode a valid sphinx packet from the passed io.Reader
 // instance using the rHash as the associated data when checking the relevant
-// MACs during the decoding process.
+// MACs during the decoding process. If the HTLC was received with a blinding
+// point, the packet is processed with our blinded node key.
 func (p *OnionProcessor) DecodeHopIterator(r io.Reader, rHash []byte,
-	incomingCltv uint32) (Iterator, lnwire.FailCode) {
+	incomingCltv uint32, incomingAmount lnwire.MilliBronees,
+	blindingPoint *bronec.PublicKey) (Iterator, lnwire.FailCode) {
+
+	router, err := p.blindedRouter(blindingPoint)
+	if err != nil {
+		log.Errorf("unable to derive blinded node key: %v", err)
+		return nil, lnwire.CodeInvalidOnionBlinding
+	}
 
 	onionPkt := &sphinx.OnionPacket{}
 	if err := onionPkt.Decode(r); err != nil {
+		// As we aren't the introduction node of a blinded route, any
+		// failure is reported as an invalid blinding to not reveal our
+		// position in the route.
+		if blindingPoint != nil {
+			log.Errorf("unable to decode blinded onion packet: %v",
+				err)
+			return nil, lnwire.CodeInvalidOnionBlinding
+		}
+
 		switch err {
 		case sphinx.ErrInvalidOnionVersion:
 			return nil, lnwire.CodeInvalidOnionVersion
@@ -168,10 +249,16 @@ func (p *OnionProcessor) DecodeHopIterator(r io.Reader, rHash []byte,
 	// associated data in order to thwart attempts a replay attacks. In the
 	// case of a replay, an attacker is *forced* to use the same payment
 	// hash twice, thereby losing their money entirely.
-	sphinxPacket, err := p.router.ProcessOnionPacket(
+	sphinxPacket, err := router.ProcessOnionPacket(
 		onionPkt, rHash, incomingCltv,
 	)
 	if err != nil {
+		if blindingPoint != nil {
+			log.Errorf("unable to process blinded onion packet: %v",
+				err)
+			return nil, lnwire.CodeInvalidOnionBlinding
+		}
+
 		switch err {
 		case sphinx.ErrInvalidOnionVersion:
 			return nil, lnwire.CodeInvalidOnionVersion
@@ -185,14 +272,43 @@ func (p *OnionProcessor) DecodeHopIterator(r io.Reader, rHash []byte,
 		}
 	}
 
-	return makeSphinxHopIterator(onionPkt, sphinxPacket), lnwire.CodeNone
+	blindingKit := &BlindingKit{
+		NodeKey:           p.nodeKey,
+		UpdateAddBlinding: blindingPoint,
+		IncomingAmount:    incomingAmount,
+		IncomingCltv:      incomingCltv,
+	}
+
+	return makeSphinxHopIterator(
+		onionPkt, sphinxPacket, blindingKit, router,
+	), lnwire.CodeNone
+}
+
+// ReconstructBlindingInfo contains the information about an HTLC that is
+// needed to reconstruct its hop iterator if the HTLC is routed through a
+// blinded route.
+type ReconstructBlindingInfo struct {
+	// BlindingPoint is the blinding point that was received together
+	// with the HTLC, if any.
+	BlindingPoint *bronec.PublicKey
+
+	// IncomingAmount is the amount of the HTLC.
+	IncomingAmount lnwire.MilliBronees
+
+	// IncomingCltv is the expiry of the HTLC.
+	IncomingCltv uint32
 }
 
 // ReconstructHopIterator attempts to decode a valid sphinx packet from the passed io.Reader
 // instance using the rHash as the associated data when checking the relevant
 // MACs during the decoding process.
-func (p *OnionProcessor) ReconstructHopIterator(r io.Reader, rHash []byte) (
-	Iterator, error) {
+func (p *OnionProcessor) ReconstructHopIterator(r io.Reader, rHash []byte,
+	blindingInfo ReconstructBlindingInfo) (Iterator, error) {
+
+	router, err := p.blindedRouter(blindingInfo.BlindingPoint)
+	if err != nil {
+		return nil, err
+	}
 
 	onionPkt := &sphinx.OnionPacket{}
 	if err := onionPkt.Decode(r); err != nil {
@@ -204,21 +320,54 @@ func (p *OnionProcessor) ReconstructHopIterator(r io.Reader, rHash []byte) (
 	// associated data in order to thwart attempts a replay attacks. In the
 	// case of a replay, an attacker is *forced* to use the same payment
 	// hash twice, thereby losing their money entirely.
-	sphinxPacket, err := p.router.ReconstructOnionPacket(onionPkt, rHash)
+	sphinxPacket, err := router.ReconstructOnionPacket(onionPkt, rHash)
+	if err != nil {
+		return nil, err
+	}
+
+	blindingKit := &BlindingKit{
+		NodeKey:           p.nodeKey,
+		UpdateAddBlinding: blindingInfo.BlindingPoint,
+		IncomingAmount:    blindingInfo.IncomingAmount,
+		IncomingCltv:      blindingInfo.IncomingCltv,
+	}
+
+	return makeSphinxHopIterator(
+		onionPkt, sphinxPacket, blindingKit, router,
+	), nil
+}
+
+// blindedRouter returns the sphinx router that processes the onion packets of
+// HTLCs that were received with the given blinding point. These are encrypted
+// to our blinded node key, while packets of HTLCs without a blinding point are
+// processed by our main router.
+func (p *OnionProcessor) blindedRouter(
+	blindingPoint *bronec.PublicKey) (*sphinx.Router, error) {
+
+	if blindingPoint == nil {
+		return p.router, nil
+	}
+
+	blindedKey, err := blinding.NewBlindedECDH(p.nodeKey, blindingPoint)
 	if err != nil {
 		return nil, err
 	}
 
-	return makeSphinxHopIterator(onionPkt, sphinxPacket), nil
+	return p.newBlindedRouter(blindedKey), nil
 }
 
 // DecodeHopIteratorRequest encapsulates all date necessary to process an onion
 // packet, perform sphinx replay detection, and schedule the entry for garbage
 // collection.
 type DecodeHopIteratorRequest struct {
-	OnionReader  io.Reader
-	RHash        []byte
-	IncomingCltv uint32
+	OnionReader    io.Reader
+	RHash          []byte
+	IncomingCltv   uint32
+	IncomingAmount lnwire.MilliBronees
+
+	// BlindingPoint is the blinding point that was received together
+	// with the HTLC, if it is routed through a blinded route.
+	BlindingPoint *bronec.PublicKey
 }
 
 // DecodeHopIteratorResponse encapsulates the outcome of a batched sphinx onion
@@ -306,6 +455,16 @@ func (p *OnionProcessor) DecodeHopIterators(id []byte,
 
 			onionPkt := &onionPkts[seqNum]
 
+			// HTLCs that are routed through a blinded route are
+			// processed with our blinded node key in a batch of
+			// their own.
+			if reqs[seqNum].BlindingPoint != nil {
+				resps[seqNum] = p.decodeBlindedHopIterator(
+					id, seqNum, onionPkt, reqs[seqNum],
+				)
+				return
+			}
+
 			resps[seqNum].FailCode = decode(
 				seqNum, onionPkt, reqs[seqNum],
 			)
@@ -350,8 +509,11 @@ func (p *OnionProcessor) DecodeHopIterators(id []byte,
 	for i := range resps {
 		resp := &resps[i]
 
-		// Skip any indexes that already failed onion decoding.
-		if resp.FailCode != lnwire.CodeNone {
+		// Skip any indexes that already failed onion decoding, or were
+		// processed in a blinded batch.
+		if resp.FailCode != lnwire.CodeNone ||
+			reqs[i].BlindingPoint != nil {
+
 			continue
 		}
 
@@ -368,12 +530,87 @@ func (p *OnionProcessor) DecodeHopIterators(id []byte,
 
 		// Finally, construct a hop iterator from our processed sphinx
 		// packet, simultaneously caching the original onion packet.
-		resp.HopIterator = makeSphinxHopIterator(&onionPkts[i], &packets[i])
+		blindingKit := &BlindingKit{
+			NodeKey:        p.nodeKey,
+			IncomingAmount: reqs[i].IncomingAmount,
+			IncomingCltv:   reqs[i].IncomingCltv,
+		}
+		resp.HopIterator = makeSphinxHopIterator(
+			&onionPkts[i], &packets[i], blindingKit, p.router,
+		)
 	}
 
 	return resps, nil
 }
 
+// decodeBlindedHopIterator processes the onion packet of an HTLC that was
+// received with a blinding point. The packet is encrypted to our blinded node
+// key, so it is processed by a router using that key in a batch of its own.
+// The id of the batch is derived from the id of the forwarding package and the
+// index of the HTLC, which keeps the replay detection deterministic across
+// invocations.
+//
+// NOTE: As we aren't the introduction node of the blinded route, any failure
+// is reported as an invalid blinding to not reveal our position in the route.
+func (p *OnionProcessor) decodeBlindedHopIterator(id []byte, seqNum uint16,
+	onionPkt *sphinx.OnionPacket,
+	req DecodeHopIteratorRequest) DecodeHopIteratorResponse {
+
+	failResp := DecodeHopIteratorResponse{
+		FailCode: lnwire.CodeInvalidOnionBlinding,
+	}
+
+	if err := onionPkt.Decode(req.OnionReader); err != nil {
+		log.Errorf("unable to decode blinded onion packet: %v", err)
+		return failResp
+	}
+
+	router, err := p.blindedRouter(req.BlindingPoint)
+	if err != nil {
+		log.Errorf("unable to derive blinded node key: %v", err)
+		return failResp
+	}
+
+	var batchID [sha256.Size]byte
+	h := sha256.New()
+	_, _ = h.Write(id)
+	_ = binary.Write(h, binary.BigEndian, seqNum)
+	copy(batchID[:], h.Sum(nil))
+
+	tx := router.BeginTxn(batchID[:], 1)
+	err = tx.ProcessOnionPacket(0, onionPkt, req.RHash, req.IncomingCltv)
+	if err != nil {
+		log.Errorf("unable to process blinded onion packet: %v", err)
+		return failResp
+	}
+
+	packets, replays, err := tx.Commit()
+	if err != nil {
+		log.Errorf("unable to process blinded onion packet %x-%v: %v",
+			id, seqNum, err)
+		return failResp
+	}
+	if replays.Contains(0) {
+		log.Errorf("unable to process blinded onion packet: %v",
+			sphinx.ErrReplayedPacket)
+		return failResp
+	}
+
+	blindingKit := &BlindingKit{
+		NodeKey:           p.nodeKey,
+		UpdateAddBlinding: req.BlindingPoint,
+		IncomingAmount:    req.IncomingAmount,
+		IncomingCltv:      req.IncomingCltv,
+	}
+
+	return DecodeHopIteratorResponse{
+		HopIterator: makeSphinxHopIterator(
+			onionPkt, &packets[0], blindingKit, router,
+		),
+		FailCode: lnwire.CodeNone,
+	}
+}
+
 // ExtractErrorEncrypter takes an io.Reader which should contain the onion
 // packet as original received by a forwarding node and creates an
 // ErrorEncrypter instance using the derived shared secret. In the case that en
@@ -382,8 +619,16 @@ func (p *OnionProcessor) DecodeHopIterators(id []byte,
 func (p *OnionProcessor) ExtractErrorEncrypter(ephemeralKey *bronec.PublicKey) (
 	ErrorEncrypter, lnwire.FailCode) {
 
+	return newSphinxErrorEncrypter(p.router, ephemeralKey)
+}
+
+// newSphinxErrorEncrypter creates an ErrorEncrypter using the shared secret
+// that the passed router derives from the ephemeral key.
+func newSphinxErrorEncrypter(router *sphinx.Router,
+	ephemeralKey *bronec.PublicKey) (ErrorEncrypter, lnwire.FailCode) {
+
 	onionObfuscator, err := sphinx.NewOnionErrorEncrypter(
-		p.router, ephemeralKey,
+		router, ephemeralKey,
 	)
 	if err != nil {
 		switch err {
@@ -8,6 +8,7 @@ import (
 	"github.com/brronsuite/broln/lnwire"
 	"github.com/brronsuite/broln/record"
 	"github.com/brronsuite/broln/tlv"
+	"github.com/brronsuite/brond/bronec"
 	sphinx "github.com/brronsuite/lightning-onion"
 )
 
@@ -90,6 +91,23 @@ type Payload struct {
 	// a TLV onion payload.
 	AMP *record.AMP
 
+	// EncryptedData is the encrypted recipient data of a blinded hop. If
+	// it is set, the forwarding info is only known after the data has been
+	// decrypted.
+	EncryptedData []byte
+
+	// BlindingPoint is the blinding point that is included in the payload
+	// of the introduction node of a blinded route.
+	BlindingPoint *bronec.PublicKey
+
+	// TotalAmtMsat is the total amount of a payment to a blinded route,
+	// which is only included in the payload of the final hop.
+	TotalAmtMsat lnwire.MilliBronees
+
+	// TrampolineOnion is the serialized trampoline onion that is included
+	// in the payload of the final hop of a payment to a trampoline node.
+	TrampolineOnion []byte
+
 	// customRecords are user-defined records in the custom type range that
 	// were included in the payload.
 	customRecords record.CustomSet
@@ -115,11 +133,15 @@ func NewLegacyPayload(f *sphinx.HopData) *Payload {
 // should correspond to the bytes encapsulated in a TLV onion payload.
 func NewPayloadFromReader(r io.Reader) (*Payload, error) {
 	var (
-		cid  uint64
-		amt  uint64
-		cltv uint32
-		mpp  = &record.MPP{}
-		amp  = &record.AMP{}
+		cid           uint64
+		amt           uint64
+		cltv          uint32
+		mpp           = &record.MPP{}
+		amp           = &record.AMP{}
+		encryptedData []byte
+		blindingPoint *bronec.PublicKey
+		totalAmt      uint64
+		trampoline    []byte
 	)
 
 	tlvStream, err := tlv.NewStream(
@@ -127,7 +149,11 @@ func NewPayloadFromReader(r io.Reader) (*Payload, error) {
 		record.NewLockTimeRecord(&cltv),
 		record.NewNextHopIDRecord(&cid),
 		mpp.Record(),
+		record.NewEncryptedDataRecord(&encryptedData),
+		record.NewBlindingPointRecord(&blindingPoint),
 		amp.Record(),
+		record.NewTotalAmtMsatBlindedRecord(&totalAmt),
+		record.NewTrampolineOnionRecord(&trampoline),
 	)
 	if err != nil {
 		return nil, err
@@ -139,9 +165,15 @@ func NewPayloadFromReader(r io.Reader) (*Payload, error) {
 	}
 
 	// Validate whether the sender properly included or omitted tlv records
-	// in accordance with BOLT 04.
+	// in accordance with BOLT 04. The payload of a blinded hop can only be
+	// fully validated once its encrypted data has been decrypted.
 	nextHop := lnwire.NewShortChanIDFromInt(cid)
-	err = ValidateParsedPayloadTypes(parsedTypes, nextHop)
+	_, isBlinded := parsedTypes[record.EncryptedDataOnionType]
+	if isBlinded {
+		err = ValidateBlindedPayloadTypes(parsedTypes)
+	} else {
+		err = ValidateParsedPayloadTypes(parsedTypes, nextHop)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -168,6 +200,12 @@ func NewPayloadFromReader(r io.Reader) (*Payload, error) {
 		amp = nil
 	}
 
+	// If no blinding point was parsed, make sure we don't return the zero
+	// value.
+	if _, ok := parsedTypes[record.BlindingPointOnionType]; !ok {
+		blindingPoint = nil
+	}
+
 	// Filter out the custom records.
 	customRecords := NewCustomRecords(parsedTypes)
 
@@ -178,9 +216,13 @@ func NewPayloadFromReader(r io.Reader) (*Payload, error) {
 			AmountToForward: lnwire.MilliBronees(amt),
 			OutgoingCTLV:    cltv,
 		},
-		MPP:           mpp,
-		AMP:           amp,
-		customRecords: customRecords,
+		MPP:             mpp,
+		AMP:             amp,
+		EncryptedData:   encryptedData,
+		BlindingPoint:   blindingPoint,
+		TotalAmtMsat:    lnwire.MilliBronees(totalAmt),
+		TrampolineOnion: trampoline,
+		customRecords:   customRecords,
 	}, nil
 }
 
@@ -217,6 +259,9 @@ func ValidateParsedPayloadTypes(parsedTypes tlv.TypeMap,
 	_, hasNextHop := parsedTypes[record.NextHopOnionType]
 	_, hasMPP := parsedTypes[record.MPPOnionType]
 	_, hasAMP := parsedTypes[record.AMPOnionType]
+	_, hasBlindingPoint := parsedTypes[record.BlindingPointOnionType]
+	_, hasTotalAmt := parsedTypes[record.TotalAmtMsatBlindedType]
+	_, hasTrampoline := parsedTypes[record.TrampolineOnionType]
 
 	switch {
 
@@ -261,6 +306,70 @@ func ValidateParsedPayloadTypes(parsedTypes tlv.TypeMap,
 			Violation: IncludedViolation,
 			FinalHop:  isFinalHop,
 		}
+
+	// Trampoline onions are only delivered to the trampoline node at the
+	// end of the route.
+	case !isFinalHop && hasTrampoline:
+		return ErrInvalidPayload{
+			Type:      record.TrampolineOnionType,
+			Violation: IncludedViolation,
+			FinalHop:  isFinalHop,
+		}
+
+	// A blinding point is only valid together with encrypted data.
+	case hasBlindingPoint:
+		return ErrInvalidPayload{
+			Type:      record.BlindingPointOnionType,
+			Violation: IncludedViolation,
+			FinalHop:  isFinalHop,
+		}
+
+	// The total amount of a blinded payment is only valid together with
+	// encrypted data.
+	case hasTotalAmt:
+		return ErrInvalidPayload{
+			Type:      record.TotalAmtMsatBlindedType,
+			Violation: IncludedViolation,
+			FinalHop:  isFinalHop,
+		}
+	}
+
+	return nil
+}
+
+// ValidateBlindedPayloadTypes checks the types parsed from the payload of a
+// hop in a blinded route. The next hop of a blinded hop is part of its
+// encrypted data, so only the records that must never be included by the
+// sender are checked here. The remaining requirements are validated once the
+// encrypted data has been decrypted.
+func ValidateBlindedPayloadTypes(parsedTypes tlv.TypeMap) error {
+	_, hasNextHop := parsedTypes[record.NextHopOnionType]
+	_, hasMPP := parsedTypes[record.MPPOnionType]
+	_, hasAMP := parsedTypes[record.AMPOnionType]
+
+	switch {
+
+	// The next hop is determined by the recipient of the route.
+	case hasNextHop:
+		return ErrInvalidPayload{
+			Type:      record.NextHopOnionType,
+			Violation: IncludedViolation,
+		}
+
+	// Payments to blinded routes use the total amount record instead of
+	// MPP fields.
+	case hasMPP:
+		return ErrInvalidPayload{
+			Type:      record.MPPOnionType,
+			Violation: IncludedViolation,
+		}
+
+	// Spontaneous payments can't be sent to blinded routes.
+	case hasAMP:
+		return ErrInvalidPayload{
+			Type:      record.AMPOnionType,
+			Violation: IncludedViolation,
+		}
 	}
 
 	return nil
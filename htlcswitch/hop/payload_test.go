@@ -258,6 +258,38 @@ var decodePayloadTests = []decodePayloadTest{
 		},
 		shouldHaveAMP: true,
 	},
+	{
+		name: "intermediate hop with trampoline onion",
+		payload: []byte{
+			// amount
+			0x02, 0x00,
+			// cltv
+			0x04, 0x00,
+			// next hop id
+			0x06, 0x08,
+			0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			// trampoline onion
+			0xfe, 0x00, 0x01, 0x02, 0x34, 0x01, 0x00,
+		},
+		expErr: hop.ErrInvalidPayload{
+			Type:      record.TrampolineOnionType,
+			Violation: hop.IncludedViolation,
+			FinalHop:  false,
+		},
+	},
+	{
+		// The trampoline onion is in the custom range, but must not be
+		// reported as a custom record.
+		name: "final hop with trampoline onion",
+		payload: []byte{
+			// amount
+			0x02, 0x00,
+			// cltv
+			0x04, 0x00,
+			// trampoline onion
+			0xfe, 0x00, 0x01, 0x02, 0x34, 0x01, 0x00,
+		},
+	},
 }
 
 // TestDecodeHopPayloadRecordValidation asserts that parsing the payloads in the
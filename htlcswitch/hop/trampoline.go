@@ -0,0 +1,391 @@
+package hop
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/brronsuite/broln/keychain"
+	"github.com/brronsuite/broln/lnwire"
+	"github.com/brronsuite/broln/onionmsg"
+	"github.com/brronsuite/broln/record"
+	"github.com/brronsuite/broln/tlv"
+	"github.com/brronsuite/broln/zpay32"
+	"github.com/brronsuite/brond/bronec"
+)
+
+const (
+	// TrampolineRoutingInfoSize is the size of the routing info of the
+	// trampoline onions we create. It leaves enough room in the outer
+	// onion for the route to the first trampoline node.
+	TrampolineRoutingInfoSize = 400
+
+	// TrampolineOnionSize is the size of the serialized trampoline onions
+	// we create.
+	TrampolineOnionSize = 1 + 33 + TrampolineRoutingInfoSize + 32
+
+	// hopHintSize is the size of a single serialized hop hint in the
+	// invoice routing info of a trampoline payload.
+	hopHintSize = 33 + 8 + 4 + 4 + 2
+)
+
+// ErrInvalidRoutingInfo is returned when the invoice routing info of a
+// trampoline payload can't be parsed.
+var ErrInvalidRoutingInfo = errors.New("invalid trampoline invoice " +
+	"routing info")
+
+// TrampolinePayload is the payload a trampoline node finds in its layer of
+// the trampoline onion. Intermediate trampoline nodes learn the next
+// trampoline node or the final recipient, which they have to find a route to
+// themselves.
+type TrampolinePayload struct {
+	// AmtToForward is the amount the next trampoline node or the final
+	// recipient must receive.
+	AmtToForward lnwire.MilliBronees
+
+	// OutgoingCltv is the cltv expiry the next trampoline node or the
+	// final recipient must receive.
+	OutgoingCltv uint32
+
+	// OutgoingNodeID is the public key of the next trampoline node or the
+	// final recipient. It is nil in the payload of the final recipient.
+	OutgoingNodeID *bronec.PublicKey
+
+	// MPP holds the total amount and payment address of the payment for
+	// the next trampoline node or the final recipient.
+	MPP *record.MPP
+
+	// InvoiceFeatures are the features of the recipient's invoice. They
+	// are only set for recipients that don't support trampoline routing,
+	// in which case the last trampoline node pays the recipient with a
+	// regular payment.
+	InvoiceFeatures *lnwire.RawFeatureVector
+
+	// InvoiceRoutingInfo are the route hints of the recipient's invoice
+	// for recipients that don't support trampoline routing.
+	InvoiceRoutingInfo [][]zpay32.HopHint
+}
+
+// IsFinal returns true if the payload is meant for the final recipient of the
+// payment.
+func (p *TrampolinePayload) IsFinal() bool {
+	return p.OutgoingNodeID == nil
+}
+
+// Encode serializes the trampoline payload as a TLV stream.
+func (p *TrampolinePayload) Encode(w io.Writer) error {
+	amt := uint64(p.AmtToForward)
+	records := []tlv.Record{
+		record.NewAmtToFwdRecord(&amt),
+		record.NewLockTimeRecord(&p.OutgoingCltv),
+	}
+
+	if p.MPP != nil {
+		records = append(records, p.MPP.Record())
+	}
+
+	if p.InvoiceFeatures != nil {
+		var b bytes.Buffer
+		if err := p.InvoiceFeatures.EncodeBase256(&b); err != nil {
+			return err
+		}
+		features := b.Bytes()
+		records = append(
+			records, record.NewInvoiceFeaturesRecord(&features),
+		)
+	}
+
+	if p.OutgoingNodeID != nil {
+		nodeID := p.OutgoingNodeID
+		records = append(
+			records, record.NewOutgoingNodeIDRecord(&nodeID),
+		)
+	}
+
+	if len(p.InvoiceRoutingInfo) > 0 {
+		routingInfo, err := encodeRoutingInfo(p.InvoiceRoutingInfo)
+		if err != nil {
+			return err
+		}
+		records = append(records, record.NewInvoiceRoutingInfoRecord(
+			&routingInfo,
+		))
+	}
+
+	tlvStream, err := tlv.NewStream(records...)
+	if err != nil {
+		return err
+	}
+
+	return tlvStream.Encode(w)
+}
+
+// DecodeTrampolinePayload parses a trampoline payload from the passed reader
+// and validates that the proper fields are included or omitted.
+func DecodeTrampolinePayload(r io.Reader) (*TrampolinePayload, error) {
+	var (
+		amt            uint64
+		cltv           uint32
+		mpp            = &record.MPP{}
+		features       []byte
+		outgoingNodeID *bronec.PublicKey
+		routingInfo    []byte
+	)
+
+	tlvStream, err := tlv.NewStream(
+		record.NewAmtToFwdRecord(&amt),
+		record.NewLockTimeRecord(&cltv),
+		mpp.Record(),
+		record.NewInvoiceFeaturesRecord(&features),
+		record.NewOutgoingNodeIDRecord(&outgoingNodeID),
+		record.NewInvoiceRoutingInfoRecord(&routingInfo),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedTypes, err := tlvStream.DecodeWithParsedTypes(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateTrampolinePayloadTypes(parsedTypes); err != nil {
+		return nil, err
+	}
+
+	_, hasNodeID := parsedTypes[record.OutgoingNodeIDOnionType]
+	violatingType := getMinRequiredViolation(parsedTypes)
+	if violatingType != nil {
+		return nil, ErrInvalidPayload{
+			Type:      *violatingType,
+			Violation: RequiredViolation,
+			FinalHop:  !hasNodeID,
+		}
+	}
+
+	payload := &TrampolinePayload{
+		AmtToForward:   lnwire.MilliBronees(amt),
+		OutgoingCltv:   cltv,
+		OutgoingNodeID: outgoingNodeID,
+	}
+
+	if _, ok := parsedTypes[record.MPPOnionType]; ok {
+		payload.MPP = mpp
+	}
+
+	if _, ok := parsedTypes[record.InvoiceFeaturesOnionType]; ok {
+		payload.InvoiceFeatures = lnwire.NewRawFeatureVector()
+		err := payload.InvoiceFeatures.DecodeBase256(
+			bytes.NewReader(features), len(features),
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(routingInfo) > 0 {
+		payload.InvoiceRoutingInfo, err = decodeRoutingInfo(routingInfo)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return payload, nil
+}
+
+// validateTrampolinePayloadTypes checks the types parsed from a trampoline
+// payload to ensure that the proper fields are either included or omitted.
+func validateTrampolinePayloadTypes(parsedTypes tlv.TypeMap) error {
+	_, hasAmt := parsedTypes[record.AmtOnionType]
+	_, hasLockTime := parsedTypes[record.LockTimeOnionType]
+	_, hasNodeID := parsedTypes[record.OutgoingNodeIDOnionType]
+	_, hasFeatures := parsedTypes[record.InvoiceFeaturesOnionType]
+	_, hasRoutingInfo := parsedTypes[record.InvoiceRoutingInfoOnionType]
+
+	switch {
+
+	// All trampoline hops must include an amount to forward.
+	case !hasAmt:
+		return ErrInvalidPayload{
+			Type:      record.AmtOnionType,
+			Violation: OmittedViolation,
+			FinalHop:  !hasNodeID,
+		}
+
+	// All trampoline hops must include a cltv expiry.
+	case !hasLockTime:
+		return ErrInvalidPayload{
+			Type:      record.LockTimeOnionType,
+			Violation: OmittedViolation,
+			FinalHop:  !hasNodeID,
+		}
+
+	// The invoice of the recipient is only of interest to the trampoline
+	// node that pays it.
+	case !hasNodeID && hasFeatures:
+		return ErrInvalidPayload{
+			Type:      record.InvoiceFeaturesOnionType,
+			Violation: IncludedViolation,
+			FinalHop:  true,
+		}
+
+	case !hasNodeID && hasRoutingInfo:
+		return ErrInvalidPayload{
+			Type:      record.InvoiceRoutingInfoOnionType,
+			Violation: IncludedViolation,
+			FinalHop:  true,
+		}
+	}
+
+	return nil
+}
+
+// encodeRoutingInfo serializes the route hints of an invoice. Every route is
+// prefixed by its number of hops.
+func encodeRoutingInfo(routes [][]zpay32.HopHint) ([]byte, error) {
+	var b bytes.Buffer
+	for _, hints := range routes {
+		if len(hints) == 0 || len(hints) > 255 {
+			return nil, fmt.Errorf("invalid route hint length %v",
+				len(hints))
+		}
+		b.WriteByte(byte(len(hints)))
+
+		for _, hint := range hints {
+			var scratch [hopHintSize]byte
+			copy(scratch[:33], hint.NodeID.SerializeCompressed())
+			binary.BigEndian.PutUint64(
+				scratch[33:41], hint.ChannelID,
+			)
+			binary.BigEndian.PutUint32(
+				scratch[41:45], hint.FeeBaseMSat,
+			)
+			binary.BigEndian.PutUint32(
+				scratch[45:49], hint.FeeProportionalMillionths,
+			)
+			binary.BigEndian.PutUint16(
+				scratch[49:51], hint.CLTVExpiryDelta,
+			)
+			b.Write(scratch[:])
+		}
+	}
+
+	return b.Bytes(), nil
+}
+
+// decodeRoutingInfo parses the route hints serialized by encodeRoutingInfo.
+func decodeRoutingInfo(b []byte) ([][]zpay32.HopHint, error) {
+	var routes [][]zpay32.HopHint
+	for len(b) > 0 {
+		numHops := int(b[0])
+		b = b[1:]
+
+		if numHops == 0 || len(b) < numHops*hopHintSize {
+			return nil, ErrInvalidRoutingInfo
+		}
+
+		hints := make([]zpay32.HopHint, 0, numHops)
+		for i := 0; i < numHops; i++ {
+			h := b[:hopHintSize]
+			b = b[hopHintSize:]
+
+			nodeID, err := bronec.ParsePubKey(h[:33], bronec.S256())
+			if err != nil {
+				return nil, err
+			}
+
+			be := binary.BigEndian
+			hints = append(hints, zpay32.HopHint{
+				NodeID:                    nodeID,
+				ChannelID:                 be.Uint64(h[33:41]),
+				FeeBaseMSat:               be.Uint32(h[41:45]),
+				FeeProportionalMillionths: be.Uint32(h[45:49]),
+				CLTVExpiryDelta:           be.Uint16(h[49:51]),
+			})
+		}
+
+		routes = append(routes, hints)
+	}
+
+	return routes, nil
+}
+
+// TrampolineHop is a hop of the route a trampoline onion takes.
+type TrampolineHop struct {
+	// NodePub is the public key of the trampoline node or the final
+	// recipient.
+	NodePub *bronec.PublicKey
+
+	// Payload is the payload for the hop.
+	Payload *TrampolinePayload
+}
+
+// NewTrampolineOnion creates a serialized trampoline onion for the given hops
+// using the session key as the first ephemeral key.
+func NewTrampolineOnion(sessionKey *bronec.PrivateKey,
+	hops []TrampolineHop) ([]byte, error) {
+
+	onionHops := make([]*onionmsg.HopInfo, 0, len(hops))
+	for _, hop := range hops {
+		var payload bytes.Buffer
+		if err := hop.Payload.Encode(&payload); err != nil {
+			return nil, err
+		}
+
+		onionHops = append(onionHops, &onionmsg.HopInfo{
+			NodePub: hop.NodePub,
+			Payload: payload.Bytes(),
+		})
+	}
+
+	packet, err := onionmsg.NewFixedSizePacket(
+		sessionKey, onionHops, TrampolineRoutingInfoSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if err := packet.Encode(&b); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// ProcessTrampolineOnion peels our layer off the serialized trampoline onion.
+// It returns our trampoline payload along with the serialized onion for the
+// next trampoline node, which is nil if we are the final recipient.
+func ProcessTrampolineOnion(key keychain.SingleKeyECDH,
+	onion []byte) (*TrampolinePayload, []byte, error) {
+
+	packet, err := onionmsg.DecodePacket(onion)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	processed, err := onionmsg.ProcessPacket(key, packet)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	payload, err := DecodeTrampolinePayload(
+		bytes.NewReader(processed.Payload),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if processed.NextPacket == nil {
+		return payload, nil, nil
+	}
+
+	var next bytes.Buffer
+	if err := processed.NextPacket.Encode(&next); err != nil {
+		return nil, nil, err
+	}
+
+	return payload, next.Bytes(), nil
+}
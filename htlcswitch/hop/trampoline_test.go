@@ -0,0 +1,199 @@
+package hop_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/brronsuite/broln/htlcswitch/hop"
+	"github.com/brronsuite/broln/keychain"
+	"github.com/brronsuite/broln/lnwire"
+	"github.com/brronsuite/broln/record"
+	"github.com/brronsuite/broln/zpay32"
+	"github.com/brronsuite/brond/bronec"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTrampolinePayloadEncodeDecode asserts that a trampoline payload with all
+// optional fields survives an encode and decode round trip.
+func TestTrampolinePayloadEncodeDecode(t *testing.T) {
+	nodeKey, err := bronec.NewPrivateKey(bronec.S256())
+	require.NoError(t, err)
+	hintKey, err := bronec.NewPrivateKey(bronec.S256())
+	require.NoError(t, err)
+
+	hint := zpay32.HopHint{
+		NodeID:                    hintKey.PubKey(),
+		ChannelID:                 12345,
+		FeeBaseMSat:               1000,
+		FeeProportionalMillionths: 100,
+		CLTVExpiryDelta:           40,
+	}
+	payload := &hop.TrampolinePayload{
+		AmtToForward:   100_000,
+		OutgoingCltv:   700_000,
+		OutgoingNodeID: nodeKey.PubKey(),
+		MPP:            record.NewMPP(200_000, [32]byte{1}),
+		InvoiceFeatures: lnwire.NewRawFeatureVector(
+			lnwire.TLVOnionPayloadRequired,
+			lnwire.PaymentAddrRequired,
+		),
+		InvoiceRoutingInfo: [][]zpay32.HopHint{
+			{hint}, {hint, hint},
+		},
+	}
+
+	var b bytes.Buffer
+	require.NoError(t, payload.Encode(&b))
+
+	decoded, err := hop.DecodeTrampolinePayload(&b)
+	require.NoError(t, err)
+
+	require.False(t, decoded.IsFinal())
+	require.Equal(t, payload.AmtToForward, decoded.AmtToForward)
+	require.Equal(t, payload.OutgoingCltv, decoded.OutgoingCltv)
+	require.True(t, decoded.OutgoingNodeID.IsEqual(nodeKey.PubKey()))
+	require.Equal(t, payload.MPP, decoded.MPP)
+	require.Equal(t, payload.InvoiceFeatures, decoded.InvoiceFeatures)
+
+	require.Len(t, decoded.InvoiceRoutingInfo, 2)
+	require.Len(t, decoded.InvoiceRoutingInfo[0], 1)
+	require.Len(t, decoded.InvoiceRoutingInfo[1], 2)
+	for _, hints := range decoded.InvoiceRoutingInfo {
+		for _, decodedHint := range hints {
+			require.True(t, decodedHint.NodeID.IsEqual(hint.NodeID))
+			decodedHint.NodeID = hint.NodeID
+			require.Equal(t, hint, decodedHint)
+		}
+	}
+}
+
+// TestTrampolinePayloadValidation asserts that trampoline payloads with
+// missing or misplaced fields are rejected.
+func TestTrampolinePayloadValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload []byte
+		expErr  error
+	}{
+		{
+			name:    "final hop valid",
+			payload: []byte{0x02, 0x00, 0x04, 0x00},
+		},
+		{
+			name:    "final hop no amount",
+			payload: []byte{0x04, 0x00},
+			expErr: hop.ErrInvalidPayload{
+				Type:      record.AmtOnionType,
+				Violation: hop.OmittedViolation,
+				FinalHop:  true,
+			},
+		},
+		{
+			name:    "final hop no expiry",
+			payload: []byte{0x02, 0x00},
+			expErr: hop.ErrInvalidPayload{
+				Type:      record.LockTimeOnionType,
+				Violation: hop.OmittedViolation,
+				FinalHop:  true,
+			},
+		},
+		{
+			name: "final hop with invoice features",
+			payload: []byte{
+				// amount
+				0x02, 0x00,
+				// cltv
+				0x04, 0x00,
+				// invoice features
+				0xfe, 0x00, 0x01, 0x02, 0x31, 0x01, 0x01,
+			},
+			expErr: hop.ErrInvalidPayload{
+				Type:      record.InvoiceFeaturesOnionType,
+				Violation: hop.IncludedViolation,
+				FinalHop:  true,
+			},
+		},
+		{
+			name: "required type",
+			payload: []byte{
+				// amount
+				0x02, 0x00,
+				// cltv
+				0x04, 0x00,
+				// unknown required type
+				0x06, 0x00,
+			},
+			expErr: hop.ErrInvalidPayload{
+				Type:      0x06,
+				Violation: hop.RequiredViolation,
+				FinalHop:  true,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			_, err := hop.DecodeTrampolinePayload(
+				bytes.NewReader(test.payload),
+			)
+			require.Equal(t, test.expErr, err)
+		})
+	}
+}
+
+// TestTrampolineOnion asserts that every hop of a trampoline onion learns its
+// own payload, and that only the final hop is told that it is the final hop.
+func TestTrampolineOnion(t *testing.T) {
+	trampolineKey, err := bronec.NewPrivateKey(bronec.S256())
+	require.NoError(t, err)
+	recipientKey, err := bronec.NewPrivateKey(bronec.S256())
+	require.NoError(t, err)
+	sessionKey, err := bronec.NewPrivateKey(bronec.S256())
+	require.NoError(t, err)
+
+	trampolinePayload := &hop.TrampolinePayload{
+		AmtToForward:   100_000,
+		OutgoingCltv:   700_000,
+		OutgoingNodeID: recipientKey.PubKey(),
+	}
+	recipientPayload := &hop.TrampolinePayload{
+		AmtToForward: 100_000,
+		OutgoingCltv: 700_000,
+		MPP:          record.NewMPP(100_000, [32]byte{1}),
+	}
+
+	onion, err := hop.NewTrampolineOnion(sessionKey, []hop.TrampolineHop{
+		{
+			NodePub: trampolineKey.PubKey(),
+			Payload: trampolinePayload,
+		},
+		{
+			NodePub: recipientKey.PubKey(),
+			Payload: recipientPayload,
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, onion, hop.TrampolineOnionSize)
+
+	// The recipient can't process the onion before the trampoline node
+	// has peeled off its layer.
+	recipientECDH := &keychain.PrivKeyECDH{PrivKey: recipientKey}
+	_, _, err = hop.ProcessTrampolineOnion(recipientECDH, onion)
+	require.Error(t, err)
+
+	payload, next, err := hop.ProcessTrampolineOnion(
+		&keychain.PrivKeyECDH{PrivKey: trampolineKey}, onion,
+	)
+	require.NoError(t, err)
+	require.False(t, payload.IsFinal())
+	require.Equal(t, trampolinePayload.AmtToForward, payload.AmtToForward)
+	require.True(t, payload.OutgoingNodeID.IsEqual(recipientKey.PubKey()))
+	require.Len(t, next, hop.TrampolineOnionSize)
+
+	payload, next, err = hop.ProcessTrampolineOnion(recipientECDH, next)
+	require.NoError(t, err)
+	require.Nil(t, next)
+	require.True(t, payload.IsFinal())
+	require.Equal(t, recipientPayload, payload)
+}
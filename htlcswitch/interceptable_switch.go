@@ -3,8 +3,10 @@ package htlcswitch
 import (
 	"fmt"
 	"sync"
+	"sync/atomic"
 
 	"github.com/go-errors/errors"
+	"github.com/brronsuite/broln/chainntnfs"
 	"github.com/brronsuite/broln/channeldb"
 	"github.com/brronsuite/broln/htlcswitch/hop"
 	"github.com/brronsuite/broln/lntypes"
@@ -17,6 +19,27 @@ var (
 	ErrFwdNotExists = errors.New("forward does not exist")
 )
 
+// InterceptableSwitchConfig contains the configuration of the interceptable
+// switch.
+type InterceptableSwitchConfig struct {
+	// Switch is the underlying switch to which forwards are handed once
+	// they are resolved.
+	Switch *Switch
+
+	// Notifier is used to learn about new blocks, such that held
+	// forwards can be failed before their incoming htlc expires.
+	Notifier chainntnfs.ChainNotifier
+
+	// CltvRejectDelta is the number of blocks before the expiry of an
+	// incoming htlc at which a forward that is held while no interceptor
+	// is set is failed back.
+	CltvRejectDelta uint32
+
+	// RequireInterceptor indicates whether forwards are held while no
+	// interceptor is set, instead of being resumed.
+	RequireInterceptor bool
+}
+
 // InterceptableSwitch is an implementation of ForwardingSwitch interface.
 // This implementation is used like a proxy that wraps the switch and
 // intercepts forward requests. A reference to the Switch is held in order
@@ -25,8 +48,13 @@ var (
 // Settle - routes UpdateFulfillHTLC to the originating link.
 // Fail - routes UpdateFailHTLC to the originating link.
 type InterceptableSwitch struct {
+	started int32 // To be used atomically.
+	stopped int32 // To be used atomically.
+
 	sync.RWMutex
 
+	cfg InterceptableSwitchConfig
+
 	// htlcSwitch is the underline switch
 	htlcSwitch *Switch
 
@@ -34,20 +62,208 @@ type InterceptableSwitch struct {
 	// an incoming htlc. It should return true if it is interested in handling
 	// it.
 	fwdInterceptor ForwardInterceptor
+
+	// interceptorGen is incremented every time the interceptor is set,
+	// such that a forward that was declined by an interceptor isn't
+	// offered to that same interceptor again.
+	interceptorGen uint64
+
+	// requireInterceptor indicates whether forwards are held while no
+	// interceptor is set, instead of being resumed.
+	requireInterceptor bool
+
+	// heldForwards are the forwards that are held while no interceptor is
+	// set, if an interceptor is required.
+	heldForwards map[channeldb.CircuitKey]InterceptedForward
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+// NewInterceptableSwitch returns an instance of InterceptableSwitch. If an
+// interceptor is required, forwards are held while no interceptor is set,
+// and offered to the next interceptor that is set.
+func NewInterceptableSwitch(
+	cfg *InterceptableSwitchConfig) *InterceptableSwitch {
+
+	return &InterceptableSwitch{
+		cfg:                *cfg,
+		htlcSwitch:         cfg.Switch,
+		requireInterceptor: cfg.RequireInterceptor,
+		heldForwards: make(
+			map[channeldb.CircuitKey]InterceptedForward,
+		),
+		quit: make(chan struct{}),
+	}
+}
+
+// Start subscribes to new blocks, such that held forwards are failed back
+// before their incoming htlc expires.
+func (s *InterceptableSwitch) Start() error {
+	if !atomic.CompareAndSwapInt32(&s.started, 0, 1) {
+		return errors.New("interceptable switch already started")
+	}
+
+	blockEpochStream, err := s.cfg.Notifier.RegisterBlockEpochNtfn(nil)
+	if err != nil {
+		return err
+	}
+
+	s.wg.Add(1)
+	go s.expireHeldForwards(blockEpochStream)
+
+	return nil
+}
+
+// Stop stops the interceptable switch.
+func (s *InterceptableSwitch) Stop() error {
+	if !atomic.CompareAndSwapInt32(&s.stopped, 0, 1) {
+		return errors.New("interceptable switch already stopped")
+	}
+
+	close(s.quit)
+	s.wg.Wait()
+
+	return nil
 }
 
-// NewInterceptableSwitch returns an instance of InterceptableSwitch.
-func NewInterceptableSwitch(s *Switch) *InterceptableSwitch {
-	return &InterceptableSwitch{htlcSwitch: s}
+// expireHeldForwards fails back the forwards that are held while no
+// interceptor is set once their incoming htlc is about to expire. Otherwise
+// we would have to force close the incoming channel if no interceptor is set
+// in time.
+//
+// NOTE: This MUST be run as a goroutine.
+func (s *InterceptableSwitch) expireHeldForwards(
+	blockEpochStream *chainntnfs.BlockEpochEvent) {
+
+	defer s.wg.Done()
+	defer blockEpochStream.Cancel()
+
+	for {
+		select {
+		case blockEpoch, ok := <-blockEpochStream.Epochs:
+			if !ok {
+				return
+			}
+
+			s.failExpiringForwards(uint32(blockEpoch.Height))
+
+		case <-s.quit:
+			return
+		}
+	}
 }
 
-// SetInterceptor sets the ForwardInterceptor to be used.
+// failExpiringForwards fails back all held forwards whose incoming htlc
+// expires within CltvRejectDelta blocks of the given height.
+func (s *InterceptableSwitch) failExpiringForwards(height uint32) {
+	var expiring []InterceptedForward
+
+	s.Lock()
+	for key, fwd := range s.heldForwards {
+		expiry := fwd.Packet().IncomingExpiry
+		if expiry > height+s.cfg.CltvRejectDelta {
+			continue
+		}
+
+		expiring = append(expiring, fwd)
+		delete(s.heldForwards, key)
+	}
+	s.Unlock()
+
+	for _, fwd := range expiring {
+		packet := fwd.Packet()
+
+		log.Infof("Failing held forward %v at height %v, as its "+
+			"incoming htlc expires at height %v",
+			packet.IncomingCircuit, height, packet.IncomingExpiry)
+
+		// If the forward can't be failed, it is held again once the
+		// link that accepted it replays it.
+		if err := fwd.Fail(); err != nil {
+			log.Errorf("Unable to fail held forward %v: %v",
+				packet.IncomingCircuit, err)
+		}
+	}
+}
+
+// SetInterceptor sets the ForwardInterceptor to be used. Any forwards that
+// were held while no interceptor was set are offered to the new interceptor.
 func (s *InterceptableSwitch) SetInterceptor(
 	interceptor ForwardInterceptor) {
 
 	s.Lock()
-	defer s.Unlock()
 	s.fwdInterceptor = interceptor
+	s.interceptorGen++
+	generation := s.interceptorGen
+
+	var held []InterceptedForward
+	if interceptor != nil {
+		for key, fwd := range s.heldForwards {
+			held = append(held, fwd)
+			delete(s.heldForwards, key)
+		}
+	}
+	s.Unlock()
+
+	if len(held) == 0 {
+		return
+	}
+
+	log.Infof("Offering %v held forwards to new interceptor", len(held))
+
+	// The interceptor may not accept the forwards until its caller
+	// returns, so we offer them in the background.
+	go func() {
+		for _, fwd := range held {
+			if !interceptor(fwd) {
+				s.holdForward(fwd, generation)
+			}
+		}
+	}()
+}
+
+// Release hands back a held forward that the interceptor didn't resolve. The
+// forward is either resumed, or held until the next interceptor is set if an
+// interceptor is required.
+func (s *InterceptableSwitch) Release(fwd InterceptedForward) error {
+	if !s.requireInterceptor {
+		return fwd.Resume()
+	}
+
+	s.holdForward(fwd, 0)
+
+	return nil
+}
+
+// holdForward holds the forward until the next interceptor is set. If an
+// interceptor other than the one of the given generation that declined the
+// forward is set already, the forward is offered to it instead. A zero
+// generation indicates that the forward wasn't declined by any interceptor.
+func (s *InterceptableSwitch) holdForward(fwd InterceptedForward,
+	declinedGen uint64) {
+
+	for {
+		s.Lock()
+		interceptor := s.fwdInterceptor
+		generation := s.interceptorGen
+
+		// An interceptor declines forwards while it is shutting down,
+		// so we hold the forward instead of offering it to the same
+		// interceptor again.
+		if interceptor == nil || generation == declinedGen {
+			s.heldForwards[fwd.Packet().IncomingCircuit] = fwd
+			s.Unlock()
+
+			return
+		}
+		s.Unlock()
+
+		if interceptor(fwd) {
+			return
+		}
+		declinedGen = generation
+	}
 }
 
 // ForwardPackets attempts to forward the batch of htlcs through the
@@ -62,14 +278,17 @@ func (s *InterceptableSwitch) ForwardPackets(linkQuit chan struct{},
 	interceptor = s.fwdInterceptor
 	s.Unlock()
 
-	// Optimize for the case we don't have an interceptor.
-	if interceptor == nil {
+	// Optimize for the case we don't have an interceptor, and none of the
+	// packets can be replays of held forwards.
+	if interceptor == nil && !s.requireInterceptor &&
+		s.htlcSwitch.heldForwards.empty() {
+
 		return s.htlcSwitch.ForwardPackets(linkQuit, packets...)
 	}
 
 	var notIntercepted []*htlcPacket
 	for _, p := range packets {
-		if !s.interceptForward(p, interceptor, linkQuit) {
+		if !s.interceptForward(p, linkQuit) {
 			notIntercepted = append(notIntercepted, p)
 		}
 	}
@@ -81,7 +300,7 @@ func (s *InterceptableSwitch) ForwardPackets(linkQuit chan struct{},
 // are being checked for interception. It can be extended in the future given
 // the right use case.
 func (s *InterceptableSwitch) interceptForward(packet *htlcPacket,
-	interceptor ForwardInterceptor, linkQuit chan struct{}) bool {
+	linkQuit chan struct{}) bool {
 
 	switch htlc := packet.htlc.(type) {
 	case *lnwire.UpdateAddHTLC:
@@ -98,12 +317,82 @@ func (s *InterceptableSwitch) interceptForward(packet *htlcPacket,
 		}
 
 		// If this htlc was intercepted, don't handle the forward.
-		return interceptor(intercepted)
+		return s.intercept(intercepted)
 	default:
 		return false
 	}
 }
 
+// InterceptExitHop checks if there is any external interceptor interested in
+// an htlc destined for one of our invoices. It returns true if the htlc is
+// held, in which case it is resolved through the passed intercepted htlc.
+func (s *InterceptableSwitch) InterceptExitHop(
+	intercepted InterceptedForward) bool {
+
+	return s.intercept(intercepted)
+}
+
+// HeldForwards returns all htlcs that are held by an interceptor, including
+// the ones that were held before a restart and weren't replayed yet.
+func (s *InterceptableSwitch) HeldForwards() []HeldForward {
+	return s.htlcSwitch.heldForwards.list()
+}
+
+// intercept offers the intercepted htlc to the interceptor, holding it if the
+// interceptor isn't interested while an interceptor is required. Htlcs that
+// were held before a restart are held again, even if no interceptor is
+// required, such that they aren't resumed before the interceptor reconnects.
+func (s *InterceptableSwitch) intercept(
+	intercepted InterceptedForward) bool {
+
+	s.Lock()
+	interceptor := s.fwdInterceptor
+	generation := s.interceptorGen
+	s.Unlock()
+
+	store := s.htlcSwitch.heldForwards
+	key := intercepted.Packet().IncomingCircuit
+	wasHeld := store.isHeld(key)
+
+	if interceptor == nil && !s.requireInterceptor && !wasHeld {
+		return false
+	}
+
+	fwd := &persistedForward{
+		InterceptedForward: intercepted,
+		store:              store,
+		persisted:          wasHeld,
+	}
+
+	// The htlc is only persisted as held once the interceptor accepted it
+	// or we hold it ourselves, such that htlcs that are resumed right
+	// away don't cost a database write. If the interceptor resolves the
+	// htlc before it is persisted, it isn't persisted at all.
+	if interceptor != nil && interceptor(fwd) {
+		s.persistForward(fwd)
+
+		return true
+	}
+
+	if !s.requireInterceptor && !wasHeld {
+		return false
+	}
+
+	s.persistForward(fwd)
+	s.holdForward(fwd, generation)
+
+	return true
+}
+
+// persistForward persists the forward as held, such that it is held again
+// when the link that accepted it replays it after a restart.
+func (s *InterceptableSwitch) persistForward(fwd *persistedForward) {
+	if err := fwd.persist(); err != nil {
+		log.Errorf("Unable to persist held forward %v: %v",
+			fwd.Packet().IncomingCircuit, err)
+	}
+}
+
 // interceptedForward implements the InterceptedForward interface.
 // It is passed from the switch to external interceptors that are interested
 // in holding forwards and resolve them manually.
@@ -146,9 +435,15 @@ func (f *interceptedForward) Fail() error {
 		return err
 	}
 
-	reason, err := f.packet.obfuscator.EncryptFirstHop(
-		lnwire.NewTemporaryChannelFailure(update),
-	)
+	return f.FailWithMessage(lnwire.NewTemporaryChannelFailure(update))
+}
+
+// FailWithMessage forwards a packet failed with the given failure message to
+// the switch.
+func (f *interceptedForward) FailWithMessage(
+	failure lnwire.FailureMessage) error {
+
+	reason, err := f.packet.obfuscator.EncryptFirstHop(failure)
 	if err != nil {
 		return fmt.Errorf("failed to encrypt failure reason %v", err)
 	}
@@ -157,6 +452,14 @@ func (f *interceptedForward) Fail() error {
 	})
 }
 
+// FailWithReason forwards a packet failed with the given encrypted failure
+// reason to the switch.
+func (f *interceptedForward) FailWithReason(reason lnwire.OpaqueReason) error {
+	return f.resolve(&lnwire.UpdateFailHTLC{
+		Reason: f.packet.obfuscator.IntermediateEncrypt(reason),
+	})
+}
+
 // Settle forwards a settled packet to the switch.
 func (f *interceptedForward) Settle(preimage lntypes.Preimage) error {
 	if !preimage.Matches(f.htlc.PaymentHash) {
@@ -183,3 +486,103 @@ func (f *interceptedForward) resolve(message lnwire.Message) error {
 	}
 	return f.htlcSwitch.mailOrchestrator.Deliver(pkt.incomingChanID, pkt)
 }
+
+// interceptedExitHop implements the InterceptedForward interface for htlcs
+// destined for our own invoices. Its resolutions are delivered to the link
+// that accepted the htlc, which applies them instead of notifying the invoice
+// registry.
+type interceptedExitHop struct {
+	packet       InterceptedPacket
+	acceptHeight uint32
+	linkQuit     chan struct{}
+	resolutions  chan<- interface{}
+}
+
+// Packet returns the intercepted htlc packet.
+func (f *interceptedExitHop) Packet() InterceptedPacket {
+	return f.packet
+}
+
+// Resume hands the htlc to the invoice registry as if it was not intercepted.
+func (f *interceptedExitHop) Resume() error {
+	return f.resolve(&exitHopResolution{
+		circuitKey: f.packet.IncomingCircuit,
+		resume:     true,
+	})
+}
+
+// Fail fails the htlc with incorrect payment details, as the invoice registry
+// does for htlcs it rejects.
+func (f *interceptedExitHop) Fail() error {
+	return f.FailWithMessage(lnwire.NewFailIncorrectDetails(
+		f.packet.IncomingAmount, f.acceptHeight,
+	))
+}
+
+// FailWithMessage fails the htlc with the given failure message.
+func (f *interceptedExitHop) FailWithMessage(
+	failure lnwire.FailureMessage) error {
+
+	return f.resolve(&exitHopResolution{
+		circuitKey: f.packet.IncomingCircuit,
+		failure:    failure,
+	})
+}
+
+// FailWithReason fails the htlc with the given encrypted failure reason.
+func (f *interceptedExitHop) FailWithReason(reason lnwire.OpaqueReason) error {
+	return f.resolve(&exitHopResolution{
+		circuitKey: f.packet.IncomingCircuit,
+		reason:     reason,
+	})
+}
+
+// Settle settles the htlc with the given preimage, without settling the
+// invoice it is destined for.
+func (f *interceptedExitHop) Settle(preimage lntypes.Preimage) error {
+	if !preimage.Matches(f.packet.Hash) {
+		return errors.New("preimage does not match hash")
+	}
+	return f.resolve(&exitHopResolution{
+		circuitKey: f.packet.IncomingCircuit,
+		preimage:   &preimage,
+	})
+}
+
+// resolve delivers the resolution to the link that accepted the htlc.
+func (f *interceptedExitHop) resolve(resolution *exitHopResolution) error {
+	select {
+	case f.resolutions <- resolution:
+		return nil
+	case <-f.linkQuit:
+		return ErrLinkShuttingDown
+	}
+}
+
+// exitHopResolution is the resolution of an intercepted htlc that is destined
+// for one of our invoices. It implements the invoices.HtlcResolution
+// interface, such that it can be delivered through the link's hodl queue.
+type exitHopResolution struct {
+	// circuitKey is the key of the htlc for which we have a resolution.
+	circuitKey channeldb.CircuitKey
+
+	// resume indicates that the htlc should be handed to the invoice
+	// registry.
+	resume bool
+
+	// preimage is set if the htlc should be settled.
+	preimage *lntypes.Preimage
+
+	// failure is set if the htlc should be failed with a failure message.
+	failure lnwire.FailureMessage
+
+	// reason is set if the htlc should be failed with an encrypted
+	// failure reason.
+	reason lnwire.OpaqueReason
+}
+
+// CircuitKey returns the circuit key for the htlc that we have a resolution
+// for.
+func (r *exitHopResolution) CircuitKey() channeldb.CircuitKey {
+	return r.circuitKey
+}
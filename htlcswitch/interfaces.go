@@ -165,11 +165,12 @@ type ChannelLink interface {
 	// satisfy the current forwarding policy fo the target link. Otherwise,
 	// a LinkError with a valid protocol failure message should be returned
 	// in order to signal to the source of the HTLC, the policy consistency
-	// issue.
+	// issue. The inbound fee is the fee of the link over which the HTLC
+	// arrived.
 	CheckHtlcForward(payHash [32]byte, incomingAmt lnwire.MilliBronees,
 		amtToForward lnwire.MilliBronees,
 		incomingTimeout, outgoingTimeout uint32,
-		heightNow uint32) *LinkError
+		inboundFee lnwire.InboundFee, heightNow uint32) *LinkError
 
 	// CheckHtlcTransit should return a nil error if the passed HTLC details
 	// satisfy the current channel policy.  Otherwise, a LinkError with a
@@ -234,10 +235,21 @@ type TowerClient interface {
 type InterceptableHtlcForwarder interface {
 	// SetInterceptor sets a ForwardInterceptor.
 	SetInterceptor(interceptor ForwardInterceptor)
+
+	// Release hands back a held forward that the interceptor didn't
+	// resolve. The forward is either resumed, or held until the next
+	// interceptor is set if an interceptor is required.
+	Release(fwd InterceptedForward) error
+
+	// HeldForwards returns all htlcs that are held by an interceptor,
+	// including the ones that were held before a restart and weren't
+	// replayed yet.
+	HeldForwards() []HeldForward
 }
 
 // ForwardInterceptor is a function that is invoked from the switch for every
-// incoming htlc that is intended to be forwarded. It is passed with the
+// incoming htlc that is intended to be forwarded or settled with one of our
+// invoices. It is passed with the
 // InterceptedForward that contains the information about the packet and a way
 // to resolve it manually later in case it is held.
 // The return value indicates if this handler will take control of this forward
@@ -251,7 +263,8 @@ type InterceptedPacket struct {
 	// packet.
 	IncomingCircuit channeldb.CircuitKey
 
-	// OutgoingChanID is the destination channel for this packet.
+	// OutgoingChanID is the destination channel for this packet. It is
+	// hop.Exit for htlcs destined for our own invoices.
 	OutgoingChanID lnwire.ShortChannelID
 
 	// Hash is the payment hash of the htlc.
@@ -275,7 +288,8 @@ type InterceptedPacket struct {
 	// were included in the payload.
 	CustomRecords record.CustomSet
 
-	// OnionBlob is the onion packet for the next hop
+	// OnionBlob is the onion packet for the next hop. It is empty for
+	// htlcs destined for our own invoices.
 	OnionBlob [lnwire.OnionPacketSize]byte
 }
 
@@ -299,6 +313,35 @@ type InterceptedForward interface {
 
 	// Fails notifies the intention to fail an existing hold forward
 	Fail() error
+
+	// FailWithMessage notifies the intention to fail an existing hold
+	// forward with the given failure message, which is encrypted for the
+	// sender of the htlc.
+	FailWithMessage(lnwire.FailureMessage) error
+
+	// FailWithReason notifies the intention to fail an existing hold
+	// forward with a failure reason that is already encrypted for the
+	// sender of the htlc, such as one received from a downstream node. The
+	// reason is obfuscated with our own shared secret before it is sent
+	// back.
+	FailWithReason(lnwire.OpaqueReason) error
+}
+
+// TrampolineHtlc is an htlc destined for us that carries a trampoline onion.
+// It is offered to the trampoline forwarder instead of the invoice registry,
+// and resolved through the embedded InterceptedForward once the payment to
+// the next trampoline node or the final recipient completes.
+type TrampolineHtlc struct {
+	InterceptedForward
+
+	// TrampolineOnion is the serialized trampoline onion found in the
+	// payload of the htlc.
+	TrampolineOnion []byte
+
+	// MPP is the mpp record of the payload, which identifies the set of
+	// htlcs that together pay for the trampoline forward. It is nil if
+	// the sender didn't include one.
+	MPP *record.MPP
 }
 
 // htlcNotifier is an interface which represents the input side of the
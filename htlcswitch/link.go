@@ -97,6 +97,11 @@ type ForwardingPolicy struct {
 	//    per-hop payload of the incoming HTLC's onion packet.
 	TimeLockDelta uint32
 
+	// InboundFee is the fee that must be paid for each HTLC that arrives
+	// over this channel, on top of the fee of the outgoing channel. It
+	// may be negative, in which case it is a discount on the outgoing fee.
+	InboundFee lnwire.InboundFee
+
 	// TODO(roasbeef): add fee module inside of switch
 }
 
@@ -143,6 +148,20 @@ type ChannelLinkConfig struct {
 	// cancellation of forwarding during link shutdown.
 	ForwardPackets func(chan struct{}, ...*htlcPacket) error
 
+	// InterceptExitHop offers htlcs destined for our own invoices to an
+	// external interceptor before they are handed to the invoice
+	// registry. It returns true if the htlc is held by the interceptor,
+	// in which case its resolution is delivered through the hodl queue.
+	// If nil, htlcs aren't intercepted.
+	InterceptExitHop func(InterceptedForward) bool
+
+	// ForwardTrampoline offers htlcs destined for us that carry a
+	// trampoline onion to the trampoline forwarder. It returns true if
+	// the htlc is held by the forwarder, in which case its resolution is
+	// delivered through the hodl queue. If nil, such htlcs are handed to
+	// the invoice registry like any other htlc.
+	ForwardTrampoline func(*TrampolineHtlc) bool
+
 	// DecodeHopIterators facilitates batched decoding of HTLC Sphinx onion
 	// blobs, which are then used to inform how to forward an HTLC.
 	//
@@ -257,6 +276,13 @@ type ChannelLinkConfig struct {
 	// risk offering an htlc that triggers channel closure.
 	OutgoingCltvRejectDelta uint32
 
+	// BackupCommitState is an optional closure that persists the latest
+	// local commitment of the channel to the static channel backup. It's
+	// called before we revoke our prior commitment, as restoring a backup
+	// carrying a revoked commitment allows the remote party to sweep all
+	// funds of the channel.
+	BackupCommitState func(wire.OutPoint) error
+
 	// TowerClient is an optional engine that manages the signing,
 	// encrypting, and uploading of justice transactions to the daemon's
 	// configured set of watchtowers for legacy channels.
@@ -411,6 +437,11 @@ type channelLink struct {
 type hodlHtlc struct {
 	pd         *lnwallet.PaymentDescriptor
 	obfuscator hop.ErrorEncrypter
+
+	// payload is the hop payload of an htlc that is held by an
+	// interceptor, which is needed to hand it to the invoice registry
+	// once resumed.
+	payload invoices.Payload
 }
 
 // NewChannelLink creates a new instance of a ChannelLink given a configuration
@@ -757,8 +788,17 @@ func (l *channelLink) syncChanStates() error {
 
 		// If we have any messages to retransmit, we'll do so
 		// immediately so we return to a synchronized state as soon as
-		// possible.
+		// possible. If we're about to retransmit a revocation, we may
+		// have gone down before the backup of our new commitment was
+		// updated, so we'll make sure it is before.
 		for _, msg := range msgsToReSend {
+			if _, ok := msg.(*lnwire.RevokeAndAck); ok {
+				if err := l.backupCommitState(); err != nil {
+					return fmt.Errorf("unable to backup "+
+						"commitment: %v", err)
+				}
+			}
+
 			l.cfg.Peer.SendMessage(false, msg)
 		}
 
@@ -769,6 +809,16 @@ func (l *channelLink) syncChanStates() error {
 	return nil
 }
 
+// backupCommitState updates the static channel backup with our latest local
+// commitment, if the backups carry the commitment state.
+func (l *channelLink) backupCommitState() error {
+	if l.cfg.BackupCommitState == nil {
+		return nil
+	}
+
+	return l.cfg.BackupCommitState(*l.ChannelPoint())
+}
+
 // resolveFwdPkgs loads any forwarding packages for this link from disk, and
 // reprocesses them in order. The primary goal is to make sure that any HTLCs
 // we previously received are reinstated in memory, and forwarded to the switch
@@ -1240,13 +1290,15 @@ loop:
 			return fmt.Errorf("hodl htlc not found: %v", circuitKey)
 		}
 
+		// Clean up hodl map. This is done before processing the
+		// resolution, as a resumed intercepted htlc may be held again
+		// by the invoice registry.
+		delete(l.hodlMap, circuitKey)
+
 		if err := l.processHtlcResolution(htlcResolution, hodlHtlc); err != nil {
 			return err
 		}
 
-		// Clean up hodl map.
-		delete(l.hodlMap, circuitKey)
-
 		select {
 		case item := <-l.hodlQueue.ChanOut():
 			htlcResolution = item.(invoices.HtlcResolution)
@@ -1297,6 +1349,10 @@ func (l *channelLink) processHtlcResolution(resolution invoices.HtlcResolution,
 		)
 		return nil
 
+	// An interceptor resolved an htlc destined for one of our invoices.
+	case *exitHopResolution:
+		return l.processExitHopResolution(res, htlc)
+
 	// Fail if we do not get a settle of fail resolution, since we
 	// are only expecting to handle settles and fails.
 	default:
@@ -1799,7 +1855,7 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 		// We just received a new updates to our local commitment
 		// chain, validate this new commitment, closing the link if
 		// invalid.
-		err = l.channel.ReceiveNewCommitment(msg.CommitSig, msg.HtlcSigs)
+		err = l.channel.ReceiveCommitSig(msg)
 		if err != nil {
 			// If we were unable to reconstruct their proposed
 			// commitment, then we'll examine the type of error. If
@@ -1833,6 +1889,17 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 			l.log.Errorf("unable to revoke commitment: %v", err)
 			return
 		}
+
+		// Our prior commitment is only revoked once the remote party
+		// receives our revocation, so we'll first update the backup
+		// of the channel with our new commitment.
+		if err := l.backupCommitState(); err != nil {
+			l.fail(
+				LinkFailureError{code: ErrInternalError},
+				"unable to backup commitment: %v", err,
+			)
+			return
+		}
 		l.cfg.Peer.SendMessage(false, nextRevocation)
 
 		// Since we just revoked our commitment, we may have a new set
@@ -2064,7 +2131,7 @@ func (l *channelLink) updateCommitTx() error {
 		return nil
 	}
 
-	theirCommitSig, htlcSigs, pendingHTLCs, err := l.channel.SignNextCommitment()
+	commitSig, pendingHTLCs, err := l.channel.SignNextCommitSig()
 	if err == lnwallet.ErrNoWindow {
 		l.cfg.PendingCommitTicker.Resume()
 
@@ -2101,11 +2168,6 @@ func (l *channelLink) updateCommitTx() error {
 		return ErrLinkShuttingDown
 	}
 
-	commitSig := &lnwire.CommitSig{
-		ChanID:    l.ChanID(),
-		CommitSig: theirCommitSig,
-		HtlcSigs:  htlcSigs,
-	}
 	l.cfg.Peer.SendMessage(false, commitSig)
 
 	return nil
@@ -2312,7 +2374,7 @@ func (l *channelLink) UpdateForwardingPolicy(newPolicy ForwardingPolicy) {
 func (l *channelLink) CheckHtlcForward(payHash [32]byte,
 	incomingHtlcAmt, amtToForward lnwire.MilliBronees,
 	incomingTimeout, outgoingTimeout uint32,
-	heightNow uint32) *LinkError {
+	inboundFee lnwire.InboundFee, heightNow uint32) *LinkError {
 
 	l.RLock()
 	policy := l.cfg.FwrdingPolicy
@@ -2329,18 +2391,29 @@ func (l *channelLink) CheckHtlcForward(payHash [32]byte,
 	// Next, using the amount of the incoming HTLC, we'll calculate the
 	// expected fee this incoming HTLC must carry in order to satisfy the
 	// constraints of the outgoing link.
-	expectedFee := ExpectedFee(policy, amtToForward)
+	outboundFee := ExpectedFee(policy, amtToForward)
+
+	// Then we add the inbound fee of the incoming link, which is based on
+	// the outgoing amount plus the outbound fee. Both fee components are
+	// calculated separately, as rounding an aggregate fee could require a
+	// slightly higher fee than the sender expects. An inbound discount
+	// never makes the total fee negative.
+	expectedFee := int64(outboundFee) +
+		inboundFee.CalcFee(amtToForward+outboundFee)
+	if expectedFee < 0 {
+		expectedFee = 0
+	}
 
 	// If the actual fee is less than our expected fee, then we'll reject
 	// this HTLC as it didn't provide a sufficient amount of fees, or the
 	// values have been tampered with, or the send used incorrect/dated
 	// information to construct the forwarding information for this hop. In
 	// any case, we'll cancel this HTLC.
-	actualFee := incomingHtlcAmt - amtToForward
+	actualFee := int64(incomingHtlcAmt) - int64(amtToForward)
 	if incomingHtlcAmt < amtToForward || actualFee < expectedFee {
 		l.log.Warnf("outgoing htlc(%x) has insufficient fee: "+
 			"expected %v, got %v",
-			payHash[:], int64(expectedFee), int64(actualFee))
+			payHash[:], expectedFee, actualFee)
 
 		// As part of the returned error, we'll send our latest routing
 		// policy so the sending node obtains the most up to date data.
@@ -2720,6 +2793,13 @@ func (l *channelLink) processRemoteAdds(fwdPkg *channeldb.FwdPkg,
 	l.log.Tracef("processing %d remote adds for height %d",
 		len(lockedInHtlcs), fwdPkg.Height)
 
+	// The inbound fee of our current policy is attached to all forwarded
+	// htlcs, such that the switch can enforce it together with the fee of
+	// the outgoing link.
+	l.RLock()
+	inboundFee := l.cfg.FwrdingPolicy.InboundFee
+	l.RUnlock()
+
 	decodeReqs := make(
 		[]hop.DecodeHopIteratorRequest, 0, len(lockedInHtlcs),
 	)
@@ -2735,9 +2815,11 @@ func (l *channelLink) processRemoteAdds(fwdPkg *channeldb.FwdPkg,
 			onionReader := bytes.NewReader(pd.OnionBlob)
 
 			req := hop.DecodeHopIteratorRequest{
-				OnionReader:  onionReader,
-				RHash:        pd.RHash[:],
-				IncomingCltv: pd.Timeout,
+				OnionReader:    onionReader,
+				RHash:          pd.RHash[:],
+				IncomingCltv:   pd.Timeout,
+				IncomingAmount: pd.Amount,
+				BlindingPoint:  pd.BlindingPoint,
 			}
 
 			decodeReqs = append(decodeReqs, req)
@@ -2837,7 +2919,18 @@ func (l *channelLink) processRemoteAdds(fwdPkg *channeldb.FwdPkg,
 			// for TLV payloads that also supports injecting invalid
 			// payloads. Deferring this non-trival effort till a
 			// later date
-			failure := lnwire.NewInvalidOnionPayload(failedType, 0)
+			var failure lnwire.FailureMessage
+			failure = lnwire.NewInvalidOnionPayload(failedType, 0)
+
+			// As the introduction node of a blinded route, we
+			// report any problem with the encrypted data as an
+			// invalid blinding to not reveal details of the route.
+			if _, ok := err.(hop.ErrInvalidBlinding); ok {
+				failure = lnwire.NewInvalidOnionBlinding(
+					onionBlob[:],
+				)
+			}
+
 			l.sendHTLCError(
 				pd, NewLinkError(failure), obfuscator, false,
 			)
@@ -2888,9 +2981,10 @@ func (l *channelLink) processRemoteAdds(fwdPkg *channeldb.FwdPkg,
 				// Otherwise, it was already processed, we can
 				// can collect it and continue.
 				addMsg := &lnwire.UpdateAddHTLC{
-					Expiry:      fwdInfo.OutgoingCTLV,
-					Amount:      fwdInfo.AmountToForward,
-					PaymentHash: pd.RHash,
+					Expiry:        fwdInfo.OutgoingCTLV,
+					Amount:        fwdInfo.AmountToForward,
+					PaymentHash:   pd.RHash,
+					BlindingPoint: fwdInfo.NextBlinding,
 				}
 
 				// Finally, we'll encode the onion packet for
@@ -2914,6 +3008,7 @@ func (l *channelLink) processRemoteAdds(fwdPkg *channeldb.FwdPkg,
 					obfuscator:      obfuscator,
 					incomingTimeout: pd.Timeout,
 					outgoingTimeout: fwdInfo.OutgoingCTLV,
+					inboundFee:      inboundFee,
 					customRecords:   pld.CustomRecords(),
 				}
 				switchPackets = append(
@@ -2930,9 +3025,10 @@ func (l *channelLink) processRemoteAdds(fwdPkg *channeldb.FwdPkg,
 			// create the outgoing HTLC using the parameters as
 			// specified in the forwarding info.
 			addMsg := &lnwire.UpdateAddHTLC{
-				Expiry:      fwdInfo.OutgoingCTLV,
-				Amount:      fwdInfo.AmountToForward,
-				PaymentHash: pd.RHash,
+				Expiry:        fwdInfo.OutgoingCTLV,
+				Amount:        fwdInfo.AmountToForward,
+				PaymentHash:   pd.RHash,
+				BlindingPoint: fwdInfo.NextBlinding,
 			}
 
 			// Finally, we'll encode the onion packet for the
@@ -2978,6 +3074,7 @@ func (l *channelLink) processRemoteAdds(fwdPkg *channeldb.FwdPkg,
 					obfuscator:      obfuscator,
 					incomingTimeout: pd.Timeout,
 					outgoingTimeout: fwdInfo.OutgoingCTLV,
+					inboundFee:      inboundFee,
 					customRecords:   pld.CustomRecords(),
 				}
 
@@ -3060,28 +3157,166 @@ func (l *channelLink) processExitHop(pd *lnwallet.PaymentDescriptor,
 		return nil
 	}
 
-	// Notify the invoiceRegistry of the exit hop htlc. If we crash right
-	// after this, this code will be re-executed after restart. We will
-	// receive back a resolution event.
-	invoiceHash := lntypes.Hash(pd.RHash)
-
 	circuitKey := channeldb.CircuitKey{
 		ChanID: l.ShortChanID(),
 		HtlcID: pd.HtlcIndex,
 	}
 
-	event, err := l.cfg.Registry.NotifyExitHopHtlc(
-		invoiceHash, pd.Amount, pd.Timeout, int32(heightNow),
-		circuitKey, l.hodlQueue.ChanIn(), payload,
-	)
-	if err != nil {
-		return err
-	}
-
 	// Create a hodlHtlc struct and decide either resolved now or later.
 	htlc := hodlHtlc{
 		pd:         pd,
 		obfuscator: obfuscator,
+		payload:    payload,
+	}
+
+	// Htlcs that carry a trampoline onion aren't paying one of our
+	// invoices, but ask us to pay the next trampoline node or the final
+	// recipient. If we crash while the outgoing payment is in flight,
+	// the htlc will be offered again after restart.
+	if l.forwardTrampoline(circuitKey, htlc, fwdInfo, heightNow) {
+		l.hodlMap[circuitKey] = htlc
+		return nil
+	}
+
+	// Before handing the htlc to the invoice registry, we offer it to an
+	// external interceptor. If we crash while it is held, it will be
+	// offered again after restart.
+	if l.interceptExitHop(circuitKey, htlc, fwdInfo, heightNow) {
+		l.hodlMap[circuitKey] = htlc
+		return nil
+	}
+
+	return l.notifyExitHop(circuitKey, htlc, heightNow)
+}
+
+// interceptExitHop offers an htlc destined for one of our invoices to an
+// external interceptor. It returns true if the htlc is held by the
+// interceptor.
+func (l *channelLink) interceptExitHop(circuitKey channeldb.CircuitKey,
+	htlc hodlHtlc, fwdInfo hop.ForwardingInfo, heightNow uint32) bool {
+
+	if l.cfg.InterceptExitHop == nil {
+		return false
+	}
+
+	return l.cfg.InterceptExitHop(&interceptedExitHop{
+		packet: InterceptedPacket{
+			IncomingCircuit: circuitKey,
+			OutgoingChanID:  hop.Exit,
+			Hash:            lntypes.Hash(htlc.pd.RHash),
+			OutgoingExpiry:  fwdInfo.OutgoingCTLV,
+			OutgoingAmount:  fwdInfo.AmountToForward,
+			IncomingExpiry:  htlc.pd.Timeout,
+			IncomingAmount:  htlc.pd.Amount,
+			CustomRecords:   htlc.payload.CustomRecords(),
+		},
+		acceptHeight: heightNow,
+		linkQuit:     l.quit,
+		resolutions:  l.hodlQueue.ChanIn(),
+	})
+}
+
+// forwardTrampoline offers an htlc that carries a trampoline onion to the
+// trampoline forwarder. It returns true if the htlc is held by the forwarder.
+func (l *channelLink) forwardTrampoline(circuitKey channeldb.CircuitKey,
+	htlc hodlHtlc, fwdInfo hop.ForwardingInfo, heightNow uint32) bool {
+
+	if l.cfg.ForwardTrampoline == nil {
+		return false
+	}
+
+	payload, ok := htlc.payload.(*hop.Payload)
+	if !ok || payload.TrampolineOnion == nil {
+		return false
+	}
+
+	return l.cfg.ForwardTrampoline(&TrampolineHtlc{
+		InterceptedForward: &interceptedExitHop{
+			packet: InterceptedPacket{
+				IncomingCircuit: circuitKey,
+				OutgoingChanID:  hop.Exit,
+				Hash:            lntypes.Hash(htlc.pd.RHash),
+				OutgoingExpiry:  fwdInfo.OutgoingCTLV,
+				OutgoingAmount:  fwdInfo.AmountToForward,
+				IncomingExpiry:  htlc.pd.Timeout,
+				IncomingAmount:  htlc.pd.Amount,
+				CustomRecords:   payload.CustomRecords(),
+			},
+			acceptHeight: heightNow,
+			linkQuit:     l.quit,
+			resolutions:  l.hodlQueue.ChanIn(),
+		},
+		TrampolineOnion: payload.TrampolineOnion,
+		MPP:             payload.MultiPath(),
+	})
+}
+
+// processExitHopResolution applies the resolution of an intercepted htlc
+// destined for one of our invoices. When this function returns without an
+// error, the commit tx should be updated.
+func (l *channelLink) processExitHopResolution(res *exitHopResolution,
+	htlc hodlHtlc) error {
+
+	switch {
+	case res.resume:
+		l.log.Debugf("resuming intercepted htlc %v", res.circuitKey)
+
+		return l.notifyExitHop(
+			res.circuitKey, htlc, l.cfg.BestHeight(),
+		)
+
+	case res.preimage != nil:
+		l.log.Debugf("interceptor settled htlc %v", res.circuitKey)
+
+		return l.settleHTLC(*res.preimage, htlc.pd)
+
+	case res.failure != nil:
+		l.log.Debugf("interceptor failed htlc %v with %v",
+			res.circuitKey, res.failure)
+
+		l.sendHTLCError(
+			htlc.pd, NewLinkError(res.failure), htlc.obfuscator,
+			true,
+		)
+		return nil
+
+	default:
+		l.log.Debugf("interceptor failed htlc %v with encrypted "+
+			"reason", res.circuitKey)
+
+		reason := htlc.obfuscator.IntermediateEncrypt(res.reason)
+		err := l.channel.FailHTLC(
+			htlc.pd.HtlcIndex, reason, htlc.pd.SourceRef, nil, nil,
+		)
+		if err != nil {
+			return fmt.Errorf("unable to cancel htlc: %v", err)
+		}
+
+		l.cfg.Peer.SendMessage(false, &lnwire.UpdateFailHTLC{
+			ChanID: l.ChanID(),
+			ID:     htlc.pd.HtlcIndex,
+			Reason: reason,
+		})
+		return nil
+	}
+}
+
+// notifyExitHop hands an htlc destined for one of our invoices to the invoice
+// registry.
+func (l *channelLink) notifyExitHop(circuitKey channeldb.CircuitKey,
+	htlc hodlHtlc, heightNow uint32) error {
+
+	// Notify the invoiceRegistry of the exit hop htlc. If we crash right
+	// after this, this code will be re-executed after restart. We will
+	// receive back a resolution event.
+	invoiceHash := lntypes.Hash(htlc.pd.RHash)
+
+	event, err := l.cfg.Registry.NotifyExitHopHtlc(
+		invoiceHash, htlc.pd.Amount, htlc.pd.Timeout, int32(heightNow),
+		circuitKey, l.hodlQueue.ChanIn(), htlc.payload,
+	)
+	if err != nil {
+		return err
 	}
 
 	// If the event is nil, the invoice is being held, so we save payment
@@ -3168,23 +3403,36 @@ func (l *channelLink) forwardBatch(packets ...*htlcPacket) {
 func (l *channelLink) sendHTLCError(pd *lnwallet.PaymentDescriptor,
 	failure *LinkError, e hop.ErrorEncrypter, isReceive bool) {
 
-	reason, err := e.EncryptFirstHop(failure.WireMessage())
-	if err != nil {
-		l.log.Errorf("unable to obfuscate error: %v", err)
-		return
-	}
+	// If the HTLC was received with a blinding point, we are inside of a
+	// blinded route and must not reveal why the HTLC failed. Instead we
+	// fail it as malformed with an invalid blinding, which the
+	// introduction node translates into an error for the sender.
+	if pd.BlindingPoint != nil {
+		l.sendMalformedHTLCError(
+			pd.HtlcIndex, lnwire.CodeInvalidOnionBlinding,
+			pd.OnionBlob, pd.SourceRef,
+		)
+	} else {
+		reason, err := e.EncryptFirstHop(failure.WireMessage())
+		if err != nil {
+			l.log.Errorf("unable to obfuscate error: %v", err)
+			return
+		}
 
-	err = l.channel.FailHTLC(pd.HtlcIndex, reason, pd.SourceRef, nil, nil)
-	if err != nil {
-		l.log.Errorf("unable cancel htlc: %v", err)
-		return
-	}
+		err = l.channel.FailHTLC(
+			pd.HtlcIndex, reason, pd.SourceRef, nil, nil,
+		)
+		if err != nil {
+			l.log.Errorf("unable cancel htlc: %v", err)
+			return
+		}
 
-	l.cfg.Peer.SendMessage(false, &lnwire.UpdateFailHTLC{
-		ChanID: l.ChanID(),
-		ID:     pd.HtlcIndex,
-		Reason: reason,
-	})
+		l.cfg.Peer.SendMessage(false, &lnwire.UpdateFailHTLC{
+			ChanID: l.ChanID(),
+			ID:     pd.HtlcIndex,
+			Reason: reason,
+		})
+	}
 
 	// Notify a link failure on our incoming link. Outgoing htlc information
 	// is not available at this point, because we have not decrypted the
@@ -5568,6 +5568,47 @@ func TestChannelLinkFail(t *testing.T) {
 			true,
 			false,
 		},
+		{
+			// Test that we don't force close the channel, but
+			// fail the link before revoking our prior commitment,
+			// if we're unable to backup our new commitment.
+			func(c *channelLink) {
+				c.cfg.BackupCommitState = func(
+					wire.OutPoint) error {
+
+					return fmt.Errorf("backup failed")
+				}
+			},
+			func(t *testing.T, c *channelLink, remoteChannel *lnwallet.LightningChannel) {
+
+				// Generate an HTLC and send to the link.
+				htlc1 := generateHtlc(t, c, 0)
+				ctx := linkTestContext{
+					t:          t,
+					aliceLink:  c,
+					bobChannel: remoteChannel,
+				}
+				ctx.sendHtlcBobToAlice(htlc1)
+
+				// Sign a valid commitment that will include
+				// the HTLC just sent.
+				sig, htlcSigs, _, err :=
+					remoteChannel.SignNextCommitment()
+				if err != nil {
+					t.Fatalf("error signing commitment: %v",
+						err)
+				}
+
+				commitSig := &lnwire.CommitSig{
+					CommitSig: sig,
+					HtlcSigs:  htlcSigs,
+				}
+
+				c.HandleChannelUpdate(commitSig)
+			},
+			false,
+			false,
+		},
 		{
 			// Test that we consider the failure permanent if we
 			// receive a link error from the remote.
@@ -5788,7 +5829,7 @@ func TestCheckHtlcForward(t *testing.T) {
 
 	t.Run("satisfied", func(t *testing.T) {
 		result := link.CheckHtlcForward(hash, 1500, 1000,
-			200, 150, 0)
+			200, 150, lnwire.InboundFee{}, 0)
 		if result != nil {
 			t.Fatalf("expected policy to be satisfied")
 		}
@@ -5796,7 +5837,7 @@ func TestCheckHtlcForward(t *testing.T) {
 
 	t.Run("below minhtlc", func(t *testing.T) {
 		result := link.CheckHtlcForward(hash, 100, 50,
-			200, 150, 0)
+			200, 150, lnwire.InboundFee{}, 0)
 		if _, ok := result.WireMessage().(*lnwire.FailAmountBelowMinimum); !ok {
 			t.Fatalf("expected FailAmountBelowMinimum failure code")
 		}
@@ -5804,7 +5845,7 @@ func TestCheckHtlcForward(t *testing.T) {
 
 	t.Run("above maxhtlc", func(t *testing.T) {
 		result := link.CheckHtlcForward(hash, 1500, 1200,
-			200, 150, 0)
+			200, 150, lnwire.InboundFee{}, 0)
 		if _, ok := result.WireMessage().(*lnwire.FailTemporaryChannelFailure); !ok {
 			t.Fatalf("expected FailTemporaryChannelFailure failure code")
 		}
@@ -5812,7 +5853,40 @@ func TestCheckHtlcForward(t *testing.T) {
 
 	t.Run("insufficient fee", func(t *testing.T) {
 		result := link.CheckHtlcForward(hash, 1005, 1000,
-			200, 150, 0)
+			200, 150, lnwire.InboundFee{}, 0)
+		if _, ok := result.WireMessage().(*lnwire.FailFeeInsufficient); !ok {
+			t.Fatalf("expected FailFeeInsufficient failure code")
+		}
+	})
+
+	t.Run("insufficient inbound fee", func(t *testing.T) {
+		inboundFee := lnwire.InboundFee{
+			BaseFee: 5,
+		}
+		result := link.CheckHtlcForward(hash, 1010, 1000,
+			200, 150, inboundFee, 0)
+		if _, ok := result.WireMessage().(*lnwire.FailFeeInsufficient); !ok {
+			t.Fatalf("expected FailFeeInsufficient failure code")
+		}
+	})
+
+	t.Run("inbound discount", func(t *testing.T) {
+		inboundFee := lnwire.InboundFee{
+			BaseFee: -10,
+		}
+		result := link.CheckHtlcForward(hash, 1000, 1000,
+			200, 150, inboundFee, 0)
+		if result != nil {
+			t.Fatalf("expected policy to be satisfied")
+		}
+	})
+
+	t.Run("negative total fee", func(t *testing.T) {
+		inboundFee := lnwire.InboundFee{
+			BaseFee: -20,
+		}
+		result := link.CheckHtlcForward(hash, 995, 1000,
+			200, 150, inboundFee, 0)
 		if _, ok := result.WireMessage().(*lnwire.FailFeeInsufficient); !ok {
 			t.Fatalf("expected FailFeeInsufficient failure code")
 		}
@@ -5820,7 +5894,7 @@ func TestCheckHtlcForward(t *testing.T) {
 
 	t.Run("expiry too soon", func(t *testing.T) {
 		result := link.CheckHtlcForward(hash, 1500, 1000,
-			200, 150, 190)
+			200, 150, lnwire.InboundFee{}, 190)
 		if _, ok := result.WireMessage().(*lnwire.FailExpiryTooSoon); !ok {
 			t.Fatalf("expected FailExpiryTooSoon failure code")
 		}
@@ -5828,7 +5902,7 @@ func TestCheckHtlcForward(t *testing.T) {
 
 	t.Run("incorrect cltv expiry", func(t *testing.T) {
 		result := link.CheckHtlcForward(hash, 1500, 1000,
-			200, 190, 0)
+			200, 190, lnwire.InboundFee{}, 0)
 		if _, ok := result.WireMessage().(*lnwire.FailIncorrectCltvExpiry); !ok {
 			t.Fatalf("expected FailIncorrectCltvExpiry failure code")
 		}
@@ -5838,7 +5912,7 @@ func TestCheckHtlcForward(t *testing.T) {
 	t.Run("cltv expiry too far in the future", func(t *testing.T) {
 		// Check that expiry isn't too far in the future.
 		result := link.CheckHtlcForward(hash, 1500, 1000,
-			10200, 10100, 0)
+			10200, 10100, lnwire.InboundFee{}, 0)
 		if _, ok := result.WireMessage().(*lnwire.FailExpiryTooFar); !ok {
 			t.Fatalf("expected FailExpiryTooFar failure code")
 		}
@@ -749,7 +749,8 @@ func (f *mockChannelLink) HandleChannelUpdate(lnwire.Message) {
 func (f *mockChannelLink) UpdateForwardingPolicy(_ ForwardingPolicy) {
 }
 func (f *mockChannelLink) CheckHtlcForward([32]byte, lnwire.MilliBronees,
-	lnwire.MilliBronees, uint32, uint32, uint32) *LinkError {
+	lnwire.MilliBronees, uint32, uint32, lnwire.InboundFee,
+	uint32) *LinkError {
 
 	return f.checkHtlcForwardResult
 }
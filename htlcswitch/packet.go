@@ -93,6 +93,11 @@ type htlcPacket struct {
 	// link.
 	outgoingTimeout uint32
 
+	// inboundFee is the inbound fee of the incoming link at the time the
+	// HTLC was accepted. It must be paid on top of the fee of the
+	// outgoing link.
+	inboundFee lnwire.InboundFee
+
 	// customRecords are user-defined records in the custom type range that
 	// were included in the payload.
 	customRecords record.CustomSet
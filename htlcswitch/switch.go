@@ -200,6 +200,11 @@ type Config struct {
 	// DustThreshold is the threshold in milli-broneess after which we'll
 	// fail incoming or outgoing dust payments for a particular channel.
 	DustThreshold lnwire.MilliBronees
+
+	// FindBaseScid maps an alias SCID to the base SCID of its channel. If
+	// set, it is used to look up links by any of their aliases.
+	FindBaseScid func(lnwire.ShortChannelID) (lnwire.ShortChannelID,
+		error)
 }
 
 // Switch is the central messaging bus for all incoming/outgoing HTLCs.
@@ -233,6 +238,10 @@ type Switch struct {
 	// results might be overwritten.
 	networkResults *networkResultStore
 
+	// heldForwards stores the htlcs that are held by an interceptor, such
+	// that they are held again when they are replayed after a restart.
+	heldForwards *heldForwardStore
+
 	// circuits is storage for payment circuits which are used to
 	// forward the settle/fail htlc updates back to the add htlc initiator.
 	circuits CircuitMap
@@ -311,6 +320,13 @@ func New(cfg Config, currentHeight uint32) (*Switch, error) {
 		return nil, err
 	}
 
+	heldForwards, err := newHeldForwardStore(
+		cfg.DB, cfg.Clock, cfg.FetchAllOpenChannels,
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	s := &Switch{
 		bestHeight:        currentHeight,
 		cfg:               &cfg,
@@ -320,6 +336,7 @@ func New(cfg Config, currentHeight uint32) (*Switch, error) {
 		interfaceIndex:    make(map[[33]byte]map[lnwire.ChannelID]ChannelLink),
 		pendingLinkIndex:  make(map[lnwire.ChannelID]ChannelLink),
 		networkResults:    newNetworkResultStore(cfg.DB),
+		heldForwards:      heldForwards,
 		htlcPlex:          make(chan *plexPacket),
 		chanCloseRequests: make(chan *ChanClose),
 		resolutionMsgs:    make(chan *resolutionMsg),
@@ -590,6 +607,22 @@ func (s *Switch) IsForwardedHTLC(chanID lnwire.ShortChannelID,
 	return circuit != nil && circuit.Incoming.ChanID != hop.Source
 }
 
+// QueryIncomingCircuit returns the circuit key of the incoming HTLC of the
+// forward that the outgoing HTLC identified by the given circuit key belongs
+// to. Nil is returned if there is no open circuit for the outgoing HTLC, or if
+// it belongs to a locally initiated payment.
+func (s *Switch) QueryIncomingCircuit(
+	circuit channeldb.CircuitKey) *channeldb.CircuitKey {
+
+	openCircuit := s.circuits.LookupOpenCircuit(circuit)
+	if openCircuit == nil || openCircuit.Incoming.ChanID == hop.Source {
+		return nil
+	}
+
+	incoming := openCircuit.Incoming
+	return &incoming
+}
+
 // ForwardPackets adds a list of packets to the switch for processing. Fails
 // and settles are added on a first past, simultaneously constructing circuits
 // for any adds. After persisting the circuits, another pass of the adds is
@@ -1081,7 +1114,8 @@ func (s *Switch) handlePacketForward(packet *htlcPacket) error {
 				failure = link.CheckHtlcForward(
 					htlc.PaymentHash, packet.incomingAmount,
 					packet.amount, packet.incomingTimeout,
-					packet.outgoingTimeout, currentHeight,
+					packet.outgoingTimeout,
+					packet.inboundFee, currentHeight,
 				)
 			}
 
@@ -2150,6 +2184,22 @@ func (s *Switch) GetLinkByShortID(chanID lnwire.ShortChannelID) (ChannelLink,
 // NOTE: This MUST be called with the indexMtx held.
 func (s *Switch) getLinkByShortID(chanID lnwire.ShortChannelID) (ChannelLink, error) {
 	link, ok := s.forwardingIndex[chanID]
+	if ok {
+		return link, nil
+	}
+
+	// The SCID might be an alias of a channel, in which case the link is
+	// indexed by the channel's base SCID.
+	if s.cfg.FindBaseScid == nil {
+		return nil, ErrChannelLinkNotFound
+	}
+
+	baseScid, err := s.cfg.FindBaseScid(chanID)
+	if err != nil {
+		return nil, ErrChannelLinkNotFound
+	}
+
+	link, ok = s.forwardingIndex[baseScid]
 	if !ok {
 		return nil, ErrChannelLinkNotFound
 	}
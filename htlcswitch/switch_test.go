@@ -7,12 +7,15 @@ import (
 	"io"
 	"io/ioutil"
 	"reflect"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/brronsuite/broln/chainntnfs"
 	"github.com/brronsuite/broln/channeldb"
 	"github.com/brronsuite/broln/htlcswitch/hodl"
 	"github.com/brronsuite/broln/htlcswitch/hop"
+	"github.com/brronsuite/broln/lntest/mock"
 	"github.com/brronsuite/broln/lntypes"
 	"github.com/brronsuite/broln/lnwire"
 	"github.com/brronsuite/broln/ticker"
@@ -3160,6 +3163,18 @@ func (m *mockForwardInterceptor) resume() error {
 	return m.intercepted.Resume()
 }
 
+func (m *mockForwardInterceptor) failWithMessage(
+	failure lnwire.FailureMessage) error {
+
+	return m.intercepted.FailWithMessage(failure)
+}
+
+func (m *mockForwardInterceptor) failWithReason(
+	reason lnwire.OpaqueReason) error {
+
+	return m.intercepted.FailWithReason(reason)
+}
+
 func assertNumCircuits(t *testing.T, s *Switch, pending, opened int) {
 	if s.circuits.NumPending() != pending {
 		t.Fatal("wrong amount of half circuits")
@@ -3259,7 +3274,11 @@ func TestSwitchHoldForward(t *testing.T) {
 	}
 
 	forwardInterceptor := &mockForwardInterceptor{}
-	switchForwardInterceptor := NewInterceptableSwitch(s)
+	switchForwardInterceptor := NewInterceptableSwitch(
+		&InterceptableSwitchConfig{
+			Switch: s,
+		},
+	)
 	switchForwardInterceptor.SetInterceptor(forwardInterceptor.InterceptForwardHtlc)
 	linkQuit := make(chan struct{})
 
@@ -3306,6 +3325,38 @@ func TestSwitchHoldForward(t *testing.T) {
 	assertOutgoingLinkReceive(t, aliceChannelLink, true)
 	assertNumCircuits(t, s, 0, 0)
 
+	// Test failing a hold forward with a custom failure message
+	if err := switchForwardInterceptor.ForwardPackets(linkQuit, ogPacket); err != nil {
+		t.Fatalf("can't forward htlc packet: %v", err)
+	}
+	assertNumCircuits(t, s, 0, 0)
+	assertOutgoingLinkReceive(t, bobChannelLink, false)
+
+	err = forwardInterceptor.failWithMessage(
+		lnwire.NewTemporaryNodeFailure(),
+	)
+	if err != nil {
+		t.Fatalf("failed to cancel forward %v", err)
+	}
+	assertOutgoingLinkReceive(t, bobChannelLink, false)
+	assertOutgoingLinkReceive(t, aliceChannelLink, true)
+	assertNumCircuits(t, s, 0, 0)
+
+	// Test failing a hold forward with an encrypted failure
+	if err := switchForwardInterceptor.ForwardPackets(linkQuit, ogPacket); err != nil {
+		t.Fatalf("can't forward htlc packet: %v", err)
+	}
+	assertNumCircuits(t, s, 0, 0)
+	assertOutgoingLinkReceive(t, bobChannelLink, false)
+
+	err = forwardInterceptor.failWithReason(lnwire.OpaqueReason{1, 2, 3})
+	if err != nil {
+		t.Fatalf("failed to cancel forward %v", err)
+	}
+	assertOutgoingLinkReceive(t, bobChannelLink, false)
+	assertOutgoingLinkReceive(t, aliceChannelLink, true)
+	assertNumCircuits(t, s, 0, 0)
+
 	// Test settling a hold forward
 	if err := switchForwardInterceptor.ForwardPackets(linkQuit, ogPacket); err != nil {
 		t.Fatalf("can't forward htlc packet: %v", err)
@@ -3321,6 +3372,223 @@ func TestSwitchHoldForward(t *testing.T) {
 	assertNumCircuits(t, s, 0, 0)
 }
 
+// TestSwitchHoldForwardRequired tests that forwards are held while no
+// interceptor is set if an interceptor is required, and that they are offered
+// to the next interceptor that is set.
+func TestSwitchHoldForwardRequired(t *testing.T) {
+	t.Parallel()
+
+	chanID1, chanID2, aliceChanID, bobChanID := genIDs()
+
+	alicePeer, err := newMockServer(
+		t, "alice", testStartingHeight, nil, testDefaultDelta,
+	)
+	require.NoError(t, err)
+	bobPeer, err := newMockServer(
+		t, "bob", testStartingHeight, nil, testDefaultDelta,
+	)
+	require.NoError(t, err)
+
+	tempPath, err := ioutil.TempDir("", "circuitdb")
+	require.NoError(t, err)
+
+	cdb, err := channeldb.Open(tempPath)
+	require.NoError(t, err)
+
+	s, err := initSwitchWithDB(testStartingHeight, cdb)
+	require.NoError(t, err)
+	require.NoError(t, s.Start())
+	defer func() {
+		require.NoError(t, s.Stop())
+	}()
+
+	aliceChannelLink := newMockChannelLink(
+		s, chanID1, aliceChanID, alicePeer, true,
+	)
+	bobChannelLink := newMockChannelLink(
+		s, chanID2, bobChanID, bobPeer, true,
+	)
+	require.NoError(t, s.AddLink(aliceChannelLink))
+	require.NoError(t, s.AddLink(bobChannelLink))
+
+	preimage := [sha256.Size]byte{1}
+	rhash := sha256.Sum256(preimage[:])
+	ogPacket := &htlcPacket{
+		incomingChanID: aliceChannelLink.ShortChanID(),
+		incomingHTLCID: 0,
+		outgoingChanID: bobChannelLink.ShortChanID(),
+		obfuscator:     NewMockObfuscator(),
+		htlc: &lnwire.UpdateAddHTLC{
+			PaymentHash: rhash,
+			Amount:      1,
+		},
+	}
+
+	switchForwardInterceptor := NewInterceptableSwitch(
+		&InterceptableSwitchConfig{
+			Switch:             s,
+			RequireInterceptor: true,
+		},
+	)
+	linkQuit := make(chan struct{})
+
+	// Without an interceptor, the forward must be held instead of being
+	// resumed.
+	err = switchForwardInterceptor.ForwardPackets(linkQuit, ogPacket)
+	require.NoError(t, err)
+	assertOutgoingLinkReceive(t, bobChannelLink, false)
+	assertNumCircuits(t, s, 0, 0)
+
+	// Once an interceptor is set, the held forward is offered to it.
+	intercepted := make(chan InterceptedForward, 1)
+	switchForwardInterceptor.SetInterceptor(
+		func(fwd InterceptedForward) bool {
+			intercepted <- fwd
+			return true
+		},
+	)
+
+	var fwd InterceptedForward
+	select {
+	case fwd = <-intercepted:
+	case <-time.After(time.Second):
+		t.Fatal("held forward not offered to interceptor")
+	}
+	require.Equal(
+		t, ogPacket.inKey(), fwd.Packet().IncomingCircuit,
+	)
+
+	// If the interceptor releases the forward after it is removed, the
+	// forward is held again.
+	switchForwardInterceptor.SetInterceptor(nil)
+	require.NoError(t, switchForwardInterceptor.Release(fwd))
+	assertOutgoingLinkReceive(t, bobChannelLink, false)
+
+	switchForwardInterceptor.SetInterceptor(
+		func(fwd InterceptedForward) bool {
+			intercepted <- fwd
+			return true
+		},
+	)
+
+	select {
+	case fwd = <-intercepted:
+	case <-time.After(time.Second):
+		t.Fatal("held forward not offered to interceptor")
+	}
+
+	// Finally, resuming the forward lands it at bob's link.
+	require.NoError(t, fwd.Resume())
+	assertOutgoingLinkReceive(t, bobChannelLink, true)
+	assertNumCircuits(t, s, 1, 1)
+}
+
+// TestSwitchHoldForwardExpiry tests that a forward that is declined by the
+// interceptor is held instead of being offered to it again, and that held
+// forwards are failed back once their incoming htlc is about to expire.
+func TestSwitchHoldForwardExpiry(t *testing.T) {
+	t.Parallel()
+
+	chanID1, chanID2, aliceChanID, bobChanID := genIDs()
+
+	alicePeer, err := newMockServer(
+		t, "alice", testStartingHeight, nil, testDefaultDelta,
+	)
+	require.NoError(t, err)
+	bobPeer, err := newMockServer(
+		t, "bob", testStartingHeight, nil, testDefaultDelta,
+	)
+	require.NoError(t, err)
+
+	tempPath, err := ioutil.TempDir("", "circuitdb")
+	require.NoError(t, err)
+
+	cdb, err := channeldb.Open(tempPath)
+	require.NoError(t, err)
+
+	s, err := initSwitchWithDB(testStartingHeight, cdb)
+	require.NoError(t, err)
+	require.NoError(t, s.Start())
+	defer func() {
+		require.NoError(t, s.Stop())
+	}()
+
+	aliceChannelLink := newMockChannelLink(
+		s, chanID1, aliceChanID, alicePeer, true,
+	)
+	bobChannelLink := newMockChannelLink(
+		s, chanID2, bobChanID, bobPeer, true,
+	)
+	require.NoError(t, s.AddLink(aliceChannelLink))
+	require.NoError(t, s.AddLink(bobChannelLink))
+
+	const cltvRejectDelta = 10
+	incomingExpiry := uint32(testStartingHeight + 20)
+
+	preimage := [sha256.Size]byte{1}
+	rhash := sha256.Sum256(preimage[:])
+	ogPacket := &htlcPacket{
+		incomingChanID:  aliceChannelLink.ShortChanID(),
+		incomingHTLCID:  0,
+		outgoingChanID:  bobChannelLink.ShortChanID(),
+		incomingTimeout: incomingExpiry,
+		obfuscator:      NewMockObfuscator(),
+		htlc: &lnwire.UpdateAddHTLC{
+			PaymentHash: rhash,
+			Amount:      1,
+		},
+	}
+
+	epochChan := make(chan *chainntnfs.BlockEpoch)
+	switchForwardInterceptor := NewInterceptableSwitch(
+		&InterceptableSwitchConfig{
+			Switch: s,
+			Notifier: &mock.ChainNotifier{
+				EpochChan: epochChan,
+			},
+			CltvRejectDelta:    cltvRejectDelta,
+			RequireInterceptor: true,
+		},
+	)
+	require.NoError(t, switchForwardInterceptor.Start())
+	defer func() {
+		require.NoError(t, switchForwardInterceptor.Stop())
+	}()
+
+	// Set an interceptor that declines all forwards, as it does while
+	// shutting down.
+	var offered int32
+	switchForwardInterceptor.SetInterceptor(
+		func(fwd InterceptedForward) bool {
+			atomic.AddInt32(&offered, 1)
+			return false
+		},
+	)
+
+	// The declined forward must be held, without being offered to the
+	// same interceptor again.
+	linkQuit := make(chan struct{})
+	err = switchForwardInterceptor.ForwardPackets(linkQuit, ogPacket)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, atomic.LoadInt32(&offered))
+	assertOutgoingLinkReceive(t, bobChannelLink, false)
+
+	// A block that doesn't bring the incoming htlc within the reject delta
+	// keeps the forward held.
+	epochChan <- &chainntnfs.BlockEpoch{
+		Height: int32(incomingExpiry - cltvRejectDelta - 1),
+	}
+	assertOutgoingLinkReceive(t, aliceChannelLink, false)
+
+	// Once the incoming htlc is within the reject delta, the forward is
+	// failed back.
+	epochChan <- &chainntnfs.BlockEpoch{
+		Height: int32(incomingExpiry - cltvRejectDelta),
+	}
+	assertOutgoingLinkReceive(t, aliceChannelLink, true)
+	assertOutgoingLinkReceive(t, bobChannelLink, false)
+}
+
 // TestSwitchDustForwarding tests that the switch properly fails HTLC's which
 // have incoming or outgoing links that breach their dust thresholds.
 func TestSwitchDustForwarding(t *testing.T) {
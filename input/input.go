@@ -38,10 +38,12 @@ type Input interface {
 	// CraftInputScript returns a valid set of input scripts allowing this
 	// output to be spent. The returns input scripts should target the
 	// input at location txIndex within the passed transaction. The input
-	// scripts generated by this method support spending p2wkh, p2wsh, and
-	// also nested p2sh outputs.
+	// scripts generated by this method support spending p2wkh, p2wsh,
+	// nested p2sh and p2tr key spend outputs. The prevOutputFetcher must
+	// return the previous outputs of all inputs of the transaction.
 	CraftInputScript(signer Signer, txn *wire.MsgTx,
 		hashCache *txscript.TxSigHashes,
+		prevOutputFetcher txscript.PrevOutputFetcher,
 		txinIdx int) (*Script, error)
 
 	// BlocksToMaturity returns the relative timelock, as a number of
@@ -219,11 +221,16 @@ func NewCsvInputWithCltv(outpoint *wire.OutPoint, witnessType WitnessType,
 // CraftInputScript returns a valid set of input scripts allowing this output
 // to be spent. The returned input scripts should target the input at location
 // txIndex within the passed transaction. The input scripts generated by this
-// method support spending p2wkh, p2wsh, and also nested p2sh outputs.
+// method support spending p2wkh, p2wsh, nested p2sh and p2tr key spend
+// outputs.
 func (bi *BaseInput) CraftInputScript(signer Signer, txn *wire.MsgTx,
-	hashCache *txscript.TxSigHashes, txinIdx int) (*Script, error) {
+	hashCache *txscript.TxSigHashes,
+	prevOutputFetcher txscript.PrevOutputFetcher,
+	txinIdx int) (*Script, error) {
 
-	witnessFunc := bi.witnessType.WitnessGenerator(signer, bi.SignDesc())
+	signDesc := *bi.SignDesc()
+	signDesc.PrevOutputFetcher = prevOutputFetcher
+	witnessFunc := bi.witnessType.WitnessGenerator(signer, &signDesc)
 
 	return witnessFunc(txn, hashCache, txinIdx)
 }
@@ -260,10 +267,13 @@ func MakeHtlcSucceedInput(outpoint *wire.OutPoint,
 // txIndex within the passed transaction. The input scripts generated by this
 // method support spending p2wkh, p2wsh, and also nested p2sh outputs.
 func (h *HtlcSucceedInput) CraftInputScript(signer Signer, txn *wire.MsgTx,
-	hashCache *txscript.TxSigHashes, txinIdx int) (*Script, error) {
+	hashCache *txscript.TxSigHashes,
+	prevOutputFetcher txscript.PrevOutputFetcher,
+	txinIdx int) (*Script, error) {
 
 	desc := h.signDesc
 	desc.SigHashes = hashCache
+	desc.PrevOutputFetcher = prevOutputFetcher
 	desc.InputIndex = txinIdx
 
 	witness, err := SenderHtlcSpendRedeem(
@@ -291,7 +301,9 @@ type HtlcSecondLevelAnchorInput struct {
 	// createWitness creates a witness allowing the passed transaction to
 	// spend the input.
 	createWitness func(signer Signer, txn *wire.MsgTx,
-		hashCache *txscript.TxSigHashes, txinIdx int) (wire.TxWitness, error)
+		hashCache *txscript.TxSigHashes,
+		prevOutputFetcher txscript.PrevOutputFetcher,
+		txinIdx int) (wire.TxWitness, error)
 }
 
 // RequiredTxOut returns the tx out needed to be present on the sweep tx for
@@ -313,9 +325,12 @@ func (i *HtlcSecondLevelAnchorInput) RequiredLockTime() (uint32, bool) {
 // method support spending p2wkh, p2wsh, and also nested p2sh outputs.
 func (i *HtlcSecondLevelAnchorInput) CraftInputScript(signer Signer,
 	txn *wire.MsgTx, hashCache *txscript.TxSigHashes,
+	prevOutputFetcher txscript.PrevOutputFetcher,
 	txinIdx int) (*Script, error) {
 
-	witness, err := i.createWitness(signer, txn, hashCache, txinIdx)
+	witness, err := i.createWitness(
+		signer, txn, hashCache, prevOutputFetcher, txinIdx,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -335,10 +350,12 @@ func MakeHtlcSecondLevelTimeoutAnchorInput(signedTx *wire.MsgTx,
 	// 2nd timeout transaction.
 	createWitness := func(signer Signer, txn *wire.MsgTx,
 		hashCache *txscript.TxSigHashes,
+		prevOutputFetcher txscript.PrevOutputFetcher,
 		txinIdx int) (wire.TxWitness, error) {
 
 		desc := signDetails.SignDesc
-		desc.SigHashes = txscript.NewTxSigHashes(txn)
+		desc.SigHashes = txscript.NewTxSigHashes(txn, prevOutputFetcher)
+		desc.PrevOutputFetcher = prevOutputFetcher
 		desc.InputIndex = txinIdx
 
 		return SenderHtlcSpendTimeout(
@@ -373,10 +390,12 @@ func MakeHtlcSecondLevelSuccessAnchorInput(signedTx *wire.MsgTx,
 	// success transaction.
 	createWitness := func(signer Signer, txn *wire.MsgTx,
 		hashCache *txscript.TxSigHashes,
+		prevOutputFetcher txscript.PrevOutputFetcher,
 		txinIdx int) (wire.TxWitness, error) {
 
 		desc := signDetails.SignDesc
 		desc.SigHashes = hashCache
+		desc.PrevOutputFetcher = prevOutputFetcher
 		desc.InputIndex = txinIdx
 
 		return ReceiverHtlcSpendRedeem(
@@ -0,0 +1,170 @@
+package input
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/brronsuite/broln/keychain"
+	"github.com/brronsuite/brond/bronec"
+	"github.com/brronsuite/brond/bronec/schnorr"
+	"github.com/brronsuite/brond/bronec/schnorr/musig2"
+	"github.com/stretchr/testify/require"
+)
+
+// singleKeyRing is a secret key ring that only knows a single private key that
+// is returned for every key descriptor.
+type singleKeyRing struct {
+	keychain.SecretKeyRing
+
+	privKey *bronec.PrivateKey
+}
+
+// DerivePrivKey returns the single private key of the key ring.
+func (s *singleKeyRing) DerivePrivKey(
+	_ keychain.KeyDescriptor) (*bronec.PrivateKey, error) {
+
+	return s.privKey, nil
+}
+
+// TestMuSig2SessionManager tests that two session managers can create a valid
+// MuSig2 signature for a BIP-0086 tweaked combined key.
+func TestMuSig2SessionManager(t *testing.T) {
+	t.Parallel()
+
+	alicePriv, _ := bronec.PrivKeyFromBytes(
+		bronec.S256(), testWalletPrivKey,
+	)
+	bobPriv, _ := bronec.PrivKeyFromBytes(bronec.S256(), bobsPrivKey)
+	allSignerPubKeys := []*bronec.PublicKey{
+		alicePriv.PubKey(), bobPriv.PubKey(),
+	}
+	tweaks := &MuSig2Tweaks{
+		TaprootBIP0086Tweak: true,
+	}
+
+	alice := NewMusigSessionManager(&singleKeyRing{privKey: alicePriv})
+	bob := NewMusigSessionManager(&singleKeyRing{privKey: bobPriv})
+
+	aliceSession, err := alice.MuSig2CreateSession(
+		keychain.KeyLocator{}, allSignerPubKeys, tweaks, nil,
+	)
+	require.NoError(t, err)
+	require.False(t, aliceSession.HaveAllNonces)
+	require.True(t, aliceSession.TaprootTweak)
+	require.NotNil(t, aliceSession.TaprootInternalKey)
+
+	// Bob already knows Alice's nonce when creating his session.
+	bobSession, err := bob.MuSig2CreateSession(
+		keychain.KeyLocator{}, allSignerPubKeys, tweaks,
+		[][musig2.PubNonceSize]byte{aliceSession.PublicNonce},
+	)
+	require.NoError(t, err)
+	require.True(t, bobSession.HaveAllNonces)
+
+	// Both parties must arrive at the same combined key, which must also
+	// match the key computed without a session.
+	require.Equal(
+		t, schnorr.SerializePubKey(aliceSession.CombinedKey),
+		schnorr.SerializePubKey(bobSession.CombinedKey),
+	)
+	combinedKey, err := MuSig2CombineKeys(allSignerPubKeys, tweaks)
+	require.NoError(t, err)
+	require.Equal(
+		t, schnorr.SerializePubKey(aliceSession.CombinedKey),
+		schnorr.SerializePubKey(combinedKey.FinalKey),
+	)
+
+	// Alice can't sign before she knows Bob's nonce.
+	msg := sha256.Sum256([]byte("musig2 test message"))
+	_, err = alice.MuSig2Sign(aliceSession.SessionID, msg, false)
+	require.Error(t, err)
+
+	haveAll, err := alice.MuSig2RegisterNonces(
+		aliceSession.SessionID,
+		[][musig2.PubNonceSize]byte{bobSession.PublicNonce},
+	)
+	require.NoError(t, err)
+	require.True(t, haveAll)
+
+	// Bob signs and cleans up his session, as Alice is responsible for
+	// combining the signatures.
+	bobSig, err := bob.MuSig2Sign(bobSession.SessionID, msg, true)
+	require.NoError(t, err)
+	require.Error(t, bob.MuSig2Cleanup(bobSession.SessionID))
+
+	// The partial signature must survive a serialization round trip.
+	bobSigBytes, err := SerializePartialSignature(bobSig)
+	require.NoError(t, err)
+	bobSig, err = DeserializePartialSignature(bobSigBytes[:])
+	require.NoError(t, err)
+
+	_, err = alice.MuSig2Sign(aliceSession.SessionID, msg, false)
+	require.NoError(t, err)
+
+	finalSig, haveAllSigs, err := alice.MuSig2CombineSig(
+		aliceSession.SessionID, []*musig2.PartialSignature{bobSig},
+	)
+	require.NoError(t, err)
+	require.True(t, haveAllSigs)
+	require.True(t, finalSig.Verify(msg[:], aliceSession.CombinedKey))
+
+	// The session is removed once all signatures were combined.
+	require.Error(t, alice.MuSig2Cleanup(aliceSession.SessionID))
+}
+
+// TestMuSig2TweakCombination tests that generic tweaks can't be combined with
+// a taproot tweak, as they can't be applied together.
+func TestMuSig2TweakCombination(t *testing.T) {
+	t.Parallel()
+
+	alicePriv, _ := bronec.PrivKeyFromBytes(
+		bronec.S256(), testWalletPrivKey,
+	)
+	bobPriv, _ := bronec.PrivKeyFromBytes(bronec.S256(), bobsPrivKey)
+
+	allSignerPubKeys := []*bronec.PublicKey{
+		alicePriv.PubKey(), bobPriv.PubKey(),
+	}
+	genericTweaks := []musig2.KeyTweakDesc{{
+		Tweak:   [32]byte{1},
+		IsXOnly: true,
+	}}
+
+	testCases := []struct {
+		name   string
+		tweaks *MuSig2Tweaks
+	}{
+		{
+			name: "bip86 tweak",
+			tweaks: &MuSig2Tweaks{
+				GenericTweaks:       genericTweaks,
+				TaprootBIP0086Tweak: true,
+			},
+		},
+		{
+			name: "taproot tweak",
+			tweaks: &MuSig2Tweaks{
+				GenericTweaks: genericTweaks,
+				TaprootTweak:  []byte{2},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := MuSig2CombineKeys(allSignerPubKeys, tc.tweaks)
+			require.ErrorIs(t, err, ErrMuSig2TweakCombination)
+
+			alice := NewMusigSessionManager(
+				&singleKeyRing{privKey: alicePriv},
+			)
+			_, err = alice.MuSig2CreateSession(
+				keychain.KeyLocator{}, allSignerPubKeys,
+				tc.tweaks, nil,
+			)
+			require.ErrorIs(t, err, ErrMuSig2TweakCombination)
+		})
+	}
+}
@@ -847,6 +847,16 @@ func LeaseSecondLevelHtlcScript(revocationKey, delayKey *bronec.PublicKey,
 	return builder.Script()
 }
 
+// NewTxSigHashesV0Only returns a new txscript.TxSigHashes instance that will
+// only calculate the sighash midstate values for segwit v0 inputs and can
+// therefore never be used for transactions that want to spend taproot inputs.
+func NewTxSigHashesV0Only(tx *wire.MsgTx) *txscript.TxSigHashes {
+	// The canned output fetcher returns a wire.TxOut that indicates it's
+	// not a taproot output, so no taproot midstate is calculated.
+	nilFetcher := txscript.NewCannedPrevOutputFetcher(nil, 0)
+	return txscript.NewTxSigHashes(tx, nilFetcher)
+}
+
 // HtlcSpendSuccess spends a second-level HTLC output. This function is to be
 // used by the sender of an HTLC to claim the output after a relative timeout
 // or the receiver of the HTLC to claim on-chain with the pre-image.
@@ -864,7 +874,7 @@ func HtlcSpendSuccess(signer Signer, signDesc *SignDescriptor,
 
 	// As we mutated the transaction, we'll re-calculate the sighashes for
 	// this instance.
-	signDesc.SigHashes = txscript.NewTxSigHashes(sweepTx)
+	signDesc.SigHashes = NewTxSigHashesV0Only(sweepTx)
 
 	// With the proper sequence and version set, we'll now sign the timeout
 	// transaction using the passed signed descriptor. In order to generate
@@ -283,7 +283,7 @@ func TestHTLCSenderSpendValidation(t *testing.T) {
 			},
 		)
 
-		sweepTxSigHashes = txscript.NewTxSigHashes(sweepTx)
+		sweepTxSigHashes = NewTxSigHashesV0Only(sweepTx)
 
 		bobSigHash = txscript.SigHashAll
 		if confirmed {
@@ -551,9 +551,12 @@ func TestHTLCSenderSpendValidation(t *testing.T) {
 		sweepTx.TxIn[0].Witness = testCase.witness()
 
 		newEngine := func() (*txscript.Engine, error) {
+			prevOutFetcher := txscript.NewCannedPrevOutputFetcher(
+				htlcPkScript, int64(paymentAmt),
+			)
 			return txscript.NewEngine(htlcPkScript,
 				sweepTx, 0, txscript.StandardVerifyFlags, nil,
-				nil, int64(paymentAmt))
+				nil, int64(paymentAmt), prevOutFetcher)
 		}
 
 		assertEngineExecution(t, i, testCase.valid, newEngine)
@@ -682,7 +685,7 @@ func TestHTLCReceiverSpendValidation(t *testing.T) {
 				Value:    1 * 10e8,
 			},
 		)
-		sweepTxSigHashes = txscript.NewTxSigHashes(sweepTx)
+		sweepTxSigHashes = NewTxSigHashesV0Only(sweepTx)
 
 		aliceSigHash = txscript.SigHashAll
 		if confirmed {
@@ -970,9 +973,12 @@ func TestHTLCReceiverSpendValidation(t *testing.T) {
 		sweepTx.TxIn[0].Witness = testCase.witness()
 
 		newEngine := func() (*txscript.Engine, error) {
+			prevOutFetcher := txscript.NewCannedPrevOutputFetcher(
+				htlcPkScript, int64(paymentAmt),
+			)
 			return txscript.NewEngine(htlcPkScript,
 				sweepTx, 0, txscript.StandardVerifyFlags, nil,
-				nil, int64(paymentAmt))
+				nil, int64(paymentAmt), prevOutFetcher)
 		}
 
 		assertEngineExecution(t, i, testCase.valid, newEngine)
@@ -1025,7 +1031,7 @@ func TestSecondLevelHtlcSpends(t *testing.T) {
 			Value:    1 * 10e8,
 		},
 	)
-	sweepTxSigHashes := txscript.NewTxSigHashes(sweepTx)
+	sweepTxSigHashes := NewTxSigHashesV0Only(sweepTx)
 
 	// The delay key will be crafted using Bob's public key as the output
 	// we created will be spending from Alice's commitment transaction.
@@ -1176,9 +1182,12 @@ func TestSecondLevelHtlcSpends(t *testing.T) {
 		sweepTx.TxIn[0].Witness = testCase.witness()
 
 		newEngine := func() (*txscript.Engine, error) {
+			prevOutFetcher := txscript.NewCannedPrevOutputFetcher(
+				htlcPkScript, int64(htlcAmt),
+			)
 			return txscript.NewEngine(htlcPkScript,
 				sweepTx, 0, txscript.StandardVerifyFlags, nil,
-				nil, int64(htlcAmt))
+				nil, int64(htlcAmt), prevOutFetcher)
 		}
 
 		assertEngineExecution(t, i, testCase.valid, newEngine)
@@ -1237,7 +1246,7 @@ func TestLeaseSecondLevelHtlcSpends(t *testing.T) {
 			Value:    1 * 10e8,
 		},
 	)
-	sweepTxSigHashes := txscript.NewTxSigHashes(sweepTx)
+	sweepTxSigHashes := NewTxSigHashesV0Only(sweepTx)
 
 	// The delay key will be crafted using Bob's public key as the output
 	// we created will be spending from Alice's commitment transaction.
@@ -1417,9 +1426,12 @@ func TestLeaseSecondLevelHtlcSpends(t *testing.T) {
 		sweepTx.TxIn[0].Witness = testCase.witness()
 
 		newEngine := func() (*txscript.Engine, error) {
+			prevOutFetcher := txscript.NewCannedPrevOutputFetcher(
+				htlcPkScript, int64(htlcAmt),
+			)
 			return txscript.NewEngine(htlcPkScript,
 				sweepTx, 0, txscript.StandardVerifyFlags, nil,
-				nil, int64(htlcAmt))
+				nil, int64(htlcAmt), prevOutFetcher)
 		}
 
 		assertEngineExecution(t, i, testCase.valid, newEngine)
@@ -1495,7 +1507,7 @@ func TestLeaseCommmitSpendToSelf(t *testing.T) {
 			// Bob can spend with his revocation key, but not
 			// without the proper tweak.
 			makeWitnessTestCase(t, func() (wire.TxWitness, error) {
-				sweepTxSigHashes := txscript.NewTxSigHashes(sweepTx)
+				sweepTxSigHashes := NewTxSigHashesV0Only(sweepTx)
 				signDesc := &SignDescriptor{
 					KeyDesc: keychain.KeyDescriptor{
 						PubKey: bobKeyPub,
@@ -1517,7 +1529,7 @@ func TestLeaseCommmitSpendToSelf(t *testing.T) {
 			// Bob can spend with his revocation key with the proper
 			// tweak.
 			makeWitnessTestCase(t, func() (wire.TxWitness, error) {
-				sweepTxSigHashes := txscript.NewTxSigHashes(sweepTx)
+				sweepTxSigHashes := NewTxSigHashesV0Only(sweepTx)
 				signDesc := &SignDescriptor{
 					KeyDesc: keychain.KeyDescriptor{
 						PubKey: bobKeyPub,
@@ -1544,7 +1556,7 @@ func TestLeaseCommmitSpendToSelf(t *testing.T) {
 				sweepTx.TxIn[0].Sequence = LockTimeToSequence(
 					false, csvDelay/2,
 				)
-				sweepTxSigHashes := txscript.NewTxSigHashes(sweepTx)
+				sweepTxSigHashes := NewTxSigHashesV0Only(sweepTx)
 				signDesc := &SignDescriptor{
 					KeyDesc: keychain.KeyDescriptor{
 						PubKey: aliceKeyPub,
@@ -1571,7 +1583,7 @@ func TestLeaseCommmitSpendToSelf(t *testing.T) {
 				sweepTx.TxIn[0].Sequence = LockTimeToSequence(
 					false, csvDelay,
 				)
-				sweepTxSigHashes := txscript.NewTxSigHashes(sweepTx)
+				sweepTxSigHashes := NewTxSigHashesV0Only(sweepTx)
 				signDesc := &SignDescriptor{
 					KeyDesc: keychain.KeyDescriptor{
 						PubKey: aliceKeyPub,
@@ -1598,7 +1610,7 @@ func TestLeaseCommmitSpendToSelf(t *testing.T) {
 				sweepTx.TxIn[0].Sequence = LockTimeToSequence(
 					false, csvDelay,
 				)
-				sweepTxSigHashes := txscript.NewTxSigHashes(sweepTx)
+				sweepTxSigHashes := NewTxSigHashesV0Only(sweepTx)
 				signDesc := &SignDescriptor{
 					KeyDesc: keychain.KeyDescriptor{
 						PubKey: aliceKeyPub,
@@ -1622,9 +1634,12 @@ func TestLeaseCommmitSpendToSelf(t *testing.T) {
 		sweepTx.TxIn[0].Witness = testCase.witness()
 
 		newEngine := func() (*txscript.Engine, error) {
+			prevOutFetcher := txscript.NewCannedPrevOutputFetcher(
+				commitPkScript, int64(outputVal),
+			)
 			return txscript.NewEngine(commitPkScript,
 				sweepTx, 0, txscript.StandardVerifyFlags, nil,
-				nil, int64(outputVal))
+				nil, int64(outputVal), prevOutFetcher)
 		}
 
 		assertEngineExecution(t, i, testCase.valid, newEngine)
@@ -1683,7 +1698,7 @@ func TestCommitSpendToRemoteConfirmed(t *testing.T) {
 			// Alice can spend after the a CSV delay has passed.
 			makeWitnessTestCase(t, func() (wire.TxWitness, error) {
 				sweepTx.TxIn[0].Sequence = LockTimeToSequence(false, 1)
-				sweepTxSigHashes := txscript.NewTxSigHashes(sweepTx)
+				sweepTxSigHashes := NewTxSigHashesV0Only(sweepTx)
 
 				signDesc := &SignDescriptor{
 					KeyDesc: keychain.KeyDescriptor{
@@ -1705,7 +1720,7 @@ func TestCommitSpendToRemoteConfirmed(t *testing.T) {
 			// Alice cannot spend output without sequence set.
 			makeWitnessTestCase(t, func() (wire.TxWitness, error) {
 				sweepTx.TxIn[0].Sequence = wire.MaxTxInSequenceNum
-				sweepTxSigHashes := txscript.NewTxSigHashes(sweepTx)
+				sweepTxSigHashes := NewTxSigHashesV0Only(sweepTx)
 
 				signDesc := &SignDescriptor{
 					KeyDesc: keychain.KeyDescriptor{
@@ -1729,9 +1744,12 @@ func TestCommitSpendToRemoteConfirmed(t *testing.T) {
 		sweepTx.TxIn[0].Witness = testCase.witness()
 
 		newEngine := func() (*txscript.Engine, error) {
+			prevOutFetcher := txscript.NewCannedPrevOutputFetcher(
+				commitPkScript, int64(outputVal),
+			)
 			return txscript.NewEngine(commitPkScript,
 				sweepTx, 0, txscript.StandardVerifyFlags, nil,
-				nil, int64(outputVal))
+				nil, int64(outputVal), prevOutFetcher)
 		}
 
 		assertEngineExecution(t, i, testCase.valid, newEngine)
@@ -1794,7 +1812,7 @@ func TestLeaseCommitSpendToRemoteConfirmed(t *testing.T) {
 				sweepTx.TxIn[0].Sequence = LockTimeToSequence(
 					false, 1,
 				)
-				sweepTxSigHashes := txscript.NewTxSigHashes(sweepTx)
+				sweepTxSigHashes := NewTxSigHashesV0Only(sweepTx)
 
 				signDesc := &SignDescriptor{
 					KeyDesc: keychain.KeyDescriptor{
@@ -1819,7 +1837,7 @@ func TestLeaseCommitSpendToRemoteConfirmed(t *testing.T) {
 			makeWitnessTestCase(t, func() (wire.TxWitness, error) {
 				sweepTx.LockTime = leaseExpiry
 				sweepTx.TxIn[0].Sequence = wire.MaxTxInSequenceNum
-				sweepTxSigHashes := txscript.NewTxSigHashes(sweepTx)
+				sweepTxSigHashes := NewTxSigHashesV0Only(sweepTx)
 
 				signDesc := &SignDescriptor{
 					KeyDesc: keychain.KeyDescriptor{
@@ -1844,7 +1862,7 @@ func TestLeaseCommitSpendToRemoteConfirmed(t *testing.T) {
 			makeWitnessTestCase(t, func() (wire.TxWitness, error) {
 				sweepTx.LockTime = 0
 				sweepTx.TxIn[0].Sequence = wire.MaxTxInSequenceNum
-				sweepTxSigHashes := txscript.NewTxSigHashes(sweepTx)
+				sweepTxSigHashes := NewTxSigHashesV0Only(sweepTx)
 
 				signDesc := &SignDescriptor{
 					KeyDesc: keychain.KeyDescriptor{
@@ -1869,10 +1887,13 @@ func TestLeaseCommitSpendToRemoteConfirmed(t *testing.T) {
 		sweepTx.TxIn[0].Witness = testCase.witness()
 
 		newEngine := func() (*txscript.Engine, error) {
+			prevOutFetcher := txscript.NewCannedPrevOutputFetcher(
+				commitPkScript, int64(outputVal),
+			)
 			return txscript.NewEngine(
 				commitPkScript, sweepTx, 0,
 				txscript.StandardVerifyFlags, nil, nil,
-				int64(outputVal),
+				int64(outputVal), prevOutFetcher,
 			)
 		}
 
@@ -1938,7 +1959,7 @@ func TestSpendAnchor(t *testing.T) {
 			// Alice can spend immediately.
 			makeWitnessTestCase(t, func() (wire.TxWitness, error) {
 				sweepTx.TxIn[0].Sequence = wire.MaxTxInSequenceNum
-				sweepTxSigHashes := txscript.NewTxSigHashes(sweepTx)
+				sweepTxSigHashes := NewTxSigHashesV0Only(sweepTx)
 
 				signDesc := &SignDescriptor{
 					KeyDesc: keychain.KeyDescriptor{
@@ -1978,9 +1999,12 @@ func TestSpendAnchor(t *testing.T) {
 		sweepTx.TxIn[0].Witness = testCase.witness()
 
 		newEngine := func() (*txscript.Engine, error) {
+			prevOutFetcher := txscript.NewCannedPrevOutputFetcher(
+				anchorPkScript, int64(anchorSize),
+			)
 			return txscript.NewEngine(anchorPkScript,
 				sweepTx, 0, txscript.StandardVerifyFlags, nil,
-				nil, int64(anchorSize))
+				nil, int64(anchorSize), prevOutFetcher)
 		}
 
 		assertEngineExecution(t, i, testCase.valid, newEngine)
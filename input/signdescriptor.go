@@ -3,6 +3,7 @@ package input
 import (
 	"encoding/binary"
 	"errors"
+	"fmt"
 	"io"
 
 	"github.com/brronsuite/broln/keychain"
@@ -17,6 +18,67 @@ var (
 	ErrTweakOverdose = errors.New("sign descriptor should only have one tweak")
 )
 
+// SignMethod defines the different ways a signer can sign, given a specific
+// input.
+type SignMethod uint8
+
+const (
+	// WitnessV0SignMethod denotes that a SegWit v0 (p2wkh, np2wkh, p2wsh)
+	// input script should be signed.
+	WitnessV0SignMethod SignMethod = 0
+
+	// TaprootKeySpendBIP0086SignMethod denotes that a SegWit v1 (p2tr)
+	// input should be signed by using the BIP0086 method (commit to
+	// internal key only).
+	TaprootKeySpendBIP0086SignMethod SignMethod = 1
+
+	// TaprootKeySpendSignMethod denotes that a SegWit v1 (p2tr) input
+	// should be signed by using a given taproot hash to commit to in
+	// addition to the internal key.
+	TaprootKeySpendSignMethod SignMethod = 2
+
+	// TaprootScriptSpendSignMethod denotes that a SegWit v1 (p2tr) input
+	// should be spent using the script path and that a specific leaf
+	// script should be signed for.
+	TaprootScriptSpendSignMethod SignMethod = 3
+)
+
+// String returns a human-readable representation of the signing method.
+func (s SignMethod) String() string {
+	switch s {
+	case WitnessV0SignMethod:
+		return "witness_v0"
+	case TaprootKeySpendBIP0086SignMethod:
+		return "taproot_key_spend_bip86"
+	case TaprootKeySpendSignMethod:
+		return "taproot_key_spend"
+	case TaprootScriptSpendSignMethod:
+		return "taproot_script_spend"
+	default:
+		return fmt.Sprintf("unknown<%d>", s)
+	}
+}
+
+// PkScriptCompatible returns true if the given public key script is compatible
+// with the sign method.
+func (s SignMethod) PkScriptCompatible(pkScript []byte) bool {
+	switch s {
+	// SegWit v0 can be p2wkh, np2wkh, p2wsh.
+	case WitnessV0SignMethod:
+		return txscript.IsPayToWitnessPubKeyHash(pkScript) ||
+			txscript.IsPayToWitnessScriptHash(pkScript) ||
+			txscript.IsPayToScriptHash(pkScript)
+
+	case TaprootKeySpendBIP0086SignMethod, TaprootKeySpendSignMethod,
+		TaprootScriptSpendSignMethod:
+
+		return txscript.IsPayToTaproot(pkScript)
+
+	default:
+		return false
+	}
+}
+
 // SignDescriptor houses the necessary information required to successfully
 // sign a given segwit output. This struct is used by the Signer interface in
 // order to gain access to critical data needed to generate a valid signature.
@@ -61,11 +123,30 @@ type SignDescriptor struct {
 	// script (PkScript).
 	WitnessScript []byte
 
+	// SignMethod specifies how the input should be signed. Depending on the
+	// selected method, either the TapTweak, WitnessScript or both need to
+	// be specified.
+	SignMethod SignMethod
+
+	// TapTweak is a 32-byte value that will be used to tweak the internal
+	// key when signing a taproot key spend with the
+	// TaprootKeySpendSignMethod. This is usually the root hash of the
+	// tapscript tree the output commits to. For the
+	// TaprootKeySpendBIP0086SignMethod this value is ignored, as the key
+	// is tweaked with an empty script root as defined in BIP-0086.
+	TapTweak []byte
+
 	// Output is the target output which should be signed. The PkScript and
 	// Value fields within the output should be properly populated,
 	// otherwise an invalid signature may be generated.
 	Output *wire.TxOut
 
+	// PrevOutputFetcher returns the previous outputs of all inputs of the
+	// transaction that is being signed. The BIP-341 sighash of taproot
+	// inputs commits to the amounts and scripts of all spent outputs, so
+	// this must be set when signing a taproot input.
+	PrevOutputFetcher txscript.PrevOutputFetcher
+
 	// HashType is the target sighash type that should be used when
 	// generating the final sighash, and signature.
 	HashType txscript.SigHashType
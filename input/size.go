@@ -2,6 +2,7 @@ package input
 
 import (
 	"github.com/brronsuite/brond/blockchain"
+	"github.com/brronsuite/brond/txscript"
 	"github.com/brronsuite/brond/wire"
 )
 
@@ -41,6 +42,12 @@ const (
 	//      - P2WSHWitnessProgram: 34 bytes
 	NestedP2WSHSize = 1 + P2WSHSize
 
+	// P2TRSize 34 bytes
+	//	- OP_1: 1 byte
+	//	- OP_DATA: 1 byte (x-only public key length)
+	//	- x-only public key: 32 bytes
+	P2TRSize = 1 + 1 + 32
+
 	// UnknownWitnessSize 42 bytes
 	//      - OP_x: 1 byte
 	//      - OP_DATA: 1 byte (max-size length)
@@ -68,6 +75,12 @@ const (
 	//      - pkscript (p2wsh): 34 bytes
 	P2WSHOutputSize = 8 + 1 + P2WSHSize
 
+	// P2TROutputSize 43 bytes
+	//      - value: 8 bytes
+	//      - var_int: 1 byte (pkscript_length)
+	//      - pkscript (p2tr): 34 bytes
+	P2TROutputSize = 8 + 1 + P2TRSize
+
 	// P2SHSize 23 bytes
 	P2SHSize = 23
 
@@ -92,6 +105,19 @@ const (
 	//      - pubkey
 	P2WKHWitnessSize = 1 + 1 + 73 + 1 + 33
 
+	// TaprootKeyPathWitnessSize 66 bytes
+	//      - number_of_witness_elements: 1 byte
+	//      - signature_length: 1 byte
+	//      - signature: 64 bytes
+	TaprootKeyPathWitnessSize = 1 + 1 + 64
+
+	// TaprootKeyPathCustomSighashWitnessSize 67 bytes
+	//      - number_of_witness_elements: 1 byte
+	//      - signature_length: 1 byte
+	//      - signature: 64 bytes
+	//      - sighash_type: 1 byte
+	TaprootKeyPathCustomSighashWitnessSize = TaprootKeyPathWitnessSize + 1
+
 	// MultiSigSize 71 bytes
 	//	- OP_2: 1 byte
 	//	- OP_DATA: 1 byte (pubKeyAlice length)
@@ -582,6 +608,22 @@ func (twe *TxWeightEstimator) AddWitnessInput(witnessSize int) *TxWeightEstimato
 	return twe
 }
 
+// AddTaprootKeySpendInput updates the weight estimate to account for an
+// additional input spending a segwit v1 pay-to-taproot output using the key
+// spend path. The sighash type is needed to determine whether the signature
+// carries an explicit sighash flag byte.
+func (twe *TxWeightEstimator) AddTaprootKeySpendInput(
+	hashType txscript.SigHashType) *TxWeightEstimator {
+
+	if hashType == txscript.SigHashDefault {
+		twe.AddWitnessInput(TaprootKeyPathWitnessSize)
+	} else {
+		twe.AddWitnessInput(TaprootKeyPathCustomSighashWitnessSize)
+	}
+
+	return twe
+}
+
 // AddNestedP2WKHInput updates the weight estimate to account for an additional
 // input spending a P2SH output with a nested P2WKH redeem script.
 func (twe *TxWeightEstimator) AddNestedP2WKHInput() *TxWeightEstimator {
@@ -639,6 +681,15 @@ func (twe *TxWeightEstimator) AddP2WSHOutput() *TxWeightEstimator {
 	return twe
 }
 
+// AddP2TROutput updates the weight estimate to account for an additional
+// native segwit v1 P2TR output.
+func (twe *TxWeightEstimator) AddP2TROutput() *TxWeightEstimator {
+	twe.outputSize += P2TROutputSize
+	twe.outputCount++
+
+	return twe
+}
+
 // AddP2SHOutput updates the weight estimate to account for an additional P2SH
 // output.
 func (twe *TxWeightEstimator) AddP2SHOutput() *TxWeightEstimator {
@@ -89,6 +89,12 @@ func TestTxWeightEstimator(t *testing.T) {
 		t.Fatalf("Failed to generate scriptPubKey: %v", err)
 	}
 
+	p2trScript, err := txscript.NewScriptBuilder().AddOp(txscript.OP_1).
+		AddData(make([]byte, 32)).Script()
+	if err != nil {
+		t.Fatalf("Failed to generate scriptPubKey: %v", err)
+	}
+
 	p2shAddr, err := bronutil.NewAddressScriptHash([]byte{0}, netParams)
 	if err != nil {
 		t.Fatalf("Failed to generate address: %v", err)
@@ -104,10 +110,12 @@ func TestTxWeightEstimator(t *testing.T) {
 		numP2WSHInputs       int
 		numNestedP2WKHInputs int
 		numNestedP2WSHInputs int
+		numP2TRInputs        int
 		numP2PKHOutputs      int
 		numP2WKHOutputs      int
 		numP2WSHOutputs      int
 		numP2SHOutputs       int
+		numP2TROutputs       int
 	}{
 		// Assert base txn size.
 		{},
@@ -140,6 +148,12 @@ func TestTxWeightEstimator(t *testing.T) {
 		{
 			numP2SHOutputs: 1,
 		},
+		{
+			numP2TRInputs: 1,
+		},
+		{
+			numP2TROutputs: 1,
+		},
 
 		// Assert each input/output increments input/output counts.
 		{
@@ -169,6 +183,12 @@ func TestTxWeightEstimator(t *testing.T) {
 		{
 			numP2SHOutputs: 253,
 		},
+		{
+			numP2TRInputs: 253,
+		},
+		{
+			numP2TROutputs: 253,
+		},
 
 		// Assert basic combinations of inputs and outputs.
 		{
@@ -207,6 +227,12 @@ func TestTxWeightEstimator(t *testing.T) {
 			numNestedP2WSHInputs: 1,
 			numP2WKHOutputs:      1,
 		},
+		{
+			numP2TRInputs:   1,
+			numP2WKHInputs:  1,
+			numP2TROutputs:  1,
+			numP2WKHOutputs: 1,
+		},
 
 		// Assert disparate input/output types increment total
 		// input/output counts.
@@ -297,6 +323,15 @@ func TestTxWeightEstimator(t *testing.T) {
 
 			tx.AddTxIn(&wire.TxIn{SignatureScript: scriptSig, Witness: witness})
 		}
+		for j := 0; j < test.numP2TRInputs; j++ {
+			weightEstimate.AddTaprootKeySpendInput(
+				txscript.SigHashDefault,
+			)
+
+			signature := make([]byte, 64)
+			witness := wire.TxWitness{signature}
+			tx.AddTxIn(&wire.TxIn{Witness: witness})
+		}
 		for j := 0; j < test.numP2PKHOutputs; j++ {
 			weightEstimate.AddP2PKHOutput()
 			tx.AddTxOut(&wire.TxOut{PkScript: p2pkhScript})
@@ -313,6 +348,10 @@ func TestTxWeightEstimator(t *testing.T) {
 			weightEstimate.AddP2SHOutput()
 			tx.AddTxOut(&wire.TxOut{PkScript: p2shScript})
 		}
+		for j := 0; j < test.numP2TROutputs; j++ {
+			weightEstimate.AddP2TROutput()
+			tx.AddTxOut(&wire.TxOut{PkScript: p2trScript})
+		}
 
 		expectedWeight := blockchain.GetTransactionWeight(bronutil.NewTx(tx))
 		if weightEstimate.Weight() != int(expectedWeight) {
@@ -175,6 +175,11 @@ const (
 	// and CLTV locktime as part of the script enforced lease commitment
 	// type.
 	LeaseHtlcAcceptedSuccessSecondLevel StandardWitnessType = 20
+
+	// TaprootPubKeySpend is a witness type that allows us to spend a
+	// regular p2tr output that's sent to an output which is under complete
+	// control of the backing wallet, using the key spend path.
+	TaprootPubKeySpend StandardWitnessType = 21
 )
 
 // String returns a human readable version of the target WitnessType.
@@ -245,6 +250,9 @@ func (wt StandardWitnessType) String() string {
 	case LeaseHtlcAcceptedSuccessSecondLevel:
 		return "LeaseHtlcAcceptedSuccessSecondLevel"
 
+	case TaprootPubKeySpend:
+		return "TaprootPubKeySpend"
+
 	default:
 		return fmt.Sprintf("Unknown WitnessType: %v", uint32(wt))
 	}
@@ -388,6 +396,8 @@ func (wt StandardWitnessType) WitnessGenerator(signer Signer,
 
 		case WitnessKeyHash:
 			fallthrough
+		case TaprootPubKeySpend:
+			fallthrough
 		case NestedWitnessKeyHash:
 			return signer.ComputeInputScript(tx, desc)
 
@@ -473,6 +483,11 @@ func (wt StandardWitnessType) SizeUpperBound() (int, bool, error) {
 	case HtlcAcceptedRemoteSuccess:
 		return OfferedHtlcSuccessWitnessSize, false, nil
 
+	// A p2tr key spend input of the wallet. We assume the signature
+	// carries an explicit sighash flag to get an upper bound.
+	case TaprootPubKeySpend:
+		return TaprootKeyPathCustomSighashWitnessSize, false, nil
+
 	// A nested P2SH input that has a p2wkh witness script. We'll mark this
 	// as nested P2SH so the caller can estimate the weight properly
 	// including the sigScript.
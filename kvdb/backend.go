@@ -238,12 +238,12 @@ func updateLastCompactionDate(dbFile string) error {
 	return ioutil.WriteFile(tsFile, tsBytes[:], 0600)
 }
 
-// GetTestBackend opens (or creates if doesn't exist) a bbolt or etcd
-// backed database (for testing), and returns a kvdb.Backend and a cleanup
-// func. Whether to create/open bbolt or embedded etcd database is based
+// GetTestBackend opens (or creates if doesn't exist) a bbolt, etcd, postgres
+// or sqlite backed database (for testing), and returns a kvdb.Backend and a
+// cleanup func. Whether to create/open bbolt or embedded etcd database is based
 // on the TestBackend constant which is conditionally compiled with build tag.
 // The passed path is used to hold all db files, while the name is only used
-// for bbolt.
+// for bbolt and sqlite.
 func GetTestBackend(path, name string) (Backend, func(), error) {
 	empty := func() {}
 
@@ -260,6 +260,15 @@ func GetTestBackend(path, name string) (Backend, func(), error) {
 			_ = f.DB().Close()
 		}, nil
 
+	case SqliteBackend:
+		f, err := NewSqliteFixture(filepath.Join(path, name))
+		if err != nil {
+			return nil, func() {}, err
+		}
+		return f.DB(), func() {
+			_ = f.DB().Close()
+		}, nil
+
 	case TestBackend == BoltBackendName:
 		db, err := GetBoltBackend(&BoltBackendConfig{
 			DBPath:         path,
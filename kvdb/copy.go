@@ -0,0 +1,380 @@
+package kvdb
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"sort"
+)
+
+const (
+	// DefaultCopyBatchSize is the default maximum number of write
+	// operations that are executed in a single destination transaction
+	// when copying one backend into another. Remote backends such as etcd
+	// limit the size of a single transaction, so we can't copy a whole
+	// database in one go.
+	DefaultCopyBatchSize = 10000
+)
+
+var (
+	// ErrDestinationNotEmpty is returned when the destination of a copy
+	// operation already contains top-level buckets.
+	ErrDestinationNotEmpty = errors.New("destination database is not " +
+		"empty")
+)
+
+// sequenceBucket is implemented by the buckets of all our backends, even the
+// read-only ones. The walletdb.ReadBucket interface does not expose the
+// sequence, so we need to type assert to get it from a read transaction.
+type sequenceBucket interface {
+	Sequence() uint64
+}
+
+// bucketSequence returns the sequence number of the given bucket or zero if
+// the bucket implementation doesn't expose it.
+func bucketSequence(b RBucket) uint64 {
+	if seqBucket, ok := b.(sequenceBucket); ok {
+		return seqBucket.Sequence()
+	}
+
+	return 0
+}
+
+// backendCopier copies the content of a read transaction of one backend into
+// a destination backend, committing the destination transaction every
+// batchSize operations.
+type backendCopier struct {
+	dst       Backend
+	batchSize int
+
+	// tx is the currently open destination transaction.
+	tx RwTx
+
+	// ops is the number of write operations executed in the current
+	// destination transaction.
+	ops int
+
+	// generation is increased every time the destination transaction is
+	// committed and replaced. Buckets fetched in an older generation must
+	// be looked up again.
+	generation int
+}
+
+// dstBucket returns the destination bucket at the given path in the current
+// destination transaction.
+func (c *backendCopier) dstBucket(path [][]byte) (RwBucket, error) {
+	bucket := c.tx.ReadWriteBucket(path[0])
+	for _, key := range path[1:] {
+		if bucket == nil {
+			break
+		}
+
+		bucket = bucket.NestedReadWriteBucket(key)
+	}
+
+	if bucket == nil {
+		return nil, fmt.Errorf("destination bucket %x not found",
+			bytes.Join(path, []byte("/")))
+	}
+
+	return bucket, nil
+}
+
+// countOp accounts for a write operation in the current destination
+// transaction and commits the transaction if the batch is full.
+func (c *backendCopier) countOp() error {
+	c.ops++
+	if c.ops < c.batchSize {
+		return nil
+	}
+
+	if err := c.tx.Commit(); err != nil {
+		return err
+	}
+
+	tx, err := c.dst.BeginReadWriteTx()
+	if err != nil {
+		return err
+	}
+
+	c.tx = tx
+	c.ops = 0
+	c.generation++
+
+	return nil
+}
+
+// copyBucket recursively copies all key/value pairs, nested buckets and
+// bucket sequence numbers of the source bucket into the destination bucket at
+// the given path.
+func (c *backendCopier) copyBucket(src RBucket, path [][]byte) error {
+	var (
+		dst           RwBucket
+		dstGeneration = -1
+	)
+
+	// Carry over the sequence number of the bucket itself first.
+	if seq := bucketSequence(src); seq != 0 {
+		bucket, err := c.dstBucket(path)
+		if err != nil {
+			return err
+		}
+
+		if err := bucket.SetSequence(seq); err != nil {
+			return err
+		}
+
+		if err := c.countOp(); err != nil {
+			return err
+		}
+	}
+
+	return src.ForEach(func(k, v []byte) error {
+		// The destination transaction might have been replaced since
+		// we last looked up our bucket, so we fetch it again if
+		// needed.
+		if dst == nil || dstGeneration != c.generation {
+			var err error
+			dst, err = c.dstBucket(path)
+			if err != nil {
+				return err
+			}
+			dstGeneration = c.generation
+		}
+
+		// A nil value denotes a nested bucket.
+		if v == nil {
+			nested := src.NestedReadBucket(k)
+			if nested == nil {
+				return fmt.Errorf("nested bucket %x not found", k)
+			}
+
+			if _, err := dst.CreateBucket(k); err != nil {
+				return err
+			}
+			if err := c.countOp(); err != nil {
+				return err
+			}
+
+			nestedPath := make([][]byte, len(path), len(path)+1)
+			copy(nestedPath, path)
+			nestedPath = append(nestedPath, copyBytes(k))
+
+			return c.copyBucket(nested, nestedPath)
+		}
+
+		if err := dst.Put(k, v); err != nil {
+			return err
+		}
+
+		return c.countOp()
+	})
+}
+
+// copyBytes returns a copy of the passed byte slice. Keys handed out by a
+// cursor are only valid for the lifetime of the transaction, so we need our
+// own copy if we hold on to them.
+func copyBytes(b []byte) []byte {
+	c := make([]byte, len(b))
+	copy(c, b)
+
+	return c
+}
+
+// CopyBackend copies all top-level buckets of the source backend, including
+// all their nested buckets, key/value pairs and bucket sequence numbers, into
+// the destination backend. The destination backend must be empty. Writes to
+// the destination are committed in batches of at most batchSize operations.
+// If batchSize is zero, DefaultCopyBatchSize is used.
+//
+// NOTE: Neither of the backends must be written to by anyone else while the
+// copy is in progress.
+func CopyBackend(src, dst Backend, batchSize int) error {
+	if batchSize <= 0 {
+		batchSize = DefaultCopyBatchSize
+	}
+
+	// Make sure we don't accidentally merge the source into an existing
+	// database.
+	err := View(dst, func(tx RTx) error {
+		return tx.ForEachBucket(func(_ []byte) error {
+			return ErrDestinationNotEmpty
+		})
+	}, func() {})
+	if err != nil {
+		return err
+	}
+
+	return View(src, func(srcTx RTx) error {
+		dstTx, err := dst.BeginReadWriteTx()
+		if err != nil {
+			return err
+		}
+
+		c := &backendCopier{
+			dst:       dst,
+			batchSize: batchSize,
+			tx:        dstTx,
+		}
+
+		// Roll back whatever transaction is open at the time we return
+		// with an error.
+		success := false
+		defer func() {
+			if !success {
+				_ = c.tx.Rollback()
+			}
+		}()
+
+		err = srcTx.ForEachBucket(func(key []byte) error {
+			src := srcTx.ReadBucket(key)
+			if src == nil {
+				return fmt.Errorf("top-level bucket %x not "+
+					"found", key)
+			}
+
+			log.Debugf("Copying top-level bucket %x", key)
+
+			if _, err := c.tx.CreateTopLevelBucket(key); err != nil {
+				return err
+			}
+			if err := c.countOp(); err != nil {
+				return err
+			}
+
+			return c.copyBucket(src, [][]byte{copyBytes(key)})
+		})
+		if err != nil {
+			return err
+		}
+
+		success = true
+		return c.tx.Commit()
+	}, func() {})
+}
+
+// writeChecksumBytes writes the length of the given byte slice followed by the
+// byte slice itself to the hash, so the encoding is unambiguous.
+func writeChecksumBytes(h hash.Hash, b []byte) {
+	var lenBytes [8]byte
+	binary.BigEndian.PutUint64(lenBytes[:], uint64(len(b)))
+
+	_, _ = h.Write(lenBytes[:])
+	_, _ = h.Write(b)
+}
+
+// checksumBucket adds the sequence number and the full content of the given
+// bucket to the hash, recursing into nested buckets.
+func checksumBucket(h hash.Hash, bucket RBucket) error {
+	var seqBytes [8]byte
+	binary.BigEndian.PutUint64(seqBytes[:], bucketSequence(bucket))
+	_, _ = h.Write(seqBytes[:])
+
+	return bucket.ForEach(func(k, v []byte) error {
+		if v == nil {
+			nested := bucket.NestedReadBucket(k)
+			if nested == nil {
+				return fmt.Errorf("nested bucket %x not found", k)
+			}
+
+			_, _ = h.Write([]byte{'b'})
+			writeChecksumBytes(h, k)
+			if err := checksumBucket(h, nested); err != nil {
+				return err
+			}
+
+			// Mark the end of the nested bucket so that a key
+			// following it can't be confused with a key inside
+			// of it.
+			_, _ = h.Write([]byte{'e'})
+
+			return nil
+		}
+
+		_, _ = h.Write([]byte{'v'})
+		writeChecksumBytes(h, k)
+		writeChecksumBytes(h, v)
+
+		return nil
+	})
+}
+
+// BucketChecksums returns a SHA-256 checksum over the full content of every
+// top-level bucket of the given backend, keyed by the bucket name. The
+// checksum only depends on the logical content of the buckets, so it can be
+// used to compare databases of different backend types.
+func BucketChecksums(db Backend) (map[string][sha256.Size]byte, error) {
+	var checksums map[string][sha256.Size]byte
+	err := View(db, func(tx RTx) error {
+		return tx.ForEachBucket(func(key []byte) error {
+			bucket := tx.ReadBucket(key)
+			if bucket == nil {
+				return fmt.Errorf("top-level bucket %x not "+
+					"found", key)
+			}
+
+			h := sha256.New()
+			if err := checksumBucket(h, bucket); err != nil {
+				return err
+			}
+
+			var sum [sha256.Size]byte
+			copy(sum[:], h.Sum(nil))
+			checksums[string(key)] = sum
+
+			return nil
+		})
+	}, func() {
+		checksums = make(map[string][sha256.Size]byte)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return checksums, nil
+}
+
+// VerifyCopy compares the checksums of all top-level buckets of the two
+// backends and returns an error if they don't contain exactly the same data.
+func VerifyCopy(src, dst Backend) error {
+	srcChecksums, err := BucketChecksums(src)
+	if err != nil {
+		return fmt.Errorf("unable to checksum source: %v", err)
+	}
+
+	dstChecksums, err := BucketChecksums(dst)
+	if err != nil {
+		return fmt.Errorf("unable to checksum destination: %v", err)
+	}
+
+	// Iterate in a deterministic order so the error message is stable.
+	keys := make([]string, 0, len(srcChecksums))
+	for key := range srcChecksums {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		dstChecksum, ok := dstChecksums[key]
+		if !ok {
+			return fmt.Errorf("top-level bucket %x missing in "+
+				"destination", key)
+		}
+
+		if dstChecksum != srcChecksums[key] {
+			return fmt.Errorf("checksum mismatch for top-level "+
+				"bucket %x: source=%x, destination=%x", key,
+				srcChecksums[key], dstChecksum)
+		}
+	}
+
+	if len(dstChecksums) != len(srcChecksums) {
+		return fmt.Errorf("destination has %d top-level buckets, "+
+			"source has %d", len(dstChecksums), len(srcChecksums))
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package kvdb
+
+import (
+	"testing"
+
+	"github.com/brronsuite/bronwallet/walletdb"
+	"github.com/stretchr/testify/require"
+)
+
+// fillCopyTestDB populates the given backend with a few nested buckets, values
+// and bucket sequence numbers.
+func fillCopyTestDB(t *testing.T, db walletdb.DB) {
+	err := Update(db, func(tx walletdb.ReadWriteTx) error {
+		apple, err := tx.CreateTopLevelBucket([]byte("apple"))
+		require.NoError(t, err)
+		require.NoError(t, apple.Put([]byte("key1"), []byte("val1")))
+		require.NoError(t, apple.Put([]byte("key2"), []byte{}))
+		require.NoError(t, apple.SetSequence(42))
+
+		banana, err := apple.CreateBucket([]byte("banana"))
+		require.NoError(t, err)
+		require.NoError(t, banana.Put([]byte("key3"), []byte("val3")))
+		_, err = banana.NextSequence()
+		require.NoError(t, err)
+
+		pear, err := banana.CreateBucket([]byte("pear"))
+		require.NoError(t, err)
+		require.NoError(t, pear.Put([]byte("key4"), []byte("val4")))
+
+		mango, err := tx.CreateTopLevelBucket([]byte("mango"))
+		require.NoError(t, err)
+		require.NoError(t, mango.Put([]byte("key5"), []byte("val5")))
+
+		return nil
+	}, func() {})
+	require.NoError(t, err)
+}
+
+// TestCopyBackend tests that a backend can be copied into an empty backend and
+// that the copy is verified correctly.
+func TestCopyBackend(t *testing.T) {
+	t.Parallel()
+
+	srcFixture := NewBoltFixture(t)
+	defer srcFixture.Cleanup()
+	src := srcFixture.NewBackend()
+	defer src.Close()
+
+	dstFixture := NewBoltFixture(t)
+	defer dstFixture.Cleanup()
+	dst := dstFixture.NewBackend()
+	defer dst.Close()
+
+	fillCopyTestDB(t, src)
+
+	// Use a tiny batch size to make sure the destination transaction is
+	// replaced a couple of times during the copy.
+	require.NoError(t, CopyBackend(src, dst, 2))
+	require.NoError(t, VerifyCopy(src, dst))
+
+	// Make sure the sequence numbers were carried over.
+	err := View(dst, func(tx walletdb.ReadTx) error {
+		apple := tx.ReadBucket([]byte("apple"))
+		require.NotNil(t, apple)
+		require.Equal(t, uint64(42), bucketSequence(apple))
+
+		banana := apple.NestedReadBucket([]byte("banana"))
+		require.NotNil(t, banana)
+		require.Equal(t, uint64(1), bucketSequence(banana))
+
+		return nil
+	}, func() {})
+	require.NoError(t, err)
+
+	// A second copy into the now populated destination must fail.
+	require.ErrorIs(t, CopyBackend(src, dst, 0), ErrDestinationNotEmpty)
+
+	// Modifying a value deep down in the destination must be detected.
+	err = Update(dst, func(tx walletdb.ReadWriteTx) error {
+		pear := tx.ReadWriteBucket([]byte("apple")).
+			NestedReadWriteBucket([]byte("banana")).
+			NestedReadWriteBucket([]byte("pear"))
+
+		return pear.Put([]byte("key4"), []byte("modified"))
+	}, func() {})
+	require.NoError(t, err)
+	require.Error(t, VerifyCopy(src, dst))
+}
+
+// TestBucketChecksumsStructure tests that the bucket checksum takes the
+// nesting of buckets into account and not only the keys and values.
+func TestBucketChecksumsStructure(t *testing.T) {
+	t.Parallel()
+
+	f1 := NewBoltFixture(t)
+	defer f1.Cleanup()
+	db1 := f1.NewBackend()
+	defer db1.Close()
+
+	f2 := NewBoltFixture(t)
+	defer f2.Cleanup()
+	db2 := f2.NewBackend()
+	defer db2.Close()
+
+	// The first database has the key inside the nested bucket, the second
+	// one has it next to the nested bucket.
+	err := Update(db1, func(tx walletdb.ReadWriteTx) error {
+		top, err := tx.CreateTopLevelBucket([]byte("top"))
+		require.NoError(t, err)
+
+		nested, err := top.CreateBucket([]byte("nested"))
+		require.NoError(t, err)
+
+		return nested.Put([]byte("z"), []byte("val"))
+	}, func() {})
+	require.NoError(t, err)
+
+	err = Update(db2, func(tx walletdb.ReadWriteTx) error {
+		top, err := tx.CreateTopLevelBucket([]byte("top"))
+		require.NoError(t, err)
+
+		_, err = top.CreateBucket([]byte("nested"))
+		require.NoError(t, err)
+
+		return top.Put([]byte("z"), []byte("val"))
+	}, func() {})
+	require.NoError(t, err)
+
+	require.Error(t, VerifyCopy(db1, db2))
+}
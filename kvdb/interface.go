@@ -1,6 +1,8 @@
 package kvdb
 
 import (
+	"database/sql"
+
 	"github.com/brronsuite/bronwallet/walletdb"
 )
 
@@ -117,6 +119,34 @@ type ExtendedRBucket interface {
 	ForAll(func(k, v []byte) error) error
 }
 
+// SQLTx is an extension to walletdb.ReadWriteTx that is implemented by the
+// transactions of the postgres backend. It gives access to the underlying SQL
+// transaction, so sub-systems can maintain their own relational tables next to
+// the key/value data within the same atomic transaction.
+type SQLTx interface {
+	// Exec executes a statement that doesn't return any rows.
+	Exec(query string, args ...interface{}) (sql.Result, error)
+
+	// Query executes a query that returns multiple rows. The returned
+	// cancel closure must be called once the rows aren't needed anymore.
+	Query(query string, args ...interface{}) (*sql.Rows, func(), error)
+
+	// QueryRow executes a query that returns at most one row. The
+	// returned cancel closure must be called once the row was scanned.
+	QueryRow(query string, args ...interface{}) (*sql.Row, func())
+
+	// PrefixedTableName returns the given table name prefixed with the
+	// namespace of the database the transaction belongs to.
+	PrefixedTableName(table string) string
+}
+
+// AsSQLTx returns the passed transaction as a SQLTx if the backend it belongs
+// to supports direct SQL access.
+func AsSQLTx(t RTx) (SQLTx, bool) {
+	tx, ok := t.(SQLTx)
+	return tx, ok
+}
+
 // Prefetch will attempt to prefetch all values under a path from the passed
 // bucket.
 func Prefetch(b RBucket, paths ...[]string) {
@@ -0,0 +1,16 @@
+//go:build !kvdb_sqlite
+// +build !kvdb_sqlite
+
+package kvdb
+
+import (
+	"errors"
+
+	"github.com/brronsuite/broln/kvdb/sqlite"
+)
+
+const SqliteBackend = false
+
+func NewSqliteFixture(dbPath string) (sqlite.Fixture, error) {
+	return nil, errors.New("sqlite backend not available")
+}
@@ -0,0 +1,12 @@
+//go:build kvdb_sqlite
+// +build kvdb_sqlite
+
+package kvdb
+
+import "github.com/brronsuite/broln/kvdb/sqlite"
+
+const SqliteBackend = true
+
+func NewSqliteFixture(dbPath string) (sqlite.Fixture, error) {
+	return sqlite.NewFixture(dbPath)
+}
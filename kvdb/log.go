@@ -2,6 +2,7 @@ package kvdb
 
 import (
 	"github.com/brronsuite/broln/kvdb/postgres"
+	"github.com/brronsuite/broln/kvdb/sqlite"
 	"github.com/brronsuite/bronlog"
 )
 
@@ -14,4 +15,5 @@ func UseLogger(logger bronlog.Logger) {
 	log = logger
 
 	postgres.UseLogger(log)
+	sqlite.UseLogger(log)
 }
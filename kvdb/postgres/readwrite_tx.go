@@ -167,6 +167,12 @@ func (tx *readWriteTx) OnCommit(cb func()) {
 	tx.onCommit = cb
 }
 
+// PrefixedTableName returns the given table name prefixed with the namespace
+// of the database this transaction belongs to.
+func (tx *readWriteTx) PrefixedTableName(table string) string {
+	return tx.db.getPrefixedTableName(table)
+}
+
 // QueryRow executes a QueryRow call with a timeout context.
 func (tx *readWriteTx) QueryRow(query string, args ...interface{}) (*sql.Row,
 	func()) {
@@ -0,0 +1,26 @@
+//go:build kvdb_sqlite
+// +build kvdb_sqlite
+
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/brronsuite/bronwallet/walletdb/walletdbtest"
+)
+
+// TestInterface performs all interfaces tests for this database driver.
+func TestInterface(t *testing.T) {
+	dbPath := t.TempDir()
+
+	ctx := context.Background()
+	cfg := &Config{
+		BusyTimeout: 5 * time.Second,
+	}
+
+	walletdbtest.TestInterface(
+		t, dbType, ctx, cfg, dbPath, testDBFileName, prefix,
+	)
+}
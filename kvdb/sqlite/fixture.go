@@ -0,0 +1,123 @@
+//go:build kvdb_sqlite
+// +build kvdb_sqlite
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"time"
+
+	"github.com/brronsuite/bronwallet/walletdb"
+)
+
+const (
+	testDBFileName = "test.sqlite"
+	prefix         = "test"
+)
+
+// NewFixture returns a new sqlite test database stored in a file in the given
+// directory.
+func NewFixture(dbPath string) (*fixture, error) {
+	db, err := newSqliteBackend(
+		context.Background(),
+		&Config{
+			Timeout:     time.Minute,
+			BusyTimeout: 5 * time.Second,
+		},
+		dbPath, testDBFileName, prefix,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fixture{
+		DBPath: dbPath,
+		Db:     db,
+	}, nil
+}
+
+type fixture struct {
+	DBPath string
+	Db     walletdb.DB
+}
+
+func (b *fixture) DB() walletdb.DB {
+	return b.Db
+}
+
+// Dump returns the raw contents of the database.
+func (b *fixture) Dump() (map[string]interface{}, error) {
+	dbConn, err := sql.Open(
+		sqliteDriverName, filepath.Join(b.DBPath, testDBFileName),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer dbConn.Close()
+
+	rows, err := dbConn.Query(
+		"SELECT name FROM sqlite_master WHERE type='table'",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var tables []string
+	for rows.Next() {
+		var table string
+		err := rows.Scan(&table)
+		if err != nil {
+			return nil, err
+		}
+
+		tables = append(tables, table)
+	}
+
+	result := make(map[string]interface{})
+
+	for _, table := range tables {
+		rows, err := dbConn.Query("SELECT * FROM " + table)
+		if err != nil {
+			return nil, err
+		}
+
+		cols, err := rows.Columns()
+		if err != nil {
+			return nil, err
+		}
+		colCount := len(cols)
+
+		var tableRows []map[string]interface{}
+		for rows.Next() {
+			values := make([]interface{}, colCount)
+			valuePtrs := make([]interface{}, colCount)
+			for i := range values {
+				valuePtrs[i] = &values[i]
+			}
+
+			err := rows.Scan(valuePtrs...)
+			if err != nil {
+				return nil, err
+			}
+
+			tableData := make(map[string]interface{})
+			for i, v := range values {
+				// Cast byte slices to string to keep the
+				// expected database contents in test code more
+				// readable.
+				if ar, ok := v.([]uint8); ok {
+					v = string(ar)
+				}
+				tableData[cols[i]] = v
+			}
+
+			tableRows = append(tableRows, tableData)
+		}
+
+		result[table] = tableRows
+	}
+
+	return result, nil
+}
@@ -68,6 +68,9 @@ const (
 
 	// LabelTypeSweepTransaction is used to label sweeps.
 	LabelTypeSweepTransaction LabelType = "sweep"
+
+	// LabelTypeSplice is used to label splices of channels.
+	LabelTypeSplice LabelType = "splice"
 )
 
 // LabelField is used to tag a value within a label.
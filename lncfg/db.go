@@ -8,6 +8,7 @@ import (
 	"github.com/brronsuite/broln/kvdb"
 	"github.com/brronsuite/broln/kvdb/etcd"
 	"github.com/brronsuite/broln/kvdb/postgres"
+	"github.com/brronsuite/broln/kvdb/sqlite"
 	"github.com/brronsuite/broln/lnwallet/bronwallet"
 )
 
@@ -18,12 +19,26 @@ const (
 	towerClientDBName = "wtclient.db"
 	towerServerDBName = "watchtower.db"
 
+	// SqliteChannelDBName is the name of the sqlite database file that
+	// holds the graph, channel state, decayed log and tower client data.
+	SqliteChannelDBName = "channel.sqlite"
+
+	// SqliteChainDBName is the name of the sqlite database file that holds
+	// the wallet and macaroon data.
+	SqliteChainDBName = "chain.sqlite"
+
+	// SqliteTowerDBName is the name of the sqlite database file that holds
+	// the watchtower server data.
+	SqliteTowerDBName = "watchtower.sqlite"
+
 	BoltBackend                = "bolt"
 	EtcdBackend                = "etcd"
 	PostgresBackend            = "postgres"
+	SqliteBackend              = "sqlite"
 	DefaultBatchCommitInterval = 500 * time.Millisecond
 
 	defaultPostgresMaxConnections = 50
+	defaultSqliteBusyTimeout      = 5 * time.Second
 
 	// NSChannelDB is the namespace name that we use for the combined graph
 	// and channel state DB.
@@ -60,6 +75,8 @@ type DB struct {
 
 	Postgres *postgres.Config `group:"postgres" namespace:"postgres" description:"Postgres settings."`
 
+	Sqlite *sqlite.Config `group:"sqlite" namespace:"sqlite" description:"Sqlite settings."`
+
 	NoGraphCache bool `long:"no-graph-cache" description:"Don't use the in-memory graph cache for path finding. Much slower but uses less RAM. Can only be used with a bolt database backend."`
 }
 
@@ -80,6 +97,9 @@ func DefaultDB() *DB {
 		Postgres: &postgres.Config{
 			MaxConnections: defaultPostgresMaxConnections,
 		},
+		Sqlite: &sqlite.Config{
+			BusyTimeout: defaultSqliteBusyTimeout,
+		},
 	}
 }
 
@@ -92,14 +112,21 @@ func (db *DB) Validate() error {
 			return fmt.Errorf("postgres dsn must be set")
 		}
 
+	case SqliteBackend:
+		if db.Sqlite.BusyTimeout < 0 {
+			return fmt.Errorf("sqlite busy timeout must not be " +
+				"negative")
+		}
+
 	case EtcdBackend:
 		if !db.Etcd.Embedded && db.Etcd.Host == "" {
 			return fmt.Errorf("etcd host must be set")
 		}
 
 	default:
-		return fmt.Errorf("unknown backend, must be either '%v' or "+
-			"'%v'", BoltBackend, EtcdBackend)
+		return fmt.Errorf("unknown backend, must be one of '%v', "+
+			"'%v', '%v' or '%v'", BoltBackend, EtcdBackend,
+			PostgresBackend, SqliteBackend)
 	}
 
 	// The path finding uses a manual read transaction that's open for a
@@ -386,6 +413,105 @@ func (db *DB) GetBackends(ctx context.Context, chanDBPath,
 			Remote:     true,
 			CloseFuncs: closeFuncs,
 		}, nil
+
+	case SqliteBackend:
+		// All the critical channel state and graph data lives in a
+		// single file in the channel DB directory. Each sub DB gets
+		// its own table prefix within that file.
+		sqliteBackend, err := kvdb.Open(
+			kvdb.SqliteBackendName, ctx, db.Sqlite, chanDBPath,
+			SqliteChannelDBName, NSChannelDB,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error opening sqlite graph "+
+				"DB: %v", err)
+		}
+		closeFuncs[NSChannelDB] = sqliteBackend.Close
+
+		// The macaroon and wallet data is stored next to each other in
+		// the chain specific wallet directory, just like the bbolt
+		// macaroons.db and wallet.db files.
+		sqliteMacaroonBackend, err := kvdb.Open(
+			kvdb.SqliteBackendName, ctx, db.Sqlite, walletDBPath,
+			SqliteChainDBName, NSMacaroonDB,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error opening sqlite "+
+				"macaroon DB: %v", err)
+		}
+		closeFuncs[NSMacaroonDB] = sqliteMacaroonBackend.Close
+
+		sqliteDecayedLogBackend, err := kvdb.Open(
+			kvdb.SqliteBackendName, ctx, db.Sqlite, chanDBPath,
+			SqliteChannelDBName, NSDecayedLogDB,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error opening sqlite decayed "+
+				"log DB: %v", err)
+		}
+		closeFuncs[NSDecayedLogDB] = sqliteDecayedLogBackend.Close
+
+		// The tower client is optional and might not be enabled by the
+		// user. We handle it being nil properly in the main server.
+		var sqliteTowerClientBackend kvdb.Backend
+		if towerClientEnabled {
+			sqliteTowerClientBackend, err = kvdb.Open(
+				kvdb.SqliteBackendName, ctx, db.Sqlite,
+				chanDBPath, SqliteChannelDBName,
+				NSTowerClientDB,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("error opening sqlite "+
+					"tower client DB: %v", err)
+			}
+			closeFuncs[NSTowerClientDB] =
+				sqliteTowerClientBackend.Close
+		}
+
+		// The tower server is optional and might not be enabled by the
+		// user. We handle it being nil properly in the main server.
+		var sqliteTowerServerBackend kvdb.Backend
+		if towerServerEnabled {
+			sqliteTowerServerBackend, err = kvdb.Open(
+				kvdb.SqliteBackendName, ctx, db.Sqlite,
+				towerServerDBPath, SqliteTowerDBName,
+				NSTowerServerDB,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("error opening sqlite "+
+					"tower server DB: %v", err)
+			}
+			closeFuncs[NSTowerServerDB] =
+				sqliteTowerServerBackend.Close
+		}
+
+		sqliteWalletBackend, err := kvdb.Open(
+			kvdb.SqliteBackendName, ctx, db.Sqlite, walletDBPath,
+			SqliteChainDBName, NSWalletDB,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error opening sqlite wallet "+
+				"DB: %v", err)
+		}
+		closeFuncs[NSWalletDB] = sqliteWalletBackend.Close
+
+		returnEarly = false
+		return &DatabaseBackends{
+			GraphDB:       sqliteBackend,
+			ChanStateDB:   sqliteBackend,
+			HeightHintDB:  sqliteBackend,
+			MacaroonDB:    sqliteMacaroonBackend,
+			DecayedLogDB:  sqliteDecayedLogBackend,
+			TowerClientDB: sqliteTowerClientBackend,
+			TowerServerDB: sqliteTowerServerBackend,
+			// The wallet is stored in the same sqlite file as the
+			// macaroons, so we hand the already opened backend to
+			// the wallet loader.
+			WalletDB: bronwallet.LoaderWithExternalWalletDB(
+				sqliteWalletBackend,
+			),
+			CloseFuncs: closeFuncs,
+		}, nil
 	}
 
 	// We're using all bbolt based databases by default.
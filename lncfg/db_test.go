@@ -21,4 +21,17 @@ func TestDBDefaultConfig(t *testing.T) {
 	// Implicitly, the following fields are default to false.
 	require.False(t, defaultConfig.Bolt.AutoCompact)
 	require.True(t, defaultConfig.Bolt.NoFreelistSync)
+	require.NotZero(t, defaultConfig.Sqlite.BusyTimeout)
+}
+
+// TestDBValidateSqlite tests that the sqlite backend is accepted by the DB
+// config validation.
+func TestDBValidateSqlite(t *testing.T) {
+	cfg := lncfg.DefaultDB()
+	cfg.Backend = lncfg.SqliteBackend
+	require.NoError(t, cfg.Validate())
+
+	// The graph cache can only be disabled for bbolt.
+	cfg.NoGraphCache = true
+	require.Error(t, cfg.Validate())
 }
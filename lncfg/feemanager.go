@@ -0,0 +1,89 @@
+package lncfg
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultFeeManagerInterval is the default interval at which the fee
+	// manager adjusts the fee rates of our channels.
+	DefaultFeeManagerInterval = time.Hour
+
+	// DefaultFeeManagerMinFeeRate is the default lowest fee rate in parts
+	// per million that the fee manager sets on a channel.
+	DefaultFeeManagerMinFeeRate = 1
+
+	// DefaultFeeManagerMaxFeeRate is the default highest fee rate in parts
+	// per million that the fee manager sets on a channel.
+	DefaultFeeManagerMaxFeeRate = 1000
+
+	// DefaultFeeManagerForwardingWindow is the default period over which
+	// the forwarding volume of a channel is measured.
+	DefaultFeeManagerForwardingWindow = 7 * 24 * time.Hour
+
+	// DefaultFeeManagerMinUpdateInterval is the default minimum time
+	// between two fee rate updates of the same channel.
+	DefaultFeeManagerMinUpdateInterval = 6 * time.Hour
+
+	// DefaultFeeManagerMinChangeRatio is the default minimum relative
+	// change of the fee rate of a channel that warrants a new channel
+	// update.
+	DefaultFeeManagerMinChangeRatio = 0.1
+)
+
+// FeeManager holds the configuration options for the automatic fee manager.
+type FeeManager struct {
+	Active            bool          `long:"active" description:"If true, the fee rates of all channels are adjusted on a schedule, based on their local balance and forwarding volume."`
+	Interval          time.Duration `long:"interval" description:"The interval at which the fee rates of all channels are adjusted."`
+	MinFeeRate        uint32        `long:"min-fee-rate" description:"The lowest fee rate in parts per million that is set on a channel."`
+	MaxFeeRate        uint32        `long:"max-fee-rate" description:"The highest fee rate in parts per million that is set on a channel."`
+	ForwardingWindow  time.Duration `long:"forwarding-window" description:"The period over which the forwarding volume of a channel is measured."`
+	MinUpdateInterval time.Duration `long:"min-update-interval" description:"The minimum time between two fee rate updates of the same channel, which limits the number of channel updates that are gossiped."`
+	MinChangeRatio    float64       `long:"min-change-ratio" description:"The minimum relative change of the fee rate of a channel that warrants a new channel update."`
+}
+
+// Validate checks the values configured for the fee manager.
+//
+// NOTE: Part of the Validator interface.
+func (f *FeeManager) Validate() error {
+	switch {
+	case f.Interval <= 0:
+		return fmt.Errorf("fee manager interval must be positive")
+
+	case f.MinFeeRate > f.MaxFeeRate:
+		return fmt.Errorf("fee manager min fee rate of %v exceeds max "+
+			"fee rate of %v", f.MinFeeRate, f.MaxFeeRate)
+
+	case f.ForwardingWindow <= 0:
+		return fmt.Errorf("fee manager forwarding window must be " +
+			"positive")
+
+	case f.MinUpdateInterval < 0:
+		return fmt.Errorf("fee manager min update interval cannot be " +
+			"negative")
+
+	case f.MinChangeRatio < 0:
+		return fmt.Errorf("fee manager min change ratio cannot be " +
+			"negative")
+	}
+
+	return nil
+}
+
+// DefaultFeeManager returns the default values for the fee manager
+// configuration.
+func DefaultFeeManager() *FeeManager {
+	return &FeeManager{
+		Interval:          DefaultFeeManagerInterval,
+		MinFeeRate:        DefaultFeeManagerMinFeeRate,
+		MaxFeeRate:        DefaultFeeManagerMaxFeeRate,
+		ForwardingWindow:  DefaultFeeManagerForwardingWindow,
+		MinUpdateInterval: DefaultFeeManagerMinUpdateInterval,
+		MinChangeRatio:    DefaultFeeManagerMinChangeRatio,
+	}
+}
+
+// Compile-time constraint to ensure FeeManager implements the Validator
+// interface.
+var _ Validator = (*FeeManager)(nil)
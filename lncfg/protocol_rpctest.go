@@ -30,6 +30,42 @@ type ProtocolOptions struct {
 	//
 	// TODO: Move to experimental?
 	ScriptEnforcedLease bool `long:"script-enforced-lease" description:"enable support for script enforced lease commitments"`
+
+	// OptionScidAlias should be set if we want to signal the
+	// option-scid-alias feature bit. This allows scid aliases and the
+	// option-scid-alias channel-type.
+	OptionScidAlias bool `long:"option-scid-alias" description:"enable support for option_scid_alias channels"`
+
+	// OptionZeroConf should be set if we want to signal the zero-conf
+	// feature bit.
+	OptionZeroConf bool `long:"zero-conf" description:"enable support for zero-conf channels, must have option-scid-alias set also"`
+
+	// OptionOnionMessages should be set if we want to signal the
+	// onion-messages feature bit and relay onion messages for our peers.
+	OptionOnionMessages bool `long:"onion-messages" description:"enable support for sending, receiving and relaying onion messages"`
+
+	// OptionRouteBlinding should be set if we want to signal the
+	// route-blinding feature bit and forward payments through blinded
+	// routes.
+	OptionRouteBlinding bool `long:"route-blinding" description:"enable support for forwarding and receiving payments through blinded routes"`
+
+	// OptionDualFund should be set if we want to signal the dual-fund
+	// feature bit and open and accept dual funded channels.
+	OptionDualFund bool `long:"dual-fund" description:"enable support for opening and accepting dual funded channels"`
+
+	// OptionSplice should be set if we want to signal the splice and
+	// quiescence feature bits and resize channels on-chain.
+	OptionSplice bool `long:"splice" description:"enable support for resizing channels on-chain through splicing"`
+
+	// OptionRbfCoopClose should be set if we want to signal the RBF
+	// cooperative close feature bit and negotiate cooperative closes
+	// whose fee can be bumped by either party.
+	OptionRbfCoopClose bool `long:"rbf-coop-close" description:"enable support for cooperative closes whose fee can be bumped through RBF"`
+
+	// OptionPeerStorage should be set if we want to signal the
+	// provide-storage feature bit, store backup blobs for our channel
+	// peers and hand our own channel backup to peers that offer storage.
+	OptionPeerStorage bool `long:"peer-storage" description:"enable storing channel backups with peers and storing the backups of our channel peers"`
 }
 
 // Wumbo returns true if broln should permit the creation and acceptance of wumbo
@@ -49,3 +85,47 @@ func (l *ProtocolOptions) NoAnchorCommitments() bool {
 func (l *ProtocolOptions) NoScriptEnforcementLease() bool {
 	return !l.ScriptEnforcedLease
 }
+
+// ScidAlias returns true if we have enabled the option-scid-alias feature bit.
+func (l *ProtocolOptions) ScidAlias() bool {
+	return l.OptionScidAlias
+}
+
+// ZeroConf returns true if we have enabled the zero-conf feature bit.
+func (l *ProtocolOptions) ZeroConf() bool {
+	return l.OptionZeroConf
+}
+
+// OnionMessages returns true if we have enabled the onion-messages feature
+// bit.
+func (l *ProtocolOptions) OnionMessages() bool {
+	return l.OptionOnionMessages
+}
+
+// RouteBlinding returns true if we have enabled the route-blinding feature
+// bit.
+func (l *ProtocolOptions) RouteBlinding() bool {
+	return l.OptionRouteBlinding
+}
+
+// DualFund returns true if we have enabled the dual-fund feature bit.
+func (l *ProtocolOptions) DualFund() bool {
+	return l.OptionDualFund
+}
+
+// Splice returns true if we have enabled the splice feature bit.
+func (l *ProtocolOptions) Splice() bool {
+	return l.OptionSplice
+}
+
+// RbfCoopClose returns true if we have enabled the RBF cooperative close
+// feature bit.
+func (l *ProtocolOptions) RbfCoopClose() bool {
+	return l.OptionRbfCoopClose
+}
+
+// PeerStorage returns true if we have enabled the provide-storage feature
+// bit.
+func (l *ProtocolOptions) PeerStorage() bool {
+	return l.OptionPeerStorage
+}
@@ -0,0 +1,86 @@
+package lncfg
+
+import (
+	"errors"
+	"net/url"
+)
+
+const (
+	// DefaultRemoteBackupS3Region is the default region of the bucket
+	// that channel backups are uploaded to.
+	DefaultRemoteBackupS3Region = "us-east-1"
+
+	// DefaultRemoteBackupS3Key is the default object key that channel
+	// backups are uploaded to.
+	DefaultRemoteBackupS3Key = "channel.backup"
+)
+
+// RemoteBackup holds the configuration of the remote destinations that every
+// updated multi-channel backup is pushed to, in addition to the backup file on
+// disk.
+type RemoteBackup struct {
+	S3 *RemoteBackupS3 `group:"s3" namespace:"s3"`
+
+	WebDAV *RemoteBackupWebDAV `group:"webdav" namespace:"webdav"`
+
+	Hook string `long:"hook" description:"The path of an executable that is run with every updated multi-channel backup as its standard input. A non-zero exit code marks the upload as failed and it is retried."`
+}
+
+// RemoteBackupS3 holds the configuration of an S3-compatible destination for
+// channel backups.
+type RemoteBackupS3 struct {
+	Endpoint        string `long:"endpoint" description:"The URL of the S3-compatible service to upload channel backups to, for example https://s3.us-east-1.amazonaws.com. If not set, backups aren't uploaded to S3."`
+	Region          string `long:"region" description:"The region of the bucket, used to sign requests."`
+	Bucket          string `long:"bucket" description:"The bucket to upload channel backups to."`
+	Key             string `long:"key" description:"The object key to upload channel backups to."`
+	AccessKeyID     string `long:"accesskeyid" description:"The access key ID used to sign requests."`
+	SecretAccessKey string `long:"secretaccesskey" description:"The secret access key used to sign requests."`
+}
+
+// RemoteBackupWebDAV holds the configuration of a WebDAV destination for
+// channel backups.
+type RemoteBackupWebDAV struct {
+	URL      string `long:"url" description:"The URL of the file on a WebDAV server to upload channel backups to. If not set, backups aren't uploaded to WebDAV."`
+	User     string `long:"user" description:"The user for basic authentication with the WebDAV server."`
+	Password string `long:"password" description:"The password for basic authentication with the WebDAV server."`
+}
+
+// Validate checks the values configured for remote channel backups.
+//
+// NOTE: Part of the Validator interface.
+func (r *RemoteBackup) Validate() error {
+	if r.S3.Endpoint != "" {
+		if _, err := url.ParseRequestURI(r.S3.Endpoint); err != nil {
+			return errors.New("invalid remote backup s3 endpoint")
+		}
+
+		if r.S3.Bucket == "" || r.S3.Key == "" {
+			return errors.New("remote backup s3 bucket and key " +
+				"must be set")
+		}
+	}
+
+	if r.WebDAV.URL != "" {
+		if _, err := url.ParseRequestURI(r.WebDAV.URL); err != nil {
+			return errors.New("invalid remote backup webdav url")
+		}
+	}
+
+	return nil
+}
+
+// DefaultRemoteBackup returns the default values for the remote channel
+// backup configuration.
+func DefaultRemoteBackup() *RemoteBackup {
+	return &RemoteBackup{
+		S3: &RemoteBackupS3{
+			Region: DefaultRemoteBackupS3Region,
+			Key:    DefaultRemoteBackupS3Key,
+		},
+		WebDAV: &RemoteBackupWebDAV{},
+	}
+}
+
+// Compile-time constraint to ensure RemoteBackup implements the Validator
+// interface.
+var _ Validator = (*RemoteBackup)(nil)
@@ -0,0 +1,34 @@
+package lncfg
+
+import (
+	"fmt"
+
+	"github.com/brronsuite/broln/sweep"
+)
+
+// Sweeper holds the configuration options for the UtxoSweeper.
+type Sweeper struct {
+	FeeFunction string `long:"feefunction" description:"The function along which the fee rate of time-sensitive sweeps, such as those of HTLCs after a force close, is raised towards their budget as their deadline approaches." choice:"linear" choice:"cubic-delay" choice:"cubic-eager"`
+}
+
+// Validate checks the values configured for the sweeper.
+//
+// NOTE: Part of the Validator interface.
+func (s *Sweeper) Validate() error {
+	if _, err := sweep.FeeFunctionByName(s.FeeFunction); err != nil {
+		return fmt.Errorf("invalid sweeper fee function: %v", err)
+	}
+
+	return nil
+}
+
+// DefaultSweeper returns the default values for the sweeper configuration.
+func DefaultSweeper() *Sweeper {
+	return &Sweeper{
+		FeeFunction: sweep.DefaultFeeFunction,
+	}
+}
+
+// Compile-time constraint to ensure Sweeper implements the Validator
+// interface.
+var _ Validator = (*Sweeper)(nil)
@@ -0,0 +1,102 @@
+package lncfg
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/brronsuite/broln/routing/route"
+)
+
+const (
+	// DefaultTrampolineFeeBaseMsat is the default base fee in
+	// millibroneess that we expect to pay a trampoline node.
+	DefaultTrampolineFeeBaseMsat = 1000
+
+	// DefaultTrampolineFeeRatePPM is the default fee rate in parts per
+	// million that we expect to pay a trampoline node.
+	DefaultTrampolineFeeRatePPM = 5000
+
+	// DefaultTrampolineCltvDelta is the default CLTV delta that we expect
+	// a trampoline node to require.
+	DefaultTrampolineCltvDelta = 288
+
+	// DefaultTrampolineForwardFeeBaseMsat is the default base fee in
+	// millibroneess that we charge for forwarding trampoline payments.
+	DefaultTrampolineForwardFeeBaseMsat = DefaultTrampolineFeeBaseMsat
+
+	// DefaultTrampolineForwardFeeRatePPM is the default fee rate in parts
+	// per million that we charge for forwarding trampoline payments.
+	DefaultTrampolineForwardFeeRatePPM = DefaultTrampolineFeeRatePPM
+
+	// DefaultTrampolineForwardCltvDelta is the default CLTV delta that we
+	// require for forwarding trampoline payments.
+	DefaultTrampolineForwardCltvDelta = DefaultTrampolineCltvDelta
+
+	// DefaultTrampolineMppTimeout is the default time we wait for all
+	// htlcs of a trampoline payment to arrive.
+	DefaultTrampolineMppTimeout = time.Minute
+)
+
+// Trampoline holds the configuration options for payments through trampoline
+// nodes.
+type Trampoline struct {
+	Pay         bool   `long:"pay" description:"If true, payments are sent through a trampoline node that finds the route to the recipient on our behalf. The trampoline node is taken from the invoice if it announces one, or from trampoline.node otherwise."`
+	Node        string `long:"node" description:"The hex encoded public key of the trampoline node to pay through if the invoice doesn't announce one."`
+	FeeBaseMsat uint32 `long:"fee-base-msat" description:"The base fee in millibroneess that we pay the trampoline node set by trampoline.node."`
+	FeeRatePPM  uint32 `long:"fee-rate-ppm" description:"The fee rate in parts per million that we pay the trampoline node set by trampoline.node."`
+	CltvDelta   uint16 `long:"cltv-delta" description:"The CLTV delta that the trampoline node set by trampoline.node requires."`
+
+	Forward            bool          `long:"forward" description:"If true, we forward trampoline payments by finding the route to the next trampoline node or the final recipient on behalf of the sender. Support for trampoline routing is advertised to our peers and in our node announcement."`
+	ForwardFeeBaseMsat uint32        `long:"forward-fee-base-msat" description:"The base fee in millibroneess that we charge for forwarding a trampoline payment. The fee covers the routing fees of the outgoing payment, and whatever isn't spent on routing is earned by us."`
+	ForwardFeeRatePPM  uint32        `long:"forward-fee-rate-ppm" description:"The fee rate in parts per million that we charge for forwarding a trampoline payment."`
+	ForwardCltvDelta   uint16        `long:"forward-cltv-delta" description:"The CLTV delta that we require for forwarding a trampoline payment. It must cover the CLTV deltas of the route to the next trampoline node or the final recipient."`
+	MppTimeout         time.Duration `long:"mpp-timeout" description:"The time we wait for all htlcs of a trampoline payment to arrive before failing them."`
+}
+
+// Validate checks the values configured for trampoline payments.
+//
+// NOTE: Part of the Validator interface.
+func (t *Trampoline) Validate() error {
+	if t.Node != "" {
+		if _, err := route.NewVertexFromStr(t.Node); err != nil {
+			return fmt.Errorf("invalid trampoline node: %v", err)
+		}
+	}
+
+	if !t.Forward {
+		return nil
+	}
+
+	// The CLTV delta of trampoline forwards must leave room for a route
+	// after reserving the blocks we need to claim the incoming htlcs.
+	if uint32(t.ForwardCltvDelta) <= DefaultFinalCltvRejectDelta {
+		return fmt.Errorf("trampoline forward cltv delta must be "+
+			"greater than %v", DefaultFinalCltvRejectDelta)
+	}
+
+	if t.MppTimeout <= 0 {
+		return errors.New("trampoline mpp timeout must be positive")
+	}
+
+	return nil
+}
+
+// DefaultTrampoline returns the default values for the trampoline
+// configuration.
+func DefaultTrampoline() *Trampoline {
+	return &Trampoline{
+		FeeBaseMsat: DefaultTrampolineFeeBaseMsat,
+		FeeRatePPM:  DefaultTrampolineFeeRatePPM,
+		CltvDelta:   DefaultTrampolineCltvDelta,
+
+		ForwardFeeBaseMsat: DefaultTrampolineForwardFeeBaseMsat,
+		ForwardFeeRatePPM:  DefaultTrampolineForwardFeeRatePPM,
+		ForwardCltvDelta:   DefaultTrampolineForwardCltvDelta,
+		MppTimeout:         DefaultTrampolineMppTimeout,
+	}
+}
+
+// Compile-time constraint to ensure Trampoline implements the Validator
+// interface.
+var _ Validator = (*Trampoline)(nil)
@@ -0,0 +1,18 @@
+//go:build feemanagerrpc
+// +build feemanagerrpc
+
+package feemanagerrpc
+
+import (
+	"github.com/brronsuite/broln/routing/feemanager"
+)
+
+// Config is the primary configuration struct for the fee manager RPC server.
+// It contains all the items required for the rpc server to carry out its
+// duties. The fields with struct tags are meant to be parsed as normal
+// configuration options, while if able to be populated, the latter fields MUST
+// also be specified.
+type Config struct {
+	// Manager is the fee manager of the daemon.
+	Manager *feemanager.Manager
+}
@@ -0,0 +1,7 @@
+//go:build !feemanagerrpc
+// +build !feemanagerrpc
+
+package feemanagerrpc
+
+// Config is empty for non-feemanagerrpc builds.
+type Config struct{}
@@ -0,0 +1,503 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.27.1
+// 	protoc        v3.6.1
+// source: feemanagerrpc/feemanager.proto
+
+package feemanagerrpc
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type StatusRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *StatusRequest) Reset() {
+	*x = StatusRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_feemanagerrpc_feemanager_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusRequest) ProtoMessage() {}
+
+func (x *StatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_feemanagerrpc_feemanager_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusRequest.ProtoReflect.Descriptor instead.
+func (*StatusRequest) Descriptor() ([]byte, []int) {
+	return file_feemanagerrpc_feemanager_proto_rawDescGZIP(), []int{0}
+}
+
+type StatusResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Indicates whether the fee manager adjusts fee rates on a schedule.
+	Active bool `protobuf:"varint,1,opt,name=active,proto3" json:"active,omitempty"`
+	//
+	//The unix timestamp in seconds at which the fee rates were last adjusted.
+	//Zero if they weren't adjusted yet.
+	LastRun int64 `protobuf:"varint,2,opt,name=last_run,json=lastRun,proto3" json:"last_run,omitempty"`
+}
+
+func (x *StatusResponse) Reset() {
+	*x = StatusResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_feemanagerrpc_feemanager_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusResponse) ProtoMessage() {}
+
+func (x *StatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_feemanagerrpc_feemanager_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusResponse.ProtoReflect.Descriptor instead.
+func (*StatusResponse) Descriptor() ([]byte, []int) {
+	return file_feemanagerrpc_feemanager_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *StatusResponse) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *StatusResponse) GetLastRun() int64 {
+	if x != nil {
+		return x.LastRun
+	}
+	return 0
+}
+
+type AdjustFeesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// If set, the adjustments are only reported and not applied.
+	DryRun bool `protobuf:"varint,1,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+}
+
+func (x *AdjustFeesRequest) Reset() {
+	*x = AdjustFeesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_feemanagerrpc_feemanager_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdjustFeesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdjustFeesRequest) ProtoMessage() {}
+
+func (x *AdjustFeesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_feemanagerrpc_feemanager_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdjustFeesRequest.ProtoReflect.Descriptor instead.
+func (*AdjustFeesRequest) Descriptor() ([]byte, []int) {
+	return file_feemanagerrpc_feemanager_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *AdjustFeesRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type ChannelFeeAdjustment struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The outpoint of the channel in format txid:n.
+	ChannelPoint string `protobuf:"bytes,1,opt,name=channel_point,json=channelPoint,proto3" json:"channel_point,omitempty"`
+	// The short channel id of the channel.
+	ChanId uint64 `protobuf:"varint,2,opt,name=chan_id,json=chanId,proto3" json:"chan_id,omitempty"`
+	// The share of the channel capacity that is on our side of the channel.
+	LocalBalanceRatio float64 `protobuf:"fixed64,3,opt,name=local_balance_ratio,json=localBalanceRatio,proto3" json:"local_balance_ratio,omitempty"`
+	// The amount that was forwarded over the channel within the forwarding
+	// window, in milli-broneess.
+	ForwardedMsat uint64 `protobuf:"varint,4,opt,name=forwarded_msat,json=forwardedMsat,proto3" json:"forwarded_msat,omitempty"`
+	// The current fee rate of the channel in parts per million.
+	OldFeeRatePpm uint32 `protobuf:"varint,5,opt,name=old_fee_rate_ppm,json=oldFeeRatePpm,proto3" json:"old_fee_rate_ppm,omitempty"`
+	// The target fee rate of the channel in parts per million.
+	NewFeeRatePpm uint32 `protobuf:"varint,6,opt,name=new_fee_rate_ppm,json=newFeeRatePpm,proto3" json:"new_fee_rate_ppm,omitempty"`
+	// Whether the target fee rate was applied to the channel.
+	Applied bool `protobuf:"varint,7,opt,name=applied,proto3" json:"applied,omitempty"`
+	// The reason why the target fee rate isn't applied, if it isn't.
+	SkipReason string `protobuf:"bytes,8,opt,name=skip_reason,json=skipReason,proto3" json:"skip_reason,omitempty"`
+}
+
+func (x *ChannelFeeAdjustment) Reset() {
+	*x = ChannelFeeAdjustment{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_feemanagerrpc_feemanager_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ChannelFeeAdjustment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChannelFeeAdjustment) ProtoMessage() {}
+
+func (x *ChannelFeeAdjustment) ProtoReflect() protoreflect.Message {
+	mi := &file_feemanagerrpc_feemanager_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChannelFeeAdjustment.ProtoReflect.Descriptor instead.
+func (*ChannelFeeAdjustment) Descriptor() ([]byte, []int) {
+	return file_feemanagerrpc_feemanager_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ChannelFeeAdjustment) GetChannelPoint() string {
+	if x != nil {
+		return x.ChannelPoint
+	}
+	return ""
+}
+
+func (x *ChannelFeeAdjustment) GetChanId() uint64 {
+	if x != nil {
+		return x.ChanId
+	}
+	return 0
+}
+
+func (x *ChannelFeeAdjustment) GetLocalBalanceRatio() float64 {
+	if x != nil {
+		return x.LocalBalanceRatio
+	}
+	return 0
+}
+
+func (x *ChannelFeeAdjustment) GetForwardedMsat() uint64 {
+	if x != nil {
+		return x.ForwardedMsat
+	}
+	return 0
+}
+
+func (x *ChannelFeeAdjustment) GetOldFeeRatePpm() uint32 {
+	if x != nil {
+		return x.OldFeeRatePpm
+	}
+	return 0
+}
+
+func (x *ChannelFeeAdjustment) GetNewFeeRatePpm() uint32 {
+	if x != nil {
+		return x.NewFeeRatePpm
+	}
+	return 0
+}
+
+func (x *ChannelFeeAdjustment) GetApplied() bool {
+	if x != nil {
+		return x.Applied
+	}
+	return false
+}
+
+func (x *ChannelFeeAdjustment) GetSkipReason() string {
+	if x != nil {
+		return x.SkipReason
+	}
+	return ""
+}
+
+type AdjustFeesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The fee rate adjustment of each channel.
+	Adjustments []*ChannelFeeAdjustment `protobuf:"bytes,1,rep,name=adjustments,proto3" json:"adjustments,omitempty"`
+}
+
+func (x *AdjustFeesResponse) Reset() {
+	*x = AdjustFeesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_feemanagerrpc_feemanager_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdjustFeesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdjustFeesResponse) ProtoMessage() {}
+
+func (x *AdjustFeesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_feemanagerrpc_feemanager_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdjustFeesResponse.ProtoReflect.Descriptor instead.
+func (*AdjustFeesResponse) Descriptor() ([]byte, []int) {
+	return file_feemanagerrpc_feemanager_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *AdjustFeesResponse) GetAdjustments() []*ChannelFeeAdjustment {
+	if x != nil {
+		return x.Adjustments
+	}
+	return nil
+}
+
+var File_feemanagerrpc_feemanager_proto protoreflect.FileDescriptor
+
+var file_feemanagerrpc_feemanager_proto_rawDesc = []byte{
+	0x0a, 0x1e, 0x66, 0x65, 0x65, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2f,
+	0x66, 0x65, 0x65, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x12, 0x0d, 0x66, 0x65, 0x65, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x72, 0x70, 0x63, 0x22,
+	0x0f, 0x0a, 0x0d, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x22, 0x43, 0x0a, 0x0e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x6c, 0x61,
+	0x73, 0x74, 0x5f, 0x72, 0x75, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x6c, 0x61,
+	0x73, 0x74, 0x52, 0x75, 0x6e, 0x22, 0x2c, 0x0a, 0x11, 0x41, 0x64, 0x6a, 0x75, 0x73, 0x74, 0x46,
+	0x65, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x64, 0x72,
+	0x79, 0x5f, 0x72, 0x75, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x64, 0x72, 0x79,
+	0x52, 0x75, 0x6e, 0x22, 0xbc, 0x02, 0x0a, 0x14, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x46,
+	0x65, 0x65, 0x41, 0x64, 0x6a, 0x75, 0x73, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x23, 0x0a, 0x0d,
+	0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x5f, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0c, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x50, 0x6f, 0x69, 0x6e,
+	0x74, 0x12, 0x1b, 0x0a, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x04, 0x42, 0x02, 0x30, 0x01, 0x52, 0x06, 0x63, 0x68, 0x61, 0x6e, 0x49, 0x64, 0x12, 0x2e,
+	0x0a, 0x13, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x5f, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x5f,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x11, 0x6c, 0x6f, 0x63,
+	0x61, 0x6c, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x61, 0x74, 0x69, 0x6f, 0x12, 0x25,
+	0x0a, 0x0e, 0x66, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x65, 0x64, 0x5f, 0x6d, 0x73, 0x61, 0x74,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0d, 0x66, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x65,
+	0x64, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x27, 0x0a, 0x10, 0x6f, 0x6c, 0x64, 0x5f, 0x66, 0x65, 0x65,
+	0x5f, 0x72, 0x61, 0x74, 0x65, 0x5f, 0x70, 0x70, 0x6d, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x0d, 0x6f, 0x6c, 0x64, 0x46, 0x65, 0x65, 0x52, 0x61, 0x74, 0x65, 0x50, 0x70, 0x6d, 0x12, 0x27,
+	0x0a, 0x10, 0x6e, 0x65, 0x77, 0x5f, 0x66, 0x65, 0x65, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x5f, 0x70,
+	0x70, 0x6d, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0d, 0x6e, 0x65, 0x77, 0x46, 0x65, 0x65,
+	0x52, 0x61, 0x74, 0x65, 0x50, 0x70, 0x6d, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x70, 0x70, 0x6c, 0x69,
+	0x65, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x61, 0x70, 0x70, 0x6c, 0x69, 0x65,
+	0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x6b, 0x69, 0x70, 0x5f, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x73, 0x6b, 0x69, 0x70, 0x52, 0x65, 0x61, 0x73,
+	0x6f, 0x6e, 0x22, 0x5b, 0x0a, 0x12, 0x41, 0x64, 0x6a, 0x75, 0x73, 0x74, 0x46, 0x65, 0x65, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x45, 0x0a, 0x0b, 0x61, 0x64, 0x6a, 0x75,
+	0x73, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x23, 0x2e,
+	0x66, 0x65, 0x65, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x68,
+	0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x46, 0x65, 0x65, 0x41, 0x64, 0x6a, 0x75, 0x73, 0x74, 0x6d, 0x65,
+	0x6e, 0x74, 0x52, 0x0b, 0x61, 0x64, 0x6a, 0x75, 0x73, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x32,
+	0xa6, 0x01, 0x0a, 0x0a, 0x46, 0x65, 0x65, 0x4d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x12, 0x45,
+	0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1c, 0x2e, 0x66, 0x65, 0x65, 0x6d, 0x61,
+	0x6e, 0x61, 0x67, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x66, 0x65, 0x65, 0x6d, 0x61, 0x6e, 0x61,
+	0x67, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x51, 0x0a, 0x0a, 0x41, 0x64, 0x6a, 0x75, 0x73, 0x74, 0x46,
+	0x65, 0x65, 0x73, 0x12, 0x20, 0x2e, 0x66, 0x65, 0x65, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72,
+	0x72, 0x70, 0x63, 0x2e, 0x41, 0x64, 0x6a, 0x75, 0x73, 0x74, 0x46, 0x65, 0x65, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x66, 0x65, 0x65, 0x6d, 0x61, 0x6e, 0x61, 0x67,
+	0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x64, 0x6a, 0x75, 0x73, 0x74, 0x46, 0x65, 0x65, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x31, 0x5a, 0x2f, 0x67, 0x69, 0x74, 0x68,
+	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x62, 0x72, 0x72, 0x6f, 0x6e, 0x73, 0x75, 0x69, 0x74,
+	0x65, 0x2f, 0x62, 0x72, 0x6f, 0x6c, 0x6e, 0x2f, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2f, 0x66, 0x65,
+	0x65, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
+}
+
+var (
+	file_feemanagerrpc_feemanager_proto_rawDescOnce sync.Once
+	file_feemanagerrpc_feemanager_proto_rawDescData = file_feemanagerrpc_feemanager_proto_rawDesc
+)
+
+func file_feemanagerrpc_feemanager_proto_rawDescGZIP() []byte {
+	file_feemanagerrpc_feemanager_proto_rawDescOnce.Do(func() {
+		file_feemanagerrpc_feemanager_proto_rawDescData = protoimpl.X.CompressGZIP(file_feemanagerrpc_feemanager_proto_rawDescData)
+	})
+	return file_feemanagerrpc_feemanager_proto_rawDescData
+}
+
+var file_feemanagerrpc_feemanager_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_feemanagerrpc_feemanager_proto_goTypes = []interface{}{
+	(*StatusRequest)(nil),        // 0: feemanagerrpc.StatusRequest
+	(*StatusResponse)(nil),       // 1: feemanagerrpc.StatusResponse
+	(*AdjustFeesRequest)(nil),    // 2: feemanagerrpc.AdjustFeesRequest
+	(*ChannelFeeAdjustment)(nil), // 3: feemanagerrpc.ChannelFeeAdjustment
+	(*AdjustFeesResponse)(nil),   // 4: feemanagerrpc.AdjustFeesResponse
+}
+var file_feemanagerrpc_feemanager_proto_depIdxs = []int32{
+	3, // 0: feemanagerrpc.AdjustFeesResponse.adjustments:type_name -> feemanagerrpc.ChannelFeeAdjustment
+	0, // 1: feemanagerrpc.FeeManager.Status:input_type -> feemanagerrpc.StatusRequest
+	2, // 2: feemanagerrpc.FeeManager.AdjustFees:input_type -> feemanagerrpc.AdjustFeesRequest
+	1, // 3: feemanagerrpc.FeeManager.Status:output_type -> feemanagerrpc.StatusResponse
+	4, // 4: feemanagerrpc.FeeManager.AdjustFees:output_type -> feemanagerrpc.AdjustFeesResponse
+	3, // [3:5] is the sub-list for method output_type
+	1, // [1:3] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_feemanagerrpc_feemanager_proto_init() }
+func file_feemanagerrpc_feemanager_proto_init() {
+	if File_feemanagerrpc_feemanager_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_feemanagerrpc_feemanager_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StatusRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_feemanagerrpc_feemanager_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StatusResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_feemanagerrpc_feemanager_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AdjustFeesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_feemanagerrpc_feemanager_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChannelFeeAdjustment); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_feemanagerrpc_feemanager_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AdjustFeesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_feemanagerrpc_feemanager_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_feemanagerrpc_feemanager_proto_goTypes,
+		DependencyIndexes: file_feemanagerrpc_feemanager_proto_depIdxs,
+		MessageInfos:      file_feemanagerrpc_feemanager_proto_msgTypes,
+	}.Build()
+	File_feemanagerrpc_feemanager_proto = out.File
+	file_feemanagerrpc_feemanager_proto_rawDesc = nil
+	file_feemanagerrpc_feemanager_proto_goTypes = nil
+	file_feemanagerrpc_feemanager_proto_depIdxs = nil
+}
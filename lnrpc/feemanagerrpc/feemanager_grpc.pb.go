@@ -0,0 +1,155 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.6.1
+// source: feemanagerrpc/feemanager.proto
+
+package feemanagerrpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// FeeManagerClient is the client API for FeeManager service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type FeeManagerClient interface {
+	//
+	//Status returns whether the fee manager adjusts fee rates on a schedule, and
+	//when it last adjusted them.
+	Status(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*StatusResponse, error)
+	//
+	//AdjustFees determines the target fee rate of each channel, and updates the
+	//policies of the channels for which a new channel update is warranted. In
+	//dry-run mode, the adjustments are only reported.
+	AdjustFees(ctx context.Context, in *AdjustFeesRequest, opts ...grpc.CallOption) (*AdjustFeesResponse, error)
+}
+
+type feeManagerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFeeManagerClient(cc grpc.ClientConnInterface) FeeManagerClient {
+	return &feeManagerClient{cc}
+}
+
+func (c *feeManagerClient) Status(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*StatusResponse, error) {
+	out := new(StatusResponse)
+	err := c.cc.Invoke(ctx, "/feemanagerrpc.FeeManager/Status", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *feeManagerClient) AdjustFees(ctx context.Context, in *AdjustFeesRequest, opts ...grpc.CallOption) (*AdjustFeesResponse, error) {
+	out := new(AdjustFeesResponse)
+	err := c.cc.Invoke(ctx, "/feemanagerrpc.FeeManager/AdjustFees", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FeeManagerServer is the server API for FeeManager service.
+// All implementations must embed UnimplementedFeeManagerServer
+// for forward compatibility
+type FeeManagerServer interface {
+	//
+	//Status returns whether the fee manager adjusts fee rates on a schedule, and
+	//when it last adjusted them.
+	Status(context.Context, *StatusRequest) (*StatusResponse, error)
+	//
+	//AdjustFees determines the target fee rate of each channel, and updates the
+	//policies of the channels for which a new channel update is warranted. In
+	//dry-run mode, the adjustments are only reported.
+	AdjustFees(context.Context, *AdjustFeesRequest) (*AdjustFeesResponse, error)
+	mustEmbedUnimplementedFeeManagerServer()
+}
+
+// UnimplementedFeeManagerServer must be embedded to have forward compatible implementations.
+type UnimplementedFeeManagerServer struct {
+}
+
+func (UnimplementedFeeManagerServer) Status(context.Context, *StatusRequest) (*StatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Status not implemented")
+}
+func (UnimplementedFeeManagerServer) AdjustFees(context.Context, *AdjustFeesRequest) (*AdjustFeesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdjustFees not implemented")
+}
+func (UnimplementedFeeManagerServer) mustEmbedUnimplementedFeeManagerServer() {}
+
+// UnsafeFeeManagerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FeeManagerServer will
+// result in compilation errors.
+type UnsafeFeeManagerServer interface {
+	mustEmbedUnimplementedFeeManagerServer()
+}
+
+func RegisterFeeManagerServer(s grpc.ServiceRegistrar, srv FeeManagerServer) {
+	s.RegisterService(&FeeManager_ServiceDesc, srv)
+}
+
+func _FeeManager_Status_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FeeManagerServer).Status(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/feemanagerrpc.FeeManager/Status",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FeeManagerServer).Status(ctx, req.(*StatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FeeManager_AdjustFees_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdjustFeesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FeeManagerServer).AdjustFees(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/feemanagerrpc.FeeManager/AdjustFees",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FeeManagerServer).AdjustFees(ctx, req.(*AdjustFeesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// FeeManager_ServiceDesc is the grpc.ServiceDesc for FeeManager service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var FeeManager_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "feemanagerrpc.FeeManager",
+	HandlerType: (*FeeManagerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Status",
+			Handler:    _FeeManager_Status_Handler,
+		},
+		{
+			MethodName: "AdjustFees",
+			Handler:    _FeeManager_AdjustFees_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "feemanagerrpc/feemanager.proto",
+}
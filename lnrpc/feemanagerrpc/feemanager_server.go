@@ -0,0 +1,234 @@
+//go:build feemanagerrpc
+// +build feemanagerrpc
+
+package feemanagerrpc
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/brronsuite/broln/lnrpc"
+	"github.com/brronsuite/broln/routing/feemanager"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"gopkg.in/macaroon-bakery.v2/bakery"
+)
+
+const (
+	// subServerName is the name of the sub rpc server. We'll use this name
+	// to register ourselves, and we also require that the main
+	// SubServerConfigDispatcher instance recognize it as the name of our
+	// RPC service.
+	subServerName = "FeeManagerRPC"
+)
+
+var (
+	// macPermissions maps RPC calls to the permissions they require.
+	macPermissions = map[string][]bakery.Op{
+		"/feemanagerrpc.FeeManager/Status": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/feemanagerrpc.FeeManager/AdjustFees": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+	}
+)
+
+// ServerShell is a shell struct holding a reference to the actual sub-server.
+// It is used to register the gRPC sub-server with the root server before we
+// have the necessary dependencies to populate the actual sub-server.
+type ServerShell struct {
+	FeeManagerServer
+}
+
+// Server is a sub-server of the main RPC server: the fee manager RPC. This sub
+// RPC server allows external callers to inspect the automatic fee manager, and
+// to trigger or preview fee rate adjustments.
+type Server struct {
+	started  int32 // To be used atomically.
+	shutdown int32 // To be used atomically.
+
+	// Required by the grpc-gateway/v2 library for forward compatibility.
+	// Must be after the atomically used variables to not break struct
+	// alignment.
+	UnimplementedFeeManagerServer
+
+	cfg *Config
+
+	manager *feemanager.Manager
+}
+
+// A compile time check to ensure that Server fully implements the
+// FeeManagerServer gRPC service.
+var _ FeeManagerServer = (*Server)(nil)
+
+// New returns a new instance of the feemanagerrpc FeeManager sub-server. We
+// also return the set of permissions for the macaroons that we may create
+// within this method.
+func New(cfg *Config) (*Server, lnrpc.MacaroonPerms, error) {
+	// We don't create any new macaroons for this subserver, instead reuse
+	// existing offchain permissions.
+	server := &Server{
+		cfg:     cfg,
+		manager: cfg.Manager,
+	}
+
+	return server, macPermissions, nil
+}
+
+// Start launches any helper goroutines required for the Server to function.
+// The fee manager itself is started by the main server, as its schedule
+// doesn't depend on the RPC server.
+//
+// NOTE: This is part of the lnrpc.SubServer interface.
+func (s *Server) Start() error {
+	atomic.AddInt32(&s.started, 1)
+
+	return nil
+}
+
+// Stop signals any active goroutines for a graceful closure.
+//
+// NOTE: This is part of the lnrpc.SubServer interface.
+func (s *Server) Stop() error {
+	atomic.AddInt32(&s.shutdown, 1)
+
+	return nil
+}
+
+// Name returns a unique string representation of the sub-server. This can be
+// used to identify the sub-server and also de-duplicate them.
+//
+// NOTE: This is part of the lnrpc.SubServer interface.
+func (s *Server) Name() string {
+	return subServerName
+}
+
+// RegisterWithRootServer will be called by the root gRPC server to direct a
+// sub RPC server to register itself with the main gRPC root server. Until this
+// is called, each sub-server won't be able to have requests routed towards it.
+//
+// NOTE: This is part of the lnrpc.GrpcHandler interface.
+func (r *ServerShell) RegisterWithRootServer(grpcServer *grpc.Server) error {
+	// We make sure that we register it with the main gRPC server to ensure
+	// all our methods are routed properly.
+	RegisterFeeManagerServer(grpcServer, r)
+
+	log.Debugf("FeeManager RPC server successfully register with root " +
+		"gRPC server")
+
+	return nil
+}
+
+// RegisterWithRestServer will be called by the root REST mux to direct a sub
+// RPC server to register itself with the main REST mux server. Until this is
+// called, each sub-server won't be able to have requests routed towards it.
+//
+// NOTE: This is part of the lnrpc.GrpcHandler interface.
+func (r *ServerShell) RegisterWithRestServer(ctx context.Context,
+	mux *runtime.ServeMux, dest string, opts []grpc.DialOption) error {
+
+	// We make sure that we register it with the main REST server to ensure
+	// all our methods are routed properly.
+	err := RegisterFeeManagerHandlerFromEndpoint(ctx, mux, dest, opts)
+	if err != nil {
+		log.Errorf("Could not register FeeManager REST server "+
+			"with root REST server: %v", err)
+		return err
+	}
+
+	log.Debugf("FeeManager REST server successfully registered with " +
+		"root REST server")
+	return nil
+}
+
+// CreateSubServer populates the subserver's dependencies using the passed
+// SubServerConfigDispatcher. This method should fully initialize the
+// sub-server instance, making it ready for action. It returns the macaroon
+// permissions that the sub-server wishes to pass on to the root server for all
+// methods routed towards it.
+//
+// NOTE: This is part of the lnrpc.GrpcHandler interface.
+func (r *ServerShell) CreateSubServer(
+	configRegistry lnrpc.SubServerConfigDispatcher) (lnrpc.SubServer,
+	lnrpc.MacaroonPerms, error) {
+
+	subServer, macPermissions, err := createNewSubServer(configRegistry)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r.FeeManagerServer = subServer
+	return subServer, macPermissions, nil
+}
+
+// Status returns whether the fee manager adjusts fee rates on a schedule, and
+// when it last adjusted them.
+//
+// NOTE: Part of the FeeManagerServer interface.
+func (s *Server) Status(ctx context.Context,
+	in *StatusRequest) (*StatusResponse, error) {
+
+	status := s.manager.Status()
+
+	resp := &StatusResponse{
+		Active: status.Active,
+	}
+	if !status.LastRun.IsZero() {
+		resp.LastRun = status.LastRun.Unix()
+	}
+
+	return resp, nil
+}
+
+// AdjustFees determines the target fee rate of each channel, and updates the
+// policies of the channels for which a new channel update is warranted. In
+// dry-run mode, the adjustments are only reported.
+//
+// NOTE: Part of the FeeManagerServer interface.
+func (s *Server) AdjustFees(ctx context.Context,
+	in *AdjustFeesRequest) (*AdjustFeesResponse, error) {
+
+	log.Debugf("Adjusting fee rates, dry_run=%v", in.DryRun)
+
+	adjustments, err := s.manager.AdjustFees(in.DryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &AdjustFeesResponse{
+		Adjustments: make(
+			[]*ChannelFeeAdjustment, 0, len(adjustments),
+		),
+	}
+	for _, adjustment := range adjustments {
+		resp.Adjustments = append(
+			resp.Adjustments, marshallAdjustment(adjustment),
+		)
+	}
+
+	return resp, nil
+}
+
+// marshallAdjustment converts a fee rate adjustment into its RPC
+// representation.
+func marshallAdjustment(a feemanager.Adjustment) *ChannelFeeAdjustment {
+	skipReason := string(a.SkipReason)
+	if a.UpdateErr != nil {
+		skipReason = fmt.Sprintf("%v: %v", skipReason, a.UpdateErr)
+	}
+
+	return &ChannelFeeAdjustment{
+		ChannelPoint:      a.ChanPoint.String(),
+		ChanId:            a.ChanID.ToUint64(),
+		LocalBalanceRatio: a.LocalBalanceRatio,
+		ForwardedMsat:     uint64(a.ForwardedAmt),
+		OldFeeRatePpm:     a.OldFeeRate,
+		NewFeeRatePpm:     a.NewFeeRate,
+		Applied:           a.Applied,
+		SkipReason:        skipReason,
+	}
+}
@@ -41,6 +41,11 @@ type AddInvoiceConfig struct {
 	// IsChannelActive is used to generate valid hop hints.
 	IsChannelActive func(chanID lnwire.ChannelID) bool
 
+	// GetAlias returns the alias the peer assigned to the channel. It is
+	// used instead of the channel's SCID in hop hints for scid-alias
+	// channels.
+	GetAlias func(lnwire.ChannelID) (lnwire.ShortChannelID, error)
+
 	// ChainParams are required to properly decode invoice payment requests
 	// that are marshalled over rpc.
 	ChainParams *chaincfg.Params
@@ -67,6 +72,10 @@ type AddInvoiceConfig struct {
 	// GenAmpInvoiceFeatures returns a feature containing feature bits that
 	// should be advertised on freshly generated AMP invoices.
 	GenAmpInvoiceFeatures func() *lnwire.FeatureVector
+
+	// BestHeight returns the height of the best block of the main chain.
+	// It is used to set the expiry constraints of blinded paths.
+	BestHeight func() (uint32, error)
 }
 
 // AddInvoiceData contains the required data to create a new invoice.
@@ -120,6 +129,11 @@ type AddInvoiceData struct {
 	// RouteHints are optional route hints that can each be individually used
 	// to assist in reaching the invoice's destination.
 	RouteHints [][]zpay32.HopHint
+
+	// Blind signals that the invoice should reach us through blinded paths
+	// over our private channels instead of route hints, so that the
+	// channels aren't revealed to the payer.
+	Blind bool
 }
 
 // paymentHashAndPreimage returns the payment hash and preimage for this invoice
@@ -252,6 +266,18 @@ func AddInvoice(ctx context.Context, cfg *AddInvoiceConfig,
 			maxInvoiceAmt)
 	}
 
+	// Blinded paths end at the payment address of the invoice, which the
+	// payer can't use for AMP payments, and replace route hints entirely.
+	switch {
+	case invoice.Blind && invoice.Amp:
+		return nil, nil, fmt.Errorf("blinded paths are not supported " +
+			"for AMP invoices")
+
+	case invoice.Blind && len(invoice.RouteHints) > 0:
+		return nil, nil, fmt.Errorf("route hints can't be combined " +
+			"with blinded paths")
+	}
+
 	amtMSat := invoice.Value
 
 	// We also create an encoded payment request which allows the
@@ -279,6 +305,7 @@ func AddInvoice(ctx context.Context, cfg *AddInvoiceConfig,
 		options = append(options, zpay32.FallbackAddr(addr))
 	}
 
+	var expiry time.Duration
 	switch {
 
 	// If expiry is set, specify it. If it is not provided, no expiry time
@@ -298,16 +325,18 @@ func AddInvoice(ctx context.Context, cfg *AddInvoiceConfig,
 				float64(expSeconds), maxExpiry.Seconds())
 		}
 
-		expiry := time.Duration(invoice.Expiry) * time.Second
+		expiry = time.Duration(invoice.Expiry) * time.Second
 		options = append(options, zpay32.Expiry(expiry))
 
 	// If no custom expiry is provided, use the default MPP expiry.
 	case !invoice.Amp:
-		options = append(options, zpay32.Expiry(DefaultInvoiceExpiry))
+		expiry = DefaultInvoiceExpiry
+		options = append(options, zpay32.Expiry(expiry))
 
 	// Otherwise, use the default AMP expiry.
 	default:
-		options = append(options, zpay32.Expiry(DefaultAMPInvoiceExpiry))
+		expiry = DefaultAMPInvoiceExpiry
+		options = append(options, zpay32.Expiry(expiry))
 
 	}
 
@@ -325,6 +354,7 @@ func AddInvoice(ctx context.Context, cfg *AddInvoiceConfig,
 
 	// We'll use our current default CLTV value unless one was specified as
 	// an option on the command line when creating an invoice.
+	finalCltvDelta := uint64(cfg.DefaultCLTVExpiry)
 	switch {
 	case invoice.CltvExpiry > math.MaxUint16:
 		return nil, nil, fmt.Errorf("CLTV delta of %v is too large, max "+
@@ -338,12 +368,21 @@ func AddInvoice(ctx context.Context, cfg *AddInvoiceConfig,
 				routing.MinCLTVDelta, invoice.CltvExpiry)
 		}
 
+		finalCltvDelta = invoice.CltvExpiry
 		options = append(options,
 			zpay32.CLTVExpiry(invoice.CltvExpiry))
 	default:
 		// TODO(roasbeef): assumes set delta between versions
-		defaultDelta := cfg.DefaultCLTVExpiry
-		options = append(options, zpay32.CLTVExpiry(uint64(defaultDelta)))
+		options = append(options, zpay32.CLTVExpiry(finalCltvDelta))
+	}
+
+	// Generate a random payment address for this invoice. If the sender
+	// understands payment addresses, this can be used to avoid
+	// intermediaries probing the receiver. Blinded paths also use it to
+	// identify the invoice they lead to.
+	var paymentAddr [32]byte
+	if _, err := rand.Read(paymentAddr[:]); err != nil {
+		return nil, nil, err
 	}
 
 	// We make sure that the given invoice routing hints number is within the
@@ -367,10 +406,36 @@ func AddInvoice(ctx context.Context, cfg *AddInvoiceConfig,
 		forcedHints[h[0].ChannelID] = struct{}{}
 	}
 
+	switch {
+
+	// If we were requested to reach us through blinded paths, then we'll
+	// build them over our available private channels instead of
+	// revealing the channels in routing hints.
+	case invoice.Blind:
+		openChannels, err := cfg.ChanDB.FetchAllChannels()
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not fetch all " +
+				"channels")
+		}
+
+		blindedPaths, err := SelectBlindedPaths(
+			amtMSat, cfg, openChannels, maxBlindedPaths,
+			&blindedPathParams{
+				paymentAddr:    paymentAddr,
+				finalCltvDelta: uint16(finalCltvDelta),
+				expiry:         expiry,
+			},
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		options = append(options, blindedPaths...)
+
 	// If we were requested to include routing hints in the invoice, then
 	// we'll fetch all of our available private channels and create routing
 	// hints for them.
-	if invoice.Private {
+	case invoice.Private:
 		openChannels, err := cfg.ChanDB.FetchAllChannels()
 		if err != nil {
 			return nil, nil, fmt.Errorf("could not fetch all channels")
@@ -407,13 +472,6 @@ func AddInvoice(ctx context.Context, cfg *AddInvoiceConfig,
 	}
 	options = append(options, zpay32.Features(invoiceFeatures))
 
-	// Generate and set a random payment address for this invoice. If the
-	// sender understands payment addresses, this can be used to avoid
-	// intermediaries probing the receiver.
-	var paymentAddr [32]byte
-	if _, err := rand.Read(paymentAddr[:]); err != nil {
-		return nil, nil, err
-	}
 	options = append(options, zpay32.PaymentAddr(paymentAddr))
 
 	// Create and encode the payment request as a bech32 (zpay32) string.
@@ -465,15 +523,17 @@ func AddInvoice(ctx context.Context, cfg *AddInvoiceConfig,
 }
 
 // chanCanBeHopHint returns true if the target channel is eligible to be a hop
-// hint.
+// hint. The short channel ID to use in the hop hint is returned as well.
 func chanCanBeHopHint(channel *channeldb.OpenChannel, cfg *AddInvoiceConfig) (
-	*channeldb.ChannelEdgePolicy, bool) {
+	*channeldb.ChannelEdgePolicy, lnwire.ShortChannelID, bool) {
+
+	var zeroScid lnwire.ShortChannelID
 
 	// Since we're only interested in our private channels, we'll skip
 	// public ones.
 	isPublic := channel.ChannelFlags&lnwire.FFAnnounceChannel != 0
 	if isPublic {
-		return nil, false
+		return nil, zeroScid, false
 	}
 
 	// Make sure the channel is active.
@@ -484,7 +544,24 @@ func chanCanBeHopHint(channel *channeldb.OpenChannel, cfg *AddInvoiceConfig) (
 		log.Debugf("Skipping channel %v due to not "+
 			"being eligible to forward payments",
 			chanPoint)
-		return nil, false
+		return nil, zeroScid, false
+	}
+
+	// The peer only knows an scid-alias channel by the alias it sent us,
+	// so that's what we need to put in the hop hint.
+	hintScid := channel.ShortChanID()
+	if channel.HasScidAlias() {
+		if cfg.GetAlias == nil {
+			return nil, zeroScid, false
+		}
+
+		alias, err := cfg.GetAlias(chanPoint)
+		if err != nil {
+			log.Debugf("Skipping channel %v due to missing "+
+				"peer alias: %v", chanPoint, err)
+			return nil, zeroScid, false
+		}
+		hintScid = alias
 	}
 
 	// To ensure we don't leak unadvertised nodes, we'll make sure our
@@ -498,14 +575,14 @@ func chanCanBeHopHint(channel *channeldb.OpenChannel, cfg *AddInvoiceConfig) (
 	if err != nil {
 		log.Errorf("Unable to determine if node %x "+
 			"is advertised: %v", remotePub, err)
-		return nil, false
+		return nil, zeroScid, false
 	}
 
 	if !isRemoteNodePublic {
 		log.Debugf("Skipping channel %v due to "+
 			"counterparty %x being unadvertised",
 			chanPoint, remotePub)
-		return nil, false
+		return nil, zeroScid, false
 	}
 
 	// Fetch the policies for each end of the channel.
@@ -515,7 +592,7 @@ func chanCanBeHopHint(channel *channeldb.OpenChannel, cfg *AddInvoiceConfig) (
 		log.Errorf("Unable to fetch the routing "+
 			"policies for the edges of the channel "+
 			"%v: %v", chanPoint, err)
-		return nil, false
+		return nil, zeroScid, false
 	}
 
 	// Now, we'll need to determine which is the correct policy for HTLCs
@@ -527,17 +604,18 @@ func chanCanBeHopHint(channel *channeldb.OpenChannel, cfg *AddInvoiceConfig) (
 		remotePolicy = p2
 	}
 
-	return remotePolicy, true
+	return remotePolicy, hintScid, true
 }
 
-// addHopHint creates a hop hint out of the passed channel and channel policy.
-// The new hop hint is appended to the passed slice.
+// addHopHint creates a hop hint out of the passed channel, short channel ID
+// and channel policy. The new hop hint is appended to the passed slice.
 func addHopHint(hopHints *[]func(*zpay32.Invoice),
-	channel *channeldb.OpenChannel, chanPolicy *channeldb.ChannelEdgePolicy) {
+	channel *channeldb.OpenChannel, hintScid lnwire.ShortChannelID,
+	chanPolicy *channeldb.ChannelEdgePolicy) {
 
 	hopHint := zpay32.HopHint{
 		NodeID:      channel.IdentityPub,
-		ChannelID:   channel.ShortChanID().ToUint64(),
+		ChannelID:   hintScid.ToUint64(),
 		FeeBaseMSat: uint32(chanPolicy.FeeBaseMSat),
 		FeeProportionalMillionths: uint32(
 			chanPolicy.FeeProportionalMillionths,
@@ -549,24 +627,52 @@ func addHopHint(hopHints *[]func(*zpay32.Invoice),
 	)
 }
 
+// hopHintChannel is a private channel that was selected to reach us through,
+// together with the short channel ID and policy a payer has to use for it.
+type hopHintChannel struct {
+	channel *channeldb.OpenChannel
+	scid    lnwire.ShortChannelID
+	policy  *channeldb.ChannelEdgePolicy
+}
+
 // SelectHopHints will select up to numMaxHophints from the set of passed open
 // channels. The set of hop hints will be returned as a slice of functional
 // options that'll append the route hint to the set of all route hints.
-//
-// TODO(roasbeef): do proper sub-set sum max hints usually << numChans
 func SelectHopHints(amtMSat lnwire.MilliBronees, cfg *AddInvoiceConfig,
 	openChannels []*channeldb.OpenChannel,
 	numMaxHophints int) []func(*zpay32.Invoice) {
 
+	hintChans := selectHopHintChannels(
+		amtMSat, cfg, openChannels, numMaxHophints,
+	)
+
+	hopHints := make([]func(*zpay32.Invoice), 0, len(hintChans))
+	for _, c := range hintChans {
+		addHopHint(&hopHints, c.channel, c.scid, c.policy)
+	}
+
+	return hopHints
+}
+
+// selectHopHintChannels will select up to numMaxHophints channels from the
+// set of passed open channels that are eligible to be used as hop hints.
+//
+// TODO(roasbeef): do proper sub-set sum max hints usually << numChans
+func selectHopHintChannels(amtMSat lnwire.MilliBronees, cfg *AddInvoiceConfig,
+	openChannels []*channeldb.OpenChannel,
+	numMaxHophints int) []*hopHintChannel {
+
 	// We'll add our hop hints in two passes, first we'll add all channels
 	// that are eligible to be hop hints, and also have a local balance
 	// above the payment amount.
 	var totalHintBandwidth lnwire.MilliBronees
 	hopHintChans := make(map[wire.OutPoint]struct{})
-	hopHints := make([]func(*zpay32.Invoice), 0, numMaxHophints)
+	hopHints := make([]*hopHintChannel, 0, numMaxHophints)
 	for _, channel := range openChannels {
 		// If this channel can't be a hop hint, then skip it.
-		edgePolicy, canBeHopHint := chanCanBeHopHint(channel, cfg)
+		edgePolicy, hintScid, canBeHopHint := chanCanBeHopHint(
+			channel, cfg,
+		)
 		if edgePolicy == nil || !canBeHopHint {
 			continue
 		}
@@ -579,7 +685,11 @@ func SelectHopHints(amtMSat lnwire.MilliBronees, cfg *AddInvoiceConfig,
 
 		// Now that we now this channel use usable, add it as a hop
 		// hint and the indexes we'll use later.
-		addHopHint(&hopHints, channel, edgePolicy)
+		hopHints = append(hopHints, &hopHintChannel{
+			channel: channel,
+			scid:    hintScid,
+			policy:  edgePolicy,
+		})
 
 		hopHintChans[channel.FundingOutpoint] = struct{}{}
 		totalHintBandwidth += channel.LocalCommitment.RemoteBalance
@@ -616,14 +726,20 @@ func SelectHopHints(amtMSat lnwire.MilliBronees, cfg *AddInvoiceConfig,
 		// If the channel can't be a hop hint, then we'll skip it.
 		// Otherwise, we'll use the policy information to populate the
 		// hop hint.
-		remotePolicy, canBeHopHint := chanCanBeHopHint(channel, cfg)
+		remotePolicy, hintScid, canBeHopHint := chanCanBeHopHint(
+			channel, cfg,
+		)
 		if !canBeHopHint || remotePolicy == nil {
 			continue
 		}
 
-		// Include the route hint in our set of options that will be
+		// Include the route hint in our set of channels that will be
 		// used when creating the invoice.
-		addHopHint(&hopHints, channel, remotePolicy)
+		hopHints = append(hopHints, &hopHintChannel{
+			channel: channel,
+			scid:    hintScid,
+			policy:  remotePolicy,
+		})
 
 		// As we've just added a new hop hint, we'll accumulate it's
 		// available balance now to update our tally.
@@ -0,0 +1,233 @@
+package invoicesrpc
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/brronsuite/broln/blinding"
+	"github.com/brronsuite/broln/channeldb"
+	"github.com/brronsuite/broln/lnwire"
+	"github.com/brronsuite/broln/zpay32"
+	"github.com/brronsuite/brond/bronec"
+)
+
+const (
+	// maxBlindedPaths is the maximum number of blinded paths we add to an
+	// invoice. Blinded paths are considerably larger than route hints, so
+	// we use fewer of them to keep the invoice at a reasonable size.
+	maxBlindedPaths = 3
+
+	// blindedPathExpiryMargin is the number of blocks that HTLCs may
+	// expire after the latest expiry we expect for a payment of the
+	// invoice, to leave room for blocks being mined while the payment is
+	// in flight and for the shadow route of the sender.
+	blindedPathExpiryMargin = 144
+
+	// blockInterval is the expected time between two blocks, which is used
+	// to convert the invoice expiry into blocks.
+	blockInterval = 10 * time.Minute
+)
+
+// blindedPathParams holds the invoice specific parameters of the blinded paths
+// that are created for it.
+type blindedPathParams struct {
+	// paymentAddr is the payment address of the invoice, which is used as
+	// the path id of our own hop.
+	paymentAddr [32]byte
+
+	// finalCltvDelta is the final CLTV delta of the invoice.
+	finalCltvDelta uint16
+
+	// expiry is the expiry of the invoice.
+	expiry time.Duration
+}
+
+// SelectBlindedPaths will create up to numMaxPaths blinded paths to our node
+// from the set of passed open channels. Each path is introduced by the peer of
+// one of the channels that would otherwise be used as a hop hint, so that the
+// channel itself isn't revealed to the payer. If none of the channels can be
+// used, which is the case for nodes with only public channels, a single path
+// introduced by our own node is created instead. The paths are returned as a
+// slice of functional options that'll add them to the invoice.
+func SelectBlindedPaths(amtMSat lnwire.MilliBronees, cfg *AddInvoiceConfig,
+	openChannels []*channeldb.OpenChannel, numMaxPaths int,
+	params *blindedPathParams) ([]func(*zpay32.Invoice), error) {
+
+	hintChans := selectHopHintChannels(
+		amtMSat, cfg, openChannels, numMaxPaths,
+	)
+
+	bestHeight, err := cfg.BestHeight()
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch best height: %v", err)
+	}
+
+	// Our hop must accept HTLCs until the payer could still be paying the
+	// invoice and the final CLTV delta has elapsed.
+	expiryBlocks := uint32(params.expiry / blockInterval)
+	maxCltvExpiry := bestHeight + expiryBlocks +
+		uint32(params.finalCltvDelta) + blindedPathExpiryMargin
+
+	if len(hintChans) == 0 {
+		path, err := newSelfBlindedPaymentPath(
+			cfg, openChannels, params, maxCltvExpiry,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		return []func(*zpay32.Invoice){zpay32.BlindedPath(path)}, nil
+	}
+
+	paths := make([]func(*zpay32.Invoice), 0, len(hintChans))
+	for _, c := range hintChans {
+		path, err := newBlindedPaymentPath(
+			cfg, c, params, maxCltvExpiry,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		paths = append(paths, zpay32.BlindedPath(path))
+	}
+
+	return paths, nil
+}
+
+// newBlindedPaymentPath creates a blinded path to our node that is introduced
+// by the peer of the passed channel.
+func newBlindedPaymentPath(cfg *AddInvoiceConfig, c *hopHintChannel,
+	params *blindedPathParams,
+	maxCltvExpiry uint32) (*zpay32.BlindedPaymentPath, error) {
+
+	policy := c.policy
+	localMinHTLC := c.channel.LocalChanCfg.MinHTLC
+
+	// The peer forwards the HTLC over the channel to us using the policy
+	// it advertised for it. It must not accept HTLCs that would expire
+	// after our own constraint once the delta of the channel is applied.
+	introData := &blinding.RouteData{
+		ShortChannelID: &c.scid,
+		PaymentRelay: &blinding.PaymentRelay{
+			CltvExpiryDelta: policy.TimeLockDelta,
+			FeeProportionalMillionths: uint32(
+				policy.FeeProportionalMillionths,
+			),
+			FeeBaseMsat: uint32(policy.FeeBaseMSat),
+		},
+		PaymentConstraints: &blinding.PaymentConstraints{
+			MaxCltvExpiry: maxCltvExpiry +
+				uint32(policy.TimeLockDelta),
+			HtlcMinimumMsat: policy.MinHTLC,
+		},
+	}
+
+	// The payment address we put in our own hop tells us which invoice
+	// the payment is for.
+	finalData := &blinding.RouteData{
+		PathID: params.paymentAddr[:],
+		PaymentConstraints: &blinding.PaymentConstraints{
+			MaxCltvExpiry:   maxCltvExpiry,
+			HtlcMinimumMsat: localMinHTLC,
+		},
+	}
+
+	introPlainText, err := blinding.EncodeRouteData(introData)
+	if err != nil {
+		return nil, err
+	}
+
+	finalPlainText, err := blinding.EncodeRouteData(finalData)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionKey, err := bronec.NewPrivateKey(bronec.S256())
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := blinding.BuildBlindedPath(sessionKey, []*blinding.HopInfo{
+		{
+			NodePub:   c.channel.IdentityPub,
+			PlainText: introPlainText,
+		},
+		{
+			NodePub:   cfg.NodeSigner.PubKey(),
+			PlainText: finalPlainText,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The payer needs to pay at least the minimum of both hops, including
+	// the fee the peer takes for forwarding the minimum to us.
+	htlcMin := localMinHTLC + policy.ComputeFee(localMinHTLC)
+	if policy.MinHTLC > htlcMin {
+		htlcMin = policy.MinHTLC
+	}
+
+	// We can't receive more than the balance of the peer in the channel.
+	htlcMax := c.channel.LocalCommitment.RemoteBalance
+	if policy.MessageFlags.HasMaxHtlc() && policy.MaxHTLC < htlcMax {
+		htlcMax = policy.MaxHTLC
+	}
+
+	return &zpay32.BlindedPaymentPath{
+		FeeBaseMsat:     uint32(policy.FeeBaseMSat),
+		FeeRate:         uint32(policy.FeeProportionalMillionths),
+		CltvExpiryDelta: policy.TimeLockDelta + params.finalCltvDelta,
+		HTLCMinMsat:     uint64(htlcMin),
+		HTLCMaxMsat:     uint64(htlcMax),
+		Features:        lnwire.EmptyFeatureVector(),
+		Path:            path,
+	}, nil
+}
+
+// newSelfBlindedPaymentPath creates a blinded path that is introduced by our
+// own node. Such a path doesn't hide our node, but it lets payers that only
+// support paying through blinded paths reach us.
+func newSelfBlindedPaymentPath(cfg *AddInvoiceConfig,
+	openChannels []*channeldb.OpenChannel, params *blindedPathParams,
+	maxCltvExpiry uint32) (*zpay32.BlindedPaymentPath, error) {
+
+	// We can receive at most the sum of the balances of our peers in
+	// our channels.
+	var htlcMax lnwire.MilliBronees
+	for _, channel := range openChannels {
+		htlcMax += channel.LocalCommitment.RemoteBalance
+	}
+
+	plainText, err := blinding.EncodeRouteData(&blinding.RouteData{
+		PathID: params.paymentAddr[:],
+		PaymentConstraints: &blinding.PaymentConstraints{
+			MaxCltvExpiry: maxCltvExpiry,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sessionKey, err := bronec.NewPrivateKey(bronec.S256())
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := blinding.BuildBlindedPath(sessionKey, []*blinding.HopInfo{
+		{
+			NodePub:   cfg.NodeSigner.PubKey(),
+			PlainText: plainText,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &zpay32.BlindedPaymentPath{
+		CltvExpiryDelta: params.finalCltvDelta,
+		HTLCMaxMsat:     uint64(htlcMax),
+		Features:        lnwire.EmptyFeatureVector(),
+		Path:            path,
+	}, nil
+}
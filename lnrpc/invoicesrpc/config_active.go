@@ -33,6 +33,10 @@ type Config struct {
 	// IsChannelActive is used to generate valid hop hints.
 	IsChannelActive func(chanID lnwire.ChannelID) bool
 
+	// GetAlias returns the peer's alias of a channel, which is used in
+	// hop hints of scid-alias channels.
+	GetAlias func(lnwire.ChannelID) (lnwire.ShortChannelID, error)
+
 	// ChainParams are required to properly decode invoice payment requests
 	// that are marshalled over rpc.
 	ChainParams *chaincfg.Params
@@ -60,4 +64,7 @@ type Config struct {
 	// GenAmpInvoiceFeatures returns a feature containing feature bits that
 	// should be advertised on freshly generated AMP invoices.
 	GenAmpInvoiceFeatures func() *lnwire.FeatureVector
+
+	// BestHeight returns the height of the best block of the main chain.
+	BestHeight func() (uint32, error)
 }
@@ -321,6 +321,7 @@ func (s *Server) AddHoldInvoice(ctx context.Context,
 	addInvoiceCfg := &AddInvoiceConfig{
 		AddInvoice:            s.cfg.InvoiceRegistry.AddInvoice,
 		IsChannelActive:       s.cfg.IsChannelActive,
+		GetAlias:              s.cfg.GetAlias,
 		ChainParams:           s.cfg.ChainParams,
 		NodeSigner:            s.cfg.NodeSigner,
 		DefaultCLTVExpiry:     s.cfg.DefaultCLTVExpiry,
@@ -328,6 +329,7 @@ func (s *Server) AddHoldInvoice(ctx context.Context,
 		Graph:                 s.cfg.GraphDB,
 		GenInvoiceFeatures:    s.cfg.GenInvoiceFeatures,
 		GenAmpInvoiceFeatures: s.cfg.GenAmpInvoiceFeatures,
+		BestHeight:            s.cfg.BestHeight,
 	}
 
 	hash, err := lntypes.MakeHash(invoice.Hash)
@@ -357,6 +359,7 @@ func (s *Server) AddHoldInvoice(ctx context.Context,
 		HodlInvoice:     true,
 		Preimage:        nil,
 		RouteHints:      routeHints,
+		Blind:           invoice.IsBlinded,
 	}
 
 	_, dbInvoice, err := AddInvoice(ctx, addInvoiceCfg, addInvoiceData)
@@ -217,6 +217,25 @@ func CreateRPCInvoice(invoice *channeldb.Invoice,
 	return rpcInvoice, nil
 }
 
+// UnmarshallInvoiceState converts an rpc invoice state into the state used by
+// the invoice database.
+func UnmarshallInvoiceState(
+	state lnrpc.Invoice_InvoiceState) (channeldb.ContractState, error) {
+
+	switch state {
+	case lnrpc.Invoice_OPEN:
+		return channeldb.ContractOpen, nil
+	case lnrpc.Invoice_SETTLED:
+		return channeldb.ContractSettled, nil
+	case lnrpc.Invoice_CANCELED:
+		return channeldb.ContractCanceled, nil
+	case lnrpc.Invoice_ACCEPTED:
+		return channeldb.ContractAccepted, nil
+	default:
+		return 0, fmt.Errorf("unknown invoice state %v", state)
+	}
+}
+
 // CreateRPCFeatures maps a feature vector into a list of lnrpc.Features.
 func CreateRPCFeatures(fv *lnwire.FeatureVector) map[uint32]*lnrpc.Feature {
 	if fv == nil {
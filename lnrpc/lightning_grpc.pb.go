@@ -160,6 +160,21 @@ type LightningClient interface {
 	//Alternatively, this can be used to interactively drive PSBT signing for
 	//funding for partially complete funding transactions.
 	FundingStateStep(ctx context.Context, in *FundingTransitionMsg, opts ...grpc.CallOption) (*FundingStateStepResp, error)
+	// brolncli: `splicechannel`
+	//SpliceChannel resizes an existing channel on-chain without closing it. A
+	//positive relative amount adds funds from the wallet (or from the inputs of
+	//a funded PSBT) to our side of the channel, a negative amount moves funds
+	//from our side of the channel to a wallet address. The channel can't carry
+	//any HTLCs while being spliced and stays quiescent until the splice
+	//transaction confirms. If a funded PSBT is given, a psbt_sign update
+	//carrying the splice transaction is sent once it's negotiated, and the PSBT
+	//inputs must then be signed and passed to SpliceFinalizePsbt.
+	SpliceChannel(ctx context.Context, in *SpliceChannelRequest, opts ...grpc.CallOption) (Lightning_SpliceChannelClient, error)
+	//
+	//SpliceFinalizePsbt hands the signed PSBT of a PSBT funded splice back to
+	//broln, after which the splice signatures are exchanged with the peer and
+	//the splice transaction is published.
+	SpliceFinalizePsbt(ctx context.Context, in *SpliceFinalizePsbtRequest, opts ...grpc.CallOption) (*SpliceFinalizePsbtResponse, error)
 	//
 	//ChannelAcceptor dispatches a bi-directional streaming RPC in which
 	//OpenChannel requests are sent to the client and the client responds with
@@ -176,6 +191,12 @@ type LightningClient interface {
 	//closure transaction is confirmed, or a manual fee rate. If neither are
 	//specified, then a default lax, block confirmation target is used.
 	CloseChannel(ctx context.Context, in *CloseChannelRequest, opts ...grpc.CallOption) (Lightning_CloseChannelClient, error)
+	// brolncli: `bumpclosefee`
+	//BumpCloseFee proposes a new closing transaction for a channel that is being
+	//cooperatively closed, paying a higher fee from our own channel output. Once
+	//signed by the remote peer, it replaces the current closing transaction. Both
+	//we and the remote peer must support the RBF cooperative close protocol.
+	BumpCloseFee(ctx context.Context, in *BumpCloseFeeRequest, opts ...grpc.CallOption) (*BumpCloseFeeResponse, error)
 	// brolncli: `abandonchannel`
 	//AbandonChannel removes all channel state from the database except for a
 	//close summary. This method can be used to get rid of permanently unusable
@@ -368,6 +389,11 @@ type LightningClient interface {
 	//ups, but the updated set of encrypted multi-chan backups with the closed
 	//channel(s) removed.
 	SubscribeChannelBackups(ctx context.Context, in *ChannelBackupSubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelBackupsClient, error)
+	// brolncli: `backupsinkstatus`
+	//GetBackupSinkStatus returns the upload status of each of the remote
+	//destinations that every updated multi-channel backup is pushed to,
+	//including the time of the last successful upload.
+	GetBackupSinkStatus(ctx context.Context, in *GetBackupSinkStatusRequest, opts ...grpc.CallOption) (*GetBackupSinkStatusResponse, error)
 	// brolncli: `bakemacaroon`
 	//BakeMacaroon allows the creation of a new macaroon with custom read and
 	//write permissions. No first-party caveats are added since this can be done
@@ -404,12 +430,35 @@ type LightningClient interface {
 	//modify responses for requests made with _unencumbered_ macaroons!
 	RegisterRPCMiddleware(ctx context.Context, opts ...grpc.CallOption) (Lightning_RegisterRPCMiddlewareClient, error)
 	// brolncli: `sendcustom`
-	//SendCustomMessage sends a custom peer message.
+	//SendCustomMessage sends a custom peer message. Messages of a type that is
+	//reserved for one of broln's protocol messages are rejected.
 	SendCustomMessage(ctx context.Context, in *SendCustomMessageRequest, opts ...grpc.CallOption) (*SendCustomMessageResponse, error)
 	// brolncli: `subscribecustom`
 	//SubscribeCustomMessages subscribes to a stream of incoming custom peer
 	//messages.
 	SubscribeCustomMessages(ctx context.Context, in *SubscribeCustomMessagesRequest, opts ...grpc.CallOption) (Lightning_SubscribeCustomMessagesClient, error)
+	// brolncli: `sendonion`
+	//SendOnionMessage sends an onion message through a blinded path. Unlike
+	//custom messages, onion messages can reach nodes that aren't direct peers.
+	SendOnionMessage(ctx context.Context, in *SendOnionMessageRequest, opts ...grpc.CallOption) (*SendOnionMessageResponse, error)
+	// brolncli: `subscribeonion`
+	//SubscribeOnionMessages subscribes to a stream of incoming onion messages
+	//that are addressed to this node.
+	SubscribeOnionMessages(ctx context.Context, in *SubscribeOnionMessagesRequest, opts ...grpc.CallOption) (Lightning_SubscribeOnionMessagesClient, error)
+	// brolncli: `offers create`
+	//AddOffer creates a new BOLT 12 offer issued by this node. Payers request
+	//invoices for the offer over onion messages, which are answered
+	//automatically as long as the offer is enabled.
+	AddOffer(ctx context.Context, in *AddOfferRequest, opts ...grpc.CallOption) (*AddOfferResponse, error)
+	// brolncli: `offers list`
+	//ListOffers returns all offers created by this node.
+	ListOffers(ctx context.Context, in *ListOffersRequest, opts ...grpc.CallOption) (*ListOffersResponse, error)
+	// brolncli: `offers disable`
+	//DisableOffer stops answering invoice requests for an offer.
+	DisableOffer(ctx context.Context, in *DisableOfferRequest, opts ...grpc.CallOption) (*DisableOfferResponse, error)
+	// brolncli: `offers decode`
+	//DecodeOffer decodes an lno encoded BOLT 12 offer.
+	DecodeOffer(ctx context.Context, in *DecodeOfferRequest, opts ...grpc.CallOption) (*Offer, error)
 }
 
 type lightningClient struct {
@@ -737,8 +786,49 @@ func (c *lightningClient) FundingStateStep(ctx context.Context, in *FundingTrans
 	return out, nil
 }
 
+func (c *lightningClient) SpliceChannel(ctx context.Context, in *SpliceChannelRequest, opts ...grpc.CallOption) (Lightning_SpliceChannelClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[4], "/lnrpc.Lightning/SpliceChannel", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningSpliceChannelClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_SpliceChannelClient interface {
+	Recv() (*SpliceStatusUpdate, error)
+	grpc.ClientStream
+}
+
+type lightningSpliceChannelClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningSpliceChannelClient) Recv() (*SpliceStatusUpdate, error) {
+	m := new(SpliceStatusUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lightningClient) SpliceFinalizePsbt(ctx context.Context, in *SpliceFinalizePsbtRequest, opts ...grpc.CallOption) (*SpliceFinalizePsbtResponse, error) {
+	out := new(SpliceFinalizePsbtResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/SpliceFinalizePsbt", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) ChannelAcceptor(ctx context.Context, opts ...grpc.CallOption) (Lightning_ChannelAcceptorClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[4], "/lnrpc.Lightning/ChannelAcceptor", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[5], "/lnrpc.Lightning/ChannelAcceptor", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -769,7 +859,7 @@ func (x *lightningChannelAcceptorClient) Recv() (*ChannelAcceptRequest, error) {
 }
 
 func (c *lightningClient) CloseChannel(ctx context.Context, in *CloseChannelRequest, opts ...grpc.CallOption) (Lightning_CloseChannelClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[5], "/lnrpc.Lightning/CloseChannel", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[6], "/lnrpc.Lightning/CloseChannel", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -800,6 +890,15 @@ func (x *lightningCloseChannelClient) Recv() (*CloseStatusUpdate, error) {
 	return m, nil
 }
 
+func (c *lightningClient) BumpCloseFee(ctx context.Context, in *BumpCloseFeeRequest, opts ...grpc.CallOption) (*BumpCloseFeeResponse, error) {
+	out := new(BumpCloseFeeResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/BumpCloseFee", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) AbandonChannel(ctx context.Context, in *AbandonChannelRequest, opts ...grpc.CallOption) (*AbandonChannelResponse, error) {
 	out := new(AbandonChannelResponse)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/AbandonChannel", in, out, opts...)
@@ -811,7 +910,7 @@ func (c *lightningClient) AbandonChannel(ctx context.Context, in *AbandonChannel
 
 // Deprecated: Do not use.
 func (c *lightningClient) SendPayment(ctx context.Context, opts ...grpc.CallOption) (Lightning_SendPaymentClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[6], "/lnrpc.Lightning/SendPayment", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[7], "/lnrpc.Lightning/SendPayment", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -852,7 +951,7 @@ func (c *lightningClient) SendPaymentSync(ctx context.Context, in *SendRequest,
 
 // Deprecated: Do not use.
 func (c *lightningClient) SendToRoute(ctx context.Context, opts ...grpc.CallOption) (Lightning_SendToRouteClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[7], "/lnrpc.Lightning/SendToRoute", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[8], "/lnrpc.Lightning/SendToRoute", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -919,7 +1018,7 @@ func (c *lightningClient) LookupInvoice(ctx context.Context, in *PaymentHash, op
 }
 
 func (c *lightningClient) SubscribeInvoices(ctx context.Context, in *InvoiceSubscription, opts ...grpc.CallOption) (Lightning_SubscribeInvoicesClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[8], "/lnrpc.Lightning/SubscribeInvoices", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[9], "/lnrpc.Lightning/SubscribeInvoices", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1050,7 +1149,7 @@ func (c *lightningClient) StopDaemon(ctx context.Context, in *StopRequest, opts
 }
 
 func (c *lightningClient) SubscribeChannelGraph(ctx context.Context, in *GraphTopologySubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelGraphClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[9], "/lnrpc.Lightning/SubscribeChannelGraph", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[10], "/lnrpc.Lightning/SubscribeChannelGraph", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1154,7 +1253,7 @@ func (c *lightningClient) RestoreChannelBackups(ctx context.Context, in *Restore
 }
 
 func (c *lightningClient) SubscribeChannelBackups(ctx context.Context, in *ChannelBackupSubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelBackupsClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[10], "/lnrpc.Lightning/SubscribeChannelBackups", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[11], "/lnrpc.Lightning/SubscribeChannelBackups", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1185,6 +1284,15 @@ func (x *lightningSubscribeChannelBackupsClient) Recv() (*ChanBackupSnapshot, er
 	return m, nil
 }
 
+func (c *lightningClient) GetBackupSinkStatus(ctx context.Context, in *GetBackupSinkStatusRequest, opts ...grpc.CallOption) (*GetBackupSinkStatusResponse, error) {
+	out := new(GetBackupSinkStatusResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/GetBackupSinkStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) BakeMacaroon(ctx context.Context, in *BakeMacaroonRequest, opts ...grpc.CallOption) (*BakeMacaroonResponse, error) {
 	out := new(BakeMacaroonResponse)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/BakeMacaroon", in, out, opts...)
@@ -1231,7 +1339,7 @@ func (c *lightningClient) CheckMacaroonPermissions(ctx context.Context, in *Chec
 }
 
 func (c *lightningClient) RegisterRPCMiddleware(ctx context.Context, opts ...grpc.CallOption) (Lightning_RegisterRPCMiddlewareClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[11], "/lnrpc.Lightning/RegisterRPCMiddleware", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[12], "/lnrpc.Lightning/RegisterRPCMiddleware", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1271,7 +1379,7 @@ func (c *lightningClient) SendCustomMessage(ctx context.Context, in *SendCustomM
 }
 
 func (c *lightningClient) SubscribeCustomMessages(ctx context.Context, in *SubscribeCustomMessagesRequest, opts ...grpc.CallOption) (Lightning_SubscribeCustomMessagesClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[12], "/lnrpc.Lightning/SubscribeCustomMessages", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[13], "/lnrpc.Lightning/SubscribeCustomMessages", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1302,6 +1410,83 @@ func (x *lightningSubscribeCustomMessagesClient) Recv() (*CustomMessage, error)
 	return m, nil
 }
 
+func (c *lightningClient) SendOnionMessage(ctx context.Context, in *SendOnionMessageRequest, opts ...grpc.CallOption) (*SendOnionMessageResponse, error) {
+	out := new(SendOnionMessageResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/SendOnionMessage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SubscribeOnionMessages(ctx context.Context, in *SubscribeOnionMessagesRequest, opts ...grpc.CallOption) (Lightning_SubscribeOnionMessagesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[14], "/lnrpc.Lightning/SubscribeOnionMessages", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningSubscribeOnionMessagesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_SubscribeOnionMessagesClient interface {
+	Recv() (*OnionMessage, error)
+	grpc.ClientStream
+}
+
+type lightningSubscribeOnionMessagesClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningSubscribeOnionMessagesClient) Recv() (*OnionMessage, error) {
+	m := new(OnionMessage)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lightningClient) AddOffer(ctx context.Context, in *AddOfferRequest, opts ...grpc.CallOption) (*AddOfferResponse, error) {
+	out := new(AddOfferResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/AddOffer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ListOffers(ctx context.Context, in *ListOffersRequest, opts ...grpc.CallOption) (*ListOffersResponse, error) {
+	out := new(ListOffersResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ListOffers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) DisableOffer(ctx context.Context, in *DisableOfferRequest, opts ...grpc.CallOption) (*DisableOfferResponse, error) {
+	out := new(DisableOfferResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/DisableOffer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) DecodeOffer(ctx context.Context, in *DecodeOfferRequest, opts ...grpc.CallOption) (*Offer, error) {
+	out := new(Offer)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/DecodeOffer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // LightningServer is the server API for Lightning service.
 // All implementations must embed UnimplementedLightningServer
 // for forward compatibility
@@ -1448,6 +1633,21 @@ type LightningServer interface {
 	//Alternatively, this can be used to interactively drive PSBT signing for
 	//funding for partially complete funding transactions.
 	FundingStateStep(context.Context, *FundingTransitionMsg) (*FundingStateStepResp, error)
+	// brolncli: `splicechannel`
+	//SpliceChannel resizes an existing channel on-chain without closing it. A
+	//positive relative amount adds funds from the wallet (or from the inputs of
+	//a funded PSBT) to our side of the channel, a negative amount moves funds
+	//from our side of the channel to a wallet address. The channel can't carry
+	//any HTLCs while being spliced and stays quiescent until the splice
+	//transaction confirms. If a funded PSBT is given, a psbt_sign update
+	//carrying the splice transaction is sent once it's negotiated, and the PSBT
+	//inputs must then be signed and passed to SpliceFinalizePsbt.
+	SpliceChannel(*SpliceChannelRequest, Lightning_SpliceChannelServer) error
+	//
+	//SpliceFinalizePsbt hands the signed PSBT of a PSBT funded splice back to
+	//broln, after which the splice signatures are exchanged with the peer and
+	//the splice transaction is published.
+	SpliceFinalizePsbt(context.Context, *SpliceFinalizePsbtRequest) (*SpliceFinalizePsbtResponse, error)
 	//
 	//ChannelAcceptor dispatches a bi-directional streaming RPC in which
 	//OpenChannel requests are sent to the client and the client responds with
@@ -1464,6 +1664,12 @@ type LightningServer interface {
 	//closure transaction is confirmed, or a manual fee rate. If neither are
 	//specified, then a default lax, block confirmation target is used.
 	CloseChannel(*CloseChannelRequest, Lightning_CloseChannelServer) error
+	// brolncli: `bumpclosefee`
+	//BumpCloseFee proposes a new closing transaction for a channel that is being
+	//cooperatively closed, paying a higher fee from our own channel output. Once
+	//signed by the remote peer, it replaces the current closing transaction. Both
+	//we and the remote peer must support the RBF cooperative close protocol.
+	BumpCloseFee(context.Context, *BumpCloseFeeRequest) (*BumpCloseFeeResponse, error)
 	// brolncli: `abandonchannel`
 	//AbandonChannel removes all channel state from the database except for a
 	//close summary. This method can be used to get rid of permanently unusable
@@ -1656,6 +1862,11 @@ type LightningServer interface {
 	//ups, but the updated set of encrypted multi-chan backups with the closed
 	//channel(s) removed.
 	SubscribeChannelBackups(*ChannelBackupSubscription, Lightning_SubscribeChannelBackupsServer) error
+	// brolncli: `backupsinkstatus`
+	//GetBackupSinkStatus returns the upload status of each of the remote
+	//destinations that every updated multi-channel backup is pushed to,
+	//including the time of the last successful upload.
+	GetBackupSinkStatus(context.Context, *GetBackupSinkStatusRequest) (*GetBackupSinkStatusResponse, error)
 	// brolncli: `bakemacaroon`
 	//BakeMacaroon allows the creation of a new macaroon with custom read and
 	//write permissions. No first-party caveats are added since this can be done
@@ -1692,12 +1903,35 @@ type LightningServer interface {
 	//modify responses for requests made with _unencumbered_ macaroons!
 	RegisterRPCMiddleware(Lightning_RegisterRPCMiddlewareServer) error
 	// brolncli: `sendcustom`
-	//SendCustomMessage sends a custom peer message.
+	//SendCustomMessage sends a custom peer message. Messages of a type that is
+	//reserved for one of broln's protocol messages are rejected.
 	SendCustomMessage(context.Context, *SendCustomMessageRequest) (*SendCustomMessageResponse, error)
 	// brolncli: `subscribecustom`
 	//SubscribeCustomMessages subscribes to a stream of incoming custom peer
 	//messages.
 	SubscribeCustomMessages(*SubscribeCustomMessagesRequest, Lightning_SubscribeCustomMessagesServer) error
+	// brolncli: `sendonion`
+	//SendOnionMessage sends an onion message through a blinded path. Unlike
+	//custom messages, onion messages can reach nodes that aren't direct peers.
+	SendOnionMessage(context.Context, *SendOnionMessageRequest) (*SendOnionMessageResponse, error)
+	// brolncli: `subscribeonion`
+	//SubscribeOnionMessages subscribes to a stream of incoming onion messages
+	//that are addressed to this node.
+	SubscribeOnionMessages(*SubscribeOnionMessagesRequest, Lightning_SubscribeOnionMessagesServer) error
+	// brolncli: `offers create`
+	//AddOffer creates a new BOLT 12 offer issued by this node. Payers request
+	//invoices for the offer over onion messages, which are answered
+	//automatically as long as the offer is enabled.
+	AddOffer(context.Context, *AddOfferRequest) (*AddOfferResponse, error)
+	// brolncli: `offers list`
+	//ListOffers returns all offers created by this node.
+	ListOffers(context.Context, *ListOffersRequest) (*ListOffersResponse, error)
+	// brolncli: `offers disable`
+	//DisableOffer stops answering invoice requests for an offer.
+	DisableOffer(context.Context, *DisableOfferRequest) (*DisableOfferResponse, error)
+	// brolncli: `offers decode`
+	//DecodeOffer decodes an lno encoded BOLT 12 offer.
+	DecodeOffer(context.Context, *DecodeOfferRequest) (*Offer, error)
 	mustEmbedUnimplementedLightningServer()
 }
 
@@ -1780,12 +2014,21 @@ func (UnimplementedLightningServer) BatchOpenChannel(context.Context, *BatchOpen
 func (UnimplementedLightningServer) FundingStateStep(context.Context, *FundingTransitionMsg) (*FundingStateStepResp, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FundingStateStep not implemented")
 }
+func (UnimplementedLightningServer) SpliceChannel(*SpliceChannelRequest, Lightning_SpliceChannelServer) error {
+	return status.Errorf(codes.Unimplemented, "method SpliceChannel not implemented")
+}
+func (UnimplementedLightningServer) SpliceFinalizePsbt(context.Context, *SpliceFinalizePsbtRequest) (*SpliceFinalizePsbtResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SpliceFinalizePsbt not implemented")
+}
 func (UnimplementedLightningServer) ChannelAcceptor(Lightning_ChannelAcceptorServer) error {
 	return status.Errorf(codes.Unimplemented, "method ChannelAcceptor not implemented")
 }
 func (UnimplementedLightningServer) CloseChannel(*CloseChannelRequest, Lightning_CloseChannelServer) error {
 	return status.Errorf(codes.Unimplemented, "method CloseChannel not implemented")
 }
+func (UnimplementedLightningServer) BumpCloseFee(context.Context, *BumpCloseFeeRequest) (*BumpCloseFeeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BumpCloseFee not implemented")
+}
 func (UnimplementedLightningServer) AbandonChannel(context.Context, *AbandonChannelRequest) (*AbandonChannelResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AbandonChannel not implemented")
 }
@@ -1876,6 +2119,9 @@ func (UnimplementedLightningServer) RestoreChannelBackups(context.Context, *Rest
 func (UnimplementedLightningServer) SubscribeChannelBackups(*ChannelBackupSubscription, Lightning_SubscribeChannelBackupsServer) error {
 	return status.Errorf(codes.Unimplemented, "method SubscribeChannelBackups not implemented")
 }
+func (UnimplementedLightningServer) GetBackupSinkStatus(context.Context, *GetBackupSinkStatusRequest) (*GetBackupSinkStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBackupSinkStatus not implemented")
+}
 func (UnimplementedLightningServer) BakeMacaroon(context.Context, *BakeMacaroonRequest) (*BakeMacaroonResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method BakeMacaroon not implemented")
 }
@@ -1900,6 +2146,24 @@ func (UnimplementedLightningServer) SendCustomMessage(context.Context, *SendCust
 func (UnimplementedLightningServer) SubscribeCustomMessages(*SubscribeCustomMessagesRequest, Lightning_SubscribeCustomMessagesServer) error {
 	return status.Errorf(codes.Unimplemented, "method SubscribeCustomMessages not implemented")
 }
+func (UnimplementedLightningServer) SendOnionMessage(context.Context, *SendOnionMessageRequest) (*SendOnionMessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendOnionMessage not implemented")
+}
+func (UnimplementedLightningServer) SubscribeOnionMessages(*SubscribeOnionMessagesRequest, Lightning_SubscribeOnionMessagesServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeOnionMessages not implemented")
+}
+func (UnimplementedLightningServer) AddOffer(context.Context, *AddOfferRequest) (*AddOfferResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddOffer not implemented")
+}
+func (UnimplementedLightningServer) ListOffers(context.Context, *ListOffersRequest) (*ListOffersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOffers not implemented")
+}
+func (UnimplementedLightningServer) DisableOffer(context.Context, *DisableOfferRequest) (*DisableOfferResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DisableOffer not implemented")
+}
+func (UnimplementedLightningServer) DecodeOffer(context.Context, *DecodeOfferRequest) (*Offer, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DecodeOffer not implemented")
+}
 func (UnimplementedLightningServer) mustEmbedUnimplementedLightningServer() {}
 
 // UnsafeLightningServer may be embedded to opt out of forward compatibility for this service.
@@ -2375,6 +2639,45 @@ func _Lightning_FundingStateStep_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lightning_SpliceChannel_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SpliceChannelRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LightningServer).SpliceChannel(m, &lightningSpliceChannelServer{stream})
+}
+
+type Lightning_SpliceChannelServer interface {
+	Send(*SpliceStatusUpdate) error
+	grpc.ServerStream
+}
+
+type lightningSpliceChannelServer struct {
+	grpc.ServerStream
+}
+
+func (x *lightningSpliceChannelServer) Send(m *SpliceStatusUpdate) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Lightning_SpliceFinalizePsbt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SpliceFinalizePsbtRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).SpliceFinalizePsbt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/SpliceFinalizePsbt",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).SpliceFinalizePsbt(ctx, req.(*SpliceFinalizePsbtRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_ChannelAcceptor_Handler(srv interface{}, stream grpc.ServerStream) error {
 	return srv.(LightningServer).ChannelAcceptor(&lightningChannelAcceptorServer{stream})
 }
@@ -2422,6 +2725,24 @@ func (x *lightningCloseChannelServer) Send(m *CloseStatusUpdate) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Lightning_BumpCloseFee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BumpCloseFeeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).BumpCloseFee(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/BumpCloseFee",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).BumpCloseFee(ctx, req.(*BumpCloseFeeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_AbandonChannel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(AbandonChannelRequest)
 	if err := dec(in); err != nil {
@@ -2987,6 +3308,24 @@ func (x *lightningSubscribeChannelBackupsServer) Send(m *ChanBackupSnapshot) err
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Lightning_GetBackupSinkStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBackupSinkStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).GetBackupSinkStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/GetBackupSinkStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).GetBackupSinkStatus(ctx, req.(*GetBackupSinkStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_BakeMacaroon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(BakeMacaroonRequest)
 	if err := dec(in); err != nil {
@@ -3142,6 +3481,117 @@ func (x *lightningSubscribeCustomMessagesServer) Send(m *CustomMessage) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Lightning_SendOnionMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendOnionMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).SendOnionMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/SendOnionMessage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).SendOnionMessage(ctx, req.(*SendOnionMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_SubscribeOnionMessages_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeOnionMessagesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LightningServer).SubscribeOnionMessages(m, &lightningSubscribeOnionMessagesServer{stream})
+}
+
+type Lightning_SubscribeOnionMessagesServer interface {
+	Send(*OnionMessage) error
+	grpc.ServerStream
+}
+
+type lightningSubscribeOnionMessagesServer struct {
+	grpc.ServerStream
+}
+
+func (x *lightningSubscribeOnionMessagesServer) Send(m *OnionMessage) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Lightning_AddOffer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddOfferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).AddOffer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/AddOffer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).AddOffer(ctx, req.(*AddOfferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_ListOffers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOffersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ListOffers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ListOffers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ListOffers(ctx, req.(*ListOffersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_DisableOffer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DisableOfferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).DisableOffer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/DisableOffer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).DisableOffer(ctx, req.(*DisableOfferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_DecodeOffer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecodeOfferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).DecodeOffer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/DecodeOffer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).DecodeOffer(ctx, req.(*DecodeOfferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Lightning_ServiceDesc is the grpc.ServiceDesc for Lightning service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -3233,6 +3683,14 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "FundingStateStep",
 			Handler:    _Lightning_FundingStateStep_Handler,
 		},
+		{
+			MethodName: "SpliceFinalizePsbt",
+			Handler:    _Lightning_SpliceFinalizePsbt_Handler,
+		},
+		{
+			MethodName: "BumpCloseFee",
+			Handler:    _Lightning_BumpCloseFee_Handler,
+		},
 		{
 			MethodName: "AbandonChannel",
 			Handler:    _Lightning_AbandonChannel_Handler,
@@ -3333,6 +3791,10 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RestoreChannelBackups",
 			Handler:    _Lightning_RestoreChannelBackups_Handler,
 		},
+		{
+			MethodName: "GetBackupSinkStatus",
+			Handler:    _Lightning_GetBackupSinkStatus_Handler,
+		},
 		{
 			MethodName: "BakeMacaroon",
 			Handler:    _Lightning_BakeMacaroon_Handler,
@@ -3357,6 +3819,26 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SendCustomMessage",
 			Handler:    _Lightning_SendCustomMessage_Handler,
 		},
+		{
+			MethodName: "SendOnionMessage",
+			Handler:    _Lightning_SendOnionMessage_Handler,
+		},
+		{
+			MethodName: "AddOffer",
+			Handler:    _Lightning_AddOffer_Handler,
+		},
+		{
+			MethodName: "ListOffers",
+			Handler:    _Lightning_ListOffers_Handler,
+		},
+		{
+			MethodName: "DisableOffer",
+			Handler:    _Lightning_DisableOffer_Handler,
+		},
+		{
+			MethodName: "DecodeOffer",
+			Handler:    _Lightning_DecodeOffer_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -3379,6 +3861,11 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Lightning_OpenChannel_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "SpliceChannel",
+			Handler:       _Lightning_SpliceChannel_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "ChannelAcceptor",
 			Handler:       _Lightning_ChannelAcceptor_Handler,
@@ -3428,6 +3915,11 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Lightning_SubscribeCustomMessages_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "SubscribeOnionMessages",
+			Handler:       _Lightning_SubscribeOnionMessages_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "lightning.proto",
 }
@@ -5,8 +5,10 @@ import (
 	"errors"
 	fmt "fmt"
 
+	"github.com/brronsuite/broln/blinding"
 	"github.com/brronsuite/broln/lnwallet"
 	"github.com/brronsuite/broln/lnwire"
+	"github.com/brronsuite/brond/bronec"
 	"github.com/brronsuite/brond/chaincfg"
 	"github.com/brronsuite/brond/txscript"
 	"github.com/brronsuite/bronutil"
@@ -96,6 +98,9 @@ func MarshalUtxos(utxos []*lnwallet.Utxo, activeNetParams *chaincfg.Params) (
 		case lnwallet.NestedWitnessPubKey:
 			addrType = AddressType_NESTED_PUBKEY_HASH
 
+		case lnwallet.TaprootPubkey:
+			addrType = AddressType_TAPROOT_PUBKEY
+
 		case lnwallet.UnknownAddressType:
 			continue
 
@@ -143,3 +148,66 @@ func MarshalUtxos(utxos []*lnwallet.Utxo, activeNetParams *chaincfg.Params) (
 
 	return res, nil
 }
+
+// MarshalBlindedPath converts a blinded path into its RPC representation.
+func MarshalBlindedPath(path *blinding.BlindedPath) *BlindedPath {
+	rpcPath := &BlindedPath{
+		IntroductionNode: path.IntroductionPoint.SerializeCompressed(),
+		BlindingPoint:    path.BlindingPoint.SerializeCompressed(),
+		BlindedHops:      make([]*BlindedHop, len(path.BlindedHops)),
+	}
+	for i, hop := range path.BlindedHops {
+		rpcPath.BlindedHops[i] = &BlindedHop{
+			BlindedNode:   hop.BlindedNodePub.SerializeCompressed(),
+			EncryptedData: hop.CipherText,
+		}
+	}
+
+	return rpcPath
+}
+
+// UnmarshalBlindedPath parses a blinded path from its RPC representation.
+func UnmarshalBlindedPath(rpcPath *BlindedPath) (*blinding.BlindedPath,
+	error) {
+
+	if len(rpcPath.BlindedHops) == 0 {
+		return nil, blinding.ErrNoHops
+	}
+
+	introductionPoint, err := bronec.ParsePubKey(
+		rpcPath.IntroductionNode, bronec.S256(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid introduction node: %v", err)
+	}
+
+	blindingPoint, err := bronec.ParsePubKey(
+		rpcPath.BlindingPoint, bronec.S256(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid blinding point: %v", err)
+	}
+
+	path := &blinding.BlindedPath{
+		IntroductionPoint: introductionPoint,
+		BlindingPoint:     blindingPoint,
+		BlindedHops: make(
+			[]*blinding.BlindedHop, len(rpcPath.BlindedHops),
+		),
+	}
+	for i, hop := range rpcPath.BlindedHops {
+		blindedNode, err := bronec.ParsePubKey(
+			hop.BlindedNode, bronec.S256(),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("invalid blinded node: %v", err)
+		}
+
+		path.BlindedHops[i] = &blinding.BlindedHop{
+			BlindedNodePub: blindedNode,
+			CipherText:     hop.EncryptedData,
+		}
+	}
+
+	return path, nil
+}
@@ -1,6 +1,7 @@
 package routerrpc
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"sync"
@@ -19,6 +20,11 @@ var (
 	// ErrMissingPreimage is an error returned when the caller tries to settle
 	// a forward and doesn't provide a preimage.
 	ErrMissingPreimage = errors.New("missing preimage")
+
+	// ErrAmbiguousFailure is an error returned when the caller tries to
+	// fail a forward with both a failure message and an encrypted failure.
+	ErrAmbiguousFailure = errors.New("failure message and encrypted " +
+		"failure are mutually exclusive")
 )
 
 // forwardInterceptor is a helper struct that handles the lifecycle of an rpc
@@ -69,9 +75,9 @@ func (r *forwardInterceptor) run() error {
 	defer r.onDisconnect()
 
 	// Register our interceptor so we receive all forwarded packets.
-	interceptableForwarder := r.server.cfg.RouterBackend.InterceptableForwarder
-	interceptableForwarder.SetInterceptor(r.onIntercept)
-	defer interceptableForwarder.SetInterceptor(nil)
+	forwarder := r.server.cfg.RouterBackend.InterceptableForwarder
+	forwarder.SetInterceptor(r.onIntercept)
+	defer forwarder.SetInterceptor(nil)
 
 	// start a go routine that reads client resolutions.
 	errChan := make(chan error)
@@ -150,8 +156,11 @@ func (r *forwardInterceptor) holdAndForwardToClient(
 	htlc := forward.Packet()
 	inKey := htlc.IncomingCircuit
 
-	// Ignore already held htlcs.
+	// Already held htlcs are offered again if the link that accepted them
+	// was restarted, in which case only the new forward can be resolved.
+	// As the client already knows about them, we don't send them again.
 	if _, ok := r.holdForwards[inKey]; ok {
+		r.holdForwards[inKey] = forward
 		return nil
 	}
 
@@ -194,7 +203,7 @@ func (r *forwardInterceptor) resolveFromClient(
 	case ResolveHoldForwardAction_RESUME:
 		return interceptedForward.Resume()
 	case ResolveHoldForwardAction_FAIL:
-		return interceptedForward.Fail()
+		return failForward(interceptedForward, in)
 	case ResolveHoldForwardAction_SETTLE:
 		if in.Preimage == nil {
 			return ErrMissingPreimage
@@ -209,17 +218,47 @@ func (r *forwardInterceptor) resolveFromClient(
 	}
 }
 
+// failForward fails the intercepted forward with the failure requested by the
+// client.
+func failForward(forward htlcswitch.InterceptedForward,
+	in *ForwardHtlcInterceptResponse) error {
+
+	switch {
+	case len(in.FailureMessage) > 0 && len(in.EncryptedFailure) > 0:
+		return ErrAmbiguousFailure
+
+	case len(in.FailureMessage) > 0:
+		failure, err := lnwire.DecodeFailureMessage(
+			bytes.NewReader(in.FailureMessage), 0,
+		)
+		if err != nil {
+			return fmt.Errorf("unable to decode failure message: "+
+				"%v", err)
+		}
+
+		return forward.FailWithMessage(failure)
+
+	case len(in.EncryptedFailure) > 0:
+		return forward.FailWithReason(in.EncryptedFailure)
+
+	default:
+		return forward.Fail()
+	}
+}
+
 // onDisconnect removes all previousely held forwards from
-// the store. Before they are removed it ensure to resume as the default
-// behavior.
+// the store. Before they are removed they are released to the switch, which
+// either resumes them as the default behavior, or holds them until the next
+// interceptor connects if an interceptor is required.
 func (r *forwardInterceptor) onDisconnect() {
 	// Then close the channel so all go routine will exit.
 	close(r.quit)
 
 	log.Infof("RPC interceptor disconnected, resolving held packets")
+	forwarder := r.server.cfg.RouterBackend.InterceptableForwarder
 	for key, forward := range r.holdForwards {
-		if err := forward.Resume(); err != nil {
-			log.Errorf("failed to resume hold forward %v", err)
+		if err := forwarder.Release(forward); err != nil {
+			log.Errorf("failed to release hold forward %v", err)
 		}
 		delete(r.holdForwards, key)
 	}
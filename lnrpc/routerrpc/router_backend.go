@@ -15,6 +15,7 @@ import (
 	"github.com/brronsuite/broln/lnrpc"
 	"github.com/brronsuite/broln/lntypes"
 	"github.com/brronsuite/broln/lnwire"
+	"github.com/brronsuite/broln/offers"
 	"github.com/brronsuite/broln/record"
 	"github.com/brronsuite/broln/routing"
 	"github.com/brronsuite/broln/routing/route"
@@ -95,6 +96,18 @@ type RouterBackend struct {
 	// SetChannelAuto exposes the ability to restore automatic channel state
 	// management after manually setting channel status.
 	SetChannelAuto func(wire.OutPoint) error
+
+	// FetchOfferInvoice requests an invoice for the given amount from the
+	// issuer of the offer. It is nil if the node can't exchange onion
+	// messages.
+	FetchOfferInvoice func(offer *offers.Offer, amt lnwire.MilliBronees,
+		quantity *uint64, payerNote string) (*offers.Invoice, error)
+
+	// AddSelfInvoice adds an invoice for the given amount to our own
+	// invoice registry. It is used for circular payments that rebalance
+	// our channels.
+	AddSelfInvoice func(ctx context.Context, amt lnwire.MilliBronees,
+		memo string) (*channeldb.Invoice, error)
 }
 
 // MissionControl defines the mission control dependencies of routerrpc.
@@ -297,7 +310,7 @@ func (r *RouterBackend) QueryRoutes(ctx context.Context,
 		if err != nil {
 			return nil, err
 		}
-		restrictions.LastHop = &lastHop
+		restrictions.LastHops = []route.Vertex{lastHop}
 	}
 
 	// If we have any TLV records destined for the final hop, then we'll
@@ -574,15 +587,51 @@ func (r *RouterBackend) extractIntentFromSendRequest(
 		)
 	}
 
-	// Pass along a last hop restriction if specified.
+	// Pass along restrictions on the last hops that may be used.
+	lastHops, err := unmarshallVertices(rpcPayReq.LastHopPubkeys)
+	if err != nil {
+		return nil, err
+	}
+	payIntent.LastHops = lastHops
+
+	// Add the deprecated single last hop restriction if present.
 	if len(rpcPayReq.LastHopPubkey) > 0 {
+		if payIntent.LastHops != nil {
+			return nil, errors.New("last_hop_pubkey and " +
+				"last_hop_pubkeys are mutually exclusive")
+		}
+
 		lastHop, err := route.NewVertexFromBytes(
 			rpcPayReq.LastHopPubkey,
 		)
 		if err != nil {
 			return nil, err
 		}
-		payIntent.LastHop = &lastHop
+		payIntent.LastHops = []route.Vertex{lastHop}
+	}
+
+	// Pass along restrictions on the incoming channels that may be used.
+	payIntent.IncomingChannelIDs = rpcPayReq.IncomingChanIds
+
+	// Pass along the nodes and channels that must not be used by any of
+	// the partial payments.
+	payIntent.ExcludedNodes, err = unmarshallVertices(
+		rpcPayReq.ExcludeNodes,
+	)
+	if err != nil {
+		return nil, err
+	}
+	payIntent.ExcludedChannelIDs = rpcPayReq.ExcludeChannels
+
+	// Pass along the trampoline node to send the payment through.
+	if len(rpcPayReq.TrampolineNode) > 0 {
+		trampolineNode, err := route.NewVertexFromBytes(
+			rpcPayReq.TrampolineNode,
+		)
+		if err != nil {
+			return nil, err
+		}
+		payIntent.TrampolineNode = &trampolineNode
 	}
 
 	// Take the CLTV limit from the request if set, otherwise use the max.
@@ -650,10 +699,24 @@ func (r *RouterBackend) extractIntentFromSendRequest(
 		return nil, err
 	}
 
+	if rpcPayReq.PayerNote != "" && rpcPayReq.Offer == "" {
+		return nil, errors.New("payer_note can only be set together " +
+			"with offer")
+	}
+
+	switch {
+	// If the offer field isn't blank, we request an invoice from the
+	// issuer of the offer and pay it through one of its blinded paths.
+	case rpcPayReq.Offer != "":
+		err := r.populateOfferIntent(payIntent, rpcPayReq, reqAmt)
+		if err != nil {
+			return nil, err
+		}
+
 	// If the payment request field isn't blank, then the details of the
 	// invoice are encoded entirely within the encoded payReq.  So we'll
 	// attempt to decode it, populating the payment accordingly.
-	if rpcPayReq.PaymentRequest != "" {
+	case rpcPayReq.PaymentRequest != "":
 		switch {
 
 		case len(rpcPayReq.Dest) > 0:
@@ -759,7 +822,8 @@ func (r *RouterBackend) extractIntentFromSendRequest(
 		payIntent.DestFeatures = payReq.Features
 		payIntent.PaymentAddr = payAddr
 		payIntent.PaymentRequest = []byte(rpcPayReq.PaymentRequest)
-	} else {
+
+	default:
 		// Otherwise, If the payment request field was not specified
 		// (and a custom route wasn't specified), construct the payment
 		// from the other fields.
@@ -897,6 +961,199 @@ func (r *RouterBackend) extractIntentFromSendRequest(
 	return payIntent, nil
 }
 
+// extractIntentFromRebalanceRequest adds an invoice of our own node for the
+// amount to rebalance, and returns the intent to pay it through the outgoing
+// channel of the request back to ourselves through the incoming channel.
+func (r *RouterBackend) extractIntentFromRebalanceRequest(ctx context.Context,
+	req *RebalanceChannelRequest) (*routing.LightningPayment, error) {
+
+	switch {
+	case req.OutgoingChanId == 0:
+		return nil, errors.New("outgoing_chan_id must be specified")
+
+	case req.IncomingChanId == 0:
+		return nil, errors.New("incoming_chan_id must be specified")
+
+	case req.OutgoingChanId == req.IncomingChanId:
+		return nil, errors.New("outgoing_chan_id and " +
+			"incoming_chan_id must be different channels")
+
+	case r.AddSelfInvoice == nil:
+		return nil, errors.New("rebalancing requires an invoice " +
+			"registry")
+	}
+
+	// Both channels must be channels of our own node.
+	chanIDs := []uint64{req.OutgoingChanId, req.IncomingChanId}
+	for _, chanID := range chanIDs {
+		node1, node2, err := r.FetchChannelEndpoints(chanID)
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch channel %v: %v",
+				chanID, err)
+		}
+
+		if node1 != r.SelfNode && node2 != r.SelfNode {
+			return nil, fmt.Errorf("channel %v is not a channel "+
+				"of our node", chanID)
+		}
+	}
+
+	amt, err := lnrpc.UnmarshallAmt(req.Amt, req.AmtMsat)
+	if err != nil {
+		return nil, err
+	}
+	if amt <= 0 {
+		return nil, errors.New("amount to rebalance must be positive")
+	}
+
+	memo := fmt.Sprintf("rebalance from channel %v to channel %v",
+		lnwire.NewShortChanIDFromInt(req.OutgoingChanId),
+		lnwire.NewShortChanIDFromInt(req.IncomingChanId))
+
+	invoice, err := r.AddSelfInvoice(ctx, amt, memo)
+	if err != nil {
+		return nil, fmt.Errorf("unable to add invoice: %v", err)
+	}
+
+	// Unless requested otherwise, the rebalance is attempted in a single
+	// payment, as each shard would be constrained to the same channels.
+	maxParts := req.MaxParts
+	if maxParts == 0 {
+		maxParts = 1
+	}
+
+	// The invoice is paid like any other payment request, which takes
+	// care of the fee limit, the timeout and the CLTV limit. The first
+	// hop is restricted to the outgoing channel.
+	payIntent, err := r.extractIntentFromSendRequest(&SendPaymentRequest{
+		PaymentRequest:   string(invoice.PaymentRequest),
+		TimeoutSeconds:   req.TimeoutSeconds,
+		FeeLimitSat:      req.FeeLimitSat,
+		FeeLimitMsat:     req.FeeLimitMsat,
+		OutgoingChanIds:  []uint64{req.OutgoingChanId},
+		CltvLimit:        req.CltvLimit,
+		AllowSelfPayment: true,
+		MaxParts:         maxParts,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The last hop back to ourselves is restricted to the incoming
+	// channel.
+	payIntent.IncomingChannelIDs = []uint64{req.IncomingChanId}
+
+	return payIntent, nil
+}
+
+// populateOfferIntent requests an invoice for the offer of the payment request
+// and populates the payment intent with it. The invoice is paid through the
+// first of its blinded paths that can carry the amount.
+func (r *RouterBackend) populateOfferIntent(payIntent *routing.LightningPayment,
+	rpcPayReq *SendPaymentRequest, reqAmt lnwire.MilliBronees) error {
+
+	switch {
+	case rpcPayReq.PaymentRequest != "":
+		return errors.New("offer and payment_request cannot appear " +
+			"together")
+
+	case len(rpcPayReq.Dest) > 0:
+		return errors.New("dest and offer cannot appear together")
+
+	case len(rpcPayReq.PaymentHash) > 0:
+		return errors.New("payment_hash and offer cannot appear " +
+			"together")
+
+	case rpcPayReq.FinalCltvDelta != 0:
+		return errors.New("final_cltv_delta and offer cannot appear " +
+			"together")
+
+	case rpcPayReq.Amp:
+		return errors.New("amp payments to offers are not supported")
+
+	case r.FetchOfferInvoice == nil:
+		return errors.New("paying offers requires onion messages to " +
+			"be enabled")
+	}
+
+	offer, err := offers.DecodeOffer(rpcPayReq.Offer)
+	if err != nil {
+		return err
+	}
+
+	// We request a single item for offers that allow several.
+	var quantity *uint64
+	if offer.QuantityMax != nil {
+		one := uint64(1)
+		quantity = &one
+	}
+
+	invoice, err := r.FetchOfferInvoice(
+		offer, reqAmt, quantity, rpcPayReq.PayerNote,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to fetch invoice for offer: %w", err)
+	}
+
+	if time.Now().After(invoice.Expiry()) {
+		return fmt.Errorf("invoice expired at %v", invoice.Expiry())
+	}
+
+	var blindedPayment *routing.BlindedPayment
+	for _, path := range invoice.Paths {
+		info := path.PayInfo
+		candidate := &routing.BlindedPayment{
+			Path:            path.Path,
+			BaseFee:         info.FeeBaseMsat,
+			ProportionalFee: info.FeeRate,
+			CltvExpiryDelta: info.CltvExpiryDelta,
+			HtlcMinimum:     info.HtlcMinimumMsat,
+			HtlcMaximum:     info.HtlcMaximumMsat,
+		}
+		if candidate.Validate(invoice.Amount) == nil {
+			blindedPayment = candidate
+			break
+		}
+	}
+	if blindedPayment == nil {
+		return errors.New("no blinded path of the invoice can carry " +
+			"the payment")
+	}
+
+	err = payIntent.SetPaymentHash(invoice.PaymentHash)
+	if err != nil {
+		return err
+	}
+
+	payIntent.Amount = invoice.Amount
+	payIntent.Target = blindedPayment.IntroductionNode()
+	payIntent.FinalCLTVDelta = blindedPayment.CltvExpiryDelta
+	payIntent.BlindedPayment = blindedPayment
+	payIntent.MaxParts = 1
+
+	return nil
+}
+
+// unmarshallVertices unmarshalls a list of node pubkeys. It returns nil if the
+// list is empty, so that an unset list doesn't restrict path finding.
+func unmarshallVertices(pubKeys [][]byte) ([]route.Vertex, error) {
+	if len(pubKeys) == 0 {
+		return nil, nil
+	}
+
+	vertices := make([]route.Vertex, 0, len(pubKeys))
+	for _, pubKey := range pubKeys {
+		vertex, err := route.NewVertexFromBytes(pubKey)
+		if err != nil {
+			return nil, err
+		}
+
+		vertices = append(vertices, vertex)
+	}
+
+	return vertices, nil
+}
+
 // unmarshallRouteHints unmarshalls a list of route hints.
 func unmarshallRouteHints(rpcRouteHints []*lnrpc.RouteHint) (
 	[][]zpay32.HopHint, error) {
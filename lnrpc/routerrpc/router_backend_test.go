@@ -161,7 +161,7 @@ func testQueryRoutes(t *testing.T, useMissionControl bool, useMsat bool,
 			t.Fatal("expecting 0% probability for ignored pair")
 		}
 
-		if *restrictions.LastHop != lastHop {
+		if restrictions.LastHops[0] != lastHop {
 			t.Fatal("unexpected last hop")
 		}
 
@@ -470,3 +470,67 @@ func testUnmarshalAMP(t *testing.T, test unmarshalAMPTest) {
 		t.Fatalf("test case has non-standard outcome")
 	}
 }
+
+// TestExtractIntentRestrictions asserts that the channel and node restrictions
+// of a send request are parsed and passed onto the payment.
+func TestExtractIntentRestrictions(t *testing.T) {
+	t.Parallel()
+
+	destNodeBytes, err := hex.DecodeString(destKey)
+	require.NoError(t, err)
+
+	ignoreNodeBytes, err := hex.DecodeString(ignoreNodeKey)
+	require.NoError(t, err)
+
+	hintNodeBytes, err := hex.DecodeString(hintNodeKey)
+	require.NoError(t, err)
+
+	backend := &RouterBackend{
+		SelfNode:              sourceKey,
+		MaxTotalTimelock:      1000,
+		DefaultFinalCltvDelta: 40,
+	}
+
+	req := &SendPaymentRequest{
+		Dest:            destNodeBytes,
+		Amt:             100000,
+		PaymentHash:     make([]byte, 32),
+		TimeoutSeconds:  60,
+		OutgoingChanIds: []uint64{1, 2},
+		LastHopPubkeys:  [][]byte{hintNodeBytes},
+		IncomingChanIds: []uint64{3, 4},
+		ExcludeNodes:    [][]byte{ignoreNodeBytes},
+		ExcludeChannels: []uint64{5},
+	}
+
+	payIntent, err := backend.extractIntentFromSendRequest(req)
+	require.NoError(t, err)
+
+	hintNode, err := route.NewVertexFromBytes(hintNodeBytes)
+	require.NoError(t, err)
+
+	ignoreNode, err := route.NewVertexFromBytes(ignoreNodeBytes)
+	require.NoError(t, err)
+
+	require.Equal(t, []uint64{1, 2}, payIntent.OutgoingChannelIDs)
+	require.Equal(t, []route.Vertex{hintNode}, payIntent.LastHops)
+	require.Equal(t, []uint64{3, 4}, payIntent.IncomingChannelIDs)
+	require.Equal(t, []route.Vertex{ignoreNode}, payIntent.ExcludedNodes)
+	require.Equal(t, []uint64{5}, payIntent.ExcludedChannelIDs)
+
+	// The deprecated single last hop is converted into a list, but can't
+	// be combined with the list.
+	req.LastHopPubkey = hintNodeBytes
+	_, err = backend.extractIntentFromSendRequest(req)
+	require.Error(t, err)
+
+	req.LastHopPubkeys = nil
+	payIntent, err = backend.extractIntentFromSendRequest(req)
+	require.NoError(t, err)
+	require.Equal(t, []route.Vertex{hintNode}, payIntent.LastHops)
+
+	// Invalid pubkeys of excluded nodes are rejected.
+	req.ExcludeNodes = [][]byte{{1, 2, 3}}
+	_, err = backend.extractIntentFromSendRequest(req)
+	require.Error(t, err)
+}
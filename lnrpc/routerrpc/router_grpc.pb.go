@@ -96,14 +96,30 @@ type RouterClient interface {
 	//Forwarded HTLC requests are sent to the client and the client responds with
 	//a boolean that tells broln if this htlc should be intercepted.
 	//In case of interception, the htlc can be either settled, cancelled or
-	//resumed later by using the ResolveHoldForward endpoint.
+	//resumed later by using the ResolveHoldForward endpoint. HTLCs destined for
+	//our own invoices are intercepted as well, and are handed to the invoice
+	//registry when resumed. If broln is started with requireinterceptor, HTLCs
+	//are held while no interceptor is connected, and HTLCs that weren't resolved
+	//when an interceptor disconnects are offered to the next one.
 	HtlcInterceptor(ctx context.Context, opts ...grpc.CallOption) (Router_HtlcInterceptorClient, error)
 	//
+	//ListHeldForwards lists all HTLCs that are held by an HTLC interceptor,
+	//together with the time since which they are held. Held HTLCs are persisted
+	//and replayed to the interceptor after a restart, and HTLCs that were held
+	//before a restart are listed even if they weren't replayed yet.
+	ListHeldForwards(ctx context.Context, in *ListHeldForwardsRequest, opts ...grpc.CallOption) (*ListHeldForwardsResponse, error)
+	//
 	//UpdateChanStatus attempts to manually set the state of a channel
 	//(enabled, disabled, or auto). A manual "disable" request will cause the
 	//channel to stay disabled until a subsequent manual request of either
 	//"enable" or "auto".
 	UpdateChanStatus(ctx context.Context, in *UpdateChanStatusRequest, opts ...grpc.CallOption) (*UpdateChanStatusResponse, error)
+	//
+	//RebalanceChannel moves liquidity between two of our channels by paying an
+	//invoice of our own node. The payment leaves through the outgoing channel
+	//and returns through the incoming channel, while the route in between is
+	//found through path finding. The call returns a stream of payment updates.
+	RebalanceChannel(ctx context.Context, in *RebalanceChannelRequest, opts ...grpc.CallOption) (Router_RebalanceChannelClient, error)
 }
 
 type routerClient struct {
@@ -398,6 +414,15 @@ func (x *routerHtlcInterceptorClient) Recv() (*ForwardHtlcInterceptRequest, erro
 	return m, nil
 }
 
+func (c *routerClient) ListHeldForwards(ctx context.Context, in *ListHeldForwardsRequest, opts ...grpc.CallOption) (*ListHeldForwardsResponse, error) {
+	out := new(ListHeldForwardsResponse)
+	err := c.cc.Invoke(ctx, "/routerrpc.Router/ListHeldForwards", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *routerClient) UpdateChanStatus(ctx context.Context, in *UpdateChanStatusRequest, opts ...grpc.CallOption) (*UpdateChanStatusResponse, error) {
 	out := new(UpdateChanStatusResponse)
 	err := c.cc.Invoke(ctx, "/routerrpc.Router/UpdateChanStatus", in, out, opts...)
@@ -407,6 +432,38 @@ func (c *routerClient) UpdateChanStatus(ctx context.Context, in *UpdateChanStatu
 	return out, nil
 }
 
+func (c *routerClient) RebalanceChannel(ctx context.Context, in *RebalanceChannelRequest, opts ...grpc.CallOption) (Router_RebalanceChannelClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Router_ServiceDesc.Streams[6], "/routerrpc.Router/RebalanceChannel", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &routerRebalanceChannelClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Router_RebalanceChannelClient interface {
+	Recv() (*lnrpc.Payment, error)
+	grpc.ClientStream
+}
+
+type routerRebalanceChannelClient struct {
+	grpc.ClientStream
+}
+
+func (x *routerRebalanceChannelClient) Recv() (*lnrpc.Payment, error) {
+	m := new(lnrpc.Payment)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // RouterServer is the server API for Router service.
 // All implementations must embed UnimplementedRouterServer
 // for forward compatibility
@@ -488,14 +545,30 @@ type RouterServer interface {
 	//Forwarded HTLC requests are sent to the client and the client responds with
 	//a boolean that tells broln if this htlc should be intercepted.
 	//In case of interception, the htlc can be either settled, cancelled or
-	//resumed later by using the ResolveHoldForward endpoint.
+	//resumed later by using the ResolveHoldForward endpoint. HTLCs destined for
+	//our own invoices are intercepted as well, and are handed to the invoice
+	//registry when resumed. If broln is started with requireinterceptor, HTLCs
+	//are held while no interceptor is connected, and HTLCs that weren't resolved
+	//when an interceptor disconnects are offered to the next one.
 	HtlcInterceptor(Router_HtlcInterceptorServer) error
 	//
+	//ListHeldForwards lists all HTLCs that are held by an HTLC interceptor,
+	//together with the time since which they are held. Held HTLCs are persisted
+	//and replayed to the interceptor after a restart, and HTLCs that were held
+	//before a restart are listed even if they weren't replayed yet.
+	ListHeldForwards(context.Context, *ListHeldForwardsRequest) (*ListHeldForwardsResponse, error)
+	//
 	//UpdateChanStatus attempts to manually set the state of a channel
 	//(enabled, disabled, or auto). A manual "disable" request will cause the
 	//channel to stay disabled until a subsequent manual request of either
 	//"enable" or "auto".
 	UpdateChanStatus(context.Context, *UpdateChanStatusRequest) (*UpdateChanStatusResponse, error)
+	//
+	//RebalanceChannel moves liquidity between two of our channels by paying an
+	//invoice of our own node. The payment leaves through the outgoing channel
+	//and returns through the incoming channel, while the route in between is
+	//found through path finding. The call returns a stream of payment updates.
+	RebalanceChannel(*RebalanceChannelRequest, Router_RebalanceChannelServer) error
 	mustEmbedUnimplementedRouterServer()
 }
 
@@ -551,9 +624,15 @@ func (UnimplementedRouterServer) TrackPayment(*TrackPaymentRequest, Router_Track
 func (UnimplementedRouterServer) HtlcInterceptor(Router_HtlcInterceptorServer) error {
 	return status.Errorf(codes.Unimplemented, "method HtlcInterceptor not implemented")
 }
+func (UnimplementedRouterServer) ListHeldForwards(context.Context, *ListHeldForwardsRequest) (*ListHeldForwardsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListHeldForwards not implemented")
+}
 func (UnimplementedRouterServer) UpdateChanStatus(context.Context, *UpdateChanStatusRequest) (*UpdateChanStatusResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateChanStatus not implemented")
 }
+func (UnimplementedRouterServer) RebalanceChannel(*RebalanceChannelRequest, Router_RebalanceChannelServer) error {
+	return status.Errorf(codes.Unimplemented, "method RebalanceChannel not implemented")
+}
 func (UnimplementedRouterServer) mustEmbedUnimplementedRouterServer() {}
 
 // UnsafeRouterServer may be embedded to opt out of forward compatibility for this service.
@@ -878,6 +957,24 @@ func (x *routerHtlcInterceptorServer) Recv() (*ForwardHtlcInterceptResponse, err
 	return m, nil
 }
 
+func _Router_ListHeldForwards_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListHeldForwardsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).ListHeldForwards(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routerrpc.Router/ListHeldForwards",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).ListHeldForwards(ctx, req.(*ListHeldForwardsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Router_UpdateChanStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(UpdateChanStatusRequest)
 	if err := dec(in); err != nil {
@@ -896,6 +993,27 @@ func _Router_UpdateChanStatus_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Router_RebalanceChannel_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(RebalanceChannelRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RouterServer).RebalanceChannel(m, &routerRebalanceChannelServer{stream})
+}
+
+type Router_RebalanceChannelServer interface {
+	Send(*lnrpc.Payment) error
+	grpc.ServerStream
+}
+
+type routerRebalanceChannelServer struct {
+	grpc.ServerStream
+}
+
+func (x *routerRebalanceChannelServer) Send(m *lnrpc.Payment) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // Router_ServiceDesc is the grpc.ServiceDesc for Router service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -943,6 +1061,10 @@ var Router_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "BuildRoute",
 			Handler:    _Router_BuildRoute_Handler,
 		},
+		{
+			MethodName: "ListHeldForwards",
+			Handler:    _Router_ListHeldForwards_Handler,
+		},
 		{
 			MethodName: "UpdateChanStatus",
 			Handler:    _Router_UpdateChanStatus_Handler,
@@ -980,6 +1102,11 @@ var Router_ServiceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "RebalanceChannel",
+			Handler:       _Router_RebalanceChannel_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "routerrpc/router.proto",
 }
@@ -121,10 +121,18 @@ var (
 			Entity: "offchain",
 			Action: "write",
 		}},
+		"/routerrpc.Router/ListHeldForwards": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
 		"/routerrpc.Router/UpdateChanStatus": {{
 			Entity: "offchain",
 			Action: "write",
 		}},
+		"/routerrpc.Router/RebalanceChannel": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
 	}
 
 	// DefaultRouterMacFilename is the default name of the router macaroon
@@ -338,6 +346,37 @@ func (s *Server) SendPaymentV2(req *SendPaymentRequest,
 	return s.trackPayment(payment.Identifier(), stream, req.NoInflightUpdates)
 }
 
+// RebalanceChannel moves liquidity between two of our channels by paying an
+// invoice of our own node through the outgoing channel of the request, and
+// back to us through its incoming channel. The payment is tracked like any
+// other payment, and its updates are streamed back to the caller.
+func (s *Server) RebalanceChannel(req *RebalanceChannelRequest,
+	stream Router_RebalanceChannelServer) error {
+
+	payment, err := s.cfg.RouterBackend.extractIntentFromRebalanceRequest(
+		stream.Context(), req,
+	)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Rebalancing %v from channel %v to channel %v, payment %x",
+		payment.Amount, req.OutgoingChanId, req.IncomingChanId,
+		payment.Identifier())
+
+	err = s.cfg.Router.SendPaymentAsync(payment)
+	if err != nil {
+		log.Errorf("Rebalance async error for payment %x: %v",
+			payment.Identifier(), err)
+
+		return err
+	}
+
+	return s.trackPayment(
+		payment.Identifier(), stream, req.NoInflightUpdates,
+	)
+}
+
 // EstimateRouteFee allows callers to obtain a lower bound w.r.t how much it
 // may cost to send an HTLC to the target end destination.
 func (s *Server) EstimateRouteFee(ctx context.Context,
@@ -894,6 +933,41 @@ func (s *Server) HtlcInterceptor(stream Router_HtlcInterceptorServer) error {
 	return newForwardInterceptor(s, stream).run()
 }
 
+// ListHeldForwards lists all htlcs that are held by an htlc interceptor,
+// including the ones that were held before a restart and weren't replayed
+// yet.
+func (s *Server) ListHeldForwards(_ context.Context,
+	_ *ListHeldForwardsRequest) (*ListHeldForwardsResponse, error) {
+
+	forwarder := s.cfg.RouterBackend.InterceptableForwarder
+	heldForwards := forwarder.HeldForwards()
+
+	now := time.Now()
+	resp := &ListHeldForwardsResponse{
+		HeldForwards: make([]*HeldForward, 0, len(heldForwards)),
+	}
+	for _, f := range heldForwards {
+		resp.HeldForwards = append(resp.HeldForwards, &HeldForward{
+			IncomingCircuitKey: &CircuitKey{
+				ChanId: f.IncomingCircuit.ChanID.ToUint64(),
+				HtlcId: f.IncomingCircuit.HtlcID,
+			},
+			IncomingAmountMsat:      uint64(f.IncomingAmount),
+			IncomingExpiry:          f.IncomingExpiry,
+			PaymentHash:             f.Hash[:],
+			OutgoingRequestedChanId: f.OutgoingChanID.ToUint64(),
+			OutgoingAmountMsat:      uint64(f.OutgoingAmount),
+			OutgoingExpiry:          f.OutgoingExpiry,
+			HeldSince:               f.HeldSince.Unix(),
+			HoldDurationSec: uint64(
+				now.Sub(f.HeldSince) / time.Second,
+			),
+		})
+	}
+
+	return resp, nil
+}
+
 func extractOutPoint(req *UpdateChanStatusRequest) (*wire.OutPoint, error) {
 	chanPoint := req.GetChanPoint()
 	txid, err := lnrpc.GetChanPointFundingTxid(chanPoint)
@@ -36,4 +36,8 @@ type Config struct {
 	// KeyRing is an interface that the signer will use to derive any keys
 	// for signing messages.
 	KeyRing keychain.SecretKeyRing
+
+	// MuSig2Signer keeps track of the MuSig2 signing sessions that are
+	// created through the signer RPC server.
+	MuSig2Signer input.MuSig2Signer
 }
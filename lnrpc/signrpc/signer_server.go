@@ -6,6 +6,7 @@ package signrpc
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -17,6 +18,8 @@ import (
 	"github.com/brronsuite/broln/lnwire"
 	"github.com/brronsuite/broln/macaroons"
 	"github.com/brronsuite/brond/bronec"
+	"github.com/brronsuite/brond/bronec/schnorr"
+	"github.com/brronsuite/brond/bronec/schnorr/musig2"
 	"github.com/brronsuite/brond/chaincfg/chainhash"
 	"github.com/brronsuite/brond/txscript"
 	"github.com/brronsuite/brond/wire"
@@ -69,6 +72,30 @@ var (
 			Entity: "signer",
 			Action: "generate",
 		}},
+		"/signrpc.Signer/MuSig2CombineKeys": {{
+			Entity: "signer",
+			Action: "read",
+		}},
+		"/signrpc.Signer/MuSig2CreateSession": {{
+			Entity: "signer",
+			Action: "generate",
+		}},
+		"/signrpc.Signer/MuSig2RegisterNonces": {{
+			Entity: "signer",
+			Action: "generate",
+		}},
+		"/signrpc.Signer/MuSig2Sign": {{
+			Entity: "signer",
+			Action: "generate",
+		}},
+		"/signrpc.Signer/MuSig2CombineSig": {{
+			Entity: "signer",
+			Action: "generate",
+		}},
+		"/signrpc.Signer/MuSig2Cleanup": {{
+			Entity: "signer",
+			Action: "generate",
+		}},
 	}
 
 	// DefaultSignerMacFilename is the default name of the signer macaroon
@@ -266,7 +293,11 @@ func (s *Server) SignOutputRaw(ctx context.Context, in *SignReq) (*SignResp,
 		return nil, fmt.Errorf("unable to decode tx: %v", err)
 	}
 
-	sigHashCache := txscript.NewTxSigHashes(&txToSign)
+	prevOutFetcher, err := parsePrevOutputs(&txToSign, in)
+	if err != nil {
+		return nil, err
+	}
+	sigHashCache := txscript.NewTxSigHashes(&txToSign, prevOutFetcher)
 
 	log.Debugf("Generating sigs for %v inputs: ", len(in.SignDescs))
 
@@ -308,6 +339,23 @@ func (s *Server) SignOutputRaw(ctx context.Context, in *SignReq) (*SignResp,
 			}
 		}
 
+		signMethod, err := UnmarshalSignMethod(signDesc.SignMethod)
+		if err != nil {
+			return nil, err
+		}
+
+		// Make sure the sign method matches the output that is being
+		// spent.
+		output := signDesc.Output
+		if output == nil ||
+			!signMethod.PkScriptCompatible(output.PkScript) {
+
+			return nil, fmt.Errorf("sign method %v is not "+
+				"compatible with the output being spent",
+				signMethod)
+		}
+
+		switch signMethod {
 		// If a witness script isn't passed, then we can't proceed, as
 		// in the p2wsh case, we can't properly generate the sighash.
 		// A P2WKH doesn't need a witness script. But SignOutputRaw
@@ -315,9 +363,45 @@ func (s *Server) SignOutputRaw(ctx context.Context, in *SignReq) (*SignResp,
 		// output. We'll send it in the WitnessScript field, the
 		// SignOutputRaw RPC will know what to do with it when creating
 		// the sighash.
-		if len(signDesc.WitnessScript) == 0 {
-			return nil, fmt.Errorf("witness script MUST be " +
-				"specified")
+		case input.WitnessV0SignMethod:
+			if len(signDesc.WitnessScript) == 0 {
+				return nil, fmt.Errorf("witness script MUST " +
+					"be specified")
+			}
+
+		// A BIP-0086 key spend only commits to the internal key, so no
+		// tweak is allowed.
+		case input.TaprootKeySpendBIP0086SignMethod:
+			if len(signDesc.TapTweak) > 0 {
+				return nil, fmt.Errorf("tap tweak must be " +
+					"empty for BIP0086 key spend")
+			}
+
+		// A key spend of an output that commits to a script tree needs
+		// the root hash of that tree.
+		case input.TaprootKeySpendSignMethod:
+			if len(signDesc.TapTweak) != sha256.Size {
+				return nil, fmt.Errorf("tap tweak must be %d "+
+					"bytes for taproot key spend",
+					sha256.Size)
+			}
+
+		// A script spend signs for the leaf script, so we need to know
+		// it.
+		case input.TaprootScriptSpendSignMethod:
+			if len(signDesc.WitnessScript) == 0 {
+				return nil, fmt.Errorf("witness script MUST " +
+					"be specified for taproot script spend")
+			}
+		}
+
+		// Taproot signatures commit to all previous outputs, so the
+		// caller must tell us about all of them.
+		if signMethod != input.WitnessV0SignMethod &&
+			len(in.PrevOutputs) != len(txToSign.TxIn) {
+
+			return nil, fmt.Errorf("all previous outputs MUST " +
+				"be specified when signing a taproot input")
 		}
 
 		// If the users provided a double tweak, then we'll need to
@@ -340,13 +424,16 @@ func (s *Server) SignOutputRaw(ctx context.Context, in *SignReq) (*SignResp,
 			SingleTweak:   signDesc.SingleTweak,
 			DoubleTweak:   tweakPrivKey,
 			WitnessScript: signDesc.WitnessScript,
+			SignMethod:    signMethod,
+			TapTweak:      signDesc.TapTweak,
 			Output: &wire.TxOut{
 				Value:    signDesc.Output.Value,
 				PkScript: signDesc.Output.PkScript,
 			},
-			HashType:   txscript.SigHashType(signDesc.Sighash),
-			SigHashes:  sigHashCache,
-			InputIndex: int(signDesc.InputIndex),
+			HashType:          txscript.SigHashType(signDesc.Sighash),
+			SigHashes:         sigHashCache,
+			PrevOutputFetcher: prevOutFetcher,
+			InputIndex:        int(signDesc.InputIndex),
 		})
 	}
 
@@ -405,7 +492,11 @@ func (s *Server) ComputeInputScript(ctx context.Context,
 		return nil, fmt.Errorf("unable to decode tx: %v", err)
 	}
 
-	sigHashCache := txscript.NewTxSigHashes(&txToSign)
+	prevOutFetcher, err := parsePrevOutputs(&txToSign, in)
+	if err != nil {
+		return nil, err
+	}
+	sigHashCache := txscript.NewTxSigHashes(&txToSign, prevOutFetcher)
 
 	signDescs := make([]*input.SignDescriptor, 0, len(in.SignDescs))
 	for _, signDesc := range in.SignDescs {
@@ -418,9 +509,10 @@ func (s *Server) ComputeInputScript(ctx context.Context,
 				Value:    signDesc.Output.Value,
 				PkScript: signDesc.Output.PkScript,
 			},
-			HashType:   txscript.SigHashType(signDesc.Sighash),
-			SigHashes:  sigHashCache,
-			InputIndex: int(signDesc.InputIndex),
+			HashType:          txscript.SigHashType(signDesc.Sighash),
+			SigHashes:         sigHashCache,
+			PrevOutputFetcher: prevOutFetcher,
+			InputIndex:        int(signDesc.InputIndex),
 		})
 	}
 
@@ -448,6 +540,60 @@ func (s *Server) ComputeInputScript(ctx context.Context,
 	return resp, nil
 }
 
+// parsePrevOutputs returns a previous output fetcher for the transaction of
+// the sign request. If the request contains the full list of previous outputs
+// then those are used. Otherwise the fetcher only knows about the outputs
+// referenced by the sign descriptors.
+func parsePrevOutputs(tx *wire.MsgTx,
+	in *SignReq) (*txscript.MultiPrevOutFetcher, error) {
+
+	if len(in.PrevOutputs) == 0 {
+		return prevOutFetcherFromSignDescs(tx, in.SignDescs), nil
+	}
+
+	if len(in.PrevOutputs) != len(tx.TxIn) {
+		return nil, fmt.Errorf("must supply exactly one previous "+
+			"output per input, got %d previous outputs for %d "+
+			"inputs", len(in.PrevOutputs), len(tx.TxIn))
+	}
+
+	fetcher := txscript.NewMultiPrevOutFetcher(nil)
+	for idx, prevOut := range in.PrevOutputs {
+		if prevOut == nil {
+			return nil, fmt.Errorf("previous output %d is nil", idx)
+		}
+
+		fetcher.AddPrevOut(tx.TxIn[idx].PreviousOutPoint, &wire.TxOut{
+			Value:    prevOut.Value,
+			PkScript: prevOut.PkScript,
+		})
+	}
+
+	return fetcher, nil
+}
+
+// prevOutFetcherFromSignDescs returns a previous output fetcher that knows
+// about the outputs referenced by the given sign descriptors. Inputs without a
+// sign descriptor are unknown to the fetcher.
+func prevOutFetcherFromSignDescs(tx *wire.MsgTx,
+	signDescs []*SignDescriptor) *txscript.MultiPrevOutFetcher {
+
+	fetcher := txscript.NewMultiPrevOutFetcher(nil)
+	for _, signDesc := range signDescs {
+		idx := int(signDesc.InputIndex)
+		if signDesc.Output == nil || idx < 0 || idx >= len(tx.TxIn) {
+			continue
+		}
+
+		fetcher.AddPrevOut(tx.TxIn[idx].PreviousOutPoint, &wire.TxOut{
+			Value:    signDesc.Output.Value,
+			PkScript: signDesc.Output.PkScript,
+		})
+	}
+
+	return fetcher
+}
+
 // SignMessage signs a message with the key specified in the key locator. The
 // returned signature is fixed-size LN wire format encoded.
 func (s *Server) SignMessage(_ context.Context,
@@ -652,3 +798,379 @@ func parseRawKeyBytes(rawKeyBytes []byte) (*bronec.PublicKey, error) {
 			"specified")
 	}
 }
+
+// MuSig2CombineKeys combines the given set of public keys into a single
+// combined MuSig2 combined public key, applying the given tweaks.
+func (s *Server) MuSig2CombineKeys(_ context.Context,
+	in *MuSig2CombineKeysRequest) (*MuSig2CombineKeysResponse, error) {
+
+	// Parse the public keys of all signing participants. This must also
+	// include our own, local key.
+	allSignerPubKeys, err := input.MuSig2ParsePubKeys(
+		in.AllSignerPubkeys,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing all signer public "+
+			"keys: %v", err)
+	}
+
+	// Are there any tweaks to apply to the combined public key?
+	tweaks, err := UnmarshalTweaks(in.Tweaks, in.TaprootTweak)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshaling tweak options: %v",
+			err)
+	}
+
+	// Combine the keys now without creating a session in memory.
+	combinedKey, err := input.MuSig2CombineKeys(allSignerPubKeys, tweaks)
+	if err != nil {
+		return nil, fmt.Errorf("error combining keys: %v", err)
+	}
+
+	var internalKeyBytes []byte
+	if combinedKey.PreTweakedKey != nil {
+		internalKeyBytes = schnorr.SerializePubKey(
+			combinedKey.PreTweakedKey,
+		)
+	}
+
+	return &MuSig2CombineKeysResponse{
+		CombinedKey: schnorr.SerializePubKey(
+			combinedKey.FinalKey,
+		),
+		TaprootInternalKey: internalKeyBytes,
+	}, nil
+}
+
+// MuSig2CreateSession creates a new MuSig2 signing session using the local
+// key identified by the key locator. The complete list of all public keys of
+// all signing parties must be provided, including the public key of the local
+// signing key. If nonces of other parties are already known, they can be
+// submitted as well to reduce the number of RPC calls necessary later on.
+func (s *Server) MuSig2CreateSession(_ context.Context,
+	in *MuSig2SessionRequest) (*MuSig2SessionResponse, error) {
+
+	// A key locator is always mandatory.
+	if in.KeyLoc == nil {
+		return nil, fmt.Errorf("missing key_loc")
+	}
+	keyLoc := keychain.KeyLocator{
+		Family: keychain.KeyFamily(in.KeyLoc.KeyFamily),
+		Index:  uint32(in.KeyLoc.KeyIndex),
+	}
+
+	// Parse the public keys of all signing participants. This must also
+	// include our own, local key.
+	allSignerPubKeys, err := input.MuSig2ParsePubKeys(
+		in.AllSignerPubkeys,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing all signer public "+
+			"keys: %v", err)
+	}
+
+	// We participate a nonce ourselves, so we can't have more nonces than
+	// the total number of participants minus ourselves.
+	maxNonces := len(in.AllSignerPubkeys) - 1
+	if len(in.OtherSignerPublicNonces) > maxNonces {
+		return nil, fmt.Errorf("too many other signer public nonces, "+
+			"got %d but expected a maximum of %d",
+			len(in.OtherSignerPublicNonces), maxNonces)
+	}
+
+	// Parse all other nonces we might already know.
+	otherSignerNonces, err := parseMuSig2PublicNonces(
+		in.OtherSignerPublicNonces,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing other nonces: %v", err)
+	}
+
+	// Are there any tweaks to apply to the combined public key?
+	tweaks, err := UnmarshalTweaks(in.Tweaks, in.TaprootTweak)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshaling tweak options: %v",
+			err)
+	}
+
+	// Register the session with the internal wallet/signer now.
+	session, err := s.cfg.MuSig2Signer.MuSig2CreateSession(
+		keyLoc, allSignerPubKeys, tweaks, otherSignerNonces,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error registering session: %v", err)
+	}
+
+	var internalKeyBytes []byte
+	if session.TaprootTweak {
+		internalKeyBytes = schnorr.SerializePubKey(
+			session.TaprootInternalKey,
+		)
+	}
+
+	return &MuSig2SessionResponse{
+		SessionId: session.SessionID[:],
+		CombinedKey: schnorr.SerializePubKey(
+			session.CombinedKey,
+		),
+		TaprootInternalKey: internalKeyBytes,
+		LocalPublicNonces:  session.PublicNonce[:],
+		HaveAllNonces:      session.HaveAllNonces,
+	}, nil
+}
+
+// MuSig2RegisterNonces registers one or more public nonces of other signing
+// participants for a session identified by its ID.
+func (s *Server) MuSig2RegisterNonces(_ context.Context,
+	in *MuSig2RegisterNoncesRequest) (*MuSig2RegisterNoncesResponse,
+	error) {
+
+	// Check session ID length.
+	sessionID, err := parseMuSig2SessionID(in.SessionId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse the other signing participants' nonces. We can't validate the
+	// number of nonces here because we don't have access to the session in
+	// this context. But the signer will be able to make sure we don't
+	// register more nonces than there are signers (which would mean
+	// something is wrong in the signing setup). But we want at least a
+	// single nonce for each call.
+	if len(in.OtherSignerPublicNonces) == 0 {
+		return nil, fmt.Errorf("at least one other signer public " +
+			"nonce is required")
+	}
+	otherSignerNonces, err := parseMuSig2PublicNonces(
+		in.OtherSignerPublicNonces,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing other nonces: %v", err)
+	}
+
+	// Register the nonces now.
+	haveAllNonces, err := s.cfg.MuSig2Signer.MuSig2RegisterNonces(
+		sessionID, otherSignerNonces,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error registering nonces: %v", err)
+	}
+
+	return &MuSig2RegisterNoncesResponse{HaveAllNonces: haveAllNonces}, nil
+}
+
+// MuSig2Sign creates a partial signature using the local signing key that was
+// specified when the session was created. This can only be called when all
+// public nonces of all participants are known and have been registered with
+// the session. If this node isn't responsible for combining all the partial
+// signatures, then the cleanup flag should be set, indicating that the session
+// can be removed from memory once the signature was produced.
+func (s *Server) MuSig2Sign(_ context.Context,
+	in *MuSig2SignRequest) (*MuSig2SignResponse, error) {
+
+	// Check session ID length.
+	sessionID, err := parseMuSig2SessionID(in.SessionId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Schnorr signatures only work reliably if the message is 32 bytes.
+	msg := [sha256.Size]byte{}
+	if len(in.MessageDigest) != sha256.Size {
+		return nil, fmt.Errorf("invalid message digest size, got %d "+
+			"but expected %d", len(in.MessageDigest), sha256.Size)
+	}
+	copy(msg[:], in.MessageDigest)
+
+	// Create our own partial signature with the local signing key.
+	partialSig, err := s.cfg.MuSig2Signer.MuSig2Sign(
+		sessionID, msg, in.Cleanup,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error signing: %v", err)
+	}
+
+	serializedPartialSig, err := input.SerializePartialSignature(
+		partialSig,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error serializing sig: %v", err)
+	}
+
+	return &MuSig2SignResponse{
+		LocalPartialSignature: serializedPartialSig[:],
+	}, nil
+}
+
+// MuSig2CombineSig combines the given partial signature(s) with the local one,
+// if it already exists. Once a partial signature of all participants is
+// registered, the final signature will be combined and returned.
+func (s *Server) MuSig2CombineSig(_ context.Context,
+	in *MuSig2CombineSigRequest) (*MuSig2CombineSigResponse, error) {
+
+	// Check session ID length.
+	sessionID, err := parseMuSig2SessionID(in.SessionId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse all other signatures. This can be called multiple times, so we
+	// can't really sanity check how many we already have vs. how many the
+	// user supplied in this call.
+	partialSigs, err := parseMuSig2PartialSigs(in.OtherPartialSignatures)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing partial signatures: %v",
+			err)
+	}
+
+	// Combine the signatures now.
+	finalSig, haveAllSigs, err := s.cfg.MuSig2Signer.MuSig2CombineSig(
+		sessionID, partialSigs,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error combining signatures: %v", err)
+	}
+
+	resp := &MuSig2CombineSigResponse{
+		HaveAllSignatures: haveAllSigs,
+	}
+	if haveAllSigs {
+		resp.FinalSignature = finalSig.Serialize()
+	}
+
+	return resp, nil
+}
+
+// MuSig2Cleanup removes a session from memory to free up resources.
+func (s *Server) MuSig2Cleanup(_ context.Context,
+	in *MuSig2CleanupRequest) (*MuSig2CleanupResponse, error) {
+
+	// Check session ID length.
+	sessionID, err := parseMuSig2SessionID(in.SessionId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.cfg.MuSig2Signer.MuSig2Cleanup(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("error cleaning up session: %v", err)
+	}
+
+	return &MuSig2CleanupResponse{}, nil
+}
+
+// parseMuSig2SessionID parses a MuSig2 session ID from its raw bytes.
+func parseMuSig2SessionID(rawID []byte) (input.MuSig2SessionID, error) {
+	var sessionID input.MuSig2SessionID
+	if len(rawID) != len(sessionID) {
+		return sessionID, fmt.Errorf("invalid session ID size, got %d "+
+			"but expected %d", len(rawID), len(sessionID))
+	}
+	copy(sessionID[:], rawID)
+
+	return sessionID, nil
+}
+
+// parseMuSig2PublicNonces sanity checks and parses the other signers' public
+// nonces.
+func parseMuSig2PublicNonces(
+	pubNonces [][]byte) ([][musig2.PubNonceSize]byte, error) {
+
+	// For some calls the nonces are optional while for others it doesn't
+	// make any sense to not specify them (for example for the explicit
+	// nonce registration call). So we don't check for the number of nonces
+	// here.
+	result := make([][musig2.PubNonceSize]byte, len(pubNonces))
+	for idx, otherNonceBytes := range pubNonces {
+		if len(otherNonceBytes) != musig2.PubNonceSize {
+			return nil, fmt.Errorf("invalid public nonce at index "+
+				"%d: invalid length, got %d but expected %d",
+				idx, len(otherNonceBytes), musig2.PubNonceSize)
+		}
+		copy(result[idx][:], otherNonceBytes)
+	}
+
+	return result, nil
+}
+
+// parseMuSig2PartialSigs parses the other signers' partial signatures.
+func parseMuSig2PartialSigs(
+	partialSignatures [][]byte) ([]*musig2.PartialSignature, error) {
+
+	// We wait for at least one signature. The user can call this RPC
+	// multiple times to add more signatures.
+	if len(partialSignatures) == 0 {
+		return nil, fmt.Errorf("at least one partial signature is " +
+			"required")
+	}
+
+	result := make([]*musig2.PartialSignature, len(partialSignatures))
+	for idx, sig := range partialSignatures {
+		parsedSig, err := input.DeserializePartialSignature(sig)
+		if err != nil {
+			return nil, fmt.Errorf("invalid partial signature at "+
+				"index %d: %v", idx, err)
+		}
+
+		result[idx] = parsedSig
+	}
+
+	return result, nil
+}
+
+// UnmarshalTweaks parses the RPC tweak descriptions into their native
+// counterpart.
+func UnmarshalTweaks(rpcTweaks []*TweakDesc,
+	taprootTweak *TaprootTweakDesc) (*input.MuSig2Tweaks, error) {
+
+	// Parse the generic tweaks first.
+	tweaks := &input.MuSig2Tweaks{
+		GenericTweaks: make([]musig2.KeyTweakDesc, len(rpcTweaks)),
+	}
+	for idx, rpcTweak := range rpcTweaks {
+		if len(rpcTweak.Tweak) != sha256.Size {
+			return nil, fmt.Errorf("tweak at index %d must be %d "+
+				"bytes", idx, sha256.Size)
+		}
+
+		copy(tweaks.GenericTweaks[idx].Tweak[:], rpcTweak.Tweak)
+		tweaks.GenericTweaks[idx].IsXOnly = rpcTweak.IsXOnly
+	}
+
+	// Now parse the taproot specific tweak.
+	if taprootTweak != nil {
+		if taprootTweak.KeySpendOnly {
+			tweaks.TaprootBIP0086Tweak = true
+		} else {
+			if len(taprootTweak.ScriptRoot) == 0 {
+				return nil, fmt.Errorf("script root cannot " +
+					"be empty for non-keyspend")
+			}
+
+			tweaks.TaprootTweak = taprootTweak.ScriptRoot
+		}
+	}
+
+	return tweaks, nil
+}
+
+// UnmarshalSignMethod parses the RPC sign method into the native counterpart.
+func UnmarshalSignMethod(rpcSignMethod SignMethod) (input.SignMethod, error) {
+	switch rpcSignMethod {
+	case SignMethod_SIGN_METHOD_WITNESS_V0:
+		return input.WitnessV0SignMethod, nil
+
+	case SignMethod_SIGN_METHOD_TAPROOT_KEY_SPEND_BIP0086:
+		return input.TaprootKeySpendBIP0086SignMethod, nil
+
+	case SignMethod_SIGN_METHOD_TAPROOT_KEY_SPEND:
+		return input.TaprootKeySpendSignMethod, nil
+
+	case SignMethod_SIGN_METHOD_TAPROOT_SCRIPT_SPEND:
+		return input.TaprootScriptSpendSignMethod, nil
+
+	default:
+		return 0, fmt.Errorf("unknown RPC sign method <%d>",
+			rpcSignMethod)
+	}
+}
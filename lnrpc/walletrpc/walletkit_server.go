@@ -524,6 +524,9 @@ func (w *WalletKit) NextAddr(ctx context.Context,
 	case AddressType_NESTED_WITNESS_PUBKEY_HASH:
 		addrType = lnwallet.NestedWitnessPubKey
 
+	case AddressType_TAPROOT_PUBKEY:
+		addrType = lnwallet.TaprootPubkey
+
 	case AddressType_HYBRID_NESTED_WITNESS_PUBKEY_HASH:
 		return nil, fmt.Errorf("invalid address type for next "+
 			"address: %v", req.Type)
@@ -700,6 +703,8 @@ func (w *WalletKit) PendingSweeps(ctx context.Context,
 			witnessType = WitnessType_NESTED_WITNESS_KEY_HASH
 		case input.CommitmentAnchor:
 			witnessType = WitnessType_COMMITMENT_ANCHOR
+		case input.TaprootPubKeySpend:
+			witnessType = WitnessType_TAPROOT_PUB_KEY_SPEND
 		default:
 			log.Warnf("Unhandled witness type %v for input %v",
 				pendingInput.WitnessType, pendingInput.OutPoint)
@@ -849,6 +854,8 @@ func (w *WalletKit) BumpFee(ctx context.Context,
 		witnessType = input.WitnessKeyHash
 	case lnwallet.NestedWitnessPubKey:
 		witnessType = input.NestedWitnessKeyHash
+	case lnwallet.TaprootPubkey:
+		witnessType = input.TaprootPubKeySpend
 	default:
 		return nil, fmt.Errorf("unknown input witness %v", op)
 	}
@@ -1326,6 +1333,9 @@ func marshalWalletAccount(internalScope waddrmgr.KeyScope,
 	case waddrmgr.KeyScopeBIP0084:
 		addrType = AddressType_WITNESS_PUBKEY_HASH
 
+	case waddrmgr.KeyScopeBIP0086:
+		addrType = AddressType_TAPROOT_PUBKEY
+
 	case internalScope:
 		addrType = AddressType_WITNESS_PUBKEY_HASH
 
@@ -1384,6 +1394,10 @@ func (w *WalletKit) ListAccounts(ctx context.Context,
 		keyScope := waddrmgr.KeyScopeBIP0049Plus
 		keyScopeFilter = &keyScope
 
+	case AddressType_TAPROOT_PUBKEY:
+		keyScope := waddrmgr.KeyScopeBIP0086
+		keyScopeFilter = &keyScope
+
 	default:
 		return nil, fmt.Errorf("unhandled address type %v", req.AddressType)
 	}
@@ -1438,6 +1452,10 @@ func parseAddrType(addrType AddressType,
 		addrTyp := waddrmgr.WitnessPubKey
 		return &addrTyp, nil
 
+	case AddressType_TAPROOT_PUBKEY:
+		addrTyp := waddrmgr.TaprootPubKey
+		return &addrTyp, nil
+
 	default:
 		return nil, fmt.Errorf("unhandled address type %v", addrType)
 	}
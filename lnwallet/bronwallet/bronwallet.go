@@ -65,6 +65,14 @@ var (
 		InternalAddrType: waddrmgr.WitnessPubKey,
 	}
 
+	// walletKeyScopes are the key scopes of the on-chain wallet that the
+	// default and imported accounts span across.
+	walletKeyScopes = []waddrmgr.KeyScope{
+		waddrmgr.KeyScopeBIP0049Plus,
+		waddrmgr.KeyScopeBIP0084,
+		waddrmgr.KeyScopeBIP0086,
+	}
+
 	// errNoImportedAddrGen is an error returned when a new address is
 	// requested for the default imported account within the wallet.
 	errNoImportedAddrGen = errors.New("addresses cannot be generated for " +
@@ -339,6 +347,17 @@ func (b *bronwallet) Start() error {
 		}
 	}
 
+	// Because we might add new "default" key scopes over time, they are
+	// created correctly for new wallets. Existing wallets don't
+	// automatically add them, we need to do that manually now. This isn't
+	// possible for watch-only wallets as they lack the private keys to
+	// derive the new accounts.
+	if !walletIsWatchOnly {
+		if err := b.addMissingDefaultKeyScopes(); err != nil {
+			return err
+		}
+	}
+
 	// Now that the wallet is unlocked, we'll go ahead and make sure we
 	// create accounts for all the key families we're going to use. This
 	// will make it possible to list all the account/family xpubs in the
@@ -405,6 +424,43 @@ func (b *bronwallet) Start() error {
 	return nil
 }
 
+// addMissingDefaultKeyScopes creates all default key scopes of the address
+// manager that don't exist yet in the wallet, for example the BIP-0086 scope in
+// wallets that were created before taproot addresses were supported.
+func (b *bronwallet) addMissingDefaultKeyScopes() error {
+	for _, scope := range waddrmgr.DefaultKeyScopes {
+		_, err := b.wallet.Manager.FetchScopedKeyManager(scope)
+		switch {
+		case err == nil:
+			continue
+
+		case !waddrmgr.IsError(err, waddrmgr.ErrScopeNotFound):
+			return err
+		}
+
+		log.Infof("Adding missing default key scope %v to wallet",
+			scope)
+
+		addrSchema := waddrmgr.ScopeAddrMap[scope]
+		err = walletdb.Update(
+			b.db, func(tx walletdb.ReadWriteTx) error {
+				ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+
+				_, err := b.wallet.Manager.NewScopedKeyManager(
+					ns, scope, addrSchema,
+				)
+				return err
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("unable to create key scope %v: %v",
+				scope, err)
+		}
+	}
+
+	return nil
+}
+
 // Stop signals the wallet for shutdown. Shutdown may entail closing
 // any active sockets, database handles, stopping goroutines, etc.
 //
@@ -458,6 +514,8 @@ func (b *bronwallet) keyScopeForAccountAddr(accountName string,
 		addrKeyScope = waddrmgr.KeyScopeBIP0084
 	case lnwallet.NestedWitnessPubKey:
 		addrKeyScope = waddrmgr.KeyScopeBIP0049Plus
+	case lnwallet.TaprootPubkey:
+		addrKeyScope = waddrmgr.KeyScopeBIP0086
 	default:
 		return waddrmgr.KeyScope{}, 0,
 			fmt.Errorf("unknown address type")
@@ -562,25 +620,19 @@ func (b *bronwallet) ListAccounts(name string,
 	// Only the name filter was provided.
 	case name != "" && keyScope == nil:
 		// If the name corresponds to the default or imported accounts,
-		// we'll return them for both of our supported key scopes.
+		// we'll return them for all of our supported key scopes.
 		if name == lnwallet.DefaultAccountName ||
 			name == waddrmgr.ImportedAddrAccountName {
 
-			a1, err := b.wallet.AccountPropertiesByName(
-				waddrmgr.KeyScopeBIP0049Plus, name,
-			)
-			if err != nil {
-				return nil, err
-			}
-			res = append(res, a1)
-
-			a2, err := b.wallet.AccountPropertiesByName(
-				waddrmgr.KeyScopeBIP0084, name,
-			)
-			if err != nil {
-				return nil, err
+			for _, scope := range walletKeyScopes {
+				a, err := b.wallet.AccountPropertiesByName(
+					scope, name,
+				)
+				if err != nil {
+					return nil, err
+				}
+				res = append(res, a)
 			}
-			res = append(res, a2)
 			break
 		}
 
@@ -611,34 +663,21 @@ func (b *bronwallet) ListAccounts(name string,
 	// Neither of the filters were provided, so return all accounts for our
 	// supported key scopes.
 	case name == "" && keyScope == nil:
-		accounts, err := b.wallet.Accounts(waddrmgr.KeyScopeBIP0049Plus)
-		if err != nil {
-			return nil, err
-		}
-		for _, account := range accounts.Accounts {
-			account := account
-			res = append(res, &account.AccountProperties)
-		}
-
-		accounts, err = b.wallet.Accounts(waddrmgr.KeyScopeBIP0084)
-		if err != nil {
-			return nil, err
-		}
-		for _, account := range accounts.Accounts {
-			account := account
-			res = append(res, &account.AccountProperties)
-		}
-
-		accounts, err = b.wallet.Accounts(waddrmgr.KeyScope{
+		scopes := append([]waddrmgr.KeyScope{}, walletKeyScopes...)
+		scopes = append(scopes, waddrmgr.KeyScope{
 			Purpose: keychain.BIP0043Purpose,
 			Coin:    b.cfg.CoinType,
 		})
-		if err != nil {
-			return nil, err
-		}
-		for _, account := range accounts.Accounts {
-			account := account
-			res = append(res, &account.AccountProperties)
+
+		for _, scope := range scopes {
+			accounts, err := b.wallet.Accounts(scope)
+			if err != nil {
+				return nil, err
+			}
+			for _, account := range accounts.Accounts {
+				account := account
+				res = append(res, &account.AccountProperties)
+			}
 		}
 	}
 
@@ -899,10 +938,13 @@ func (b *bronwallet) ListUnspentWitness(minConfs, maxConfs int32,
 			// wallet are nested p2pkh. We can't check the redeem script because
 			// the bronwallet service does not include it.
 			addressType = lnwallet.NestedWitnessPubKey
+		} else if txscript.IsPayToTaproot(pkScript) {
+			addressType = lnwallet.TaprootPubkey
 		}
 
 		if addressType == lnwallet.WitnessPubKey ||
-			addressType == lnwallet.NestedWitnessPubKey {
+			addressType == lnwallet.NestedWitnessPubKey ||
+			addressType == lnwallet.TaprootPubkey {
 
 			txid, err := chainhash.NewHashFromStr(output.TxID)
 			if err != nil {
@@ -8,6 +8,7 @@ import (
 	"github.com/brronsuite/broln/lnwallet"
 	"github.com/brronsuite/broln/lnwallet/chainfee"
 	"github.com/brronsuite/brond/bronec"
+	"github.com/brronsuite/brond/bronec/schnorr"
 	"github.com/brronsuite/brond/txscript"
 	"github.com/brronsuite/bronutil"
 	"github.com/brronsuite/bronutil/psbt"
@@ -57,8 +58,8 @@ func (b *bronwallet) FundPsbt(packet *psbt.Packet, minConfs int32,
 	)
 	switch accountName {
 	// If the default/imported account name was specified, we'll provide a
-	// nil key scope to FundPsbt, allowing it to select inputs from both key
-	// scopes (NP2WKH, P2WKH).
+	// nil key scope to FundPsbt, allowing it to select inputs from all key
+	// scopes (NP2WKH, P2WKH, P2TR).
 	case lnwallet.DefaultAccountName:
 		accountNum = defaultAccount
 
@@ -107,7 +108,8 @@ func (b *bronwallet) SignPsbt(packet *psbt.Packet) error {
 	// there are inputs that we don't know how to sign, we won't return any
 	// error. So it's possible we're not the final signer.
 	tx := packet.UnsignedTx
-	sigHashes := txscript.NewTxSigHashes(tx)
+	prevOutFetcher := PsbtPrevOutputFetcher(packet)
+	sigHashes := txscript.NewTxSigHashes(tx, prevOutFetcher)
 	for idx := range tx.TxIn {
 		in := packet.Inputs[idx]
 
@@ -124,6 +126,17 @@ func (b *bronwallet) SignPsbt(packet *psbt.Packet) error {
 			continue
 		}
 
+		// Taproot inputs are signed using the key spend path and carry
+		// their derivation info in a separate field.
+		if txscript.IsPayToTaproot(in.WitnessUtxo.PkScript) {
+			err := b.signTaprootKeySpendPsbt(packet, idx, sigHashes)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
 		// Skip this input if there is no BIP32 derivation info
 		// available.
 		if len(in.Bip32Derivation) == 0 {
@@ -201,6 +214,89 @@ func (b *bronwallet) SignPsbt(packet *psbt.Packet) error {
 	return nil
 }
 
+// signTaprootKeySpendPsbt signs the BIP-86 p2tr input with the given index
+// using the key spend path, if the input belongs to the wallet. The signature
+// is added to the taproot key spend signature field of the input.
+func (b *bronwallet) signTaprootKeySpendPsbt(packet *psbt.Packet, idx int,
+	sigHashes *txscript.TxSigHashes) error {
+
+	in := packet.Inputs[idx]
+
+	// Skip this input if it's already signed or there is no BIP32
+	// derivation info available.
+	if len(in.TaprootKeySpendSig) > 0 ||
+		len(in.TaprootBip32Derivation) == 0 {
+
+		return nil
+	}
+
+	// We only support BIP-86 key spends, so the key must be derived
+	// from one of the wallet's key scopes.
+	derivationInfo := in.TaprootBip32Derivation[0]
+	privKey, err := b.deriveKeyByBIP32Path(derivationInfo.Bip32Path)
+	if err != nil {
+		log.Warnf("SignPsbt: Skipping input %d, error deriving "+
+			"signing key: %v", idx, err)
+		return nil
+	}
+
+	// We need to make sure we actually derived the key that was expected
+	// to be derived.
+	xOnlyPubKey := schnorr.SerializePubKey(privKey.PubKey())
+	if !bytes.Equal(derivationInfo.XOnlyPubKey, xOnlyPubKey) {
+		log.Warnf("SignPsbt: Skipping input %d, derived public key "+
+			"%x does not match taproot bip32 derivation info "+
+			"public key %x", idx, xOnlyPubKey,
+			derivationInfo.XOnlyPubKey)
+		return nil
+	}
+
+	// A missing sighash type means the default sighash type for taproot
+	// inputs, which results in a 64 byte signature without the sighash
+	// flag. The private key is tweaked with an empty script root as
+	// defined in BIP-86 by the signing function.
+	sig, err := txscript.RawTxInTaprootSignature(
+		packet.UnsignedTx, sigHashes, idx, in.WitnessUtxo.Value,
+		in.WitnessUtxo.PkScript, nil, in.SighashType, privKey,
+	)
+	if err != nil {
+		return fmt.Errorf("error signing input %d: %v", idx, err)
+	}
+	packet.Inputs[idx].TaprootKeySpendSig = sig
+
+	return nil
+}
+
+// PsbtPrevOutputFetcher returns a previous output fetcher built from the UTXO
+// information in a PSBT packet. Inputs without UTXO information are unknown to
+// the returned fetcher.
+func PsbtPrevOutputFetcher(packet *psbt.Packet) *txscript.MultiPrevOutFetcher {
+	fetcher := txscript.NewMultiPrevOutFetcher(nil)
+	for idx, txIn := range packet.UnsignedTx.TxIn {
+		in := packet.Inputs[idx]
+
+		switch {
+		case in.WitnessUtxo != nil:
+			fetcher.AddPrevOut(
+				txIn.PreviousOutPoint, in.WitnessUtxo,
+			)
+
+		case in.NonWitnessUtxo != nil:
+			prevIndex := txIn.PreviousOutPoint.Index
+			if int(prevIndex) >= len(in.NonWitnessUtxo.TxOut) {
+				continue
+			}
+
+			fetcher.AddPrevOut(
+				txIn.PreviousOutPoint,
+				in.NonWitnessUtxo.TxOut[prevIndex],
+			)
+		}
+	}
+
+	return fetcher
+}
+
 // prepareScripts returns the appropriate witness and/or legacy scripts,
 // depending on the type of input that should be signed.
 func prepareScripts(in psbt.PInput) ([]byte, []byte, error) {
@@ -274,8 +370,8 @@ func (b *bronwallet) FinalizePsbt(packet *psbt.Packet, accountName string) error
 	)
 	switch accountName {
 	// If the default/imported account name was specified, we'll provide a
-	// nil key scope to FundPsbt, allowing it to sign inputs from both key
-	// scopes (NP2WKH, P2WKH).
+	// nil key scope to FundPsbt, allowing it to sign inputs from all key
+	// scopes (NP2WKH, P2WKH, P2TR).
 	case lnwallet.DefaultAccountName:
 		accountNum = defaultAccount
 
@@ -10,6 +10,7 @@ import (
 	"github.com/brronsuite/broln/input"
 	"github.com/brronsuite/broln/keychain"
 	"github.com/brronsuite/brond/bronec"
+	"github.com/brronsuite/brond/bronec/schnorr"
 	"github.com/brronsuite/brond/chaincfg"
 	"github.com/brronsuite/brond/txscript"
 	"github.com/brronsuite/brond/wire"
@@ -56,6 +57,7 @@ const (
 	nestedP2WKH                 testInputType = 2
 	singleKeyP2WSH              testInputType = 3
 	singleKeyDoubleTweakedP2WSH testInputType = 4
+	taprootP2TR                 testInputType = 5
 )
 
 func (i testInputType) keyPath() []uint32 {
@@ -84,6 +86,14 @@ func (i testInputType) keyPath() []uint32 {
 			0, 9,
 		}
 
+	case taprootP2TR:
+		return []uint32{
+			hardenedKey(waddrmgr.KeyScopeBIP0086.Purpose),
+			hardenedKey(0),
+			hardenedKey(0),
+			0, 0,
+		}
+
 	default:
 		return []uint32{
 			hardenedKey(waddrmgr.KeyScopeBIP0084.Purpose),
@@ -164,6 +174,13 @@ func (i testInputType) output(t *testing.T,
 		addr, err = bronutil.NewAddressWitnessScriptHash(h[:], netParams)
 		require.NoError(t, err)
 
+	case taprootP2TR:
+		trKey := txscript.ComputeTaprootKeyNoScript(privKey.PubKey())
+		addr, err = bronutil.NewAddressTaproot(
+			schnorr.SerializePubKey(trKey), netParams,
+		)
+		require.NoError(t, err)
+
 	default:
 		t.Fatalf("invalid input type")
 	}
@@ -202,11 +219,30 @@ func (i testInputType) decorateInput(t *testing.T, privKey *bronec.PrivateKey,
 			Key:   PsbtKeyTypeInputSignatureTweakDouble,
 			Value: testCommitSecret.Serialize(),
 		}}
+
+	case taprootP2TR:
+		in.TaprootBip32Derivation = []*psbt.TaprootBip32Derivation{{
+			XOnlyPubKey: schnorr.SerializePubKey(privKey.PubKey()),
+			Bip32Path:   in.Bip32Derivation[0].Bip32Path,
+		}}
+		in.Bip32Derivation = nil
 	}
 }
 
 func (i testInputType) beforeFinalize(t *testing.T, packet *psbt.Packet) {
 	in := &packet.Inputs[0]
+
+	// A taproot key spend only has the signature on the witness stack.
+	if i == taprootP2TR {
+		var err error
+		in.FinalScriptWitness, err = serializeTxWitness(
+			wire.TxWitness{in.TaprootKeySpendSig},
+		)
+		require.NoError(t, err)
+
+		return
+	}
+
 	sigBytes := in.PartialSigs[0].Signature
 	pubKeyBytes := in.PartialSigs[0].PubKey
 
@@ -271,6 +307,9 @@ func TestSignPsbt(t *testing.T) {
 	}, {
 		name:      "single key double tweaked P2WSH",
 		inputType: singleKeyDoubleTweakedP2WSH,
+	}, {
+		name:      "taproot key spend P2TR",
+		inputType: taprootP2TR,
 	}}
 
 	for _, tc := range testCases {
@@ -326,10 +365,13 @@ func TestSignPsbt(t *testing.T) {
 		finalTx, err := psbt.Extract(packet)
 		require.NoError(t, err)
 
+		prevOutFetcher := txscript.NewCannedPrevOutputFetcher(
+			refTx.TxOut[0].PkScript, refTx.TxOut[0].Value,
+		)
 		vm, err := txscript.NewEngine(
 			refTx.TxOut[0].PkScript, finalTx, 0,
 			txscript.StandardVerifyFlags, nil, nil,
-			refTx.TxOut[0].Value,
+			refTx.TxOut[0].Value, prevOutFetcher,
 		)
 		require.NoError(t, err)
 		require.NoError(t, vm.Execute())
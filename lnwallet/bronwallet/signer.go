@@ -7,6 +7,7 @@ import (
 	"github.com/brronsuite/broln/keychain"
 	"github.com/brronsuite/broln/lnwallet"
 	"github.com/brronsuite/brond/bronec"
+	"github.com/brronsuite/brond/bronec/schnorr"
 	"github.com/brronsuite/brond/chaincfg/chainhash"
 	"github.com/brronsuite/brond/txscript"
 	"github.com/brronsuite/brond/wire"
@@ -38,6 +39,8 @@ func (b *bronwallet) FetchInputInfo(prevOut *wire.OutPoint) (*lnwallet.Utxo, err
 		addressType = lnwallet.WitnessPubKey
 	case txscript.IsPayToScriptHash(txOut.PkScript):
 		addressType = lnwallet.NestedWitnessPubKey
+	case txscript.IsPayToTaproot(txOut.PkScript):
+		addressType = lnwallet.TaprootPubkey
 	}
 
 	return &lnwallet.Utxo{
@@ -89,7 +92,7 @@ func (b *bronwallet) deriveKeyByBIP32Path(path []uint32) (*bronec.PrivateKey,
 	// Make sure we get a full path with exactly 5 elements. A path is
 	// either custom purpose one with 4 dynamic and one static elements:
 	//    m/1017'/coinType'/keyFamily'/0/index
-	// Or a default BIP49/89 one with 5 elements:
+	// Or a default BIP49/84/86 one with 5 elements:
 	//    m/purpose'/coinType'/account'/change/index
 	const expectedDerivationPathDepth = 5
 	if len(path) != expectedDerivationPathDepth {
@@ -129,26 +132,28 @@ func (b *bronwallet) deriveKeyByBIP32Path(path []uint32) (*bronec.PrivateKey,
 	// Is it a standard, BIP defined purpose that the wallet understands?
 	case waddrmgr.KeyScopeBIP0044.Purpose,
 		waddrmgr.KeyScopeBIP0049Plus.Purpose,
-		waddrmgr.KeyScopeBIP0084.Purpose:
+		waddrmgr.KeyScopeBIP0084.Purpose,
+		waddrmgr.KeyScopeBIP0086.Purpose:
 
 		// We're going to continue below the switch statement to avoid
 		// unnecessary indentation for this default case.
 
 	// Currently, there is no way to import any other key scopes than the
-	// one custom purpose or three standard ones into broln's wallet. So we
+	// one custom purpose or four standard ones into broln's wallet. So we
 	// shouldn't accept any other scopes to sign for.
 	default:
 		return nil, fmt.Errorf("invalid BIP32 derivation path, "+
 			"unknown purpose %d", purpose)
 	}
 
-	// Okay, we made sure it's a BIP49/84 key, so we need to derive it now.
+	// Okay, we made sure it's a BIP49/84/86 key, so we need to derive it
+	// now.
 	// Interestingly, the bronwallet never actually uses a coin type other
 	// than 0 for those keys, so we need to make sure this behavior is
 	// replicated here.
 	if coinType != 0 {
 		return nil, fmt.Errorf("invalid BIP32 derivation path, coin " +
-			"type must be 0 for BIP49/84 bronwallet keys")
+			"type must be 0 for BIP49/84/86 bronwallet keys")
 	}
 
 	// We only expect to be asked to sign with key scopes that we know
@@ -355,6 +360,12 @@ func (b *bronwallet) SignOutputRaw(tx *wire.MsgTx,
 		return nil, err
 	}
 
+	// In case of a taproot output any signature is always a Schnorr
+	// signature, based on the new tapscript sighash algorithm.
+	if txscript.IsPayToTaproot(signDesc.Output.PkScript) {
+		return signTaprootOutputRaw(tx, signDesc, privKey)
+	}
+
 	// TODO(roasbeef): generate sighash midstate if not present?
 
 	amt := signDesc.Output.Value
@@ -370,15 +381,81 @@ func (b *bronwallet) SignOutputRaw(tx *wire.MsgTx,
 	return bronec.ParseDERSignature(sig[:len(sig)-1], bronec.S256())
 }
 
+// signTaprootOutputRaw creates a Schnorr signature for a taproot input of the
+// passed transaction, using either the key spend or the script spend path as
+// defined by the sign method of the descriptor.
+func signTaprootOutputRaw(tx *wire.MsgTx, signDesc *input.SignDescriptor,
+	privKey *bronec.PrivateKey) (input.Signature, error) {
+
+	// The BIP-341 sighash commits to all previous outputs, so we can't
+	// sign without knowing them.
+	if signDesc.PrevOutputFetcher == nil {
+		return nil, fmt.Errorf("previous output fetcher required to " +
+			"sign taproot input")
+	}
+	sigHashes := txscript.NewTxSigHashes(tx, signDesc.PrevOutputFetcher)
+
+	// Are we spending a script path or the key path? The API is slightly
+	// different, so we need to account for that to get the raw signature.
+	var (
+		rawSig []byte
+		err    error
+	)
+	switch signDesc.SignMethod {
+	case input.TaprootKeySpendBIP0086SignMethod,
+		input.TaprootKeySpendSignMethod:
+
+		// This function tweaks the private key using the tap root key
+		// supplied as the tweak. For BIP-0086 the tweak is nil which
+		// results in a commitment to the internal key only.
+		var tapTweak []byte
+		if signDesc.SignMethod == input.TaprootKeySpendSignMethod {
+			tapTweak = signDesc.TapTweak
+		}
+		rawSig, err = txscript.RawTxInTaprootSignature(
+			tx, sigHashes, signDesc.InputIndex,
+			signDesc.Output.Value, signDesc.Output.PkScript,
+			tapTweak, signDesc.HashType, privKey,
+		)
+
+	case input.TaprootScriptSpendSignMethod:
+		leaf := txscript.NewBaseTapLeaf(signDesc.WitnessScript)
+		rawSig, err = txscript.RawTxInTapscriptSignature(
+			tx, sigHashes, signDesc.InputIndex,
+			signDesc.Output.Value, signDesc.Output.PkScript, leaf,
+			signDesc.HashType, privKey,
+		)
+
+	default:
+		return nil, fmt.Errorf("invalid sign method %v for taproot "+
+			"output", signDesc.SignMethod)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// The signature returned above might have a sighash flag attached if
+	// a non-default type was used. We'll slice this off if it exists to
+	// ensure we can properly parse the raw signature.
+	return schnorr.ParseSignature(rawSig[:schnorr.SignatureSize])
+}
+
 // ComputeInputScript generates a complete InputScript for the passed
 // transaction with the signature as defined within the passed SignDescriptor.
 // This method is capable of generating the proper input script for both
-// regular p2wkh output and p2wkh outputs nested within a regular p2sh output.
+// regular p2wkh output and p2wkh outputs nested within a regular p2sh output as
+// well as p2tr outputs that are spent using the key spend path.
 //
 // This is a part of the WalletController interface.
 func (b *bronwallet) ComputeInputScript(tx *wire.MsgTx,
 	signDesc *input.SignDescriptor) (*input.Script, error) {
 
+	// Taproot outputs of the wallet are spent using the key spend path,
+	// which requires a schnorr signature over the BIP-341 sighash.
+	if txscript.IsPayToTaproot(signDesc.Output.PkScript) {
+		return b.computeTaprootKeySpendScript(tx, signDesc)
+	}
+
 	// If a tweak (single or double) is specified, then we'll need to use
 	// this tweak to derive the final private key to be used for signing
 	// this output.
@@ -401,6 +478,51 @@ func (b *bronwallet) ComputeInputScript(tx *wire.MsgTx,
 	}, nil
 }
 
+// computeTaprootKeySpendScript generates the witness for spending a BIP-86 p2tr
+// output of the wallet using the key spend path.
+func (b *bronwallet) computeTaprootKeySpendScript(tx *wire.MsgTx,
+	signDesc *input.SignDescriptor) (*input.Script, error) {
+
+	// The BIP-341 sighash commits to all previous outputs, so we can't
+	// sign without knowing them.
+	if signDesc.PrevOutputFetcher == nil {
+		return nil, fmt.Errorf("previous output fetcher required to " +
+			"sign taproot input")
+	}
+
+	managedAddr, _, _, err := b.wallet.ScriptForOutput(signDesc.Output)
+	if err != nil {
+		return nil, err
+	}
+	privKey, err := managedAddr.PrivKey()
+	if err != nil {
+		return nil, err
+	}
+
+	privKey, err = maybeTweakPrivKey(signDesc, privKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// The sighash midstate in the sign descriptor might have been created
+	// without the taproot specific values, so we compute it from the
+	// previous outputs to make sure they are present. The private key is
+	// tweaked with an empty script root as defined in BIP-86 by the
+	// signing function.
+	sigHashes := txscript.NewTxSigHashes(tx, signDesc.PrevOutputFetcher)
+	sig, err := txscript.RawTxInTaprootSignature(
+		tx, sigHashes, signDesc.InputIndex, signDesc.Output.Value,
+		signDesc.Output.PkScript, nil, signDesc.HashType, privKey,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &input.Script{
+		Witness: wire.TxWitness{sig},
+	}, nil
+}
+
 // A compile time check to ensure that bronwallet implements the Signer
 // interface.
 var _ input.Signer = (*bronwallet)(nil)
@@ -99,7 +99,7 @@ var (
 		path: []uint32{
 			hardenedKey(84), hardenedKey(1), hardenedKey(0), 0, 0,
 		},
-		err: "coin type must be 0 for BIP49/84 bronwallet keys",
+		err: "coin type must be 0 for BIP49/84/86 bronwallet keys",
 	}, {
 		name: "m/1017'/0'/0'/0/0",
 		path: []uint32{
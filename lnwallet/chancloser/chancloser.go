@@ -66,6 +66,15 @@ const (
 	// causes a shift into the closeFinished state.
 	closeFeeNegotiation
 
+	// closeRbfNegotiation replaces closeFeeNegotiation if both parties
+	// support RBF cooperative closes. In this state, either party can
+	// propose a closing transaction paid from its own output with a
+	// ClosingComplete message, which the other party completes with a
+	// ClosingSig message. Once a closing transaction is completed, the
+	// state machine transitions into closeFinished, where closing
+	// transactions can still be replaced by ones paying a higher fee.
+	closeRbfNegotiation
+
 	// closeFinished is the final state of the state machine. In this state, a
 	// side has accepted a fee offer and has broadcast the valid closing
 	// transaction to the network. During this phase, the closing transaction
@@ -92,6 +101,16 @@ type ChanCloseCfg struct {
 	// Quit is a channel that should be sent upon in the occasion the state
 	// machine should cease all progress and shutdown.
 	Quit chan struct{}
+
+	// RbfCoopClose signals that both parties support RBF cooperative
+	// closes, in which case the closing transaction is negotiated with
+	// ClosingComplete and ClosingSig messages instead of ClosingSigned.
+	RbfCoopClose bool
+
+	// BestHeight returns the height of the current best block. It is used
+	// to reject closing transactions proposed by the remote party that
+	// can't be broadcast yet.
+	BestHeight func() (uint32, error)
 }
 
 // ChanCloser is a state machine that handles the cooperative channel closure
@@ -153,6 +172,15 @@ type ChanCloser struct {
 
 	// locallyInitiated is true if we initiated the channel close.
 	locallyInitiated bool
+
+	// idealFeeRate is the fee rate of the closing transaction we propose
+	// once the shutdown is complete in an RBF cooperative close.
+	idealFeeRate chainfee.SatPerKWeight
+
+	// localProposal is the last closing transaction we proposed in an RBF
+	// cooperative close, which is completed once the remote party's
+	// ClosingSig for it is received.
+	localProposal *lnwire.ClosingComplete
 }
 
 // NewChanCloser creates a new instance of the channel closure given the passed
@@ -196,6 +224,7 @@ func NewChanCloser(cfg ChanCloseCfg, deliveryScript []byte,
 		localDeliveryScript: deliveryScript,
 		priorFeeOffers:      make(map[bronutil.Amount]*lnwire.ClosingSigned),
 		locallyInitiated:    locallyInitiated,
+		idealFeeRate:        idealFeePerKw,
 	}
 }
 
@@ -225,6 +254,17 @@ func (c *ChanCloser) initChanShutdown() (*lnwire.Shutdown, error) {
 		return nil, err
 	}
 
+	// In an RBF cooperative close, we also persist our delivery script,
+	// so the close can be resumed to bump its fee after reconnecting.
+	if c.cfg.RbfCoopClose {
+		err := c.cfg.Channel.State().PutCoopCloseDeliveryScript(
+			c.localDeliveryScript,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	chancloserLog.Infof("ChannelPoint(%v): sending shutdown message",
 		c.chanPoint)
 
@@ -292,6 +332,13 @@ func (c *ChanCloser) NegotiationHeight() uint32 {
 	return c.negotiationHeight
 }
 
+// RbfCoopClose returns true if the channel is closed using the RBF
+// cooperative close protocol, in which case the closing transaction may be
+// replaced by either party.
+func (c *ChanCloser) RbfCoopClose() bool {
+	return c.cfg.RbfCoopClose
+}
+
 // maybeMatchScript attempts to match the script provided in our peer's
 // shutdown message with the upfront shutdown script we have on record. If no
 // upfront shutdown script was set, we do not need to enforce option upfront
@@ -329,10 +376,20 @@ func maybeMatchScript(disconnect func() error, upfrontScript,
 // This method will update the state accordingly and return two primary values:
 // the next set of messages to be sent, and a bool indicating if the fee
 // negotiation process has completed. If the second value is true, then this
-// means the ChanCloser can be garbage collected.
+// means the ChanCloser can be garbage collected. In an RBF cooperative close,
+// the second value is only true once the first closing transaction was
+// completed, and the ChanCloser continues to process replacements of it.
 func (c *ChanCloser) ProcessCloseMsg(msg lnwire.Message) ([]lnwire.Message,
 	bool, error) {
 
+	// Once the shutdown is complete in an RBF cooperative close, closing
+	// transactions can be proposed and replaced by either party.
+	if c.cfg.RbfCoopClose &&
+		(c.state == closeRbfNegotiation || c.state == closeFinished) {
+
+		return c.processRbfCloseMsg(msg)
+	}
+
 	switch c.state {
 
 	// If we're in the close idle state, and we're receiving a channel closure
@@ -395,6 +452,14 @@ func (c *ChanCloser) ProcessCloseMsg(msg lnwire.Message) ([]lnwire.Message,
 		msgsToSend := make([]lnwire.Message, 0, 2)
 		msgsToSend = append(msgsToSend, localShutdown)
 
+		// In an RBF cooperative close, the initiator of the shutdown
+		// proposes the first closing transaction, which it pays for.
+		if c.cfg.RbfCoopClose {
+			c.state = closeRbfNegotiation
+
+			return msgsToSend, false, nil
+		}
+
 		// After the other party receives this message, we'll actually start
 		// the final stage of the closure process: fee negotiation. So we'll
 		// update our internal state to reflect this, so we can handle the next
@@ -439,6 +504,38 @@ func (c *ChanCloser) ProcessCloseMsg(msg lnwire.Message) ([]lnwire.Message,
 		// record their preferred delivery closing script.
 		c.remoteDeliveryScript = shutdownMsg.Address
 
+		// If we resumed an RBF cooperative close whose closing
+		// transaction was already completed, either party may now
+		// replace it.
+		if c.cfg.RbfCoopClose && c.closingTx != nil {
+			c.state = closeFinished
+
+			chancloserLog.Infof("ChannelPoint(%v): RBF close "+
+				"resumed", c.chanPoint)
+
+			return nil, false, nil
+		}
+
+		// In an RBF cooperative close, we propose the first closing
+		// transaction as the initiator of the shutdown, paying its
+		// fee from our own output.
+		if c.cfg.RbfCoopClose {
+			c.state = closeRbfNegotiation
+
+			chancloserLog.Infof("ChannelPoint(%v): shutdown "+
+				"response received, proposing RBF closing "+
+				"transaction", c.chanPoint)
+
+			closingComplete, err := c.proposeClosingComplete(
+				c.idealFeeRate,
+			)
+			if err != nil {
+				return nil, false, err
+			}
+
+			return []lnwire.Message{closingComplete}, false, nil
+		}
+
 		// At this point, we can now start the fee negotiation state, by
 		// constructing and sending our initial signature for what we think the
 		// closing transaction should look like.
@@ -2,6 +2,7 @@ package chancloser
 
 import (
 	"crypto/rand"
+	"errors"
 	"testing"
 
 	"github.com/brronsuite/broln/lnwire"
@@ -74,3 +75,29 @@ func TestMaybeMatchScript(t *testing.T) {
 		})
 	}
 }
+
+// TestRbfCloseLockTime tests that a closing transaction proposed by the remote
+// party is rejected if its lock time is above the current height.
+func TestRbfCloseLockTime(t *testing.T) {
+	t.Parallel()
+
+	const bestHeight = 100
+
+	closer := &ChanCloser{
+		cfg: ChanCloseCfg{
+			RbfCoopClose: true,
+			BestHeight: func() (uint32, error) {
+				return bestHeight, nil
+			},
+		},
+		state: closeRbfNegotiation,
+	}
+
+	_, _, err := closer.processRbfCloseMsg(&lnwire.ClosingComplete{
+		FeeBroneess: 1000,
+		LockTime:    bestHeight + 1,
+	})
+	if !errors.Is(err, ErrCloseLockTimeTooHigh) {
+		t.Fatalf("expected ErrCloseLockTimeTooHigh, got: %v", err)
+	}
+}
@@ -0,0 +1,331 @@
+package chancloser
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/brronsuite/broln/labels"
+	"github.com/brronsuite/broln/lnwallet"
+	"github.com/brronsuite/broln/lnwallet/chainfee"
+	"github.com/brronsuite/broln/lnwire"
+	"github.com/brronsuite/brond/wire"
+	"github.com/brronsuite/bronutil"
+	"github.com/davecgh/go-spew/spew"
+)
+
+var (
+	// ErrRbfCloseNotSupported is returned when a fee bump is requested
+	// for a cooperative close that isn't negotiated as an RBF cooperative
+	// close.
+	ErrRbfCloseNotSupported = fmt.Errorf("cooperative close doesn't " +
+		"support fee bumps")
+
+	// ErrCloseNotNegotiating is returned when a fee bump is requested
+	// before both parties sent their shutdown message.
+	ErrCloseNotNegotiating = fmt.Errorf("shutdown not yet completed")
+
+	// ErrCloseFeeTooLow is returned when a fee bump is requested with a
+	// fee that doesn't exceed the fee of the current closing transaction.
+	ErrCloseFeeTooLow = fmt.Errorf("close fee must exceed the fee of " +
+		"the current closing transaction")
+
+	// ErrCloseLockTimeTooHigh is returned when the remote party proposes a
+	// closing transaction with a lock time above the current height,
+	// which can't be broadcast yet.
+	ErrCloseLockTimeTooHigh = fmt.Errorf("close lock time above " +
+		"current height")
+)
+
+// ResumeRbfClose resumes an RBF cooperative close whose closing transaction
+// was completed before reconnecting to the remote party. The returned
+// shutdown message needs to be retransmitted to the remote party, after which
+// either party can bump the fee of the passed closing transaction.
+func (c *ChanCloser) ResumeRbfClose(closingTx *wire.MsgTx) (*lnwire.Shutdown,
+	error) {
+
+	if !c.cfg.RbfCoopClose {
+		return nil, ErrRbfCloseNotSupported
+	}
+	if c.state != closeIdle {
+		return nil, ErrChanAlreadyClosing
+	}
+
+	chancloserLog.Infof("ChannelPoint(%v): resuming RBF close with "+
+		"closing tx %v", c.chanPoint, closingTx.TxHash())
+
+	c.closingTx = closingTx
+	c.state = closeShutdownInitiated
+
+	return lnwire.NewShutdown(c.cid, c.localDeliveryScript), nil
+}
+
+// BumpFee proposes a closing transaction paying the given fee rate from our
+// own output, replacing the current closing transaction once the remote party
+// signed it. The returned ClosingComplete message needs to be sent to the
+// remote party, along with the txid of the proposed closing transaction.
+func (c *ChanCloser) BumpFee(feeRate chainfee.SatPerKWeight) (
+	*lnwire.ClosingComplete, *wire.MsgTx, error) {
+
+	if !c.cfg.RbfCoopClose {
+		return nil, nil, ErrRbfCloseNotSupported
+	}
+	if c.state != closeRbfNegotiation && c.state != closeFinished {
+		return nil, nil, ErrCloseNotNegotiating
+	}
+
+	// The new closing transaction must pay a higher fee than both the
+	// current closing transaction and our last proposal, as it would
+	// otherwise not be able to replace them.
+	fee := lnwallet.RbfCloseFee(
+		feeRate, c.localDeliveryScript, c.remoteDeliveryScript,
+	)
+	if c.closingTx != nil && fee <= c.closeTxFee(c.closingTx) {
+		return nil, nil, ErrCloseFeeTooLow
+	}
+	if c.localProposal != nil && fee <= c.localProposal.FeeBroneess {
+		return nil, nil, ErrCloseFeeTooLow
+	}
+
+	return c.proposeClosingCompleteFee(fee)
+}
+
+// closeTxFee returns the fee paid by the passed closing transaction.
+func (c *ChanCloser) closeTxFee(closeTx *wire.MsgTx) bronutil.Amount {
+	fee := c.cfg.Channel.State().Capacity
+	for _, txOut := range closeTx.TxOut {
+		fee -= bronutil.Amount(txOut.Value)
+	}
+
+	return fee
+}
+
+// proposeClosingComplete proposes a closing transaction paying the given fee
+// rate from our own output.
+func (c *ChanCloser) proposeClosingComplete(
+	feeRate chainfee.SatPerKWeight) (*lnwire.ClosingComplete, error) {
+
+	fee := lnwallet.RbfCloseFee(
+		feeRate, c.localDeliveryScript, c.remoteDeliveryScript,
+	)
+	closingComplete, _, err := c.proposeClosingCompleteFee(fee)
+
+	return closingComplete, err
+}
+
+// proposeClosingCompleteFee signs a closing transaction paying the given fee
+// from our own output, and returns the ClosingComplete message proposing it
+// to the remote party along with the unsigned closing transaction.
+func (c *ChanCloser) proposeClosingCompleteFee(fee bronutil.Amount) (
+	*lnwire.ClosingComplete, *wire.MsgTx, error) {
+
+	lockTime := c.negotiationHeight
+	rawSig, closeTx, err := c.cfg.Channel.CreateRbfCloseProposal(
+		fee, lockTime, c.localDeliveryScript, c.remoteDeliveryScript,
+		true,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sig, err := lnwire.NewSigFromSignature(rawSig)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	chancloserLog.Infof("ChannelPoint(%v): proposing RBF close tx %v "+
+		"paying fee of %v", c.chanPoint, closeTx.TxHash(), fee)
+
+	c.localProposal = &lnwire.ClosingComplete{
+		ChannelID:   c.cid,
+		FeeBroneess: fee,
+		LockTime:    lockTime,
+		Signature:   sig,
+	}
+
+	return c.localProposal, closeTx, nil
+}
+
+// processRbfCloseMsg processes a ClosingComplete or ClosingSig message once
+// the shutdown of an RBF cooperative close is complete. The returned bool is
+// true if the message completed the first closing transaction of the channel.
+func (c *ChanCloser) processRbfCloseMsg(msg lnwire.Message) ([]lnwire.Message,
+	bool, error) {
+
+	switch msg := msg.(type) {
+
+	// The remote party proposed a closing transaction paying the fee from
+	// its own output, which we complete with our signature.
+	case *lnwire.ClosingComplete:
+		chancloserLog.Infof("ChannelPoint(%v): remote party proposed "+
+			"RBF close paying fee of %v", c.chanPoint,
+			msg.FeeBroneess)
+
+		// We don't sign a closing transaction we can't broadcast right
+		// away, as the remote party could otherwise delay the close by
+		// choosing a lock time in the future.
+		bestHeight, err := c.cfg.BestHeight()
+		if err != nil {
+			return nil, false, err
+		}
+		if msg.LockTime > bestHeight {
+			return nil, false, fmt.Errorf("%w: lock_time=%v, "+
+				"height=%v", ErrCloseLockTimeTooHigh,
+				msg.LockTime, bestHeight)
+		}
+
+		rawSig, _, err := c.cfg.Channel.CreateRbfCloseProposal(
+			msg.FeeBroneess, msg.LockTime, c.localDeliveryScript,
+			c.remoteDeliveryScript, false,
+		)
+		if err != nil {
+			return nil, false, err
+		}
+
+		remoteSig, err := msg.Signature.ToSignature()
+		if err != nil {
+			return nil, false, err
+		}
+
+		closeTx, err := c.cfg.Channel.CompleteRbfCooperativeClose(
+			rawSig, remoteSig, msg.FeeBroneess, msg.LockTime,
+			c.localDeliveryScript, c.remoteDeliveryScript, false,
+		)
+		if err != nil {
+			return nil, false, err
+		}
+
+		sig, err := lnwire.NewSigFromSignature(rawSig)
+		if err != nil {
+			return nil, false, err
+		}
+
+		closingSig := &lnwire.ClosingSig{
+			ChannelID:   c.cid,
+			FeeBroneess: msg.FeeBroneess,
+			LockTime:    msg.LockTime,
+			Signature:   sig,
+		}
+
+		first, err := c.publishRbfCloseTx(closeTx)
+		if err != nil {
+			return nil, false, err
+		}
+
+		return []lnwire.Message{closingSig}, first, nil
+
+	// The remote party signed a closing transaction we proposed.
+	case *lnwire.ClosingSig:
+		proposal := c.localProposal
+
+		// We only complete our latest proposal. A signature for a
+		// prior proposal is ignored, as the remote party's signature
+		// for our latest proposal will follow.
+		if proposal == nil || msg.FeeBroneess != proposal.FeeBroneess ||
+			msg.LockTime != proposal.LockTime {
+
+			chancloserLog.Debugf("ChannelPoint(%v): ignoring "+
+				"ClosingSig for stale proposal with fee %v",
+				c.chanPoint, msg.FeeBroneess)
+
+			return nil, false, nil
+		}
+
+		localSig, err := proposal.Signature.ToSignature()
+		if err != nil {
+			return nil, false, err
+		}
+		remoteSig, err := msg.Signature.ToSignature()
+		if err != nil {
+			return nil, false, err
+		}
+
+		closeTx, err := c.cfg.Channel.CompleteRbfCooperativeClose(
+			localSig, remoteSig, proposal.FeeBroneess,
+			proposal.LockTime, c.localDeliveryScript,
+			c.remoteDeliveryScript, true,
+		)
+		if err != nil {
+			return nil, false, err
+		}
+
+		c.localProposal = nil
+
+		first, err := c.publishRbfCloseTx(closeTx)
+		if err != nil {
+			return nil, false, err
+		}
+
+		return nil, first, nil
+
+	// The remote party may retransmit its shutdown message after
+	// reconnecting, which doesn't change the close.
+	case *lnwire.Shutdown:
+		if !bytes.Equal(msg.Address, c.remoteDeliveryScript) {
+			return nil, false, fmt.Errorf("remote party changed " +
+				"its delivery script")
+		}
+
+		return nil, false, nil
+
+	default:
+		return nil, false, fmt.Errorf("expected lnwire.ClosingComplete "+
+			"or lnwire.ClosingSig, instead have %v", spew.Sdump(msg))
+	}
+}
+
+// publishRbfCloseTx persists and broadcasts a completed RBF closing
+// transaction. It returns true if this is the first closing transaction
+// completed for the channel. A replacement that fails to broadcast is not
+// treated as an error, as the prior closing transaction remains valid.
+func (c *ChanCloser) publishRbfCloseTx(closeTx *wire.MsgTx) (bool, error) {
+	first := c.state != closeFinished
+
+	chanID := c.cfg.Channel.ShortChanID()
+	closeLabel := labels.MakeLabel(labels.LabelTypeChannelClose, &chanID)
+
+	chancloserLog.Infof("Broadcasting RBF cooperative close tx: %v",
+		newLogClosure(func() string {
+			return spew.Sdump(closeTx)
+		}),
+	)
+
+	// As with the regular cooperative close, the first closing
+	// transaction is persisted before publishing it, such that it can be
+	// republished if something goes wrong.
+	if first {
+		err := c.cfg.Channel.MarkCoopBroadcasted(
+			closeTx, c.locallyInitiated,
+		)
+		if err != nil {
+			return false, err
+		}
+
+		if err := c.cfg.BroadcastTx(closeTx, closeLabel); err != nil {
+			return false, err
+		}
+
+		c.closingTx = closeTx
+		c.state = closeFinished
+
+		return true, nil
+	}
+
+	// A replacement is only persisted once it was accepted, as it may be
+	// rejected if it doesn't pay enough fees to replace the prior closing
+	// transaction.
+	if err := c.cfg.BroadcastTx(closeTx, closeLabel); err != nil {
+		chancloserLog.Warnf("ChannelPoint(%v): unable to broadcast "+
+			"replacement close tx %v: %v", c.chanPoint,
+			closeTx.TxHash(), err)
+
+		return false, nil
+	}
+
+	err := c.cfg.Channel.MarkCoopBroadcasted(closeTx, c.locallyInitiated)
+	if err != nil {
+		return false, err
+	}
+	c.closingTx = closeTx
+
+	return false, nil
+}
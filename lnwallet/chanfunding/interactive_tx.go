@@ -0,0 +1,766 @@
+package chanfunding
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/brronsuite/broln/input"
+	"github.com/brronsuite/broln/lnwallet/chainfee"
+	"github.com/brronsuite/brond/bronec"
+	"github.com/brronsuite/brond/txscript"
+	"github.com/brronsuite/brond/wire"
+	"github.com/brronsuite/bronutil"
+)
+
+const (
+	// MaxInteractiveTxInputs is the maximum number of inputs an
+	// interactively constructed transaction may have.
+	MaxInteractiveTxInputs = 252
+
+	// MaxInteractiveTxOutputs is the maximum number of outputs an
+	// interactively constructed transaction may have.
+	MaxInteractiveTxOutputs = 252
+
+	// maxInteractiveTxAdds is the maximum number of inputs and outputs
+	// the remote party may add during the construction, including the
+	// ones it removes again.
+	maxInteractiveTxAdds = 4096
+
+	// InteractiveTxSequence is the sequence of the inputs we add to an
+	// interactively constructed transaction. It signals replaceability,
+	// so that the transaction can be fee bumped later on.
+	InteractiveTxSequence = wire.MaxTxInSequenceNum - 2
+
+	// sharedInputSerialID is the serial ID of the shared input of a
+	// splice transaction. It's never sent over the wire, as both parties
+	// know the funding output spent by the splice, and is the lowest
+	// possible serial ID, so the shared input is always the first input.
+	sharedInputSerialID = 0
+)
+
+var (
+	// ErrInvalidSerialID is returned when the remote party adds an input
+	// or output with a serial ID of the wrong parity.
+	ErrInvalidSerialID = errors.New("serial id has the wrong parity")
+
+	// ErrDuplicateSerialID is returned when an input or output is added
+	// with a serial ID that is already in use.
+	ErrDuplicateSerialID = errors.New("duplicate serial id")
+
+	// ErrUnknownSerialID is returned when the remote party removes an
+	// input or output it didn't add.
+	ErrUnknownSerialID = errors.New("unknown serial id")
+
+	// ErrDuplicateInput is returned when an input spends an outpoint that
+	// is already spent by another input of the transaction.
+	ErrDuplicateInput = errors.New("outpoint is spent twice")
+
+	// ErrTooManyInputs is returned when the transaction would exceed the
+	// maximum number of inputs.
+	ErrTooManyInputs = errors.New("too many inputs")
+
+	// ErrTooManyOutputs is returned when the transaction would exceed the
+	// maximum number of outputs.
+	ErrTooManyOutputs = errors.New("too many outputs")
+
+	// ErrTooManyAdds is returned when the remote party added more inputs
+	// and outputs than allowed during a single construction.
+	ErrTooManyAdds = errors.New("too many inputs and outputs added")
+
+	// ErrMissingFundingOutput is returned when the constructed transaction
+	// doesn't contain the expected funding output exactly once.
+	ErrMissingFundingOutput = errors.New("funding output missing")
+
+	// ErrWitnessMismatch is returned when the number of witnesses sent by
+	// the remote party doesn't match the number of inputs it added.
+	ErrWitnessMismatch = errors.New("number of witnesses doesn't match " +
+		"number of remote inputs")
+
+	// ErrInsufficientContribution is returned when the inputs of the
+	// remote party don't cover its contribution and its share of the fee.
+	ErrInsufficientContribution = errors.New("remote inputs don't " +
+		"cover the remote contribution")
+
+	// ErrSharedInputExists is returned when a second shared input is
+	// added to the transaction.
+	ErrSharedInputExists = errors.New("shared input already added")
+)
+
+// InteractiveTxInput is an input of an interactively constructed
+// transaction.
+type InteractiveTxInput struct {
+	// SerialID identifies the input and determines its position within
+	// the final transaction.
+	SerialID uint64
+
+	// PrevTx is the transaction that contains the spent output.
+	PrevTx *wire.MsgTx
+
+	// PrevOutIndex is the index of the spent output within PrevTx.
+	PrevOutIndex uint32
+
+	// Sequence is the sequence number of the input.
+	Sequence uint32
+
+	// sharedOutPoint and sharedPrevOut are set instead of PrevTx if the
+	// input is the shared input of a splice, which spends the current
+	// funding output of the channel.
+	sharedOutPoint *wire.OutPoint
+	sharedPrevOut  *wire.TxOut
+}
+
+// OutPoint returns the outpoint spent by the input.
+func (i *InteractiveTxInput) OutPoint() wire.OutPoint {
+	if i.sharedOutPoint != nil {
+		return *i.sharedOutPoint
+	}
+
+	return wire.OutPoint{
+		Hash:  i.PrevTx.TxHash(),
+		Index: i.PrevOutIndex,
+	}
+}
+
+// PrevOut returns the output spent by the input.
+func (i *InteractiveTxInput) PrevOut() *wire.TxOut {
+	if i.sharedPrevOut != nil {
+		return i.sharedPrevOut
+	}
+
+	return i.PrevTx.TxOut[i.PrevOutIndex]
+}
+
+// InteractiveTxOutput is an output of an interactively constructed
+// transaction.
+type InteractiveTxOutput struct {
+	// SerialID identifies the output and determines its position within
+	// the final transaction.
+	SerialID uint64
+
+	// TxOut is the output itself.
+	TxOut *wire.TxOut
+}
+
+// InteractiveTx tracks the inputs and outputs both parties add to a
+// transaction during its interactive construction. The initiator of the
+// construction uses even serial IDs and the responder odd ones, so it's
+// always known which party owns an input or output. Each party pays the fee
+// for the inputs and outputs it adds, while the initiator additionally pays
+// for the common fields of the transaction. When splicing a channel, the
+// transaction also has a shared input spending the current funding output,
+// which is paid for by the initiator as well.
+//
+// NOTE: An InteractiveTx is not safe for concurrent use.
+type InteractiveTx struct {
+	// initiator is true if we initiated the construction.
+	initiator bool
+
+	// localInputs and remoteInputs are the inputs added by each party,
+	// keyed by their serial ID.
+	localInputs  map[uint64]*InteractiveTxInput
+	remoteInputs map[uint64]*InteractiveTxInput
+
+	// localOutputs and remoteOutputs are the outputs added by each party,
+	// keyed by their serial ID.
+	localOutputs  map[uint64]*InteractiveTxOutput
+	remoteOutputs map[uint64]*InteractiveTxOutput
+
+	// sharedInput is the input spending the current funding output of
+	// the channel if the transaction is a splice, and nil otherwise.
+	sharedInput *InteractiveTxInput
+
+	// numRemoteAdds is the number of inputs and outputs the remote party
+	// added so far, including the removed ones.
+	numRemoteAdds int
+}
+
+// NewInteractiveTx creates a new, empty interactively constructed
+// transaction.
+func NewInteractiveTx(initiator bool) *InteractiveTx {
+	return &InteractiveTx{
+		initiator:     initiator,
+		localInputs:   make(map[uint64]*InteractiveTxInput),
+		remoteInputs:  make(map[uint64]*InteractiveTxInput),
+		localOutputs:  make(map[uint64]*InteractiveTxOutput),
+		remoteOutputs: make(map[uint64]*InteractiveTxOutput),
+	}
+}
+
+// isLocalSerialID returns true if the serial ID has the parity of the serial
+// IDs we use.
+func (t *InteractiveTx) isLocalSerialID(serialID uint64) bool {
+	return (serialID%2 == 0) == t.initiator
+}
+
+// hasSerialID returns true if an input or output with the serial ID exists.
+func (t *InteractiveTx) hasSerialID(serialID uint64) bool {
+	if t.sharedInput != nil && serialID == sharedInputSerialID {
+		return true
+	}
+
+	_, ok := t.localInputs[serialID]
+	if ok {
+		return true
+	}
+	_, ok = t.remoteInputs[serialID]
+	if ok {
+		return true
+	}
+	_, ok = t.localOutputs[serialID]
+	if ok {
+		return true
+	}
+	_, ok = t.remoteOutputs[serialID]
+
+	return ok
+}
+
+// newSerialID returns a random, unused serial ID of our parity. The serial
+// IDs are random so that the position of an input or output doesn't reveal
+// the party that added it.
+func (t *InteractiveTx) newSerialID() (uint64, error) {
+	for {
+		var b [8]byte
+		if _, err := rand.Read(b[:]); err != nil {
+			return 0, err
+		}
+
+		serialID := binary.BigEndian.Uint64(b[:]) &^ 1
+		if !t.initiator {
+			serialID |= 1
+		}
+
+		if !t.hasSerialID(serialID) {
+			return serialID, nil
+		}
+	}
+}
+
+// hasOutPoint returns true if an input already spends the outpoint.
+func (t *InteractiveTx) hasOutPoint(op wire.OutPoint) bool {
+	if t.isSharedInput(op) {
+		return true
+	}
+
+	for _, in := range t.localInputs {
+		if in.OutPoint() == op {
+			return true
+		}
+	}
+	for _, in := range t.remoteInputs {
+		if in.OutPoint() == op {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkInput asserts that the input can be added to the transaction.
+func (t *InteractiveTx) checkInput(in *InteractiveTxInput) error {
+	if in.PrevTx == nil || int(in.PrevOutIndex) >= len(in.PrevTx.TxOut) {
+		return fmt.Errorf("invalid previous output index %v",
+			in.PrevOutIndex)
+	}
+
+	// We need to be able to estimate the weight of the input to
+	// attribute the fee to the party that added it, so only the segwit
+	// output types our wallet uses are allowed.
+	var weightEstimate input.TxWeightEstimator
+	err := addInputWeight(&weightEstimate, in.PrevOut().PkScript)
+	if err != nil {
+		return err
+	}
+
+	if t.hasOutPoint(in.OutPoint()) {
+		return ErrDuplicateInput
+	}
+
+	if len(t.inputs()) >= MaxInteractiveTxInputs {
+		return ErrTooManyInputs
+	}
+
+	return nil
+}
+
+// checkOutput asserts that an output can be added to the transaction.
+func (t *InteractiveTx) checkOutput(out *wire.TxOut) error {
+	if out.Value <= 0 {
+		return fmt.Errorf("invalid output value %v", out.Value)
+	}
+
+	numOutputs := len(t.localOutputs) + len(t.remoteOutputs)
+	if numOutputs >= MaxInteractiveTxOutputs {
+		return ErrTooManyOutputs
+	}
+
+	return nil
+}
+
+// AddLocalInput adds an input we contribute to the transaction and returns
+// it, so that it can be sent to the remote party.
+func (t *InteractiveTx) AddLocalInput(prevTx *wire.MsgTx,
+	prevOutIndex uint32) (*InteractiveTxInput, error) {
+
+	in := &InteractiveTxInput{
+		PrevTx:       prevTx,
+		PrevOutIndex: prevOutIndex,
+		Sequence:     InteractiveTxSequence,
+	}
+	if err := t.checkInput(in); err != nil {
+		return nil, err
+	}
+
+	serialID, err := t.newSerialID()
+	if err != nil {
+		return nil, err
+	}
+	in.SerialID = serialID
+	t.localInputs[serialID] = in
+
+	return in, nil
+}
+
+// AddSharedInput adds the shared input of a splice to the transaction, which
+// spends the current funding output of the channel. Both parties add the
+// shared input locally, as it isn't sent over the wire.
+func (t *InteractiveTx) AddSharedInput(op wire.OutPoint,
+	prevOut *wire.TxOut) error {
+
+	if t.sharedInput != nil {
+		return ErrSharedInputExists
+	}
+
+	if t.hasOutPoint(op) {
+		return ErrDuplicateInput
+	}
+
+	t.sharedInput = &InteractiveTxInput{
+		SerialID:       sharedInputSerialID,
+		Sequence:       InteractiveTxSequence,
+		sharedOutPoint: &op,
+		sharedPrevOut:  prevOut,
+	}
+
+	return nil
+}
+
+// AddLocalOutput adds an output we contribute to the transaction and returns
+// it, so that it can be sent to the remote party.
+func (t *InteractiveTx) AddLocalOutput(
+	txOut *wire.TxOut) (*InteractiveTxOutput, error) {
+
+	if err := t.checkOutput(txOut); err != nil {
+		return nil, err
+	}
+
+	serialID, err := t.newSerialID()
+	if err != nil {
+		return nil, err
+	}
+
+	out := &InteractiveTxOutput{
+		SerialID: serialID,
+		TxOut:    txOut,
+	}
+	t.localOutputs[serialID] = out
+
+	return out, nil
+}
+
+// checkRemoteAdd asserts that the remote party may add an input or output
+// with the given serial ID.
+func (t *InteractiveTx) checkRemoteAdd(serialID uint64) error {
+	if t.isLocalSerialID(serialID) {
+		return ErrInvalidSerialID
+	}
+
+	if t.hasSerialID(serialID) {
+		return ErrDuplicateSerialID
+	}
+
+	if t.numRemoteAdds >= maxInteractiveTxAdds {
+		return ErrTooManyAdds
+	}
+
+	return nil
+}
+
+// AddRemoteInput adds an input the remote party contributes to the
+// transaction.
+func (t *InteractiveTx) AddRemoteInput(in *InteractiveTxInput) error {
+	if err := t.checkRemoteAdd(in.SerialID); err != nil {
+		return err
+	}
+
+	if err := t.checkInput(in); err != nil {
+		return err
+	}
+
+	t.remoteInputs[in.SerialID] = in
+	t.numRemoteAdds++
+
+	return nil
+}
+
+// AddRemoteOutput adds an output the remote party contributes to the
+// transaction.
+func (t *InteractiveTx) AddRemoteOutput(out *InteractiveTxOutput) error {
+	if err := t.checkRemoteAdd(out.SerialID); err != nil {
+		return err
+	}
+
+	if err := t.checkOutput(out.TxOut); err != nil {
+		return err
+	}
+
+	t.remoteOutputs[out.SerialID] = out
+	t.numRemoteAdds++
+
+	return nil
+}
+
+// RemoveRemoteInput removes an input the remote party added before.
+func (t *InteractiveTx) RemoveRemoteInput(serialID uint64) error {
+	if _, ok := t.remoteInputs[serialID]; !ok {
+		return ErrUnknownSerialID
+	}
+
+	delete(t.remoteInputs, serialID)
+
+	return nil
+}
+
+// RemoveRemoteOutput removes an output the remote party added before.
+func (t *InteractiveTx) RemoveRemoteOutput(serialID uint64) error {
+	if _, ok := t.remoteOutputs[serialID]; !ok {
+		return ErrUnknownSerialID
+	}
+
+	delete(t.remoteOutputs, serialID)
+
+	return nil
+}
+
+// inputs returns all inputs of the transaction ordered by their serial ID.
+func (t *InteractiveTx) inputs() []*InteractiveTxInput {
+	inputs := make(
+		[]*InteractiveTxInput, 0,
+		len(t.localInputs)+len(t.remoteInputs)+1,
+	)
+	if t.sharedInput != nil {
+		inputs = append(inputs, t.sharedInput)
+	}
+	for _, in := range t.localInputs {
+		inputs = append(inputs, in)
+	}
+	for _, in := range t.remoteInputs {
+		inputs = append(inputs, in)
+	}
+
+	sort.Slice(inputs, func(i, j int) bool {
+		return inputs[i].SerialID < inputs[j].SerialID
+	})
+
+	return inputs
+}
+
+// outputs returns all outputs of the transaction ordered by their serial ID.
+func (t *InteractiveTx) outputs() []*InteractiveTxOutput {
+	outputs := make(
+		[]*InteractiveTxOutput, 0,
+		len(t.localOutputs)+len(t.remoteOutputs),
+	)
+	for _, out := range t.localOutputs {
+		outputs = append(outputs, out)
+	}
+	for _, out := range t.remoteOutputs {
+		outputs = append(outputs, out)
+	}
+
+	sort.Slice(outputs, func(i, j int) bool {
+		return outputs[i].SerialID < outputs[j].SerialID
+	})
+
+	return outputs
+}
+
+// Tx returns the unsigned transaction, with its inputs and outputs ordered
+// by their serial IDs.
+func (t *InteractiveTx) Tx(lockTime uint32) *wire.MsgTx {
+	tx := wire.NewMsgTx(2)
+	tx.LockTime = lockTime
+
+	for _, in := range t.inputs() {
+		tx.AddTxIn(&wire.TxIn{
+			PreviousOutPoint: in.OutPoint(),
+			Sequence:         in.Sequence,
+		})
+	}
+	for _, out := range t.outputs() {
+		tx.AddTxOut(out.TxOut)
+	}
+
+	return tx
+}
+
+// PrevOutFetcher returns a fetcher for the outputs spent by all inputs of
+// the transaction.
+func (t *InteractiveTx) PrevOutFetcher() *txscript.MultiPrevOutFetcher {
+	fetcher := txscript.NewMultiPrevOutFetcher(nil)
+	for _, in := range t.inputs() {
+		fetcher.AddPrevOut(in.OutPoint(), in.PrevOut())
+	}
+
+	return fetcher
+}
+
+// RemoteInputs returns the number of inputs the remote party added.
+func (t *InteractiveTx) RemoteInputs() int {
+	return len(t.remoteInputs)
+}
+
+// LocalSignsFirst returns true if we must send the witnesses of our inputs
+// before the remote party sends its own. As mandated by BOLT 02, the party
+// whose inputs add up to the lower amount signs first, with ties broken by the
+// lower node ID. The shared input of a splice counts towards neither party.
+func (t *InteractiveTx) LocalSignsFirst(localNodeID,
+	remoteNodeID *bronec.PublicKey) bool {
+
+	var localIn, remoteIn bronutil.Amount
+	for _, in := range t.localInputs {
+		localIn += bronutil.Amount(in.PrevOut().Value)
+	}
+	for _, in := range t.remoteInputs {
+		remoteIn += bronutil.Amount(in.PrevOut().Value)
+	}
+
+	if localIn != remoteIn {
+		return localIn < remoteIn
+	}
+
+	return bytes.Compare(
+		localNodeID.SerializeCompressed(),
+		remoteNodeID.SerializeCompressed(),
+	) < 0
+}
+
+// LocalWitnesses returns the witnesses of the inputs we added to the signed
+// transaction, in the order they appear in the transaction. The witness of
+// the shared input isn't included, as it requires signatures of both
+// parties.
+func (t *InteractiveTx) LocalWitnesses(tx *wire.MsgTx) []wire.TxWitness {
+	var witnesses []wire.TxWitness
+	for _, txIn := range tx.TxIn {
+		if !t.isLocalInput(txIn.PreviousOutPoint) {
+			continue
+		}
+
+		witnesses = append(witnesses, txIn.Witness)
+	}
+
+	return witnesses
+}
+
+// AddRemoteWitnesses populates the remote inputs of the transaction with the
+// witnesses sent by the remote party, which must be in the order the inputs
+// appear in the transaction. Every witness is validated before it's added.
+func (t *InteractiveTx) AddRemoteWitnesses(tx *wire.MsgTx,
+	witnesses []wire.TxWitness) error {
+
+	if len(witnesses) != len(t.remoteInputs) {
+		return ErrWitnessMismatch
+	}
+
+	prevOutFetcher := t.PrevOutFetcher()
+	sigHashes := txscript.NewTxSigHashes(tx, prevOutFetcher)
+
+	var witnessIndex int
+	for i, txIn := range tx.TxIn {
+		if t.isLocalInput(txIn.PreviousOutPoint) ||
+			t.isSharedInput(txIn.PreviousOutPoint) {
+
+			continue
+		}
+
+		witness := witnesses[witnessIndex]
+		witnessIndex++
+
+		prevOut := prevOutFetcher.FetchPrevOutput(txIn.PreviousOutPoint)
+		if prevOut == nil {
+			return fmt.Errorf("unknown input %v",
+				txIn.PreviousOutPoint)
+		}
+
+		// Nested inputs additionally need the signature script that
+		// pushes the witness program, which can be derived from the
+		// public key within the witness.
+		if txscript.IsPayToScriptHash(prevOut.PkScript) {
+			if len(witness) != 2 {
+				return fmt.Errorf("invalid witness for "+
+					"input %v", txIn.PreviousOutPoint)
+			}
+
+			sigScript, err := nestedP2WKHSigScript(witness[1])
+			if err != nil {
+				return err
+			}
+			txIn.SignatureScript = sigScript
+		}
+		txIn.Witness = witness
+
+		vm, err := txscript.NewEngine(
+			prevOut.PkScript, tx, i, txscript.StandardVerifyFlags,
+			nil, sigHashes, prevOut.Value, prevOutFetcher,
+		)
+		if err != nil {
+			return err
+		}
+		if err := vm.Execute(); err != nil {
+			return fmt.Errorf("invalid witness for input %v: %v",
+				txIn.PreviousOutPoint, err)
+		}
+	}
+
+	return nil
+}
+
+// isLocalInput returns true if the outpoint is spent by an input we added.
+func (t *InteractiveTx) isLocalInput(op wire.OutPoint) bool {
+	for _, in := range t.localInputs {
+		if in.OutPoint() == op {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isSharedInput returns true if the outpoint is spent by the shared input.
+func (t *InteractiveTx) isSharedInput(op wire.OutPoint) bool {
+	return t.sharedInput != nil && t.sharedInput.OutPoint() == op
+}
+
+// Verify checks that the constructed transaction contains the funding output
+// exactly once, added by the initiator, and that the inputs of the remote
+// party cover its contribution to the funding output as well as its share of
+// the fee at the given fee rate. When splicing, the remote contribution may be
+// negative if the remote party removes funds from the channel.
+func (t *InteractiveTx) Verify(fundingOutput *wire.TxOut,
+	remoteAmt bronutil.Amount, feeRate chainfee.SatPerKWeight) error {
+
+	isFundingOutput := func(out *InteractiveTxOutput) bool {
+		return out.TxOut.Value == fundingOutput.Value &&
+			string(out.TxOut.PkScript) ==
+				string(fundingOutput.PkScript)
+	}
+
+	initiatorOutputs := t.localOutputs
+	if !t.initiator {
+		initiatorOutputs = t.remoteOutputs
+	}
+
+	var numFundingOutputs int
+	for _, out := range t.outputs() {
+		if !isFundingOutput(out) {
+			continue
+		}
+
+		if _, ok := initiatorOutputs[out.SerialID]; !ok {
+			return ErrMissingFundingOutput
+		}
+		numFundingOutputs++
+	}
+	if numFundingOutputs != 1 {
+		return ErrMissingFundingOutput
+	}
+
+	// Sum up what the remote party puts into and takes out of the
+	// transaction, and estimate the weight it needs to pay the fee for.
+	var (
+		remoteIn       bronutil.Amount
+		remoteOut      bronutil.Amount
+		weightEstimate input.TxWeightEstimator
+	)
+	for _, in := range t.remoteInputs {
+		remoteIn += bronutil.Amount(in.PrevOut().Value)
+
+		err := addInputWeight(&weightEstimate, in.PrevOut().PkScript)
+		if err != nil {
+			return err
+		}
+	}
+	for _, out := range t.remoteOutputs {
+		weightEstimate.AddTxOutput(out.TxOut)
+
+		// The funding output is paid for by both parties, so it's
+		// accounted for through the remote contribution instead.
+		if isFundingOutput(out) {
+			continue
+		}
+		remoteOut += bronutil.Amount(out.TxOut.Value)
+	}
+
+	// The shared input of a splice is paid for by the initiator as well.
+	if t.sharedInput != nil && !t.initiator {
+		weightEstimate.AddWitnessInput(input.MultiSigWitnessSize)
+	}
+
+	// The common fields are paid for by the initiator, so if the remote
+	// party isn't the initiator, its share excludes the weight of an
+	// empty transaction.
+	weight := int64(weightEstimate.Weight())
+	if t.initiator {
+		var emptyTx input.TxWeightEstimator
+		weight -= int64(emptyTx.Weight())
+	}
+	remoteFee := feeRate.FeeForWeight(weight)
+
+	if remoteIn-remoteOut < remoteAmt+remoteFee {
+		return fmt.Errorf("%w: inputs=%v, outputs=%v, "+
+			"contribution=%v, fee=%v", ErrInsufficientContribution,
+			remoteIn, remoteOut, remoteAmt, remoteFee)
+	}
+
+	return nil
+}
+
+// nestedP2WKHSigScript returns the signature script of an input that spends
+// a P2WKH output nested in P2SH with the given public key.
+func nestedP2WKHSigScript(pubKey []byte) ([]byte, error) {
+	witnessProgram, err := txscript.NewScriptBuilder().
+		AddOp(txscript.OP_0).
+		AddData(bronutil.Hash160(pubKey)).
+		Script()
+	if err != nil {
+		return nil, err
+	}
+
+	return txscript.NewScriptBuilder().AddData(witnessProgram).Script()
+}
+
+// addInputWeight adds the weight of an input spending the given script to
+// the estimate.
+func addInputWeight(weightEstimate *input.TxWeightEstimator,
+	pkScript []byte) error {
+
+	switch {
+	case txscript.IsPayToWitnessPubKeyHash(pkScript):
+		weightEstimate.AddP2WKHInput()
+
+	case txscript.IsPayToScriptHash(pkScript):
+		weightEstimate.AddNestedP2WKHInput()
+
+	case txscript.IsPayToTaproot(pkScript):
+		weightEstimate.AddTaprootKeySpendInput(txscript.SigHashAll)
+
+	default:
+		return &errUnsupportedInput{pkScript}
+	}
+
+	return nil
+}
@@ -0,0 +1,501 @@
+package chanfunding
+
+import (
+	"testing"
+
+	"github.com/brronsuite/broln/input"
+	"github.com/brronsuite/broln/lnwallet/chainfee"
+	"github.com/brronsuite/brond/bronec"
+	"github.com/brronsuite/brond/txscript"
+	"github.com/brronsuite/brond/wire"
+	"github.com/brronsuite/bronutil"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	fundingScript = append(
+		[]byte{0x00, 0x20}, make([]byte, 32)...,
+	)
+)
+
+// newPrevTx creates a transaction with a single P2WKH output of the given
+// value. The lock time is used to make the transactions unique.
+func newPrevTx(value int64, lockTime uint32) *wire.MsgTx {
+	tx := wire.NewMsgTx(2)
+	tx.LockTime = lockTime
+	tx.AddTxOut(&wire.TxOut{
+		Value:    value,
+		PkScript: p2wkhScript,
+	})
+
+	return tx
+}
+
+// TestInteractiveTxRemoteAdds tests that the inputs and outputs added by the
+// remote party are validated.
+func TestInteractiveTxRemoteAdds(t *testing.T) {
+	t.Parallel()
+
+	tx := NewInteractiveTx(true)
+
+	localIn, err := tx.AddLocalInput(newPrevTx(1000, 1), 0)
+	require.NoError(t, err)
+	require.Zero(t, localIn.SerialID%2)
+
+	// The responder must use odd serial IDs.
+	err = tx.AddRemoteInput(&InteractiveTxInput{
+		SerialID: 2,
+		PrevTx:   newPrevTx(1000, 2),
+	})
+	require.ErrorIs(t, err, ErrInvalidSerialID)
+
+	err = tx.AddRemoteInput(&InteractiveTxInput{
+		SerialID: 3,
+		PrevTx:   newPrevTx(1000, 2),
+	})
+	require.NoError(t, err)
+
+	// Serial IDs can't be reused, neither for inputs nor for outputs.
+	err = tx.AddRemoteOutput(&InteractiveTxOutput{
+		SerialID: 3,
+		TxOut:    &wire.TxOut{Value: 1000, PkScript: p2wkhScript},
+	})
+	require.ErrorIs(t, err, ErrDuplicateSerialID)
+
+	// The same outpoint can't be spent twice.
+	err = tx.AddRemoteInput(&InteractiveTxInput{
+		SerialID: 5,
+		PrevTx:   localIn.PrevTx,
+	})
+	require.ErrorIs(t, err, ErrDuplicateInput)
+
+	// Inputs must spend outputs we can estimate the weight of.
+	legacyTx := newPrevTx(1000, 3)
+	legacyTx.TxOut[0].PkScript = p2khScript
+	err = tx.AddRemoteInput(&InteractiveTxInput{
+		SerialID: 7,
+		PrevTx:   legacyTx,
+	})
+	var unsupportedErr *errUnsupportedInput
+	require.ErrorAs(t, err, &unsupportedErr)
+
+	// The output index must exist in the previous transaction.
+	err = tx.AddRemoteInput(&InteractiveTxInput{
+		SerialID:     9,
+		PrevTx:       newPrevTx(1000, 4),
+		PrevOutIndex: 1,
+	})
+	require.Error(t, err)
+
+	// Only inputs that were added by the remote party can be removed.
+	require.ErrorIs(
+		t, tx.RemoveRemoteInput(localIn.SerialID), ErrUnknownSerialID,
+	)
+	require.NoError(t, tx.RemoveRemoteInput(3))
+	require.Zero(t, tx.RemoteInputs())
+}
+
+// TestInteractiveTxOrdering tests that the inputs and outputs of the
+// transaction are ordered by their serial IDs.
+func TestInteractiveTxOrdering(t *testing.T) {
+	t.Parallel()
+
+	tx := NewInteractiveTx(false)
+
+	remoteTxs := []*wire.MsgTx{newPrevTx(1000, 1), newPrevTx(2000, 2)}
+	require.NoError(t, tx.AddRemoteInput(&InteractiveTxInput{
+		SerialID: 10,
+		PrevTx:   remoteTxs[1],
+	}))
+	require.NoError(t, tx.AddRemoteInput(&InteractiveTxInput{
+		SerialID: 0,
+		PrevTx:   remoteTxs[0],
+	}))
+	require.NoError(t, tx.AddRemoteOutput(&InteractiveTxOutput{
+		SerialID: 4,
+		TxOut:    &wire.TxOut{Value: 2, PkScript: p2wkhScript},
+	}))
+	require.NoError(t, tx.AddRemoteOutput(&InteractiveTxOutput{
+		SerialID: 2,
+		TxOut:    &wire.TxOut{Value: 1, PkScript: p2wkhScript},
+	}))
+
+	msgTx := tx.Tx(100)
+	require.EqualValues(t, 100, msgTx.LockTime)
+	require.Len(t, msgTx.TxIn, 2)
+	require.Equal(
+		t, remoteTxs[0].TxHash(), msgTx.TxIn[0].PreviousOutPoint.Hash,
+	)
+	require.Equal(
+		t, remoteTxs[1].TxHash(), msgTx.TxIn[1].PreviousOutPoint.Hash,
+	)
+	require.Len(t, msgTx.TxOut, 2)
+	require.EqualValues(t, 1, msgTx.TxOut[0].Value)
+	require.EqualValues(t, 2, msgTx.TxOut[1].Value)
+
+	// The previous outputs of all inputs must be known for signing.
+	fetcher := tx.PrevOutFetcher()
+	for _, txIn := range msgTx.TxIn {
+		require.NotNil(t, fetcher.FetchPrevOutput(txIn.PreviousOutPoint))
+	}
+}
+
+// TestInteractiveTxVerify tests that the funding output and the contribution
+// of the remote party are verified.
+func TestInteractiveTxVerify(t *testing.T) {
+	t.Parallel()
+
+	const (
+		feeRate     = chainfee.SatPerKWeight(1000)
+		localAmt    = bronutil.Amount(100_000)
+		remoteAmt   = bronutil.Amount(50_000)
+		remoteInput = int64(60_000)
+	)
+
+	fundingOutput := &wire.TxOut{
+		Value:    int64(localAmt + remoteAmt),
+		PkScript: fundingScript,
+	}
+
+	// The responder only pays for its own input and change output.
+	var weightEstimate input.TxWeightEstimator
+	weightEstimate.AddP2WKHInput()
+	weightEstimate.AddP2WKHOutput()
+	var emptyTx input.TxWeightEstimator
+	remoteFee := feeRate.FeeForWeight(
+		int64(weightEstimate.Weight() - emptyTx.Weight()),
+	)
+
+	testCases := []struct {
+		name string
+
+		// addFunding is true if the initiator adds the funding
+		// output.
+		addFunding bool
+
+		// remoteFunding is true if the responder adds a funding
+		// output.
+		remoteFunding bool
+
+		// remoteChange is the value of the change output of the
+		// responder.
+		remoteChange int64
+
+		expectedErr error
+	}{
+		{
+			name:         "valid",
+			addFunding:   true,
+			remoteChange: remoteInput - int64(remoteAmt+remoteFee),
+		},
+		{
+			name:         "missing funding output",
+			remoteChange: remoteInput - int64(remoteAmt+remoteFee),
+			expectedErr:  ErrMissingFundingOutput,
+		},
+		{
+			name:          "funding output added by responder",
+			remoteFunding: true,
+			remoteChange:  remoteInput - int64(remoteAmt+remoteFee),
+			expectedErr:   ErrMissingFundingOutput,
+		},
+		{
+			name:         "fee not covered",
+			addFunding:   true,
+			remoteChange: remoteInput - int64(remoteAmt),
+			expectedErr:  ErrInsufficientContribution,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			tx := NewInteractiveTx(true)
+
+			_, err := tx.AddLocalInput(newPrevTx(200_000, 1), 0)
+			require.NoError(t, err)
+
+			if testCase.addFunding {
+				_, err := tx.AddLocalOutput(fundingOutput)
+				require.NoError(t, err)
+			}
+
+			require.NoError(t, tx.AddRemoteInput(&InteractiveTxInput{
+				SerialID: 1,
+				PrevTx:   newPrevTx(remoteInput, 2),
+			}))
+			require.NoError(t, tx.AddRemoteOutput(
+				&InteractiveTxOutput{
+					SerialID: 3,
+					TxOut: &wire.TxOut{
+						Value:    testCase.remoteChange,
+						PkScript: p2wkhScript,
+					},
+				},
+			))
+
+			if testCase.remoteFunding {
+				require.NoError(t, tx.AddRemoteOutput(
+					&InteractiveTxOutput{
+						SerialID: 5,
+						TxOut:    fundingOutput,
+					},
+				))
+			}
+
+			err = tx.Verify(fundingOutput, remoteAmt, feeRate)
+			if testCase.expectedErr == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, testCase.expectedErr)
+		})
+	}
+}
+
+// TestInteractiveTxRemoteWitnesses tests that the witnesses sent by the remote
+// party are attached to its inputs and validated.
+func TestInteractiveTxRemoteWitnesses(t *testing.T) {
+	t.Parallel()
+
+	privKey, err := bronec.NewPrivateKey(bronec.S256())
+	require.NoError(t, err)
+	pubKey := privKey.PubKey().SerializeCompressed()
+
+	remotePkScript, err := txscript.NewScriptBuilder().
+		AddOp(txscript.OP_0).
+		AddData(bronutil.Hash160(pubKey)).
+		Script()
+	require.NoError(t, err)
+
+	tx := NewInteractiveTx(true)
+
+	_, err = tx.AddLocalInput(newPrevTx(1000, 1), 0)
+	require.NoError(t, err)
+
+	remotePrevTx := newPrevTx(2000, 2)
+	remotePrevTx.TxOut[0].PkScript = remotePkScript
+	require.NoError(t, tx.AddRemoteInput(&InteractiveTxInput{
+		SerialID: 1,
+		PrevTx:   remotePrevTx,
+	}))
+
+	_, err = tx.AddLocalOutput(&wire.TxOut{
+		Value:    2500,
+		PkScript: fundingScript,
+	})
+	require.NoError(t, err)
+
+	// Sign the input of the remote party, so we can pass its witness.
+	msgTx := tx.Tx(0)
+	prevOutFetcher := tx.PrevOutFetcher()
+	sigHashes := txscript.NewTxSigHashes(msgTx, prevOutFetcher)
+
+	remoteIndex := 0
+	if msgTx.TxIn[0].PreviousOutPoint.Hash != remotePrevTx.TxHash() {
+		remoteIndex = 1
+	}
+	witness, err := txscript.WitnessSignature(
+		msgTx, sigHashes, remoteIndex, 2000, remotePkScript,
+		txscript.SigHashAll, privKey, true,
+	)
+	require.NoError(t, err)
+
+	// The number of witnesses must match the number of remote inputs.
+	err = tx.AddRemoteWitnesses(msgTx, nil)
+	require.ErrorIs(t, err, ErrWitnessMismatch)
+
+	// A witness that doesn't satisfy the spent output is rejected.
+	invalidWitness := wire.TxWitness{witness[0][:10], witness[1]}
+	err = tx.AddRemoteWitnesses(
+		msgTx, []wire.TxWitness{invalidWitness},
+	)
+	require.Error(t, err)
+
+	require.NoError(t, tx.AddRemoteWitnesses(
+		msgTx, []wire.TxWitness{witness},
+	))
+	require.Equal(t, witness, msgTx.TxIn[remoteIndex].Witness)
+
+	// Our own input hasn't been signed, so its witness is still empty.
+	localWitnesses := tx.LocalWitnesses(msgTx)
+	require.Len(t, localWitnesses, 1)
+	require.Empty(t, localWitnesses[0])
+}
+
+// TestInteractiveTxSignOrder tests that the party with the lower input amount
+// sends its witnesses first, and that ties are broken by the node IDs.
+func TestInteractiveTxSignOrder(t *testing.T) {
+	t.Parallel()
+
+	keyA, err := bronec.NewPrivateKey(bronec.S256())
+	require.NoError(t, err)
+	keyB, err := bronec.NewPrivateKey(bronec.S256())
+	require.NoError(t, err)
+
+	lowKey, highKey := keyA.PubKey(), keyB.PubKey()
+	if string(lowKey.SerializeCompressed()) >
+		string(highKey.SerializeCompressed()) {
+
+		lowKey, highKey = highKey, lowKey
+	}
+
+	newTx := func(localAmt, remoteAmt int64) *InteractiveTx {
+		tx := NewInteractiveTx(true)
+		_, err := tx.AddLocalInput(newPrevTx(localAmt, 1), 0)
+		require.NoError(t, err)
+		require.NoError(t, tx.AddRemoteInput(&InteractiveTxInput{
+			SerialID: 1,
+			PrevTx:   newPrevTx(remoteAmt, 2),
+		}))
+
+		return tx
+	}
+
+	// The party contributing less signs first, regardless of its node
+	// ID.
+	tx := newTx(1000, 2000)
+	require.True(t, tx.LocalSignsFirst(highKey, lowKey))
+	require.True(t, tx.LocalSignsFirst(lowKey, highKey))
+
+	tx = newTx(2000, 1000)
+	require.False(t, tx.LocalSignsFirst(highKey, lowKey))
+	require.False(t, tx.LocalSignsFirst(lowKey, highKey))
+
+	// With equal contributions, the lower node ID signs first.
+	tx = newTx(1000, 1000)
+	require.True(t, tx.LocalSignsFirst(lowKey, highKey))
+	require.False(t, tx.LocalSignsFirst(highKey, lowKey))
+}
+
+// TestInteractiveTxSharedInput tests that the shared input of a splice is
+// placed first, can't be spent twice and is paid for by the initiator.
+func TestInteractiveTxSharedInput(t *testing.T) {
+	t.Parallel()
+
+	const (
+		feeRate     = chainfee.SatPerKWeight(1000)
+		oldCapacity = int64(150_000)
+		remoteInput = int64(60_000)
+	)
+
+	sharedOutPoint := wire.OutPoint{Index: 1}
+	sharedPrevOut := &wire.TxOut{
+		Value:    oldCapacity,
+		PkScript: fundingScript,
+	}
+
+	// The initiator pays for the shared input in addition to its own
+	// input and change output.
+	var weightEstimate input.TxWeightEstimator
+	weightEstimate.AddP2WKHInput()
+	weightEstimate.AddP2WKHOutput()
+	weightEstimate.AddTxOutput(&wire.TxOut{PkScript: fundingScript})
+	weightEstimate.AddWitnessInput(input.MultiSigWitnessSize)
+	remoteFee := feeRate.FeeForWeight(int64(weightEstimate.Weight()))
+
+	testCases := []struct {
+		name string
+
+		// remoteAmt is the amount the initiator adds to or removes
+		// from the channel.
+		remoteAmt bronutil.Amount
+
+		// fee is the fee the initiator pays.
+		fee bronutil.Amount
+
+		expectedErr error
+	}{
+		{
+			name:      "splice in",
+			remoteAmt: 50_000,
+			fee:       remoteFee,
+		},
+		{
+			name:      "splice out",
+			remoteAmt: -50_000,
+			fee:       remoteFee,
+		},
+		{
+			name:        "shared input not paid for",
+			remoteAmt:   50_000,
+			fee:         remoteFee - feeRate.FeeForWeight(500),
+			expectedErr: ErrInsufficientContribution,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			tx := NewInteractiveTx(false)
+			require.NoError(t, tx.AddSharedInput(
+				sharedOutPoint, sharedPrevOut,
+			))
+
+			// The shared input can only be added once, and its
+			// outpoint and serial ID can't be reused.
+			require.ErrorIs(t, tx.AddSharedInput(
+				sharedOutPoint, sharedPrevOut,
+			), ErrSharedInputExists)
+
+			prevTx := newPrevTx(remoteInput, 1)
+			err := tx.AddRemoteInput(&InteractiveTxInput{
+				SerialID: 0,
+				PrevTx:   prevTx,
+			})
+			require.ErrorIs(t, err, ErrDuplicateSerialID)
+
+			require.NoError(t, tx.AddRemoteInput(&InteractiveTxInput{
+				SerialID: 2,
+				PrevTx:   prevTx,
+			}))
+
+			fundingOutput := &wire.TxOut{
+				Value: oldCapacity +
+					int64(testCase.remoteAmt),
+				PkScript: fundingScript,
+			}
+			require.NoError(t, tx.AddRemoteOutput(
+				&InteractiveTxOutput{
+					SerialID: 4,
+					TxOut:    fundingOutput,
+				},
+			))
+
+			change := remoteInput - int64(testCase.remoteAmt) -
+				int64(testCase.fee)
+			require.NoError(t, tx.AddRemoteOutput(
+				&InteractiveTxOutput{
+					SerialID: 6,
+					TxOut: &wire.TxOut{
+						Value:    change,
+						PkScript: p2wkhScript,
+					},
+				},
+			))
+
+			msgTx := tx.Tx(0)
+			require.Len(t, msgTx.TxIn, 2)
+			require.Equal(
+				t, sharedOutPoint,
+				msgTx.TxIn[0].PreviousOutPoint,
+			)
+			require.Equal(t, 1, tx.RemoteInputs())
+
+			err = tx.Verify(
+				fundingOutput, testCase.remoteAmt, feeRate,
+			)
+			if testCase.expectedErr == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, testCase.expectedErr)
+		})
+	}
+}
@@ -59,6 +59,14 @@ func (f *FullIntent) BindKeys(localKey *keychain.KeyDescriptor,
 	f.remoteKey = remoteKey
 }
 
+// SetRemoteFundingAmt sets the amount the remote party contributes to the
+// funding output. This is used for dual funded channels, where the initiator
+// only learns the contribution of the remote party after the intent was
+// created. It must be called before BindKeys.
+func (f *FullIntent) SetRemoteFundingAmt(amt bronutil.Amount) {
+	f.remoteFundingAmt = amt
+}
+
 // CompileFundingTx is to be called after BindKeys on the sub-intent has been
 // called. This method will construct the final funding transaction, and fully
 // sign all inputs that are known by the backing CoinSource. After this method
@@ -105,12 +113,78 @@ func (f *FullIntent) CompileFundingTx(extraInputs []*wire.TxIn,
 		fundingTx, fundingOutput.PkScript,
 	)
 
-	// Next, sign all inputs that are ours, collecting the signatures in
-	// order of the inputs.
-	signDesc := input.SignDescriptor{
-		HashType:  txscript.SigHashAll,
-		SigHashes: txscript.NewTxSigHashes(fundingTx),
+	// Next, we'll sign all inputs that are ours.
+	err = f.signFundingTx(
+		fundingTx, txscript.NewMultiPrevOutFetcher(nil),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Finally, we'll populate the chanPoint now that we've fully
+	// constructed the funding transaction.
+	f.chanPoint = &wire.OutPoint{
+		Hash:  fundingTx.TxHash(),
+		Index: multiSigIndex,
+	}
+
+	return fundingTx, nil
+}
+
+// SignInteractiveFundingTx is to be called after BindKeys on the sub-intent
+// has been called, as an alternative to CompileFundingTx for funding
+// transactions that were constructed interactively with the remote party. It
+// signs all inputs of the transaction that are known by the backing
+// CoinSource and records the channel point. The prevOutFetcher must know the
+// previous outputs of all inputs of the transaction.
+func (f *FullIntent) SignInteractiveFundingTx(fundingTx *wire.MsgTx,
+	prevOutFetcher *txscript.MultiPrevOutFetcher) error {
+
+	_, fundingOutput, err := f.FundingOutput()
+	if err != nil {
+		return err
+	}
+
+	found, multiSigIndex := input.FindScriptOutputIndex(
+		fundingTx, fundingOutput.PkScript,
+	)
+	if !found {
+		return fmt.Errorf("funding output not found in transaction")
+	}
+
+	if err := f.signFundingTx(fundingTx, prevOutFetcher); err != nil {
+		return err
+	}
+
+	f.chanPoint = &wire.OutPoint{
+		Hash:  fundingTx.TxHash(),
+		Index: multiSigIndex,
 	}
+
+	return nil
+}
+
+// SignInputs signs all inputs of the transaction that are known by the
+// backing CoinSource. Unlike SignInteractiveFundingTx, it doesn't require the
+// funding keys to be bound, as it's used to sign our contribution to
+// transactions that don't open a new channel, such as a splice. The
+// prevOutFetcher must know the previous outputs of all inputs of the
+// transaction.
+func (f *FullIntent) SignInputs(tx *wire.MsgTx,
+	prevOutFetcher *txscript.MultiPrevOutFetcher) error {
+
+	return f.signFundingTx(tx, prevOutFetcher)
+}
+
+// signFundingTx signs all inputs of the funding transaction that are known by
+// the backing CoinSource, populating their witnesses. The previous outputs of
+// our inputs are added to the passed prevOutFetcher.
+func (f *FullIntent) signFundingTx(fundingTx *wire.MsgTx,
+	prevOutFetcher *txscript.MultiPrevOutFetcher) error {
+
+	// First, we'll look up all inputs that are ours. We need to know the
+	// previous outputs of all of them to compute the sighashes.
+	ourInputs := make(map[int]*wire.TxOut)
 	for i, txIn := range fundingTx.TxIn {
 		// We can only sign this input if it's ours, so we'll ask the
 		// coin source if it can map this outpoint into a coin we own.
@@ -122,12 +196,30 @@ func (f *FullIntent) CompileFundingTx(extraInputs []*wire.TxIn,
 			continue
 		}
 
-		// Now that we know the input is ours, we'll populate the
-		// signDesc with the per input unique information.
-		signDesc.Output = &wire.TxOut{
+		ourInputs[i] = &wire.TxOut{
 			Value:    info.Value,
 			PkScript: info.PkScript,
 		}
+		prevOutFetcher.AddPrevOut(txIn.PreviousOutPoint, ourInputs[i])
+	}
+
+	// Now sign all inputs that are ours, collecting the signatures in
+	// order of the inputs.
+	sigHashes := txscript.NewTxSigHashes(fundingTx, prevOutFetcher)
+	signDesc := input.SignDescriptor{
+		HashType:          txscript.SigHashAll,
+		SigHashes:         sigHashes,
+		PrevOutputFetcher: prevOutFetcher,
+	}
+	for i, txIn := range fundingTx.TxIn {
+		prevOut, ok := ourInputs[i]
+		if !ok {
+			continue
+		}
+
+		// Now that we know the input is ours, we'll populate the
+		// signDesc with the per input unique information.
+		signDesc.Output = prevOut
 		signDesc.InputIndex = i
 
 		// Finally, we'll sign the input as is, and populate the input
@@ -136,21 +228,14 @@ func (f *FullIntent) CompileFundingTx(extraInputs []*wire.TxIn,
 			fundingTx, &signDesc,
 		)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
 		txIn.SignatureScript = inputScript.SigScript
 		txIn.Witness = inputScript.Witness
 	}
 
-	// Finally, we'll populate the chanPoint now that we've fully
-	// constructed the funding transaction.
-	f.chanPoint = &wire.OutPoint{
-		Hash:  fundingTx.TxHash(),
-		Index: multiSigIndex,
-	}
-
-	return fundingTx, nil
+	return nil
 }
 
 // Inputs returns all inputs to the final funding transaction that we
@@ -326,6 +326,12 @@ type PaymentDescriptor struct {
 	// NOTE: Populated only on add payment descriptor entry types.
 	OnionBlob []byte
 
+	// BlindingPoint is the route blinding point that was sent along with
+	// the HTLC if it is routed through a blinded route.
+	//
+	// NOTE: Populated only on add payment descriptor entry types.
+	BlindingPoint *bronec.PublicKey
+
 	// ShaOnionBlob is a sha of the onion blob.
 	//
 	// NOTE: Populated only in payment descriptor with MalformedFail type.
@@ -394,12 +400,13 @@ func PayDescsFromRemoteLogUpdates(chanID lnwire.ShortChannelID, height uint64,
 
 		case *lnwire.UpdateAddHTLC:
 			pd = PaymentDescriptor{
-				RHash:     wireMsg.PaymentHash,
-				Timeout:   wireMsg.Expiry,
-				Amount:    wireMsg.Amount,
-				EntryType: Add,
-				HtlcIndex: wireMsg.ID,
-				LogIndex:  logUpdate.LogIndex,
+				RHash:         wireMsg.PaymentHash,
+				Timeout:       wireMsg.Expiry,
+				Amount:        wireMsg.Amount,
+				EntryType:     Add,
+				HtlcIndex:     wireMsg.ID,
+				LogIndex:      logUpdate.LogIndex,
+				BlindingPoint: wireMsg.BlindingPoint,
 				SourceRef: &channeldb.AddRef{
 					Height: height,
 					Index:  uint16(i),
@@ -543,6 +550,14 @@ type commitment struct {
 	// view.
 	outgoingHTLCIndex map[int32]*PaymentDescriptor
 	incomingHTLCIndex map[int32]*PaymentDescriptor
+
+	// spliceCommits are the commitments for the same state that spend the
+	// funding outputs of the pending splices of the channel, keyed by the
+	// txid of their splice transaction.
+	//
+	// NOTE: These are only populated for new commitments, until they are
+	// written to disk.
+	spliceCommits map[chainhash.Hash]*commitment
 }
 
 // locateOutputIndex is a small helper function to locate the output index of a
@@ -727,6 +742,7 @@ func (c *commitment) toDiskCommit(ourCommit bool) *channeldb.ChannelCommitment {
 			HtlcIndex:     htlc.HtlcIndex,
 			LogIndex:      htlc.LogIndex,
 			Incoming:      false,
+			BlindingPoint: htlc.BlindingPoint,
 		}
 		h.OnionBlob = make([]byte, len(htlc.OnionBlob))
 		copy(h.OnionBlob[:], htlc.OnionBlob)
@@ -752,6 +768,7 @@ func (c *commitment) toDiskCommit(ourCommit bool) *channeldb.ChannelCommitment {
 			HtlcIndex:     htlc.HtlcIndex,
 			LogIndex:      htlc.LogIndex,
 			Incoming:      true,
+			BlindingPoint: htlc.BlindingPoint,
 		}
 		h.OnionBlob = make([]byte, len(htlc.OnionBlob))
 		copy(h.OnionBlob[:], htlc.OnionBlob)
@@ -843,6 +860,7 @@ func (lc *LightningChannel) diskHtlcToPayDesc(feeRate chainfee.SatPerKWeight,
 		HtlcIndex:          htlc.HtlcIndex,
 		LogIndex:           htlc.LogIndex,
 		OnionBlob:          htlc.OnionBlob,
+		BlindingPoint:      htlc.BlindingPoint,
 		localOutputIndex:   localOutputIndex,
 		remoteOutputIndex:  remoteOutputIndex,
 		ourPkScript:        ourP2WSH,
@@ -1276,6 +1294,14 @@ type LightningChannel struct {
 
 	channelState *channeldb.OpenChannel
 
+	// pendingSplices are the splices of the channel that have been signed
+	// by both parties but aren't locked yet. Every new commitment is also
+	// created for the funding outputs of these splices.
+	//
+	// NOTE: Their commitments aren't kept up to date in memory, they're
+	// only updated on disk.
+	pendingSplices []*channeldb.PendingSplice
+
 	commitBuilder *CommitmentBuilder
 
 	// [local|remote]Log is a (mostly) append-only log storing all the HTLC
@@ -1347,6 +1373,13 @@ func NewLightningChannel(signer input.Signer,
 		return nil, err
 	}
 
+	// Any pending splices need to be restored as well, as new commitments
+	// have to be signed for their funding outputs.
+	lc.pendingSplices, err = state.PendingSplices()
+	if err != nil {
+		return nil, err
+	}
+
 	// Create the sign descriptor which we'll be using very frequently to
 	// request a signature for the 2-of-2 multi-sig from the signer in
 	// order to complete channel state transitions.
@@ -1431,6 +1464,7 @@ func (lc *LightningChannel) logUpdateToPayDesc(logUpdate *channeldb.LogUpdate,
 			EntryType:             Add,
 			HtlcIndex:             wireMsg.ID,
 			LogIndex:              logUpdate.LogIndex,
+			BlindingPoint:         wireMsg.BlindingPoint,
 			addCommitHeightRemote: commitHeight,
 		}
 		pd.OnionBlob = make([]byte, len(wireMsg.OnionBlob))
@@ -1630,6 +1664,7 @@ func (lc *LightningChannel) remoteLogUpdateToPayDesc(logUpdate *channeldb.LogUpd
 			EntryType:            Add,
 			HtlcIndex:            wireMsg.ID,
 			LogIndex:             logUpdate.LogIndex,
+			BlindingPoint:        wireMsg.BlindingPoint,
 			addCommitHeightLocal: commitHeight,
 		}
 		pd.OnionBlob = make([]byte, len(wireMsg.OnionBlob))
@@ -2596,13 +2631,62 @@ func (lc *LightningChannel) fetchCommitmentView(remoteChain bool,
 	}
 	feePerKw := filteredHTLCView.feePerKw
 
+	c := &commitment{
+		ourMessageIndex:   ourLogIndex,
+		ourHtlcIndex:      ourHtlcIndex,
+		theirMessageIndex: theirLogIndex,
+		theirHtlcIndex:    theirHtlcIndex,
+		height:            nextHeight,
+		feePerKw:          feePerKw,
+		dustLimit:         dustLimit,
+		isOurs:            !remoteChain,
+	}
+	err = lc.buildCommitment(
+		c, ourBalance, theirBalance, filteredHTLCView, keyRing,
+		fundingTxIn(lc.channelState), lc.channelState.Capacity,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// The same state is also committed to for the funding outputs of all
+	// pending splices of the channel, so whichever splice transaction
+	// confirms, the latest state can be published.
+	for _, splice := range lc.pendingSplices {
+		spliceCommit, err := lc.newSpliceCommitment(
+			c, splice, ourBalance, theirBalance, filteredHTLCView,
+			keyRing,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if c.spliceCommits == nil {
+			c.spliceCommits = make(map[chainhash.Hash]*commitment)
+		}
+		c.spliceCommits[splice.SpliceTx.TxHash()] = spliceCommit
+	}
+
+	return c, nil
+}
+
+// buildCommitment creates the commitment transaction for the evaluated HTLC
+// view, spending the funding output of the given capacity referenced by
+// fundingTxIn, and populates the balances, transaction and HTLCs of the passed
+// commitment with it. The passed balances are the ones *before* subtracting
+// the commitment fee.
+func (lc *LightningChannel) buildCommitment(c *commitment, ourBalance,
+	theirBalance lnwire.MilliBronees, filteredHTLCView *htlcView,
+	keyRing *CommitmentKeyRing, fundingTxIn wire.TxIn,
+	capacity bronutil.Amount) error {
+
 	// Actually generate unsigned commitment transaction for this view.
 	commitTx, err := lc.commitBuilder.createUnsignedCommitmentTx(
-		ourBalance, theirBalance, !remoteChain, feePerKw, nextHeight,
-		filteredHTLCView, keyRing,
+		ourBalance, theirBalance, c.isOurs, c.feePerKw, c.height,
+		filteredHTLCView, keyRing, fundingTxIn, capacity,
 	)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	// We'll assert that there hasn't been a mistake during fee calculation
@@ -2611,7 +2695,7 @@ func (lc *LightningChannel) fetchCommitmentView(remoteChain bool,
 	for _, txOut := range commitTx.txn.TxOut {
 		totalOut += bronutil.Amount(txOut.Value)
 	}
-	fee := lc.channelState.Capacity - totalOut
+	fee := capacity - totalOut
 
 	// Since the transaction is not signed yet, we use the witness weight
 	// used for weight calculation.
@@ -2622,28 +2706,18 @@ func (lc *LightningChannel) fetchCommitmentView(remoteChain bool,
 	effFeeRate := chainfee.SatPerKWeight(fee) * 1000 /
 		chainfee.SatPerKWeight(weight)
 	if effFeeRate < chainfee.AbsoluteFeePerKwFloor {
-		return nil, fmt.Errorf("height=%v, for ChannelPoint(%v) "+
+		return fmt.Errorf("height=%v, for ChannelPoint(%v) "+
 			"attempts to create commitment with feerate %v: %v",
-			nextHeight, lc.channelState.FundingOutpoint,
-			effFeeRate, spew.Sdump(commitTx))
+			c.height, fundingTxIn.PreviousOutPoint, effFeeRate,
+			spew.Sdump(commitTx))
 	}
 
-	// With the commitment view created, store the resulting balances and
-	// transaction with the other parameters for this height.
-	c := &commitment{
-		ourBalance:        commitTx.ourBalance,
-		theirBalance:      commitTx.theirBalance,
-		txn:               commitTx.txn,
-		fee:               commitTx.fee,
-		ourMessageIndex:   ourLogIndex,
-		ourHtlcIndex:      ourHtlcIndex,
-		theirMessageIndex: theirLogIndex,
-		theirHtlcIndex:    theirHtlcIndex,
-		height:            nextHeight,
-		feePerKw:          feePerKw,
-		dustLimit:         dustLimit,
-		isOurs:            !remoteChain,
-	}
+	// With the commitment transaction created, store the resulting
+	// balances and transaction.
+	c.ourBalance = commitTx.ourBalance
+	c.theirBalance = commitTx.theirBalance
+	c.txn = commitTx.txn
+	c.fee = commitTx.fee
 
 	// In order to ensure _none_ of the HTLC's associated with this new
 	// commitment are mutated, we'll manually copy over each HTLC to its
@@ -2661,12 +2735,7 @@ func (lc *LightningChannel) fetchCommitmentView(remoteChain bool,
 	// locations of each HTLC in the commitment state. We pass in the sorted
 	// slice of CLTV deltas in order to properly locate HTLCs that otherwise
 	// have the same payment hash and amount.
-	err = c.populateHtlcIndexes(lc.channelState.ChanType, commitTx.cltvs)
-	if err != nil {
-		return nil, err
-	}
-
-	return c, nil
+	return c.populateHtlcIndexes(lc.channelState.ChanType, commitTx.cltvs)
 }
 
 func fundingTxIn(chanState *channeldb.OpenChannel) wire.TxIn {
@@ -3046,7 +3115,7 @@ func genRemoteHtlcSigJobs(keyRing *CommitmentKeyRing,
 			WitnessScript: htlc.theirWitnessScript,
 			Output:        txOut,
 			HashType:      sigHashType,
-			SigHashes:     txscript.NewTxSigHashes(sigJob.Tx),
+			SigHashes:     input.NewTxSigHashesV0Only(sigJob.Tx),
 			InputIndex:    0,
 		}
 		sigJob.OutputIndex = htlc.remoteOutputIndex
@@ -3099,7 +3168,7 @@ func genRemoteHtlcSigJobs(keyRing *CommitmentKeyRing,
 			WitnessScript: htlc.theirWitnessScript,
 			Output:        txOut,
 			HashType:      sigHashType,
-			SigHashes:     txscript.NewTxSigHashes(sigJob.Tx),
+			SigHashes:     input.NewTxSigHashesV0Only(sigJob.Tx),
 			InputIndex:    0,
 		}
 		sigJob.OutputIndex = htlc.remoteOutputIndex
@@ -3116,8 +3185,8 @@ func genRemoteHtlcSigJobs(keyRing *CommitmentKeyRing,
 // new commitment to the remote party. The commit diff returned contains all
 // information necessary for retransmission.
 func (lc *LightningChannel) createCommitDiff(
-	newCommit *commitment, commitSig lnwire.Sig,
-	htlcSigs []lnwire.Sig) (*channeldb.CommitDiff, error) {
+	newCommit *commitment, commitSig lnwire.Sig, htlcSigs []lnwire.Sig,
+	spliceSigs lnwire.SpliceSigs) (*channeldb.CommitDiff, error) {
 
 	// First, we need to convert the funding outpoint into the ID that's
 	// used on the wire to identify this channel. We'll use this shortly
@@ -3163,11 +3232,12 @@ func (lc *LightningChannel) createCommitDiff(
 		switch pd.EntryType {
 		case Add:
 			htlc := &lnwire.UpdateAddHTLC{
-				ChanID:      chanID,
-				ID:          pd.HtlcIndex,
-				Amount:      pd.Amount,
-				Expiry:      pd.Timeout,
-				PaymentHash: pd.RHash,
+				ChanID:        chanID,
+				ID:            pd.HtlcIndex,
+				Amount:        pd.Amount,
+				Expiry:        pd.Timeout,
+				PaymentHash:   pd.RHash,
+				BlindingPoint: pd.BlindingPoint,
 			}
 			copy(htlc.OnionBlob[:], pd.OnionBlob)
 			logUpdate.UpdateMsg = htlc
@@ -3239,20 +3309,32 @@ func (lc *LightningChannel) createCommitDiff(
 	// disk.
 	diskCommit := newCommit.toDiskCommit(false)
 
+	var spliceCommits map[chainhash.Hash]*channeldb.ChannelCommitment
+	for txid, spliceCommit := range newCommit.spliceCommits {
+		if spliceCommits == nil {
+			spliceCommits = make(
+				map[chainhash.Hash]*channeldb.ChannelCommitment,
+			)
+		}
+		spliceCommits[txid] = spliceCommit.toDiskCommit(false)
+	}
+
 	return &channeldb.CommitDiff{
 		Commitment: *diskCommit,
 		CommitSig: &lnwire.CommitSig{
 			ChanID: lnwire.NewChanIDFromOutPoint(
 				&lc.channelState.FundingOutpoint,
 			),
-			CommitSig: commitSig,
-			HtlcSigs:  htlcSigs,
+			CommitSig:  commitSig,
+			HtlcSigs:   htlcSigs,
+			SpliceSigs: spliceSigs,
 		},
 		LogUpdates:        logUpdates,
 		OpenedCircuitKeys: openCircuitKeys,
 		ClosedCircuitKeys: closedCircuitKeys,
 		AddAcks:           ackAddRefs,
 		SettleFailAcks:    settleFailRefs,
+		SpliceCommitments: spliceCommits,
 	}, nil
 }
 
@@ -3300,11 +3382,12 @@ func (lc *LightningChannel) getUnsignedAckedUpdates() []channeldb.LogUpdate {
 		switch pd.EntryType {
 		case Add:
 			htlc := &lnwire.UpdateAddHTLC{
-				ChanID:      chanID,
-				ID:          pd.HtlcIndex,
-				Amount:      pd.Amount,
-				Expiry:      pd.Timeout,
-				PaymentHash: pd.RHash,
+				ChanID:        chanID,
+				ID:            pd.HtlcIndex,
+				Amount:        pd.Amount,
+				Expiry:        pd.Timeout,
+				PaymentHash:   pd.RHash,
+				BlindingPoint: pd.BlindingPoint,
 			}
 			copy(htlc.OnionBlob[:], pd.OnionBlob)
 			logUpdate.UpdateMsg = htlc
@@ -3507,7 +3590,25 @@ func (lc *LightningChannel) validateCommitmentSanity(theirLogCounter,
 // any). The HTLC signatures are sorted according to the BIP 69 order of the
 // HTLC's on the commitment transaction. Finally, the new set of pending HTLCs
 // for the remote party's commitment are also returned.
+//
+// NOTE: The signatures for the commitments spending the funding outputs of
+// pending splices are only returned by SignNextCommitSig.
 func (lc *LightningChannel) SignNextCommitment() (lnwire.Sig, []lnwire.Sig, []channeldb.HTLC, error) {
+	commitSig, htlcs, err := lc.SignNextCommitSig()
+	if err != nil {
+		return lnwire.Sig{}, nil, nil, err
+	}
+
+	return commitSig.CommitSig, commitSig.HtlcSigs, htlcs, nil
+}
+
+// SignNextCommitSig signs a new commitment for the remote party in the same
+// way as SignNextCommitment, but returns the complete CommitSig message to be
+// sent to them. If the channel has pending splices, the message also carries
+// the signatures for the new commitments of the remote party spending their
+// funding outputs.
+func (lc *LightningChannel) SignNextCommitSig() (*lnwire.CommitSig,
+	[]channeldb.HTLC, error) {
 
 	lc.Lock()
 	defer lc.Unlock()
@@ -3520,11 +3621,6 @@ func (lc *LightningChannel) SignNextCommitment() (lnwire.Sig, []lnwire.Sig, []ch
 		lc.log.Errorf("sending empty commit sig")
 	}
 
-	var (
-		sig      lnwire.Sig
-		htlcSigs []lnwire.Sig
-	)
-
 	// If we're awaiting for an ACK to a commitment signature, or if we
 	// don't yet have the initial next revocation point of the remote
 	// party, then we're unable to create new states. Each time we create a
@@ -3532,7 +3628,7 @@ func (lc *LightningChannel) SignNextCommitment() (lnwire.Sig, []lnwire.Sig, []ch
 	commitPoint := lc.channelState.RemoteNextRevocation
 	if lc.remoteCommitChain.hasUnackedCommitment() || commitPoint == nil {
 
-		return sig, htlcSigs, nil, ErrNoWindow
+		return nil, nil, ErrNoWindow
 	}
 
 	// Determine the last update on the remote log that has been locked in.
@@ -3547,7 +3643,7 @@ func (lc *LightningChannel) SignNextCommitment() (lnwire.Sig, []lnwire.Sig, []ch
 		remoteACKedIndex, lc.localUpdateLog.logIndex, true, nil, nil,
 	)
 	if err != nil {
-		return sig, htlcSigs, nil, err
+		return nil, nil, err
 	}
 
 	// Grab the next commitment point for the remote party. This will be
@@ -3570,7 +3666,7 @@ func (lc *LightningChannel) SignNextCommitment() (lnwire.Sig, []lnwire.Sig, []ch
 		remoteACKedIndex, remoteHtlcIndex, keyRing,
 	)
 	if err != nil {
-		return sig, htlcSigs, nil, err
+		return nil, nil, err
 	}
 
 	lc.log.Tracef("extending remote chain to height %v, "+
@@ -3587,6 +3683,68 @@ func (lc *LightningChannel) SignNextCommitment() (lnwire.Sig, []lnwire.Sig, []ch
 		}),
 	)
 
+	sig, htlcSigs, err := lc.signCommitment(
+		newCommitView, keyRing, lc.signDesc,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The commitments spending the funding outputs of the pending splices
+	// are signed in the same way, using the funding output of the splice.
+	var spliceSigs lnwire.SpliceSigs
+	for _, splice := range lc.pendingSplices {
+		txid := splice.SpliceTx.TxHash()
+		spliceSig, spliceHtlcSigs, err := lc.signCommitment(
+			newCommitView.spliceCommits[txid], keyRing,
+			lc.spliceSignDesc(splice),
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		spliceSigs = append(spliceSigs, lnwire.SpliceSig{
+			FundingTxID: txid,
+			CommitSig:   spliceSig,
+			HtlcSigs:    spliceHtlcSigs,
+		})
+	}
+
+	// As we're about to proposer a new commitment state for the remote
+	// party, we'll write this pending state to disk before we exit, so we
+	// can retransmit it if necessary.
+	commitDiff, err := lc.createCommitDiff(
+		newCommitView, sig, htlcSigs, spliceSigs,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = lc.channelState.AppendRemoteCommitChain(commitDiff)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// TODO(roasbeef): check that one eclair bug
+	//  * need to retransmit on first state still?
+	//  * after initial reconnect
+
+	// Extend the remote commitment chain by one with the addition of our
+	// latest commitment update.
+	lc.remoteCommitChain.addCommitment(newCommitView)
+
+	return commitDiff.CommitSig, commitDiff.Commitment.Htlcs, nil
+}
+
+// signCommitment signs the passed commitment of the remote party, which spends
+// the funding output described by signDesc, returning the signature for the
+// commitment transaction and the signatures for its HTLC transactions, sorted
+// according to the BIP 69 order of the HTLC's on the commitment transaction.
+func (lc *LightningChannel) signCommitment(remoteCommitView *commitment,
+	keyRing *CommitmentKeyRing,
+	signDesc *input.SignDescriptor) (lnwire.Sig, []lnwire.Sig, error) {
+
+	var sig lnwire.Sig
+
 	// With the commitment view constructed, if there are any HTLC's, we'll
 	// need to generate signatures of each of them for the remote party's
 	// commitment state. We do so in two phases: first we generate and
@@ -3598,26 +3756,26 @@ func (lc *LightningChannel) SignNextCommitment() (lnwire.Sig, []lnwire.Sig, []ch
 	sigBatch, cancelChan, err := genRemoteHtlcSigJobs(
 		keyRing, lc.channelState.ChanType, !lc.channelState.IsInitiator,
 		leaseExpiry, &lc.channelState.LocalChanCfg,
-		&lc.channelState.RemoteChanCfg, newCommitView,
+		&lc.channelState.RemoteChanCfg, remoteCommitView,
 	)
 	if err != nil {
-		return sig, htlcSigs, nil, err
+		return sig, nil, err
 	}
 	lc.sigPool.SubmitSignBatch(sigBatch)
 
 	// While the jobs are being carried out, we'll Sign their version of
 	// the new commitment transaction while we're waiting for the rest of
 	// the HTLC signatures to be processed.
-	lc.signDesc.SigHashes = txscript.NewTxSigHashes(newCommitView.txn)
-	rawSig, err := lc.Signer.SignOutputRaw(newCommitView.txn, lc.signDesc)
+	signDesc.SigHashes = input.NewTxSigHashesV0Only(remoteCommitView.txn)
+	rawSig, err := lc.Signer.SignOutputRaw(remoteCommitView.txn, signDesc)
 	if err != nil {
 		close(cancelChan)
-		return sig, htlcSigs, nil, err
+		return sig, nil, err
 	}
 	sig, err = lnwire.NewSigFromSignature(rawSig)
 	if err != nil {
 		close(cancelChan)
-		return sig, htlcSigs, nil, err
+		return sig, nil, err
 	}
 
 	// We'll need to send over the signatures to the remote party in the
@@ -3629,7 +3787,7 @@ func (lc *LightningChannel) SignNextCommitment() (lnwire.Sig, []lnwire.Sig, []ch
 
 	// With the jobs sorted, we'll now iterate through all the responses to
 	// gather each of the signatures in order.
-	htlcSigs = make([]lnwire.Sig, 0, len(sigBatch))
+	htlcSigs := make([]lnwire.Sig, 0, len(sigBatch))
 	for _, htlcSigJob := range sigBatch {
 		jobResp := <-htlcSigJob.Resp
 
@@ -3637,33 +3795,13 @@ func (lc *LightningChannel) SignNextCommitment() (lnwire.Sig, []lnwire.Sig, []ch
 		// jobs.
 		if jobResp.Err != nil {
 			close(cancelChan)
-			return sig, htlcSigs, nil, jobResp.Err
+			return sig, nil, jobResp.Err
 		}
 
 		htlcSigs = append(htlcSigs, jobResp.Sig)
 	}
 
-	// As we're about to proposer a new commitment state for the remote
-	// party, we'll write this pending state to disk before we exit, so we
-	// can retransmit it if necessary.
-	commitDiff, err := lc.createCommitDiff(newCommitView, sig, htlcSigs)
-	if err != nil {
-		return sig, htlcSigs, nil, err
-	}
-	err = lc.channelState.AppendRemoteCommitChain(commitDiff)
-	if err != nil {
-		return sig, htlcSigs, nil, err
-	}
-
-	// TODO(roasbeef): check that one eclair bug
-	//  * need to retransmit on first state still?
-	//  * after initial reconnect
-
-	// Extend the remote commitment chain by one with the addition of our
-	// latest commitment update.
-	lc.remoteCommitChain.addCommitment(newCommitView)
-
-	return sig, htlcSigs, commitDiff.Commitment.Htlcs, nil
+	return sig, htlcSigs, nil
 }
 
 // ProcessChanSyncMsg processes a ChannelReestablish message sent by the remote
@@ -3821,19 +3959,13 @@ func (lc *LightningChannel) ProcessChanSyncMsg(
 		// revocation, but also initiate a state transition to re-sync
 		// them.
 		if lc.OweCommitment(true) {
-			commitSig, htlcSigs, _, err := lc.SignNextCommitment()
+			commitSig, _, err := lc.SignNextCommitSig()
 			switch {
 
 			// If we signed this state, then we'll accumulate
 			// another update to send over.
 			case err == nil:
-				updates = append(updates, &lnwire.CommitSig{
-					ChanID: lnwire.NewChanIDFromOutPoint(
-						&lc.channelState.FundingOutpoint,
-					),
-					CommitSig: commitSig,
-					HtlcSigs:  htlcSigs,
-				})
+				updates = append(updates, commitSig)
 
 			// If we get a failure due to not knowing their next
 			// point, then this is fine as they'll either send
@@ -4139,7 +4271,9 @@ func genHtlcSigValidationJobs(localCommitmentView *commitment,
 					return nil, err
 				}
 
-				hashCache := txscript.NewTxSigHashes(successTx)
+				hashCache := input.NewTxSigHashesV0Only(
+					successTx,
+				)
 				sigHash, err := txscript.CalcWitnessSigHash(
 					htlc.ourWitnessScript, hashCache,
 					sigHashType, successTx, 0,
@@ -4194,7 +4328,9 @@ func genHtlcSigValidationJobs(localCommitmentView *commitment,
 					return nil, err
 				}
 
-				hashCache := txscript.NewTxSigHashes(timeoutTx)
+				hashCache := input.NewTxSigHashesV0Only(
+					timeoutTx,
+				)
 				sigHash, err := txscript.CalcWitnessSigHash(
 					htlc.ourWitnessScript, hashCache,
 					sigHashType, timeoutTx, 0,
@@ -4310,9 +4446,24 @@ var _ error = (*InvalidCommitSigError)(nil)
 // to our local commitment chain. Once we send a revocation for our prior
 // state, then this newly added commitment becomes our current accepted channel
 // state.
+//
+// NOTE: If the channel has pending splices, ReceiveCommitSig must be used, as
+// the signatures for the commitments spending their funding outputs are
+// required as well.
 func (lc *LightningChannel) ReceiveNewCommitment(commitSig lnwire.Sig,
 	htlcSigs []lnwire.Sig) error {
 
+	return lc.ReceiveCommitSig(&lnwire.CommitSig{
+		CommitSig: commitSig,
+		HtlcSigs:  htlcSigs,
+	})
+}
+
+// ReceiveCommitSig processes a CommitSig message of the remote party in the
+// same way as ReceiveNewCommitment. If the channel has pending splices, the
+// message must also carry valid signatures for our new commitments spending
+// their funding outputs.
+func (lc *LightningChannel) ReceiveCommitSig(msg *lnwire.CommitSig) error {
 	lc.Lock()
 	defer lc.Unlock()
 
@@ -4383,14 +4534,62 @@ func (lc *LightningChannel) ReceiveNewCommitment(commitSig lnwire.Sig,
 		}),
 	)
 
+	err = lc.verifyCommitment(
+		localCommitmentView, keyRing, msg.CommitSig, msg.HtlcSigs,
+		lc.channelState.Capacity,
+	)
+	if err != nil {
+		return err
+	}
+
+	// Our commitments spending the funding outputs of the pending splices
+	// must be signed as well.
+	if len(msg.SpliceSigs) != len(lc.pendingSplices) {
+		return fmt.Errorf("expected signatures for %v pending "+
+			"splices, got %v", len(lc.pendingSplices),
+			len(msg.SpliceSigs))
+	}
+	for _, splice := range lc.pendingSplices {
+		txid := splice.SpliceTx.TxHash()
+		spliceSig := findSpliceSig(msg.SpliceSigs, txid)
+		if spliceSig == nil {
+			return fmt.Errorf("missing signature for pending "+
+				"splice %v", txid)
+		}
+
+		err := lc.verifyCommitment(
+			localCommitmentView.spliceCommits[txid], keyRing,
+			spliceSig.CommitSig, spliceSig.HtlcSigs,
+			splice.Capacity,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	// The signatures check out, so we can now add the new commitment to
+	// our local commitment chain.
+	lc.localCommitChain.addCommitment(localCommitmentView)
+
+	return nil
+}
+
+// verifyCommitment verifies the signature of the remote party for the passed
+// commitment of ours, which spends a funding output of the given capacity,
+// along with the signatures for its HTLC transactions. If all of them are
+// valid, the signatures are stored within the commitment.
+func (lc *LightningChannel) verifyCommitment(localCommitmentView *commitment,
+	keyRing *CommitmentKeyRing, commitSig lnwire.Sig,
+	htlcSigs []lnwire.Sig, capacity bronutil.Amount) error {
+
 	// Construct the sighash of the commitment transaction corresponding to
 	// this newly proposed state update.
 	localCommitTx := localCommitmentView.txn
 	multiSigScript := lc.signDesc.WitnessScript
-	hashCache := txscript.NewTxSigHashes(localCommitTx)
+	hashCache := input.NewTxSigHashesV0Only(localCommitTx)
 	sigHash, err := txscript.CalcWitnessSigHash(
 		multiSigScript, hashCache, txscript.SigHashAll,
-		localCommitTx, 0, int64(lc.channelState.Capacity),
+		localCommitTx, 0, int64(capacity),
 	)
 	if err != nil {
 		// TODO(roasbeef): fetchview has already mutated the HTLCs...
@@ -4440,7 +4639,7 @@ func (lc *LightningChannel) ReceiveNewCommitment(commitSig lnwire.Sig,
 		var txBytes bytes.Buffer
 		localCommitTx.Serialize(&txBytes)
 		return &InvalidCommitSigError{
-			commitHeight: nextHeight,
+			commitHeight: localCommitmentView.height,
 			commitSig:    commitSig.ToSignatureBytes(),
 			sigHash:      sigHash,
 			commitTx:     txBytes.Bytes(),
@@ -4470,7 +4669,7 @@ func (lc *LightningChannel) ReceiveNewCommitment(commitSig lnwire.Sig,
 			var txBytes bytes.Buffer
 			localCommitTx.Serialize(&txBytes)
 			return &InvalidHtlcSigError{
-				commitHeight: nextHeight,
+				commitHeight: localCommitmentView.height,
 				htlcSig:      sig.ToSignatureBytes(),
 				htlcIndex:    htlcErr.HtlcIndex,
 				sigHash:      sigHash,
@@ -4479,10 +4678,7 @@ func (lc *LightningChannel) ReceiveNewCommitment(commitSig lnwire.Sig,
 		}
 	}
 
-	// The signature checks out, so we can now add the new commitment to
-	// our local commitment chain.
 	localCommitmentView.sig = commitSig.ToSignatureBytes()
-	lc.localCommitChain.addCommitment(localCommitmentView)
 
 	return nil
 }
@@ -4637,13 +4833,25 @@ func (lc *LightningChannel) RevokeCurrentCommitment() (*lnwire.RevokeAndAck, []c
 	chainTail := lc.localCommitChain.tail()
 	newCommitment := chainTail.toDiskCommit(true)
 
+	// Our commitments for the same state spending the funding outputs of
+	// the pending splices become current as well.
+	var spliceCommits map[chainhash.Hash]*channeldb.ChannelCommitment
+	for txid, spliceCommit := range chainTail.spliceCommits {
+		if spliceCommits == nil {
+			spliceCommits = make(
+				map[chainhash.Hash]*channeldb.ChannelCommitment,
+			)
+		}
+		spliceCommits[txid] = spliceCommit.toDiskCommit(true)
+	}
+
 	// Get the unsigned acked remotes updates that are currently in memory.
 	// We need them after a restart to sync our remote commitment with what
 	// is committed locally.
 	unsignedAckedUpdates := lc.getUnsignedAckedUpdates()
 
 	err = lc.channelState.UpdateCommitment(
-		newCommitment, unsignedAckedUpdates,
+		newCommitment, unsignedAckedUpdates, spliceCommits,
 	)
 	if err != nil {
 		return nil, nil, err
@@ -4821,11 +5029,12 @@ func (lc *LightningChannel) ReceiveRevocation(revMsg *lnwire.RevokeAndAck) (
 		switch pd.EntryType {
 		case Add:
 			htlc := &lnwire.UpdateAddHTLC{
-				ChanID:      chanID,
-				ID:          pd.HtlcIndex,
-				Amount:      pd.Amount,
-				Expiry:      pd.Timeout,
-				PaymentHash: pd.RHash,
+				ChanID:        chanID,
+				ID:            pd.HtlcIndex,
+				Amount:        pd.Amount,
+				Expiry:        pd.Timeout,
+				PaymentHash:   pd.RHash,
+				BlindingPoint: pd.BlindingPoint,
 			}
 			copy(htlc.OnionBlob[:], pd.OnionBlob)
 			logUpdate.UpdateMsg = htlc
@@ -5113,6 +5322,7 @@ func (lc *LightningChannel) htlcAddDescriptor(htlc *lnwire.UpdateAddHTLC,
 		LogIndex:       lc.localUpdateLog.logIndex,
 		HtlcIndex:      lc.localUpdateLog.htlcCounter,
 		OnionBlob:      htlc.OnionBlob[:],
+		BlindingPoint:  htlc.BlindingPoint,
 		OpenCircuitKey: openKey,
 	}
 }
@@ -5162,13 +5372,14 @@ func (lc *LightningChannel) ReceiveHTLC(htlc *lnwire.UpdateAddHTLC) (uint64, err
 	}
 
 	pd := &PaymentDescriptor{
-		EntryType: Add,
-		RHash:     PaymentHash(htlc.PaymentHash),
-		Timeout:   htlc.Expiry,
-		Amount:    htlc.Amount,
-		LogIndex:  lc.remoteUpdateLog.logIndex,
-		HtlcIndex: lc.remoteUpdateLog.htlcCounter,
-		OnionBlob: htlc.OnionBlob[:],
+		EntryType:     Add,
+		RHash:         PaymentHash(htlc.PaymentHash),
+		Timeout:       htlc.Expiry,
+		Amount:        htlc.Amount,
+		LogIndex:      lc.remoteUpdateLog.logIndex,
+		HtlcIndex:     lc.remoteUpdateLog.htlcCounter,
+		OnionBlob:     htlc.OnionBlob[:],
+		BlindingPoint: htlc.BlindingPoint,
 	}
 
 	localACKedIndex := lc.remoteCommitChain.tail().ourMessageIndex
@@ -5493,7 +5704,7 @@ func (lc *LightningChannel) getSignedCommitTx() (*wire.MsgTx, error) {
 
 	// With this, we then generate the full witness so the caller can
 	// broadcast a fully signed transaction.
-	lc.signDesc.SigHashes = txscript.NewTxSigHashes(commitTx)
+	lc.signDesc.SigHashes = input.NewTxSigHashesV0Only(commitTx)
 	ourSig, err := lc.Signer.SignOutputRaw(commitTx, lc.signDesc)
 	if err != nil {
 		return nil, err
@@ -5901,7 +6112,7 @@ func newOutgoingHtlcResolution(signer input.Signer,
 		WitnessScript: htlcScript,
 		Output:        txOut,
 		HashType:      txscript.SigHashAll,
-		SigHashes:     txscript.NewTxSigHashes(timeoutTx),
+		SigHashes:     input.NewTxSigHashesV0Only(timeoutTx),
 		InputIndex:    0,
 	}
 
@@ -6036,7 +6247,7 @@ func newIncomingHtlcResolution(signer input.Signer,
 		WitnessScript: htlcScript,
 		Output:        txOut,
 		HashType:      txscript.SigHashAll,
-		SigHashes:     txscript.NewTxSigHashes(successTx),
+		SigHashes:     input.NewTxSigHashesV0Only(successTx),
 		InputIndex:    0,
 	}
 
@@ -6458,7 +6669,7 @@ func (lc *LightningChannel) CreateCloseProposal(proposedFee bronutil.Amount,
 	// initiator we'll simply send our signature over to the remote party,
 	// using the generated txid to be notified once the closure transaction
 	// has been confirmed.
-	lc.signDesc.SigHashes = txscript.NewTxSigHashes(closeTx)
+	lc.signDesc.SigHashes = input.NewTxSigHashesV0Only(closeTx)
 	sig, err := lc.Signer.SignOutputRaw(closeTx, lc.signDesc)
 	if err != nil {
 		return nil, nil, 0, err
@@ -6518,10 +6729,33 @@ func (lc *LightningChannel) CompleteCooperativeClose(
 	if err := blockchain.CheckTransactionSanity(tx); err != nil {
 		return nil, 0, err
 	}
-	hashCache := txscript.NewTxSigHashes(closeTx)
 
-	// Finally, construct the witness stack minding the order of the
-	// pubkeys+sigs on the stack.
+	// Finally, add the witness and validate the transaction.
+	if err := lc.finalizeCloseTx(closeTx, localSig, remoteSig); err != nil {
+		return nil, 0, err
+	}
+
+	// As the transaction is sane, and the scripts are valid we'll mark the
+	// channel now as closed as the closure transaction should get into the
+	// chain in a timely manner and possibly be re-broadcast by the wallet.
+	lc.status = channelClosed
+
+	return closeTx, ourBalance, nil
+}
+
+// finalizeCloseTx adds the witness spending the funding output with the
+// passed signatures to a cooperative closing transaction, and validates the
+// resulting transaction to ensure the remote party supplied a valid
+// signature.
+//
+// NOTE: This method MUST be called with the channel's lock held.
+func (lc *LightningChannel) finalizeCloseTx(closeTx *wire.MsgTx,
+	localSig, remoteSig input.Signature) error {
+
+	hashCache := input.NewTxSigHashesV0Only(closeTx)
+
+	// Construct the witness stack minding the order of the pubkeys+sigs on
+	// the stack.
 	ourKey := lc.channelState.LocalChanCfg.MultiSigKey.PubKey.
 		SerializeCompressed()
 	theirKey := lc.channelState.RemoteChanCfg.MultiSigKey.PubKey.
@@ -6535,21 +6769,18 @@ func (lc *LightningChannel) CompleteCooperativeClose(
 	// Validate the finalized transaction to ensure the output script is
 	// properly met, and that the remote peer supplied a valid signature.
 	prevOut := lc.signDesc.Output
-	vm, err := txscript.NewEngine(prevOut.PkScript, closeTx, 0,
-		txscript.StandardVerifyFlags, nil, hashCache, prevOut.Value)
+	prevOutFetcher := txscript.NewCannedPrevOutputFetcher(
+		prevOut.PkScript, prevOut.Value,
+	)
+	vm, err := txscript.NewEngine(
+		prevOut.PkScript, closeTx, 0, txscript.StandardVerifyFlags,
+		nil, hashCache, prevOut.Value, prevOutFetcher,
+	)
 	if err != nil {
-		return nil, 0, err
-	}
-	if err := vm.Execute(); err != nil {
-		return nil, 0, err
+		return err
 	}
 
-	// As the transaction is sane, and the scripts are valid we'll mark the
-	// channel now as closed as the closure transaction should get into the
-	// chain in a timely manner and possibly be re-broadcast by the wallet.
-	lc.status = channelClosed
-
-	return closeTx, ourBalance, nil
+	return vm.Execute()
 }
 
 // AnchorResolutions is a set of anchor resolutions that's being used when
@@ -802,6 +802,106 @@ func testCoopClose(t *testing.T, testCase *coopCloseTestCase) {
 	}
 }
 
+// TestRbfCooperativeChannelClosure tests that an RBF cooperative close paid
+// by the non-initiator of the channel can be completed by both parties, and
+// that the closer can replace the completed closing transaction with one
+// paying a higher fee.
+func TestRbfCooperativeChannelClosure(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, bobChannel, cleanUp, err := CreateTestChannels(
+		channeldb.SingleFunderTweaklessBit,
+	)
+	require.NoError(t, err)
+	defer cleanUp()
+
+	aliceDeliveryScript := bobsPrivKey[:]
+	bobDeliveryScript := testHdSeed[:]
+
+	const lockTime = 100
+
+	// closeWithFee lets Bob, who didn't initiate the channel, propose a
+	// closing transaction paying the given fee, and asserts that both
+	// parties arrive at the same fully signed transaction.
+	closeWithFee := func(fee bronutil.Amount) *wire.MsgTx {
+		bobSig, bobTx, err := bobChannel.CreateRbfCloseProposal(
+			fee, lockTime, bobDeliveryScript, aliceDeliveryScript,
+			true,
+		)
+		require.NoError(t, err)
+
+		aliceSig, aliceTx, err := aliceChannel.CreateRbfCloseProposal(
+			fee, lockTime, aliceDeliveryScript, bobDeliveryScript,
+			false,
+		)
+		require.NoError(t, err)
+		require.Equal(t, bobTx.TxHash(), aliceTx.TxHash())
+
+		aliceCloseTx, err := aliceChannel.CompleteRbfCooperativeClose(
+			aliceSig, bobSig, fee, lockTime, aliceDeliveryScript,
+			bobDeliveryScript, false,
+		)
+		require.NoError(t, err)
+
+		bobCloseTx, err := bobChannel.CompleteRbfCooperativeClose(
+			bobSig, aliceSig, fee, lockTime, bobDeliveryScript,
+			aliceDeliveryScript, true,
+		)
+		require.NoError(t, err)
+		require.Equal(t, aliceCloseTx.TxHash(), bobCloseTx.TxHash())
+
+		require.EqualValues(t, lockTime, bobCloseTx.LockTime)
+		require.EqualValues(
+			t, RbfCloseSequence, bobCloseTx.TxIn[0].Sequence,
+		)
+
+		return bobCloseTx
+	}
+
+	outputValue := func(tx *wire.MsgTx, script []byte) bronutil.Amount {
+		for _, txOut := range tx.TxOut {
+			if bytes.Equal(txOut.PkScript, script) {
+				return bronutil.Amount(txOut.Value)
+			}
+		}
+
+		t.Fatalf("output with script %x not found", script)
+		return 0
+	}
+
+	// Alice initiated the channel, so she gets back the commitment fee,
+	// while Bob pays the full closing fee.
+	aliceBalance := aliceChannel.channelState.LocalCommitment.
+		LocalBalance.ToBroneess() +
+		aliceChannel.channelState.LocalCommitment.CommitFee
+	bobBalance := bobChannel.channelState.LocalCommitment.
+		LocalBalance.ToBroneess()
+
+	const fee = 5000
+	closeTx := closeWithFee(fee)
+	require.Equal(t, aliceBalance, outputValue(closeTx, aliceDeliveryScript))
+	require.Equal(t, bobBalance-fee, outputValue(closeTx, bobDeliveryScript))
+
+	// Bob can now bump the fee by replacing the closing transaction, which
+	// again only affects his own output.
+	const bumpedFee = 2 * fee
+	bumpedTx := closeWithFee(bumpedFee)
+	require.NotEqual(t, closeTx.TxHash(), bumpedTx.TxHash())
+	require.Equal(
+		t, aliceBalance, outputValue(bumpedTx, aliceDeliveryScript),
+	)
+	require.Equal(
+		t, bobBalance-bumpedFee, outputValue(bumpedTx, bobDeliveryScript),
+	)
+
+	// A fee exceeding Bob's balance can't be paid by him.
+	_, _, err = bobChannel.CreateRbfCloseProposal(
+		bobBalance+1, lockTime, bobDeliveryScript, aliceDeliveryScript,
+		true,
+	)
+	require.Error(t, err)
+}
+
 // TestForceClose checks that the resulting ForceCloseSummary is correct when a
 // peer is ForceClosing the channel. Will check outputs both above and below
 // the dust limit. Additionally, we'll ensure that the node which executed the
@@ -990,9 +1090,12 @@ func testForceClose(t *testing.T, testCase *forceCloseTestCase) {
 	// the multi-sig clause within the output on the commitment transaction
 	// that produces this HTLC.
 	timeoutTx := htlcResolution.SignedTimeoutTx
+	prevOutFetcher := txscript.NewCannedPrevOutputFetcher(
+		senderHtlcPkScript, int64(htlcAmount.ToBroneess()),
+	)
 	vm, err := txscript.NewEngine(senderHtlcPkScript,
 		timeoutTx, 0, txscript.StandardVerifyFlags, nil,
-		nil, int64(htlcAmount.ToBroneess()))
+		nil, int64(htlcAmount.ToBroneess()), prevOutFetcher)
 	if err != nil {
 		t.Fatalf("unable to create engine: %v", err)
 	}
@@ -1024,10 +1127,14 @@ func testForceClose(t *testing.T, testCase *forceCloseTestCase) {
 	// With the witness fully populated for the success spend from the
 	// second-level transaction, we ensure that the scripts properly
 	// validate given the information within the htlc resolution struct.
+	prevOutFetcher = txscript.NewCannedPrevOutputFetcher(
+		htlcResolution.SweepSignDesc.Output.PkScript,
+		htlcResolution.SweepSignDesc.Output.Value,
+	)
 	vm, err = txscript.NewEngine(
 		htlcResolution.SweepSignDesc.Output.PkScript,
 		sweepTx, 0, txscript.StandardVerifyFlags, nil,
-		nil, htlcResolution.SweepSignDesc.Output.Value,
+		nil, htlcResolution.SweepSignDesc.Output.Value, prevOutFetcher,
 	)
 	if err != nil {
 		t.Fatalf("unable to create engine: %v", err)
@@ -1057,9 +1164,12 @@ func testForceClose(t *testing.T, testCase *forceCloseTestCase) {
 	// before publication.
 	successTx := inHtlcResolution.SignedSuccessTx
 	successTx.TxIn[0].Witness[3] = preimageBob[:]
+	prevOutFetcher = txscript.NewCannedPrevOutputFetcher(
+		receiverHtlcScript, int64(htlcAmount.ToBroneess()),
+	)
 	vm, err = txscript.NewEngine(receiverHtlcScript,
 		successTx, 0, txscript.StandardVerifyFlags, nil,
-		nil, int64(htlcAmount.ToBroneess()))
+		nil, int64(htlcAmount.ToBroneess()), prevOutFetcher)
 	if err != nil {
 		t.Fatalf("unable to create engine: %v", err)
 	}
@@ -1087,10 +1197,15 @@ func testForceClose(t *testing.T, testCase *forceCloseTestCase) {
 
 	// The spend we create above spending the second level HTLC output
 	// should validate without any issues.
+	prevOutFetcher = txscript.NewCannedPrevOutputFetcher(
+		inHtlcResolution.SweepSignDesc.Output.PkScript,
+		inHtlcResolution.SweepSignDesc.Output.Value,
+	)
 	vm, err = txscript.NewEngine(
 		inHtlcResolution.SweepSignDesc.Output.PkScript,
 		sweepTx, 0, txscript.StandardVerifyFlags, nil,
 		nil, inHtlcResolution.SweepSignDesc.Output.Value,
+		prevOutFetcher,
 	)
 	if err != nil {
 		t.Fatalf("unable to create engine: %v", err)
@@ -5960,7 +6075,7 @@ func TestChannelUnilateralCloseHtlcResolution(t *testing.T) {
 		Value:    outHtlcResolution.SweepSignDesc.Output.Value,
 	})
 	outHtlcResolution.SweepSignDesc.InputIndex = 0
-	outHtlcResolution.SweepSignDesc.SigHashes = txscript.NewTxSigHashes(
+	outHtlcResolution.SweepSignDesc.SigHashes = input.NewTxSigHashesV0Only(
 		sweepTx,
 	)
 	sweepTx.LockTime = outHtlcResolution.Expiry
@@ -5974,10 +6089,15 @@ func TestChannelUnilateralCloseHtlcResolution(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unable to witness: %v", err)
 	}
+	prevOutFetcher := txscript.NewCannedPrevOutputFetcher(
+		outHtlcResolution.SweepSignDesc.Output.PkScript,
+		outHtlcResolution.SweepSignDesc.Output.Value,
+	)
 	vm, err := txscript.NewEngine(
 		outHtlcResolution.SweepSignDesc.Output.PkScript,
 		sweepTx, 0, txscript.StandardVerifyFlags, nil,
 		nil, outHtlcResolution.SweepSignDesc.Output.Value,
+		prevOutFetcher,
 	)
 	if err != nil {
 		t.Fatalf("unable to create engine: %v", err)
@@ -5998,7 +6118,7 @@ func TestChannelUnilateralCloseHtlcResolution(t *testing.T) {
 		Value:    inHtlcResolution.SweepSignDesc.Output.Value,
 	})
 	inHtlcResolution.SweepSignDesc.InputIndex = 0
-	inHtlcResolution.SweepSignDesc.SigHashes = txscript.NewTxSigHashes(
+	inHtlcResolution.SweepSignDesc.SigHashes = input.NewTxSigHashesV0Only(
 		sweepTx,
 	)
 	sweepTx.TxIn[0].Witness, err = input.SenderHtlcSpendRedeem(
@@ -6012,10 +6132,15 @@ func TestChannelUnilateralCloseHtlcResolution(t *testing.T) {
 
 	// Finally, we'll verify the constructed witness to ensure that Alice
 	// can properly sweep the output.
+	prevOutFetcher = txscript.NewCannedPrevOutputFetcher(
+		inHtlcResolution.SweepSignDesc.Output.PkScript,
+		inHtlcResolution.SweepSignDesc.Output.Value,
+	)
 	vm, err = txscript.NewEngine(
 		inHtlcResolution.SweepSignDesc.Output.PkScript,
 		sweepTx, 0, txscript.StandardVerifyFlags, nil,
 		nil, inHtlcResolution.SweepSignDesc.Output.Value,
+		prevOutFetcher,
 	)
 	if err != nil {
 		t.Fatalf("unable to create engine: %v", err)
@@ -6133,7 +6258,7 @@ func TestChannelUnilateralClosePendingCommit(t *testing.T) {
 		PkScript: testHdSeed[:],
 		Value:    aliceSignDesc.Output.Value,
 	})
-	aliceSignDesc.SigHashes = txscript.NewTxSigHashes(sweepTx)
+	aliceSignDesc.SigHashes = input.NewTxSigHashesV0Only(sweepTx)
 	sweepTx.TxIn[0].Witness, err = input.CommitSpendNoDelay(
 		aliceChannel.Signer, &aliceSignDesc, sweepTx, false,
 	)
@@ -6143,10 +6268,13 @@ func TestChannelUnilateralClosePendingCommit(t *testing.T) {
 
 	// If we validate the signature on the new sweep transaction, it should
 	// be fully valid.
+	prevOutFetcher := txscript.NewCannedPrevOutputFetcher(
+		aliceSignDesc.Output.PkScript, aliceSignDesc.Output.Value,
+	)
 	vm, err := txscript.NewEngine(
 		aliceSignDesc.Output.PkScript, sweepTx, 0,
 		txscript.StandardVerifyFlags, nil, nil,
-		aliceSignDesc.Output.Value,
+		aliceSignDesc.Output.Value, prevOutFetcher,
 	)
 	if err != nil {
 		t.Fatalf("unable to create engine: %v", err)
@@ -186,9 +186,13 @@ func Validate(ctx *Context) (*wire.OutPoint, error) {
 	// If we reach this point, then all other checks have succeeded, so
 	// we'll now attempt a full Script VM execution to ensure that we're
 	// able to close the channel using this initial state.
+	prevOutFetcher := txscript.NewCannedPrevOutputFetcher(
+		ctx.MultiSigPkScript, fundingValue,
+	)
 	vm, err := txscript.NewEngine(
 		ctx.MultiSigPkScript, ctx.CommitCtx.FullySignedCommitTx,
 		0, txscript.StandardVerifyFlags, nil, nil, fundingValue,
+		prevOutFetcher,
 	)
 	if err != nil {
 		return nil, err
@@ -536,12 +536,15 @@ type unsignedCommitmentTx struct {
 // createUnsignedCommitmentTx generates the unsigned commitment transaction for
 // a commitment view and returns it as part of the unsignedCommitmentTx. The
 // passed in balances should be balances *before* subtracting any commitment
-// fees, but after anchor outputs.
+// fees, but after anchor outputs. The commitment spends the funding output of
+// the given capacity referenced by fundingTxIn, which is the current funding
+// output of the channel unless the commitment is for a pending splice.
 func (cb *CommitmentBuilder) createUnsignedCommitmentTx(ourBalance,
 	theirBalance lnwire.MilliBronees, isOurs bool,
 	feePerKw chainfee.SatPerKWeight, height uint64,
-	filteredHTLCView *htlcView,
-	keyRing *CommitmentKeyRing) (*unsignedCommitmentTx, error) {
+	filteredHTLCView *htlcView, keyRing *CommitmentKeyRing,
+	fundingTxIn wire.TxIn,
+	capacity bronutil.Amount) (*unsignedCommitmentTx, error) {
 
 	dustLimit := cb.chanState.LocalChanCfg.DustLimit
 	if !isOurs {
@@ -615,14 +618,14 @@ func (cb *CommitmentBuilder) createUnsignedCommitmentTx(ourBalance,
 	}
 	if isOurs {
 		commitTx, err = CreateCommitTx(
-			cb.chanState.ChanType, fundingTxIn(cb.chanState), keyRing,
+			cb.chanState.ChanType, fundingTxIn, keyRing,
 			&cb.chanState.LocalChanCfg, &cb.chanState.RemoteChanCfg,
 			ourBalance.ToBroneess(), theirBalance.ToBroneess(),
 			numHTLCs, cb.chanState.IsInitiator, leaseExpiry,
 		)
 	} else {
 		commitTx, err = CreateCommitTx(
-			cb.chanState.ChanType, fundingTxIn(cb.chanState), keyRing,
+			cb.chanState.ChanType, fundingTxIn, keyRing,
 			&cb.chanState.RemoteChanCfg, &cb.chanState.LocalChanCfg,
 			theirBalance.ToBroneess(), ourBalance.ToBroneess(),
 			numHTLCs, !cb.chanState.IsInitiator, leaseExpiry,
@@ -703,11 +706,11 @@ func (cb *CommitmentBuilder) createUnsignedCommitmentTx(ourBalance,
 	for _, txOut := range commitTx.TxOut {
 		totalOut += bronutil.Amount(txOut.Value)
 	}
-	if totalOut+commitFee > cb.chanState.Capacity {
+	if totalOut+commitFee > capacity {
 		return nil, fmt.Errorf("height=%v, for ChannelPoint(%v) "+
 			"attempts to consume %v while channel capacity is %v",
-			height, cb.chanState.FundingOutpoint,
-			totalOut+commitFee, cb.chanState.Capacity)
+			height, fundingTxIn.PreviousOutPoint,
+			totalOut+commitFee, capacity)
 	}
 
 	return &unsignedCommitmentTx{
@@ -848,6 +851,38 @@ func CoopCloseBalance(chanType channeldb.ChannelType, isInitiator bool,
 	return ourBalance, theirBalance, nil
 }
 
+// RbfCoopCloseBalance returns the settled balances of both parties in an RBF
+// cooperative close. In contrast to CoopCloseBalance, the fee of the closing
+// transaction is paid in full by the closer, which is us if localCloser is
+// true, regardless of who initiated the channel.
+func RbfCoopCloseBalance(chanType channeldb.ChannelType, isInitiator,
+	localCloser bool, closeFee bronutil.Amount,
+	localCommit channeldb.ChannelCommitment) (bronutil.Amount,
+	bronutil.Amount, error) {
+
+	// Return the dangling commitment fee and anchors to the initiator
+	// without subtracting any fee.
+	ourBalance, theirBalance, err := CoopCloseBalance(
+		chanType, isInitiator, 0, localCommit,
+	)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if localCloser {
+		ourBalance -= closeFee
+	} else {
+		theirBalance -= closeFee
+	}
+
+	if ourBalance < 0 || theirBalance < 0 {
+		return 0, 0, fmt.Errorf("closer cannot afford proposed coop " +
+			"close fee")
+	}
+
+	return ourBalance, theirBalance, nil
+}
+
 // genHtlcScript generates the proper P2WSH public key scripts for the HTLC
 // output modified by two-bits denoting if this is an incoming HTLC, and if the
 // HTLC is being applied to their commitment transaction or ours.
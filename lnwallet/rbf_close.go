@@ -0,0 +1,133 @@
+package lnwallet
+
+import (
+	"github.com/brronsuite/broln/input"
+	"github.com/brronsuite/broln/lnwallet/chainfee"
+	"github.com/brronsuite/brond/blockchain"
+	"github.com/brronsuite/brond/wire"
+	"github.com/brronsuite/bronutil"
+)
+
+// RbfCloseSequence is the sequence of the funding input of an RBF cooperative
+// closing transaction. It signals replaceability and enforces the lock time
+// of the transaction.
+const RbfCloseSequence = wire.MaxTxInSequenceNum - 2
+
+// rbfCloseTx creates an RBF cooperative closing transaction paying the passed
+// fee from the output of the closer, which is us if localCloser is true.
+//
+// NOTE: This method MUST be called with the channel's lock held.
+func (lc *LightningChannel) rbfCloseTx(fee bronutil.Amount, lockTime uint32,
+	localDeliveryScript, remoteDeliveryScript []byte,
+	localCloser bool) (*wire.MsgTx, error) {
+
+	ourBalance, theirBalance, err := RbfCoopCloseBalance(
+		lc.channelState.ChanType, lc.channelState.IsInitiator,
+		localCloser, fee, lc.channelState.LocalCommitment,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	txIn := fundingTxIn(lc.channelState)
+	txIn.Sequence = RbfCloseSequence
+
+	closeTx := CreateCooperativeCloseTx(
+		txIn, lc.channelState.LocalChanCfg.DustLimit,
+		lc.channelState.RemoteChanCfg.DustLimit, ourBalance,
+		theirBalance, localDeliveryScript, remoteDeliveryScript,
+	)
+	closeTx.LockTime = lockTime
+
+	// Ensure that the transaction doesn't explicitly violate any
+	// consensus rules such as being too big, or having any value with a
+	// negative output.
+	tx := bronutil.NewTx(closeTx)
+	if err := blockchain.CheckTransactionSanity(tx); err != nil {
+		return nil, err
+	}
+
+	return closeTx, nil
+}
+
+// RbfCloseFee returns the fee of an RBF cooperative closing transaction with
+// outputs to the passed delivery scripts at the given fee rate.
+func RbfCloseFee(feeRate chainfee.SatPerKWeight, localDeliveryScript,
+	remoteDeliveryScript []byte) bronutil.Amount {
+
+	var weightEstimate input.TxWeightEstimator
+	weightEstimate.AddWitnessInput(input.MultiSigWitnessSize)
+	weightEstimate.AddTxOutput(&wire.TxOut{PkScript: localDeliveryScript})
+	weightEstimate.AddTxOutput(&wire.TxOut{PkScript: remoteDeliveryScript})
+
+	return feeRate.FeeForWeight(int64(weightEstimate.Weight()))
+}
+
+// CreateRbfCloseProposal creates and signs an RBF cooperative closing
+// transaction. In contrast to CreateCloseProposal, the fee is paid in full by
+// the closer, which is us if localCloser is true, and the funding input
+// signals replaceability. As a closing transaction can be replaced by one
+// with a higher fee at any time, this may also be called once a prior closing
+// transaction was completed. The signature and the closing transaction are
+// returned.
+func (lc *LightningChannel) CreateRbfCloseProposal(fee bronutil.Amount,
+	lockTime uint32, localDeliveryScript, remoteDeliveryScript []byte,
+	localCloser bool) (input.Signature, *wire.MsgTx, error) {
+
+	lc.Lock()
+	defer lc.Unlock()
+
+	closeTx, err := lc.rbfCloseTx(
+		fee, lockTime, localDeliveryScript, remoteDeliveryScript,
+		localCloser,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	lc.signDesc.SigHashes = input.NewTxSigHashesV0Only(closeTx)
+	sig, err := lc.Signer.SignOutputRaw(closeTx, lc.signDesc)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// A closing transaction may have been completed already, in which
+	// case the channel remains closed.
+	if lc.status != channelClosed {
+		lc.status = channelClosing
+	}
+
+	return sig, closeTx, nil
+}
+
+// CompleteRbfCooperativeClose completes an RBF cooperative closing
+// transaction created with the same parameters by CreateRbfCloseProposal,
+// verifying the remote party's signature. The fully signed closing
+// transaction is returned.
+//
+// NOTE: The passed local and remote sigs are expected to be fully complete
+// signatures including the proper sighash byte.
+func (lc *LightningChannel) CompleteRbfCooperativeClose(localSig,
+	remoteSig input.Signature, fee bronutil.Amount, lockTime uint32,
+	localDeliveryScript, remoteDeliveryScript []byte,
+	localCloser bool) (*wire.MsgTx, error) {
+
+	lc.Lock()
+	defer lc.Unlock()
+
+	closeTx, err := lc.rbfCloseTx(
+		fee, lockTime, localDeliveryScript, remoteDeliveryScript,
+		localCloser,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := lc.finalizeCloseTx(closeTx, localSig, remoteSig); err != nil {
+		return nil, err
+	}
+
+	lc.status = channelClosed
+
+	return closeTx, nil
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/brronsuite/broln/lnwire"
 	"github.com/brronsuite/brond/bronec"
 	"github.com/brronsuite/brond/chaincfg/chainhash"
+	"github.com/brronsuite/brond/txscript"
 	"github.com/brronsuite/brond/wire"
 	"github.com/brronsuite/bronutil"
 )
@@ -109,6 +110,11 @@ type ChannelContribution struct {
 	// send to the remote party.
 	FirstCommitmentPoint *bronec.PublicKey
 
+	// SecondCommitmentPoint is the commitment point that will be used for
+	// the commitment transaction following the first one. It's only
+	// exchanged during the funding flow of dual funded channels.
+	SecondCommitmentPoint *bronec.PublicKey
+
 	// ChannelConfig is the concrete contribution that this node is
 	// offering to the channel. This includes all the various constraints
 	// such as the min HTLC, and also all the keys which will be used for
@@ -198,6 +204,10 @@ type ChannelReservation struct {
 
 	fundingIntent chanfunding.Intent
 
+	// interactive is true if this is a dual funded channel whose funding
+	// transaction is constructed interactively with the remote party.
+	interactive bool
+
 	// nextRevocationKeyLoc stores the key locator information for this
 	// channel.
 	nextRevocationKeyLoc keychain.KeyLocator
@@ -415,6 +425,127 @@ func NewChannelReservation(capacity, localFundingAmt bronutil.Amount,
 	}, nil
 }
 
+// NewDualFundedReservation creates a new channel reservation for a channel
+// that is funded by both parties through an interactively constructed funding
+// transaction. As with single funder channels, the initiator pays the fee of
+// the commitment transaction. The remote funding amount may be unknown to the
+// initiator at this point, in which case it's set later on through
+// SetRemoteFundingAmt.
+func NewDualFundedReservation(localFundingAmt, remoteFundingAmt bronutil.Amount,
+	initiator bool, commitFeePerKw chainfee.SatPerKWeight,
+	wallet *LightningWallet, id uint64, chainHash *chainhash.Hash,
+	flags lnwire.FundingFlag, commitType CommitmentType,
+	fundingAssembler chanfunding.Assembler,
+	pendingChanID [32]byte) (*ChannelReservation, error) {
+
+	if commitType == CommitmentTypeScriptEnforcedLease {
+		return nil, errors.New("script enforced leases are not " +
+			"supported for dual funded channels")
+	}
+
+	chanType := channeldb.DualFunderBit
+	if commitType.HasStaticRemoteKey() {
+		chanType |= channeldb.SingleFunderTweaklessBit
+	}
+	if commitType.HasAnchors() {
+		chanType |= channeldb.AnchorOutputsBit
+		chanType |= channeldb.ZeroHtlcTxFeeBit
+	}
+
+	r := &ChannelReservation{
+		ourContribution: &ChannelContribution{
+			ChannelConfig: &channeldb.ChannelConfig{},
+		},
+		theirContribution: &ChannelContribution{
+			ChannelConfig: &channeldb.ChannelConfig{},
+		},
+		partialState: &channeldb.OpenChannel{
+			ChanType:     chanType,
+			ChainHash:    *chainHash,
+			IsPending:    true,
+			IsInitiator:  initiator,
+			ChannelFlags: flags,
+			Db:           wallet.Cfg.Database,
+		},
+		pendingChanID: pendingChanID,
+		reservationID: id,
+		wallet:        wallet,
+		chanFunder:    fundingAssembler,
+		interactive:   true,
+	}
+
+	err := r.setFundingAmounts(
+		localFundingAmt, remoteFundingAmt, commitFeePerKw,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// setFundingAmounts sets the capacity and the initial balances of a dual
+// funded channel. The fee of the commitment transaction, including the anchor
+// outputs, is deducted from the balance of the initiator. This method should
+// be called with the lock held.
+func (r *ChannelReservation) setFundingAmounts(localFundingAmt,
+	remoteFundingAmt bronutil.Amount,
+	commitFeePerKw chainfee.SatPerKWeight) error {
+
+	chanType := r.partialState.ChanType
+
+	commitWeight := int64(input.CommitWeight)
+	if chanType.HasAnchors() {
+		commitWeight = int64(input.AnchorCommitWeight)
+	}
+	commitFee := commitFeePerKw.FeeForWeight(commitWeight)
+
+	feeMSat := lnwire.NewMSatFromBroneess(commitFee)
+	if chanType.HasAnchors() {
+		feeMSat += 2 * lnwire.NewMSatFromBroneess(anchorSize)
+	}
+
+	ourBalance := lnwire.NewMSatFromBroneess(localFundingAmt)
+	theirBalance := lnwire.NewMSatFromBroneess(remoteFundingAmt)
+
+	initiatorBalance := &ourBalance
+	if !r.partialState.IsInitiator {
+		initiatorBalance = &theirBalance
+	}
+
+	// The initiator must be able to pay the fees and still have a balance
+	// above twice the dust limit afterwards.
+	defaultDust := r.wallet.Cfg.DefaultConstraints.DustLimit
+	if *initiatorBalance < feeMSat ||
+		(*initiatorBalance-feeMSat).ToBroneess() <= 2*defaultDust {
+
+		return ErrFunderBalanceDust(
+			int64(commitFee), int64(initiatorBalance.ToBroneess()),
+			int64(2*defaultDust),
+		)
+	}
+	*initiatorBalance -= feeMSat
+
+	r.ourContribution.FundingAmount = ourBalance.ToBroneess()
+	r.theirContribution.FundingAmount = theirBalance.ToBroneess()
+
+	r.partialState.Capacity = localFundingAmt + remoteFundingAmt
+	r.partialState.LocalCommitment = channeldb.ChannelCommitment{
+		LocalBalance:  ourBalance,
+		RemoteBalance: theirBalance,
+		FeePerKw:      bronutil.Amount(commitFeePerKw),
+		CommitFee:     commitFee,
+	}
+	r.partialState.RemoteCommitment = channeldb.ChannelCommitment{
+		LocalBalance:  ourBalance,
+		RemoteBalance: theirBalance,
+		FeePerKw:      bronutil.Amount(commitFeePerKw),
+		CommitFee:     commitFee,
+	}
+
+	return nil
+}
+
 // SetNumConfsRequired sets the number of confirmations that are required for
 // the ultimate funding transaction before the channel can be considered open.
 // This is distinct from the main reservation workflow as it allows
@@ -573,6 +704,93 @@ func (r *ChannelReservation) IsPsbt() bool {
 	return ok
 }
 
+// IsInteractive returns true if this is a reservation for a dual funded
+// channel whose funding transaction is constructed interactively.
+func (r *ChannelReservation) IsInteractive() bool {
+	r.RLock()
+	defer r.RUnlock()
+
+	return r.interactive
+}
+
+// SetRemoteFundingAmt sets the amount the remote party contributes to a dual
+// funded channel, updating the capacity and the initial balances of the
+// channel. This is used by the initiator, which learns the contribution of the
+// remote party only after the reservation was created. It must be called
+// before the remote contribution is processed.
+func (r *ChannelReservation) SetRemoteFundingAmt(amt bronutil.Amount) error {
+	r.Lock()
+	defer r.Unlock()
+
+	if !r.interactive {
+		return errors.New("remote funding amount can only be set " +
+			"for dual funded channels")
+	}
+
+	fullIntent, ok := r.fundingIntent.(*chanfunding.FullIntent)
+	if !ok {
+		return errors.New("dual funded channels must be funded by " +
+			"the wallet")
+	}
+	fullIntent.SetRemoteFundingAmt(amt)
+
+	return r.setFundingAmounts(
+		fullIntent.LocalFundingAmt(), amt,
+		chainfee.SatPerKWeight(r.partialState.LocalCommitment.FeePerKw),
+	)
+}
+
+// InteractiveContribution returns the outpoints of the inputs and the change
+// outputs we contribute to the funding transaction of a dual funded channel.
+func (r *ChannelReservation) InteractiveContribution() ([]wire.OutPoint,
+	[]*wire.TxOut, error) {
+
+	r.RLock()
+	defer r.RUnlock()
+
+	fullIntent, ok := r.fundingIntent.(*chanfunding.FullIntent)
+	if !r.interactive || !ok {
+		return nil, nil, errors.New("reservation has no interactive " +
+			"funding intent")
+	}
+
+	return fullIntent.Inputs(), fullIntent.ChangeOutputs, nil
+}
+
+// FundingOutput returns the multi-sig funding output of a dual funded
+// channel.
+//
+// NOTE: This will only be available after the remote contribution was
+// processed with ProcessSingleContribution.
+func (r *ChannelReservation) FundingOutput() (*wire.TxOut, error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	_, fundingOutput, err := r.fundingIntent.FundingOutput()
+	return fundingOutput, err
+}
+
+// ProcessInteractiveTx continues the funding flow of a dual funded channel
+// once the funding transaction was constructed with the remote party. The
+// prevOutFetcher must know the outputs spent by all inputs of the
+// transaction. Our inputs are signed, and a signature for the counterparty's
+// version of the commitment transaction is generated, which is available via
+// the .OurSignatures() method afterwards.
+func (r *ChannelReservation) ProcessInteractiveTx(fundingTx *wire.MsgTx,
+	prevOutFetcher *txscript.MultiPrevOutFetcher) error {
+
+	errChan := make(chan error, 1)
+
+	r.wallet.msgChan <- &addInteractiveTxMsg{
+		pendingFundingID: r.reservationID,
+		fundingTx:        fundingTx,
+		prevOutFetcher:   prevOutFetcher,
+		err:              errChan,
+	}
+
+	return <-errChan
+}
+
 // IsCannedShim returns true if there is a canned shim funding intent mapped to
 // this reservation.
 func (r *ChannelReservation) IsCannedShim() bool {
@@ -759,6 +977,34 @@ func (r *ChannelReservation) Capacity() bronutil.Amount {
 	return r.partialState.Capacity
 }
 
+// AddAlias stores the first alias of a zero-conf or scid-alias channel as its
+// short channel ID. The channel will be referred to by this alias until it is
+// closed.
+func (r *ChannelReservation) AddAlias(scid lnwire.ShortChannelID) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.partialState.ShortChannelID = scid
+}
+
+// IsZeroConf returns if the reservation's underlying channel type is
+// zero-conf.
+func (r *ChannelReservation) IsZeroConf() bool {
+	r.RLock()
+	defer r.RUnlock()
+
+	return r.partialState.ChanType.HasZeroConf()
+}
+
+// IsScidAlias returns if the reservation's underlying channel type is
+// scid-alias.
+func (r *ChannelReservation) IsScidAlias() bool {
+	r.RLock()
+	defer r.RUnlock()
+
+	return r.partialState.ChanType.HasScidAliasChan()
+}
+
 // LeaseExpiry returns the absolute expiration height for a leased channel using
 // the script enforced commitment type. A zero value is returned when the
 // channel is not using a script enforced lease commitment type.
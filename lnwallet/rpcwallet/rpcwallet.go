@@ -3,6 +3,7 @@ package rpcwallet
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"crypto/x509"
 	"errors"
 	"fmt"
@@ -20,6 +21,8 @@ import (
 	"github.com/brronsuite/broln/lnwire"
 	"github.com/brronsuite/broln/macaroons"
 	"github.com/brronsuite/brond/bronec"
+	"github.com/brronsuite/brond/bronec/schnorr"
+	"github.com/brronsuite/brond/bronec/schnorr/musig2"
 	"github.com/brronsuite/brond/txscript"
 	"github.com/brronsuite/brond/wire"
 	"github.com/brronsuite/bronutil"
@@ -60,6 +63,7 @@ type RPCKeyRing struct {
 
 var _ keychain.SecretKeyRing = (*RPCKeyRing)(nil)
 var _ input.Signer = (*RPCKeyRing)(nil)
+var _ input.MuSig2Signer = (*RPCKeyRing)(nil)
 var _ keychain.MessageSignerRing = (*RPCKeyRing)(nil)
 var _ lnwallet.WalletController = (*RPCKeyRing)(nil)
 
@@ -110,7 +114,7 @@ func (r *RPCKeyRing) NewAddress(addrType lnwallet.AddressType, change bool,
 //
 // NOTE: This is a part of the WalletController interface.
 //
-// NOTE: This method only signs with BIP49/84 keys.
+// NOTE: This method only signs with BIP49/84/86 keys.
 func (r *RPCKeyRing) SendOutputs(outputs []*wire.TxOut,
 	feeRate chainfee.SatPerKWeight, minConfs int32,
 	label string) (*wire.MsgTx, error) {
@@ -129,10 +133,8 @@ func (r *RPCKeyRing) SendOutputs(outputs []*wire.TxOut,
 
 	// We know at this point that we only have inputs from our own wallet.
 	// So we can just compute the input script using the remote signer.
-	signDesc := input.SignDescriptor{
-		HashType:  txscript.SigHashAll,
-		SigHashes: txscript.NewTxSigHashes(tx),
-	}
+	prevOutFetcher := txscript.NewMultiPrevOutFetcher(nil)
+	prevOuts := make([]*wire.TxOut, len(tx.TxIn))
 	for i, txIn := range tx.TxIn {
 		// We can only sign this input if it's ours, so we'll ask the
 		// watch-only wallet if it can map this outpoint into a coin we
@@ -145,12 +147,22 @@ func (r *RPCKeyRing) SendOutputs(outputs []*wire.TxOut,
 			return nil, fmt.Errorf("error looking up utxo: %v", err)
 		}
 
-		// Now that we know the input is ours, we'll populate the
-		// signDesc with the per input unique information.
-		signDesc.Output = &wire.TxOut{
+		prevOuts[i] = &wire.TxOut{
 			Value:    int64(info.Value),
 			PkScript: info.PkScript,
 		}
+		prevOutFetcher.AddPrevOut(txIn.PreviousOutPoint, prevOuts[i])
+	}
+
+	signDesc := input.SignDescriptor{
+		HashType:          txscript.SigHashAll,
+		SigHashes:         txscript.NewTxSigHashes(tx, prevOutFetcher),
+		PrevOutputFetcher: prevOutFetcher,
+	}
+	for i, txIn := range tx.TxIn {
+		// Now that we know the input is ours, we'll populate the
+		// signDesc with the per input unique information.
+		signDesc.Output = prevOuts[i]
 		signDesc.InputIndex = i
 
 		// Finally, we'll sign the input as is, and populate the input
@@ -251,7 +263,8 @@ func (r *RPCKeyRing) FinalizePsbt(packet *psbt.Packet, _ string) error {
 	// ones to sign. If there is any input without witness data that we
 	// cannot sign because it's not our UTXO, this will be a hard failure.
 	tx := packet.UnsignedTx
-	sigHashes := txscript.NewTxSigHashes(tx)
+	prevOutFetcher := bronwallet.PsbtPrevOutputFetcher(packet)
+	sigHashes := txscript.NewTxSigHashes(tx, prevOutFetcher)
 	for idx, txIn := range tx.TxIn {
 		in := packet.Inputs[idx]
 
@@ -281,9 +294,10 @@ func (r *RPCKeyRing) FinalizePsbt(packet *psbt.Packet, _ string) error {
 				Value:    int64(utxo.Value),
 				PkScript: utxo.PkScript,
 			},
-			HashType:   in.SighashType,
-			SigHashes:  sigHashes,
-			InputIndex: idx,
+			HashType:          in.SighashType,
+			SigHashes:         sigHashes,
+			PrevOutputFetcher: prevOutFetcher,
+			InputIndex:        idx,
 		}
 
 		// Find out what UTXO we are signing. Wallets _should_ always
@@ -517,6 +531,13 @@ func (r *RPCKeyRing) DerivePrivKey(_ keychain.KeyDescriptor) (*bronec.PrivateKey
 func (r *RPCKeyRing) SignOutputRaw(tx *wire.MsgTx,
 	signDesc *input.SignDescriptor) (input.Signature, error) {
 
+	// Taproot signatures can't be expressed through the partial
+	// signatures of a PSBT, so we ask the remote signer to create the
+	// Schnorr signature directly.
+	if signDesc.SignMethod != input.WitnessV0SignMethod {
+		return r.remoteSignOutputRaw(tx, signDesc)
+	}
+
 	// Forward the call to the remote signing instance. This call is only
 	// ever called for signing witness (p2pkh or p2wsh) inputs and never
 	// nested witness inputs, so the sigScript is always nil.
@@ -537,6 +558,12 @@ func (r *RPCKeyRing) SignOutputRaw(tx *wire.MsgTx,
 func (r *RPCKeyRing) ComputeInputScript(tx *wire.MsgTx,
 	signDesc *input.SignDescriptor) (*input.Script, error) {
 
+	// Taproot key spend inputs produce a schnorr signature that doesn't
+	// need any of the script information below.
+	if txscript.IsPayToTaproot(signDesc.Output.PkScript) {
+		return r.remoteSignTaproot(tx, signDesc)
+	}
+
 	addr, witnessProgram, sigScript, err := r.WalletController.ScriptForOutput(
 		signDesc.Output,
 	)
@@ -643,33 +670,11 @@ func (r *RPCKeyRing) remoteSign(tx *wire.MsgTx, signDesc *input.SignDescriptor,
 	}
 
 	// Okay, let's sign the input by the remote signer now.
-	ctxt, cancel := context.WithTimeout(context.Background(), r.rpcTimeout)
-	defer cancel()
-
-	var buf bytes.Buffer
-	if err := packet.Serialize(&buf); err != nil {
-		return nil, fmt.Errorf("error serializing PSBT: %v", err)
-	}
-
-	resp, err := r.walletClient.SignPsbt(
-		ctxt, &walletrpc.SignPsbtRequest{FundedPsbt: buf.Bytes()},
-	)
+	signedPacket, err := r.signPsbtRemote(packet)
 	if err != nil {
-		err = fmt.Errorf("error signing PSBT in remote signer "+
-			"instance: %v", err)
-
-		// Log as critical as we should shut down if there is no signer.
-		log.Criticalf("RPC signer error: %v", err)
 		return nil, err
 	}
 
-	signedPacket, err := psbt.NewFromRawBytes(
-		bytes.NewReader(resp.SignedPsbt), false,
-	)
-	if err != nil {
-		return nil, fmt.Errorf("error parsing signed PSBT: %v", err)
-	}
-
 	// We expect a signature in the input now.
 	if signDesc.InputIndex >= len(signedPacket.Inputs) {
 		return nil, fmt.Errorf("remote signer returned invalid PSBT")
@@ -749,6 +754,476 @@ func connectRPC(hostPort, tlsCertPath, macaroonPath string,
 	return conn, nil
 }
 
+// remoteSignOutputRaw creates a Schnorr signature for a taproot input of the
+// given transaction by invoking SignOutputRaw on the remote signing instance.
+func (r *RPCKeyRing) remoteSignOutputRaw(tx *wire.MsgTx,
+	signDesc *input.SignDescriptor) (input.Signature, error) {
+
+	// The BIP-341 sighash commits to all previous outputs, so the remote
+	// signer needs to know all of them.
+	if signDesc.PrevOutputFetcher == nil {
+		return nil, fmt.Errorf("previous output fetcher required to " +
+			"sign taproot input")
+	}
+	prevOutputs := make([]*signrpc.TxOut, len(tx.TxIn))
+	for idx, txIn := range tx.TxIn {
+		prevOut := signDesc.PrevOutputFetcher.FetchPrevOutput(
+			txIn.PreviousOutPoint,
+		)
+		if prevOut == nil {
+			return nil, fmt.Errorf("previous output of input %d "+
+				"unknown", idx)
+		}
+		prevOutputs[idx] = &signrpc.TxOut{
+			Value:    prevOut.Value,
+			PkScript: prevOut.PkScript,
+		}
+	}
+
+	var signMethod signrpc.SignMethod
+	switch signDesc.SignMethod {
+	case input.TaprootKeySpendBIP0086SignMethod:
+		signMethod = signrpc.SignMethod_SIGN_METHOD_TAPROOT_KEY_SPEND_BIP0086
+
+	case input.TaprootKeySpendSignMethod:
+		signMethod = signrpc.SignMethod_SIGN_METHOD_TAPROOT_KEY_SPEND
+
+	case input.TaprootScriptSpendSignMethod:
+		signMethod = signrpc.SignMethod_SIGN_METHOD_TAPROOT_SCRIPT_SPEND
+
+	default:
+		return nil, fmt.Errorf("unsupported sign method %v",
+			signDesc.SignMethod)
+	}
+
+	var txBuf bytes.Buffer
+	if err := tx.Serialize(&txBuf); err != nil {
+		return nil, fmt.Errorf("error serializing TX: %v", err)
+	}
+
+	keyDesc := &signrpc.KeyDescriptor{
+		KeyLoc: &signrpc.KeyLocator{
+			KeyFamily: int32(signDesc.KeyDesc.Family),
+			KeyIndex:  int32(signDesc.KeyDesc.Index),
+		},
+	}
+	if pubKey := signDesc.KeyDesc.PubKey; pubKey != nil {
+		keyDesc.RawKeyBytes = pubKey.SerializeCompressed()
+	}
+
+	var doubleTweak []byte
+	if signDesc.DoubleTweak != nil {
+		doubleTweak = signDesc.DoubleTweak.Serialize()
+	}
+
+	ctxt, cancel := context.WithTimeout(context.Background(), r.rpcTimeout)
+	defer cancel()
+
+	resp, err := r.signerClient.SignOutputRaw(ctxt, &signrpc.SignReq{
+		RawTxBytes: txBuf.Bytes(),
+		SignDescs: []*signrpc.SignDescriptor{{
+			KeyDesc:       keyDesc,
+			SingleTweak:   signDesc.SingleTweak,
+			DoubleTweak:   doubleTweak,
+			TapTweak:      signDesc.TapTweak,
+			WitnessScript: signDesc.WitnessScript,
+			Output: &signrpc.TxOut{
+				Value:    signDesc.Output.Value,
+				PkScript: signDesc.Output.PkScript,
+			},
+			Sighash:    uint32(signDesc.HashType),
+			InputIndex: int32(signDesc.InputIndex),
+			SignMethod: signMethod,
+		}},
+		PrevOutputs: prevOutputs,
+	})
+	if err != nil {
+		err = fmt.Errorf("error signing output in remote signer "+
+			"instance: %v", err)
+
+		// Log as critical as we should shut down if there is no signer.
+		log.Criticalf("RPC signer error: %v", err)
+		return nil, err
+	}
+
+	if len(resp.RawSigs) != 1 {
+		return nil, fmt.Errorf("remote signer returned %d signatures, "+
+			"wanted 1", len(resp.RawSigs))
+	}
+
+	return schnorr.ParseSignature(resp.RawSigs[0])
+}
+
+// MuSig2CreateSession creates a new MuSig2 signing session using the local key
+// identified by the key locator. The complete list of all public keys of all
+// signing parties must be provided, including the public key of the local
+// signing key. If nonces of other parties are already known, they can be
+// submitted as well to reduce the number of method calls necessary later on.
+//
+// NOTE: This method is part of the input.MuSig2Signer interface.
+func (r *RPCKeyRing) MuSig2CreateSession(keyLoc keychain.KeyLocator,
+	pubKeys []*bronec.PublicKey, tweaks *input.MuSig2Tweaks,
+	otherNonces [][musig2.PubNonceSize]byte) (*input.MuSig2SessionInfo,
+	error) {
+
+	// We need to serialize all data for the RPC call. We can do that by
+	// putting everything directly into the request struct.
+	req := &signrpc.MuSig2SessionRequest{
+		KeyLoc: &signrpc.KeyLocator{
+			KeyFamily: int32(keyLoc.Family),
+			KeyIndex:  int32(keyLoc.Index),
+		},
+		AllSignerPubkeys:        make([][]byte, len(pubKeys)),
+		Tweaks:                  marshallTweaks(tweaks),
+		TaprootTweak:            marshallTaprootTweak(tweaks),
+		OtherSignerPublicNonces: make([][]byte, len(otherNonces)),
+	}
+	for idx, pubKey := range pubKeys {
+		req.AllSignerPubkeys[idx] = schnorr.SerializePubKey(pubKey)
+	}
+	for idx := range otherNonces {
+		req.OtherSignerPublicNonces[idx] = otherNonces[idx][:]
+	}
+
+	ctxt, cancel := context.WithTimeout(context.Background(), r.rpcTimeout)
+	defer cancel()
+
+	resp, err := r.signerClient.MuSig2CreateSession(ctxt, req)
+	if err != nil {
+		err = fmt.Errorf("error creating MuSig2 session in remote "+
+			"signer instance: %v", err)
+
+		// Log as critical as we should shut down if there is no signer.
+		log.Criticalf("RPC signer error: %v", err)
+		return nil, err
+	}
+
+	combinedKey, err := schnorr.ParsePubKey(resp.CombinedKey)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing combined key: %v", err)
+	}
+
+	info := &input.MuSig2SessionInfo{
+		CombinedKey:   combinedKey,
+		TaprootTweak:  tweaks.HasTaprootTweak(),
+		HaveAllNonces: resp.HaveAllNonces,
+	}
+
+	if len(resp.SessionId) != len(info.SessionID) {
+		return nil, fmt.Errorf("remote signer returned invalid "+
+			"session ID length %d", len(resp.SessionId))
+	}
+	copy(info.SessionID[:], resp.SessionId)
+
+	if len(resp.LocalPublicNonces) != musig2.PubNonceSize {
+		return nil, fmt.Errorf("remote signer returned invalid "+
+			"public nonce length %d", len(resp.LocalPublicNonces))
+	}
+	copy(info.PublicNonce[:], resp.LocalPublicNonces)
+
+	// The internal key is only returned if a taproot tweak was used.
+	if info.TaprootTweak {
+		info.TaprootInternalKey, err = schnorr.ParsePubKey(
+			resp.TaprootInternalKey,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing internal key: %v",
+				err)
+		}
+	}
+
+	return info, nil
+}
+
+// MuSig2RegisterNonces registers one or more public nonces of other signing
+// participants for a session identified by its ID. This method returns true
+// once we have all nonces for all other signing participants.
+//
+// NOTE: This method is part of the input.MuSig2Signer interface.
+func (r *RPCKeyRing) MuSig2RegisterNonces(sessionID input.MuSig2SessionID,
+	pubNonces [][musig2.PubNonceSize]byte) (bool, error) {
+
+	req := &signrpc.MuSig2RegisterNoncesRequest{
+		SessionId:               sessionID[:],
+		OtherSignerPublicNonces: make([][]byte, len(pubNonces)),
+	}
+	for idx := range pubNonces {
+		req.OtherSignerPublicNonces[idx] = pubNonces[idx][:]
+	}
+
+	ctxt, cancel := context.WithTimeout(context.Background(), r.rpcTimeout)
+	defer cancel()
+
+	resp, err := r.signerClient.MuSig2RegisterNonces(ctxt, req)
+	if err != nil {
+		err = fmt.Errorf("error registering MuSig2 nonces in remote "+
+			"signer instance: %v", err)
+
+		// Log as critical as we should shut down if there is no signer.
+		log.Criticalf("RPC signer error: %v", err)
+		return false, err
+	}
+
+	return resp.HaveAllNonces, nil
+}
+
+// MuSig2Sign creates a partial signature using the local signing key that was
+// specified when the session was created. This can only be called when all
+// public nonces of all participants are known and have been registered with
+// the session. If this node isn't responsible for combining all the partial
+// signatures, then the cleanup parameter should be set, indicating that the
+// session can be removed from memory once the signature was produced.
+//
+// NOTE: This method is part of the input.MuSig2Signer interface.
+func (r *RPCKeyRing) MuSig2Sign(sessionID input.MuSig2SessionID,
+	msg [sha256.Size]byte, cleanUp bool) (*musig2.PartialSignature, error) {
+
+	ctxt, cancel := context.WithTimeout(context.Background(), r.rpcTimeout)
+	defer cancel()
+
+	resp, err := r.signerClient.MuSig2Sign(ctxt, &signrpc.MuSig2SignRequest{
+		SessionId:     sessionID[:],
+		MessageDigest: msg[:],
+		Cleanup:       cleanUp,
+	})
+	if err != nil {
+		err = fmt.Errorf("error signing MuSig2 session in remote "+
+			"signer instance: %v", err)
+
+		// Log as critical as we should shut down if there is no signer.
+		log.Criticalf("RPC signer error: %v", err)
+		return nil, err
+	}
+
+	partialSig, err := input.DeserializePartialSignature(
+		resp.LocalPartialSignature,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing partial signature from "+
+			"remote signer: %v", err)
+	}
+
+	return partialSig, nil
+}
+
+// MuSig2CombineSig combines the given partial signature(s) with the local one,
+// if it already exists. Once a partial signature of all participants is
+// registered, the final signature will be combined and returned.
+//
+// NOTE: This method is part of the input.MuSig2Signer interface.
+func (r *RPCKeyRing) MuSig2CombineSig(sessionID input.MuSig2SessionID,
+	partialSigs []*musig2.PartialSignature) (*schnorr.Signature, bool,
+	error) {
+
+	req := &signrpc.MuSig2CombineSigRequest{
+		SessionId:              sessionID[:],
+		OtherPartialSignatures: make([][]byte, len(partialSigs)),
+	}
+	for idx, partialSig := range partialSigs {
+		rawSig, err := input.SerializePartialSignature(partialSig)
+		if err != nil {
+			return nil, false, fmt.Errorf("error serializing "+
+				"partial signature: %v", err)
+		}
+		req.OtherPartialSignatures[idx] = rawSig[:]
+	}
+
+	ctxt, cancel := context.WithTimeout(context.Background(), r.rpcTimeout)
+	defer cancel()
+
+	resp, err := r.signerClient.MuSig2CombineSig(ctxt, req)
+	if err != nil {
+		err = fmt.Errorf("error combining MuSig2 signatures in remote "+
+			"signer instance: %v", err)
+
+		// Log as critical as we should shut down if there is no signer.
+		log.Criticalf("RPC signer error: %v", err)
+		return nil, false, err
+	}
+
+	// The final signature is only available when we have all the other
+	// partial signatures from all participants.
+	if !resp.HaveAllSignatures {
+		return nil, false, nil
+	}
+
+	finalSig, err := schnorr.ParseSignature(resp.FinalSignature)
+	if err != nil {
+		return nil, false, fmt.Errorf("error parsing final signature: "+
+			"%v", err)
+	}
+
+	return finalSig, true, nil
+}
+
+// MuSig2Cleanup removes a session from memory to free up resources.
+//
+// NOTE: This method is part of the input.MuSig2Signer interface.
+func (r *RPCKeyRing) MuSig2Cleanup(sessionID input.MuSig2SessionID) error {
+	ctxt, cancel := context.WithTimeout(context.Background(), r.rpcTimeout)
+	defer cancel()
+
+	_, err := r.signerClient.MuSig2Cleanup(
+		ctxt, &signrpc.MuSig2CleanupRequest{SessionId: sessionID[:]},
+	)
+	if err != nil {
+		err = fmt.Errorf("error cleaning up MuSig2 session in remote "+
+			"signer instance: %v", err)
+
+		// Log as critical as we should shut down if there is no signer.
+		log.Criticalf("RPC signer error: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// marshallTweaks converts the generic MuSig2 tweaks into their RPC
+// counterpart.
+func marshallTweaks(tweaks *input.MuSig2Tweaks) []*signrpc.TweakDesc {
+	rpcTweaks := make([]*signrpc.TweakDesc, len(tweaks.GenericTweaks))
+	for idx, tweak := range tweaks.GenericTweaks {
+		rpcTweaks[idx] = &signrpc.TweakDesc{
+			Tweak:   tweak.Tweak[:],
+			IsXOnly: tweak.IsXOnly,
+		}
+	}
+
+	return rpcTweaks
+}
+
+// marshallTaprootTweak converts the taproot specific MuSig2 tweak into its RPC
+// counterpart. Nil is returned if no taproot tweak is set.
+func marshallTaprootTweak(
+	tweaks *input.MuSig2Tweaks) *signrpc.TaprootTweakDesc {
+
+	switch {
+	case tweaks.TaprootBIP0086Tweak:
+		return &signrpc.TaprootTweakDesc{
+			KeySpendOnly: true,
+		}
+
+	case len(tweaks.TaprootTweak) > 0:
+		return &signrpc.TaprootTweakDesc{
+			ScriptRoot: tweaks.TaprootTweak,
+		}
+
+	default:
+		return nil
+	}
+}
+
+// remoteSignTaproot signs a BIP-86 p2tr input of the watch-only wallet using
+// the key spend path with the remote signer and returns its witness.
+func (r *RPCKeyRing) remoteSignTaproot(tx *wire.MsgTx,
+	signDesc *input.SignDescriptor) (*input.Script, error) {
+
+	// The BIP-341 sighash commits to all previous outputs, so the remote
+	// signer needs to know all of them.
+	if signDesc.PrevOutputFetcher == nil {
+		return nil, fmt.Errorf("previous output fetcher required to " +
+			"sign taproot input")
+	}
+
+	packet, err := packetFromTx(tx)
+	if err != nil {
+		return nil, fmt.Errorf("error converting TX into PSBT: %v", err)
+	}
+
+	// Catch incorrect signing input index, just in case.
+	if signDesc.InputIndex < 0 || signDesc.InputIndex >= len(packet.Inputs) {
+		return nil, fmt.Errorf("invalid input index in sign descriptor")
+	}
+
+	for idx, txIn := range tx.TxIn {
+		prevOut := signDesc.PrevOutputFetcher.FetchPrevOutput(
+			txIn.PreviousOutPoint,
+		)
+		if prevOut == nil {
+			return nil, fmt.Errorf("previous output of input %d "+
+				"unknown", idx)
+		}
+		packet.Inputs[idx].WitnessUtxo = prevOut
+	}
+
+	// Taproot inputs can only be signed if they belong to the wallet, as
+	// we need the full derivation info of the key.
+	txIn := tx.TxIn[signDesc.InputIndex]
+	info, err := r.WalletController.FetchInputInfo(&txIn.PreviousOutPoint)
+	if err != nil {
+		return nil, fmt.Errorf("error looking up utxo: %v", err)
+	}
+	pubKey, err := bronec.ParsePubKey(info.Derivation.PubKey, bronec.S256())
+	if err != nil {
+		return nil, fmt.Errorf("error parsing derivation public key: "+
+			"%v", err)
+	}
+
+	in := &packet.Inputs[signDesc.InputIndex]
+	in.SighashType = signDesc.HashType
+	in.TaprootBip32Derivation = []*psbt.TaprootBip32Derivation{{
+		XOnlyPubKey:          schnorr.SerializePubKey(pubKey),
+		MasterKeyFingerprint: info.Derivation.MasterKeyFingerprint,
+		Bip32Path:            info.Derivation.Bip32Path,
+	}}
+
+	signedPacket, err := r.signPsbtRemote(packet)
+	if err != nil {
+		return nil, err
+	}
+
+	// We expect a key spend signature in the input now.
+	if signDesc.InputIndex >= len(signedPacket.Inputs) {
+		return nil, fmt.Errorf("remote signer returned invalid PSBT")
+	}
+	sig := signedPacket.Inputs[signDesc.InputIndex].TaprootKeySpendSig
+	if len(sig) != schnorr.SignatureSize &&
+		len(sig) != schnorr.SignatureSize+1 {
+
+		return nil, fmt.Errorf("remote signer returned invalid "+
+			"taproot key spend signature with %d bytes", len(sig))
+	}
+
+	return &input.Script{
+		Witness: wire.TxWitness{sig},
+	}, nil
+}
+
+// signPsbtRemote sends the given packet to the remote signer for signing and
+// returns the signed packet.
+func (r *RPCKeyRing) signPsbtRemote(packet *psbt.Packet) (*psbt.Packet,
+	error) {
+
+	ctxt, cancel := context.WithTimeout(context.Background(), r.rpcTimeout)
+	defer cancel()
+
+	var buf bytes.Buffer
+	if err := packet.Serialize(&buf); err != nil {
+		return nil, fmt.Errorf("error serializing PSBT: %v", err)
+	}
+
+	resp, err := r.walletClient.SignPsbt(
+		ctxt, &walletrpc.SignPsbtRequest{FundedPsbt: buf.Bytes()},
+	)
+	if err != nil {
+		err = fmt.Errorf("error signing PSBT in remote signer "+
+			"instance: %v", err)
+
+		// Log as critical as we should shut down if there is no signer.
+		log.Criticalf("RPC signer error: %v", err)
+		return nil, err
+	}
+
+	signedPacket, err := psbt.NewFromRawBytes(
+		bytes.NewReader(resp.SignedPsbt), false,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing signed PSBT: %v", err)
+	}
+
+	return signedPacket, nil
+}
+
 // packetFromTx creates a PSBT from a tx that potentially already contains
 // signed inputs.
 func packetFromTx(original *wire.MsgTx) (*psbt.Packet, error) {
@@ -0,0 +1,529 @@
+package lnwallet
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/brronsuite/broln/channeldb"
+	"github.com/brronsuite/broln/input"
+	"github.com/brronsuite/broln/lnwallet/chainfee"
+	"github.com/brronsuite/broln/lnwallet/chanfunding"
+	"github.com/brronsuite/broln/lnwire"
+	"github.com/brronsuite/brond/chaincfg/chainhash"
+	"github.com/brronsuite/brond/txscript"
+	"github.com/brronsuite/brond/wire"
+	"github.com/brronsuite/bronutil"
+)
+
+var (
+	// ErrChannelNotClean is returned when a splice is attempted on a
+	// channel that still has pending updates.
+	ErrChannelNotClean = errors.New("channel has pending updates")
+
+	// ErrSpliceOutputNotFound is returned when the splice transaction
+	// doesn't contain the new funding output.
+	ErrSpliceOutputNotFound = errors.New("splice funding output not found")
+)
+
+// Splice tracks a splice of the channel from the construction of its
+// transaction until both parties signed the shared input.
+type Splice struct {
+	// SpliceTx is the splice transaction, which spends the current
+	// funding output of the channel through its shared input.
+	SpliceTx *wire.MsgTx
+
+	// FundingOutpoint is the outpoint of the new funding output.
+	FundingOutpoint wire.OutPoint
+
+	// Capacity is the capacity of the channel after the splice.
+	Capacity bronutil.Amount
+
+	// LocalContribution and RemoteContribution are the amounts added to
+	// (or removed from if negative) the channel by each party.
+	LocalContribution  bronutil.Amount
+	RemoteContribution bronutil.Amount
+
+	// FeeRate is the fee rate of the splice transaction.
+	FeeRate chainfee.SatPerKWeight
+
+	// IsInitiator is true if we initiated the splice.
+	IsInitiator bool
+
+	// localCommit and remoteCommit are the commitments for the current
+	// state of the channel spending the new funding output.
+	localCommit  *commitment
+	remoteCommit *commitment
+
+	// sharedInputIndex is the index of the shared input within SpliceTx.
+	sharedInputIndex int
+}
+
+// SharedInput returns the current funding outpoint of the channel and the
+// output it refers to, which are spent by the shared input of a splice.
+func (lc *LightningChannel) SharedInput() (wire.OutPoint, *wire.TxOut) {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	return lc.channelState.FundingOutpoint, lc.signDesc.Output
+}
+
+// SpliceFundingOutput returns the funding output of the channel after a
+// splice that results in the given capacity. It uses the same multi-sig
+// script as the current funding output.
+func (lc *LightningChannel) SpliceFundingOutput(
+	capacity bronutil.Amount) *wire.TxOut {
+
+	lc.RLock()
+	defer lc.RUnlock()
+
+	return &wire.TxOut{
+		PkScript: lc.signDesc.Output.PkScript,
+		Value:    int64(capacity),
+	}
+}
+
+// PendingSplices returns the splices of the channel that have been signed by
+// both parties, but aren't locked yet.
+func (lc *LightningChannel) PendingSplices() []*channeldb.PendingSplice {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	splices := make([]*channeldb.PendingSplice, len(lc.pendingSplices))
+	copy(splices, lc.pendingSplices)
+
+	return splices
+}
+
+// spliceSignDesc returns the sign descriptor for commitments spending the
+// funding output of the given splice.
+func (lc *LightningChannel) spliceSignDesc(
+	splice *channeldb.PendingSplice) *input.SignDescriptor {
+
+	signDesc := *lc.signDesc
+	signDesc.Output = &wire.TxOut{
+		PkScript: lc.signDesc.Output.PkScript,
+		Value:    int64(splice.Capacity),
+	}
+
+	return &signDesc
+}
+
+// findSpliceSig returns the signatures for the commitment spending the funding
+// output of the splice transaction with the given txid, or nil if there are
+// none.
+func findSpliceSig(sigs lnwire.SpliceSigs,
+	txid chainhash.Hash) *lnwire.SpliceSig {
+
+	for i := range sigs {
+		if sigs[i].FundingTxID == txid {
+			return &sigs[i]
+		}
+	}
+
+	return nil
+}
+
+// spliceBalance applies the contribution of a party to a splice to its
+// balance, failing if more than the balance is removed from the channel.
+func spliceBalance(balance lnwire.MilliBronees,
+	contribution bronutil.Amount) (lnwire.MilliBronees, error) {
+
+	if contribution >= 0 {
+		return balance + lnwire.NewMSatFromBroneess(contribution), nil
+	}
+
+	removed := lnwire.NewMSatFromBroneess(-contribution)
+	if removed > balance {
+		return 0, fmt.Errorf("splice removes %v from balance of %v",
+			removed, balance)
+	}
+
+	return balance - removed, nil
+}
+
+// newSpliceCommitment creates the commitment for the same state as the passed
+// one that spends the funding output of the given splice. The passed balances
+// are the ones of the state *before* subtracting the commitment fee, to which
+// the contributions of both parties to the splice are applied.
+func (lc *LightningChannel) newSpliceCommitment(c *commitment,
+	splice *channeldb.PendingSplice, ourBalance,
+	theirBalance lnwire.MilliBronees, filteredHTLCView *htlcView,
+	keyRing *CommitmentKeyRing) (*commitment, error) {
+
+	ourBalance, err := spliceBalance(ourBalance, splice.LocalContribution)
+	if err != nil {
+		return nil, err
+	}
+	theirBalance, err = spliceBalance(
+		theirBalance, splice.RemoteContribution,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	spliceCommit := &commitment{
+		ourMessageIndex:   c.ourMessageIndex,
+		ourHtlcIndex:      c.ourHtlcIndex,
+		theirMessageIndex: c.theirMessageIndex,
+		theirHtlcIndex:    c.theirHtlcIndex,
+		height:            c.height,
+		feePerKw:          c.feePerKw,
+		dustLimit:         c.dustLimit,
+		isOurs:            c.isOurs,
+	}
+	err = lc.buildCommitment(
+		spliceCommit, ourBalance, theirBalance, filteredHTLCView,
+		keyRing, *wire.NewTxIn(&splice.FundingOutpoint, nil, nil),
+		splice.Capacity,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return spliceCommit, nil
+}
+
+// currentSpliceCommitment creates the commitment for the passed current
+// commitment of the channel that spends the funding output of the given
+// splice.
+func (lc *LightningChannel) currentSpliceCommitment(c *commitment,
+	splice *channeldb.PendingSplice,
+	keyRing *CommitmentKeyRing) (*commitment, error) {
+
+	view := &htlcView{
+		feePerKw: c.feePerKw,
+	}
+	for i := range c.outgoingHTLCs {
+		view.ourUpdates = append(view.ourUpdates, &c.outgoingHTLCs[i])
+	}
+	for i := range c.incomingHTLCs {
+		view.theirUpdates = append(
+			view.theirUpdates, &c.incomingHTLCs[i],
+		)
+	}
+
+	// The balances of the commitment are the ones after the initiator paid
+	// the commitment fee, so we'll add it back.
+	ourBalance, theirBalance := c.ourBalance, c.theirBalance
+	if lc.channelState.IsInitiator {
+		ourBalance += lnwire.NewMSatFromBroneess(c.fee)
+	} else {
+		theirBalance += lnwire.NewMSatFromBroneess(c.fee)
+	}
+
+	return lc.newSpliceCommitment(
+		c, splice, ourBalance, theirBalance, view, keyRing,
+	)
+}
+
+// NewSplice creates the commitment transactions for the current state of the
+// channel spending the new funding output of the passed splice transaction,
+// in which our balance changes by localContribution and the remote balance by
+// remoteContribution. The returned CommitSig carries our signatures for the
+// remote commitment, which the remote party needs before signing the shared
+// input.
+//
+// NOTE: The channel must be quiescent, without any pending updates.
+func (lc *LightningChannel) NewSplice(spliceTx *wire.MsgTx,
+	localContribution, remoteContribution bronutil.Amount,
+	feeRate chainfee.SatPerKWeight,
+	initiator bool) (*Splice, *lnwire.CommitSig, error) {
+
+	lc.Lock()
+	defer lc.Unlock()
+
+	if lc.localCommitChain.hasUnackedCommitment() ||
+		lc.remoteCommitChain.hasUnackedCommitment() ||
+		lc.oweCommitment(true) || lc.oweCommitment(false) {
+
+		return nil, nil, ErrChannelNotClean
+	}
+
+	chanState := lc.channelState
+	capacity := chanState.Capacity + localContribution + remoteContribution
+	fundingOutput := &wire.TxOut{
+		PkScript: lc.signDesc.Output.PkScript,
+		Value:    int64(capacity),
+	}
+	found, outputIndex := input.FindScriptOutputIndex(
+		spliceTx, fundingOutput.PkScript,
+	)
+	if !found || spliceTx.TxOut[outputIndex].Value != fundingOutput.Value {
+		return nil, nil, ErrSpliceOutputNotFound
+	}
+
+	var sharedInputIndex = -1
+	for i, txIn := range spliceTx.TxIn {
+		if txIn.PreviousOutPoint == chanState.FundingOutpoint {
+			sharedInputIndex = i
+			break
+		}
+	}
+	if sharedInputIndex < 0 {
+		return nil, nil, fmt.Errorf("splice tx doesn't spend funding "+
+			"outpoint %v", chanState.FundingOutpoint)
+	}
+
+	pendingSplice := &channeldb.PendingSplice{
+		SpliceTx: spliceTx,
+		FundingOutpoint: wire.OutPoint{
+			Hash:  spliceTx.TxHash(),
+			Index: outputIndex,
+		},
+		Capacity:           capacity,
+		LocalContribution:  localContribution,
+		RemoteContribution: remoteContribution,
+	}
+
+	// The commitments are created for the current commitment points of
+	// both parties, so they can be revoked as usual once the channel
+	// continues with the splice pending.
+	localCommit := lc.localCommitChain.tail()
+	localSecret, err := chanState.RevocationProducer.AtIndex(
+		localCommit.height,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	localKeyRing := DeriveCommitmentKeys(
+		input.ComputeCommitmentPoint(localSecret[:]), true,
+		chanState.ChanType, &chanState.LocalChanCfg,
+		&chanState.RemoteChanCfg,
+	)
+	remoteKeyRing := DeriveCommitmentKeys(
+		chanState.RemoteCurrentRevocation, false, chanState.ChanType,
+		&chanState.LocalChanCfg, &chanState.RemoteChanCfg,
+	)
+
+	ourCommit, err := lc.currentSpliceCommitment(
+		localCommit, pendingSplice, localKeyRing,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	theirCommit, err := lc.currentSpliceCommitment(
+		lc.remoteCommitChain.tail(), pendingSplice, remoteKeyRing,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sig, htlcSigs, err := lc.signCommitment(
+		theirCommit, remoteKeyRing, lc.spliceSignDesc(pendingSplice),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	splice := &Splice{
+		SpliceTx:           spliceTx,
+		FundingOutpoint:    pendingSplice.FundingOutpoint,
+		Capacity:           capacity,
+		LocalContribution:  localContribution,
+		RemoteContribution: remoteContribution,
+		FeeRate:            feeRate,
+		IsInitiator:        initiator,
+		localCommit:        ourCommit,
+		remoteCommit:       theirCommit,
+		sharedInputIndex:   sharedInputIndex,
+	}
+	chanID := lnwire.NewChanIDFromOutPoint(&chanState.FundingOutpoint)
+	commitSig := &lnwire.CommitSig{
+		ChanID:    chanID,
+		CommitSig: sig,
+		HtlcSigs:  htlcSigs,
+		ExtraData: make([]byte, 0),
+	}
+
+	return splice, commitSig, nil
+}
+
+// ReceiveSpliceCommitSig verifies the remote party's signatures for our
+// commitment spending the new funding output of the splice.
+func (lc *LightningChannel) ReceiveSpliceCommitSig(splice *Splice,
+	msg *lnwire.CommitSig) error {
+
+	lc.RLock()
+	defer lc.RUnlock()
+
+	localSecret, err := lc.channelState.RevocationProducer.AtIndex(
+		splice.localCommit.height,
+	)
+	if err != nil {
+		return err
+	}
+	keyRing := DeriveCommitmentKeys(
+		input.ComputeCommitmentPoint(localSecret[:]), true,
+		lc.channelState.ChanType, &lc.channelState.LocalChanCfg,
+		&lc.channelState.RemoteChanCfg,
+	)
+
+	return lc.verifyCommitment(
+		splice.localCommit, keyRing, msg.CommitSig, msg.HtlcSigs,
+		splice.Capacity,
+	)
+}
+
+// SignSharedInput returns our signature for the shared input of the splice,
+// which spends the current funding output of the channel. The prevOutFetcher
+// must know the outputs spent by all inputs of the splice transaction.
+func (lc *LightningChannel) SignSharedInput(splice *Splice,
+	prevOutFetcher txscript.PrevOutputFetcher) (lnwire.Sig, error) {
+
+	lc.RLock()
+	defer lc.RUnlock()
+
+	signDesc := *lc.signDesc
+	signDesc.InputIndex = splice.sharedInputIndex
+	signDesc.SigHashes = txscript.NewTxSigHashes(
+		splice.SpliceTx, prevOutFetcher,
+	)
+
+	rawSig, err := lc.Signer.SignOutputRaw(splice.SpliceTx, &signDesc)
+	if err != nil {
+		return lnwire.Sig{}, err
+	}
+
+	return lnwire.NewSigFromSignature(rawSig)
+}
+
+// CompleteSplice adds the witness spending the current funding output to the
+// shared input of the splice transaction, using our and the remote party's
+// signature, and validates it. The splice is then persisted as a pending
+// splice of the channel, so all further commitments are also signed for its
+// funding output until one of the pending splices is locked.
+//
+// NOTE: The witnesses of all other inputs must already be populated.
+func (lc *LightningChannel) CompleteSplice(splice *Splice,
+	localSig, remoteSig lnwire.Sig,
+	prevOutFetcher txscript.PrevOutputFetcher) error {
+
+	lc.Lock()
+	defer lc.Unlock()
+
+	ourSig, err := localSig.ToSignature()
+	if err != nil {
+		return err
+	}
+	theirSig, err := remoteSig.ToSignature()
+	if err != nil {
+		return err
+	}
+
+	ourKey := lc.channelState.LocalChanCfg.MultiSigKey.PubKey.
+		SerializeCompressed()
+	theirKey := lc.channelState.RemoteChanCfg.MultiSigKey.PubKey.
+		SerializeCompressed()
+
+	spliceTx := splice.SpliceTx
+	spliceTx.TxIn[splice.sharedInputIndex].Witness = input.SpendMultiSig(
+		lc.signDesc.WitnessScript, ourKey, ourSig, theirKey, theirSig,
+	)
+
+	// Validate the shared input to ensure the remote party supplied a
+	// valid signature.
+	prevOut := lc.signDesc.Output
+	vm, err := txscript.NewEngine(
+		prevOut.PkScript, spliceTx, splice.sharedInputIndex,
+		txscript.StandardVerifyFlags, nil,
+		txscript.NewTxSigHashes(spliceTx, prevOutFetcher),
+		prevOut.Value, prevOutFetcher,
+	)
+	if err != nil {
+		return err
+	}
+	if err := vm.Execute(); err != nil {
+		return err
+	}
+
+	prevOutScripts := make([][]byte, len(spliceTx.TxIn))
+	for i, txIn := range spliceTx.TxIn {
+		prevOut := prevOutFetcher.FetchPrevOutput(txIn.PreviousOutPoint)
+		if prevOut == nil {
+			return fmt.Errorf("unknown splice input %v",
+				txIn.PreviousOutPoint)
+		}
+		prevOutScripts[i] = prevOut.PkScript
+	}
+
+	pendingSplice := &channeldb.PendingSplice{
+		SpliceTx:           spliceTx,
+		FundingOutpoint:    splice.FundingOutpoint,
+		Capacity:           splice.Capacity,
+		LocalContribution:  splice.LocalContribution,
+		RemoteContribution: splice.RemoteContribution,
+		FeePerKw:           bronutil.Amount(splice.FeeRate),
+		IsInitiator:        splice.IsInitiator,
+		PrevOutScripts:     prevOutScripts,
+		LocalCommitment:    *splice.localCommit.toDiskCommit(true),
+		RemoteCommitment:   *splice.remoteCommit.toDiskCommit(false),
+	}
+	if err := lc.channelState.AddPendingSplice(pendingSplice); err != nil {
+		return err
+	}
+	lc.pendingSplices = append(lc.pendingSplices, pendingSplice)
+
+	return nil
+}
+
+// RemovePendingSplices removes all pending splices of the channel, which is
+// done once none of the splice transactions can confirm anymore. From then on,
+// the commitments of the channel are only signed for its current funding
+// output.
+//
+// NOTE: The channel must be quiescent, so both parties remove the pending
+// splices at the same state.
+func (lc *LightningChannel) RemovePendingSplices() error {
+	lc.Lock()
+	defer lc.Unlock()
+
+	if err := lc.channelState.RemovePendingSplices(); err != nil {
+		return err
+	}
+	lc.pendingSplices = nil
+
+	return nil
+}
+
+// ProvisionSpliceIn selects coins of the wallet that fund adding amt to a
+// channel through a splice at the given fee rate. The returned intent holds
+// the selected inputs and change outputs, and signs the inputs once the
+// splice transaction is constructed.
+//
+// NOTE: Cancel must be called on the intent if the splice fails, so the
+// selected coins are unlocked again.
+func (l *LightningWallet) ProvisionSpliceIn(amt bronutil.Amount,
+	feeRate chainfee.SatPerKWeight,
+	minConfs int32) (*chanfunding.FullIntent, error) {
+
+	cfg := chanfunding.WalletConfig{
+		CoinSource:       &CoinSource{l},
+		CoinSelectLocker: l,
+		CoinLocker:       l,
+		Signer:           l.Cfg.Signer,
+		DustLimit:        DustLimitForSize(input.P2WSHSize),
+	}
+	intent, err := chanfunding.NewWalletAssembler(cfg).ProvisionChannel(
+		&chanfunding.Request{
+			LocalAmt: amt,
+			MinConfs: minConfs,
+			FeeRate:  feeRate,
+			ChangeAddr: func() (bronutil.Address, error) {
+				return l.NewAddress(
+					WitnessPubKey, true, DefaultAccountName,
+				)
+			},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	fullIntent, ok := intent.(*chanfunding.FullIntent)
+	if !ok {
+		intent.Cancel()
+		return nil, fmt.Errorf("unexpected intent %T", intent)
+	}
+
+	return fullIntent, nil
+}
@@ -1699,7 +1699,7 @@ func txFromOutput(tx *wire.MsgTx, signer input.Signer, fromPubKey,
 		WitnessScript: keyScript,
 		Output:        tx.TxOut[outputIndex],
 		HashType:      txscript.SigHashAll,
-		SigHashes:     txscript.NewTxSigHashes(tx1),
+		SigHashes:     input.NewTxSigHashesV0Only(tx1),
 		InputIndex:    0, // Has only one input.
 	}
 
@@ -1717,9 +1717,12 @@ func txFromOutput(tx *wire.MsgTx, signer input.Signer, fromPubKey,
 	// Finally, attempt to validate the completed transaction. This should
 	// succeed if the wallet was able to properly generate the proper
 	// private key.
+	prevOutFetcher := txscript.NewCannedPrevOutputFetcher(
+		keyScript, outputValue,
+	)
 	vm, err := txscript.NewEngine(
 		keyScript, tx1, 0, txscript.StandardVerifyFlags, nil,
-		nil, outputValue,
+		nil, outputValue, prevOutFetcher,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create engine: %v", err)
@@ -2085,7 +2088,7 @@ func testSignOutputUsingTweaks(r *rpctest.Harness,
 			WitnessScript: keyScript,
 			Output:        newOutput,
 			HashType:      txscript.SigHashAll,
-			SigHashes:     txscript.NewTxSigHashes(sweepTx),
+			SigHashes:     input.NewTxSigHashesV0Only(sweepTx),
 			InputIndex:    0,
 		}
 
@@ -2112,9 +2115,12 @@ func testSignOutputUsingTweaks(r *rpctest.Harness,
 		// Finally, attempt to validate the completed transaction. This
 		// should succeed if the wallet was able to properly generate
 		// the proper private key.
+		prevOutFetcher := txscript.NewCannedPrevOutputFetcher(
+			keyScript, int64(bronutil.BroneesPerBrocoin),
+		)
 		vm, err := txscript.NewEngine(keyScript,
 			sweepTx, 0, txscript.StandardVerifyFlags, nil,
-			nil, int64(bronutil.BroneesPerBrocoin))
+			nil, int64(bronutil.BroneesPerBrocoin), prevOutFetcher)
 		if err != nil {
 			t.Fatalf("unable to create engine: %v", err)
 		}
@@ -2497,6 +2503,7 @@ func testLastUnusedAddr(miner *rpctest.Harness,
 	// rotated properly.
 	addrTypes := []lnwallet.AddressType{
 		lnwallet.WitnessPubKey, lnwallet.NestedWitnessPubKey,
+		lnwallet.TaprootPubkey,
 	}
 	for _, addrType := range addrTypes {
 		addr1, err := alice.LastUnusedAddress(
@@ -2822,7 +2829,7 @@ func testSignOutputCreateAccount(r *rpctest.Harness, w *lnwallet.LightningWallet
 			Value: 1000,
 		},
 		HashType:   txscript.SigHashAll,
-		SigHashes:  txscript.NewTxSigHashes(fakeTx),
+		SigHashes:  input.NewTxSigHashesV0Only(fakeTx),
 		InputIndex: 0,
 	}
 
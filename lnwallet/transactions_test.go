@@ -612,7 +612,7 @@ func testSpendValidation(t *testing.T, tweakless bool) {
 			PubKey: aliceKeyPub,
 		},
 		SingleTweak: remoteCommitTweak,
-		SigHashes:   txscript.NewTxSigHashes(sweepTx),
+		SigHashes:   input.NewTxSigHashesV0Only(sweepTx),
 		Output: &wire.TxOut{
 			Value: int64(channelBalance),
 		},
@@ -626,9 +626,12 @@ func testSpendValidation(t *testing.T, tweakless bool) {
 		t.Fatalf("unable to generate delay commit spend witness: %v", err)
 	}
 	sweepTx.TxIn[0].Witness = aliceWitnessSpend
+	prevOutFetcher := txscript.NewCannedPrevOutputFetcher(
+		delayOutput.PkScript, int64(channelBalance),
+	)
 	vm, err := txscript.NewEngine(delayOutput.PkScript,
 		sweepTx, 0, txscript.StandardVerifyFlags, nil,
-		nil, int64(channelBalance))
+		nil, int64(channelBalance), prevOutFetcher)
 	if err != nil {
 		t.Fatalf("unable to create engine: %v", err)
 	}
@@ -647,7 +650,7 @@ func testSpendValidation(t *testing.T, tweakless bool) {
 		},
 		DoubleTweak:   commitSecret,
 		WitnessScript: delayScript,
-		SigHashes:     txscript.NewTxSigHashes(sweepTx),
+		SigHashes:     input.NewTxSigHashesV0Only(sweepTx),
 		Output: &wire.TxOut{
 			Value: int64(channelBalance),
 		},
@@ -660,9 +663,12 @@ func testSpendValidation(t *testing.T, tweakless bool) {
 		t.Fatalf("unable to generate revocation witness: %v", err)
 	}
 	sweepTx.TxIn[0].Witness = bobWitnessSpend
+	prevOutFetcher = txscript.NewCannedPrevOutputFetcher(
+		delayOutput.PkScript, int64(channelBalance),
+	)
 	vm, err = txscript.NewEngine(delayOutput.PkScript,
 		sweepTx, 0, txscript.StandardVerifyFlags, nil,
-		nil, int64(channelBalance))
+		nil, int64(channelBalance), prevOutFetcher)
 	if err != nil {
 		t.Fatalf("unable to create engine: %v", err)
 	}
@@ -691,7 +697,7 @@ func testSpendValidation(t *testing.T, tweakless bool) {
 			PubKey: bobKeyPub,
 		},
 		WitnessScript: bobScriptP2WKH,
-		SigHashes:     txscript.NewTxSigHashes(sweepTx),
+		SigHashes:     input.NewTxSigHashesV0Only(sweepTx),
 		Output: &wire.TxOut{
 			Value:    int64(channelBalance),
 			PkScript: bobScriptP2WKH,
@@ -709,10 +715,13 @@ func testSpendValidation(t *testing.T, tweakless bool) {
 		t.Fatalf("unable to create bob regular spend: %v", err)
 	}
 	sweepTx.TxIn[0].Witness = bobRegularSpend
+	prevOutFetcher = txscript.NewCannedPrevOutputFetcher(
+		regularOutput.PkScript, int64(channelBalance),
+	)
 	vm, err = txscript.NewEngine(
 		regularOutput.PkScript,
 		sweepTx, 0, txscript.StandardVerifyFlags, nil,
-		nil, int64(channelBalance),
+		nil, int64(channelBalance), prevOutFetcher,
 	)
 	if err != nil {
 		t.Fatalf("unable to create engine: %v", err)
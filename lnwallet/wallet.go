@@ -147,6 +147,22 @@ type InitFundingReserveMsg struct {
 	// be using, like tweakless or anchors.
 	CommitType CommitmentType
 
+	// ZeroConf is a boolean that is true if a zero-conf channel was
+	// negotiated.
+	ZeroConf bool
+
+	// OptionScidAlias is a boolean that is true if an option-scid-alias
+	// channel type was explicitly negotiated.
+	OptionScidAlias bool
+
+	// DualFund is true if both parties contribute funds to the channel
+	// through an interactively constructed funding transaction.
+	DualFund bool
+
+	// Initiator is true if we initiated the opening of a dual funded
+	// channel. It's only used if DualFund is set.
+	Initiator bool
+
 	// ChanFunder is an optional channel funder that allows the caller to
 	// control exactly how the channel funding is carried out. If not
 	// specified, then the default chanfunding.WalletAssembler will be
@@ -205,6 +221,25 @@ type continueContributionMsg struct {
 	err chan error
 }
 
+// addInteractiveTxMsg represents a message executing the second phase of the
+// dual funded channel workflow, after the funding transaction was constructed
+// interactively with the remote party. Processing it signs our inputs to the
+// funding transaction and the remote node's version of the commitment
+// transaction.
+type addInteractiveTxMsg struct {
+	pendingFundingID uint64
+
+	// fundingTx is the interactively constructed funding transaction.
+	fundingTx *wire.MsgTx
+
+	// prevOutFetcher knows the outputs spent by all inputs of the funding
+	// transaction.
+	prevOutFetcher *txscript.MultiPrevOutFetcher
+
+	// NOTE: In order to avoid deadlocks, this channel MUST be buffered.
+	err chan error
+}
+
 // addSingleContributionMsg represents a message executing the second phase of
 // a single funder channel reservation workflow. This messages carries the
 // counterparty's "contribution" to the payment channel. As this message is
@@ -504,6 +539,8 @@ out:
 				l.handleContributionMsg(msg)
 			case *continueContributionMsg:
 				l.handleChanPointReady(msg)
+			case *addInteractiveTxMsg:
+				l.handleInteractiveTx(msg)
 			case *addSingleFunderSigsMsg:
 				l.handleSingleFunderSigs(msg)
 			case *addCounterPartySigsMsg:
@@ -844,12 +881,38 @@ func (l *LightningWallet) handleFundingReserveRequest(req *InitFundingReserveMsg
 	capacity := localFundingAmt + remoteFundingAmt
 
 	id := atomic.AddUint64(&l.nextFundingID, 1)
-	reservation, err := NewChannelReservation(
-		capacity, localFundingAmt, req.CommitFeePerKw, l, id,
-		req.PushMSat, l.Cfg.NetParams.GenesisHash, req.Flags,
-		req.CommitType, req.ChanFunder, req.PendingChanID,
-		thawHeight,
-	)
+
+	var reservation *ChannelReservation
+	switch {
+	// The funding transaction of a dual funded channel is constructed
+	// interactively, which requires the inputs to come from our wallet.
+	case req.DualFund:
+		if _, ok := fundingIntent.(*chanfunding.FullIntent); !ok {
+			err = errors.New("dual funded channels must be " +
+				"funded by the wallet")
+			break
+		}
+		if req.PushMSat != 0 {
+			err = errors.New("dual funded channels can't push " +
+				"funds")
+			break
+		}
+
+		reservation, err = NewDualFundedReservation(
+			localFundingAmt, remoteFundingAmt, req.Initiator,
+			req.CommitFeePerKw, l, id,
+			l.Cfg.NetParams.GenesisHash, req.Flags,
+			req.CommitType, req.ChanFunder, req.PendingChanID,
+		)
+
+	default:
+		reservation, err = NewChannelReservation(
+			capacity, localFundingAmt, req.CommitFeePerKw, l, id,
+			req.PushMSat, l.Cfg.NetParams.GenesisHash, req.Flags,
+			req.CommitType, req.ChanFunder, req.PendingChanID,
+			thawHeight,
+		)
+	}
 	if err != nil {
 		fundingIntent.Cancel()
 
@@ -858,6 +921,15 @@ func (l *LightningWallet) handleFundingReserveRequest(req *InitFundingReserveMsg
 		return
 	}
 
+	// Mark the channel type with the zero-conf and scid-alias bits if
+	// these were negotiated for this channel.
+	if req.ZeroConf {
+		reservation.partialState.ChanType |= channeldb.ZeroConfBit
+	}
+	if req.OptionScidAlias {
+		reservation.partialState.ChanType |= channeldb.ScidAliasChanBit
+	}
+
 	err = l.initOurContribution(
 		reservation, fundingIntent, req.NodeAddr, req.NodeID, keyRing,
 	)
@@ -1195,6 +1267,13 @@ func (l *LightningWallet) initOurContribution(reservation *ChannelReservation,
 		firstPreimage[:],
 	)
 
+	secondPreimage, err := producer.AtIndex(1)
+	if err != nil {
+		return err
+	}
+	secondPoint := input.ComputeCommitmentPoint(secondPreimage[:])
+	reservation.ourContribution.SecondCommitmentPoint = secondPoint
+
 	reservation.partialState.RevocationProducer = producer
 	reservation.ourContribution.ChannelConstraints = l.Cfg.DefaultConstraints
 
@@ -1556,12 +1635,24 @@ func (l *LightningWallet) handleChanPointReady(req *continueContributionMsg) {
 	// obfuscator then use it to encode the current state number within
 	// both commitment transactions.
 	var stateObfuscator [StateHintSize]byte
-	if chanState.ChanType.IsSingleFunder() {
+	switch {
+	// The obfuscator is derived from the payment base point of the
+	// initiator first. Interactively funded channels have an initiator as
+	// well, so they are treated the same way as single funder channels.
+	case chanState.ChanType.IsSingleFunder() ||
+		pendingReservation.interactive:
+
+		initiatorPoint := ourContribution.PaymentBasePoint.PubKey
+		responderPoint := theirContribution.PaymentBasePoint.PubKey
+		if !chanState.IsInitiator {
+			initiatorPoint, responderPoint = responderPoint,
+				initiatorPoint
+		}
 		stateObfuscator = DeriveStateHintObfuscator(
-			ourContribution.PaymentBasePoint.PubKey,
-			theirContribution.PaymentBasePoint.PubKey,
+			initiatorPoint, responderPoint,
 		)
-	} else {
+
+	default:
 		ourSer := ourContribution.PaymentBasePoint.PubKey.SerializeCompressed()
 		theirSer := theirContribution.PaymentBasePoint.PubKey.SerializeCompressed()
 		switch bytes.Compare(ourSer, theirSer) {
@@ -1617,7 +1708,7 @@ func (l *LightningWallet) handleChanPointReady(req *continueContributionMsg) {
 		KeyDesc:       ourKey,
 		Output:        fundingOutput,
 		HashType:      txscript.SigHashAll,
-		SigHashes:     txscript.NewTxSigHashes(theirCommitTx),
+		SigHashes:     input.NewTxSigHashesV0Only(theirCommitTx),
 		InputIndex:    0,
 	}
 	sigTheirCommit, err := l.Cfg.Signer.SignOutputRaw(theirCommitTx, &signDesc)
@@ -1683,16 +1774,103 @@ func (l *LightningWallet) handleSingleContribution(req *addSingleContributionMsg
 	// process is complete.
 	chanState.RemoteCurrentRevocation = theirContribution.FirstCommitmentPoint
 
+	// If the funding transaction is constructed interactively, we now
+	// know both multi-sig keys and can bind them to the intent, so the
+	// funding output can be added to the transaction.
+	if pendingReservation.interactive {
+		intent := pendingReservation.fundingIntent
+		fullIntent, ok := intent.(*chanfunding.FullIntent)
+		if !ok {
+			req.err <- fmt.Errorf("dual funded channel has no " +
+				"wallet funding intent")
+			return
+		}
+
+		fullIntent.BindKeys(
+			&pendingReservation.ourContribution.MultiSigKey,
+			theirContribution.MultiSigKey.PubKey,
+		)
+	}
+
 	req.err <- nil
 }
 
+// handleInteractiveTx is called as the second step of a dual funded channel
+// workflow, once the funding transaction was constructed interactively. Our
+// inputs to the funding transaction are signed, and both commitment
+// transactions are created, after which we sign the remote node's version.
+func (l *LightningWallet) handleInteractiveTx(req *addInteractiveTxMsg) {
+	l.limboMtx.Lock()
+	pendingReservation, ok := l.fundingLimbo[req.pendingFundingID]
+	l.limboMtx.Unlock()
+	if !ok {
+		req.err <- fmt.Errorf("attempted to update non-existent " +
+			"funding state")
+		return
+	}
+
+	// Grab the mutex on the ChannelReservation to ensure thread-safety.
+	pendingReservation.Lock()
+	defer pendingReservation.Unlock()
+
+	intent := pendingReservation.fundingIntent
+	fullIntent, ok := intent.(*chanfunding.FullIntent)
+	if !pendingReservation.interactive || !ok {
+		req.err <- fmt.Errorf("reservation has no interactive " +
+			"funding intent")
+		return
+	}
+
+	fundingTx := req.fundingTx
+	err := fullIntent.SignInteractiveFundingTx(
+		fundingTx, req.prevOutFetcher,
+	)
+	if err != nil {
+		req.err <- fmt.Errorf("unable to sign funding tx: %v", err)
+		return
+	}
+	chanPoint, err := fullIntent.ChanPoint()
+	if err != nil {
+		req.err <- fmt.Errorf("unable to obtain chan point: %v", err)
+		return
+	}
+
+	// Record the transaction along with the scripts of the inputs we
+	// signed. The witnesses of the remote party's inputs are only added
+	// once the commitment signatures were exchanged.
+	pendingReservation.fundingTx = fundingTx
+	pendingReservation.partialState.FundingOutpoint = *chanPoint
+	pendingReservation.ourFundingInputScripts = nil
+	for _, txIn := range fundingTx.TxIn {
+		if len(txIn.Witness) == 0 {
+			continue
+		}
+
+		pendingReservation.ourFundingInputScripts = append(
+			pendingReservation.ourFundingInputScripts,
+			&input.Script{
+				Witness:   txIn.Witness,
+				SigScript: txIn.SignatureScript,
+			},
+		)
+	}
+
+	walletLog.Tracef("Interactive funding tx for ChannelPoint(%v): %v",
+		chanPoint, spew.Sdump(fundingTx))
+
+	l.handleChanPointReady(&continueContributionMsg{
+		pendingFundingID: req.pendingFundingID,
+		err:              req.err,
+	})
+}
+
 // verifyFundingInputs attempts to verify all remote inputs to the funding
 // transaction.
 func (l *LightningWallet) verifyFundingInputs(fundingTx *wire.MsgTx,
 	remoteInputScripts []*input.Script) error {
 
 	sigIndex := 0
-	fundingHashCache := txscript.NewTxSigHashes(fundingTx)
+	fundingHashCache := input.NewTxSigHashesV0Only(fundingTx)
 	inputScripts := remoteInputScripts
 	for i, txin := range fundingTx.TxIn {
 		if len(inputScripts) != 0 && len(txin.Witness) == 0 {
@@ -1725,10 +1903,13 @@ func (l *LightningWallet) verifyFundingInputs(fundingTx *wire.MsgTx,
 			}
 
 			// Ensure that the witness+sigScript combo is valid.
+			prevOutFetcher := txscript.NewCannedPrevOutputFetcher(
+				output.PkScript, output.Value,
+			)
 			vm, err := txscript.NewEngine(
 				output.PkScript, fundingTx, i,
 				txscript.StandardVerifyFlags, nil,
-				fundingHashCache, output.Value,
+				fundingHashCache, output.Value, prevOutFetcher,
 			)
 			if err != nil {
 				return fmt.Errorf("cannot create script "+
@@ -1808,7 +1989,7 @@ func (l *LightningWallet) handleFundingCounterPartySigs(msg *addCounterPartySigs
 	// Next, create the spending scriptSig, and then verify that the script
 	// is complete, allowing us to spend from the funding transaction.
 	channelValue := int64(res.partialState.Capacity)
-	hashCache := txscript.NewTxSigHashes(commitTx)
+	hashCache := input.NewTxSigHashesV0Only(commitTx)
 	sigHash, err := txscript.CalcWitnessSigHash(
 		witnessScript, hashCache, txscript.SigHashAll, commitTx,
 		0, channelValue,
@@ -1959,7 +2140,7 @@ func (l *LightningWallet) handleSingleFunderSigs(req *addSingleFunderSigsMsg) {
 		req.fundingOutpoint, spew.Sdump(theirCommitTx))
 
 	channelValue := int64(pendingReservation.partialState.Capacity)
-	hashCache := txscript.NewTxSigHashes(ourCommitTx)
+	hashCache := input.NewTxSigHashesV0Only(ourCommitTx)
 	theirKey := pendingReservation.theirContribution.MultiSigKey
 	ourKey := pendingReservation.ourContribution.MultiSigKey
 	witnessScript, _, err := input.GenFundingPkScript(
@@ -2010,7 +2191,7 @@ func (l *LightningWallet) handleSingleFunderSigs(req *addSingleFunderSigsMsg) {
 			Value:    channelValue,
 		},
 		HashType:   txscript.SigHashAll,
-		SigHashes:  txscript.NewTxSigHashes(theirCommitTx),
+		SigHashes:  input.NewTxSigHashesV0Only(theirCommitTx),
 		InputIndex: 0,
 	}
 	sigTheirCommit, err := l.Cfg.Signer.SignOutputRaw(theirCommitTx, &signDesc)
@@ -0,0 +1,85 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/brronsuite/bronutil"
+)
+
+// ClosingComplete is sent by either party of a channel once both sent their
+// Shutdown message, if both signal support for RBF cooperative closes. The
+// sender, the closer, proposes a closing transaction whose fee is paid in full
+// from its own output, along with its signature for the transaction. The
+// closer can send another ClosingComplete with a higher fee at any time to
+// replace a closing transaction that doesn't confirm.
+//
+// NOTE: As with ClosingSigned, the closing transaction is assembled observing
+// BIP 69, so both sides arrive at an identical transaction given the fee and
+// the lock time.
+type ClosingComplete struct {
+	// ChannelID serves to identify which channel is to be closed.
+	ChannelID ChannelID
+
+	// FeeBroneess is the total fee in broneess of the closing transaction,
+	// which is deducted from the closer's output.
+	FeeBroneess bronutil.Amount
+
+	// LockTime is the lock time of the closing transaction.
+	LockTime uint32
+
+	// Signature is the closer's signature for the closing transaction.
+	Signature Sig
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure ClosingComplete implements the
+// lnwire.Message interface.
+var _ Message = (*ClosingComplete)(nil)
+
+// Decode deserializes a serialized ClosingComplete message stored in the
+// passed io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (c *ClosingComplete) Decode(r io.Reader, pver uint32) error {
+	return ReadElements(
+		r, &c.ChannelID, &c.FeeBroneess, &c.LockTime, &c.Signature,
+		&c.ExtraData,
+	)
+}
+
+// Encode serializes the target ClosingComplete into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (c *ClosingComplete) Encode(w *bytes.Buffer, pver uint32) error {
+	if err := WriteChannelID(w, c.ChannelID); err != nil {
+		return err
+	}
+
+	if err := WriteBronees(w, c.FeeBroneess); err != nil {
+		return err
+	}
+
+	if err := WriteUint32(w, c.LockTime); err != nil {
+		return err
+	}
+
+	if err := WriteSig(w, c.Signature); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, c.ExtraData)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (c *ClosingComplete) MsgType() MessageType {
+	return MsgClosingComplete
+}
@@ -0,0 +1,80 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/brronsuite/bronutil"
+)
+
+// ClosingSig is sent in response to a ClosingComplete message by the closee,
+// which is the party that doesn't pay the fee of the proposed closing
+// transaction. It carries the closee's signature, which completes the closing
+// transaction proposed by the closer.
+type ClosingSig struct {
+	// ChannelID serves to identify which channel is to be closed.
+	ChannelID ChannelID
+
+	// FeeBroneess is the fee of the closing transaction being signed, as
+	// proposed in the ClosingComplete message.
+	FeeBroneess bronutil.Amount
+
+	// LockTime is the lock time of the closing transaction being signed,
+	// as proposed in the ClosingComplete message.
+	LockTime uint32
+
+	// Signature is the closee's signature for the closing transaction.
+	Signature Sig
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure ClosingSig implements the lnwire.Message
+// interface.
+var _ Message = (*ClosingSig)(nil)
+
+// Decode deserializes a serialized ClosingSig message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (c *ClosingSig) Decode(r io.Reader, pver uint32) error {
+	return ReadElements(
+		r, &c.ChannelID, &c.FeeBroneess, &c.LockTime, &c.Signature,
+		&c.ExtraData,
+	)
+}
+
+// Encode serializes the target ClosingSig into the passed io.Writer observing
+// the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (c *ClosingSig) Encode(w *bytes.Buffer, pver uint32) error {
+	if err := WriteChannelID(w, c.ChannelID); err != nil {
+		return err
+	}
+
+	if err := WriteBronees(w, c.FeeBroneess); err != nil {
+		return err
+	}
+
+	if err := WriteUint32(w, c.LockTime); err != nil {
+		return err
+	}
+
+	if err := WriteSig(w, c.Signature); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, c.ExtraData)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (c *ClosingSig) MsgType() MessageType {
+	return MsgClosingSig
+}
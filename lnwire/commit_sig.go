@@ -36,6 +36,11 @@ type CommitSig struct {
 	// transaction should be signed.
 	HtlcSigs []Sig
 
+	// SpliceSigs holds the signatures for the commitments that spend the
+	// funding outputs of the pending splices of the channel. They are
+	// only present while a splice awaits being locked.
+	SpliceSigs SpliceSigs
+
 	// ExtraData is the set of data that was appended to this message to
 	// fill out the full maximum transport message size. These fields can
 	// be used to specify optional data such as custom TLV fields.
@@ -58,12 +63,33 @@ var _ Message = (*CommitSig)(nil)
 //
 // This is part of the lnwire.Message interface.
 func (c *CommitSig) Decode(r io.Reader, pver uint32) error {
-	return ReadElements(r,
+	err := ReadElements(r,
 		&c.ChanID,
 		&c.CommitSig,
 		&c.HtlcSigs,
-		&c.ExtraData,
 	)
+	if err != nil {
+		return err
+	}
+
+	var tlvRecords ExtraOpaqueData
+	if err := ReadElements(r, &tlvRecords); err != nil {
+		return err
+	}
+
+	var spliceSigs SpliceSigs
+	typeMap, err := tlvRecords.ExtractRecords(&spliceSigs)
+	if err != nil {
+		return err
+	}
+
+	if val, ok := typeMap[SpliceSigsRecordType]; ok && val == nil {
+		c.SpliceSigs = spliceSigs
+	}
+
+	c.ExtraData = tlvRecords
+
+	return nil
 }
 
 // Encode serializes the target CommitSig into the passed io.Writer
@@ -71,6 +97,15 @@ func (c *CommitSig) Decode(r io.Reader, pver uint32) error {
 //
 // This is part of the lnwire.Message interface.
 func (c *CommitSig) Encode(w *bytes.Buffer, pver uint32) error {
+	// The extra data is only replaced if there are splice signatures to
+	// encode, so any other records are sent as is otherwise.
+	if len(c.SpliceSigs) > 0 {
+		err := EncodeMessageExtraData(&c.ExtraData, &c.SpliceSigs)
+		if err != nil {
+			return err
+		}
+	}
+
 	if err := WriteChannelID(w, c.ChanID); err != nil {
 		return err
 	}
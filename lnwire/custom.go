@@ -10,6 +10,43 @@ import (
 // defined in BOLT 01.
 var CustomTypeStart MessageType = 32768
 
+var (
+	// ErrReservedCustomType is returned when a custom message is created
+	// with a type that is reserved for one of our protocol messages.
+	ErrReservedCustomType = errors.New("msg type reserved for protocol " +
+		"message")
+
+	// reservedCustomTypes are the types in the custom range that are used
+	// by protocol messages we parse ourselves. Messages of these types are
+	// never delivered as custom messages, and sending them as custom
+	// messages would inject them into the protocol flows of our peers.
+	reservedCustomTypes = map[MessageType]struct{}{
+		MsgClosingComplete: {},
+		MsgClosingSig:      {},
+		MsgOpenChannel2:    {},
+		MsgAcceptChannel2:  {},
+		MsgTxAddInput:      {},
+		MsgTxAddOutput:     {},
+		MsgTxRemoveInput:   {},
+		MsgTxRemoveOutput:  {},
+		MsgTxComplete:      {},
+		MsgTxSignatures:    {},
+		MsgTxInitRbf:       {},
+		MsgTxAckRbf:        {},
+		MsgTxAbort:         {},
+		MsgSpliceLocked:    {},
+		MsgSpliceInit:      {},
+		MsgSpliceAck:       {},
+	}
+)
+
+// IsReservedCustomType returns true if the given message type is in the custom
+// range, but reserved for one of our protocol messages.
+func IsReservedCustomType(msgType MessageType) bool {
+	_, ok := reservedCustomTypes[msgType]
+	return ok
+}
+
 // Custom represents an application-defined wire message.
 type Custom struct {
 	Type MessageType
@@ -26,6 +63,10 @@ func NewCustom(msgType MessageType, data []byte) (*Custom, error) {
 		return nil, errors.New("msg type not in custom range")
 	}
 
+	if IsReservedCustomType(msgType) {
+		return nil, ErrReservedCustomType
+	}
+
 	return &Custom{
 		Type: msgType,
 		Data: data,
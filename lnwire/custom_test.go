@@ -0,0 +1,43 @@
+package lnwire
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestNewCustomReservedType asserts that custom messages can't be created with
+// a type that is reserved for one of our protocol messages.
+func TestNewCustomReservedType(t *testing.T) {
+	t.Parallel()
+
+	reservedTypes := []MessageType{
+		MsgClosingComplete, MsgClosingSig, MsgOpenChannel2,
+		MsgAcceptChannel2, MsgTxAddInput, MsgTxAddOutput,
+		MsgTxRemoveInput, MsgTxRemoveOutput, MsgTxComplete,
+		MsgTxSignatures, MsgTxInitRbf, MsgTxAckRbf, MsgTxAbort,
+		MsgSpliceLocked, MsgSpliceInit, MsgSpliceAck,
+	}
+	for _, msgType := range reservedTypes {
+		_, err := NewCustom(msgType, nil)
+		require.ErrorIs(t, err, ErrReservedCustomType)
+	}
+
+	_, err := NewCustom(CustomTypeStart, nil)
+	require.NoError(t, err)
+
+	// Every type in the custom range that we parse as a protocol message
+	// must be reserved.
+	for msgType := CustomTypeStart; msgType != 0; msgType++ {
+		msg, err := makeEmptyMessage(msgType)
+		require.NoError(t, err)
+
+		if _, ok := msg.(*Custom); ok {
+			continue
+		}
+		require.Truef(
+			t, IsReservedCustomType(msgType),
+			"type %v not reserved", msgType,
+		)
+	}
+}
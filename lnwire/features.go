@@ -129,6 +129,16 @@ const (
 	// transactions, which also imply anchor commitments.
 	AnchorsZeroFeeHtlcTxOptional FeatureBit = 23
 
+	// RouteBlindingRequired is a required feature bit that signals that
+	// the node requires its peers to be able to forward payments through
+	// blinded routes.
+	RouteBlindingRequired FeatureBit = 24
+
+	// RouteBlindingOptional is an optional feature bit that signals that
+	// the node is able to forward and receive payments through blinded
+	// routes.
+	RouteBlindingOptional FeatureBit = 25
+
 	// AMPRequired is a required feature bit that signals that the receiver
 	// of a payment supports accepts spontaneous payments, i.e.
 	// sender-generated preimages according to BOLT XX.
@@ -139,6 +149,33 @@ const (
 	// sender-generated preimages according to BOLT XX.
 	AMPOptional FeatureBit = 31
 
+	// QuiescenceRequired is a required feature bit that signals that the
+	// node requires its peers to understand the stfu message used to
+	// bring a channel into a quiescent state.
+	QuiescenceRequired FeatureBit = 34
+
+	// QuiescenceOptional is an optional feature bit that signals that the
+	// node understands the stfu message used to bring a channel into a
+	// quiescent state.
+	QuiescenceOptional FeatureBit = 35
+
+	// OnionMessagesRequired is a required feature bit that signals that
+	// the node requires its peers to forward onion messages.
+	OnionMessagesRequired FeatureBit = 38
+
+	// OnionMessagesOptional is an optional feature bit that signals that
+	// the node is able to forward and receive onion messages.
+	OnionMessagesOptional FeatureBit = 39
+
+	// ProvideStorageRequired is a required feature bit that signals that
+	// the node requires its peers to store a backup blob on its behalf.
+	ProvideStorageRequired FeatureBit = 42
+
+	// ProvideStorageOptional is an optional feature bit that signals that
+	// the node is willing to store a backup blob on behalf of its channel
+	// peers and hand it back to them when they reconnect.
+	ProvideStorageOptional FeatureBit = 43
+
 	// ExplicitChannelTypeRequired is a required bit that denotes that a
 	// connection established with this node is to use explicit channel
 	// commitment types for negotiation instead of the existing implicit
@@ -155,6 +192,78 @@ const (
 	// TODO: Decide on actual feature bit value.
 	ExplicitChannelTypeOptional = 45
 
+	// ScidAliasRequired is a required feature bit that signals that the
+	// node requires understanding of ShortChannelID aliases in the TLV
+	// segment of the funding_locked message.
+	ScidAliasRequired FeatureBit = 46
+
+	// ScidAliasOptional is an optional feature bit that signals that the
+	// node understands ShortChannelID aliases.
+	ScidAliasOptional FeatureBit = 47
+
+	// ZeroConfRequired is a required feature bit that signals that the
+	// node requires understanding of the zero-conf channel_type.
+	ZeroConfRequired FeatureBit = 50
+
+	// ZeroConfOptional is an optional feature bit that signals that the
+	// node understands the zero-conf channel type.
+	ZeroConfOptional FeatureBit = 51
+
+	// TrampolineRoutingRequired is a required feature bit that signals
+	// that the node requires its peers to delegate path finding to
+	// trampoline nodes.
+	TrampolineRoutingRequired FeatureBit = 56
+
+	// TrampolineRoutingOptional is an optional feature bit that signals
+	// that the node is able to find routes on behalf of payers as a
+	// trampoline node. In invoices, it signals that the route hints point
+	// to trampoline nodes of the recipient.
+	TrampolineRoutingOptional FeatureBit = 57
+
+	// RbfCoopCloseRequired is a required feature bit that signals that
+	// the node requires cooperative closes to be negotiated with the RBF
+	// based closing_complete/closing_sig protocol.
+	//
+	// NOTE: The messages of the protocol deviate from the proposed
+	// option_simple_close specification, so it is signalled with an
+	// experimental bit instead of bit 60.
+	RbfCoopCloseRequired FeatureBit = 160
+
+	// RbfCoopCloseOptional is an optional feature bit that signals that
+	// the node supports cooperative closes negotiated with the RBF based
+	// closing_complete/closing_sig protocol, which allows either party to
+	// bump the fee of the closing transaction.
+	RbfCoopCloseOptional FeatureBit = 161
+
+	// DualFundRequired is a required feature bit that signals that the
+	// node requires its peers to open channels through the dual funded
+	// channel establishment protocol.
+	//
+	// NOTE: The protocol deviates from option_dual_fund by exchanging the
+	// initial commitment signatures through funding_created and
+	// funding_signed, so it is signalled with an experimental bit instead
+	// of bit 28.
+	DualFundRequired FeatureBit = 162
+
+	// DualFundOptional is an optional feature bit that signals that the
+	// node is able to open and accept dual funded channels.
+	DualFundOptional FeatureBit = 163
+
+	// SpliceRequired is a required feature bit that signals that the node
+	// requires its peers to support resizing channels on-chain through a
+	// splice transaction.
+	//
+	// NOTE: The protocol deviates from the proposed splicing
+	// specification by moving the channel to the channel ID of the new
+	// funding outpoint, so it is signalled with an experimental bit
+	// instead of bit 62.
+	SpliceRequired FeatureBit = 164
+
+	// SpliceOptional is an optional feature bit that signals that the
+	// node is able to resize channels on-chain through a splice
+	// transaction.
+	SpliceOptional FeatureBit = 165
+
 	// ScriptEnforcedLeaseOptional is an optional feature bit that signals
 	// that the node requires channels having zero-fee second-level HTLC
 	// transactions, which also imply anchor commitments, along with an
@@ -214,14 +323,34 @@ var Features = map[FeatureBit]string{
 	AnchorsOptional:               "anchor-commitments",
 	AnchorsZeroFeeHtlcTxRequired:  "anchors-zero-fee-htlc-tx",
 	AnchorsZeroFeeHtlcTxOptional:  "anchors-zero-fee-htlc-tx",
+	RouteBlindingRequired:         "route-blinding",
+	RouteBlindingOptional:         "route-blinding",
 	WumboChannelsRequired:         "wumbo-channels",
 	WumboChannelsOptional:         "wumbo-channels",
+	DualFundRequired:              "dual-fund",
+	DualFundOptional:              "dual-fund",
 	AMPRequired:                   "amp",
 	AMPOptional:                   "amp",
+	OnionMessagesRequired:         "onion-messages",
+	OnionMessagesOptional:         "onion-messages",
+	ProvideStorageRequired:        "provide-storage",
+	ProvideStorageOptional:        "provide-storage",
 	ExplicitChannelTypeOptional:   "explicit-commitment-type",
 	ExplicitChannelTypeRequired:   "explicit-commitment-type",
 	ScriptEnforcedLeaseRequired:   "script-enforced-lease",
 	ScriptEnforcedLeaseOptional:   "script-enforced-lease",
+	ScidAliasRequired:             "scid-alias",
+	ScidAliasOptional:             "scid-alias",
+	ZeroConfRequired:              "zero-conf",
+	ZeroConfOptional:              "zero-conf",
+	QuiescenceRequired:            "quiescence",
+	QuiescenceOptional:            "quiescence",
+	RbfCoopCloseRequired:          "rbf-coop-close",
+	RbfCoopCloseOptional:          "rbf-coop-close",
+	SpliceRequired:                "splice",
+	SpliceOptional:                "splice",
+	TrampolineRoutingRequired:     "trampoline-routing",
+	TrampolineRoutingOptional:     "trampoline-routing",
 }
 
 // RawFeatureVector represents a set of feature bits as defined in BOLT-09.  A
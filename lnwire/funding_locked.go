@@ -4,9 +4,16 @@ import (
 	"bytes"
 	"io"
 
+	"github.com/brronsuite/broln/tlv"
 	"github.com/brronsuite/brond/btcec"
 )
 
+const (
+	// AliasScidRecordType is the type of the record used to communicate
+	// the ShortChannelID alias of a channel in the FundingLocked message.
+	AliasScidRecordType tlv.Type = 1
+)
+
 // FundingLocked is the message that both parties to a new channel creation
 // send once they have observed the funding transaction being confirmed on the
 // blockchain. FundingLocked contains the signatures necessary for the channel
@@ -21,6 +28,11 @@ type FundingLocked struct {
 	// next commitment transaction for the channel.
 	NextPerCommitmentPoint *btcec.PublicKey
 
+	// AliasScid is an alias ShortChannelID used to refer to the underlying
+	// channel. It can be used instead of the confirmed on-chain
+	// ShortChannelID for forwarding. This is an optional TLV field.
+	AliasScid *ShortChannelID
+
 	// ExtraData is the set of data that was appended to this message to
 	// fill out the full maximum transport message size. These fields can
 	// be used to specify optional data such as custom TLV fields.
@@ -47,11 +59,36 @@ var _ Message = (*FundingLocked)(nil)
 //
 // This is part of the lnwire.Message interface.
 func (c *FundingLocked) Decode(r io.Reader, pver uint32) error {
-	return ReadElements(r,
+	// Read all the mandatory fields in the message.
+	err := ReadElements(r,
 		&c.ChanID,
 		&c.NextPerCommitmentPoint,
-		&c.ExtraData,
 	)
+	if err != nil {
+		return err
+	}
+
+	// Next we'll parse out the set of known records, keeping the raw tlv
+	// bytes untouched to ensure we don't drop any bytes erroneously.
+	var tlvRecords ExtraOpaqueData
+	if err := ReadElements(r, &tlvRecords); err != nil {
+		return err
+	}
+
+	var aliasScid ShortChannelID
+	typeMap, err := tlvRecords.ExtractRecords(&aliasScid)
+	if err != nil {
+		return err
+	}
+
+	// Set the corresponding TLV types if they were included in the stream.
+	if val, ok := typeMap[AliasScidRecordType]; ok && val == nil {
+		c.AliasScid = &aliasScid
+	}
+
+	c.ExtraData = tlvRecords
+
+	return nil
 }
 
 // Encode serializes the target FundingLocked message into the passed io.Writer
@@ -68,6 +105,14 @@ func (c *FundingLocked) Encode(w *bytes.Buffer, pver uint32) error {
 		return err
 	}
 
+	// We'll only encode the AliasScid in a TLV segment if it exists.
+	if c.AliasScid != nil {
+		err := EncodeMessageExtraData(&c.ExtraData, c.AliasScid)
+		if err != nil {
+			return err
+		}
+	}
+
 	return WriteBytes(w, c.ExtraData)
 }
 
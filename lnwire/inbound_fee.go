@@ -0,0 +1,159 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/brronsuite/broln/tlv"
+)
+
+const (
+	// InboundFeeRecordType is the type of the record that carries the
+	// inbound fee of a channel in the extra opaque data of a
+	// ChannelUpdate.
+	InboundFeeRecordType tlv.Type = 55555
+)
+
+// InboundFee is the fee a node charges for htlcs that arrive over a channel,
+// on top of the fee of the channel over which they are forwarded. Both
+// components may be negative, in which case they are a discount on the
+// outbound fee. The total fee of a forward is never negative.
+type InboundFee struct {
+	// BaseFee is the base fee in millibronees.
+	BaseFee int32
+
+	// FeeRate is the proportional fee in millionths of the amount.
+	FeeRate int32
+}
+
+// CalcFee calculates the inbound fee for the given amount. To keep the integer
+// math simple, positive fees are rounded down while negative fees are rounded
+// up.
+func (f InboundFee) CalcFee(amt MilliBronees) int64 {
+	return int64(f.BaseFee) + int64(f.FeeRate)*int64(amt)/1_000_000
+}
+
+// IsZero returns true if neither an inbound base fee nor an inbound fee rate
+// is set.
+func (f InboundFee) IsZero() bool {
+	return f.BaseFee == 0 && f.FeeRate == 0
+}
+
+// Record returns a TLV record that can be used to encode/decode the
+// InboundFee type from a given TLV stream.
+func (f *InboundFee) Record() tlv.Record {
+	return tlv.MakeStaticRecord(
+		InboundFeeRecordType, f, 8, inboundFeeEncoder,
+		inboundFeeDecoder,
+	)
+}
+
+// inboundFeeEncoder is a custom TLV encoder for the InboundFee record.
+func inboundFeeEncoder(w io.Writer, val interface{}, buf *[8]byte) error {
+	if v, ok := val.(*InboundFee); ok {
+		if err := tlv.EUint32T(w, uint32(v.BaseFee), buf); err != nil {
+			return err
+		}
+
+		return tlv.EUint32T(w, uint32(v.FeeRate), buf)
+	}
+
+	return tlv.NewTypeForEncodingErr(val, "lnwire.InboundFee")
+}
+
+// inboundFeeDecoder is a custom TLV decoder for the InboundFee record.
+func inboundFeeDecoder(r io.Reader, val interface{}, buf *[8]byte,
+	l uint64) error {
+
+	if v, ok := val.(*InboundFee); ok && l == 8 {
+		var baseFee, feeRate uint32
+		if err := tlv.DUint32(r, &baseFee, buf, 4); err != nil {
+			return err
+		}
+		if err := tlv.DUint32(r, &feeRate, buf, 4); err != nil {
+			return err
+		}
+
+		v.BaseFee = int32(baseFee)
+		v.FeeRate = int32(feeRate)
+
+		return nil
+	}
+
+	return tlv.NewTypeForDecodingErr(val, "lnwire.InboundFee", l, 8)
+}
+
+// ParseInboundFee returns the inbound fee that is carried in the extra opaque
+// data of a ChannelUpdate. A zero fee is returned if the data doesn't carry an
+// inbound fee.
+func ParseInboundFee(extraData ExtraOpaqueData) (InboundFee, error) {
+	var fee InboundFee
+	if len(extraData) == 0 {
+		return fee, nil
+	}
+
+	if _, err := extraData.ExtractRecords(&fee); err != nil {
+		return InboundFee{}, err
+	}
+
+	return fee, nil
+}
+
+// InboundFee returns the inbound fee that is carried in the extra opaque data
+// of the channel update. A zero fee is returned if the update doesn't carry
+// an inbound fee.
+func (a *ChannelUpdate) InboundFee() (InboundFee, error) {
+	return ParseInboundFee(a.ExtraOpaqueData)
+}
+
+// EncodeInboundFee returns the extra opaque data of a ChannelUpdate that
+// carries the given inbound fee. Empty data is returned for a zero fee, such
+// that channel updates without an inbound fee remain unchanged.
+func EncodeInboundFee(fee InboundFee) (ExtraOpaqueData, error) {
+	return SetInboundFee(nil, fee)
+}
+
+// SetInboundFee returns the given extra opaque data of a ChannelUpdate with
+// its inbound fee record replaced by the given inbound fee. All other records
+// are kept as they are. The inbound fee record is removed for a zero fee.
+func SetInboundFee(extraData ExtraOpaqueData,
+	fee InboundFee) (ExtraOpaqueData, error) {
+
+	var oldFee InboundFee
+	parsedTypes, err := extraData.ExtractRecords(&oldFee)
+	if err != nil {
+		return nil, err
+	}
+
+	// The records we don't know are returned with their raw value, which
+	// we encode as is.
+	tlvMap := make(map[uint64][]byte, len(parsedTypes))
+	for typ, value := range parsedTypes {
+		if typ == InboundFeeRecordType {
+			continue
+		}
+
+		tlvMap[uint64(typ)] = value
+	}
+
+	records := tlv.MapToRecords(tlvMap)
+	if !fee.IsZero() {
+		records = append(records, fee.Record())
+		tlv.SortRecords(records)
+	}
+
+	tlvStream, err := tlv.NewStream(records...)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if err := tlvStream.Encode(&b); err != nil {
+		return nil, err
+	}
+
+	newExtraData := make(ExtraOpaqueData, 0, b.Len())
+	newExtraData = append(newExtraData, b.Bytes()...)
+
+	return newExtraData, nil
+}
@@ -0,0 +1,95 @@
+package lnwire
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestInboundFeeEncodeDecode tests that we're able to properly encode and
+// decode inbound fees within the extra data of a channel update.
+func TestInboundFeeEncodeDecode(t *testing.T) {
+	t.Parallel()
+
+	fee := InboundFee{
+		BaseFee: -1000,
+		FeeRate: 250,
+	}
+
+	extraData, err := EncodeInboundFee(fee)
+	require.NoError(t, err)
+
+	update := &ChannelUpdate{
+		ExtraOpaqueData: extraData,
+	}
+	fee2, err := update.InboundFee()
+	require.NoError(t, err)
+	require.Equal(t, fee, fee2)
+
+	// A zero fee must not add any extra data.
+	extraData, err = EncodeInboundFee(InboundFee{})
+	require.NoError(t, err)
+	require.Empty(t, extraData)
+
+	fee2, err = ParseInboundFee(extraData)
+	require.NoError(t, err)
+	require.True(t, fee2.IsZero())
+}
+
+// TestInboundFeeCalcFee tests that inbound fees are rounded towards zero.
+func TestInboundFeeCalcFee(t *testing.T) {
+	t.Parallel()
+
+	fee := InboundFee{
+		BaseFee: 10,
+		FeeRate: 1,
+	}
+	require.EqualValues(t, 11, fee.CalcFee(1_999_999))
+
+	fee = InboundFee{
+		BaseFee: -10,
+		FeeRate: -1,
+	}
+	require.EqualValues(t, -11, fee.CalcFee(1_999_999))
+}
+
+// TestSetInboundFee tests that setting the inbound fee of a channel update
+// keeps the other records of its extra data.
+func TestSetInboundFee(t *testing.T) {
+	t.Parallel()
+
+	// The extra data carries a record below and a record above the inbound
+	// fee record.
+	otherRecords := ExtraOpaqueData{
+		0x01, 0x02, 0xaa, 0xbb,
+		0xfd, 0xff, 0xff, 0x01, 0xcc,
+	}
+
+	fee := InboundFee{
+		BaseFee: -1000,
+		FeeRate: -250,
+	}
+	extraData, err := SetInboundFee(otherRecords, fee)
+	require.NoError(t, err)
+
+	fee2, err := ParseInboundFee(extraData)
+	require.NoError(t, err)
+	require.Equal(t, fee, fee2)
+
+	// Replacing the inbound fee must not duplicate its record.
+	fee = InboundFee{
+		BaseFee: -500,
+	}
+	extraData, err = SetInboundFee(extraData, fee)
+	require.NoError(t, err)
+
+	fee2, err = ParseInboundFee(extraData)
+	require.NoError(t, err)
+	require.Equal(t, fee, fee2)
+
+	// A zero fee removes the inbound fee record, leaving the other records
+	// untouched.
+	extraData, err = SetInboundFee(extraData, InboundFee{})
+	require.NoError(t, err)
+	require.Equal(t, otherRecords, extraData)
+}
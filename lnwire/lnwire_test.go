@@ -45,6 +45,34 @@ func randAlias(r *rand.Rand) NodeAlias {
 	return a
 }
 
+// randSpliceSig generates random signatures for the commitment of a pending
+// splice.
+func randSpliceSig(r *rand.Rand) (SpliceSig, error) {
+	var sig SpliceSig
+	if _, err := r.Read(sig.FundingTxID[:]); err != nil {
+		return sig, err
+	}
+
+	var err error
+	sig.CommitSig, err = NewSigFromSignature(testSig)
+	if err != nil {
+		return sig, err
+	}
+
+	numSigs := r.Intn(5)
+	if numSigs > 0 {
+		sig.HtlcSigs = make([]Sig, numSigs)
+	}
+	for i := range sig.HtlcSigs {
+		sig.HtlcSigs[i], err = NewSigFromSignature(testSig)
+		if err != nil {
+			return sig, err
+		}
+	}
+
+	return sig, nil
+}
+
 func randPubKey() (*bronec.PublicKey, error) {
 	priv, err := bronec.NewPrivateKey(bronec.S256())
 	if err != nil {
@@ -501,6 +529,240 @@ func TestLightningWireProtocol(t *testing.T) {
 
 			v[0] = reflect.ValueOf(req)
 		},
+		MsgOpenChannel2: func(v []reflect.Value, r *rand.Rand) {
+			req := OpenChannel2{
+				FundingFeePerKiloWeight: uint32(r.Int31()),
+				CommitFeePerKiloWeight:  uint32(r.Int31()),
+				FundingAmount:           bronutil.Amount(r.Int63()),
+				DustLimit:               bronutil.Amount(r.Int63()),
+				MaxValueInFlight:        MilliBronees(r.Int63()),
+				HtlcMinimum:             MilliBronees(r.Int31()),
+				CsvDelay:                uint16(r.Int31()),
+				MaxAcceptedHTLCs:        uint16(r.Int31()),
+				LockTime:                uint32(r.Int31()),
+				ChannelFlags:            FundingFlag(uint8(r.Int31())),
+			}
+
+			if _, err := r.Read(req.ChainHash[:]); err != nil {
+				t.Fatalf("unable to generate chain hash: %v", err)
+				return
+			}
+
+			if _, err := r.Read(req.PendingChannelID[:]); err != nil {
+				t.Fatalf("unable to generate pending chan id: %v", err)
+				return
+			}
+
+			keys := []**bronec.PublicKey{
+				&req.FundingKey, &req.RevocationPoint,
+				&req.PaymentPoint, &req.DelayedPaymentPoint,
+				&req.HtlcPoint, &req.FirstCommitmentPoint,
+				&req.SecondCommitmentPoint,
+			}
+			for _, key := range keys {
+				var err error
+				*key, err = randPubKey()
+				if err != nil {
+					t.Fatalf("unable to generate key: %v", err)
+					return
+				}
+			}
+
+			// 1/2 chance empty TLV records.
+			if r.Intn(2) == 0 {
+				var err error
+				req.UpfrontShutdownScript, err = randDeliveryAddress(r)
+				if err != nil {
+					t.Fatalf("unable to generate delivery "+
+						"address: %v", err)
+					return
+				}
+
+				req.ChannelType = new(ChannelType)
+				*req.ChannelType = ChannelType(
+					*randRawFeatureVector(r),
+				)
+			} else {
+				req.UpfrontShutdownScript = []byte{}
+			}
+
+			// 1/2 chance additional TLV data.
+			if r.Intn(2) == 0 {
+				req.ExtraData = []byte{0xfd, 0x00, 0xff, 0x00}
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgAcceptChannel2: func(v []reflect.Value, r *rand.Rand) {
+			req := AcceptChannel2{
+				FundingAmount:    bronutil.Amount(r.Int63()),
+				DustLimit:        bronutil.Amount(r.Int63()),
+				MaxValueInFlight: MilliBronees(r.Int63()),
+				HtlcMinimum:      MilliBronees(r.Int31()),
+				MinAcceptDepth:   uint32(r.Int31()),
+				CsvDelay:         uint16(r.Int31()),
+				MaxAcceptedHTLCs: uint16(r.Int31()),
+			}
+
+			if _, err := r.Read(req.PendingChannelID[:]); err != nil {
+				t.Fatalf("unable to generate pending chan id: %v", err)
+				return
+			}
+
+			keys := []**bronec.PublicKey{
+				&req.FundingKey, &req.RevocationPoint,
+				&req.PaymentPoint, &req.DelayedPaymentPoint,
+				&req.HtlcPoint, &req.FirstCommitmentPoint,
+				&req.SecondCommitmentPoint,
+			}
+			for _, key := range keys {
+				var err error
+				*key, err = randPubKey()
+				if err != nil {
+					t.Fatalf("unable to generate key: %v", err)
+					return
+				}
+			}
+
+			// 1/2 chance empty TLV records.
+			if r.Intn(2) == 0 {
+				var err error
+				req.UpfrontShutdownScript, err = randDeliveryAddress(r)
+				if err != nil {
+					t.Fatalf("unable to generate delivery "+
+						"address: %v", err)
+					return
+				}
+
+				req.ChannelType = new(ChannelType)
+				*req.ChannelType = ChannelType(
+					*randRawFeatureVector(r),
+				)
+			} else {
+				req.UpfrontShutdownScript = []byte{}
+			}
+
+			// 1/2 chance additional TLV data.
+			if r.Intn(2) == 0 {
+				req.ExtraData = []byte{0xfd, 0x00, 0xff, 0x00}
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgTxSignatures: func(v []reflect.Value, r *rand.Rand) {
+			req := TxSignatures{
+				ExtraData: make([]byte, 0),
+			}
+
+			if _, err := r.Read(req.ChannelID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+
+			if _, err := r.Read(req.TxID[:]); err != nil {
+				t.Fatalf("unable to generate txid: %v", err)
+				return
+			}
+
+			numWitnesses := r.Intn(4)
+			for i := 0; i < numWitnesses; i++ {
+				witness := make(wire.TxWitness, r.Intn(3)+1)
+				for j := range witness {
+					witness[j] = make([]byte, r.Intn(80))
+					_, err := r.Read(witness[j])
+					if err != nil {
+						t.Fatalf("unable to generate "+
+							"witness: %v", err)
+						return
+					}
+				}
+				req.Witnesses = append(req.Witnesses, witness)
+			}
+
+			// 1/2 chance to attach a shared input signature.
+			if r.Intn(2) == 0 {
+				var sig Sig
+				if _, err := r.Read(sig[:]); err != nil {
+					t.Fatalf("unable to generate sig: %v",
+						err)
+					return
+				}
+				req.SharedInputSig = &sig
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgOnionMessage: func(v []reflect.Value, r *rand.Rand) {
+			pubKey, err := randPubKey()
+			if err != nil {
+				t.Fatalf("unable to generate key: %v", err)
+				return
+			}
+
+			onionBlob := make([]byte, 1366)
+			if _, err := r.Read(onionBlob); err != nil {
+				t.Fatalf("unable to generate onion blob: %v",
+					err)
+				return
+			}
+
+			req := NewOnionMessage(pubKey, onionBlob)
+
+			v[0] = reflect.ValueOf(*req)
+		},
+		MsgPeerStorage: func(v []reflect.Value, r *rand.Rand) {
+			blob := make([]byte, r.Intn(MaxPeerStorageBlobSize+1))
+			if _, err := r.Read(blob); err != nil {
+				t.Fatalf("unable to generate blob: %v", err)
+				return
+			}
+
+			v[0] = reflect.ValueOf(*NewPeerStorage(blob))
+		},
+		MsgPeerStorageRetrieval: func(v []reflect.Value, r *rand.Rand) {
+			blob := make([]byte, r.Intn(MaxPeerStorageBlobSize+1))
+			if _, err := r.Read(blob); err != nil {
+				t.Fatalf("unable to generate blob: %v", err)
+				return
+			}
+
+			v[0] = reflect.ValueOf(*NewPeerStorageRetrieval(blob))
+		},
+		MsgUpdateAddHTLC: func(v []reflect.Value, r *rand.Rand) {
+			req := UpdateAddHTLC{
+				ID:        r.Uint64(),
+				Amount:    MilliBronees(r.Int63()),
+				Expiry:    r.Uint32(),
+				ExtraData: make([]byte, 0),
+			}
+
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+			if _, err := r.Read(req.PaymentHash[:]); err != nil {
+				t.Fatalf("unable to generate hash: %v", err)
+				return
+			}
+			if _, err := r.Read(req.OnionBlob[:]); err != nil {
+				t.Fatalf("unable to generate onion blob: %v",
+					err)
+				return
+			}
+
+			// 1/2 chance to attach a blinding point.
+			if r.Intn(2) == 0 {
+				var err error
+				req.BlindingPoint, err = randPubKey()
+				if err != nil {
+					t.Fatalf("unable to generate key: %v",
+						err)
+					return
+				}
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
 		MsgFundingLocked: func(v []reflect.Value, r *rand.Rand) {
 
 			var c [32]byte
@@ -517,6 +779,14 @@ func TestLightningWireProtocol(t *testing.T) {
 
 			req := NewFundingLocked(ChannelID(c), pubKey)
 
+			// 1/2 chance to attach an alias SCID.
+			if r.Intn(2) == 0 {
+				req.AliasScid = new(ShortChannelID)
+				*req.AliasScid = NewShortChanIDFromInt(
+					uint64(r.Int63()),
+				)
+			}
+
 			v[0] = reflect.ValueOf(*req)
 		},
 		MsgClosingSigned: func(v []reflect.Value, r *rand.Rand) {
@@ -538,6 +808,46 @@ func TestLightningWireProtocol(t *testing.T) {
 
 			v[0] = reflect.ValueOf(req)
 		},
+		MsgClosingComplete: func(v []reflect.Value, r *rand.Rand) {
+			req := ClosingComplete{
+				FeeBroneess: bronutil.Amount(r.Int63()),
+				LockTime:    r.Uint32(),
+				ExtraData:   make([]byte, 0),
+			}
+			var err error
+			req.Signature, err = NewSigFromSignature(testSig)
+			if err != nil {
+				t.Fatalf("unable to parse sig: %v", err)
+				return
+			}
+
+			if _, err := r.Read(req.ChannelID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgClosingSig: func(v []reflect.Value, r *rand.Rand) {
+			req := ClosingSig{
+				FeeBroneess: bronutil.Amount(r.Int63()),
+				LockTime:    r.Uint32(),
+				ExtraData:   make([]byte, 0),
+			}
+			var err error
+			req.Signature, err = NewSigFromSignature(testSig)
+			if err != nil {
+				t.Fatalf("unable to parse sig: %v", err)
+				return
+			}
+
+			if _, err := r.Read(req.ChannelID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
 		MsgCommitSig: func(v []reflect.Value, r *rand.Rand) {
 			req := NewCommitSig()
 			if _, err := r.Read(req.ChanID[:]); err != nil {
@@ -567,8 +877,69 @@ func TestLightningWireProtocol(t *testing.T) {
 				}
 			}
 
+			// 1/2 chance of signatures for pending splices, as long
+			// as they fit into the message.
+			numSplices := r.Intn(3)
+			if r.Intn(2) == 0 && numSplices > 0 && numSigs < 500 {
+				req.SpliceSigs = make(SpliceSigs, numSplices)
+			}
+			for i := range req.SpliceSigs {
+				req.SpliceSigs[i], err = randSpliceSig(r)
+				if err != nil {
+					t.Fatalf("unable to generate splice "+
+						"sig: %v", err)
+					return
+				}
+			}
+
 			v[0] = reflect.ValueOf(*req)
 		},
+		MsgSpliceInit: func(v []reflect.Value, r *rand.Rand) {
+			req := SpliceInit{
+				FundingContribution: bronutil.Amount(
+					r.Int63() - r.Int63(),
+				),
+				FundingFeePerKiloWeight: uint32(r.Int31()),
+				LockTime:                uint32(r.Int31()),
+				ExtraData:               make([]byte, 0),
+			}
+
+			if _, err := r.Read(req.ChannelID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+
+			var err error
+			req.FundingPubKey, err = randPubKey()
+			if err != nil {
+				t.Fatalf("unable to generate key: %v", err)
+				return
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgSpliceAck: func(v []reflect.Value, r *rand.Rand) {
+			req := SpliceAck{
+				FundingContribution: bronutil.Amount(
+					r.Int63() - r.Int63(),
+				),
+				ExtraData: make([]byte, 0),
+			}
+
+			if _, err := r.Read(req.ChannelID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+
+			var err error
+			req.FundingPubKey, err = randPubKey()
+			if err != nil {
+				t.Fatalf("unable to generate key: %v", err)
+				return
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
 		MsgRevokeAndAck: func(v []reflect.Value, r *rand.Rand) {
 			req := NewRevokeAndAck()
 			if _, err := r.Read(req.ChanID[:]); err != nil {
@@ -959,6 +1330,18 @@ func TestLightningWireProtocol(t *testing.T) {
 				return mainScenario(&m)
 			},
 		},
+		{
+			msgType: MsgClosingComplete,
+			scenario: func(m ClosingComplete) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgClosingSig,
+			scenario: func(m ClosingSig) bool {
+				return mainScenario(&m)
+			},
+		},
 		{
 			msgType: MsgUpdateAddHTLC,
 			scenario: func(m UpdateAddHTLC) bool {
@@ -1062,6 +1445,114 @@ func TestLightningWireProtocol(t *testing.T) {
 				return mainScenario(&m)
 			},
 		},
+		{
+			msgType: MsgOpenChannel2,
+			scenario: func(m OpenChannel2) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgAcceptChannel2,
+			scenario: func(m AcceptChannel2) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgTxAddInput,
+			scenario: func(m TxAddInput) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgTxAddOutput,
+			scenario: func(m TxAddOutput) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgTxRemoveInput,
+			scenario: func(m TxRemoveInput) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgTxRemoveOutput,
+			scenario: func(m TxRemoveOutput) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgTxComplete,
+			scenario: func(m TxComplete) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgTxSignatures,
+			scenario: func(m TxSignatures) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgTxInitRbf,
+			scenario: func(m TxInitRbf) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgTxAckRbf,
+			scenario: func(m TxAckRbf) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgTxAbort,
+			scenario: func(m TxAbort) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgStfu,
+			scenario: func(m Stfu) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgSpliceInit,
+			scenario: func(m SpliceInit) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgSpliceAck,
+			scenario: func(m SpliceAck) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgSpliceLocked,
+			scenario: func(m SpliceLocked) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgOnionMessage,
+			scenario: func(m OnionMessage) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgPeerStorage,
+			scenario: func(m PeerStorage) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgPeerStorageRetrieval,
+			scenario: func(m PeerStorageRetrieval) bool {
+				return mainScenario(&m)
+			},
+		},
 	}
 	for _, test := range tests {
 		var config *quick.Config
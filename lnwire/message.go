@@ -22,7 +22,10 @@ type MessageType uint16
 // The currently defined message types within this current version of the
 // Lightning protocol.
 const (
-	MsgInit                    MessageType = 16
+	MsgStfu                    MessageType = 2
+	MsgPeerStorage                         = 7
+	MsgPeerStorageRetrieval                = 9
+	MsgInit                                = 16
 	MsgError                               = 17
 	MsgPing                                = 18
 	MsgPong                                = 19
@@ -50,6 +53,40 @@ const (
 	MsgQueryChannelRange                   = 263
 	MsgReplyChannelRange                   = 264
 	MsgGossipTimestampRange                = 265
+	MsgOnionMessage                        = 513
+)
+
+// The message types of protocols that deviate from their proposed
+// specification. Until they are brought in line with it, they use types of the
+// experimental range defined in BOLT 01, so they can't be mistaken for the
+// messages of spec compliant implementations.
+//
+// NOTE: The dual funding flow still exchanges the initial commitment
+// signatures through funding_created and funding_signed instead of
+// commitment_signed, so its messages, including the ones of the interactive
+// transaction construction, are offset by 32768 from their spec types.
+//
+// NOTE: Splicing moves a channel to the channel ID derived from the new
+// funding outpoint and carries the signatures of the commitments spending
+// the new funding output within commitment_signed, so its messages are offset
+// by 32768 from their spec types as well.
+const (
+	MsgClosingComplete MessageType = 32808
+	MsgClosingSig      MessageType = 32809
+	MsgOpenChannel2    MessageType = 32832
+	MsgAcceptChannel2  MessageType = 32833
+	MsgTxAddInput      MessageType = 32834
+	MsgTxAddOutput     MessageType = 32835
+	MsgTxRemoveInput   MessageType = 32836
+	MsgTxRemoveOutput  MessageType = 32837
+	MsgTxComplete      MessageType = 32838
+	MsgTxSignatures    MessageType = 32839
+	MsgTxInitRbf       MessageType = 32840
+	MsgTxAckRbf        MessageType = 32841
+	MsgTxAbort         MessageType = 32842
+	MsgSpliceLocked    MessageType = 32845
+	MsgSpliceInit      MessageType = 32848
+	MsgSpliceAck       MessageType = 32849
 )
 
 // ErrorEncodeMessage is used when failed to encode the message payload.
@@ -78,6 +115,10 @@ func (t MessageType) String() string {
 	switch t {
 	case MsgInit:
 		return "Init"
+	case MsgPeerStorage:
+		return "PeerStorage"
+	case MsgPeerStorageRetrieval:
+		return "PeerStorageRetrieval"
 	case MsgOpenChannel:
 		return "MsgOpenChannel"
 	case MsgAcceptChannel:
@@ -92,6 +133,40 @@ func (t MessageType) String() string {
 		return "Shutdown"
 	case MsgClosingSigned:
 		return "ClosingSigned"
+	case MsgClosingComplete:
+		return "ClosingComplete"
+	case MsgClosingSig:
+		return "ClosingSig"
+	case MsgOpenChannel2:
+		return "MsgOpenChannel2"
+	case MsgAcceptChannel2:
+		return "MsgAcceptChannel2"
+	case MsgTxAddInput:
+		return "TxAddInput"
+	case MsgTxAddOutput:
+		return "TxAddOutput"
+	case MsgTxRemoveInput:
+		return "TxRemoveInput"
+	case MsgTxRemoveOutput:
+		return "TxRemoveOutput"
+	case MsgTxComplete:
+		return "TxComplete"
+	case MsgTxSignatures:
+		return "TxSignatures"
+	case MsgTxInitRbf:
+		return "TxInitRbf"
+	case MsgTxAckRbf:
+		return "TxAckRbf"
+	case MsgTxAbort:
+		return "TxAbort"
+	case MsgStfu:
+		return "Stfu"
+	case MsgSpliceInit:
+		return "SpliceInit"
+	case MsgSpliceAck:
+		return "SpliceAck"
+	case MsgSpliceLocked:
+		return "SpliceLocked"
 	case MsgUpdateAddHTLC:
 		return "UpdateAddHTLC"
 	case MsgUpdateFailHTLC:
@@ -132,6 +207,8 @@ func (t MessageType) String() string {
 		return "ReplyChannelRange"
 	case MsgGossipTimestampRange:
 		return "GossipTimestampRange"
+	case MsgOnionMessage:
+		return "OnionMessage"
 	default:
 		return "<unknown>"
 	}
@@ -192,6 +269,44 @@ func makeEmptyMessage(msgType MessageType) (Message, error) {
 		msg = &Shutdown{}
 	case MsgClosingSigned:
 		msg = &ClosingSigned{}
+	case MsgClosingComplete:
+		msg = &ClosingComplete{}
+	case MsgClosingSig:
+		msg = &ClosingSig{}
+	case MsgOpenChannel2:
+		msg = &OpenChannel2{}
+	case MsgAcceptChannel2:
+		msg = &AcceptChannel2{}
+	case MsgTxAddInput:
+		msg = &TxAddInput{}
+	case MsgTxAddOutput:
+		msg = &TxAddOutput{}
+	case MsgTxRemoveInput:
+		msg = &TxRemoveInput{}
+	case MsgTxRemoveOutput:
+		msg = &TxRemoveOutput{}
+	case MsgTxComplete:
+		msg = &TxComplete{}
+	case MsgTxSignatures:
+		msg = &TxSignatures{}
+	case MsgTxInitRbf:
+		msg = &TxInitRbf{}
+	case MsgTxAckRbf:
+		msg = &TxAckRbf{}
+	case MsgTxAbort:
+		msg = &TxAbort{}
+	case MsgStfu:
+		msg = &Stfu{}
+	case MsgPeerStorage:
+		msg = &PeerStorage{}
+	case MsgPeerStorageRetrieval:
+		msg = &PeerStorageRetrieval{}
+	case MsgSpliceInit:
+		msg = &SpliceInit{}
+	case MsgSpliceAck:
+		msg = &SpliceAck{}
+	case MsgSpliceLocked:
+		msg = &SpliceLocked{}
 	case MsgUpdateAddHTLC:
 		msg = &UpdateAddHTLC{}
 	case MsgUpdateFailHTLC:
@@ -232,6 +347,8 @@ func makeEmptyMessage(msgType MessageType) (Message, error) {
 		msg = &ReplyChannelRange{}
 	case MsgGossipTimestampRange:
 		msg = &GossipTimestampRange{}
+	case MsgOnionMessage:
+		msg = &OnionMessage{}
 	default:
 		if msgType < CustomTypeStart {
 			return nil, &UnknownMessage{msgType}
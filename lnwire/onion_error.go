@@ -81,6 +81,9 @@ const (
 	CodeExpiryTooFar                     FailCode = 21
 	CodeInvalidOnionPayload                       = FlagPerm | 22
 	CodeMPPTimeout                       FailCode = 23
+	CodeInvalidOnionBlinding                      = FlagBadOnion | FlagPerm | 24
+	CodeTrampolineFeeInsufficient                 = FlagNode | 51
+	CodeTrampolineExpiryTooSoon                   = FlagNode | 52
 )
 
 // String returns the string representation of the failure code.
@@ -158,6 +161,15 @@ func (c FailCode) String() string {
 	case CodeMPPTimeout:
 		return "MPPTimeout"
 
+	case CodeInvalidOnionBlinding:
+		return "InvalidOnionBlinding"
+
+	case CodeTrampolineFeeInsufficient:
+		return "TrampolineFeeInsufficient"
+
+	case CodeTrampolineExpiryTooSoon:
+		return "TrampolineExpiryTooSoon"
+
 	default:
 		return "<unknown>"
 	}
@@ -576,6 +588,51 @@ func (f *FailInvalidOnionKey) Error() string {
 	return fmt.Sprintf("InvalidOnionKey(onion_sha=%x)", f.OnionSHA256[:])
 }
 
+// FailInvalidOnionBlinding is returned if an HTLC that is routed through a
+// blinded route couldn't be processed. To not leak any information about the
+// blinded route, every failure inside of it is reported with this code.
+//
+// NOTE: May only be returned by nodes that are part of a blinded route.
+type FailInvalidOnionBlinding struct {
+	// OnionSHA256 hash of the onion blob which haven't been proceeded.
+	OnionSHA256 [sha256.Size]byte
+}
+
+// NewInvalidOnionBlinding creates new instance of the
+// FailInvalidOnionBlinding.
+func NewInvalidOnionBlinding(onion []byte) *FailInvalidOnionBlinding {
+	return &FailInvalidOnionBlinding{OnionSHA256: sha256.Sum256(onion)}
+}
+
+// Code returns the failure unique code.
+//
+// NOTE: Part of the FailureMessage interface.
+func (f *FailInvalidOnionBlinding) Code() FailCode {
+	return CodeInvalidOnionBlinding
+}
+
+// Decode decodes the failure from bytes stream.
+//
+// NOTE: Part of the Serializable interface.
+func (f *FailInvalidOnionBlinding) Decode(r io.Reader, pver uint32) error {
+	return ReadElement(r, f.OnionSHA256[:])
+}
+
+// Encode writes the failure in bytes stream.
+//
+// NOTE: Part of the Serializable interface.
+func (f *FailInvalidOnionBlinding) Encode(w *bytes.Buffer, pver uint32) error {
+	return WriteBytes(w, f.OnionSHA256[:])
+}
+
+// Returns a human readable string describing the target FailureMessage.
+//
+// NOTE: Implements the error interface.
+func (f *FailInvalidOnionBlinding) Error() string {
+	return fmt.Sprintf("InvalidOnionBlinding(onion_sha=%x)",
+		f.OnionSHA256[:])
+}
+
 // parseChannelUpdateCompatabilityMode will attempt to parse a channel updated
 // encoded into an onion error payload in two ways. First, we'll try the
 // compatibility oriented version wherein we'll _skip_ the length prefixing on
@@ -1216,6 +1273,46 @@ func (f *FailMPPTimeout) Error() string {
 	return f.Code().String()
 }
 
+// FailTrampolineFeeInsufficient is returned by a trampoline node if the fee
+// it receives for a trampoline forward doesn't satisfy its fee policy.
+//
+// NOTE: May only be returned by trampoline nodes.
+type FailTrampolineFeeInsufficient struct{}
+
+// Code returns the failure unique code.
+//
+// NOTE: Part of the FailureMessage interface.
+func (f *FailTrampolineFeeInsufficient) Code() FailCode {
+	return CodeTrampolineFeeInsufficient
+}
+
+// Returns a human readable string describing the target FailureMessage.
+//
+// NOTE: Implements the error interface.
+func (f *FailTrampolineFeeInsufficient) Error() string {
+	return f.Code().String()
+}
+
+// FailTrampolineExpiryTooSoon is returned by a trampoline node if the CLTV
+// delta it receives for a trampoline forward doesn't satisfy its policy.
+//
+// NOTE: May only be returned by trampoline nodes.
+type FailTrampolineExpiryTooSoon struct{}
+
+// Code returns the failure unique code.
+//
+// NOTE: Part of the FailureMessage interface.
+func (f *FailTrampolineExpiryTooSoon) Code() FailCode {
+	return CodeTrampolineExpiryTooSoon
+}
+
+// Returns a human readable string describing the target FailureMessage.
+//
+// NOTE: Implements the error interface.
+func (f *FailTrampolineExpiryTooSoon) Error() string {
+	return f.Code().String()
+}
+
 // DecodeFailure decodes, validates, and parses the lnwire onion failure, for
 // the provided protocol version.
 func DecodeFailure(r io.Reader, pver uint32) (FailureMessage, error) {
@@ -1411,6 +1508,15 @@ func makeEmptyOnionError(code FailCode) (FailureMessage, error) {
 	case CodeMPPTimeout:
 		return &FailMPPTimeout{}, nil
 
+	case CodeInvalidOnionBlinding:
+		return &FailInvalidOnionBlinding{}, nil
+
+	case CodeTrampolineFeeInsufficient:
+		return &FailTrampolineFeeInsufficient{}, nil
+
+	case CodeTrampolineExpiryTooSoon:
+		return &FailTrampolineExpiryTooSoon{}, nil
+
 	default:
 		return nil, errors.Errorf("unknown error code: %v", code)
 	}
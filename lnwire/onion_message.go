@@ -0,0 +1,71 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/brronsuite/brond/bronec"
+)
+
+// OnionMessage is a message that carries an onion packet through the network
+// without being tied to a payment or a channel. Each hop peels one layer of
+// the onion and forwards the remainder to the next node along a blinded
+// route.
+type OnionMessage struct {
+	// BlindingPoint is the route blinding ephemeral point that the
+	// receiving node uses to derive the key it needs to decrypt the onion
+	// and its encrypted recipient data.
+	BlindingPoint *bronec.PublicKey
+
+	// OnionBlob is the serialized onion message packet.
+	OnionBlob []byte
+}
+
+// NewOnionMessage creates a new onion message with the given blinding point
+// and serialized onion packet.
+func NewOnionMessage(blindingPoint *bronec.PublicKey,
+	onionBlob []byte) *OnionMessage {
+
+	return &OnionMessage{
+		BlindingPoint: blindingPoint,
+		OnionBlob:     onionBlob,
+	}
+}
+
+// A compile time check to ensure OnionMessage implements the lnwire.Message
+// interface.
+var _ Message = (*OnionMessage)(nil)
+
+// Decode deserializes a serialized OnionMessage stored in the passed io.Reader
+// observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (o *OnionMessage) Decode(r io.Reader, pver uint32) error {
+	var onionLen uint16
+	if err := ReadElements(r, &o.BlindingPoint, &onionLen); err != nil {
+		return err
+	}
+
+	o.OnionBlob = make([]byte, onionLen)
+	return ReadElement(r, o.OnionBlob)
+}
+
+// Encode serializes the target OnionMessage into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (o *OnionMessage) Encode(w *bytes.Buffer, pver uint32) error {
+	if err := WritePublicKey(w, o.BlindingPoint); err != nil {
+		return err
+	}
+
+	return writeDataWithLength(w, o.OnionBlob)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (o *OnionMessage) MsgType() MessageType {
+	return MsgOnionMessage
+}
@@ -0,0 +1,270 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/brronsuite/broln/tlv"
+	"github.com/brronsuite/brond/bronec"
+	"github.com/brronsuite/brond/chaincfg/chainhash"
+	"github.com/brronsuite/bronutil"
+)
+
+// OpenChannel2 is the message Alice sends to Bob if she'd like to create a
+// dual funded channel with him. Unlike OpenChannel, the funding transaction
+// isn't created by Alice alone, instead both parties contribute inputs and
+// outputs to it during the interactive transaction construction that follows
+// the AcceptChannel2 message.
+type OpenChannel2 struct {
+	// ChainHash is the target chain that the initiator wishes to open a
+	// channel within.
+	ChainHash chainhash.Hash
+
+	// PendingChannelID serves to uniquely identify the future channel
+	// created by the initiated dual funder workflow. It's also used as
+	// the channel ID of the interactive transaction construction messages.
+	PendingChannelID [32]byte
+
+	// FundingFeePerKiloWeight is the fee rate the funding transaction
+	// should pay, expressed in sat per kilo-weight. Each party pays the
+	// fees for the inputs and outputs it contributes, while the initiator
+	// also pays for the common fields of the transaction.
+	FundingFeePerKiloWeight uint32
+
+	// CommitFeePerKiloWeight is the initial fee rate that the initiator
+	// suggests for both commitment transactions, expressed in sat per
+	// kilo-weight.
+	CommitFeePerKiloWeight uint32
+
+	// FundingAmount is the amount of broneess that the initiator
+	// contributes to the channel. The capacity of the channel is this
+	// amount plus the contribution of the responder.
+	FundingAmount bronutil.Amount
+
+	// DustLimit is the specific dust limit the sender of this message
+	// would like enforced on their version of the commitment transaction.
+	DustLimit bronutil.Amount
+
+	// MaxValueInFlight represents the maximum amount of coins that can be
+	// pending within the channel at any given time.
+	MaxValueInFlight MilliBronees
+
+	// HtlcMinimum is the smallest HTLC that the sender of this message
+	// will accept.
+	HtlcMinimum MilliBronees
+
+	// CsvDelay is the number of blocks to use for the relative time lock
+	// in the pay-to-self output of both commitment transactions.
+	CsvDelay uint16
+
+	// MaxAcceptedHTLCs is the total number of incoming HTLC's that the
+	// sender of this channel will accept.
+	MaxAcceptedHTLCs uint16
+
+	// LockTime is the lock time of the funding transaction.
+	LockTime uint32
+
+	// FundingKey is the key that should be used on behalf of the sender
+	// within the 2-of-2 multi-sig output that it contained within the
+	// funding transaction.
+	FundingKey *bronec.PublicKey
+
+	// RevocationPoint is the base revocation point for the sending party.
+	RevocationPoint *bronec.PublicKey
+
+	// PaymentPoint is the base payment point for the sending party.
+	PaymentPoint *bronec.PublicKey
+
+	// DelayedPaymentPoint is the delay point for the sending party.
+	DelayedPaymentPoint *bronec.PublicKey
+
+	// HtlcPoint is the base point used to derive the set of keys for this
+	// party that will be used within the HTLC public key scripts.
+	HtlcPoint *bronec.PublicKey
+
+	// FirstCommitmentPoint is the first commitment point for the sending
+	// party.
+	FirstCommitmentPoint *bronec.PublicKey
+
+	// SecondCommitmentPoint is the second commitment point for the
+	// sending party, which is used for the commitment transaction that
+	// follows the initial one.
+	SecondCommitmentPoint *bronec.PublicKey
+
+	// ChannelFlags is a bit-field which allows the initiator of the
+	// channel to specify further behavior surrounding the channel.
+	ChannelFlags FundingFlag
+
+	// UpfrontShutdownScript is the script to which the channel funds should
+	// be paid when mutually closing the channel. It's always encoded, as a
+	// zero length script if it isn't set.
+	UpfrontShutdownScript DeliveryAddress
+
+	// ChannelType is the explicit channel type the initiator wishes to
+	// open.
+	ChannelType *ChannelType
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure OpenChannel2 implements the lnwire.Message
+// interface.
+var _ Message = (*OpenChannel2)(nil)
+
+// Encode serializes the target OpenChannel2 into the passed io.Writer
+// implementation. Serialization will observe the rules defined by the passed
+// protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (o *OpenChannel2) Encode(w *bytes.Buffer, pver uint32) error {
+	recordProducers := []tlv.RecordProducer{&o.UpfrontShutdownScript}
+	if o.ChannelType != nil {
+		recordProducers = append(recordProducers, o.ChannelType)
+	}
+	err := EncodeMessageExtraData(&o.ExtraData, recordProducers...)
+	if err != nil {
+		return err
+	}
+
+	if err := WriteBytes(w, o.ChainHash[:]); err != nil {
+		return err
+	}
+
+	if err := WriteBytes(w, o.PendingChannelID[:]); err != nil {
+		return err
+	}
+
+	if err := WriteUint32(w, o.FundingFeePerKiloWeight); err != nil {
+		return err
+	}
+
+	if err := WriteUint32(w, o.CommitFeePerKiloWeight); err != nil {
+		return err
+	}
+
+	if err := WriteBronees(w, o.FundingAmount); err != nil {
+		return err
+	}
+
+	if err := WriteBronees(w, o.DustLimit); err != nil {
+		return err
+	}
+
+	if err := WriteMilliBronees(w, o.MaxValueInFlight); err != nil {
+		return err
+	}
+
+	if err := WriteMilliBronees(w, o.HtlcMinimum); err != nil {
+		return err
+	}
+
+	if err := WriteUint16(w, o.CsvDelay); err != nil {
+		return err
+	}
+
+	if err := WriteUint16(w, o.MaxAcceptedHTLCs); err != nil {
+		return err
+	}
+
+	if err := WriteUint32(w, o.LockTime); err != nil {
+		return err
+	}
+
+	if err := WritePublicKey(w, o.FundingKey); err != nil {
+		return err
+	}
+
+	if err := WritePublicKey(w, o.RevocationPoint); err != nil {
+		return err
+	}
+
+	if err := WritePublicKey(w, o.PaymentPoint); err != nil {
+		return err
+	}
+
+	if err := WritePublicKey(w, o.DelayedPaymentPoint); err != nil {
+		return err
+	}
+
+	if err := WritePublicKey(w, o.HtlcPoint); err != nil {
+		return err
+	}
+
+	if err := WritePublicKey(w, o.FirstCommitmentPoint); err != nil {
+		return err
+	}
+
+	if err := WritePublicKey(w, o.SecondCommitmentPoint); err != nil {
+		return err
+	}
+
+	if err := WriteFundingFlag(w, o.ChannelFlags); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, o.ExtraData)
+}
+
+// Decode deserializes the serialized OpenChannel2 stored in the passed
+// io.Reader into the target OpenChannel2 using the deserialization rules
+// defined by the passed protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (o *OpenChannel2) Decode(r io.Reader, pver uint32) error {
+	err := ReadElements(r,
+		o.ChainHash[:],
+		o.PendingChannelID[:],
+		&o.FundingFeePerKiloWeight,
+		&o.CommitFeePerKiloWeight,
+		&o.FundingAmount,
+		&o.DustLimit,
+		&o.MaxValueInFlight,
+		&o.HtlcMinimum,
+		&o.CsvDelay,
+		&o.MaxAcceptedHTLCs,
+		&o.LockTime,
+		&o.FundingKey,
+		&o.RevocationPoint,
+		&o.PaymentPoint,
+		&o.DelayedPaymentPoint,
+		&o.HtlcPoint,
+		&o.FirstCommitmentPoint,
+		&o.SecondCommitmentPoint,
+		&o.ChannelFlags,
+	)
+	if err != nil {
+		return err
+	}
+
+	var tlvRecords ExtraOpaqueData
+	if err := ReadElements(r, &tlvRecords); err != nil {
+		return err
+	}
+
+	var chanType ChannelType
+	typeMap, err := tlvRecords.ExtractRecords(
+		&o.UpfrontShutdownScript, &chanType,
+	)
+	if err != nil {
+		return err
+	}
+
+	if val, ok := typeMap[ChannelTypeRecordType]; ok && val == nil {
+		o.ChannelType = &chanType
+	}
+
+	o.ExtraData = tlvRecords
+
+	return nil
+}
+
+// MsgType returns the MessageType code which uniquely identifies this message
+// as an OpenChannel2 on the wire.
+//
+// This is part of the lnwire.Message interface.
+func (o *OpenChannel2) MsgType() MessageType {
+	return MsgOpenChannel2
+}
@@ -0,0 +1,127 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+)
+
+// MaxPeerStorageBlobSize is the largest blob a peer can ask us to store. It is
+// bounded by the maximum message size minus the two byte length prefix of the
+// blob.
+const MaxPeerStorageBlobSize = MaxMsgBody - 2
+
+// PeerStorage is sent to a peer that signals the provide storage feature to
+// ask it to store the enclosed blob on our behalf. Each new PeerStorage
+// message replaces the blob the peer stored for us before. The blob is opaque
+// to the receiving peer, the sender is expected to encrypt it.
+type PeerStorage struct {
+	// Blob is the opaque data the peer should store for us.
+	Blob []byte
+}
+
+// NewPeerStorage creates a new PeerStorage message carrying the given blob.
+func NewPeerStorage(blob []byte) *PeerStorage {
+	return &PeerStorage{
+		Blob: blob,
+	}
+}
+
+// A compile time check to ensure PeerStorage implements the lnwire.Message
+// interface.
+var _ Message = (*PeerStorage)(nil)
+
+// Decode deserializes a serialized PeerStorage message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (p *PeerStorage) Decode(r io.Reader, pver uint32) error {
+	blob, err := readPeerStorageBlob(r)
+	if err != nil {
+		return err
+	}
+
+	p.Blob = blob
+	return nil
+}
+
+// Encode serializes the target PeerStorage message into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (p *PeerStorage) Encode(w *bytes.Buffer, pver uint32) error {
+	return writeDataWithLength(w, p.Blob)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (p *PeerStorage) MsgType() MessageType {
+	return MsgPeerStorage
+}
+
+// PeerStorageRetrieval is sent right after the init exchange to a peer that
+// previously asked us to store a blob for it. It carries the latest blob the
+// peer handed us, which allows the peer to recover its data after losing it.
+type PeerStorageRetrieval struct {
+	// Blob is the opaque data the peer last asked us to store.
+	Blob []byte
+}
+
+// NewPeerStorageRetrieval creates a new PeerStorageRetrieval message carrying
+// the given blob.
+func NewPeerStorageRetrieval(blob []byte) *PeerStorageRetrieval {
+	return &PeerStorageRetrieval{
+		Blob: blob,
+	}
+}
+
+// A compile time check to ensure PeerStorageRetrieval implements the
+// lnwire.Message interface.
+var _ Message = (*PeerStorageRetrieval)(nil)
+
+// Decode deserializes a serialized PeerStorageRetrieval message stored in the
+// passed io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (p *PeerStorageRetrieval) Decode(r io.Reader, pver uint32) error {
+	blob, err := readPeerStorageBlob(r)
+	if err != nil {
+		return err
+	}
+
+	p.Blob = blob
+	return nil
+}
+
+// Encode serializes the target PeerStorageRetrieval message into the passed
+// io.Writer observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (p *PeerStorageRetrieval) Encode(w *bytes.Buffer, pver uint32) error {
+	return writeDataWithLength(w, p.Blob)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (p *PeerStorageRetrieval) MsgType() MessageType {
+	return MsgPeerStorageRetrieval
+}
+
+// readPeerStorageBlob reads a length prefixed peer storage blob from the
+// passed io.Reader.
+func readPeerStorageBlob(r io.Reader) ([]byte, error) {
+	var blobLen uint16
+	if err := ReadElement(r, &blobLen); err != nil {
+		return nil, err
+	}
+
+	blob := make([]byte, blobLen)
+	if err := ReadElement(r, blob); err != nil {
+		return nil, err
+	}
+
+	return blob, nil
+}
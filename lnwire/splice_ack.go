@@ -0,0 +1,74 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/brronsuite/brond/bronec"
+	"github.com/brronsuite/bronutil"
+)
+
+// SpliceAck is sent by the responder of a splice in reply to a SpliceInit
+// message to accept the splice and announce its own contribution.
+type SpliceAck struct {
+	// ChannelID is the ID of the channel that should be spliced.
+	ChannelID ChannelID
+
+	// FundingContribution is the amount the responder adds to the channel.
+	// A negative amount is removed from the responder's balance.
+	FundingContribution bronutil.Amount
+
+	// FundingPubKey is the key the responder uses in the 2-of-2 multisig
+	// script of the new funding output.
+	FundingPubKey *bronec.PublicKey
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure SpliceAck implements the lnwire.Message
+// interface.
+var _ Message = (*SpliceAck)(nil)
+
+// Encode serializes the target SpliceAck into the passed io.Writer
+// implementation. Serialization will observe the rules defined by the passed
+// protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceAck) Encode(w *bytes.Buffer, pver uint32) error {
+	if err := WriteChannelID(w, s.ChannelID); err != nil {
+		return err
+	}
+
+	if err := WriteBronees(w, s.FundingContribution); err != nil {
+		return err
+	}
+
+	if err := WritePublicKey(w, s.FundingPubKey); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, s.ExtraData)
+}
+
+// Decode deserializes the serialized SpliceAck stored in the passed io.Reader
+// into the target SpliceAck using the deserialization rules defined by the
+// passed protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceAck) Decode(r io.Reader, pver uint32) error {
+	return ReadElements(r,
+		&s.ChannelID, &s.FundingContribution, &s.FundingPubKey,
+		&s.ExtraData,
+	)
+}
+
+// MsgType returns the MessageType code which uniquely identifies this message
+// as a SpliceAck on the wire.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceAck) MsgType() MessageType {
+	return MsgSpliceAck
+}
@@ -0,0 +1,99 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/brronsuite/brond/bronec"
+	"github.com/brronsuite/bronutil"
+)
+
+// SpliceInit is sent by the initiator of a splice once the channel is
+// quiescent. It proposes to replace the funding output of the channel with a
+// new one whose value is changed by the contributions of both parties. The
+// splice transaction is then constructed interactively through the tx_*
+// messages, with the current funding output added as a shared input.
+type SpliceInit struct {
+	// ChannelID is the ID of the channel that should be spliced.
+	ChannelID ChannelID
+
+	// FundingContribution is the amount the initiator adds to the channel.
+	// A negative amount is removed from the initiator's balance and spent
+	// to outputs of the splice transaction instead.
+	FundingContribution bronutil.Amount
+
+	// FundingFeePerKiloWeight is the fee rate the splice transaction
+	// should pay, expressed in sat per kilo-weight. Each party pays the
+	// fees for the inputs and outputs it contributes, while the initiator
+	// also pays for the common fields and the shared input.
+	FundingFeePerKiloWeight uint32
+
+	// LockTime is the lock time of the splice transaction.
+	LockTime uint32
+
+	// FundingPubKey is the key the initiator uses in the 2-of-2 multisig
+	// script of the new funding output.
+	FundingPubKey *bronec.PublicKey
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure SpliceInit implements the lnwire.Message
+// interface.
+var _ Message = (*SpliceInit)(nil)
+
+// Encode serializes the target SpliceInit into the passed io.Writer
+// implementation. Serialization will observe the rules defined by the passed
+// protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceInit) Encode(w *bytes.Buffer, pver uint32) error {
+	if err := WriteChannelID(w, s.ChannelID); err != nil {
+		return err
+	}
+
+	if err := WriteBronees(w, s.FundingContribution); err != nil {
+		return err
+	}
+
+	if err := WriteUint32(w, s.FundingFeePerKiloWeight); err != nil {
+		return err
+	}
+
+	if err := WriteUint32(w, s.LockTime); err != nil {
+		return err
+	}
+
+	if err := WritePublicKey(w, s.FundingPubKey); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, s.ExtraData)
+}
+
+// Decode deserializes the serialized SpliceInit stored in the passed
+// io.Reader into the target SpliceInit using the deserialization rules
+// defined by the passed protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceInit) Decode(r io.Reader, pver uint32) error {
+	return ReadElements(r,
+		&s.ChannelID,
+		&s.FundingContribution,
+		&s.FundingFeePerKiloWeight,
+		&s.LockTime,
+		&s.FundingPubKey,
+		&s.ExtraData,
+	)
+}
+
+// MsgType returns the MessageType code which uniquely identifies this message
+// as a SpliceInit on the wire.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceInit) MsgType() MessageType {
+	return MsgSpliceInit
+}
@@ -0,0 +1,63 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/brronsuite/brond/chaincfg/chainhash"
+)
+
+// SpliceLocked is sent by both parties of a splice once the splice
+// transaction has reached a safe number of confirmations. After both parties
+// sent SpliceLocked, the channel stops signing commitments spending the
+// previous funding output and continues to operate on top of the new one.
+type SpliceLocked struct {
+	// ChannelID is the ID of the channel before the splice.
+	ChannelID ChannelID
+
+	// SpliceTxID is the txid of the confirmed splice transaction.
+	SpliceTxID chainhash.Hash
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure SpliceLocked implements the lnwire.Message
+// interface.
+var _ Message = (*SpliceLocked)(nil)
+
+// Encode serializes the target SpliceLocked into the passed io.Writer
+// implementation. Serialization will observe the rules defined by the passed
+// protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceLocked) Encode(w *bytes.Buffer, pver uint32) error {
+	if err := WriteChannelID(w, s.ChannelID); err != nil {
+		return err
+	}
+
+	if err := WriteBytes(w, s.SpliceTxID[:]); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, s.ExtraData)
+}
+
+// Decode deserializes the serialized SpliceLocked stored in the passed
+// io.Reader into the target SpliceLocked using the deserialization rules
+// defined by the passed protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceLocked) Decode(r io.Reader, pver uint32) error {
+	return ReadElements(r, &s.ChannelID, s.SpliceTxID[:], &s.ExtraData)
+}
+
+// MsgType returns the MessageType code which uniquely identifies this message
+// as a SpliceLocked on the wire.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceLocked) MsgType() MessageType {
+	return MsgSpliceLocked
+}
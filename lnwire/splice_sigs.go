@@ -0,0 +1,147 @@
+package lnwire
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/brronsuite/broln/tlv"
+	"github.com/brronsuite/brond/chaincfg/chainhash"
+)
+
+const (
+	// SpliceSigsRecordType is the type of the experimental record that
+	// carries the signatures of the commitments spending the funding
+	// outputs of pending splice transactions within a CommitSig message.
+	SpliceSigsRecordType tlv.Type = 65536
+
+	// spliceSigSize is the size of an encoded SpliceSig without its HTLC
+	// signatures: the funding txid, the commitment signature and the
+	// number of HTLC signatures.
+	spliceSigSize = 32 + 64 + 2
+)
+
+// SpliceSig holds the signatures for a commitment that spends the funding
+// output created by a pending splice transaction.
+type SpliceSig struct {
+	// FundingTxID is the txid of the splice transaction whose funding
+	// output the commitment spends.
+	FundingTxID chainhash.Hash
+
+	// CommitSig is the signature for the commitment transaction.
+	CommitSig Sig
+
+	// HtlcSigs are the signatures for the second level HTLC transactions
+	// of the commitment, in the order of the HTLC outputs of the
+	// commitment.
+	HtlcSigs []Sig
+}
+
+// SpliceSigs is the list of signatures of the commitments spending the
+// funding outputs of all pending splice transactions of a channel. They are
+// sent along with every CommitSig until a splice is locked, so both the
+// current commitment and the ones of the splices can be published.
+type SpliceSigs []SpliceSig
+
+// encodedLen returns the length in bytes of the encoded splice signatures.
+func (s SpliceSigs) encodedLen() uint64 {
+	var l uint64
+	for _, sig := range s {
+		l += spliceSigSize + uint64(len(sig.HtlcSigs))*64
+	}
+
+	return l
+}
+
+// Record returns a TLV record that can be used to encode/decode the splice
+// signatures from a given TLV stream.
+func (s *SpliceSigs) Record() tlv.Record {
+	return tlv.MakeDynamicRecord(
+		SpliceSigsRecordType, s, s.encodedLen, spliceSigsEncoder,
+		spliceSigsDecoder,
+	)
+}
+
+// spliceSigsEncoder is a custom TLV encoder for the SpliceSigs record.
+func spliceSigsEncoder(w io.Writer, val interface{}, buf *[8]byte) error {
+	v, ok := val.(*SpliceSigs)
+	if !ok {
+		return tlv.NewTypeForEncodingErr(val, "lnwire.SpliceSigs")
+	}
+
+	for _, sig := range *v {
+		if _, err := w.Write(sig.FundingTxID[:]); err != nil {
+			return err
+		}
+		if _, err := w.Write(sig.CommitSig[:]); err != nil {
+			return err
+		}
+
+		numSigs := uint16(len(sig.HtlcSigs))
+		if err := tlv.EUint16T(w, numSigs, buf); err != nil {
+			return err
+		}
+		for _, htlcSig := range sig.HtlcSigs {
+			if _, err := w.Write(htlcSig[:]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// spliceSigsDecoder is a custom TLV decoder for the SpliceSigs record.
+func spliceSigsDecoder(r io.Reader, val interface{}, buf *[8]byte,
+	l uint64) error {
+
+	v, ok := val.(*SpliceSigs)
+	if !ok {
+		return tlv.NewTypeForDecodingErr(
+			val, "lnwire.SpliceSigs", l, l,
+		)
+	}
+
+	var sigs SpliceSigs
+	for l > 0 {
+		if l < spliceSigSize {
+			return tlv.NewTypeForDecodingErr(
+				val, "lnwire.SpliceSigs", l, spliceSigSize,
+			)
+		}
+
+		var sig SpliceSig
+		if _, err := io.ReadFull(r, sig.FundingTxID[:]); err != nil {
+			return err
+		}
+		if _, err := io.ReadFull(r, sig.CommitSig[:]); err != nil {
+			return err
+		}
+		if _, err := io.ReadFull(r, buf[:2]); err != nil {
+			return err
+		}
+		numSigs := binary.BigEndian.Uint16(buf[:2])
+		l -= spliceSigSize
+
+		if l < uint64(numSigs)*64 {
+			return tlv.NewTypeForDecodingErr(
+				val, "lnwire.SpliceSigs", l,
+				uint64(numSigs)*64,
+			)
+		}
+		if numSigs > 0 {
+			sig.HtlcSigs = make([]Sig, numSigs)
+		}
+		for i := range sig.HtlcSigs {
+			_, err := io.ReadFull(r, sig.HtlcSigs[i][:])
+			if err != nil {
+				return err
+			}
+		}
+		l -= uint64(numSigs) * 64
+
+		sigs = append(sigs, sig)
+	}
+	*v = sigs
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+)
+
+// Stfu is sent by either party to signal that it wishes to bring the channel
+// into a quiescent state. Once both parties sent Stfu and no updates are
+// pending, no further updates will be added to the channel until the
+// operation that required quiescence, such as a splice, has completed.
+type Stfu struct {
+	// ChannelID is the ID of the channel that should become quiescent.
+	ChannelID ChannelID
+
+	// Initiator is true if the sender of this message initiated the
+	// quiescence, and false if the sender is responding to a Stfu message
+	// of the remote party.
+	Initiator bool
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure Stfu implements the lnwire.Message interface.
+var _ Message = (*Stfu)(nil)
+
+// Encode serializes the target Stfu into the passed io.Writer implementation.
+// Serialization will observe the rules defined by the passed protocol
+// version.
+//
+// This is part of the lnwire.Message interface.
+func (s *Stfu) Encode(w *bytes.Buffer, pver uint32) error {
+	if err := WriteChannelID(w, s.ChannelID); err != nil {
+		return err
+	}
+
+	if err := WriteBool(w, s.Initiator); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, s.ExtraData)
+}
+
+// Decode deserializes the serialized Stfu stored in the passed io.Reader into
+// the target Stfu using the deserialization rules defined by the passed
+// protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (s *Stfu) Decode(r io.Reader, pver uint32) error {
+	return ReadElements(r, &s.ChannelID, &s.Initiator, &s.ExtraData)
+}
+
+// MsgType returns the MessageType code which uniquely identifies this message
+// as a Stfu on the wire.
+//
+// This is part of the lnwire.Message interface.
+func (s *Stfu) MsgType() MessageType {
+	return MsgStfu
+}
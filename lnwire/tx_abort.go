@@ -0,0 +1,65 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+)
+
+// TxAbort is sent by either party to abort the interactive construction of a
+// transaction before it was signed by both parties.
+type TxAbort struct {
+	// ChannelID is the ID of the channel the transaction is constructed
+	// for.
+	ChannelID ChannelID
+
+	// Data is a human-readable reason for aborting the construction.
+	Data ErrorData
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure TxAbort implements the lnwire.Message
+// interface.
+var _ Message = (*TxAbort)(nil)
+
+// Encode serializes the target TxAbort into the passed io.Writer
+// implementation. Serialization will observe the rules defined by the passed
+// protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxAbort) Encode(w *bytes.Buffer, pver uint32) error {
+	if err := WriteChannelID(w, t.ChannelID); err != nil {
+		return err
+	}
+
+	if err := WriteErrorData(w, t.Data); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, t.ExtraData)
+}
+
+// Decode deserializes the serialized TxAbort stored in the passed io.Reader
+// into the target TxAbort using the deserialization rules defined by the
+// passed protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxAbort) Decode(r io.Reader, pver uint32) error {
+	return ReadElements(r, &t.ChannelID, &t.Data, &t.ExtraData)
+}
+
+// MsgType returns the MessageType code which uniquely identifies this message
+// as a TxAbort on the wire.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxAbort) MsgType() MessageType {
+	return MsgTxAbort
+}
+
+// Error returns the string representation of the abort reason.
+func (t *TxAbort) Error() string {
+	return string(t.Data)
+}
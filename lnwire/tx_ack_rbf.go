@@ -0,0 +1,66 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/brronsuite/bronutil"
+)
+
+// TxAckRbf is sent by the responder in reply to a TxInitRbf message to accept
+// the construction of a replacement transaction and announce its own
+// contribution to it.
+type TxAckRbf struct {
+	// ChannelID is the ID of the channel the transaction is constructed
+	// for.
+	ChannelID ChannelID
+
+	// FundingContribution is the amount the responder adds to the
+	// channel with the replacement transaction.
+	FundingContribution bronutil.Amount
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure TxAckRbf implements the lnwire.Message
+// interface.
+var _ Message = (*TxAckRbf)(nil)
+
+// Encode serializes the target TxAckRbf into the passed io.Writer
+// implementation. Serialization will observe the rules defined by the passed
+// protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxAckRbf) Encode(w *bytes.Buffer, pver uint32) error {
+	if err := WriteChannelID(w, t.ChannelID); err != nil {
+		return err
+	}
+
+	if err := WriteBronees(w, t.FundingContribution); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, t.ExtraData)
+}
+
+// Decode deserializes the serialized TxAckRbf stored in the passed io.Reader
+// into the target TxAckRbf using the deserialization rules defined by the
+// passed protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxAckRbf) Decode(r io.Reader, pver uint32) error {
+	return ReadElements(r,
+		&t.ChannelID, &t.FundingContribution, &t.ExtraData,
+	)
+}
+
+// MsgType returns the MessageType code which uniquely identifies this message
+// as a TxAckRbf on the wire.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxAckRbf) MsgType() MessageType {
+	return MsgTxAckRbf
+}
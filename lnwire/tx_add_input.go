@@ -0,0 +1,98 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+)
+
+// TxAddInput is sent during the interactive construction of a transaction to
+// add an input to it. As the receiver needs to verify that the input spends
+// a segwit output and learn its value, the complete previous transaction is
+// included instead of just the outpoint.
+type TxAddInput struct {
+	// ChannelID is the ID of the channel the transaction is constructed
+	// for.
+	ChannelID ChannelID
+
+	// SerialID identifies the input within the transaction and determines
+	// its position in the final transaction. The initiator of the
+	// construction only uses even serial IDs, the responder odd ones.
+	SerialID uint64
+
+	// PrevTx is the serialized transaction that contains the output spent
+	// by the input.
+	PrevTx []byte
+
+	// PrevTxOutIndex is the index of the spent output in PrevTx.
+	PrevTxOutIndex uint32
+
+	// Sequence is the sequence number of the input.
+	Sequence uint32
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure TxAddInput implements the lnwire.Message
+// interface.
+var _ Message = (*TxAddInput)(nil)
+
+// Encode serializes the target TxAddInput into the passed io.Writer
+// implementation. Serialization will observe the rules defined by the passed
+// protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxAddInput) Encode(w *bytes.Buffer, pver uint32) error {
+	if err := WriteChannelID(w, t.ChannelID); err != nil {
+		return err
+	}
+
+	if err := WriteUint64(w, t.SerialID); err != nil {
+		return err
+	}
+
+	if err := writeDataWithLength(w, t.PrevTx); err != nil {
+		return err
+	}
+
+	if err := WriteUint32(w, t.PrevTxOutIndex); err != nil {
+		return err
+	}
+
+	if err := WriteUint32(w, t.Sequence); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, t.ExtraData)
+}
+
+// Decode deserializes the serialized TxAddInput stored in the passed
+// io.Reader into the target TxAddInput using the deserialization rules
+// defined by the passed protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxAddInput) Decode(r io.Reader, pver uint32) error {
+	var prevTxLen uint16
+	err := ReadElements(r, &t.ChannelID, &t.SerialID, &prevTxLen)
+	if err != nil {
+		return err
+	}
+
+	t.PrevTx = make([]byte, prevTxLen)
+	return ReadElements(r,
+		t.PrevTx,
+		&t.PrevTxOutIndex,
+		&t.Sequence,
+		&t.ExtraData,
+	)
+}
+
+// MsgType returns the MessageType code which uniquely identifies this message
+// as a TxAddInput on the wire.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxAddInput) MsgType() MessageType {
+	return MsgTxAddInput
+}
@@ -0,0 +1,87 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/brronsuite/bronutil"
+)
+
+// TxAddOutput is sent during the interactive construction of a transaction to
+// add an output to it.
+type TxAddOutput struct {
+	// ChannelID is the ID of the channel the transaction is constructed
+	// for.
+	ChannelID ChannelID
+
+	// SerialID identifies the output within the transaction and determines
+	// its position in the final transaction. The initiator of the
+	// construction only uses even serial IDs, the responder odd ones.
+	SerialID uint64
+
+	// Amount is the value of the output.
+	Amount bronutil.Amount
+
+	// PkScript is the script of the output.
+	PkScript []byte
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure TxAddOutput implements the lnwire.Message
+// interface.
+var _ Message = (*TxAddOutput)(nil)
+
+// Encode serializes the target TxAddOutput into the passed io.Writer
+// implementation. Serialization will observe the rules defined by the passed
+// protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxAddOutput) Encode(w *bytes.Buffer, pver uint32) error {
+	if err := WriteChannelID(w, t.ChannelID); err != nil {
+		return err
+	}
+
+	if err := WriteUint64(w, t.SerialID); err != nil {
+		return err
+	}
+
+	if err := WriteBronees(w, t.Amount); err != nil {
+		return err
+	}
+
+	if err := writeDataWithLength(w, t.PkScript); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, t.ExtraData)
+}
+
+// Decode deserializes the serialized TxAddOutput stored in the passed
+// io.Reader into the target TxAddOutput using the deserialization rules
+// defined by the passed protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxAddOutput) Decode(r io.Reader, pver uint32) error {
+	var scriptLen uint16
+	err := ReadElements(
+		r, &t.ChannelID, &t.SerialID, &t.Amount, &scriptLen,
+	)
+	if err != nil {
+		return err
+	}
+
+	t.PkScript = make([]byte, scriptLen)
+	return ReadElements(r, t.PkScript, &t.ExtraData)
+}
+
+// MsgType returns the MessageType code which uniquely identifies this message
+// as a TxAddOutput on the wire.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxAddOutput) MsgType() MessageType {
+	return MsgTxAddOutput
+}
@@ -0,0 +1,86 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/brronsuite/bronutil"
+)
+
+// TxInitRbf is sent by the initiator of an interactively constructed
+// transaction that hasn't confirmed yet to propose a replacement paying a
+// higher fee rate. The replacement is then constructed through the tx_*
+// messages, and spends at least one input of the transaction it replaces.
+type TxInitRbf struct {
+	// ChannelID is the ID of the channel the transaction is constructed
+	// for.
+	ChannelID ChannelID
+
+	// LockTime is the lock time of the replacement transaction.
+	LockTime uint32
+
+	// FeeRate is the fee rate of the replacement transaction, expressed
+	// in sat per kilo-weight.
+	FeeRate uint32
+
+	// FundingContribution is the amount the initiator adds to the
+	// channel with the replacement transaction.
+	FundingContribution bronutil.Amount
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure TxInitRbf implements the lnwire.Message
+// interface.
+var _ Message = (*TxInitRbf)(nil)
+
+// Encode serializes the target TxInitRbf into the passed io.Writer
+// implementation. Serialization will observe the rules defined by the passed
+// protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxInitRbf) Encode(w *bytes.Buffer, pver uint32) error {
+	if err := WriteChannelID(w, t.ChannelID); err != nil {
+		return err
+	}
+
+	if err := WriteUint32(w, t.LockTime); err != nil {
+		return err
+	}
+
+	if err := WriteUint32(w, t.FeeRate); err != nil {
+		return err
+	}
+
+	if err := WriteBronees(w, t.FundingContribution); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, t.ExtraData)
+}
+
+// Decode deserializes the serialized TxInitRbf stored in the passed io.Reader
+// into the target TxInitRbf using the deserialization rules defined by the
+// passed protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxInitRbf) Decode(r io.Reader, pver uint32) error {
+	return ReadElements(r,
+		&t.ChannelID,
+		&t.LockTime,
+		&t.FeeRate,
+		&t.FundingContribution,
+		&t.ExtraData,
+	)
+}
+
+// MsgType returns the MessageType code which uniquely identifies this message
+// as a TxInitRbf on the wire.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxInitRbf) MsgType() MessageType {
+	return MsgTxInitRbf
+}
@@ -0,0 +1,190 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/brronsuite/broln/tlv"
+	"github.com/brronsuite/brond/chaincfg/chainhash"
+	"github.com/brronsuite/brond/wire"
+)
+
+const (
+	// SharedInputSigRecordType is the type of the record used to
+	// communicate the sender's signature for the shared funding input of
+	// a splice transaction.
+	SharedInputSigRecordType tlv.Type = 0
+)
+
+// TxSignatures is sent once an interactively constructed transaction and
+// the commitment transactions spending from it have been signed. It contains
+// the witnesses of all inputs the sender added to the transaction, in the
+// order the inputs appear in the transaction.
+type TxSignatures struct {
+	// ChannelID is the ID of the channel the transaction is constructed
+	// for.
+	ChannelID ChannelID
+
+	// TxID is the txid of the signed transaction.
+	TxID chainhash.Hash
+
+	// Witnesses are the witnesses of the inputs the sender contributed.
+	Witnesses []wire.TxWitness
+
+	// SharedInputSig is the sender's signature for the 2-of-2 multisig
+	// funding output of the channel that is spent by a splice
+	// transaction. This is an optional TLV field that is only set when
+	// the transaction is a splice.
+	SharedInputSig *Sig
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure TxSignatures implements the lnwire.Message
+// interface.
+var _ Message = (*TxSignatures)(nil)
+
+// Encode serializes the target TxSignatures into the passed io.Writer
+// implementation. Serialization will observe the rules defined by the passed
+// protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxSignatures) Encode(w *bytes.Buffer, pver uint32) error {
+	if err := WriteChannelID(w, t.ChannelID); err != nil {
+		return err
+	}
+
+	if err := WriteBytes(w, t.TxID[:]); err != nil {
+		return err
+	}
+
+	// Each witness is encoded as the number of its elements followed by
+	// the length prefixed elements.
+	if err := WriteUint16(w, uint16(len(t.Witnesses))); err != nil {
+		return err
+	}
+	for _, witness := range t.Witnesses {
+		if err := WriteUint16(w, uint16(len(witness))); err != nil {
+			return err
+		}
+
+		for _, element := range witness {
+			if err := writeDataWithLength(w, element); err != nil {
+				return err
+			}
+		}
+	}
+
+	// We'll only encode the SharedInputSig in a TLV segment if it exists.
+	if t.SharedInputSig != nil {
+		err := EncodeMessageExtraData(
+			&t.ExtraData, &sharedInputSig{t.SharedInputSig},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return WriteBytes(w, t.ExtraData)
+}
+
+// Decode deserializes the serialized TxSignatures stored in the passed
+// io.Reader into the target TxSignatures using the deserialization rules
+// defined by the passed protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxSignatures) Decode(r io.Reader, pver uint32) error {
+	var numWitnesses uint16
+	err := ReadElements(r, &t.ChannelID, t.TxID[:], &numWitnesses)
+	if err != nil {
+		return err
+	}
+
+	t.Witnesses = nil
+	for i := 0; i < int(numWitnesses); i++ {
+		var numElements uint16
+		if err := ReadElement(r, &numElements); err != nil {
+			return err
+		}
+
+		witness := make(wire.TxWitness, numElements)
+		for j := range witness {
+			var elementLen uint16
+			if err := ReadElement(r, &elementLen); err != nil {
+				return err
+			}
+
+			witness[j] = make([]byte, elementLen)
+			if err := ReadElement(r, witness[j]); err != nil {
+				return err
+			}
+		}
+
+		t.Witnesses = append(t.Witnesses, witness)
+	}
+
+	// Next we'll parse out the set of known records, keeping the raw tlv
+	// bytes untouched to ensure we don't drop any bytes erroneously.
+	var tlvRecords ExtraOpaqueData
+	if err := ReadElements(r, &tlvRecords); err != nil {
+		return err
+	}
+
+	sig := sharedInputSig{new(Sig)}
+	typeMap, err := tlvRecords.ExtractRecords(&sig)
+	if err != nil {
+		return err
+	}
+
+	// Set the corresponding TLV types if they were included in the stream.
+	if val, ok := typeMap[SharedInputSigRecordType]; ok && val == nil {
+		t.SharedInputSig = sig.Sig
+	}
+
+	t.ExtraData = tlvRecords
+
+	return nil
+}
+
+// MsgType returns the MessageType code which uniquely identifies this message
+// as a TxSignatures on the wire.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxSignatures) MsgType() MessageType {
+	return MsgTxSignatures
+}
+
+// sharedInputSig is a thin wrapper around the shared input signature of a
+// TxSignatures message that implements the tlv.RecordProducer interface.
+type sharedInputSig struct {
+	*Sig
+}
+
+// Record returns a TLV record that can be used to encode/decode the shared
+// input signature from a given TLV stream.
+func (s *sharedInputSig) Record() tlv.Record {
+	return tlv.MakeStaticRecord(
+		SharedInputSigRecordType, s.Sig, 64, sigEncoder, sigDecoder,
+	)
+}
+
+// sigEncoder is a custom TLV encoder for a Sig record.
+func sigEncoder(w io.Writer, val interface{}, buf *[8]byte) error {
+	if v, ok := val.(*Sig); ok {
+		return tlv.EBytes64(w, (*[64]byte)(v), buf)
+	}
+
+	return tlv.NewTypeForEncodingErr(val, "lnwire.Sig")
+}
+
+// sigDecoder is a custom TLV decoder for a Sig record.
+func sigDecoder(r io.Reader, val interface{}, buf *[8]byte, l uint64) error {
+	if v, ok := val.(*Sig); ok {
+		return tlv.DBytes64(r, (*[64]byte)(v), buf, l)
+	}
+
+	return tlv.NewTypeForDecodingErr(val, "lnwire.Sig", l, 64)
+}
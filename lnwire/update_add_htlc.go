@@ -3,6 +3,9 @@ package lnwire
 import (
 	"bytes"
 	"io"
+
+	"github.com/brronsuite/broln/tlv"
+	"github.com/brronsuite/brond/bronec"
 )
 
 // OnionPacketSize is the size of the serialized Sphinx onion packet included
@@ -11,6 +14,12 @@ import (
 // of per-hop data, and a 32-byte HMAC over the entire packet.
 const OnionPacketSize = 1366
 
+const (
+	// BlindingPointRecordType is the type of the record used to pass the
+	// route blinding point of an HTLC in the UpdateAddHTLC message.
+	BlindingPointRecordType tlv.Type = 0
+)
+
 // UpdateAddHTLC is the message sent by Alice to Bob when she wishes to add an
 // HTLC to his remote commitment transaction. In addition to information
 // detailing the value, the ID, expiry, and the onion blob is also included
@@ -54,6 +63,11 @@ type UpdateAddHTLC struct {
 	// used in the subsequent UpdateAddHTLC message.
 	OnionBlob [OnionPacketSize]byte
 
+	// BlindingPoint is the ephemeral point used by the receiving node to
+	// derive the key it needs to process an HTLC that is routed through a
+	// blinded route. This is an optional TLV field.
+	BlindingPoint *bronec.PublicKey
+
 	// ExtraData is the set of data that was appended to this message to
 	// fill out the full maximum transport message size. These fields can
 	// be used to specify optional data such as custom TLV fields.
@@ -74,15 +88,40 @@ var _ Message = (*UpdateAddHTLC)(nil)
 //
 // This is part of the lnwire.Message interface.
 func (c *UpdateAddHTLC) Decode(r io.Reader, pver uint32) error {
-	return ReadElements(r,
+	// Read all the mandatory fields in the message.
+	err := ReadElements(r,
 		&c.ChanID,
 		&c.ID,
 		&c.Amount,
 		c.PaymentHash[:],
 		&c.Expiry,
 		c.OnionBlob[:],
-		&c.ExtraData,
 	)
+	if err != nil {
+		return err
+	}
+
+	// Next we'll parse out the set of known records, keeping the raw tlv
+	// bytes untouched to ensure we don't drop any bytes erroneously.
+	var tlvRecords ExtraOpaqueData
+	if err := ReadElements(r, &tlvRecords); err != nil {
+		return err
+	}
+
+	var blinding blindingPoint
+	typeMap, err := tlvRecords.ExtractRecords(&blinding)
+	if err != nil {
+		return err
+	}
+
+	// Set the corresponding TLV types if they were included in the stream.
+	if val, ok := typeMap[BlindingPointRecordType]; ok && val == nil {
+		c.BlindingPoint = blinding.key
+	}
+
+	c.ExtraData = tlvRecords
+
+	return nil
 }
 
 // Encode serializes the target UpdateAddHTLC into the passed io.Writer
@@ -114,6 +153,16 @@ func (c *UpdateAddHTLC) Encode(w *bytes.Buffer, pver uint32) error {
 		return err
 	}
 
+	// We'll only encode the BlindingPoint in a TLV segment if it exists.
+	if c.BlindingPoint != nil {
+		err := EncodeMessageExtraData(
+			&c.ExtraData, &blindingPoint{key: c.BlindingPoint},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
 	return WriteBytes(w, c.ExtraData)
 }
 
@@ -132,3 +181,15 @@ func (c *UpdateAddHTLC) MsgType() MessageType {
 func (c *UpdateAddHTLC) TargetChanID() ChannelID {
 	return c.ChanID
 }
+
+// blindingPoint wraps the blinding point of an HTLC so it can be encoded as a
+// TLV record.
+type blindingPoint struct {
+	key *bronec.PublicKey
+}
+
+// Record returns a TLV record that can be used to encode/decode the blinding
+// point of an HTLC to/from a TLV stream.
+func (b *blindingPoint) Record() tlv.Record {
+	return tlv.MakePrimitiveRecord(BlindingPointRecordType, &b.key)
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/brronsuite/broln/invoices"
 	"github.com/brronsuite/broln/lnrpc/autopilotrpc"
 	"github.com/brronsuite/broln/lnrpc/chainrpc"
+	"github.com/brronsuite/broln/lnrpc/feemanagerrpc"
 	"github.com/brronsuite/broln/lnrpc/invoicesrpc"
 	"github.com/brronsuite/broln/lnrpc/routerrpc"
 	"github.com/brronsuite/broln/lnrpc/signrpc"
@@ -31,10 +32,14 @@ import (
 	"github.com/brronsuite/broln/lnwallet/rpcwallet"
 	"github.com/brronsuite/broln/monitoring"
 	"github.com/brronsuite/broln/netann"
+	"github.com/brronsuite/broln/offers"
+	"github.com/brronsuite/broln/onionmsg"
 	"github.com/brronsuite/broln/peer"
 	"github.com/brronsuite/broln/peernotifier"
 	"github.com/brronsuite/broln/routing"
+	"github.com/brronsuite/broln/routing/feemanager"
 	"github.com/brronsuite/broln/routing/localchans"
+	"github.com/brronsuite/broln/routing/trampoline"
 	"github.com/brronsuite/broln/rpcperms"
 	"github.com/brronsuite/broln/signal"
 	"github.com/brronsuite/broln/sweep"
@@ -140,6 +145,7 @@ func SetupLoggers(root *build.RotatingLogWriter, interceptor signal.Interceptor)
 	AddSubLogger(root, "SGNR", interceptor, signrpc.UseLogger)
 	AddSubLogger(root, "WLKT", interceptor, walletrpc.UseLogger)
 	AddSubLogger(root, "ARPC", interceptor, autopilotrpc.UseLogger)
+	AddSubLogger(root, "FMRP", interceptor, feemanagerrpc.UseLogger)
 	AddSubLogger(root, "INVC", interceptor, invoices.UseLogger)
 	AddSubLogger(root, "NANN", interceptor, netann.UseLogger)
 	AddSubLogger(root, "WTWR", interceptor, watchtower.UseLogger)
@@ -167,6 +173,10 @@ func SetupLoggers(root *build.RotatingLogWriter, interceptor signal.Interceptor)
 	AddSubLogger(root, tor.Subsystem, interceptor, tor.UseLogger)
 	AddSubLogger(root, bronwallet.Subsystem, interceptor, bronwallet.UseLogger)
 	AddSubLogger(root, rpcwallet.Subsystem, interceptor, rpcwallet.UseLogger)
+	AddSubLogger(root, onionmsg.Subsystem, interceptor, onionmsg.UseLogger)
+	AddSubLogger(root, offers.Subsystem, interceptor, offers.UseLogger)
+	AddSubLogger(root, feemanager.Subsystem, interceptor, feemanager.UseLogger)
+	AddSubLogger(root, trampoline.Subsystem, interceptor, trampoline.UseLogger)
 }
 
 // AddSubLogger is a helper method to conveniently create and register the
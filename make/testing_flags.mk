@@ -1,5 +1,5 @@
 DEV_TAGS = dev
-RPC_TAGS = autopilotrpc chainrpc invoicesrpc routerrpc signrpc verrpc walletrpc watchtowerrpc wtclientrpc
+RPC_TAGS = autopilotrpc chainrpc feemanagerrpc invoicesrpc routerrpc signrpc verrpc walletrpc watchtowerrpc wtclientrpc
 LOG_TAGS =
 TEST_FLAGS =
 ITEST_FLAGS = 
@@ -63,6 +63,10 @@ ifeq ($(dbbackend),postgres)
 DEV_TAGS += kvdb_postgres
 endif
 
+ifeq ($(dbbackend),sqlite)
+DEV_TAGS += kvdb_sqlite
+endif
+
 ifneq ($(tags),)
 DEV_TAGS += ${tags}
 endif
@@ -52,6 +52,11 @@ func (n *NodeSigner) SignMessageCompact(msg []byte, doubleHash bool) ([]byte,
 	return n.keySigner.SignMessageCompact(msg, doubleHash)
 }
 
+// PubKey returns the identity public key of the resident node.
+func (n *NodeSigner) PubKey() *bronec.PublicKey {
+	return n.keySigner.PubKey()
+}
+
 // A compile time check to ensure that NodeSigner implements the MessageSigner
 // interface.
 var _ lnwallet.MessageSigner = (*NodeSigner)(nil)
@@ -0,0 +1,86 @@
+package offers
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/brronsuite/bronutil/bech32"
+)
+
+// charset is the bech32 character set that BOLT 12 strings are encoded with.
+const charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// encodeString encodes the data as a BOLT 12 string with the given
+// human-readable part. Unlike BOLT 11 invoices, BOLT 12 strings don't carry a
+// checksum, as the messages are signed or committed to in other ways.
+func encodeString(hrp string, data []byte) (string, error) {
+	base32, err := bech32.ConvertBits(data, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.Grow(len(hrp) + 1 + len(base32))
+	b.WriteString(hrp)
+	b.WriteByte('1')
+	for _, c := range base32 {
+		b.WriteByte(charset[c])
+	}
+
+	return b.String(), nil
+}
+
+// decodeString decodes a BOLT 12 string, returning its human-readable part
+// and data. Strings may be split into several parts that are joined by a '+'
+// followed by optional whitespace.
+func decodeString(s string) (string, []byte, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '+' {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		// A '+' must be surrounded by bech32 characters.
+		if i == 0 || i == len(s)-1 {
+			return "", nil, fmt.Errorf("invalid '+' at position %v",
+				i)
+		}
+		for i+1 < len(s) && unicode.IsSpace(rune(s[i+1])) {
+			i++
+		}
+	}
+	s = b.String()
+
+	// The characters must be either all lowercase or all uppercase.
+	lower := strings.ToLower(s)
+	if s != lower && s != strings.ToUpper(s) {
+		return "", nil, fmt.Errorf("string not all lowercase or all " +
+			"uppercase")
+	}
+	s = lower
+
+	one := strings.LastIndexByte(s, '1')
+	if one < 1 || one == len(s)-1 {
+		return "", nil, fmt.Errorf("invalid index of 1")
+	}
+
+	hrp := s[:one]
+	base32 := make([]byte, 0, len(s)-one-1)
+	for i := one + 1; i < len(s); i++ {
+		c := strings.IndexByte(charset, s[i])
+		if c < 0 {
+			return "", nil, fmt.Errorf("invalid character in "+
+				"string: '%c'", s[i])
+		}
+		base32 = append(base32, byte(c))
+	}
+
+	data, err := bech32.ConvertBits(base32, 5, 8, false)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return hrp, data, nil
+}
@@ -0,0 +1,483 @@
+package offers
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/brronsuite/broln/blinding"
+	"github.com/brronsuite/broln/lntypes"
+	"github.com/brronsuite/broln/lnwire"
+	"github.com/brronsuite/broln/tlv"
+	"github.com/brronsuite/brond/bronec"
+	"github.com/brronsuite/brond/bronec/schnorr"
+)
+
+const (
+	// invoiceHRP is the human-readable part of encoded invoices.
+	invoiceHRP = "lni"
+
+	// invoiceMessage is the message name that invoices are signed with.
+	invoiceMessage = "invoice"
+
+	invoicePathsType          tlv.Type = 160
+	invoiceBlindedPayType     tlv.Type = 162
+	invoiceCreatedAtType      tlv.Type = 164
+	invoiceRelativeExpiryType tlv.Type = 166
+	invoicePaymentHashType    tlv.Type = 168
+	invoiceAmountType         tlv.Type = 170
+	invoiceFeaturesType       tlv.Type = 174
+	invoiceNodeIDType         tlv.Type = 176
+
+	// maxInvoiceType is the exclusive upper bound of the types that
+	// belong to the invoice, excluding the signature.
+	maxInvoiceType = 240
+
+	// DefaultInvoiceExpiry is the expiry of invoices that don't specify a
+	// relative expiry.
+	DefaultInvoiceExpiry = 2 * time.Hour
+)
+
+var (
+	// ErrNoPaymentPaths is returned when an invoice doesn't contain any
+	// paths to pay it through.
+	ErrNoPaymentPaths = errors.New("invoice without payment paths")
+
+	// invoiceKnownTypes are the invoice types we understand, in addition
+	// to the types of the invoice request.
+	invoiceKnownTypes = map[uint64]struct{}{
+		uint64(invoicePathsType):          {},
+		uint64(invoiceBlindedPayType):     {},
+		uint64(invoiceCreatedAtType):      {},
+		uint64(invoiceRelativeExpiryType): {},
+		uint64(invoicePaymentHashType):    {},
+		uint64(invoiceAmountType):         {},
+		uint64(invoiceFeaturesType):       {},
+		uint64(invoiceNodeIDType):         {},
+	}
+)
+
+// PayInfo holds the aggregated relay parameters of a blinded payment path.
+type PayInfo struct {
+	// FeeBaseMsat is the total base fee of the path.
+	FeeBaseMsat uint32
+
+	// FeeRate is the total proportional fee of the path in millionths.
+	FeeRate uint32
+
+	// CltvExpiryDelta is the total CLTV delta of the path, including the
+	// final CLTV delta of the recipient.
+	CltvExpiryDelta uint16
+
+	// HtlcMinimumMsat is the minimum amount that the path accepts.
+	HtlcMinimumMsat lnwire.MilliBronees
+
+	// HtlcMaximumMsat is the maximum amount that the path accepts.
+	HtlcMaximumMsat lnwire.MilliBronees
+
+	// Features are the features of the path.
+	Features *lnwire.RawFeatureVector
+}
+
+// PaymentPath is a blinded path to the recipient of an invoice together with
+// the parameters needed to pay through it.
+type PaymentPath struct {
+	// Path is the blinded path to the recipient.
+	Path *blinding.BlindedPath
+
+	// PayInfo holds the relay parameters of the path.
+	PayInfo *PayInfo
+}
+
+// Invoice is an invoice that is sent in response to an invoice request. It
+// contains all records of the invoice request it answers, except for its
+// signature.
+type Invoice struct {
+	// Request is the invoice request that is answered by the invoice.
+	Request *InvoiceRequest
+
+	// Paths are the blinded paths the invoice can be paid through.
+	Paths []*PaymentPath
+
+	// CreatedAt is the time the invoice was created at.
+	CreatedAt time.Time
+
+	// RelativeExpiry is the duration after its creation that the invoice
+	// can be paid in. If zero, DefaultInvoiceExpiry applies.
+	RelativeExpiry time.Duration
+
+	// PaymentHash is the payment hash of the invoice.
+	PaymentHash lntypes.Hash
+
+	// Amount is the amount to pay.
+	Amount lnwire.MilliBronees
+
+	// Features are the features of the invoice.
+	Features *lnwire.RawFeatureVector
+
+	// NodeID is the key that the invoice is signed with.
+	NodeID *bronec.PublicKey
+
+	// Signature is the signature of the invoice by the node id.
+	Signature *schnorr.Signature
+}
+
+// encodePayInfos serializes the pay infos of the payment paths.
+func encodePayInfos(paths []*PaymentPath) ([]byte, error) {
+	var b bytes.Buffer
+	for _, path := range paths {
+		info := path.PayInfo
+
+		var features []byte
+		if info.Features != nil {
+			var err error
+			features, err = encodeFeatures(info.Features)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		var buf [28]byte
+		binary.BigEndian.PutUint32(buf[0:4], info.FeeBaseMsat)
+		binary.BigEndian.PutUint32(buf[4:8], info.FeeRate)
+		binary.BigEndian.PutUint16(buf[8:10], info.CltvExpiryDelta)
+		binary.BigEndian.PutUint64(
+			buf[10:18], uint64(info.HtlcMinimumMsat),
+		)
+		binary.BigEndian.PutUint64(
+			buf[18:26], uint64(info.HtlcMaximumMsat),
+		)
+		binary.BigEndian.PutUint16(buf[26:28], uint16(len(features)))
+
+		b.Write(buf[:])
+		b.Write(features)
+	}
+
+	return b.Bytes(), nil
+}
+
+// decodePayInfos parses the pay infos of payment paths.
+func decodePayInfos(v []byte) ([]*PayInfo, error) {
+	var (
+		infos []*PayInfo
+		r     = bytes.NewReader(v)
+	)
+	for r.Len() > 0 {
+		var buf [28]byte
+		if _, err := io.ReadFull(r, buf[:]); err != nil {
+			return nil, err
+		}
+
+		featuresLen := int(binary.BigEndian.Uint16(buf[26:28]))
+		if featuresLen > r.Len() {
+			return nil, io.ErrUnexpectedEOF
+		}
+		features := make([]byte, featuresLen)
+		if _, err := io.ReadFull(r, features); err != nil {
+			return nil, err
+		}
+
+		fv, err := decodeFeatures(features)
+		if err != nil {
+			return nil, err
+		}
+
+		infos = append(infos, &PayInfo{
+			FeeBaseMsat:     binary.BigEndian.Uint32(buf[0:4]),
+			FeeRate:         binary.BigEndian.Uint32(buf[4:8]),
+			CltvExpiryDelta: binary.BigEndian.Uint16(buf[8:10]),
+			HtlcMinimumMsat: lnwire.MilliBronees(
+				binary.BigEndian.Uint64(buf[10:18]),
+			),
+			HtlcMaximumMsat: lnwire.MilliBronees(
+				binary.BigEndian.Uint64(buf[18:26]),
+			),
+			Features: fv,
+		})
+	}
+
+	return infos, nil
+}
+
+// records returns the TLV records of the invoice, excluding the signature.
+func (i *Invoice) records() ([]tlv.Record, error) {
+	records, err := i.Request.records()
+	if err != nil {
+		return nil, err
+	}
+
+	blindedPaths := make([]*blinding.BlindedPath, 0, len(i.Paths))
+	for _, path := range i.Paths {
+		blindedPaths = append(blindedPaths, path.Path)
+	}
+
+	paths, err := encodeBlindedPaths(blindedPaths)
+	if err != nil {
+		return nil, err
+	}
+
+	payInfos, err := encodePayInfos(i.Paths)
+	if err != nil {
+		return nil, err
+	}
+
+	createdAt := uint64(i.CreatedAt.Unix())
+	paymentHash := [32]byte(i.PaymentHash)
+	amt := uint64(i.Amount)
+
+	records = append(records,
+		tlv.MakePrimitiveRecord(invoicePathsType, &paths),
+		tlv.MakePrimitiveRecord(invoiceBlindedPayType, &payInfos),
+		tu64Record(invoiceCreatedAtType, &createdAt),
+		tlv.MakePrimitiveRecord(invoicePaymentHashType, &paymentHash),
+		tu64Record(invoiceAmountType, &amt),
+	)
+
+	if i.RelativeExpiry != 0 {
+		expiry := uint32(i.RelativeExpiry / time.Second)
+		records = append(records, tu32Record(
+			invoiceRelativeExpiryType, &expiry,
+		))
+	}
+
+	if i.Features != nil && i.Features.SerializeSize() > 0 {
+		features, err := encodeFeatures(i.Features)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, tlv.MakePrimitiveRecord(
+			invoiceFeaturesType, &features,
+		))
+	}
+
+	if i.NodeID != nil {
+		records = append(records, tlv.MakePrimitiveRecord(
+			invoiceNodeIDType, &i.NodeID,
+		))
+	}
+
+	return records, nil
+}
+
+// decodeRecords populates the invoice from the invoice records among the
+// passed records.
+func (i *Invoice) decodeRecords(records []rawRecord) error {
+	var (
+		paths    []*blinding.BlindedPath
+		payInfos []*PayInfo
+		err      error
+	)
+	for _, r := range records {
+		switch tlv.Type(r.typ) {
+		case invoicePathsType:
+			paths, err = decodeBlindedPaths(r.value)
+
+		case invoiceBlindedPayType:
+			payInfos, err = decodePayInfos(r.value)
+
+		case invoiceCreatedAtType:
+			var createdAt uint64
+			createdAt, err = decodeTU64(r.value)
+			i.CreatedAt = time.Unix(int64(createdAt), 0)
+
+		case invoiceRelativeExpiryType:
+			var expiry uint32
+			expiry, err = decodeTU32(r.value)
+			i.RelativeExpiry = time.Duration(expiry) * time.Second
+
+		case invoicePaymentHashType:
+			if len(r.value) != lntypes.HashSize {
+				return fmt.Errorf("invalid payment hash "+
+					"length %v", len(r.value))
+			}
+			copy(i.PaymentHash[:], r.value)
+
+		case invoiceAmountType:
+			var amt uint64
+			amt, err = decodeTU64(r.value)
+			i.Amount = lnwire.MilliBronees(amt)
+
+		case invoiceFeaturesType:
+			i.Features, err = decodeFeatures(r.value)
+
+		case invoiceNodeIDType:
+			i.NodeID, err = decodePubKey(r.value)
+
+		case signatureType:
+			i.Signature, err = schnorr.ParseSignature(r.value)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid invoice record %v: %w",
+				r.typ, err)
+		}
+	}
+
+	if len(paths) == 0 {
+		return ErrNoPaymentPaths
+	}
+	if len(paths) != len(payInfos) {
+		return fmt.Errorf("invoice with %v paths but %v pay infos",
+			len(paths), len(payInfos))
+	}
+
+	for idx, path := range paths {
+		i.Paths = append(i.Paths, &PaymentPath{
+			Path:    path,
+			PayInfo: payInfos[idx],
+		})
+	}
+
+	return nil
+}
+
+// Expiry returns the time after which the invoice can't be paid anymore.
+func (i *Invoice) Expiry() time.Time {
+	expiry := i.RelativeExpiry
+	if expiry == 0 {
+		expiry = DefaultInvoiceExpiry
+	}
+
+	return i.CreatedAt.Add(expiry)
+}
+
+// Digest returns the digest of the invoice that is signed by the issuer.
+func (i *Invoice) Digest() ([32]byte, error) {
+	records, err := i.records()
+	if err != nil {
+		return [32]byte{}, err
+	}
+
+	b, err := encodeRecords(records)
+	if err != nil {
+		return [32]byte{}, err
+	}
+
+	parsed, err := parseRecords(b)
+	if err != nil {
+		return [32]byte{}, err
+	}
+
+	return signatureDigest(invoiceMessage, parsed), nil
+}
+
+// Sign signs the invoice with the passed signer, which must sign with the key
+// of the node id.
+func (i *Invoice) Sign(
+	signer func([32]byte) (*schnorr.Signature, error)) error {
+
+	digest, err := i.Digest()
+	if err != nil {
+		return err
+	}
+
+	i.Signature, err = signer(digest)
+
+	return err
+}
+
+// Serialize returns the TLV encoding of the invoice, including its
+// signature.
+func (i *Invoice) Serialize() ([]byte, error) {
+	records, err := i.records()
+	if err != nil {
+		return nil, err
+	}
+
+	if i.Signature != nil {
+		var sig [64]byte
+		copy(sig[:], i.Signature.Serialize())
+		records = append(records, tlv.MakePrimitiveRecord(
+			signatureType, &sig,
+		))
+	}
+
+	return encodeRecords(records)
+}
+
+// Encode returns the invoice as an lni string.
+func (i *Invoice) Encode() (string, error) {
+	b, err := i.Serialize()
+	if err != nil {
+		return "", err
+	}
+
+	return encodeString(invoiceHRP, b)
+}
+
+// DeserializeInvoice parses the TLV encoding of an invoice and verifies its
+// signature.
+func DeserializeInvoice(b []byte) (*Invoice, error) {
+	records, err := parseRecords(b)
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[uint64]struct{})
+	for _, types := range []map[uint64]struct{}{
+		offerKnownTypes, invoiceRequestKnownTypes, invoiceKnownTypes,
+	} {
+		for typ := range types {
+			known[typ] = struct{}{}
+		}
+	}
+	err = checkTypes(records, known, func(typ uint64) bool {
+		return typ < maxInvoiceType || typ == uint64(signatureType)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The signature of the invoice request isn't part of the invoice, so
+	// the request records are decoded without verifying it.
+	offer := &Offer{}
+	if err := offer.decodeRecords(records); err != nil {
+		return nil, err
+	}
+
+	request := &InvoiceRequest{
+		Offer: offer,
+	}
+	requestRecords := make([]rawRecord, 0, len(records))
+	for _, r := range records {
+		if r.typ < maxInvoiceRequestType {
+			requestRecords = append(requestRecords, r)
+		}
+	}
+	if err := request.decodeRecords(requestRecords); err != nil {
+		return nil, err
+	}
+
+	i := &Invoice{
+		Request: request,
+	}
+	if err := i.decodeRecords(records); err != nil {
+		return nil, err
+	}
+
+	if i.NodeID == nil {
+		return nil, errors.New("invoice without node id")
+	}
+
+	err = verifySignature(invoiceMessage, records, i.Signature, i.NodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	return i, nil
+}
+
+// DecodeInvoice parses an lni string and verifies its signature.
+func DecodeInvoice(s string) (*Invoice, error) {
+	hrp, b, err := decodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if hrp != invoiceHRP {
+		return nil, fmt.Errorf("invalid invoice prefix %v", hrp)
+	}
+
+	return DeserializeInvoice(b)
+}
@@ -0,0 +1,96 @@
+package offers
+
+import (
+	"fmt"
+
+	"github.com/brronsuite/broln/tlv"
+)
+
+const (
+	invoiceErrorFieldType          tlv.Type = 1
+	invoiceErrorSuggestedValueType tlv.Type = 3
+	invoiceErrorMessageType        tlv.Type = 5
+)
+
+// InvoiceError is sent in response to an invoice request or invoice that
+// couldn't be processed.
+type InvoiceError struct {
+	// ErroneousField is the TLV type of the record that caused the error,
+	// if the error is caused by a single record.
+	ErroneousField *uint64
+
+	// SuggestedValue is a value of the erroneous field that would be
+	// accepted.
+	SuggestedValue []byte
+
+	// Message is a description of the error.
+	Message string
+}
+
+// Error returns the description of the error.
+//
+// NOTE: Part of the error interface.
+func (e *InvoiceError) Error() string {
+	if e.ErroneousField != nil {
+		return fmt.Sprintf("invoice error in field %v: %v",
+			*e.ErroneousField, e.Message)
+	}
+
+	return fmt.Sprintf("invoice error: %v", e.Message)
+}
+
+// Serialize returns the TLV encoding of the error.
+func (e *InvoiceError) Serialize() ([]byte, error) {
+	message := []byte(e.Message)
+	records := []tlv.Record{
+		tlv.MakePrimitiveRecord(invoiceErrorMessageType, &message),
+	}
+
+	if e.ErroneousField != nil {
+		records = append(records, tu64Record(
+			invoiceErrorFieldType, e.ErroneousField,
+		))
+	}
+
+	if e.SuggestedValue != nil {
+		records = append(records, tlv.MakePrimitiveRecord(
+			invoiceErrorSuggestedValueType, &e.SuggestedValue,
+		))
+	}
+
+	return encodeRecords(records)
+}
+
+// DeserializeInvoiceError parses the TLV encoding of an invoice error.
+func DeserializeInvoiceError(b []byte) (*InvoiceError, error) {
+	records, err := parseRecords(b)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &InvoiceError{}
+	for _, r := range records {
+		switch tlv.Type(r.typ) {
+		case invoiceErrorFieldType:
+			field, err := decodeTU64(r.value)
+			if err != nil {
+				return nil, err
+			}
+			e.ErroneousField = &field
+
+		case invoiceErrorSuggestedValueType:
+			e.SuggestedValue = r.value
+
+		case invoiceErrorMessageType:
+			e.Message = string(r.value)
+
+		default:
+			if r.typ%2 == 0 {
+				return nil, fmt.Errorf("unknown required tlv "+
+					"type %v", r.typ)
+			}
+		}
+	}
+
+	return e, nil
+}
@@ -0,0 +1,334 @@
+package offers
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/brronsuite/broln/lnwire"
+	"github.com/brronsuite/broln/tlv"
+	"github.com/brronsuite/brond/bronec"
+	"github.com/brronsuite/brond/bronec/schnorr"
+	"github.com/brronsuite/brond/chaincfg/chainhash"
+)
+
+const (
+	// invoiceRequestHRP is the human-readable part of encoded invoice
+	// requests.
+	invoiceRequestHRP = "lnr"
+
+	// invoiceRequestMessage is the message name that invoice requests are
+	// signed with.
+	invoiceRequestMessage = "invoice_request"
+
+	invReqMetadataType  tlv.Type = 0
+	invReqChainType     tlv.Type = 80
+	invReqAmountType    tlv.Type = 82
+	invReqFeaturesType  tlv.Type = 84
+	invReqQuantityType  tlv.Type = 86
+	invReqPayerIDType   tlv.Type = 88
+	invReqPayerNoteType tlv.Type = 89
+
+	// maxInvoiceRequestType is the exclusive upper bound of the types
+	// that belong to the invoice request, excluding the signature.
+	maxInvoiceRequestType = 160
+)
+
+var (
+	// ErrMissingPayerID is returned when an invoice request doesn't
+	// contain the key of the payer.
+	ErrMissingPayerID = errors.New("invoice request without payer id")
+
+	// ErrMissingMetadata is returned when an invoice request doesn't
+	// contain metadata.
+	ErrMissingMetadata = errors.New("invoice request without metadata")
+
+	// invoiceRequestKnownTypes are the invoice request types we
+	// understand, in addition to the types of the offer.
+	invoiceRequestKnownTypes = map[uint64]struct{}{
+		uint64(invReqMetadataType):  {},
+		uint64(invReqChainType):     {},
+		uint64(invReqAmountType):    {},
+		uint64(invReqFeaturesType):  {},
+		uint64(invReqQuantityType):  {},
+		uint64(invReqPayerIDType):   {},
+		uint64(invReqPayerNoteType): {},
+		uint64(signatureType):       {},
+	}
+)
+
+// InvoiceRequest is a request of a payer for an invoice for an offer. It
+// contains all records of the offer it is for.
+type InvoiceRequest struct {
+	// Offer is the offer that the invoice is requested for.
+	Offer *Offer
+
+	// Metadata is unpredictable data of the payer, which makes the
+	// request unique.
+	Metadata []byte
+
+	// Chain is the genesis hash of the chain the payer wants to pay on. It
+	// may only be nil if the payment is on brocoin mainnet.
+	Chain *chainhash.Hash
+
+	// Amount is the amount the payer wants to pay. It must be set if the
+	// offer doesn't contain an amount.
+	Amount lnwire.MilliBronees
+
+	// Features are the features of the invoice request.
+	Features *lnwire.RawFeatureVector
+
+	// Quantity is the number of items requested, if the offer allows
+	// requesting several items.
+	Quantity *uint64
+
+	// PayerID is the transient key of the payer that the request is signed
+	// with.
+	PayerID *bronec.PublicKey
+
+	// PayerNote is a note of the payer to the issuer.
+	PayerNote string
+
+	// Signature is the signature of the request by the payer id.
+	Signature *schnorr.Signature
+}
+
+// records returns the TLV records of the request, excluding the signature.
+func (r *InvoiceRequest) records() ([]tlv.Record, error) {
+	records, err := r.Offer.records()
+	if err != nil {
+		return nil, err
+	}
+
+	records = append(records, tlv.MakePrimitiveRecord(
+		invReqMetadataType, &r.Metadata,
+	))
+
+	if r.Chain != nil {
+		chain := [32]byte(*r.Chain)
+		records = append(records, tlv.MakePrimitiveRecord(
+			invReqChainType, &chain,
+		))
+	}
+
+	if r.Amount != 0 {
+		amt := uint64(r.Amount)
+		records = append(records, tu64Record(invReqAmountType, &amt))
+	}
+
+	if r.Features != nil && r.Features.SerializeSize() > 0 {
+		features, err := encodeFeatures(r.Features)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, tlv.MakePrimitiveRecord(
+			invReqFeaturesType, &features,
+		))
+	}
+
+	if r.Quantity != nil {
+		records = append(records, tu64Record(
+			invReqQuantityType, r.Quantity,
+		))
+	}
+
+	if r.PayerID != nil {
+		records = append(records, tlv.MakePrimitiveRecord(
+			invReqPayerIDType, &r.PayerID,
+		))
+	}
+
+	if r.PayerNote != "" {
+		note := []byte(r.PayerNote)
+		records = append(records, tlv.MakePrimitiveRecord(
+			invReqPayerNoteType, &note,
+		))
+	}
+
+	return records, nil
+}
+
+// decodeRecords populates the request from the invoice request records among
+// the passed records.
+func (r *InvoiceRequest) decodeRecords(records []rawRecord) error {
+	var err error
+	for _, rec := range records {
+		switch tlv.Type(rec.typ) {
+		case invReqMetadataType:
+			r.Metadata = rec.value
+
+		case invReqChainType:
+			var chain *chainhash.Hash
+			chain, err = chainhash.NewHash(rec.value)
+			r.Chain = chain
+
+		case invReqAmountType:
+			var amt uint64
+			amt, err = decodeTU64(rec.value)
+			r.Amount = lnwire.MilliBronees(amt)
+
+		case invReqFeaturesType:
+			r.Features, err = decodeFeatures(rec.value)
+
+		case invReqQuantityType:
+			var quantity uint64
+			quantity, err = decodeTU64(rec.value)
+			r.Quantity = &quantity
+
+		case invReqPayerIDType:
+			r.PayerID, err = decodePubKey(rec.value)
+
+		case invReqPayerNoteType:
+			r.PayerNote = string(rec.value)
+
+		case signatureType:
+			r.Signature, err = schnorr.ParseSignature(rec.value)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid invoice request record "+
+				"%v: %w", rec.typ, err)
+		}
+	}
+
+	return nil
+}
+
+// ChainHash returns the genesis hash of the chain the payer wants to pay on.
+func (r *InvoiceRequest) ChainHash(defaultChain chainhash.Hash) chainhash.Hash {
+	if r.Chain != nil {
+		return *r.Chain
+	}
+
+	return defaultChain
+}
+
+// Digest returns the digest of the request that is signed by the payer.
+func (r *InvoiceRequest) Digest() ([32]byte, error) {
+	records, err := r.records()
+	if err != nil {
+		return [32]byte{}, err
+	}
+
+	b, err := encodeRecords(records)
+	if err != nil {
+		return [32]byte{}, err
+	}
+
+	parsed, err := parseRecords(b)
+	if err != nil {
+		return [32]byte{}, err
+	}
+
+	return signatureDigest(invoiceRequestMessage, parsed), nil
+}
+
+// Sign signs the request with the passed signer, which must sign with the key
+// of the payer id.
+func (r *InvoiceRequest) Sign(
+	signer func([32]byte) (*schnorr.Signature, error)) error {
+
+	digest, err := r.Digest()
+	if err != nil {
+		return err
+	}
+
+	r.Signature, err = signer(digest)
+
+	return err
+}
+
+// Serialize returns the TLV encoding of the request, including its signature.
+func (r *InvoiceRequest) Serialize() ([]byte, error) {
+	records, err := r.records()
+	if err != nil {
+		return nil, err
+	}
+
+	if r.Signature != nil {
+		var sig [64]byte
+		copy(sig[:], r.Signature.Serialize())
+		records = append(records, tlv.MakePrimitiveRecord(
+			signatureType, &sig,
+		))
+	}
+
+	return encodeRecords(records)
+}
+
+// Encode returns the request as an lnr string.
+func (r *InvoiceRequest) Encode() (string, error) {
+	b, err := r.Serialize()
+	if err != nil {
+		return "", err
+	}
+
+	return encodeString(invoiceRequestHRP, b)
+}
+
+// DeserializeInvoiceRequest parses the TLV encoding of an invoice request and
+// verifies its signature.
+func DeserializeInvoiceRequest(b []byte) (*InvoiceRequest, error) {
+	records, err := parseRecords(b)
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[uint64]struct{})
+	for typ := range offerKnownTypes {
+		known[typ] = struct{}{}
+	}
+	for typ := range invoiceRequestKnownTypes {
+		known[typ] = struct{}{}
+	}
+	err = checkTypes(records, known, func(typ uint64) bool {
+		return typ < maxInvoiceRequestType ||
+			typ == uint64(signatureType)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The offer the request is for may have expired or be otherwise
+	// unpayable, which is up to the issuer to decide, so it isn't
+	// validated here.
+	offer := &Offer{}
+	if err := offer.decodeRecords(records); err != nil {
+		return nil, err
+	}
+
+	r := &InvoiceRequest{
+		Offer: offer,
+	}
+	if err := r.decodeRecords(records); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case r.Metadata == nil:
+		return nil, ErrMissingMetadata
+
+	case r.PayerID == nil:
+		return nil, ErrMissingPayerID
+	}
+
+	err = verifySignature(
+		invoiceRequestMessage, records, r.Signature, r.PayerID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// DecodeInvoiceRequest parses an lnr string and verifies its signature.
+func DecodeInvoiceRequest(s string) (*InvoiceRequest, error) {
+	hrp, b, err := decodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if hrp != invoiceRequestHRP {
+		return nil, fmt.Errorf("invalid invoice request prefix %v", hrp)
+	}
+
+	return DeserializeInvoiceRequest(b)
+}
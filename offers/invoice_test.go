@@ -0,0 +1,101 @@
+package offers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/brronsuite/broln/blinding"
+	"github.com/brronsuite/broln/lntypes"
+	"github.com/brronsuite/broln/lnwire"
+	"github.com/brronsuite/brond/bronec"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestPaymentPath creates a blinded payment path to a random node.
+func newTestPaymentPath(t *testing.T) *PaymentPath {
+	sessionKey, err := bronec.NewPrivateKey(bronec.S256())
+	require.NoError(t, err)
+	nodeKey, err := bronec.NewPrivateKey(bronec.S256())
+	require.NoError(t, err)
+
+	path, err := blinding.BuildBlindedPath(sessionKey, []*blinding.HopInfo{
+		{
+			NodePub:   nodeKey.PubKey(),
+			PlainText: []byte{1, 2, 3},
+		},
+	})
+	require.NoError(t, err)
+
+	return &PaymentPath{
+		Path: path,
+		PayInfo: &PayInfo{
+			FeeBaseMsat:     1_000,
+			FeeRate:         100,
+			CltvExpiryDelta: 80,
+			HtlcMinimumMsat: 1,
+			HtlcMaximumMsat: 1_000_000,
+			Features:        lnwire.NewRawFeatureVector(),
+		},
+	}
+}
+
+// TestInvoiceEncoding tests that invoices survive an encoding round trip and
+// that only invoices signed by their node id are accepted.
+func TestInvoiceEncoding(t *testing.T) {
+	t.Parallel()
+
+	offer, issuerKey := newTestOffer(t)
+
+	payerKey, err := bronec.NewPrivateKey(bronec.S256())
+	require.NoError(t, err)
+
+	req := &InvoiceRequest{
+		Offer:    offer,
+		Metadata: []byte{4, 5, 6},
+		PayerID:  payerKey.PubKey(),
+	}
+	require.NoError(t, req.Sign(signer(payerKey)))
+
+	invoice := &Invoice{
+		Request:        req,
+		Paths:          []*PaymentPath{newTestPaymentPath(t)},
+		CreatedAt:      time.Unix(1_800_000_000, 0),
+		RelativeExpiry: time.Hour,
+		PaymentHash:    lntypes.Hash{1, 2, 3},
+		Amount:         offer.Amount,
+		NodeID:         issuerKey.PubKey(),
+	}
+	require.NoError(t, invoice.Sign(signer(issuerKey)))
+
+	encoded, err := invoice.Encode()
+	require.NoError(t, err)
+
+	decoded, err := DecodeInvoice(encoded)
+	require.NoError(t, err)
+	require.Equal(t, invoice.PaymentHash, decoded.PaymentHash)
+	require.Equal(t, invoice.Amount, decoded.Amount)
+	require.True(t, invoice.CreatedAt.Equal(decoded.CreatedAt))
+	require.True(t, invoice.Expiry().Equal(decoded.Expiry()))
+	require.Len(t, decoded.Paths, 1)
+	require.Equal(t, invoice.Paths[0].PayInfo, decoded.Paths[0].PayInfo)
+	require.Equal(t, req.Metadata, decoded.Request.Metadata)
+
+	reencoded, err := decoded.Encode()
+	require.NoError(t, err)
+	require.Equal(t, encoded, reencoded)
+
+	// An invoice that isn't signed by its node id is rejected.
+	require.NoError(t, invoice.Sign(signer(payerKey)))
+	b, err := invoice.Serialize()
+	require.NoError(t, err)
+	_, err = DeserializeInvoice(b)
+	require.ErrorIs(t, err, ErrInvalidSignature)
+
+	// An invoice without payment paths is rejected.
+	invoice.Paths = nil
+	require.NoError(t, invoice.Sign(signer(issuerKey)))
+	b, err = invoice.Serialize()
+	require.NoError(t, err)
+	_, err = DeserializeInvoice(b)
+	require.ErrorIs(t, err, ErrNoPaymentPaths)
+}
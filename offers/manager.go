@@ -0,0 +1,639 @@
+package offers
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/brronsuite/broln/blinding"
+	"github.com/brronsuite/broln/lntypes"
+	"github.com/brronsuite/broln/lnwire"
+	"github.com/brronsuite/broln/onionmsg"
+	"github.com/brronsuite/broln/subscribe"
+	"github.com/brronsuite/brond/bronec"
+	"github.com/brronsuite/brond/bronec/schnorr"
+	"github.com/brronsuite/brond/chaincfg"
+	"github.com/brronsuite/brond/chaincfg/chainhash"
+)
+
+const (
+	// InvoiceRequestRecordType is the onion message record type that
+	// carries invoice requests.
+	InvoiceRequestRecordType = 64
+
+	// InvoiceRecordType is the onion message record type that carries
+	// invoices.
+	InvoiceRecordType = 66
+
+	// InvoiceErrorRecordType is the onion message record type that
+	// carries invoice errors.
+	InvoiceErrorRecordType = 68
+
+	// DefaultRequestTimeout is the default time we wait for the issuer of
+	// an offer to answer our invoice request.
+	DefaultRequestTimeout = time.Minute
+
+	// metadataSize is the size of the random metadata we put in offers
+	// and invoice requests to make them unique.
+	metadataSize = 16
+
+	// pathIDSize is the size of the random path id of our reply paths.
+	pathIDSize = 16
+)
+
+var (
+	// ErrRequestTimeout is returned when the issuer of an offer doesn't
+	// answer our invoice request in time.
+	ErrRequestTimeout = errors.New("timeout waiting for invoice")
+
+	// ErrOfferExpired is returned when an offer can't be paid anymore.
+	ErrOfferExpired = errors.New("offer expired")
+
+	// ErrOfferDisabled is returned when an invoice is requested for an
+	// offer that we disabled.
+	ErrOfferDisabled = errors.New("offer disabled")
+
+	// ErrUnsupportedChain is returned when an offer can't be paid on the
+	// chain of our node.
+	ErrUnsupportedChain = errors.New("offer not payable on our chain")
+
+	// ErrManagerShuttingDown is returned when the manager is stopped while
+	// waiting for an invoice.
+	ErrManagerShuttingDown = errors.New("offer manager shutting down")
+)
+
+// Messenger sends and receives onion messages.
+type Messenger interface {
+	// SendMessage sends an onion message with the given custom records
+	// through the blinded path.
+	SendMessage(path, replyPath *blinding.BlindedPath,
+		customRecords map[uint64][]byte) error
+
+	// SubscribeMessages returns a subscription that delivers all onion
+	// messages addressed to our node.
+	SubscribeMessages() (*subscribe.Client, error)
+}
+
+// Config contains the dependencies of the Manager.
+type Config struct {
+	// Store persists the offers we created.
+	Store *Store
+
+	// NodeID is the identity key of our node, which is used as the issuer
+	// id of our offers.
+	NodeID *bronec.PublicKey
+
+	// ChainHash is the genesis hash of the chain our node operates on.
+	ChainHash chainhash.Hash
+
+	// SignDigest signs the digest with the identity key of our node.
+	SignDigest func(digest [32]byte) (*schnorr.Signature, error)
+
+	// Messenger sends and receives the onion messages that carry invoice
+	// requests and invoices.
+	Messenger Messenger
+
+	// AddInvoice adds an invoice for the given amount to the invoice
+	// registry and returns its payment hash and the blinded paths it can
+	// be paid through.
+	AddInvoice func(amt lnwire.MilliBronees, memo string,
+		expiry time.Duration) (lntypes.Hash, []*PaymentPath, error)
+
+	// RequestTimeout is the time we wait for the issuer of an offer to
+	// answer our invoice request.
+	RequestTimeout time.Duration
+
+	// Now returns the current time.
+	Now func() time.Time
+}
+
+// Manager creates offers, answers the invoice requests our peers send for
+// them and requests invoices for the offers we pay.
+type Manager struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg *Config
+
+	// pending maps the path ids of the reply paths of our invoice
+	// requests to the channel the reply is delivered on.
+	pending   map[string]chan *onionmsg.ReceivedMessage
+	pendingMu sync.Mutex
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewManager creates a new Manager from the given config.
+func NewManager(cfg *Config) *Manager {
+	return &Manager{
+		cfg:     cfg,
+		pending: make(map[string]chan *onionmsg.ReceivedMessage),
+		quit:    make(chan struct{}),
+	}
+}
+
+// Start starts the Manager.
+func (m *Manager) Start() error {
+	var err error
+	m.started.Do(func() {
+		log.Info("Offer manager starting")
+
+		var sub *subscribe.Client
+		sub, err = m.cfg.Messenger.SubscribeMessages()
+		if err != nil {
+			return
+		}
+
+		m.wg.Add(1)
+		go m.handleMessages(sub)
+	})
+
+	return err
+}
+
+// Stop stops the Manager.
+func (m *Manager) Stop() error {
+	m.stopped.Do(func() {
+		log.Info("Offer manager shutting down")
+
+		close(m.quit)
+		m.wg.Wait()
+	})
+
+	return nil
+}
+
+// handleMessages dispatches the onion messages addressed to our node.
+//
+// NOTE: This MUST be run as a goroutine.
+func (m *Manager) handleMessages(sub *subscribe.Client) {
+	defer m.wg.Done()
+	defer sub.Cancel()
+
+	for {
+		select {
+		case update := <-sub.Updates():
+			msg, ok := update.(*onionmsg.ReceivedMessage)
+			if !ok {
+				continue
+			}
+
+			_, ok = msg.CustomRecords[InvoiceRequestRecordType]
+			if ok {
+				m.handleInvoiceRequest(msg)
+				continue
+			}
+
+			m.deliverReply(msg)
+
+		case <-sub.Quit():
+			return
+
+		case <-m.quit:
+			return
+		}
+	}
+}
+
+// deliverReply hands an invoice or invoice error to the caller that waits for
+// it.
+func (m *Manager) deliverReply(msg *onionmsg.ReceivedMessage) {
+	m.pendingMu.Lock()
+	replyChan, ok := m.pending[string(msg.PathID)]
+	if ok {
+		delete(m.pending, string(msg.PathID))
+	}
+	m.pendingMu.Unlock()
+
+	if !ok {
+		log.Debugf("Ignoring onion message with unknown path id %x",
+			msg.PathID)
+		return
+	}
+
+	replyChan <- msg
+}
+
+// handleInvoiceRequest answers an invoice request with an invoice or, if we
+// can't create one, with an invoice error.
+func (m *Manager) handleInvoiceRequest(msg *onionmsg.ReceivedMessage) {
+	if msg.ReplyPath == nil {
+		log.Debugf("Ignoring invoice request without reply path")
+		return
+	}
+
+	records := make(map[uint64][]byte)
+	invoice, err := m.createInvoice(
+		msg.CustomRecords[InvoiceRequestRecordType],
+	)
+	if err == nil {
+		records[InvoiceRecordType], err = invoice.Serialize()
+	}
+	if err != nil {
+		log.Debugf("Unable to answer invoice request: %v", err)
+
+		var invErr *InvoiceError
+		if !errors.As(err, &invErr) {
+			invErr = &InvoiceError{
+				Message: err.Error(),
+			}
+		}
+
+		records[InvoiceErrorRecordType], err = invErr.Serialize()
+		if err != nil {
+			log.Errorf("Unable to serialize invoice error: %v", err)
+			return
+		}
+	}
+
+	err = m.cfg.Messenger.SendMessage(msg.ReplyPath, nil, records)
+	if err != nil {
+		log.Errorf("Unable to reply to invoice request: %v", err)
+	}
+}
+
+// requestAmount returns the amount that the invoice for the request must be
+// created for.
+func requestAmount(offer *Offer, req *InvoiceRequest) (lnwire.MilliBronees,
+	error) {
+
+	quantity := uint64(1)
+	switch {
+	case offer.QuantityMax == nil && req.Quantity != nil:
+		field := uint64(invReqQuantityType)
+		return 0, &InvoiceError{
+			ErroneousField: &field,
+			Message:        "offer doesn't allow a quantity",
+		}
+
+	case offer.QuantityMax != nil && req.Quantity == nil:
+		field := uint64(invReqQuantityType)
+		return 0, &InvoiceError{
+			ErroneousField: &field,
+			Message:        "missing quantity",
+		}
+
+	case offer.QuantityMax != nil:
+		quantity = *req.Quantity
+		if quantity == 0 || (*offer.QuantityMax != 0 &&
+			quantity > *offer.QuantityMax) {
+
+			field := uint64(invReqQuantityType)
+			return 0, &InvoiceError{
+				ErroneousField: &field,
+				Message:        "invalid quantity",
+			}
+		}
+	}
+
+	if offer.Amount == 0 {
+		if req.Amount == 0 {
+			field := uint64(invReqAmountType)
+			return 0, &InvoiceError{
+				ErroneousField: &field,
+				Message:        "missing amount",
+			}
+		}
+
+		return req.Amount, nil
+	}
+
+	// Without an upper bound on the quantity, the total amount may not
+	// fit into an amount.
+	if quantity > math.MaxUint64/uint64(offer.Amount) {
+		field := uint64(invReqQuantityType)
+		return 0, &InvoiceError{
+			ErroneousField: &field,
+			Message:        "quantity too large",
+		}
+	}
+
+	// The payer may pay more than the offer asks for, but never less.
+	expected := offer.Amount * lnwire.MilliBronees(quantity)
+	if req.Amount != 0 && req.Amount < expected {
+		field := uint64(invReqAmountType)
+		return 0, &InvoiceError{
+			ErroneousField: &field,
+			Message:        "amount below offer amount",
+		}
+	}
+	if req.Amount > expected {
+		return req.Amount, nil
+	}
+
+	return expected, nil
+}
+
+// createInvoice creates a signed invoice for the serialized invoice request.
+func (m *Manager) createInvoice(b []byte) (*Invoice, error) {
+	req, err := DeserializeInvoiceRequest(b)
+	if err != nil {
+		return nil, err
+	}
+
+	offerID, err := req.Offer.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := m.cfg.Store.FetchOffer(offerID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := m.cfg.Now()
+	switch {
+	case stored.Disabled:
+		return nil, ErrOfferDisabled
+
+	case stored.Offer.Expired(now):
+		return nil, ErrOfferExpired
+
+	case req.ChainHash(*chaincfg.MainNetParams.GenesisHash) !=
+		m.cfg.ChainHash:
+
+		return nil, ErrUnsupportedChain
+	}
+
+	amt, err := requestAmount(stored.Offer, req)
+	if err != nil {
+		return nil, err
+	}
+
+	paymentHash, paths, err := m.cfg.AddInvoice(
+		amt, stored.Offer.Description, DefaultInvoiceExpiry,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice := &Invoice{
+		Request:     req,
+		Paths:       paths,
+		CreatedAt:   now,
+		PaymentHash: paymentHash,
+		Amount:      amt,
+		NodeID:      m.cfg.NodeID,
+	}
+	if err := invoice.Sign(m.cfg.SignDigest); err != nil {
+		return nil, err
+	}
+
+	log.Infof("Created invoice for offer %x with payment hash %v",
+		offerID[:], paymentHash)
+
+	return invoice, nil
+}
+
+// CreateOffer creates a new offer issued by our node and stores it, so that
+// we answer invoice requests for it.
+func (m *Manager) CreateOffer(offer *Offer) (*StoredOffer, error) {
+	offer.IssuerID = m.cfg.NodeID
+	if m.cfg.ChainHash != *chaincfg.MainNetParams.GenesisHash {
+		offer.Chains = []chainhash.Hash{m.cfg.ChainHash}
+	}
+
+	// Random metadata makes sure that offers with the same parameters
+	// still have distinct ids.
+	offer.Metadata = make([]byte, metadataSize)
+	if _, err := rand.Read(offer.Metadata); err != nil {
+		return nil, err
+	}
+
+	if err := offer.Validate(); err != nil {
+		return nil, err
+	}
+
+	id, err := offer.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	stored := &StoredOffer{
+		Offer:     offer,
+		ID:        id,
+		CreatedAt: m.cfg.Now(),
+	}
+	if err := m.cfg.Store.AddOffer(stored); err != nil {
+		return nil, err
+	}
+
+	log.Infof("Created offer %x", id[:])
+
+	return stored, nil
+}
+
+// ListOffers returns all offers we created.
+func (m *Manager) ListOffers() ([]*StoredOffer, error) {
+	return m.cfg.Store.ListOffers()
+}
+
+// DisableOffer stops answering invoice requests for the offer with the given
+// id.
+func (m *Manager) DisableOffer(id [32]byte) error {
+	return m.cfg.Store.DisableOffer(id)
+}
+
+// FetchInvoice requests an invoice for the offer from its issuer. The amount
+// must be set if the offer doesn't specify one, while the quantity must be
+// set if the offer allows requesting several items.
+func (m *Manager) FetchInvoice(offer *Offer, amt lnwire.MilliBronees,
+	quantity *uint64, payerNote string) (*Invoice, error) {
+
+	if err := offer.Validate(); err != nil {
+		return nil, err
+	}
+	if offer.Expired(m.cfg.Now()) {
+		return nil, ErrOfferExpired
+	}
+	if !offer.SupportsChain(m.cfg.ChainHash) {
+		return nil, ErrUnsupportedChain
+	}
+	if offer.Amount == 0 && amt == 0 {
+		return nil, errors.New("amount must be specified for offers " +
+			"without amount")
+	}
+
+	// We sign the request with a transient key, so that the issuer can't
+	// link the payment to our node.
+	payerKey, err := bronec.NewPrivateKey(bronec.S256())
+	if err != nil {
+		return nil, err
+	}
+
+	req := &InvoiceRequest{
+		Offer:     offer,
+		Metadata:  make([]byte, metadataSize),
+		Amount:    amt,
+		Quantity:  quantity,
+		PayerID:   payerKey.PubKey(),
+		PayerNote: payerNote,
+	}
+	if m.cfg.ChainHash != *chaincfg.MainNetParams.GenesisHash {
+		chain := m.cfg.ChainHash
+		req.Chain = &chain
+	}
+	if _, err := rand.Read(req.Metadata); err != nil {
+		return nil, err
+	}
+
+	// The issuer must reject the request if the amount is too low, so we
+	// check it ourselves first to fail early.
+	expectedAmt, err := requestAmount(offer, req)
+	if err != nil {
+		return nil, err
+	}
+
+	err = req.Sign(func(digest [32]byte) (*schnorr.Signature, error) {
+		return schnorr.Sign(payerKey, digest[:])
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reqBytes, err := req.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	// The request is sent through the first blinded path of the offer or
+	// directly to the issuer if the offer has none.
+	path, err := m.requestPath(offer)
+	if err != nil {
+		return nil, err
+	}
+
+	pathID := make([]byte, pathIDSize)
+	if _, err := rand.Read(pathID); err != nil {
+		return nil, err
+	}
+
+	// If the request is relayed by the introduction node of a path, the
+	// issuer may not be connected to us, so the reply is relayed by the
+	// introduction node as well.
+	replyNodes := []*bronec.PublicKey{m.cfg.NodeID}
+	if len(path.BlindedHops) > 1 {
+		replyNodes = []*bronec.PublicKey{
+			path.IntroductionPoint, m.cfg.NodeID,
+		}
+	}
+	replyPath, err := onionmsg.BuildPath(replyNodes, pathID)
+	if err != nil {
+		return nil, err
+	}
+
+	replyChan := make(chan *onionmsg.ReceivedMessage, 1)
+	m.pendingMu.Lock()
+	m.pending[string(pathID)] = replyChan
+	m.pendingMu.Unlock()
+
+	defer func() {
+		m.pendingMu.Lock()
+		delete(m.pending, string(pathID))
+		m.pendingMu.Unlock()
+	}()
+
+	err = m.cfg.Messenger.SendMessage(path, replyPath, map[uint64][]byte{
+		InvoiceRequestRecordType: reqBytes,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var reply *onionmsg.ReceivedMessage
+	select {
+	case reply = <-replyChan:
+
+	case <-time.After(m.cfg.RequestTimeout):
+		return nil, ErrRequestTimeout
+
+	case <-m.quit:
+		return nil, ErrManagerShuttingDown
+	}
+
+	if b, ok := reply.CustomRecords[InvoiceErrorRecordType]; ok {
+		invErr, err := DeserializeInvoiceError(b)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, invErr
+	}
+
+	b, ok := reply.CustomRecords[InvoiceRecordType]
+	if !ok {
+		return nil, errors.New("reply without invoice")
+	}
+
+	invoice, err := DeserializeInvoice(b)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateInvoice(invoice, req, path, expectedAmt); err != nil {
+		return nil, err
+	}
+
+	return invoice, nil
+}
+
+// requestPath returns the blinded path that invoice requests for the offer are
+// sent through.
+func (m *Manager) requestPath(offer *Offer) (*blinding.BlindedPath, error) {
+	if len(offer.Paths) > 0 {
+		return offer.Paths[0], nil
+	}
+
+	return onionmsg.BuildPath([]*bronec.PublicKey{offer.IssuerID}, nil)
+}
+
+// validateInvoice checks that the invoice answers our request.
+func validateInvoice(invoice *Invoice, req *InvoiceRequest,
+	path *blinding.BlindedPath, expectedAmt lnwire.MilliBronees) error {
+
+	invReq, err := invoice.Request.Serialize()
+	if err != nil {
+		return err
+	}
+
+	// Our request without its signature must be contained in the invoice
+	// unchanged.
+	unsigned := *req
+	unsigned.Signature = nil
+	ourReq, err := unsigned.Serialize()
+	if err != nil {
+		return err
+	}
+
+	if string(invReq) != string(ourReq) {
+		return errors.New("invoice doesn't match invoice request")
+	}
+
+	// The invoice must be signed by the issuer or, if the offer doesn't
+	// contain its id, by the blinded node id of the final hop of the path
+	// we sent the request through.
+	nodeID := req.Offer.IssuerID
+	if nodeID == nil {
+		hops := path.BlindedHops
+		nodeID = hops[len(hops)-1].BlindedNodePub
+	}
+	if !invoice.NodeID.IsEqual(nodeID) {
+		return fmt.Errorf("invoice signed by %x instead of issuer %x",
+			invoice.NodeID.SerializeCompressed(),
+			nodeID.SerializeCompressed())
+	}
+
+	// The invoice must be for exactly the amount we requested, or the
+	// amount of the offer if we didn't request one, as we pay whatever
+	// amount the invoice asks for.
+	if invoice.Amount != expectedAmt {
+		return fmt.Errorf("invoice amount %v doesn't match requested "+
+			"amount %v", invoice.Amount, expectedAmt)
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package offers
+
+import (
+	"math"
+	"testing"
+
+	"github.com/brronsuite/broln/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRequestAmount tests the amount that the invoice for a request must be
+// created for.
+func TestRequestAmount(t *testing.T) {
+	t.Parallel()
+
+	offer, _ := newTestOffer(t)
+
+	unlimited := uint64(0)
+	unlimitedOffer := *offer
+	unlimitedOffer.QuantityMax = &unlimited
+
+	quantity := func(q uint64) *uint64 {
+		return &q
+	}
+
+	tests := []struct {
+		name     string
+		offer    *Offer
+		req      *InvoiceRequest
+		expected lnwire.MilliBronees
+		err      bool
+	}{
+		{
+			name:     "offer amount times quantity",
+			offer:    offer,
+			req:      &InvoiceRequest{Quantity: quantity(2)},
+			expected: 42_000,
+		},
+		{
+			name:  "requested amount above offer amount",
+			offer: offer,
+			req: &InvoiceRequest{
+				Quantity: quantity(2),
+				Amount:   50_000,
+			},
+			expected: 50_000,
+		},
+		{
+			name:  "requested amount below offer amount",
+			offer: offer,
+			req: &InvoiceRequest{
+				Quantity: quantity(2),
+				Amount:   41_999,
+			},
+			err: true,
+		},
+		{
+			name:  "quantity above maximum",
+			offer: offer,
+			req:   &InvoiceRequest{Quantity: quantity(6)},
+			err:   true,
+		},
+		{
+			name:     "unlimited quantity",
+			offer:    &unlimitedOffer,
+			req:      &InvoiceRequest{Quantity: quantity(1_000)},
+			expected: 21_000_000,
+		},
+		{
+			name:  "unlimited quantity overflows amount",
+			offer: &unlimitedOffer,
+			req: &InvoiceRequest{
+				Quantity: quantity(math.MaxUint64/21_000 + 1),
+			},
+			err: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			amt, err := requestAmount(test.offer, test.req)
+			if test.err {
+				var invoiceErr *InvoiceError
+				require.ErrorAs(t, err, &invoiceErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expected, amt)
+		})
+	}
+}
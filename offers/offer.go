@@ -0,0 +1,354 @@
+package offers
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/brronsuite/broln/blinding"
+	"github.com/brronsuite/broln/lnwire"
+	"github.com/brronsuite/broln/tlv"
+	"github.com/brronsuite/brond/bronec"
+	"github.com/brronsuite/brond/chaincfg"
+	"github.com/brronsuite/brond/chaincfg/chainhash"
+)
+
+const (
+	// offerHRP is the human-readable part of encoded offers.
+	offerHRP = "lno"
+
+	offerChainsType         tlv.Type = 2
+	offerMetadataType       tlv.Type = 4
+	offerCurrencyType       tlv.Type = 6
+	offerAmountType         tlv.Type = 8
+	offerDescriptionType    tlv.Type = 10
+	offerFeaturesType       tlv.Type = 12
+	offerAbsoluteExpiryType tlv.Type = 14
+	offerPathsType          tlv.Type = 16
+	offerIssuerType         tlv.Type = 18
+	offerQuantityMaxType    tlv.Type = 20
+	offerIssuerIDType       tlv.Type = 22
+
+	// maxOfferType is the exclusive upper bound of the types that belong
+	// to the offer.
+	maxOfferType = 80
+)
+
+var (
+	// ErrUnsupportedCurrency is returned when an offer denominates its
+	// amount in a currency other than brocoin.
+	ErrUnsupportedCurrency = errors.New("offers with currencies other " +
+		"than brocoin are not supported")
+
+	// ErrNoIssuerID is returned when an offer neither contains the
+	// issuer's node id nor blinded paths to reach it.
+	ErrNoIssuerID = errors.New("offer without issuer id or paths")
+
+	// offerKnownTypes are the offer types we understand.
+	offerKnownTypes = map[uint64]struct{}{
+		uint64(offerChainsType):         {},
+		uint64(offerMetadataType):       {},
+		uint64(offerCurrencyType):       {},
+		uint64(offerAmountType):         {},
+		uint64(offerDescriptionType):    {},
+		uint64(offerFeaturesType):       {},
+		uint64(offerAbsoluteExpiryType): {},
+		uint64(offerPathsType):          {},
+		uint64(offerIssuerType):         {},
+		uint64(offerQuantityMaxType):    {},
+		uint64(offerIssuerIDType):       {},
+	}
+)
+
+// Offer is a BOLT 12 offer. An offer is a static, reusable payment code that
+// the payer uses to request an invoice from the issuer over onion messages.
+type Offer struct {
+	// Chains are the genesis hashes of the chains the offer can be paid
+	// on. If empty, the offer can only be paid on brocoin mainnet.
+	Chains []chainhash.Hash
+
+	// Metadata is opaque data for the issuer.
+	Metadata []byte
+
+	// Amount is the amount that is expected per item. If zero, the payer
+	// chooses the amount.
+	Amount lnwire.MilliBronees
+
+	// Description describes what the offer is for.
+	Description string
+
+	// Features are the features of the offer.
+	Features *lnwire.RawFeatureVector
+
+	// AbsoluteExpiry is the time after which the offer can't be paid
+	// anymore. If zero, the offer doesn't expire.
+	AbsoluteExpiry time.Time
+
+	// Paths are blinded paths to the issuer that invoice requests can be
+	// sent through.
+	Paths []*blinding.BlindedPath
+
+	// Issuer is a human-readable name of the issuer.
+	Issuer string
+
+	// QuantityMax is the maximum number of items that can be requested.
+	// If nil, only a single item can be requested, while zero means that
+	// there is no limit.
+	QuantityMax *uint64
+
+	// IssuerID is the node id of the issuer that invoices are signed
+	// with.
+	IssuerID *bronec.PublicKey
+}
+
+// records returns the TLV records of the offer.
+func (o *Offer) records() ([]tlv.Record, error) {
+	var records []tlv.Record
+
+	if len(o.Chains) > 0 {
+		chains := make([]byte, 0, len(o.Chains)*chainhash.HashSize)
+		for _, chain := range o.Chains {
+			chains = append(chains, chain[:]...)
+		}
+		records = append(records, tlv.MakePrimitiveRecord(
+			offerChainsType, &chains,
+		))
+	}
+
+	if o.Metadata != nil {
+		records = append(records, tlv.MakePrimitiveRecord(
+			offerMetadataType, &o.Metadata,
+		))
+	}
+
+	if o.Amount != 0 {
+		amt := uint64(o.Amount)
+		records = append(records, tu64Record(offerAmountType, &amt))
+	}
+
+	if o.Description != "" {
+		description := []byte(o.Description)
+		records = append(records, tlv.MakePrimitiveRecord(
+			offerDescriptionType, &description,
+		))
+	}
+
+	if o.Features != nil && o.Features.SerializeSize() > 0 {
+		features, err := encodeFeatures(o.Features)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, tlv.MakePrimitiveRecord(
+			offerFeaturesType, &features,
+		))
+	}
+
+	if !o.AbsoluteExpiry.IsZero() {
+		expiry := uint64(o.AbsoluteExpiry.Unix())
+		records = append(records, tu64Record(
+			offerAbsoluteExpiryType, &expiry,
+		))
+	}
+
+	if len(o.Paths) > 0 {
+		paths, err := encodeBlindedPaths(o.Paths)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, tlv.MakePrimitiveRecord(
+			offerPathsType, &paths,
+		))
+	}
+
+	if o.Issuer != "" {
+		issuer := []byte(o.Issuer)
+		records = append(records, tlv.MakePrimitiveRecord(
+			offerIssuerType, &issuer,
+		))
+	}
+
+	if o.QuantityMax != nil {
+		records = append(records, tu64Record(
+			offerQuantityMaxType, o.QuantityMax,
+		))
+	}
+
+	if o.IssuerID != nil {
+		records = append(records, tlv.MakePrimitiveRecord(
+			offerIssuerIDType, &o.IssuerID,
+		))
+	}
+
+	return records, nil
+}
+
+// decodeRecords populates the offer from the offer records among the
+// passed records.
+func (o *Offer) decodeRecords(records []rawRecord) error {
+	var err error
+	for _, r := range records {
+		switch tlv.Type(r.typ) {
+		case offerChainsType:
+			if len(r.value)%chainhash.HashSize != 0 {
+				return fmt.Errorf("invalid offer_chains "+
+					"length %v", len(r.value))
+			}
+			for i := 0; i < len(r.value); i += chainhash.HashSize {
+				var chain chainhash.Hash
+				copy(chain[:], r.value[i:])
+				o.Chains = append(o.Chains, chain)
+			}
+
+		case offerMetadataType:
+			o.Metadata = r.value
+
+		case offerCurrencyType:
+			return ErrUnsupportedCurrency
+
+		case offerAmountType:
+			var amt uint64
+			amt, err = decodeTU64(r.value)
+			o.Amount = lnwire.MilliBronees(amt)
+
+		case offerDescriptionType:
+			o.Description = string(r.value)
+
+		case offerFeaturesType:
+			o.Features, err = decodeFeatures(r.value)
+
+		case offerAbsoluteExpiryType:
+			var expiry uint64
+			expiry, err = decodeTU64(r.value)
+			o.AbsoluteExpiry = time.Unix(int64(expiry), 0)
+
+		case offerPathsType:
+			o.Paths, err = decodeBlindedPaths(r.value)
+
+		case offerIssuerType:
+			o.Issuer = string(r.value)
+
+		case offerQuantityMaxType:
+			var quantityMax uint64
+			quantityMax, err = decodeTU64(r.value)
+			o.QuantityMax = &quantityMax
+
+		case offerIssuerIDType:
+			o.IssuerID, err = decodePubKey(r.value)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid offer record %v: %w", r.typ,
+				err)
+		}
+	}
+
+	return nil
+}
+
+// Validate checks that the offer can be paid.
+func (o *Offer) Validate() error {
+	if o.IssuerID == nil && len(o.Paths) == 0 {
+		return ErrNoIssuerID
+	}
+
+	if o.Amount != 0 && o.Description == "" {
+		return errors.New("offer with amount but without description")
+	}
+
+	return nil
+}
+
+// SupportsChain returns true if the offer can be paid on the chain with the
+// given genesis hash.
+func (o *Offer) SupportsChain(chain chainhash.Hash) bool {
+	if len(o.Chains) == 0 {
+		return chain == *chaincfg.MainNetParams.GenesisHash
+	}
+
+	for _, c := range o.Chains {
+		if c == chain {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Expired returns true if the offer can't be paid anymore at the given time.
+func (o *Offer) Expired(now time.Time) bool {
+	return !o.AbsoluteExpiry.IsZero() && now.After(o.AbsoluteExpiry)
+}
+
+// Serialize returns the TLV encoding of the offer.
+func (o *Offer) Serialize() ([]byte, error) {
+	records, err := o.records()
+	if err != nil {
+		return nil, err
+	}
+
+	return encodeRecords(records)
+}
+
+// ID returns the offer id, which is the merkle root of the offer's records.
+// Invoice requests and invoices for the offer commit to the same records.
+func (o *Offer) ID() ([32]byte, error) {
+	b, err := o.Serialize()
+	if err != nil {
+		return [32]byte{}, err
+	}
+
+	records, err := parseRecords(b)
+	if err != nil {
+		return [32]byte{}, err
+	}
+
+	return merkleRoot(records), nil
+}
+
+// Encode returns the offer as an lno string.
+func (o *Offer) Encode() (string, error) {
+	b, err := o.Serialize()
+	if err != nil {
+		return "", err
+	}
+
+	return encodeString(offerHRP, b)
+}
+
+// DeserializeOffer parses the TLV encoding of an offer.
+func DeserializeOffer(b []byte) (*Offer, error) {
+	records, err := parseRecords(b)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkTypes(records, offerKnownTypes, func(typ uint64) bool {
+		return typ > 0 && typ < maxOfferType
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var o Offer
+	if err := o.decodeRecords(records); err != nil {
+		return nil, err
+	}
+
+	if err := o.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &o, nil
+}
+
+// DecodeOffer parses an lno string.
+func DecodeOffer(s string) (*Offer, error) {
+	hrp, b, err := decodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if hrp != offerHRP {
+		return nil, fmt.Errorf("invalid offer prefix %v", hrp)
+	}
+
+	return DeserializeOffer(b)
+}
@@ -0,0 +1,176 @@
+package offers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/brronsuite/broln/lnwire"
+	"github.com/brronsuite/brond/bronec"
+	"github.com/brronsuite/brond/bronec/schnorr"
+	"github.com/brronsuite/brond/chaincfg"
+	"github.com/brronsuite/brond/chaincfg/chainhash"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestOffer creates an offer issued by a new random key.
+func newTestOffer(t *testing.T) (*Offer, *bronec.PrivateKey) {
+	issuerKey, err := bronec.NewPrivateKey(bronec.S256())
+	require.NoError(t, err)
+
+	quantityMax := uint64(5)
+	regtest := *chaincfg.RegressionNetParams.GenesisHash
+	offer := &Offer{
+		Chains:         []chainhash.Hash{regtest},
+		Metadata:       []byte{1, 2, 3},
+		Amount:         lnwire.MilliBronees(21_000),
+		Description:    "coffee",
+		AbsoluteExpiry: time.Unix(1_900_000_000, 0),
+		Issuer:         "brocafe",
+		QuantityMax:    &quantityMax,
+		IssuerID:       issuerKey.PubKey(),
+	}
+
+	return offer, issuerKey
+}
+
+// signer returns a signer that signs digests with the given key.
+func signer(key *bronec.PrivateKey) func([32]byte) (*schnorr.Signature,
+	error) {
+
+	return func(digest [32]byte) (*schnorr.Signature, error) {
+		return schnorr.Sign(key, digest[:])
+	}
+}
+
+// TestOfferEncoding tests that offers survive an encoding round trip and
+// that their id doesn't change in the process.
+func TestOfferEncoding(t *testing.T) {
+	t.Parallel()
+
+	offer, _ := newTestOffer(t)
+
+	encoded, err := offer.Encode()
+	require.NoError(t, err)
+	require.Equal(t, offerHRP, encoded[:len(offerHRP)])
+
+	decoded, err := DecodeOffer(encoded)
+	require.NoError(t, err)
+	require.Equal(t, offer.Description, decoded.Description)
+	require.Equal(t, offer.Amount, decoded.Amount)
+	require.Equal(t, offer.Issuer, decoded.Issuer)
+	require.Equal(t, offer.Chains, decoded.Chains)
+	require.Equal(t, *offer.QuantityMax, *decoded.QuantityMax)
+	require.True(t, offer.AbsoluteExpiry.Equal(decoded.AbsoluteExpiry))
+	require.True(t, offer.IssuerID.IsEqual(decoded.IssuerID))
+
+	reencoded, err := decoded.Encode()
+	require.NoError(t, err)
+	require.Equal(t, encoded, reencoded)
+
+	id, err := offer.ID()
+	require.NoError(t, err)
+	decodedID, err := decoded.ID()
+	require.NoError(t, err)
+	require.Equal(t, id, decodedID)
+
+	// Changing any record of the offer changes its id.
+	decoded.Description = "tea"
+	changedID, err := decoded.ID()
+	require.NoError(t, err)
+	require.NotEqual(t, id, changedID)
+
+	require.True(t, offer.SupportsChain(
+		*chaincfg.RegressionNetParams.GenesisHash,
+	))
+	require.False(t, offer.SupportsChain(
+		*chaincfg.MainNetParams.GenesisHash,
+	))
+	require.True(t, offer.Expired(time.Unix(2_000_000_000, 0)))
+	require.False(t, offer.Expired(time.Unix(1_800_000_000, 0)))
+}
+
+// TestOfferDecodingErrors tests that malformed and unpayable offers are
+// rejected.
+func TestOfferDecodingErrors(t *testing.T) {
+	t.Parallel()
+
+	offer, _ := newTestOffer(t)
+	encoded, err := offer.Encode()
+	require.NoError(t, err)
+
+	// Strings may be split with a '+' followed by whitespace.
+	split := encoded[:20] + "+\n  " + encoded[20:]
+	_, err = DecodeOffer(split)
+	require.NoError(t, err)
+
+	_, err = DecodeOffer(encoded + "+")
+	require.Error(t, err)
+
+	_, err = DecodeOffer("lnr" + encoded[len(offerHRP):])
+	require.Error(t, err)
+
+	// Offers without a way to reach the issuer can't be paid.
+	offer.IssuerID = nil
+	b, err := offer.Serialize()
+	require.NoError(t, err)
+	_, err = DeserializeOffer(b)
+	require.ErrorIs(t, err, ErrNoIssuerID)
+}
+
+// TestInvoiceRequestSignature tests that invoice requests are signed by the
+// payer and that the signature is verified when decoding them.
+func TestInvoiceRequestSignature(t *testing.T) {
+	t.Parallel()
+
+	offer, _ := newTestOffer(t)
+
+	payerKey, err := bronec.NewPrivateKey(bronec.S256())
+	require.NoError(t, err)
+
+	quantity := uint64(2)
+	req := &InvoiceRequest{
+		Offer:     offer,
+		Metadata:  []byte{4, 5, 6},
+		Chain:     chaincfg.RegressionNetParams.GenesisHash,
+		Amount:    42_000,
+		Quantity:  &quantity,
+		PayerID:   payerKey.PubKey(),
+		PayerNote: "thanks",
+	}
+	require.NoError(t, req.Sign(signer(payerKey)))
+
+	encoded, err := req.Encode()
+	require.NoError(t, err)
+
+	decoded, err := DecodeInvoiceRequest(encoded)
+	require.NoError(t, err)
+	require.Equal(t, req.Amount, decoded.Amount)
+	require.Equal(t, req.PayerNote, decoded.PayerNote)
+	require.Equal(t, quantity, *decoded.Quantity)
+	require.Equal(t, offer.Description, decoded.Offer.Description)
+
+	// The request commits to the same offer records as the offer.
+	offerID, err := offer.ID()
+	require.NoError(t, err)
+	decodedID, err := decoded.Offer.ID()
+	require.NoError(t, err)
+	require.Equal(t, offerID, decodedID)
+
+	// A request that is signed by another key is rejected.
+	otherKey, err := bronec.NewPrivateKey(bronec.S256())
+	require.NoError(t, err)
+	require.NoError(t, req.Sign(signer(otherKey)))
+
+	b, err := req.Serialize()
+	require.NoError(t, err)
+	_, err = DeserializeInvoiceRequest(b)
+	require.ErrorIs(t, err, ErrInvalidSignature)
+
+	// A request without a payer id is rejected.
+	req.PayerID = nil
+	req.Signature = nil
+	b, err = req.Serialize()
+	require.NoError(t, err)
+	_, err = DeserializeInvoiceRequest(b)
+	require.ErrorIs(t, err, ErrMissingPayerID)
+}
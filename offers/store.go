@@ -0,0 +1,211 @@
+package offers
+
+import (
+	"bytes"
+	"errors"
+	"time"
+
+	"github.com/brronsuite/broln/kvdb"
+	"github.com/brronsuite/broln/tlv"
+)
+
+var (
+	// offerBucket stores the offers we created, keyed by their offer id.
+	offerBucket = []byte("offer-bucket")
+
+	// ErrOfferNotFound is returned when an offer isn't known.
+	ErrOfferNotFound = errors.New("offer not found")
+)
+
+const (
+	storedOfferType     tlv.Type = 0
+	storedCreatedAtType tlv.Type = 1
+	storedDisabledType  tlv.Type = 2
+)
+
+// StoredOffer is an offer that we created, together with its state.
+type StoredOffer struct {
+	// Offer is the offer itself.
+	Offer *Offer
+
+	// ID is the offer id.
+	ID [32]byte
+
+	// CreatedAt is the time the offer was created at.
+	CreatedAt time.Time
+
+	// Disabled is true if we don't answer invoice requests for the offer
+	// anymore.
+	Disabled bool
+}
+
+// Store persists the offers we created.
+type Store struct {
+	backend kvdb.Backend
+}
+
+// NewStore creates a new offer store from the passed database backend.
+func NewStore(db kvdb.Backend) (*Store, error) {
+	err := kvdb.Update(db, func(tx kvdb.RwTx) error {
+		_, err := tx.CreateTopLevelBucket(offerBucket)
+		return err
+	}, func() {})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Store{backend: db}, nil
+}
+
+// serializeStoredOffer serializes the offer and its state.
+func serializeStoredOffer(o *StoredOffer) ([]byte, error) {
+	offer, err := o.Offer.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	createdAt := uint64(o.CreatedAt.UnixNano())
+	var disabled uint8
+	if o.Disabled {
+		disabled = 1
+	}
+
+	stream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(storedOfferType, &offer),
+		tlv.MakePrimitiveRecord(storedCreatedAtType, &createdAt),
+		tlv.MakePrimitiveRecord(storedDisabledType, &disabled),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if err := stream.Encode(&b); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// deserializeStoredOffer parses an offer and its state.
+func deserializeStoredOffer(id, v []byte) (*StoredOffer, error) {
+	var (
+		offer     []byte
+		createdAt uint64
+		disabled  uint8
+	)
+	stream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(storedOfferType, &offer),
+		tlv.MakePrimitiveRecord(storedCreatedAtType, &createdAt),
+		tlv.MakePrimitiveRecord(storedDisabledType, &disabled),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := stream.Decode(bytes.NewReader(v)); err != nil {
+		return nil, err
+	}
+
+	o, err := DeserializeOffer(offer)
+	if err != nil {
+		return nil, err
+	}
+
+	stored := &StoredOffer{
+		Offer:     o,
+		CreatedAt: time.Unix(0, int64(createdAt)),
+		Disabled:  disabled == 1,
+	}
+	copy(stored.ID[:], id)
+
+	return stored, nil
+}
+
+// AddOffer stores a newly created offer.
+func (s *Store) AddOffer(o *StoredOffer) error {
+	v, err := serializeStoredOffer(o)
+	if err != nil {
+		return err
+	}
+
+	return kvdb.Update(s.backend, func(tx kvdb.RwTx) error {
+		bucket := tx.ReadWriteBucket(offerBucket)
+		return bucket.Put(o.ID[:], v)
+	}, func() {})
+}
+
+// FetchOffer returns the offer with the given id.
+func (s *Store) FetchOffer(id [32]byte) (*StoredOffer, error) {
+	var offer *StoredOffer
+	err := kvdb.View(s.backend, func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(offerBucket)
+
+		v := bucket.Get(id[:])
+		if v == nil {
+			return ErrOfferNotFound
+		}
+
+		var err error
+		offer, err = deserializeStoredOffer(id[:], v)
+		return err
+	}, func() {
+		offer = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return offer, nil
+}
+
+// ListOffers returns all offers we created.
+func (s *Store) ListOffers() ([]*StoredOffer, error) {
+	var offers []*StoredOffer
+	err := kvdb.View(s.backend, func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(offerBucket)
+
+		return bucket.ForEach(func(k, v []byte) error {
+			offer, err := deserializeStoredOffer(k, v)
+			if err != nil {
+				return err
+			}
+
+			offers = append(offers, offer)
+			return nil
+		})
+	}, func() {
+		offers = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return offers, nil
+}
+
+// DisableOffer marks the offer with the given id as disabled, so that we
+// don't answer invoice requests for it anymore.
+func (s *Store) DisableOffer(id [32]byte) error {
+	return kvdb.Update(s.backend, func(tx kvdb.RwTx) error {
+		bucket := tx.ReadWriteBucket(offerBucket)
+
+		v := bucket.Get(id[:])
+		if v == nil {
+			return ErrOfferNotFound
+		}
+
+		offer, err := deserializeStoredOffer(id[:], v)
+		if err != nil {
+			return err
+		}
+		offer.Disabled = true
+
+		v, err = serializeStoredOffer(offer)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(id[:], v)
+	}, func() {})
+}
@@ -0,0 +1,79 @@
+package offers
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/brronsuite/broln/kvdb"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStore tests that offers can be added, listed and disabled and that
+// they survive a restart.
+func TestStore(t *testing.T) {
+	t.Parallel()
+
+	db, cleanup := makeTestDB(t)
+	defer cleanup()
+
+	store, err := NewStore(db)
+	require.NoError(t, err)
+
+	offer, _ := newTestOffer(t)
+	id, err := offer.ID()
+	require.NoError(t, err)
+
+	_, err = store.FetchOffer(id)
+	require.ErrorIs(t, err, ErrOfferNotFound)
+	require.ErrorIs(t, store.DisableOffer(id), ErrOfferNotFound)
+
+	stored := &StoredOffer{
+		Offer:     offer,
+		ID:        id,
+		CreatedAt: time.Unix(0, 1_800_000_000_000_000_000),
+	}
+	require.NoError(t, store.AddOffer(stored))
+
+	fetched, err := store.FetchOffer(id)
+	require.NoError(t, err)
+	require.Equal(t, id, fetched.ID)
+	require.True(t, stored.CreatedAt.Equal(fetched.CreatedAt))
+	require.False(t, fetched.Disabled)
+
+	fetchedID, err := fetched.Offer.ID()
+	require.NoError(t, err)
+	require.Equal(t, id, fetchedID)
+
+	require.NoError(t, store.DisableOffer(id))
+
+	// The state of the offer is kept when the store is recreated.
+	store, err = NewStore(db)
+	require.NoError(t, err)
+
+	offers, err := store.ListOffers()
+	require.NoError(t, err)
+	require.Len(t, offers, 1)
+	require.Equal(t, id, offers[0].ID)
+	require.True(t, offers[0].Disabled)
+}
+
+// makeTestDB creates a new bolt backed database in a temporary file.
+func makeTestDB(t *testing.T) (kvdb.Backend, func()) {
+	file, err := ioutil.TempFile("", "*.db")
+	require.NoError(t, err)
+
+	dbPath := file.Name()
+	require.NoError(t, file.Close())
+
+	db, err := kvdb.Create(
+		kvdb.BoltBackendName, dbPath, true, kvdb.DefaultDBTimeout,
+	)
+	require.NoError(t, err)
+
+	return db, func() {
+		db.Close()
+		os.Remove(dbPath)
+	}
+}
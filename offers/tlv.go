@@ -0,0 +1,318 @@
+package offers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/brronsuite/broln/blinding"
+	"github.com/brronsuite/broln/lnwire"
+	"github.com/brronsuite/broln/tlv"
+	"github.com/brronsuite/brond/bronec"
+	"github.com/brronsuite/brond/bronec/schnorr"
+)
+
+const (
+	// signatureType is the TLV type of the signature of invoice requests
+	// and invoices.
+	signatureType tlv.Type = 240
+
+	// minSignatureType and maxSignatureType are the bounds of the range
+	// of signature types, which are excluded from the merkle tree.
+	minSignatureType = 240
+	maxSignatureType = 1000
+)
+
+var (
+	// ErrInvalidSignature is returned when the signature of an invoice
+	// request or invoice doesn't match its contents.
+	ErrInvalidSignature = errors.New("invalid signature")
+)
+
+// rawRecord is a single serialized TLV record of a BOLT 12 message.
+type rawRecord struct {
+	// typ is the type of the record.
+	typ uint64
+
+	// value is the value of the record.
+	value []byte
+
+	// raw is the complete serialized record, including its type and
+	// length.
+	raw []byte
+}
+
+// parseRecords splits a serialized TLV stream into its records. The records
+// must be sorted by strictly increasing type.
+func parseRecords(b []byte) ([]rawRecord, error) {
+	var (
+		records []rawRecord
+		r       = bytes.NewReader(b)
+		buf     [8]byte
+	)
+	for r.Len() > 0 {
+		start := len(b) - r.Len()
+
+		typ, err := tlv.ReadVarInt(r, &buf)
+		if err != nil {
+			return nil, err
+		}
+		if len(records) > 0 && typ <= records[len(records)-1].typ {
+			return nil, fmt.Errorf("tlv types not strictly "+
+				"increasing: %v", typ)
+		}
+
+		length, err := tlv.ReadVarInt(r, &buf)
+		if err != nil {
+			return nil, err
+		}
+		if length > uint64(r.Len()) {
+			return nil, io.ErrUnexpectedEOF
+		}
+
+		valueStart := len(b) - r.Len()
+		end := valueStart + int(length)
+		if _, err := r.Seek(int64(length), io.SeekCurrent); err != nil {
+			return nil, err
+		}
+
+		records = append(records, rawRecord{
+			typ:   typ,
+			value: b[valueStart:end],
+			raw:   b[start:end],
+		})
+	}
+
+	return records, nil
+}
+
+// checkTypes asserts that all records are of one of the allowed types.
+// Unknown records that aren't allowed in the message at all are rejected,
+// while unknown odd types within the allowed ranges are ignored.
+func checkTypes(records []rawRecord, known map[uint64]struct{},
+	allowed func(uint64) bool) error {
+
+	for _, r := range records {
+		if !allowed(r.typ) {
+			return fmt.Errorf("unexpected tlv type %v", r.typ)
+		}
+
+		_, ok := known[r.typ]
+		if !ok && r.typ%2 == 0 {
+			return fmt.Errorf("unknown required tlv type %v",
+				r.typ)
+		}
+	}
+
+	return nil
+}
+
+// encodeRecords serializes the records as a TLV stream.
+func encodeRecords(records []tlv.Record) ([]byte, error) {
+	tlv.SortRecords(records)
+
+	stream, err := tlv.NewStream(records...)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if err := stream.Encode(&b); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// tu64Record creates a record for a truncated uint64.
+func tu64Record(typ tlv.Type, v *uint64) tlv.Record {
+	return tlv.MakeDynamicRecord(
+		typ, v, func() uint64 {
+			return tlv.SizeTUint64(*v)
+		}, tlv.ETUint64, tlv.DTUint64,
+	)
+}
+
+// tu32Record creates a record for a truncated uint32.
+func tu32Record(typ tlv.Type, v *uint32) tlv.Record {
+	return tlv.MakeDynamicRecord(
+		typ, v, func() uint64 {
+			return tlv.SizeTUint32(*v)
+		}, tlv.ETUint32, tlv.DTUint32,
+	)
+}
+
+// decodeTU64 parses the value of a truncated uint64 record.
+func decodeTU64(v []byte) (uint64, error) {
+	var (
+		val uint64
+		buf [8]byte
+	)
+	err := tlv.DTUint64(bytes.NewReader(v), &val, &buf, uint64(len(v)))
+
+	return val, err
+}
+
+// decodeTU32 parses the value of a truncated uint32 record.
+func decodeTU32(v []byte) (uint32, error) {
+	var (
+		val uint32
+		buf [8]byte
+	)
+	err := tlv.DTUint32(bytes.NewReader(v), &val, &buf, uint64(len(v)))
+
+	return val, err
+}
+
+// decodePubKey parses the value of a point record.
+func decodePubKey(v []byte) (*bronec.PublicKey, error) {
+	return bronec.ParsePubKey(v, bronec.S256())
+}
+
+// encodeFeatures serializes a feature vector without length prefix, as it
+// is used in BOLT 12 messages.
+func encodeFeatures(f *lnwire.RawFeatureVector) ([]byte, error) {
+	var b bytes.Buffer
+	if err := f.EncodeBase256(&b); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// decodeFeatures parses a feature vector without length prefix.
+func decodeFeatures(v []byte) (*lnwire.RawFeatureVector, error) {
+	f := lnwire.NewRawFeatureVector()
+	if err := f.DecodeBase256(bytes.NewReader(v), len(v)); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// encodeBlindedPaths serializes a list of blinded paths.
+func encodeBlindedPaths(paths []*blinding.BlindedPath) ([]byte, error) {
+	var b bytes.Buffer
+	for _, path := range paths {
+		if err := path.Encode(&b); err != nil {
+			return nil, err
+		}
+	}
+
+	return b.Bytes(), nil
+}
+
+// decodeBlindedPaths parses a list of blinded paths.
+func decodeBlindedPaths(v []byte) ([]*blinding.BlindedPath, error) {
+	var (
+		paths []*blinding.BlindedPath
+		r     = bytes.NewReader(v)
+	)
+	for r.Len() > 0 {
+		path, err := blinding.DecodeBlindedPath(r)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+
+	return paths, nil
+}
+
+// taggedHash computes the BIP 340 tagged hash of the message.
+func taggedHash(tag []byte, msg ...[]byte) [32]byte {
+	tagHash := sha256.Sum256(tag)
+
+	h := sha256.New()
+	h.Write(tagHash[:])
+	h.Write(tagHash[:])
+	for _, m := range msg {
+		h.Write(m)
+	}
+
+	var res [32]byte
+	copy(res[:], h.Sum(nil))
+
+	return res
+}
+
+// merkleRoot computes the root of the merkle tree of the records as defined
+// by BOLT 12. Signature records are not part of the tree.
+func merkleRoot(records []rawRecord) [32]byte {
+	var leaves [][32]byte
+	for _, r := range records {
+		if r.typ >= minSignatureType && r.typ <= maxSignatureType {
+			continue
+		}
+
+		// Every record is paired with a nonce leaf derived from the
+		// first record, so that the contents of a record can't be
+		// guessed from a proof that omits it.
+		var typ bytes.Buffer
+		var buf [8]byte
+		_ = tlv.WriteVarInt(&typ, r.typ, &buf)
+
+		leaf := taggedHash([]byte("LnLeaf"), r.raw)
+		nonceTag := append([]byte("LnNonce"), records[0].raw...)
+		nonce := taggedHash(nonceTag, typ.Bytes())
+
+		leaves = append(leaves, branchHash(leaf, nonce))
+	}
+
+	if len(leaves) == 0 {
+		return [32]byte{}
+	}
+
+	// Combine the leaves pairwise until only the root is left. An odd
+	// node is carried to the next level, so the deepest parts of the tree
+	// are the lowest-order leaves.
+	for len(leaves) > 1 {
+		next := make([][32]byte, 0, (len(leaves)+1)/2)
+		for i := 0; i < len(leaves); i += 2 {
+			if i+1 == len(leaves) {
+				next = append(next, leaves[i])
+				continue
+			}
+			next = append(next, branchHash(leaves[i], leaves[i+1]))
+		}
+		leaves = next
+	}
+
+	return leaves[0]
+}
+
+// branchHash computes the hash of an inner node of the merkle tree from its
+// two children, ordered by their value.
+func branchHash(a, b [32]byte) [32]byte {
+	if bytes.Compare(a[:], b[:]) > 0 {
+		a, b = b, a
+	}
+
+	return taggedHash([]byte("LnBranch"), a[:], b[:])
+}
+
+// signatureDigest computes the digest that is signed for the given message.
+func signatureDigest(messageName string, records []rawRecord) [32]byte {
+	root := merkleRoot(records)
+	tag := []byte("lightning" + messageName + "signature")
+
+	return taggedHash(tag, root[:])
+}
+
+// verifySignature checks the signature of the message by the given key.
+func verifySignature(messageName string, records []rawRecord,
+	sig *schnorr.Signature, key *bronec.PublicKey) error {
+
+	if sig == nil {
+		return ErrInvalidSignature
+	}
+
+	digest := signatureDigest(messageName, records)
+	if !sig.Verify(digest[:], key) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
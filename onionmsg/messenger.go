@@ -0,0 +1,338 @@
+package onionmsg
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/brronsuite/broln/blinding"
+	"github.com/brronsuite/broln/keychain"
+	"github.com/brronsuite/broln/lnwire"
+	"github.com/brronsuite/broln/subscribe"
+	"github.com/brronsuite/brond/bronec"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// DefaultMaxRelayBurst is the default maximum number of onion messages
+	// from a specific peer that we'll relay over an interval.
+	DefaultMaxRelayBurst = 20
+
+	// DefaultRelayInterval is the default interval we'll use to determine
+	// how often we relay a new onion message from a specific peer.
+	DefaultRelayInterval = 100 * time.Millisecond
+)
+
+var (
+	// ErrNoNextNode is returned when an onion message must be forwarded
+	// but the encrypted data doesn't specify the next node.
+	ErrNoNextNode = errors.New("onion message without next node id")
+)
+
+// ReceivedMessage is an onion message that was addressed to our node.
+type ReceivedMessage struct {
+	// Peer is the peer that forwarded the message to us. This is not
+	// necessarily the sender of the message.
+	Peer [33]byte
+
+	// PathID is the path id we put in the encrypted data of the blinded
+	// path the message was sent through, if any.
+	PathID []byte
+
+	// ReplyPath is the blinded path the sender provided for a reply, if
+	// any.
+	ReplyPath *blinding.BlindedPath
+
+	// CustomRecords are the records meant for the application.
+	CustomRecords map[uint64][]byte
+}
+
+// Config contains the dependencies of the Messenger.
+type Config struct {
+	// NodeKey is the identity key of our node that is used to decrypt
+	// onion messages.
+	NodeKey keychain.SingleKeyECDH
+
+	// SendToPeer sends the given message to the peer with the given
+	// public key. The peer must be connected.
+	SendToPeer func(peer [33]byte, msg lnwire.Message) error
+
+	// MaxRelayBurst specifies the maximum number of onion messages from a
+	// specific peer that we'll relay over an interval.
+	MaxRelayBurst int
+
+	// RelayInterval specifies the interval we'll use to determine how
+	// often we relay a new onion message from a specific peer.
+	RelayInterval time.Duration
+}
+
+// Messenger handles the onion messages received from our peers and lets the
+// caller send onion messages through blinded paths. Messages that aren't
+// addressed to us are forwarded to the next hop, the others are delivered to
+// the subscribers of the Messenger.
+type Messenger struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg *Config
+
+	msgServer *subscribe.Server
+
+	// relayRateLimiter contains a rate limiter for each peer whose onion
+	// messages we relay, such that a peer can't use us to flood the
+	// network.
+	relayRateLimiter map[[33]byte]*rate.Limiter
+	relayMtx         sync.Mutex
+}
+
+// New creates a new Messenger from the given config.
+func New(cfg *Config) *Messenger {
+	return &Messenger{
+		cfg:              cfg,
+		msgServer:        subscribe.NewServer(),
+		relayRateLimiter: make(map[[33]byte]*rate.Limiter),
+	}
+}
+
+// Start starts the Messenger.
+func (m *Messenger) Start() error {
+	var err error
+	m.started.Do(func() {
+		log.Info("Onion messenger starting")
+		err = m.msgServer.Start()
+	})
+
+	return err
+}
+
+// Stop stops the Messenger.
+func (m *Messenger) Stop() error {
+	var err error
+	m.stopped.Do(func() {
+		log.Info("Onion messenger shutting down")
+		err = m.msgServer.Stop()
+	})
+
+	return err
+}
+
+// SubscribeMessages returns a subscription that delivers all onion messages
+// addressed to our node as *ReceivedMessage.
+func (m *Messenger) SubscribeMessages() (*subscribe.Client, error) {
+	return m.msgServer.Subscribe()
+}
+
+// HandleMessage processes an onion message received from the given peer. The
+// message is either forwarded to the next hop or delivered to our
+// subscribers.
+func (m *Messenger) HandleMessage(peer [33]byte,
+	msg *lnwire.OnionMessage) error {
+
+	// The onion is encrypted to our blinded node id, so we derive the
+	// matching key from the blinding point.
+	blindedKey, err := blinding.NewBlindedECDH(
+		m.cfg.NodeKey, msg.BlindingPoint,
+	)
+	if err != nil {
+		return err
+	}
+
+	packet, err := DecodePacket(msg.OnionBlob)
+	if err != nil {
+		return err
+	}
+
+	processed, err := ProcessPacket(blindedKey, packet)
+	if err != nil {
+		return err
+	}
+
+	payload, err := DecodePayload(processed.Payload)
+	if err != nil {
+		return err
+	}
+	if payload.EncryptedData == nil {
+		return ErrNoEncryptedData
+	}
+
+	plainText, err := blinding.DecryptHopData(
+		m.cfg.NodeKey, msg.BlindingPoint, payload.EncryptedData,
+	)
+	if err != nil {
+		return err
+	}
+
+	routeData, err := blinding.DecodeRouteData(plainText)
+	if err != nil {
+		return err
+	}
+
+	// If there is no next packet, the message is addressed to us.
+	if processed.NextPacket == nil {
+		log.Debugf("Received onion message from peer %x with %d "+
+			"custom records", peer, len(payload.CustomRecords))
+
+		return m.msgServer.SendUpdate(&ReceivedMessage{
+			Peer:          peer,
+			PathID:        routeData.PathID,
+			ReplyPath:     payload.ReplyPath,
+			CustomRecords: payload.CustomRecords,
+		})
+	}
+
+	if routeData.NextNodeID == nil {
+		return ErrNoNextNode
+	}
+
+	nextBlinding := routeData.NextBlindingOverride
+	if nextBlinding == nil {
+		nextBlinding, err = blinding.NextBlindingPoint(
+			m.cfg.NodeKey, msg.BlindingPoint,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	var nextPeer [33]byte
+	copy(nextPeer[:], routeData.NextNodeID.SerializeCompressed())
+
+	// Messages we can't relay right now are dropped, onion messages are
+	// unreliable anyway.
+	if !m.allowRelay(peer) {
+		log.Debugf("Rate limiting onion message from peer %x to %x",
+			peer, nextPeer)
+
+		return nil
+	}
+
+	log.Debugf("Forwarding onion message from peer %x to %x", peer,
+		nextPeer)
+
+	return m.sendPacket(nextPeer, nextBlinding, processed.NextPacket)
+}
+
+// allowRelay returns whether we may relay another onion message received from
+// the given peer.
+func (m *Messenger) allowRelay(peer [33]byte) bool {
+	m.relayMtx.Lock()
+	defer m.relayMtx.Unlock()
+
+	limiter, ok := m.relayRateLimiter[peer]
+	if !ok {
+		interval := m.cfg.RelayInterval
+		if interval == 0 {
+			interval = DefaultRelayInterval
+		}
+		burst := m.cfg.MaxRelayBurst
+		if burst == 0 {
+			burst = DefaultMaxRelayBurst
+		}
+
+		limiter = rate.NewLimiter(rate.Every(interval), burst)
+		m.relayRateLimiter[peer] = limiter
+	}
+
+	return limiter.Allow()
+}
+
+// SendMessage sends an onion message with the given custom records through
+// the blinded path. If a reply path is given, it is included in the payload of
+// the final hop.
+func (m *Messenger) SendMessage(path *blinding.BlindedPath,
+	replyPath *blinding.BlindedPath,
+	customRecords map[uint64][]byte) error {
+
+	sessionKey, err := bronec.NewPrivateKey(bronec.S256())
+	if err != nil {
+		return err
+	}
+
+	hops := make([]*HopInfo, len(path.BlindedHops))
+	for i, blindedHop := range path.BlindedHops {
+		payload := &Payload{
+			EncryptedData: blindedHop.CipherText,
+		}
+		if i == len(path.BlindedHops)-1 {
+			payload.ReplyPath = replyPath
+			payload.CustomRecords = customRecords
+		}
+
+		var b bytes.Buffer
+		if err := payload.Encode(&b); err != nil {
+			return err
+		}
+
+		hops[i] = &HopInfo{
+			NodePub: blindedHop.BlindedNodePub,
+			Payload: b.Bytes(),
+		}
+	}
+
+	packet, err := NewPacket(sessionKey, hops)
+	if err != nil {
+		return err
+	}
+
+	var firstPeer [33]byte
+	copy(firstPeer[:], path.IntroductionPoint.SerializeCompressed())
+
+	return m.sendPacket(firstPeer, path.BlindingPoint, packet)
+}
+
+// sendPacket sends the onion message packet to the given peer.
+func (m *Messenger) sendPacket(peer [33]byte,
+	blindingPoint *bronec.PublicKey, packet *Packet) error {
+
+	var b bytes.Buffer
+	if err := packet.Encode(&b); err != nil {
+		return err
+	}
+
+	msg := lnwire.NewOnionMessage(blindingPoint, b.Bytes())
+	if err := m.cfg.SendToPeer(peer, msg); err != nil {
+		return fmt.Errorf("unable to send onion message to %x: %w",
+			peer, err)
+	}
+
+	return nil
+}
+
+// BuildPath creates a blinded path through the given nodes. Every node learns
+// the next one from its encrypted data, the final node receives the path id.
+func BuildPath(nodes []*bronec.PublicKey,
+	pathID []byte) (*blinding.BlindedPath, error) {
+
+	if len(nodes) == 0 {
+		return nil, blinding.ErrNoHops
+	}
+
+	hops := make([]*blinding.HopInfo, len(nodes))
+	for i, node := range nodes {
+		var data blinding.RouteData
+		if i < len(nodes)-1 {
+			data.NextNodeID = nodes[i+1]
+		} else {
+			data.PathID = pathID
+		}
+
+		plainText, err := blinding.EncodeRouteData(&data)
+		if err != nil {
+			return nil, err
+		}
+
+		hops[i] = &blinding.HopInfo{
+			NodePub:   node,
+			PlainText: plainText,
+		}
+	}
+
+	sessionKey, err := bronec.NewPrivateKey(bronec.S256())
+	if err != nil {
+		return nil, err
+	}
+
+	return blinding.BuildBlindedPath(sessionKey, hops)
+}
@@ -0,0 +1,259 @@
+package onionmsg
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/brronsuite/broln/keychain"
+	"github.com/brronsuite/broln/lnwire"
+	"github.com/brronsuite/brond/bronec"
+	"github.com/stretchr/testify/require"
+)
+
+// testNetwork is a set of messengers that deliver messages to each other
+// directly.
+type testNetwork struct {
+	t          *testing.T
+	messengers map[[33]byte]*Messenger
+	keys       []*bronec.PublicKey
+}
+
+// newTestNetwork creates a network of the given number of started messengers.
+// The given function, if any, can modify the config of every messenger.
+func newTestNetwork(t *testing.T, numNodes int,
+	modifyCfg func(*Config)) *testNetwork {
+
+	n := &testNetwork{
+		t:          t,
+		messengers: make(map[[33]byte]*Messenger),
+	}
+
+	for i := 0; i < numNodes; i++ {
+		priv, err := bronec.NewPrivateKey(bronec.S256())
+		require.NoError(t, err)
+
+		var pub [33]byte
+		copy(pub[:], priv.PubKey().SerializeCompressed())
+
+		cfg := &Config{
+			NodeKey: &keychain.PrivKeyECDH{PrivKey: priv},
+			SendToPeer: func(peer [33]byte,
+				msg lnwire.Message) error {
+
+				return n.deliver(pub, peer, msg)
+			},
+		}
+		if modifyCfg != nil {
+			modifyCfg(cfg)
+		}
+
+		m := New(cfg)
+		require.NoError(t, m.Start())
+		t.Cleanup(func() {
+			require.NoError(t, m.Stop())
+		})
+
+		n.messengers[pub] = m
+		n.keys = append(n.keys, priv.PubKey())
+	}
+
+	return n
+}
+
+// deliver passes the message through its wire encoding and hands it to the
+// messenger of the receiving node.
+func (n *testNetwork) deliver(from, to [33]byte, msg lnwire.Message) error {
+	var b bytes.Buffer
+	_, err := lnwire.WriteMessage(&b, msg, 0)
+	require.NoError(n.t, err)
+
+	decoded, err := lnwire.ReadMessage(&b, 0)
+	require.NoError(n.t, err)
+
+	m, ok := n.messengers[to]
+	require.True(n.t, ok)
+
+	return m.HandleMessage(from, decoded.(*lnwire.OnionMessage))
+}
+
+// messenger returns the messenger of the node with the given index.
+func (n *testNetwork) messenger(i int) *Messenger {
+	var pub [33]byte
+	copy(pub[:], n.keys[i].SerializeCompressed())
+
+	return n.messengers[pub]
+}
+
+// TestOnionMessageRelay tests that an onion message is forwarded along a
+// blinded path and delivered to the final node with its custom records and
+// reply path.
+func TestOnionMessageRelay(t *testing.T) {
+	t.Parallel()
+
+	network := newTestNetwork(t, 4, nil)
+
+	// Subscribe to the messages of the final node and one of the relaying
+	// nodes, which must not receive anything.
+	finalSub, err := network.messenger(3).SubscribeMessages()
+	require.NoError(t, err)
+	defer finalSub.Cancel()
+
+	relaySub, err := network.messenger(2).SubscribeMessages()
+	require.NoError(t, err)
+	defer relaySub.Cancel()
+
+	pathID := []byte("test path id")
+	path, err := BuildPath(network.keys[1:], pathID)
+	require.NoError(t, err)
+
+	replyPath, err := BuildPath(
+		[]*bronec.PublicKey{network.keys[1], network.keys[0]}, nil,
+	)
+	require.NoError(t, err)
+
+	customRecords := map[uint64][]byte{
+		64:  []byte("even app data"),
+		101: bytes.Repeat([]byte{1}, 2000),
+	}
+
+	// The payload is larger than the default routing info size, so a
+	// larger packet is created.
+	err = network.messenger(0).SendMessage(path, replyPath, customRecords)
+	require.NoError(t, err)
+
+	select {
+	case update := <-finalSub.Updates():
+		msg, ok := update.(*ReceivedMessage)
+		require.True(t, ok)
+
+		require.Equal(t, pathID, msg.PathID)
+		require.Equal(t, customRecords, msg.CustomRecords)
+		require.Equal(t, replyPath, msg.ReplyPath)
+
+	case <-time.After(time.Second):
+		t.Fatalf("message not received")
+	}
+
+	select {
+	case <-relaySub.Updates():
+		t.Fatalf("relaying node received message")
+
+	default:
+	}
+}
+
+// TestOnionMessageWrongNode tests that a node can't process an onion message
+// that is not meant for it.
+func TestOnionMessageWrongNode(t *testing.T) {
+	t.Parallel()
+
+	network := newTestNetwork(t, 3, nil)
+
+	path, err := BuildPath(network.keys[1:], nil)
+	require.NoError(t, err)
+
+	// The message is wrongly delivered to the final node instead of the
+	// introduction node.
+	path.IntroductionPoint = network.keys[2]
+
+	err = network.messenger(0).SendMessage(path, nil, nil)
+	require.ErrorIs(t, err, ErrInvalidOnionHMAC)
+
+	// Custom records below the allowed range are rejected.
+	err = network.messenger(0).SendMessage(
+		path, nil, map[uint64][]byte{10: {1}},
+	)
+	require.ErrorIs(t, err, ErrInvalidCustomRecord)
+}
+
+// TestOnionMessageRelayRateLimit tests that onion messages a peer asks us to
+// relay are dropped once its rate limit is reached.
+func TestOnionMessageRelayRateLimit(t *testing.T) {
+	t.Parallel()
+
+	network := newTestNetwork(t, 3, func(cfg *Config) {
+		cfg.MaxRelayBurst = 1
+		cfg.RelayInterval = time.Hour
+	})
+
+	finalSub, err := network.messenger(2).SubscribeMessages()
+	require.NoError(t, err)
+	defer finalSub.Cancel()
+
+	path, err := BuildPath(network.keys[1:], nil)
+	require.NoError(t, err)
+
+	// Both messages are accepted by the relaying node, but only the first
+	// one is forwarded.
+	for i := 0; i < 2; i++ {
+		err := network.messenger(0).SendMessage(path, nil, nil)
+		require.NoError(t, err)
+	}
+
+	select {
+	case <-finalSub.Updates():
+	case <-time.After(time.Second):
+		t.Fatalf("message not received")
+	}
+
+	select {
+	case <-finalSub.Updates():
+		t.Fatalf("rate limited message received")
+
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// TestNewPacketRoutingInfoSize tests that packets are only created with one
+// of the two standard routing info sizes.
+func TestNewPacketRoutingInfoSize(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		payloadSize int
+		expected    int
+	}{
+		{
+			name:        "small payload",
+			payloadSize: 100,
+			expected:    DefaultRoutingInfoSize,
+		},
+		{
+			name:        "payload larger than default",
+			payloadSize: 2000,
+			expected:    MaxRoutingInfoSize,
+		},
+		{
+			name:        "payload too large",
+			payloadSize: MaxRoutingInfoSize,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			sessionKey, err := bronec.NewPrivateKey(bronec.S256())
+			require.NoError(t, err)
+
+			nodeKey, err := bronec.NewPrivateKey(bronec.S256())
+			require.NoError(t, err)
+
+			hops := []*HopInfo{{
+				NodePub: nodeKey.PubKey(),
+				Payload: make([]byte, tc.payloadSize),
+			}}
+
+			packet, err := NewPacket(sessionKey, hops)
+			if tc.expected == 0 {
+				require.ErrorIs(t, err, ErrPayloadTooLarge)
+				return
+			}
+			require.NoError(t, err)
+
+			require.Len(t, packet.RoutingInfo, tc.expected)
+		})
+	}
+}
@@ -0,0 +1,442 @@
+package onionmsg
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/brronsuite/broln/blinding"
+	"github.com/brronsuite/broln/keychain"
+	"github.com/brronsuite/broln/tlv"
+	"github.com/brronsuite/brond/bronec"
+	"golang.org/x/crypto/chacha20"
+)
+
+const (
+	// onionVersion is the only supported version of the onion message
+	// packet.
+	onionVersion byte = 0
+
+	// hmacSize is the size of the HMAC that authenticates every layer of
+	// the onion.
+	hmacSize = 32
+
+	// DefaultRoutingInfoSize is the size of the routing info of an onion
+	// message packet that is used unless the payloads don't fit.
+	DefaultRoutingInfoSize = 1300
+
+	// MaxRoutingInfoSize is the size of the routing info of an onion
+	// message packet whose payloads don't fit into the default size. It is
+	// the largest routing info of an onion message packet we create.
+	MaxRoutingInfoSize = 32768
+
+	// packetOverhead is the size of a packet without its routing info.
+	packetOverhead = 1 + 33 + hmacSize
+)
+
+var (
+	// ErrInvalidOnionVersion is returned when an onion message packet with
+	// an unknown version is received.
+	ErrInvalidOnionVersion = errors.New("invalid onion message version")
+
+	// ErrInvalidOnionHMAC is returned when the HMAC of an onion message
+	// packet doesn't match, meaning it was not meant for us or it was
+	// tampered with.
+	ErrInvalidOnionHMAC = errors.New("invalid onion message hmac")
+
+	// ErrPayloadTooLarge is returned when the hop payloads don't fit into
+	// the largest supported routing info.
+	ErrPayloadTooLarge = errors.New("onion message payloads too large")
+
+	// ErrInvalidPayloadSize is returned when a hop payload announces a
+	// length that exceeds the routing info.
+	ErrInvalidPayloadSize = errors.New("invalid onion message payload " +
+		"size")
+)
+
+// Packet is an onion message packet. It is structured like the sphinx packet
+// used for payments, but has a variable size routing info and no associated
+// data.
+type Packet struct {
+	// Version is the version of the packet.
+	Version byte
+
+	// EphemeralKey is the ephemeral public key used by the receiver to
+	// derive the shared secret of this layer of the onion.
+	EphemeralKey *bronec.PublicKey
+
+	// RoutingInfo contains the encrypted hop payloads.
+	RoutingInfo []byte
+
+	// HMAC authenticates the routing info.
+	HMAC [hmacSize]byte
+}
+
+// Encode serializes the packet to the passed writer.
+func (p *Packet) Encode(w io.Writer) error {
+	if _, err := w.Write([]byte{p.Version}); err != nil {
+		return err
+	}
+	_, err := w.Write(p.EphemeralKey.SerializeCompressed())
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(p.RoutingInfo); err != nil {
+		return err
+	}
+	_, err = w.Write(p.HMAC[:])
+
+	return err
+}
+
+// DecodePacket parses a serialized onion message packet.
+func DecodePacket(b []byte) (*Packet, error) {
+	if len(b) < packetOverhead {
+		return nil, fmt.Errorf("onion message packet too short: %v",
+			len(b))
+	}
+
+	if b[0] != onionVersion {
+		return nil, ErrInvalidOnionVersion
+	}
+
+	ephemeralKey, err := bronec.ParsePubKey(b[1:34], bronec.S256())
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Packet{
+		Version:      b[0],
+		EphemeralKey: ephemeralKey,
+		RoutingInfo:  make([]byte, len(b)-packetOverhead),
+	}
+	copy(p.RoutingInfo, b[34:len(b)-hmacSize])
+	copy(p.HMAC[:], b[len(b)-hmacSize:])
+
+	return p, nil
+}
+
+// HopInfo is a hop of the route an onion message takes.
+type HopInfo struct {
+	// NodePub is the public key the layer of the onion is encrypted to.
+	// For blinded hops this is the blinded node id.
+	NodePub *bronec.PublicKey
+
+	// Payload is the serialized onion message TLV payload for the hop.
+	Payload []byte
+}
+
+// NewPacket creates a new onion message packet for the given hops using the
+// session key as the first ephemeral key.
+func NewPacket(sessionKey *bronec.PrivateKey,
+	hops []*HopInfo) (*Packet, error) {
+
+	if len(hops) == 0 {
+		return nil, errors.New("onion message route must have at " +
+			"least one hop")
+	}
+
+	// Only the two standard routing info sizes are used, such that the
+	// size of a packet doesn't reveal the size of its payloads. We pick
+	// the smaller one if all hop payloads fit into it.
+	var payloadsSize int
+	for _, hop := range hops {
+		payloadsSize += hopPayloadSize(hop.Payload)
+	}
+
+	var routingInfoSize int
+	switch {
+	case payloadsSize <= DefaultRoutingInfoSize:
+		routingInfoSize = DefaultRoutingInfoSize
+
+	case payloadsSize <= MaxRoutingInfoSize:
+		routingInfoSize = MaxRoutingInfoSize
+
+	default:
+		return nil, ErrPayloadTooLarge
+	}
+
+	return newPacket(sessionKey, hops, routingInfoSize)
+}
+
+// NewFixedSizePacket creates a new packet for the given hops with a routing
+// info of exactly the given size. It is used by protocols that nest packets
+// of a well known size within other onions, such as trampoline payments.
+func NewFixedSizePacket(sessionKey *bronec.PrivateKey, hops []*HopInfo,
+	routingInfoSize int) (*Packet, error) {
+
+	if len(hops) == 0 {
+		return nil, errors.New("onion route must have at least one hop")
+	}
+
+	var payloadsSize int
+	for _, hop := range hops {
+		payloadsSize += hopPayloadSize(hop.Payload)
+	}
+	if payloadsSize > routingInfoSize {
+		return nil, ErrPayloadTooLarge
+	}
+
+	return newPacket(sessionKey, hops, routingInfoSize)
+}
+
+// newPacket creates a new packet with the given routing info size, which must
+// be large enough to fit all hop payloads.
+func newPacket(sessionKey *bronec.PrivateKey, hops []*HopInfo,
+	routingInfoSize int) (*Packet, error) {
+
+	sharedSecrets, err := generateSharedSecrets(sessionKey, hops)
+	if err != nil {
+		return nil, err
+	}
+
+	filler := generateFiller(hops, sharedSecrets, routingInfoSize)
+
+	// The routing info is initialized with pseudo random bytes so the
+	// last hop can't learn the route length.
+	padKey := generateKey("pad", sessionKey.Serialize())
+	mixHeader := generateCipherStream(padKey, routingInfoSize)
+
+	var nextHMAC [hmacSize]byte
+	for i := len(hops) - 1; i >= 0; i-- {
+		rho := generateKey("rho", sharedSecrets[i][:])
+		mu := generateKey("mu", sharedSecrets[i][:])
+
+		var hopPayload bytes.Buffer
+		if err := encodeHopPayload(
+			&hopPayload, hops[i].Payload, nextHMAC,
+		); err != nil {
+			return nil, err
+		}
+
+		// Shift the routing info to the right to make room for the
+		// payload of this hop, then encrypt it.
+		shift := hopPayload.Len()
+		copy(mixHeader[shift:], mixHeader[:routingInfoSize-shift])
+		copy(mixHeader, hopPayload.Bytes())
+		xor(mixHeader, mixHeader, generateCipherStream(
+			rho, routingInfoSize,
+		))
+
+		if i == len(hops)-1 {
+			copy(mixHeader[routingInfoSize-len(filler):], filler)
+		}
+
+		nextHMAC = calcMAC(mu, mixHeader)
+	}
+
+	return &Packet{
+		Version:      onionVersion,
+		EphemeralKey: sessionKey.PubKey(),
+		RoutingInfo:  mixHeader,
+		HMAC:         nextHMAC,
+	}, nil
+}
+
+// ProcessedPacket is the result of peeling a layer off an onion message.
+type ProcessedPacket struct {
+	// Payload is the payload of the onion message meant for us.
+	Payload []byte
+
+	// NextPacket is the packet to forward to the next hop. It is nil if
+	// we are the final hop.
+	NextPacket *Packet
+}
+
+// ProcessPacket peels a layer off the onion message using the given key. For
+// onion messages the key is the blinded node key.
+func ProcessPacket(key keychain.SingleKeyECDH,
+	p *Packet) (*ProcessedPacket, error) {
+
+	if p.Version != onionVersion {
+		return nil, ErrInvalidOnionVersion
+	}
+
+	ss, err := key.ECDH(p.EphemeralKey)
+	if err != nil {
+		return nil, err
+	}
+
+	mu := generateKey("mu", ss[:])
+	expectedHMAC := calcMAC(mu, p.RoutingInfo)
+	if !hmac.Equal(expectedHMAC[:], p.HMAC[:]) {
+		return nil, ErrInvalidOnionHMAC
+	}
+
+	// Extend the routing info with zeroes before decrypting it so the
+	// packet for the next hop keeps the same size.
+	routingInfoSize := len(p.RoutingInfo)
+	rho := generateKey("rho", ss[:])
+	decrypted := make([]byte, 2*routingInfoSize)
+	copy(decrypted, p.RoutingInfo)
+	xor(decrypted, decrypted, generateCipherStream(
+		rho, 2*routingInfoSize,
+	))
+
+	r := bytes.NewReader(decrypted)
+	var b [8]byte
+	payloadLen, err := tlv.ReadVarInt(r, &b)
+	if err != nil {
+		return nil, err
+	}
+	hopLen := tlv.VarIntSize(payloadLen) + payloadLen + hmacSize
+	if payloadLen > uint64(routingInfoSize) ||
+		hopLen > uint64(routingInfoSize) {
+
+		return nil, ErrInvalidPayloadSize
+	}
+
+	payload := make([]byte, payloadLen)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, err
+	}
+
+	var nextHMAC [hmacSize]byte
+	if _, err := io.ReadFull(r, nextHMAC[:]); err != nil {
+		return nil, err
+	}
+
+	processed := &ProcessedPacket{
+		Payload: payload,
+	}
+
+	// An empty HMAC signals that we are the final hop.
+	if nextHMAC == [hmacSize]byte{} {
+		return processed, nil
+	}
+
+	nextKey := blinding.NextEphemeralPub(p.EphemeralKey, ss)
+
+	nextRoutingInfo := decrypted[hopLen : hopLen+uint64(routingInfoSize)]
+	processed.NextPacket = &Packet{
+		Version:      onionVersion,
+		EphemeralKey: nextKey,
+		RoutingInfo:  nextRoutingInfo,
+		HMAC:         nextHMAC,
+	}
+
+	return processed, nil
+}
+
+// generateSharedSecrets derives the shared secret with every hop of the
+// route. The ephemeral key is blinded after every hop, so every hop sees a
+// different ephemeral key.
+func generateSharedSecrets(sessionKey *bronec.PrivateKey,
+	hops []*HopInfo) ([][32]byte, error) {
+
+	sharedSecrets := make([][32]byte, len(hops))
+	ephemeral := sessionKey
+	for i, hop := range hops {
+		ecdh := &keychain.PrivKeyECDH{PrivKey: ephemeral}
+		ss, err := ecdh.ECDH(hop.NodePub)
+		if err != nil {
+			return nil, err
+		}
+		sharedSecrets[i] = ss
+
+		ephemeral = blinding.NextEphemeralPriv(ephemeral, ss)
+	}
+
+	return sharedSecrets, nil
+}
+
+// generateFiller creates the filler that is appended to the routing info by
+// the last hop so the HMACs of all hops are computed over the bytes they will
+// actually see.
+func generateFiller(hops []*HopInfo, sharedSecrets [][32]byte,
+	routingInfoSize int) []byte {
+
+	var fillerSize int
+	for _, hop := range hops[:len(hops)-1] {
+		fillerSize += hopPayloadSize(hop.Payload)
+	}
+
+	filler := make([]byte, fillerSize)
+	fillerStart := routingInfoSize
+	for i, hop := range hops[:len(hops)-1] {
+		fillerEnd := routingInfoSize + hopPayloadSize(hop.Payload)
+
+		rho := generateKey("rho", sharedSecrets[i][:])
+		stream := generateCipherStream(rho, 2*routingInfoSize)
+		xor(filler, filler, stream[fillerStart:fillerEnd])
+
+		fillerStart -= hopPayloadSize(hop.Payload)
+	}
+
+	return filler
+}
+
+// encodeHopPayload writes the length prefixed payload followed by the HMAC for
+// the next hop.
+func encodeHopPayload(w io.Writer, payload []byte,
+	nextHMAC [hmacSize]byte) error {
+
+	var b [8]byte
+	if err := tlv.WriteVarInt(w, uint64(len(payload)), &b); err != nil {
+		return err
+	}
+	if _, err := w.Write(payload); err != nil {
+		return err
+	}
+	_, err := w.Write(nextHMAC[:])
+
+	return err
+}
+
+// hopPayloadSize returns the number of bytes a hop payload takes up in the
+// routing info.
+func hopPayloadSize(payload []byte) int {
+	return int(tlv.VarIntSize(uint64(len(payload)))) + len(payload) +
+		hmacSize
+}
+
+// generateKey derives a key of the given type from the shared secret.
+func generateKey(keyType string, ss []byte) [32]byte {
+	var key [32]byte
+	mac := hmac.New(sha256.New, []byte(keyType))
+	mac.Write(ss)
+	copy(key[:], mac.Sum(nil))
+
+	return key
+}
+
+// generateCipherStream generates a pseudo random stream of the given length
+// by encrypting zeroes with ChaCha20 under the given key.
+func generateCipherStream(key [32]byte, numBytes int) []byte {
+	var nonce [chacha20.NonceSize]byte
+	cipher, err := chacha20.NewUnauthenticatedCipher(key[:], nonce[:])
+	if err != nil {
+		// This can't happen as the key and nonce have the right size.
+		panic(err)
+	}
+
+	stream := make([]byte, numBytes)
+	cipher.XORKeyStream(stream, stream)
+
+	return stream
+}
+
+// calcMAC computes the HMAC of the routing info under the given key.
+func calcMAC(key [32]byte, msg []byte) [hmacSize]byte {
+	var mac [hmacSize]byte
+	h := hmac.New(sha256.New, key[:])
+	h.Write(msg)
+	copy(mac[:], h.Sum(nil))
+
+	return mac
+}
+
+// xor computes the byte wise xor of a and b and writes it to dst. Only the
+// common length of the inputs is processed.
+func xor(dst, a, b []byte) {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	for i := 0; i < n; i++ {
+		dst[i] = a[i] ^ b[i]
+	}
+}
@@ -0,0 +1,150 @@
+package onionmsg
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/brronsuite/broln/blinding"
+	"github.com/brronsuite/broln/tlv"
+)
+
+const (
+	// replyPathType is the TLV type of the blinded path the recipient
+	// can use to reply to the message.
+	replyPathType tlv.Type = 2
+
+	// encryptedDataType is the TLV type of the encrypted data of a
+	// blinded hop.
+	encryptedDataType tlv.Type = 4
+
+	// MinCustomRecordType is the smallest TLV type of the records that are
+	// delivered to the application of the final recipient.
+	MinCustomRecordType = 64
+)
+
+var (
+	// ErrNoEncryptedData is returned when an onion message payload
+	// doesn't contain the encrypted data of the blinded hop.
+	ErrNoEncryptedData = errors.New("onion message payload without " +
+		"encrypted data")
+
+	// ErrInvalidCustomRecord is returned when a custom record with a type
+	// below MinCustomRecordType is passed.
+	ErrInvalidCustomRecord = fmt.Errorf("custom record types must be at "+
+		"least %v", MinCustomRecordType)
+)
+
+// Payload is the payload of a single hop of an onion message.
+type Payload struct {
+	// ReplyPath is the blinded path the final recipient can use to reply
+	// to the message.
+	ReplyPath *blinding.BlindedPath
+
+	// EncryptedData is the encrypted data of the blinded hop.
+	EncryptedData []byte
+
+	// CustomRecords are the records meant for the application of the
+	// final recipient.
+	CustomRecords map[uint64][]byte
+}
+
+// Encode serializes the payload as a TLV stream.
+func (p *Payload) Encode(w io.Writer) error {
+	var records []tlv.Record
+	if p.ReplyPath != nil {
+		var b bytes.Buffer
+		if err := p.ReplyPath.Encode(&b); err != nil {
+			return err
+		}
+		replyPath := b.Bytes()
+
+		records = append(records, tlv.MakePrimitiveRecord(
+			replyPathType, &replyPath,
+		))
+	}
+	if p.EncryptedData != nil {
+		records = append(records, tlv.MakePrimitiveRecord(
+			encryptedDataType, &p.EncryptedData,
+		))
+	}
+
+	for typ := range p.CustomRecords {
+		if typ < MinCustomRecordType {
+			return ErrInvalidCustomRecord
+		}
+	}
+	records = append(records, tlv.MapToRecords(p.CustomRecords)...)
+	tlv.SortRecords(records)
+
+	stream, err := tlv.NewStream(records...)
+	if err != nil {
+		return err
+	}
+
+	return stream.Encode(w)
+}
+
+// DecodePayload parses an onion message payload. All records with a type of
+// at least MinCustomRecordType are returned as custom records, regardless of
+// whether their type is even or odd, as they are interpreted by the
+// application.
+func DecodePayload(b []byte) (*Payload, error) {
+	var (
+		p        Payload
+		r        = bytes.NewReader(b)
+		buf      [8]byte
+		lastType *uint64
+	)
+	for r.Len() > 0 {
+		typ, err := tlv.ReadVarInt(r, &buf)
+		if err != nil {
+			return nil, err
+		}
+		if lastType != nil && typ <= *lastType {
+			return nil, fmt.Errorf("onion message payload types "+
+				"not strictly increasing: %v", typ)
+		}
+		lastType = &typ
+
+		length, err := tlv.ReadVarInt(r, &buf)
+		if err != nil {
+			return nil, err
+		}
+		if length > uint64(r.Len()) {
+			return nil, io.ErrUnexpectedEOF
+		}
+
+		value := make([]byte, length)
+		if _, err := io.ReadFull(r, value); err != nil {
+			return nil, err
+		}
+
+		switch {
+		case tlv.Type(typ) == replyPathType:
+			p.ReplyPath, err = blinding.DecodeBlindedPath(
+				bytes.NewReader(value),
+			)
+			if err != nil {
+				return nil, err
+			}
+
+		case tlv.Type(typ) == encryptedDataType:
+			p.EncryptedData = value
+
+		case typ >= MinCustomRecordType:
+			if p.CustomRecords == nil {
+				p.CustomRecords = make(map[uint64][]byte)
+			}
+			p.CustomRecords[typ] = value
+
+		// Unknown even types below the custom range must be understood.
+		case typ%2 == 0:
+			return nil, fmt.Errorf("unknown required onion "+
+				"message payload type %v", typ)
+		}
+	}
+
+	return &p, nil
+}
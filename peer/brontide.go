@@ -327,6 +327,44 @@ type Config struct {
 	// from the peer.
 	HandleCustomMessage func(peer [33]byte, msg *lnwire.Custom) error
 
+	// HandleOnionMessage is called whenever an onion message is received
+	// from the peer. The handler peels the onion and either forwards the
+	// message to the next hop or delivers it locally.
+	HandleOnionMessage func(peer [33]byte, msg *lnwire.OnionMessage) error
+
+	// ForwardTrampoline is called for htlcs destined for us that carry a
+	// trampoline onion. It returns true if the htlc is held until the
+	// trampoline payment is forwarded. If nil, trampoline payments aren't
+	// forwarded.
+	ForwardTrampoline func(*htlcswitch.TrampolineHtlc) bool
+
+	// PeerStorageQuota is the maximum size of the backup blob we store on
+	// behalf of the peer. Larger blobs are ignored.
+	PeerStorageQuota int
+
+	// StorePeerStorage persists the backup blob the peer asked us to store
+	// on its behalf. If nil, blobs sent by the peer are ignored.
+	StorePeerStorage func(peer [33]byte, blob []byte) error
+
+	// FetchPeerStorage returns the backup blob the peer last asked us to
+	// store, or nil if we don't hold one for the peer. The blob is handed
+	// back to the peer right after the init exchange.
+	FetchPeerStorage func(peer [33]byte) ([]byte, error)
+
+	// OurPeerStorage returns the latest encrypted backup of our channels
+	// that we ask peers offering storage to hold for us, or nil if we
+	// don't have one.
+	OurPeerStorage func() []byte
+
+	// HandlePeerStorageRetrieval is called whenever the peer hands us back
+	// the backup blob we asked it to store.
+	HandlePeerStorageRetrieval func(peer [33]byte, blob []byte) error
+
+	// BackupCommitState is passed to the ChannelLink on creation and
+	// persists the latest local commitment of a channel to the static
+	// channel backup. If nil, the backups don't carry commitment state.
+	BackupCommitState func(wire.OutPoint) error
+
 	// PongBuf is a slice we'll reuse instead of allocating memory on the
 	// heap. Since only reads will occur and no writes, there is no need
 	// for any synchronization primitives. As a result, it's safe to share
@@ -436,6 +474,53 @@ type Brontide struct {
 	// well as lnwire.ClosingSigned messages.
 	chanCloseMsgs chan *closeMsg
 
+	// bumpCloseFeeReqs is a channel over which local requests to bump the
+	// fee of an RBF cooperative close are sent.
+	bumpCloseFeeReqs chan *bumpCloseFeeReq
+
+	// activeSplices is a map that keeps track of all channels that are
+	// quiescent, until the splice is published or the pending splices are
+	// locked or aborted. It's only accessed by the channelManager
+	// goroutine.
+	activeSplices map[lnwire.ChannelID]*spliceCtx
+
+	// pendingSplices is a map that keeps track of all channels with
+	// published splice transactions, until one of them is locked or all
+	// of them are aborted. It's only accessed by the channelManager
+	// goroutine.
+	pendingSplices map[lnwire.ChannelID]*pendingSpliceState
+
+	// splicingChans is the set of channels that are being spliced. The
+	// interactive tx and commitment messages of these channels are
+	// directed to the splice state machine instead of the funding manager
+	// or the channel's link.
+	splicingChans    map[lnwire.ChannelID]struct{}
+	splicingChansMtx sync.RWMutex
+
+	// localSpliceReqs is a channel in which any local requests to splice
+	// a particular channel are sent over.
+	localSpliceReqs chan *SpliceReq
+
+	// chanSpliceMsgs is a channel that any message related to splices is
+	// sent over.
+	chanSpliceMsgs chan lnwire.Message
+
+	// spliceFinalizeReqs is a channel over which the signed PSBTs of PSBT
+	// funded splices are sent.
+	spliceFinalizeReqs chan *spliceFinalizeReq
+
+	// splicesConfirmed is a channel over which channels are sent whose
+	// splice transaction confirmed.
+	splicesConfirmed chan *spliceConfirmedMsg
+
+	// spliceInputSpends is a channel over which spends of the inputs of
+	// pending splice transactions are sent.
+	spliceInputSpends chan *spliceInputSpend
+
+	// spliceRetries is a channel over which channels are sent whose
+	// pending splices we try again to lock or abort.
+	spliceRetries chan lnwire.ChannelID
+
 	// remoteFeatures is the feature vector received from the peer during
 	// the connection handshake.
 	remoteFeatures *lnwire.FeatureVector
@@ -470,6 +555,16 @@ func NewBrontide(cfg Config) *Brontide {
 		localCloseChanReqs: make(chan *htlcswitch.ChanClose),
 		linkFailures:       make(chan linkFailureReport),
 		chanCloseMsgs:      make(chan *closeMsg),
+		bumpCloseFeeReqs:   make(chan *bumpCloseFeeReq),
+		activeSplices:      make(map[lnwire.ChannelID]*spliceCtx),
+		splicingChans:      make(map[lnwire.ChannelID]struct{}),
+		localSpliceReqs:    make(chan *SpliceReq),
+		chanSpliceMsgs:     make(chan lnwire.Message),
+		spliceFinalizeReqs: make(chan *spliceFinalizeReq),
+		splicesConfirmed:   make(chan *spliceConfirmedMsg),
+		pendingSplices:     make(map[lnwire.ChannelID]*pendingSpliceState),
+		spliceInputSpends:  make(chan *spliceInputSpend),
+		spliceRetries:      make(chan lnwire.ChannelID),
 		resentChanSyncMsg:  make(map[lnwire.ChannelID]struct{}),
 		queueQuit:          make(chan struct{}),
 		quit:               make(chan struct{}),
@@ -588,6 +683,12 @@ func (p *Brontide) Start() error {
 	// Signal to any external processes that the peer is now active.
 	close(p.activeSignal)
 
+	// Hand the peer back the backup it asked us to store and ask it to
+	// store our own backup. This is done before any channel sync messages
+	// are sent, so that a peer that lost its channel state can recover
+	// before reestablishing its channels.
+	p.exchangePeerStorage()
+
 	// Now that the peer has started up, we send any channel sync messages
 	// that must be resent for borked channels.
 	if len(msgs) > 0 {
@@ -697,56 +798,41 @@ func (p *Brontide) loadActiveChannels(chans []*channeldb.OpenChannel) (
 			}
 
 			msgs = append(msgs, chanSync)
+
+			// If we broadcast an RBF cooperative closing
+			// transaction for the channel, we resume the close
+			// such that either party can still bump its fee.
+			if !rbfCloseResumable(dbChan) ||
+				!p.rbfCoopCloseSupported() {
+
+				continue
+			}
+
+			// Channels closed with the legacy protocol don't have
+			// a delivery script stored, so they can't be resumed.
+			shutdown, err := p.resumeRbfClose(lnChan)
+			switch {
+			case err == channeldb.ErrNoDeliveryScript:
+				continue
+
+			case err != nil:
+				peerLog.Errorf("Unable to resume RBF close of "+
+					"channel %v: %v", chanPoint, err)
+				continue
+			}
+
+			msgs = append(msgs, shutdown)
 			continue
 		}
 
 		// Before we register this new link with the HTLC Switch, we'll
 		// need to fetch its current link-layer forwarding policy from
 		// the database.
-		graph := p.cfg.ChannelGraph
-		info, p1, p2, err := graph.FetchChannelEdgesByOutpoint(chanPoint)
-		if err != nil && err != channeldb.ErrEdgeNotFound {
+		forwardingPolicy, err := p.fetchForwardingPolicy(chanPoint)
+		if err != nil {
 			return nil, err
 		}
 
-		// We'll filter out our policy from the directional channel
-		// edges based whom the edge connects to. If it doesn't connect
-		// to us, then we know that we were the one that advertised the
-		// policy.
-		//
-		// TODO(roasbeef): can add helper method to get policy for
-		// particular channel.
-		var selfPolicy *channeldb.ChannelEdgePolicy
-		if info != nil && bytes.Equal(info.NodeKey1Bytes[:],
-			p.cfg.ServerPubKey[:]) {
-
-			selfPolicy = p1
-		} else {
-			selfPolicy = p2
-		}
-
-		// If we don't yet have an advertised routing policy, then
-		// we'll use the current default, otherwise we'll translate the
-		// routing policy into a forwarding policy.
-		var forwardingPolicy *htlcswitch.ForwardingPolicy
-		if selfPolicy != nil {
-			forwardingPolicy = &htlcswitch.ForwardingPolicy{
-				MinHTLCOut:    selfPolicy.MinHTLC,
-				MaxHTLC:       selfPolicy.MaxHTLC,
-				BaseFee:       selfPolicy.FeeBaseMSat,
-				FeeRate:       selfPolicy.FeeProportionalMillionths,
-				TimeLockDelta: uint32(selfPolicy.TimeLockDelta),
-			}
-		} else {
-			peerLog.Warnf("Unable to find our forwarding policy "+
-				"for channel %v, using default values",
-				chanPoint)
-			forwardingPolicy = &p.cfg.RoutingPolicy
-		}
-
-		peerLog.Tracef("Using link policy of: %v",
-			spew.Sdump(forwardingPolicy))
-
 		// If the channel is pending, set the value to nil in the
 		// activeChannels map. This is done to signify that the channel is
 		// pending. We don't add the link to the switch here - it's the funding
@@ -761,6 +847,14 @@ func (p *Brontide) loadActiveChannels(chans []*channeldb.OpenChannel) (
 			continue
 		}
 
+		// Channels with pending splices keep operating until one of
+		// them is locked, so we only track their splice transactions.
+		if splices := lnChan.PendingSplices(); len(splices) > 0 {
+			p.resumePendingSplice(
+				chanID, lnChan, splices, forwardingPolicy,
+			)
+		}
+
 		// Subscribe to the set of on-chain events for this channel.
 		chainEvents, err := p.cfg.ChainArb.SubscribeChannelEvents(
 			*chanPoint,
@@ -786,6 +880,55 @@ func (p *Brontide) loadActiveChannels(chans []*channeldb.OpenChannel) (
 	return msgs, nil
 }
 
+// fetchForwardingPolicy fetches the link-layer forwarding policy of a channel
+// from our advertised routing policy in the graph. If we don't have one yet,
+// the default policy is returned.
+func (p *Brontide) fetchForwardingPolicy(
+	chanPoint *wire.OutPoint) (*htlcswitch.ForwardingPolicy, error) {
+
+	graph := p.cfg.ChannelGraph
+	info, p1, p2, err := graph.FetchChannelEdgesByOutpoint(chanPoint)
+	if err != nil && err != channeldb.ErrEdgeNotFound {
+		return nil, err
+	}
+
+	// We'll filter out our policy from the directional channel edges
+	// based whom the edge connects to. If it doesn't connect to us, then
+	// we know that we were the one that advertised the policy.
+	var selfPolicy *channeldb.ChannelEdgePolicy
+	if info != nil && bytes.Equal(info.NodeKey1Bytes[:],
+		p.cfg.ServerPubKey[:]) {
+
+		selfPolicy = p1
+	} else {
+		selfPolicy = p2
+	}
+
+	// If we don't yet have an advertised routing policy, then we'll use
+	// the current default, otherwise we'll translate the routing policy
+	// into a forwarding policy.
+	var forwardingPolicy *htlcswitch.ForwardingPolicy
+	if selfPolicy != nil {
+		forwardingPolicy = &htlcswitch.ForwardingPolicy{
+			MinHTLCOut:    selfPolicy.MinHTLC,
+			MaxHTLC:       selfPolicy.MaxHTLC,
+			BaseFee:       selfPolicy.FeeBaseMSat,
+			FeeRate:       selfPolicy.FeeProportionalMillionths,
+			TimeLockDelta: uint32(selfPolicy.TimeLockDelta),
+			InboundFee:    selfPolicy.InboundFee(),
+		}
+	} else {
+		peerLog.Warnf("Unable to find our forwarding policy for "+
+			"channel %v, using default values", chanPoint)
+		forwardingPolicy = &p.cfg.RoutingPolicy
+	}
+
+	peerLog.Tracef("Using link policy of: %v",
+		spew.Sdump(forwardingPolicy))
+
+	return forwardingPolicy, nil
+}
+
 // addLink creates and adds a new ChannelLink from the specified channel.
 func (p *Brontide) addLink(chanPoint *wire.OutPoint,
 	lnChan *lnwallet.LightningChannel,
@@ -839,6 +982,8 @@ func (p *Brontide) addLink(chanPoint *wire.OutPoint,
 		BestHeight:              p.cfg.Switch.BestHeight,
 		Circuits:                p.cfg.Switch.CircuitModifier(),
 		ForwardPackets:          p.cfg.InterceptSwitch.ForwardPackets,
+		InterceptExitHop:        p.cfg.InterceptSwitch.InterceptExitHop,
+		ForwardTrampoline:       p.cfg.ForwardTrampoline,
 		FwrdingPolicy:           *forwardingPolicy,
 		FeeEstimator:            p.cfg.FeeEstimator,
 		PreimageCache:           p.cfg.WitnessBeacon,
@@ -855,6 +1000,7 @@ func (p *Brontide) addLink(chanPoint *wire.OutPoint,
 		MaxFeeUpdateTimeout:     htlcswitch.DefaultMaxLinkFeeUpdateTimeout,
 		OutgoingCltvRejectDelta: p.cfg.OutgoingCltvRejectDelta,
 		TowerClient:             towerClient,
+		BackupCommitState:       p.cfg.BackupCommitState,
 		MaxOutgoingCltvExpiry:   p.cfg.MaxOutgoingCltvExpiry,
 		MaxFeeAllocation:        p.cfg.MaxChannelFeeAllocation,
 		MaxAnchorsCommitFeeRate: p.cfg.MaxAnchorsCommitFeeRate,
@@ -1241,17 +1387,15 @@ func waitUntilLinkActive(p *Brontide,
 // newChanMsgStream is used to create a msgStream between the peer and
 // particular channel link in the htlcswitch. We utilize additional
 // synchronization with the fundingManager to ensure we don't attempt to
-// dispatch a message to a channel before it is fully active. A reference to the
-// channel this stream forwards to is held in scope to prevent unnecessary
-// lookups.
+// dispatch a message to a channel before it is fully active. The link is
+// looked up for each message, as the link of a channel is replaced once the
+// channel has been quiescent for a splice.
 func newChanMsgStream(p *Brontide, cid lnwire.ChannelID) *msgStream {
-
-	var chanLink htlcswitch.ChannelUpdateHandler
-
 	apply := func(msg lnwire.Message) {
 		// This check is fine because if the link no longer exists, it will
 		// be removed from the activeChannels map and subsequent messages
 		// shouldn't reach the chan msg stream.
+		chanLink := p.fetchLinkFromKeyAndCid(cid)
 		if chanLink == nil {
 			chanLink = waitUntilLinkActive(p, cid)
 
@@ -1378,6 +1522,21 @@ out:
 			}
 		}
 
+		// Messages of a splice are handled by the channelManager,
+		// including the interactive tx and commitment messages that
+		// would otherwise be handled by the funding manager or the
+		// channel's link.
+		if p.isSpliceMsg(nextMsg) {
+			select {
+			case p.chanSpliceMsgs <- nextMsg:
+			case <-p.quit:
+				break out
+			}
+
+			idleTimer.Reset(idleTimeout)
+			continue
+		}
+
 		var (
 			targetChan   lnwire.ChannelID
 			isLinkUpdate bool
@@ -1405,6 +1564,15 @@ out:
 
 		case *lnwire.OpenChannel,
 			*lnwire.AcceptChannel,
+			*lnwire.OpenChannel2,
+			*lnwire.AcceptChannel2,
+			*lnwire.TxAddInput,
+			*lnwire.TxAddOutput,
+			*lnwire.TxRemoveInput,
+			*lnwire.TxRemoveOutput,
+			*lnwire.TxComplete,
+			*lnwire.TxSignatures,
+			*lnwire.TxAbort,
 			*lnwire.FundingCreated,
 			*lnwire.FundingSigned,
 			*lnwire.FundingLocked:
@@ -1423,6 +1591,18 @@ out:
 			case <-p.quit:
 				break out
 			}
+		case *lnwire.ClosingComplete:
+			select {
+			case p.chanCloseMsgs <- &closeMsg{msg.ChannelID, msg}:
+			case <-p.quit:
+				break out
+			}
+		case *lnwire.ClosingSig:
+			select {
+			case p.chanCloseMsgs <- &closeMsg{msg.ChannelID, msg}:
+			case <-p.quit:
+				break out
+			}
 
 		case *lnwire.Error:
 			targetChan = msg.ChanID
@@ -1470,6 +1650,28 @@ out:
 				peerLog.Errorf("peer: %v, %v", p, err)
 			}
 
+		case *lnwire.OnionMessage:
+			err := p.handleOnionMessage(msg)
+			if err != nil {
+				p.storeError(err)
+				peerLog.Errorf("peer: %v, unable to handle "+
+					"onion message: %v", p, err)
+			}
+
+		case *lnwire.PeerStorage:
+			err := p.handlePeerStorage(msg)
+			if err != nil {
+				peerLog.Errorf("peer: %v, unable to store peer "+
+					"storage blob: %v", p, err)
+			}
+
+		case *lnwire.PeerStorageRetrieval:
+			err := p.handlePeerStorageRetrieval(msg)
+			if err != nil {
+				peerLog.Errorf("peer: %v, unable to handle "+
+					"peer storage retrieval: %v", p, err)
+			}
+
 		default:
 			// If the message we received is unknown to us, store
 			// the type to track the failure.
@@ -1518,6 +1720,89 @@ func (p *Brontide) handleCustomMessage(msg *lnwire.Custom) error {
 	return p.cfg.HandleCustomMessage(p.PubKey(), msg)
 }
 
+// handleOnionMessage hands the given onion message to the onion message
+// handler if one is registered.
+func (p *Brontide) handleOnionMessage(msg *lnwire.OnionMessage) error {
+	if p.cfg.HandleOnionMessage == nil {
+		return errors.New("no onion message handler registered")
+	}
+
+	return p.cfg.HandleOnionMessage(p.PubKey(), msg)
+}
+
+// exchangePeerStorage sends the peer the backup blob it previously asked us to
+// store, if any, and asks the peer to store our latest backup if it offers
+// storage.
+func (p *Brontide) exchangePeerStorage() {
+	var msgs []lnwire.Message
+
+	if p.cfg.FetchPeerStorage != nil {
+		blob, err := p.cfg.FetchPeerStorage(p.PubKey())
+		if err != nil {
+			peerLog.Errorf("Unable to fetch peer storage blob of "+
+				"peer %v: %v", p, err)
+		} else if len(blob) > 0 {
+			msgs = append(msgs, lnwire.NewPeerStorageRetrieval(blob))
+		}
+	}
+
+	if p.cfg.OurPeerStorage != nil &&
+		p.remoteFeatures.HasFeature(lnwire.ProvideStorageOptional) {
+
+		if blob := p.cfg.OurPeerStorage(); len(blob) > 0 {
+			msgs = append(msgs, lnwire.NewPeerStorage(blob))
+		}
+	}
+
+	if len(msgs) == 0 {
+		return
+	}
+
+	if err := p.SendMessage(false, msgs...); err != nil {
+		peerLog.Warnf("Failed sending peer storage messages to peer "+
+			"%v: %v", p, err)
+	}
+}
+
+// handlePeerStorage stores the backup blob the peer asked us to hold on its
+// behalf. As a dos mitigation, blobs are only stored for peers we have an
+// active channel with and only if they fit within our quota.
+func (p *Brontide) handlePeerStorage(msg *lnwire.PeerStorage) error {
+	if p.cfg.StorePeerStorage == nil {
+		peerLog.Debugf("Peer storage disabled, ignoring blob of peer %v",
+			p)
+		return nil
+	}
+
+	if !p.hasActiveChannels() {
+		peerLog.Debugf("No channels with peer %v, ignoring peer "+
+			"storage blob", p)
+		return nil
+	}
+
+	if len(msg.Blob) > p.cfg.PeerStorageQuota {
+		peerLog.Warnf("Peer storage blob of peer %v exceeds quota: "+
+			"%v > %v", p, len(msg.Blob), p.cfg.PeerStorageQuota)
+		return nil
+	}
+
+	return p.cfg.StorePeerStorage(p.PubKey(), msg.Blob)
+}
+
+// handlePeerStorageRetrieval hands the backup blob the peer returned to us to
+// the retrieval handler if one is registered.
+func (p *Brontide) handlePeerStorageRetrieval(
+	msg *lnwire.PeerStorageRetrieval) error {
+
+	if p.cfg.HandlePeerStorageRetrieval == nil {
+		peerLog.Debugf("Ignoring peer storage retrieval from peer %v",
+			p)
+		return nil
+	}
+
+	return p.cfg.HandlePeerStorageRetrieval(p.PubKey(), msg.Blob)
+}
+
 // isActiveChannel returns true if the provided channel id is active, otherwise
 // returns false.
 func (p *Brontide) isActiveChannel(chanID lnwire.ChannelID) bool {
@@ -1527,28 +1812,30 @@ func (p *Brontide) isActiveChannel(chanID lnwire.ChannelID) bool {
 	return ok
 }
 
-// storeError stores an error in our peer's buffer of recent errors with the
-// current timestamp. Errors are only stored if we have at least one active
-// channel with the peer to mitigate a dos vector where a peer costlessly
-// connects to us and spams us with errors.
-func (p *Brontide) storeError(err error) {
-	var haveChannels bool
-
+// hasActiveChannels returns true if we have at least one active, non-pending
+// channel with the peer.
+func (p *Brontide) hasActiveChannels() bool {
 	p.activeChanMtx.RLock()
+	defer p.activeChanMtx.RUnlock()
+
 	for _, channel := range p.activeChannels {
 		// Pending channels will be nil in the activeChannels map.
-		if channel == nil {
-			continue
+		if channel != nil {
+			return true
 		}
-
-		haveChannels = true
-		break
 	}
-	p.activeChanMtx.RUnlock()
 
+	return false
+}
+
+// storeError stores an error in our peer's buffer of recent errors with the
+// current timestamp. Errors are only stored if we have at least one active
+// channel with the peer to mitigate a dos vector where a peer costlessly
+// connects to us and spams us with errors.
+func (p *Brontide) storeError(err error) {
 	// If we do not have any active channels with the peer, we do not store
 	// errors as a dos mitigation.
-	if !haveChannels {
+	if !p.hasActiveChannels() {
 		peerLog.Tracef("no channels with peer: %v, not storing err", p)
 		return
 	}
@@ -1614,6 +1901,45 @@ func messageSummary(msg lnwire.Message) string {
 			msg.PendingChannelID[:], msg.ChannelReserve, msg.CsvDelay,
 			msg.MinAcceptDepth)
 
+	case *lnwire.OpenChannel2:
+		return fmt.Sprintf("temp_chan_id=%x, chain=%v, csv=%v, amt=%v, "+
+			"funding_fee_rate=%v, flags=%v",
+			msg.PendingChannelID[:], msg.ChainHash, msg.CsvDelay,
+			msg.FundingAmount, msg.FundingFeePerKiloWeight,
+			msg.ChannelFlags)
+
+	case *lnwire.AcceptChannel2:
+		return fmt.Sprintf("temp_chan_id=%x, amt=%v, csv=%v, "+
+			"num_confs=%v", msg.PendingChannelID[:],
+			msg.FundingAmount, msg.CsvDelay, msg.MinAcceptDepth)
+
+	case *lnwire.TxAddInput:
+		return fmt.Sprintf("chan_id=%v, serial_id=%v, prev_out_index=%v",
+			msg.ChannelID, msg.SerialID, msg.PrevTxOutIndex)
+
+	case *lnwire.TxAddOutput:
+		return fmt.Sprintf("chan_id=%v, serial_id=%v, amt=%v",
+			msg.ChannelID, msg.SerialID, msg.Amount)
+
+	case *lnwire.TxRemoveInput:
+		return fmt.Sprintf("chan_id=%v, serial_id=%v", msg.ChannelID,
+			msg.SerialID)
+
+	case *lnwire.TxRemoveOutput:
+		return fmt.Sprintf("chan_id=%v, serial_id=%v", msg.ChannelID,
+			msg.SerialID)
+
+	case *lnwire.TxComplete:
+		return fmt.Sprintf("chan_id=%v", msg.ChannelID)
+
+	case *lnwire.TxSignatures:
+		return fmt.Sprintf("chan_id=%v, txid=%v, num_witnesses=%v",
+			msg.ChannelID, msg.TxID, len(msg.Witnesses))
+
+	case *lnwire.TxAbort:
+		return fmt.Sprintf("chan_id=%v, reason=%v", msg.ChannelID,
+			msg.Error())
+
 	case *lnwire.FundingCreated:
 		return fmt.Sprintf("temp_chan_id=%x, chan_point=%v",
 			msg.PendingChannelID[:], msg.FundingPoint)
@@ -1633,6 +1959,14 @@ func messageSummary(msg lnwire.Message) string {
 		return fmt.Sprintf("chan_id=%v, fee_sat=%v", msg.ChannelID,
 			msg.FeeBroneess)
 
+	case *lnwire.ClosingComplete:
+		return fmt.Sprintf("chan_id=%v, fee_sat=%v, locktime=%v",
+			msg.ChannelID, msg.FeeBroneess, msg.LockTime)
+
+	case *lnwire.ClosingSig:
+		return fmt.Sprintf("chan_id=%v, fee_sat=%v, locktime=%v",
+			msg.ChannelID, msg.FeeBroneess, msg.LockTime)
+
 	case *lnwire.UpdateAddHTLC:
 		return fmt.Sprintf("chan_id=%v, id=%v, amt=%v, expiry=%v, hash=%x",
 			msg.ChanID, msg.ID, msg.Amount, msg.Expiry, msg.PaymentHash[:])
@@ -1720,6 +2054,17 @@ func messageSummary(msg lnwire.Message) string {
 
 	case *lnwire.Custom:
 		return fmt.Sprintf("type=%d", msg.Type)
+
+	case *lnwire.OnionMessage:
+		return fmt.Sprintf("blinding_point=%x, onion_len=%d",
+			msg.BlindingPoint.SerializeCompressed(),
+			len(msg.OnionBlob))
+
+	case *lnwire.PeerStorage:
+		return fmt.Sprintf("blob_len=%d", len(msg.Blob))
+
+	case *lnwire.PeerStorageRetrieval:
+		return fmt.Sprintf("blob_len=%d", len(msg.Blob))
 	}
 
 	return ""
@@ -1780,6 +2125,20 @@ func (p *Brontide) logWireMessage(msg lnwire.Message, read bool) {
 		m.DelayedPaymentPoint.Curve = nil
 		m.HtlcPoint.Curve = nil
 		m.FirstCommitmentPoint.Curve = nil
+	case *lnwire.AcceptChannel2:
+		m.FundingKey.Curve = nil
+		m.RevocationPoint.Curve = nil
+		m.PaymentPoint.Curve = nil
+		m.DelayedPaymentPoint.Curve = nil
+		m.HtlcPoint.Curve = nil
+		m.FirstCommitmentPoint.Curve = nil
+	case *lnwire.OpenChannel2:
+		m.FundingKey.Curve = nil
+		m.RevocationPoint.Curve = nil
+		m.PaymentPoint.Curve = nil
+		m.DelayedPaymentPoint.Curve = nil
+		m.HtlcPoint.Curve = nil
+		m.FirstCommitmentPoint.Curve = nil
 	case *lnwire.FundingLocked:
 		m.NextPerCommitmentPoint.Curve = nil
 	}
@@ -2328,6 +2687,42 @@ out:
 		case closeMsg := <-p.chanCloseMsgs:
 			p.handleCloseMsg(closeMsg)
 
+		// We've just received a local request to bump the fee of an
+		// RBF cooperative close, so we'll propose a new closing
+		// transaction to the remote peer.
+		case req := <-p.bumpCloseFeeReqs:
+			p.handleBumpCloseFeeReq(req)
+
+		// We've just received a local request to splice a channel,
+		// which will make the channel quiescent and kick off the
+		// splice negotiation.
+		case req := <-p.localSpliceReqs:
+			p.handleLocalSpliceReq(req)
+
+		// We've received a splice related message from the remote
+		// peer, we'll use it to advance the splice state machine.
+		case msg := <-p.chanSpliceMsgs:
+			p.handleSpliceMsg(msg)
+
+		// The inputs of a PSBT funded splice have been signed.
+		case req := <-p.spliceFinalizeReqs:
+			p.handleSpliceFinalizeReq(req)
+
+		// A pending splice of a channel has confirmed, so it can be
+		// locked once the remote party confirmed it as well.
+		case msg := <-p.splicesConfirmed:
+			p.handleSpliceConfirmed(msg)
+
+		// An input of a pending splice transaction has been spent,
+		// possibly by another transaction.
+		case msg := <-p.spliceInputSpends:
+			p.handleSpliceInputSpend(msg)
+
+		// We try again to lock or abort the pending splices of a
+		// channel.
+		case chanID := <-p.spliceRetries:
+			p.handleSpliceRetry(chanID)
+
 		// The channel reannounce delay has elapsed, broadcast the
 		// reenabled channel updates to the network. This should only
 		// fire once, so we set the reenableTimeout channel to nil to
@@ -2423,8 +2818,17 @@ func (p *Brontide) reenableActiveChannels() {
 func (p *Brontide) fetchActiveChanCloser(chanID lnwire.ChannelID) (
 	*chancloser.ChanCloser, error) {
 
-	// First, we'll ensure that we actually know of the target channel. If
-	// not, we'll ignore this message.
+	// If there's an existing state machine for the channel, we return it
+	// right away. The channel of an RBF cooperative close is no longer
+	// active once its first closing transaction was broadcast, but its
+	// state machine continues to process replacements of it.
+	chanCloser, ok := p.activeChanCloses[chanID]
+	if ok {
+		return chanCloser, nil
+	}
+
+	// Otherwise, we'll ensure that we actually know of the target channel.
+	// If not, we'll ignore this message.
 	p.activeChanMtx.RLock()
 	channel, ok := p.activeChannels[chanID]
 	p.activeChanMtx.RUnlock()
@@ -2435,77 +2839,97 @@ func (p *Brontide) fetchActiveChanCloser(chanID lnwire.ChannelID) (
 		return nil, ErrChannelNotFound
 	}
 
-	// We'll attempt to look up the matching state machine, if we can't
-	// find one then this means that the remote party is initiating a
-	// cooperative channel closure.
-	chanCloser, ok := p.activeChanCloses[chanID]
-	if !ok {
-		// Optimistically try a link shutdown, erroring out if it
-		// failed.
-		if err := p.tryLinkShutdown(chanID); err != nil {
-			peerLog.Errorf("failed link shutdown: %v", err)
-			return nil, err
-		}
+	// As we didn't find a matching state machine, this means that the
+	// remote party is initiating a cooperative channel closure.
+	// Optimistically try a link shutdown, erroring out if it failed.
+	if err := p.tryLinkShutdown(chanID); err != nil {
+		peerLog.Errorf("failed link shutdown: %v", err)
+		return nil, err
+	}
 
-		// We'll create a valid closing state machine in order to
-		// respond to the initiated cooperative channel closure. First,
-		// we set the delivery script that our funds will be paid out
-		// to. If an upfront shutdown script was set, we will use it.
-		// Otherwise, we get a fresh delivery script.
-		//
-		// TODO: Expose option to allow upfront shutdown script from
-		// watch-only accounts.
-		deliveryScript := channel.LocalUpfrontShutdownScript()
-		if len(deliveryScript) == 0 {
-			var err error
-			deliveryScript, err = p.genDeliveryScript()
-			if err != nil {
-				peerLog.Errorf("unable to gen delivery script: %v", err)
-				return nil, fmt.Errorf("close addr unavailable")
-			}
+	// We'll create a valid closing state machine in order to respond to
+	// the initiated cooperative channel closure. First, we set the
+	// delivery script that our funds will be paid out to. If an upfront
+	// shutdown script was set, we will use it. Otherwise, we get a fresh
+	// delivery script.
+	//
+	// TODO: Expose option to allow upfront shutdown script from
+	// watch-only accounts.
+	deliveryScript := channel.LocalUpfrontShutdownScript()
+	if len(deliveryScript) == 0 {
+		var err error
+		deliveryScript, err = p.genDeliveryScript()
+		if err != nil {
+			peerLog.Errorf("unable to gen delivery script: %v", err)
+			return nil, fmt.Errorf("close addr unavailable")
 		}
+	}
 
-		// In order to begin fee negotiations, we'll first compute our
-		// target ideal fee-per-kw.
-		feePerKw, err := p.cfg.FeeEstimator.EstimateFeePerKW(
-			p.cfg.CoopCloseTargetConfs,
-		)
-		if err != nil {
-			peerLog.Errorf("unable to query fee estimator: %v", err)
+	// In order to begin fee negotiations, we'll first compute our target
+	// ideal fee-per-kw.
+	feePerKw, err := p.cfg.FeeEstimator.EstimateFeePerKW(
+		p.cfg.CoopCloseTargetConfs,
+	)
+	if err != nil {
+		peerLog.Errorf("unable to query fee estimator: %v", err)
 
-			return nil, fmt.Errorf("unable to estimate fee")
-		}
+		return nil, fmt.Errorf("unable to estimate fee")
+	}
 
-		_, startingHeight, err := p.cfg.ChainIO.GetBestBlock()
-		if err != nil {
-			peerLog.Errorf("unable to obtain best block: %v", err)
-			return nil, fmt.Errorf("cannot obtain best block")
-		}
-
-		chanCloser = chancloser.NewChanCloser(
-			chancloser.ChanCloseCfg{
-				Channel:     channel,
-				BroadcastTx: p.cfg.Wallet.PublishTransaction,
-				DisableChannel: func(chanPoint wire.OutPoint) error {
-					return p.cfg.ChanStatusMgr.RequestDisable(chanPoint, false)
-				},
-				Disconnect: func() error {
-					return p.cfg.DisconnectPeer(p.IdentityKey())
-				},
-				Quit: p.quit,
-			},
-			deliveryScript,
-			feePerKw,
-			uint32(startingHeight),
-			nil,
-			false,
-		)
-		p.activeChanCloses[chanID] = chanCloser
+	_, startingHeight, err := p.cfg.ChainIO.GetBestBlock()
+	if err != nil {
+		peerLog.Errorf("unable to obtain best block: %v", err)
+		return nil, fmt.Errorf("cannot obtain best block")
 	}
 
+	chanCloser = p.newChanCloser(
+		channel, deliveryScript, feePerKw, uint32(startingHeight), nil,
+		false,
+	)
+	p.activeChanCloses[chanID] = chanCloser
+
 	return chanCloser, nil
 }
 
+// newChanCloser creates a closing state machine for the given channel. The
+// RBF cooperative close protocol is used if both we and the peer support it.
+func (p *Brontide) newChanCloser(channel *lnwallet.LightningChannel,
+	deliveryScript lnwire.DeliveryAddress,
+	idealFeePerKw chainfee.SatPerKWeight, negotiationHeight uint32,
+	closeReq *htlcswitch.ChanClose,
+	locallyInitiated bool) *chancloser.ChanCloser {
+
+	return chancloser.NewChanCloser(
+		chancloser.ChanCloseCfg{
+			Channel:     channel,
+			BroadcastTx: p.cfg.Wallet.PublishTransaction,
+			DisableChannel: func(chanPoint wire.OutPoint) error {
+				return p.cfg.ChanStatusMgr.RequestDisable(
+					chanPoint, false,
+				)
+			},
+			Disconnect: func() error {
+				return p.cfg.DisconnectPeer(p.IdentityKey())
+			},
+			RbfCoopClose: p.rbfCoopCloseSupported(),
+			BestHeight: func() (uint32, error) {
+				_, height, err := p.cfg.ChainIO.GetBestBlock()
+				if err != nil {
+					return 0, err
+				}
+
+				return uint32(height), nil
+			},
+			Quit: p.quit,
+		},
+		deliveryScript,
+		idealFeePerKw,
+		negotiationHeight,
+		closeReq,
+		locallyInitiated,
+	)
+}
+
 // chooseDeliveryScript takes two optionally set shutdown scripts and returns
 // a suitable script to close out to. This may be nil if neither script is
 // set. If both scripts are set, this function will error if they do not match.
@@ -2607,23 +3031,9 @@ func (p *Brontide) handleLocalCloseReq(req *htlcswitch.ChanClose) {
 			return
 		}
 
-		chanCloser := chancloser.NewChanCloser(
-			chancloser.ChanCloseCfg{
-				Channel:     channel,
-				BroadcastTx: p.cfg.Wallet.PublishTransaction,
-				DisableChannel: func(chanPoint wire.OutPoint) error {
-					return p.cfg.ChanStatusMgr.RequestDisable(chanPoint, false)
-				},
-				Disconnect: func() error {
-					return p.cfg.DisconnectPeer(p.IdentityKey())
-				},
-				Quit: p.quit,
-			},
-			deliveryScript,
-			req.TargetFeePerKw,
-			uint32(startingHeight),
-			req,
-			true,
+		chanCloser := p.newChanCloser(
+			channel, deliveryScript, req.TargetFeePerKw,
+			uint32(startingHeight), req, true,
 		)
 		p.activeChanCloses[chanID] = chanCloser
 
@@ -2829,6 +3239,34 @@ func (p *Brontide) finalizeChanClosure(chanCloser *chancloser.ChanCloser) {
 		}
 	}
 
+	// The closing transaction of an RBF cooperative close may be replaced
+	// by either party, so we wait for the funding output to be spent
+	// instead of waiting for this particular transaction to confirm.
+	if chanCloser.RbfCoopClose() {
+		go waitForRbfClose(chanCloser.NegotiationHeight(), notifier,
+			errChan, chanCloser.Channel().State(),
+			func(closingTxid chainhash.Hash) {
+
+				// Respond to the local subsystem which
+				// requested the channel closure. As we don't
+				// block on notifying replacements, the
+				// subsystem may have stopped listening.
+				if closeReq == nil {
+					return
+				}
+
+				select {
+				case closeReq.Updates <- &ChannelCloseUpdate{
+					ClosingTxid: closingTxid[:],
+					Success:     true,
+				}:
+				default:
+				}
+			})
+
+		return
+	}
+
 	go WaitForChanToClose(chanCloser.NegotiationHeight(), notifier, errChan,
 		chanPoint, &closingTxid, closingTx.TxOut[0].PkScript, func() {
 
@@ -3180,6 +3618,10 @@ func (p *Brontide) handleCloseMsg(msg *closeMsg) {
 		return
 	}
 
+	// In an RBF cooperative close, the closing transaction may already be
+	// complete, in which case we'll note it to detect its replacement.
+	prevClosingTx, prevErr := chanCloser.ClosingTx()
+
 	// Next, we'll process the next message using the target state machine.
 	// We'll either continue negotiation, or halt.
 	msgs, closeFin, err := chanCloser.ProcessCloseMsg(
@@ -3189,6 +3631,13 @@ func (p *Brontide) handleCloseMsg(msg *closeMsg) {
 		err := fmt.Errorf("unable to process close msg: %v", err)
 		peerLog.Error(err)
 
+		// If a closing transaction was already broadcast, a failed
+		// replacement doesn't affect the close, so we keep the state
+		// machine around for further replacements.
+		if prevErr == nil {
+			return
+		}
+
 		// As the negotiations failed, we'll reset the channel state machine to
 		// ensure we act to on-chain events as normal.
 		chanCloser.Channel().ResetState()
@@ -3207,8 +3656,16 @@ func (p *Brontide) handleCloseMsg(msg *closeMsg) {
 	}
 
 	// If we haven't finished close negotiations, then we'll continue as we
-	// can't yet finalize the closure.
+	// can't yet finalize the closure. An RBF closing transaction that
+	// replaced the prior one is reported to the requester of the close.
 	if !closeFin {
+		closingTx, err := chanCloser.ClosingTx()
+		if prevErr == nil && err == nil &&
+			closingTx.TxHash() != prevClosingTx.TxHash() {
+
+			notifyRbfCloseReplaced(chanCloser, closingTx.TxHash())
+		}
+
 		return
 	}
 
@@ -11,6 +11,7 @@ import (
 	"github.com/brronsuite/broln/contractcourt"
 	"github.com/brronsuite/broln/htlcswitch"
 	"github.com/brronsuite/broln/lntest/mock"
+	"github.com/brronsuite/broln/lnwallet"
 	"github.com/brronsuite/broln/lnwallet/chancloser"
 	"github.com/brronsuite/broln/lnwire"
 	"github.com/brronsuite/broln/pool"
@@ -676,6 +677,152 @@ func TestPeerChannelClosureFeeNegotiationsInitiator(t *testing.T) {
 	notifier.ConfChan <- &chainntnfs.TxConfirmation{}
 }
 
+// TestPeerRbfCoopClose tests an RBF cooperative close initiated by the remote
+// party, and that the fee of the closing transaction can be bumped by us
+// afterwards.
+func TestPeerRbfCoopClose(t *testing.T) {
+	t.Parallel()
+
+	notifier := &mock.ChainNotifier{
+		SpendChan: make(chan *chainntnfs.SpendDetail),
+		EpochChan: make(chan *chainntnfs.BlockEpoch),
+		ConfChan:  make(chan *chainntnfs.TxConfirmation),
+	}
+	broadcastTxChan := make(chan *wire.MsgTx)
+
+	mockSwitch := &mockMessageSwitch{}
+
+	alicePeer, bobChan, cleanUp, err := createTestPeer(
+		notifier, broadcastTxChan, noUpdate, mockSwitch,
+	)
+	require.NoError(t, err, "unable to create test channels")
+	defer cleanUp()
+
+	// Both Alice and Bob support the RBF cooperative close protocol.
+	rbfFeatures := lnwire.NewRawFeatureVector(lnwire.RbfCoopCloseOptional)
+	alicePeer.cfg.Features = lnwire.NewFeatureVector(
+		rbfFeatures, lnwire.Features,
+	)
+	alicePeer.remoteFeatures = lnwire.NewFeatureVector(
+		rbfFeatures, lnwire.Features,
+	)
+
+	chanID := lnwire.NewChanIDFromOutPoint(bobChan.ChannelPoint())
+
+	mockLink := newMockUpdateHandler(chanID)
+	mockSwitch.links = append(mockSwitch.links, mockLink)
+
+	receiveMsg := func() lnwire.Message {
+		select {
+		case outMsg := <-alicePeer.outgoingQueue:
+			return outMsg.msg
+		case <-time.After(timeout):
+			t.Fatalf("did not receive message")
+			return nil
+		}
+	}
+	receiveTx := func() *wire.MsgTx {
+		select {
+		case tx := <-broadcastTxChan:
+			return tx
+		case <-time.After(timeout):
+			t.Fatalf("closing tx not broadcast")
+			return nil
+		}
+	}
+
+	// We send a shutdown request to Alice, who answers with a Shutdown
+	// message. As we initiated the shutdown, she doesn't propose a
+	// closing transaction.
+	alicePeer.chanCloseMsgs <- &closeMsg{
+		cid: chanID,
+		msg: lnwire.NewShutdown(chanID, dummyDeliveryScript),
+	}
+
+	msg := receiveMsg()
+	shutdownMsg, ok := msg.(*lnwire.Shutdown)
+	require.True(t, ok, "expected Shutdown message, got %T", msg)
+	respDeliveryScript := shutdownMsg.Address
+
+	// We propose a closing transaction paying the fee from our output,
+	// which Alice signs and broadcasts.
+	const (
+		bobFee      = bronutil.Amount(1000)
+		bobLockTime = uint32(100)
+	)
+	bobSig, bobCloseTx, err := bobChan.CreateRbfCloseProposal(
+		bobFee, bobLockTime, dummyDeliveryScript, respDeliveryScript,
+		true,
+	)
+	require.NoError(t, err, "error creating close proposal")
+	parsedSig, err := lnwire.NewSigFromSignature(bobSig)
+	require.NoError(t, err, "error parsing signature")
+
+	alicePeer.chanCloseMsgs <- &closeMsg{
+		cid: chanID,
+		msg: &lnwire.ClosingComplete{
+			ChannelID:   chanID,
+			FeeBroneess: bobFee,
+			LockTime:    bobLockTime,
+			Signature:   parsedSig,
+		},
+	}
+
+	closeTx := receiveTx()
+	require.Equal(t, bobCloseTx.TxHash(), closeTx.TxHash())
+
+	msg = receiveMsg()
+	closingSig, ok := msg.(*lnwire.ClosingSig)
+	require.True(t, ok, "expected ClosingSig message, got %T", msg)
+	require.Equal(t, bobFee, closingSig.FeeBroneess)
+
+	// A fee bump that doesn't exceed the current fee is rejected.
+	_, err = alicePeer.BumpCloseFee(*bobChan.ChannelPoint(), 1)
+	require.ErrorIs(t, err, chancloser.ErrCloseFeeTooLow)
+
+	// Alice now bumps the fee of the closing transaction, proposing a new
+	// one paying the fee from her output.
+	bumpTxid, err := alicePeer.BumpCloseFee(
+		*bobChan.ChannelPoint(), 10000,
+	)
+	require.NoError(t, err, "unable to bump close fee")
+
+	msg = receiveMsg()
+	closingComplete, ok := msg.(*lnwire.ClosingComplete)
+	require.True(t, ok, "expected ClosingComplete message, got %T", msg)
+	require.Greater(t, int64(closingComplete.FeeBroneess), int64(bobFee))
+
+	// We sign Alice's proposal, after which she broadcasts the
+	// replacement.
+	bobSig, bobCloseTx, err = bobChan.CreateRbfCloseProposal(
+		closingComplete.FeeBroneess, closingComplete.LockTime,
+		dummyDeliveryScript, respDeliveryScript, false,
+	)
+	require.NoError(t, err, "error creating close proposal")
+	require.Equal(t, *bumpTxid, bobCloseTx.TxHash())
+	parsedSig, err = lnwire.NewSigFromSignature(bobSig)
+	require.NoError(t, err, "error parsing signature")
+
+	alicePeer.chanCloseMsgs <- &closeMsg{
+		cid: chanID,
+		msg: &lnwire.ClosingSig{
+			ChannelID:   chanID,
+			FeeBroneess: closingComplete.FeeBroneess,
+			LockTime:    closingComplete.LockTime,
+			Signature:   parsedSig,
+		},
+	}
+
+	closeTx = receiveTx()
+	require.Equal(t, *bumpTxid, closeTx.TxHash())
+
+	// Alice should be waiting for the funding output to be spent by
+	// either closing transaction.
+	notifier.SpendChan <- &chainntnfs.SpendDetail{
+		SpenderTxHash: bumpTxid,
+	}
+}
+
 // TestChooseDeliveryScript tests that chooseDeliveryScript correctly errors
 // when upfront and user set scripts that do not match are provided, allows
 // matching values and returns appropriate values in the case where one or none
@@ -1155,3 +1302,157 @@ func TestPeerCustomMessage(t *testing.T) {
 	require.Equal(t, remoteKey, receivedCustom.peer)
 	require.Equal(t, receivedCustomMsg, &receivedCustom.msg)
 }
+
+// TestPeerStorage tests that the peer hands back the blob it stores for the
+// remote peer and our own backup after the init exchange, and that it only
+// stores blobs of peers it has an active channel with within the quota.
+func TestPeerStorage(t *testing.T) {
+	t.Parallel()
+
+	// Set up node Alice.
+	alicePath, err := ioutil.TempDir("", "alicedb")
+	require.NoError(t, err)
+
+	dbAlice, err := channeldb.Open(alicePath)
+	require.NoError(t, err)
+
+	aliceKey, err := bronec.NewPrivateKey(bronec.S256())
+	require.NoError(t, err)
+
+	writeBufferPool := pool.NewWriteBuffer(
+		pool.DefaultWriteBufferGCInterval,
+		pool.DefaultWriteBufferExpiryInterval,
+	)
+
+	writePool := pool.NewWrite(
+		writeBufferPool, 1, timeout,
+	)
+	require.NoError(t, writePool.Start())
+
+	readBufferPool := pool.NewReadBuffer(
+		pool.DefaultReadBufferGCInterval,
+		pool.DefaultReadBufferExpiryInterval,
+	)
+
+	readPool := pool.NewRead(
+		readBufferPool, 1, timeout,
+	)
+	require.NoError(t, readPool.Start())
+
+	mockConn := newMockConn(t, 1)
+
+	remoteKey := [33]byte{8}
+
+	notifier := &mock.ChainNotifier{
+		SpendChan: make(chan *chainntnfs.SpendDetail),
+		EpochChan: make(chan *chainntnfs.BlockEpoch),
+		ConfChan:  make(chan *chainntnfs.TxConfirmation),
+	}
+
+	var (
+		theirBlob  = []byte{1, 2, 3}
+		ourBlob    = []byte{4, 5, 6}
+		storedBlob []byte
+	)
+
+	alicePeer := NewBrontide(Config{
+		PubKeyBytes: remoteKey,
+		ChannelDB:   dbAlice.ChannelStateDB(),
+		Addr: &lnwire.NetAddress{
+			IdentityKey: aliceKey.PubKey(),
+		},
+		PrunePersistentPeerConnection: func([33]byte) {},
+		Features:                      lnwire.EmptyFeatureVector(),
+		LegacyFeatures:                lnwire.EmptyFeatureVector(),
+		WritePool:                     writePool,
+		ReadPool:                      readPool,
+		Conn:                          mockConn,
+		ChainNotifier:                 notifier,
+		PeerStorageQuota:              4,
+		StorePeerStorage: func(peer [33]byte, blob []byte) error {
+			require.Equal(t, remoteKey, peer)
+			storedBlob = blob
+			return nil
+		},
+		FetchPeerStorage: func(peer [33]byte) ([]byte, error) {
+			require.Equal(t, remoteKey, peer)
+			return theirBlob, nil
+		},
+		OurPeerStorage: func() []byte {
+			return ourBlob
+		},
+		PongBuf: make([]byte, lnwire.MaxPongBytes),
+	})
+
+	// Set up the init sequence. The remote peer offers to store our
+	// backup.
+	go func() {
+		// Read init message.
+		<-mockConn.writtenMessages
+
+		// Write the init reply message.
+		initReplyMsg := lnwire.NewInitMessage(
+			lnwire.NewRawFeatureVector(
+				lnwire.DataLossProtectRequired,
+				lnwire.ProvideStorageOptional,
+			),
+			lnwire.NewRawFeatureVector(),
+		)
+		var b bytes.Buffer
+		_, err = lnwire.WriteMessage(&b, initReplyMsg, 0)
+		assert.NoError(t, err)
+
+		mockConn.readMessages <- b.Bytes()
+	}()
+
+	// Start the peer.
+	require.NoError(t, alicePeer.Start())
+
+	// The peer should hand back the blob it stores for the remote peer,
+	// followed by our own backup.
+	var b bytes.Buffer
+	_, err = lnwire.WriteMessage(
+		&b, lnwire.NewPeerStorageRetrieval(theirBlob), 0,
+	)
+	require.NoError(t, err)
+	mockConn.assertWrite(b.Bytes())
+
+	b.Reset()
+	_, err = lnwire.WriteMessage(&b, lnwire.NewPeerStorage(ourBlob), 0)
+	require.NoError(t, err)
+	mockConn.assertWrite(b.Bytes())
+
+	// As we don't have a channel with the remote peer, its blob shouldn't
+	// be stored.
+	blob := []byte{7, 8, 9}
+	require.NoError(t, alicePeer.handlePeerStorage(
+		lnwire.NewPeerStorage(blob),
+	))
+	require.Nil(t, storedBlob)
+
+	// Pending channels don't count either.
+	chanID := lnwire.ChannelID{1}
+	alicePeer.activeChanMtx.Lock()
+	alicePeer.activeChannels[chanID] = nil
+	alicePeer.activeChanMtx.Unlock()
+
+	require.NoError(t, alicePeer.handlePeerStorage(
+		lnwire.NewPeerStorage(blob),
+	))
+	require.Nil(t, storedBlob)
+
+	// Once the channel is active, blobs within the quota are stored.
+	alicePeer.activeChanMtx.Lock()
+	alicePeer.activeChannels[chanID] = &lnwallet.LightningChannel{}
+	alicePeer.activeChanMtx.Unlock()
+
+	require.NoError(t, alicePeer.handlePeerStorage(
+		lnwire.NewPeerStorage([]byte{1, 2, 3, 4, 5}),
+	))
+	require.Nil(t, storedBlob)
+
+	require.NoError(t, alicePeer.handlePeerStorage(
+		lnwire.NewPeerStorage(blob),
+	))
+	require.Equal(t, blob, storedBlob)
+}
@@ -0,0 +1,208 @@
+package peer
+
+import (
+	"errors"
+
+	"github.com/brronsuite/broln/chainntnfs"
+	"github.com/brronsuite/broln/channeldb"
+	"github.com/brronsuite/broln/input"
+	"github.com/brronsuite/broln/lnpeer"
+	"github.com/brronsuite/broln/lnwallet"
+	"github.com/brronsuite/broln/lnwallet/chainfee"
+	"github.com/brronsuite/broln/lnwallet/chancloser"
+	"github.com/brronsuite/broln/lnwire"
+	"github.com/brronsuite/brond/chaincfg/chainhash"
+	"github.com/brronsuite/brond/wire"
+)
+
+// ErrNoActiveClose is returned when the fee of a cooperative close is bumped
+// for a channel that isn't being cooperatively closed with the peer.
+var ErrNoActiveClose = errors.New("no active cooperative close for channel")
+
+// bumpCloseFeeReq is a request to bump the fee of the closing transaction of
+// an RBF cooperative close.
+type bumpCloseFeeReq struct {
+	chanPoint wire.OutPoint
+	feeRate   chainfee.SatPerKWeight
+	txid      chan *chainhash.Hash
+	err       chan error
+}
+
+// rbfCoopCloseSupported returns true if both we and the remote peer support
+// the RBF cooperative close protocol.
+func (p *Brontide) rbfCoopCloseSupported() bool {
+	return p.LocalFeatures().HasFeature(lnwire.RbfCoopCloseOptional) &&
+		p.RemoteFeatures().HasFeature(lnwire.RbfCoopCloseOptional)
+}
+
+// BumpCloseFee proposes a closing transaction paying the given fee rate from
+// our own output to the remote party, replacing the current closing
+// transaction of the cooperative close of the channel once the remote party
+// signed it. The txid of the proposed closing transaction is returned.
+func (p *Brontide) BumpCloseFee(chanPoint wire.OutPoint,
+	feeRate chainfee.SatPerKWeight) (*chainhash.Hash, error) {
+
+	req := &bumpCloseFeeReq{
+		chanPoint: chanPoint,
+		feeRate:   feeRate,
+		txid:      make(chan *chainhash.Hash, 1),
+		err:       make(chan error, 1),
+	}
+
+	select {
+	case p.bumpCloseFeeReqs <- req:
+	case <-p.quit:
+		return nil, lnpeer.ErrPeerExiting
+	}
+
+	select {
+	case txid := <-req.txid:
+		return txid, nil
+	case err := <-req.err:
+		return nil, err
+	case <-p.quit:
+		return nil, lnpeer.ErrPeerExiting
+	}
+}
+
+// handleBumpCloseFeeReq proposes a closing transaction with the requested fee
+// rate to the remote party.
+func (p *Brontide) handleBumpCloseFeeReq(req *bumpCloseFeeReq) {
+	chanID := lnwire.NewChanIDFromOutPoint(&req.chanPoint)
+
+	chanCloser, ok := p.activeChanCloses[chanID]
+	if !ok {
+		req.err <- ErrNoActiveClose
+		return
+	}
+
+	closingComplete, closeTx, err := chanCloser.BumpFee(req.feeRate)
+	if err != nil {
+		peerLog.Errorf("Unable to bump fee of cooperative close of "+
+			"ChannelPoint(%v): %v", req.chanPoint, err)
+		req.err <- err
+		return
+	}
+
+	p.queueMsg(closingComplete, nil)
+
+	txid := closeTx.TxHash()
+	req.txid <- &txid
+}
+
+// rbfCloseResumable returns true if a cooperative closing transaction was
+// broadcast for the channel, and no other close of the channel was attempted
+// since then.
+func rbfCloseResumable(dbChan *channeldb.OpenChannel) bool {
+	return dbChan.HasChanStatus(channeldb.ChanStatusCoopBroadcasted) &&
+		!dbChan.HasChanStatus(channeldb.ChanStatusCommitBroadcasted) &&
+		!dbChan.HasChanStatus(channeldb.ChanStatusLocalDataLoss) &&
+		!dbChan.HasChanStatus(channeldb.ChanStatusBorked)
+}
+
+// resumeRbfClose creates a closing state machine for a channel whose RBF
+// cooperative closing transaction was broadcast before we reconnected to the
+// peer, such that either party can continue to bump its fee. The returned
+// shutdown message needs to be retransmitted to the peer.
+func (p *Brontide) resumeRbfClose(lnChan *lnwallet.LightningChannel) (
+	lnwire.Message, error) {
+
+	dbChan := lnChan.State()
+
+	deliveryScript, err := dbChan.CoopCloseDeliveryScript()
+	if err != nil {
+		return nil, err
+	}
+
+	closingTx, err := dbChan.BroadcastedCooperative()
+	if err != nil {
+		return nil, err
+	}
+
+	_, bestHeight, err := p.cfg.ChainIO.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	chanCloser := p.newChanCloser(
+		lnChan, deliveryScript, 0, uint32(bestHeight), nil,
+		dbChan.HasChanStatus(channeldb.ChanStatusLocalCloseInitiator),
+	)
+
+	shutdown, err := chanCloser.ResumeRbfClose(closingTx)
+	if err != nil {
+		return nil, err
+	}
+
+	chanID := lnwire.NewChanIDFromOutPoint(&dbChan.FundingOutpoint)
+	p.activeChanCloses[chanID] = chanCloser
+
+	return shutdown, nil
+}
+
+// notifyRbfCloseReplaced informs the local subsystem which requested the
+// closure of the channel that its closing transaction was replaced. The
+// notification is dropped if the subsystem isn't listening anymore.
+func notifyRbfCloseReplaced(chanCloser *chancloser.ChanCloser,
+	closingTxid chainhash.Hash) {
+
+	closeReq := chanCloser.CloseRequest()
+	if closeReq == nil {
+		return
+	}
+
+	select {
+	case closeReq.Updates <- &PendingUpdate{Txid: closingTxid[:]}:
+	default:
+	}
+}
+
+// waitForRbfClose waits until the funding output of a channel closed with an
+// RBF cooperative close is spent. As the closing transaction can be replaced
+// by either party, the callback is passed the txid of the transaction that
+// actually spent the funding output.
+func waitForRbfClose(bestHeight uint32, notifier chainntnfs.ChainNotifier,
+	errChan chan error, chanState *channeldb.OpenChannel,
+	cb func(closingTxid chainhash.Hash)) {
+
+	chanPoint := chanState.FundingOutpoint
+
+	peerLog.Infof("Waiting for RBF cooperative close of "+
+		"ChannelPoint(%v)", chanPoint)
+
+	localKey := chanState.LocalChanCfg.MultiSigKey.PubKey
+	remoteKey := chanState.RemoteChanCfg.MultiSigKey.PubKey
+	_, fundingOutput, err := input.GenFundingPkScript(
+		localKey.SerializeCompressed(), remoteKey.SerializeCompressed(),
+		int64(chanState.Capacity),
+	)
+	if err != nil {
+		if errChan != nil {
+			errChan <- err
+		}
+		return
+	}
+
+	spendNtfn, err := notifier.RegisterSpendNtfn(
+		&chanPoint, fundingOutput.PkScript, bestHeight,
+	)
+	if err != nil {
+		if errChan != nil {
+			errChan <- err
+		}
+		return
+	}
+	defer spendNtfn.Cancel()
+
+	// In the case that the ChainNotifier is shutting down, all subscriber
+	// notification channels will be closed, generating a nil receive.
+	spend, ok := <-spendNtfn.Spend
+	if !ok {
+		return
+	}
+
+	peerLog.Infof("ChannelPoint(%v) is now closed by %v at height %v",
+		chanPoint, spend.SpenderTxHash, spend.SpendingHeight)
+
+	cb(*spend.SpenderTxHash)
+}
@@ -0,0 +1,2019 @@
+package peer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/brronsuite/broln/chainntnfs"
+	"github.com/brronsuite/broln/channeldb"
+	"github.com/brronsuite/broln/htlcswitch"
+	"github.com/brronsuite/broln/input"
+	"github.com/brronsuite/broln/labels"
+	"github.com/brronsuite/broln/lnpeer"
+	"github.com/brronsuite/broln/lnwallet"
+	"github.com/brronsuite/broln/lnwallet/chainfee"
+	"github.com/brronsuite/broln/lnwallet/chanfunding"
+	"github.com/brronsuite/broln/lnwire"
+	"github.com/brronsuite/brond/bronec"
+	"github.com/brronsuite/brond/chaincfg/chainhash"
+	"github.com/brronsuite/brond/txscript"
+	"github.com/brronsuite/brond/wire"
+	"github.com/brronsuite/bronutil"
+	"github.com/brronsuite/bronutil/psbt"
+)
+
+var (
+	// ErrSpliceNotSupported is returned when a splice is requested, but
+	// either we or the remote peer don't support splicing.
+	ErrSpliceNotSupported = errors.New("splicing not supported")
+
+	// ErrSpliceInProgress is returned when a splice is requested for a
+	// channel that is already being spliced.
+	ErrSpliceInProgress = errors.New("channel is already being spliced")
+
+	// ErrNoActiveSplice is returned when a message or request refers to a
+	// splice that doesn't exist.
+	ErrNoActiveSplice = errors.New("no active splice for channel")
+
+	// ErrSpliceDoubleSpent is returned when the pending splices of a
+	// channel are aborted, as an input of each of their splice
+	// transactions has been spent by another transaction.
+	ErrSpliceDoubleSpent = errors.New("inputs of all pending splice " +
+		"transactions double spent")
+)
+
+const (
+	// spliceRetryInterval is the time after which we try again to make a
+	// channel quiescent to lock or abort its pending splices, if the
+	// channel wasn't clean before.
+	spliceRetryInterval = 10 * time.Second
+)
+
+// spliceAction is the action a channel is made quiescent for.
+type spliceAction uint8
+
+const (
+	// spliceActionNew negotiates a new splice of the channel.
+	spliceActionNew spliceAction = iota
+
+	// spliceActionRbf negotiates a replacement of the pending splice
+	// transactions of the channel that pays a higher fee rate.
+	spliceActionRbf
+
+	// spliceActionLock moves the channel onto the funding output of the
+	// pending splice both parties sent SpliceLocked for.
+	spliceActionLock
+
+	// spliceActionAbort removes the pending splices of the channel once
+	// none of their splice transactions can confirm anymore.
+	spliceActionAbort
+)
+
+// SpliceReq is a request to splice a channel, which adds funds to or removes
+// funds from our side of the channel without closing it. If the channel
+// already has pending splices, the request replaces their splice
+// transactions with one paying a higher fee rate.
+type SpliceReq struct {
+	// ChanPoint is the funding outpoint of the channel to splice.
+	ChanPoint *wire.OutPoint
+
+	// Amount is the amount added to our balance if positive, or removed
+	// from it if negative.
+	Amount bronutil.Amount
+
+	// FeeRate is the fee rate of the splice transaction.
+	FeeRate chainfee.SatPerKWeight
+
+	// MinConfs is the minimum number of confirmations of the wallet
+	// coins that fund a splice-in.
+	MinConfs int32
+
+	// FundingPsbt, if set, holds the inputs and outputs that fund the
+	// splice instead of the wallet. Once the splice transaction is
+	// constructed, a SplicePsbtUpdate is sent and the inputs must be
+	// signed and passed to FinalizeSplicePsbt.
+	FundingPsbt *psbt.Packet
+
+	// Updates is used by the peer to send updates on the state of the
+	// splice: a SplicePsbtUpdate for PSBT funded splices, a PendingUpdate
+	// once the splice transaction is broadcast, and a SpliceLockedUpdate
+	// once it confirmed.
+	Updates chan interface{}
+
+	// Err is used by the peer to send any error that occurred while
+	// splicing the channel.
+	Err chan error
+}
+
+// SplicePsbtUpdate is sent to the initiator of a PSBT funded splice once the
+// splice transaction is constructed. The inputs of the PSBT that fund the
+// splice must be signed and passed to FinalizeSplicePsbt.
+type SplicePsbtUpdate struct {
+	Psbt *psbt.Packet
+}
+
+// SpliceLockedUpdate is sent once the splice transaction has confirmed and
+// the channel resumed operation with its new funding outpoint.
+type SpliceLockedUpdate struct {
+	ChanPoint wire.OutPoint
+}
+
+// spliceFinalizeReq passes the signed PSBT of a splice to the channelManager.
+type spliceFinalizeReq struct {
+	chanPoint wire.OutPoint
+	packet    *psbt.Packet
+	err       chan error
+}
+
+// spliceConfirmedMsg notifies the channelManager that the splice of a channel
+// reached the depth at which it can be locked.
+type spliceConfirmedMsg struct {
+	chanPoint  wire.OutPoint
+	spliceTxid chainhash.Hash
+}
+
+// spliceInputSpend notifies the channelManager that an input of a pending
+// splice transaction has been spent.
+type spliceInputSpend struct {
+	chanID     lnwire.ChannelID
+	spliceTxid chainhash.Hash
+	spend      *chainntnfs.SpendDetail
+}
+
+// pendingSpliceState tracks the published splice transactions of a channel
+// until one of them is locked. In the meantime, the channel keeps operating
+// with commitments signed for its current funding output and the funding
+// outputs of all pending splices.
+type pendingSpliceState struct {
+	// initiator is true if we initiated the channel. The initiator of the
+	// channel makes the channel quiescent to lock or abort its pending
+	// splices.
+	initiator bool
+
+	// policy is the forwarding policy of the channel's link, which is
+	// kept once the channel moves onto its new funding output.
+	policy *htlcswitch.ForwardingPolicy
+
+	// reqs are our requests for the pending splices, which are notified
+	// once a splice is locked or the pending splices are aborted.
+	reqs []*SpliceReq
+
+	// localLocked and remoteLocked are the splice transactions the
+	// respective party sent SpliceLocked for.
+	localLocked  *chainhash.Hash
+	remoteLocked *chainhash.Hash
+
+	// doubleSpent is the set of pending splice transactions that can't
+	// confirm anymore, as one of their inputs has been spent by another
+	// transaction.
+	doubleSpent map[chainhash.Hash]struct{}
+
+	// retryScheduled is true while we wait to try again to make the
+	// channel quiescent.
+	retryScheduled bool
+}
+
+// spliceCtx tracks a quiescent channel, from the exchange of Stfu until a
+// new splice has been negotiated and published, or the pending splices of
+// the channel are locked or aborted.
+type spliceCtx struct {
+	// chanID is the ID of the channel prior to the splice.
+	chanID lnwire.ChannelID
+
+	// channel is the channel that is spliced.
+	channel *lnwallet.LightningChannel
+
+	// initiator is true if we made the channel quiescent.
+	initiator bool
+
+	// action is what the channel has been made quiescent for.
+	action spliceAction
+
+	// req is our request for the splice. It's nil if the remote party
+	// initiated the splice.
+	req *SpliceReq
+
+	// sentStfu is true once we sent Stfu to the remote party.
+	sentStfu bool
+
+	// policy is the forwarding policy of the channel's link, which is
+	// restored once the link is added again.
+	policy *htlcswitch.ForwardingPolicy
+
+	// localAmt and remoteAmt are the contributions of each party.
+	localAmt  bronutil.Amount
+	remoteAmt bronutil.Amount
+
+	// feeRate and lockTime are the parameters of the splice transaction.
+	feeRate  chainfee.SatPerKWeight
+	lockTime uint32
+
+	// tx is the splice transaction under construction.
+	tx *chanfunding.InteractiveTx
+
+	// queue holds the messages adding our inputs and outputs that
+	// haven't been sent to the remote party yet.
+	queue []lnwire.Message
+
+	// sentComplete is true if our last message was a TxComplete.
+	sentComplete bool
+
+	// walletIntent holds the wallet coins that fund a splice-in.
+	walletIntent *chanfunding.FullIntent
+
+	// psbtInputs are the inputs of the funding PSBT of a PSBT funded
+	// splice, which are signed externally.
+	psbtInputs map[wire.OutPoint]*psbt.PInput
+
+	// splice holds the splice transaction and the new commitments once
+	// the transaction is constructed.
+	splice *lnwallet.Splice
+
+	// recvCommitSig is true once we received the remote party's
+	// signature for our new commitment.
+	recvCommitSig bool
+
+	// inputsSigned is true once all of our inputs are signed.
+	inputsSigned bool
+
+	// localSharedSig is our signature for the shared input.
+	localSharedSig lnwire.Sig
+
+	// remoteSigs are the witnesses and shared input signature of the
+	// remote party, if received.
+	remoteSigs *lnwire.TxSignatures
+
+	// sentSigs is true once we sent our TxSignatures. From then on, the
+	// splice can't be aborted anymore, as the remote party is able to
+	// broadcast the splice transaction.
+	sentSigs bool
+
+	// published is true once the splice transaction is complete and
+	// published.
+	published bool
+
+	// sentAbort is true once we sent TxAbort to abort the pending
+	// splices of the channel.
+	sentAbort bool
+
+	// abortPending is true if the remote party aborts the pending
+	// splices of the channel, but we didn't see all of their splice
+	// transactions being double spent yet.
+	abortPending bool
+}
+
+// spliceSupported returns true if both we and the peer signal support for
+// splicing.
+func (p *Brontide) spliceSupported() bool {
+	return p.LocalFeatures().HasFeature(lnwire.SpliceOptional) &&
+		p.RemoteFeatures().HasFeature(lnwire.SpliceOptional)
+}
+
+// isSplicing returns true if the channel is currently being spliced.
+func (p *Brontide) isSplicing(chanID lnwire.ChannelID) bool {
+	p.splicingChansMtx.RLock()
+	defer p.splicingChansMtx.RUnlock()
+
+	_, ok := p.splicingChans[chanID]
+	return ok
+}
+
+// setSplicing marks the channel as being spliced or not, which determines
+// whether the interactive tx and commitment messages of the channel are
+// handled by the splice state machine.
+func (p *Brontide) setSplicing(chanID lnwire.ChannelID, splicing bool) {
+	p.splicingChansMtx.Lock()
+	defer p.splicingChansMtx.Unlock()
+
+	if splicing {
+		p.splicingChans[chanID] = struct{}{}
+	} else {
+		delete(p.splicingChans, chanID)
+	}
+}
+
+// isSpliceMsg returns true if the message is part of a splice and should be
+// handled by the splice state machine instead of the funding manager or the
+// channel's link.
+func (p *Brontide) isSpliceMsg(msg lnwire.Message) bool {
+	var chanID lnwire.ChannelID
+	switch msg := msg.(type) {
+	case *lnwire.Stfu, *lnwire.SpliceInit, *lnwire.SpliceAck,
+		*lnwire.SpliceLocked:
+
+		return true
+
+	case *lnwire.TxAddInput:
+		chanID = msg.ChannelID
+	case *lnwire.TxAddOutput:
+		chanID = msg.ChannelID
+	case *lnwire.TxRemoveInput:
+		chanID = msg.ChannelID
+	case *lnwire.TxRemoveOutput:
+		chanID = msg.ChannelID
+	case *lnwire.TxComplete:
+		chanID = msg.ChannelID
+	case *lnwire.TxSignatures:
+		chanID = msg.ChannelID
+	case *lnwire.TxAbort:
+		chanID = msg.ChannelID
+	case *lnwire.TxInitRbf:
+		chanID = msg.ChannelID
+	case *lnwire.TxAckRbf:
+		chanID = msg.ChannelID
+	case *lnwire.CommitSig:
+		chanID = msg.ChanID
+
+	default:
+		return false
+	}
+
+	return p.isSplicing(chanID)
+}
+
+// HandleLocalSpliceReq accepts a *SpliceReq and passes it onto the
+// channelManager goroutine, which will make the channel quiescent and
+// negotiate the splice with the remote party.
+func (p *Brontide) HandleLocalSpliceReq(req *SpliceReq) {
+	select {
+	case p.localSpliceReqs <- req:
+		peerLog.Infof("Local splice request delivered to peer: %x",
+			p.PubKey())
+	case <-p.quit:
+		peerLog.Infof("Unable to deliver local splice request to "+
+			"peer %x", p.PubKey())
+	}
+}
+
+// FinalizeSplicePsbt passes the signed PSBT of a PSBT funded splice to the
+// splice state machine. The PSBT must be the one sent with the
+// SplicePsbtUpdate, with all inputs funding the splice signed.
+func (p *Brontide) FinalizeSplicePsbt(chanPoint wire.OutPoint,
+	packet *psbt.Packet) error {
+
+	req := &spliceFinalizeReq{
+		chanPoint: chanPoint,
+		packet:    packet,
+		err:       make(chan error, 1),
+	}
+
+	select {
+	case p.spliceFinalizeReqs <- req:
+	case <-p.quit:
+		return lnpeer.ErrPeerExiting
+	}
+
+	select {
+	case err := <-req.err:
+		return err
+	case <-p.quit:
+		return lnpeer.ErrPeerExiting
+	}
+}
+
+// HandleSpliceConfirmed notifies the peer that the splice of a channel
+// reached the depth at which it can be locked, which happens once both
+// parties sent SpliceLocked.
+func (p *Brontide) HandleSpliceConfirmed(chanPoint wire.OutPoint,
+	spliceTxid chainhash.Hash) {
+
+	select {
+	case p.splicesConfirmed <- &spliceConfirmedMsg{
+		chanPoint:  chanPoint,
+		spliceTxid: spliceTxid,
+	}:
+	case <-p.quit:
+	}
+}
+
+// quiesceChannel stops the link of the channel, which requires the channel
+// to be clean, and creates a splice context for it.
+func (p *Brontide) quiesceChannel(chanID lnwire.ChannelID,
+	initiator bool) (*spliceCtx, error) {
+
+	if !p.spliceSupported() {
+		return nil, ErrSpliceNotSupported
+	}
+
+	if _, ok := p.activeSplices[chanID]; ok {
+		return nil, ErrSpliceInProgress
+	}
+
+	p.activeChanMtx.RLock()
+	channel, ok := p.activeChannels[chanID]
+	p.activeChanMtx.RUnlock()
+	if !ok || channel == nil {
+		return nil, ErrChannelNotFound
+	}
+
+	// The forwarding policy is kept, so the link is restored with the
+	// same policy once the splice is done.
+	policy, err := p.fetchForwardingPolicy(channel.ChannelPoint())
+	if err != nil {
+		return nil, err
+	}
+
+	// Shutting down the link ensures no further updates are added to the
+	// channel.
+	if err := p.tryLinkShutdown(chanID); err != nil {
+		return nil, err
+	}
+
+	ctx := &spliceCtx{
+		chanID:    chanID,
+		channel:   channel,
+		initiator: initiator,
+		policy:    policy,
+	}
+	p.activeSplices[chanID] = ctx
+	p.setSplicing(chanID, true)
+
+	return ctx, nil
+}
+
+// handleLocalSpliceReq kicks off the splice of a channel by making it
+// quiescent.
+func (p *Brontide) handleLocalSpliceReq(req *SpliceReq) {
+	chanID := lnwire.NewChanIDFromOutPoint(req.ChanPoint)
+
+	p.activeChanMtx.RLock()
+	channel, ok := p.activeChannels[chanID]
+	p.activeChanMtx.RUnlock()
+	if !ok || channel == nil {
+		req.Err <- ErrChannelNotFound
+		return
+	}
+
+	// A splice-out must leave at least our channel reserve in the
+	// channel.
+	localBalance := channel.StateSnapshot().LocalBalance.ToBroneess()
+	reserve := channel.State().LocalChanCfg.ChanReserve
+	if req.Amount < 0 && localBalance+req.Amount < reserve {
+		req.Err <- fmt.Errorf("splice-out of %v exceeds local "+
+			"balance %v minus reserve %v", -req.Amount,
+			localBalance, reserve)
+		return
+	}
+	if req.Amount == 0 {
+		req.Err <- errors.New("splice amount must not be zero")
+		return
+	}
+
+	// If the channel has pending splices, the request replaces their
+	// splice transactions, which requires a higher fee rate.
+	action := spliceActionNew
+	if state, ok := p.pendingSplices[chanID]; ok {
+		if state.localLocked != nil {
+			req.Err <- errors.New("pending splice already " +
+				"confirmed")
+			return
+		}
+
+		minFeeRate := minSpliceRbfFeeRate(channel.PendingSplices())
+		if req.FeeRate < minFeeRate {
+			req.Err <- fmt.Errorf("fee rate %v below minimum "+
+				"replacement fee rate %v", req.FeeRate,
+				minFeeRate)
+			return
+		}
+		action = spliceActionRbf
+	}
+
+	// The splice transaction is locked to the current height, which
+	// discourages fee sniping.
+	_, height, err := p.cfg.ChainIO.GetBestBlock()
+	if err != nil {
+		req.Err <- err
+		return
+	}
+
+	ctx, err := p.quiesceChannel(chanID, true)
+	if err != nil {
+		req.Err <- err
+		return
+	}
+	ctx.req = req
+	ctx.action = action
+	ctx.localAmt = req.Amount
+	ctx.feeRate = req.FeeRate
+	ctx.lockTime = uint32(height)
+
+	ctx.sentStfu = true
+	err = p.SendMessage(true, &lnwire.Stfu{
+		ChannelID: chanID,
+		Initiator: true,
+	})
+	if err != nil {
+		p.failSplice(ctx, err)
+	}
+}
+
+// handleSpliceMsg advances the splice state machine of a channel with a
+// message of the remote party.
+func (p *Brontide) handleSpliceMsg(msg lnwire.Message) {
+	var err error
+	switch msg := msg.(type) {
+	case *lnwire.Stfu:
+		err = p.handleStfu(msg)
+
+	case *lnwire.SpliceInit:
+		err = p.handleSpliceInit(msg)
+
+	case *lnwire.SpliceAck:
+		err = p.handleSpliceAck(msg)
+
+	case *lnwire.TxInitRbf:
+		err = p.handleTxInitRbf(msg)
+
+	case *lnwire.TxAckRbf:
+		err = p.handleTxAckRbf(msg)
+
+	case *lnwire.SpliceLocked:
+		err = p.handleSpliceLocked(msg)
+
+	case *lnwire.CommitSig:
+		err = p.handleSpliceCommitSig(msg)
+
+	case *lnwire.TxSignatures:
+		err = p.handleSpliceTxSignatures(msg)
+
+	case *lnwire.TxAbort:
+		p.handleSpliceTxAbort(msg)
+
+	default:
+		err = p.handleSpliceTxMsg(msg)
+	}
+
+	if err != nil {
+		peerLog.Errorf("Unable to handle splice message %v from "+
+			"peer %v: %v", msg.MsgType(), p, err)
+	}
+}
+
+// handleStfu handles the Stfu message of the remote party. If we made the
+// channel quiescent, we go on with the action we made it quiescent for.
+// Otherwise, we stop the link of the channel and reply with Stfu.
+func (p *Brontide) handleStfu(msg *lnwire.Stfu) error {
+	ctx, ok := p.activeSplices[msg.ChannelID]
+	if !ok {
+		if !msg.Initiator {
+			return ErrNoActiveSplice
+		}
+
+		ctx, err := p.quiesceChannel(msg.ChannelID, false)
+		if err != nil {
+			p.sendTxAbort(msg.ChannelID, err)
+			return err
+		}
+
+		ctx.sentStfu = true
+		err = p.SendMessage(true, &lnwire.Stfu{
+			ChannelID: msg.ChannelID,
+		})
+		if err != nil {
+			p.failSplice(ctx, err)
+			return err
+		}
+
+		// Once both parties sent SpliceLocked, the initiator of the
+		// channel only makes it quiescent to lock the splice.
+		if p.lockableSplice(msg.ChannelID) != nil {
+			ctx.action = spliceActionLock
+			p.lockSplice(ctx)
+		}
+
+		return nil
+	}
+
+	if !ctx.initiator || !ctx.sentStfu || ctx.tx != nil || ctx.sentAbort {
+		err := errors.New("unexpected stfu")
+		p.failSplice(ctx, err)
+		return err
+	}
+
+	switch ctx.action {
+	case spliceActionLock:
+		p.lockSplice(ctx)
+		return nil
+
+	// The remote party acknowledges the abort with TxAbort once it sees
+	// that none of the pending splice transactions can confirm anymore.
+	case spliceActionAbort:
+		ctx.sentAbort = true
+		p.sendTxAbort(ctx.chanID, ErrSpliceDoubleSpent)
+		return nil
+
+	case spliceActionRbf:
+		ctx.tx = chanfunding.NewInteractiveTx(true)
+		err := p.SendMessage(true, &lnwire.TxInitRbf{
+			ChannelID:           ctx.chanID,
+			LockTime:            ctx.lockTime,
+			FeeRate:             uint32(ctx.feeRate),
+			FundingContribution: ctx.localAmt,
+		})
+		if err != nil {
+			p.failSplice(ctx, err)
+			return err
+		}
+
+		return nil
+	}
+
+	ctx.tx = chanfunding.NewInteractiveTx(true)
+
+	localKey := ctx.channel.State().LocalChanCfg.MultiSigKey.PubKey
+	err := p.SendMessage(true, &lnwire.SpliceInit{
+		ChannelID:               ctx.chanID,
+		FundingContribution:     ctx.localAmt,
+		FundingFeePerKiloWeight: uint32(ctx.feeRate),
+		LockTime:                ctx.lockTime,
+		FundingPubKey:           localKey,
+	})
+	if err != nil {
+		p.failSplice(ctx, err)
+		return err
+	}
+
+	return nil
+}
+
+// handleSpliceInit handles the splice proposal of the remote party. We don't
+// contribute to splices initiated by the remote party.
+func (p *Brontide) handleSpliceInit(msg *lnwire.SpliceInit) error {
+	ctx, ok := p.activeSplices[msg.ChannelID]
+	if !ok {
+		return ErrNoActiveSplice
+	}
+	if ctx.initiator || ctx.tx != nil {
+		err := errors.New("unexpected splice_init")
+		p.failSplice(ctx, err)
+		return err
+	}
+
+	// Pending splices can only be replaced, as a new splice would spend
+	// the funding output of a splice transaction that isn't locked yet.
+	if _, ok := p.pendingSplices[msg.ChannelID]; ok {
+		err := errors.New("channel has pending splices")
+		p.failSplice(ctx, err)
+		return err
+	}
+
+	if err := checkSpliceFundingKey(ctx, msg.FundingPubKey); err != nil {
+		p.failSplice(ctx, err)
+		return err
+	}
+
+	err := checkRemoteSpliceContribution(ctx, msg.FundingContribution)
+	if err != nil {
+		p.failSplice(ctx, err)
+		return err
+	}
+
+	ctx.remoteAmt = msg.FundingContribution
+	ctx.feeRate = chainfee.SatPerKWeight(msg.FundingFeePerKiloWeight)
+	ctx.lockTime = msg.LockTime
+	ctx.tx = chanfunding.NewInteractiveTx(false)
+
+	sharedOutPoint, sharedPrevOut := ctx.channel.SharedInput()
+	err = ctx.tx.AddSharedInput(sharedOutPoint, sharedPrevOut)
+	if err != nil {
+		p.failSplice(ctx, err)
+		return err
+	}
+
+	localKey := ctx.channel.State().LocalChanCfg.MultiSigKey.PubKey
+	err = p.SendMessage(true, &lnwire.SpliceAck{
+		ChannelID:     ctx.chanID,
+		FundingPubKey: localKey,
+	})
+	if err != nil {
+		p.failSplice(ctx, err)
+		return err
+	}
+
+	return nil
+}
+
+// handleSpliceAck handles the acceptance of our splice proposal. We add the
+// shared input and our contribution to the splice transaction and start its
+// interactive construction.
+func (p *Brontide) handleSpliceAck(msg *lnwire.SpliceAck) error {
+	ctx, ok := p.activeSplices[msg.ChannelID]
+	if !ok {
+		return ErrNoActiveSplice
+	}
+	if !ctx.initiator || ctx.tx == nil || ctx.splice != nil {
+		err := errors.New("unexpected splice_ack")
+		p.failSplice(ctx, err)
+		return err
+	}
+
+	if err := checkSpliceFundingKey(ctx, msg.FundingPubKey); err != nil {
+		p.failSplice(ctx, err)
+		return err
+	}
+
+	ctx.remoteAmt = msg.FundingContribution
+
+	return p.startSpliceTx(ctx)
+}
+
+// handleTxInitRbf handles the proposal of the remote party to replace the
+// pending splice transactions of the channel with one paying a higher fee
+// rate. Like for new splices, we don't contribute to the replacement.
+func (p *Brontide) handleTxInitRbf(msg *lnwire.TxInitRbf) error {
+	ctx, ok := p.activeSplices[msg.ChannelID]
+	if !ok {
+		return ErrNoActiveSplice
+	}
+	state, ok := p.pendingSplices[msg.ChannelID]
+	if ctx.initiator || ctx.tx != nil || !ok {
+		err := errors.New("unexpected tx_init_rbf")
+		p.failSplice(ctx, err)
+		return err
+	}
+
+	// Once a pending splice transaction reached the lock depth, its
+	// replacements can't confirm anymore.
+	if state.localLocked != nil {
+		err := errors.New("pending splice already confirmed")
+		p.failSplice(ctx, err)
+		return err
+	}
+
+	feeRate := chainfee.SatPerKWeight(msg.FeeRate)
+	minFeeRate := minSpliceRbfFeeRate(ctx.channel.PendingSplices())
+	if feeRate < minFeeRate {
+		err := fmt.Errorf("fee rate %v below minimum replacement "+
+			"fee rate %v", feeRate, minFeeRate)
+		p.failSplice(ctx, err)
+		return err
+	}
+
+	err := checkRemoteSpliceContribution(ctx, msg.FundingContribution)
+	if err != nil {
+		p.failSplice(ctx, err)
+		return err
+	}
+
+	ctx.action = spliceActionRbf
+	ctx.remoteAmt = msg.FundingContribution
+	ctx.feeRate = feeRate
+	ctx.lockTime = msg.LockTime
+	ctx.tx = chanfunding.NewInteractiveTx(false)
+
+	sharedOutPoint, sharedPrevOut := ctx.channel.SharedInput()
+	err = ctx.tx.AddSharedInput(sharedOutPoint, sharedPrevOut)
+	if err != nil {
+		p.failSplice(ctx, err)
+		return err
+	}
+
+	err = p.SendMessage(true, &lnwire.TxAckRbf{
+		ChannelID: ctx.chanID,
+	})
+	if err != nil {
+		p.failSplice(ctx, err)
+		return err
+	}
+
+	return nil
+}
+
+// handleTxAckRbf handles the acceptance of our proposal to replace the
+// pending splice transactions, and starts the interactive construction of
+// the replacement.
+func (p *Brontide) handleTxAckRbf(msg *lnwire.TxAckRbf) error {
+	ctx, ok := p.activeSplices[msg.ChannelID]
+	if !ok {
+		return ErrNoActiveSplice
+	}
+	if !ctx.initiator || ctx.action != spliceActionRbf || ctx.tx == nil ||
+		ctx.splice != nil {
+
+		err := errors.New("unexpected tx_ack_rbf")
+		p.failSplice(ctx, err)
+		return err
+	}
+
+	ctx.remoteAmt = msg.FundingContribution
+
+	return p.startSpliceTx(ctx)
+}
+
+// startSpliceTx adds the shared input and our contribution to the splice
+// transaction and starts its interactive construction.
+func (p *Brontide) startSpliceTx(ctx *spliceCtx) error {
+	sharedOutPoint, sharedPrevOut := ctx.channel.SharedInput()
+	err := ctx.tx.AddSharedInput(sharedOutPoint, sharedPrevOut)
+	if err != nil {
+		p.failSplice(ctx, err)
+		return err
+	}
+
+	if err := p.queueSpliceContribution(ctx); err != nil {
+		p.failSplice(ctx, err)
+		return err
+	}
+
+	if _, err := p.sendNextSpliceTxMsg(ctx); err != nil {
+		p.failSplice(ctx, err)
+		return err
+	}
+
+	return nil
+}
+
+// checkRemoteSpliceContribution makes sure the remote party keeps at least
+// its reserve when removing funds from the channel.
+func checkRemoteSpliceContribution(ctx *spliceCtx,
+	contribution bronutil.Amount) error {
+
+	remoteBalance := ctx.channel.StateSnapshot().RemoteBalance.ToBroneess()
+	reserve := ctx.channel.State().RemoteChanCfg.ChanReserve
+	if remoteBalance+contribution < reserve {
+		return fmt.Errorf("splice-out of %v exceeds remote balance "+
+			"%v minus reserve %v", -contribution, remoteBalance,
+			reserve)
+	}
+
+	return nil
+}
+
+// minSpliceRbfFeeRate returns the minimum fee rate of a replacement of the
+// given pending splices, which must exceed the fee rate of each of them by
+// at least 1/24.
+func minSpliceRbfFeeRate(
+	splices []*channeldb.PendingSplice) chainfee.SatPerKWeight {
+
+	var maxFeeRate chainfee.SatPerKWeight
+	for _, splice := range splices {
+		feeRate := chainfee.SatPerKWeight(splice.FeePerKw)
+		if feeRate > maxFeeRate {
+			maxFeeRate = feeRate
+		}
+	}
+
+	return (maxFeeRate*25 + 23) / 24
+}
+
+// checkSpliceFundingKey makes sure the remote party keeps using its current
+// multisig key for the new funding output, as the funding output of a splice
+// is locked to the same keys as the current one.
+func checkSpliceFundingKey(ctx *spliceCtx, key *bronec.PublicKey) error {
+	remoteKey := ctx.channel.State().RemoteChanCfg.MultiSigKey.PubKey
+	if key == nil || !key.IsEqual(remoteKey) {
+		return errors.New("splice funding key must match the " +
+			"current funding key")
+	}
+
+	return nil
+}
+
+// spliceFee returns the fee we pay for the given weight of our inputs and
+// outputs. As the initiator, we additionally pay for the common fields of
+// the transaction, the shared input and the new funding output.
+func spliceFee(ctx *spliceCtx, est *input.TxWeightEstimator,
+	fundingOutput *wire.TxOut) bronutil.Amount {
+
+	if ctx.initiator {
+		est.AddWitnessInput(input.MultiSigWitnessSize)
+		est.AddTxOutput(fundingOutput)
+	}
+
+	weight := int64(est.Weight())
+	if !ctx.initiator {
+		var emptyTx input.TxWeightEstimator
+		weight -= int64(emptyTx.Weight())
+	}
+
+	return ctx.feeRate.FeeForWeight(weight)
+}
+
+// queueSpliceContribution adds our inputs and outputs to the splice
+// transaction, and queues the messages that announce them to the remote
+// party. Funds are either added from the wallet or the funding PSBT of the
+// request, or removed to a wallet address.
+func (p *Brontide) queueSpliceContribution(ctx *spliceCtx) error {
+	capacity := ctx.channel.State().Capacity + ctx.localAmt + ctx.remoteAmt
+	fundingOutput := ctx.channel.SpliceFundingOutput(capacity)
+
+	var (
+		prevTxs []*wire.MsgTx
+		outPts  []wire.OutPoint
+		outputs []*wire.TxOut
+	)
+	switch {
+	// A PSBT funded splice adds the inputs and outputs of the PSBT as is.
+	case ctx.req.FundingPsbt != nil:
+		packet := ctx.req.FundingPsbt
+		ctx.psbtInputs = make(map[wire.OutPoint]*psbt.PInput)
+		for i, txIn := range packet.UnsignedTx.TxIn {
+			pIn := packet.Inputs[i]
+			if pIn.NonWitnessUtxo == nil {
+				return fmt.Errorf("input %v of PSBT is missing "+
+					"its previous transaction",
+					txIn.PreviousOutPoint)
+			}
+
+			prevTxs = append(prevTxs, pIn.NonWitnessUtxo)
+			outPts = append(outPts, txIn.PreviousOutPoint)
+			ctx.psbtInputs[txIn.PreviousOutPoint] = &packet.Inputs[i]
+		}
+		outputs = packet.UnsignedTx.TxOut
+
+	// Funds added from the wallet are selected by coin selection, which
+	// also covers the fee of the shared input we pay for.
+	case ctx.localAmt > 0:
+		var est input.TxWeightEstimator
+		est.AddWitnessInput(input.MultiSigWitnessSize)
+		sharedInputFee := ctx.feeRate.FeeForWeight(int64(est.Weight()))
+
+		intent, err := p.cfg.Wallet.ProvisionSpliceIn(
+			ctx.localAmt+sharedInputFee, ctx.feeRate,
+			ctx.req.MinConfs,
+		)
+		if err != nil {
+			return err
+		}
+		ctx.walletIntent = intent
+
+		for _, outPoint := range intent.Inputs() {
+			outPoint := outPoint
+
+			utxo, err := p.cfg.Wallet.FetchInputInfo(&outPoint)
+			if err != nil {
+				return err
+			}
+			if utxo.PrevTx == nil {
+				return fmt.Errorf("previous transaction of "+
+					"input %v unknown", outPoint)
+			}
+
+			prevTxs = append(prevTxs, utxo.PrevTx)
+			outPts = append(outPts, outPoint)
+		}
+		outputs = intent.ChangeOutputs
+
+	// Funds removed from the channel are paid to a wallet address, minus
+	// our share of the fee.
+	default:
+		addr, err := p.cfg.Wallet.NewAddress(
+			lnwallet.WitnessPubKey, false,
+			lnwallet.DefaultAccountName,
+		)
+		if err != nil {
+			return err
+		}
+		pkScript, err := txscript.PayToAddrScript(addr)
+		if err != nil {
+			return err
+		}
+
+		var est input.TxWeightEstimator
+		est.AddP2WKHOutput()
+		fee := spliceFee(ctx, &est, fundingOutput)
+		outputs = []*wire.TxOut{{
+			Value:    int64(-ctx.localAmt - fee),
+			PkScript: pkScript,
+		}}
+	}
+
+	for i, prevTx := range prevTxs {
+		in, err := ctx.tx.AddLocalInput(prevTx, outPts[i].Index)
+		if err != nil {
+			return err
+		}
+
+		var b bytes.Buffer
+		if err := prevTx.Serialize(&b); err != nil {
+			return err
+		}
+
+		ctx.queue = append(ctx.queue, &lnwire.TxAddInput{
+			ChannelID:      ctx.chanID,
+			SerialID:       in.SerialID,
+			PrevTx:         b.Bytes(),
+			PrevTxOutIndex: in.PrevOutIndex,
+			Sequence:       in.Sequence,
+		})
+	}
+
+	// As the initiator, we add the new funding output.
+	outputs = append(outputs, fundingOutput)
+	for _, txOut := range outputs {
+		out, err := ctx.tx.AddLocalOutput(txOut)
+		if err != nil {
+			return err
+		}
+
+		ctx.queue = append(ctx.queue, &lnwire.TxAddOutput{
+			ChannelID: ctx.chanID,
+			SerialID:  out.SerialID,
+			Amount:    bronutil.Amount(txOut.Value),
+			PkScript:  txOut.PkScript,
+		})
+	}
+
+	return nil
+}
+
+// sendNextSpliceTxMsg sends the next queued message of the interactive
+// construction of the splice transaction, or a TxComplete message if we
+// don't have anything left to add. It returns true if a TxComplete message
+// was sent.
+func (p *Brontide) sendNextSpliceTxMsg(ctx *spliceCtx) (bool, error) {
+	var msg lnwire.Message
+	if len(ctx.queue) > 0 {
+		msg, ctx.queue = ctx.queue[0], ctx.queue[1:]
+		ctx.sentComplete = false
+	} else {
+		msg = &lnwire.TxComplete{ChannelID: ctx.chanID}
+		ctx.sentComplete = true
+	}
+
+	if err := p.SendMessage(true, msg); err != nil {
+		return false, err
+	}
+
+	return ctx.sentComplete, nil
+}
+
+// handleSpliceTxMsg processes a message of the remote party that is part of
+// the interactive construction of the splice transaction. The parties take
+// turns, and the construction is finished once both parties have sent
+// consecutive TxComplete messages.
+func (p *Brontide) handleSpliceTxMsg(msg lnwire.Message) error {
+	var (
+		chanID lnwire.ChannelID
+		err    error
+	)
+	switch msg := msg.(type) {
+	case *lnwire.TxAddInput:
+		chanID = msg.ChannelID
+	case *lnwire.TxAddOutput:
+		chanID = msg.ChannelID
+	case *lnwire.TxRemoveInput:
+		chanID = msg.ChannelID
+	case *lnwire.TxRemoveOutput:
+		chanID = msg.ChannelID
+	case *lnwire.TxComplete:
+		chanID = msg.ChannelID
+	default:
+		return fmt.Errorf("unexpected message %v", msg.MsgType())
+	}
+
+	ctx, ok := p.activeSplices[chanID]
+	if !ok {
+		return ErrNoActiveSplice
+	}
+	if ctx.tx == nil || ctx.splice != nil {
+		err := errors.New("splice transaction not under construction")
+		p.failSplice(ctx, err)
+		return err
+	}
+
+	switch msg := msg.(type) {
+	case *lnwire.TxAddInput:
+		prevTx := &wire.MsgTx{}
+		err = prevTx.Deserialize(bytes.NewReader(msg.PrevTx))
+		if err != nil {
+			break
+		}
+
+		err = ctx.tx.AddRemoteInput(&chanfunding.InteractiveTxInput{
+			SerialID:     msg.SerialID,
+			PrevTx:       prevTx,
+			PrevOutIndex: msg.PrevTxOutIndex,
+			Sequence:     msg.Sequence,
+		})
+
+	case *lnwire.TxAddOutput:
+		err = ctx.tx.AddRemoteOutput(&chanfunding.InteractiveTxOutput{
+			SerialID: msg.SerialID,
+			TxOut: &wire.TxOut{
+				Value:    int64(msg.Amount),
+				PkScript: msg.PkScript,
+			},
+		})
+
+	case *lnwire.TxRemoveInput:
+		err = ctx.tx.RemoveRemoteInput(msg.SerialID)
+
+	case *lnwire.TxRemoveOutput:
+		err = ctx.tx.RemoveRemoteOutput(msg.SerialID)
+
+	case *lnwire.TxComplete:
+		if ctx.sentComplete {
+			return p.finishSpliceTx(ctx)
+		}
+	}
+	if err != nil {
+		p.failSplice(ctx, err)
+		return err
+	}
+
+	_, remoteComplete := msg.(*lnwire.TxComplete)
+	sentComplete, err := p.sendNextSpliceTxMsg(ctx)
+	if err != nil {
+		p.failSplice(ctx, err)
+		return err
+	}
+
+	if remoteComplete && sentComplete {
+		return p.finishSpliceTx(ctx)
+	}
+
+	return nil
+}
+
+// finishSpliceTx verifies the constructed splice transaction and sends our
+// signature for the remote party's commitment spending the new funding
+// output.
+func (p *Brontide) finishSpliceTx(ctx *spliceCtx) error {
+	capacity := ctx.channel.State().Capacity + ctx.localAmt + ctx.remoteAmt
+	fundingOutput := ctx.channel.SpliceFundingOutput(capacity)
+
+	err := ctx.tx.Verify(fundingOutput, ctx.remoteAmt, ctx.feeRate)
+	if err != nil {
+		p.failSplice(ctx, err)
+		return err
+	}
+
+	spliceTx := ctx.tx.Tx(ctx.lockTime)
+	splice, commitSig, err := ctx.channel.NewSplice(
+		spliceTx, ctx.localAmt, ctx.remoteAmt, ctx.feeRate,
+		ctx.initiator,
+	)
+	if err != nil {
+		p.failSplice(ctx, err)
+		return err
+	}
+	ctx.splice = splice
+
+	peerLog.Infof("Constructed splice tx %v for ChannelID(%v)",
+		spliceTx.TxHash(), ctx.chanID)
+
+	if err := p.SendMessage(true, commitSig); err != nil {
+		p.failSplice(ctx, err)
+		return err
+	}
+
+	// The inputs of a PSBT funded splice are signed externally, so we
+	// hand out the final transaction to be signed.
+	if ctx.psbtInputs != nil {
+		packet, err := psbt.NewFromUnsignedTx(spliceTx.Copy())
+		if err != nil {
+			p.failSplice(ctx, err)
+			return err
+		}
+		for i, txIn := range spliceTx.TxIn {
+			pIn, ok := ctx.psbtInputs[txIn.PreviousOutPoint]
+			if !ok {
+				continue
+			}
+
+			packet.Inputs[i].NonWitnessUtxo = pIn.NonWitnessUtxo
+			packet.Inputs[i].WitnessUtxo = pIn.WitnessUtxo
+			packet.Inputs[i].Bip32Derivation = pIn.Bip32Derivation
+		}
+
+		ctx.req.Updates <- &SplicePsbtUpdate{Psbt: packet}
+
+		return nil
+	}
+
+	return p.signSpliceInputs(ctx)
+}
+
+// signSpliceInputs signs our wallet inputs and the shared input of the
+// splice transaction.
+func (p *Brontide) signSpliceInputs(ctx *spliceCtx) error {
+	spliceTx := ctx.splice.SpliceTx
+	prevOutFetcher := ctx.tx.PrevOutFetcher()
+
+	if ctx.walletIntent != nil {
+		err := ctx.walletIntent.SignInputs(spliceTx, prevOutFetcher)
+		if err != nil {
+			p.failSplice(ctx, err)
+			return err
+		}
+	}
+
+	sig, err := ctx.channel.SignSharedInput(ctx.splice, prevOutFetcher)
+	if err != nil {
+		p.failSplice(ctx, err)
+		return err
+	}
+	ctx.localSharedSig = sig
+	ctx.inputsSigned = true
+
+	return p.maybeSendSpliceSigs(ctx)
+}
+
+// handleSpliceCommitSig verifies the remote party's signature for our
+// commitment spending the new funding output.
+func (p *Brontide) handleSpliceCommitSig(msg *lnwire.CommitSig) error {
+	ctx, ok := p.activeSplices[msg.ChanID]
+	if !ok {
+		return ErrNoActiveSplice
+	}
+	if ctx.splice == nil || ctx.recvCommitSig {
+		err := errors.New("unexpected commitment signature")
+		p.failSplice(ctx, err)
+		return err
+	}
+
+	err := ctx.channel.ReceiveSpliceCommitSig(ctx.splice, msg)
+	if err != nil {
+		p.failSplice(ctx, err)
+		return err
+	}
+	ctx.recvCommitSig = true
+
+	return p.maybeSendSpliceSigs(ctx)
+}
+
+// handleSpliceTxSignatures processes the witnesses of the remote party's
+// inputs and its signature for the shared input.
+func (p *Brontide) handleSpliceTxSignatures(msg *lnwire.TxSignatures) error {
+	ctx, ok := p.activeSplices[msg.ChannelID]
+	if !ok {
+		return ErrNoActiveSplice
+	}
+	if ctx.splice == nil || !ctx.recvCommitSig || ctx.remoteSigs != nil {
+		err := errors.New("unexpected tx_signatures")
+		p.failSplice(ctx, err)
+		return err
+	}
+
+	spliceTx := ctx.splice.SpliceTx
+	if msg.TxID != spliceTx.TxHash() {
+		err := fmt.Errorf("signatures for unknown splice tx %v",
+			msg.TxID)
+		p.failSplice(ctx, err)
+		return err
+	}
+	if msg.SharedInputSig == nil {
+		err := errors.New("missing shared input signature")
+		p.failSplice(ctx, err)
+		return err
+	}
+
+	err := ctx.tx.AddRemoteWitnesses(spliceTx, msg.Witnesses)
+	if err != nil {
+		p.failSplice(ctx, err)
+		return err
+	}
+	ctx.remoteSigs = msg
+
+	if err := p.maybeSendSpliceSigs(ctx); err != nil {
+		return err
+	}
+
+	return p.maybeCompleteSplice(ctx)
+}
+
+// maybeSendSpliceSigs sends our TxSignatures once the commitment signatures
+// have been exchanged and our inputs are signed. The responder sends its
+// signatures first, while the initiator waits for them before replying with
+// its own.
+func (p *Brontide) maybeSendSpliceSigs(ctx *spliceCtx) error {
+	if ctx.sentSigs || !ctx.recvCommitSig || !ctx.inputsSigned {
+		return nil
+	}
+	if ctx.initiator && ctx.remoteSigs == nil {
+		return nil
+	}
+
+	spliceTx := ctx.splice.SpliceTx
+	ctx.sentSigs = true
+	err := p.SendMessage(true, &lnwire.TxSignatures{
+		ChannelID:      ctx.chanID,
+		TxID:           spliceTx.TxHash(),
+		Witnesses:      ctx.tx.LocalWitnesses(spliceTx),
+		SharedInputSig: &ctx.localSharedSig,
+	})
+	if err != nil {
+		return err
+	}
+
+	return p.maybeCompleteSplice(ctx)
+}
+
+// maybeCompleteSplice completes the shared input of the splice transaction
+// once both parties sent their signatures, persists the splice and publishes
+// the transaction. The channel then resumes operation until a pending splice
+// is locked.
+func (p *Brontide) maybeCompleteSplice(ctx *spliceCtx) error {
+	if ctx.published || !ctx.sentSigs || ctx.remoteSigs == nil {
+		return nil
+	}
+
+	err := ctx.channel.CompleteSplice(
+		ctx.splice, ctx.localSharedSig, *ctx.remoteSigs.SharedInputSig,
+		ctx.tx.PrevOutFetcher(),
+	)
+	if err != nil {
+		// The remote party might still publish the splice
+		// transaction, so we keep the channel quiescent.
+		return err
+	}
+	ctx.published = true
+
+	spliceTx := ctx.splice.SpliceTx
+	txid := spliceTx.TxHash()
+
+	peerLog.Infof("Broadcasting splice tx %v for ChannelID(%v)", txid,
+		ctx.chanID)
+
+	label := labels.MakeLabel(labels.LabelTypeSplice, nil)
+	if err := p.cfg.Wallet.PublishTransaction(spliceTx, label); err != nil {
+		peerLog.Errorf("Unable to broadcast splice tx %v: %v", txid,
+			err)
+	}
+
+	if ctx.req != nil {
+		ctx.req.Updates <- &PendingUpdate{
+			Txid:        txid[:],
+			OutputIndex: ctx.splice.FundingOutpoint.Index,
+		}
+	}
+
+	state, ok := p.pendingSplices[ctx.chanID]
+	if !ok {
+		state = &pendingSpliceState{
+			initiator:   ctx.channel.State().IsInitiator,
+			policy:      ctx.policy,
+			doubleSpent: make(map[chainhash.Hash]struct{}),
+		}
+		p.pendingSplices[ctx.chanID] = state
+	}
+	if ctx.req != nil {
+		state.reqs = append(state.reqs, ctx.req)
+	}
+
+	chanPoint := *ctx.channel.ChannelPoint()
+	splices := ctx.channel.PendingSplices()
+	p.watchSpliceInputs(ctx.chanID, splices[len(splices)-1], chanPoint)
+
+	// The channel keeps operating with commitments signed for its current
+	// funding output and the funding outputs of all pending splices.
+	delete(p.activeSplices, ctx.chanID)
+	p.setSplicing(ctx.chanID, false)
+
+	return p.restoreLink(chanPoint, ctx.policy)
+}
+
+// handleSpliceFinalizeReq processes the signed PSBT of a PSBT funded splice.
+// The witnesses of our inputs are copied to the splice transaction.
+func (p *Brontide) handleSpliceFinalizeReq(req *spliceFinalizeReq) {
+	chanID := lnwire.NewChanIDFromOutPoint(&req.chanPoint)
+	ctx, ok := p.activeSplices[chanID]
+	if !ok || ctx.psbtInputs == nil || ctx.splice == nil ||
+		ctx.inputsSigned {
+
+		req.err <- ErrNoActiveSplice
+		return
+	}
+
+	spliceTx := ctx.splice.SpliceTx
+	packet := req.packet
+	if packet.UnsignedTx.TxHash() != spliceTx.TxHash() {
+		req.err <- errors.New("PSBT doesn't match splice transaction")
+		return
+	}
+
+	for i, txIn := range spliceTx.TxIn {
+		if _, ok := ctx.psbtInputs[txIn.PreviousOutPoint]; !ok {
+			continue
+		}
+
+		if err := psbt.Finalize(packet, i); err != nil {
+			req.err <- fmt.Errorf("unable to finalize input %v: %v",
+				txIn.PreviousOutPoint, err)
+			return
+		}
+
+		witness, err := parseWitness(packet.Inputs[i].FinalScriptWitness)
+		if err != nil {
+			req.err <- err
+			return
+		}
+		txIn.SignatureScript = packet.Inputs[i].FinalScriptSig
+		txIn.Witness = witness
+	}
+
+	// Make sure the signatures are valid before sending them to the
+	// remote party.
+	prevOutFetcher := ctx.tx.PrevOutFetcher()
+	sigHashes := txscript.NewTxSigHashes(spliceTx, prevOutFetcher)
+	for i, txIn := range spliceTx.TxIn {
+		if _, ok := ctx.psbtInputs[txIn.PreviousOutPoint]; !ok {
+			continue
+		}
+
+		prevOut := prevOutFetcher.FetchPrevOutput(txIn.PreviousOutPoint)
+		vm, err := txscript.NewEngine(
+			prevOut.PkScript, spliceTx, i,
+			txscript.StandardVerifyFlags, nil, sigHashes,
+			prevOut.Value, prevOutFetcher,
+		)
+		if err == nil {
+			err = vm.Execute()
+		}
+		if err != nil {
+			req.err <- fmt.Errorf("invalid signature for input "+
+				"%v: %v", txIn.PreviousOutPoint, err)
+			return
+		}
+	}
+
+	req.err <- p.signSpliceInputs(ctx)
+}
+
+// parseWitness parses a serialized witness stack as found in the final
+// script witness field of a PSBT input.
+func parseWitness(b []byte) (wire.TxWitness, error) {
+	r := bytes.NewReader(b)
+	numItems, err := wire.ReadVarInt(r, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	witness := make(wire.TxWitness, numItems)
+	for i := range witness {
+		witness[i], err = wire.ReadVarBytes(
+			r, 0, txscript.MaxScriptSize, "witness item",
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return witness, nil
+}
+
+// handleSpliceConfirmed sends SpliceLocked once a pending splice transaction
+// of a channel reached the depth at which it can be locked.
+func (p *Brontide) handleSpliceConfirmed(msg *spliceConfirmedMsg) {
+	chanID := lnwire.NewChanIDFromOutPoint(&msg.chanPoint)
+	state, ok := p.pendingSplices[chanID]
+	if !ok {
+		peerLog.Warnf("Splice of unknown ChannelPoint(%v) confirmed",
+			msg.chanPoint)
+		return
+	}
+
+	spliceTxid := msg.spliceTxid
+	state.localLocked = &spliceTxid
+
+	err := p.SendMessage(true, &lnwire.SpliceLocked{
+		ChannelID:  chanID,
+		SpliceTxID: spliceTxid,
+	})
+	if err != nil {
+		peerLog.Errorf("Unable to send splice_locked: %v", err)
+		return
+	}
+
+	p.maybeLockSplice(chanID)
+}
+
+// handleSpliceLocked processes the remote party's SpliceLocked message.
+func (p *Brontide) handleSpliceLocked(msg *lnwire.SpliceLocked) error {
+	state, ok := p.pendingSplices[msg.ChannelID]
+	if !ok {
+		// If the splice confirmed while we weren't connected, we
+		// already resumed the channel with its new funding outpoint.
+		// We reply, so the remote party resumes it as well.
+		if !p.hasChannelFundedBy(msg.SpliceTxID) {
+			return ErrNoActiveSplice
+		}
+
+		return p.SendMessage(true, &lnwire.SpliceLocked{
+			ChannelID:  msg.ChannelID,
+			SpliceTxID: msg.SpliceTxID,
+		})
+	}
+
+	if p.findPendingSplice(msg.ChannelID, msg.SpliceTxID) == nil {
+		return fmt.Errorf("splice_locked for unknown splice tx %v",
+			msg.SpliceTxID)
+	}
+
+	spliceTxid := msg.SpliceTxID
+	state.remoteLocked = &spliceTxid
+	p.maybeLockSplice(msg.ChannelID)
+
+	return nil
+}
+
+// handleSpliceTxAbort processes the remote party's TxAbort message, which
+// either aborts the splice being negotiated, or the pending splices of the
+// channel.
+func (p *Brontide) handleSpliceTxAbort(msg *lnwire.TxAbort) {
+	ctx, ok := p.activeSplices[msg.ChannelID]
+	if !ok {
+		return
+	}
+
+	_, hasPending := p.pendingSplices[msg.ChannelID]
+	switch {
+	// The remote party acknowledged our abort of the pending splices.
+	case ctx.action == spliceActionAbort && ctx.sentAbort:
+		p.removePendingSplices(ctx)
+
+	// The remote party aborts the pending splices of the quiescent
+	// channel. We only follow once we saw all of their splice
+	// transactions being double spent as well.
+	case !ctx.initiator && ctx.tx == nil && hasPending:
+		peerLog.Infof("Peer %v aborts pending splices of "+
+			"ChannelID(%v): %v", p, msg.ChannelID,
+			string(msg.Data))
+
+		ctx.action = spliceActionAbort
+		ctx.abortPending = true
+		p.maybeAckSpliceAbort(ctx)
+
+	default:
+		peerLog.Warnf("Peer %v aborted splice of ChannelID(%v): %v",
+			p, msg.ChannelID, string(msg.Data))
+
+		p.abortSplice(ctx, fmt.Errorf("splice aborted by peer: %v",
+			string(msg.Data)), false)
+	}
+}
+
+// hasChannelFundedBy returns true if we have an active channel whose funding
+// output was created by the given transaction.
+func (p *Brontide) hasChannelFundedBy(txid chainhash.Hash) bool {
+	p.activeChanMtx.RLock()
+	defer p.activeChanMtx.RUnlock()
+
+	for _, channel := range p.activeChannels {
+		if channel != nil && channel.ChannelPoint().Hash == txid {
+			return true
+		}
+	}
+
+	return false
+}
+
+// findPendingSplice returns the pending splice of the channel with the given
+// splice transaction, or nil if there is none.
+func (p *Brontide) findPendingSplice(chanID lnwire.ChannelID,
+	spliceTxid chainhash.Hash) *channeldb.PendingSplice {
+
+	p.activeChanMtx.RLock()
+	channel, ok := p.activeChannels[chanID]
+	p.activeChanMtx.RUnlock()
+	if !ok || channel == nil {
+		return nil
+	}
+
+	for _, splice := range channel.PendingSplices() {
+		if splice.SpliceTx.TxHash() == spliceTxid {
+			return splice
+		}
+	}
+
+	return nil
+}
+
+// lockableSplice returns the pending splice of the channel both parties sent
+// SpliceLocked for, or nil if there is none.
+func (p *Brontide) lockableSplice(
+	chanID lnwire.ChannelID) *channeldb.PendingSplice {
+
+	state, ok := p.pendingSplices[chanID]
+	if !ok || state.localLocked == nil || state.remoteLocked == nil ||
+		*state.localLocked != *state.remoteLocked {
+
+		return nil
+	}
+
+	return p.findPendingSplice(chanID, *state.localLocked)
+}
+
+// maybeLockSplice makes the channel quiescent to move it onto the funding
+// output of the splice both parties sent SpliceLocked for. Only the
+// initiator of the channel does so, the remote party locks the splice once
+// it received our Stfu.
+func (p *Brontide) maybeLockSplice(chanID lnwire.ChannelID) {
+	state, ok := p.pendingSplices[chanID]
+	if !ok || !state.initiator || p.lockableSplice(chanID) == nil {
+		return
+	}
+
+	p.quiesceForSplice(chanID, spliceActionLock)
+}
+
+// maybeAbortSplice makes the channel quiescent to remove its pending splices
+// once an input of each of their splice transactions has been double spent.
+// Only the initiator of the channel does so.
+func (p *Brontide) maybeAbortSplice(chanID lnwire.ChannelID) {
+	state, ok := p.pendingSplices[chanID]
+	if !ok || !state.initiator || state.localLocked != nil ||
+		!p.allSplicesDoubleSpent(chanID) {
+
+		return
+	}
+
+	p.quiesceForSplice(chanID, spliceActionAbort)
+}
+
+// allSplicesDoubleSpent returns true if none of the pending splice
+// transactions of the channel can confirm anymore.
+func (p *Brontide) allSplicesDoubleSpent(chanID lnwire.ChannelID) bool {
+	state, ok := p.pendingSplices[chanID]
+	if !ok {
+		return false
+	}
+
+	p.activeChanMtx.RLock()
+	channel, ok := p.activeChannels[chanID]
+	p.activeChanMtx.RUnlock()
+	if !ok || channel == nil {
+		return false
+	}
+
+	for _, splice := range channel.PendingSplices() {
+		txid := splice.SpliceTx.TxHash()
+		if _, ok := state.doubleSpent[txid]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// quiesceForSplice makes the channel quiescent to lock or abort its pending
+// splices. If the channel can't be made quiescent right now, we try again
+// later.
+func (p *Brontide) quiesceForSplice(chanID lnwire.ChannelID,
+	action spliceAction) {
+
+	// A splice being negotiated is aborted or completed before, after
+	// which we try again.
+	if _, ok := p.activeSplices[chanID]; ok {
+		p.scheduleSpliceRetry(chanID)
+		return
+	}
+
+	ctx, err := p.quiesceChannel(chanID, true)
+	if err != nil {
+		peerLog.Debugf("Unable to make ChannelID(%v) quiescent: %v",
+			chanID, err)
+		p.scheduleSpliceRetry(chanID)
+		return
+	}
+	ctx.action = action
+
+	ctx.sentStfu = true
+	err = p.SendMessage(true, &lnwire.Stfu{
+		ChannelID: chanID,
+		Initiator: true,
+	})
+	if err != nil {
+		p.failSplice(ctx, err)
+	}
+}
+
+// scheduleSpliceRetry tries again to lock or abort the pending splices of the
+// channel after spliceRetryInterval.
+func (p *Brontide) scheduleSpliceRetry(chanID lnwire.ChannelID) {
+	state, ok := p.pendingSplices[chanID]
+	if !ok || state.retryScheduled {
+		return
+	}
+	state.retryScheduled = true
+
+	time.AfterFunc(spliceRetryInterval, func() {
+		select {
+		case p.spliceRetries <- chanID:
+		case <-p.quit:
+		}
+	})
+}
+
+// handleSpliceRetry tries again to lock or abort the pending splices of the
+// channel.
+func (p *Brontide) handleSpliceRetry(chanID lnwire.ChannelID) {
+	state, ok := p.pendingSplices[chanID]
+	if !ok {
+		return
+	}
+	state.retryScheduled = false
+
+	p.maybeLockSplice(chanID)
+	p.maybeAbortSplice(chanID)
+}
+
+// lockSplice moves the quiescent channel onto the funding output of the
+// splice both parties sent SpliceLocked for. From then on, the channel
+// operates with its new funding outpoint and channel ID.
+func (p *Brontide) lockSplice(ctx *spliceCtx) {
+	splice := p.lockableSplice(ctx.chanID)
+	if splice == nil {
+		p.failSplice(ctx, errors.New("no splice to lock"))
+		return
+	}
+	state := p.pendingSplices[ctx.chanID]
+
+	spliceTxid := splice.SpliceTx.TxHash()
+	chanPoint := splice.FundingOutpoint
+	oldChanPoint := *ctx.channel.ChannelPoint()
+	err := p.cfg.ChainArb.LockSplice(oldChanPoint, spliceTxid)
+	if err != nil {
+		p.failSplice(ctx, fmt.Errorf("unable to lock splice: %v", err))
+		return
+	}
+
+	delete(p.activeSplices, ctx.chanID)
+	delete(p.pendingSplices, ctx.chanID)
+	p.setSplicing(ctx.chanID, false)
+
+	p.activeChanMtx.Lock()
+	delete(p.activeChannels, ctx.chanID)
+	p.activeChanMtx.Unlock()
+
+	err = p.restoreLink(chanPoint, state.policy)
+	if err != nil {
+		peerLog.Errorf("Unable to resume spliced ChannelPoint(%v): %v",
+			chanPoint, err)
+		for _, req := range state.reqs {
+			req.Err <- err
+		}
+		return
+	}
+
+	peerLog.Infof("Spliced channel active ChannelPoint(%v) with "+
+		"NodeKey(%x)", chanPoint, p.PubKey())
+
+	// The old channel edge is pruned from the graph as its funding output
+	// has been spent, so the channel needs to be announced again.
+	channel, err := p.cfg.ChannelDB.FetchChannel(nil, chanPoint)
+	if err == nil {
+		err = p.cfg.FundingManager.AnnounceSplicedChannel(channel)
+	}
+	if err != nil {
+		peerLog.Errorf("Unable to announce spliced ChannelPoint(%v): "+
+			"%v", chanPoint, err)
+	}
+
+	for _, req := range state.reqs {
+		req.Updates <- &SpliceLockedUpdate{ChanPoint: chanPoint}
+	}
+}
+
+// watchSpliceInputs watches the inputs of a pending splice transaction that
+// don't belong to the channel, so the pending splices can be aborted once an
+// input of each of their splice transactions has been double spent.
+func (p *Brontide) watchSpliceInputs(chanID lnwire.ChannelID,
+	splice *channeldb.PendingSplice, fundingOutpoint wire.OutPoint) {
+
+	spliceTx := splice.SpliceTx
+	spliceTxid := spliceTx.TxHash()
+	for i, txIn := range spliceTx.TxIn {
+		if txIn.PreviousOutPoint == fundingOutpoint ||
+			i >= len(splice.PrevOutScripts) {
+
+			continue
+		}
+
+		outpoint := txIn.PreviousOutPoint
+		spendNtfn, err := p.cfg.ChainNotifier.RegisterSpendNtfn(
+			&outpoint, splice.PrevOutScripts[i], spliceTx.LockTime,
+		)
+		if err != nil {
+			peerLog.Errorf("Unable to watch input %v of splice tx "+
+				"%v: %v", outpoint, spliceTxid, err)
+			continue
+		}
+
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			defer spendNtfn.Cancel()
+
+			var spend *chainntnfs.SpendDetail
+			select {
+			case spend = <-spendNtfn.Spend:
+				if spend == nil {
+					return
+				}
+			case <-p.quit:
+				return
+			}
+
+			select {
+			case p.spliceInputSpends <- &spliceInputSpend{
+				chanID:     chanID,
+				spliceTxid: spliceTxid,
+				spend:      spend,
+			}:
+			case <-p.quit:
+			}
+		}()
+	}
+}
+
+// handleSpliceInputSpend processes the spend of an input of a pending splice
+// transaction. If it has been spent by another transaction, the splice
+// transaction can't confirm anymore.
+func (p *Brontide) handleSpliceInputSpend(msg *spliceInputSpend) {
+	state, ok := p.pendingSplices[msg.chanID]
+	if !ok {
+		return
+	}
+
+	// An input is shared by all pending splice transactions, so it's
+	// spent by one of them unless the splice is double spent.
+	if p.findPendingSplice(msg.chanID, *msg.spend.SpenderTxHash) != nil {
+		return
+	}
+
+	peerLog.Warnf("Input of splice tx %v of ChannelID(%v) double spent "+
+		"by %v", msg.spliceTxid, msg.chanID, msg.spend.SpenderTxHash)
+
+	state.doubleSpent[msg.spliceTxid] = struct{}{}
+
+	if ctx, ok := p.activeSplices[msg.chanID]; ok && ctx.abortPending {
+		p.maybeAckSpliceAbort(ctx)
+		return
+	}
+	p.maybeAbortSplice(msg.chanID)
+}
+
+// maybeAckSpliceAbort acknowledges the remote party's abort of the pending
+// splices once we saw all of their splice transactions being double spent.
+func (p *Brontide) maybeAckSpliceAbort(ctx *spliceCtx) {
+	if !p.allSplicesDoubleSpent(ctx.chanID) {
+		return
+	}
+
+	ctx.sentAbort = true
+	p.sendTxAbort(ctx.chanID, ErrSpliceDoubleSpent)
+	p.removePendingSplices(ctx)
+}
+
+// removePendingSplices removes the pending splices of the quiescent channel,
+// which resumes with commitments signed only for its current funding output.
+func (p *Brontide) removePendingSplices(ctx *spliceCtx) {
+	state := p.pendingSplices[ctx.chanID]
+
+	if err := ctx.channel.RemovePendingSplices(); err != nil {
+		p.failSplice(ctx, fmt.Errorf("unable to remove pending "+
+			"splices: %v", err))
+		return
+	}
+
+	peerLog.Infof("Removed pending splices of ChannelID(%v)", ctx.chanID)
+
+	delete(p.activeSplices, ctx.chanID)
+	delete(p.pendingSplices, ctx.chanID)
+	p.setSplicing(ctx.chanID, false)
+
+	policy := ctx.policy
+	if state != nil {
+		policy = state.policy
+		for _, req := range state.reqs {
+			req.Err <- ErrSpliceDoubleSpent
+		}
+	}
+
+	chanPoint := ctx.channel.ChannelPoint()
+	if err := p.restoreLink(*chanPoint, policy); err != nil {
+		peerLog.Errorf("Unable to restore link of ChannelPoint(%v): %v",
+			chanPoint, err)
+	}
+}
+
+// restoreLink creates a new LightningChannel from the persisted state of the
+// channel and adds its link to the switch, re-establishing the channel with
+// the remote party.
+func (p *Brontide) restoreLink(chanPoint wire.OutPoint,
+	policy *htlcswitch.ForwardingPolicy) error {
+
+	dbChan, err := p.cfg.ChannelDB.FetchChannel(nil, chanPoint)
+	if err != nil {
+		return err
+	}
+
+	lnChan, err := lnwallet.NewLightningChannel(
+		p.cfg.Signer, dbChan, p.cfg.SigPool,
+	)
+	if err != nil {
+		return err
+	}
+
+	chainEvents, err := p.cfg.ChainArb.SubscribeChannelEvents(chanPoint)
+	if err != nil {
+		return err
+	}
+
+	chanID := lnwire.NewChanIDFromOutPoint(&chanPoint)
+	p.activeChanMtx.Lock()
+	p.activeChannels[chanID] = lnChan
+	p.activeChanMtx.Unlock()
+
+	return p.addLink(&chanPoint, lnChan, policy, chainEvents, true)
+}
+
+// sendTxAbort informs the remote party that we abort the splice.
+func (p *Brontide) sendTxAbort(chanID lnwire.ChannelID, reason error) {
+	err := p.SendMessage(true, &lnwire.TxAbort{
+		ChannelID: chanID,
+		Data:      lnwire.ErrorData(reason.Error()),
+	})
+	if err != nil {
+		peerLog.Errorf("Unable to send tx_abort: %v", err)
+	}
+}
+
+// failSplice aborts the splice due to the given error and informs the remote
+// party.
+func (p *Brontide) failSplice(ctx *spliceCtx, reason error) {
+	p.abortSplice(ctx, reason, true)
+}
+
+// abortSplice aborts the splice due to the given error, optionally informing
+// the remote party. Once we sent our signatures, the splice can't be aborted
+// anymore, as the remote party may publish the splice transaction.
+func (p *Brontide) abortSplice(ctx *spliceCtx, reason error,
+	notifyRemote bool) {
+
+	if ctx.sentSigs {
+		peerLog.Errorf("Error after signing splice of ChannelID(%v): "+
+			"%v", ctx.chanID, reason)
+		return
+	}
+
+	peerLog.Warnf("Aborting splice of ChannelID(%v): %v", ctx.chanID,
+		reason)
+
+	if notifyRemote {
+		p.sendTxAbort(ctx.chanID, reason)
+	}
+
+	if ctx.walletIntent != nil {
+		ctx.walletIntent.Cancel()
+	}
+
+	delete(p.activeSplices, ctx.chanID)
+	p.setSplicing(ctx.chanID, false)
+
+	if ctx.req != nil {
+		ctx.req.Err <- reason
+	}
+
+	// The channel resumes with its current funding output.
+	chanPoint := ctx.channel.ChannelPoint()
+	if err := p.restoreLink(*chanPoint, ctx.policy); err != nil {
+		peerLog.Errorf("Unable to restore link of ChannelPoint(%v): %v",
+			chanPoint, err)
+	}
+
+	// The pending splices of the channel still need to be locked or
+	// aborted.
+	if _, ok := p.pendingSplices[ctx.chanID]; ok {
+		p.scheduleSpliceRetry(ctx.chanID)
+	}
+}
+
+// resumePendingSplice tracks the pending splices of a channel after a
+// restart, until one of them is locked or all of them are aborted. The splice
+// transactions are published again in case they didn't make it into the
+// mempool.
+func (p *Brontide) resumePendingSplice(chanID lnwire.ChannelID,
+	lnChan *lnwallet.LightningChannel, splices []*channeldb.PendingSplice,
+	policy *htlcswitch.ForwardingPolicy) {
+
+	p.pendingSplices[chanID] = &pendingSpliceState{
+		initiator:   lnChan.State().IsInitiator,
+		policy:      policy,
+		doubleSpent: make(map[chainhash.Hash]struct{}),
+	}
+
+	chanPoint := *lnChan.ChannelPoint()
+	label := labels.MakeLabel(labels.LabelTypeSplice, nil)
+	for _, splice := range splices {
+		err := p.cfg.Wallet.PublishTransaction(splice.SpliceTx, label)
+		if err != nil {
+			peerLog.Warnf("Unable to republish splice tx %v: %v",
+				splice.SpliceTx.TxHash(), err)
+		}
+
+		p.watchSpliceInputs(chanID, splice, chanPoint)
+	}
+
+	// If a splice transaction reached the lock depth while we weren't
+	// connected, the notification of the chain arbitrator was missed.
+	spliceTxid, ok := p.cfg.ChainArb.ConfirmedSplice(chanPoint)
+	if ok {
+		go p.HandleSpliceConfirmed(chanPoint, spliceTxid)
+	}
+}
@@ -0,0 +1,55 @@
+package peer
+
+import (
+	"testing"
+
+	"github.com/brronsuite/broln/channeldb"
+	"github.com/brronsuite/broln/lnwallet/chainfee"
+	"github.com/brronsuite/bronutil"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMinSpliceRbfFeeRate tests that a replacement of pending splices must
+// exceed the highest fee rate among them by at least 1/24.
+func TestMinSpliceRbfFeeRate(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		feeRates []chainfee.SatPerKWeight
+		expected chainfee.SatPerKWeight
+	}{
+		{
+			name:     "single splice",
+			feeRates: []chainfee.SatPerKWeight{2400},
+			expected: 2500,
+		},
+		{
+			name:     "rounded up",
+			feeRates: []chainfee.SatPerKWeight{253},
+			expected: 264,
+		},
+		{
+			name:     "highest fee rate",
+			feeRates: []chainfee.SatPerKWeight{2400, 4800, 3600},
+			expected: 5000,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			var splices []*channeldb.PendingSplice
+			for _, feeRate := range tc.feeRates {
+				splice := &channeldb.PendingSplice{
+					FeePerKw: bronutil.Amount(feeRate),
+				}
+				splices = append(splices, splice)
+			}
+
+			minFeeRate := minSpliceRbfFeeRate(splices)
+			require.Equal(t, tc.expected, minFeeRate)
+		})
+	}
+}
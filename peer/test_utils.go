@@ -367,7 +367,9 @@ func createTestPeer(notifier chainntnfs.ChainNotifier,
 		Switch:      mockSwitch,
 
 		ChanActiveTimeout: chanActiveTimeout,
-		InterceptSwitch:   htlcswitch.NewInterceptableSwitch(nil),
+		InterceptSwitch: htlcswitch.NewInterceptableSwitch(
+			&htlcswitch.InterceptableSwitchConfig{},
+		),
 
 		ChannelDB:      dbAlice.ChannelStateDB(),
 		FeeEstimator:   estimator,
@@ -375,9 +377,11 @@ func createTestPeer(notifier chainntnfs.ChainNotifier,
 		ChainNotifier:  notifier,
 		ChanStatusMgr:  chanStatusMgr,
 		DisconnectPeer: func(b *bronec.PublicKey) error { return nil },
+		Features:       lnwire.EmptyFeatureVector(),
 	}
 
 	alicePeer := NewBrontide(*cfg)
+	alicePeer.remoteFeatures = lnwire.EmptyFeatureVector()
 
 	chanID := lnwire.NewChanIDFromOutPoint(channelAlice.ChannelPoint())
 	alicePeer.activeChannels[chanID] = channelAlice
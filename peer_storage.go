@@ -0,0 +1,337 @@
+package broln
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/brronsuite/broln/chanbackup"
+	"github.com/brronsuite/broln/channeldb"
+	"github.com/brronsuite/broln/channelnotifier"
+	"github.com/brronsuite/broln/keychain"
+	"github.com/brronsuite/broln/lnwire"
+	"github.com/brronsuite/broln/peer"
+	"github.com/brronsuite/broln/routing/route"
+	"github.com/brronsuite/broln/subscribe"
+	"github.com/brronsuite/brond/bronec"
+	"github.com/brronsuite/brond/wire"
+)
+
+// peerStorageSwapper is a chanbackup.Swapper that, in addition to swapping the
+// on-disk multi-channel backup, keeps the latest packed backup around and
+// hands it to all of our peers that offer to store it for us. As the packed
+// backup is encrypted with a key derived from our seed, peers learn nothing
+// about our channels from it. The commitment state of the channels is never
+// handed to our peers, as a peer could hand us back a revoked one.
+type peerStorageSwapper struct {
+	chanbackup.Swapper
+
+	// keyRing is the key ring the backups are encrypted with.
+	keyRing keychain.KeyRing
+
+	// peers returns all peers we're currently connected to.
+	peers func() []*peer.Brontide
+
+	mu     sync.RWMutex
+	backup chanbackup.PackedMulti
+
+	// plaintext is the plaintext of the backup we last handed to our
+	// peers, which allows us to skip handing out unchanged backups.
+	plaintext []byte
+}
+
+// A compile-time constraint to ensure peerStorageSwapper implements
+// chanbackup.Swapper.
+var _ chanbackup.Swapper = (*peerStorageSwapper)(nil)
+
+// UpdateAndSwap updates the on-disk multi-channel backup and hands the new
+// backup to all active peers that offer storage.
+//
+// NOTE: Part of the chanbackup.Swapper interface.
+func (p *peerStorageSwapper) UpdateAndSwap(
+	newBackup chanbackup.PackedMulti) error {
+
+	if err := p.Swapper.UpdateAndSwap(newBackup); err != nil {
+		return err
+	}
+
+	// A backup without any channels would replace the backup our peers
+	// hold for us, which we may still need after losing our channel
+	// state, so we never hand it out.
+	multi, err := newBackup.Unpack(p.keyRing)
+	if err != nil {
+		return err
+	}
+	if len(multi.StaticBackups) == 0 {
+		return nil
+	}
+
+	// Most updates of the backup only advance the commitment state of a
+	// channel, which we don't hand out, so we skip handing the backup to
+	// our peers if nothing else changed.
+	peerMulti := multi.WithoutCommitState()
+
+	var plaintext bytes.Buffer
+	if err := peerMulti.Serialize(&plaintext); err != nil {
+		return err
+	}
+
+	p.mu.RLock()
+	unchanged := bytes.Equal(plaintext.Bytes(), p.plaintext)
+	p.mu.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	var b bytes.Buffer
+	if err := peerMulti.PackToWriter(&b, p.keyRing); err != nil {
+		return err
+	}
+	peerBackup := chanbackup.PackedMulti(b.Bytes())
+
+	if len(peerBackup) > lnwire.MaxPeerStorageBlobSize {
+		srvrLog.Warnf("Channel backup of %v bytes exceeds peer "+
+			"storage limit of %v bytes, not handing it to peers",
+			len(peerBackup), lnwire.MaxPeerStorageBlobSize)
+		return nil
+	}
+
+	p.mu.Lock()
+	p.backup = peerBackup
+	p.plaintext = plaintext.Bytes()
+	p.mu.Unlock()
+
+	msg := lnwire.NewPeerStorage(peerBackup)
+	for _, peer := range p.peers() {
+		// Peers that haven't completed the init exchange yet will be
+		// handed the new backup once they have.
+		select {
+		case <-peer.ActiveSignal():
+		default:
+			continue
+		}
+
+		if !peer.RemoteFeatures().HasFeature(
+			lnwire.ProvideStorageOptional,
+		) {
+
+			continue
+		}
+
+		if err := peer.SendMessageLazy(false, msg); err != nil {
+			srvrLog.Warnf("Unable to hand channel backup to "+
+				"peer %v: %v", peer, err)
+		}
+	}
+
+	return nil
+}
+
+// Backup returns the latest packed multi-channel backup we hand to our peers,
+// or nil if we don't have one yet.
+func (p *peerStorageSwapper) Backup() []byte {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.backup
+}
+
+// storePeerStorage stores the backup blob the given peer asked us to hold on
+// its behalf.
+func (s *server) storePeerStorage(peer [33]byte, blob []byte) error {
+	return s.miscDB.StorePeerStorage(peer, blob)
+}
+
+// fetchPeerStorage returns the backup blob the given peer last asked us to
+// hold on its behalf, or nil if we don't hold one.
+func (s *server) fetchPeerStorage(peer [33]byte) ([]byte, error) {
+	blob, err := s.miscDB.FetchPeerStorage(peer)
+	if errors.Is(err, channeldb.ErrNoPeerStorage) {
+		return nil, nil
+	}
+
+	return blob, err
+}
+
+// handlePeerStorageRetrieval unpacks the backup a peer handed back to us and,
+// if restore-from-peer-storage is set, restores all channels found in it that
+// we don't know of. This allows a node that lost its channel state to recover
+// its channels from its seed alone once it reconnects to its peers. As
+// restoring a channel makes the peer force close it, it's never done unless
+// explicitly asked for.
+func (s *server) handlePeerStorageRetrieval(peer [33]byte,
+	blob []byte) error {
+
+	// Only a backup encrypted with a key derived from our seed can be
+	// unpacked, so a peer can't hand us channels we never had.
+	packedMulti := chanbackup.PackedMulti(blob)
+	multi, err := packedMulti.Unpack(s.cc.KeyRing)
+	if err != nil {
+		return fmt.Errorf("unable to unpack channel backup: %v", err)
+	}
+
+	s.restoringChansMtx.Lock()
+	defer s.restoringChansMtx.Unlock()
+
+	var unknownChans []chanbackup.Single
+	for _, single := range multi.StaticBackups {
+		// Several peers may hand back the same channel, so we skip the
+		// ones we're already restoring.
+		chanPoint := single.FundingOutpoint
+		if _, ok := s.restoringChans[chanPoint]; ok {
+			continue
+		}
+
+		known, err := s.isKnownChannel(chanPoint)
+		if err != nil {
+			return err
+		}
+
+		if !known {
+			unknownChans = append(unknownChans, single)
+		}
+	}
+
+	if len(unknownChans) == 0 {
+		return nil
+	}
+
+	if !s.cfg.RestoreFromPeerStorage {
+		srvrLog.Warnf("Channel backup handed back by peer %x contains "+
+			"%v channels we don't know of, restart with "+
+			"--restore-from-peer-storage to restore them",
+			peer, len(unknownChans))
+		return nil
+	}
+
+	srvrLog.Infof("Restoring %v unknown channels from channel backup "+
+		"handed back by peer %x", len(unknownChans), peer)
+
+	for _, single := range unknownChans {
+		s.restoringChans[single.FundingOutpoint] = struct{}{}
+	}
+
+	chanRestorer := &chanDBRestorer{
+		db:         s.chanStateDB,
+		secretKeys: s.cc.KeyRing,
+		chainArb:   s.chainArb,
+	}
+
+	// Recovering the channels reconnects to their peers, which includes
+	// the peer whose read handler called us, so we do it in the
+	// background.
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		err := chanbackup.Recover(unknownChans, chanRestorer, s)
+		if err != nil {
+			srvrLog.Errorf("Unable to restore channels from "+
+				"channel backup handed back by peer %x: %v",
+				peer, err)
+		}
+
+		// Restored channels are now known, while the ones we failed
+		// to restore may be retried once a peer hands them back
+		// again.
+		s.restoringChansMtx.Lock()
+		for _, single := range unknownChans {
+			delete(s.restoringChans, single.FundingOutpoint)
+		}
+		s.restoringChansMtx.Unlock()
+	}()
+
+	return nil
+}
+
+// isKnownChannel returns true if the channel with the given funding outpoint
+// is either open or closed according to our channel database.
+func (s *server) isKnownChannel(chanPoint wire.OutPoint) (bool, error) {
+	_, err := s.chanStateDB.FetchChannel(nil, chanPoint)
+	switch {
+	case err == nil:
+		return true, nil
+
+	case !errors.Is(err, channeldb.ErrChannelNotFound) &&
+		!errors.Is(err, channeldb.ErrNoActiveChannels):
+
+		return false, err
+	}
+
+	_, err = s.chanStateDB.FetchClosedChannel(&chanPoint)
+	switch {
+	case err == nil:
+		return true, nil
+
+	case errors.Is(err, channeldb.ErrClosedChannelNotFound):
+		return false, nil
+
+	default:
+		return false, err
+	}
+}
+
+// prunePeerStorage deletes the backup blobs we hold for peers we no longer have
+// any channels with. The blobs of peers whose channels closed while we were
+// offline are pruned right away, all others once their last channel closes.
+//
+// NOTE: This MUST be run as a goroutine.
+func (s *server) prunePeerStorage(chanSub *subscribe.Client) {
+	defer s.wg.Done()
+	defer chanSub.Cancel()
+
+	peers, err := s.miscDB.FetchPeerStoragePeers()
+	if err != nil {
+		srvrLog.Errorf("Unable to fetch peers we hold peer storage "+
+			"blobs for: %v", err)
+	}
+	for _, peer := range peers {
+		s.maybePrunePeerStorage(peer)
+	}
+
+	for {
+		select {
+		case e := <-chanSub.Updates():
+			event, ok := e.(channelnotifier.ClosedChannelEvent)
+			if !ok {
+				continue
+			}
+
+			s.maybePrunePeerStorage(
+				route.NewVertex(event.CloseSummary.RemotePub),
+			)
+
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// maybePrunePeerStorage deletes the backup blob we hold for the given peer if
+// we don't have any open or pending channels with it anymore.
+func (s *server) maybePrunePeerStorage(peer route.Vertex) {
+	pubKey, err := bronec.ParsePubKey(peer[:])
+	if err != nil {
+		srvrLog.Errorf("Unable to parse peer key %x: %v", peer, err)
+		return
+	}
+
+	channels, err := s.chanStateDB.FetchOpenChannels(pubKey)
+	if err != nil {
+		srvrLog.Errorf("Unable to fetch channels of peer %x: %v",
+			peer, err)
+		return
+	}
+	if len(channels) > 0 {
+		return
+	}
+
+	srvrLog.Debugf("Pruning peer storage blob of peer %x without "+
+		"channels", peer)
+
+	if err := s.miscDB.StorePeerStorage(peer, nil); err != nil {
+		srvrLog.Errorf("Unable to prune peer storage blob of peer "+
+			"%x: %v", peer, err)
+	}
+}
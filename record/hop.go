@@ -2,6 +2,7 @@ package record
 
 import (
 	"github.com/brronsuite/broln/tlv"
+	"github.com/brronsuite/brond/bronec"
 )
 
 const (
@@ -16,6 +17,18 @@ const (
 	// NextHopOnionType is the type used in the onion to reference the ID
 	// of the next hop.
 	NextHopOnionType tlv.Type = 6
+
+	// EncryptedDataOnionType is the type used in the onion to reference
+	// the encrypted recipient data of a blinded hop.
+	EncryptedDataOnionType tlv.Type = 10
+
+	// BlindingPointOnionType is the type used in the onion to reference
+	// the blinding point of the introduction node of a blinded route.
+	BlindingPointOnionType tlv.Type = 12
+
+	// TotalAmtMsatBlindedType is the type used in the onion to reference
+	// the total amount of a payment to a blinded route.
+	TotalAmtMsatBlindedType tlv.Type = 18
 )
 
 // NewAmtToFwdRecord creates a tlv.Record that encodes the amount_to_forward
@@ -45,3 +58,27 @@ func NewLockTimeRecord(lockTime *uint32) tlv.Record {
 func NewNextHopIDRecord(cid *uint64) tlv.Record {
 	return tlv.MakePrimitiveRecord(NextHopOnionType, cid)
 }
+
+// NewEncryptedDataRecord creates a tlv.Record that encodes the
+// encrypted_recipient_data (type 10) for an onion payload.
+func NewEncryptedDataRecord(data *[]byte) tlv.Record {
+	return tlv.MakePrimitiveRecord(EncryptedDataOnionType, data)
+}
+
+// NewBlindingPointRecord creates a tlv.Record that encodes the
+// current_blinding_point (type 12) for an onion payload.
+func NewBlindingPointRecord(point **bronec.PublicKey) tlv.Record {
+	return tlv.MakePrimitiveRecord(BlindingPointOnionType, point)
+}
+
+// NewTotalAmtMsatBlindedRecord creates a tlv.Record that encodes the
+// total_amount_msat (type 18) of a payment to a blinded route for an onion
+// payload.
+func NewTotalAmtMsatBlindedRecord(amt *uint64) tlv.Record {
+	return tlv.MakeDynamicRecord(
+		TotalAmtMsatBlindedType, amt, func() uint64 {
+			return tlv.SizeTUint64(*amt)
+		},
+		tlv.ETUint64, tlv.DTUint64,
+	)
+}
@@ -0,0 +1,52 @@
+package record
+
+import (
+	"github.com/brronsuite/broln/tlv"
+	"github.com/brronsuite/brond/bronec"
+)
+
+const (
+	// InvoiceFeaturesOnionType is the type used in a trampoline payload to
+	// reference the feature bits of the recipient's invoice, for payments
+	// to recipients that don't support trampoline routing themselves.
+	InvoiceFeaturesOnionType tlv.Type = 66097
+
+	// OutgoingNodeIDOnionType is the type used in a trampoline payload to
+	// reference the public key of the next trampoline node or the final
+	// recipient.
+	OutgoingNodeIDOnionType tlv.Type = 66098
+
+	// InvoiceRoutingInfoOnionType is the type used in a trampoline payload
+	// to reference the route hints of the recipient's invoice, for
+	// payments to recipients that don't support trampoline routing
+	// themselves.
+	InvoiceRoutingInfoOnionType tlv.Type = 66099
+
+	// TrampolineOnionType is the type used in the onion to reference the
+	// trampoline onion packet destined for the trampoline node.
+	TrampolineOnionType tlv.Type = 66100
+)
+
+// NewInvoiceFeaturesRecord creates a tlv.Record that encodes the
+// invoice_features (type 66097) for a trampoline payload.
+func NewInvoiceFeaturesRecord(features *[]byte) tlv.Record {
+	return tlv.MakePrimitiveRecord(InvoiceFeaturesOnionType, features)
+}
+
+// NewOutgoingNodeIDRecord creates a tlv.Record that encodes the
+// outgoing_node_id (type 66098) for a trampoline payload.
+func NewOutgoingNodeIDRecord(nodeID **bronec.PublicKey) tlv.Record {
+	return tlv.MakePrimitiveRecord(OutgoingNodeIDOnionType, nodeID)
+}
+
+// NewInvoiceRoutingInfoRecord creates a tlv.Record that encodes the
+// invoice_routing_info (type 66099) for a trampoline payload.
+func NewInvoiceRoutingInfoRecord(routingInfo *[]byte) tlv.Record {
+	return tlv.MakePrimitiveRecord(InvoiceRoutingInfoOnionType, routingInfo)
+}
+
+// NewTrampolineOnionRecord creates a tlv.Record that encodes the
+// trampoline_onion_packet (type 66100) for an onion payload.
+func NewTrampolineOnionRecord(onion *[]byte) tlv.Record {
+	return tlv.MakePrimitiveRecord(TrampolineOnionType, onion)
+}
@@ -0,0 +1,108 @@
+package routing
+
+import (
+	"errors"
+
+	"github.com/brronsuite/broln/blinding"
+	"github.com/brronsuite/broln/lnwire"
+	"github.com/brronsuite/broln/routing/route"
+)
+
+var (
+	// ErrBlindedPathTooShort is returned when a payment should be sent to
+	// a blinded path without any hops.
+	ErrBlindedPathTooShort = errors.New("blinded path without hops")
+)
+
+// BlindedPayment describes a blinded path to the destination of a payment
+// together with the aggregated relay parameters of the path that the
+// recipient provided to us.
+type BlindedPayment struct {
+	// Path is the blinded path to the destination. The route to the
+	// introduction node of the path is found through path finding, while
+	// the remainder of the route is given by the path.
+	Path *blinding.BlindedPath
+
+	// BaseFee is the total base fee of the blinded part of the route.
+	BaseFee uint32
+
+	// ProportionalFee is the total proportional fee of the blinded part
+	// of the route in millionths.
+	ProportionalFee uint32
+
+	// CltvExpiryDelta is the total CLTV delta of the blinded part of the
+	// route, including the final CLTV delta of the destination.
+	CltvExpiryDelta uint16
+
+	// HtlcMinimum is the minimum amount that the path accepts.
+	HtlcMinimum lnwire.MilliBronees
+
+	// HtlcMaximum is the maximum amount that the path accepts.
+	HtlcMaximum lnwire.MilliBronees
+}
+
+// Validate checks that the blinded payment can be used to send the given
+// amount.
+func (b *BlindedPayment) Validate(amt lnwire.MilliBronees) error {
+	if b.Path == nil || len(b.Path.BlindedHops) == 0 {
+		return ErrBlindedPathTooShort
+	}
+
+	if amt < b.HtlcMinimum {
+		return errors.New("amount below minimum of blinded path")
+	}
+
+	if b.HtlcMaximum != 0 && amt > b.HtlcMaximum {
+		return errors.New("amount above maximum of blinded path")
+	}
+
+	return nil
+}
+
+// IntroductionNode returns the introduction node of the blinded path, which
+// is the target of path finding.
+func (b *BlindedPayment) IntroductionNode() route.Vertex {
+	return route.NewVertex(b.Path.IntroductionPoint)
+}
+
+// fee returns the fee that the blinded part of the route charges for
+// delivering the given amount to the destination. The hops compute the amount
+// to forward from the amount they receive rounding up, so charging the
+// rounded up proportional fee on the delivered amount is always sufficient.
+func (b *BlindedPayment) fee(amt lnwire.MilliBronees) lnwire.MilliBronees {
+	proportional := (uint64(amt)*uint64(b.ProportionalFee) + 999_999) /
+		1_000_000
+
+	return lnwire.MilliBronees(uint64(b.BaseFee) + proportional)
+}
+
+// extendRoute appends the blinded part of the route to the route to the
+// introduction node. The route must deliver the amount plus the fee of the
+// blinded part to the introduction node. Within the blinded part, the hops
+// learn the amounts and expiries from their encrypted data, so only the
+// final hop receives the amount and expiry from us.
+func (b *BlindedPayment) extendRoute(rt *route.Route,
+	amt, totalAmt lnwire.MilliBronees, finalExpiry uint32) {
+
+	blindedHops := b.Path.BlindedHops
+
+	intro := rt.Hops[len(rt.Hops)-1]
+	intro.EncryptedData = blindedHops[0].CipherText
+	intro.BlindingPoint = b.Path.BlindingPoint
+	intro.LegacyPayload = false
+
+	for _, blindedHop := range blindedHops[1:] {
+		pubKey := route.NewVertex(blindedHop.BlindedNodePub)
+		rt.Hops = append(rt.Hops, &route.Hop{
+			PubKeyBytes:      pubKey,
+			AmtToForward:     intro.AmtToForward,
+			OutgoingTimeLock: intro.OutgoingTimeLock,
+			EncryptedData:    blindedHop.CipherText,
+		})
+	}
+
+	final := rt.Hops[len(rt.Hops)-1]
+	final.AmtToForward = amt
+	final.OutgoingTimeLock = finalExpiry
+	final.TotalAmtMsat = totalAmt
+}
@@ -0,0 +1,482 @@
+package feemanager
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/brronsuite/broln/channeldb"
+	"github.com/brronsuite/broln/clock"
+	"github.com/brronsuite/broln/kvdb"
+	"github.com/brronsuite/broln/lnrpc"
+	"github.com/brronsuite/broln/lnwire"
+	"github.com/brronsuite/broln/routing"
+	"github.com/brronsuite/broln/ticker"
+	"github.com/brronsuite/brond/wire"
+)
+
+const (
+	// maxEventsPerQuery is the maximum number of forwarding events that
+	// we fetch from the forwarding log at once.
+	maxEventsPerQuery = 10000
+)
+
+// SkipReason describes why the fee manager doesn't update the fee rate of a
+// channel.
+type SkipReason string
+
+const (
+	// SkipNone indicates that the fee rate of the channel is updated.
+	SkipNone SkipReason = ""
+
+	// SkipNoPolicy indicates that we don't know our own policy for the
+	// channel yet.
+	SkipNoPolicy SkipReason = "no local policy"
+
+	// SkipUnchanged indicates that the channel already has the target fee
+	// rate.
+	SkipUnchanged SkipReason = "fee rate unchanged"
+
+	// SkipBelowThreshold indicates that the difference between the
+	// current and the target fee rate is too small to warrant a new
+	// channel update.
+	SkipBelowThreshold SkipReason = "change below threshold"
+
+	// SkipRateLimited indicates that the policy of the channel was updated
+	// too recently to send out another channel update.
+	SkipRateLimited SkipReason = "policy updated recently"
+
+	// SkipUpdateFailed indicates that the new fee rate couldn't be applied
+	// to the channel. The cause is recorded in the adjustment.
+	SkipUpdateFailed SkipReason = "policy update failed"
+)
+
+// Config holds the parameters and the dependencies of the fee manager.
+type Config struct {
+	// MinFeeRate is the lowest fee rate in parts per million that the fee
+	// manager sets on a channel.
+	MinFeeRate uint32
+
+	// MaxFeeRate is the highest fee rate in parts per million that the fee
+	// manager sets on a channel.
+	MaxFeeRate uint32
+
+	// ForwardingWindow is the period over which the forwarding volume of
+	// a channel is measured.
+	ForwardingWindow time.Duration
+
+	// MinUpdateInterval is the minimum time between two policy updates of
+	// the same channel. It limits the number of channel updates that we
+	// gossip to the network.
+	MinUpdateInterval time.Duration
+
+	// MinChangeRatio is the minimum relative change of the fee rate of a
+	// channel that warrants a new channel update.
+	MinChangeRatio float64
+
+	// FetchAllOpenChannels fetches all our open channels from the
+	// database.
+	FetchAllOpenChannels func() ([]*channeldb.OpenChannel, error)
+
+	// ForAllOutgoingChannels is required to iterate over the current
+	// policies of all our local channels.
+	ForAllOutgoingChannels func(cb func(kvdb.RTx,
+		*channeldb.ChannelEdgeInfo,
+		*channeldb.ChannelEdgePolicy) error) error
+
+	// QueryForwardingLog queries the forwarding log for the forwards that
+	// were settled within a time slice.
+	QueryForwardingLog func(q channeldb.ForwardingEventQuery) (
+		channeldb.ForwardingLogTimeSlice, error)
+
+	// UpdatePolicy updates the policy of the given channels, and
+	// broadcasts it to the network.
+	UpdatePolicy func(newSchema routing.ChannelPolicy,
+		chanPoints ...wire.OutPoint) ([]*lnrpc.FailedUpdate, error)
+
+	// Clock is the clock used to determine the forwarding window and
+	// whether a channel was updated recently.
+	Clock clock.Clock
+
+	// Ticker determines how often the fee manager adjusts the fee rates of
+	// our channels once it is started.
+	Ticker ticker.Ticker
+}
+
+// Adjustment describes the fee rate adjustment of a single channel.
+type Adjustment struct {
+	// ChanPoint is the funding outpoint of the channel.
+	ChanPoint wire.OutPoint
+
+	// ChanID is the short channel id of the channel.
+	ChanID lnwire.ShortChannelID
+
+	// LocalBalanceRatio is the share of the channel capacity that is on
+	// our side of the channel.
+	LocalBalanceRatio float64
+
+	// ForwardedAmt is the amount that was forwarded over the channel
+	// within the forwarding window.
+	ForwardedAmt lnwire.MilliBronees
+
+	// OldFeeRate is the current fee rate of the channel in parts per
+	// million.
+	OldFeeRate uint32
+
+	// NewFeeRate is the target fee rate of the channel in parts per
+	// million.
+	NewFeeRate uint32
+
+	// Applied is true if the new fee rate was applied to the channel.
+	Applied bool
+
+	// SkipReason describes why the new fee rate isn't applied, if it
+	// isn't.
+	SkipReason SkipReason
+
+	// UpdateErr is the error that prevented the new fee rate from being
+	// applied, if the SkipReason is SkipUpdateFailed.
+	UpdateErr error
+}
+
+// Status describes the current state of the fee manager.
+type Status struct {
+	// Active is true if the fee manager adjusts fee rates on a schedule.
+	Active bool
+
+	// LastRun is the time at which the fee rates were last adjusted. It
+	// is the zero time if they weren't adjusted yet.
+	LastRun time.Time
+}
+
+// Manager periodically adjusts the fee rates of our channels based on their
+// local balance and their forwarding volume. Payments are steered towards
+// channels that have liquidity to spare, while channels that are in high
+// demand earn more.
+type Manager struct {
+	cfg *Config
+
+	// mu serializes fee adjustments and guards the fields below.
+	mu      sync.Mutex
+	active  bool
+	lastRun time.Time
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New creates a new fee manager. The fee rates are only adjusted on a
+// schedule once the manager is started.
+func New(cfg *Config) (*Manager, error) {
+	switch {
+	case cfg.MinFeeRate > cfg.MaxFeeRate:
+		return nil, fmt.Errorf("min fee rate %v exceeds max fee rate "+
+			"%v", cfg.MinFeeRate, cfg.MaxFeeRate)
+
+	case cfg.MinChangeRatio < 0:
+		return nil, errors.New("min change ratio must not be " +
+			"negative")
+	}
+
+	return &Manager{
+		cfg:  cfg,
+		quit: make(chan struct{}),
+	}, nil
+}
+
+// Start launches the goroutine that adjusts the fee rates of our channels on
+// a schedule.
+func (m *Manager) Start() error {
+	m.startOnce.Do(func() {
+		log.Info("Fee manager starting")
+
+		m.mu.Lock()
+		m.active = true
+		m.mu.Unlock()
+
+		m.wg.Add(1)
+		go m.feeAdjuster()
+	})
+
+	return nil
+}
+
+// Stop signals the fee manager for a graceful shutdown.
+func (m *Manager) Stop() error {
+	m.stopOnce.Do(func() {
+		log.Info("Fee manager shutting down")
+
+		close(m.quit)
+		m.wg.Wait()
+
+		m.mu.Lock()
+		m.active = false
+		m.mu.Unlock()
+	})
+
+	return nil
+}
+
+// Status returns the current state of the fee manager.
+func (m *Manager) Status() Status {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return Status{
+		Active:  m.active,
+		LastRun: m.lastRun,
+	}
+}
+
+// feeAdjuster adjusts the fee rates of our channels whenever the ticker
+// fires.
+//
+// NOTE: This MUST be run as a goroutine.
+func (m *Manager) feeAdjuster() {
+	defer m.wg.Done()
+
+	m.cfg.Ticker.Resume()
+	defer m.cfg.Ticker.Stop()
+
+	for {
+		select {
+		case <-m.cfg.Ticker.Ticks():
+			adjustments, err := m.AdjustFees(false)
+			if err != nil {
+				log.Errorf("Unable to adjust fee rates: %v",
+					err)
+				continue
+			}
+
+			var numApplied, numFailed int
+			for _, adjustment := range adjustments {
+				switch {
+				case adjustment.Applied:
+					numApplied++
+
+				case adjustment.UpdateErr != nil:
+					numFailed++
+				}
+			}
+
+			log.Infof("Adjusted fee rates of %v out of %v "+
+				"channels, %v updates failed", numApplied,
+				len(adjustments), numFailed)
+
+		case <-m.quit:
+			return
+		}
+	}
+}
+
+// AdjustFees determines the target fee rate of each of our channels, and
+// updates the policies of the channels for which a new channel update is
+// warranted. If dryRun is true, the adjustments are only reported. A channel
+// whose policy can't be updated doesn't prevent the other channels from being
+// updated, the failure is recorded in its adjustment instead.
+func (m *Manager) AdjustFees(dryRun bool) ([]Adjustment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.cfg.Clock.Now()
+
+	volumes, err := m.forwardingVolumes(now)
+	if err != nil {
+		return nil, err
+	}
+
+	policies := make(map[wire.OutPoint]*channeldb.ChannelEdgePolicy)
+	err = m.cfg.ForAllOutgoingChannels(func(_ kvdb.RTx,
+		info *channeldb.ChannelEdgeInfo,
+		policy *channeldb.ChannelEdgePolicy) error {
+
+		if policy != nil {
+			policies[info.ChannelPoint] = policy
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	channels, err := m.cfg.FetchAllOpenChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	adjustments := make([]Adjustment, 0, len(channels))
+	for _, channel := range channels {
+		if channel.IsPending {
+			continue
+		}
+
+		policy := policies[channel.FundingOutpoint]
+		adjustment := m.newAdjustment(
+			channel, policy, volumes[channel.ShortChanID()], now,
+		)
+
+		if adjustment.SkipReason == SkipNone && !dryRun {
+			err := m.applyFeeRate(policy, &adjustment)
+			if err != nil {
+				log.Errorf("Unable to update fee rate of "+
+					"channel %v: %v", adjustment.ChanPoint,
+					err)
+
+				adjustment.SkipReason = SkipUpdateFailed
+				adjustment.UpdateErr = err
+			}
+		}
+
+		adjustments = append(adjustments, adjustment)
+	}
+
+	if !dryRun {
+		m.lastRun = now
+	}
+
+	return adjustments, nil
+}
+
+// newAdjustment determines the target fee rate of a channel, and whether it
+// warrants a new channel update.
+func (m *Manager) newAdjustment(channel *channeldb.OpenChannel,
+	policy *channeldb.ChannelEdgePolicy, forwarded lnwire.MilliBronees,
+	now time.Time) Adjustment {
+
+	capacity := lnwire.NewMSatFromBroneess(channel.Capacity)
+	localBalance := channel.LocalCommitment.LocalBalance
+
+	adjustment := Adjustment{
+		ChanPoint:    channel.FundingOutpoint,
+		ChanID:       channel.ShortChanID(),
+		ForwardedAmt: forwarded,
+	}
+
+	var turnover float64
+	if capacity > 0 {
+		adjustment.LocalBalanceRatio = float64(localBalance) /
+			float64(capacity)
+		turnover = math.Min(float64(forwarded)/float64(capacity), 1)
+	}
+
+	adjustment.NewFeeRate = m.targetFeeRate(
+		adjustment.LocalBalanceRatio, turnover,
+	)
+
+	if policy == nil {
+		adjustment.SkipReason = SkipNoPolicy
+		return adjustment
+	}
+
+	adjustment.OldFeeRate = uint32(policy.FeeProportionalMillionths)
+
+	oldRate := float64(adjustment.OldFeeRate)
+	change := math.Abs(float64(adjustment.NewFeeRate) - oldRate)
+
+	switch {
+	case change == 0:
+		adjustment.SkipReason = SkipUnchanged
+
+	case oldRate > 0 && change/oldRate < m.cfg.MinChangeRatio:
+		adjustment.SkipReason = SkipBelowThreshold
+
+	case now.Sub(policy.LastUpdate) < m.cfg.MinUpdateInterval:
+		adjustment.SkipReason = SkipRateLimited
+	}
+
+	return adjustment
+}
+
+// targetFeeRate returns the fee rate for a channel with the given local
+// balance ratio and turnover. The fee rate increases linearly from the min to
+// the max fee rate as the local balance of the channel depletes. On top of
+// that, a channel claims the share of the remaining room up to the max fee
+// rate that matches its turnover, which is its forwarded amount relative to
+// its capacity.
+func (m *Manager) targetFeeRate(localRatio, turnover float64) uint32 {
+	minRate := float64(m.cfg.MinFeeRate)
+	maxRate := float64(m.cfg.MaxFeeRate)
+
+	rate := minRate + (maxRate-minRate)*(1-localRatio)
+	rate += (maxRate - rate) * turnover
+
+	return uint32(math.Round(math.Min(math.Max(rate, minRate), maxRate)))
+}
+
+// applyFeeRate updates the policy of the channel with the new fee rate of the
+// adjustment, leaving the other policy parameters unchanged.
+func (m *Manager) applyFeeRate(policy *channeldb.ChannelEdgePolicy,
+	adjustment *Adjustment) error {
+
+	minHTLC := policy.MinHTLC
+	newPolicy := routing.ChannelPolicy{
+		FeeSchema: routing.FeeSchema{
+			BaseFee: policy.FeeBaseMSat,
+			FeeRate: adjustment.NewFeeRate,
+		},
+		TimeLockDelta: uint32(policy.TimeLockDelta),
+		MaxHTLC:       policy.MaxHTLC,
+		MinHTLC:       &minHTLC,
+	}
+
+	failedUpdates, err := m.cfg.UpdatePolicy(
+		newPolicy, adjustment.ChanPoint,
+	)
+	if err != nil {
+		return err
+	}
+
+	if len(failedUpdates) > 0 {
+		return fmt.Errorf("unable to update policy of channel %v: %v",
+			adjustment.ChanPoint, failedUpdates[0].UpdateError)
+	}
+
+	log.Debugf("Updated fee rate of channel %v from %v to %v ppm",
+		adjustment.ChanPoint, adjustment.OldFeeRate,
+		adjustment.NewFeeRate)
+
+	adjustment.Applied = true
+
+	return nil
+}
+
+// forwardingVolumes returns the amount that was forwarded over each of our
+// channels within the forwarding window.
+func (m *Manager) forwardingVolumes(now time.Time) (
+	map[lnwire.ShortChannelID]lnwire.MilliBronees, error) {
+
+	volumes := make(map[lnwire.ShortChannelID]lnwire.MilliBronees)
+
+	query := channeldb.ForwardingEventQuery{
+		StartTime:    now.Add(-m.cfg.ForwardingWindow),
+		EndTime:      now,
+		NumMaxEvents: maxEventsPerQuery,
+	}
+	for {
+		timeSlice, err := m.cfg.QueryForwardingLog(query)
+		switch {
+		case errors.Is(err, channeldb.ErrNoForwardingEvents):
+			return volumes, nil
+
+		case err != nil:
+			return nil, err
+		}
+
+		for _, event := range timeSlice.ForwardingEvents {
+			volumes[event.OutgoingChanID] += event.AmtOut
+		}
+
+		numEvents := uint32(len(timeSlice.ForwardingEvents))
+		if numEvents < query.NumMaxEvents {
+			return volumes, nil
+		}
+
+		query.IndexOffset = timeSlice.LastIndexOffset
+	}
+}
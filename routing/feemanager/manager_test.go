@@ -0,0 +1,341 @@
+package feemanager
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/brronsuite/broln/channeldb"
+	"github.com/brronsuite/broln/clock"
+	"github.com/brronsuite/broln/kvdb"
+	"github.com/brronsuite/broln/lnrpc"
+	"github.com/brronsuite/broln/lnwire"
+	"github.com/brronsuite/broln/routing"
+	"github.com/brronsuite/broln/ticker"
+	"github.com/brronsuite/brond/wire"
+	"github.com/brronsuite/bronutil"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAdjustFees tests that the fee manager determines the target fee rates
+// of our channels from their local balance and forwarding volume, and that
+// channel updates are rate limited.
+func TestAdjustFees(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(100000, 0)
+	testClock := clock.NewTestClock(now)
+
+	const capacity = bronutil.Amount(1000000)
+	capacityMsat := lnwire.NewMSatFromBroneess(capacity)
+
+	newChannel := func(index uint32, localShare float64,
+		pending bool) *channeldb.OpenChannel {
+
+		chanID := lnwire.NewShortChanIDFromInt(uint64(index))
+
+		return &channeldb.OpenChannel{
+			FundingOutpoint: wire.OutPoint{Index: index},
+			ShortChannelID:  chanID,
+			Capacity:        capacity,
+			IsPending:       pending,
+			LocalCommitment: channeldb.ChannelCommitment{
+				LocalBalance: lnwire.MilliBronees(
+					float64(capacityMsat) * localShare,
+				),
+			},
+		}
+	}
+
+	channels := []*channeldb.OpenChannel{
+		// A depleted channel that gets a higher fee rate.
+		newChannel(1, 0.25, false),
+
+		// A busy channel whose fee rate changes too little to update
+		// it.
+		newChannel(2, 0.5, false),
+
+		// A full channel whose policy was updated recently.
+		newChannel(3, 1, false),
+
+		// A channel without a policy.
+		newChannel(4, 0, false),
+
+		// A pending channel that is ignored.
+		newChannel(5, 0.5, true),
+	}
+
+	policies := map[uint32]*channeldb.ChannelEdgePolicy{
+		1: {
+			FeeBaseMSat:               1000,
+			FeeProportionalMillionths: 100,
+			TimeLockDelta:             40,
+			MinHTLC:                   1000,
+			MaxHTLC:                   capacityMsat,
+			LastUpdate:                now.Add(-2 * time.Hour),
+		},
+		2: {
+			FeeProportionalMillionths: 800,
+			LastUpdate:                now.Add(-2 * time.Hour),
+		},
+		3: {
+			FeeProportionalMillionths: 500,
+			LastUpdate:                now.Add(-10 * time.Minute),
+		},
+	}
+
+	forAllOutgoingChannels := func(cb func(kvdb.RTx,
+		*channeldb.ChannelEdgeInfo,
+		*channeldb.ChannelEdgePolicy) error) error {
+
+		for _, channel := range channels {
+			info := &channeldb.ChannelEdgeInfo{
+				ChannelPoint: channel.FundingOutpoint,
+			}
+			policy := policies[channel.FundingOutpoint.Index]
+			if err := cb(nil, info, policy); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	// Half of the capacity of the second channel was forwarded within the
+	// forwarding window.
+	queryForwardingLog := func(q channeldb.ForwardingEventQuery) (
+		channeldb.ForwardingLogTimeSlice, error) {
+
+		require.Equal(t, now.Add(-24*time.Hour), q.StartTime)
+		require.Equal(t, now, q.EndTime)
+
+		chanID := lnwire.NewShortChanIDFromInt(2)
+		return channeldb.ForwardingLogTimeSlice{
+			ForwardingEventQuery: q,
+			ForwardingEvents: []channeldb.ForwardingEvent{{
+				OutgoingChanID: chanID,
+				AmtOut:         capacityMsat / 4,
+			}, {
+				OutgoingChanID: chanID,
+				AmtOut:         capacityMsat / 4,
+			}},
+			LastIndexOffset: 2,
+		}, nil
+	}
+
+	var updates []routing.ChannelPolicy
+	updatePolicy := func(newSchema routing.ChannelPolicy,
+		chanPoints ...wire.OutPoint) ([]*lnrpc.FailedUpdate, error) {
+
+		require.Equal(t, []wire.OutPoint{{Index: 1}}, chanPoints)
+		updates = append(updates, newSchema)
+
+		return nil, nil
+	}
+
+	mgr, err := New(&Config{
+		MinFeeRate:        100,
+		MaxFeeRate:        1100,
+		ForwardingWindow:  24 * time.Hour,
+		MinUpdateInterval: time.Hour,
+		MinChangeRatio:    0.1,
+		FetchAllOpenChannels: func() ([]*channeldb.OpenChannel,
+			error) {
+
+			return channels, nil
+		},
+		ForAllOutgoingChannels: forAllOutgoingChannels,
+		QueryForwardingLog:     queryForwardingLog,
+		UpdatePolicy:           updatePolicy,
+		Clock:                  testClock,
+		Ticker:                 ticker.NewForce(time.Hour),
+	})
+	require.NoError(t, err)
+
+	expected := []Adjustment{{
+		ChanPoint:         wire.OutPoint{Index: 1},
+		ChanID:            lnwire.NewShortChanIDFromInt(1),
+		LocalBalanceRatio: 0.25,
+		OldFeeRate:        100,
+		NewFeeRate:        850,
+	}, {
+		ChanPoint:         wire.OutPoint{Index: 2},
+		ChanID:            lnwire.NewShortChanIDFromInt(2),
+		LocalBalanceRatio: 0.5,
+		ForwardedAmt:      capacityMsat / 2,
+		OldFeeRate:        800,
+		NewFeeRate:        850,
+		SkipReason:        SkipBelowThreshold,
+	}, {
+		ChanPoint:         wire.OutPoint{Index: 3},
+		ChanID:            lnwire.NewShortChanIDFromInt(3),
+		LocalBalanceRatio: 1,
+		OldFeeRate:        500,
+		NewFeeRate:        100,
+		SkipReason:        SkipRateLimited,
+	}, {
+		ChanPoint:  wire.OutPoint{Index: 4},
+		ChanID:     lnwire.NewShortChanIDFromInt(4),
+		NewFeeRate: 1100,
+		SkipReason: SkipNoPolicy,
+	}}
+
+	// A dry run must only report the adjustments.
+	adjustments, err := mgr.AdjustFees(true)
+	require.NoError(t, err)
+	require.Equal(t, expected, adjustments)
+	require.Empty(t, updates)
+	require.True(t, mgr.Status().LastRun.IsZero())
+
+	// A regular run must update the policy of the first channel, leaving
+	// all other policy parameters unchanged.
+	adjustments, err = mgr.AdjustFees(false)
+	require.NoError(t, err)
+
+	expected[0].Applied = true
+	require.Equal(t, expected, adjustments)
+	require.Equal(t, now, mgr.Status().LastRun)
+
+	minHTLC := lnwire.MilliBronees(1000)
+	require.Equal(t, []routing.ChannelPolicy{{
+		FeeSchema: routing.FeeSchema{
+			BaseFee: 1000,
+			FeeRate: 850,
+		},
+		TimeLockDelta: 40,
+		MaxHTLC:       capacityMsat,
+		MinHTLC:       &minHTLC,
+	}}, updates)
+}
+
+// TestAdjustFeesUpdateFailure tests that a channel whose policy can't be
+// updated doesn't prevent the fee rates of the other channels from being
+// adjusted, and that the failure is recorded in its adjustment.
+func TestAdjustFeesUpdateFailure(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(100000, 0)
+
+	const capacity = bronutil.Amount(1000000)
+
+	var channels []*channeldb.OpenChannel
+	for index := uint32(1); index <= 2; index++ {
+		chanID := lnwire.NewShortChanIDFromInt(uint64(index))
+
+		channels = append(channels, &channeldb.OpenChannel{
+			FundingOutpoint: wire.OutPoint{Index: index},
+			ShortChannelID:  chanID,
+			Capacity:        capacity,
+		})
+	}
+
+	lastUpdate := now.Add(-2 * time.Hour)
+	forAllOutgoingChannels := func(cb func(kvdb.RTx,
+		*channeldb.ChannelEdgeInfo,
+		*channeldb.ChannelEdgePolicy) error) error {
+
+		for _, channel := range channels {
+			info := &channeldb.ChannelEdgeInfo{
+				ChannelPoint: channel.FundingOutpoint,
+			}
+			policy := &channeldb.ChannelEdgePolicy{
+				FeeProportionalMillionths: 100,
+				LastUpdate:                lastUpdate,
+			}
+			if err := cb(nil, info, policy); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	// The policy update of the first channel fails, while the second one
+	// succeeds.
+	errUpdate := errors.New("update failed")
+	var updated []wire.OutPoint
+	updatePolicy := func(newSchema routing.ChannelPolicy,
+		chanPoints ...wire.OutPoint) ([]*lnrpc.FailedUpdate, error) {
+
+		if chanPoints[0].Index == 1 {
+			return nil, errUpdate
+		}
+		updated = append(updated, chanPoints...)
+
+		return nil, nil
+	}
+
+	mgr, err := New(&Config{
+		MinFeeRate:        100,
+		MaxFeeRate:        1100,
+		ForwardingWindow:  24 * time.Hour,
+		MinUpdateInterval: time.Hour,
+		FetchAllOpenChannels: func() ([]*channeldb.OpenChannel,
+			error) {
+
+			return channels, nil
+		},
+		ForAllOutgoingChannels: forAllOutgoingChannels,
+		QueryForwardingLog: func(channeldb.ForwardingEventQuery) (
+			channeldb.ForwardingLogTimeSlice, error) {
+
+			return channeldb.ForwardingLogTimeSlice{},
+				channeldb.ErrNoForwardingEvents
+		},
+		UpdatePolicy: updatePolicy,
+		Clock:        clock.NewTestClock(now),
+		Ticker:       ticker.NewForce(time.Hour),
+	})
+	require.NoError(t, err)
+
+	adjustments, err := mgr.AdjustFees(false)
+	require.NoError(t, err)
+	require.Len(t, adjustments, 2)
+
+	require.False(t, adjustments[0].Applied)
+	require.Equal(t, SkipUpdateFailed, adjustments[0].SkipReason)
+	require.ErrorIs(t, adjustments[0].UpdateErr, errUpdate)
+
+	require.True(t, adjustments[1].Applied)
+	require.Equal(t, SkipNone, adjustments[1].SkipReason)
+	require.NoError(t, adjustments[1].UpdateErr)
+	require.Equal(t, []wire.OutPoint{{Index: 2}}, updated)
+}
+
+// TestTargetFeeRate tests the fee rate that is targeted for various local
+// balance ratios and turnovers.
+func TestTargetFeeRate(t *testing.T) {
+	t.Parallel()
+
+	mgr, err := New(&Config{
+		MinFeeRate: 10,
+		MaxFeeRate: 1010,
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		localRatio float64
+		turnover   float64
+		expected   uint32
+	}{
+		{localRatio: 1, turnover: 0, expected: 10},
+		{localRatio: 0, turnover: 0, expected: 1010},
+		{localRatio: 0.5, turnover: 0, expected: 510},
+		{localRatio: 1, turnover: 0.5, expected: 510},
+		{localRatio: 0.5, turnover: 1, expected: 1010},
+	}
+
+	for _, test := range tests {
+		require.Equal(
+			t, test.expected,
+			mgr.targetFeeRate(test.localRatio, test.turnover),
+		)
+	}
+
+	// The min fee rate must not exceed the max fee rate.
+	_, err = New(&Config{
+		MinFeeRate: 11,
+		MaxFeeRate: 10,
+	})
+	require.Error(t, err)
+}
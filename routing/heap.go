@@ -24,6 +24,11 @@ type nodeWithDist struct {
 	// amount that includes also the fees for subsequent hops.
 	amountToReceive lnwire.MilliBronees
 
+	// outboundFee is the fee this node charges for forwarding over the
+	// channel towards the next hop. It bounds the inbound discount the
+	// node can give on the channel over which it receives the htlc.
+	outboundFee lnwire.MilliBronees
+
 	// incomingCltv is the expected absolute expiry height for the incoming
 	// htlc of this node. This value should already include the final cltv
 	// delta.
@@ -112,6 +112,7 @@ func (r *Manager) UpdatePolicy(newSchema routing.ChannelPolicy,
 			TimeLockDelta: uint32(edge.TimeLockDelta),
 			MinHTLCOut:    edge.MinHTLC,
 			MaxHTLC:       edge.MaxHTLC,
+			InboundFee:    edge.InboundFee(),
 		}
 
 		return nil
@@ -182,6 +183,19 @@ func (r *Manager) updateEdge(tx kvdb.RTx, chanPoint wire.OutPoint,
 	)
 	edge.TimeLockDelta = uint16(newSchema.TimeLockDelta)
 
+	// If a new inbound fee is specified, update the edge. The inbound fee
+	// is carried in the extra opaque data of the channel update, next to
+	// other records that we keep.
+	if newSchema.InboundFee != nil {
+		extraData, err := lnwire.SetInboundFee(
+			edge.ExtraOpaqueData, *newSchema.InboundFee,
+		)
+		if err != nil {
+			return err
+		}
+		edge.ExtraOpaqueData = extraData
+	}
+
 	// Retrieve negotiated channel htlc amt limits.
 	amtMin, amtMax, err := r.getHtlcAmtLimits(tx, chanPoint)
 	if err != nil {
@@ -282,6 +282,10 @@ type ChannelEdgeUpdate struct {
 	// across this channel direction.
 	FeeRate lnwire.MilliBronees
 
+	// InboundFee is the fee that the advertising node charges for HTLC's
+	// that arrive over the channel.
+	InboundFee lnwire.InboundFee
+
 	// TimeLockDelta is the time-lock expressed in blocks that will be
 	// added to outgoing HTLC's from incoming HTLC's. This value is the
 	// difference of the incoming and outgoing HTLC's time-locks routed
@@ -373,6 +377,7 @@ func addToTopologyChange(graph *channeldb.ChannelGraph, update *TopologyChange,
 			MaxHTLC:         m.MaxHTLC,
 			BaseFee:         m.FeeBaseMSat,
 			FeeRate:         m.FeeProportionalMillionths,
+			InboundFee:      m.InboundFee(),
 			AdvertisingNode: aNode,
 			ConnectingNode:  cNode,
 			Disabled:        m.ChannelFlags&lnwire.ChanUpdateDisabled != 0,
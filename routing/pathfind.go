@@ -212,7 +212,15 @@ func newRoute(sourceVertex route.Vertex,
 			// and its policy for the outgoing channel. This policy
 			// is stored as part of the incoming channel of
 			// the next hop.
-			fee = pathEdges[i+1].ComputeFee(amtToForward)
+			outboundFee := pathEdges[i+1].ComputeFee(amtToForward)
+
+			// On top of that, the current hop charges an inbound
+			// fee for the channel over which it receives the htlc,
+			// based on the amount to forward plus the outbound fee.
+			fee = addInboundFee(
+				outboundFee, edge.ToNodeInboundFee,
+				amtToForward+outboundFee,
+			)
 
 			// We'll take the total timelock of the preceding hop as
 			// the outgoing timelock or this hop. Then we'll
@@ -254,6 +262,21 @@ func newRoute(sourceVertex route.Vertex,
 	return newRoute, nil
 }
 
+// addInboundFee adds the inbound fee for the given amount to the outbound fee
+// of a node. As routing nodes treat a negative total fee as zero, an inbound
+// discount never makes the total fee negative.
+func addInboundFee(outboundFee lnwire.MilliBronees,
+	inboundFee lnwire.InboundFee,
+	amt lnwire.MilliBronees) lnwire.MilliBronees {
+
+	fee := int64(outboundFee) + inboundFee.CalcFee(amt)
+	if fee < 0 {
+		return 0
+	}
+
+	return lnwire.MilliBronees(fee)
+}
+
 // edgeWeight computes the weight of an edge. This value is used when searching
 // for the shortest path within the channel graph between two nodes. Weight is
 // is the fee itself plus a time lock penalty added to it. This benefits
@@ -308,9 +331,22 @@ type RestrictParams struct {
 	// first hop. If nil, any channel may be used.
 	OutgoingChannelIDs []uint64
 
-	// LastHop is the pubkey of the last node before the final destination
-	// is reached. If nil, any node may be used.
-	LastHop *route.Vertex
+	// LastHops is the list of nodes that are allowed as the last node
+	// before the final destination is reached. If nil, any node may be
+	// used.
+	LastHops []route.Vertex
+
+	// IncomingChannelIDs is the list of channels that are allowed for the
+	// last hop into the target. If nil, any channel may be used.
+	IncomingChannelIDs []uint64
+
+	// ExcludedNodes is the list of nodes that must not be used as a hop
+	// of the path.
+	ExcludedNodes []route.Vertex
+
+	// ExcludedChannelIDs is the list of channels that must not be used
+	// anywhere on the path.
+	ExcludedChannelIDs []uint64
 
 	// CltvLimit is the maximum time lock of the route excluding the final
 	// ctlv. After path finding is complete, the caller needs to increase
@@ -355,7 +391,8 @@ type PathFindingConfig struct {
 // channels of the given node. The second return parameters is the total
 // available balance.
 func getOutgoingBalance(node route.Vertex, outgoingChans map[uint64]struct{},
-	bandwidthHints bandwidthHints,
+	excludedChans map[uint64]struct{},
+	excludedNodes map[route.Vertex]struct{}, bandwidthHints bandwidthHints,
 	g routingGraph) (lnwire.MilliBronees, lnwire.MilliBronees, error) {
 
 	var max, total lnwire.MilliBronees
@@ -373,6 +410,15 @@ func getOutgoingBalance(node route.Vertex, outgoingChans map[uint64]struct{},
 			}
 		}
 
+		// Channels that are excluded, or that lead to an excluded
+		// peer, can't carry any part of the payment.
+		if _, ok := excludedChans[chanID]; ok {
+			return nil
+		}
+		if _, ok := excludedNodes[channel.OtherNode]; ok {
+			return nil
+		}
+
 		bandwidth, ok := bandwidthHints.availableChanBandwidth(
 			chanID, 0,
 		)
@@ -483,13 +529,43 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 		}
 	}
 
+	// Set up incoming channel map for quicker access.
+	var incomingChanMap map[uint64]struct{}
+	if len(r.IncomingChannelIDs) > 0 {
+		incomingChanMap = make(map[uint64]struct{})
+		for _, inChan := range r.IncomingChannelIDs {
+			incomingChanMap[inChan] = struct{}{}
+		}
+	}
+
+	// Set up last hop map for quicker access.
+	var lastHopMap map[route.Vertex]struct{}
+	if len(r.LastHops) > 0 {
+		lastHopMap = make(map[route.Vertex]struct{})
+		for _, lastHop := range r.LastHops {
+			lastHopMap[lastHop] = struct{}{}
+		}
+	}
+
+	// Set up the maps of excluded channels and nodes. Unlike the
+	// restrictions above, an empty map excludes nothing.
+	excludedChanMap := make(map[uint64]struct{})
+	for _, chanID := range r.ExcludedChannelIDs {
+		excludedChanMap[chanID] = struct{}{}
+	}
+	excludedNodeMap := make(map[route.Vertex]struct{})
+	for _, node := range r.ExcludedNodes {
+		excludedNodeMap[node] = struct{}{}
+	}
+
 	// If we are routing from ourselves, check that we have enough local
 	// balance available.
 	self := g.graph.sourceNode()
 
 	if source == self {
 		max, total, err := getOutgoingBalance(
-			self, outgoingChanMap, g.bandwidthHints, g.graph,
+			self, outgoingChanMap, excludedChanMap,
+			excludedNodeMap, g.bandwidthHints, g.graph,
 		)
 		if err != nil {
 			return nil, err
@@ -593,6 +669,24 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 		// out.
 		amountToSend := toNodeDist.amountToReceive
 
+		// The to node charges an inbound fee for htlcs that arrive
+		// over this channel, which depends on the amount it forwards
+		// plus its outbound fee. The target node doesn't charge an
+		// inbound fee. The inbound fee of the to node is accounted for
+		// in the amount that the candidate node needs to send out.
+		var inboundFee int64
+		if toNodeDist.node != target {
+			toNodeFee := addInboundFee(
+				toNodeDist.outboundFee, edge.ToNodeInboundFee,
+				amountToSend,
+			)
+			inboundFee = int64(toNodeFee) -
+				int64(toNodeDist.outboundFee)
+			amountToSend = lnwire.MilliBronees(
+				int64(amountToSend) + inboundFee,
+			)
+		}
+
 		// Request the success probability for this edge.
 		edgeProbability := r.ProbabilitySource(
 			fromVertex, toNodeDist.node, amountToSend,
@@ -661,10 +755,19 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 		}
 
 		// By adding fromVertex in the route, there will be an extra
-		// weight composed of the fee that this node will charge and
-		// the amount that will be locked for timeLockDelta blocks in
-		// the HTLC that is handed out to fromVertex.
-		weight := edgeWeight(amountToReceive, fee, timeLockDelta)
+		// weight composed of the fee that this node will charge, the
+		// inbound fee of the to node and the amount that will be
+		// locked for timeLockDelta blocks in the HTLC that is handed
+		// out to fromVertex. Dijkstra doesn't support negative edge
+		// weights, so an inbound discount can at most offset the fee.
+		weightFee := int64(fee) + inboundFee
+		if weightFee < 0 {
+			weightFee = 0
+		}
+		weight := edgeWeight(
+			amountToReceive, lnwire.MilliBronees(weightFee),
+			timeLockDelta,
+		)
 
 		// Compute the tentative weight to this new channel/edge
 		// which is the weight from our toNode to the target node
@@ -743,6 +846,7 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 			weight:          tempWeight,
 			node:            fromVertex,
 			amountToReceive: amountToReceive,
+			outboundFee:     fee,
 			incomingCltv:    incomingCltv,
 			probability:     probability,
 			nextHop:         edge,
@@ -806,8 +910,17 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 
 		pivot := partialPath.node
 
-		// Create unified policies for all incoming connections.
-		u := newUnifiedPolicies(self, pivot, outgoingChanMap)
+		// Create unified policies for all incoming connections. The
+		// incoming channel restriction only applies to the channels
+		// towards the target.
+		var inChanRestr map[uint64]struct{}
+		if pivot == target {
+			inChanRestr = incomingChanMap
+		}
+		u := newUnifiedPolicies(
+			self, pivot, outgoingChanMap, inChanRestr,
+			excludedChanMap,
+		)
 
 		err := u.addGraphPolicies(g.graph)
 		if err != nil {
@@ -833,9 +946,14 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 			}
 
 			// Apply last hop restriction if set.
-			if r.LastHop != nil &&
-				pivot == target && fromNode != *r.LastHop {
+			if lastHopMap != nil && pivot == target {
+				if _, ok := lastHopMap[fromNode]; !ok {
+					continue
+				}
+			}
 
+			// Skip excluded nodes.
+			if _, ok := excludedNodeMap[fromNode]; ok {
 				continue
 			}
 
@@ -28,6 +28,7 @@ import (
 	"github.com/brronsuite/brond/chaincfg/chainhash"
 	"github.com/brronsuite/brond/wire"
 	"github.com/brronsuite/bronutil"
+	"github.com/stretchr/testify/require"
 )
 
 const (
@@ -825,6 +826,9 @@ func TestPathFinding(t *testing.T) {
 	}, {
 		name: "restrict last hop",
 		fn:   runRestrictLastHop,
+	}, {
+		name: "exclude nodes and channels",
+		fn:   runExcludeNodesAndChannels,
 	}, {
 		name: "CLTV limit",
 		fn:   runCltvLimit,
@@ -840,6 +844,9 @@ func TestPathFinding(t *testing.T) {
 	}, {
 		name: "route to self",
 		fn:   runRouteToSelf,
+	}, {
+		name: "inbound fees",
+		fn:   runInboundFees,
 	}}
 
 	// Run with graph cache enabled.
@@ -2572,7 +2579,7 @@ func runRestrictLastHop(t *testing.T, useCache bool) {
 
 	// Find the best path given the restriction to use b as the last hop.
 	// This should force pathfinding to not take the lowest cost option.
-	ctx.restrictParams.LastHop = &lastHop
+	ctx.restrictParams.LastHops = []route.Vertex{lastHop}
 	path, err := ctx.findPath(target, paymentAmt)
 	if err != nil {
 		t.Fatalf("unable to find path: %v", err)
@@ -2584,6 +2591,73 @@ func runRestrictLastHop(t *testing.T, useCache bool) {
 	}
 }
 
+// runExcludeNodesAndChannels asserts that a set of last hops, excluded nodes
+// and excluded channels are obeyed by the path finding algorithm.
+func runExcludeNodesAndChannels(t *testing.T, useCache bool) {
+	// Set up a test graph with three possible paths from roasbeef to
+	// target. The path via a is the lowest cost path, followed by the
+	// paths via b and c.
+	testChannels := []*testChannel{
+		symmetricTestChannel("source", "a", 100000, &testChannelPolicy{
+			Expiry: 144,
+		}, 1),
+		symmetricTestChannel("a", "target", 100000, &testChannelPolicy{
+			Expiry:  144,
+			FeeRate: 400,
+		}, 2),
+		symmetricTestChannel("source", "b", 100000, &testChannelPolicy{
+			Expiry: 144,
+		}, 3),
+		symmetricTestChannel("b", "target", 100000, &testChannelPolicy{
+			Expiry:  144,
+			FeeRate: 800,
+		}, 4),
+		symmetricTestChannel("source", "c", 100000, &testChannelPolicy{
+			Expiry: 144,
+		}, 5),
+		symmetricTestChannel("c", "target", 100000, &testChannelPolicy{
+			Expiry:  144,
+			FeeRate: 1200,
+		}, 6),
+	}
+
+	ctx := newPathFindingTestContext(t, useCache, testChannels, "source")
+	defer ctx.cleanup()
+
+	paymentAmt := lnwire.NewMSatFromBroneess(100)
+	target := ctx.keyFromAlias("target")
+
+	// Restricting the last hop to either b or c must select the cheaper
+	// path via b.
+	ctx.restrictParams.LastHops = []route.Vertex{
+		ctx.keyFromAlias("b"), ctx.keyFromAlias("c"),
+	}
+	path, err := ctx.findPath(target, paymentAmt)
+	require.NoError(t, err)
+	ctx.assertPath(path, []uint64{3, 4})
+
+	// Excluding node a has the same effect.
+	ctx.restrictParams.LastHops = nil
+	ctx.restrictParams.ExcludedNodes = []route.Vertex{
+		ctx.keyFromAlias("a"),
+	}
+	path, err = ctx.findPath(target, paymentAmt)
+	require.NoError(t, err)
+	ctx.assertPath(path, []uint64{3, 4})
+
+	// Excluding the channel from b to the target leaves the most expensive
+	// path via c.
+	ctx.restrictParams.ExcludedChannelIDs = []uint64{4}
+	path, err = ctx.findPath(target, paymentAmt)
+	require.NoError(t, err)
+	ctx.assertPath(path, []uint64{5, 6})
+
+	// Excluding our channel to c as well leaves no path at all.
+	ctx.restrictParams.ExcludedChannelIDs = []uint64{4, 5}
+	_, err = ctx.findPath(target, paymentAmt)
+	require.ErrorIs(t, err, errNoPathFound)
+}
+
 // runCltvLimit asserts that a cltv limit is obeyed by the path finding
 // algorithm.
 func runCltvLimit(t *testing.T, useCache bool) {
@@ -2991,6 +3065,101 @@ func runNoCycle(t *testing.T, useCache bool) {
 	}
 }
 
+// runInboundFees tests that path finding accounts for the inbound fees that
+// nodes charge for htlcs arriving over a channel, and that the total fee of a
+// node is never negative.
+func runInboundFees(t *testing.T, useCache bool) {
+	// Set up a test graph with two paths to the target. Without inbound
+	// fees, the path through a is the cheapest.
+	testChannels := []*testChannel{
+		symmetricTestChannel("roasbeef", "a", 100000, &testChannelPolicy{
+			Expiry:  144,
+			MinHTLC: 1,
+		}, 1),
+		symmetricTestChannel("a", "target", 100000, &testChannelPolicy{
+			Expiry:      144,
+			FeeBaseMsat: 10,
+			MinHTLC:     1,
+		}, 2),
+		symmetricTestChannel("roasbeef", "b", 100000, &testChannelPolicy{
+			Expiry:  144,
+			MinHTLC: 1,
+		}, 3),
+		symmetricTestChannel("b", "target", 100000, &testChannelPolicy{
+			Expiry:      144,
+			FeeBaseMsat: 20,
+			MinHTLC:     1,
+		}, 4),
+	}
+
+	ctx := newPathFindingTestContext(t, useCache, testChannels, "roasbeef")
+	defer ctx.cleanup()
+
+	paymentAmt := lnwire.MilliBronees(100000)
+	target := ctx.keyFromAlias("target")
+
+	findRoute := func() *route.Route {
+		path, err := ctx.findPath(target, paymentAmt)
+		require.NoError(t, err)
+
+		rt, err := newRoute(
+			ctx.source, path, 100, finalHopParams{
+				amt:       paymentAmt,
+				cltvDelta: 1,
+			},
+		)
+		require.NoError(t, err)
+
+		return rt
+	}
+
+	// setInboundFee sets the inbound fee that b charges for htlcs that
+	// arrive over its channel with roasbeef. It is part of the policy of
+	// b for that channel.
+	setInboundFee := func(fee lnwire.InboundFee) {
+		graph := ctx.testGraphInstance.graph
+		info, e1, e2, err := graph.FetchChannelEdgesByID(3)
+		require.NoError(t, err)
+
+		policy := e2
+		if info.NodeKey1Bytes == ctx.keyFromAlias("b") {
+			policy = e1
+		}
+
+		policy.ExtraOpaqueData, err = lnwire.EncodeInboundFee(fee)
+		require.NoError(t, err)
+		require.NoError(t, graph.UpdateEdgePolicy(policy))
+	}
+
+	rt := findRoute()
+	require.Equal(t, ctx.keyFromAlias("a"), rt.Hops[0].PubKeyBytes)
+	require.EqualValues(t, 10, rt.TotalFees())
+
+	// With an inbound discount, the path through b becomes the cheapest
+	// one and the discount is deducted from the fee paid to b.
+	setInboundFee(lnwire.InboundFee{BaseFee: -15})
+
+	rt = findRoute()
+	require.Equal(t, ctx.keyFromAlias("b"), rt.Hops[0].PubKeyBytes)
+	require.EqualValues(t, 5, rt.TotalFees())
+	require.Equal(t, paymentAmt+5, rt.TotalAmount)
+
+	// A discount that exceeds the outbound fee of b doesn't make its total
+	// fee negative.
+	setInboundFee(lnwire.InboundFee{BaseFee: -50})
+
+	rt = findRoute()
+	require.Equal(t, ctx.keyFromAlias("b"), rt.Hops[0].PubKeyBytes)
+	require.EqualValues(t, 0, rt.TotalFees())
+
+	// An inbound surcharge makes the path through a the cheapest again.
+	setInboundFee(lnwire.InboundFee{BaseFee: 5})
+
+	rt = findRoute()
+	require.Equal(t, ctx.keyFromAlias("a"), rt.Hops[0].PubKeyBytes)
+	require.EqualValues(t, 10, rt.TotalFees())
+}
+
 // runRouteToSelf tests that it is possible to find a route to the self node.
 func runRouteToSelf(t *testing.T, useCache bool) {
 	testChannels := []*testChannel{
@@ -3025,7 +3194,7 @@ func runRouteToSelf(t *testing.T, useCache bool) {
 	outgoingChanID := uint64(1)
 	lastHop := ctx.keyFromAlias("b")
 	ctx.restrictParams.OutgoingChannelIDs = []uint64{outgoingChanID}
-	ctx.restrictParams.LastHop = &lastHop
+	ctx.restrictParams.LastHops = []route.Vertex{lastHop}
 
 	// Find the best path to self given that we want to go out via channel 1
 	// and return through node b.
@@ -3034,6 +3203,26 @@ func runRouteToSelf(t *testing.T, useCache bool) {
 		t.Fatalf("unable to find path: %v", err)
 	}
 	ctx.assertPath(path, []uint64{1, 3, 2})
+
+	// Find the best path to self given that we want to go out via channel 1
+	// and return through channel 2, which is how channels are rebalanced.
+	ctx.restrictParams.LastHops = nil
+	ctx.restrictParams.IncomingChannelIDs = []uint64{2}
+
+	path, err = ctx.findPath(target, paymentAmt)
+	if err != nil {
+		t.Fatalf("unable to find path: %v", err)
+	}
+	ctx.assertPath(path, []uint64{1, 3, 2})
+
+	// Returning through the outgoing channel itself is possible too.
+	ctx.restrictParams.IncomingChannelIDs = []uint64{1}
+
+	path, err = ctx.findPath(target, paymentAmt)
+	if err != nil {
+		t.Fatalf("unable to find path: %v", err)
+	}
+	ctx.assertPath(path, []uint64{1, 1})
 }
 
 type pathFindingTestContext struct {
@@ -252,7 +252,10 @@ func (p *paymentSession) RequestRoute(maxAmt, feeLimit lnwire.MilliBronees,
 		ProbabilitySource:  p.missionControl.GetProbability,
 		FeeLimit:           feeLimit,
 		OutgoingChannelIDs: p.payment.OutgoingChannelIDs,
-		LastHop:            p.payment.LastHop,
+		LastHops:           p.payment.LastHops,
+		IncomingChannelIDs: p.payment.IncomingChannelIDs,
+		ExcludedNodes:      p.payment.ExcludedNodes,
+		ExcludedChannelIDs: p.payment.ExcludedChannelIDs,
 		CltvLimit:          cltvLimit,
 		DestCustomRecords:  p.payment.DestCustomRecords,
 		DestFeatures:       p.payment.DestFeatures,
@@ -261,6 +264,68 @@ func (p *paymentSession) RequestRoute(maxAmt, feeLimit lnwire.MilliBronees,
 
 	finalHtlcExpiry := int32(height) + int32(finalCltvDelta)
 
+	// A payment to a blinded path is routed to the introduction node of
+	// the path, which must receive the fee of the blinded part of the
+	// route on top of the amount. The fee limit covers both parts.
+	blindedPayment := p.payment.BlindedPayment
+	if blindedPayment != nil {
+		blindedFee := blindedPayment.fee(maxAmt)
+		if blindedFee > feeLimit {
+			p.log.Debugf("blinded path fee %v exceeds fee limit %v",
+				blindedFee, feeLimit)
+
+			return nil, errNoPathFound
+		}
+		restrictions.FeeLimit -= blindedFee
+	}
+
+	// A payment through a trampoline node is routed to the trampoline
+	// node, which must receive its fee on top of the amount and enough
+	// CLTV headroom to reach the destination. The destination is reached
+	// through the trampoline onion, so restrictions on the last hop to the
+	// destination can't be respected.
+	target := p.payment.Target
+	trampoline := p.payment.trampoline
+	pathCltvDelta := finalCltvDelta
+	if trampoline != nil {
+		if hasLastHopRestrictions(p.payment) {
+			return nil, ErrTrampolineUnsupported
+		}
+
+		trampolineFee := trampoline.fee(maxAmt)
+		if trampolineFee > feeLimit {
+			p.log.Debugf("trampoline fee %v exceeds fee limit %v",
+				trampolineFee, feeLimit)
+
+			return nil, errNoPathFound
+		}
+
+		if cltvLimit <= uint32(trampoline.CltvExpiryDelta) {
+			p.log.Debugf("trampoline cltv delta %v exceeds cltv "+
+				"limit %v", trampoline.CltvExpiryDelta,
+				cltvLimit)
+
+			return nil, errNoPathFound
+		}
+
+		target = trampoline.Node
+		pathCltvDelta += trampoline.CltvExpiryDelta
+		finalHtlcExpiry += int32(trampoline.CltvExpiryDelta)
+
+		restrictions.FeeLimit -= trampolineFee
+		restrictions.CltvLimit -= uint32(trampoline.CltvExpiryDelta)
+		restrictions.DestCustomRecords = trampolineOnionPlaceholder()
+		restrictions.DestFeatures = trampolineFeatures
+		restrictions.PaymentAddr = &trampoline.paymentAddr
+	}
+
+	// A payment that forwards a trampoline onion to the next trampoline
+	// node must leave room for the onion in the payload of the target.
+	if p.payment.TrampolineOnion != nil {
+		restrictions.DestCustomRecords = trampolineOnionPlaceholder()
+		restrictions.DestFeatures = trampolineFeatures
+	}
+
 	// Before we enter the loop below, we'll make sure to respect the max
 	// payment shard size (if it's set), which is effectively our
 	// client-side MTU that we'll attempt to respect at all times.
@@ -295,6 +360,14 @@ func (p *paymentSession) RequestRoute(maxAmt, feeLimit lnwire.MilliBronees,
 
 		sourceVertex := routingGraph.sourceNode()
 
+		pathAmt := maxAmt
+		if blindedPayment != nil {
+			pathAmt += blindedPayment.fee(maxAmt)
+		}
+		if trampoline != nil {
+			pathAmt += trampoline.fee(maxAmt)
+		}
+
 		// Find a route for the current amount.
 		path, err := p.pathFinder(
 			&graphParams{
@@ -303,8 +376,7 @@ func (p *paymentSession) RequestRoute(maxAmt, feeLimit lnwire.MilliBronees,
 				graph:           routingGraph,
 			},
 			restrictions, &p.pathFindingConfig,
-			sourceVertex, p.payment.Target,
-			maxAmt, finalHtlcExpiry,
+			sourceVertex, target, pathAmt, finalHtlcExpiry,
 		)
 
 		// Close routing graph.
@@ -380,20 +452,54 @@ func (p *paymentSession) RequestRoute(maxAmt, feeLimit lnwire.MilliBronees,
 		// With the next candidate path found, we'll attempt to turn
 		// this into a route by applying the time-lock and fee
 		// requirements.
-		route, err := newRoute(
-			sourceVertex, path, height,
-			finalHopParams{
-				amt:         maxAmt,
-				totalAmt:    p.payment.Amount,
-				cltvDelta:   finalCltvDelta,
-				records:     p.payment.DestCustomRecords,
-				paymentAddr: p.payment.PaymentAddr,
-			},
-		)
+		finalHop := finalHopParams{
+			amt:         pathAmt,
+			totalAmt:    p.payment.Amount,
+			cltvDelta:   finalCltvDelta,
+			records:     p.payment.DestCustomRecords,
+			paymentAddr: p.payment.PaymentAddr,
+		}
+		if trampoline != nil {
+			finalHop = finalHopParams{
+				amt:         pathAmt,
+				totalAmt:    pathAmt,
+				cltvDelta:   pathCltvDelta,
+				paymentAddr: &trampoline.paymentAddr,
+			}
+		}
+
+		route, err := newRoute(sourceVertex, path, height, finalHop)
 		if err != nil {
 			return nil, err
 		}
 
+		// The trampoline node receives the trampoline onion that tells
+		// it how to reach the destination.
+		if p.payment.TrampolineOnion != nil {
+			route.FinalHop().TrampolineOnion =
+				p.payment.TrampolineOnion
+		}
+		if trampoline != nil {
+			err := trampoline.extendRoute(
+				route, p.payment, maxAmt,
+				height+uint32(finalCltvDelta),
+			)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		// The route to the introduction node is extended by the hops
+		// of the blinded path. The destination only checks that the
+		// HTLC doesn't expire before the expiry we tell it, which is
+		// padded like the final expiry of any other payment.
+		if blindedPayment != nil {
+			blindedPayment.extendRoute(
+				route, maxAmt, p.payment.Amount,
+				height+uint32(BlockPadding),
+			)
+		}
+
 		return route, err
 	}
 }
@@ -5,10 +5,14 @@ import (
 	"time"
 
 	"github.com/brronsuite/broln/channeldb"
+	"github.com/brronsuite/broln/htlcswitch/hop"
+	"github.com/brronsuite/broln/keychain"
 	"github.com/brronsuite/broln/lntypes"
 	"github.com/brronsuite/broln/lnwire"
+	"github.com/brronsuite/broln/record"
 	"github.com/brronsuite/broln/routing/route"
 	"github.com/brronsuite/broln/zpay32"
+	"github.com/brronsuite/brond/bronec"
 	"github.com/stretchr/testify/require"
 )
 
@@ -250,6 +254,275 @@ func TestRequestRoute(t *testing.T) {
 	}
 }
 
+// TestRequestRouteTrampoline asserts that a payment through a trampoline node
+// is routed to the trampoline node, which receives a trampoline onion that
+// tells it how to reach the destination.
+func TestRequestRouteTrampoline(t *testing.T) {
+	const (
+		height         = 10
+		finalCltvDelta = 18
+		trampolineCltv = 144
+	)
+
+	trampolineKey, err := bronec.NewPrivateKey(bronec.S256())
+	require.NoError(t, err)
+	destKey, err := bronec.NewPrivateKey(bronec.S256())
+	require.NoError(t, err)
+
+	trampolineNode := route.NewVertex(trampolineKey.PubKey())
+	paymentAddr := [32]byte{1}
+	payment := &LightningPayment{
+		Target:         route.NewVertex(destKey.PubKey()),
+		CltvLimit:      1000,
+		FinalCLTVDelta: finalCltvDelta,
+		Amount:         100_000,
+		FeeLimit:       10_000,
+		PaymentAddr:    &paymentAddr,
+		MaxParts:       1,
+		trampoline: &TrampolinePayment{
+			Node:            trampolineNode,
+			BaseFee:         1000,
+			ProportionalFee: 5000,
+			CltvExpiryDelta: trampolineCltv,
+			paymentAddr:     [32]byte{2},
+		},
+	}
+	require.NoError(t, payment.SetPaymentHash([32]byte{}))
+
+	session, err := newPaymentSession(
+		payment,
+		func(routingGraph) (bandwidthHints, error) {
+			return &mockBandwidthHints{}, nil
+		},
+		func() (routingGraph, func(), error) {
+			return &sessionGraph{}, func() {}, nil
+		},
+		&MissionControl{},
+		PathFindingConfig{},
+	)
+	require.NoError(t, err)
+
+	// The trampoline node charges 1000 msat plus 0.5% of the amount.
+	const pathAmt = 100_000 + 1000 + 500
+
+	session.pathFinder = func(
+		g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
+		source, target route.Vertex, amt lnwire.MilliBronees,
+		finalHtlcExpiry int32) ([]*channeldb.CachedEdgePolicy, error) {
+
+		// Path finding must target the trampoline node and leave room
+		// for its fee, cltv delta and the trampoline onion.
+		require.Equal(t, trampolineNode, target)
+		require.EqualValues(t, pathAmt, amt)
+		require.EqualValues(t, 10_000-1500, r.FeeLimit)
+		require.EqualValues(
+			t, 1000-finalCltvDelta-BlockPadding-trampolineCltv,
+			r.CltvLimit,
+		)
+		require.Equal(t, [32]byte{2}, *r.PaymentAddr)
+		require.Contains(
+			t, r.DestCustomRecords,
+			uint64(record.TrampolineOnionType),
+		)
+
+		return []*channeldb.CachedEdgePolicy{{
+			ToNodePubKey: func() route.Vertex {
+				return trampolineNode
+			},
+			ToNodeFeatures: trampolineFeatures,
+		}}, nil
+	}
+
+	rt, err := session.RequestRoute(
+		payment.Amount, payment.FeeLimit, 0, height,
+	)
+	require.NoError(t, err)
+
+	// The route ends at the destination, which isn't part of the outer
+	// onion.
+	require.Len(t, rt.Hops, 2)
+	require.EqualValues(t, pathAmt, rt.TotalAmount)
+	require.EqualValues(t, 1500, rt.TotalFees())
+	require.Equal(t, payment.Amount, rt.ReceiverAmt())
+	require.EqualValues(
+		t, height+finalCltvDelta+BlockPadding+trampolineCltv,
+		rt.TotalTimeLock,
+	)
+
+	path, err := rt.ToSphinxPath()
+	require.NoError(t, err)
+	require.Equal(t, 1, path.TrueRouteLength())
+
+	trampolineHop := rt.Hops[0]
+	require.EqualValues(t, pathAmt, trampolineHop.MPP.TotalMsat())
+	require.Len(t, trampolineHop.TrampolineOnion, hop.TrampolineOnionSize)
+
+	// The trampoline node learns how to pay the destination, which doesn't
+	// support trampoline routing, from the trampoline onion.
+	payload, next, err := hop.ProcessTrampolineOnion(
+		&keychain.PrivKeyECDH{PrivKey: trampolineKey},
+		trampolineHop.TrampolineOnion,
+	)
+	require.NoError(t, err)
+	require.Nil(t, next)
+	require.Equal(t, payment.Amount, payload.AmtToForward)
+	require.EqualValues(
+		t, height+finalCltvDelta+BlockPadding, payload.OutgoingCltv,
+	)
+	require.True(t, payload.OutgoingNodeID.IsEqual(destKey.PubKey()))
+	require.Equal(t, paymentAddr, payload.MPP.PaymentAddr())
+	require.Equal(t, payment.Amount, payload.MPP.TotalMsat())
+}
+
+// TestRequestRouteTrampolineLastHops asserts that a payment through a
+// trampoline node can't restrict the last hop to the destination, as that part
+// of the route is found by the trampoline node.
+func TestRequestRouteTrampolineLastHops(t *testing.T) {
+	trampolineKey, err := bronec.NewPrivateKey(bronec.S256())
+	require.NoError(t, err)
+	destKey, err := bronec.NewPrivateKey(bronec.S256())
+	require.NoError(t, err)
+
+	trampolineNode := route.NewVertex(trampolineKey.PubKey())
+	target := route.NewVertex(destKey.PubKey())
+
+	testCases := []struct {
+		name               string
+		lastHops           []route.Vertex
+		incomingChannelIDs []uint64
+	}{
+		{
+			name:     "last hops",
+			lastHops: []route.Vertex{{1}},
+		},
+		{
+			name:               "incoming channels",
+			incomingChannelIDs: []uint64{1},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			paymentAddr := [32]byte{1}
+			payment := &LightningPayment{
+				Target:             target,
+				CltvLimit:          1000,
+				FinalCLTVDelta:     18,
+				Amount:             100_000,
+				FeeLimit:           10_000,
+				PaymentAddr:        &paymentAddr,
+				MaxParts:           1,
+				LastHops:           tc.lastHops,
+				IncomingChannelIDs: tc.incomingChannelIDs,
+				trampoline: &TrampolinePayment{
+					Node:            trampolineNode,
+					CltvExpiryDelta: 144,
+				},
+			}
+			require.NoError(
+				t, payment.SetPaymentHash([32]byte{}),
+			)
+
+			session, err := newPaymentSession(
+				payment,
+				func(routingGraph) (bandwidthHints, error) {
+					return &mockBandwidthHints{}, nil
+				},
+				func() (routingGraph, func(), error) {
+					return &sessionGraph{}, func() {}, nil
+				},
+				&MissionControl{},
+				PathFindingConfig{},
+			)
+			require.NoError(t, err)
+
+			_, err = session.RequestRoute(
+				payment.Amount, payment.FeeLimit, 0, 10,
+			)
+			require.ErrorIs(t, err, ErrTrampolineUnsupported)
+		})
+	}
+}
+
+// TestRequestRouteTrampolineForward tests that a payment that forwards a
+// trampoline onion hands the onion to the target of the payment.
+func TestRequestRouteTrampolineForward(t *testing.T) {
+	const (
+		height         = 10
+		finalCltvDelta = 144
+	)
+
+	targetKey, err := bronec.NewPrivateKey(bronec.S256())
+	require.NoError(t, err)
+
+	target := route.NewVertex(targetKey.PubKey())
+	paymentAddr := [32]byte{1}
+	onion := make([]byte, hop.TrampolineOnionSize)
+	payment := &LightningPayment{
+		Target:          target,
+		CltvLimit:       1000,
+		FinalCLTVDelta:  finalCltvDelta,
+		Amount:          100_000,
+		FeeLimit:        10_000,
+		PaymentAddr:     &paymentAddr,
+		MaxParts:        1,
+		TrampolineOnion: onion,
+	}
+	require.NoError(t, payment.SetPaymentHash([32]byte{}))
+
+	session, err := newPaymentSession(
+		payment,
+		func(routingGraph) (bandwidthHints, error) {
+			return &mockBandwidthHints{}, nil
+		},
+		func() (routingGraph, func(), error) {
+			return &sessionGraph{}, func() {}, nil
+		},
+		&MissionControl{},
+		PathFindingConfig{},
+	)
+	require.NoError(t, err)
+
+	session.pathFinder = func(
+		g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
+		source, target route.Vertex, amt lnwire.MilliBronees,
+		finalHtlcExpiry int32) ([]*channeldb.CachedEdgePolicy, error) {
+
+		// Path finding must leave room for the trampoline onion, and
+		// must not depend on the features of the target in the graph.
+		require.Equal(t, payment.Target, target)
+		require.EqualValues(t, payment.Amount, amt)
+		require.Contains(
+			t, r.DestCustomRecords,
+			uint64(record.TrampolineOnionType),
+		)
+		require.Equal(t, trampolineFeatures, r.DestFeatures)
+
+		return []*channeldb.CachedEdgePolicy{{
+			ToNodePubKey: func() route.Vertex {
+				return target
+			},
+			ToNodeFeatures: trampolineFeatures,
+		}}, nil
+	}
+
+	rt, err := session.RequestRoute(
+		payment.Amount, payment.FeeLimit, 0, height,
+	)
+	require.NoError(t, err)
+
+	require.Len(t, rt.Hops, 1)
+	finalHop := rt.FinalHop()
+	require.Equal(t, onion, finalHop.TrampolineOnion)
+	require.Empty(t, finalHop.CustomRecords)
+	require.Equal(t, paymentAddr, finalHop.MPP.PaymentAddr())
+	require.EqualValues(
+		t, height+finalCltvDelta+BlockPadding, rt.TotalTimeLock,
+	)
+}
+
 type sessionGraph struct {
 	routingGraph
 }
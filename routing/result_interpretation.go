@@ -94,8 +94,14 @@ func interpretResult(rt *route.Route, success bool, failureSrcIdx *int,
 // processSuccess processes a successful payment attempt.
 func (i *interpretedResult) processSuccess(route *route.Route) {
 	// For successes, all nodes must have acted in the right way. Therefore
-	// we mark all of them with a success result.
-	i.successPairRange(route, 0, len(route.Hops)-1)
+	// we mark all of them with a success result. The hops behind a
+	// trampoline node were chosen by the trampoline node, so we don't
+	// learn anything about them.
+	lastIdx := len(route.Hops) - 1
+	if trampolineIdx := trampolineNodeIdx(route); trampolineIdx > 0 {
+		lastIdx = trampolineIdx - 1
+	}
+	i.successPairRange(route, 0, lastIdx)
 }
 
 // processFail processes a failed payment attempt.
@@ -108,6 +114,14 @@ func (i *interpretedResult) processFail(
 		return
 	}
 
+	// Failures reported by a trampoline node concern the part of the
+	// payment that the trampoline node routes on our behalf.
+	trampolineIdx := trampolineNodeIdx(rt)
+	if trampolineIdx > 0 && *errSourceIdx == trampolineIdx {
+		i.processPaymentOutcomeTrampoline(rt, trampolineIdx, failure)
+		return
+	}
+
 	switch *errSourceIdx {
 
 	// We are the source of the failure.
@@ -401,6 +415,47 @@ func (i *interpretedResult) processPaymentOutcomeIntermediate(
 	}
 }
 
+// processPaymentOutcomeTrampoline handles failures sent by a trampoline node.
+func (i *interpretedResult) processPaymentOutcomeTrampoline(
+	route *route.Route, trampolineIdx int,
+	failure lnwire.FailureMessage) {
+
+	// The payment reached the trampoline node, so the route to the
+	// trampoline node worked.
+	i.successPairRange(route, 0, trampolineIdx-1)
+
+	switch failure.(type) {
+
+	// The recipient rejected the payment, which the trampoline node
+	// relays to us.
+	case *lnwire.FailIncorrectDetails,
+		*lnwire.FailIncorrectPaymentAmount,
+		*lnwire.FailFinalExpiryTooSoon:
+
+		i.finalFailureReason = &reasonIncorrectDetails
+
+	// In all other cases, either our fee and cltv budget didn't satisfy
+	// the trampoline node's policy or it couldn't reach the recipient.
+	// Another route to the same trampoline node won't change that, so we
+	// fail the payment.
+	default:
+		i.finalFailureReason = &reasonError
+	}
+}
+
+// trampolineNodeIdx returns the index of the trampoline node in the route,
+// using the same indexing as the failure source index. Zero is returned if
+// the route doesn't go through a trampoline node.
+func trampolineNodeIdx(rt *route.Route) int {
+	for idx, hop := range rt.Hops {
+		if hop.TrampolineOnion != nil {
+			return idx + 1
+		}
+	}
+
+	return 0
+}
+
 // processPaymentOutcomeUnknown processes a payment outcome for which no failure
 // message or source is available.
 func (i *interpretedResult) processPaymentOutcomeUnknown(route *route.Route) {
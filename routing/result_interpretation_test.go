@@ -52,6 +52,20 @@ var (
 			{PubKeyBytes: hops[4], AmtToForward: 90},
 		},
 	}
+
+	routeTrampoline = route.Route{
+		SourcePubKey: hops[0],
+		TotalAmount:  100,
+		Hops: []*route.Hop{
+			{PubKeyBytes: hops[1], AmtToForward: 99},
+			{
+				PubKeyBytes:     hops[2],
+				AmtToForward:    97,
+				TrampolineOnion: []byte{1},
+			},
+			{PubKeyBytes: hops[3], AmtToForward: 90},
+		},
+	}
 )
 
 func getTestPair(from, to int) DirectedNodePair {
@@ -367,6 +381,71 @@ var resultTestCases = []resultTestCase{
 			policyFailure: getPolicyFailure(1, 2),
 		},
 	},
+
+	// Test that a policy failure of a trampoline node fails the payment
+	// without penalizing the route to the trampoline node.
+	{
+		name:          "trampoline fee insufficient",
+		route:         &routeTrampoline,
+		failureSrcIdx: 2,
+		failure:       &lnwire.FailTrampolineFeeInsufficient{},
+
+		expectedResult: &interpretedResult{
+			pairResults: map[DirectedNodePair]pairResult{
+				getTestPair(0, 1): successPairResult(100),
+				getTestPair(1, 2): successPairResult(99),
+			},
+			finalFailureReason: &reasonError,
+		},
+	},
+
+	// Test that incorrect payment details relayed by a trampoline node
+	// are interpreted like the ones of a final node.
+	{
+		name:          "trampoline incorrect details",
+		route:         &routeTrampoline,
+		failureSrcIdx: 2,
+		failure:       lnwire.NewFailIncorrectDetails(90, 0),
+
+		expectedResult: &interpretedResult{
+			pairResults: map[DirectedNodePair]pairResult{
+				getTestPair(0, 1): successPairResult(100),
+				getTestPair(1, 2): successPairResult(99),
+			},
+			finalFailureReason: &reasonIncorrectDetails,
+		},
+	},
+
+	// Test that a failure before the trampoline node is interpreted like
+	// any other intermediate failure.
+	{
+		name:          "trampoline route failure",
+		route:         &routeTrampoline,
+		failureSrcIdx: 1,
+		failure:       lnwire.NewTemporaryChannelFailure(nil),
+
+		expectedResult: &interpretedResult{
+			pairResults: map[DirectedNodePair]pairResult{
+				getTestPair(0, 1): successPairResult(100),
+				getTestPair(1, 2): failPairResult(99),
+			},
+		},
+	},
+
+	// Test that a success through a trampoline node only rewards the route
+	// to the trampoline node.
+	{
+		name:    "trampoline success",
+		route:   &routeTrampoline,
+		success: true,
+
+		expectedResult: &interpretedResult{
+			pairResults: map[DirectedNodePair]pairResult{
+				getTestPair(0, 1): successPairResult(100),
+				getTestPair(1, 2): successPairResult(99),
+			},
+		},
+	},
 }
 
 // TestResultInterpretation executes a list of test cases that test the result
@@ -127,6 +127,25 @@ type Hop struct {
 	// understand the new TLV payload, so we must instead use the legacy
 	// payload.
 	LegacyPayload bool
+
+	// EncryptedData is the data the recipient encrypted for this hop of a
+	// blinded route. It is set for all hops of the blinded part of the
+	// route, including its introduction node.
+	EncryptedData []byte
+
+	// BlindingPoint is the blinding point of a blinded route. This field
+	// should only be set for the introduction node.
+	BlindingPoint *bronec.PublicKey
+
+	// TotalAmtMsat is the total amount of a payment to a blinded route.
+	// This field should only be set for the final hop of a blinded route.
+	TotalAmtMsat lnwire.MilliBronees
+
+	// TrampolineOnion is the trampoline onion that is delivered to this
+	// hop. If it is set, the hop is the trampoline node that finds the
+	// route to the final recipient, which is the only hop that follows it
+	// in the route. The final recipient isn't part of the outer onion.
+	TrampolineOnion []byte
 }
 
 // Copy returns a deep copy of the Hop.
@@ -143,6 +162,16 @@ func (h *Hop) Copy() *Hop {
 		c.AMP = &a
 	}
 
+	if h.EncryptedData != nil {
+		c.EncryptedData = make([]byte, len(h.EncryptedData))
+		copy(c.EncryptedData, h.EncryptedData)
+	}
+
+	if h.TrampolineOnion != nil {
+		c.TrampolineOnion = make([]byte, len(h.TrampolineOnion))
+		copy(c.TrampolineOnion, h.TrampolineOnion)
+	}
+
 	return &c
 }
 
@@ -165,23 +194,47 @@ func (h *Hop) PackHopPayload(w io.Writer, nextChanID uint64) error {
 	// required routing fields, as well as these optional values.
 	var records []tlv.Record
 
-	// Every hop must have an amount to forward and CLTV expiry.
-	amt := uint64(h.AmtToForward)
-	records = append(records,
-		record.NewAmtToFwdRecord(&amt),
-		record.NewLockTimeRecord(&h.OutgoingTimeLock),
-	)
+	// Hops within a blinded route learn how to forward the HTLC from
+	// their encrypted data, so only the final hop of the route receives
+	// the amount and expiry from us.
+	isBlinded := h.EncryptedData != nil
+	if !isBlinded || h.TotalAmtMsat != 0 {
+		amt := uint64(h.AmtToForward)
+		records = append(records,
+			record.NewAmtToFwdRecord(&amt),
+			record.NewLockTimeRecord(&h.OutgoingTimeLock),
+		)
+	}
 
 	// BOLT 04 says the next_hop_id should be omitted for the final hop,
 	// but present for all others.
 	//
 	// TODO(conner): test using hop.Exit once available
-	if nextChanID != 0 {
+	if nextChanID != 0 && !isBlinded {
 		records = append(records,
 			record.NewNextHopIDRecord(&nextChanID),
 		)
 	}
 
+	if isBlinded {
+		records = append(records,
+			record.NewEncryptedDataRecord(&h.EncryptedData),
+		)
+	}
+
+	if h.BlindingPoint != nil {
+		records = append(records,
+			record.NewBlindingPointRecord(&h.BlindingPoint),
+		)
+	}
+
+	if h.TotalAmtMsat != 0 {
+		totalAmt := uint64(h.TotalAmtMsat)
+		records = append(records,
+			record.NewTotalAmtMsatBlindedRecord(&totalAmt),
+		)
+	}
+
 	// If an MPP record is destined for this hop, ensure that we only ever
 	// attach it to the final hop. Otherwise the route was constructed
 	// incorrectly.
@@ -205,6 +258,12 @@ func (h *Hop) PackHopPayload(w io.Writer, nextChanID uint64) error {
 		}
 	}
 
+	if h.TrampolineOnion != nil {
+		records = append(records,
+			record.NewTrampolineOnionRecord(&h.TrampolineOnion),
+		)
+	}
+
 	// Append any custom types destined for this hop.
 	tlvRecords := tlv.MapToRecords(h.CustomRecords)
 	records = append(records, tlvRecords...)
@@ -235,20 +294,43 @@ func (h *Hop) PayloadSize(nextChanID uint64) uint64 {
 			tlv.VarIntSize(length) + length
 	}
 
-	// Add amount size.
-	addRecord(record.AmtOnionType, tlv.SizeTUint64(uint64(h.AmtToForward)))
+	isBlinded := h.EncryptedData != nil
+	if !isBlinded || h.TotalAmtMsat != 0 {
+		// Add amount size.
+		addRecord(
+			record.AmtOnionType,
+			tlv.SizeTUint64(uint64(h.AmtToForward)),
+		)
 
-	// Add lock time size.
-	addRecord(
-		record.LockTimeOnionType,
-		tlv.SizeTUint64(uint64(h.OutgoingTimeLock)),
-	)
+		// Add lock time size.
+		addRecord(
+			record.LockTimeOnionType,
+			tlv.SizeTUint64(uint64(h.OutgoingTimeLock)),
+		)
+	}
 
 	// Add next hop if present.
-	if nextChanID != 0 {
+	if nextChanID != 0 && !isBlinded {
 		addRecord(record.NextHopOnionType, 8)
 	}
 
+	// Add the blinded route fields if present.
+	if isBlinded {
+		addRecord(
+			record.EncryptedDataOnionType,
+			uint64(len(h.EncryptedData)),
+		)
+	}
+	if h.BlindingPoint != nil {
+		addRecord(record.BlindingPointOnionType, 33)
+	}
+	if h.TotalAmtMsat != 0 {
+		addRecord(
+			record.TotalAmtMsatBlindedType,
+			tlv.SizeTUint64(uint64(h.TotalAmtMsat)),
+		)
+	}
+
 	// Add mpp if present.
 	if h.MPP != nil {
 		addRecord(record.MPPOnionType, h.MPP.PayloadSize())
@@ -259,6 +341,14 @@ func (h *Hop) PayloadSize(nextChanID uint64) uint64 {
 		addRecord(record.AMPOnionType, h.AMP.PayloadSize())
 	}
 
+	// Add trampoline onion if present.
+	if h.TrampolineOnion != nil {
+		addRecord(
+			record.TrampolineOnionType,
+			uint64(len(h.TrampolineOnion)),
+		)
+	}
+
 	// Add custom records.
 	for k, v := range h.CustomRecords {
 		addRecord(tlv.Type(k), uint64(len(v)))
@@ -402,8 +492,12 @@ func (r *Route) ToSphinxPath() (*sphinx.PaymentPath, error) {
 
 	// For each hop encoded within the route, we'll convert the hop struct
 	// to an OnionHop with matching per-hop payload within the path as used
-	// by the sphinx package.
+	// by the sphinx package. The onion ends at the trampoline node if the
+	// route has one, as the final recipient is reached through the
+	// trampoline onion.
 	for i, hop := range r.Hops {
+		isTrampoline := hop.TrampolineOnion != nil
+
 		pub, err := bronec.ParsePubKey(
 			hop.PubKeyBytes[:], bronec.S256(),
 		)
@@ -417,7 +511,7 @@ func (r *Route) ToSphinxPath() (*sphinx.PaymentPath, error) {
 
 		// If we aren't on the last hop, then we set the "next address"
 		// field to be the channel that directly follows it.
-		if i != len(r.Hops)-1 {
+		if i != len(r.Hops)-1 && !isTrampoline {
 			nextHop = r.Hops[i+1].ChannelID
 		}
 
@@ -465,6 +559,10 @@ func (r *Route) ToSphinxPath() (*sphinx.PaymentPath, error) {
 			NodePub:    *pub,
 			HopPayload: payload,
 		}
+
+		if isTrampoline {
+			break
+		}
 	}
 
 	return &path, nil
@@ -8,6 +8,7 @@ import (
 	"github.com/brronsuite/broln/lnwire"
 	"github.com/brronsuite/broln/record"
 	"github.com/brronsuite/brond/bronec"
+	"github.com/stretchr/testify/require"
 )
 
 var (
@@ -213,3 +214,51 @@ func TestPayloadSize(t *testing.T) {
 		}
 	}
 }
+
+// TestTrampolineHop asserts that the outer onion of a route with a trampoline
+// node ends at the trampoline node, which receives the trampoline onion.
+func TestTrampolineHop(t *testing.T) {
+	trampolineOnion := bytes.Repeat([]byte{1}, 466)
+	hops := []*Hop{
+		{
+			PubKeyBytes:      testPubKeyBytes,
+			AmtToForward:     1200,
+			OutgoingTimeLock: 700000,
+			ChannelID:        63584534844,
+		},
+		{
+			PubKeyBytes:      testPubKeyBytes,
+			AmtToForward:     1200,
+			OutgoingTimeLock: 700040,
+			ChannelID:        3432483437438,
+			MPP:              record.NewMPP(1200, [32]byte{2}),
+			TrampolineOnion:  trampolineOnion,
+		},
+		{
+			PubKeyBytes:      testPubKeyBytes,
+			AmtToForward:     1000,
+			OutgoingTimeLock: 700000,
+			MPP:              record.NewMPP(1000, [32]byte{1}),
+		},
+	}
+
+	rt := Route{
+		TotalAmount: 1300,
+		Hops:        hops,
+	}
+	require.Equal(t, lnwire.MilliBronees(1000), rt.ReceiverAmt())
+	require.Equal(t, lnwire.MilliBronees(300), rt.TotalFees())
+
+	path, err := rt.ToSphinxPath()
+	require.NoError(t, err)
+	require.Equal(t, 2, path.TrueRouteLength())
+
+	// The trampoline node is the final hop of the outer onion, so its
+	// payload is packed without a next hop.
+	var b bytes.Buffer
+	require.NoError(t, hops[1].PackHopPayload(&b, 0))
+	require.Equal(t, b.Bytes(), path[1].HopPayload.Payload)
+	require.EqualValues(
+		t, path[1].HopPayload.NumBytes(), hops[1].PayloadSize(0),
+	)
+}
@@ -103,6 +103,13 @@ type ChannelGraphSource interface {
 	// edge/channel might be used in construction of payment path.
 	AddEdge(edge *channeldb.ChannelEdgeInfo, op ...batch.SchedulerOption) error
 
+	// AddAliasEdge is used to add one of our own channels whose short
+	// channel ID is an alias to the topology of the router. As an alias
+	// doesn't point to a funding output, the edge isn't validated against
+	// the chain.
+	AddAliasEdge(edge *channeldb.ChannelEdgeInfo,
+		op ...batch.SchedulerOption) error
+
 	// AddProof updates the channel edge info with proof which is needed to
 	// properly announce the edge to the rest of the network.
 	AddProof(chanID lnwire.ShortChannelID, proof *channeldb.ChannelAuthProof) error
@@ -245,6 +252,11 @@ type FeeSchema struct {
 	// the effective fee rate charged per mSAT will be: (amount *
 	// FeeRate/1,000,000).
 	FeeRate uint32
+
+	// InboundFee is the optional fee that is charged for payments that
+	// arrive over the channel, on top of the fee of the outgoing channel.
+	// If nil, the current inbound fee is kept.
+	InboundFee *lnwire.InboundFee
 }
 
 // ChannelPolicy holds the parameters that determine the policy we enforce
@@ -359,6 +371,10 @@ type Config struct {
 	// Otherwise, we'll only prune the channel when both edges have a very
 	// dated last update.
 	StrictZombiePruning bool
+
+	// Trampoline holds the configuration of payments through trampoline
+	// nodes.
+	Trampoline TrampolineConfig
 }
 
 // EdgeLocator is a struct used to identify a specific edge.
@@ -1084,7 +1100,9 @@ func (r *ChannelRouter) networkHandler() {
 				// this is either a new update from our PoV or
 				// an update to a prior vertex/edge we
 				// previously accepted.
-				err = r.processUpdate(update.msg, update.op...)
+				err = r.processUpdate(
+					update.msg, update.isAlias, update.op...,
+				)
 				update.err <- err
 
 				// If this message had any dependencies, then
@@ -1413,7 +1431,7 @@ func (r *ChannelRouter) addZombieEdge(chanID uint64) error {
 // channel/edge update network update. If the update didn't affect the internal
 // state of the draft due to either being out of date, invalid, or redundant,
 // then error is returned.
-func (r *ChannelRouter) processUpdate(msg interface{},
+func (r *ChannelRouter) processUpdate(msg interface{}, isAlias bool,
 	op ...batch.SchedulerOption) error {
 
 	switch msg := msg.(type) {
@@ -1456,8 +1474,9 @@ func (r *ChannelRouter) processUpdate(msg interface{},
 		// If AssumeChannelValid is present, then we are unable to
 		// perform any of the expensive checks below, so we'll
 		// short-circuit our path straight to adding the edge to our
-		// graph.
-		if r.cfg.AssumeChannelValid {
+		// graph. The same applies to the alias edges of our own
+		// channels, as they don't point to an on-chain funding output.
+		if r.cfg.AssumeChannelValid || isAlias {
 			if err := r.cfg.Graph.AddChannelEdge(msg, op...); err != nil {
 				return fmt.Errorf("unable to add edge: %v", err)
 			}
@@ -1727,6 +1746,10 @@ type routingMsg struct {
 	msg interface{}
 	op  []batch.SchedulerOption
 	err chan error
+
+	// isAlias is true if msg is an edge of one of our own channels whose
+	// short channel ID is an alias.
+	isAlias bool
 }
 
 // FindRoute attempts to query the ChannelRouter for the optimum path to a
@@ -1925,9 +1948,23 @@ type LightningPayment struct {
 	// first hop. If nil, any channel may be used.
 	OutgoingChannelIDs []uint64
 
-	// LastHop is the pubkey of the last node before the final destination
-	// is reached. If nil, any node may be used.
-	LastHop *route.Vertex
+	// LastHops is the list of nodes that are allowed as the last node
+	// before the final destination is reached. If nil, any node may be
+	// used.
+	LastHops []route.Vertex
+
+	// IncomingChannelIDs is the list of channels that are allowed for the
+	// last hop into the final destination. If nil, any channel may be
+	// used.
+	IncomingChannelIDs []uint64
+
+	// ExcludedNodes is the list of nodes that must not be used as a hop
+	// by any of the partial payments.
+	ExcludedNodes []route.Vertex
+
+	// ExcludedChannelIDs is the list of channels that must not be used by
+	// any of the partial payments.
+	ExcludedChannelIDs []uint64
 
 	// DestFeatures specifies the set of features we assume the final node
 	// has for pathfinding. Typically these will be taken directly from an
@@ -1963,6 +2000,36 @@ type LightningPayment struct {
 	//
 	// NOTE: This field is _optional_.
 	MaxShardAmt *lnwire.MilliBronees
+
+	// BlindedPayment is the blinded path the payment is sent through. If
+	// set, Target must be the introduction node of the path and
+	// FinalCLTVDelta the CLTV delta of the path. Payments to blinded paths
+	// are sent in a single shard, as the destination identifies the
+	// payment by the path instead of a payment address.
+	//
+	// NOTE: This field is _optional_.
+	BlindedPayment *BlindedPayment
+
+	// TrampolineNode is the trampoline node the payment is sent through.
+	// If set, path finding is only used to find a route to the trampoline
+	// node, which finds the route to the final destination on our behalf.
+	//
+	// NOTE: This field is _optional_.
+	TrampolineNode *route.Vertex
+
+	// TrampolineOnion is the trampoline onion that is handed to Target,
+	// which must be a trampoline node. It is set when we forward a
+	// trampoline payment to the next trampoline node, in which case
+	// PaymentAddr is the payment address of the outer onion that the next
+	// trampoline node uses to identify the payment.
+	//
+	// NOTE: This field is _optional_.
+	TrampolineOnion []byte
+
+	// trampoline is set by the router if the payment is sent through a
+	// trampoline node, either because TrampolineNode is set or because
+	// trampoline payments are enabled in the router's config.
+	trampoline *TrampolinePayment
 }
 
 // AMPOptions houses information that must be known in order to send an AMP
@@ -2088,6 +2155,12 @@ func spewPayment(payment *LightningPayment) logClosure {
 func (r *ChannelRouter) preparePayment(payment *LightningPayment) (
 	PaymentSession, shards.ShardTracker, error) {
 
+	// Decide whether the payment is sent through a trampoline node before
+	// the payment session is created, as it affects path finding.
+	if err := r.setTrampoline(payment); err != nil {
+		return nil, nil, err
+	}
+
 	// Before starting the HTLC routing attempt, we'll create a fresh
 	// payment session which will report our errors back to mission
 	// control.
@@ -2445,6 +2518,34 @@ func (r *ChannelRouter) AddEdge(edge *channeldb.ChannelEdgeInfo,
 	}
 }
 
+// AddAliasEdge is used to add one of our own channels whose short channel ID is
+// an alias to the topology of the router. As an alias doesn't point to a
+// funding output, the edge isn't validated against the chain.
+//
+// NOTE: This method is part of the ChannelGraphSource interface.
+func (r *ChannelRouter) AddAliasEdge(edge *channeldb.ChannelEdgeInfo,
+	op ...batch.SchedulerOption) error {
+
+	rMsg := &routingMsg{
+		msg:     edge,
+		op:      op,
+		err:     make(chan error, 1),
+		isAlias: true,
+	}
+
+	select {
+	case r.networkUpdates <- rMsg:
+		select {
+		case err := <-rMsg.err:
+			return err
+		case <-r.quit:
+			return ErrRouterShuttingDown
+		}
+	case <-r.quit:
+		return ErrRouterShuttingDown
+	}
+}
+
 // UpdateEdge is used to update edge information, without this message edge
 // considered as not fully constructed.
 //
@@ -2726,7 +2827,9 @@ func (r *ChannelRouter) BuildRoute(amt *lnwire.MilliBronees,
 
 		// Build unified policies for this hop based on the channels
 		// known in the graph.
-		u := newUnifiedPolicies(source, toNode, outgoingChans)
+		u := newUnifiedPolicies(
+			source, toNode, outgoingChans, nil, nil,
+		)
 
 		err := u.addGraphPolicies(r.cachedGraph)
 		if err != nil {
@@ -18,6 +18,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/brronsuite/broln/aliasmgr"
 	"github.com/brronsuite/broln/chainntnfs"
 	"github.com/brronsuite/broln/channeldb"
 	"github.com/brronsuite/broln/clock"
@@ -3481,6 +3482,41 @@ func TestChannelOnChainRejectionZombie(t *testing.T) {
 	assertChanChainRejection(t, ctx, edge, ErrInvalidFundingOutput)
 }
 
+// TestAliasEdgeValidation tests that edges with an alias SCID are only added
+// without validation against the chain if they are explicitly added as alias
+// edges of our own channels.
+func TestAliasEdgeValidation(t *testing.T) {
+	t.Parallel()
+
+	ctx, cleanup := createTestCtxSingleNode(t, 0)
+	defer cleanup()
+
+	aliasHeight := aliasmgr.StartingAlias.BlockHeight
+
+	// An alias edge added through the regular path, which is used for
+	// remote announcements, is validated against the chain and rejected.
+	edge, err := newChannelEdgeInfo(
+		ctx, aliasHeight, edgeCreationNoFundingTx,
+	)
+	require.NoError(t, err)
+	require.True(t, aliasmgr.IsAlias(
+		lnwire.NewShortChanIDFromInt(edge.ChannelID),
+	))
+	assertChanChainRejection(t, ctx, edge, ErrNoFundingTransaction)
+
+	// The alias edge of one of our own channels is added without any
+	// funding transaction on chain.
+	edge, err = newChannelEdgeInfo(
+		ctx, aliasHeight+1, edgeCreationNoFundingTx,
+	)
+	require.NoError(t, err)
+	require.NoError(t, ctx.router.AddAliasEdge(edge))
+
+	_, _, exists, _, err := ctx.graph.HasChannelEdge(edge.ChannelID)
+	require.NoError(t, err)
+	require.True(t, exists, "alias edge should be in the graph")
+}
+
 func createDummyTestGraph(t *testing.T) *testGraphInstance {
 	// Setup two simple channels such that we can mock sending along this
 	// route.
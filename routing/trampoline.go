@@ -0,0 +1,315 @@
+package routing
+
+import (
+	"crypto/rand"
+	"errors"
+
+	"github.com/brronsuite/broln/htlcswitch/hop"
+	"github.com/brronsuite/broln/lnwire"
+	"github.com/brronsuite/broln/record"
+	"github.com/brronsuite/broln/routing/route"
+	"github.com/brronsuite/brond/bronec"
+)
+
+var (
+	// ErrTrampolineNoPaymentAddr is returned when a payment should be sent
+	// through a trampoline node, but the recipient didn't provide a
+	// payment address.
+	ErrTrampolineNoPaymentAddr = errors.New("trampoline payments " +
+		"require a payment address")
+
+	// ErrTrampolineUnsupported is returned when a payment can't be sent
+	// through the trampoline node that was explicitly requested.
+	ErrTrampolineUnsupported = errors.New("trampoline payments can't " +
+		"be combined with blinded paths, AMP, custom records or last " +
+		"hop restrictions")
+
+	// trampolineFeatures are the features we assume a trampoline node
+	// has. They don't need to be known from the graph, which allows us to
+	// pay through trampoline nodes without syncing it.
+	trampolineFeatures = lnwire.NewFeatureVector(
+		lnwire.NewRawFeatureVector(
+			lnwire.TLVOnionPayloadOptional,
+			lnwire.PaymentAddrOptional,
+			lnwire.TrampolineRoutingOptional,
+		), lnwire.Features,
+	)
+)
+
+// TrampolineConfig holds the configuration of payments through trampoline
+// nodes.
+type TrampolineConfig struct {
+	// Pay indicates whether payments without an explicit trampoline node
+	// should be sent through the trampoline node of the invoice or Node.
+	Pay bool
+
+	// Node is the trampoline node that is used if the invoice doesn't
+	// announce one.
+	Node *route.Vertex
+
+	// BaseFee is the base fee that Node charges.
+	BaseFee uint32
+
+	// ProportionalFee is the fee rate in millionths that Node charges.
+	ProportionalFee uint32
+
+	// CltvExpiryDelta is the CLTV delta that Node requires.
+	CltvExpiryDelta uint16
+}
+
+// TrampolinePayment describes the trampoline node a payment is sent through
+// and the relay parameters we expect it to charge.
+type TrampolinePayment struct {
+	// Node is the trampoline node. The route to the trampoline node is
+	// found through path finding, while the trampoline node finds the
+	// route to the recipient.
+	Node route.Vertex
+
+	// BaseFee is the base fee of the trampoline node.
+	BaseFee uint32
+
+	// ProportionalFee is the proportional fee of the trampoline node in
+	// millionths.
+	ProportionalFee uint32
+
+	// CltvExpiryDelta is the CLTV delta that the trampoline node requires
+	// to reach the recipient.
+	CltvExpiryDelta uint16
+
+	// paymentAddr is the payment address of the outer onion, which the
+	// trampoline node uses to identify the payment.
+	paymentAddr [32]byte
+}
+
+// fee returns the fee that the trampoline node charges for delivering the
+// given amount to the recipient.
+func (t *TrampolinePayment) fee(amt lnwire.MilliBronees) lnwire.MilliBronees {
+	proportional := (uint64(amt)*uint64(t.ProportionalFee) + 999_999) /
+		1_000_000
+
+	return lnwire.MilliBronees(uint64(t.BaseFee) + proportional)
+}
+
+// trampolineOnionPlaceholder returns a record set of the size of a trampoline
+// onion. It is passed to path finding as the custom records of the trampoline
+// node, so that the route leaves enough room for the trampoline onion in the
+// outer onion.
+func trampolineOnionPlaceholder() record.CustomSet {
+	return record.CustomSet{
+		uint64(record.TrampolineOnionType): make(
+			[]byte, hop.TrampolineOnionSize,
+		),
+	}
+}
+
+// extendRoute adds the trampoline onion to the route to the trampoline node
+// and appends the recipient as the final hop of the route. The recipient
+// isn't part of the outer onion, but is kept in the route so that the amount
+// and fees of the route are reported like for any other payment.
+func (t *TrampolinePayment) extendRoute(rt *route.Route,
+	payment *LightningPayment, amt lnwire.MilliBronees,
+	finalExpiry uint32) error {
+
+	trampolineNode, err := bronec.ParsePubKey(t.Node[:], bronec.S256())
+	if err != nil {
+		return err
+	}
+	recipient, err := bronec.ParsePubKey(
+		payment.Target[:], bronec.S256(),
+	)
+	if err != nil {
+		return err
+	}
+
+	mpp := record.NewMPP(payment.Amount, *payment.PaymentAddr)
+	trampolinePayload := &hop.TrampolinePayload{
+		AmtToForward:   amt,
+		OutgoingCltv:   finalExpiry,
+		OutgoingNodeID: recipient,
+	}
+	hops := []hop.TrampolineHop{{
+		NodePub: trampolineNode,
+		Payload: trampolinePayload,
+	}}
+
+	// Recipients that support trampoline routing receive their own layer
+	// of the trampoline onion. For all other recipients, the trampoline
+	// node pays the invoice with a regular payment, so it needs to know
+	// the invoice fields that are relevant for path finding.
+	destFeatures := payment.DestFeatures
+	if destFeatures != nil &&
+		destFeatures.HasFeature(lnwire.TrampolineRoutingOptional) {
+
+		hops = append(hops, hop.TrampolineHop{
+			NodePub: recipient,
+			Payload: &hop.TrampolinePayload{
+				AmtToForward: amt,
+				OutgoingCltv: finalExpiry,
+				MPP:          mpp,
+			},
+		})
+	} else {
+		trampolinePayload.MPP = mpp
+		trampolinePayload.InvoiceRoutingInfo = payment.RouteHints
+		if destFeatures != nil {
+			trampolinePayload.InvoiceFeatures =
+				destFeatures.RawFeatureVector
+		}
+	}
+
+	sessionKey, err := bronec.NewPrivateKey(bronec.S256())
+	if err != nil {
+		return err
+	}
+
+	onion, err := hop.NewTrampolineOnion(sessionKey, hops)
+	if err != nil {
+		return err
+	}
+
+	trampolineHop := rt.Hops[len(rt.Hops)-1]
+	trampolineHop.TrampolineOnion = onion
+
+	rt.Hops = append(rt.Hops, &route.Hop{
+		PubKeyBytes:      payment.Target,
+		AmtToForward:     amt,
+		OutgoingTimeLock: finalExpiry,
+		MPP:              mpp,
+	})
+
+	return nil
+}
+
+// trampolineNode selects the trampoline node that the payment is sent
+// through. A trampoline node that was set on the payment takes precedence.
+// Otherwise, if trampoline payments are enabled, the trampoline node that the
+// recipient announces in its invoice is used, falling back to the configured
+// trampoline node. False is returned if the payment should not be sent
+// through a trampoline node.
+func (r *ChannelRouter) trampolineNode(
+	payment *LightningPayment) (*TrampolinePayment, bool) {
+
+	cfg := r.cfg.Trampoline
+	if payment.TrampolineNode != nil {
+		return &TrampolinePayment{
+			Node:            *payment.TrampolineNode,
+			BaseFee:         cfg.BaseFee,
+			ProportionalFee: cfg.ProportionalFee,
+			CltvExpiryDelta: cfg.CltvExpiryDelta,
+		}, true
+	}
+
+	if !cfg.Pay {
+		return nil, false
+	}
+
+	// Recipients that support trampoline routing announce their
+	// trampoline nodes as single hop route hints, which also tell us the
+	// relay parameters of the trampoline node.
+	destFeatures := payment.DestFeatures
+	if destFeatures != nil &&
+		destFeatures.HasFeature(lnwire.TrampolineRoutingOptional) {
+
+		for _, hints := range payment.RouteHints {
+			if len(hints) != 1 {
+				continue
+			}
+
+			hint := hints[0]
+			return &TrampolinePayment{
+				Node:            route.NewVertex(hint.NodeID),
+				BaseFee:         hint.FeeBaseMSat,
+				ProportionalFee: hint.FeeProportionalMillionths,
+				CltvExpiryDelta: hint.CLTVExpiryDelta,
+			}, true
+		}
+	}
+
+	if cfg.Node == nil {
+		return nil, false
+	}
+
+	return &TrampolinePayment{
+		Node:            *cfg.Node,
+		BaseFee:         cfg.BaseFee,
+		ProportionalFee: cfg.ProportionalFee,
+		CltvExpiryDelta: cfg.CltvExpiryDelta,
+	}, true
+}
+
+// setTrampoline decides whether the payment is sent through a trampoline
+// node and prepares the payment accordingly. Payments through a trampoline
+// node are sent in a single shard.
+func (r *ChannelRouter) setTrampoline(payment *LightningPayment) error {
+	// Payments that forward a trampoline onion are already addressed to
+	// the next trampoline node, which identifies the payment by the
+	// payment address of the outer onion.
+	if payment.TrampolineOnion != nil {
+		switch {
+		case payment.TrampolineNode != nil,
+			payment.BlindedPayment != nil, payment.amp != nil,
+			len(payment.DestCustomRecords) > 0:
+
+			return ErrTrampolineUnsupported
+
+		case payment.PaymentAddr == nil:
+			return ErrTrampolineNoPaymentAddr
+		}
+
+		return nil
+	}
+
+	trampoline, ok := r.trampolineNode(payment)
+	if !ok {
+		return nil
+	}
+
+	// Payments to ourselves and to the trampoline node itself don't need
+	// a trampoline node.
+	selfNode := r.selfNode.PubKeyBytes
+	if payment.Target == selfNode || payment.Target == trampoline.Node {
+		return nil
+	}
+
+	// Payments that we can't hand over to a trampoline node are sent as
+	// regular payments, unless the trampoline node was explicitly
+	// requested. The route to the recipient is found by the trampoline
+	// node, so we can't restrict its last hop either.
+	explicit := payment.TrampolineNode != nil
+	unsupported := payment.BlindedPayment != nil || payment.amp != nil ||
+		len(payment.DestCustomRecords) > 0 ||
+		hasLastHopRestrictions(payment)
+	switch {
+	case unsupported && explicit:
+		return ErrTrampolineUnsupported
+
+	case unsupported:
+		return nil
+
+	case payment.PaymentAddr == nil && explicit:
+		return ErrTrampolineNoPaymentAddr
+
+	case payment.PaymentAddr == nil:
+		return nil
+	}
+
+	if _, err := rand.Read(trampoline.paymentAddr[:]); err != nil {
+		return err
+	}
+
+	log.Debugf("Sending payment %x through trampoline node %v",
+		payment.Identifier(), trampoline.Node)
+
+	payment.trampoline = trampoline
+	payment.MaxParts = 1
+	payment.MaxShardAmt = nil
+
+	return nil
+}
+
+// hasLastHopRestrictions returns whether the payment restricts the last hop of
+// the route to the recipient.
+func hasLastHopRestrictions(payment *LightningPayment) bool {
+	return len(payment.LastHops) > 0 ||
+		len(payment.IncomingChannelIDs) > 0
+}
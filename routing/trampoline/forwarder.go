@@ -0,0 +1,677 @@
+package trampoline
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/brronsuite/broln/channeldb"
+	"github.com/brronsuite/broln/clock"
+	"github.com/brronsuite/broln/htlcswitch"
+	"github.com/brronsuite/broln/htlcswitch/hop"
+	"github.com/brronsuite/broln/keychain"
+	"github.com/brronsuite/broln/lntypes"
+	"github.com/brronsuite/broln/lnwire"
+	"github.com/brronsuite/broln/record"
+	"github.com/brronsuite/broln/routing"
+	"github.com/brronsuite/broln/routing/route"
+)
+
+const (
+	// DefaultMppTimeout is the default time we wait for all htlcs of a
+	// trampoline payment to arrive.
+	DefaultMppTimeout = time.Minute
+
+	// DefaultPaymentTimeout is the default time we spend trying to reach
+	// the next trampoline node or the final recipient.
+	DefaultPaymentTimeout = time.Minute
+
+	// maxOutgoingParts is the maximum number of htlcs that the outgoing
+	// payment is split into.
+	maxOutgoingParts = 16
+)
+
+// Config holds the parameters and the dependencies of the trampoline
+// forwarder.
+type Config struct {
+	// NodeKey is used to peel our layer off the trampoline onions we
+	// receive.
+	NodeKey keychain.SingleKeyECDH
+
+	// SendPayment starts the outgoing payment to the next trampoline node
+	// or the final recipient, without waiting for its result.
+	SendPayment func(*routing.LightningPayment) error
+
+	// SubscribePayment subscribes to the updates of the outgoing payment
+	// with the given hash. The first update holds the current state of
+	// the payment. The returned function cancels the subscription. If
+	// there is no payment with the given hash,
+	// channeldb.ErrPaymentNotInitiated is returned.
+	SubscribePayment func(lntypes.Hash) (<-chan interface{}, func(),
+		error)
+
+	// BestHeight returns the current block height.
+	BestHeight func() uint32
+
+	// BaseFee is the base fee that we require for a trampoline forward.
+	// The fee covers the routing fees of the outgoing payment, and
+	// whatever isn't spent on routing is what we earn.
+	BaseFee lnwire.MilliBronees
+
+	// FeeRate is the fee rate in parts per million that we charge on top
+	// of BaseFee for the amount of a trampoline forward.
+	FeeRate uint32
+
+	// CltvDelta is the minimum difference between the expiry of the
+	// incoming htlcs and the outgoing cltv of a trampoline forward.
+	CltvDelta uint16
+
+	// FinalCltvRejectDelta is the number of blocks before the expiry of
+	// the incoming htlcs that the outgoing payment must have been
+	// resolved, so that we can still claim the incoming htlcs.
+	FinalCltvRejectDelta uint32
+
+	// MppTimeout is the time we wait for all htlcs of a trampoline
+	// payment to arrive.
+	MppTimeout time.Duration
+
+	// PaymentTimeout is the time we spend trying to reach the next
+	// trampoline node or the final recipient.
+	PaymentTimeout time.Duration
+
+	// Clock is used to time out incomplete htlc sets.
+	Clock clock.Clock
+}
+
+// setKey identifies the set of htlcs that together pay for a trampoline
+// forward.
+type setKey struct {
+	hash        lntypes.Hash
+	paymentAddr [32]byte
+}
+
+// htlcSet is a set of htlcs that together pay for a trampoline forward.
+type htlcSet struct {
+	// htlcs are the htlcs that are part of the set.
+	htlcs []*htlcswitch.TrampolineHtlc
+
+	// onion is the trampoline onion that all htlcs of the set carry.
+	onion []byte
+
+	// payload is our payload of the trampoline onion.
+	payload *hop.TrampolinePayload
+
+	// next is the trampoline onion for the next trampoline node. It is
+	// nil if the next node is the final recipient that doesn't support
+	// trampoline routing.
+	next []byte
+
+	// total is the total amount of the set, as announced in the mpp
+	// record of the htlcs.
+	total lnwire.MilliBronees
+
+	// received is the sum of the amounts of the htlcs in the set.
+	received lnwire.MilliBronees
+
+	// complete is closed once the full amount of the set has arrived.
+	complete chan struct{}
+
+	// forwarding is set once the set is complete and the outgoing payment
+	// is started.
+	forwarding bool
+}
+
+// minIncomingExpiry returns the earliest expiry of the htlcs in the set.
+func (s *htlcSet) minIncomingExpiry() uint32 {
+	expiry := uint32(math.MaxUint32)
+	for _, htlc := range s.htlcs {
+		if pkt := htlc.Packet(); pkt.IncomingExpiry < expiry {
+			expiry = pkt.IncomingExpiry
+		}
+	}
+
+	return expiry
+}
+
+// Forwarder accepts htlcs that carry a trampoline onion. Once all htlcs of a
+// trampoline payment have arrived and satisfy our fee and cltv policy, it pays
+// the next trampoline node or the final recipient and resolves the incoming
+// htlcs according to the outcome of that payment.
+type Forwarder struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg *Config
+
+	// sets are the trampoline payments that we are currently collecting
+	// or forwarding.
+	sets map[setKey]*htlcSet
+	mu   sync.Mutex
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+// New creates a new trampoline forwarder.
+func New(cfg *Config) (*Forwarder, error) {
+	if cfg.NodeKey == nil {
+		return nil, errors.New("node key required")
+	}
+
+	return &Forwarder{
+		cfg:  cfg,
+		sets: make(map[setKey]*htlcSet),
+		quit: make(chan struct{}),
+	}, nil
+}
+
+// Start starts the trampoline forwarder.
+func (f *Forwarder) Start() error {
+	f.started.Do(func() {
+		log.Info("Trampoline forwarder starting")
+	})
+
+	return nil
+}
+
+// Stop signals the trampoline forwarder for a graceful shutdown. Htlcs that
+// are held by the forwarder stay unresolved, and will be offered again once
+// their links are restored.
+func (f *Forwarder) Stop() error {
+	f.stopped.Do(func() {
+		log.Info("Trampoline forwarder shutting down")
+
+		close(f.quit)
+		f.wg.Wait()
+	})
+
+	return nil
+}
+
+// fee returns the fee that we charge for forwarding the given amount.
+func (f *Forwarder) fee(amt lnwire.MilliBronees) lnwire.MilliBronees {
+	proportional := (uint64(amt)*uint64(f.cfg.FeeRate) + 999_999) /
+		1_000_000
+
+	return f.cfg.BaseFee + lnwire.MilliBronees(proportional)
+}
+
+// HandleHtlc accepts an htlc that carries a trampoline onion. It returns true
+// if the htlc is held by the forwarder, which is always the case as htlcs
+// that can't be forwarded are failed right away.
+//
+// NOTE: This is called from the link's goroutine, so the outgoing payment
+// is started in a separate goroutine.
+func (f *Forwarder) HandleHtlc(htlc *htlcswitch.TrampolineHtlc) bool {
+	pkt := htlc.Packet()
+
+	// Without an mpp record, we can't tell which htlcs belong to the same
+	// trampoline payment.
+	if htlc.MPP == nil {
+		log.Debugf("Trampoline htlc %v without mpp record",
+			pkt.IncomingCircuit)
+
+		f.fail(htlc, lnwire.NewInvalidOnionPayload(
+			uint64(record.MPPOnionType), 0,
+		))
+		return true
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := setKey{
+		hash:        pkt.Hash,
+		paymentAddr: htlc.MPP.PaymentAddr(),
+	}
+
+	set, ok := f.sets[key]
+	if !ok {
+		var err error
+		set, err = f.newSet(htlc)
+		if err != nil {
+			log.Debugf("Unable to accept trampoline htlc %v: %v",
+				pkt.IncomingCircuit, err)
+
+			f.fail(htlc, failureMessage(err))
+			return true
+		}
+
+		f.sets[key] = set
+
+		f.wg.Add(1)
+		go f.waitForSet(key, set)
+	}
+
+	// All htlcs of a trampoline payment must agree on the payment they
+	// are part of.
+	if set.total != htlc.MPP.TotalMsat() ||
+		!bytes.Equal(set.onion, htlc.TrampolineOnion) {
+
+		log.Debugf("Trampoline htlc %v doesn't match the set of "+
+			"payment %v", pkt.IncomingCircuit, pkt.Hash)
+
+		f.fail(htlc, lnwire.NewFailIncorrectDetails(
+			pkt.IncomingAmount, f.cfg.BestHeight(),
+		))
+		return true
+	}
+
+	set.htlcs = append(set.htlcs, htlc)
+	set.received += pkt.IncomingAmount
+
+	log.Debugf("Accepted trampoline htlc %v of payment %v: received "+
+		"%v of %v", pkt.IncomingCircuit, pkt.Hash, set.received,
+		set.total)
+
+	if set.received >= set.total && !set.forwarding {
+		set.forwarding = true
+		close(set.complete)
+	}
+
+	return true
+}
+
+// newSet creates the set of htlcs for the trampoline payment that the given
+// htlc is the first part of.
+func (f *Forwarder) newSet(htlc *htlcswitch.TrampolineHtlc) (*htlcSet,
+	error) {
+
+	payload, next, err := hop.ProcessTrampolineOnion(
+		f.cfg.NodeKey, htlc.TrampolineOnion,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// We only relay trampoline payments. Payments to us are made without
+	// a trampoline onion.
+	if payload.IsFinal() {
+		return nil, errFinalPayload
+	}
+
+	// A recipient that doesn't support trampoline routing is paid with a
+	// regular payment, which requires its payment address.
+	if next == nil && payload.MPP == nil {
+		return nil, hop.ErrInvalidPayload{
+			Type:      record.MPPOnionType,
+			Violation: hop.OmittedViolation,
+		}
+	}
+
+	return &htlcSet{
+		onion:    htlc.TrampolineOnion,
+		payload:  payload,
+		next:     next,
+		total:    htlc.MPP.TotalMsat(),
+		complete: make(chan struct{}),
+	}, nil
+}
+
+// waitForSet waits until all htlcs of the set have arrived and forwards the
+// payment. If the set isn't complete in time, its htlcs are failed.
+//
+// If we already started the outgoing payment, the htlcs are replayed, for
+// example after a restart. We then only wait for the result of the outgoing
+// payment, as the htlcs must not be failed while it may still succeed, even if
+// they no longer satisfy our policy or not all of them are replayed.
+func (f *Forwarder) waitForSet(key setKey, set *htlcSet) {
+	defer f.wg.Done()
+
+	payment, updates, cancel, err := f.subscribeExisting(key.hash)
+	if err != nil {
+		// Without knowing whether the outgoing payment was started,
+		// we can't safely fail the htlcs, so we keep holding them.
+		log.Errorf("Unable to look up trampoline payment %v: %v",
+			key.hash, err)
+
+		f.mu.Lock()
+		set.forwarding = true
+		f.mu.Unlock()
+
+		return
+	}
+	if payment != nil {
+		defer cancel()
+
+		log.Debugf("Trampoline payment %v already sent, waiting for "+
+			"its result", key.hash)
+
+		f.mu.Lock()
+		set.forwarding = true
+		f.mu.Unlock()
+
+		if !f.handlePaymentUpdate(key, set, payment) {
+			f.waitForPayment(key, set, updates)
+		}
+		return
+	}
+
+	select {
+	case <-set.complete:
+		f.forward(key, set)
+
+	case <-f.cfg.Clock.TickAfter(f.cfg.MppTimeout):
+		f.mu.Lock()
+		if !set.forwarding {
+			log.Debugf("Trampoline payment %v timed out with %v "+
+				"of %v", key.hash, set.received, set.total)
+
+			delete(f.sets, key)
+			for _, htlc := range set.htlcs {
+				f.fail(htlc, &lnwire.FailMPPTimeout{})
+			}
+			f.mu.Unlock()
+
+			return
+		}
+		f.mu.Unlock()
+
+		// The set was completed just before the timeout.
+		f.forward(key, set)
+
+	case <-f.quit:
+	}
+}
+
+// forward checks the complete set of htlcs against our policy, pays the next
+// trampoline node or the final recipient and resolves the htlcs accordingly.
+func (f *Forwarder) forward(key setKey, set *htlcSet) {
+	f.mu.Lock()
+	payload := set.payload
+	received := set.received
+	minExpiry := set.minIncomingExpiry()
+	f.mu.Unlock()
+
+	height := f.cfg.BestHeight()
+	payment, failure := f.newPayment(
+		key.hash, set, received, minExpiry, height,
+	)
+	if failure != nil {
+		log.Debugf("Rejecting trampoline payment %v: %v", key.hash,
+			failure)
+
+		f.resolve(key, set, nil, failure)
+		return
+	}
+
+	log.Debugf("Forwarding trampoline payment %v of %v to %v", key.hash,
+		payload.AmtToForward, payment.Target)
+
+	err := f.cfg.SendPayment(payment)
+	switch {
+	// The payment was already started before, for example before a
+	// restart. We wait for its result like for a new payment.
+	case err == channeldb.ErrPaymentInFlight,
+		err == channeldb.ErrAlreadyPaid:
+
+		log.Debugf("Trampoline payment %v already sent", key.hash)
+
+	case err != nil:
+		log.Errorf("Unable to forward trampoline payment %v: %v",
+			key.hash, err)
+
+		f.resolve(key, set, nil, &lnwire.FailTemporaryNodeFailure{})
+		return
+	}
+
+	// The outgoing payment exists now, so the htlcs are held until its
+	// result is known, even if we fail to subscribe to it. They are then
+	// resolved once they are replayed after a restart.
+	updates, cancel, err := f.cfg.SubscribePayment(key.hash)
+	if err != nil {
+		log.Errorf("Unable to subscribe to trampoline payment %v: %v",
+			key.hash, err)
+
+		return
+	}
+	defer cancel()
+
+	f.waitForPayment(key, set, updates)
+}
+
+// subscribeExisting subscribes to the outgoing payment with the given hash if
+// we already started it and it hasn't failed. It returns the current state of
+// the payment along with the channel that delivers its updates. A nil payment
+// is returned if there is no such payment.
+func (f *Forwarder) subscribeExisting(hash lntypes.Hash) (*channeldb.MPPayment,
+	<-chan interface{}, func(), error) {
+
+	updates, cancel, err := f.cfg.SubscribePayment(hash)
+	switch {
+	case err == channeldb.ErrPaymentNotInitiated:
+		return nil, nil, nil, nil
+
+	case err != nil:
+		return nil, nil, nil, err
+	}
+
+	// The first update holds the current state of the payment. A failed
+	// payment has no htlcs in flight and is started anew when we forward
+	// the htlcs.
+	select {
+	case update, ok := <-updates:
+		if !ok {
+			cancel()
+			return nil, nil, nil, errors.New("payment " +
+				"subscription closed")
+		}
+
+		payment := update.(*channeldb.MPPayment)
+		if payment.Status == channeldb.StatusFailed {
+			cancel()
+			return nil, nil, nil, nil
+		}
+
+		return payment, updates, cancel, nil
+
+	case <-f.quit:
+		cancel()
+		return nil, nil, nil, errors.New("forwarder shutting down")
+	}
+}
+
+// waitForPayment waits for the result of the outgoing payment and resolves the
+// htlcs of the set accordingly.
+func (f *Forwarder) waitForPayment(key setKey, set *htlcSet,
+	updates <-chan interface{}) {
+
+	for {
+		select {
+		case update, ok := <-updates:
+			if !ok {
+				return
+			}
+
+			payment := update.(*channeldb.MPPayment)
+			if f.handlePaymentUpdate(key, set, payment) {
+				return
+			}
+
+		case <-f.quit:
+			return
+		}
+	}
+}
+
+// handlePaymentUpdate resolves the htlcs of the set if the outgoing payment
+// has a final result, and returns true if it does. The htlcs are only failed
+// once the payment has failed without any htlcs in flight.
+func (f *Forwarder) handlePaymentUpdate(key setKey, set *htlcSet,
+	payment *channeldb.MPPayment) bool {
+
+	settle, reason := payment.TerminalInfo()
+	switch {
+	case settle != nil:
+		log.Debugf("Trampoline payment %v succeeded", key.hash)
+
+		f.resolve(key, set, &settle.Preimage, nil)
+		return true
+
+	case reason != nil && payment.Status == channeldb.StatusFailed:
+		log.Debugf("Trampoline payment %v failed: %v", key.hash,
+			*reason)
+
+		f.resolve(key, set, nil, paymentFailure(*reason))
+		return true
+	}
+
+	return false
+}
+
+// newPayment checks the trampoline payment against our fee and cltv policy
+// and returns the outgoing payment. If the policy isn't satisfied, the
+// failure message to fail the incoming htlcs with is returned.
+func (f *Forwarder) newPayment(hash lntypes.Hash, set *htlcSet,
+	received lnwire.MilliBronees, minExpiry,
+	height uint32) (*routing.LightningPayment, lnwire.FailureMessage) {
+
+	payload := set.payload
+	amt := payload.AmtToForward
+
+	// The incoming htlcs must pay our fee on top of the amount to
+	// forward. The whole fee is the budget for the routing fees of the
+	// outgoing payment.
+	if received < amt+f.fee(amt) {
+		return nil, &lnwire.FailTrampolineFeeInsufficient{}
+	}
+
+	// The incoming htlcs must leave us enough time to reach the next node
+	// and to resolve the incoming htlcs once the outgoing payment is
+	// resolved.
+	outgoingCltv := payload.OutgoingCltv
+	finalCltvDelta := int64(outgoingCltv) - int64(height) -
+		int64(routing.BlockPadding)
+	switch {
+	case minExpiry < outgoingCltv+uint32(f.cfg.CltvDelta):
+		return nil, &lnwire.FailTrampolineExpiryTooSoon{}
+
+	case finalCltvDelta <= 0 || finalCltvDelta > math.MaxUint16:
+		return nil, &lnwire.FailTrampolineExpiryTooSoon{}
+	}
+
+	target := route.NewVertex(payload.OutgoingNodeID)
+	payment := &routing.LightningPayment{
+		Target:            target,
+		Amount:            amt,
+		FeeLimit:          received - amt,
+		CltvLimit:         minExpiry - f.cfg.FinalCltvRejectDelta - height,
+		FinalCLTVDelta:    uint16(finalCltvDelta),
+		PayAttemptTimeout: f.cfg.PaymentTimeout,
+		MaxParts:          maxOutgoingParts,
+	}
+	if err := payment.SetPaymentHash(hash); err != nil {
+		return nil, &lnwire.FailTemporaryNodeFailure{}
+	}
+
+	// The next trampoline node receives its layer of the trampoline onion
+	// and identifies our payment by a payment address of our own.
+	if set.next != nil {
+		var paymentAddr [32]byte
+		if _, err := rand.Read(paymentAddr[:]); err != nil {
+			return nil, &lnwire.FailTemporaryNodeFailure{}
+		}
+
+		payment.PaymentAddr = &paymentAddr
+		payment.TrampolineOnion = set.next
+
+		return payment, nil
+	}
+
+	// A recipient that doesn't support trampoline routing is paid like
+	// the invoice that the sender gave us the details of.
+	if payload.MPP.TotalMsat() != amt {
+		return nil, lnwire.NewInvalidOnionPayload(
+			uint64(record.MPPOnionType), 0,
+		)
+	}
+
+	paymentAddr := payload.MPP.PaymentAddr()
+	payment.PaymentAddr = &paymentAddr
+	payment.RouteHints = payload.InvoiceRoutingInfo
+	if payload.InvoiceFeatures != nil {
+		payment.DestFeatures = lnwire.NewFeatureVector(
+			payload.InvoiceFeatures, lnwire.Features,
+		)
+	}
+
+	return payment, nil
+}
+
+// resolve settles the htlcs of the set if a preimage is given, and fails them
+// with the given failure message otherwise.
+func (f *Forwarder) resolve(key setKey, set *htlcSet,
+	preimage *lntypes.Preimage, failure lnwire.FailureMessage) {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.sets, key)
+
+	for _, htlc := range set.htlcs {
+		if preimage == nil {
+			f.fail(htlc, failure)
+			continue
+		}
+
+		if err := htlc.Settle(*preimage); err != nil {
+			log.Errorf("Unable to settle trampoline htlc %v: %v",
+				htlc.Packet().IncomingCircuit, err)
+		}
+	}
+}
+
+// fail fails the htlc with the given failure message. Incorrect payment
+// details are reported with the amount of the htlc and the height at which it
+// was accepted, regardless of the values in the passed failure message.
+func (f *Forwarder) fail(htlc *htlcswitch.TrampolineHtlc,
+	failure lnwire.FailureMessage) {
+
+	var err error
+	if _, ok := failure.(*lnwire.FailIncorrectDetails); ok {
+		err = htlc.Fail()
+	} else {
+		err = htlc.FailWithMessage(failure)
+	}
+	if err != nil {
+		log.Errorf("Unable to fail trampoline htlc %v: %v",
+			htlc.Packet().IncomingCircuit, err)
+	}
+}
+
+// errFinalPayload is returned if we are the final node of a trampoline onion.
+var errFinalPayload = errors.New("final trampoline payload")
+
+// failureMessage returns the failure message to fail an htlc with whose
+// trampoline onion can't be processed.
+func failureMessage(err error) lnwire.FailureMessage {
+	var payloadErr hop.ErrInvalidPayload
+	switch {
+	case errors.As(err, &payloadErr):
+		return lnwire.NewInvalidOnionPayload(uint64(payloadErr.Type), 0)
+
+	case err == errFinalPayload:
+		return lnwire.NewFailIncorrectDetails(0, 0)
+
+	default:
+		return lnwire.NewInvalidOnionPayload(
+			uint64(record.TrampolineOnionType), 0,
+		)
+	}
+}
+
+// paymentFailure returns the failure message to fail the incoming htlcs with
+// if the outgoing payment failed for the given reason.
+func paymentFailure(reason channeldb.FailureReason) lnwire.FailureMessage {
+	switch reason {
+	// The recipient rejected the payment, which we relay to the sender.
+	case channeldb.FailureReasonPaymentDetails:
+		return lnwire.NewFailIncorrectDetails(0, 0)
+
+	default:
+		return &lnwire.FailTemporaryNodeFailure{}
+	}
+}
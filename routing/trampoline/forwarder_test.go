@@ -0,0 +1,462 @@
+package trampoline
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/brronsuite/broln/channeldb"
+	"github.com/brronsuite/broln/clock"
+	"github.com/brronsuite/broln/htlcswitch"
+	"github.com/brronsuite/broln/htlcswitch/hop"
+	"github.com/brronsuite/broln/keychain"
+	"github.com/brronsuite/broln/lntypes"
+	"github.com/brronsuite/broln/lnwire"
+	"github.com/brronsuite/broln/record"
+	"github.com/brronsuite/broln/routing"
+	"github.com/brronsuite/broln/routing/route"
+	"github.com/brronsuite/brond/bronec"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testHeight     = 100
+	testTimeout    = 5 * time.Second
+	testAmt        = 100_000
+	testCltv       = testHeight + 200
+	testCltvDelta  = 144
+	testRejectCltv = 19
+)
+
+var (
+	testPreimage = lntypes.Preimage{1}
+	testHash     = testPreimage.Hash()
+	testTime     = time.Unix(1_000_000, 0)
+)
+
+// mockHtlc is a mock implementation of the htlcswitch.InterceptedForward
+// interface that records the resolution of the htlc.
+type mockHtlc struct {
+	packet      htlcswitch.InterceptedPacket
+	resolutions chan interface{}
+}
+
+func (m *mockHtlc) Packet() htlcswitch.InterceptedPacket {
+	return m.packet
+}
+
+func (m *mockHtlc) Resume() error {
+	m.resolutions <- nil
+	return nil
+}
+
+func (m *mockHtlc) Settle(preimage lntypes.Preimage) error {
+	m.resolutions <- preimage
+	return nil
+}
+
+func (m *mockHtlc) Fail() error {
+	m.resolutions <- lnwire.NewFailIncorrectDetails(
+		m.packet.IncomingAmount, testHeight,
+	)
+	return nil
+}
+
+func (m *mockHtlc) FailWithMessage(failure lnwire.FailureMessage) error {
+	m.resolutions <- failure
+	return nil
+}
+
+func (m *mockHtlc) FailWithReason(reason lnwire.OpaqueReason) error {
+	m.resolutions <- reason
+	return nil
+}
+
+// assertResolution asserts that the htlc is resolved with the given
+// resolution.
+func (m *mockHtlc) assertResolution(t *testing.T, expected interface{}) {
+	t.Helper()
+
+	select {
+	case resolution := <-m.resolutions:
+		require.Equal(t, expected, resolution)
+
+	case <-time.After(testTimeout):
+		t.Fatal("htlc not resolved")
+	}
+}
+
+// assertUnresolved asserts that the htlc is still held.
+func (m *mockHtlc) assertUnresolved(t *testing.T) {
+	t.Helper()
+
+	select {
+	case resolution := <-m.resolutions:
+		t.Fatalf("unexpected resolution: %v", resolution)
+
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+type forwarderTestContext struct {
+	t         *testing.T
+	forwarder *Forwarder
+	clock     *clock.TestClock
+	tick      chan time.Duration
+
+	nodeKey *bronec.PrivateKey
+	nextKey *bronec.PrivateKey
+
+	payments chan *routing.LightningPayment
+	updates  chan interface{}
+
+	// sent is true once the outgoing payment exists.
+	sent   bool
+	sentMu sync.Mutex
+}
+
+// setSent marks the outgoing payment as existing or not.
+func (c *forwarderTestContext) setSent(sent bool) {
+	c.sentMu.Lock()
+	defer c.sentMu.Unlock()
+
+	c.sent = sent
+}
+
+func newForwarderTestContext(t *testing.T) *forwarderTestContext {
+	nodeKey, err := bronec.NewPrivateKey(bronec.S256())
+	require.NoError(t, err)
+	nextKey, err := bronec.NewPrivateKey(bronec.S256())
+	require.NoError(t, err)
+
+	ctx := &forwarderTestContext{
+		t:        t,
+		tick:     make(chan time.Duration, 10),
+		nodeKey:  nodeKey,
+		nextKey:  nextKey,
+		payments: make(chan *routing.LightningPayment, 1),
+		updates:  make(chan interface{}, 1),
+	}
+	ctx.clock = clock.NewTestClockWithTickSignal(testTime, ctx.tick)
+
+	ctx.forwarder, err = New(&Config{
+		NodeKey: &keychain.PrivKeyECDH{PrivKey: nodeKey},
+		SendPayment: func(payment *routing.LightningPayment) error {
+			ctx.setSent(true)
+			ctx.payments <- payment
+			return nil
+		},
+		SubscribePayment: func(hash lntypes.Hash) (<-chan interface{},
+			func(), error) {
+
+			require.Equal(t, testHash, hash)
+
+			ctx.sentMu.Lock()
+			defer ctx.sentMu.Unlock()
+
+			if !ctx.sent {
+				return nil, nil, channeldb.ErrPaymentNotInitiated
+			}
+
+			return ctx.updates, func() {}, nil
+		},
+		BestHeight: func() uint32 {
+			return testHeight
+		},
+		BaseFee:              1000,
+		FeeRate:              1000,
+		CltvDelta:            testCltvDelta,
+		FinalCltvRejectDelta: testRejectCltv,
+		MppTimeout:           time.Minute,
+		PaymentTimeout:       time.Minute,
+		Clock:                ctx.clock,
+	})
+	require.NoError(t, err)
+	require.NoError(t, ctx.forwarder.Start())
+
+	t.Cleanup(func() {
+		require.NoError(t, ctx.forwarder.Stop())
+	})
+
+	return ctx
+}
+
+// onion creates a trampoline onion that asks us to forward the test amount to
+// the next node. If the next node is a trampoline node, it receives its own
+// layer of the onion.
+func (c *forwarderTestContext) onion(nextTrampoline bool) []byte {
+	payload := &hop.TrampolinePayload{
+		AmtToForward:   testAmt,
+		OutgoingCltv:   testCltv,
+		OutgoingNodeID: c.nextKey.PubKey(),
+	}
+	hops := []hop.TrampolineHop{{
+		NodePub: c.nodeKey.PubKey(),
+		Payload: payload,
+	}}
+
+	if nextTrampoline {
+		hops = append(hops, hop.TrampolineHop{
+			NodePub: c.nextKey.PubKey(),
+			Payload: &hop.TrampolinePayload{
+				AmtToForward: testAmt,
+				OutgoingCltv: testCltv,
+				MPP:          record.NewMPP(testAmt, [32]byte{3}),
+			},
+		})
+	} else {
+		payload.MPP = record.NewMPP(testAmt, [32]byte{3})
+		payload.InvoiceFeatures = lnwire.NewRawFeatureVector(
+			lnwire.TLVOnionPayloadOptional,
+			lnwire.PaymentAddrOptional,
+		)
+	}
+
+	sessionKey, err := bronec.NewPrivateKey(bronec.S256())
+	require.NoError(c.t, err)
+
+	onion, err := hop.NewTrampolineOnion(sessionKey, hops)
+	require.NoError(c.t, err)
+
+	return onion
+}
+
+// htlc offers an htlc with the given amount and onion to the forwarder. The
+// htlc is part of the trampoline payment identified by the payment address.
+func (c *forwarderTestContext) htlc(id uint64, paymentAddr byte, amt,
+	total lnwire.MilliBronees, expiry uint32, onion []byte) *mockHtlc {
+
+	htlc := &mockHtlc{
+		packet: htlcswitch.InterceptedPacket{
+			IncomingCircuit: channeldb.CircuitKey{HtlcID: id},
+			Hash:            testHash,
+			IncomingAmount:  amt,
+			IncomingExpiry:  expiry,
+		},
+		resolutions: make(chan interface{}, 1),
+	}
+
+	held := c.forwarder.HandleHtlc(&htlcswitch.TrampolineHtlc{
+		InterceptedForward: htlc,
+		TrampolineOnion:    onion,
+		MPP:                record.NewMPP(total, [32]byte{paymentAddr}),
+	})
+	require.True(c.t, held)
+
+	return htlc
+}
+
+// assertPayment asserts that the forwarder starts an outgoing payment.
+func (c *forwarderTestContext) assertPayment() *routing.LightningPayment {
+	select {
+	case payment := <-c.payments:
+		return payment
+
+	case <-time.After(testTimeout):
+		c.t.Fatal("payment not sent")
+		return nil
+	}
+}
+
+// TestForwarderSettle tests that the htlcs of a trampoline payment are
+// aggregated, forwarded to the next trampoline node and settled once the
+// outgoing payment succeeds.
+func TestForwarderSettle(t *testing.T) {
+	ctx := newForwarderTestContext(t)
+	onion := ctx.onion(true)
+
+	// Our fee is 1000 msat plus 0.1% of the amount. We receive 500 msat
+	// more, all of which can be spent on routing fees.
+	const total = testAmt + 1100 + 500
+
+	expiry := uint32(testCltv + testCltvDelta + 10)
+	htlc1 := ctx.htlc(1, 1, total/2, total, expiry+1, onion)
+	htlc1.assertUnresolved(t)
+
+	htlc2 := ctx.htlc(2, 1, total-total/2, total, expiry, onion)
+
+	payment := ctx.assertPayment()
+	require.Equal(t, route.NewVertex(ctx.nextKey.PubKey()), payment.Target)
+	require.EqualValues(t, testAmt, payment.Amount)
+	require.EqualValues(t, 1600, payment.FeeLimit)
+	require.EqualValues(
+		t, expiry-testRejectCltv-testHeight, payment.CltvLimit,
+	)
+	require.EqualValues(
+		t, testCltv-testHeight-routing.BlockPadding,
+		payment.FinalCLTVDelta,
+	)
+	require.Equal(t, testHash, payment.Identifier())
+	require.NotNil(t, payment.PaymentAddr)
+	require.Len(t, payment.TrampolineOnion, hop.TrampolineOnionSize)
+
+	// The next trampoline node must be able to process the onion we hand
+	// it.
+	payload, _, err := hop.ProcessTrampolineOnion(
+		&keychain.PrivKeyECDH{PrivKey: ctx.nextKey},
+		payment.TrampolineOnion,
+	)
+	require.NoError(t, err)
+	require.True(t, payload.IsFinal())
+
+	ctx.updates <- &channeldb.MPPayment{
+		Status: channeldb.StatusSucceeded,
+		HTLCs: []channeldb.HTLCAttempt{{
+			Settle: &channeldb.HTLCSettleInfo{
+				Preimage: testPreimage,
+			},
+		}},
+	}
+
+	htlc1.assertResolution(t, testPreimage)
+	htlc2.assertResolution(t, testPreimage)
+}
+
+// TestForwarderFinalRecipient tests that a recipient without trampoline
+// support is paid like the invoice that the sender describes, and that a
+// rejection by the recipient is relayed to the sender.
+func TestForwarderFinalRecipient(t *testing.T) {
+	ctx := newForwarderTestContext(t)
+
+	const total = testAmt + 1100
+	htlc := ctx.htlc(
+		1, 1, total, total, testCltv+testCltvDelta, ctx.onion(false),
+	)
+
+	payment := ctx.assertPayment()
+	require.Nil(t, payment.TrampolineOnion)
+	require.EqualValues(t, 1100, payment.FeeLimit)
+	require.Equal(t, [32]byte{3}, *payment.PaymentAddr)
+	require.True(t, payment.DestFeatures.HasFeature(
+		lnwire.PaymentAddrOptional,
+	))
+
+	reason := channeldb.FailureReasonPaymentDetails
+	ctx.updates <- &channeldb.MPPayment{
+		Status:        channeldb.StatusFailed,
+		FailureReason: &reason,
+	}
+
+	htlc.assertResolution(
+		t, lnwire.NewFailIncorrectDetails(total, testHeight),
+	)
+}
+
+// TestForwarderPolicy tests that trampoline payments that don't satisfy our
+// fee or cltv policy are failed without forwarding them.
+func TestForwarderPolicy(t *testing.T) {
+	ctx := newForwarderTestContext(t)
+	onion := ctx.onion(true)
+
+	// The fee doesn't cover our fee policy.
+	const total = testAmt + 1000
+	htlc := ctx.htlc(1, 1, total, total, testCltv+testCltvDelta, onion)
+	htlc.assertResolution(t, &lnwire.FailTrampolineFeeInsufficient{})
+
+	// The cltv delta doesn't satisfy our cltv policy.
+	const fullTotal = testAmt + 1100
+	htlc = ctx.htlc(
+		2, 2, fullTotal, fullTotal, testCltv+testCltvDelta-1, onion,
+	)
+	htlc.assertResolution(t, &lnwire.FailTrampolineExpiryTooSoon{})
+
+	// An onion that isn't meant for us can't be processed.
+	htlc = ctx.htlc(
+		3, 3, fullTotal, fullTotal, testCltv+testCltvDelta, onion[1:],
+	)
+	htlc.assertResolution(t, lnwire.NewInvalidOnionPayload(
+		uint64(record.TrampolineOnionType), 0,
+	))
+
+	select {
+	case payment := <-ctx.payments:
+		t.Fatalf("unexpected payment: %v", payment)
+	default:
+	}
+}
+
+// TestForwarderMppTimeout tests that the htlcs of an incomplete trampoline
+// payment are failed after the mpp timeout.
+func TestForwarderMppTimeout(t *testing.T) {
+	ctx := newForwarderTestContext(t)
+
+	const total = testAmt + 1100
+	htlc := ctx.htlc(
+		1, 1, total/2, total, testCltv+testCltvDelta, ctx.onion(true),
+	)
+
+	select {
+	case <-ctx.tick:
+	case <-time.After(testTimeout):
+		t.Fatal("mpp timeout not started")
+	}
+	htlc.assertUnresolved(t)
+
+	ctx.clock.SetTime(testTime.Add(time.Minute))
+	htlc.assertResolution(t, &lnwire.FailMPPTimeout{})
+}
+
+// TestForwarderReplay tests that htlcs that are replayed after a restart only
+// wait for the result of the outgoing payment that was already started, even
+// if they no longer satisfy our policy and not all of them are replayed.
+func TestForwarderReplay(t *testing.T) {
+	ctx := newForwarderTestContext(t)
+	ctx.setSent(true)
+
+	// The outgoing payment is still in flight when the first htlc is
+	// replayed. Its expiry no longer satisfies our cltv policy.
+	ctx.updates <- &channeldb.MPPayment{
+		Status: channeldb.StatusInFlight,
+		HTLCs:  []channeldb.HTLCAttempt{{}},
+	}
+
+	const total = testAmt + 1100
+	htlc1 := ctx.htlc(1, 1, total/2, total, testHeight+1, ctx.onion(true))
+
+	// The htlc is neither failed by our policy, nor by the mpp timeout,
+	// nor is the payment started again.
+	ctx.clock.SetTime(testTime.Add(time.Minute))
+	htlc1.assertUnresolved(t)
+
+	select {
+	case payment := <-ctx.payments:
+		t.Fatalf("unexpected payment: %v", payment)
+	default:
+	}
+
+	// Even if the payment is marked as failed, the htlc is held as long as
+	// there are outgoing htlcs in flight.
+	reason := channeldb.FailureReasonTimeout
+	ctx.updates <- &channeldb.MPPayment{
+		Status:        channeldb.StatusInFlight,
+		FailureReason: &reason,
+		HTLCs:         []channeldb.HTLCAttempt{{}},
+	}
+	htlc1.assertUnresolved(t)
+
+	// Once the outgoing payment succeeds, the htlc is settled.
+	ctx.updates <- &channeldb.MPPayment{
+		Status: channeldb.StatusSucceeded,
+		HTLCs: []channeldb.HTLCAttempt{{
+			Settle: &channeldb.HTLCSettleInfo{
+				Preimage: testPreimage,
+			},
+		}},
+	}
+	htlc1.assertResolution(t, testPreimage)
+
+	// An htlc of the same payment that is only replayed afterwards is
+	// settled right away.
+	ctx.updates <- &channeldb.MPPayment{
+		Status: channeldb.StatusSucceeded,
+		HTLCs: []channeldb.HTLCAttempt{{
+			Settle: &channeldb.HTLCSettleInfo{
+				Preimage: testPreimage,
+			},
+		}},
+	}
+	htlc2 := ctx.htlc(
+		2, 1, total-total/2, total, testHeight+1, ctx.onion(true),
+	)
+	htlc2.assertResolution(t, testPreimage)
+}
@@ -22,18 +22,28 @@ type unifiedPolicies struct {
 	// outChanRestr is an optional outgoing channel restriction for the
 	// local channel to use.
 	outChanRestr map[uint64]struct{}
+
+	// inChanRestr is an optional restriction on the channels towards the
+	// toNode that may be used.
+	inChanRestr map[uint64]struct{}
+
+	// excludedChans is an optional set of channels that must not be used.
+	excludedChans map[uint64]struct{}
 }
 
 // newUnifiedPolicies instantiates a new unifiedPolicies object. Channel
 // policies can be added to this object.
 func newUnifiedPolicies(sourceNode, toNode route.Vertex,
-	outChanRestr map[uint64]struct{}) *unifiedPolicies {
+	outChanRestr, inChanRestr,
+	excludedChans map[uint64]struct{}) *unifiedPolicies {
 
 	return &unifiedPolicies{
-		policies:     make(map[route.Vertex]*unifiedPolicy),
-		toNode:       toNode,
-		sourceNode:   sourceNode,
-		outChanRestr: outChanRestr,
+		policies:      make(map[route.Vertex]*unifiedPolicy),
+		toNode:        toNode,
+		sourceNode:    sourceNode,
+		outChanRestr:  outChanRestr,
+		inChanRestr:   inChanRestr,
+		excludedChans: excludedChans,
 	}
 }
 
@@ -51,6 +61,18 @@ func (u *unifiedPolicies) addPolicy(fromNode route.Vertex,
 		}
 	}
 
+	// Skip channels if there is an incoming channel restriction.
+	if u.inChanRestr != nil {
+		if _, ok := u.inChanRestr[edge.ChannelID]; !ok {
+			return
+		}
+	}
+
+	// Skip excluded channels.
+	if _, ok := u.excludedChans[edge.ChannelID]; ok {
+		return
+	}
+
 	// Update the policies map.
 	policy, ok := u.policies[fromNode]
 	if !ok {
@@ -77,6 +99,12 @@ func (u *unifiedPolicies) addGraphPolicies(g routingGraph) error {
 			return nil
 		}
 
+		// The inbound fee of the to node for this channel is part of
+		// its own policy. We attach it to the incoming policy, which
+		// is a copy that we are free to modify, such that it is
+		// accounted for when the policy is selected.
+		channel.InPolicy.ToNodeInboundFee = channel.InboundFee
+
 		// Add this policy to the unified policies map.
 		u.addPolicy(
 			channel.OtherNode, channel.InPolicy, channel.Capacity,
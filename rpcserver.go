@@ -18,6 +18,7 @@ import (
 	"time"
 
 	"github.com/brronsuite/broln/autopilot"
+	"github.com/brronsuite/broln/blinding"
 	"github.com/brronsuite/broln/build"
 	"github.com/brronsuite/broln/chainreg"
 	"github.com/brronsuite/broln/chanacceptor"
@@ -49,6 +50,8 @@ import (
 	"github.com/brronsuite/broln/lnwallet/chanfunding"
 	"github.com/brronsuite/broln/lnwire"
 	"github.com/brronsuite/broln/macaroons"
+	"github.com/brronsuite/broln/offers"
+	"github.com/brronsuite/broln/onionmsg"
 	"github.com/brronsuite/broln/peer"
 	"github.com/brronsuite/broln/peernotifier"
 	"github.com/brronsuite/broln/record"
@@ -207,6 +210,11 @@ var (
 	// macaroon related services are used.
 	errMacaroonDisabled = fmt.Errorf("macaroon authentication disabled, " +
 		"remove --no-macaroons flag to enable")
+
+	// errOnionMessagesDisabled is returned when the onion message RPCs are
+	// used without enabling onion messages.
+	errOnionMessagesDisabled = errors.New("onion messages disabled, set " +
+		"--protocol.onion-messages to enable")
 )
 
 // stringInSlice returns true if a string is contained in the given slice.
@@ -358,6 +366,13 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "offchain",
 			Action: "write",
 		}},
+		"/lnrpc.Lightning/BumpCloseFee": {{
+			Entity: "onchain",
+			Action: "write",
+		}, {
+			Entity: "offchain",
+			Action: "write",
+		}},
 		"/lnrpc.Lightning/AbandonChannel": {{
 			Entity: "offchain",
 			Action: "write",
@@ -526,6 +541,10 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "offchain",
 			Action: "read",
 		}},
+		"/lnrpc.Lightning/GetBackupSinkStatus": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
 		"/lnrpc.Lightning/ChannelAcceptor": {{
 			Entity: "onchain",
 			Action: "write",
@@ -564,6 +583,20 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "offchain",
 			Action: "write",
 		}},
+		"/lnrpc.Lightning/SpliceChannel": {{
+			Entity: "onchain",
+			Action: "write",
+		}, {
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/SpliceFinalizePsbt": {{
+			Entity: "onchain",
+			Action: "write",
+		}, {
+			Entity: "offchain",
+			Action: "write",
+		}},
 		lnrpc.RegisterRPCMiddlewareURI: {{
 			Entity: "macaroon",
 			Action: "write",
@@ -576,6 +609,30 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "offchain",
 			Action: "read",
 		}},
+		"/lnrpc.Lightning/SendOnionMessage": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/SubscribeOnionMessages": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/AddOffer": {{
+			Entity: "invoices",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/ListOffers": {{
+			Entity: "invoices",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/DisableOffer": {{
+			Entity: "invoices",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/DecodeOffer": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
 	}
 }
 
@@ -723,6 +780,26 @@ func (r *rpcServer) addDeps(s *server, macService *macaroons.Service,
 		SetChannelAuto: s.chanStatusMgr.RequestAuto,
 	}
 
+	// Offers can only be paid if we can request invoices for them over
+	// onion messages.
+	if s.offerMgr != nil {
+		routerBackend.FetchOfferInvoice = s.offerMgr.FetchInvoice
+	}
+
+	// Channels are rebalanced by paying invoices of our own node.
+	routerBackend.AddSelfInvoice = func(ctx context.Context,
+		amt lnwire.MilliBronees, memo string) (*channeldb.Invoice,
+		error) {
+
+		_, invoice, err := invoicesrpc.AddInvoice(
+			ctx, r.addInvoiceConfig(), &invoicesrpc.AddInvoiceData{
+				Memo:  memo,
+				Value: amt,
+			},
+		)
+		return invoice, err
+	}
+
 	genInvoiceFeatures := func() *lnwire.FeatureVector {
 		return s.featureMgr.Get(feature.SetInvoice)
 	}
@@ -741,12 +818,12 @@ func (r *rpcServer) addDeps(s *server, macService *macaroons.Service,
 	//
 	// TODO(roasbeef): extend sub-sever config to have both (local vs remote) DB
 	err = subServerCgs.PopulateDependencies(
-		r.cfg, s.cc, r.cfg.networkDir, macService, atpl, invoiceRegistry,
-		s.htlcSwitch, r.cfg.ActiveNetParams.Params, s.chanRouter,
-		routerBackend, s.nodeSigner, s.graphDB, s.chanStateDB,
-		s.sweeper, tower, s.towerClient, s.anchorTowerClient,
+		r.cfg, s.cc, r.cfg.networkDir, macService, atpl, s.feeMgr,
+		invoiceRegistry, s.htlcSwitch, r.cfg.ActiveNetParams.Params,
+		s.chanRouter, routerBackend, s.nodeSigner, s.graphDB,
+		s.chanStateDB, s.sweeper, tower, s.towerClient, s.anchorTowerClient,
 		r.cfg.net.ResolveTCPAddr, genInvoiceFeatures,
-		genAmpInvoiceFeatures, rpcsLog,
+		genAmpInvoiceFeatures, s.aliasMgr.GetPeerAlias, rpcsLog,
 	)
 	if err != nil {
 		return err
@@ -1512,6 +1589,25 @@ func (r *rpcServer) NewAddress(ctx context.Context,
 		if err != nil {
 			return nil, err
 		}
+
+	case lnrpc.AddressType_TAPROOT_PUBKEY:
+		addr, err = r.server.cc.Wallet.NewAddress(
+			lnwallet.TaprootPubkey, false, account,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+	case lnrpc.AddressType_UNUSED_TAPROOT_PUBKEY:
+		addr, err = r.server.cc.Wallet.LastUnusedAddress(
+			lnwallet.TaprootPubkey, account,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown address type: %v", in.Type)
 	}
 
 	rpcsLog.Debugf("[newaddress] account=%v type=%v addr=%v", account,
@@ -2024,6 +2120,36 @@ func (r *rpcServer) parseOpenChannelReq(in *lnrpc.OpenChannelRequest,
 			in.CommitmentType)
 	}
 
+	// Zero-conf and scid-alias channels can't be announced, as their alias
+	// doesn't point to a funding output on-chain. They also require the
+	// corresponding protocol options to be enabled.
+	if in.ZeroConf || in.ScidAlias {
+		if !in.Private {
+			return nil, fmt.Errorf("zero-conf and scid-alias " +
+				"channels must be private")
+		}
+
+		if !globalFeatureSet.HasFeature(lnwire.ScidAliasOptional) {
+			return nil, fmt.Errorf("scid-alias channels require " +
+				"protocol.option-scid-alias to be set")
+		}
+	}
+	if in.ZeroConf &&
+		!globalFeatureSet.HasFeature(lnwire.ZeroConfOptional) {
+
+		return nil, fmt.Errorf("zero-conf channels require " +
+			"protocol.zero-conf to be set")
+	}
+
+	// Dual funded channels require the corresponding protocol option to
+	// be enabled.
+	if in.DualFund &&
+		!globalFeatureSet.HasFeature(lnwire.DualFundOptional) {
+
+		return nil, fmt.Errorf("dual funded channels require " +
+			"protocol.dual-fund to be set")
+	}
+
 	// Instruct the server to trigger the necessary events to attempt to
 	// open a new channel. A stream is returned in place, this stream will
 	// be used to consume updates of the state of the pending channel.
@@ -2042,6 +2168,9 @@ func (r *rpcServer) parseOpenChannelReq(in *lnrpc.OpenChannelRequest,
 		MaxHtlcs:         maxHtlcs,
 		MaxLocalCsv:      uint16(in.MaxLocalCsv),
 		ChannelType:      channelType,
+		ZeroConf:         in.ZeroConf,
+		ScidAlias:        in.ScidAlias,
+		DualFund:         in.DualFund,
 	}, nil
 }
 
@@ -2536,6 +2665,269 @@ func createRPCCloseUpdate(update interface{}) (
 	return nil, errors.New("unknown close status update")
 }
 
+// SpliceChannel resizes an existing channel on-chain without closing it. The
+// splice is negotiated by the peer of the channel, which streams the updates
+// of the splice back to the caller.
+// BumpCloseFee proposes a new closing transaction for a channel that is being
+// cooperatively closed, paying a higher fee from our own channel output.
+func (r *rpcServer) BumpCloseFee(ctx context.Context,
+	in *lnrpc.BumpCloseFeeRequest) (*lnrpc.BumpCloseFeeResponse, error) {
+
+	if !r.server.Started() {
+		return nil, ErrServerNotActive
+	}
+
+	if in.GetChannelPoint() == nil {
+		return nil, fmt.Errorf("must specify channel point")
+	}
+	txid, err := lnrpc.GetChanPointFundingTxid(in.GetChannelPoint())
+	if err != nil {
+		return nil, err
+	}
+	chanPoint := wire.NewOutPoint(txid, in.ChannelPoint.OutputIndex)
+
+	channel, err := r.server.chanStateDB.FetchChannel(nil, *chanPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	feeRate, err := calculateFeeRate(
+		0, in.SatPerVbyte, uint32(in.TargetConf),
+		r.server.cc.FeeEstimator,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	chanPeer, err := r.server.FindPeer(channel.IdentityPub)
+	if err != nil {
+		return nil, fmt.Errorf("unable to bump close fee while peer "+
+			"is offline: %v", err)
+	}
+
+	rpcsLog.Debugf("[bumpclosefee] bumping close fee of "+
+		"ChannelPoint(%v) to fee rate %v", chanPoint, feeRate)
+
+	closingTxid, err := chanPeer.BumpCloseFee(*chanPoint, feeRate)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.BumpCloseFeeResponse{
+		ClosingTxid: closingTxid[:],
+	}, nil
+}
+
+func (r *rpcServer) SpliceChannel(in *lnrpc.SpliceChannelRequest,
+	updateStream lnrpc.Lightning_SpliceChannelServer) error {
+
+	if !r.server.Started() {
+		return ErrServerNotActive
+	}
+
+	if in.GetChannelPoint() == nil {
+		return fmt.Errorf("must specify channel point to splice")
+	}
+	txid, err := lnrpc.GetChanPointFundingTxid(in.GetChannelPoint())
+	if err != nil {
+		return err
+	}
+	chanPoint := wire.NewOutPoint(txid, in.ChannelPoint.OutputIndex)
+
+	if in.RelativeAmount == 0 {
+		return fmt.Errorf("relative amount must not be zero")
+	}
+	if in.MinConfs < 0 {
+		return fmt.Errorf("min_confs must not be negative")
+	}
+
+	// A funded PSBT can only be used to add funds to the channel.
+	var packet *psbt.Packet
+	if len(in.FundedPsbt) > 0 {
+		if in.RelativeAmount < 0 {
+			return fmt.Errorf("a funded PSBT can only be used to " +
+				"splice funds into the channel")
+		}
+
+		packet, err = psbt.NewFromRawBytes(
+			bytes.NewReader(in.FundedPsbt), false,
+		)
+		if err != nil {
+			return fmt.Errorf("error parsing funded PSBT: %v", err)
+		}
+	}
+
+	channel, err := r.server.chanStateDB.FetchChannel(nil, *chanPoint)
+	if err != nil {
+		return err
+	}
+
+	// We can't splice restored channels or channels that have experienced
+	// local data loss, as we don't know their current state.
+	if channel.HasChanStatus(channeldb.ChanStatusRestored) ||
+		channel.HasChanStatus(channeldb.ChanStatusLocalDataLoss) {
+
+		return fmt.Errorf("cannot splice channel with state: %v",
+			channel.ChanStatus())
+	}
+
+	if len(channel.ActiveHtlcs()) != 0 {
+		return fmt.Errorf("cannot splice channel with active htlcs")
+	}
+
+	feeRate, err := calculateFeeRate(
+		0, in.SatPerVbyte, uint32(in.TargetConf),
+		r.server.cc.FeeEstimator,
+	)
+	if err != nil {
+		return err
+	}
+
+	chanPeer, err := r.server.FindPeer(channel.IdentityPub)
+	if err != nil {
+		return fmt.Errorf("unable to splice channel while peer is "+
+			"offline: %v", err)
+	}
+
+	rpcsLog.Debugf("[splicechannel] splicing ChannelPoint(%v) by %v at "+
+		"fee rate %v", chanPoint, bronutil.Amount(in.RelativeAmount),
+		feeRate)
+
+	req := &peer.SpliceReq{
+		ChanPoint:   chanPoint,
+		Amount:      bronutil.Amount(in.RelativeAmount),
+		FeeRate:     feeRate,
+		MinConfs:    in.MinConfs,
+		FundingPsbt: packet,
+		Updates:     make(chan interface{}, 3),
+		Err:         make(chan error, 1),
+	}
+	chanPeer.HandleLocalSpliceReq(req)
+
+	for {
+		select {
+		case err := <-req.Err:
+			rpcsLog.Errorf("[splicechannel] unable to splice "+
+				"ChannelPoint(%v): %v", chanPoint, err)
+			return err
+
+		case update := <-req.Updates:
+			rpcUpdate, err := createRPCSpliceUpdate(update)
+			if err != nil {
+				return err
+			}
+
+			rpcsLog.Tracef("[splicechannel] sending update: %v",
+				rpcUpdate)
+
+			if err := updateStream.Send(rpcUpdate); err != nil {
+				return err
+			}
+
+			// Once the splice is locked, the channel is usable
+			// again and no further updates follow.
+			if u, ok := update.(*peer.SpliceLockedUpdate); ok {
+				rpcsLog.Infof("[splicechannel] splice completed: "+
+					"ChannelPoint(%v)", u.ChanPoint)
+				return nil
+			}
+
+		case <-updateStream.Context().Done():
+			return updateStream.Context().Err()
+
+		case <-r.quit:
+			return nil
+		}
+	}
+}
+
+func createRPCSpliceUpdate(update interface{}) (*lnrpc.SpliceStatusUpdate,
+	error) {
+
+	switch u := update.(type) {
+	case *peer.SplicePsbtUpdate:
+		var b bytes.Buffer
+		if err := u.Psbt.Serialize(&b); err != nil {
+			return nil, err
+		}
+
+		return &lnrpc.SpliceStatusUpdate{
+			Update: &lnrpc.SpliceStatusUpdate_PsbtSign{
+				PsbtSign: &lnrpc.SplicePsbtSign{
+					Psbt: b.Bytes(),
+				},
+			},
+		}, nil
+
+	case *peer.PendingUpdate:
+		return &lnrpc.SpliceStatusUpdate{
+			Update: &lnrpc.SpliceStatusUpdate_SplicePending{
+				SplicePending: &lnrpc.PendingUpdate{
+					Txid:        u.Txid,
+					OutputIndex: u.OutputIndex,
+				},
+			},
+		}, nil
+
+	case *peer.SpliceLockedUpdate:
+		txid := u.ChanPoint.Hash
+		return &lnrpc.SpliceStatusUpdate{
+			Update: &lnrpc.SpliceStatusUpdate_SpliceLocked{
+				SpliceLocked: &lnrpc.ChannelOpenUpdate{
+					ChannelPoint: &lnrpc.ChannelPoint{
+						FundingTxid: &lnrpc.ChannelPoint_FundingTxidBytes{
+							FundingTxidBytes: txid[:],
+						},
+						OutputIndex: u.ChanPoint.Index,
+					},
+				},
+			},
+		}, nil
+	}
+
+	return nil, errors.New("unknown splice status update")
+}
+
+// SpliceFinalizePsbt hands the signed PSBT of a PSBT funded splice to the peer
+// of the spliced channel.
+func (r *rpcServer) SpliceFinalizePsbt(ctx context.Context,
+	in *lnrpc.SpliceFinalizePsbtRequest) (*lnrpc.SpliceFinalizePsbtResponse,
+	error) {
+
+	if in.GetChannelPoint() == nil {
+		return nil, fmt.Errorf("must specify channel point")
+	}
+	txid, err := lnrpc.GetChanPointFundingTxid(in.GetChannelPoint())
+	if err != nil {
+		return nil, err
+	}
+	chanPoint := wire.NewOutPoint(txid, in.ChannelPoint.OutputIndex)
+
+	packet, err := psbt.NewFromRawBytes(
+		bytes.NewReader(in.SignedPsbt), false,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing signed PSBT: %v", err)
+	}
+
+	channel, err := r.server.chanStateDB.FetchChannel(nil, *chanPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	chanPeer, err := r.server.FindPeer(channel.IdentityPub)
+	if err != nil {
+		return nil, err
+	}
+
+	err = chanPeer.FinalizeSplicePsbt(*chanPoint, packet)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.SpliceFinalizePsbtResponse{}, nil
+}
+
 // abandonChanFromGraph attempts to remove a channel from the channel graph. If
 // we can't find the chanID in the graph, then we assume it has already been
 // removed, and will return a nop.
@@ -3394,6 +3786,69 @@ func (r *rpcServer) fetchWaitingCloseChannels() (waitingCloseChannels,
 	result := make(waitingCloseChannels, 0)
 	limboBalance := int64(0)
 
+	// Index the anchor outputs the sweeper is currently sweeping by the
+	// commitment transaction they are on, so we can report how far the
+	// fee bumping of the commitments of each channel has progressed.
+	pendingInputs, err := r.server.sweeper.PendingInputs()
+	if err != nil {
+		rpcsLog.Errorf("unable to fetch pending sweeps: %v", err)
+		return nil, 0, err
+	}
+
+	anchorSweeps := make(
+		map[string][]*lnrpc.PendingChannelsResponse_AnchorSweep,
+	)
+	for _, pendingInput := range pendingInputs {
+		if pendingInput.WitnessType != input.CommitmentAnchor {
+			continue
+		}
+
+		op := pendingInput.OutPoint
+		commitTxid := op.Hash.String()
+		anchorSweep := &lnrpc.PendingChannelsResponse_AnchorSweep{
+			Outpoint: &lnrpc.OutPoint{
+				OutputIndex: op.Index,
+				TxidStr:     commitTxid,
+				TxidBytes:   op.Hash[:],
+			},
+			CommitTxid: commitTxid,
+			SatPerVbyte: uint64(
+				pendingInput.LastFeeRate.FeePerKVByte() / 1000,
+			),
+			BroadcastAttempts: uint32(
+				pendingInput.BroadcastAttempts,
+			),
+			NextBroadcastHeight: pendingInput.NextBroadcastHeight,
+			BudgetSat:           int64(pendingInput.Params.Budget),
+		}
+		if pendingInput.Params.DeadlineHeight != nil {
+			anchorSweep.DeadlineHeight = uint32(
+				*pendingInput.Params.DeadlineHeight,
+			)
+		}
+
+		anchorSweeps[commitTxid] = append(
+			anchorSweeps[commitTxid], anchorSweep,
+		)
+	}
+
+	// getAnchorSweeps is a helper closure that returns the anchor sweeps
+	// of the given commitment txids. Empty txids are skipped.
+	getAnchorSweeps := func(
+		txids ...string) []*lnrpc.PendingChannelsResponse_AnchorSweep {
+
+		var sweeps []*lnrpc.PendingChannelsResponse_AnchorSweep
+		for _, txid := range txids {
+			if txid == "" {
+				continue
+			}
+
+			sweeps = append(sweeps, anchorSweeps[txid]...)
+		}
+
+		return sweeps
+	}
+
 	// getClosingTx is a helper closure that tries to find the closing txid
 	// of a given waiting close channel. Notice that if the remote closes
 	// the channel, we may not have the closing txid.
@@ -3521,6 +3976,10 @@ func (r *rpcServer) fetchWaitingCloseChannels() (waitingCloseChannels,
 			LimboBalance: channel.LocalBalance,
 			Commitments:  &commitments,
 			ClosingTxid:  closingTxid,
+			AnchorSweeps: getAnchorSweeps(
+				commitments.LocalTxid, commitments.RemoteTxid,
+				commitments.RemotePendingTxid,
+			),
 		}
 
 		// A close tx has been broadcasted, all our balance will be in
@@ -4045,6 +4504,27 @@ func createRPCOpenChannel(r *rpcServer, graph *channeldb.ChannelGraph,
 		channel.PushAmountSat = uint64(localBalance.ToBroneess())
 	}
 
+	// For alias channels, we'll report all of our local aliases, the
+	// confirmed SCID and the alias the peer assigned to the channel.
+	if dbChannel.HasScidAlias() {
+		aliases := r.server.aliasMgr.GetAliases(dbChannel.ShortChanID())
+		for _, alias := range aliases {
+			channel.AliasScids = append(
+				channel.AliasScids, alias.ToUint64(),
+			)
+		}
+
+		channel.ZeroConf = dbChannel.IsZeroConf()
+		channel.ZeroConfConfirmedScid = dbChannel.ZeroConfRealScid().
+			ToUint64()
+
+		chanID := lnwire.NewChanIDFromOutPoint(&chanPoint)
+		peerAlias, err := r.server.aliasMgr.GetPeerAlias(chanID)
+		if err == nil {
+			channel.PeerScidAlias = peerAlias.ToUint64()
+		}
+	}
+
 	if len(dbChannel.LocalShutdownScript) > 0 {
 		_, addresses, _, err := txscript.ExtractPkScriptAddrs(
 			dbChannel.LocalShutdownScript, r.cfg.ActiveNetParams.Params,
@@ -4547,7 +5027,7 @@ type rpcPaymentIntent struct {
 	cltvDelta          uint16
 	routeHints         [][]zpay32.HopHint
 	outgoingChannelIDs []uint64
-	lastHop            *route.Vertex
+	lastHops           []route.Vertex
 	destFeatures       *lnwire.FeatureVector
 	paymentAddr        *[32]byte
 	payReq             []byte
@@ -4602,7 +5082,7 @@ func (r *rpcServer) extractPaymentIntent(rpcPayReq *rpcPaymentRequest) (rpcPayme
 		if err != nil {
 			return payIntent, err
 		}
-		payIntent.lastHop = &lastHop
+		payIntent.lastHops = []route.Vertex{lastHop}
 	}
 
 	// Take the CLTV limit from the request if set, otherwise use the max.
@@ -4832,7 +5312,7 @@ func (r *rpcServer) dispatchPaymentIntent(
 			CltvLimit:          payIntent.cltvLimit,
 			RouteHints:         payIntent.routeHints,
 			OutgoingChannelIDs: payIntent.outgoingChannelIDs,
-			LastHop:            payIntent.lastHop,
+			LastHops:           payIntent.lastHops,
 			PaymentRequest:     payIntent.payReq,
 			PayAttemptTimeout:  routing.DefaultPayAttemptTimeout,
 			DestCustomRecords:  payIntent.destCustomRecords,
@@ -5174,20 +5654,18 @@ func (r *rpcServer) sendPaymentSync(ctx context.Context,
 	}, nil
 }
 
-// AddInvoice attempts to add a new invoice to the invoice database. Any
-// duplicated invoices are rejected, therefore all invoices *must* have a
-// unique payment preimage.
-func (r *rpcServer) AddInvoice(ctx context.Context,
-	invoice *lnrpc.Invoice) (*lnrpc.AddInvoiceResponse, error) {
-
+// addInvoiceConfig returns the configuration used to add invoices to our
+// invoice registry.
+func (r *rpcServer) addInvoiceConfig() *invoicesrpc.AddInvoiceConfig {
 	defaultDelta := r.cfg.Brocoin.TimeLockDelta
 	if r.cfg.registeredChains.PrimaryChain() == chainreg.LitecoinChain {
 		defaultDelta = r.cfg.Litecoin.TimeLockDelta
 	}
 
-	addInvoiceCfg := &invoicesrpc.AddInvoiceConfig{
+	return &invoicesrpc.AddInvoiceConfig{
 		AddInvoice:        r.server.invoices.AddInvoice,
 		IsChannelActive:   r.server.htlcSwitch.HasActiveLink,
+		GetAlias:          r.server.aliasMgr.GetPeerAlias,
 		ChainParams:       r.cfg.ActiveNetParams.Params,
 		NodeSigner:        r.server.nodeSigner,
 		DefaultCLTVExpiry: defaultDelta,
@@ -5199,7 +5677,20 @@ func (r *rpcServer) AddInvoice(ctx context.Context,
 		GenAmpInvoiceFeatures: func() *lnwire.FeatureVector {
 			return r.server.featureMgr.Get(feature.SetInvoiceAmp)
 		},
+		BestHeight: func() (uint32, error) {
+			_, height, err := r.server.cc.ChainIO.GetBestBlock()
+			return uint32(height), err
+		},
 	}
+}
+
+// AddInvoice attempts to add a new invoice to the invoice database. Any
+// duplicated invoices are rejected, therefore all invoices *must* have a
+// unique payment preimage.
+func (r *rpcServer) AddInvoice(ctx context.Context,
+	invoice *lnrpc.Invoice) (*lnrpc.AddInvoiceResponse, error) {
+
+	addInvoiceCfg := r.addInvoiceConfig()
 
 	value, err := lnrpc.UnmarshallAmt(invoice.Value, invoice.ValueMsat)
 	if err != nil {
@@ -5221,6 +5712,7 @@ func (r *rpcServer) AddInvoice(ctx context.Context,
 		Private:         invoice.Private,
 		RouteHints:      routeHints,
 		Amp:             invoice.IsAmp,
+		Blind:           invoice.IsBlinded,
 	}
 
 	if invoice.RPreimage != nil {
@@ -5319,7 +5811,31 @@ func (r *rpcServer) ListInvoices(ctx context.Context,
 		NumMaxInvoices: req.NumMaxInvoices,
 		PendingOnly:    req.PendingOnly,
 		Reversed:       req.Reversed,
+		MemoContains:   req.MemoFilter,
+	}
+
+	if req.CreationDateStart != 0 {
+		q.CreationDateStart = time.Unix(int64(req.CreationDateStart), 0)
+	}
+	if req.CreationDateEnd != 0 {
+		q.CreationDateEnd = time.Unix(int64(req.CreationDateEnd), 0)
+	}
+	if req.CreationDateStart != 0 && req.CreationDateEnd != 0 &&
+		req.CreationDateStart > req.CreationDateEnd {
+
+		return nil, fmt.Errorf("creation_date_start must not be after " +
+			"creation_date_end")
+	}
+
+	for _, rpcState := range req.States {
+		state, err := invoicesrpc.UnmarshallInvoiceState(rpcState)
+		if err != nil {
+			return nil, err
+		}
+
+		q.States = append(q.States, state)
 	}
+
 	invoiceSlice, err := r.server.miscDB.QueryInvoices(q)
 	if err != nil {
 		return nil, fmt.Errorf("unable to query invoices: %v", err)
@@ -5600,6 +6116,10 @@ func marshalDbEdge(edgeInfo *channeldb.ChannelEdgeInfo,
 			Disabled:         c1.ChannelFlags&lnwire.ChanUpdateDisabled != 0,
 			LastUpdate:       uint32(c1.LastUpdate.Unix()),
 		}
+
+		inboundFee := c1.InboundFee()
+		edge.Node1Policy.InboundFeeBaseMsat = inboundFee.BaseFee
+		edge.Node1Policy.InboundFeeRateMilliMsat = inboundFee.FeeRate
 	}
 
 	if c2 != nil {
@@ -5612,6 +6132,10 @@ func marshalDbEdge(edgeInfo *channeldb.ChannelEdgeInfo,
 			Disabled:         c2.ChannelFlags&lnwire.ChanUpdateDisabled != 0,
 			LastUpdate:       uint32(c2.LastUpdate.Unix()),
 		}
+
+		inboundFee := c2.InboundFee()
+		edge.Node2Policy.InboundFeeBaseMsat = inboundFee.BaseFee
+		edge.Node2Policy.InboundFeeRateMilliMsat = inboundFee.FeeRate
 	}
 
 	return edge
@@ -6072,6 +6596,11 @@ func marshallTopologyChange(topChange *routing.TopologyChange) *lnrpc.GraphTopol
 			AdvertisingNode: encodeKey(channelUpdate.AdvertisingNode),
 			ConnectingNode:  encodeKey(channelUpdate.ConnectingNode),
 		}
+
+		inboundFee := channelUpdate.InboundFee
+		policy := channelUpdates[i].RoutingPolicy
+		policy.InboundFeeBaseMsat = inboundFee.BaseFee
+		policy.InboundFeeRateMilliMsat = inboundFee.FeeRate
 	}
 
 	closedChans := make([]*lnrpc.ClosedChannelUpdate, len(topChange.ClosedChannels))
@@ -6321,13 +6850,17 @@ func (r *rpcServer) FeeReport(ctx context.Context,
 		feeRateFixedPoint := edgePolicy.FeeProportionalMillionths
 		feeRate := float64(feeRateFixedPoint) / feeBase
 
+		inboundFee := edgePolicy.InboundFee()
+
 		// TODO(roasbeef): also add stats for revenue for each channel
 		feeReports = append(feeReports, &lnrpc.ChannelFeeReport{
-			ChanId:       chanInfo.ChannelID,
-			ChannelPoint: chanInfo.ChannelPoint.String(),
-			BaseFeeMsat:  int64(edgePolicy.FeeBaseMSat),
-			FeePerMil:    int64(feeRateFixedPoint),
-			FeeRate:      feeRate,
+			ChanId:             chanInfo.ChannelID,
+			ChannelPoint:       chanInfo.ChannelPoint.String(),
+			BaseFeeMsat:        int64(edgePolicy.FeeBaseMSat),
+			FeePerMil:          int64(feeRateFixedPoint),
+			FeeRate:            feeRate,
+			InboundBaseFeeMsat: inboundFee.BaseFee,
+			InboundFeePerMil:   inboundFee.FeeRate,
 		})
 
 		return nil
@@ -6511,6 +7044,24 @@ func (r *rpcServer) UpdateChannelPolicy(ctx context.Context,
 		FeeRate: feeRateFixed,
 	}
 
+	// If an inbound fee is specified, it replaces the current inbound fee
+	// of the targeted channels. Inbound fees can only be a discount on the
+	// outbound fee, as senders that don't know about them wouldn't pay a
+	// positive inbound fee, and their payments would fail.
+	if req.InboundFee != nil {
+		if req.InboundFee.BaseFeeMsat > 0 ||
+			req.InboundFee.FeeRatePpm > 0 {
+
+			return nil, status.Errorf(codes.InvalidArgument,
+				"positive inbound fees are not supported")
+		}
+
+		feeSchema.InboundFee = &lnwire.InboundFee{
+			BaseFee: req.InboundFee.BaseFeeMsat,
+			FeeRate: req.InboundFee.FeeRatePpm,
+		}
+	}
+
 	maxHtlc := lnwire.MilliBronees(req.MaxHtlcMsat)
 	var minHtlc *lnwire.MilliBronees
 	if req.MinHtlcMsatSpecified {
@@ -6527,9 +7078,9 @@ func (r *rpcServer) UpdateChannelPolicy(ctx context.Context,
 
 	rpcsLog.Debugf("[updatechanpolicy] updating channel policy base_fee=%v, "+
 		"rate_fixed=%v, time_lock_delta: %v, "+
-		"min_htlc=%v, max_htlc=%v, targets=%v",
+		"min_htlc=%v, max_htlc=%v, inbound_fee=%v, targets=%v",
 		req.BaseFeeMsat, feeRateFixed, req.TimeLockDelta,
-		minHtlc, maxHtlc,
+		minHtlc, maxHtlc, spew.Sdump(feeSchema.InboundFee),
 		spew.Sdump(targetChans))
 
 	// With the scope resolved, we'll now send this to the local channel
@@ -6664,6 +7215,7 @@ func (r *rpcServer) ExportChannelBackup(ctx context.Context,
 	// unknown, then we'll return an error
 	unpackedBackup, err := chanbackup.FetchBackupForChan(
 		chanPoint, r.server.chanStateDB, r.server.addrSource,
+		r.server.chanBackupOpts...,
 	)
 	if err != nil {
 		return nil, err
@@ -6834,6 +7386,7 @@ func (r *rpcServer) ExportAllChannelBackups(ctx context.Context,
 	// channels from disk.
 	allUnpackedBackups, err := chanbackup.FetchStaticChanBackups(
 		r.server.chanStateDB, r.server.addrSource,
+		r.server.chanBackupOpts...,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("unable to fetch all static chan "+
@@ -6860,11 +7413,15 @@ func (r *rpcServer) RestoreChannelBackups(ctx context.Context,
 	// First, we'll make our implementation of the
 	// chanbackup.ChannelRestorer interface which we'll use to properly
 	// restore either a set of chanbackup.Single or chanbackup.Multi
-	// backups.
+	// backups. If requested, the restorer also broadcasts the commitment
+	// transactions carried within the backups.
 	chanRestorer := &chanDBRestorer{
 		db:         r.server.chanStateDB,
 		secretKeys: r.server.cc.KeyRing,
 		chainArb:   r.server.chainArb,
+		forceClose: in.ForceClose,
+		signer:     r.server.cc.Signer,
+		publishTx:  r.server.cc.Wallet.PublishTransaction,
 	}
 
 	// We'll accept either a list of Single backups, or a single Multi
@@ -6961,6 +7518,7 @@ func (r *rpcServer) SubscribeChannelBackups(req *lnrpc.ChannelBackupSubscription
 			// backups from disk.
 			chanBackups, err := chanbackup.FetchStaticChanBackups(
 				r.server.chanStateDB, r.server.addrSource,
+				r.server.chanBackupOpts...,
 			)
 			if err != nil {
 				return fmt.Errorf("unable to fetch all "+
@@ -6995,6 +7553,41 @@ func (r *rpcServer) SubscribeChannelBackups(req *lnrpc.ChannelBackupSubscription
 	}
 }
 
+// GetBackupSinkStatus returns the upload status of every remote sink that
+// multi-channel backups are pushed to.
+func (r *rpcServer) GetBackupSinkStatus(ctx context.Context,
+	_ *lnrpc.GetBackupSinkStatusRequest) (*lnrpc.GetBackupSinkStatusResponse,
+	error) {
+
+	// unixOrZero returns the unix timestamp of the given time, or zero if
+	// the time isn't set.
+	unixOrZero := func(t time.Time) int64 {
+		if t.IsZero() {
+			return 0
+		}
+
+		return t.Unix()
+	}
+
+	resp := &lnrpc.GetBackupSinkStatusResponse{}
+	for _, status := range r.server.chanSubSwapper.SinkStatus() {
+		var lastErr string
+		if status.LastErr != nil {
+			lastErr = status.LastErr.Error()
+		}
+
+		resp.Sinks = append(resp.Sinks, &lnrpc.BackupSinkStatus{
+			Name:        status.Name,
+			LastSuccess: unixOrZero(status.LastSuccess),
+			LastAttempt: unixOrZero(status.LastAttempt),
+			LastError:   lastErr,
+			Pending:     status.Pending,
+		})
+	}
+
+	return resp, nil
+}
+
 // ChannelAcceptor dispatches a bi-directional streaming RPC in which
 // OpenChannel requests are sent to the client and the client responds with
 // a boolean that tells broln whether or not to accept the channel. This allows
@@ -7544,6 +8137,274 @@ func (r *rpcServer) SubscribeCustomMessages(req *lnrpc.SubscribeCustomMessagesRe
 	}
 }
 
+// SendOnionMessage sends an onion message through a blinded path.
+func (r *rpcServer) SendOnionMessage(ctx context.Context,
+	req *lnrpc.SendOnionMessageRequest) (*lnrpc.SendOnionMessageResponse,
+	error) {
+
+	if r.server.onionMessenger == nil {
+		return nil, errOnionMessagesDisabled
+	}
+
+	var (
+		path *blinding.BlindedPath
+		err  error
+	)
+	switch {
+	case len(req.NodePath) > 0 && req.BlindedPath != nil:
+		return nil, errors.New("node_path and blinded_path are " +
+			"mutually exclusive")
+
+	case len(req.NodePath) > 0:
+		nodes, err := parsePubKeys(req.NodePath)
+		if err != nil {
+			return nil, err
+		}
+
+		path, err = onionmsg.BuildPath(nodes, nil)
+		if err != nil {
+			return nil, err
+		}
+
+	case req.BlindedPath != nil:
+		path, err = lnrpc.UnmarshalBlindedPath(req.BlindedPath)
+		if err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, errors.New("either node_path or blinded_path " +
+			"must be set")
+	}
+
+	// If a reply path is requested, we create a blinded path through the
+	// given nodes that ends at our own node.
+	var replyPath *blinding.BlindedPath
+	if len(req.ReplyPath) > 0 {
+		nodes, err := parsePubKeys(req.ReplyPath)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, r.server.identityECDH.PubKey())
+
+		replyPath, err = onionmsg.BuildPath(nodes, req.ReplyPathId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = r.server.onionMessenger.SendMessage(
+		path, replyPath, req.CustomRecords,
+	)
+	switch {
+	case errors.Is(err, ErrPeerNotConnected):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case err != nil:
+		return nil, err
+	}
+
+	return &lnrpc.SendOnionMessageResponse{}, nil
+}
+
+// SubscribeOnionMessages subscribes to a stream of incoming onion messages
+// that are addressed to our node.
+func (r *rpcServer) SubscribeOnionMessages(
+	req *lnrpc.SubscribeOnionMessagesRequest,
+	server lnrpc.Lightning_SubscribeOnionMessagesServer) error {
+
+	if r.server.onionMessenger == nil {
+		return errOnionMessagesDisabled
+	}
+
+	client, err := r.server.onionMessenger.SubscribeMessages()
+	if err != nil {
+		return err
+	}
+	defer client.Cancel()
+
+	for {
+		select {
+		case <-client.Quit():
+			return errors.New("shutdown")
+
+		case <-server.Context().Done():
+			return server.Context().Err()
+
+		case update := <-client.Updates():
+			msg := update.(*onionmsg.ReceivedMessage)
+
+			rpcMsg := &lnrpc.OnionMessage{
+				Peer:          msg.Peer[:],
+				PathId:        msg.PathID,
+				CustomRecords: msg.CustomRecords,
+			}
+			if msg.ReplyPath != nil {
+				rpcMsg.ReplyPath = lnrpc.MarshalBlindedPath(
+					msg.ReplyPath,
+				)
+			}
+
+			if err := server.Send(rpcMsg); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// AddOffer creates a new BOLT 12 offer issued by our node.
+func (r *rpcServer) AddOffer(ctx context.Context,
+	req *lnrpc.AddOfferRequest) (*lnrpc.AddOfferResponse, error) {
+
+	if r.server.offerMgr == nil {
+		return nil, errOnionMessagesDisabled
+	}
+
+	if req.AmtMsat < 0 {
+		return nil, errors.New("amount must not be negative")
+	}
+
+	offer := &offers.Offer{
+		Amount:      lnwire.MilliBronees(req.AmtMsat),
+		Description: req.Description,
+		Issuer:      req.Issuer,
+	}
+	if req.AbsoluteExpiry != 0 {
+		offer.AbsoluteExpiry = time.Unix(req.AbsoluteExpiry, 0)
+	}
+	if req.AllowQuantity {
+		quantityMax := req.QuantityMax
+		offer.QuantityMax = &quantityMax
+	}
+
+	stored, err := r.server.offerMgr.CreateOffer(offer)
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, err := stored.Offer.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.AddOfferResponse{
+		OfferId: stored.ID[:],
+		Offer:   encoded,
+	}, nil
+}
+
+// ListOffers returns all offers created by our node.
+func (r *rpcServer) ListOffers(ctx context.Context,
+	req *lnrpc.ListOffersRequest) (*lnrpc.ListOffersResponse, error) {
+
+	if r.server.offerMgr == nil {
+		return nil, errOnionMessagesDisabled
+	}
+
+	storedOffers, err := r.server.offerMgr.ListOffers()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.ListOffersResponse{
+		Offers: make([]*lnrpc.Offer, 0, len(storedOffers)),
+	}
+	for _, stored := range storedOffers {
+		rpcOffer, err := marshalOffer(stored.Offer)
+		if err != nil {
+			return nil, err
+		}
+		rpcOffer.CreationDate = stored.CreatedAt.Unix()
+		rpcOffer.Disabled = stored.Disabled
+
+		resp.Offers = append(resp.Offers, rpcOffer)
+	}
+
+	return resp, nil
+}
+
+// DisableOffer stops answering invoice requests for an offer.
+func (r *rpcServer) DisableOffer(ctx context.Context,
+	req *lnrpc.DisableOfferRequest) (*lnrpc.DisableOfferResponse, error) {
+
+	if r.server.offerMgr == nil {
+		return nil, errOnionMessagesDisabled
+	}
+
+	if len(req.OfferId) != 32 {
+		return nil, fmt.Errorf("offer id must be 32 bytes, got %v",
+			len(req.OfferId))
+	}
+
+	var id [32]byte
+	copy(id[:], req.OfferId)
+	if err := r.server.offerMgr.DisableOffer(id); err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.DisableOfferResponse{}, nil
+}
+
+// DecodeOffer decodes an lno encoded BOLT 12 offer.
+func (r *rpcServer) DecodeOffer(ctx context.Context,
+	req *lnrpc.DecodeOfferRequest) (*lnrpc.Offer, error) {
+
+	offer, err := offers.DecodeOffer(req.Offer)
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalOffer(offer)
+}
+
+// marshalOffer converts an offer into its RPC representation.
+func marshalOffer(offer *offers.Offer) (*lnrpc.Offer, error) {
+	id, err := offer.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, err := offer.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	rpcOffer := &lnrpc.Offer{
+		OfferId:     id[:],
+		Offer:       encoded,
+		AmtMsat:     int64(offer.Amount),
+		Description: offer.Description,
+		Issuer:      offer.Issuer,
+		NumPaths:    uint32(len(offer.Paths)),
+	}
+	if offer.IssuerID != nil {
+		rpcOffer.IssuerId = offer.IssuerID.SerializeCompressed()
+	}
+	if !offer.AbsoluteExpiry.IsZero() {
+		rpcOffer.AbsoluteExpiry = offer.AbsoluteExpiry.Unix()
+	}
+	if offer.QuantityMax != nil {
+		rpcOffer.AllowQuantity = true
+		rpcOffer.QuantityMax = *offer.QuantityMax
+	}
+
+	return rpcOffer, nil
+}
+
+// parsePubKeys parses a list of serialized public keys.
+func parsePubKeys(keys [][]byte) ([]*bronec.PublicKey, error) {
+	pubKeys := make([]*bronec.PublicKey, len(keys))
+	for i, key := range keys {
+		pubKey, err := bronec.ParsePubKey(key, bronec.S256())
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key %x: %v", key,
+				err)
+		}
+		pubKeys[i] = pubKey
+	}
+
+	return pubKeys, nil
+}
+
 // rpcInitiator returns the correct lnrpc initiator for channels where we have
 // a record of the opening channel.
 func rpcInitiator(isInitiator bool) lnrpc.Initiator {
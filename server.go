@@ -17,6 +17,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/brronsuite/broln/aliasmgr"
 	"github.com/brronsuite/broln/autopilot"
 	"github.com/brronsuite/broln/brontide"
 	"github.com/brronsuite/broln/cert"
@@ -41,20 +42,26 @@ import (
 	"github.com/brronsuite/broln/lncfg"
 	"github.com/brronsuite/broln/lnpeer"
 	"github.com/brronsuite/broln/lnrpc"
+	"github.com/brronsuite/broln/lnrpc/invoicesrpc"
 	"github.com/brronsuite/broln/lnrpc/routerrpc"
+	"github.com/brronsuite/broln/lntypes"
 	"github.com/brronsuite/broln/lnwallet"
 	"github.com/brronsuite/broln/lnwallet/chainfee"
 	"github.com/brronsuite/broln/lnwallet/rpcwallet"
 	"github.com/brronsuite/broln/lnwire"
 	"github.com/brronsuite/broln/nat"
 	"github.com/brronsuite/broln/netann"
+	"github.com/brronsuite/broln/offers"
+	"github.com/brronsuite/broln/onionmsg"
 	"github.com/brronsuite/broln/peer"
 	"github.com/brronsuite/broln/peernotifier"
 	"github.com/brronsuite/broln/pool"
 	"github.com/brronsuite/broln/queue"
 	"github.com/brronsuite/broln/routing"
+	"github.com/brronsuite/broln/routing/feemanager"
 	"github.com/brronsuite/broln/routing/localchans"
 	"github.com/brronsuite/broln/routing/route"
+	"github.com/brronsuite/broln/routing/trampoline"
 	"github.com/brronsuite/broln/subscribe"
 	"github.com/brronsuite/broln/sweep"
 	"github.com/brronsuite/broln/ticker"
@@ -64,7 +71,9 @@ import (
 	"github.com/brronsuite/broln/watchtower/wtclient"
 	"github.com/brronsuite/broln/watchtower/wtpolicy"
 	"github.com/brronsuite/broln/watchtower/wtserver"
+	"github.com/brronsuite/broln/zpay32"
 	"github.com/brronsuite/brond/bronec"
+	"github.com/brronsuite/brond/bronec/schnorr"
 	"github.com/brronsuite/brond/chaincfg/chainhash"
 	"github.com/brronsuite/brond/connmgr"
 	"github.com/brronsuite/brond/txscript"
@@ -243,6 +252,10 @@ type server struct {
 
 	chanStateDB *channeldb.ChannelStateDB
 
+	// aliasMgr manages the SCID aliases of zero-conf and scid-alias
+	// channels.
+	aliasMgr *aliasmgr.Manager
+
 	addrSource chanbackup.AddressSource
 
 	// miscDB is the DB that contains all "other" databases within the main
@@ -275,6 +288,12 @@ type server struct {
 
 	localChanMgr *localchans.Manager
 
+	feeMgr *feemanager.Manager
+
+	// trampolineForwarder forwards trampoline payments on behalf of their
+	// senders. It is nil if trampoline forwarding is disabled.
+	trampolineForwarder *trampoline.Forwarder
+
 	utxoNursery *contractcourt.UtxoNursery
 
 	sweeper *sweep.UtxoSweeper
@@ -313,6 +332,20 @@ type server struct {
 	// channelNotifier to be notified of newly opened and closed channels.
 	chanSubSwapper *chanbackup.SubSwapper
 
+	// chanBackupOpts are the options used to create static channel
+	// backups.
+	chanBackupOpts []chanbackup.SingleOption
+
+	// peerStorage hands our latest channel backup to the peers that offer
+	// to store it for us. It is nil if peer storage is disabled.
+	peerStorage *peerStorageSwapper
+
+	// restoringChans is the set of channels we're currently restoring
+	// from backups handed back by our peers, which keeps us from
+	// restoring a channel twice if several peers hand it back at once.
+	restoringChans    map[wire.OutPoint]struct{}
+	restoringChansMtx sync.Mutex
+
 	// chanEventStore tracks the behaviour of channels and their remote peers to
 	// provide insights into their health and performance.
 	chanEventStore *chanfitness.ChannelEventStore
@@ -324,6 +357,15 @@ type server struct {
 
 	customMessageServer *subscribe.Server
 
+	// onionMessenger relays onion messages for our peers and delivers the
+	// ones addressed to us. It is nil if onion messages are disabled.
+	onionMessenger *onionmsg.Messenger
+
+	// offerMgr creates BOLT 12 offers, answers the invoice requests for
+	// them and fetches invoices for the offers we pay. It is nil if onion
+	// messages are disabled.
+	offerMgr *offers.Manager
+
 	quit chan struct{}
 
 	wg sync.WaitGroup
@@ -536,6 +578,15 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		NoAnchors:                cfg.ProtocolOptions.NoAnchorCommitments(),
 		NoWumbo:                  !cfg.ProtocolOptions.Wumbo(),
 		NoScriptEnforcementLease: cfg.ProtocolOptions.NoScriptEnforcementLease(),
+		NoOptionScidAlias:        !cfg.ProtocolOptions.ScidAlias(),
+		NoZeroConf:               !cfg.ProtocolOptions.ZeroConf(),
+		NoOnionMessages:          !cfg.ProtocolOptions.OnionMessages(),
+		NoRouteBlinding:          !cfg.ProtocolOptions.RouteBlinding(),
+		NoDualFund:               !cfg.ProtocolOptions.DualFund(),
+		NoSplice:                 !cfg.ProtocolOptions.Splice(),
+		NoRbfCoopClose:           !cfg.ProtocolOptions.RbfCoopClose(),
+		NoTrampolineRouting:      !cfg.Trampoline.Forward,
+		NoPeerStorage:            !cfg.ProtocolOptions.PeerStorage(),
 	})
 	if err != nil {
 		return nil, err
@@ -576,7 +627,15 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 
 		// TODO(roasbeef): derive proper onion key based on rotation
 		// schedule
-		sphinx: hop.NewOnionProcessor(sphinxRouter),
+		sphinx: hop.NewOnionProcessor(
+			sphinxRouter, nodeKeyECDH,
+			func(blindedKey keychain.SingleKeyECDH) *sphinx.Router {
+				return sphinx.NewRouter(
+					blindedKey, cfg.ActiveNetParams.Params,
+					replayLog,
+				)
+			},
+		),
 
 		torController: torController,
 
@@ -602,6 +661,15 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		quit:       make(chan struct{}),
 	}
 
+	if cfg.ProtocolOptions.OnionMessages() {
+		s.onionMessenger = onionmsg.New(&onionmsg.Config{
+			NodeKey:       nodeKeyECDH,
+			SendToPeer:    s.sendOnionMessage,
+			MaxRelayBurst: onionmsg.DefaultMaxRelayBurst,
+			RelayInterval: onionmsg.DefaultRelayInterval,
+		})
+	}
+
 	s.witnessBeacon = &preimageBeacon{
 		wCache:      dbs.ChanStateDB.NewWitnessCache(),
 		subscribers: make(map[uint64]*preimageSubscriber),
@@ -625,6 +693,11 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 	thresholdSats := bronutil.Amount(cfg.DustThreshold)
 	thresholdMSats := lnwire.NewMSatFromBroneess(thresholdSats)
 
+	s.aliasMgr, err = aliasmgr.NewManager(dbs.ChanStateDB)
+	if err != nil {
+		return nil, err
+	}
+
 	s.htlcSwitch, err = htlcswitch.New(htlcswitch.Config{
 		DB:                   dbs.ChanStateDB,
 		FetchAllOpenChannels: s.chanStateDB.FetchAllOpenChannels,
@@ -657,11 +730,19 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		Clock:                  clock.NewDefaultClock(),
 		HTLCExpiry:             htlcswitch.DefaultHTLCExpiry,
 		DustThreshold:          thresholdMSats,
+		FindBaseScid:           s.aliasMgr.FindBaseSCID,
 	}, uint32(currentHeight))
 	if err != nil {
 		return nil, err
 	}
-	s.interceptableSwitch = htlcswitch.NewInterceptableSwitch(s.htlcSwitch)
+	s.interceptableSwitch = htlcswitch.NewInterceptableSwitch(
+		&htlcswitch.InterceptableSwitchConfig{
+			Switch:             s.htlcSwitch,
+			Notifier:           s.cc.ChainNotifier,
+			CltvRejectDelta:    lncfg.DefaultFinalCltvRejectDelta,
+			RequireInterceptor: cfg.RequireInterceptor,
+		},
+	)
 
 	chanStatusMgrCfg := &netann.ChanStatusConfig{
 		ChanStatusSampleInterval: cfg.ChanStatusSampleInterval,
@@ -892,6 +973,20 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 
 	s.controlTower = routing.NewControlTower(paymentControl)
 
+	trampolineCfg := routing.TrampolineConfig{
+		Pay:             cfg.Trampoline.Pay,
+		BaseFee:         cfg.Trampoline.FeeBaseMsat,
+		ProportionalFee: cfg.Trampoline.FeeRatePPM,
+		CltvExpiryDelta: cfg.Trampoline.CltvDelta,
+	}
+	if cfg.Trampoline.Node != "" {
+		node, err := route.NewVertexFromStr(cfg.Trampoline.Node)
+		if err != nil {
+			return nil, err
+		}
+		trampolineCfg.Node = &node
+	}
+
 	strictPruning := (cfg.Brocoin.Node == "neutrino" ||
 		cfg.Routing.StrictZombiePruning)
 	s.chanRouter, err = routing.New(routing.Config{
@@ -912,11 +1007,42 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		PathFindingConfig:   pathFindingConfig,
 		Clock:               clock.NewDefaultClock(),
 		StrictZombiePruning: strictPruning,
+		Trampoline:          trampolineCfg,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("can't create router: %v", err)
 	}
 
+	if cfg.Trampoline.Forward {
+		s.trampolineForwarder, err = trampoline.New(&trampoline.Config{
+			NodeKey:     nodeKeyECDH,
+			SendPayment: s.chanRouter.SendPaymentAsync,
+			SubscribePayment: func(hash lntypes.Hash) (
+				<-chan interface{}, func(), error) {
+
+				sub, err := s.controlTower.SubscribePayment(hash)
+				if err != nil {
+					return nil, nil, err
+				}
+
+				return sub.Updates, sub.Close, nil
+			},
+			BestHeight: s.htlcSwitch.BestHeight,
+			BaseFee: lnwire.MilliBronees(
+				cfg.Trampoline.ForwardFeeBaseMsat,
+			),
+			FeeRate:              cfg.Trampoline.ForwardFeeRatePPM,
+			CltvDelta:            cfg.Trampoline.ForwardCltvDelta,
+			FinalCltvRejectDelta: lncfg.DefaultFinalCltvRejectDelta,
+			MppTimeout:           cfg.Trampoline.MppTimeout,
+			PaymentTimeout:       trampoline.DefaultPaymentTimeout,
+			Clock:                clock.NewDefaultClock(),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	chanSeries := discovery.NewChanSeries(s.graphDB)
 	gossipMessageStore, err := discovery.NewMessageStore(dbs.ChanStateDB)
 	if err != nil {
@@ -963,6 +1089,54 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		FetchChannel:              s.chanStateDB.FetchChannel,
 	}
 
+	s.feeMgr, err = feemanager.New(&feemanager.Config{
+		MinFeeRate:             cfg.FeeManager.MinFeeRate,
+		MaxFeeRate:             cfg.FeeManager.MaxFeeRate,
+		ForwardingWindow:       cfg.FeeManager.ForwardingWindow,
+		MinUpdateInterval:      cfg.FeeManager.MinUpdateInterval,
+		MinChangeRatio:         cfg.FeeManager.MinChangeRatio,
+		FetchAllOpenChannels:   s.chanStateDB.FetchAllOpenChannels,
+		ForAllOutgoingChannels: s.chanRouter.ForAllOutgoingChannels,
+		QueryForwardingLog:     s.chanStateDB.ForwardingLog().Query,
+		UpdatePolicy:           s.localChanMgr.UpdatePolicy,
+		Clock:                  clock.NewDefaultClock(),
+		Ticker:                 ticker.New(cfg.FeeManager.Interval),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Offers are exchanged over onion messages, so we can only support
+	// them if onion messages are enabled.
+	if s.onionMessenger != nil {
+		offerStore, err := offers.NewStore(dbs.ChanStateDB)
+		if err != nil {
+			return nil, err
+		}
+
+		s.offerMgr = offers.NewManager(&offers.Config{
+			Store:     offerStore,
+			NodeID:    nodeKeyDesc.PubKey,
+			ChainHash: *cfg.ActiveNetParams.GenesisHash,
+			SignDigest: func(digest [32]byte) (*schnorr.Signature,
+				error) {
+
+				privKey, err := cc.KeyRing.DerivePrivKey(
+					*nodeKeyDesc,
+				)
+				if err != nil {
+					return nil, err
+				}
+
+				return schnorr.Sign(privKey, digest[:])
+			},
+			Messenger:      s.onionMessenger,
+			AddInvoice:     s.addOfferInvoice,
+			RequestTimeout: offers.DefaultRequestTimeout,
+			Now:            time.Now,
+		})
+	}
+
 	utxnStore, err := contractcourt.NewNurseryStore(
 		s.cfg.ActiveNetParams.GenesisHash, dbs.ChanStateDB,
 	)
@@ -982,6 +1156,11 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		return nil, err
 	}
 
+	feeFunction, err := sweep.FeeFunctionByName(cfg.Sweeper.FeeFunction)
+	if err != nil {
+		return nil, err
+	}
+
 	s.sweeper = sweep.New(&sweep.UtxoSweeperConfig{
 		FeeEstimator:   cc.FeeEstimator,
 		GenSweepScript: newSweepPkScriptGen(cc.Wallet),
@@ -996,7 +1175,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		MaxSweepAttempts:     sweep.DefaultMaxSweepAttempts,
 		NextAttemptDeltaFunc: sweep.DefaultNextAttemptDeltaFunc,
 		MaxFeeRate:           sweep.DefaultMaxFeeRate,
-		FeeRateBucketSize:    sweep.DefaultFeeRateBucketSize,
+		FeeFunction:          feeFunction,
 	})
 
 	s.utxoNursery = contractcourt.NewUtxoNursery(&contractcourt.NurseryConfig{
@@ -1134,9 +1313,11 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		Registry:                      s.invoices,
 		NotifyClosedChannel:           s.channelNotifier.NotifyClosedChannelEvent,
 		NotifyFullyResolvedChannel:    s.channelNotifier.NotifyFullyResolvedChannelEvent,
+		NotifySpliceConfirmed:         s.notifySpliceConfirmed,
 		OnionProcessor:                s.sphinx,
 		PaymentsExpirationGracePeriod: cfg.PaymentsExpirationGracePeriod,
 		IsForwardedHTLC:               s.htlcSwitch.IsForwardedHTLC,
+		QueryIncomingCircuit:          s.htlcSwitch.QueryIncomingCircuit,
 		Clock:                         clock.NewDefaultClock(),
 		SubscribeBreachComplete:       s.breachArbiter.SubscribeBreachComplete,
 	}, dbs.ChanStateDB)
@@ -1342,26 +1523,86 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		RegisteredChains:              cfg.registeredChains,
 		MaxAnchorsCommitFeeRate: chainfee.SatPerKVByte(
 			s.cfg.MaxCommitFeeRateAnchors * 1000).FeePerKWeight(),
+		AliasManager: s.aliasMgr,
+		DeleteAliasEdge: func(alias lnwire.ShortChannelID) error {
+			err := s.graphDB.DeleteChannelEdges(
+				false, alias.ToUint64(),
+			)
+			if err == channeldb.ErrEdgeNotFound {
+				return nil
+			}
+			return err
+		},
 	})
 	if err != nil {
 		return nil, err
 	}
 
 	// Next, we'll assemble the sub-system that will maintain an on-disk
-	// static backup of the latest channel state.
+	// static backup of the latest channel state. If the backups carry the
+	// commitment state, the links also update them on every new
+	// commitment, before revoking the prior one.
+	if cfg.BackupCommitState {
+		s.chanBackupOpts = append(
+			s.chanBackupOpts, chanbackup.WithCommitState(),
+		)
+	}
 	chanNotifier := &channelNotifier{
 		chanNotifier: s.channelNotifier,
 		addrs:        dbs.ChanStateDB,
 	}
-	backupFile := chanbackup.NewMultiFile(cfg.BackupFilePath)
+	var backupSwapper chanbackup.Swapper = chanbackup.NewMultiFile(
+		cfg.BackupFilePath,
+	)
+
+	// If peer storage is enabled, every new backup is also handed to the
+	// peers that offer to store it for us.
+	if cfg.ProtocolOptions.PeerStorage() {
+		s.peerStorage = &peerStorageSwapper{
+			Swapper: backupSwapper,
+			keyRing: s.cc.KeyRing,
+			peers:   s.Peers,
+		}
+		backupSwapper = s.peerStorage
+		s.restoringChans = make(map[wire.OutPoint]struct{})
+	}
+
+	// Every new backup is also pushed to the configured remote sinks.
+	var backupSinks []chanbackup.Sink
+	if s3Cfg := cfg.RemoteBackup.S3; s3Cfg.Endpoint != "" {
+		backupSinks = append(backupSinks, chanbackup.NewS3Sink(
+			chanbackup.S3SinkConfig{
+				Endpoint:        s3Cfg.Endpoint,
+				Region:          s3Cfg.Region,
+				Bucket:          s3Cfg.Bucket,
+				Key:             s3Cfg.Key,
+				AccessKeyID:     s3Cfg.AccessKeyID,
+				SecretAccessKey: s3Cfg.SecretAccessKey,
+			},
+		))
+	}
+	if cfg.RemoteBackup.WebDAV.URL != "" {
+		backupSinks = append(backupSinks, chanbackup.NewWebDAVSink(
+			cfg.RemoteBackup.WebDAV.URL,
+			cfg.RemoteBackup.WebDAV.User,
+			cfg.RemoteBackup.WebDAV.Password,
+		))
+	}
+	if cfg.RemoteBackup.Hook != "" {
+		backupSinks = append(backupSinks, chanbackup.NewHookSink(
+			cfg.RemoteBackup.Hook,
+		))
+	}
+
 	startingChans, err := chanbackup.FetchStaticChanBackups(
-		s.chanStateDB, s.addrSource,
+		s.chanStateDB, s.addrSource, s.chanBackupOpts...,
 	)
 	if err != nil {
 		return nil, err
 	}
 	s.chanSubSwapper, err = chanbackup.NewSubSwapper(
-		startingChans, chanNotifier, s.cc.KeyRing, backupFile,
+		startingChans, chanNotifier, s.cc.KeyRing, backupSwapper,
+		s.chanBackupOpts, backupSinks...,
 	)
 	if err != nil {
 		return nil, err
@@ -1673,6 +1914,22 @@ func (s *server) Start() error {
 		}
 		cleanup = cleanup.add(s.customMessageServer.Stop)
 
+		if s.onionMessenger != nil {
+			if err := s.onionMessenger.Start(); err != nil {
+				startErr = err
+				return
+			}
+			cleanup = cleanup.add(s.onionMessenger.Stop)
+		}
+
+		if s.offerMgr != nil {
+			if err := s.offerMgr.Start(); err != nil {
+				startErr = err
+				return
+			}
+			cleanup = cleanup.add(s.offerMgr.Stop)
+		}
+
 		if s.hostAnn != nil {
 			if err := s.hostAnn.Start(); err != nil {
 				startErr = err
@@ -1724,6 +1981,20 @@ func (s *server) Start() error {
 		}
 		cleanup = cleanup.add(s.channelNotifier.Stop)
 
+		// The backup blobs we hold for our peers are pruned once we
+		// no longer have any channels with them.
+		if s.peerStorage != nil {
+			chanSub, err := s.channelNotifier.
+				SubscribeChannelEvents()
+			if err != nil {
+				startErr = err
+				return
+			}
+
+			s.wg.Add(1)
+			go s.prunePeerStorage(chanSub)
+		}
+
 		if err := s.peerNotifier.Start(); err != nil {
 			startErr = err
 			return
@@ -1794,6 +2065,14 @@ func (s *server) Start() error {
 		}
 		cleanup = cleanup.add(s.chanRouter.Stop)
 
+		if s.trampolineForwarder != nil {
+			if err := s.trampolineForwarder.Start(); err != nil {
+				startErr = err
+				return
+			}
+			cleanup = cleanup.add(s.trampolineForwarder.Stop)
+		}
+
 		if err := s.invoices.Start(); err != nil {
 			startErr = err
 			return
@@ -1812,6 +2091,12 @@ func (s *server) Start() error {
 		}
 		cleanup = cleanup.add(s.htlcSwitch.Stop)
 
+		if err := s.interceptableSwitch.Start(); err != nil {
+			startErr = err
+			return
+		}
+		cleanup = cleanup.add(s.interceptableSwitch.Stop)
+
 		if err := s.chanStatusMgr.Start(); err != nil {
 			startErr = err
 			return
@@ -1827,6 +2112,16 @@ func (s *server) Start() error {
 			return nil
 		})
 
+		// The fee manager only adjusts fee rates on a schedule if it
+		// is active. Otherwise, it can still be used through the RPC.
+		if s.cfg.FeeManager.Active {
+			if err := s.feeMgr.Start(); err != nil {
+				startErr = err
+				return
+			}
+			cleanup = cleanup.add(s.feeMgr.Stop)
+		}
+
 		s.missionControl.RunStoreTicker()
 		cleanup.add(func() error {
 			s.missionControl.StopStoreTicker()
@@ -2013,6 +2308,10 @@ func (s *server) Stop() error {
 
 		// Shutdown the wallet, funding manager, and the rpc server.
 		s.chanStatusMgr.Stop()
+		if err := s.interceptableSwitch.Stop(); err != nil {
+			srvrLog.Warnf("failed to stop interceptable "+
+				"switch: %v", err)
+		}
 		if err := s.htlcSwitch.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop htlcSwitch: %v", err)
 		}
@@ -2022,6 +2321,12 @@ func (s *server) Stop() error {
 		if err := s.invoices.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop invoices: %v", err)
 		}
+		if s.trampolineForwarder != nil {
+			if err := s.trampolineForwarder.Stop(); err != nil {
+				srvrLog.Warnf("failed to stop trampoline "+
+					"forwarder: %v", err)
+			}
+		}
 		if err := s.chanRouter.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop chanRouter: %v", err)
 		}
@@ -2060,6 +2365,9 @@ func (s *server) Stop() error {
 		}
 		s.chanEventStore.Stop()
 		s.missionControl.StopStoreTicker()
+		if err := s.feeMgr.Stop(); err != nil {
+			srvrLog.Warnf("failed to stop feeMgr: %v", err)
+		}
 
 		// Disconnect from each active peers to ensure that
 		// peerTerminationWatchers signal completion to each peer.
@@ -2104,6 +2412,20 @@ func (s *server) Stop() error {
 			}
 		}
 
+		if s.offerMgr != nil {
+			if err := s.offerMgr.Stop(); err != nil {
+				srvrLog.Warnf("unable to shutdown offer "+
+					"manager: %v", err)
+			}
+		}
+
+		if s.onionMessenger != nil {
+			if err := s.onionMessenger.Stop(); err != nil {
+				srvrLog.Warnf("unable to shutdown onion "+
+					"messenger: %v", err)
+			}
+		}
+
 		// Wait for all lingering goroutines to quit.
 		s.wg.Wait()
 
@@ -3050,6 +3372,23 @@ func (s *server) NotifyWhenOffline(peerPubKey [33]byte) <-chan struct{} {
 	return c
 }
 
+// notifySpliceConfirmed passes the splice of a channel that reached the depth
+// at which it can be locked to the peer of the channel, which locks the
+// splice once both parties sent splice_locked. If the peer isn't connected,
+// the confirmed splice is looked up once it reconnects.
+func (s *server) notifySpliceConfirmed(chanPoint wire.OutPoint,
+	remotePub *bronec.PublicKey, spliceTxid chainhash.Hash) {
+
+	peer, err := s.FindPeer(remotePub)
+	if err != nil {
+		srvrLog.Debugf("Peer of ChannelPoint(%v) with confirmed "+
+			"splice not connected: %v", chanPoint, err)
+		return
+	}
+
+	peer.HandleSpliceConfirmed(chanPoint, spliceTxid)
+}
+
 // FindPeer will return the peer that corresponds to the passed in public key.
 // This function is used by the funding manager, allowing it to update the
 // daemon's local representation of the remote peer.
@@ -3519,6 +3858,26 @@ func (s *server) peerConnected(conn net.Conn, connReq *connmgr.ConnReq,
 		Quit:                   s.quit,
 	}
 
+	if s.onionMessenger != nil {
+		pCfg.HandleOnionMessage = s.onionMessenger.HandleMessage
+	}
+
+	if s.trampolineForwarder != nil {
+		pCfg.ForwardTrampoline = s.trampolineForwarder.HandleHtlc
+	}
+
+	if s.cfg.BackupCommitState {
+		pCfg.BackupCommitState = s.backupCommitState
+	}
+
+	if s.peerStorage != nil {
+		pCfg.PeerStorageQuota = s.cfg.PeerStorageQuota
+		pCfg.StorePeerStorage = s.storePeerStorage
+		pCfg.FetchPeerStorage = s.fetchPeerStorage
+		pCfg.OurPeerStorage = s.peerStorage.Backup
+		pCfg.HandlePeerStorageRetrieval = s.handlePeerStorageRetrieval
+	}
+
 	copy(pCfg.PubKeyBytes[:], peerAddr.IdentityKey.SerializeCompressed())
 	copy(pCfg.ServerPubKey[:], s.identityECDH.PubKey().SerializeCompressed())
 
@@ -4267,6 +4626,20 @@ func (s *server) fetchNodeAdvertisedAddrs(pub *bronec.PublicKey) ([]net.Addr, er
 	return node.Addresses, nil
 }
 
+// backupCommitState updates the static backup of the given channel with the
+// latest local commitment of the channel, and returns once the backup has been
+// written to disk.
+func (s *server) backupCommitState(chanPoint wire.OutPoint) error {
+	backup, err := chanbackup.FetchBackupForChan(
+		chanPoint, s.chanStateDB, s.addrSource, s.chanBackupOpts...,
+	)
+	if err != nil {
+		return err
+	}
+
+	return s.chanSubSwapper.UpdateCommitState(*backup)
+}
+
 // fetchLastChanUpdate returns a function which is able to retrieve our latest
 // channel update for a target channel.
 func (s *server) fetchLastChanUpdate() func(lnwire.ShortChannelID) (
@@ -4302,6 +4675,13 @@ func (s *server) applyChannelUpdate(update *lnwire.ChannelUpdate) error {
 func (s *server) SendCustomMessage(peerPub [33]byte, msgType lnwire.MessageType,
 	data []byte) error {
 
+	// Reject messages outside of the custom range, or of a type reserved
+	// for our protocol messages, before waiting for the peer.
+	msg, err := lnwire.NewCustom(msgType, data)
+	if err != nil {
+		return err
+	}
+
 	peer, err := s.FindPeerByPubStr(string(peerPub[:]))
 	if err != nil {
 		return err
@@ -4316,14 +4696,104 @@ func (s *server) SendCustomMessage(peerPub [33]byte, msgType lnwire.MessageType,
 		return ErrServerShuttingDown
 	}
 
-	msg, err := lnwire.NewCustom(msgType, data)
+	// Send the message as low-priority. For now we assume that all
+	// application-defined message are low priority.
+	return peer.SendMessageLazy(true, msg)
+}
+
+// sendOnionMessage sends an onion message to the given peer. Unlike
+// SendCustomMessage, it doesn't wait for the peer to become active, as it is
+// also used to forward messages from within the read handler of another peer.
+func (s *server) sendOnionMessage(peerPub [33]byte, msg lnwire.Message) error {
+	peer, err := s.FindPeerByPubStr(string(peerPub[:]))
 	if err != nil {
 		return err
 	}
 
-	// Send the message as low-priority. For now we assume that all
-	// application-defined message are low priority.
-	return peer.SendMessageLazy(true, msg)
+	// Onion messages are low priority and we don't want to block on
+	// them.
+	return peer.SendMessageLazy(false, msg)
+}
+
+// addOfferInvoice adds an invoice for an invoice request for one of our offers
+// to the invoice registry. The invoice can only be paid through blinded paths,
+// which are returned together with its payment hash.
+func (s *server) addOfferInvoice(amt lnwire.MilliBronees, memo string,
+	expiry time.Duration) (lntypes.Hash, []*offers.PaymentPath, error) {
+
+	defaultDelta := s.cfg.Brocoin.TimeLockDelta
+	if s.cfg.registeredChains.PrimaryChain() == chainreg.LitecoinChain {
+		defaultDelta = s.cfg.Litecoin.TimeLockDelta
+	}
+
+	addInvoiceCfg := &invoicesrpc.AddInvoiceConfig{
+		AddInvoice:        s.invoices.AddInvoice,
+		IsChannelActive:   s.htlcSwitch.HasActiveLink,
+		GetAlias:          s.aliasMgr.GetPeerAlias,
+		ChainParams:       s.cfg.ActiveNetParams.Params,
+		NodeSigner:        s.nodeSigner,
+		DefaultCLTVExpiry: defaultDelta,
+		ChanDB:            s.chanStateDB,
+		Graph:             s.graphDB,
+		GenInvoiceFeatures: func() *lnwire.FeatureVector {
+			return s.featureMgr.Get(feature.SetInvoice)
+		},
+		GenAmpInvoiceFeatures: func() *lnwire.FeatureVector {
+			return s.featureMgr.Get(feature.SetInvoiceAmp)
+		},
+		BestHeight: func() (uint32, error) {
+			_, height, err := s.cc.ChainIO.GetBestBlock()
+			return uint32(height), err
+		},
+	}
+
+	hash, dbInvoice, err := invoicesrpc.AddInvoice(
+		context.Background(), addInvoiceCfg,
+		&invoicesrpc.AddInvoiceData{
+			Memo:   memo,
+			Value:  amt,
+			Expiry: int64(expiry / time.Second),
+			Blind:  true,
+		},
+	)
+	if err != nil {
+		return lntypes.Hash{}, nil, err
+	}
+
+	// The blinded paths were added to the payment request of the invoice,
+	// which is the only place they are stored.
+	payReq, err := zpay32.Decode(
+		string(dbInvoice.PaymentRequest), s.cfg.ActiveNetParams.Params,
+	)
+	if err != nil {
+		return lntypes.Hash{}, nil, err
+	}
+
+	paths := make([]*offers.PaymentPath, 0, len(payReq.BlindedPaymentPaths))
+	for _, path := range payReq.BlindedPaymentPaths {
+		features := lnwire.NewRawFeatureVector()
+		if path.Features != nil {
+			features = path.Features.RawFeatureVector
+		}
+
+		paths = append(paths, &offers.PaymentPath{
+			Path: path.Path,
+			PayInfo: &offers.PayInfo{
+				FeeBaseMsat:     path.FeeBaseMsat,
+				FeeRate:         path.FeeRate,
+				CltvExpiryDelta: path.CltvExpiryDelta,
+				HtlcMinimumMsat: lnwire.MilliBronees(
+					path.HTLCMinMsat,
+				),
+				HtlcMaximumMsat: lnwire.MilliBronees(
+					path.HTLCMaxMsat,
+				),
+				Features: features,
+			},
+		})
+	}
+
+	return *hash, paths, nil
 }
 
 // newSweepPkScriptGen creates closure that generates a new public key script
@@ -12,6 +12,7 @@ import (
 	"github.com/brronsuite/broln/lncfg"
 	"github.com/brronsuite/broln/lnrpc/autopilotrpc"
 	"github.com/brronsuite/broln/lnrpc/chainrpc"
+	"github.com/brronsuite/broln/lnrpc/feemanagerrpc"
 	"github.com/brronsuite/broln/lnrpc/invoicesrpc"
 	"github.com/brronsuite/broln/lnrpc/routerrpc"
 	"github.com/brronsuite/broln/lnrpc/signrpc"
@@ -22,6 +23,7 @@ import (
 	"github.com/brronsuite/broln/macaroons"
 	"github.com/brronsuite/broln/netann"
 	"github.com/brronsuite/broln/routing"
+	"github.com/brronsuite/broln/routing/feemanager"
 	"github.com/brronsuite/broln/sweep"
 	"github.com/brronsuite/broln/watchtower"
 	"github.com/brronsuite/broln/watchtower/wtclient"
@@ -74,6 +76,10 @@ type subRPCServerConfigs struct {
 	// instance within broln in order to add, remove, list registered client
 	// towers, etc.
 	WatchtowerClientRPC *wtclientrpc.Config `group:"wtclientrpc" namespace:"wtclientrpc"`
+
+	// FeeManagerRPC is a sub-RPC server that exposes the automatic fee
+	// manager, which adjusts the fee rates of our channels.
+	FeeManagerRPC *feemanagerrpc.Config `group:"feemanagerrpc" namespace:"feemanagerrpc"`
 }
 
 // PopulateDependencies attempts to iterate through all the sub-server configs
@@ -86,6 +92,7 @@ func (s *subRPCServerConfigs) PopulateDependencies(cfg *Config,
 	cc *chainreg.ChainControl,
 	networkDir string, macService *macaroons.Service,
 	atpl *autopilot.Manager,
+	feeMgr *feemanager.Manager,
 	invoiceRegistry *invoices.InvoiceRegistry,
 	htlcSwitch *htlcswitch.Switch,
 	activeNetParams *chaincfg.Params,
@@ -101,6 +108,7 @@ func (s *subRPCServerConfigs) PopulateDependencies(cfg *Config,
 	tcpResolver lncfg.TCPResolver,
 	genInvoiceFeatures func() *lnwire.FeatureVector,
 	genAmpInvoiceFeatures func() *lnwire.FeatureVector,
+	getAlias func(lnwire.ChannelID) (lnwire.ShortChannelID, error),
 	rpcLogger bronlog.Logger) error {
 
 	// First, we'll use reflect to obtain a version of the config struct
@@ -142,6 +150,9 @@ func (s *subRPCServerConfigs) PopulateDependencies(cfg *Config,
 			subCfgValue.FieldByName("KeyRing").Set(
 				reflect.ValueOf(cc.KeyRing),
 			)
+			subCfgValue.FieldByName("MuSig2Signer").Set(
+				reflect.ValueOf(cc.MuSig2Signer),
+			)
 
 		case *walletrpc.Config:
 			subCfgValue := extractReflectValue(subCfg)
@@ -181,6 +192,13 @@ func (s *subRPCServerConfigs) PopulateDependencies(cfg *Config,
 				reflect.ValueOf(atpl),
 			)
 
+		case *feemanagerrpc.Config:
+			subCfgValue := extractReflectValue(subCfg)
+
+			subCfgValue.FieldByName("Manager").Set(
+				reflect.ValueOf(feeMgr),
+			)
+
 		case *chainrpc.Config:
 			subCfgValue := extractReflectValue(subCfg)
 
@@ -209,6 +227,9 @@ func (s *subRPCServerConfigs) PopulateDependencies(cfg *Config,
 			subCfgValue.FieldByName("IsChannelActive").Set(
 				reflect.ValueOf(htlcSwitch.HasActiveLink),
 			)
+			subCfgValue.FieldByName("GetAlias").Set(
+				reflect.ValueOf(getAlias),
+			)
 			subCfgValue.FieldByName("ChainParams").Set(
 				reflect.ValueOf(activeNetParams),
 			)
@@ -235,6 +256,14 @@ func (s *subRPCServerConfigs) PopulateDependencies(cfg *Config,
 				reflect.ValueOf(genAmpInvoiceFeatures),
 			)
 
+			bestHeight := func() (uint32, error) {
+				_, height, err := cc.ChainIO.GetBestBlock()
+				return uint32(height), err
+			}
+			subCfgValue.FieldByName("BestHeight").Set(
+				reflect.ValueOf(bestHeight),
+			)
+
 		// RouterRPC isn't conditionally compiled and doesn't need to be
 		// populated using reflection.
 		case *routerrpc.Config:
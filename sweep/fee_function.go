@@ -0,0 +1,98 @@
+package sweep
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/brronsuite/broln/lnwallet/chainfee"
+)
+
+const (
+	// FeeFunctionLinear is the name of the fee function that raises the
+	// fee rate by the same amount every block.
+	FeeFunctionLinear = "linear"
+
+	// FeeFunctionCubicDelay is the name of the fee function that raises
+	// the fee rate slowly at first and steeply close to the deadline.
+	FeeFunctionCubicDelay = "cubic-delay"
+
+	// FeeFunctionCubicEager is the name of the fee function that raises
+	// the fee rate steeply at first and slowly close to the deadline.
+	FeeFunctionCubicEager = "cubic-eager"
+
+	// DefaultFeeFunction is the name of the fee function used if none is
+	// configured.
+	DefaultFeeFunction = FeeFunctionLinear
+)
+
+// FeeFunction determines the fee rate of an input that needs to be swept
+// before a deadline. The fee rate moves from the start fee rate towards the
+// end fee rate, which is the highest fee rate the budget of the input allows.
+// The width is the number of blocks between the height the input was offered
+// to the sweeper at and its deadline, the position is the number of blocks
+// that have passed since then. Once the position reaches the width, the end
+// fee rate is returned.
+type FeeFunction func(start, end chainfee.SatPerKWeight, width,
+	position int32) chainfee.SatPerKWeight
+
+var (
+	// LinearFeeFunction raises the fee rate by the same amount every
+	// block.
+	LinearFeeFunction = newFeeFunction(func(x float64) float64 {
+		return x
+	})
+
+	// CubicDelayFeeFunction raises the fee rate slowly at first, which
+	// gives the input a chance to confirm at a low fee rate, and steeply
+	// close to the deadline.
+	CubicDelayFeeFunction = newFeeFunction(func(x float64) float64 {
+		return math.Pow(x, 3)
+	})
+
+	// CubicEagerFeeFunction raises the fee rate steeply at first, which
+	// gets the input confirmed early, and slowly close to the deadline.
+	CubicEagerFeeFunction = newFeeFunction(func(x float64) float64 {
+		return 1 - math.Pow(1-x, 3)
+	})
+)
+
+// newFeeFunction returns a fee function that interpolates between the start
+// and end fee rate along the given curve. The curve maps the share of the
+// width that has passed to the share of the distance between the start and
+// end fee rate that should be covered, both within [0, 1].
+func newFeeFunction(curve func(float64) float64) FeeFunction {
+	return func(start, end chainfee.SatPerKWeight, width,
+		position int32) chainfee.SatPerKWeight {
+
+		switch {
+		case position >= width:
+			return end
+
+		case position <= 0:
+			return start
+		}
+
+		progress := curve(float64(position) / float64(width))
+
+		return start + chainfee.SatPerKWeight(
+			progress*float64(end-start),
+		)
+	}
+}
+
+// FeeFunctionByName returns the fee function with the given name.
+func FeeFunctionByName(name string) (FeeFunction, error) {
+	switch name {
+	case FeeFunctionLinear:
+		return LinearFeeFunction, nil
+
+	case FeeFunctionCubicDelay:
+		return CubicDelayFeeFunction, nil
+
+	case FeeFunctionCubicEager:
+		return CubicEagerFeeFunction, nil
+
+	default:
+		return nil, fmt.Errorf("unknown fee function %q", name)
+	}
+}
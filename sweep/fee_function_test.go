@@ -0,0 +1,103 @@
+package sweep
+
+import (
+	"testing"
+
+	"github.com/brronsuite/broln/lnwallet/chainfee"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFeeFunctions asserts that the fee functions move from the start to the
+// end fee rate along their curves.
+func TestFeeFunctions(t *testing.T) {
+	t.Parallel()
+
+	const (
+		start = chainfee.SatPerKWeight(1000)
+		end   = chainfee.SatPerKWeight(9000)
+		width = 10
+	)
+
+	testCases := []struct {
+		name     string
+		position int32
+		linear   chainfee.SatPerKWeight
+		delay    chainfee.SatPerKWeight
+		eager    chainfee.SatPerKWeight
+	}{
+		{
+			name:     "before start",
+			position: -1,
+			linear:   start,
+			delay:    start,
+			eager:    start,
+		},
+		{
+			name:     "start",
+			position: 0,
+			linear:   start,
+			delay:    start,
+			eager:    start,
+		},
+		{
+			name:     "halfway",
+			position: 5,
+			linear:   5000,
+			delay:    2000,
+			eager:    8000,
+		},
+		{
+			name:     "deadline",
+			position: width,
+			linear:   end,
+			delay:    end,
+			eager:    end,
+		},
+		{
+			name:     "past deadline",
+			position: width + 5,
+			linear:   end,
+			delay:    end,
+			eager:    end,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(
+				t, test.linear, LinearFeeFunction(
+					start, end, width, test.position,
+				),
+			)
+			require.Equal(
+				t, test.delay, CubicDelayFeeFunction(
+					start, end, width, test.position,
+				),
+			)
+			require.Equal(
+				t, test.eager, CubicEagerFeeFunction(
+					start, end, width, test.position,
+				),
+			)
+		})
+	}
+}
+
+// TestFeeFunctionByName asserts that fee functions are looked up by their
+// configured names.
+func TestFeeFunctionByName(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{
+		FeeFunctionLinear, FeeFunctionCubicDelay, FeeFunctionCubicEager,
+	} {
+		feeFunction, err := FeeFunctionByName(name)
+		require.NoError(t, err)
+		require.NotNil(t, feeFunction)
+	}
+
+	_, err := FeeFunctionByName("exponential")
+	require.Error(t, err)
+}
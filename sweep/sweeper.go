@@ -26,17 +26,11 @@ const (
 	// sat/vbyte.
 	DefaultMaxFeeRate = chainfee.FeePerKwFloor * 1e4
 
-	// DefaultFeeRateBucketSize is the default size of fee rate buckets
-	// we'll use when clustering inputs into buckets with similar fee rates
-	// within the UtxoSweeper.
-	//
-	// Given a minimum relay fee rate of 1 sat/vbyte, a multiplier of 10
-	// would result in the following fee rate buckets up to the maximum fee
-	// rate:
-	//
-	//   #1: min = 1 sat/vbyte, max = 10 sat/vbyte
-	//   #2: min = 11 sat/vbyte, max = 20 sat/vbyte...
-	DefaultFeeRateBucketSize = 10
+	// DefaultDeadlineDelta is the number of blocks after which inputs that
+	// were offered with a budget but without a deadline height should be
+	// swept. Their fee rate is raised along the fee function as if their
+	// deadline was this many blocks after they were offered.
+	DefaultDeadlineDelta = 144
 )
 
 var (
@@ -72,7 +66,10 @@ var (
 type Params struct {
 	// Fee is the fee preference of the client who requested the input to be
 	// swept. If a confirmation target is specified, then we'll map it into
-	// a fee rate whenever we attempt to cluster inputs for a sweep.
+	// a fee rate whenever we attempt to cluster inputs for a sweep. For
+	// inputs with a deadline, it is the fee rate the fee function starts
+	// at, and it may be left empty to start at the fee rate estimated to
+	// confirm the input within the blocks left until the deadline.
 	Fee FeePreference
 
 	// Force indicates whether the input should be swept regardless of
@@ -82,6 +79,20 @@ type Params struct {
 	// ExclusiveGroup is an identifier that, if set, prevents other inputs
 	// with the same identifier from being batched together.
 	ExclusiveGroup *uint64
+
+	// Budget is the maximum fee the input may pay for its sweep. It bounds
+	// the fee rate the fee function raises the input's fee rate to as its
+	// deadline approaches. If no deadline height is set, the input is
+	// given a deadline DefaultDeadlineDelta blocks after it was offered.
+	// If zero, the fee rate of the input isn't raised beyond its fee
+	// preference.
+	Budget bronutil.Amount
+
+	// DeadlineHeight is the block height by which the input should be
+	// swept. If set, the input is re-swept every block at a fee rate that
+	// follows the fee function of the sweeper, reaching the highest fee
+	// rate its budget allows at the deadline.
+	DeadlineHeight *int32
 }
 
 // ParamsUpdate contains a new set of parameters to update a pending sweep with.
@@ -98,8 +109,14 @@ type ParamsUpdate struct {
 
 // String returns a human readable interpretation of the sweep parameters.
 func (p Params) String() string {
-	return fmt.Sprintf("fee=%v, force=%v, exclusive_group=%v",
-		p.Fee, p.Force, p.ExclusiveGroup)
+	deadline := "none"
+	if p.DeadlineHeight != nil {
+		deadline = fmt.Sprintf("%d", *p.DeadlineHeight)
+	}
+
+	return fmt.Sprintf("fee=%v, force=%v, exclusive_group=%v, "+
+		"budget=%v, deadline_height=%v", p.Fee, p.Force,
+		p.ExclusiveGroup, p.Budget, deadline)
 }
 
 // pendingInput is created when an input reaches the main loop for the first
@@ -120,6 +137,11 @@ type pendingInput struct {
 	// input may be (re)published.
 	minPublishHeight int32
 
+	// startHeight is the block height at which the input was first offered
+	// to the sweeper. It is where the fee function of inputs with a
+	// deadline starts.
+	startHeight int32
+
 	// publishAttempts records the number of attempts that have already been
 	// made to sweep this tx.
 	publishAttempts int
@@ -139,6 +161,23 @@ func (p *pendingInput) parameters() Params {
 	return p.params
 }
 
+// deadline returns the block height by which the input should be swept. Inputs
+// offered with a budget but without a deadline height are given a deadline
+// DefaultDeadlineDelta blocks after they were offered. False is returned if
+// the input has neither.
+func (p *pendingInput) deadline() (int32, bool) {
+	switch {
+	case p.params.DeadlineHeight != nil:
+		return *p.params.DeadlineHeight, true
+
+	case p.params.Budget != 0:
+		return p.startHeight + DefaultDeadlineDelta, true
+
+	default:
+		return 0, false
+	}
+}
+
 // pendingInputs is a type alias for a set of pending inputs.
 type pendingInputs = map[wire.OutPoint]*pendingInput
 
@@ -285,17 +324,10 @@ type UtxoSweeperConfig struct {
 	// UtxoSweeper.
 	MaxFeeRate chainfee.SatPerKWeight
 
-	// FeeRateBucketSize is the default size of fee rate buckets we'll use
-	// when clustering inputs into buckets with similar fee rates within the
-	// UtxoSweeper.
-	//
-	// Given a minimum relay fee rate of 1 sat/vbyte, a fee rate bucket size
-	// of 10 would result in the following fee rate buckets up to the
-	// maximum fee rate:
-	//
-	//   #1: min = 1 sat/vbyte, max (exclusive) = 11 sat/vbyte
-	//   #2: min = 11 sat/vbyte, max (exclusive) = 21 sat/vbyte...
-	FeeRateBucketSize int
+	// FeeFunction determines how the fee rate of inputs with a deadline is
+	// raised towards the highest fee rate their budget allows as their
+	// deadline approaches.
+	FeeFunction FeeFunction
 }
 
 // Result is the struct that is pushed through the result channel. Callers can
@@ -443,7 +475,9 @@ func (s *UtxoSweeper) Stop() error {
 // provided to determine what fee rate should be used for the input. Note that
 // the input may not always be swept with this exact value, as its possible for
 // it to be batched under the same transaction with other similar fee rate
-// inputs.
+// inputs. Inputs that need to confirm before a deadline can be given a deadline
+// height and a budget instead, in which case their fee rate is raised every
+// block along the fee function of the sweeper.
 //
 // NOTE: Extreme care needs to be taken that input isn't changed externally.
 // Because it is an interface and we don't know what is exactly behind it, we
@@ -455,9 +489,14 @@ func (s *UtxoSweeper) SweepInput(input input.Input,
 		return nil, errors.New("nil input received")
 	}
 
-	// Ensure the client provided a sane fee preference.
-	if _, err := s.feeRateForPreference(params.Fee); err != nil {
-		return nil, err
+	// Ensure the client provided a sane fee preference. Inputs with a
+	// deadline or a budget don't need one, as their fee rate is derived
+	// from the deadline if it is missing.
+	hasDeadline := params.DeadlineHeight != nil || params.Budget != 0
+	if !hasDeadline || params.Fee != (FeePreference{}) {
+		if _, err := s.feeRateForPreference(params.Fee); err != nil {
+			return nil, err
+		}
 	}
 
 	absoluteTimeLock, _ := input.RequiredLockTime()
@@ -510,6 +549,76 @@ func (s *UtxoSweeper) feeRateForPreference(
 	return feeRate, nil
 }
 
+// feeRateForInput returns the fee rate the given input should be swept with at
+// the given height. Inputs without a deadline are swept at the fee rate of
+// their fee preference. For inputs with a deadline, the fee rate is raised
+// along the fee function from their start fee rate towards the highest fee
+// rate their budget allows, which is reached at the deadline.
+func (s *UtxoSweeper) feeRateForInput(pi *pendingInput,
+	currentHeight int32) (chainfee.SatPerKWeight, error) {
+
+	params := pi.params
+	deadline, ok := pi.deadline()
+	if !ok {
+		return s.feeRateForPreference(params.Fee)
+	}
+
+	// Without a fee preference, the fee function starts at the fee rate
+	// estimated to confirm the input within the blocks left until its
+	// deadline.
+	feePref := params.Fee
+	if feePref == (FeePreference{}) {
+		feePref.ConfTarget = 1
+		if deadline > currentHeight {
+			feePref.ConfTarget = uint32(deadline - currentHeight)
+		}
+	}
+
+	startFeeRate, err := s.feeRateForPreference(feePref)
+	if err != nil {
+		return 0, err
+	}
+
+	if params.Budget == 0 {
+		return startFeeRate, nil
+	}
+
+	// The budget determines the fee rate at the deadline. We derive it
+	// from the weight of a transaction sweeping only this input, which
+	// makes it a conservative bound for batched sweeps in which the input
+	// only pays for its share of the transaction. If the input has an
+	// unconfirmed parent, the budget is spent on top of the fee the parent
+	// already pays, to raise the fee rate of the package of both.
+	_, estimator := getWeightEstimate([]input.Input{pi}, nil, 0)
+	weight := int64(estimator.weight())
+	fee := params.Budget
+	if parent := pi.UnconfParent(); parent != nil {
+		weight += parent.Weight
+		fee += parent.Fee
+	}
+	endFeeRate := chainfee.SatPerKWeight(
+		fee * 1000 / bronutil.Amount(weight),
+	)
+	if endFeeRate > s.cfg.MaxFeeRate {
+		endFeeRate = s.cfg.MaxFeeRate
+	}
+
+	// If the budget doesn't even cover the start fee rate, we sweep at the
+	// highest fee rate it allows, as long as that can still be relayed.
+	if endFeeRate <= startFeeRate {
+		if endFeeRate < s.relayFeeRate {
+			endFeeRate = s.relayFeeRate
+		}
+
+		return endFeeRate, nil
+	}
+
+	return s.cfg.FeeFunction(
+		startFeeRate, endFeeRate, deadline-pi.startHeight,
+		currentHeight-pi.startHeight,
+	), nil
+}
+
 // collector is the sweeper main loop. It processes new inputs, spend
 // notifications and counts down to publication of the sweep tx.
 func (s *UtxoSweeper) collector(blockEpochs <-chan *chainntnfs.BlockEpoch) {
@@ -575,6 +684,7 @@ func (s *UtxoSweeper) collector(blockEpochs <-chan *chainntnfs.BlockEpoch) {
 				listeners:        []chan Result{input.resultChan},
 				Input:            input.input,
 				minPublishHeight: bestHeight,
+				startHeight:      bestHeight,
 				params:           input.params,
 			}
 			s.pendingInputs[outpoint] = pendInput
@@ -689,13 +799,13 @@ func (s *UtxoSweeper) collector(blockEpochs <-chan *chainntnfs.BlockEpoch) {
 			// be started when new inputs arrive.
 			s.timer = nil
 
-			// We'll attempt to cluster all of our inputs with
-			// similar fee rates. Before attempting to sweep them,
-			// we'll sort them in descending fee rate order. We do
-			// this to ensure any inputs which have had their fee
-			// rate bumped are broadcast first in order enforce the
-			// RBF policy.
-			inputClusters := s.createInputClusters()
+			// We'll attempt to cluster all of our inputs with the
+			// same deadline or fee rate. Before attempting to sweep
+			// them, we'll sort them in descending fee rate order.
+			// We do this to ensure any inputs which have had their
+			// fee rate bumped are broadcast first in order enforce
+			// the RBF policy.
+			inputClusters := s.createInputClusters(bestHeight)
 			sort.Slice(inputClusters, func(i, j int) bool {
 				return inputClusters[i].sweepFeeRate >
 					inputClusters[j].sweepFeeRate
@@ -782,36 +892,45 @@ func (s *UtxoSweeper) sweepCluster(cluster inputCluster,
 	})
 }
 
-// bucketForFeeReate determines the proper bucket for a fee rate. This is done
-// in order to batch inputs with similar fee rates together.
-func (s *UtxoSweeper) bucketForFeeRate(
-	feeRate chainfee.SatPerKWeight) int {
-
-	// Create an isolated bucket for sweeps at the minimum fee rate. This is
-	// to prevent very small outputs (anchors) from becoming uneconomical if
-	// their fee rate would be averaged with higher fee rate inputs in a
-	// regular bucket.
-	if feeRate == s.relayFeeRate {
-		return 0
-	}
-
-	return 1 + int(feeRate-s.relayFeeRate)/s.cfg.FeeRateBucketSize
+// clusterKey identifies the cluster an input without a required locktime is
+// swept in.
+type clusterKey struct {
+	// hasDeadline is true if the cluster contains inputs with a deadline.
+	hasDeadline bool
+
+	// deadline is the deadline height shared by the inputs of the
+	// cluster, if they have one.
+	deadline int32
+
+	// feeRate is the fee rate shared by the inputs of the cluster, if they
+	// don't have a deadline.
+	feeRate chainfee.SatPerKWeight
+
+	// exclusiveInput is the outpoint of the only input of the cluster if
+	// it belongs to an exclusive group. Such inputs are swept on their
+	// own, as an input of an exclusive group may be invalid (for example
+	// in the case of commitment anchors) and could thereby block the
+	// sweep of the other inputs, and only one input of the same group can
+	// ever be swept.
+	exclusiveInput wire.OutPoint
 }
 
 // createInputClusters creates a list of input clusters from the set of pending
 // inputs known by the UtxoSweeper. It clusters inputs by
 // 1) Required tx locktime
-// 2) Similar fee rates
-func (s *UtxoSweeper) createInputClusters() []inputCluster {
+// 2) Deadline height or, for inputs without a deadline, fee rate
+func (s *UtxoSweeper) createInputClusters(currentHeight int32) []inputCluster {
 	inputs := s.pendingInputs
 
 	// We start by getting the inputs clusters by locktime. Since the
 	// inputs commit to the locktime, they can only be clustered together
 	// if the locktime is equal.
-	lockTimeClusters, nonLockTimeInputs := s.clusterByLockTime(inputs)
+	lockTimeClusters, nonLockTimeInputs := s.clusterByLockTime(
+		inputs, currentHeight,
+	)
 
-	// Cluster the the remaining inputs by sweep fee rate.
-	feeClusters := s.clusterBySweepFeeRate(nonLockTimeInputs)
+	// Cluster the the remaining inputs by deadline.
+	feeClusters := s.clusterByDeadline(nonLockTimeInputs, currentHeight)
 
 	// Since the inputs that we clustered by fee rate don't commit to a
 	// specific locktime, we can try to merge a locktime cluster with a fee
@@ -824,8 +943,8 @@ func (s *UtxoSweeper) createInputClusters() []inputCluster {
 // is determined by calculating the average fee rate of all inputs within that
 // cluster. In addition to the created clusters, inputs that did not specify a
 // required lock time are returned.
-func (s *UtxoSweeper) clusterByLockTime(inputs pendingInputs) ([]inputCluster,
-	pendingInputs) {
+func (s *UtxoSweeper) clusterByLockTime(inputs pendingInputs,
+	currentHeight int32) ([]inputCluster, pendingInputs) {
 
 	locktimes := make(map[uint32]pendingInputs)
 	inputFeeRates := make(map[wire.OutPoint]chainfee.SatPerKWeight)
@@ -849,7 +968,7 @@ func (s *UtxoSweeper) clusterByLockTime(inputs pendingInputs) ([]inputCluster,
 		locktimes[lt] = p
 
 		// We also get the preferred fee rate for this input.
-		feeRate, err := s.feeRateForPreference(input.params.Fee)
+		feeRate, err := s.feeRateForInput(input, currentHeight)
 		if err != nil {
 			log.Warnf("Skipping input %v: %v", op, err)
 			continue
@@ -881,18 +1000,23 @@ func (s *UtxoSweeper) clusterByLockTime(inputs pendingInputs) ([]inputCluster,
 	return inputClusters, rem
 }
 
-// clusterBySweepFeeRate takes the set of pending inputs within the UtxoSweeper
-// and clusters those together with similar fee rates. Each cluster contains a
-// sweep fee rate, which is determined by calculating the average fee rate of
-// all inputs within that cluster.
-func (s *UtxoSweeper) clusterBySweepFeeRate(inputs pendingInputs) []inputCluster {
-	bucketInputs := make(map[int]*bucketList)
+// clusterByDeadline takes the set of pending inputs within the UtxoSweeper and
+// clusters those together that share a deadline height, as their fee rates
+// follow the same fee function. Inputs without a deadline are clustered with
+// those swept at the same fee rate. Inputs of an exclusive group are swept in
+// a cluster of their own. Each cluster contains a sweep fee rate, which is
+// determined by calculating the average fee rate of all inputs within that
+// cluster.
+func (s *UtxoSweeper) clusterByDeadline(inputs pendingInputs,
+	currentHeight int32) []inputCluster {
+
+	clusters := make(map[clusterKey]pendingInputs)
 	inputFeeRates := make(map[wire.OutPoint]chainfee.SatPerKWeight)
 
-	// First, we'll group together all inputs with similar fee rates. This
-	// is done by determining the fee rate bucket they should belong in.
+	// First, we'll group together all inputs with the same deadline, or
+	// the same fee rate if they don't have a deadline.
 	for op, input := range inputs {
-		feeRate, err := s.feeRateForPreference(input.params.Fee)
+		feeRate, err := s.feeRateForInput(input, currentHeight)
 		if err != nil {
 			log.Warnf("Skipping input %v: %v", op, err)
 			continue
@@ -917,19 +1041,24 @@ func (s *UtxoSweeper) clusterBySweepFeeRate(inputs pendingInputs) []inputCluster
 			}
 		}
 
-		feeGroup := s.bucketForFeeRate(feeRate)
+		group := clusterKey{feeRate: feeRate}
+		if deadline, ok := input.deadline(); ok {
+			group = clusterKey{
+				hasDeadline: true,
+				deadline:    deadline,
+			}
+		}
+		if input.params.ExclusiveGroup != nil {
+			group.exclusiveInput = op
+		}
 
-		// Create a bucket list for this fee rate if there isn't one
-		// yet.
-		buckets, ok := bucketInputs[feeGroup]
+		// Create the cluster if there isn't one yet.
+		cluster, ok := clusters[group]
 		if !ok {
-			buckets = &bucketList{}
-			bucketInputs[feeGroup] = buckets
+			cluster = make(pendingInputs)
+			clusters[group] = cluster
 		}
-
-		// Request the bucket list to add this input. The bucket list
-		// will take into account exclusive group constraints.
-		buckets.add(input)
+		cluster[op] = input
 
 		input.lastFeeRate = feeRate
 		inputFeeRates[op] = feeRate
@@ -937,19 +1066,17 @@ func (s *UtxoSweeper) clusterBySweepFeeRate(inputs pendingInputs) []inputCluster
 
 	// We'll then determine the sweep fee rate for each set of inputs by
 	// calculating the average fee rate of the inputs within each set.
-	inputClusters := make([]inputCluster, 0, len(bucketInputs))
-	for _, buckets := range bucketInputs {
-		for _, inputs := range buckets.buckets {
-			var sweepFeeRate chainfee.SatPerKWeight
-			for op := range inputs {
-				sweepFeeRate += inputFeeRates[op]
-			}
-			sweepFeeRate /= chainfee.SatPerKWeight(len(inputs))
-			inputClusters = append(inputClusters, inputCluster{
-				sweepFeeRate: sweepFeeRate,
-				inputs:       inputs,
-			})
+	inputClusters := make([]inputCluster, 0, len(clusters))
+	for _, inputs := range clusters {
+		var sweepFeeRate chainfee.SatPerKWeight
+		for op := range inputs {
+			sweepFeeRate += inputFeeRates[op]
 		}
+		sweepFeeRate /= chainfee.SatPerKWeight(len(inputs))
+		inputClusters = append(inputClusters, inputCluster{
+			sweepFeeRate: sweepFeeRate,
+			inputs:       inputs,
+		})
 	}
 
 	return inputClusters
@@ -1060,7 +1187,7 @@ func (s *UtxoSweeper) scheduleSweep(currentHeight int32) error {
 
 	// We'll only start our timer once we have inputs we're able to sweep.
 	startTimer := false
-	for _, cluster := range s.createInputClusters() {
+	for _, cluster := range s.createInputClusters(currentHeight) {
 		// Examine pending inputs and try to construct lists of inputs.
 		// We don't need to obtain the coin selection lock, because we
 		// just need an indication as to whether we can sweep. More
@@ -1268,6 +1395,21 @@ func (s *UtxoSweeper) sweep(inputs inputSet, feeRate chainfee.SatPerKWeight,
 		// Record another publish attempt.
 		pi.publishAttempts++
 
+		// Inputs with a deadline are retried every block, so that a
+		// replacement transaction picks up the fee rate raised by the
+		// fee function. We never give up on them, as failing to sweep
+		// them before their deadline may lose funds.
+		if deadline, ok := pi.deadline(); ok {
+			pi.minPublishHeight = currentHeight + 1
+
+			log.Debugf("Rescheduling input %v with deadline %v "+
+				"after %v attempts at height %v",
+				input.PreviousOutPoint, deadline,
+				pi.publishAttempts, pi.minPublishHeight)
+
+			continue
+		}
+
 		// We don't care what the result of the publish call was. Even
 		// if it is published successfully, it can still be that it
 		// needs to be retried. Call NextAttemptDeltaFunc to calculate
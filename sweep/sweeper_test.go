@@ -148,8 +148,8 @@ func createSweeperTestContext(t *testing.T) *sweeperTestContext {
 			// Use delta func without random factor.
 			return 1 << uint(attempts-1)
 		},
-		MaxFeeRate:        DefaultMaxFeeRate,
-		FeeRateBucketSize: DefaultFeeRateBucketSize,
+		MaxFeeRate:  DefaultMaxFeeRate,
+		FeeFunction: LinearFeeFunction,
 	})
 
 	ctx.sweeper.Start()
@@ -1254,6 +1254,101 @@ func TestBumpFeeRBF(t *testing.T) {
 	ctx.finish(1)
 }
 
+// TestDeadlineFeeBump asserts that the fee rate of an input with a deadline is
+// raised every block along the fee function, reaching the highest fee rate its
+// budget allows at the deadline.
+func TestDeadlineFeeBump(t *testing.T) {
+	ctx := createSweeperTestContext(t)
+
+	startFeeRate := chainfee.SatPerKWeight(1000)
+	ctx.estimator.updateFees(startFeeRate, chainfee.FeePerKwFloor)
+
+	// Offer an input that should be swept within 10 blocks, paying at
+	// most half of its value in fees.
+	inp := createTestInput(100_000, input.CommitmentTimeLock)
+	deadline := mockChainHeight + 10
+	budget := bronutil.Amount(50_000)
+	result, err := ctx.sweeper.SweepInput(
+		&inp, Params{
+			Budget:         budget,
+			DeadlineHeight: &deadline,
+		},
+	)
+	require.NoError(t, err)
+
+	// The budget determines the fee rate at the deadline.
+	_, estimator := getWeightEstimate([]input.Input{&inp}, nil, 0)
+	endFeeRate := chainfee.SatPerKWeight(
+		budget * 1000 / bronutil.Amount(estimator.weight()),
+	)
+
+	// The first sweep is published at the start fee rate.
+	ctx.tick()
+	tx := ctx.receiveTx()
+	assertTxFeeRate(t, &tx, startFeeRate, &inp)
+
+	// Halfway to the deadline, the fee rate should have been raised half
+	// way to the end fee rate.
+	ctx.notifier.NotifyEpoch(mockChainHeight + 5)
+	ctx.tick()
+	tx = ctx.receiveTx()
+	assertTxFeeRate(
+		t, &tx, LinearFeeFunction(startFeeRate, endFeeRate, 10, 5),
+		&inp,
+	)
+
+	// At the deadline, the whole budget is used.
+	ctx.notifier.NotifyEpoch(deadline)
+	ctx.tick()
+	tx = ctx.receiveTx()
+	assertTxFeeRate(t, &tx, endFeeRate, &inp)
+
+	ctx.backend.mine()
+	ctx.expectResult(result, nil)
+
+	ctx.finish(1)
+}
+
+// TestDefaultDeadline asserts that an input offered with a budget but without a
+// deadline height is given a deadline DefaultDeadlineDelta blocks after it was
+// offered, and that its fee rate is raised towards its budget every block.
+func TestDefaultDeadline(t *testing.T) {
+	ctx := createSweeperTestContext(t)
+
+	startFeeRate := chainfee.SatPerKWeight(1000)
+	ctx.estimator.updateFees(startFeeRate, chainfee.FeePerKwFloor)
+
+	inp := createTestInput(100_000, input.CommitmentTimeLock)
+	budget := bronutil.Amount(50_000)
+	result, err := ctx.sweeper.SweepInput(&inp, Params{Budget: budget})
+	require.NoError(t, err)
+
+	_, estimator := getWeightEstimate([]input.Input{&inp}, nil, 0)
+	endFeeRate := chainfee.SatPerKWeight(
+		budget * 1000 / bronutil.Amount(estimator.weight()),
+	)
+
+	// The first sweep is published at the start fee rate.
+	ctx.tick()
+	tx := ctx.receiveTx()
+	assertTxFeeRate(t, &tx, startFeeRate, &inp)
+
+	// A block later, the input is re-swept at a raised fee rate.
+	ctx.notifier.NotifyEpoch(mockChainHeight + 1)
+	ctx.tick()
+	tx = ctx.receiveTx()
+	assertTxFeeRate(
+		t, &tx, LinearFeeFunction(
+			startFeeRate, endFeeRate, DefaultDeadlineDelta, 1,
+		), &inp,
+	)
+
+	ctx.backend.mine()
+	ctx.expectResult(result, nil)
+
+	ctx.finish(1)
+}
+
 // TestExclusiveGroup tests the sweeper exclusive group functionality.
 func TestExclusiveGroup(t *testing.T) {
 	ctx := createSweeperTestContext(t)
@@ -1382,6 +1477,69 @@ func TestCpfp(t *testing.T) {
 	ctx.finish(1)
 }
 
+// TestCpfpBudget asserts that the budget of an input with an unconfirmed parent
+// is spent on the fee rate of the package of the parent and the sweep tx.
+func TestCpfpBudget(t *testing.T) {
+	ctx := createSweeperTestContext(t)
+
+	ctx.estimator.updateFees(1000, chainfee.FeePerKwFloor)
+
+	// Offer an input with an unconfirmed parent tx to the sweeper that
+	// has reached its deadline. The parent tx pays 3000 sat/kw.
+	parent := &input.TxInfo{
+		Weight: 300,
+		Fee:    900,
+	}
+	inp := input.MakeBaseInput(
+		&wire.OutPoint{Hash: chainhash.Hash{1}},
+		input.CommitmentTimeLock,
+		&input.SignDescriptor{
+			Output: &wire.TxOut{
+				Value: 330,
+			},
+			KeyDesc: keychain.KeyDescriptor{
+				PubKey: testPubKey,
+			},
+		},
+		0, parent,
+	)
+
+	deadline := mockChainHeight
+	budget := bronutil.Amount(5000)
+	result, err := ctx.sweeper.SweepInput(
+		&inp, Params{
+			Force:          true,
+			Budget:         budget,
+			DeadlineHeight: &deadline,
+		},
+	)
+	require.NoError(t, err)
+
+	// At the deadline, the budget is added to the fee of the parent to
+	// determine the fee rate of the package.
+	_, estimator := getWeightEstimate([]input.Input{&inp}, nil, 0)
+	feeRate := chainfee.SatPerKWeight(
+		(budget + parent.Fee) * 1000 /
+			bronutil.Amount(estimator.weight()+int(parent.Weight)),
+	)
+
+	// We expect a sweep transaction with our input and an attached wallet
+	// utxo, which is 759 weight units. The fee of the package of 1059
+	// weight units is reduced by the fee the parent already paid.
+	ctx.tick()
+	tx := ctx.receiveTx()
+	require.Len(t, tx.TxIn, 2)
+	require.Len(t, tx.TxOut, 1)
+
+	fee := feeRate.FeeForWeight(759+parent.Weight) - parent.Fee
+	require.Equal(t, int64(1_000_000+330-fee), tx.TxOut[0].Value)
+
+	ctx.backend.mine()
+	ctx.expectResult(result, nil)
+
+	ctx.finish(1)
+}
+
 var (
 	testInputsA = pendingInputs{
 		wire.OutPoint{Hash: chainhash.Hash{}, Index: 0}: &pendingInput{},
@@ -1623,7 +1781,9 @@ func (i *testInput) RequiredTxOut() *wire.TxOut {
 // encode the spending outpoint and the tx input index as part of the returned
 // witness.
 func (i *testInput) CraftInputScript(_ input.Signer, txn *wire.MsgTx,
-	hashCache *txscript.TxSigHashes, txinIdx int) (*input.Script, error) {
+	hashCache *txscript.TxSigHashes,
+	prevOutputFetcher txscript.PrevOutputFetcher,
+	txinIdx int) (*input.Script, error) {
 
 	// We'll encode the outpoint in the witness, so we can assert that the
 	// expected input was signed at the correct index.
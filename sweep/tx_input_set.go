@@ -362,6 +362,8 @@ func createWalletTxInput(utxo *lnwallet.Utxo) (input.Input, error) {
 		witnessType = input.WitnessKeyHash
 	case lnwallet.NestedWitnessPubKey:
 		witnessType = input.NestedWitnessKeyHash
+	case lnwallet.TaprootPubkey:
+		witnessType = input.TaprootPubKeySpend
 	default:
 		return nil, fmt.Errorf("unknown address type %v",
 			utxo.AddressType)
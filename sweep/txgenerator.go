@@ -262,13 +262,17 @@ func createSweepTx(inputs []input.Input, outputs []*wire.TxOut,
 		return nil, err
 	}
 
-	hashCache := txscript.NewTxSigHashes(sweepTx)
+	prevOutFetcher := txscript.NewMultiPrevOutFetcher(nil)
+	for _, inp := range idxs {
+		prevOutFetcher.AddPrevOut(*inp.OutPoint(), inp.SignDesc().Output)
+	}
+	hashCache := txscript.NewTxSigHashes(sweepTx, prevOutFetcher)
 
 	// With all the inputs in place, use each output's unique input script
 	// function to generate the final witness required for spending.
 	addInputScript := func(idx int, tso input.Input) error {
 		inputScript, err := tso.CraftInputScript(
-			signer, sweepTx, hashCache, idx,
+			signer, sweepTx, hashCache, prevOutFetcher, idx,
 		)
 		if err != nil {
 			return err
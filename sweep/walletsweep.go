@@ -255,6 +255,11 @@ func CraftSweepAllTx(feeRate chainfee.SatPerKWeight, blockHeight uint32,
 		case lnwallet.NestedWitnessPubKey:
 			witnessType = input.NestedWitnessKeyHash
 
+		// If this is a p2tr output, then we'll spend it using the key
+		// spend path as the wallet only creates BIP-86 addresses.
+		case lnwallet.TaprootPubkey:
+			witnessType = input.TaprootPubKeySpend
+
 		// All other output types we count as unknown and will fail to
 		// sweep.
 		default:
@@ -217,17 +217,25 @@ func (p *JusticeDescriptor) assembleJusticeTxn(txWeight int64,
 	}
 
 	// Attach each of the provided witnesses to the transaction.
+	prevOutFetcher := txscript.NewMultiPrevOutFetcher(nil)
 	for _, input := range inputs {
 		// Lookup the input's new post-sort position.
 		i := inputIndex[input.outPoint]
 		justiceTxn.TxIn[i].Witness = input.witness
 
-		// Validate the reconstructed witnesses to ensure they are valid
-		// for the breached inputs.
+		prevOutFetcher.AddPrevOut(input.outPoint, input.txOut)
+	}
+
+	// Validate the reconstructed witnesses to ensure they are valid for
+	// the breached inputs.
+	sigHashes := txscript.NewTxSigHashes(justiceTxn, prevOutFetcher)
+	for _, input := range inputs {
+		i := inputIndex[input.outPoint]
+
 		vm, err := txscript.NewEngine(
 			input.txOut.PkScript, justiceTxn, i,
-			txscript.StandardVerifyFlags,
-			nil, nil, input.txOut.Value,
+			txscript.StandardVerifyFlags, nil, sigHashes,
+			input.txOut.Value, prevOutFetcher,
 		)
 		if err != nil {
 			return nil, err
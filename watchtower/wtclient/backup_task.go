@@ -285,7 +285,13 @@ func (t *backupTask) craftSessionPayload(
 	}
 
 	// Construct a sighash cache to improve signing performance.
-	hashCache := txscript.NewTxSigHashes(justiceTxn)
+	prevOutFetcher := txscript.NewMultiPrevOutFetcher(nil)
+	for _, inp := range inputs {
+		prevOutFetcher.AddPrevOut(
+			*inp.OutPoint(), inp.SignDesc().Output,
+		)
+	}
+	hashCache := txscript.NewTxSigHashes(justiceTxn, prevOutFetcher)
 
 	// Since the transaction inputs could have been reordered as a result of
 	// the BIP69 sort, create an index mapping each prevout to it's new
@@ -304,7 +310,7 @@ func (t *backupTask) craftSessionPayload(
 
 		// Construct the full witness required to spend this input.
 		inputScript, err := inp.CraftInputScript(
-			signer, justiceTxn, hashCache, i,
+			signer, justiceTxn, hashCache, prevOutFetcher, i,
 		)
 		if err != nil {
 			return hint, nil, err
@@ -0,0 +1,167 @@
+package zpay32
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+
+	"github.com/brronsuite/broln/blinding"
+	"github.com/brronsuite/broln/lnwire"
+	"github.com/brronsuite/bronutil/bech32"
+)
+
+// BlindedPaymentPath is a blinded route to the destination of an invoice. As
+// the sender can't see the channels of the blinded hops, the recipient adds
+// the aggregated relay parameters of the route that the sender needs to pay
+// through it.
+type BlindedPaymentPath struct {
+	// FeeBaseMsat is the total base fee of the blinded hops.
+	FeeBaseMsat uint32
+
+	// FeeRate is the total proportional fee of the blinded hops in
+	// millionths.
+	FeeRate uint32
+
+	// CltvExpiryDelta is the total time-lock delta of the blinded hops,
+	// including the final CLTV delta of the destination.
+	CltvExpiryDelta uint16
+
+	// HTLCMinMsat is the minimum amount an HTLC must carry to be routed
+	// through the path.
+	HTLCMinMsat uint64
+
+	// HTLCMaxMsat is the maximum amount an HTLC can carry to be routed
+	// through the path.
+	HTLCMaxMsat uint64
+
+	// Features are the features the HTLC may use within the path.
+	Features *lnwire.FeatureVector
+
+	// Path is the blinded route itself.
+	Path *blinding.BlindedPath
+}
+
+// BlindedPath is a functional option that allows callers of NewInvoice to add
+// a blinded route to the destination. Blinded routes replace route hints to
+// not reveal the private channels of the destination.
+func BlindedPath(path *BlindedPaymentPath) func(*Invoice) {
+	return func(i *Invoice) {
+		i.BlindedPaymentPaths = append(i.BlindedPaymentPaths, path)
+	}
+}
+
+// encode serializes the blinded payment path.
+func (p *BlindedPaymentPath) encode(w io.Writer) error {
+	var buf [8]byte
+
+	binary.BigEndian.PutUint32(buf[:4], p.FeeBaseMsat)
+	if _, err := w.Write(buf[:4]); err != nil {
+		return err
+	}
+
+	binary.BigEndian.PutUint32(buf[:4], p.FeeRate)
+	if _, err := w.Write(buf[:4]); err != nil {
+		return err
+	}
+
+	binary.BigEndian.PutUint16(buf[:2], p.CltvExpiryDelta)
+	if _, err := w.Write(buf[:2]); err != nil {
+		return err
+	}
+
+	binary.BigEndian.PutUint64(buf[:], p.HTLCMinMsat)
+	if _, err := w.Write(buf[:]); err != nil {
+		return err
+	}
+
+	binary.BigEndian.PutUint64(buf[:], p.HTLCMaxMsat)
+	if _, err := w.Write(buf[:]); err != nil {
+		return err
+	}
+
+	features := lnwire.NewRawFeatureVector()
+	if p.Features != nil {
+		features = p.Features.RawFeatureVector
+	}
+	if err := features.Encode(w); err != nil {
+		return err
+	}
+
+	return p.Path.Encode(w)
+}
+
+// decodeBlindedPaymentPath parses a serialized blinded payment path.
+func decodeBlindedPaymentPath(r io.Reader) (*BlindedPaymentPath, error) {
+	var (
+		p   BlindedPaymentPath
+		buf [8]byte
+	)
+
+	if _, err := io.ReadFull(r, buf[:4]); err != nil {
+		return nil, err
+	}
+	p.FeeBaseMsat = binary.BigEndian.Uint32(buf[:4])
+
+	if _, err := io.ReadFull(r, buf[:4]); err != nil {
+		return nil, err
+	}
+	p.FeeRate = binary.BigEndian.Uint32(buf[:4])
+
+	if _, err := io.ReadFull(r, buf[:2]); err != nil {
+		return nil, err
+	}
+	p.CltvExpiryDelta = binary.BigEndian.Uint16(buf[:2])
+
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return nil, err
+	}
+	p.HTLCMinMsat = binary.BigEndian.Uint64(buf[:])
+
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return nil, err
+	}
+	p.HTLCMaxMsat = binary.BigEndian.Uint64(buf[:])
+
+	features := lnwire.NewRawFeatureVector()
+	if err := features.Decode(r); err != nil {
+		return nil, err
+	}
+	p.Features = lnwire.NewFeatureVector(features, lnwire.Features)
+
+	var err error
+	p.Path, err = blinding.DecodeBlindedPath(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// parseBlindedPaymentPath converts the data (encoded in base32) into a
+// blinded payment path.
+func parseBlindedPaymentPath(data []byte) (*BlindedPaymentPath, error) {
+	base256Data, err := bech32.ConvertBits(data, 5, 8, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeBlindedPaymentPath(bytes.NewReader(base256Data))
+}
+
+// writeBlindedPaymentPath encodes the blinded payment path as base32 and
+// writes it to bufferBase32.
+func writeBlindedPaymentPath(bufferBase32 *bytes.Buffer,
+	path *BlindedPaymentPath) error {
+
+	var b bytes.Buffer
+	if err := path.encode(&b); err != nil {
+		return err
+	}
+
+	pathBase32, err := bech32.ConvertBits(b.Bytes(), 8, 5, true)
+	if err != nil {
+		return err
+	}
+
+	return writeTaggedField(bufferBase32, fieldTypeB, pathBase32)
+}
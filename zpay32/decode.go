@@ -279,6 +279,17 @@ func parseTaggedFields(invoice *Invoice, fields []byte, net *chaincfg.Params) er
 			}
 
 			invoice.RouteHints = append(invoice.RouteHints, routeHint)
+		case fieldTypeB:
+			// A blinded path can be included in an invoice
+			// multiple times as well.
+			path, err := parseBlindedPaymentPath(base32Data)
+			if err != nil {
+				return err
+			}
+
+			invoice.BlindedPaymentPaths = append(
+				invoice.BlindedPaymentPaths, path,
+			)
 		case fieldType9:
 			if invoice.Features != nil {
 				// We skip the field if we have already seen a
@@ -73,6 +73,10 @@ const (
 	// probing the recipient.
 	fieldTypeS = 16
 
+	// fieldTypeB contains a blinded route to the destination together
+	// with the aggregated relay parameters of its hops.
+	fieldTypeB = 20
+
 	// maxInvoiceLength is the maximum total length an invoice can have.
 	// This is chosen to be the maximum number of bytes that can fit into a
 	// single QR code: https://en.wikipedia.org/wiki/QR_code#Storage
@@ -180,6 +184,13 @@ type Invoice struct {
 	// NOTE: This is optional.
 	RouteHints [][]HopHint
 
+	// BlindedPaymentPaths are blinded routes to the destination. They
+	// replace route hints if the destination doesn't want to reveal its
+	// private channels.
+	//
+	// NOTE: This is optional.
+	BlindedPaymentPaths []*BlindedPaymentPath
+
 	// Features represents an optional field used to signal optional or
 	// required support for features by the receiver.
 	Features *lnwire.FeatureVector
@@ -12,6 +12,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/brronsuite/broln/blinding"
 	"github.com/brronsuite/broln/lnwire"
 	"github.com/brronsuite/brond/bronec"
 	"github.com/brronsuite/brond/chaincfg"
@@ -955,6 +956,85 @@ func TestInvoiceChecksumMalleability(t *testing.T) {
 
 }
 
+// TestBlindedPaymentPath tests that blinded payment paths survive an encoding
+// round trip of an invoice.
+func TestBlindedPaymentPath(t *testing.T) {
+	t.Parallel()
+
+	introPriv, err := bronec.NewPrivateKey(bronec.S256())
+	if err != nil {
+		t.Fatalf("unable to create key: %v", err)
+	}
+	sessionKey, err := bronec.NewPrivateKey(bronec.S256())
+	if err != nil {
+		t.Fatalf("unable to create key: %v", err)
+	}
+
+	path, err := blinding.BuildBlindedPath(sessionKey, []*blinding.HopInfo{
+		{
+			NodePub:   introPriv.PubKey(),
+			PlainText: []byte("intro data"),
+		},
+		{
+			NodePub:   testPubKey,
+			PlainText: testPaymentAddr[:],
+		},
+	})
+	if err != nil {
+		t.Fatalf("unable to build blinded path: %v", err)
+	}
+
+	blindedPath := &BlindedPaymentPath{
+		FeeBaseMsat:     1000,
+		FeeRate:         100,
+		CltvExpiryDelta: 80,
+		HTLCMinMsat:     1,
+		HTLCMaxMsat:     1000000,
+		Features:        lnwire.EmptyFeatureVector(),
+		Path:            path,
+	}
+
+	invoice, err := NewInvoice(
+		&chaincfg.SimNetParams, testPaymentHash, time.Unix(10, 0),
+		Description(testCupOfCoffee), PaymentAddr(testPaymentAddr),
+		BlindedPath(blindedPath), BlindedPath(blindedPath),
+	)
+	if err != nil {
+		t.Fatalf("unable to create invoice: %v", err)
+	}
+
+	encoded, err := invoice.Encode(testMessageSigner)
+	if err != nil {
+		t.Fatalf("unable to encode invoice: %v", err)
+	}
+
+	decoded, err := Decode(encoded, &chaincfg.SimNetParams)
+	if err != nil {
+		t.Fatalf("unable to decode invoice: %v", err)
+	}
+
+	if len(decoded.BlindedPaymentPaths) != 2 {
+		t.Fatalf("expected 2 blinded paths, got %d",
+			len(decoded.BlindedPaymentPaths))
+	}
+	for _, decodedPath := range decoded.BlindedPaymentPaths {
+		if !reflect.DeepEqual(blindedPath, decodedPath) {
+			t.Fatalf("expected blinded path %v, got %v",
+				spewPath(blindedPath), spewPath(decodedPath))
+		}
+	}
+}
+
+// spewPath returns a printable representation of a blinded payment path.
+func spewPath(p *BlindedPaymentPath) string {
+	var b bytes.Buffer
+	if err := p.encode(&b); err != nil {
+		return err.Error()
+	}
+
+	return hex.EncodeToString(b.Bytes())
+}
+
 func compareInvoices(expected, actual *Invoice) error {
 	if !reflect.DeepEqual(expected.Net, actual.Net) {
 		return fmt.Errorf("expected net %v, got %v",